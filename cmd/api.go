@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/madstone-tech/loko/internal/adapters/filesystem"
 	"github.com/madstone-tech/loko/internal/api"
+	"github.com/madstone-tech/loko/internal/core/usecases"
 )
 
 // APICommand starts the HTTP API server.
@@ -16,6 +18,10 @@ type APICommand struct {
 	port        int
 	projectRoot string
 	apiKey      string
+	readKey     string
+	sandbox     bool
+	sandboxDirs []string // Roots the sandbox permits besides the project root
+	origins     []string // Browser origins allowed to open the event stream
 }
 
 // NewAPICommand creates a new API command.
@@ -44,36 +50,104 @@ func (c *APICommand) WithAPIKey(key string) *APICommand {
 	return c
 }
 
+// WithReadKey sets an additional API key limited to read requests.
+func (c *APICommand) WithReadKey(key string) *APICommand {
+	c.readKey = key
+	return c
+}
+
+// WithSandbox confines the paths that requests pass, such as the output_dir
+// of a build, to the project root and roots, rejecting the others.
+func (c *APICommand) WithSandbox(roots ...string) *APICommand {
+	c.sandbox = true
+	c.sandboxDirs = roots
+	return c
+}
+
+// WithAllowedOrigins allows browser pages on origins, besides the server's
+// own, to open the /api/v1/ws event stream.
+func (c *APICommand) WithAllowedOrigins(origins ...string) *APICommand {
+	c.origins = origins
+	return c
+}
+
+// streamModelEvents watches the project sources in the background and
+// publishes entity events to the server's WebSocket clients. Failing to watch
+// only disables the events.
+func (c *APICommand) streamModelEvents(ctx context.Context, repo usecases.ProjectRepository, server *api.Server) {
+	project, err := repo.LoadProject(ctx, c.projectRoot)
+	if err != nil {
+		return
+	}
+	watcher, err := NewWatchCommand(c.projectRoot).newWatcher()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: live model events disabled: %v\n", err)
+		return
+	}
+	go func() {
+		defer func() { _ = watcher.Stop() }()
+		if err := usecases.NewStreamModelEvents(watcher, server.Events()).Execute(ctx, c.projectRoot, project.SourceDir()); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: live model events disabled: %v\n", err)
+		}
+	}()
+}
+
 // Execute starts the API server.
 func (c *APICommand) Execute(ctx context.Context) error {
 	// Create repository
-	repo := filesystem.NewProjectRepository()
+	repo := newProjectRepository()
 
 	// Create server config
 	config := api.DefaultConfig()
 	config.Port = c.port
 	config.ProjectRoot = c.projectRoot
 	config.APIKey = c.apiKey
+	config.ReadAPIKey = c.readKey
+	config.Auditor = newAuditRecorder(ctx, c.projectRoot)
+	config.BuildLogs = filesystem.NewBuildLogStore(c.projectRoot)
+	config.AllowedOrigins = c.origins
+	var sandbox *usecases.PathSandbox
+	if c.sandbox {
+		var err error
+		if sandbox, err = newPathSandbox(c.projectRoot, c.sandboxDirs); err != nil {
+			return err
+		}
+		config.Sandbox = sandbox.Resolve
+	}
 
 	// Create server
 	server := api.NewServer(config, repo)
 
 	// Print startup message
 	fmt.Fprintf(os.Stderr, "Starting loko API server on port %d\n", c.port)
-	if c.apiKey != "" {
+	switch {
+	case c.apiKey != "" && c.readKey != "":
+		fmt.Fprintf(os.Stderr, "Authentication: enabled (editor and read-only API keys)\n")
+	case c.apiKey != "":
 		fmt.Fprintf(os.Stderr, "Authentication: enabled (API key required)\n")
-	} else {
+	case c.readKey != "":
+		fmt.Fprintf(os.Stderr, "Authentication: enabled (read-only API key)\n")
+	default:
 		fmt.Fprintf(os.Stderr, "Authentication: disabled\n")
 	}
 	fmt.Fprintf(os.Stderr, "Project root: %s\n", c.projectRoot)
+	if sandbox != nil {
+		fmt.Fprintf(os.Stderr, "Sandbox: %s\n", strings.Join(sandbox.Roots(), ", "))
+	}
 	fmt.Fprintf(os.Stderr, "\nEndpoints:\n")
 	fmt.Fprintf(os.Stderr, "  GET  /health           - Health check\n")
 	fmt.Fprintf(os.Stderr, "  GET  /api/v1/project   - Get project info\n")
 	fmt.Fprintf(os.Stderr, "  GET  /api/v1/systems   - List all systems\n")
 	fmt.Fprintf(os.Stderr, "  GET  /api/v1/systems/{id} - Get system details\n")
+	fmt.Fprintf(os.Stderr, "  GET  /api/v1/systems/{id}/containers/{cid}/components - List components\n")
+	fmt.Fprintf(os.Stderr, "  GET  /api/v1/elements  - List all elements (filter by type, tag, tech)\n")
+	fmt.Fprintf(os.Stderr, "  GET  /api/v1/architecture - Query architecture (summary/structure/full)\n")
 	fmt.Fprintf(os.Stderr, "  POST /api/v1/build     - Trigger documentation build\n")
 	fmt.Fprintf(os.Stderr, "  GET  /api/v1/build/{id} - Get build status\n")
+	fmt.Fprintf(os.Stderr, "  GET  /api/v1/build/{id}/log - Get build log (JSON lines)\n")
 	fmt.Fprintf(os.Stderr, "  GET  /api/v1/validate  - Validate architecture\n")
+	fmt.Fprintf(os.Stderr, "  GET  /api/v1/schemas/{name} - JSON Schema (system, container, component, config)\n")
+	fmt.Fprintf(os.Stderr, "  GET  /api/v1/ws        - WebSocket stream of model and build events\n")
 	fmt.Fprintf(os.Stderr, "\nPress Ctrl+C to stop\n\n")
 
 	// Handle graceful shutdown
@@ -89,6 +163,9 @@ func (c *APICommand) Execute(ctx context.Context) error {
 		cancel()
 	}()
 
+	// Push source changes to WebSocket clients
+	c.streamModelEvents(ctx, repo, server)
+
 	// Start server (blocks until context is cancelled)
 	return server.Start(ctx)
 }
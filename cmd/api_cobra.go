@@ -1,21 +1,51 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
 
 var apiCmd = &cobra.Command{
-	Use:     "api",
-	Short:   "Start HTTP API server",
-	Long:    "Start the loko HTTP REST API server.",
+	Use:   "api",
+	Short: "Start HTTP API server",
+	Long: `Start the loko HTTP REST API server.
+
+Requests need a bearer token when an API key is set. Editor keys (--api-key)
+may read and modify the project; read-only keys (--read-key) may only make
+GET requests.`,
 	GroupID: "serving",
 	RunE:    runAPI,
 }
 
 func init() {
 	rootCmd.AddCommand(apiCmd)
+	apiCmd.Flags().Int("port", 8081, "port to listen on")
+	apiCmd.Flags().String("api-key", "", "editor API key (default: $LOKO_API_KEY)")
+	apiCmd.Flags().String("read-key", "", "read-only API key (default: $LOKO_API_READ_KEY)")
+	apiCmd.Flags().Bool("sandbox", false, "reject requests whose paths leave the project root")
+	apiCmd.Flags().StringSlice("sandbox-root", nil, "additional directory requests may use (implies --sandbox)")
+	apiCmd.Flags().StringSlice("allowed-origin", nil, "browser origin, besides the server's own, allowed to open /api/v1/ws")
 }
 
 func runAPI(cmd *cobra.Command, args []string) error {
 	apiCommand := NewAPICommand()
 	apiCommand.WithProjectRoot(ProjectRoot)
+
+	port, _ := cmd.Flags().GetInt("port")
+	apiKey, _ := cmd.Flags().GetString("api-key")
+	if apiKey == "" {
+		apiKey = os.Getenv("LOKO_API_KEY")
+	}
+	readKey, _ := cmd.Flags().GetString("read-key")
+	if readKey == "" {
+		readKey = os.Getenv("LOKO_API_READ_KEY")
+	}
+	origins, _ := cmd.Flags().GetStringSlice("allowed-origin")
+	apiCommand.WithPort(port).WithAPIKey(apiKey).WithReadKey(readKey).WithAllowedOrigins(origins...)
+	sandbox, _ := cmd.Flags().GetBool("sandbox")
+	if roots, _ := cmd.Flags().GetStringSlice("sandbox-root"); sandbox || len(roots) > 0 {
+		apiCommand.WithSandbox(roots...)
+	}
 	return apiCommand.Execute(cmd.Context())
 }
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/adapters/git"
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// AuditShowCommand prints the audit log of architecture modifications made
+// through MCP tools and the HTTP API.
+type AuditShowCommand struct {
+	projectRoot string
+	limit       int    // Most recent entries to show; 0 shows all
+	source      string // "mcp" or "api"; empty shows both
+	showDiff    bool
+	out         io.Writer
+}
+
+// NewAuditShowCommand creates a new audit show command.
+func NewAuditShowCommand(projectRoot string) *AuditShowCommand {
+	return &AuditShowCommand{
+		projectRoot: projectRoot,
+		limit:       20,
+		out:         os.Stdout,
+	}
+}
+
+// WithLimit sets how many of the most recent entries are shown (0 for all).
+func (c *AuditShowCommand) WithLimit(limit int) *AuditShowCommand {
+	c.limit = limit
+	return c
+}
+
+// WithSource shows only entries from the given source ("mcp" or "api").
+func (c *AuditShowCommand) WithSource(source string) *AuditShowCommand {
+	c.source = strings.ToLower(strings.TrimSpace(source))
+	return c
+}
+
+// WithDiff includes the changed lines of every file.
+func (c *AuditShowCommand) WithDiff(showDiff bool) *AuditShowCommand {
+	c.showDiff = showDiff
+	return c
+}
+
+// Execute prints the selected entries, newest first.
+func (c *AuditShowCommand) Execute(ctx context.Context) error {
+	switch entities.AuditSource(c.source) {
+	case "", entities.AuditSourceMCP, entities.AuditSourceAPI:
+	default:
+		return fmt.Errorf("unknown audit source %q (expected mcp or api)", c.source)
+	}
+
+	entries, err := filesystem.NewAuditLog(c.projectRoot).Entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	var selected []*entities.AuditEntry
+	for i := len(entries) - 1; i >= 0; i-- {
+		if c.source != "" && string(entries[i].Source) != c.source {
+			continue
+		}
+		selected = append(selected, entries[i])
+		if c.limit > 0 && len(selected) == c.limit {
+			break
+		}
+	}
+	if len(selected) == 0 {
+		fmt.Fprintf(c.out, "No audit entries in %s\n", filesystem.AuditLogFile)
+		return nil
+	}
+
+	for _, entry := range selected {
+		c.printEntry(entry)
+	}
+	return nil
+}
+
+// printEntry writes one entry: a summary line, then any error and the files changed.
+func (c *AuditShowCommand) printEntry(entry *entities.AuditEntry) {
+	who := entry.Actor
+	if entry.User != "" {
+		who += " [" + entry.User + "]"
+	}
+	fmt.Fprintf(c.out, "%s  %-3s  %s  %s\n", entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Source, entry.Action, who)
+	if entry.Error != "" {
+		fmt.Fprintf(c.out, "    error: %s\n", entry.Error)
+	}
+	for _, change := range entry.Changes {
+		fmt.Fprintf(c.out, "    %-8s %s\n", change.Op, change.Path)
+		if c.showDiff {
+			for _, line := range strings.Split(strings.TrimSuffix(change.Diff, "\n"), "\n") {
+				if line != "" {
+					fmt.Fprintf(c.out, "        %s\n", line)
+				}
+			}
+		}
+	}
+}
+
+// newAuditRecorder returns the recorder that appends mutating MCP tool calls
+// and API writes to the project's audit log and, with [git] auto_commit,
+// commits the files they change.
+func newAuditRecorder(ctx context.Context, projectRoot string) *usecases.RecordAudit {
+	srcDir := "src"
+	autoCommit := false
+	if project, err := filesystem.NewProjectRepository().LoadProject(ctx, projectRoot); err == nil {
+		srcDir = project.SourceDir()
+		autoCommit = project.Config != nil && project.Config.GitAutoCommit
+	}
+	recorder := usecases.NewRecordAudit(filesystem.NewAuditLog(projectRoot), projectRoot, srcDir)
+	if autoCommit {
+		recorder.WithCommitter(git.NewHistory())
+	}
+	return recorder
+}
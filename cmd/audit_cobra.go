@@ -0,0 +1,43 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Inspect the audit log of architecture changes",
+	Long: `Every mutating MCP tool call and HTTP API write is recorded in
+.loko/audit.log with the caller, the time, the arguments and a line diff of
+the source files it changed.`,
+	GroupID: "serving",
+}
+
+var auditShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show recent architecture changes made through MCP or the API",
+	Long: `Show the most recent audit log entries, newest first. Each entry lists who
+made the change (the MCP client or API caller, and the OS user running loko),
+the tool or endpoint, and the files it added, modified or removed.`,
+	Example: `  loko audit show
+  loko audit show --diff --limit 5
+  loko audit show --source api --limit 0`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		source, _ := cmd.Flags().GetString("source")
+		showDiff, _ := cmd.Flags().GetBool("diff")
+
+		return NewAuditShowCommand(ProjectRoot).
+			WithLimit(limit).
+			WithSource(source).
+			WithDiff(showDiff).
+			Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+
+	auditCmd.AddCommand(auditShowCmd)
+	auditShowCmd.Flags().IntP("limit", "n", 20, "number of most recent entries to show (0 for all)")
+	auditShowCmd.Flags().String("source", "", "only show entries from mcp or api")
+	auditShowCmd.Flags().Bool("diff", false, "show the changed lines of each file")
+}
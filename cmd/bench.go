@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/adapters/cli"
+	"github.com/madstone-tech/loko/internal/adapters/d2"
+	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/adapters/html"
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// BenchCommand measures build performance on a generated project.
+type BenchCommand struct {
+	size       usecases.SyntheticProjectSize
+	iterations int
+	keep       bool
+}
+
+// NewBenchCommand creates a new bench command.
+func NewBenchCommand() *BenchCommand {
+	return &BenchCommand{
+		size:       usecases.SyntheticProjectSize{Systems: 10, Containers: 5, Components: 5},
+		iterations: 3,
+	}
+}
+
+// WithSize sets the shape of the generated project.
+func (c *BenchCommand) WithSize(systems, containers, components int) *BenchCommand {
+	c.size = usecases.SyntheticProjectSize{Systems: systems, Containers: containers, Components: components}
+	return c
+}
+
+// WithIterations sets the number of warm builds.
+func (c *BenchCommand) WithIterations(n int) *BenchCommand {
+	c.iterations = n
+	return c
+}
+
+// WithKeep keeps the generated project instead of deleting it.
+func (c *BenchCommand) WithKeep(keep bool) *BenchCommand {
+	c.keep = keep
+	return c
+}
+
+// Execute generates a synthetic project in a temporary directory, runs one
+// cold build (empty diagram cache) and several warm builds (cache populated),
+// and reports timings and throughput.
+func (c *BenchCommand) Execute(ctx context.Context) error {
+	if err := c.size.Validate(); err != nil {
+		return err
+	}
+	if c.iterations < 1 {
+		return fmt.Errorf("iterations must be at least 1, got %d", c.iterations)
+	}
+
+	root, err := os.MkdirTemp("", "loko-bench-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	if c.keep {
+		fmt.Printf("Project: %s\n", root)
+	} else {
+		defer func() { _ = os.RemoveAll(root) }()
+	}
+
+	renderer := d2.NewRenderer()
+	withDiagrams := renderer.IsAvailable()
+
+	fmt.Printf("🏁 Benchmarking %d systems × %d containers × %d components (%d entities)\n",
+		c.size.Systems, c.size.Containers, c.size.Components, c.size.Entities())
+	if !withDiagrams {
+		fmt.Println("   d2 not found in PATH; measuring page generation only")
+	}
+	fmt.Println()
+
+	// Generate the project
+	projectRepo := filesystem.NewProjectRepository()
+	generate := usecases.NewGenerateSyntheticProject(projectRepo)
+	if withDiagrams {
+		generate.WithDiagramGenerator(d2.NewGenerator())
+	}
+
+	start := time.Now()
+	project, err := generate.Execute(ctx, root, c.size)
+	if err != nil {
+		return fmt.Errorf("failed to generate project: %w", err)
+	}
+	generateTime := time.Since(start)
+
+	start = time.Now()
+	systems, err := projectRepo.ListSystems(ctx, root)
+	if err != nil {
+		return fmt.Errorf("failed to load systems: %w", err)
+	}
+	loadTime := time.Since(start)
+
+	if !withDiagrams {
+		stripDiagrams(systems)
+	}
+
+	// Cold build: the renderer cache is empty
+	outputDir := filepath.Join(root, "dist")
+	coldTime, err := c.timeBuild(ctx, renderer, project, systems, outputDir)
+	if err != nil {
+		return err
+	}
+
+	// Warm builds reuse the renderer and its cache
+	warmTimes := make([]time.Duration, 0, c.iterations)
+	for range c.iterations {
+		d, err := c.timeBuild(ctx, renderer, project, systems, outputDir)
+		if err != nil {
+			return err
+		}
+		warmTimes = append(warmTimes, d)
+	}
+
+	c.printReport(generateTime, loadTime, coldTime, warmTimes)
+	return nil
+}
+
+// timeBuild runs one HTML build and returns its duration.
+func (c *BenchCommand) timeBuild(
+	ctx context.Context,
+	renderer *d2.Renderer,
+	project *entities.Project,
+	systems []*entities.System,
+	outputDir string,
+) (time.Duration, error) {
+	siteBuilder, err := html.NewBuilder()
+	if err != nil {
+		return 0, fmt.Errorf("failed to create site builder: %w", err)
+	}
+	buildDocs := usecases.NewBuildDocs(renderer, siteBuilder, cli.NewQuietProgressReporter())
+
+	start := time.Now()
+	if err := buildDocs.Execute(ctx, project, systems, outputDir); err != nil {
+		return 0, fmt.Errorf("build failed: %w", err)
+	}
+	return time.Since(start), nil
+}
+
+// printReport prints timings and entity throughput for each phase.
+func (c *BenchCommand) printReport(generateTime, loadTime, coldTime time.Duration, warmTimes []time.Duration) {
+	var total, fastest time.Duration
+	for i, d := range warmTimes {
+		total += d
+		if i == 0 || d < fastest {
+			fastest = d
+		}
+	}
+	mean := total / time.Duration(len(warmTimes))
+
+	entityCount := c.size.Entities()
+	row := func(label string, d time.Duration) {
+		fmt.Printf("  %-14s %12v %14.1f entities/s\n", label, d.Round(time.Millisecond), throughput(entityCount, d))
+	}
+
+	fmt.Printf("  %-14s %12s %25s\n", "Phase", "Time", "Throughput")
+	row("Generate", generateTime)
+	row("Load", loadTime)
+	row("Build (cold)", coldTime)
+	row("Build (warm)", mean)
+	fmt.Printf("\n  Warm builds: %d runs, mean %v, fastest %v\n",
+		len(warmTimes), mean.Round(time.Millisecond), fastest.Round(time.Millisecond))
+}
+
+// throughput returns entities processed per second.
+func throughput(entities int, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(entities) / d.Seconds()
+}
+
+// stripDiagrams removes diagrams from all entities so builds skip rendering.
+func stripDiagrams(systems []*entities.System) {
+	for _, sys := range systems {
+		sys.Diagram = nil
+		for _, container := range sys.Containers {
+			container.Diagram = nil
+			for _, component := range container.Components {
+				component.Diagram = nil
+			}
+		}
+	}
+}
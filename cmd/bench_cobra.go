@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Measure build performance on a synthetic project",
+	Long: `Generate a synthetic project of the given size in a temporary directory and
+time its builds.
+
+One cold build runs with an empty diagram cache, followed by --iterations warm
+builds that reuse rendered diagrams. Timings and throughput (entities per
+second) are reported for generation, loading and each build kind, so
+performance regressions can be quantified and compared across versions.
+
+Diagrams are rendered only when the d2 binary is installed.`,
+	GroupID: "building",
+	Example: `  loko bench
+  loko bench --systems 50 --containers 10 --components 10
+  loko bench --iterations 5 --keep`,
+	RunE: runBench,
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+	benchCmd.Flags().Int("systems", 10, "number of systems to generate")
+	benchCmd.Flags().Int("containers", 5, "containers per system")
+	benchCmd.Flags().Int("components", 5, "components per container")
+	benchCmd.Flags().Int("iterations", 3, "number of warm builds")
+	benchCmd.Flags().Bool("keep", false, "keep the generated project for inspection")
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	systems, _ := cmd.Flags().GetInt("systems")
+	containers, _ := cmd.Flags().GetInt("containers")
+	components, _ := cmd.Flags().GetInt("components")
+	iterations, _ := cmd.Flags().GetInt("iterations")
+	keep, _ := cmd.Flags().GetBool("keep")
+
+	return NewBenchCommand().
+		WithSize(systems, containers, components).
+		WithIterations(iterations).
+		WithKeep(keep).
+		Execute(cmd.Context())
+}
@@ -12,11 +12,13 @@ import (
 
 	"github.com/madstone-tech/loko/internal/adapters/ason"
 	"github.com/madstone-tech/loko/internal/adapters/cli"
-	"github.com/madstone-tech/loko/internal/adapters/d2"
 	"github.com/madstone-tech/loko/internal/adapters/encoding"
 	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/adapters/git"
 	"github.com/madstone-tech/loko/internal/adapters/html"
+	"github.com/madstone-tech/loko/internal/adapters/linkcheck"
 	"github.com/madstone-tech/loko/internal/adapters/markdown"
+	"github.com/madstone-tech/loko/internal/adapters/minify"
 	"github.com/madstone-tech/loko/internal/adapters/pdf"
 	"github.com/madstone-tech/loko/internal/core/usecases"
 )
@@ -26,7 +28,29 @@ type BuildCommand struct {
 	projectRoot string
 	clean       bool
 	outputDir   string
-	formats     []string // Output formats: html, markdown, pdf
+	formats     []string // Output formats: html, markdown, pdf, toon, json
+	markdown    usecases.MarkdownOptions
+	stages      usecases.BuildStages // Build stages to run; the zero value runs all
+	profiler    profiler
+
+	allowPlaintext bool // Build even when the project's sources are encrypted
+	redact         bool // Apply the [redaction] rules for a public-safe build
+
+	provenance bool   // Write a provenance statement next to the output
+	signingKey string // Sign the provenance statement with this Ed25519 key
+
+	noHooks bool // Skip the [hooks] pre_build and post_build commands
+
+	basePath   string // Overrides [site] base_path
+	prettyURLs bool   // Overrides [site] pretty_urls
+
+	strictLinks   bool          // Fail the build on broken links
+	externalLinks bool          // Also probe the site's external links
+	linkTimeout   time.Duration // How long an external link may take to answer
+	offline       bool          // Fail the build when the site loads external resources
+
+	progress usecases.ProgressReporter // Set by Execute; records to the build log
+	cache    usecases.BuildCache       // Set by Execute; emptied first with --clean
 }
 
 // NewBuildCommand creates a new build command.
@@ -39,7 +63,7 @@ func NewBuildCommand(projectRoot string) *BuildCommand {
 	}
 }
 
-// WithClean sets whether to rebuild everything (ignore cache).
+// WithClean empties the build cache first, so everything is rebuilt.
 func (c *BuildCommand) WithClean(clean bool) *BuildCommand {
 	c.clean = clean
 	return c
@@ -68,13 +92,138 @@ func (c *BuildCommand) WithFormat(format string) *BuildCommand {
 	return c
 }
 
+// WithMarkdownOptions sets the layout and dialect of the markdown output.
+func (c *BuildCommand) WithMarkdownOptions(opts usecases.MarkdownOptions) *BuildCommand {
+	c.markdown = opts
+	return c
+}
+
+// WithStages runs only the selected build stages, see usecases.BuildStages.
+func (c *BuildCommand) WithStages(stages usecases.BuildStages) *BuildCommand {
+	c.stages = stages
+	return c
+}
+
+// WithAllowPlaintext permits writing unencrypted output for a project whose
+// sources are encrypted at rest.
+func (c *BuildCommand) WithAllowPlaintext(allow bool) *BuildCommand {
+	c.allowPlaintext = allow
+	return c
+}
+
+// WithRedaction applies the [redaction] rules from loko.toml to the output.
+func (c *BuildCommand) WithRedaction(redact bool) *BuildCommand {
+	c.redact = redact
+	return c
+}
+
+// WithProvenance writes an in-toto provenance statement to the output
+// directory, signed when signingKey (an Ed25519 PEM private key) is set.
+func (c *BuildCommand) WithProvenance(provenance bool, signingKey string) *BuildCommand {
+	c.provenance = provenance || signingKey != ""
+	c.signingKey = signingKey
+	return c
+}
+
+// WithoutHooks skips the [hooks] pre_build and post_build commands.
+func (c *BuildCommand) WithoutHooks(skip bool) *BuildCommand {
+	c.noHooks = skip
+	return c
+}
+
+// WithRouting serves the HTML site under basePath and, if pretty is set, with
+// pretty URLs, overriding the [site] settings in loko.toml.
+func (c *BuildCommand) WithRouting(basePath string, pretty bool) *BuildCommand {
+	c.basePath = basePath
+	c.prettyURLs = pretty
+	return c
+}
+
+// WithLinkCheck fails the build when the HTML site has broken links and,
+// with external set, also probes its http(s) links, giving each timeout to
+// answer. Broken internal links are reported as warnings otherwise.
+func (c *BuildCommand) WithLinkCheck(strict, external bool, timeout time.Duration) *BuildCommand {
+	c.strictLinks = strict || external
+	c.externalLinks = external
+	c.linkTimeout = timeout
+	return c
+}
+
+// WithOffline fails the build when the HTML site loads scripts, styles,
+// fonts or images from external URLs, so it would not work air-gapped. Such
+// resources are reported as warnings otherwise.
+func (c *BuildCommand) WithOffline(offline bool) *BuildCommand {
+	c.offline = offline
+	return c
+}
+
+// WithProfiling writes a CPU profile, heap profile and execution trace to the
+// given paths (empty paths are skipped) and prints the time spent per phase.
+func (c *BuildCommand) WithProfiling(cpuProfile, memProfile, traceFile string) *BuildCommand {
+	c.profiler = profiler{cpuPath: cpuProfile, memPath: memProfile, tracePath: traceFile}
+	return c
+}
+
 // Execute runs the build command.
-func (c *BuildCommand) Execute(ctx context.Context) error {
-	projectRepo := filesystem.NewProjectRepository()
+func (c *BuildCommand) Execute(ctx context.Context) (err error) {
+	var timings *usecases.BuildTimings
+	if c.profiler.enabled() {
+		if err := c.profiler.start(); err != nil {
+			return err
+		}
+		defer func() {
+			if stopErr := c.profiler.stop(); stopErr != nil && err == nil {
+				err = fmt.Errorf("failed to write profiles: %w", stopErr)
+			}
+			if err == nil {
+				c.profiler.printFiles()
+			}
+		}()
+		timings = usecases.NewBuildTimings()
+	}
+	buildStart := time.Now()
+
+	c.progress = cli.NewProgressReporter()
+	if buildLog := c.startBuildLog(ctx); buildLog != nil {
+		c.progress = buildLog
+		defer func() { c.finishBuildLog(buildLog, err) }()
+	}
+
+	stopLoad := timings.Track(usecases.PhaseLoad)
+	projectRepo := newProjectRepository()
 	project, err := projectRepo.LoadProject(ctx, c.projectRoot)
 	if err != nil {
 		return fmt.Errorf("failed to load project: %w", err)
 	}
+	if err := ensurePlaintextAllowed(project, c.projectRoot, c.allowPlaintext); err != nil {
+		return err
+	}
+	if project.Config == nil {
+		project.Config = entities.DefaultProjectConfig()
+	}
+	if c.basePath != "" {
+		project.Config.BasePath = c.basePath
+	}
+	if c.prettyURLs {
+		project.Config.PrettyURLs = true
+	}
+
+	outputFormats := c.parseFormats()
+	if len(outputFormats) == 0 {
+		outputFormats = []usecases.OutputFormat{usecases.FormatHTML}
+	}
+
+	// Pre-build hooks run before sources are read so they can generate them.
+	hooks := newRunHooks(project)
+	var hookEnv usecases.HookContext
+	if !c.noHooks && (hooks.Has(usecases.HookPreBuild) || hooks.Has(usecases.HookPostBuild)) {
+		if hookEnv, err = c.hookContext(ctx, project, outputFormats); err != nil {
+			return err
+		}
+		if err := hooks.Execute(ctx, usecases.HookPreBuild, hookEnv); err != nil {
+			return err
+		}
+	}
 
 	c.setupTemplateEngine(project, projectRepo)
 
@@ -82,39 +231,130 @@ func (c *BuildCommand) Execute(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to list systems: %w", err)
 	}
+	stopLoad()
+	writeIDIndex(ctx, c.projectRoot, project, systems)
 	if len(systems) == 0 {
 		fmt.Println("No systems found to build")
 		return nil
 	}
 
-	outputFormats := c.parseFormats()
-	if len(outputFormats) == 0 {
-		outputFormats = []usecases.OutputFormat{usecases.FormatHTML}
+	// Redacted builds skip the timeline, which is reconstructed from the
+	// unredacted git history.
+	var redactor *usecases.RedactArchitecture
+	var timeline *entities.Timeline
+	if c.redact {
+		if project, systems, redactor, err = redactArchitecture(project, systems); err != nil {
+			return err
+		}
+	} else {
+		timeline = c.loadTimeline(ctx, project, systems)
+	}
+	readSource := maskingReader(sourceReader(ctx), redactor)
+
+	diagramRenderer, closeRenderer, err := newDiagramRenderer(ctx, project.Config)
+	if err != nil {
+		return err
 	}
+	defer closeRenderer()
 
-	buildDocs, err := c.createBuildUseCase(outputFormats)
+	relationships, err := loadRelationships(ctx, c.projectRoot, systems, redactor)
 	if err != nil {
 		return err
 	}
 
+	cache := newBuildCache(c.projectRoot)
+	if c.clean {
+		if err := cache.Clear(ctx); err != nil {
+			return err
+		}
+	}
+	c.cache = cache
+
+	buildDocs, err := c.createBuildUseCase(project, outputFormats, timeline, relationships, readSource, diagramRenderer)
+	if err != nil {
+		return err
+	}
+	buildDocs.WithTimings(timings).WithBuildCache(cache, diagramCacheVersion(ctx, project.Config, diagramRenderer))
+	if project.Config != nil && project.Config.Minify {
+		buildDocs.WithMinifier(minify.NewMinifier())
+	}
+
 	startTime := time.Now()
-	err = buildDocs.ExecuteWithFormats(ctx, project, systems, c.outputDir, usecases.BuildDocsOptions{Formats: outputFormats})
+	err = buildDocs.ExecuteWithFormats(ctx, project, systems, c.outputDir, usecases.BuildDocsOptions{Formats: outputFormats, Markdown: c.markdown, Stages: c.stages})
 	elapsed := time.Since(startTime)
 	if err != nil {
-		return fmt.Errorf("build failed: %w", err)
+		return withExitCode(ExitRender, fmt.Errorf("build failed: %w", err))
 	}
 
-	if containsFormat(outputFormats, usecases.FormatHTML) {
-		if err := c.renderMarkdown(ctx, project, systems); err != nil {
+	// The site is only checked when its pages were rebuilt.
+	builtPages := containsFormat(outputFormats, usecases.FormatHTML) && c.stages.Runs(usecases.StagePages)
+	if builtPages {
+		if err := c.renderMarkdown(ctx, project, systems, readSource); err != nil {
+			return err
+		}
+		if err := c.writeHealthBadge(ctx, project, systems); err != nil {
 			return err
 		}
 	}
 
 	fmt.Printf("✓ Build completed in %v\n", elapsed.Round(10*time.Millisecond))
 	fmt.Printf("✓ Output: %s\n", c.outputDir)
+	if skipped := c.stages.Skipped(); len(skipped) > 0 {
+		names := make([]string, len(skipped))
+		for i, stage := range skipped {
+			names[i] = string(stage)
+		}
+		fmt.Printf("  Skipped: %s\n", strings.Join(names, ", "))
+	}
+	if builtPages {
+		if err := c.checkLinks(ctx, project.Config.BasePath); err != nil {
+			return err
+		}
+		if err := c.checkOffline(ctx); err != nil {
+			return err
+		}
+	}
+	if c.provenance {
+		if err := c.writeProvenance(ctx, project, outputFormats, buildStart); err != nil {
+			return err
+		}
+	}
+	if !c.noHooks {
+		if err := hooks.Execute(ctx, usecases.HookPostBuild, hookEnv); err != nil {
+			return err
+		}
+	}
+	if timings != nil {
+		printTimings(timings, time.Since(buildStart))
+	}
 	return nil
 }
 
+// writeHealthBadge writes the documentation health badge and its JSON
+// endpoint to the output directory.
+func (c *BuildCommand) writeHealthBadge(ctx context.Context, project *entities.Project, systems []*entities.System) error {
+	health, err := usecases.NewComputeHealthScore().Execute(ctx, project, systems)
+	if err != nil {
+		return err
+	}
+	return usecases.WriteHealthBadge(c.outputDir, health)
+}
+
+// hookContext describes this build to the [hooks] commands.
+func (c *BuildCommand) hookContext(ctx context.Context, project *entities.Project, outputFormats []usecases.OutputFormat) (usecases.HookContext, error) {
+	hc, err := hookContext(ctx, c.projectRoot, project)
+	if err != nil {
+		return hc, err
+	}
+	if hc.OutputDir, err = filepath.Abs(c.outputDir); err != nil {
+		return hc, fmt.Errorf("failed to resolve output directory: %w", err)
+	}
+	for _, format := range outputFormats {
+		hc.Formats = append(hc.Formats, string(format))
+	}
+	return hc, nil
+}
+
 // setupTemplateEngine configures the template engine search paths on the repository.
 func (c *BuildCommand) setupTemplateEngine(project *entities.Project, projectRepo *filesystem.ProjectRepository) {
 	templateName := "standard-3layer"
@@ -131,27 +371,66 @@ func (c *BuildCommand) setupTemplateEngine(project *entities.Project, projectRep
 	if envDir, ok := os.LookupEnv("LOKO_TEMPLATE_DIR"); ok && envDir != "" {
 		templateEngine.AddSearchPath(envDir)
 	}
+	templateEngine.SetIconResolver(newIconStore())
 	projectRepo.SetTemplateEngine(templateEngine)
 }
 
+// loadTimeline reconstructs the architecture timeline from git history.
+// Timeline generation is best-effort: failures are reported as warnings.
+func (c *BuildCommand) loadTimeline(ctx context.Context, project *entities.Project, systems []*entities.System) *entities.Timeline {
+	timeline, err := usecases.NewBuildTimeline(git.NewHistory()).Execute(ctx, project, systems)
+	if err != nil {
+		fmt.Printf("Warning: skipping timeline: %v\n", err)
+		return nil
+	}
+	return timeline
+}
+
+// startBuildLog creates the log of this build in .loko/builds/. The build
+// runs without a log when it cannot be created.
+func (c *BuildCommand) startBuildLog(ctx context.Context) *usecases.BuildLogReporter {
+	logs := filesystem.NewBuildLogStore(c.projectRoot)
+	id, err := logs.Create(ctx)
+	if err != nil {
+		fmt.Printf("Warning: build log disabled: %v\n", err)
+		return nil
+	}
+	return usecases.NewBuildLogReporter(ctx, logs, id, cli.NewProgressReporter())
+}
+
+// finishBuildLog records the outcome of the build and, when it failed, points
+// at its log.
+func (c *BuildCommand) finishBuildLog(buildLog *usecases.BuildLogReporter, err error) {
+	buildLog.Finish(err)
+	if logErr := buildLog.Err(); logErr != nil {
+		fmt.Printf("Warning: build log incomplete: %v\n", logErr)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Build log: loko builds log %s\n", buildLog.ID())
+	}
+}
+
 // createBuildUseCase creates and configures the BuildDocs use case with required adapters.
-func (c *BuildCommand) createBuildUseCase(outputFormats []usecases.OutputFormat) (*usecases.BuildDocs, error) {
-	diagramRenderer := d2.NewRenderer()
+func (c *BuildCommand) createBuildUseCase(project *entities.Project, outputFormats []usecases.OutputFormat, timeline *entities.Timeline, relationships map[string][]entities.Relationship, readSource func(path string) ([]byte, error), diagramRenderer usecases.DiagramRenderer) (*usecases.BuildDocs, error) {
 	siteBuilder, err := html.NewBuilder()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create site builder: %w", err)
 	}
+	siteBuilder.WithTimeline(timeline).WithRelationships(relationships).WithSourceReader(readSource).WithStages(c.stages).WithBuildCache(c.cache)
+	if err := applySiteCustomization(siteBuilder, c.projectRoot, project.Config); err != nil {
+		return nil, err
+	}
 
-	progressReporter := cli.NewProgressReporter()
-	buildDocs := usecases.NewBuildDocs(diagramRenderer, siteBuilder, progressReporter)
+	buildDocs := usecases.NewBuildDocs(diagramRenderer, siteBuilder, c.progress).
+		WithDiagramAnnotations(diagramAnnotations(project)).
+		WithRelationships(relationships)
 
-	if containsFormat(outputFormats, usecases.FormatMarkdown) {
-		buildDocs.WithMarkdownBuilder(markdown.NewBuilder())
-	}
-	if containsFormat(outputFormats, usecases.FormatPDF) {
-		pdfRenderer := pdf.NewRenderer()
-		if !pdfRenderer.IsAvailable() {
-			return nil, fmt.Errorf(`PDF output requested but veve-cli is not installed
+	// The markdown builder and, where veve-cli is installed, the PDF renderer
+	// also serve the per-system exports requested in system frontmatter.
+	buildDocs.WithMarkdownBuilder(markdown.NewBuilder())
+	pdfRenderer := pdf.NewRenderer()
+	if containsFormat(outputFormats, usecases.FormatPDF) && !pdfRenderer.IsAvailable() {
+		return nil, fmt.Errorf(`PDF output requested but veve-cli is not installed
 
 veve-cli is required for PDF generation. Install it with:
 
@@ -169,7 +448,8 @@ Or build HTML/Markdown only:
   loko build --format html,markdown
 
 For more info: https://github.com/terrastruct/veve`)
-		}
+	}
+	if pdfRenderer.IsAvailable() {
 		buildDocs.WithPDFRenderer(pdfRenderer)
 	}
 	if containsFormat(outputFormats, usecases.FormatTOON) {
@@ -180,13 +460,217 @@ For more info: https://github.com/terrastruct/veve`)
 	return buildDocs, nil
 }
 
+// loadRelationships reads the relationships.toml entries of each system, keyed
+// by system ID, redacting them when redactor is set.
+func loadRelationships(ctx context.Context, projectRoot string, systems []*entities.System, redactor *usecases.RedactArchitecture) (map[string][]entities.Relationship, error) {
+	repo := filesystem.NewFilesystemRelationshipRepository()
+	relationships := make(map[string][]entities.Relationship, len(systems))
+	for _, system := range systems {
+		rels, err := repo.LoadRelationships(ctx, projectRoot, system.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load relationships for %s: %w", system.ID, err)
+		}
+		if redactor != nil {
+			rels = redactor.RedactRelationships(rels)
+		}
+		relationships[system.ID] = rels
+	}
+	return relationships, nil
+}
+
+// newBuildCache returns the build cache in .loko/cache/. It is emptied when
+// another version of loko uses it.
+func newBuildCache(projectRoot string) *filesystem.BuildCache {
+	return filesystem.NewBuildCache(projectRoot, appVersion+" "+appCommit)
+}
+
+// diagramCacheVersion returns the version of renderer that keys its cached
+// diagrams, or "" to render every diagram: when [d2] cache is off or the
+// renderer cannot tell its version.
+func diagramCacheVersion(ctx context.Context, config *entities.ProjectConfig, renderer usecases.DiagramRenderer) string {
+	if config != nil && !config.D2Cache {
+		return ""
+	}
+	versioned, ok := renderer.(interface {
+		Version(ctx context.Context) (string, error)
+	})
+	if !ok {
+		return ""
+	}
+	version, err := versioned.Version(ctx)
+	if err != nil {
+		return ""
+	}
+	return version
+}
+
+// writeIDIndex updates .loko/ids.json, which editors and agents read to
+// complete relationship targets. A failure is reported as a warning.
+func writeIDIndex(ctx context.Context, projectRoot string, project *entities.Project, systems []*entities.System) {
+	if _, err := usecases.NewWriteIDIndex(filesystem.NewIDIndexStore(projectRoot)).Execute(ctx, project, systems); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+}
+
+// diagramAnnotations returns the legend and metadata footer added to rendered
+// diagrams as configured in [d2], or nil when both are disabled.
+func diagramAnnotations(project *entities.Project) *usecases.AnnotateDiagram {
+	config := project.Config
+	if config == nil {
+		config = entities.DefaultProjectConfig()
+	}
+	if !config.DiagramLegend && !config.DiagramFooter {
+		return nil
+	}
+	return usecases.NewAnnotateDiagram(project.Name, appVersion, config.DiagramLegend, config.DiagramFooter)
+}
+
+// applySiteCustomization injects the [site] head, footer and analytics settings,
+// the [issues] link template and the [icons] overrides from loko.toml into every
+// page generated by siteBuilder, renders element pages with the [site.layouts]
+// templates, and lays the site out for its base path and URL style.
+func applySiteCustomization(siteBuilder *html.Builder, projectRoot string, config *entities.ProjectConfig) error {
+	if config == nil {
+		return nil
+	}
+
+	head, err := customMarkup(projectRoot, config.CustomHead, config.CustomHeadFile)
+	if err != nil {
+		return err
+	}
+	analytics, err := html.AnalyticsSnippet(config.AnalyticsProvider, config.AnalyticsID)
+	if err != nil {
+		return withExitCode(ExitConfig, fmt.Errorf("invalid [site] configuration: %w", err))
+	}
+	head = joinMarkup(head, analytics)
+
+	footer, err := customMarkup(projectRoot, config.CustomFooter, config.CustomFooterFile)
+	if err != nil {
+		return err
+	}
+	router, err := html.NewRouter(config.BasePath, config.PrettyURLs)
+	if err != nil {
+		return withExitCode(ExitConfig, fmt.Errorf("invalid [site] configuration: %w", err))
+	}
+
+	layouts, err := pageLayouts(projectRoot, config.PageLayouts)
+	if err != nil {
+		return err
+	}
+	if err := siteBuilder.WithLayouts(layouts); err != nil {
+		return withExitCode(ExitConfig, fmt.Errorf("invalid [site.layouts] configuration: %w", err))
+	}
+
+	siteBuilder.WithCustomization(head, footer).
+		WithIssueURLTemplate(config.IssueURLTemplate).
+		WithTechnologyIcons(entities.NewIconRegistry(config.TechnologyIcons)).
+		WithCustomFields(config.CustomFields).
+		WithRouter(router)
+	return nil
+}
+
+// pageLayouts reads the template files of [site.layouts], resolved relative
+// to the project root, by selector.
+func pageLayouts(projectRoot string, files map[string]string) (map[string]string, error) {
+	layouts := make(map[string]string, len(files))
+	for selector, file := range files {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(projectRoot, file)
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read layout %q: %w", selector, err)
+		}
+		layouts[selector] = string(content)
+	}
+	return layouts, nil
+}
+
+// customMarkup combines inline markup with the contents of an optional file
+// resolved relative to the project root.
+func customMarkup(projectRoot, inline, file string) (string, error) {
+	if file == "" {
+		return inline, nil
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(projectRoot, file)
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read custom site markup: %w", err)
+	}
+	return joinMarkup(inline, strings.TrimSpace(string(content))), nil
+}
+
+// joinMarkup joins non-empty markup fragments with newlines.
+func joinMarkup(parts ...string) string {
+	var nonEmpty []string
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, "\n")
+}
+
 // renderMarkdown renders markdown documentation files to HTML.
-func (c *BuildCommand) renderMarkdown(ctx context.Context, project *entities.Project, systems []*entities.System) error {
-	progressReporter := cli.NewProgressReporter()
+func (c *BuildCommand) renderMarkdown(ctx context.Context, project *entities.Project, systems []*entities.System, readSource func(path string) ([]byte, error)) error {
 	markdownRenderer := html.NewMarkdownRenderer("", "")
-	renderMarkdownDocs := usecases.NewRenderMarkdownDocs(markdownRenderer, progressReporter)
+	renderMarkdownDocs := usecases.NewRenderMarkdownDocs(markdownRenderer, c.progress).WithSourceReader(readSource)
 	if err := renderMarkdownDocs.Execute(ctx, project, systems, c.outputDir); err != nil {
-		return fmt.Errorf("markdown rendering failed: %w", err)
+		return withExitCode(ExitRender, fmt.Errorf("markdown rendering failed: %w", err))
+	}
+	return nil
+}
+
+// checkLinks reports the broken links of the HTML site served under
+// basePath, failing the build in strict mode.
+func (c *BuildCommand) checkLinks(ctx context.Context, basePath string) error {
+	checker := usecases.NewCheckSiteLinks().WithBasePath(basePath)
+	if c.externalLinks {
+		checker.WithExternalProber(linkcheck.NewHTTPProber(c.linkTimeout))
+	}
+	report, err := checker.Execute(ctx, c.outputDir)
+	if err != nil {
+		return fmt.Errorf("link check failed: %w", err)
+	}
+
+	checked := fmt.Sprintf("%d link(s)", report.Links)
+	if c.externalLinks {
+		checked += fmt.Sprintf(" and %d external URL(s)", report.External)
+	}
+	if len(report.Broken) == 0 {
+		fmt.Printf("✓ Links: %s checked, none broken\n", checked)
+		return nil
+	}
+
+	fmt.Printf("⚠ Links: %d broken of %s checked\n", len(report.Broken), checked)
+	for _, broken := range report.Broken {
+		fmt.Printf("  %s: %s (%s)\n", broken.Page, broken.Link, broken.Reason)
+	}
+	if c.strictLinks {
+		return withExitCode(ExitWarnings, fmt.Errorf("%d broken link(s) in %s", len(report.Broken), c.outputDir))
+	}
+	return nil
+}
+
+// checkOffline reports the external resources the HTML site loads.
+func (c *BuildCommand) checkOffline(ctx context.Context) error {
+	found, err := usecases.NewCheckSiteOffline().Execute(ctx, c.outputDir)
+	if err != nil {
+		return fmt.Errorf("offline check failed: %w", err)
+	}
+	if len(found) == 0 {
+		fmt.Println("✓ Offline: no external resources")
+		return nil
+	}
+
+	fmt.Printf("⚠ Offline: %d external resource(s) need network access\n", len(found))
+	for _, r := range found {
+		fmt.Printf("  %s: %s\n", r.File, r.URL)
+	}
+	if c.offline {
+		return withExitCode(ExitWarnings, fmt.Errorf("%d external resource(s) in %s", len(found), c.outputDir))
 	}
 	return nil
 }
@@ -207,6 +691,8 @@ func (c *BuildCommand) parseFormats() []usecases.OutputFormat {
 			format = usecases.FormatPDF
 		case "toon":
 			format = usecases.FormatTOON
+		case "json":
+			format = usecases.FormatJSONBundle
 		default:
 			fmt.Printf("Warning: unknown format %q, skipping\n", f)
 			continue
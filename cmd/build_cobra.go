@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/madstone-tech/loko/internal/adapters/linkcheck"
+	"github.com/madstone-tech/loko/internal/adapters/signing"
+	"github.com/madstone-tech/loko/internal/core/usecases"
 )
 
 var buildCmd = &cobra.Command{
@@ -18,24 +23,96 @@ Supported formats:
   markdown  Markdown README.md
   pdf       PDF document (requires veve-cli)
   toon      TOON format (token-optimized for LLMs)
+  json      model.json bundle of the whole model for external tools
+
+Markdown: --markdown-per-system writes docs/<system-id>/README.md per system,
+with its diagrams embedded, and a README.md linking them. --markdown-gfm adds
+GitHub-flavored task lists and mermaid component diagrams.
+
+Note: PDF generation requires veve-cli. Install from https://github.com/terrastruct/veve
+
+Profiling: --cpuprofile, --memprofile and --trace write pprof CPU and heap
+profiles and a Go execution trace, and print the time spent per build phase
+(project loading, diagram rendering, diagram file writes, page generation).
+Attach these files when reporting performance issues on large projects.
+
+Provenance: --provenance writes provenance.intoto.json to the output
+directory, an in-toto statement recording the source commit, the loko and d2
+versions and the SHA-256 of every input and output file. --sign-key signs it
+with an Ed25519 key; check it with loko verify.
+
+Hooks: the [hooks] pre_build and post_build commands in loko.toml run before
+and after the build, with LOKO_OUTPUT_DIR, LOKO_FORMATS and
+LOKO_CHANGED_ENTITIES describing it. --no-hooks skips them.
+
+URLs: --base-path serves the site under a path such as /architecture/, for a
+reverse proxy or a GitHub Pages project site, with absolute internal links;
+--pretty-urls writes systems/payments.html as systems/payments/index.html and
+links it as systems/payments/. Both default to the [site] settings.
 
-Note: PDF generation requires veve-cli. Install from https://github.com/terrastruct/veve`,
+Links: after an HTML build, every href and src of the site, diagram images
+included, and every search result URL is checked to resolve within the output
+directory, and broken links are listed with the page they are on.
+--check-links fails the build when any are broken; --check-external-links
+also requests each external http(s) link, waiting up to --link-timeout, and
+is meant for CI.
+
+Offline: the site embeds its styles, scripts and icons, and the pages,
+stylesheets and diagrams are checked for resources loaded from external URLs,
+such as analytics scripts or remote diagram icons. --offline fails the build
+when there are any, for sites deployed air-gapped.
+
+Stages: --only runs the named build stages and --skip leaves them out, for
+fast iteration on D2 sources or templates. The stages are diagrams (D2 to
+SVG), assets (CSS and JavaScript), pages (HTML pages and the other formats),
+search-index, exports (per-system exports) and minify. Skipped stages keep
+the output of the previous build; pages link to its diagrams.
+
+Cache: diagrams whose D2 source and renderer are unchanged, and element pages
+whose inputs are unchanged, are reused from the build cache in .loko/cache/.
+--clean empties the cache and rebuilds everything.`,
 	GroupID: "building",
 	Example: `  loko build
   loko build --clean
   loko build --format html,markdown --d2-theme dark-mauve
   loko build --format toon  # Token-efficient export for LLMs
-  loko build --output ./docs --d2-layout dagre`,
+  loko build --format markdown --markdown-per-system --markdown-gfm
+  loko build --output ./docs --d2-layout dagre
+  loko build --cpuprofile cpu.prof --memprofile mem.prof
+  loko build --provenance --sign-key loko-signing.pem
+  loko build --base-path /my-repo/ --pretty-urls  # GitHub Pages project site
+  loko build --check-external-links --link-timeout 5s  # In CI
+  loko build --offline  # Air-gapped deployment
+  loko build --only diagrams  # Re-render D2 sources only
+  loko build --skip search-index,assets`,
 	RunE: runBuild,
 }
 
 func init() {
 	rootCmd.AddCommand(buildCmd)
-	buildCmd.Flags().Bool("clean", false, "rebuild everything (ignore cache)")
+	buildCmd.Flags().Bool("clean", false, "empty the build cache and rebuild everything")
 	buildCmd.Flags().StringP("output", "o", "dist", "output directory")
-	buildCmd.Flags().StringSliceP("format", "f", []string{"html"}, "output formats (html,markdown,pdf)")
+	buildCmd.Flags().StringSliceP("format", "f", []string{"html"}, "output formats (html,markdown,pdf,toon,json)")
+	buildCmd.Flags().Bool("markdown-per-system", false, "write a markdown file per system under docs/ instead of a single README.md")
+	buildCmd.Flags().Bool("markdown-gfm", false, "add GitHub-flavored task lists and mermaid diagrams to the markdown output")
 	buildCmd.Flags().String("d2-theme", "neutral-default", "D2 diagram theme")
 	buildCmd.Flags().String("d2-layout", "elk", "D2 layout engine (dagre, elk, tala)")
+	buildCmd.Flags().String("cpuprofile", "", "write a pprof CPU profile to file")
+	buildCmd.Flags().String("memprofile", "", "write a pprof heap profile to file")
+	buildCmd.Flags().String("trace", "", "write a Go execution trace to file")
+	buildCmd.Flags().Bool("allow-plaintext", false, "build even if the project's sources are encrypted")
+	buildCmd.Flags().Bool("redact", false, "apply the [redaction] rules from loko.toml for a public-safe build")
+	buildCmd.Flags().Bool("provenance", false, "write an in-toto provenance statement to the output directory")
+	buildCmd.Flags().String("sign-key", "", "sign the provenance with this Ed25519 private key (default: $LOKO_SIGNING_KEY)")
+	buildCmd.Flags().Bool("no-hooks", false, "skip the [hooks] pre_build and post_build commands")
+	buildCmd.Flags().String("base-path", "", "URL path the site is served under, e.g. /architecture/ (default: [site] base_path)")
+	buildCmd.Flags().Bool("pretty-urls", false, "write pages as <name>/index.html and link them as <name>/")
+	buildCmd.Flags().Bool("check-links", false, "fail the build when the HTML site has broken links")
+	buildCmd.Flags().Bool("check-external-links", false, "also check external links, failing the build when any are broken")
+	buildCmd.Flags().Duration("link-timeout", linkcheck.DefaultTimeout, "how long an external link may take to answer")
+	buildCmd.Flags().Bool("offline", false, "fail the build when the HTML site loads resources from external URLs")
+	buildCmd.Flags().StringSlice("only", nil, "run only these build stages (diagrams,assets,pages,search-index,exports,minify)")
+	buildCmd.Flags().StringSlice("skip", nil, "skip these build stages")
 
 	// Bind flags to Viper keys so config/env values apply when flags aren't set.
 	_ = viper.BindPFlag("d2.theme", buildCmd.Flags().Lookup("d2-theme"))
@@ -46,6 +123,8 @@ func init() {
 	_ = buildCmd.RegisterFlagCompletionFunc("d2-theme", completeD2Themes)
 	_ = buildCmd.RegisterFlagCompletionFunc("d2-layout", completeD2Layouts)
 	_ = buildCmd.RegisterFlagCompletionFunc("format", completeFormats)
+	_ = buildCmd.RegisterFlagCompletionFunc("only", completeBuildStages)
+	_ = buildCmd.RegisterFlagCompletionFunc("skip", completeBuildStages)
 }
 
 // completeD2Themes returns available D2 diagram themes.
@@ -87,6 +166,19 @@ func completeFormats(_ *cobra.Command, _ []string, _ string) ([]string, cobra.Sh
 		"markdown\tMarkdown documentation",
 		"pdf\tPDF document (requires veve-cli)",
 		"toon\tTOON format (token-optimized for LLMs)",
+		"json\tJSON model bundle for external tools",
+	}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeBuildStages returns the build stages.
+func completeBuildStages(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return []string{
+		"diagrams\tRender D2 diagrams to SVG",
+		"assets\tWrite the site's CSS and JavaScript",
+		"pages\tGenerate HTML pages and the other formats",
+		"search-index\tWrite the site's search index",
+		"exports\tWrite per-system exports",
+		"minify\tMinify the site",
 	}, cobra.ShellCompDirectiveNoFileComp
 }
 
@@ -110,6 +202,53 @@ func runBuild(cmd *cobra.Command, args []string) error {
 		buildCommand.WithFormats(formats)
 	}
 
+	perSystem, _ := cmd.Flags().GetBool("markdown-per-system")
+	gfm, _ := cmd.Flags().GetBool("markdown-gfm")
+	buildCommand.WithMarkdownOptions(usecases.MarkdownOptions{PerSystem: perSystem, GitHubFlavored: gfm})
+
+	cpuProfile, _ := cmd.Flags().GetString("cpuprofile")
+	memProfile, _ := cmd.Flags().GetString("memprofile")
+	traceFile, _ := cmd.Flags().GetString("trace")
+	buildCommand.WithProfiling(cpuProfile, memProfile, traceFile)
+
+	if allow, _ := cmd.Flags().GetBool("allow-plaintext"); allow {
+		buildCommand.WithAllowPlaintext(true)
+	}
+	if redact, _ := cmd.Flags().GetBool("redact"); redact {
+		buildCommand.WithRedaction(true)
+	}
+
+	provenance, _ := cmd.Flags().GetBool("provenance")
+	signKey, _ := cmd.Flags().GetString("sign-key")
+	if signKey == "" && provenance {
+		signKey = os.Getenv(signing.EnvSigningKey)
+	}
+	buildCommand.WithProvenance(provenance, signKey)
+
+	if noHooks, _ := cmd.Flags().GetBool("no-hooks"); noHooks {
+		buildCommand.WithoutHooks(true)
+	}
+
+	basePath, _ := cmd.Flags().GetString("base-path")
+	prettyURLs, _ := cmd.Flags().GetBool("pretty-urls")
+	buildCommand.WithRouting(basePath, prettyURLs)
+
+	checkLinks, _ := cmd.Flags().GetBool("check-links")
+	checkExternal, _ := cmd.Flags().GetBool("check-external-links")
+	linkTimeout, _ := cmd.Flags().GetDuration("link-timeout")
+	buildCommand.WithLinkCheck(checkLinks, checkExternal, linkTimeout)
+
+	offline, _ := cmd.Flags().GetBool("offline")
+	buildCommand.WithOffline(offline)
+
+	only, _ := cmd.Flags().GetStringSlice("only")
+	skip, _ := cmd.Flags().GetStringSlice("skip")
+	stages, err := usecases.ParseBuildStages(only, skip)
+	if err != nil {
+		return withExitCode(ExitConfig, err)
+	}
+	buildCommand.WithStages(stages)
+
 	// d2-theme and d2-layout are available via viper.GetString("d2.theme") / viper.GetString("d2.layout")
 	// The build command will use these when the config system is fully wired to the D2 renderer.
 
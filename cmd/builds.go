@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// BuildsCommand lists the logs of past builds in .loko/builds/ or prints one.
+type BuildsCommand struct {
+	projectRoot string
+	action      string // "list" or "log"
+	id          string // Build whose log to print; empty prints the latest
+	asJSON      bool   // Print the log as JSON lines
+	out         io.Writer
+}
+
+// NewBuildsCommand creates a new builds command running action on build id.
+func NewBuildsCommand(projectRoot, action, id string) *BuildsCommand {
+	return &BuildsCommand{
+		projectRoot: projectRoot,
+		action:      action,
+		id:          id,
+		out:         os.Stdout,
+	}
+}
+
+// WithJSON prints the log entries as JSON lines, as they are stored.
+func (c *BuildsCommand) WithJSON(asJSON bool) *BuildsCommand {
+	c.asJSON = asJSON
+	return c
+}
+
+// Execute runs the builds command.
+func (c *BuildsCommand) Execute(ctx context.Context) error {
+	logs := filesystem.NewBuildLogStore(c.projectRoot)
+	ids, err := logs.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch c.action {
+	case "list":
+		if len(ids) == 0 {
+			fmt.Fprintf(c.out, "No build logs in %s\n", filesystem.BuildLogDir)
+			return nil
+		}
+		for i := len(ids) - 1; i >= 0; i-- {
+			entries, err := logs.Entries(ctx, ids[i])
+			if err != nil {
+				fmt.Fprintf(c.out, "%s  unreadable: %v\n", ids[i], err)
+				continue
+			}
+			fmt.Fprintf(c.out, "%s  %s\n", ids[i], buildOutcome(entries))
+		}
+		return nil
+
+	case "log":
+		id := c.id
+		if id == "" {
+			if len(ids) == 0 {
+				return fmt.Errorf("no build logs in %s", filesystem.BuildLogDir)
+			}
+			id = ids[len(ids)-1]
+		}
+		entries, err := logs.Entries(ctx, id)
+		if err != nil {
+			return err
+		}
+		if c.asJSON {
+			enc := json.NewEncoder(c.out)
+			for _, entry := range entries {
+				if err := enc.Encode(entry); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		for _, entry := range entries {
+			c.printEntry(entry)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown builds action %q", c.action)
+	}
+}
+
+// printEntry prints a log entry as a single line.
+func (c *BuildsCommand) printEntry(entry entities.BuildLogEntry) {
+	line := fmt.Sprintf("%s %-8s", entry.Time.Local().Format("15:04:05"), entry.Level)
+	if entry.Step != "" {
+		line += " " + entry.Step
+		if entry.Total > 0 {
+			line += fmt.Sprintf(" (%d/%d)", entry.Current, entry.Total)
+		}
+		line += ":"
+	}
+	fmt.Fprintf(c.out, "%s %s\n", line, entry.Message)
+}
+
+// buildOutcome summarises a build log by when the build started and how it
+// ended.
+func buildOutcome(entries []entities.BuildLogEntry) string {
+	if len(entries) == 0 {
+		return "no entries"
+	}
+	started := entries[0].Time.Local().Format("2006-01-02 15:04")
+	last := entries[len(entries)-1]
+	switch {
+	case last.Level == entities.BuildLogSuccess && last.Message == "build complete":
+		return started + "  ✓ complete"
+	case last.Level == entities.BuildLogError:
+		return started + "  ✗ " + last.Message
+	default:
+		return started + "  … unfinished"
+	}
+}
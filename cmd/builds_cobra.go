@@ -0,0 +1,44 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var buildsCmd = &cobra.Command{
+	Use:   "builds",
+	Short: "List past builds or print a build log",
+	Long: `Every loko build, and every build triggered through the API, records what it
+reported as JSON lines in .loko/builds/<id>.jsonl. The latest 50 logs are kept.`,
+	GroupID: "building",
+}
+
+var buildsListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the recorded builds, newest first",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return NewBuildsCommand(ProjectRoot, "list", "").Execute(cmd.Context())
+	},
+}
+
+var buildsLogCmd = &cobra.Command{
+	Use:   "log [ID]",
+	Short: "Print the log of a build, the latest by default",
+	Args:  cobra.MaximumNArgs(1),
+	Example: `  loko builds log
+  loko builds log 20261016-0003 --json | jq 'select(.level == "error")'`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		id := ""
+		if len(args) == 1 {
+			id = args[0]
+		}
+		return NewBuildsCommand(ProjectRoot, "log", id).WithJSON(asJSON).Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(buildsCmd)
+	buildsCmd.AddCommand(buildsListCmd)
+	buildsCmd.AddCommand(buildsLogCmd)
+	buildsLogCmd.Flags().Bool("json", false, "print the entries as JSON lines")
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/madstone-tech/loko/internal/adapters/ci"
+	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/adapters/git"
+	"github.com/madstone-tech/loko/internal/adapters/notify"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// AnnotateCommand posts an architecture diff and validation summary on a pull request.
+type AnnotateCommand struct {
+	projectRoot string
+	baseRef     string
+	target      ci.Target
+	dryRun      bool
+}
+
+// NewAnnotateCommand creates a new annotate command comparing against baseRef.
+func NewAnnotateCommand(projectRoot, baseRef string) *AnnotateCommand {
+	return &AnnotateCommand{
+		projectRoot: projectRoot,
+		baseRef:     baseRef,
+	}
+}
+
+// WithTarget overrides the provider, repository and pull request number
+// detected from the CI environment. Zero-valued fields are detected.
+func (c *AnnotateCommand) WithTarget(provider, repo string, number int) *AnnotateCommand {
+	c.target.Provider = provider
+	c.target.Repo = repo
+	c.target.Number = number
+	return c
+}
+
+// WithDryRun prints the comment instead of posting it.
+func (c *AnnotateCommand) WithDryRun(dryRun bool) *AnnotateCommand {
+	c.dryRun = dryRun
+	return c
+}
+
+// Execute builds the report and posts or prints it.
+func (c *AnnotateCommand) Execute(ctx context.Context) error {
+	annotate := usecases.NewAnnotatePullRequest(newProjectRepository(), git.NewHistory()).
+		WithGraphBuilder(usecases.NewBuildArchitectureGraphWithRelRepo(filesystem.NewFilesystemRelationshipRepository()))
+
+	if !c.dryRun {
+		target, err := ci.DetectTarget(c.target, os.Getenv)
+		if err != nil {
+			return err
+		}
+		commenter, err := ci.NewCommenter(target)
+		if err != nil {
+			return err
+		}
+		annotate.WithCommenter(commenter)
+	}
+
+	report, err := annotate.Execute(ctx, c.projectRoot, c.baseRef)
+	if err != nil {
+		return err
+	}
+
+	if c.dryRun {
+		fmt.Println(usecases.RenderPullRequestComment(report))
+		return nil
+	}
+
+	fmt.Printf("✓ Architecture report posted (%d new issue(s))\n", len(report.NewViolations))
+	if report.CommentURL != "" {
+		fmt.Printf("  %s\n", report.CommentURL)
+	}
+	return nil
+}
+
+// NotifyCommand notifies the owners of elements changed since a base revision.
+type NotifyCommand struct {
+	projectRoot string
+	baseRef     string
+	dryRun      bool
+}
+
+// NewNotifyCommand creates a new notify command comparing against baseRef.
+func NewNotifyCommand(projectRoot, baseRef string) *NotifyCommand {
+	return &NotifyCommand{
+		projectRoot: projectRoot,
+		baseRef:     baseRef,
+	}
+}
+
+// WithDryRun prints the notifications instead of sending them.
+func (c *NotifyCommand) WithDryRun(dryRun bool) *NotifyCommand {
+	c.dryRun = dryRun
+	return c
+}
+
+// Execute builds the notifications and sends or prints them.
+func (c *NotifyCommand) Execute(ctx context.Context) error {
+	repo := newProjectRepository()
+	notifyOwners := usecases.NewNotifyOwners(repo, git.NewHistory()).
+		WithGraphBuilder(usecases.NewBuildArchitectureGraphWithRelRepo(filesystem.NewFilesystemRelationshipRepository()))
+
+	if !c.dryRun {
+		project, err := repo.LoadProject(ctx, c.projectRoot)
+		if err != nil {
+			return fmt.Errorf("failed to load project: %w", err)
+		}
+		notifiers, err := notify.NewNotifiers(project.Config, os.Getenv)
+		if err != nil {
+			return err
+		}
+		for _, notifier := range notifiers {
+			notifyOwners.WithNotifier(notifier)
+		}
+	}
+
+	notifications, err := notifyOwners.Execute(ctx, c.projectRoot, c.baseRef)
+	if c.dryRun {
+		for _, notification := range notifications {
+			fmt.Println(usecases.RenderOwnerNotification(notification))
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(notifications) == 0 {
+		fmt.Println("No owned elements changed")
+		return nil
+	}
+	if !c.dryRun {
+		fmt.Printf("✓ Notified %d owner(s)\n", len(notifications))
+	}
+	return nil
+}
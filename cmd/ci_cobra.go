@@ -0,0 +1,78 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var ciCmd = &cobra.Command{
+	Use:     "ci",
+	Short:   "Continuous integration helpers",
+	Long:    "Commands for running loko in CI pipelines.",
+	GroupID: "building",
+}
+
+var ciAnnotateCmd = &cobra.Command{
+	Use:   "annotate",
+	Short: "Comment architecture changes on a pull request",
+	Long: `Compare the architecture at --base with the working tree and post a comment
+on the current pull request (GitHub) or merge request (GitLab) summarizing
+added and removed elements, new dependencies, and validation issues the
+change introduces. Later runs update the same comment.
+
+The provider, repository and pull request are detected from the CI
+environment. Tokens are read from GITHUB_TOKEN or GITLAB_TOKEN.`,
+	Example: `  loko ci annotate --base origin/main
+  loko ci annotate --base origin/main --dry-run
+  loko ci annotate --base main --provider github --repo acme/arch --pr 42`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		base, _ := cmd.Flags().GetString("base")
+		provider, _ := cmd.Flags().GetString("provider")
+		repo, _ := cmd.Flags().GetString("repo")
+		pr, _ := cmd.Flags().GetInt("pr")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		return NewAnnotateCommand(ProjectRoot, base).
+			WithTarget(provider, repo, pr).
+			WithDryRun(dryRun).
+			Execute(cmd.Context())
+	},
+}
+
+var ciNotifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Notify element owners of architecture changes",
+	Long: `Compare the architecture at --base with the working tree and send each owner
+a summary of the elements and dependencies added, removed or edited in their
+area. Owners are set with the owner frontmatter field; containers and
+components also belong to the owners of their parents.
+
+Notifications go to the channels in the [notifications] section of
+loko.toml: a JSON webhook (webhook_url) and email (smtp_host, email_from)
+for owners that are email addresses. SMTP credentials are read from
+LOKO_SMTP_USER and LOKO_SMTP_PASSWORD.`,
+	Example: `  loko ci notify --base origin/main
+  loko ci notify --base HEAD~1 --dry-run`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		base, _ := cmd.Flags().GetString("base")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		return NewNotifyCommand(ProjectRoot, base).
+			WithDryRun(dryRun).
+			Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(ciCmd)
+
+	ciCmd.AddCommand(ciAnnotateCmd)
+	ciAnnotateCmd.Flags().String("base", "", "git ref to compare against (e.g. origin/main)")
+	ciAnnotateCmd.Flags().String("provider", "", "code host: github or gitlab (default: detected)")
+	ciAnnotateCmd.Flags().String("repo", "", "repository (owner/name) or GitLab project ID (default: detected)")
+	ciAnnotateCmd.Flags().Int("pr", 0, "pull or merge request number (default: detected)")
+	ciAnnotateCmd.Flags().Bool("dry-run", false, "print the comment instead of posting it")
+	_ = ciAnnotateCmd.MarkFlagRequired("base")
+
+	ciCmd.AddCommand(ciNotifyCmd)
+	ciNotifyCmd.Flags().String("base", "", "git ref to compare against (e.g. origin/main)")
+	ciNotifyCmd.Flags().Bool("dry-run", false, "print the notifications instead of sending them")
+	_ = ciNotifyCmd.MarkFlagRequired("base")
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/madstone-tech/loko/internal/adapters/d2"
+	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// newDiagramGenerator returns a D2 generator that applies the [icons]
+// overrides and the [d2] style preset from the project's loko.toml and draws
+// the project's people, falling back to the defaults when the project cannot
+// be loaded.
+func newDiagramGenerator(ctx context.Context, repo *filesystem.ProjectRepository, projectRoot string) (*d2.Generator, error) {
+	generator := d2.NewGenerator()
+	project, err := repo.LoadProject(ctx, projectRoot)
+	if err != nil || project.Config == nil {
+		return generator, nil
+	}
+
+	generator.SetPeople(project.ListPeople())
+	generator.SetIcons(entities.NewIconRegistry(project.Config.TechnologyIcons))
+	style, err := entities.LookupDiagramStyle(project.Config.D2Style)
+	if err != nil {
+		return nil, withExitCode(ExitConfig, fmt.Errorf("invalid [d2] style: %w", err))
+	}
+	generator.SetStyle(style)
+	return generator, nil
+}
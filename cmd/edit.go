@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// EditCommand applies a frontmatter change to every element matching a selector.
+type EditCommand struct {
+	projectRoot string
+	selector    []string
+	set         []string // "key=value" pairs
+	addTags     []string
+	removeTags  []string
+	dryRun      bool
+	out         io.Writer
+}
+
+// NewEditCommand creates a new edit command for the elements matching the
+// selector terms, such as "tag:legacy".
+func NewEditCommand(projectRoot string, selector []string) *EditCommand {
+	return &EditCommand{projectRoot: projectRoot, selector: selector, out: os.Stdout}
+}
+
+// WithSet sets scalar frontmatter fields, each given as "key=value".
+func (c *EditCommand) WithSet(set []string) *EditCommand {
+	c.set = set
+	return c
+}
+
+// WithTags adds and removes tags.
+func (c *EditCommand) WithTags(add, remove []string) *EditCommand {
+	c.addTags = add
+	c.removeTags = remove
+	return c
+}
+
+// WithDryRun prints the changes instead of writing them.
+func (c *EditCommand) WithDryRun(dryRun bool) *EditCommand {
+	c.dryRun = dryRun
+	return c
+}
+
+// Execute runs the edit command.
+func (c *EditCommand) Execute(ctx context.Context) error {
+	selector, err := usecases.ParseElementSelector(c.selector)
+	if err != nil {
+		return err
+	}
+	req := usecases.BulkEditRequest{
+		Selector:   selector,
+		Set:        make(map[string]string),
+		AddTags:    c.addTags,
+		RemoveTags: c.removeTags,
+	}
+	for _, pair := range c.set {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid --set %q: expected key=value", pair)
+		}
+		req.Set[strings.TrimSpace(key)] = value
+	}
+
+	projectRepo := newProjectRepository()
+	systems, err := projectRepo.ListSystems(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to list systems: %w", err)
+	}
+
+	bulkEdit := usecases.NewBulkEdit(projectRepo)
+	edits, err := bulkEdit.Plan(systems, req)
+	if err != nil {
+		return err
+	}
+	if len(edits) == 0 {
+		_, _ = fmt.Fprintln(c.out, "No elements to change")
+		return nil
+	}
+
+	if c.dryRun {
+		for _, edit := range edits {
+			path := edit.Path
+			if rel, err := filepath.Rel(c.projectRoot, path); err == nil {
+				path = rel
+			}
+			_, _ = fmt.Fprintf(c.out, "%s (%s)\n%s\n", edit.Element, filepath.ToSlash(path), edit.Diff())
+		}
+		_, _ = fmt.Fprintf(c.out, "%d element(s) would change; run without --dry-run to apply\n", len(edits))
+		return nil
+	}
+
+	if err := bulkEdit.Apply(ctx, edits); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(c.out, "✓ Edited %d element(s)\n", len(edits))
+	return nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var editCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Change the frontmatter of many elements at once",
+	Long: `Apply the same frontmatter change to every system, container and component
+matching --selector, in one pass.
+
+Selectors are key:value terms; all of them must match:
+
+  type:<system|container|component>
+  tag:<tag>
+  technology:<technology>    compared case-insensitively
+  id:<glob>                  over the qualified ID, e.g. payments/*
+  <field>:<value>            a custom frontmatter field, e.g. owner:payments-team
+
+--set sets name, description, technology or a custom scalar field;
+--add-tag and --remove-tag change tags. Use --dry-run to review the changes
+first.`,
+	GroupID: "scaffolding",
+	Example: `  loko edit --selector tag:legacy --set technology="Java 8" --add-tag deprecated --dry-run
+  loko edit --selector type:container --selector owner:payments-team --set owner=platform-team
+  loko edit --selector id:payments/* --remove-tag beta`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		selector, _ := cmd.Flags().GetStringArray("selector")
+		set, _ := cmd.Flags().GetStringArray("set")
+		addTags, _ := cmd.Flags().GetStringArray("add-tag")
+		removeTags, _ := cmd.Flags().GetStringArray("remove-tag")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		return NewEditCommand(ProjectRoot, selector).
+			WithSet(set).
+			WithTags(addTags, removeTags).
+			WithDryRun(dryRun).
+			Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+
+	editCmd.Flags().StringArray("selector", nil, "select elements by key:value (repeatable; all must match)")
+	editCmd.Flags().StringArray("set", nil, "set a frontmatter field, as key=value (repeatable)")
+	editCmd.Flags().StringArray("add-tag", nil, "add a tag (repeatable)")
+	editCmd.Flags().StringArray("remove-tag", nil, "remove a tag (repeatable)")
+	editCmd.Flags().Bool("dry-run", false, "print the changes instead of writing them")
+	_ = editCmd.MarkFlagRequired("selector")
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/madstone-tech/loko/internal/adapters/age"
+	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/adapters/git"
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// defaultRecipientsFile lists the age or SSH public keys sources are encrypted
+// to when loko.toml does not set [encryption] recipients_file.
+const defaultRecipientsFile = ".loko/recipients.txt"
+
+// EncryptCommand encrypts or decrypts a project's sources at rest.
+type EncryptCommand struct {
+	projectRoot    string
+	recipientsFile string
+	decrypt        bool
+}
+
+// NewEncryptCommand creates a new encrypt command.
+func NewEncryptCommand(projectRoot string) *EncryptCommand {
+	return &EncryptCommand{projectRoot: projectRoot}
+}
+
+// WithRecipientsFile overrides the recipients file from loko.toml.
+func (c *EncryptCommand) WithRecipientsFile(path string) *EncryptCommand {
+	c.recipientsFile = path
+	return c
+}
+
+// WithDecrypt restores plaintext sources instead of encrypting them.
+func (c *EncryptCommand) WithDecrypt(decrypt bool) *EncryptCommand {
+	c.decrypt = decrypt
+	return c
+}
+
+// Execute encrypts (or decrypts) every Markdown and D2 file in the source directory.
+func (c *EncryptCommand) Execute(ctx context.Context) error {
+	project, err := filesystem.NewProjectRepository().LoadProject(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	srcDir := filepath.Join(c.projectRoot, project.SourceDir())
+
+	if c.decrypt {
+		identity := age.DefaultIdentity(os.Getenv)
+		if identity == "" {
+			return fmt.Errorf("no decryption key found; set %s to your age or SSH private key", age.EnvIdentity)
+		}
+		paths, err := usecases.NewDecryptProject(age.NewEncrypter("", identity)).Execute(ctx, srcDir)
+		if err != nil {
+			return fmt.Errorf("decryption failed: %w", err)
+		}
+		fmt.Printf("✓ Decrypted %d file(s) in %s\n", len(paths), srcDir)
+		return nil
+	}
+
+	recipients := c.recipientsFile
+	if recipients == "" {
+		recipients = defaultRecipientsFile
+		if project.Config != nil && project.Config.RecipientsFile != "" {
+			recipients = project.Config.RecipientsFile
+		}
+		recipients = filepath.Join(c.projectRoot, recipients)
+	}
+	if _, err := os.Stat(recipients); err != nil {
+		return fmt.Errorf("recipients file not found: %w (list age or SSH public keys there, one per line)", err)
+	}
+
+	paths, err := usecases.NewEncryptProject(age.NewEncrypter(recipients, "")).Execute(ctx, srcDir)
+	if err != nil {
+		return fmt.Errorf("encryption failed: %w", err)
+	}
+	fmt.Printf("✓ Encrypted %d file(s) in %s\n", len(paths), srcDir)
+	return nil
+}
+
+// newProjectRepository returns a filesystem project repository that takes
+// element dates and authors from git and decrypts sources encrypted at rest
+// when a decryption key is present.
+func newProjectRepository() *filesystem.ProjectRepository {
+	repo := filesystem.NewProjectRepository()
+	repo.SetHistory(git.NewHistory())
+	if identity := age.DefaultIdentity(os.Getenv); identity != "" {
+		repo.SetEncrypter(age.NewEncrypter("", identity))
+	}
+	return repo
+}
+
+// sourceReader returns a reader for element Markdown files that decrypts
+// sources encrypted at rest, for use by the HTML site builder.
+func sourceReader(ctx context.Context) func(path string) ([]byte, error) {
+	repo := newProjectRepository()
+	return func(path string) ([]byte, error) {
+		return repo.ReadSource(ctx, path)
+	}
+}
+
+// ensurePlaintextAllowed refuses to write or serve decrypted documentation for
+// a project whose sources are encrypted, unless allow is set.
+func ensurePlaintextAllowed(project *entities.Project, projectRoot string, allow bool) error {
+	if err := usecases.EnsurePlaintextAllowed(project, projectRoot, allow); err != nil {
+		if errors.Is(err, usecases.ErrEncryptedSources) {
+			return fmt.Errorf("%w (use --allow-plaintext to override)", err)
+		}
+		return err
+	}
+	return nil
+}
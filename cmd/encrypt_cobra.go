@@ -0,0 +1,46 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var encryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt architecture sources at rest",
+	Long: `Encrypt every Markdown and D2 file in the source directory with age, replacing
+each file with an encrypted ".age" counterpart. Use this for projects that
+document sensitive infrastructure details.
+
+Sources are encrypted to the age or SSH public keys listed in the recipients
+file ([encryption] recipients_file, default .loko/recipients.txt). Requires
+the age CLI (https://age-encryption.org).
+
+When LOKO_AGE_IDENTITY (or ~/.ssh/id_ed25519) holds a matching private key,
+loko decrypts sources transparently on load. build, export, serve and watch
+refuse to produce unencrypted output unless --allow-plaintext is given.`,
+	GroupID: "building",
+	Example: `  loko encrypt
+  loko encrypt --recipients-file ~/.ssh/id_ed25519.pub
+  loko decrypt`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		recipients, _ := cmd.Flags().GetString("recipients-file")
+		return NewEncryptCommand(ProjectRoot).WithRecipientsFile(recipients).Execute(cmd.Context())
+	},
+}
+
+var decryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Restore plaintext architecture sources",
+	Long: `Decrypt the ".age" sources created by loko encrypt back to plaintext files,
+using the private key in LOKO_AGE_IDENTITY (or ~/.ssh/id_ed25519).`,
+	GroupID: "building",
+	Example: `  loko decrypt
+  LOKO_AGE_IDENTITY=~/.config/loko/key.txt loko decrypt`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return NewEncryptCommand(ProjectRoot).WithDecrypt(true).Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(encryptCmd)
+	rootCmd.AddCommand(decryptCmd)
+	encryptCmd.Flags().String("recipients-file", "", "file of age or SSH public keys (default: [encryption] recipients_file)")
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// Exit codes of loko, so wrapper scripts and CI steps can branch on the kind
+// of failure rather than parse stderr.
+const (
+	ExitOK         = 0 // Success
+	ExitFailure    = 1 // Any failure not covered below, e.g. an I/O error
+	ExitConfig     = 2 // Invalid loko.toml, flags or arguments
+	ExitValidation = 3 // The model or a built site failed its checks
+	ExitRender     = 4 // Diagrams or documentation could not be rendered
+	ExitWarnings   = 5 // Completed, but with warnings made fatal by --strict, --check-links, --offline or lint
+)
+
+// exitError is an error that makes loko exit with code.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// withExitCode makes loko exit with code when err is returned by a command.
+// A nil err stays nil.
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{code: code, err: err}
+}
+
+// ExitCode returns the code loko exits with after Execute returned err.
+func ExitCode(err error) int {
+	var exitErr *exitError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &exitErr):
+		return exitErr.code
+	case errors.Is(err, entities.ErrInvalidConfig), errors.Is(err, entities.ErrSchemaTooNew):
+		return ExitConfig
+	default:
+		return ExitFailure
+	}
+}
+
+// tagUsageErrors makes the flag and argument errors of cmd and its
+// subcommands exit with ExitConfig.
+func tagUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return withExitCode(ExitConfig, err)
+	})
+	if args := cmd.Args; args != nil {
+		cmd.Args = func(cmd *cobra.Command, a []string) error {
+			return withExitCode(ExitConfig, args(cmd, a))
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		tagUsageErrors(sub)
+	}
+}
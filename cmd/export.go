@@ -0,0 +1,357 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/adapters/encoding"
+	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/adapters/structurizr"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// ExportCSVCommand exports the architecture as CSV inventory sheets.
+type ExportCSVCommand struct {
+	projectRoot    string
+	outputDir      string
+	allowPlaintext bool
+	redact         bool
+}
+
+// NewExportCSVCommand creates a new CSV export command.
+func NewExportCSVCommand(projectRoot string) *ExportCSVCommand {
+	return &ExportCSVCommand{
+		projectRoot: projectRoot,
+		outputDir:   "dist",
+	}
+}
+
+// WithOutputDir sets the output directory.
+func (c *ExportCSVCommand) WithOutputDir(dir string) *ExportCSVCommand {
+	if dir != "" {
+		c.outputDir = dir
+	}
+	return c
+}
+
+// WithAllowPlaintext permits exporting a project whose sources are encrypted at rest.
+func (c *ExportCSVCommand) WithAllowPlaintext(allow bool) *ExportCSVCommand {
+	c.allowPlaintext = allow
+	return c
+}
+
+// WithRedaction applies the [redaction] rules from loko.toml to the export.
+func (c *ExportCSVCommand) WithRedaction(redact bool) *ExportCSVCommand {
+	c.redact = redact
+	return c
+}
+
+// Execute writes systems.csv, containers.csv, components.csv and relationships.csv.
+func (c *ExportCSVCommand) Execute(ctx context.Context) error {
+	projectRepo := newProjectRepository()
+	project, err := projectRepo.LoadProject(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	if err := ensurePlaintextAllowed(project, c.projectRoot, c.allowPlaintext); err != nil {
+		return err
+	}
+
+	systems, err := projectRepo.ListSystems(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to list systems: %w", err)
+	}
+
+	var redactor *usecases.RedactArchitecture
+	if c.redact {
+		if project, systems, redactor, err = redactArchitecture(project, systems); err != nil {
+			return err
+		}
+	}
+
+	graphBuilder := usecases.NewBuildArchitectureGraphWithRelRepo(filesystem.NewFilesystemRelationshipRepository())
+	paths, err := usecases.NewExportInventory(encoding.NewCSVWriter()).
+		WithGraphBuilder(graphBuilder).
+		WithRedaction(redactor).
+		Execute(ctx, project, systems, c.outputDir)
+	if err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	for _, path := range paths {
+		fmt.Printf("✓ Wrote %s\n", path)
+	}
+	return nil
+}
+
+// ExportRAGCommand exports the architecture as a chunked corpus for
+// retrieval-augmented generation.
+type ExportRAGCommand struct {
+	projectRoot    string
+	outputDir      string
+	allowPlaintext bool
+	redact         bool
+}
+
+// NewExportRAGCommand creates a new RAG corpus export command.
+func NewExportRAGCommand(projectRoot string) *ExportRAGCommand {
+	return &ExportRAGCommand{
+		projectRoot: projectRoot,
+		outputDir:   "dist",
+	}
+}
+
+// WithOutputDir sets the output directory.
+func (c *ExportRAGCommand) WithOutputDir(dir string) *ExportRAGCommand {
+	if dir != "" {
+		c.outputDir = dir
+	}
+	return c
+}
+
+// WithAllowPlaintext permits exporting a project whose sources are encrypted at rest.
+func (c *ExportRAGCommand) WithAllowPlaintext(allow bool) *ExportRAGCommand {
+	c.allowPlaintext = allow
+	return c
+}
+
+// WithRedaction applies the [redaction] rules from loko.toml to the export.
+func (c *ExportRAGCommand) WithRedaction(redact bool) *ExportRAGCommand {
+	c.redact = redact
+	return c
+}
+
+// Execute writes chunks.jsonl and metadata.jsonl.
+func (c *ExportRAGCommand) Execute(ctx context.Context) error {
+	projectRepo := newProjectRepository()
+	project, err := projectRepo.LoadProject(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	if err := ensurePlaintextAllowed(project, c.projectRoot, c.allowPlaintext); err != nil {
+		return err
+	}
+
+	systems, err := projectRepo.ListSystems(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to list systems: %w", err)
+	}
+
+	var redactor *usecases.RedactArchitecture
+	if c.redact {
+		if project, systems, redactor, err = redactArchitecture(project, systems); err != nil {
+			return err
+		}
+	}
+
+	graphBuilder := usecases.NewBuildArchitectureGraphWithRelRepo(filesystem.NewFilesystemRelationshipRepository())
+	paths, err := usecases.NewExportCorpus(encoding.NewCorpusWriter()).
+		WithGraphBuilder(graphBuilder).
+		WithRedaction(redactor).
+		WithSourceReader(sourceReader(ctx)).
+		Execute(ctx, project, systems, c.outputDir)
+	if err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	for _, path := range paths {
+		fmt.Printf("✓ Wrote %s\n", path)
+	}
+	return nil
+}
+
+// ExportStructurizrCommand exports the architecture as a Structurizr DSL
+// workspace.
+type ExportStructurizrCommand struct {
+	projectRoot    string
+	output         string // File to write, or "-" for stdout
+	allowPlaintext bool
+	redact         bool
+}
+
+// NewExportStructurizrCommand creates a new Structurizr export command.
+func NewExportStructurizrCommand(projectRoot string) *ExportStructurizrCommand {
+	return &ExportStructurizrCommand{
+		projectRoot: projectRoot,
+		output:      "workspace.dsl",
+	}
+}
+
+// WithOutput sets the file to write, "-" writing to stdout.
+func (c *ExportStructurizrCommand) WithOutput(path string) *ExportStructurizrCommand {
+	if path != "" {
+		c.output = path
+	}
+	return c
+}
+
+// WithAllowPlaintext permits exporting a project whose sources are encrypted at rest.
+func (c *ExportStructurizrCommand) WithAllowPlaintext(allow bool) *ExportStructurizrCommand {
+	c.allowPlaintext = allow
+	return c
+}
+
+// WithRedaction applies the [redaction] rules from loko.toml to the export.
+func (c *ExportStructurizrCommand) WithRedaction(redact bool) *ExportStructurizrCommand {
+	c.redact = redact
+	return c
+}
+
+// Execute writes the workspace.
+func (c *ExportStructurizrCommand) Execute(ctx context.Context) (err error) {
+	projectRepo := newProjectRepository()
+	project, err := projectRepo.LoadProject(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	if err := ensurePlaintextAllowed(project, c.projectRoot, c.allowPlaintext); err != nil {
+		return err
+	}
+
+	systems, err := projectRepo.ListSystems(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to list systems: %w", err)
+	}
+
+	var redactor *usecases.RedactArchitecture
+	if c.redact {
+		if project, systems, redactor, err = redactArchitecture(project, systems); err != nil {
+			return err
+		}
+	}
+
+	out := os.Stdout
+	if c.output != "-" {
+		if err := os.MkdirAll(filepath.Dir(c.output), 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		if out, err = os.Create(c.output); err != nil {
+			return fmt.Errorf("failed to create %s: %w", c.output, err)
+		}
+		defer func() {
+			if closeErr := out.Close(); closeErr != nil && err == nil {
+				err = fmt.Errorf("failed to close %s: %w", c.output, closeErr)
+			}
+		}()
+	}
+
+	graphBuilder := usecases.NewBuildArchitectureGraphWithRelRepo(filesystem.NewFilesystemRelationshipRepository())
+	err = usecases.NewExportExternalModel(structurizr.NewDSLExporter()).
+		WithGraphBuilder(graphBuilder).
+		WithRedaction(redactor).
+		Execute(ctx, project, systems, out)
+	if err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	if c.output != "-" {
+		fmt.Printf("✓ Wrote %s\n", c.output)
+	}
+	return nil
+}
+
+// ExportModelCommand exports the whole architecture model as one JSON, TOON
+// or YAML document described by the "model" JSON Schema.
+type ExportModelCommand struct {
+	projectRoot    string
+	format         string
+	output         string // File to write, or "-" for stdout; empty writes model.<format>
+	allowPlaintext bool
+	redact         bool
+}
+
+// NewExportModelCommand creates a new model export command.
+func NewExportModelCommand(projectRoot string) *ExportModelCommand {
+	return &ExportModelCommand{
+		projectRoot: projectRoot,
+		format:      encoding.ModelFormatJSON,
+	}
+}
+
+// WithFormat sets the document format: json, toon or yaml.
+func (c *ExportModelCommand) WithFormat(format string) *ExportModelCommand {
+	if format != "" {
+		c.format = strings.ToLower(strings.TrimSpace(format))
+	}
+	return c
+}
+
+// WithOutput sets the file to write, "-" writing to stdout.
+func (c *ExportModelCommand) WithOutput(path string) *ExportModelCommand {
+	c.output = path
+	return c
+}
+
+// WithAllowPlaintext permits exporting a project whose sources are encrypted at rest.
+func (c *ExportModelCommand) WithAllowPlaintext(allow bool) *ExportModelCommand {
+	c.allowPlaintext = allow
+	return c
+}
+
+// WithRedaction applies the [redaction] rules from loko.toml to the export.
+func (c *ExportModelCommand) WithRedaction(redact bool) *ExportModelCommand {
+	c.redact = redact
+	return c
+}
+
+// Execute writes the model document.
+func (c *ExportModelCommand) Execute(ctx context.Context) error {
+	if !slices.Contains(encoding.ModelFormats, c.format) {
+		return withExitCode(ExitConfig, fmt.Errorf("unsupported model format %q (supported: %s)", c.format, strings.Join(encoding.ModelFormats, ", ")))
+	}
+
+	projectRepo := newProjectRepository()
+	project, err := projectRepo.LoadProject(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	if err := ensurePlaintextAllowed(project, c.projectRoot, c.allowPlaintext); err != nil {
+		return err
+	}
+
+	systems, err := projectRepo.ListSystems(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to list systems: %w", err)
+	}
+
+	var redactor *usecases.RedactArchitecture
+	if c.redact {
+		if project, systems, redactor, err = redactArchitecture(project, systems); err != nil {
+			return err
+		}
+	}
+	relationships, err := loadRelationships(ctx, c.projectRoot, systems, redactor)
+	if err != nil {
+		return err
+	}
+
+	bundle, err := usecases.NewBuildModelBundle().Execute(ctx, project, systems, relationships)
+	if err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+	data, err := encoding.EncodeModel(bundle, c.format)
+	if err != nil {
+		return err
+	}
+
+	if c.output == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	output := c.output
+	if output == "" {
+		output = "model." + c.format
+	}
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	fmt.Printf("✓ Wrote %s\n", output)
+	return nil
+}
@@ -1,12 +1,130 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/madstone-tech/loko/internal/adapters/encoding"
+)
 
 var exportCmd = &cobra.Command{
-	Use:     "export",
-	Short:   "Export documentation in various formats",
-	Long:    "Export the architecture documentation as HTML, Markdown, or PDF.",
+	Use:   "export",
+	Short: "Export documentation in various formats",
+	Long: `Export the architecture documentation as HTML, Markdown, PDF, CSV, a RAG
+corpus, Structurizr DSL or a machine-readable model.
+
+The csv format writes inventory sheets for spreadsheets and CMDBs:
+systems.csv, containers.csv, components.csv and relationships.csv.
+Columns are in a fixed order and rows are sorted by ID.
+
+The rag format writes a corpus for vector stores, one chunk per system,
+container and component: chunks.jsonl holds the text to embed and
+metadata.jsonl the ID, type, breadcrumbs and relationships of each chunk.
+
+The structurizr format writes the model as a Structurizr DSL workspace,
+workspace.dsl, for teams that also keep their architecture in Structurizr.
+
+The model format writes the whole project as one JSON document, model.json,
+described by the JSON Schema of loko schema dump model.`,
 	GroupID: "building",
+	Example: "  loko export --format csv\n  loko export --format csv --output ./inventory",
+	RunE:    runExport,
+}
+
+var exportCSVCmd = &cobra.Command{
+	Use:     "csv",
+	Short:   "Export as CSV inventory sheets",
+	Example: "  loko export csv\n  loko export csv --output ./inventory",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		return newExportCSVCommand(cmd, output).Execute(cmd.Context())
+	},
+}
+
+var exportRAGCmd = &cobra.Command{
+	Use:   "rag",
+	Short: "Export as a chunked corpus for RAG pipelines",
+	Long: `Export the model as a corpus for retrieval-augmented generation.
+
+Every system, container and component becomes one self-contained chunk: its
+name and place in the hierarchy, its fields, children and relationships, and
+the prose of its Markdown file. chunks.jsonl holds {"id", "text"} per chunk
+for embedding; metadata.jsonl holds the qualified ID, type, name, breadcrumbs,
+technology, tags, relationships and source file of each chunk, in the same
+order.`,
+	Example: "  loko export rag\n  loko export rag --output ./corpus --redact",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		return newExportRAGCommand(cmd, output).Execute(cmd.Context())
+	},
+}
+
+var exportStructurizrCmd = &cobra.Command{
+	Use:   "structurizr",
+	Short: "Export as a Structurizr DSL workspace",
+	Long: `Export the model as a Structurizr DSL workspace.
+
+Systems become software systems, containers containers and components
+components, with the relationships between them. The workspace has a system
+landscape and the context, container and component views of every element
+with children. Use --output - to write to stdout.`,
+	Example: "  loko export structurizr\n  loko export structurizr --output docs/workspace.dsl",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		return newExportStructurizrCommand(cmd, output).Execute(cmd.Context())
+	},
+}
+
+var exportModelCmd = &cobra.Command{
+	Use:   "model",
+	Short: "Export the whole model as one JSON, TOON or YAML document",
+	Long: `Export the whole project as one machine-readable document: its systems,
+containers and components, the relationships of relationships.toml, the
+metadata of every diagram and the architecture graph, sorted by ID.
+
+The document is described by a published JSON Schema, printed by
+loko schema dump model, and carries a schema_version so CI pipelines and
+other tools can consume the model without parsing Markdown. Every format
+uses the same field names. Use --output - to write to stdout.`,
+	Example: `  loko export model
+  loko export model --format yaml --output architecture.yaml
+  loko export model --output - | jq ".systems[].id"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+		return newExportModelCommand(cmd, format, output).Execute(cmd.Context())
+	},
+}
+
+// runExport exports in the format given by --format, or shows help without one.
+func runExport(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+
+	switch format = strings.ToLower(strings.TrimSpace(format)); format {
+	case "":
+		return cmd.Help()
+	case "csv":
+		return newExportCSVCommand(cmd, output).Execute(cmd.Context())
+	case "rag":
+		return newExportRAGCommand(cmd, output).Execute(cmd.Context())
+	case "structurizr":
+		return newExportStructurizrCommand(cmd, filepath.Join(output, "workspace.dsl")).Execute(cmd.Context())
+	case "model":
+		return newExportModelCommand(cmd, "json", filepath.Join(output, "model.json")).Execute(cmd.Context())
+	case "html", "markdown", "pdf":
+		buildCommand := NewBuildCommand(ProjectRoot)
+		buildCommand.WithOutputDir(output)
+		buildCommand.WithFormats([]string{format})
+		buildCommand.WithAllowPlaintext(allowPlaintext(cmd))
+		buildCommand.WithRedaction(redact(cmd))
+		return buildCommand.Execute(cmd.Context())
+	default:
+		return fmt.Errorf("unsupported export format %q (supported: html, markdown, pdf, csv, rag, structurizr, model)", format)
+	}
 }
 
 var exportHTMLCmd = &cobra.Command{
@@ -18,6 +136,8 @@ var exportHTMLCmd = &cobra.Command{
 		buildCommand := NewBuildCommand(ProjectRoot)
 		buildCommand.WithOutputDir(output)
 		buildCommand.WithFormats([]string{"html"})
+		buildCommand.WithAllowPlaintext(allowPlaintext(cmd))
+		buildCommand.WithRedaction(redact(cmd))
 		return buildCommand.Execute(cmd.Context())
 	},
 }
@@ -31,6 +151,8 @@ var exportMarkdownCmd = &cobra.Command{
 		buildCommand := NewBuildCommand(ProjectRoot)
 		buildCommand.WithOutputDir(output)
 		buildCommand.WithFormats([]string{"markdown"})
+		buildCommand.WithAllowPlaintext(allowPlaintext(cmd))
+		buildCommand.WithRedaction(redact(cmd))
 		return buildCommand.Execute(cmd.Context())
 	},
 }
@@ -44,12 +166,19 @@ var exportPDFCmd = &cobra.Command{
 		buildCommand := NewBuildCommand(ProjectRoot)
 		buildCommand.WithOutputDir(output)
 		buildCommand.WithFormats([]string{"pdf"})
+		buildCommand.WithAllowPlaintext(allowPlaintext(cmd))
+		buildCommand.WithRedaction(redact(cmd))
 		return buildCommand.Execute(cmd.Context())
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringP("format", "f", "", "export format (html, markdown, pdf, csv, rag, structurizr, model)")
+	exportCmd.Flags().StringP("output", "o", "dist", "output directory")
+	exportCmd.PersistentFlags().Bool("allow-plaintext", false, "export even if the project's sources are encrypted")
+	exportCmd.PersistentFlags().Bool("redact", false, "apply the [redaction] rules from loko.toml for a public-safe export")
+	_ = exportCmd.RegisterFlagCompletionFunc("format", completeExportFormats)
 
 	exportCmd.AddCommand(exportHTMLCmd)
 	exportHTMLCmd.Flags().StringP("output", "o", "dist", "output directory")
@@ -59,4 +188,77 @@ func init() {
 
 	exportCmd.AddCommand(exportPDFCmd)
 	exportPDFCmd.Flags().StringP("output", "o", "dist", "output directory")
+
+	exportCmd.AddCommand(exportCSVCmd)
+	exportCSVCmd.Flags().StringP("output", "o", "dist", "output directory")
+
+	exportCmd.AddCommand(exportRAGCmd)
+	exportRAGCmd.Flags().StringP("output", "o", "dist", "output directory")
+
+	exportCmd.AddCommand(exportStructurizrCmd)
+	exportStructurizrCmd.Flags().StringP("output", "o", "workspace.dsl", "output file, or - for stdout")
+
+	exportCmd.AddCommand(exportModelCmd)
+	exportModelCmd.Flags().StringP("format", "f", "json", "document format (json, toon, yaml)")
+	exportModelCmd.Flags().StringP("output", "o", "", "output file, or - for stdout (default model.<format>)")
+	_ = exportModelCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(encoding.ModelFormats, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// newExportCSVCommand creates a CSV export command from the export flags.
+func newExportCSVCommand(cmd *cobra.Command, output string) *ExportCSVCommand {
+	return NewExportCSVCommand(ProjectRoot).
+		WithOutputDir(output).
+		WithAllowPlaintext(allowPlaintext(cmd)).
+		WithRedaction(redact(cmd))
+}
+
+// newExportRAGCommand creates a RAG corpus export command from the export flags.
+func newExportRAGCommand(cmd *cobra.Command, output string) *ExportRAGCommand {
+	return NewExportRAGCommand(ProjectRoot).
+		WithOutputDir(output).
+		WithAllowPlaintext(allowPlaintext(cmd)).
+		WithRedaction(redact(cmd))
+}
+
+// newExportStructurizrCommand creates a Structurizr export command from the
+// export flags.
+func newExportStructurizrCommand(cmd *cobra.Command, output string) *ExportStructurizrCommand {
+	return NewExportStructurizrCommand(ProjectRoot).
+		WithOutput(output).
+		WithAllowPlaintext(allowPlaintext(cmd)).
+		WithRedaction(redact(cmd))
+}
+
+// newExportModelCommand creates a model export command from the export flags.
+func newExportModelCommand(cmd *cobra.Command, format, output string) *ExportModelCommand {
+	return NewExportModelCommand(ProjectRoot).
+		WithFormat(format).
+		WithOutput(output).
+		WithAllowPlaintext(allowPlaintext(cmd)).
+		WithRedaction(redact(cmd))
+}
+
+// allowPlaintext reports whether --allow-plaintext was given to an export command.
+func allowPlaintext(cmd *cobra.Command) bool {
+	allow, _ := cmd.Flags().GetBool("allow-plaintext")
+	return allow
+}
+
+// redact reports whether --redact was given to an export command.
+func redact(cmd *cobra.Command) bool {
+	redact, _ := cmd.Flags().GetBool("redact")
+	return redact
+}
+
+// completeExportFormats returns the formats accepted by export --format.
+func completeExportFormats(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return []string{
+		"html\tHTML documentation site",
+		"markdown\tMarkdown documentation",
+		"pdf\tPDF document (requires veve-cli)",
+		"csv\tCSV inventory sheets",
+		"rag\tChunked corpus for RAG pipelines",
+		"structurizr\tStructurizr DSL workspace",
+		"model\tWhole model as one JSON document",
+	}, cobra.ShellCompDirectiveNoFileComp
 }
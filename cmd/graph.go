@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// GraphCommand prints the relationships of the architecture graph, rolled
+// up to systems or containers on request.
+type GraphCommand struct {
+	projectRoot string
+	rollup      string
+	format      string
+	out         io.Writer
+}
+
+// NewGraphCommand creates a new graph command.
+func NewGraphCommand(projectRoot string) *GraphCommand {
+	return &GraphCommand{
+		projectRoot: projectRoot,
+		rollup:      usecases.RollupNone,
+		format:      "text",
+		out:         os.Stdout,
+	}
+}
+
+// WithRollup sets the level relationships are rolled up to: system,
+// container, or "" for none.
+func (c *GraphCommand) WithRollup(rollup string) *GraphCommand {
+	c.rollup = strings.ToLower(rollup)
+	return c
+}
+
+// WithFormat sets the output format: text, json or d2.
+func (c *GraphCommand) WithFormat(format string) *GraphCommand {
+	if format != "" {
+		c.format = strings.ToLower(format)
+	}
+	return c
+}
+
+// Execute prints the relationships.
+func (c *GraphCommand) Execute(ctx context.Context) error {
+	if c.rollup != usecases.RollupNone && !slices.Contains(usecases.RollupLevels, c.rollup) {
+		return withExitCode(ExitConfig, fmt.Errorf("unsupported rollup %q (supported: %s)", c.rollup, strings.Join(usecases.RollupLevels, ", ")))
+	}
+	switch c.format {
+	case "text", "json":
+	case "d2":
+		if c.rollup == usecases.RollupNone {
+			return withExitCode(ExitConfig, fmt.Errorf("--format d2 needs --rollup (supported: %s)", strings.Join(usecases.RollupLevels, ", ")))
+		}
+	default:
+		return withExitCode(ExitConfig, fmt.Errorf("unsupported format %q (supported: text, json, d2)", c.format))
+	}
+
+	projectRepo := newProjectRepository()
+	project, err := projectRepo.LoadProject(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	systems, err := projectRepo.ListSystems(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to list systems: %w", err)
+	}
+	graph, err := usecases.NewBuildArchitectureGraphWithRelRepo(filesystem.NewFilesystemRelationshipRepository()).
+		Execute(ctx, project, systems)
+	if err != nil {
+		return fmt.Errorf("failed to build architecture graph: %w", err)
+	}
+
+	if c.format == "d2" {
+		source, err := usecases.GenerateRollupDiagram(graph, c.rollup)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(c.out, source)
+		return err
+	}
+
+	edges, err := usecases.RollupGraph(graph, c.rollup)
+	if err != nil {
+		return err
+	}
+	if c.format == "json" {
+		encoder := json.NewEncoder(c.out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(edges); err != nil {
+			return fmt.Errorf("failed to encode relationships: %w", err)
+		}
+		return nil
+	}
+
+	if len(edges) == 0 {
+		fmt.Fprintln(c.out, "No relationships found")
+		return nil
+	}
+	for _, edge := range edges {
+		line := fmt.Sprintf("%s -> %s", edge.Source, edge.Target)
+		if len(edge.Descriptions) > 0 {
+			line += ": " + strings.Join(edge.Descriptions, "; ")
+		}
+		if edge.Count > 1 {
+			line += fmt.Sprintf(" (%d relationships)", edge.Count)
+		}
+		fmt.Fprintln(c.out, line)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"github.com/madstone-tech/loko/internal/core/usecases"
+	"github.com/spf13/cobra"
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Show the relationships of the architecture graph",
+	Long: `Show the relationships between the elements of the project, from
+frontmatter, relationships.toml and D2 diagrams.
+
+With --rollup, relationships are rolled up across boundaries: a relationship
+between components of two systems becomes one between the two systems
+(--rollup system), or between their containers (--rollup container), counting
+the relationships it stands for. Relationships inside one system or container
+are left out.
+
+--format d2 prints the rolled up relationships as a diagram. loko build
+renders the system rollup as the landscape diagram of the project.`,
+	Example: `  loko graph
+  loko graph --rollup system
+  loko graph --rollup container --format json
+  loko graph --rollup system --format d2 > landscape.d2`,
+	Args:    cobra.NoArgs,
+	GroupID: "building",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rollup, _ := cmd.Flags().GetString("rollup")
+		format, _ := cmd.Flags().GetString("format")
+		return NewGraphCommand(ProjectRoot).
+			WithRollup(rollup).
+			WithFormat(format).
+			Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(graphCmd)
+
+	graphCmd.Flags().String("rollup", "", "roll relationships up to systems or containers (system, container)")
+	graphCmd.Flags().StringP("format", "f", "text", "output format (text, json, d2)")
+	_ = graphCmd.RegisterFlagCompletionFunc("rollup", cobra.FixedCompletions(usecases.RollupLevels, cobra.ShellCompDirectiveNoFileComp))
+	_ = graphCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"text", "json", "d2"}, cobra.ShellCompDirectiveNoFileComp))
+}
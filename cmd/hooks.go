@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/madstone-tech/loko/internal/adapters/git"
+	"github.com/madstone-tech/loko/internal/adapters/shell"
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// newRunHooks returns the [hooks] commands configured for project, run
+// through the platform shell.
+func newRunHooks(project *entities.Project) *usecases.RunHooks {
+	return usecases.NewRunHooks(shell.NewHookRunner(), project.Config)
+}
+
+// hookContext describes a build or validation of project to its hooks. The
+// changed entities are those with uncommitted changes in git; outside a git
+// repository they are unknown and left empty.
+func hookContext(ctx context.Context, projectRoot string, project *entities.Project) (usecases.HookContext, error) {
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return usecases.HookContext{}, fmt.Errorf("failed to resolve project root: %w", err)
+	}
+	dirs := sourceDirs(root, project)
+	var changes *usecases.ChangeSet
+	if files, err := git.NewHistory().ChangedFiles(ctx, root); err == nil {
+		changes = usecases.NewChangeSet(dirs...)
+		for _, file := range files {
+			changes.Add(usecases.FileChangeEvent{Path: file, Op: "write"})
+		}
+	}
+	return usecases.NewHookContext(root, dirs, changes), nil
+}
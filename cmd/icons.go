@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/adapters/config"
+	"github.com/madstone-tech/loko/internal/adapters/icons"
+)
+
+// newIconStore returns the store of icon packs installed under the XDG data
+// directory (e.g. ~/.local/share/loko/icons), which also offers the packs
+// built into loko.
+func newIconStore() *icons.Store {
+	return icons.NewStore(config.NewXDGPathResolver().IconsDir())
+}
+
+// IconsInstallCommand installs an icon pack from a directory of SVG files or
+// a built-in pack.
+type IconsInstallCommand struct {
+	source string
+	name   string
+}
+
+// NewIconsInstallCommand creates a new icons install command.
+func NewIconsInstallCommand(source string) *IconsInstallCommand {
+	return &IconsInstallCommand{source: source}
+}
+
+// WithName installs the pack under name instead of the source's name.
+func (c *IconsInstallCommand) WithName(name string) *IconsInstallCommand {
+	c.name = name
+	return c
+}
+
+// Execute copies the icons into the icons directory.
+func (c *IconsInstallCommand) Execute(ctx context.Context) error {
+	pack, err := newIconStore().Install(ctx, c.source, c.name)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Installed icon pack %s (%d icons)\n", pack.Name, len(pack.Icons))
+	fmt.Printf("  Location: %s\n", pack.Dir)
+	fmt.Printf("  Reference its icons in templates as {{icon:%s/%s}}\n", pack.Name, pack.Icons[0])
+	return nil
+}
+
+// IconsListCommand lists the icon packs, or the icons of one pack.
+type IconsListCommand struct {
+	pack string
+}
+
+// NewIconsListCommand creates a command listing the icon packs, or the icons
+// of pack if it is not empty.
+func NewIconsListCommand(pack string) *IconsListCommand {
+	return &IconsListCommand{pack: pack}
+}
+
+// Execute prints each pack with its icon count, or the icons of the pack.
+func (c *IconsListCommand) Execute(ctx context.Context) error {
+	packs, err := newIconStore().List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, pack := range packs {
+		if c.pack != "" {
+			if pack.Name == c.pack {
+				for _, icon := range pack.Icons {
+					fmt.Printf("%s/%s\n", pack.Name, icon)
+				}
+				return nil
+			}
+			continue
+		}
+		location := "built-in"
+		if !pack.Builtin {
+			location = pack.Dir
+		}
+		fmt.Printf("%-20s %4d icons  %s\n", pack.Name, len(pack.Icons), location)
+	}
+	if c.pack != "" {
+		names := make([]string, len(packs))
+		for i, pack := range packs {
+			names[i] = pack.Name
+		}
+		return fmt.Errorf("unknown icon pack %q (available: %s)", c.pack, strings.Join(names, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var iconsCmd = &cobra.Command{
+	Use:   "icons",
+	Short: "Install and list icon packs for diagram templates",
+	Long: `Icon packs are sets of SVG icons that D2 and Markdown templates reference
+by name, as {{icon:essentials/person}} or just {{icon:person}} when a single
+pack has that icon. Scaffolding embeds each icon as a data URI, so diagrams
+render without network access.
+
+loko ships the essentials pack (person, browser, database, queue, ...) and the
+tech pack of technology badges. Installed packs live under the XDG data
+directory ($XDG_DATA_HOME/loko/icons, default ~/.local/share/loko/icons) and
+take precedence over built-in packs of the same name.`,
+	GroupID: "scaffolding",
+}
+
+var iconsInstallCmd = &cobra.Command{
+	Use:   "install PACK",
+	Short: "Install an icon pack from a directory of SVG files or a built-in pack",
+	Long: `Install the icon pack PACK, replacing an installed pack of the same name.
+
+PACK is either a directory, whose SVG files become the pack's icons named
+after their path without the .svg extension, or the name of a built-in pack,
+whose icons are written out so they can be edited.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  loko icons install ./aws-icons --name aws
+  loko icons install essentials`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		return NewIconsInstallCommand(args[0]).WithName(name).Execute(cmd.Context())
+	},
+}
+
+var iconsListCmd = &cobra.Command{
+	Use:     "list [PACK]",
+	Aliases: []string{"ls"},
+	Short:   "List icon packs, or the icons of a pack",
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pack := ""
+		if len(args) > 0 {
+			pack = args[0]
+		}
+		return NewIconsListCommand(pack).Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(iconsCmd)
+	iconsCmd.AddCommand(iconsInstallCmd)
+	iconsCmd.AddCommand(iconsListCmd)
+
+	iconsInstallCmd.Flags().String("name", "", "name of the installed pack (default: the directory name)")
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/adapters/plantuml"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// ImportPlantUMLCommand creates systems, containers, components and
+// relationships from PlantUML C4 diagrams.
+type ImportPlantUMLCommand struct {
+	projectRoot string
+	path        string
+	dryRun      bool
+}
+
+// NewImportPlantUMLCommand creates a command importing the diagrams in path,
+// a directory or a single file.
+func NewImportPlantUMLCommand(projectRoot, path string) *ImportPlantUMLCommand {
+	return &ImportPlantUMLCommand{projectRoot: projectRoot, path: path}
+}
+
+// WithDryRun lists what would be imported without writing anything.
+func (c *ImportPlantUMLCommand) WithDryRun(dryRun bool) *ImportPlantUMLCommand {
+	c.dryRun = dryRun
+	return c
+}
+
+// Execute imports the diagrams and reports the elements created and skipped.
+func (c *ImportPlantUMLCommand) Execute(ctx context.Context) error {
+	projectRepo := newProjectRepository()
+	project, err := projectRepo.LoadProject(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	if err := ensurePlaintextAllowed(project, c.projectRoot, false); err != nil {
+		return err
+	}
+
+	result, err := usecases.NewImportModel(plantuml.NewParser(), projectRepo).
+		WithRelationships(filesystem.NewFilesystemRelationshipRepository()).
+		WithDryRun(c.dryRun).
+		Execute(ctx, c.projectRoot, c.path)
+	if err != nil {
+		return err
+	}
+
+	verb := "Imported"
+	if c.dryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("✓ %s %d element(s) and %d relationship(s) from %s\n", verb, len(result.Created), result.Relationships, c.path)
+	for _, id := range result.Created {
+		fmt.Printf("  + %s\n", id)
+	}
+	if len(result.Skipped) > 0 {
+		fmt.Printf("  %d element(s) already existed and were left as they are\n", len(result.Skipped))
+	}
+	for _, warning := range result.Warnings {
+		fmt.Printf("⚠ %s\n", warning)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var importPlantUMLDryRun bool
+
+var importPlantUMLCmd = &cobra.Command{
+	Use:   "plantuml PATH",
+	Short: "Create elements and relationships from PlantUML C4 diagrams",
+	Long: `Read the C4-PlantUML diagrams (.puml, .plantuml, .pu, .wsd) in the directory
+PATH, or the file PATH, and create the elements and relationships they
+declare:
+  - System, SystemDb, SystemQueue and their _Ext variants become systems,
+    external for _Ext
+  - Container and Component macros become the containers and components of
+    the System_Boundary or Container_Boundary they are declared in; a boundary
+    stands for the system or container of the same alias or label, so a
+    container diagram can detail a system of a context diagram
+  - Rel, BiRel and their directional variants become relationships in
+    relationships.toml
+  - Person macros become key users of the systems they relate to
+
+Elements that already exist are left as they are, so importing again is safe.
+Anything that cannot be mapped, such as a container outside a system
+boundary, is reported as a warning.
+
+This built-in importer takes precedence over an importer plugin named
+"plantuml".`,
+	Example: `  loko import plantuml docs/c4
+  loko import plantuml context.puml --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return NewImportPlantUMLCommand(ProjectRoot, args[0]).
+			WithDryRun(importPlantUMLDryRun).
+			Execute(cmd.Context())
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importPlantUMLCmd)
+	importPlantUMLCmd.Flags().BoolVar(&importPlantUMLDryRun, "dry-run", false, "List what would be imported without writing anything")
+}
@@ -3,11 +3,16 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 
+	"github.com/madstone-tech/loko/internal/adapters/d2"
 	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/adapters/git"
 	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
 )
 
 // InitCommand scaffolds a new loko project.
@@ -15,6 +20,8 @@ type InitCommand struct {
 	projectName string
 	projectPath string
 	description string
+	template    string // built-in preset name or git repository URL
+	adopt       bool   // initialize inside an existing codebase
 }
 
 // NewInitCommand creates a new init command.
@@ -37,9 +44,36 @@ func (ic *InitCommand) WithPath(path string) *InitCommand {
 	return ic
 }
 
+// WithTemplate sets the project template: a built-in preset
+// (see entities.PresetNames) or the URL of a git repository to copy.
+func (ic *InitCommand) WithTemplate(template string) *InitCommand {
+	ic.template = template
+	return ic
+}
+
+// WithAdopt initializes the project inside an existing codebase. The project
+// path defaults to the current directory and, when no name was given, the name
+// is inferred from go.mod, package.json or the git remote.
+func (ic *InitCommand) WithAdopt(adopt bool) *InitCommand {
+	ic.adopt = adopt
+	return ic
+}
+
+// ProjectName returns the project name, including one inferred by --adopt.
+func (ic *InitCommand) ProjectName() string {
+	return ic.projectName
+}
+
 // Execute runs the init command.
-// Creates a new project directory with loko.toml and src/ directory.
+// Creates a new project directory with loko.toml and src/ directory, then
+// scaffolds the example model of the selected template, if any.
 func (ic *InitCommand) Execute(ctx context.Context) error {
+	if ic.adopt {
+		if err := ic.prepareAdopt(); err != nil {
+			return err
+		}
+	}
+
 	if ic.projectName == "" {
 		return fmt.Errorf("project name is required")
 	}
@@ -49,6 +83,16 @@ func (ic *InitCommand) Execute(ctx context.Context) error {
 		return fmt.Errorf("invalid project name: %w", err)
 	}
 
+	// Resolve the preset before touching the file system
+	var preset *entities.ProjectPreset
+	if ic.template != "" && !git.IsRepositoryURL(ic.template) {
+		p, err := entities.FindPreset(ic.template)
+		if err != nil {
+			return fmt.Errorf("%w %q (available: %v)", err, ic.template, entities.PresetNames())
+		}
+		preset = p
+	}
+
 	// Create project directory
 	absPath, err := filepath.Abs(ic.projectPath)
 	if err != nil {
@@ -76,5 +120,116 @@ func (ic *InitCommand) Execute(ctx context.Context) error {
 		return fmt.Errorf("failed to save project: %w", err)
 	}
 
+	if preset != nil {
+		uc := usecases.NewApplyProjectPreset(repo, filesystem.NewFilesystemRelationshipRepository()).
+			WithDiagramGenerator(d2.NewGenerator())
+		result, err := uc.Execute(ctx, absPath, preset)
+		if err != nil {
+			return fmt.Errorf("failed to apply template %s: %w", preset.Name, err)
+		}
+		fmt.Printf("✓ Template '%s': %d systems, %d containers, %d components, %d relationships\n",
+			preset.Name, result.Systems, result.Containers, result.Components, result.Relationships)
+	} else if ic.template != "" {
+		copied, err := copyTemplateRepository(ctx, ic.template, absPath)
+		if err != nil {
+			return fmt.Errorf("failed to apply template %s: %w", ic.template, err)
+		}
+		fmt.Printf("✓ Template '%s': %d files copied\n", ic.template, copied)
+	}
+
+	return nil
+}
+
+// prepareAdopt resolves the project path and name for --adopt and refuses to
+// run where a loko project already exists, so nothing is overwritten.
+func (ic *InitCommand) prepareAdopt() error {
+	if ic.projectPath == "" {
+		ic.projectPath = "."
+	}
+
+	if _, err := os.Stat(filepath.Join(ic.projectPath, "loko.toml")); err == nil {
+		return fmt.Errorf("%s already contains loko.toml; nothing to adopt", ic.projectPath)
+	}
+
+	if ic.projectName == "" {
+		name, source := filesystem.DetectProjectName(ic.projectPath)
+		if name == "" {
+			return fmt.Errorf("could not infer a project name; pass one explicitly")
+		}
+		fmt.Printf("✓ Detected project name '%s' from %s\n", name, source)
+		ic.projectName = name
+	}
+
 	return nil
 }
+
+// copyTemplateRepository clones a template repository and copies its files
+// into projectPath. The repository's .git directory and loko.toml are skipped
+// and existing files are never overwritten. Returns the number of files copied.
+func copyTemplateRepository(ctx context.Context, url, projectPath string) (int, error) {
+	cloneDir, err := os.MkdirTemp("", "loko-template-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(cloneDir) }()
+
+	repoDir := filepath.Join(cloneDir, "repo")
+	if err := git.Clone(ctx, url, repoDir); err != nil {
+		return 0, err
+	}
+
+	copied := 0
+	err = filepath.WalkDir(repoDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(repoDir, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(filepath.Join(projectPath, rel), 0755)
+		}
+		if rel == "loko.toml" || !d.Type().IsRegular() {
+			return nil
+		}
+
+		target := filepath.Join(projectPath, rel)
+		if _, err := os.Stat(target); err == nil {
+			return nil // never overwrite
+		}
+		if err := copyFile(path, target); err != nil {
+			return err
+		}
+		copied++
+		return nil
+	})
+	if err != nil {
+		return copied, fmt.Errorf("failed to copy template files: %w", err)
+	}
+
+	return copied, nil
+}
+
+// copyFile copies a regular file from src to dst.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
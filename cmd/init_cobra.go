@@ -7,14 +7,31 @@ import (
 )
 
 var initCmd = &cobra.Command{
-	Use:     "init <project-name>",
-	Short:   "Initialize a new loko project",
-	Long:    "Create a new C4 architecture documentation project with loko.toml configuration and directory structure.",
+	Use:   "init <project-name>",
+	Short: "Initialize a new loko project",
+	Long: `Create a new C4 architecture documentation project with loko.toml configuration and directory structure.
+
+Use --template to start from an example model instead of an empty project:
+
+  minimal        one system with a single container
+  monolith       layered web application with database, cache and background jobs
+  microservices  services behind an API gateway communicating over HTTP and events
+  data-platform  ingestion, processing, warehouse and BI layers
+
+--template also accepts a git repository URL; its files are copied into the new
+project (except .git and loko.toml) without overwriting anything.
+
+Use --adopt inside an existing codebase to add loko documentation alongside the
+code. The project name is inferred from go.mod, package.json or the git remote
+when omitted, and only loko.toml and src/ are created; existing files are left
+untouched.`,
 	GroupID: "scaffolding",
-	Args:    cobra.ExactArgs(1),
+	Args:    cobra.MaximumNArgs(1),
 	Example: `  loko init myproject
   loko init myproject --description "Payment system architecture"
-  loko init myproject --template serverless`,
+  loko init myproject --template microservices
+  loko init myproject --template https://github.com/acme/loko-template.git
+  loko init --adopt`,
 	RunE: runInit,
 }
 
@@ -22,13 +39,25 @@ func init() {
 	rootCmd.AddCommand(initCmd)
 	initCmd.Flags().StringP("description", "d", "", "project description")
 	initCmd.Flags().String("path", "", "project path (defaults to project name)")
-	initCmd.Flags().StringP("template", "t", "standard-3layer", "template to use")
+	initCmd.Flags().Bool("adopt", false, "initialize inside an existing repository (infers the project name)")
+	initCmd.Flags().StringP("template", "t", "", "project template: minimal, monolith, microservices, data-platform, or a git URL")
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
-	projectName := args[0]
+	adopt, _ := cmd.Flags().GetBool("adopt")
+	if len(args) == 0 && !adopt {
+		return fmt.Errorf("project name is required (or use --adopt in an existing repository)")
+	}
+
+	var projectName string
+	if len(args) > 0 {
+		projectName = args[0]
+	}
 
 	initCommand := NewInitCommand(projectName)
+	if adopt {
+		initCommand.WithPath(".").WithAdopt(true)
+	}
 
 	if desc, _ := cmd.Flags().GetString("description"); desc != "" {
 		initCommand.WithDescription(desc)
@@ -36,11 +65,14 @@ func runInit(cmd *cobra.Command, args []string) error {
 	if path, _ := cmd.Flags().GetString("path"); path != "" {
 		initCommand.WithPath(path)
 	}
+	if template, _ := cmd.Flags().GetString("template"); template != "" {
+		initCommand.WithTemplate(template)
+	}
 
 	if err := initCommand.Execute(cmd.Context()); err != nil {
 		return err
 	}
 
-	fmt.Printf("✓ Project '%s' initialized\n", projectName)
+	fmt.Printf("✓ Project '%s' initialized\n", initCommand.ProjectName())
 	return nil
 }
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// LintCommand checks the names of a project's elements against the [naming]
+// conventions of loko.toml.
+type LintCommand struct {
+	projectRoot string
+}
+
+// NewLintCommand creates a new lint command.
+func NewLintCommand(projectRoot string) *LintCommand {
+	return &LintCommand{projectRoot: projectRoot}
+}
+
+// Execute runs the lint command. It fails when an element breaks a naming
+// convention.
+func (c *LintCommand) Execute(ctx context.Context) error {
+	projectRepo := newProjectRepository()
+	project, err := projectRepo.LoadProject(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	lint, err := usecases.NewLintNaming(project.Config)
+	if err != nil {
+		return err
+	}
+	if !lint.HasRules() {
+		fmt.Println("No naming conventions configured; add a [naming] section to loko.toml")
+		return nil
+	}
+
+	systems, err := projectRepo.ListSystems(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to list systems: %w", err)
+	}
+
+	report := &usecases.ArchitectureReport{}
+	lint.Execute(systems, report)
+	if len(report.Issues) == 0 {
+		fmt.Println("✓ Every element name follows the naming conventions")
+		return nil
+	}
+	report.Print()
+	return withExitCode(ExitWarnings, fmt.Errorf("lint found %d naming issue(s)", len(report.Issues)))
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check element names against the project's naming conventions",
+	Long: `Check the names of systems, containers and components against the [naming]
+section of loko.toml, and suggest a fix for every violation:
+
+  id_case               "kebab" requires kebab-case directory names
+  max_name_length       longest allowed element name
+  technology_separator  separator of technology lists, e.g. ","
+  [naming.domain_prefixes]
+                        prefix required of the systems of a domain
+
+loko lint exits non-zero when an element breaks a convention. loko validate
+reports the same issues as warnings.`,
+	GroupID: "building",
+	Args:    cobra.NoArgs,
+	Example: `  loko lint
+  loko lint --project ./myproject`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return NewLintCommand(ProjectRoot).Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+}
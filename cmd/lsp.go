@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/madstone-tech/loko/internal/adapters/d2"
+	"github.com/madstone-tech/loko/internal/lsp"
+)
+
+// LSPCommand starts the language server.
+type LSPCommand struct {
+	projectRoot string
+}
+
+// NewLSPCommand creates a new LSP command.
+func NewLSPCommand(projectRoot string) *LSPCommand {
+	return &LSPCommand{
+		projectRoot: projectRoot,
+	}
+}
+
+// Execute runs the language server over stdio until the editor exits it.
+func (c *LSPCommand) Execute(ctx context.Context) error {
+	// Editors address documents by absolute file URI
+	root, err := filepath.Abs(c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to resolve project root: %w", err)
+	}
+
+	server := lsp.NewServer(root, newProjectRepository(), d2.NewD2Parser(), os.Stdin, os.Stdout)
+
+	// Handle graceful shutdown
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	serverErrChan := make(chan error, 1)
+	go func() {
+		serverErrChan <- server.Run(ctx)
+	}()
+
+	select {
+	case <-sigChan:
+		return nil
+	case err := <-serverErrChan:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
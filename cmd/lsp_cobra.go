@@ -0,0 +1,20 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Start the language server",
+	Long: `Start a Language Server Protocol server over stdio for editing the model by
+hand. It reports frontmatter and D2 errors as you type, completes
+relationship targets, jumps from a relationship to the target's
+component.md and shows hover documentation.`,
+	GroupID: "serving",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return NewLSPCommand(ProjectRoot).Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lspCmd)
+}
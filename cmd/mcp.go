@@ -9,6 +9,8 @@ import (
 
 	"github.com/madstone-tech/loko/internal/adapters/d2"
 	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
 	"github.com/madstone-tech/loko/internal/mcp"
 	"github.com/madstone-tech/loko/internal/mcp/tools"
 )
@@ -16,6 +18,10 @@ import (
 // MCPCommand starts the MCP server.
 type MCPCommand struct {
 	projectRoot string
+	role        string // Overrides [permissions] mcp_role when set
+	session     string // Stages changes in .loko/staging/<session> when set
+	sandbox     bool
+	sandboxDirs []string // Roots the sandbox permits besides the project root
 }
 
 // NewMCPCommand creates a new MCP command.
@@ -25,19 +31,74 @@ func NewMCPCommand(projectRoot string) *MCPCommand {
 	}
 }
 
+// WithRole sets the session's role ("reader" or "editor"), overriding
+// [permissions] mcp_role in loko.toml.
+func (c *MCPCommand) WithRole(role string) *MCPCommand {
+	c.role = role
+	return c
+}
+
+// WithStaging stages the session's changes in .loko/staging/<session> instead
+// of writing them to the project, until they are committed with the
+// commit_changes tool.
+func (c *MCPCommand) WithStaging(session string) *MCPCommand {
+	c.session = session
+	return c
+}
+
+// WithSandbox confines the paths that tool calls pass to the project root and
+// roots, rejecting the others.
+func (c *MCPCommand) WithSandbox(roots ...string) *MCPCommand {
+	c.sandbox = true
+	c.sandboxDirs = roots
+	return c
+}
+
 // Execute runs the MCP server.
 func (c *MCPCommand) Execute(ctx context.Context) error {
 	// Create repository
-	repo := filesystem.NewProjectRepository()
+	repo := newProjectRepository()
+
+	policy, err := c.toolPolicy(ctx, repo)
+	if err != nil {
+		return err
+	}
 
 	// Create MCP server
 	server := mcp.NewServer(c.projectRoot, os.Stdin, os.Stdout)
 
+	diagramGenerator, err := newDiagramGenerator(ctx, repo, c.projectRoot)
+	if err != nil {
+		return err
+	}
+
 	// Register all tools
-	if err := registerTools(server, repo); err != nil {
+	if err := registerTools(server, repo, diagramGenerator); err != nil {
 		return fmt.Errorf("failed to register tools: %w", err)
 	}
 
+	if c.session != "" {
+		if err := c.enableStaging(ctx, server, repo); err != nil {
+			return err
+		}
+	}
+
+	if c.sandbox {
+		sandbox, err := newPathSandbox(c.projectRoot, c.sandboxDirs)
+		if err != nil {
+			return err
+		}
+		server.SetSandbox(sandbox.Resolve)
+	}
+
+	// Record mutating tool calls in .loko/audit.log
+	server.SetAuditor(newAuditRecorder(ctx, c.projectRoot), tools.IsMutating)
+
+	// Restrict the session to the tools its role and allowlists permit
+	server.SetToolPolicy(func(name string) bool {
+		return policy.Allows(name, tools.IsMutating(name))
+	})
+
 	// Signal to stderr that we're ready (empty line - MCP clients may check for this)
 	// This allows Claude Code to detect that the server has initialized
 	fmt.Fprintln(os.Stderr)
@@ -63,11 +124,20 @@ func (c *MCPCommand) Execute(ctx context.Context) error {
 	}
 }
 
+// newPathSandbox creates the sandbox of a server rooted at projectRoot and
+// the extra roots.
+func newPathSandbox(projectRoot string, extra []string) (*usecases.PathSandbox, error) {
+	sandbox, err := usecases.NewPathSandbox(append([]string{projectRoot}, extra...)...)
+	if err != nil {
+		return nil, withExitCode(ExitConfig, err)
+	}
+	return sandbox, nil
+}
+
 // registerTools registers all MCP tools with the server.
-func registerTools(server *mcp.Server, repo *filesystem.ProjectRepository) error {
-	// Create diagram renderer and generator
+func registerTools(server *mcp.Server, repo *filesystem.ProjectRepository, diagramGenerator *d2.Generator) error {
+	// Create diagram renderer
 	renderer := d2.NewRenderer()
-	diagramGenerator := d2.NewGenerator()
 
 	// Relationship repository — persists relationships.toml per system.
 	relRepo := filesystem.NewFilesystemRelationshipRepository()
@@ -90,6 +160,10 @@ func registerTools(server *mcp.Server, repo *filesystem.ProjectRepository) error
 		tools.NewValidateToolFull(repo, relRepo),
 		tools.NewValidateDiagramTool(renderer),
 		tools.NewQueryDependenciesToolFull(repo, relRepo, graphCache),
+		tools.NewGetDependenciesTool(repo, relRepo, graphCache),
+		tools.NewGetDependentsTool(repo, relRepo, graphCache),
+		tools.NewFindPathTool(repo, relRepo, graphCache),
+		tools.NewImpactAnalysisTool(repo, relRepo, graphCache),
 		tools.NewQueryRelatedComponentsToolFull(repo, relRepo),
 		tools.NewAnalyzeCouplingToolFull(repo, relRepo),
 		tools.NewSearchElementsTool(repo),
@@ -98,6 +172,7 @@ func registerTools(server *mcp.Server, repo *filesystem.ProjectRepository) error
 		tools.NewCreateRelationshipTool(relRepo, repo, graphCache),
 		tools.NewListRelationshipsTool(relRepo, repo),
 		tools.NewDeleteRelationshipTool(relRepo, repo, graphCache),
+		tools.NewProposeArchitectureTool(repo, relRepo, diagramGenerator, graphCache),
 	}
 
 	for _, tool := range toolList {
@@ -108,3 +183,53 @@ func registerTools(server *mcp.Server, repo *filesystem.ProjectRepository) error
 
 	return nil
 }
+
+// enableStaging opens the session's staging area, points the server's tool
+// calls at it and registers the tools that review, commit and discard it.
+func (c *MCPCommand) enableStaging(ctx context.Context, server *mcp.Server, repo *filesystem.ProjectRepository) error {
+	srcDir := "src"
+	if project, err := repo.LoadProject(ctx, c.projectRoot); err == nil {
+		srcDir = project.SourceDir()
+	}
+	area, err := usecases.NewStagingArea(c.projectRoot, srcDir, c.session)
+	if err != nil {
+		return err
+	}
+	if err := area.Open(); err != nil {
+		return fmt.Errorf("failed to open staging area: %w", err)
+	}
+	server.SetStagingRoot(area.Root())
+	fmt.Fprintf(os.Stderr, "Staging changes in %s\n", area.Root())
+
+	graphCache := server.GetGraphCache()
+	for _, tool := range []mcp.Tool{
+		tools.NewStagedChangesTool(area),
+		tools.NewCommitChangesTool(area, graphCache),
+		tools.NewDiscardChangesTool(area, graphCache),
+	} {
+		if err := server.RegisterTool(tool); err != nil {
+			return fmt.Errorf("failed to register tool %q: %w", tool.Name(), err)
+		}
+	}
+	return nil
+}
+
+// toolPolicy builds the session's tool policy from [permissions] in loko.toml
+// and the --role flag.
+func (c *MCPCommand) toolPolicy(ctx context.Context, repo *filesystem.ProjectRepository) (entities.ToolPolicy, error) {
+	var policy entities.ToolPolicy
+	role := c.role
+	if project, err := repo.LoadProject(ctx, c.projectRoot); err == nil && project.Config != nil {
+		if role == "" {
+			role = project.Config.MCPRole
+		}
+		policy.Allow = project.Config.MCPAllowTools
+		policy.Deny = project.Config.MCPDenyTools
+	}
+
+	var err error
+	if policy.Role, err = entities.ParseRole(role); err != nil {
+		return policy, fmt.Errorf("invalid MCP role: %w", err)
+	}
+	return policy, nil
+}
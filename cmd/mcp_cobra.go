@@ -18,6 +18,10 @@ var mcpCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(mcpCmd)
 	mcpCmd.Flags().String("env", "", "environment variable (KEY=VALUE)")
+	mcpCmd.Flags().String("role", "", "session role: reader or editor (default: [permissions] mcp_role)")
+	mcpCmd.Flags().String("stage", "", "stage changes in .loko/staging/<session> for review instead of writing src/")
+	mcpCmd.Flags().Bool("sandbox", false, "reject tool calls whose paths leave the project root")
+	mcpCmd.Flags().StringSlice("sandbox-root", nil, "additional directory tool calls may use (implies --sandbox)")
 }
 
 func runMCP(cmd *cobra.Command, args []string) error {
@@ -28,5 +32,12 @@ func runMCP(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	return NewMCPCommand(ProjectRoot).Execute(cmd.Context())
+	role, _ := cmd.Flags().GetString("role")
+	session, _ := cmd.Flags().GetString("stage")
+	mcpCommand := NewMCPCommand(ProjectRoot).WithRole(role).WithStaging(session)
+	sandbox, _ := cmd.Flags().GetBool("sandbox")
+	if roots, _ := cmd.Flags().GetStringSlice("sandbox-root"); sandbox || len(roots) > 0 {
+		mcpCommand.WithSandbox(roots...)
+	}
+	return mcpCommand.Execute(cmd.Context())
 }
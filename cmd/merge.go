@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/madstone-tech/loko/internal/adapters/cli"
+	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// MergeCommand lists likely duplicate components, or merges one into another.
+type MergeCommand struct {
+	projectRoot string
+	keep        string
+	duplicate   string
+	yes         bool
+}
+
+// NewMergeCommand creates a new merge command. Without keep and duplicate it
+// lists the likely duplicates.
+func NewMergeCommand(projectRoot, keep, duplicate string, yes bool) *MergeCommand {
+	return &MergeCommand{
+		projectRoot: projectRoot,
+		keep:        keep,
+		duplicate:   duplicate,
+		yes:         yes,
+	}
+}
+
+// Execute runs the merge command.
+func (c *MergeCommand) Execute(ctx context.Context) error {
+	projectRepo := newProjectRepository()
+	systems, err := projectRepo.ListSystems(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to list systems: %w", err)
+	}
+
+	if c.keep == "" {
+		candidates := usecases.NewFindDuplicates().Execute(systems)
+		if len(candidates) == 0 {
+			fmt.Println("✓ No likely duplicate components found")
+			return nil
+		}
+		fmt.Printf("🔍 %d likely duplicate pair(s)\n\n", len(candidates))
+		for _, candidate := range candidates {
+			fmt.Printf("  %s  %s\n    %s\n", candidate.A, candidate.B, candidate.Reason)
+		}
+		fmt.Println("\nMerge a pair with: loko merge <keep> <duplicate>")
+		return nil
+	}
+
+	merger := usecases.NewMergeComponents(projectRepo, filesystem.NewFilesystemRelationshipRepository()).
+		WithTrash(filesystem.NewTrash(c.projectRoot))
+	plan, err := merger.Plan(systems, c.keep, c.duplicate)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🔀 Merge %s into %s\n\n", plan.Duplicate, plan.Keep)
+	for _, change := range plan.Changes {
+		fmt.Printf("  • %s\n", change)
+	}
+	fmt.Println()
+	if !c.yes && !cli.NewPrompts(bufio.NewReader(os.Stdin)).PromptYesNo("Merge?", false) {
+		fmt.Println("Merge cancelled")
+		return nil
+	}
+
+	if err := merger.Apply(ctx, c.projectRoot, plan); err != nil {
+		return fmt.Errorf("failed to merge: %w", err)
+	}
+	fmt.Printf("✓ Merged %s into %s\n", plan.Duplicate, plan.Keep)
+	fmt.Println("  The duplicate was moved to the trash; see loko trash list")
+	return nil
+}
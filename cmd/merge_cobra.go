@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var mergeYes bool
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge [keep] [duplicate]",
+	Short: "Find and merge duplicate components",
+	Long: `Without arguments, list components that likely model the same thing in
+different containers: components with the same or similar names, or with
+similar descriptions.
+
+With two component IDs, merge the duplicate into the component kept:
+  - empty fields are filled and tags, dependencies, issues, relationships
+    and code annotations are combined
+  - relationships to the duplicate, in frontmatter and relationships.toml,
+    are rewritten to the component kept
+  - the duplicate's ID is recorded under aliases so references to it keep
+    resolving
+  - the duplicate's directory is moved to the trash (see loko trash)
+
+IDs are qualified (system/container/component) or unique component IDs.`,
+	GroupID: "scaffolding",
+	Example: `  loko merge                                       # List likely duplicates
+  loko merge shop/api/order-store shop/worker/orders
+  loko merge order-store orders --yes              # Merge without asking`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 && len(args) != 2 {
+			return fmt.Errorf("expected no arguments or <keep> <duplicate>, got %d argument(s)", len(args))
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var keep, duplicate string
+		if len(args) == 2 {
+			keep, duplicate = args[0], args[1]
+		}
+		return NewMergeCommand(ProjectRoot, keep, duplicate, mergeYes).Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mergeCmd)
+	mergeCmd.Flags().BoolVarP(&mergeYes, "yes", "y", false, "Merge without asking")
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// MigrateCommand upgrades a project to the current schema version.
+type MigrateCommand struct {
+	projectRoot string
+	dryRun      bool
+	check       bool
+}
+
+// NewMigrateCommand creates a new migrate command.
+func NewMigrateCommand(projectRoot string) *MigrateCommand {
+	return &MigrateCommand{projectRoot: projectRoot}
+}
+
+// WithDryRun lists the changes without writing them.
+func (c *MigrateCommand) WithDryRun(dryRun bool) *MigrateCommand {
+	c.dryRun = dryRun
+	return c
+}
+
+// WithCheck writes nothing and fails if the project needs migrating.
+func (c *MigrateCommand) WithCheck(check bool) *MigrateCommand {
+	c.check = check
+	return c
+}
+
+// Execute runs the migrate command.
+func (c *MigrateCommand) Execute(ctx context.Context) error {
+	project, err := newProjectRepository().LoadProject(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+
+	result, err := usecases.NewMigrateProject(c.projectRoot, project.Config).
+		WithDryRun(c.dryRun || c.check).
+		Execute(ctx)
+	if err != nil {
+		return err
+	}
+	if result.From == result.To {
+		fmt.Printf("✓ Project is at the current schema version (%d)\n", result.To)
+		return nil
+	}
+	if c.check {
+		return withExitCode(ExitValidation, fmt.Errorf("project schema version %d is older than %d; run loko migrate", result.From, result.To))
+	}
+
+	fmt.Printf("🔄 Schema version %d → %d\n\n", result.From, result.To)
+	for _, applied := range result.Applied {
+		fmt.Printf("  • %s\n", applied)
+	}
+	fmt.Println()
+	for _, change := range result.Changes {
+		fmt.Printf("  %-8s  %s\n", change.Op, change.Path)
+	}
+	fmt.Println()
+
+	if c.dryRun {
+		fmt.Println("Dry run: no files were changed")
+		return nil
+	}
+	fmt.Printf("✓ Migrated to schema version %d\n", result.To)
+	fmt.Printf("  The original files are in %s\n", result.BackupDir)
+	return nil
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the project to the current schema version",
+	Long: `Upgrade the layout and frontmatter of a project written for an older
+version of loko to the current one, and record the new version as
+schema_version in loko.toml. A loko.toml without schema_version is at
+version 1.
+
+Every file changed or removed is first copied to
+.loko/backups/migrate-<timestamp>/, so a migration can be undone by copying
+the files back.`,
+	GroupID: "scaffolding",
+	Example: `  loko migrate --dry-run   # List the changes without writing them
+  loko migrate
+  loko migrate --check     # Fail in CI if the project needs migrating`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		check, _ := cmd.Flags().GetBool("check")
+		return NewMigrateCommand(ProjectRoot).
+			WithDryRun(dryRun).
+			WithCheck(check).
+			Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.Flags().Bool("dry-run", false, "list the changes without writing them")
+	migrateCmd.Flags().Bool("check", false, "write nothing and exit non-zero if the project needs migrating")
+}
@@ -4,8 +4,10 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/madstone-tech/loko/internal/adapters/ason"
@@ -16,11 +18,17 @@ import (
 	"github.com/madstone-tech/loko/internal/core/usecases"
 )
 
-// NewCommand creates new C4 entities (system, container, component).
+// NewCommand creates new C4 entities (person, system, container, component).
 type NewCommand struct {
-	entityType   string // "system", "container", "component"
+	entityType   string // "person", "system", "container", "component"
 	entityName   string
-	parentName   string // For container/component: parent system/container
+	parentName   string            // For container/component: parent system/container
+	systemName   string            // For component: system of the parent container
+	components   []string          // For container: components to create in it
+	archetype    string            // For container: [archetypes] entry of loko.toml providing defaults
+	domain       string            // For system: dot-separated domain, e.g. "payments.cards"
+	external     bool              // For person: outside the organization
+	uses         map[string]string // For person: elements used, with descriptions
 	description  string
 	technology   string
 	projectRoot  string
@@ -43,6 +51,44 @@ func (nc *NewCommand) WithParent(parent string) *NewCommand {
 	return nc
 }
 
+// WithSystem sets the system of a component's parent container, needed
+// when containers of several systems share its name.
+func (nc *NewCommand) WithSystem(system string) *NewCommand {
+	nc.systemName = system
+	return nc
+}
+
+// WithComponents sets the components created in a new container.
+func (nc *NewCommand) WithComponents(names []string) *NewCommand {
+	nc.components = names
+	return nc
+}
+
+// WithArchetype sets the archetype a container is created from.
+func (nc *NewCommand) WithArchetype(name string) *NewCommand {
+	nc.archetype = name
+	return nc
+}
+
+// WithDomain sets the domain a system is created in.
+func (nc *NewCommand) WithDomain(domain string) *NewCommand {
+	nc.domain = domain
+	return nc
+}
+
+// WithExternal marks a person as outside the organization, e.g. a customer.
+func (nc *NewCommand) WithExternal(external bool) *NewCommand {
+	nc.external = external
+	return nc
+}
+
+// WithUses sets the systems, containers or components a person uses, with
+// the description of each relationship.
+func (nc *NewCommand) WithUses(uses map[string]string) *NewCommand {
+	nc.uses = uses
+	return nc
+}
+
 // WithDescription sets the entity description.
 func (nc *NewCommand) WithDescription(desc string) *NewCommand {
 	nc.description = desc
@@ -105,7 +151,9 @@ func (nc *NewCommand) Execute(ctx context.Context) error {
 			templateName = "standard-3layer"
 		}
 	}
-	if err := nc.validateTemplate(templateName); err != nil {
+	if nc.entityType == "person" {
+		templateName = "" // People are not scaffolded from templates
+	} else if err := nc.validateTemplate(templateName); err != nil {
 		return err
 	}
 
@@ -142,6 +190,18 @@ func (nc *NewCommand) Execute(ctx context.Context) error {
 		}
 	}
 
+	if nc.entityType == "container" {
+		for _, name := range nc.components {
+			component := NewNewCommand("component", name).
+				WithProjectRoot(nc.projectRoot).
+				WithParent(result.EntityID).
+				WithSystem(req.ParentPath[0])
+			if err := component.Execute(ctx); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -157,16 +217,38 @@ func (nc *NewCommand) buildScaffoldRequest(ctx context.Context, templateName str
 	}
 
 	switch nc.entityType {
+	case "person":
+		req.External = nc.external
+		req.Relationships = nc.uses
+	case "system":
+		req.Domain = nc.domain
 	case "container":
 		if nc.parentName == "" {
 			return nil, fmt.Errorf("parent system name is required for container")
 		}
-		req.ParentPath = []string{nc.parentName}
+		systems, err := nc.listSystems(ctx)
+		if err != nil {
+			return nil, err
+		}
+		system, err := usecases.FindSystem(systems, nc.parentName)
+		if err != nil {
+			return nil, err
+		}
+		req.ParentPath = []string{system.ID}
+		if nc.archetype != "" {
+			if err := nc.applyArchetype(ctx, req); err != nil {
+				return nil, err
+			}
+		}
 	case "component":
 		if nc.parentName == "" {
 			return nil, fmt.Errorf("parent container name is required for component")
 		}
-		req.ParentPath = nc.resolveComponentParent(ctx)
+		parentPath, err := nc.resolveComponentParent(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.ParentPath = parentPath
 		// T055: set technology-specific content template for component .md file
 		req.ContentTemplate = nc.componentTemplateName()
 	}
@@ -180,9 +262,15 @@ func (nc *NewCommand) executeScaffold(ctx context.Context, req *usecases.Scaffol
 	templateEngine := nc.createTemplateEngine(templateName)
 	repo.SetTemplateEngine(templateEngine)
 
+	diagramGenerator, err := newDiagramGenerator(ctx, repo, nc.projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
 	scaffold := usecases.NewScaffoldEntity(repo,
 		usecases.WithTemplateEngine(templateEngine),
-		usecases.WithDiagramGenerator(d2adapter.NewGenerator()),
+		usecases.WithDiagramGenerator(diagramGenerator),
+		usecases.WithPersonRepository(repo),
 	)
 	return scaffold.Execute(ctx, req)
 }
@@ -198,24 +286,79 @@ func (nc *NewCommand) gatherSystemDetails() error {
 	return nil
 }
 
-// resolveComponentParent finds the parent system for a component's container.
-func (nc *NewCommand) resolveComponentParent(ctx context.Context) []string {
-	repo := filesystem.NewProjectRepository()
-	project, err := repo.LoadProject(ctx, nc.projectRoot)
+// resolveComponentParent finds the system and container a component is
+// created in, failing with suggestions when either does not exist. A parent
+// of the form "container/component" creates a sub-component.
+func (nc *NewCommand) resolveComponentParent(ctx context.Context) ([]string, error) {
+	containerName, parentComponent, nested := strings.Cut(nc.parentName, "/")
+
+	systems, err := nc.listSystems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	system, container, err := usecases.FindContainer(systems, nc.systemName, containerName)
+	if err != nil {
+		return nil, err
+	}
+	if !nested {
+		return []string{system.ID, container.ID}, nil
+	}
+	component, err := usecases.FindComponent(container, parentComponent)
 	if err != nil {
-		return []string{"", nc.parentName}
+		return nil, err
 	}
+	return []string{system.ID, container.ID, component.ID}, nil
+}
 
-	// Search through systems to find which one contains the parent container
-	for _, system := range project.Systems {
-		for _, container := range system.Containers {
-			if container.ID == nc.parentName || container.Name == nc.parentName {
-				return []string{system.ID, container.ID}
-			}
+// applyArchetype fills in the defaults of the container's archetype: the
+// technology and description unless given, its tags, its components after
+// the ones given, and its diagram.
+func (nc *NewCommand) applyArchetype(ctx context.Context, req *usecases.ScaffoldEntityRequest) error {
+	project, err := newProjectRepository().LoadProject(ctx, nc.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	var archetypes map[string]entities.ContainerArchetype
+	if project.Config != nil {
+		archetypes = project.Config.Archetypes
+	}
+	archetype, ok := archetypes[nc.archetype]
+	if !ok {
+		if len(archetypes) == 0 {
+			return fmt.Errorf("archetype %q not found: loko.toml defines no [archetypes]", nc.archetype)
+		}
+		return fmt.Errorf("archetype %q not found — available: %s", nc.archetype, strings.Join(slices.Sorted(maps.Keys(archetypes)), ", "))
+	}
+
+	if req.Technology == "" {
+		req.Technology = archetype.Technology
+	}
+	if req.Description == "" {
+		req.Description = archetype.Description
+	}
+	req.Tags = append(req.Tags, archetype.Tags...)
+	for _, name := range archetype.Components {
+		if !slices.ContainsFunc(nc.components, func(c string) bool { return entities.NormalizeName(c) == entities.NormalizeName(name) }) {
+			nc.components = append(nc.components, name)
 		}
 	}
+	if archetype.Diagram != "" {
+		source, err := os.ReadFile(filepath.Join(nc.projectRoot, archetype.Diagram))
+		if err != nil {
+			return fmt.Errorf("failed to read diagram of archetype %s: %w", nc.archetype, err)
+		}
+		req.DiagramSource = string(source)
+	}
+	return nil
+}
 
-	return []string{"", nc.parentName}
+// listSystems loads the systems of the project a parent is looked up in.
+func (nc *NewCommand) listSystems(ctx context.Context) ([]*entities.System, error) {
+	systems, err := newProjectRepository().ListSystems(ctx, nc.projectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list systems: %w", err)
+	}
+	return systems, nil
 }
 
 // createTemplateEngine creates a template engine with standard search paths.
@@ -235,6 +378,7 @@ func (nc *NewCommand) createTemplateEngine(templateName string) *ason.TemplateEn
 		templateEngine.AddSearchPath(filepath.Join(".", "templates", templateName))
 		templateEngine.AddSearchPath(filepath.Join(".", "templates", "component"))
 	}
+	templateEngine.SetIconResolver(newIconStore())
 	return templateEngine
 }
 
@@ -293,7 +437,7 @@ func (nc *NewCommand) showPreview(ctx context.Context, req *usecases.ScaffoldEnt
 	// Render preview
 	svgContent, err := previewRenderer.RenderComponentPreview(ctx, nc.entityName, nc.technology, containerName)
 	if err != nil {
-		return fmt.Errorf("failed to render preview: %w", err)
+		return withExitCode(ExitRender, fmt.Errorf("failed to render preview: %w", err))
 	}
 
 	// Display preview
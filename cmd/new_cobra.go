@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/core/entities"
 	"github.com/spf13/cobra"
 )
 
@@ -12,10 +16,11 @@ var newCmd = &cobra.Command{
 	Use:     "new",
 	Aliases: []string{"n"},
 	Short:   "Create a new C4 entity",
-	Long:    "Create a new system, container, or component in the current project.",
+	Long:    "Create a new person, system, container, or component in the current project.",
 	GroupID: "scaffolding",
 	ValidArgsFunction: func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		return []string{
+			"person\tCreate a new person",
 			"system\tCreate a new system",
 			"container\tCreate a new container",
 			"component\tCreate a new component",
@@ -26,12 +31,19 @@ var newCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(newCmd)
 
+	// new person
+	newCmd.AddCommand(newPersonCmd)
+	newPersonCmd.Flags().StringP("description", "d", "", "person description")
+	newPersonCmd.Flags().Bool("external", false, "the person is outside the organization, e.g. a customer")
+	newPersonCmd.Flags().StringToString("uses", nil, "system, container or component the person uses, as id=description (repeatable)")
+
 	// new system
 	newCmd.AddCommand(newSystemCmd)
 	newSystemCmd.Flags().StringP("description", "d", "", "system description")
 	newSystemCmd.Flags().String("technology", "", "technology stack")
 	newSystemCmd.Flags().StringP("template", "t", "", "template override")
 	newSystemCmd.Flags().Bool("auto-template", false, "automatically select template based on technology")
+	newSystemCmd.Flags().String("domain", "", "dot-separated domain to create the system in (e.g. payments.cards)")
 
 	// new system flag completion
 	_ = newSystemCmd.RegisterFlagCompletionFunc("template", completeTemplates)
@@ -43,111 +55,201 @@ func init() {
 	newContainerCmd.Flags().String("parent", "", "parent system name (required)")
 	newContainerCmd.Flags().StringP("template", "t", "", "template override")
 	newContainerCmd.Flags().Bool("auto-template", false, "automatically select template based on technology")
+	newContainerCmd.Flags().String("archetype", "", "[archetypes] entry of loko.toml whose technology, tags, components and diagram the container gets")
+	newContainerCmd.Flags().StringSlice("with-components", nil, "components to create in each new container, e.g. handler,repository")
 	_ = newContainerCmd.MarkFlagRequired("parent")
 	_ = newContainerCmd.RegisterFlagCompletionFunc("parent", completeParentSystems)
 	_ = newContainerCmd.RegisterFlagCompletionFunc("template", completeTemplates)
+	_ = newContainerCmd.RegisterFlagCompletionFunc("archetype", completeArchetypes)
 
 	// new component
 	newCmd.AddCommand(newComponentCmd)
 	newComponentCmd.Flags().StringP("description", "d", "", "component description")
 	newComponentCmd.Flags().String("technology", "", "technology stack")
-	newComponentCmd.Flags().String("parent", "", "parent container name (required)")
+	newComponentCmd.Flags().String("parent", "", "parent container name, or container/component for a sub-component (required)")
+	newComponentCmd.Flags().String("system", "", "system of the parent container, when containers of several systems share its name")
 	newComponentCmd.Flags().StringP("template", "t", "", "template override")
 	newComponentCmd.Flags().Bool("auto-template", false, "automatically select template based on technology")
 	newComponentCmd.Flags().Bool("preview", false, "show diagram preview after creation")
 	_ = newComponentCmd.MarkFlagRequired("parent")
 	_ = newComponentCmd.RegisterFlagCompletionFunc("parent", completeParentContainers)
+	_ = newComponentCmd.RegisterFlagCompletionFunc("system", completeParentSystems)
 	_ = newComponentCmd.RegisterFlagCompletionFunc("template", completeTemplates)
 }
 
+var newPersonCmd = &cobra.Command{
+	Use:   "person <name>...",
+	Short: "Create one or more new people",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runNewPerson,
+}
+
 var newSystemCmd = &cobra.Command{
-	Use:   "system <name>",
-	Short: "Create a new system",
-	Args:  cobra.ExactArgs(1),
+	Use:   "system <name>...",
+	Short: "Create one or more new systems",
+	Args:  cobra.MinimumNArgs(1),
 	RunE:  runNewSystem,
 }
 
 var newContainerCmd = &cobra.Command{
-	Use:   "container <name>",
-	Short: "Create a new container",
-	Args:  cobra.ExactArgs(1),
+	Use:   "container <name>...",
+	Short: "Create one or more new containers",
+	Args:  cobra.MinimumNArgs(1),
 	RunE:  runNewContainer,
 }
 
 var newComponentCmd = &cobra.Command{
-	Use:   "component <name>",
-	Short: "Create a new component",
-	Args:  cobra.ExactArgs(1),
+	Use:   "component <name>...",
+	Short: "Create one or more new components",
+	Args:  cobra.MinimumNArgs(1),
 	RunE:  runNewComponent,
 }
 
-func runNewSystem(cmd *cobra.Command, args []string) error {
-	newCommand := NewNewCommand("system", args[0])
-	newCommand.WithProjectRoot(ProjectRoot)
-
-	if desc, _ := cmd.Flags().GetString("description"); desc != "" {
-		newCommand.WithDescription(desc)
-	}
-	if tech, _ := cmd.Flags().GetString("technology"); tech != "" {
-		newCommand.WithTechnology(tech)
-	}
-	if tmpl, _ := cmd.Flags().GetString("template"); tmpl != "" {
-		newCommand.WithTemplate(tmpl)
-	}
-	if auto, _ := cmd.Flags().GetBool("auto-template"); auto {
-		newCommand.WithAutoTemplate(true)
+func runNewPerson(cmd *cobra.Command, args []string) error {
+	if err := checkDistinctNames(args); err != nil {
+		return err
 	}
+	for _, name := range args {
+		newCommand := NewNewCommand("person", name)
+		newCommand.WithProjectRoot(ProjectRoot)
 
-	return newCommand.Execute(cmd.Context())
+		if desc, _ := cmd.Flags().GetString("description"); desc != "" {
+			newCommand.WithDescription(desc)
+		}
+		if external, _ := cmd.Flags().GetBool("external"); external {
+			newCommand.WithExternal(true)
+		}
+		if uses, _ := cmd.Flags().GetStringToString("uses"); len(uses) > 0 {
+			newCommand.WithUses(uses)
+		}
+
+		if err := newCommand.Execute(cmd.Context()); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func runNewContainer(cmd *cobra.Command, args []string) error {
-	newCommand := NewNewCommand("container", args[0])
-	newCommand.WithProjectRoot(ProjectRoot)
+func runNewSystem(cmd *cobra.Command, args []string) error {
+	if err := checkDistinctNames(args); err != nil {
+		return err
+	}
+	for _, name := range args {
+		newCommand := NewNewCommand("system", name)
+		newCommand.WithProjectRoot(ProjectRoot)
 
-	parent, _ := cmd.Flags().GetString("parent")
-	newCommand.WithParent(parent)
+		if desc, _ := cmd.Flags().GetString("description"); desc != "" {
+			newCommand.WithDescription(desc)
+		}
+		if tech, _ := cmd.Flags().GetString("technology"); tech != "" {
+			newCommand.WithTechnology(tech)
+		}
+		if tmpl, _ := cmd.Flags().GetString("template"); tmpl != "" {
+			newCommand.WithTemplate(tmpl)
+		}
+		if auto, _ := cmd.Flags().GetBool("auto-template"); auto {
+			newCommand.WithAutoTemplate(true)
+		}
+		if domain, _ := cmd.Flags().GetString("domain"); domain != "" {
+			newCommand.WithDomain(domain)
+		}
 
-	if desc, _ := cmd.Flags().GetString("description"); desc != "" {
-		newCommand.WithDescription(desc)
-	}
-	if tech, _ := cmd.Flags().GetString("technology"); tech != "" {
-		newCommand.WithTechnology(tech)
+		if err := newCommand.Execute(cmd.Context()); err != nil {
+			return err
+		}
 	}
-	if tmpl, _ := cmd.Flags().GetString("template"); tmpl != "" {
-		newCommand.WithTemplate(tmpl)
+	return nil
+}
+
+func runNewContainer(cmd *cobra.Command, args []string) error {
+	if err := checkDistinctNames(args); err != nil {
+		return err
 	}
-	if auto, _ := cmd.Flags().GetBool("auto-template"); auto {
-		newCommand.WithAutoTemplate(true)
+	components, _ := cmd.Flags().GetStringSlice("with-components")
+	if err := checkDistinctNames(components); err != nil {
+		return err
 	}
+	for _, name := range args {
+		newCommand := NewNewCommand("container", name)
+		newCommand.WithProjectRoot(ProjectRoot)
+
+		parent, _ := cmd.Flags().GetString("parent")
+		newCommand.WithParent(parent)
 
-	return newCommand.Execute(cmd.Context())
+		if desc, _ := cmd.Flags().GetString("description"); desc != "" {
+			newCommand.WithDescription(desc)
+		}
+		if tech, _ := cmd.Flags().GetString("technology"); tech != "" {
+			newCommand.WithTechnology(tech)
+		}
+		if tmpl, _ := cmd.Flags().GetString("template"); tmpl != "" {
+			newCommand.WithTemplate(tmpl)
+		}
+		if auto, _ := cmd.Flags().GetBool("auto-template"); auto {
+			newCommand.WithAutoTemplate(true)
+		}
+		if len(components) > 0 {
+			newCommand.WithComponents(components)
+		}
+		if archetype, _ := cmd.Flags().GetString("archetype"); archetype != "" {
+			newCommand.WithArchetype(archetype)
+		}
+
+		if err := newCommand.Execute(cmd.Context()); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func runNewComponent(cmd *cobra.Command, args []string) error {
-	newCommand := NewNewCommand("component", args[0])
-	newCommand.WithProjectRoot(ProjectRoot)
+	if err := checkDistinctNames(args); err != nil {
+		return err
+	}
+	for _, name := range args {
+		newCommand := NewNewCommand("component", name)
+		newCommand.WithProjectRoot(ProjectRoot)
 
-	parent, _ := cmd.Flags().GetString("parent")
-	newCommand.WithParent(parent)
+		parent, _ := cmd.Flags().GetString("parent")
+		newCommand.WithParent(parent)
+		if system, _ := cmd.Flags().GetString("system"); system != "" {
+			newCommand.WithSystem(system)
+		}
 
-	if desc, _ := cmd.Flags().GetString("description"); desc != "" {
-		newCommand.WithDescription(desc)
-	}
-	if tech, _ := cmd.Flags().GetString("technology"); tech != "" {
-		newCommand.WithTechnology(tech)
-	}
-	if tmpl, _ := cmd.Flags().GetString("template"); tmpl != "" {
-		newCommand.WithTemplate(tmpl)
-	}
-	if auto, _ := cmd.Flags().GetBool("auto-template"); auto {
-		newCommand.WithAutoTemplate(true)
-	}
-	if preview, _ := cmd.Flags().GetBool("preview"); preview {
-		newCommand.WithPreview(true)
+		if desc, _ := cmd.Flags().GetString("description"); desc != "" {
+			newCommand.WithDescription(desc)
+		}
+		if tech, _ := cmd.Flags().GetString("technology"); tech != "" {
+			newCommand.WithTechnology(tech)
+		}
+		if tmpl, _ := cmd.Flags().GetString("template"); tmpl != "" {
+			newCommand.WithTemplate(tmpl)
+		}
+		if auto, _ := cmd.Flags().GetBool("auto-template"); auto {
+			newCommand.WithAutoTemplate(true)
+		}
+		if preview, _ := cmd.Flags().GetBool("preview"); preview {
+			newCommand.WithPreview(true)
+		}
+
+		if err := newCommand.Execute(cmd.Context()); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	return newCommand.Execute(cmd.Context())
+// checkDistinctNames rejects names that would create the same element twice.
+func checkDistinctNames(names []string) error {
+	seen := make(map[string]string, len(names))
+	for _, name := range names {
+		id := entities.NormalizeName(name)
+		if previous, ok := seen[id]; ok {
+			return fmt.Errorf("%q and %q would both be created as %s", previous, name, id)
+		}
+		seen[id] = name
+	}
+	return nil
 }
 
 // completeTemplates returns available template names from the filesystem.
@@ -198,6 +300,15 @@ func completeParentSystems(cmd *cobra.Command, _ []string, _ string) ([]string,
 	return names, cobra.ShellCompDirectiveNoFileComp
 }
 
+// completeArchetypes returns the [archetypes] of loko.toml for --archetype completion.
+func completeArchetypes(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	project, err := filesystem.NewProjectRepository().LoadProject(cmd.Context(), ProjectRoot)
+	if err != nil || project.Config == nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return slices.Sorted(maps.Keys(project.Config.Archetypes)), cobra.ShellCompDirectiveNoFileComp
+}
+
 // completeParentContainers returns container names from the current project for --parent completion on components.
 func completeParentContainers(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 	repo := filesystem.NewProjectRepository()
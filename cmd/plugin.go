@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/adapters/config"
+	"github.com/madstone-tech/loko/internal/adapters/d2"
+	"github.com/madstone-tech/loko/internal/adapters/plugin"
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// newPluginStore returns the store of plugins installed under the XDG data
+// directory (e.g. ~/.local/share/loko/plugins).
+func newPluginStore() *plugin.Store {
+	return plugin.NewStore(config.NewXDGPathResolver().PluginsDir())
+}
+
+// PluginInstallCommand installs a plugin from a directory or executable.
+type PluginInstallCommand struct {
+	source string
+}
+
+// NewPluginInstallCommand creates a new plugin install command.
+func NewPluginInstallCommand(source string) *PluginInstallCommand {
+	return &PluginInstallCommand{source: source}
+}
+
+// Execute copies the plugin into the plugins directory.
+func (c *PluginInstallCommand) Execute(ctx context.Context) error {
+	manifest, err := newPluginStore().Install(ctx, c.source)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Installed plugin %s %s (%s)\n", manifest.Name, manifest.Version, formatCapabilities(manifest.Capabilities))
+	fmt.Printf("  Location: %s\n", manifest.Dir)
+	return nil
+}
+
+// PluginListCommand lists the installed plugins.
+type PluginListCommand struct{}
+
+// NewPluginListCommand creates a new plugin list command.
+func NewPluginListCommand() *PluginListCommand {
+	return &PluginListCommand{}
+}
+
+// Execute prints each installed plugin with its capabilities.
+func (c *PluginListCommand) Execute(ctx context.Context) error {
+	plugins, err := newPluginStore().List(ctx)
+	if err != nil {
+		return err
+	}
+	if len(plugins) == 0 {
+		fmt.Printf("No plugins installed in %s\n", config.NewXDGPathResolver().PluginsDir())
+		return nil
+	}
+	for _, manifest := range plugins {
+		fmt.Printf("%-20s %-10s %s\n", manifest.Name, manifest.Version, formatCapabilities(manifest.Capabilities))
+		if manifest.Description != "" {
+			fmt.Printf("  %s\n", manifest.Description)
+		}
+	}
+	return nil
+}
+
+// ExportPluginCommand exports the architecture through an exporter plugin.
+type ExportPluginCommand struct {
+	projectRoot    string
+	name           string
+	outputDir      string
+	options        map[string]string
+	allowPlaintext bool
+	redact         bool
+}
+
+// NewExportPluginCommand creates a command that exports with the plugin name.
+func NewExportPluginCommand(projectRoot, name string) *ExportPluginCommand {
+	return &ExportPluginCommand{
+		projectRoot: projectRoot,
+		name:        name,
+		outputDir:   "dist",
+	}
+}
+
+// WithOutputDir sets the output directory.
+func (c *ExportPluginCommand) WithOutputDir(dir string) *ExportPluginCommand {
+	if dir != "" {
+		c.outputDir = dir
+	}
+	return c
+}
+
+// WithOptions sets options passed through to the plugin.
+func (c *ExportPluginCommand) WithOptions(options map[string]string) *ExportPluginCommand {
+	c.options = options
+	return c
+}
+
+// WithAllowPlaintext permits exporting a project whose sources are encrypted at rest.
+func (c *ExportPluginCommand) WithAllowPlaintext(allow bool) *ExportPluginCommand {
+	c.allowPlaintext = allow
+	return c
+}
+
+// WithRedaction applies the [redaction] rules from loko.toml to the model
+// sent to the plugin.
+func (c *ExportPluginCommand) WithRedaction(redact bool) *ExportPluginCommand {
+	c.redact = redact
+	return c
+}
+
+// Execute sends the model to the plugin and lists the files it wrote.
+func (c *ExportPluginCommand) Execute(ctx context.Context) error {
+	plugins, err := newPluginStore().List(ctx)
+	if err != nil {
+		return err
+	}
+	manifest, err := usecases.FindPlugin(plugins, c.name, entities.PluginExporter)
+	if err != nil {
+		return err
+	}
+
+	projectRepo := newProjectRepository()
+	project, err := projectRepo.LoadProject(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	if err := ensurePlaintextAllowed(project, c.projectRoot, c.allowPlaintext); err != nil {
+		return err
+	}
+	systems, err := projectRepo.ListSystems(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to list systems: %w", err)
+	}
+	if c.redact {
+		if project, systems, _, err = redactArchitecture(project, systems); err != nil {
+			return err
+		}
+	}
+
+	outputDir, err := filepath.Abs(c.outputDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve output directory: %w", err)
+	}
+
+	host := plugin.NewHost()
+	defer host.Close()
+	paths, err := usecases.NewExportWithPlugin(host).Execute(ctx, manifest, project, systems, outputDir, c.options)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		fmt.Printf("✓ Wrote %s\n", path)
+	}
+	return nil
+}
+
+// ImportCommand creates architecture sources through an importer plugin.
+type ImportCommand struct {
+	projectRoot string
+	name        string
+	args        []string
+}
+
+// NewImportCommand creates a command that imports with the plugin name,
+// passing args through to it.
+func NewImportCommand(projectRoot, name string, args []string) *ImportCommand {
+	return &ImportCommand{projectRoot: projectRoot, name: name, args: args}
+}
+
+// Execute runs the importer and lists the source files it wrote.
+func (c *ImportCommand) Execute(ctx context.Context) error {
+	plugins, err := newPluginStore().List(ctx)
+	if err != nil {
+		return err
+	}
+	manifest, err := usecases.FindPlugin(plugins, c.name, entities.PluginImporter)
+	if err != nil {
+		return err
+	}
+
+	project, err := newProjectRepository().LoadProject(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	projectRoot, err := filepath.Abs(c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to resolve project root: %w", err)
+	}
+
+	host := plugin.NewHost()
+	defer host.Close()
+	paths, err := usecases.NewImportWithPlugin(host).Execute(ctx, manifest, projectRoot, filepath.Join(projectRoot, project.SourceDir()), c.args)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Imported %d file(s) with %s\n", len(paths), manifest.Name)
+	for _, path := range paths {
+		fmt.Printf("  %s\n", path)
+	}
+	return nil
+}
+
+// runValidatorPlugins adds the issues reported by every installed validator
+// plugin to report.
+func runValidatorPlugins(ctx context.Context, project *entities.Project, systems []*entities.System, report *usecases.ArchitectureReport) error {
+	plugins, err := newPluginStore().List(ctx)
+	if err != nil || len(plugins) == 0 {
+		return err
+	}
+	host := plugin.NewHost()
+	defer host.Close()
+	return usecases.NewRunPluginValidators(host).Execute(ctx, plugins, project, systems, report)
+}
+
+// newDiagramRenderer returns the renderer plugin named by [plugins] renderer,
+// or the d2 CLI renderer when none is configured, and a function that stops
+// the plugin.
+func newDiagramRenderer(ctx context.Context, cfg *entities.ProjectConfig) (usecases.DiagramRenderer, func(), error) {
+	if cfg == nil || cfg.PluginRenderer == "" {
+		return d2.NewRenderer(), func() {}, nil
+	}
+	plugins, err := newPluginStore().List(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	manifest, err := usecases.FindPlugin(plugins, cfg.PluginRenderer, entities.PluginRenderer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("[plugins] renderer: %w", err)
+	}
+	host := plugin.NewHost()
+	return plugin.NewRenderer(host, manifest), func() { _ = host.Close() }, nil
+}
+
+// formatCapabilities joins capabilities for display, e.g. "exporter, validator".
+func formatCapabilities(capabilities []entities.PluginCapability) string {
+	names := make([]string, len(capabilities))
+	for i, capability := range capabilities {
+		names[i] = string(capability)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Install and list plugins",
+	Long: `Plugins add renderers, exporters, validators and importers without forking
+loko. A plugin is an executable, in any language, that speaks JSON-RPC 2.0
+over stdin/stdout; see docs/guides/plugins.md for the protocol.
+
+Plugins are installed under the XDG data directory
+($XDG_DATA_HOME/loko/plugins, default ~/.local/share/loko/plugins).`,
+	GroupID: "building",
+}
+
+var pluginInstallCmd = &cobra.Command{
+	Use:   "install PATH",
+	Short: "Install a plugin from an executable or plugin directory",
+	Long: `Install the plugin at PATH, replacing an installed plugin of the same name.
+
+PATH is either a plugin executable, which loko starts once to ask for its name
+and capabilities, or a directory holding a plugin.json manifest next to the
+executable.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  loko plugin install ./loko-structurizr
+  loko plugin install ./plugins/ownership`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return NewPluginInstallCommand(args[0]).Execute(cmd.Context())
+	},
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List installed plugins",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return NewPluginListCommand().Execute(cmd.Context())
+	},
+}
+
+var exportPluginCmd = &cobra.Command{
+	Use:   "plugin NAME",
+	Short: "Export with an exporter plugin",
+	Long: `Send the architecture model to the installed exporter plugin NAME, which
+writes its files into the output directory.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  loko export plugin structurizr
+  loko export plugin backstage --output ./catalog --option owner=platform-team`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		options, _ := cmd.Flags().GetStringToString("option")
+		return NewExportPluginCommand(ProjectRoot, args[0]).
+			WithOutputDir(output).
+			WithOptions(options).
+			WithAllowPlaintext(allowPlaintext(cmd)).
+			WithRedaction(redact(cmd)).
+			Execute(cmd.Context())
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import NAME [ARGS...]",
+	Short: "Create sources from an external system with an importer plugin",
+	Long: `Run the installed importer plugin NAME, which writes Markdown and D2 sources
+into the project's source directory. Arguments after NAME, including flags,
+are passed through to the plugin.`,
+	GroupID: "scaffolding",
+	Args:    cobra.MinimumNArgs(1),
+	Example: `  loko import structurizr workspace.json
+  loko import backstage --catalog https://backstage.example.com`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return NewImportCommand(ProjectRoot, args[0], args[1:]).Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pluginCmd)
+	pluginCmd.AddCommand(pluginInstallCmd)
+	pluginCmd.AddCommand(pluginListCmd)
+
+	exportCmd.AddCommand(exportPluginCmd)
+	exportPluginCmd.Flags().StringP("output", "o", "dist", "output directory")
+	exportPluginCmd.Flags().StringToString("option", nil, "option passed to the plugin as key=value (repeatable)")
+
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().SetInterspersed(false)
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// profiler writes pprof CPU and heap profiles and an execution trace for a
+// command run. Empty paths disable the corresponding output.
+type profiler struct {
+	cpuPath   string
+	memPath   string
+	tracePath string
+
+	cpuFile   *os.File
+	traceFile *os.File
+}
+
+// enabled reports whether any profile output was requested.
+func (p *profiler) enabled() bool {
+	return p.cpuPath != "" || p.memPath != "" || p.tracePath != ""
+}
+
+// start begins CPU profiling and tracing.
+func (p *profiler) start() error {
+	if p.cpuPath != "" {
+		f, err := os.Create(p.cpuPath)
+		if err != nil {
+			return fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		p.cpuFile = f
+	}
+
+	if p.tracePath != "" {
+		f, err := os.Create(p.tracePath)
+		if err != nil {
+			p.stopCPU()
+			return fmt.Errorf("failed to create trace file: %w", err)
+		}
+		if err := trace.Start(f); err != nil {
+			_ = f.Close()
+			p.stopCPU()
+			return fmt.Errorf("failed to start trace: %w", err)
+		}
+		p.traceFile = f
+	}
+
+	return nil
+}
+
+// stop ends CPU profiling and tracing and writes the heap profile.
+func (p *profiler) stop() error {
+	var errs []error
+
+	if p.traceFile != nil {
+		trace.Stop()
+		errs = append(errs, p.traceFile.Close())
+		p.traceFile = nil
+	}
+	errs = append(errs, p.stopCPU())
+
+	if p.memPath != "" {
+		f, err := os.Create(p.memPath)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to create memory profile: %w", err))
+		} else {
+			runtime.GC() // report up-to-date live heap statistics
+			if err := pprof.WriteHeapProfile(f); err != nil {
+				errs = append(errs, fmt.Errorf("failed to write memory profile: %w", err))
+			}
+			errs = append(errs, f.Close())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// stopCPU ends CPU profiling if it is running.
+func (p *profiler) stopCPU() error {
+	if p.cpuFile == nil {
+		return nil
+	}
+	pprof.StopCPUProfile()
+	err := p.cpuFile.Close()
+	p.cpuFile = nil
+	return err
+}
+
+// printFiles lists the profile files that were written.
+func (p *profiler) printFiles() {
+	for _, f := range []struct{ kind, path string }{
+		{"CPU profile", p.cpuPath},
+		{"Memory profile", p.memPath},
+		{"Trace", p.tracePath},
+	} {
+		if f.path != "" {
+			fmt.Printf("✓ %s: %s\n", f.kind, f.path)
+		}
+	}
+	if p.cpuPath != "" || p.memPath != "" {
+		fmt.Println("  Inspect with: go tool pprof -top <file>")
+	}
+	if p.tracePath != "" {
+		fmt.Println("  Inspect with: go tool trace <file>")
+	}
+}
+
+// printTimings prints the time spent per build phase, slowest first.
+func printTimings(timings *usecases.BuildTimings, wall time.Duration) {
+	phases := timings.Phases()
+	if len(phases) == 0 {
+		return
+	}
+
+	fmt.Println("\nTime by phase:")
+	for _, p := range phases {
+		share := 0.0
+		if wall > 0 {
+			share = float64(p.Duration) / float64(wall) * 100
+		}
+		fmt.Printf("  %-22s %10v %5.1f%%\n", p.Phase, p.Duration.Round(time.Millisecond), share)
+	}
+	fmt.Printf("  %-22s %10v\n", "total", wall.Round(time.Millisecond))
+}
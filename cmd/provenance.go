@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/adapters/d2"
+	"github.com/madstone-tech/loko/internal/adapters/git"
+	"github.com/madstone-tech/loko/internal/adapters/signing"
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// writeProvenance records which sources and tool versions produced the
+// output directory.
+func (c *BuildCommand) writeProvenance(ctx context.Context, project *entities.Project, formats []usecases.OutputFormat, started time.Time) error {
+	generate := usecases.NewGenerateProvenance()
+	if c.signingKey != "" {
+		signer, err := signing.NewKeySigner(c.signingKey)
+		if err != nil {
+			return fmt.Errorf("failed to load signing key: %w", err)
+		}
+		generate.WithSigner(signer)
+	}
+
+	versions := map[string]string{"loko": appVersion}
+	if version, err := d2.NewRenderer().Version(ctx); err == nil {
+		versions["d2"] = version
+	}
+	// Without git the statement still pins every source file by digest.
+	revision, _ := git.NewHistory().SourceRevision(ctx, c.projectRoot)
+
+	names := make([]string, len(formats))
+	for i, format := range formats {
+		names[i] = string(format)
+	}
+	path, err := generate.Execute(ctx, usecases.ProvenanceRequest{
+		ProjectRoot: c.projectRoot,
+		SourceDir:   project.SourceDir(),
+		OutputDir:   c.outputDir,
+		Revision:    revision,
+		Versions:    versions,
+		Parameters:  map[string]any{"formats": names, "redact": c.redact},
+		StartedOn:   started,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Provenance: %s\n", path)
+	return nil
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/madstone-tech/loko/internal/adapters/oras"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// PushCommand publishes the built documentation site to an OCI registry.
+type PushCommand struct {
+	projectRoot string
+	url         string
+	siteDir     string
+	noModel     bool // Push the site without the model snapshot
+	plainHTTP   bool // Connect to the registry without TLS
+
+	allowPlaintext bool // Push even when the project's sources are encrypted
+	redact         bool // Apply the [redaction] rules to the model snapshot
+}
+
+// NewPushCommand creates a new push command for an oci:// URL.
+func NewPushCommand(projectRoot, url string) *PushCommand {
+	return &PushCommand{
+		projectRoot: projectRoot,
+		url:         url,
+		siteDir:     "dist",
+	}
+}
+
+// WithSiteDir sets the directory of the built site.
+func (c *PushCommand) WithSiteDir(dir string) *PushCommand {
+	c.siteDir = dir
+	return c
+}
+
+// WithoutModel pushes the site only, without a JSON snapshot of the model.
+func (c *PushCommand) WithoutModel(noModel bool) *PushCommand {
+	c.noModel = noModel
+	return c
+}
+
+// WithPlainHTTP connects to the registry over HTTP, for local registries.
+func (c *PushCommand) WithPlainHTTP(plainHTTP bool) *PushCommand {
+	c.plainHTTP = plainHTTP
+	return c
+}
+
+// WithAllowPlaintext permits publishing documentation for a project whose
+// sources are encrypted at rest.
+func (c *PushCommand) WithAllowPlaintext(allow bool) *PushCommand {
+	c.allowPlaintext = allow
+	return c
+}
+
+// WithRedaction applies the [redaction] rules to the model snapshot. The
+// site itself should be built with `loko build --redact`.
+func (c *PushCommand) WithRedaction(redact bool) *PushCommand {
+	c.redact = redact
+	return c
+}
+
+// Execute packages the site and model snapshot and pushes them.
+func (c *PushCommand) Execute(ctx context.Context) error {
+	ref, err := usecases.ParseArtifactRef(c.url)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(c.siteDir); err != nil || !info.IsDir() {
+		return fmt.Errorf("site directory %s not found; run loko build first", c.siteDir)
+	}
+
+	repo := newProjectRepository()
+	project, err := repo.LoadProject(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	if err := ensurePlaintextAllowed(project, c.projectRoot, c.allowPlaintext); err != nil {
+		return err
+	}
+
+	var model []byte
+	if !c.noModel {
+		if model, err = c.modelSnapshot(ctx, repo); err != nil {
+			return err
+		}
+	}
+
+	registry := oras.NewRegistry()
+	if !registry.IsAvailable() {
+		return fmt.Errorf("%w (install from https://oras.land)", oras.ErrOrasNotAvailable)
+	}
+	registry.SetPlainHTTP(c.plainHTTP)
+
+	digest, err := usecases.NewPublishArtifact(registry).Execute(ctx, ref, c.siteDir, model)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Pushed %s to %s\n", c.siteDir, ref)
+	if digest != "" {
+		fmt.Printf("  Digest: %s\n", digest)
+	}
+	return nil
+}
+
+// modelSnapshot returns the full architecture as JSON, redacted if requested.
+func (c *PushCommand) modelSnapshot(ctx context.Context, repo usecases.ProjectRepository) ([]byte, error) {
+	if c.redact {
+		project, err := repo.LoadProject(ctx, c.projectRoot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load project: %w", err)
+		}
+		systems, err := repo.ListSystems(ctx, c.projectRoot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list systems: %w", err)
+		}
+		project, systems, _, err = redactArchitecture(project, systems)
+		if err != nil {
+			return nil, err
+		}
+		repo = &staticRepository{ProjectRepository: repo, project: project, systems: systems}
+	}
+
+	resp, err := usecases.NewQueryArchitecture(repo).ExecuteWithFormat(ctx, c.projectRoot, "full", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot model: %w", err)
+	}
+	return []byte(resp.Text), nil
+}
+
+// PullCommand retrieves documentation published with PushCommand.
+type PullCommand struct {
+	url       string
+	outputDir string
+	plainHTTP bool
+}
+
+// NewPullCommand creates a new pull command for an oci:// URL.
+func NewPullCommand(url string) *PullCommand {
+	return &PullCommand{url: url, outputDir: "dist"}
+}
+
+// WithOutputDir sets the directory the site is unpacked into.
+func (c *PullCommand) WithOutputDir(dir string) *PullCommand {
+	c.outputDir = dir
+	return c
+}
+
+// WithPlainHTTP connects to the registry over HTTP, for local registries.
+func (c *PullCommand) WithPlainHTTP(plainHTTP bool) *PullCommand {
+	c.plainHTTP = plainHTTP
+	return c
+}
+
+// Execute pulls the artifact and unpacks the site into the output directory.
+func (c *PullCommand) Execute(ctx context.Context) error {
+	ref, err := usecases.ParseArtifactRef(c.url)
+	if err != nil {
+		return err
+	}
+
+	registry := oras.NewRegistry()
+	if !registry.IsAvailable() {
+		return fmt.Errorf("%w (install from https://oras.land)", oras.ErrOrasNotAvailable)
+	}
+	registry.SetPlainHTTP(c.plainHTTP)
+
+	count, err := usecases.NewFetchArtifact(registry).Execute(ctx, ref, c.outputDir)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Pulled %d file(s) from %s into %s\n", count, ref, c.outputDir)
+	return nil
+}
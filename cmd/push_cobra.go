@@ -0,0 +1,64 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var pushCmd = &cobra.Command{
+	Use:   "push oci://REGISTRY/REPOSITORY:TAG",
+	Short: "Publish the built site to an OCI registry",
+	Long: `Package the built documentation site and a JSON snapshot of the model as an
+OCI artifact and push it to a container registry, so documentation deployment
+can reuse existing registries, access control and signing (cosign sign).
+
+Run loko build first. Requires the oras CLI (https://oras.land); log in with
+"oras login" or "docker login". The artifact has two layers:
+
+  site.tar.gz      application/vnd.loko.site.v1.tar+gzip
+  loko-model.json  application/vnd.loko.model.v1+json`,
+	GroupID: "building",
+	Args:    cobra.ExactArgs(1),
+	Example: `  loko build && loko push oci://ghcr.io/acme/arch-docs:v1.2.0
+  loko push oci://localhost:5000/arch-docs:latest --plain-http
+  loko build --redact && loko push oci://ghcr.io/acme/public-docs:latest --redact`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, _ := cmd.Flags().GetString("dir")
+		noModel, _ := cmd.Flags().GetBool("no-model")
+		plainHTTP, _ := cmd.Flags().GetBool("plain-http")
+		allow, _ := cmd.Flags().GetBool("allow-plaintext")
+		redact, _ := cmd.Flags().GetBool("redact")
+		return NewPushCommand(ProjectRoot, args[0]).
+			WithSiteDir(dir).
+			WithoutModel(noModel).
+			WithPlainHTTP(plainHTTP).
+			WithAllowPlaintext(allow).
+			WithRedaction(redact).
+			Execute(cmd.Context())
+	},
+}
+
+var pullCmd = &cobra.Command{
+	Use:   "pull oci://REGISTRY/REPOSITORY:TAG",
+	Short: "Retrieve a documentation site from an OCI registry",
+	Long: `Pull documentation published with loko push and unpack the site into the
+output directory, with the model snapshot as loko-model.json.`,
+	GroupID: "building",
+	Args:    cobra.ExactArgs(1),
+	Example: `  loko pull oci://ghcr.io/acme/arch-docs:v1.2.0
+  loko pull oci://ghcr.io/acme/arch-docs:v1.2.0 --output /var/www/docs`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		plainHTTP, _ := cmd.Flags().GetBool("plain-http")
+		return NewPullCommand(args[0]).WithOutputDir(output).WithPlainHTTP(plainHTTP).Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pushCmd)
+	rootCmd.AddCommand(pullCmd)
+	pushCmd.Flags().String("dir", "dist", "directory of the built site")
+	pushCmd.Flags().Bool("no-model", false, "push the site without the model snapshot")
+	pushCmd.Flags().Bool("plain-http", false, "connect to the registry without TLS")
+	pushCmd.Flags().Bool("allow-plaintext", false, "push even if the project's sources are encrypted")
+	pushCmd.Flags().Bool("redact", false, "apply the [redaction] rules from loko.toml to the model snapshot")
+	pullCmd.Flags().StringP("output", "o", "dist", "directory to unpack the site into")
+	pullCmd.Flags().Bool("plain-http", false, "connect to the registry without TLS")
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// redactArchitecture applies the [redaction] rules from loko.toml for a
+// public build. It returns the redacted project and systems together with the
+// redactor, whose MaskText also applies to Markdown bodies read later.
+func redactArchitecture(project *entities.Project, systems []*entities.System) (*entities.Project, []*entities.System, *usecases.RedactArchitecture, error) {
+	redactor, err := usecases.NewRedactArchitecture(project.Config)
+	if err != nil {
+		return nil, nil, nil, withExitCode(ExitConfig, fmt.Errorf("invalid [redaction] configuration: %w", err))
+	}
+	if !redactor.Enabled() {
+		return nil, nil, nil, withExitCode(ExitConfig, fmt.Errorf("--redact requires [redaction] rules in loko.toml (remove_tags, strip_fields or mask_patterns)"))
+	}
+
+	systems = redactor.Execute(systems)
+	return redactor.RedactProject(project, systems), systems, redactor, nil
+}
+
+// maskingReader wraps read so element Markdown files are masked as they are
+// read. A nil redactor returns read unchanged.
+func maskingReader(read func(path string) ([]byte, error), redactor *usecases.RedactArchitecture) func(path string) ([]byte, error) {
+	if redactor == nil {
+		return read
+	}
+	return func(path string) ([]byte, error) {
+		content, err := read(path)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(redactor.MaskText(string(content))), nil
+	}
+}
+
+// staticRepository serves an already loaded (e.g. redacted) project to use
+// cases that load it themselves. Other methods use the wrapped repository.
+type staticRepository struct {
+	usecases.ProjectRepository
+	project *entities.Project
+	systems []*entities.System
+}
+
+func (r *staticRepository) LoadProject(context.Context, string) (*entities.Project, error) {
+	return r.project, nil
+}
+
+func (r *staticRepository) ListSystems(context.Context, string) ([]*entities.System, error) {
+	return r.systems, nil
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// Report formats.
+var reportFormats = []string{"text", "csv", "json"}
+
+// ReportCostsCommand rolls up the monthly costs declared by containers.
+type ReportCostsCommand struct {
+	projectRoot string
+	format      string
+	out         io.Writer
+}
+
+// NewReportCostsCommand creates a new report costs command.
+func NewReportCostsCommand(projectRoot string) *ReportCostsCommand {
+	return &ReportCostsCommand{projectRoot: projectRoot, format: "text", out: os.Stdout}
+}
+
+// WithFormat sets the output format: text, csv or json.
+func (c *ReportCostsCommand) WithFormat(format string) *ReportCostsCommand {
+	if format != "" {
+		c.format = strings.ToLower(format)
+	}
+	return c
+}
+
+// Execute runs the report costs command.
+func (c *ReportCostsCommand) Execute(ctx context.Context) error {
+	projectRepo := newProjectRepository()
+	project, err := projectRepo.LoadProject(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	systems, err := projectRepo.ListSystems(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to list systems: %w", err)
+	}
+
+	report := usecases.NewBuildCostReport(project.Config).Execute(ctx, systems)
+	switch c.format {
+	case "text":
+		c.writeText(report)
+	case "csv":
+		if err := c.writeCSV(report); err != nil {
+			return err
+		}
+	case "json":
+		encoder := json.NewEncoder(c.out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode cost report: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported report format %q (supported: %s)", c.format, strings.Join(reportFormats, ", "))
+	}
+	if len(report.Invalid) > 0 {
+		return withExitCode(ExitValidation, fmt.Errorf("%d container(s) have invalid cost annotations", len(report.Invalid)))
+	}
+	return nil
+}
+
+// writeText prints the report as a table per system.
+func (c *ReportCostsCommand) writeText(report *usecases.CostReport) {
+	for _, invalid := range report.Invalid {
+		fmt.Fprintf(c.out, "✗ %s\n", invalid)
+	}
+	if !report.HasCosts() {
+		fmt.Fprintln(c.out, "No container declares a monthly_cost or cost: tag")
+		return
+	}
+
+	for _, system := range report.Systems {
+		fmt.Fprintf(c.out, "%s  %s\n", system.Name, formatCost(system.Total, report.Currency))
+		for _, container := range system.Containers {
+			fmt.Fprintf(c.out, "  %-30s %s%s\n", container.Name, formatCost(container.Total, report.Currency), costBreakdown(container.ByEnvironment, report))
+		}
+	}
+	fmt.Fprintln(c.out, "\nBy environment:")
+	for _, env := range report.Environments {
+		fmt.Fprintf(c.out, "  %-30s %s\n", costEnvironment(env), formatCost(report.ByEnvironment[env], report.Currency))
+	}
+	fmt.Fprintf(c.out, "Total: %s per month\n", formatCost(report.Total, report.Currency))
+	if len(report.Unpriced) > 0 {
+		fmt.Fprintf(c.out, "⚠ %d container(s) without a cost: %s\n", len(report.Unpriced), strings.Join(report.Unpriced, ", "))
+	}
+}
+
+// writeCSV writes one row per container and environment.
+func (c *ReportCostsCommand) writeCSV(report *usecases.CostReport) error {
+	w := csv.NewWriter(c.out)
+	_ = w.Write([]string{"system", "container", "environment", "monthly_cost", "currency"})
+	for _, system := range report.Systems {
+		for _, container := range system.Containers {
+			for _, env := range report.Environments {
+				amount, ok := container.ByEnvironment[env]
+				if !ok {
+					continue
+				}
+				_ = w.Write([]string{system.ID, container.ID, env, strconv.FormatFloat(amount, 'f', 2, 64), report.Currency})
+			}
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write cost report: %w", err)
+	}
+	return nil
+}
+
+// ReportControlsCommand prints the traceability matrix of compliance
+// controls to the containers and components implementing them.
+type ReportControlsCommand struct {
+	projectRoot string
+	format      string
+	out         io.Writer
+}
+
+// NewReportControlsCommand creates a new report controls command.
+func NewReportControlsCommand(projectRoot string) *ReportControlsCommand {
+	return &ReportControlsCommand{projectRoot: projectRoot, format: "text", out: os.Stdout}
+}
+
+// WithFormat sets the output format: text, csv or json.
+func (c *ReportControlsCommand) WithFormat(format string) *ReportControlsCommand {
+	if format != "" {
+		c.format = strings.ToLower(format)
+	}
+	return c
+}
+
+// Execute runs the report controls command.
+func (c *ReportControlsCommand) Execute(ctx context.Context) error {
+	systems, err := newProjectRepository().ListSystems(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to list systems: %w", err)
+	}
+
+	matrix := usecases.NewBuildControlMatrix().Execute(ctx, systems)
+	switch c.format {
+	case "text":
+		if len(matrix.Controls) == 0 {
+			fmt.Fprintln(c.out, "No container or component lists controls")
+			return nil
+		}
+		for _, control := range matrix.Controls {
+			fmt.Fprintln(c.out, control.ID)
+			for _, implementer := range control.Implementers {
+				fmt.Fprintf(c.out, "  %-9s  %-40s %s\n", implementer.Type, implementer.ID, implementer.Name)
+			}
+		}
+		fmt.Fprintf(c.out, "\n%d control(s)\n", len(matrix.Controls))
+	case "csv":
+		w := csv.NewWriter(c.out)
+		_ = w.Write([]string{"control", "type", "id", "name"})
+		for _, control := range matrix.Controls {
+			for _, implementer := range control.Implementers {
+				_ = w.Write([]string{control.ID, implementer.Type, implementer.ID, implementer.Name})
+			}
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return fmt.Errorf("failed to write control matrix: %w", err)
+		}
+	case "json":
+		encoder := json.NewEncoder(c.out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(matrix); err != nil {
+			return fmt.Errorf("failed to encode control matrix: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported report format %q (supported: %s)", c.format, strings.Join(reportFormats, ", "))
+	}
+	return nil
+}
+
+// costBreakdown returns " (prod 1,200.00, staging 300.00)" for amounts in
+// more than one environment.
+func costBreakdown(byEnv map[string]float64, report *usecases.CostReport) string {
+	if len(byEnv) < 2 {
+		return ""
+	}
+	var parts []string
+	for _, env := range report.Environments {
+		if amount, ok := byEnv[env]; ok {
+			parts = append(parts, costEnvironment(env)+" "+formatCost(amount, ""))
+		}
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// costEnvironment names the environment of a cost.
+func costEnvironment(env string) string {
+	if env == "" {
+		return "(no environment)"
+	}
+	return env
+}
+
+// formatCost formats amount followed by currency when given.
+func formatCost(amount float64, currency string) string {
+	if currency == "" {
+		return usecases.FormatCostAmount(amount)
+	}
+	return usecases.FormatCostAmount(amount) + " " + currency
+}
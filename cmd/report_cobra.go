@@ -0,0 +1,75 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var reportCmd = &cobra.Command{
+	Use:     "report",
+	Short:   "Report on the architecture model",
+	GroupID: "building",
+}
+
+var reportCostsCmd = &cobra.Command{
+	Use:   "costs",
+	Short: "Roll up the monthly costs of containers per system and environment",
+	Long: `Aggregate the monthly running costs declared by containers, per container,
+system and environment.
+
+A container declares its cost in container.md frontmatter, either as a single
+amount or per environment with cost tags:
+
+  monthly_cost: 1200
+  tags:
+    - cost:prod=1200
+    - cost:staging=300
+
+Amounts are in the currency of [costs] in loko.toml (USD by default).
+Containers of internal systems without a cost are listed; invalid amounts
+make the command fail.`,
+	Example: `  loko report costs
+  loko report costs --format csv > costs.csv
+  loko report costs --format json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		return NewReportCostsCommand(ProjectRoot).
+			WithFormat(format).
+			Execute(cmd.Context())
+	},
+}
+
+var reportControlsCmd = &cobra.Command{
+	Use:   "controls",
+	Short: "Trace compliance controls to the containers and components implementing them",
+	Long: `Print the traceability matrix of compliance controls, such as SOC 2 or
+ISO 27001 controls, to the containers and components implementing them.
+
+Elements list the IDs of the controls they implement in their container.md
+or component.md frontmatter:
+
+  controls:
+    - CC6.1
+    - A.8.24
+
+The csv format writes one row per control and element for auditors.`,
+	Example: `  loko report controls
+  loko report controls --format csv > controls.csv`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		return NewReportControlsCommand(ProjectRoot).
+			WithFormat(format).
+			Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.AddCommand(reportCostsCmd)
+
+	reportCostsCmd.Flags().StringP("format", "f", "text", "output format (text, csv, json)")
+	_ = reportCostsCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(reportFormats, cobra.ShellCompDirectiveNoFileComp))
+
+	reportCmd.AddCommand(reportControlsCmd)
+	reportControlsCmd.Flags().StringP("format", "f", "text", "output format (text, csv, json)")
+	_ = reportControlsCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(reportFormats, cobra.ShellCompDirectiveNoFileComp))
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/adapters/git"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// ReviewApproveCommand marks an element as approved in the review workflow.
+type ReviewApproveCommand struct {
+	projectRoot string
+	id          string
+	reviewer    string
+}
+
+// NewReviewApproveCommand creates a new review approve command for the
+// element id.
+func NewReviewApproveCommand(projectRoot, id string) *ReviewApproveCommand {
+	return &ReviewApproveCommand{projectRoot: projectRoot, id: id}
+}
+
+// WithReviewer sets the reviewer recorded in reviewed_by. Defaults to the git
+// user.name, then $USER.
+func (c *ReviewApproveCommand) WithReviewer(reviewer string) *ReviewApproveCommand {
+	c.reviewer = reviewer
+	return c
+}
+
+// Execute runs the review approve command.
+func (c *ReviewApproveCommand) Execute(ctx context.Context) error {
+	projectRepo := newProjectRepository()
+	systems, err := projectRepo.ListSystems(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to list systems: %w", err)
+	}
+
+	reviewer := c.reviewer
+	if reviewer == "" {
+		reviewer = git.NewHistory().UserName(ctx, c.projectRoot)
+	}
+	if reviewer == "" {
+		reviewer = os.Getenv("USER")
+	}
+
+	id, err := usecases.NewApproveElement(projectRepo).Execute(ctx, systems, c.id, reviewer, time.Now())
+	if err != nil {
+		return err
+	}
+	if reviewer == "" {
+		fmt.Printf("✓ Approved %s\n", id)
+	} else {
+		fmt.Printf("✓ Approved %s (reviewed by %s)\n", id, reviewer)
+	}
+	return nil
+}
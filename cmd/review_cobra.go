@@ -0,0 +1,47 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Manage the review status of elements",
+	Long: `Manage the review workflow of systems, containers and components.
+
+An element's review_status frontmatter is draft, in-review or approved;
+reviewed_by and reviewed_at record who approved it and when. An element
+without a status inherits the status of its parent. With
+
+  [review]
+  require_approval = true
+
+in loko.toml, loko validate reports every element that is not approved, so
+CI can refuse to publish unreviewed architecture.`,
+	GroupID: "scaffolding",
+}
+
+var reviewApproveCmd = &cobra.Command{
+	Use:   "approve ID",
+	Short: "Mark a system, container or component as approved",
+	Long: `Set the review_status of an element to approved and record the reviewer
+and the date in its frontmatter.
+
+ID is a system ID, a system/container ID, or a qualified or unique component
+ID. The reviewer defaults to the git user.name.`,
+	Example: `  loko review approve payments
+  loko review approve payments/api
+  loko review approve payments/api/auth --by "Ana Lima"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		by, _ := cmd.Flags().GetString("by")
+		return NewReviewApproveCommand(ProjectRoot, args[0]).
+			WithReviewer(by).
+			Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reviewCmd)
+	reviewCmd.AddCommand(reviewApproveCmd)
+
+	reviewApproveCmd.Flags().String("by", "", "reviewer recorded in reviewed_by (default: git user.name)")
+}
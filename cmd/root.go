@@ -4,6 +4,7 @@ package cmd
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/madstone-tech/loko/internal/adapters/config"
 	"github.com/spf13/cobra"
@@ -54,9 +55,15 @@ func init() {
 	)
 }
 
-// Execute runs the root command. This is the main entry point called from main.go.
+// Execute runs the root command. This is the main entry point called from
+// main.go, which exits with ExitCode of the returned error. Projects that opt
+// in with [metrics] record_usage get the run recorded in .loko/usage.log.
 func Execute() error {
-	return rootCmd.Execute()
+	tagUsageErrors(rootCmd)
+	start := time.Now()
+	cmd, err := rootCmd.ExecuteC()
+	recordUsage(cmd, start, err)
+	return err
 }
 
 // SetVersionInfo sets build-time version information from ldflags.
@@ -89,6 +96,7 @@ func initConfig(root *cobra.Command) error {
 	viper.SetDefault("outputs.pdf", false)
 	viper.SetDefault("build.parallel", true)
 	viper.SetDefault("build.max_workers", 4)
+	viper.SetDefault("build.minify", false)
 	viper.SetDefault("server.serve_port", 8080)
 	viper.SetDefault("server.api_port", 8081)
 	viper.SetDefault("server.hot_reload", true)
@@ -98,7 +106,7 @@ func initConfig(root *cobra.Command) error {
 		// --config flag overrides all path resolution.
 		viper.SetConfigFile(cfgFile)
 		if err := viper.ReadInConfig(); err != nil {
-			return fmt.Errorf("failed to read config file %s: %w", cfgFile, err)
+			return withExitCode(ExitConfig, fmt.Errorf("failed to read config file %s: %w", cfgFile, err))
 		}
 	} else {
 		// Try XDG global config path.
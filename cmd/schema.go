@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// SchemaDumpCommand writes the JSON Schemas of element frontmatter,
+// loko.toml and the model document, for editor autocomplete and validation.
+type SchemaDumpCommand struct {
+	names     []string // Schemas to write; empty writes all
+	outputDir string   // Directory to write <name>.schema.json files to; empty prints to out
+	out       io.Writer
+}
+
+// NewSchemaDumpCommand creates a new schema dump command.
+func NewSchemaDumpCommand(names []string) *SchemaDumpCommand {
+	return &SchemaDumpCommand{names: names, out: os.Stdout}
+}
+
+// WithOutputDir writes each schema to <dir>/<name>.schema.json instead of
+// printing it.
+func (c *SchemaDumpCommand) WithOutputDir(dir string) *SchemaDumpCommand {
+	c.outputDir = dir
+	return c
+}
+
+// Execute prints or writes the selected schemas.
+func (c *SchemaDumpCommand) Execute(_ context.Context) error {
+	names := c.names
+	if len(names) == 0 {
+		if c.outputDir == "" {
+			return fmt.Errorf("name a schema to print (%s) or write them all with --dir", strings.Join(entities.JSONSchemaNames(), ", "))
+		}
+		names = entities.JSONSchemaNames()
+	}
+
+	for _, name := range names {
+		schema, err := entities.JSONSchema(name)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(schema); err != nil {
+			return fmt.Errorf("encode %s schema: %w", name, err)
+		}
+
+		if c.outputDir == "" {
+			if _, err := buf.WriteTo(c.out); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.MkdirAll(c.outputDir, 0755); err != nil {
+			return fmt.Errorf("create %s: %w", c.outputDir, err)
+		}
+		path := filepath.Join(c.outputDir, name+".schema.json")
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			return fmt.Errorf("write %s: %w", path, err)
+		}
+		fmt.Fprintf(c.out, "✓ Wrote %s\n", path)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "JSON Schemas for element frontmatter, loko.toml and the model",
+	Long: `loko publishes JSON Schemas for the frontmatter of system.md, container.md
+and component.md files and for loko.toml, so editors can autocomplete and
+validate them while you write, and for the model document of
+loko export model, so tools can validate what they consume. The API server
+also serves them at /api/v1/schemas/{name}.`,
+	GroupID: "scaffolding",
+}
+
+var schemaDumpCmd = &cobra.Command{
+	Use:   "dump [system|container|component|config|model...]",
+	Short: "Print or write JSON Schemas",
+	Long: `Print the named JSON Schema, or write every named schema (all of them by
+default) to <dir>/<name>.schema.json with --dir.`,
+	Example: `  loko schema dump config
+  loko schema dump --dir .loko/schemas`,
+	ValidArgs: []string{"system", "container", "component", "config", "model"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, _ := cmd.Flags().GetString("dir")
+
+		return NewSchemaDumpCommand(args).
+			WithOutputDir(dir).
+			Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+
+	schemaCmd.AddCommand(schemaDumpCmd)
+	schemaDumpCmd.Flags().String("dir", "", "write <name>.schema.json files to this directory")
+}
@@ -7,8 +7,12 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
+
+	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/adapters/html"
 )
 
 // ServeCommand serves the documentation locally.
@@ -16,6 +20,9 @@ type ServeCommand struct {
 	outputDir string
 	address   string
 	port      string
+
+	projectRoot    string // Project whose sources are checked for encryption
+	allowPlaintext bool
 }
 
 // NewServeCommand creates a new serve command.
@@ -39,6 +46,14 @@ func (c *ServeCommand) WithPort(port string) *ServeCommand {
 	return c
 }
 
+// WithProject refuses to serve documentation built from projectRoot when its
+// sources are encrypted at rest, unless allowPlaintext is set.
+func (c *ServeCommand) WithProject(projectRoot string, allowPlaintext bool) *ServeCommand {
+	c.projectRoot = projectRoot
+	c.allowPlaintext = allowPlaintext
+	return c
+}
+
 // Execute runs the serve command.
 func (c *ServeCommand) Execute(ctx context.Context) error {
 	// Verify output directory exists
@@ -46,18 +61,24 @@ func (c *ServeCommand) Execute(ctx context.Context) error {
 		return fmt.Errorf("output directory not found: %s", c.outputDir)
 	}
 
-	// Create HTTP server
-	mux := http.NewServeMux()
-
-	// Serve static files
-	fileServer := http.FileServer(http.Dir(c.outputDir))
-	mux.Handle("/", fileServer)
+	// Serving a built site does not require a project, so only guard when one loads
+	basePath := "/"
+	if c.projectRoot != "" {
+		if project, err := filesystem.NewProjectRepository().LoadProject(ctx, c.projectRoot); err == nil {
+			if err := ensurePlaintextAllowed(project, c.projectRoot, c.allowPlaintext); err != nil {
+				return err
+			}
+			if router, err := html.NewRouter(project.Config.BasePath, false); err == nil && router.BasePath() != "" {
+				basePath = router.BasePath()
+			}
+		}
+	}
 
 	// Create server
 	addr := net.JoinHostPort(c.address, c.port)
 	server := &http.Server{
 		Addr:    addr,
-		Handler: mux,
+		Handler: newSiteMux(http.FileServer(http.Dir(c.outputDir)), basePath),
 	}
 
 	// Channel for errors
@@ -65,7 +86,7 @@ func (c *ServeCommand) Execute(ctx context.Context) error {
 
 	// Start server in goroutine
 	go func() {
-		fmt.Printf("🚀 Server starting on http://%s\n", addr)
+		fmt.Printf("🚀 Server starting on http://%s%s\n", addr, basePath)
 		fmt.Printf("   Serving documentation from: %s\n", c.outputDir)
 		fmt.Println("   Press Ctrl+C to stop")
 		errChan <- server.ListenAndServe()
@@ -93,3 +114,14 @@ func (c *ServeCommand) Execute(ctx context.Context) error {
 
 	return nil
 }
+
+// newSiteMux serves a site with site under its base path, as deployed,
+// redirecting the root to it.
+func newSiteMux(site http.Handler, basePath string) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle(basePath, http.StripPrefix(strings.TrimSuffix(basePath, "/"), site))
+	if basePath != "/" {
+		mux.Handle("/{$}", http.RedirectHandler(basePath, http.StatusFound))
+	}
+	return mux
+}
@@ -22,6 +22,7 @@ func init() {
 	serveCmd.Flags().StringP("output", "o", "dist", "directory to serve")
 	serveCmd.Flags().String("address", "localhost", "server address")
 	serveCmd.Flags().String("port", "8080", "server port")
+	serveCmd.Flags().Bool("allow-plaintext", false, "serve even if the project's sources are encrypted")
 
 	_ = viper.BindPFlag("server.serve_port", serveCmd.Flags().Lookup("port"))
 }
@@ -37,5 +38,8 @@ func runServe(cmd *cobra.Command, args []string) error {
 		serveCommand.WithPort(port)
 	}
 
+	allow, _ := cmd.Flags().GetBool("allow-plaintext")
+	serveCommand.WithProject(ProjectRoot, allow)
+
 	return serveCommand.Execute(cmd.Context())
 }
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/madstone-tech/loko/internal/adapters/cli"
+	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// SplitCommand extracts a system into a new standalone project.
+type SplitCommand struct {
+	projectRoot string
+	systemID    string
+	target      string
+	yes         bool
+}
+
+// NewSplitCommand creates a new split command extracting systemID into a new
+// project at target.
+func NewSplitCommand(projectRoot, systemID, target string, yes bool) *SplitCommand {
+	return &SplitCommand{
+		projectRoot: projectRoot,
+		systemID:    systemID,
+		target:      target,
+		yes:         yes,
+	}
+}
+
+// Execute runs the split command.
+func (c *SplitCommand) Execute(ctx context.Context) error {
+	projectRepo := newProjectRepository()
+	project, err := projectRepo.LoadProject(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	systems, err := projectRepo.ListSystems(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to list systems: %w", err)
+	}
+
+	splitter := usecases.NewSplitSystem(projectRepo, projectRepo, filesystem.NewFilesystemRelationshipRepository()).
+		WithTrash(filesystem.NewTrash(c.projectRoot))
+	plan, err := splitter.Plan(ctx, project, systems, c.systemID, c.target)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✂️  Split %s into %s\n\n", plan.System, plan.Target)
+	for _, change := range plan.Changes {
+		fmt.Printf("  • %s\n", change)
+	}
+	fmt.Println()
+	if !c.yes && !cli.NewPrompts(bufio.NewReader(os.Stdin)).PromptYesNo("Split?", false) {
+		fmt.Println("Split cancelled")
+		return nil
+	}
+
+	if err := splitter.Apply(ctx, plan); err != nil {
+		return fmt.Errorf("failed to split: %w", err)
+	}
+	fmt.Printf("✓ Split %s into %s\n", plan.System, plan.Target)
+	fmt.Println("  The original directory was moved to the trash; see loko trash list")
+	return nil
+}
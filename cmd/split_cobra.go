@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	splitTo  string
+	splitYes bool
+)
+
+var splitCmd = &cobra.Command{
+	Use:   "split <system> --to <dir>",
+	Short: "Extract a system into its own project",
+	Long: `Extract a system into a new standalone loko project, for when a system has
+grown into an architecture of its own:
+  - a project is created at --to with the configuration of this one, and
+    the system's directory, with its containers, components, diagrams and
+    relationships, is copied into it
+  - in this project the system is replaced by an external system stub whose
+    project_path points at the new project; its directory is moved to the
+    trash (see loko trash)
+  - relationships crossing the split, in frontmatter and relationships.toml,
+    are pointed at the system on the other side; the systems the extracted
+    one depends on are added to the new project as external stubs pointing
+    back
+
+The target directory must not exist or be empty, and must be outside this
+project's source directory.`,
+	GroupID: "scaffolding",
+	Example: `  loko split payments --to ../payments-architecture
+  loko split payments --to ../payments-architecture --yes   # Split without asking`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return NewSplitCommand(ProjectRoot, args[0], splitTo, splitYes).Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+	splitCmd.Flags().StringVar(&splitTo, "to", "", "Root directory of the new project")
+	splitCmd.Flags().BoolVarP(&splitYes, "yes", "y", false, "Split without asking")
+	_ = splitCmd.MarkFlagRequired("to")
+}
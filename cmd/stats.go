@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/adapters/config"
+	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+	"github.com/spf13/cobra"
+)
+
+// StatsUsageCommand prints the trends of the local usage log.
+type StatsUsageCommand struct {
+	projectRoot string
+	weeks       int
+	format      string
+	out         io.Writer
+}
+
+// NewStatsUsageCommand creates a new stats usage command.
+func NewStatsUsageCommand(projectRoot string) *StatsUsageCommand {
+	return &StatsUsageCommand{
+		projectRoot: projectRoot,
+		weeks:       usecases.DefaultUsageWeeks,
+		format:      "text",
+		out:         os.Stdout,
+	}
+}
+
+// WithWeeks sets how many recent weeks the report covers.
+func (c *StatsUsageCommand) WithWeeks(weeks int) *StatsUsageCommand {
+	c.weeks = weeks
+	return c
+}
+
+// WithFormat sets the output format: text or json.
+func (c *StatsUsageCommand) WithFormat(format string) *StatsUsageCommand {
+	if format != "" {
+		c.format = strings.ToLower(format)
+	}
+	return c
+}
+
+// Execute prints the usage report.
+func (c *StatsUsageCommand) Execute(ctx context.Context) error {
+	if c.format != "text" && c.format != "json" {
+		return withExitCode(ExitConfig, fmt.Errorf("unsupported format %q (supported: text, json)", c.format))
+	}
+
+	events, err := filesystem.NewUsageLog(c.projectRoot).Events(ctx)
+	if err != nil {
+		return err
+	}
+	report := usecases.NewBuildUsageReport(c.weeks).Execute(events, time.Now())
+
+	if c.format == "json" {
+		encoder := json.NewEncoder(c.out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode usage report: %w", err)
+		}
+		return nil
+	}
+
+	if len(events) == 0 {
+		fmt.Fprintf(c.out, "No usage recorded in %s\n", filesystem.UsageLogFile)
+		fmt.Fprintln(c.out, "Enable it with record_usage = true in the [metrics] section of loko.toml")
+		return nil
+	}
+	c.writeText(report)
+	return nil
+}
+
+// writeText prints the runs per command and a week-by-week trend.
+func (c *StatsUsageCommand) writeText(report *usecases.UsageReport) {
+	fmt.Fprintf(c.out, "Usage since %s: %d run(s) in the last %d week(s)\n\n",
+		report.Since.Local().Format(time.DateOnly), report.Runs, len(report.Weeks))
+
+	if len(report.Commands) > 0 {
+		fmt.Fprintf(c.out, "  %-24s %6s %8s  %s\n", "COMMAND", "RUNS", "FAILED", "LAST RUN")
+		for _, usage := range report.Commands {
+			fmt.Fprintf(c.out, "  %-24s %6d %8d  %s\n", usage.Command, usage.Runs, usage.Failures, usage.LastRun.Local().Format(time.DateOnly))
+		}
+		fmt.Fprintln(c.out)
+	}
+
+	most := 0
+	for _, week := range report.Weeks {
+		most = max(most, week.Runs)
+	}
+	fmt.Fprintf(c.out, "  %-10s %6s %6s %10s  %s\n", "WEEK OF", "RUNS", "BUILDS", "AVG BUILD", "ACTIVITY")
+	for _, week := range report.Weeks {
+		average := "-"
+		if week.Builds > 0 {
+			average = week.AverageBuild().Round(100 * time.Millisecond).String()
+		}
+		bar := ""
+		if most > 0 {
+			bar = strings.Repeat("█", (week.Runs*20+most-1)/most)
+		}
+		fmt.Fprintf(c.out, "  %-10s %6d %6d %10s  %s\n", week.Start.Format(time.DateOnly), week.Runs, week.Builds, average, bar)
+	}
+}
+
+// StatsHealthCommand prints the documentation health score of the project.
+type StatsHealthCommand struct {
+	projectRoot string
+	staleAfter  time.Duration
+	format      string
+	out         io.Writer
+}
+
+// NewStatsHealthCommand creates a new stats health command.
+func NewStatsHealthCommand(projectRoot string) *StatsHealthCommand {
+	return &StatsHealthCommand{
+		projectRoot: projectRoot,
+		staleAfter:  usecases.DefaultStaleAfter,
+		format:      "text",
+		out:         os.Stdout,
+	}
+}
+
+// WithStaleAfter sets how long a system may go unchanged before it is stale.
+func (c *StatsHealthCommand) WithStaleAfter(staleAfter time.Duration) *StatsHealthCommand {
+	c.staleAfter = staleAfter
+	return c
+}
+
+// WithFormat sets the output format: text or json.
+func (c *StatsHealthCommand) WithFormat(format string) *StatsHealthCommand {
+	if format != "" {
+		c.format = strings.ToLower(format)
+	}
+	return c
+}
+
+// Execute prints the health score and its parts.
+func (c *StatsHealthCommand) Execute(ctx context.Context) error {
+	if c.format != "text" && c.format != "json" {
+		return withExitCode(ExitConfig, fmt.Errorf("unsupported format %q (supported: text, json)", c.format))
+	}
+
+	projectRepo := newProjectRepository()
+	project, err := projectRepo.LoadProject(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	systems, err := projectRepo.ListSystems(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to list systems: %w", err)
+	}
+	health, err := usecases.NewComputeHealthScore().WithStaleAfter(c.staleAfter).Execute(ctx, project, systems)
+	if err != nil {
+		return err
+	}
+
+	if c.format == "json" {
+		encoder := json.NewEncoder(c.out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(health); err != nil {
+			return fmt.Errorf("failed to encode health score: %w", err)
+		}
+		return nil
+	}
+
+	fmt.Fprintf(c.out, "Documentation health: %d/100 (%s)\n\n", health.Score, health.Grade)
+	fmt.Fprintf(c.out, "  %-12s %5d%%  %d element(s)\n", "Coverage", health.Coverage, health.Elements)
+	fmt.Fprintf(c.out, "  %-12s %5d%%  %d error(s), %d warning(s)\n", "Validation", health.Validation, health.Errors, health.Warnings)
+	fmt.Fprintf(c.out, "  %-12s %5d%%  %d stale system(s)\n", "Freshness", health.Freshness, health.StaleSystems)
+	fmt.Fprintf(c.out, "  %-12s %5d%%\n", "Diagrams", health.Diagrams)
+	return nil
+}
+
+// recordUsage appends the run of cmd that started at start and ended with
+// err to the usage log of the project, when its loko.toml (or the global
+// config) enables [metrics] record_usage. Recording never fails the run.
+func recordUsage(cmd *cobra.Command, start time.Time, err error) {
+	if cmd == nil || cmd == rootCmd || cmd.Hidden || !cmd.Runnable() {
+		return
+	}
+	switch cmd.Name() {
+	case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return
+	}
+	if _, statErr := os.Stat(filepath.Join(ProjectRoot, "loko.toml")); statErr != nil {
+		return
+	}
+	ctx := context.Background()
+	projectConfig, loadErr := config.NewLoader(nil).LoadConfig(ctx, ProjectRoot)
+	if loadErr != nil || !projectConfig.RecordUsage {
+		return
+	}
+
+	event := &entities.UsageEvent{
+		Time:       start.UTC(),
+		Command:    strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" "),
+		DurationMS: time.Since(start).Milliseconds(),
+		ExitCode:   ExitCode(err),
+	}
+	if appendErr := filesystem.NewUsageLog(ProjectRoot).Append(ctx, event); appendErr != nil && Verbose {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record usage: %v\n", appendErr)
+	}
+}
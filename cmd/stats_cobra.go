@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"github.com/madstone-tech/loko/internal/core/usecases"
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show statistics about how the project is maintained",
+	Long: `Show the documentation health of the project and statistics from the
+local usage log.
+
+Usage recording is off by default. With record_usage = true in the [metrics]
+section of loko.toml, every loko command run in the project is recorded in
+.loko/usage.log: the command, when it ran, how long it took and its exit
+code. Arguments are not recorded and nothing is ever sent over the network.`,
+	GroupID: "building",
+}
+
+var statsUsageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show command usage and build duration trends",
+	Long: `Show how often each command ran in recent weeks, and per week the number
+of runs, the number of builds and their average duration.`,
+	Example: `  loko stats usage
+  loko stats usage --weeks 26
+  loko stats usage --format json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		weeks, _ := cmd.Flags().GetInt("weeks")
+		format, _ := cmd.Flags().GetString("format")
+		return NewStatsUsageCommand(ProjectRoot).
+			WithWeeks(weeks).
+			WithFormat(format).
+			Execute(cmd.Context())
+	},
+}
+
+var statsHealthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Show the documentation health score",
+	Long: `Show the documentation health score of the project, from 0 to 100, and
+the parts it is computed from:
+
+  Coverage    40%  elements with a description
+  Validation  30%  fewer validation errors and warnings per element
+  Freshness   15%  systems whose sources changed within --stale-after
+  Diagrams    15%  systems and containers with a diagram
+
+loko build writes the same score to dist/badge.svg and dist/health.json, a
+shields.io endpoint: https://img.shields.io/endpoint?url=<site>/health.json`,
+	Example: `  loko stats health
+  loko stats health --stale-after 2160h
+  loko stats health --format json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		staleAfter, _ := cmd.Flags().GetDuration("stale-after")
+		format, _ := cmd.Flags().GetString("format")
+		return NewStatsHealthCommand(ProjectRoot).
+			WithStaleAfter(staleAfter).
+			WithFormat(format).
+			Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.AddCommand(statsUsageCmd)
+	statsUsageCmd.Flags().Int("weeks", usecases.DefaultUsageWeeks, "number of recent weeks to report")
+	statsUsageCmd.Flags().StringP("format", "f", "text", "output format (text, json)")
+	_ = statsUsageCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp))
+
+	statsCmd.AddCommand(statsHealthCmd)
+	statsHealthCmd.Flags().Duration("stale-after", usecases.DefaultStaleAfter, "how long a system may go unchanged before it counts as stale")
+	statsHealthCmd.Flags().StringP("format", "f", "text", "output format (text, json)")
+	_ = statsHealthCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp))
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/madstone-tech/loko/internal/adapters/d2"
+	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// SyncDiagramsCommand reconciles the D2 diagrams of systems and containers
+// with the model.
+type SyncDiagramsCommand struct {
+	projectRoot string
+	dryRun      bool
+	check       bool
+}
+
+// NewSyncDiagramsCommand creates a new sync diagrams command.
+func NewSyncDiagramsCommand(projectRoot string) *SyncDiagramsCommand {
+	return &SyncDiagramsCommand{projectRoot: projectRoot}
+}
+
+// WithDryRun lists the changes without writing them.
+func (c *SyncDiagramsCommand) WithDryRun(dryRun bool) *SyncDiagramsCommand {
+	c.dryRun = dryRun
+	return c
+}
+
+// WithCheck writes nothing and fails if a diagram is out of sync.
+func (c *SyncDiagramsCommand) WithCheck(check bool) *SyncDiagramsCommand {
+	c.check = check
+	return c
+}
+
+// Execute runs the sync diagrams command.
+func (c *SyncDiagramsCommand) Execute(ctx context.Context) error {
+	projectRepo := newProjectRepository()
+	project, err := projectRepo.LoadProject(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	systems, err := projectRepo.ListSystems(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to list systems: %w", err)
+	}
+
+	// The model's relationships: frontmatter and relationships.toml, not diagrams
+	graphBuilder := usecases.NewBuildArchitectureGraphWithRelRepo(filesystem.NewFilesystemRelationshipRepository())
+	graph, err := graphBuilder.Execute(ctx, project, systems)
+	if err != nil {
+		return fmt.Errorf("failed to build architecture graph: %w", err)
+	}
+
+	syncer := usecases.NewSyncDiagrams(d2.NewD2Parser())
+	syncs, err := syncer.Plan(ctx, graph, systems)
+	if err != nil {
+		return err
+	}
+
+	changed, stale := 0, 0
+	for _, sync := range syncs {
+		path := sync.Path
+		if rel, err := filepath.Rel(c.projectRoot, sync.Path); err == nil {
+			path = rel
+		}
+		fmt.Printf("%s (%s)\n", path, sync.Element)
+		for _, shape := range sync.Shapes {
+			fmt.Printf("  + %s\n", shape)
+		}
+		for _, edge := range sync.Edges {
+			fmt.Printf("  + %s\n", edge)
+		}
+		if sync.Changed && len(sync.Shapes)+len(sync.Edges) == 0 {
+			fmt.Println("  - managed block (nothing left to add)")
+		}
+		for _, shape := range sync.Stale {
+			fmt.Printf("  ⚠ %s is not in the model; remove it if the element was deleted\n", shape)
+		}
+		stale += len(sync.Stale)
+		if !sync.Changed {
+			continue
+		}
+		changed++
+		if c.dryRun || c.check {
+			continue
+		}
+		if err := syncer.Apply(sync); err != nil {
+			return err
+		}
+	}
+
+	if changed == 0 && stale == 0 {
+		fmt.Println("✓ Diagrams are in sync with the model")
+		return nil
+	}
+	fmt.Println()
+	switch {
+	case c.check && changed > 0:
+		return withExitCode(ExitValidation, fmt.Errorf("%d diagram(s) are out of sync with the model; run loko sync diagrams", changed))
+	case c.dryRun:
+		fmt.Printf("Dry run: %d diagram(s) would change\n", changed)
+	case changed > 0:
+		fmt.Printf("✓ Synced %d diagram(s)\n", changed)
+	}
+	if stale > 0 {
+		fmt.Printf("⚠ %d shape(s) name elements not in the model\n", stale)
+	}
+	return nil
+}
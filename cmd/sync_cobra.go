@@ -0,0 +1,49 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var syncCmd = &cobra.Command{
+	Use:     "sync",
+	Short:   "Reconcile project files with the model",
+	GroupID: "scaffolding",
+}
+
+var syncDiagramsCmd = &cobra.Command{
+	Use:   "diagrams",
+	Short: "Add the relationships of the model missing from D2 diagrams",
+	Long: `Reconcile the D2 diagrams of systems and containers with the model.
+
+A system's diagram shows its containers and a container's diagram its
+components. Every relationship declared in frontmatter or relationships.toml
+between two of them, or between their descendants, must be drawn; those
+missing are added with their shapes to the diagram's managed block:
+
+  # loko:managed:begin
+  ...
+  # loko:managed:end
+
+The block is appended when a diagram has none, and may be moved anywhere in
+the file. loko rewrites only the block, so the rest of the diagram is never
+changed: shapes nested in an element but naming none, typically elements
+removed from the model, are reported for you to delete.`,
+	Example: `  loko sync diagrams --dry-run   # List the changes without writing them
+  loko sync diagrams
+  loko sync diagrams --check     # Fail in CI if a diagram is out of sync`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		check, _ := cmd.Flags().GetBool("check")
+		return NewSyncDiagramsCommand(ProjectRoot).
+			WithDryRun(dryRun).
+			WithCheck(check).
+			Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.AddCommand(syncDiagramsCmd)
+
+	syncDiagramsCmd.Flags().Bool("dry-run", false, "list the changes without writing them")
+	syncDiagramsCmd.Flags().Bool("check", false, "write nothing and exit non-zero if a diagram is out of sync")
+}
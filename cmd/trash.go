@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/adapters/cli"
+	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// TrashCommand lists, restores or purges the elements deleted from a project
+// into .loko/trash/.
+type TrashCommand struct {
+	projectRoot string
+	action      string // "list", "restore" or "purge"
+	id          string // Entry to restore or purge; empty purges all
+	yes         bool
+	out         io.Writer
+}
+
+// NewTrashCommand creates a new trash command running action on entry id.
+func NewTrashCommand(projectRoot, action, id string) *TrashCommand {
+	return &TrashCommand{
+		projectRoot: projectRoot,
+		action:      action,
+		id:          id,
+		out:         os.Stdout,
+	}
+}
+
+// WithYes skips the confirmation before purging.
+func (c *TrashCommand) WithYes(yes bool) *TrashCommand {
+	c.yes = yes
+	return c
+}
+
+// Execute runs the trash command.
+func (c *TrashCommand) Execute(ctx context.Context) error {
+	trash := filesystem.NewTrash(c.projectRoot)
+
+	switch c.action {
+	case "list":
+		entries, err := trash.Entries(ctx)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Fprintf(c.out, "The trash in %s is empty\n", filesystem.TrashDir)
+			return nil
+		}
+		for i := len(entries) - 1; i >= 0; i-- {
+			c.printEntry(entries[i])
+		}
+		return nil
+
+	case "restore":
+		entry, err := trash.Restore(ctx, c.id)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "✓ Restored %s\n", strings.Join(entry.Paths, ", "))
+		return nil
+
+	case "purge":
+		what := "every entry in the trash"
+		if c.id != "" {
+			what = c.id
+		}
+		if !c.yes && !cli.NewPrompts(bufio.NewReader(os.Stdin)).PromptYesNo(fmt.Sprintf("Permanently delete %s?", what), false) {
+			fmt.Fprintln(c.out, "Purge cancelled")
+			return nil
+		}
+		purged, err := trash.Purge(ctx, c.id)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "✓ Purged %d deletion(s) from the trash\n", len(purged))
+		return nil
+
+	default:
+		return fmt.Errorf("unknown trash action %q (expected list, restore or purge)", c.action)
+	}
+}
+
+// printEntry writes one entry: its ID, time and reason, then the deleted paths.
+func (c *TrashCommand) printEntry(entry *entities.TrashEntry) {
+	fmt.Fprintf(c.out, "%s  %s  %s\n", entry.ID, entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Reason)
+	for _, path := range entry.Paths {
+		fmt.Fprintf(c.out, "    %s\n", path)
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "List, restore or purge deleted elements",
+	Long: `Elements deleted by loko, such as the duplicate removed by loko merge, are
+moved to .loko/trash/<timestamp>/ instead of being removed, so that an
+accidental deletion by an agent or a person can be undone.`,
+	GroupID: "scaffolding",
+}
+
+var trashListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the entries in the trash, newest first",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return NewTrashCommand(ProjectRoot, "list", "").Execute(cmd.Context())
+	},
+}
+
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore ID",
+	Short: "Move the paths of a trash entry back into the project",
+	Long: `Move the files and directories of the trash entry ID back to where they were
+deleted from. Nothing is restored if one of them has been recreated since.`,
+	Args:    cobra.ExactArgs(1),
+	Example: `  loko trash restore 20261016-142501`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return NewTrashCommand(ProjectRoot, "restore", args[0]).Execute(cmd.Context())
+	},
+}
+
+var trashPurgeCmd = &cobra.Command{
+	Use:   "purge [ID]",
+	Short: "Permanently delete a trash entry, or the whole trash",
+	Args:  cobra.MaximumNArgs(1),
+	Example: `  loko trash purge 20261016-142501
+  loko trash purge --yes`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		yes, _ := cmd.Flags().GetBool("yes")
+		id := ""
+		if len(args) == 1 {
+			id = args[0]
+		}
+		return NewTrashCommand(ProjectRoot, "purge", id).WithYes(yes).Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(trashCmd)
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashRestoreCmd)
+	trashCmd.AddCommand(trashPurgeCmd)
+	trashPurgeCmd.Flags().BoolP("yes", "y", false, "purge without asking for confirmation")
+}
@@ -1,10 +1,16 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 
+	"github.com/madstone-tech/loko/internal/adapters/cli"
+	"github.com/madstone-tech/loko/internal/adapters/d2"
 	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/adapters/issues"
 	"github.com/madstone-tech/loko/internal/core/entities"
 	"github.com/madstone-tech/loko/internal/core/usecases"
 )
@@ -15,6 +21,12 @@ type ValidateCommand struct {
 	strict      bool
 	exitCode    bool
 	checkDrift  bool
+	checkIssues bool
+	renderCheck bool
+	noPlugins   bool
+	noHooks     bool
+	fix         bool
+	yes         bool
 }
 
 // NewValidateCommand creates a new validate command.
@@ -24,18 +36,35 @@ func NewValidateCommand(projectRoot string, strict, exitCode bool) *ValidateComm
 		strict:      strict,
 		exitCode:    exitCode,
 		checkDrift:  validateCheckDrift, // Access the global flag
+		checkIssues: validateCheckIssues,
+		renderCheck: validateRenderCheck,
+		noPlugins:   validateNoPlugins,
+		noHooks:     validateNoHooks,
+		fix:         validateFix,
+		yes:         validateYes,
 	}
 }
 
 // Execute runs the validate command.
 func (c *ValidateCommand) Execute(ctx context.Context) error {
 	// Load the project
-	projectRepo := filesystem.NewProjectRepository()
+	projectRepo := newProjectRepository()
 	project, err := projectRepo.LoadProject(ctx, c.projectRoot)
 	if err != nil {
 		return fmt.Errorf("failed to load project: %w", err)
 	}
 
+	// Pre-validate hooks run before sources are read so they can generate them.
+	if hooks := newRunHooks(project); !c.noHooks && hooks.Has(usecases.HookPreValidate) {
+		hc, err := hookContext(ctx, c.projectRoot, project)
+		if err != nil {
+			return err
+		}
+		if err := hooks.Execute(ctx, usecases.HookPreValidate, hc); err != nil {
+			return err
+		}
+	}
+
 	// List systems
 	systems, err := projectRepo.ListSystems(ctx, c.projectRoot)
 	if err != nil {
@@ -47,13 +76,25 @@ func (c *ValidateCommand) Execute(ctx context.Context) error {
 		return nil
 	}
 
+	// Apply fixes first so the checks below report what remains
+	if c.fix {
+		if systems, err = c.applyFixes(ctx, projectRepo, project, systems); err != nil {
+			return err
+		}
+	}
+
 	// Check for drift if requested
 	if c.checkDrift {
 		return c.executeDriftCheck(ctx, projectRepo, systems)
 	}
 
-	// Build architecture graph
-	graphBuilder := usecases.NewBuildArchitectureGraph()
+	// Check issue references if requested
+	if c.checkIssues {
+		return c.executeIssueCheck(ctx, project.Config, systems)
+	}
+
+	// Build architecture graph, with the relationships drawn in D2 diagrams
+	graphBuilder := usecases.NewBuildArchitectureGraphWithD2(d2.NewD2Parser())
 	graph, err := graphBuilder.Execute(ctx, project, systems)
 	if err != nil {
 		return fmt.Errorf("failed to build architecture graph: %w", err)
@@ -62,6 +103,30 @@ func (c *ValidateCommand) Execute(ctx context.Context) error {
 	// Validate architecture
 	validator := usecases.NewValidateArchitecture()
 	report := validator.Execute(graph, systems)
+	if err := c.validateRelationships(ctx, project, systems, graph, report); err != nil {
+		return err
+	}
+	if project.Config != nil && project.Config.RequireDeployment {
+		usecases.NewValidateDeploymentMapping(project.Config).Execute(systems, report)
+	}
+	if project.Config != nil && project.Config.RequireApproval {
+		usecases.NewValidateReviewStatus().Execute(systems, report)
+	}
+	lint, err := usecases.NewLintNaming(project.Config)
+	if err != nil {
+		return err
+	}
+	lint.Execute(systems, report)
+	if c.renderCheck {
+		if err := usecases.NewValidateDiagramRendering(d2.NewD2Parser(), c.projectRoot).Execute(ctx, systems, report); err != nil {
+			return err
+		}
+	}
+	if !c.noPlugins {
+		if err := runValidatorPlugins(ctx, project, systems, report); err != nil {
+			return err
+		}
+	}
 
 	// Print validation results
 	c.printReport(report)
@@ -78,9 +143,9 @@ func (c *ValidateCommand) Execute(ctx context.Context) error {
 		if c.exitCode {
 			// exit-code flag: return error with exit code 1
 			if c.strict && report.Errors == 0 {
-				return fmt.Errorf("validation failed with %d warning(s) (strict mode)", report.Warnings)
+				return withExitCode(ExitWarnings, fmt.Errorf("validation failed with %d warning(s) (strict mode)", report.Warnings))
 			}
-			return fmt.Errorf("validation failed with %d error(s)", report.Errors)
+			return withExitCode(ExitValidation, fmt.Errorf("validation failed with %d error(s)", report.Errors))
 		}
 		// Without exit-code flag, print message but return success
 		fmt.Println("\n⚠  Note: Use --exit-code flag to exit with non-zero status")
@@ -89,6 +154,82 @@ func (c *ValidateCommand) Execute(ctx context.Context) error {
 	return nil
 }
 
+// validateRelationships adds an issue to report for every relationship
+// whose kind is unknown or does not fit its source and target elements, and
+// for every description breaking a [relationship_descriptions] rule.
+func (c *ValidateCommand) validateRelationships(ctx context.Context, project *entities.Project, systems []*entities.System, graph *entities.ArchitectureGraph, report *usecases.ArchitectureReport) error {
+	kinds, err := usecases.NewValidateRelationshipKinds(project.Config)
+	if err != nil {
+		return err
+	}
+	descriptions, err := usecases.NewValidateRelationshipDescriptions(project.Config)
+	if err != nil {
+		return err
+	}
+	relationships, err := loadRelationships(ctx, c.projectRoot, systems, nil)
+	if err != nil {
+		return err
+	}
+	var stored []entities.Relationship
+	for _, system := range systems {
+		kinds.Execute(systems, relationships[system.ID], report)
+		stored = append(stored, relationships[system.ID]...)
+	}
+	descriptions.Execute(graph, stored, report)
+	return nil
+}
+
+// applyFixes offers the automated fixes for the problems found in systems,
+// applies those confirmed (all of them with --yes) and returns the systems
+// reloaded after any change.
+func (c *ValidateCommand) applyFixes(ctx context.Context, projectRepo *filesystem.ProjectRepository, project *entities.Project, systems []*entities.System) ([]*entities.System, error) {
+	graph, err := usecases.NewBuildArchitectureGraph().Execute(ctx, project, systems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build architecture graph: %w", err)
+	}
+	generator, err := newDiagramGenerator(ctx, projectRepo, c.projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	fixer := usecases.NewFixArchitecture(projectRepo, generator)
+	fixes, err := fixer.Plan(graph, systems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan fixes: %w", err)
+	}
+	if len(fixes) == 0 {
+		fmt.Println("✓ Nothing to fix")
+		fmt.Println()
+		return systems, nil
+	}
+
+	fmt.Printf("🔧 %d automated fix(es) available\n", len(fixes))
+	prompts := cli.NewPrompts(bufio.NewReader(os.Stdin))
+	applied := 0
+	for _, fix := range fixes {
+		path := fix.Path
+		if rel, err := filepath.Rel(c.projectRoot, fix.Path); err == nil {
+			path = rel
+		}
+		fmt.Printf("\n  %s: %s\n    %s\n", fix.Element, fix.Description, path)
+		if !c.yes && !prompts.PromptYesNo("  Apply?", false) {
+			continue
+		}
+		if err := fixer.Apply(ctx, fix); err != nil {
+			fmt.Printf("  ✗ %v\n", err)
+			continue
+		}
+		fmt.Println("  ✓ Applied")
+		applied++
+	}
+	fmt.Printf("\nApplied %d of %d fix(es)\n\n", applied, len(fixes))
+
+	if applied == 0 {
+		return systems, nil
+	}
+	return projectRepo.ListSystems(ctx, c.projectRoot)
+}
+
 // executeDriftCheck runs drift detection and formats output according to the contract.
 func (c *ValidateCommand) executeDriftCheck(ctx context.Context, projectRepo usecases.ProjectRepository, systems []*entities.System) error {
 	// Create drift detection use case
@@ -114,7 +255,7 @@ func (c *ValidateCommand) executeDriftCheck(ctx context.Context, projectRepo use
 				fmt.Printf("  %s (ERROR): %s\n", issue.ComponentID, issue.Message)
 			}
 		}
-		return fmt.Errorf("drift detection failed with %d error(s)", len(result.Issues))
+		return withExitCode(ExitValidation, fmt.Errorf("drift detection failed with %d error(s)", len(result.Issues)))
 	} else if result.HasWarnings {
 		fmt.Println("⚠️  Validation passed with warnings")
 		fmt.Println("Issues found:")
@@ -135,6 +276,56 @@ func (c *ValidateCommand) executeDriftCheck(ctx context.Context, projectRepo use
 	}
 }
 
+// executeIssueCheck looks up the tickets referenced from `issues:` frontmatter
+// and reports elements that point at closed, abandoned or deleted tickets.
+func (c *ValidateCommand) executeIssueCheck(ctx context.Context, config *entities.ProjectConfig, systems []*entities.System) error {
+	if config == nil {
+		config = entities.DefaultProjectConfig()
+	}
+	tracker, err := issues.NewTracker(config.IssueTracker, config.IssueTrackerURL, config.IssueURLTemplate, os.Getenv)
+	if err != nil {
+		return err
+	}
+
+	report, err := usecases.NewCheckIssueReferences(tracker).Execute(ctx, systems)
+	if err != nil {
+		return fmt.Errorf("failed to check issue references: %w", err)
+	}
+
+	for _, ref := range report.Failed {
+		fmt.Printf("  %s (ERROR): could not check %s: %v\n", ref.Element, ref.Issue, ref.Err)
+	}
+	if len(report.Stale) == 0 {
+		if len(report.Failed) > 0 {
+			return fmt.Errorf("failed to check %d issue reference(s)", len(report.Failed))
+		}
+		fmt.Printf("✅ Validation passed - No stale issue references\n")
+		fmt.Printf("  Issue references checked: %d\n", report.Checked)
+		return nil
+	}
+
+	fmt.Println("⚠️  Elements reference closed or abandoned tickets")
+	fmt.Println("Issues found:")
+	for _, ref := range report.Stale {
+		switch {
+		case ref.Status == nil:
+			fmt.Printf("  %s (WARNING): %s no longer exists\n", ref.Element, ref.Issue)
+		case ref.Status.Resolution != "":
+			fmt.Printf("  %s (WARNING): %s is %s (%s)\n", ref.Element, ref.Issue, ref.Status.Status, ref.Status.Resolution)
+		default:
+			fmt.Printf("  %s (WARNING): %s is %s\n", ref.Element, ref.Issue, ref.Status.Status)
+		}
+	}
+
+	if len(report.Failed) > 0 {
+		return fmt.Errorf("failed to check %d issue reference(s)", len(report.Failed))
+	}
+	if c.strict && c.exitCode {
+		return withExitCode(ExitWarnings, fmt.Errorf("validation failed with %d stale issue reference(s) (strict mode)", len(report.Stale)))
+	}
+	return nil
+}
+
 // printReport prints the validation report to stdout.
 func (c *ValidateCommand) printReport(report *usecases.ArchitectureReport) {
 	report.Print()
@@ -3,9 +3,15 @@ package cmd
 import "github.com/spf13/cobra"
 
 var (
-	validateStrict     bool
-	validateExitCode   bool
-	validateCheckDrift bool
+	validateStrict      bool
+	validateExitCode    bool
+	validateCheckDrift  bool
+	validateCheckIssues bool
+	validateRenderCheck bool
+	validateNoPlugins   bool
+	validateNoHooks     bool
+	validateFix         bool
+	validateYes         bool
 )
 
 var validateCmd = &cobra.Command{
@@ -15,12 +21,26 @@ var validateCmd = &cobra.Command{
 	Long: `Check the project for structural errors, orphaned references, and convention violations.
 
 Flags:
-  --strict      Treat warnings as errors (useful for CI/CD)
-  --exit-code   Return non-zero exit code on validation failures`,
+  --strict        Treat warnings as errors (useful for CI/CD)
+  --exit-code     Return non-zero exit code on validation failures
+  --check-issues  Report elements referencing closed or abandoned tickets
+                  (requires an [issues] tracker and LOKO_ISSUE_TOKEN)
+  --render-check  Compile every D2 diagram, without rendering it, and report
+                  compile errors with the file paths
+  --no-plugins    Skip the checks of installed validator plugins
+  --no-hooks      Skip the [hooks] pre_validate commands
+  --fix           Offer automated fixes: create missing diagrams, remove
+                  relationships to unknown components and add placeholder
+                  descriptions marked TODO
+  --yes           Apply every fix without asking (for CI bots)`,
 	GroupID: "building",
 	Example: `  loko validate
   loko validate --project ./myproject
-  loko validate --strict --exit-code    # For CI/CD pipelines`,
+  loko validate --strict --exit-code    # For CI/CD pipelines
+  loko validate --check-issues          # Find links to closed tickets
+  loko validate --render-check          # Find diagrams the build cannot render
+  loko validate --fix                   # Review and apply fixes
+  loko validate --fix --yes             # Apply every fix`,
 	RunE: runValidate,
 }
 
@@ -29,6 +49,12 @@ func init() {
 	validateCmd.Flags().BoolVar(&validateStrict, "strict", false, "Treat warnings as errors")
 	validateCmd.Flags().BoolVar(&validateExitCode, "exit-code", false, "Exit with non-zero status on validation failures")
 	validateCmd.Flags().BoolVar(&validateCheckDrift, "check-drift", false, "Check for drift between D2 diagrams and frontmatter")
+	validateCmd.Flags().BoolVar(&validateCheckIssues, "check-issues", false, "Report elements referencing closed or abandoned tickets")
+	validateCmd.Flags().BoolVar(&validateRenderCheck, "render-check", false, "Compile every D2 diagram and report compile errors")
+	validateCmd.Flags().BoolVar(&validateNoPlugins, "no-plugins", false, "Skip the checks of installed validator plugins")
+	validateCmd.Flags().BoolVar(&validateNoHooks, "no-hooks", false, "Skip the [hooks] pre_validate commands")
+	validateCmd.Flags().BoolVar(&validateFix, "fix", false, "Offer automated fixes for common issues")
+	validateCmd.Flags().BoolVarP(&validateYes, "yes", "y", false, "Apply every fix without asking")
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/adapters/signing"
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// VerifyCommand smoke tests built documentation and checks it against its
+// provenance statement.
+type VerifyCommand struct {
+	dir         string
+	projectRoot string
+	publicKey   string
+}
+
+// NewVerifyCommand creates a new verify command for a documentation directory
+// built from the project at projectRoot.
+func NewVerifyCommand(dir, projectRoot string) *VerifyCommand {
+	return &VerifyCommand{dir: dir, projectRoot: projectRoot}
+}
+
+// WithPublicKey requires the statement to be signed by the Ed25519 key in path.
+func (c *VerifyCommand) WithPublicKey(path string) *VerifyCommand {
+	c.publicKey = path
+	return c
+}
+
+// Execute smoke tests the site and, when the directory has a provenance
+// statement or a public key is given, verifies the statement and prints the
+// model state it attests to.
+func (c *VerifyCommand) Execute(ctx context.Context) error {
+	siteErr := c.verifySite(ctx)
+	if c.publicKey == "" {
+		if _, err := os.Stat(filepath.Join(c.dir, usecases.ProvenanceFile)); errors.Is(err, fs.ErrNotExist) {
+			return siteErr
+		}
+	}
+	return errors.Join(siteErr, c.verifyProvenance(ctx))
+}
+
+// verifySite checks that the site has its home page, a page and diagram for
+// every element of the project, a valid search index and no scaffolding
+// placeholders. Without a project, the pages of the elements are not checked.
+func (c *VerifyCommand) verifySite(ctx context.Context) error {
+	verify := usecases.NewVerifySite()
+	var systems []*entities.System
+	projectRepo := newProjectRepository()
+	project, err := projectRepo.LoadProject(ctx, c.projectRoot)
+	if err == nil {
+		systems, err = projectRepo.ListSystems(ctx, c.projectRoot)
+	}
+	if err != nil {
+		fmt.Printf("⚠ No project at %s: element pages not checked\n", c.projectRoot)
+		systems = nil
+	} else if project.Config != nil {
+		verify.WithBasePath(project.Config.BasePath)
+	}
+
+	report, err := verify.Execute(ctx, c.dir, systems)
+	if err != nil {
+		return fmt.Errorf("verification failed: %w", err)
+	}
+	if report.OK() {
+		fmt.Printf("✓ Site: %d page(s), %d diagram(s), %d search result(s)\n", report.Pages, report.Diagrams, report.SearchEntries)
+		return nil
+	}
+	for _, problem := range report.Problems {
+		fmt.Printf("  %s: %s\n", problem.Path, problem.Reason)
+	}
+	return withExitCode(ExitValidation, fmt.Errorf("%s failed %d site check(s)", c.dir, len(report.Problems)))
+}
+
+// verifyProvenance verifies the statement and prints the model state it
+// attests to.
+func (c *VerifyCommand) verifyProvenance(ctx context.Context) error {
+	verify := usecases.NewVerifyProvenance()
+	if c.publicKey != "" {
+		verifier, err := signing.NewKeyVerifier(c.publicKey)
+		if err != nil {
+			return fmt.Errorf("failed to load public key: %w", err)
+		}
+		verify.WithVerifier(verifier)
+	}
+
+	report, err := verify.Execute(ctx, c.dir)
+	if err != nil {
+		return fmt.Errorf("verification failed: %w", err)
+	}
+
+	predicate := report.Statement.Predicate
+	switch {
+	case report.Verified:
+		fmt.Println("✓ Signature verified")
+	case report.Signed:
+		fmt.Println("⚠ Signed, but no public key given (use --key to check the signature)")
+	default:
+		fmt.Println("⚠ Provenance is not signed")
+	}
+	for _, dep := range predicate.BuildDefinition.ResolvedDependencies {
+		if commit := dep.Digest["gitCommit"]; commit != "" {
+			dirty := ""
+			if dep.Annotations["dirty"] == true {
+				dirty = " (with uncommitted changes)"
+			}
+			fmt.Printf("  Source:  %s@%s%s\n", dep.URI, commit, dirty)
+		}
+	}
+	for _, tool := range []string{"loko", "d2"} {
+		if version := predicate.RunDetails.Builder.Version[tool]; version != "" {
+			fmt.Printf("  %-8s %s\n", tool+":", version)
+		}
+	}
+	fmt.Printf("  Built:   %s\n", predicate.RunDetails.Metadata.FinishedOn.Format(time.RFC3339))
+	fmt.Printf("  Inputs:  %d file(s)\n", len(predicate.BuildDefinition.ResolvedDependencies))
+
+	if report.OK() {
+		fmt.Printf("✓ %d file(s) match the provenance statement\n", len(report.Statement.Subject))
+		return nil
+	}
+	for _, name := range report.Modified {
+		fmt.Printf("  modified: %s\n", name)
+	}
+	for _, name := range report.Missing {
+		fmt.Printf("  missing:  %s\n", name)
+	}
+	for _, name := range report.Unlisted {
+		fmt.Printf("  unlisted: %s\n", name)
+	}
+	return withExitCode(ExitValidation, fmt.Errorf("%s does not match its provenance statement (%d modified, %d missing, %d unlisted)",
+		c.dir, len(report.Modified), len(report.Missing), len(report.Unlisted)))
+}
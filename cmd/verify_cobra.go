@@ -0,0 +1,36 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [dir]",
+	Short: "Smoke test built documentation and verify its provenance",
+	Long: `Smoke test a built site, e.g. as a CI gate after loko build: the directory
+must have a home page, a page for every system, container and component of
+the project and an SVG for every diagram, a valid search index whose results
+resolve, non-empty SVGs, and no scaffolding placeholders such as {{Description}}
+or the "TODO: describe" left by loko validate --fix.
+
+When the directory has the provenance.intoto.json written by loko build
+--provenance, every file must also match its recorded digest, and the source
+commit, loko and d2 versions that produced the documentation are printed.
+With --key, the statement must be present and carry a valid signature from
+the Ed25519 public key (PEM) in that file.`,
+	GroupID: "building",
+	Args:    cobra.MaximumNArgs(1),
+	Example: `  loko verify dist
+  loko verify /var/www/docs --key loko-signing.pub.pem`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "dist"
+		if len(args) > 0 {
+			dir = args[0]
+		}
+		key, _ := cmd.Flags().GetString("key")
+		return NewVerifyCommand(dir, ProjectRoot).WithPublicKey(key).Execute(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+	verifyCmd.Flags().String("key", "", "Ed25519 public key (PEM) the provenance must be signed with")
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/adapters/html"
+)
+
+// ViewCommand builds the site into a temporary directory and serves it,
+// rebuilding and reloading open pages when the sources change.
+type ViewCommand struct {
+	projectRoot string
+	address     string
+	port        string
+
+	allowPlaintext bool // Serve even when the project's sources are encrypted
+}
+
+// NewViewCommand creates a new view command.
+func NewViewCommand(projectRoot string) *ViewCommand {
+	return &ViewCommand{
+		projectRoot: projectRoot,
+		address:     "localhost",
+		port:        "8080",
+	}
+}
+
+// WithAddress sets the server address.
+func (c *ViewCommand) WithAddress(address string) *ViewCommand {
+	c.address = address
+	return c
+}
+
+// WithPort sets the server port.
+func (c *ViewCommand) WithPort(port string) *ViewCommand {
+	c.port = port
+	return c
+}
+
+// WithAllowPlaintext permits serving a project whose sources are encrypted at rest.
+func (c *ViewCommand) WithAllowPlaintext(allow bool) *ViewCommand {
+	c.allowPlaintext = allow
+	return c
+}
+
+// Execute runs the view command.
+func (c *ViewCommand) Execute(ctx context.Context) error {
+	project, err := newProjectRepository().LoadProject(ctx, c.projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	if err := ensurePlaintextAllowed(project, c.projectRoot, c.allowPlaintext); err != nil {
+		return err
+	}
+	basePath := "/"
+	if router, err := html.NewRouter(project.Config.BasePath, false); err == nil && router.BasePath() != "" {
+		basePath = router.BasePath()
+	}
+
+	// The site is never written to the project, only to a directory removed on exit
+	outputDir, err := os.MkdirTemp("", "loko-view-")
+	if err != nil {
+		return fmt.Errorf("failed to create build directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(outputDir) }()
+
+	reload := html.NewLiveReload()
+	firstBuild := make(chan struct{})
+	var once sync.Once
+	watch := NewWatchCommand(c.projectRoot).
+		WithOutputDir(outputDir).
+		WithAllowPlaintext(c.allowPlaintext).
+		WithOnBuild(func(err error) {
+			once.Do(func() { close(firstBuild) })
+			if err == nil {
+				reload.Reload()
+			}
+		})
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	watchErr := make(chan error, 1)
+	go func() { watchErr <- watch.Execute(ctx) }()
+
+	// Serve once the initial build is done, so the first page loads complete
+	select {
+	case <-firstBuild:
+	case err := <-watchErr:
+		return err
+	}
+
+	mux := newSiteMux(reload.Inject(http.FileServer(http.Dir(outputDir))), basePath)
+	mux.Handle(html.LiveReloadPath, reload)
+	addr := net.JoinHostPort(c.address, c.port)
+	server := &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+	server.RegisterOnShutdown(reload.Close)
+
+	serverErr := make(chan error, 1)
+	go func() { serverErr <- server.ListenAndServe() }()
+	fmt.Printf("🚀 Viewing on http://%s%s\n", addr, basePath)
+	fmt.Println("   Pages reload when the sources change")
+	fmt.Println()
+
+	select {
+	case err := <-serverErr:
+		cancel()
+		<-watchErr
+		return fmt.Errorf("server error: %w", err)
+	case err := <-watchErr:
+		// The watcher stops on Ctrl+C
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelShutdown()
+		if shutdownErr := server.Shutdown(shutdownCtx); shutdownErr != nil && err == nil {
+			err = fmt.Errorf("shutdown error: %w", shutdownErr)
+		}
+		fmt.Println("✓ Server stopped")
+		return err
+	}
+}
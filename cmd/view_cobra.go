@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var viewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Build and serve the site in one step",
+	Long: `Build the HTML site into a temporary directory and serve it right away,
+for a quick look at a checked-out model without a build/serve cycle.
+
+Nothing is written to the project: the site is built outside it and removed
+on exit. Changes to the sources are rebuilt as by loko watch, and open pages
+reload themselves when a rebuild completes.`,
+	GroupID: "serving",
+	Example: `  loko view
+  loko view --port 3000
+  loko view --project ../payments-architecture`,
+	RunE: runView,
+}
+
+func init() {
+	rootCmd.AddCommand(viewCmd)
+	viewCmd.Flags().String("address", "localhost", "server address")
+	viewCmd.Flags().String("port", "8080", "server port")
+	viewCmd.Flags().Bool("allow-plaintext", false, "serve even if the project's sources are encrypted")
+}
+
+func runView(cmd *cobra.Command, args []string) error {
+	viewCommand := NewViewCommand(ProjectRoot)
+
+	if addr, _ := cmd.Flags().GetString("address"); addr != "localhost" {
+		viewCommand.WithAddress(addr)
+	}
+	if port, _ := cmd.Flags().GetString("port"); port != "8080" {
+		viewCommand.WithPort(port)
+	}
+	if allow, _ := cmd.Flags().GetBool("allow-plaintext"); allow {
+		viewCommand.WithAllowPlaintext(true)
+	}
+
+	return viewCommand.Execute(cmd.Context())
+}
@@ -5,29 +5,37 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
 	"github.com/madstone-tech/loko/internal/adapters/cli"
-	"github.com/madstone-tech/loko/internal/adapters/d2"
 	"github.com/madstone-tech/loko/internal/adapters/filesystem"
 	"github.com/madstone-tech/loko/internal/adapters/html"
+	"github.com/madstone-tech/loko/internal/core/entities"
 	"github.com/madstone-tech/loko/internal/core/usecases"
 )
 
 // WatchCommand watches for file changes and rebuilds documentation.
 type WatchCommand struct {
-	projectRoot string
-	outputDir   string
-	debounceMs  int
+	projectRoot  string
+	outputDir    string
+	debounceMs   int
+	poll         bool
+	pollInterval time.Duration
+
+	allowPlaintext bool // Rebuild even when the project's sources are encrypted
+
+	onBuild func(err error) // Called after each build, with its error
 }
 
 // NewWatchCommand creates a new watch command.
 func NewWatchCommand(projectRoot string) *WatchCommand {
 	return &WatchCommand{
-		projectRoot: projectRoot,
-		outputDir:   "dist",
-		debounceMs:  500,
+		projectRoot:  projectRoot,
+		outputDir:    "dist",
+		debounceMs:   500,
+		pollInterval: filesystem.DefaultPollInterval,
 	}
 }
 
@@ -43,17 +51,66 @@ func (c *WatchCommand) WithDebounce(ms int) *WatchCommand {
 	return c
 }
 
+// WithPolling forces the polling watcher (for NFS, SMB and container bind mounts).
+func (c *WatchCommand) WithPolling(poll bool) *WatchCommand {
+	c.poll = poll
+	return c
+}
+
+// WithPollInterval sets the scan interval used by the polling watcher.
+func (c *WatchCommand) WithPollInterval(interval time.Duration) *WatchCommand {
+	c.pollInterval = interval
+	return c
+}
+
+// WithAllowPlaintext permits writing unencrypted output for a project whose
+// sources are encrypted at rest.
+func (c *WatchCommand) WithAllowPlaintext(allow bool) *WatchCommand {
+	c.allowPlaintext = allow
+	return c
+}
+
+// WithOnBuild calls onBuild after the initial build and each rebuild, with the
+// build's error.
+func (c *WatchCommand) WithOnBuild(onBuild func(err error)) *WatchCommand {
+	c.onBuild = onBuild
+	return c
+}
+
+// built reports a finished build to the onBuild callback, if any.
+func (c *WatchCommand) built(err error) {
+	if c.onBuild != nil {
+		c.onBuild(err)
+	}
+}
+
+// newWatcher selects the polling watcher when forced or when the project lives
+// on a file system where fsnotify is unreliable; otherwise it uses fsnotify.
+func (c *WatchCommand) newWatcher() (usecases.FileWatcher, error) {
+	if !c.poll {
+		fsType, needsPolling := filesystem.DetectPollingFS(c.projectRoot)
+		if !needsPolling {
+			return filesystem.NewFileWatcher()
+		}
+		fmt.Printf("   Detected %s file system, falling back to polling\n", fsType)
+	}
+	return filesystem.NewPollingWatcher(c.pollInterval), nil
+}
+
 // Execute runs the watch command.
 func (c *WatchCommand) Execute(ctx context.Context) error {
 	// Load the project
-	projectRepo := filesystem.NewProjectRepository()
+	projectRepo := newProjectRepository()
 	project, err := projectRepo.LoadProject(ctx, c.projectRoot)
 	if err != nil {
 		return fmt.Errorf("failed to load project: %w", err)
 	}
+	if err := ensurePlaintextAllowed(project, c.projectRoot, c.allowPlaintext); err != nil {
+		return err
+	}
 
 	// Create file watcher
-	watcher, err := filesystem.NewFileWatcher()
+	watcher, err := c.newWatcher()
 	if err != nil {
 		return fmt.Errorf("failed to create watcher: %w", err)
 	}
@@ -68,22 +125,34 @@ func (c *WatchCommand) Execute(ctx context.Context) error {
 	fmt.Println("👁  Watching for changes...")
 	fmt.Printf("   Project: %s\n", c.projectRoot)
 	fmt.Printf("   Output: %s\n", c.outputDir)
+	if pw, ok := watcher.(*filesystem.PollingWatcher); ok {
+		fmt.Printf("   Mode: polling every %v\n", pw.Interval())
+	}
 	fmt.Println("   Press Ctrl+C to stop")
 	fmt.Println()
 
 	// Create adapters
-	diagramRenderer := d2.NewRenderer()
 	siteBuilder, err := html.NewBuilder()
 	if err != nil {
 		return fmt.Errorf("failed to create site builder: %w", err)
 	}
+	cache := newBuildCache(c.projectRoot)
+	siteBuilder.WithSourceReader(sourceReader(ctx)).WithBuildCache(cache)
+	if err := applySiteCustomization(siteBuilder, c.projectRoot, project.Config); err != nil {
+		return err
+	}
 
 	progressReporter := cli.NewProgressReporter()
-	buildDocs := usecases.NewBuildDocs(diagramRenderer, siteBuilder, progressReporter)
+	buildDocs, closeRenderer, err := newWatchBuild(ctx, project, siteBuilder, cache, progressReporter)
+	if err != nil {
+		return err
+	}
+	defer func() { closeRenderer() }()
 
-	// Track debounce timer
+	// Track debounce timer and coalesce bursts of events into one change set
 	debounceTimer := time.NewTimer(time.Duration(c.debounceMs) * time.Millisecond)
 	debounceTimer.Stop()
+	changes := usecases.NewChangeSet(sourceDirs(c.projectRoot, project)...)
 
 	// Handle signals
 	sigChan := make(chan os.Signal, 1)
@@ -91,14 +160,19 @@ func (c *WatchCommand) Execute(ctx context.Context) error {
 
 	// Initial build
 	systems, err := projectRepo.ListSystems(ctx, c.projectRoot)
+	if err == nil {
+		c.updateIDIndex(ctx, projectRepo, project, systems)
+	}
 	if err == nil && len(systems) > 0 {
+		c.reloadRelationships(ctx, siteBuilder, systems)
 		fmt.Println("🔨 Initial build...")
-		if err := buildDocs.Execute(ctx, project, systems, c.outputDir); err != nil {
+		if err = buildDocs.Execute(ctx, project, systems, c.outputDir); err != nil {
 			fmt.Printf("✗ Build failed: %v\n", err)
 		} else {
 			fmt.Println("✓ Initial build complete")
 		}
 	}
+	c.built(err)
 
 	for {
 		select {
@@ -112,32 +186,72 @@ func (c *WatchCommand) Execute(ctx context.Context) error {
 				return nil
 			}
 
-			// Reset debounce timer
+			// Record the change and reset the debounce timer
+			changes.Add(event)
 			debounceTimer.Reset(time.Duration(c.debounceMs) * time.Millisecond)
-			fmt.Printf("📝 Change detected: %s\n", event.Path)
 
 		case <-debounceTimer.C:
-			// Debounce time elapsed, rebuild
-			fmt.Println("🔨 Rebuilding...")
+			// Debounce time elapsed, rebuild once for the whole burst
+			if changes.IsEmpty() {
+				continue
+			}
+			scope := changes.Scope()
+			dirtySystems := changes.DirtySystems()
+			fmt.Printf("📝 %s\n", changes.Summary())
+			changes.Reset()
+
+			// Configuration and theme changes require reloading the project
+			if scope == usecases.RebuildFull {
+				reloaded, err := projectRepo.LoadProject(ctx, c.projectRoot)
+				if err != nil {
+					fmt.Printf("✗ Error loading project: %v\n", err)
+					continue
+				}
+				project = reloaded
+				changes = usecases.NewChangeSet(sourceDirs(c.projectRoot, project)...)
+				if err := applySiteCustomization(siteBuilder, c.projectRoot, project.Config); err != nil {
+					fmt.Printf("✗ Error applying site customization: %v\n", err)
+					continue
+				}
+				// The renderer, annotations and diagram cache version follow [d2]
+				rebuilt, closeRebuilt, err := newWatchBuild(ctx, project, siteBuilder, cache, progressReporter)
+				if err != nil {
+					fmt.Printf("✗ Error creating diagram renderer: %v\n", err)
+					continue
+				}
+				closeRenderer()
+				buildDocs, closeRenderer = rebuilt, closeRebuilt
+			}
 
 			systems, err := projectRepo.ListSystems(ctx, c.projectRoot)
 			if err != nil {
 				fmt.Printf("✗ Error loading systems: %v\n", err)
 				continue
 			}
+			c.updateIDIndex(ctx, projectRepo, project, systems)
 
 			if len(systems) == 0 {
 				fmt.Println("⚠  No systems found")
 				continue
 			}
+			c.reloadRelationships(ctx, siteBuilder, systems)
 
 			startTime := time.Now()
-			if err := buildDocs.Execute(ctx, project, systems, c.outputDir); err != nil {
+			switch {
+			case scope == usecases.RebuildPages:
+				err = buildDocs.RebuildPages(ctx, project, systems, c.outputDir)
+			case scope == usecases.RebuildContent && len(dirtySystems) > 0:
+				err = buildDocs.ExecuteIncremental(ctx, project, systems, c.outputDir, dirtySystems)
+			default:
+				err = buildDocs.Execute(ctx, project, systems, c.outputDir)
+			}
+			if err != nil {
 				fmt.Printf("✗ Build failed: %v\n", err)
 			} else {
 				elapsed := time.Since(startTime)
 				fmt.Printf("✓ Rebuild complete (%v)\n", elapsed.Round(10*time.Millisecond))
 			}
+			c.built(err)
 			fmt.Println()
 
 		case <-ctx.Done():
@@ -145,3 +259,58 @@ func (c *WatchCommand) Execute(ctx context.Context) error {
 		}
 	}
 }
+
+// newWatchBuild creates the build use case for the configuration of project:
+// its diagram renderer, diagram annotations and diagram cache version. The
+// returned function releases the renderer.
+func newWatchBuild(ctx context.Context, project *entities.Project, siteBuilder *html.Builder, cache *filesystem.BuildCache, progressReporter *cli.ProgressReporter) (*usecases.BuildDocs, func(), error) {
+	diagramRenderer, closeRenderer, err := newDiagramRenderer(ctx, project.Config)
+	if err != nil {
+		return nil, nil, err
+	}
+	buildDocs := usecases.NewBuildDocs(diagramRenderer, siteBuilder, progressReporter).
+		WithDiagramAnnotations(diagramAnnotations(project)).
+		WithBuildCache(cache, diagramCacheVersion(ctx, project.Config, diagramRenderer))
+	return buildDocs, closeRenderer, nil
+}
+
+// sourceDirs returns the project's source directory followed by the [paths]
+// source_dirs, all relative to its root as watcher event paths are.
+func sourceDirs(projectRoot string, project *entities.Project) []string {
+	dirs := project.SourceDirs()
+	for i, dir := range dirs {
+		if filepath.IsAbs(dir) {
+			if rel, err := filepath.Rel(projectRoot, dir); err == nil {
+				dirs[i] = rel
+			}
+		}
+	}
+	return dirs
+}
+
+// updateIDIndex rewrites .loko/ids.json with the current people and systems,
+// so completions follow edits while watching. People are reloaded since a
+// person.md change does not reload the project.
+func (c *WatchCommand) updateIDIndex(ctx context.Context, projectRepo *filesystem.ProjectRepository, project *entities.Project, systems []*entities.System) {
+	people, err := projectRepo.ListPeople(ctx, c.projectRoot)
+	if err != nil {
+		fmt.Printf("⚠  %v\n", err)
+		return
+	}
+	project.People = nil
+	for _, person := range people {
+		_ = project.AddPerson(person)
+	}
+	writeIDIndex(ctx, c.projectRoot, project, systems)
+}
+
+// reloadRelationships lists the current relationships.toml entries on the
+// system pages. A failure is reported and the previous entries are kept.
+func (c *WatchCommand) reloadRelationships(ctx context.Context, siteBuilder *html.Builder, systems []*entities.System) {
+	relationships, err := loadRelationships(ctx, c.projectRoot, systems, nil)
+	if err != nil {
+		fmt.Printf("⚠  %v\n", err)
+		return
+	}
+	siteBuilder.WithRelationships(relationships)
+}
@@ -1,16 +1,28 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
 
 var watchCmd = &cobra.Command{
 	Use:     "watch",
 	Aliases: []string{"w"},
 	Short:   "Watch for changes and rebuild",
-	Long:    "Watch the project for file changes and automatically rebuild documentation.",
+	Long: `Watch the project for file changes and automatically rebuild documentation.
+
+Changes to .md and .d2 sources rebuild diagrams and pages. Changes to
+.loko/templates/ re-render pages only. Changes to loko.toml or .loko/themes/
+reload the project configuration and trigger a full rebuild.
+
+On network file systems and container bind mounts, where file system events
+are unreliable, loko falls back to polling automatically. Use --poll to force it.`,
 	GroupID: "building",
 	Example: `  loko watch
   loko watch --debounce 1000
-  loko watch --output ./docs`,
+  loko watch --output ./docs
+  loko watch --poll --poll-interval 2s`,
 	RunE: runWatch,
 }
 
@@ -18,6 +30,9 @@ func init() {
 	rootCmd.AddCommand(watchCmd)
 	watchCmd.Flags().StringP("output", "o", "dist", "output directory")
 	watchCmd.Flags().Int("debounce", 500, "debounce delay in milliseconds")
+	watchCmd.Flags().Bool("poll", false, "poll for changes instead of using file system events")
+	watchCmd.Flags().Duration("poll-interval", time.Second, "scan interval when polling")
+	watchCmd.Flags().Bool("allow-plaintext", false, "rebuild even if the project's sources are encrypted")
 }
 
 func runWatch(cmd *cobra.Command, args []string) error {
@@ -30,5 +45,15 @@ func runWatch(cmd *cobra.Command, args []string) error {
 		watchCommand.WithDebounce(debounce)
 	}
 
+	if poll, _ := cmd.Flags().GetBool("poll"); poll {
+		watchCommand.WithPolling(true)
+	}
+	if interval, _ := cmd.Flags().GetDuration("poll-interval"); interval > 0 {
+		watchCommand.WithPollInterval(interval)
+	}
+	if allow, _ := cmd.Flags().GetBool("allow-plaintext"); allow {
+		watchCommand.WithAllowPlaintext(true)
+	}
+
 	return watchCommand.Execute(cmd.Context())
 }
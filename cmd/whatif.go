@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// WhatIfCommand reports what a hypothetical change to the architecture
+// would break, without modifying any files.
+type WhatIfCommand struct {
+	projectRoot string
+	format      string
+	out         io.Writer
+}
+
+// NewWhatIfCommand creates a new what-if command.
+func NewWhatIfCommand(projectRoot string) *WhatIfCommand {
+	return &WhatIfCommand{
+		projectRoot: projectRoot,
+		format:      "text",
+		out:         os.Stdout,
+	}
+}
+
+// WithFormat sets the output format: text or json.
+func (c *WhatIfCommand) WithFormat(format string) *WhatIfCommand {
+	if format != "" {
+		c.format = strings.ToLower(format)
+	}
+	return c
+}
+
+// ExecuteRemove reports what removing the element id would break.
+func (c *WhatIfCommand) ExecuteRemove(ctx context.Context, id string) error {
+	whatIf, err := c.load(ctx)
+	if err != nil {
+		return err
+	}
+	report, err := whatIf.Remove(id)
+	if err != nil {
+		return withExitCode(ExitConfig, err)
+	}
+	return c.print(report)
+}
+
+// ExecuteAddEdge reports what a new relationship from source to target
+// would change.
+func (c *WhatIfCommand) ExecuteAddEdge(ctx context.Context, source, target, description string) error {
+	whatIf, err := c.load(ctx)
+	if err != nil {
+		return err
+	}
+	report, err := whatIf.AddEdge(source, target, description)
+	if err != nil {
+		return withExitCode(ExitConfig, err)
+	}
+	return c.print(report)
+}
+
+// load builds the architecture graph of the project.
+func (c *WhatIfCommand) load(ctx context.Context) (*usecases.WhatIf, error) {
+	if c.format != "text" && c.format != "json" {
+		return nil, withExitCode(ExitConfig, fmt.Errorf("unsupported format %q (supported: text, json)", c.format))
+	}
+
+	projectRepo := newProjectRepository()
+	project, err := projectRepo.LoadProject(ctx, c.projectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load project: %w", err)
+	}
+	systems, err := projectRepo.ListSystems(ctx, c.projectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list systems: %w", err)
+	}
+	graph, err := usecases.NewBuildArchitectureGraphWithRelRepo(filesystem.NewFilesystemRelationshipRepository()).
+		Execute(ctx, project, systems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build architecture graph: %w", err)
+	}
+	return usecases.NewWhatIf(graph), nil
+}
+
+// print writes report in the output format.
+func (c *WhatIfCommand) print(report *usecases.WhatIfReport) error {
+	if c.format == "json" {
+		encoder := json.NewEncoder(c.out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+		return nil
+	}
+
+	fmt.Fprintf(c.out, "What if: %s (no files changed)\n", report.Change)
+	if len(report.Removed) > 1 {
+		fmt.Fprintf(c.out, "\nRemoved (%d):\n", len(report.Removed))
+		for _, id := range report.Removed {
+			fmt.Fprintf(c.out, "  %s\n", id)
+		}
+	}
+
+	fmt.Fprintf(c.out, "\nBroken relationships (%d):\n", len(report.BrokenRelationships))
+	for _, edge := range report.BrokenRelationships {
+		line := fmt.Sprintf("  %s -> %s", edge.Source, edge.Target)
+		if edge.Description != "" {
+			line += ": " + edge.Description
+		}
+		fmt.Fprintln(c.out, line)
+	}
+	fmt.Fprintf(c.out, "\nBroken paths (%d):\n", len(report.BrokenPaths))
+	for _, path := range report.BrokenPaths {
+		fmt.Fprintf(c.out, "  %s -/-> %s\n", path.Source, path.Target)
+	}
+	fmt.Fprintf(c.out, "\nNew cycles (%d):\n", len(report.NewCycles))
+	for _, cycle := range report.NewCycles {
+		fmt.Fprintf(c.out, "  %s\n", strings.Join(cycle, " -> "))
+	}
+
+	fmt.Fprintf(c.out, "\nImpacted elements (%d):\n", len(report.Impacted))
+	for _, ref := range report.Impacted {
+		line := fmt.Sprintf("  %s (%s", ref.ID, ref.Type)
+		if ref.Depth > 0 {
+			line += fmt.Sprintf(", %d hop(s) away", ref.Depth)
+		}
+		if ref.Owner != "" {
+			line += ", owner " + ref.Owner
+		}
+		fmt.Fprintln(c.out, line+")")
+	}
+	owners := "none"
+	if len(report.Owners) > 0 {
+		owners = strings.Join(report.Owners, ", ")
+	}
+	fmt.Fprintf(c.out, "\nImpacted owners: %s\n", owners)
+	return nil
+}
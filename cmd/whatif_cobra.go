@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var whatifCmd = &cobra.Command{
+	Use:   "whatif",
+	Short: "Report what a hypothetical architecture change would break",
+	Long: `Apply a hypothetical change to an in-memory copy of the architecture
+graph and report what it would break: relationships and paths that no longer
+resolve, dependency cycles it creates and the elements and owners it impacts.
+No files are modified.`,
+	GroupID: "building",
+}
+
+var whatifRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Report what removing an element would break",
+	Long: `Report what removing a system, container or component, and its children
+with it, would break: the relationships of other elements to it, the elements
+that would no longer reach each other, and the elements and owners depending
+on it.
+
+The element is given by qualified ID, such as shop/api/cart, or by
+unambiguous short ID.`,
+	Example: `  loko whatif remove shop/api
+  loko whatif remove cart --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		return NewWhatIfCommand(ProjectRoot).
+			WithFormat(format).
+			ExecuteRemove(cmd.Context(), args[0])
+	},
+}
+
+var whatifAddEdgeCmd = &cobra.Command{
+	Use:   "add-edge <source> <target>",
+	Short: "Report what a new relationship would change",
+	Long: `Report what a new relationship from source to target would change: the
+dependency cycle it would close, if any, and the elements that would gain
+dependencies, source and the elements depending on it, with their owners and
+the owner of target.`,
+	Example: `  loko whatif add-edge shop/web billing/api
+  loko whatif add-edge ledger cart --description "notifies"`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		description, _ := cmd.Flags().GetString("description")
+		return NewWhatIfCommand(ProjectRoot).
+			WithFormat(format).
+			ExecuteAddEdge(cmd.Context(), args[0], args[1], description)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(whatifCmd)
+	whatifCmd.AddCommand(whatifRemoveCmd, whatifAddEdgeCmd)
+
+	for _, cmd := range []*cobra.Command{whatifRemoveCmd, whatifAddEdgeCmd} {
+		cmd.Flags().StringP("format", "f", "text", "output format (text, json)")
+		_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp))
+	}
+	whatifAddEdgeCmd.Flags().String("description", "", "description of the new relationship")
+}
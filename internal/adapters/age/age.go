@@ -0,0 +1,105 @@
+// Package age provides a FileEncrypter adapter that shells out to the age CLI
+// (https://age-encryption.org). Recipients may be age or SSH public keys, so
+// teams can encrypt architecture sources to the SSH keys they already use.
+package age
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// ErrAgeNotAvailable indicates the age binary is not installed.
+var ErrAgeNotAvailable = fmt.Errorf("age is not installed or not in PATH")
+
+// EnvIdentity names the environment variable holding the path of the age or
+// SSH private key used to decrypt sources.
+const EnvIdentity = "LOKO_AGE_IDENTITY"
+
+// Ensure Encrypter implements usecases.FileEncrypter interface.
+var _ usecases.FileEncrypter = (*Encrypter)(nil)
+
+// Encrypter implements the FileEncrypter interface by shelling out to age.
+type Encrypter struct {
+	agePath        string // Path to age binary
+	recipientsFile string // File listing age or SSH public keys, one per line
+	identityFile   string // age or SSH private key
+}
+
+// NewEncrypter creates an age encrypter. recipientsFile is only needed to
+// encrypt and identityFile only to decrypt; either may be empty.
+func NewEncrypter(recipientsFile, identityFile string) *Encrypter {
+	agePath, _ := exec.LookPath("age")
+	return &Encrypter{
+		agePath:        agePath,
+		recipientsFile: recipientsFile,
+		identityFile:   identityFile,
+	}
+}
+
+// IsAvailable checks if the age binary is installed and accessible.
+func (e *Encrypter) IsAvailable() bool {
+	return e.agePath != ""
+}
+
+// CanDecrypt reports whether an identity is configured for decryption.
+func (e *Encrypter) CanDecrypt() bool {
+	return e.identityFile != ""
+}
+
+// Encrypt encrypts plaintext to every key in the recipients file.
+func (e *Encrypter) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	if e.recipientsFile == "" {
+		return nil, fmt.Errorf("no age recipients file configured")
+	}
+	return e.run(ctx, plaintext, "--encrypt", "--recipients-file", e.recipientsFile)
+}
+
+// Decrypt decrypts ciphertext with the configured identity.
+func (e *Encrypter) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	if e.identityFile == "" {
+		return nil, fmt.Errorf("no age identity configured; set %s", EnvIdentity)
+	}
+	return e.run(ctx, ciphertext, "--decrypt", "--identity", e.identityFile)
+}
+
+// run pipes input through age with the given arguments.
+func (e *Encrypter) run(ctx context.Context, input []byte, args ...string) ([]byte, error) {
+	if !e.IsAvailable() {
+		return nil, ErrAgeNotAvailable
+	}
+
+	cmd := exec.CommandContext(ctx, e.agePath, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("age %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// DefaultIdentity returns the private key used to decrypt sources: the path in
+// LOKO_AGE_IDENTITY, else ~/.ssh/id_ed25519 when it exists. It returns an
+// empty string when no key is present.
+func DefaultIdentity(getenv func(string) string) string {
+	if path := getenv(EnvIdentity); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	path := filepath.Join(home, ".ssh", "id_ed25519")
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
@@ -0,0 +1,112 @@
+package age
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeAge writes a stand-in age binary that records its arguments and wraps
+// or unwraps stdin with a marker, so the adapter can be tested without age.
+func fakeAge(t *testing.T) (agePath, argsFile string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake age script requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	argsFile = filepath.Join(dir, "args")
+	agePath = filepath.Join(dir, "age")
+	script := `#!/bin/sh
+echo "$@" > "` + argsFile + `"
+case "$1" in
+--encrypt) printf 'AGE:'; cat ;;
+--decrypt) input=$(cat); case "$input" in AGE:*) printf '%s' "${input#AGE:}" ;; *) echo "no identity matched" >&2; exit 1 ;; esac ;;
+esac
+`
+	if err := os.WriteFile(agePath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return agePath, argsFile
+}
+
+func TestEncrypterRoundTrip(t *testing.T) {
+	agePath, argsFile := fakeAge(t)
+	e := &Encrypter{agePath: agePath, recipientsFile: "recipients.txt", identityFile: "id_ed25519"}
+	ctx := context.Background()
+
+	ciphertext, err := e.Encrypt(ctx, []byte("secret topology"))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if string(ciphertext) != "AGE:secret topology" {
+		t.Errorf("ciphertext = %q", ciphertext)
+	}
+	if args, _ := os.ReadFile(argsFile); strings.TrimSpace(string(args)) != "--encrypt --recipients-file recipients.txt" {
+		t.Errorf("encrypt args = %q", args)
+	}
+
+	plaintext, err := e.Decrypt(ctx, ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if string(plaintext) != "secret topology" {
+		t.Errorf("plaintext = %q", plaintext)
+	}
+	if args, _ := os.ReadFile(argsFile); strings.TrimSpace(string(args)) != "--decrypt --identity id_ed25519" {
+		t.Errorf("decrypt args = %q", args)
+	}
+
+	if _, err := e.Decrypt(ctx, []byte("garbage")); err == nil || !strings.Contains(err.Error(), "no identity matched") {
+		t.Errorf("expected age's error message, got %v", err)
+	}
+}
+
+func TestEncrypterMissingKeys(t *testing.T) {
+	agePath, _ := fakeAge(t)
+	e := &Encrypter{agePath: agePath}
+	if _, err := e.Encrypt(context.Background(), nil); err == nil {
+		t.Error("expected error without recipients")
+	}
+	if _, err := e.Decrypt(context.Background(), nil); err == nil {
+		t.Error("expected error without identity")
+	}
+	if e.CanDecrypt() {
+		t.Error("CanDecrypt should be false without identity")
+	}
+}
+
+func TestEncrypterNotAvailable(t *testing.T) {
+	e := &Encrypter{recipientsFile: "recipients.txt"}
+	if _, err := e.Encrypt(context.Background(), nil); err != ErrAgeNotAvailable {
+		t.Errorf("expected ErrAgeNotAvailable, got %v", err)
+	}
+}
+
+func TestDefaultIdentity(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	env := func(values map[string]string) func(string) string {
+		return func(key string) string { return values[key] }
+	}
+
+	if got := DefaultIdentity(env(map[string]string{EnvIdentity: "/keys/loko.txt"})); got != "/keys/loko.txt" {
+		t.Errorf("DefaultIdentity = %q, want env value", got)
+	}
+	if got := DefaultIdentity(env(nil)); got != "" {
+		t.Errorf("DefaultIdentity = %q, want empty without a key", got)
+	}
+
+	key := filepath.Join(home, ".ssh", "id_ed25519")
+	if err := os.MkdirAll(filepath.Dir(key), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(key, []byte("key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := DefaultIdentity(env(nil)); got != key {
+		t.Errorf("DefaultIdentity = %q, want %q", got, key)
+	}
+}
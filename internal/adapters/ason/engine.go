@@ -4,16 +4,22 @@ package ason
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
 )
 
 // TemplateEngine implements the TemplateEngine port using simple variable substitution.
 // It supports template discovery from multiple search paths.
 type TemplateEngine struct {
 	searchPaths []string
+	icons       usecases.IconResolver
 }
 
 // NewTemplateEngine creates a new template engine.
@@ -31,8 +37,15 @@ func (te *TemplateEngine) AddSearchPath(path string) {
 	}
 }
 
+// SetIconResolver resolves the {{icon:pack/name}} variables of templates to
+// icon data URIs with icons.
+func (te *TemplateEngine) SetIconResolver(icons usecases.IconResolver) {
+	te.icons = icons
+}
+
 // RenderTemplate loads a template by name and applies variable substitution.
-// Variables are substituted using {{VariableName}} syntax.
+// Variables are substituted using {{VariableName}} syntax, and icons
+// referenced as {{icon:pack/name}} are embedded as data URIs.
 // Returns the rendered content or error if template not found.
 func (te *TemplateEngine) RenderTemplate(ctx context.Context, templateName string, variables map[string]string) (string, error) {
 	if templateName == "" {
@@ -54,7 +67,7 @@ func (te *TemplateEngine) RenderTemplate(ctx context.Context, templateName strin
 	// Apply variable substitution
 	rendered := te.substitute(string(content), variables)
 
-	return rendered, nil
+	return te.substituteIcons(rendered)
 }
 
 // ListTemplates returns available template names from discovery paths.
@@ -111,3 +124,30 @@ func (te *TemplateEngine) substitute(content string, variables map[string]string
 
 	return result
 }
+
+// iconVarPattern matches an icon variable such as {{icon:essentials/person}}.
+var iconVarPattern = regexp.MustCompile(`\{\{\s*` + regexp.QuoteMeta(entities.IconRefPrefix) + `\s*([^{}\s]+)\s*\}\}`)
+
+// substituteIcons replaces {{icon:ref}} with the data URI of the icon. An
+// icon that cannot be resolved is an error, so a template never renders a
+// broken icon.
+func (te *TemplateEngine) substituteIcons(content string) (string, error) {
+	var errs []error
+	rendered := iconVarPattern.ReplaceAllStringFunc(content, func(v string) string {
+		ref := iconVarPattern.FindStringSubmatch(v)[1]
+		if te.icons == nil {
+			errs = append(errs, fmt.Errorf("icon %q: no icon packs configured", ref))
+			return v
+		}
+		uri, err := te.icons.ResolveIcon(ref)
+		if err != nil {
+			errs = append(errs, err)
+			return v
+		}
+		return uri
+	})
+	if err := errors.Join(errs...); err != nil {
+		return "", err
+	}
+	return rendered, nil
+}
@@ -2,8 +2,10 @@ package ason
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -192,3 +194,45 @@ func contains(s, substr string) bool {
 	}
 	return false
 }
+
+// fakeIcons resolves the icons of its map.
+type fakeIcons map[string]string
+
+func (f fakeIcons) ResolveIcon(ref string) (string, error) {
+	if uri, ok := f[ref]; ok {
+		return uri, nil
+	}
+	return "", errors.New("no icon " + ref)
+}
+
+// TestRenderTemplateIcons tests that icon variables are resolved.
+func TestRenderTemplateIcons(t *testing.T) {
+	templateDir := t.TempDir()
+	content := "{{Name}}: {\n  icon: \"{{icon:essentials/person}}\"\n}\nDB: { icon: \"{{ icon:database }}\" }\n"
+	if err := os.WriteFile(filepath.Join(templateDir, "system.d2"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(templateDir, "broken.d2"), []byte(`a: { icon: "{{icon:aws/lambda}}" }`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := NewTemplateEngine()
+	engine.AddSearchPath(templateDir)
+	if _, err := engine.RenderTemplate(context.Background(), "system.d2", nil); err == nil {
+		t.Error("RenderTemplate() without icon packs succeeded")
+	}
+
+	engine.SetIconResolver(fakeIcons{"essentials/person": "data:person", "database": "data:db"})
+	got, err := engine.RenderTemplate(context.Background(), "system.d2", map[string]string{"Name": "User"})
+	if err != nil {
+		t.Fatalf("RenderTemplate() error = %v", err)
+	}
+	want := "User: {\n  icon: \"data:person\"\n}\nDB: { icon: \"data:db\" }\n"
+	if got != want {
+		t.Errorf("RenderTemplate() = %q, want %q", got, want)
+	}
+
+	if _, err := engine.RenderTemplate(context.Background(), "broken.d2", nil); err == nil || !strings.Contains(err.Error(), "no icon aws/lambda") {
+		t.Errorf("RenderTemplate(broken.d2) error = %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+// Package ci provides adapters for posting loko reports to code hosting
+// providers from CI pipelines. GitHub and GitLab are supported through their
+// REST APIs; the pull request to annotate is detected from the CI environment.
+package ci
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// Supported providers.
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// Default API endpoints used when the CI environment does not provide one.
+const (
+	defaultGitHubAPI = "https://api.github.com"
+	defaultGitLabAPI = "https://gitlab.com/api/v4"
+)
+
+// pageSize is the number of comments requested per page when searching for
+// an existing report.
+const pageSize = 100
+
+// Target identifies the pull or merge request to annotate.
+type Target struct {
+	Provider string // ProviderGitHub or ProviderGitLab
+	APIURL   string // REST API base URL
+	Repo     string // GitHub "owner/name" or GitLab project ID or path
+	Number   int    // Pull request number or merge request IID
+	Token    string // API token with permission to comment
+}
+
+// DetectTarget fills in a Target from the CI environment. Fields already set
+// on t take precedence. An empty Provider is detected from GITHUB_ACTIONS or
+// GITLAB_CI.
+//
+// GitHub reads GITHUB_REPOSITORY, GITHUB_API_URL, GITHUB_TOKEN and the pull
+// request number from GITHUB_EVENT_PATH or GITHUB_REF. GitLab reads
+// CI_PROJECT_ID, CI_API_V4_URL, CI_MERGE_REQUEST_IID and GITLAB_TOKEN.
+func DetectTarget(t Target, getenv func(string) string) (Target, error) {
+	if t.Provider == "" {
+		switch {
+		case getenv("GITHUB_ACTIONS") == "true":
+			t.Provider = ProviderGitHub
+		case getenv("GITLAB_CI") == "true":
+			t.Provider = ProviderGitLab
+		default:
+			return t, fmt.Errorf("could not detect the CI provider; set --provider to %s or %s", ProviderGitHub, ProviderGitLab)
+		}
+	}
+
+	switch t.Provider {
+	case ProviderGitHub:
+		t.APIURL = firstNonEmpty(t.APIURL, getenv("GITHUB_API_URL"), defaultGitHubAPI)
+		t.Repo = firstNonEmpty(t.Repo, getenv("GITHUB_REPOSITORY"))
+		t.Token = firstNonEmpty(t.Token, getenv("GITHUB_TOKEN"))
+		if t.Number == 0 {
+			t.Number = gitHubPullRequestNumber(getenv)
+		}
+	case ProviderGitLab:
+		t.APIURL = firstNonEmpty(t.APIURL, getenv("CI_API_V4_URL"), defaultGitLabAPI)
+		t.Repo = firstNonEmpty(t.Repo, getenv("CI_PROJECT_ID"))
+		t.Token = firstNonEmpty(t.Token, getenv("GITLAB_TOKEN"))
+		if t.Number == 0 {
+			t.Number, _ = strconv.Atoi(getenv("CI_MERGE_REQUEST_IID"))
+		}
+	default:
+		return t, fmt.Errorf("unsupported provider %q (supported: %s, %s)", t.Provider, ProviderGitHub, ProviderGitLab)
+	}
+
+	switch {
+	case t.Repo == "":
+		return t, fmt.Errorf("%s repository is not set; use --repo", t.Provider)
+	case t.Number <= 0:
+		return t, fmt.Errorf("%s pull request number is not set; use --pr", t.Provider)
+	case t.Token == "":
+		return t, fmt.Errorf("%s API token is not set", t.Provider)
+	}
+	return t, nil
+}
+
+// NewCommenter returns the PullRequestCommenter for t.Provider.
+func NewCommenter(t Target) (usecases.PullRequestCommenter, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	switch t.Provider {
+	case ProviderGitHub:
+		return &GitHubCommenter{client: client, target: t}, nil
+	case ProviderGitLab:
+		return &GitLabCommenter{client: client, target: t}, nil
+	default:
+		return nil, fmt.Errorf("unsupported provider %q", t.Provider)
+	}
+}
+
+// gitHubPullRequestNumber reads the pull request number from the webhook
+// event payload, falling back to a "refs/pull/<n>/merge" GITHUB_REF.
+func gitHubPullRequestNumber(getenv func(string) string) int {
+	if path := getenv("GITHUB_EVENT_PATH"); path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			var event struct {
+				Number      int `json:"number"`
+				PullRequest struct {
+					Number int `json:"number"`
+				} `json:"pull_request"`
+			}
+			if json.Unmarshal(data, &event) == nil {
+				if event.PullRequest.Number > 0 {
+					return event.PullRequest.Number
+				}
+				if event.Number > 0 {
+					return event.Number
+				}
+			}
+		}
+	}
+
+	ref := getenv("GITHUB_REF")
+	if rest, ok := strings.CutPrefix(ref, "refs/pull/"); ok {
+		if n, _, ok := strings.Cut(rest, "/"); ok {
+			number, _ := strconv.Atoi(n)
+			return number
+		}
+	}
+	return 0
+}
+
+// firstNonEmpty returns the first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// doJSON sends a request with an optional JSON body and decodes a JSON
+// response into out when out is non-nil.
+func doJSON(ctx context.Context, client *http.Client, method, url string, header http.Header, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header = header.Clone()
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", method, url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s returned %s: %s", method, url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", url, err)
+	}
+	return nil
+}
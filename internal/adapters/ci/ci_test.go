@@ -0,0 +1,200 @@
+package ci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const marker = "<!-- report -->"
+
+func envFunc(env map[string]string) func(string) string {
+	return func(key string) string { return env[key] }
+}
+
+func TestDetectTargetGitHub(t *testing.T) {
+	eventPath := filepath.Join(t.TempDir(), "event.json")
+	if err := os.WriteFile(eventPath, []byte(`{"pull_request":{"number":42}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := DetectTarget(Target{}, envFunc(map[string]string{
+		"GITHUB_ACTIONS":    "true",
+		"GITHUB_REPOSITORY": "acme/arch",
+		"GITHUB_TOKEN":      "secret",
+		"GITHUB_EVENT_PATH": eventPath,
+	}))
+	if err != nil {
+		t.Fatalf("DetectTarget failed: %v", err)
+	}
+	want := Target{Provider: ProviderGitHub, APIURL: defaultGitHubAPI, Repo: "acme/arch", Number: 42, Token: "secret"}
+	if got != want {
+		t.Errorf("DetectTarget = %+v, want %+v", got, want)
+	}
+}
+
+func TestDetectTargetGitHubRefFallback(t *testing.T) {
+	got, err := DetectTarget(Target{Repo: "acme/other"}, envFunc(map[string]string{
+		"GITHUB_ACTIONS":    "true",
+		"GITHUB_REPOSITORY": "acme/arch",
+		"GITHUB_TOKEN":      "secret",
+		"GITHUB_REF":        "refs/pull/7/merge",
+	}))
+	if err != nil {
+		t.Fatalf("DetectTarget failed: %v", err)
+	}
+	if got.Number != 7 || got.Repo != "acme/other" {
+		t.Errorf("unexpected target: %+v", got)
+	}
+}
+
+func TestDetectTargetGitLab(t *testing.T) {
+	got, err := DetectTarget(Target{}, envFunc(map[string]string{
+		"GITLAB_CI":            "true",
+		"CI_API_V4_URL":        "https://git.example.com/api/v4",
+		"CI_PROJECT_ID":        "17",
+		"CI_MERGE_REQUEST_IID": "3",
+		"GITLAB_TOKEN":         "secret",
+	}))
+	if err != nil {
+		t.Fatalf("DetectTarget failed: %v", err)
+	}
+	want := Target{Provider: ProviderGitLab, APIURL: "https://git.example.com/api/v4", Repo: "17", Number: 3, Token: "secret"}
+	if got != want {
+		t.Errorf("DetectTarget = %+v, want %+v", got, want)
+	}
+}
+
+func TestDetectTargetErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		target Target
+		env    map[string]string
+	}{
+		{"unknown environment", Target{}, nil},
+		{"unsupported provider", Target{Provider: "bitbucket"}, nil},
+		{"missing number", Target{Provider: ProviderGitHub}, map[string]string{"GITHUB_REPOSITORY": "a/b", "GITHUB_TOKEN": "x"}},
+		{"missing token", Target{Provider: ProviderGitLab, Number: 1}, map[string]string{"CI_PROJECT_ID": "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DetectTarget(tt.target, envFunc(tt.env)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+// fakeProvider serves an in-memory comment list for one pull request.
+type fakeProvider struct {
+	mu       sync.Mutex
+	comments []map[string]any
+	requests []string
+}
+
+func (f *fakeProvider) handler(t *testing.T, listPath, itemPrefix, authHeader, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+
+		if r.Header.Get(authHeader) != token {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var payload map[string]string
+		if r.Body != nil {
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+		}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == listPath:
+			_ = json.NewEncoder(w).Encode(f.comments)
+		case r.Method == http.MethodPost && r.URL.Path == listPath:
+			comment := map[string]any{"id": len(f.comments) + 1, "body": payload["body"], "html_url": "https://example.com/c/new"}
+			f.comments = append(f.comments, comment)
+			_ = json.NewEncoder(w).Encode(comment)
+		case strings.HasPrefix(r.URL.Path, itemPrefix):
+			for _, c := range f.comments {
+				if fmt.Sprint(c["id"]) == strings.TrimPrefix(r.URL.Path, itemPrefix) {
+					c["body"] = payload["body"]
+					_ = json.NewEncoder(w).Encode(c)
+					return
+				}
+			}
+			http.NotFound(w, r)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func TestGitHubCommenterUpsert(t *testing.T) {
+	fake := &fakeProvider{comments: []map[string]any{{"id": 1, "body": "LGTM", "html_url": "https://example.com/c/1"}}}
+	srv := httptest.NewServer(fake.handler(t, "/repos/acme/arch/issues/5/comments", "/repos/acme/arch/issues/comments/", "Authorization", "Bearer secret"))
+	defer srv.Close()
+
+	commenter, err := NewCommenter(Target{Provider: ProviderGitHub, APIURL: srv.URL, Repo: "acme/arch", Number: 5, Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	url, err := commenter.UpsertComment(context.Background(), marker, marker+"\nfirst")
+	if err != nil {
+		t.Fatalf("first UpsertComment failed: %v", err)
+	}
+	if url != "https://example.com/c/new" {
+		t.Errorf("url = %q", url)
+	}
+
+	if _, err := commenter.UpsertComment(context.Background(), marker, marker+"\nsecond"); err != nil {
+		t.Fatalf("second UpsertComment failed: %v", err)
+	}
+	if len(fake.comments) != 2 || fake.comments[1]["body"] != marker+"\nsecond" {
+		t.Errorf("expected the report comment to be updated in place, got %v", fake.comments)
+	}
+	if last := fake.requests[len(fake.requests)-1]; last != "PATCH /repos/acme/arch/issues/comments/2" {
+		t.Errorf("last request = %q", last)
+	}
+}
+
+func TestGitLabCommenterUpsert(t *testing.T) {
+	fake := &fakeProvider{}
+	srv := httptest.NewServer(fake.handler(t, "/projects/group/app/merge_requests/3/notes", "/projects/group/app/merge_requests/3/notes/", "Private-Token", "secret"))
+	defer srv.Close()
+
+	commenter, err := NewCommenter(Target{Provider: ProviderGitLab, APIURL: srv.URL, Repo: "group/app", Number: 3, Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, body := range []string{marker + "\nfirst", marker + "\nsecond"} {
+		if _, err := commenter.UpsertComment(context.Background(), marker, body); err != nil {
+			t.Fatalf("UpsertComment failed: %v", err)
+		}
+	}
+	if len(fake.comments) != 1 || fake.comments[0]["body"] != marker+"\nsecond" {
+		t.Errorf("expected a single updated note, got %v", fake.comments)
+	}
+}
+
+func TestCommenterAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	commenter, _ := NewCommenter(Target{Provider: ProviderGitHub, APIURL: srv.URL, Repo: "a/b", Number: 1, Token: "x"})
+	if _, err := commenter.UpsertComment(context.Background(), marker, marker); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("expected 403 error, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package ci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// Ensure GitHubCommenter implements usecases.PullRequestCommenter interface.
+var _ usecases.PullRequestCommenter = (*GitHubCommenter)(nil)
+
+// GitHubCommenter posts pull request comments through the GitHub REST API.
+type GitHubCommenter struct {
+	client *http.Client
+	target Target
+}
+
+// gitHubComment is the subset of a GitHub issue comment used here.
+type gitHubComment struct {
+	ID      int64  `json:"id"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+// UpsertComment updates the pull request comment containing marker, or
+// creates one, and returns the comment's URL.
+func (c *GitHubCommenter) UpsertComment(ctx context.Context, marker, body string) (string, error) {
+	base := strings.TrimSuffix(c.target.APIURL, "/") + "/repos/" + c.target.Repo
+	header := http.Header{
+		"Accept":               {"application/vnd.github+json"},
+		"Authorization":        {"Bearer " + c.target.Token},
+		"X-Github-Api-Version": {"2022-11-28"},
+	}
+
+	existing, err := c.findComment(ctx, base, header, marker)
+	if err != nil {
+		return "", err
+	}
+
+	var result gitHubComment
+	payload := map[string]string{"body": body}
+	if existing != nil {
+		err = doJSON(ctx, c.client, http.MethodPatch, fmt.Sprintf("%s/issues/comments/%d", base, existing.ID), header, payload, &result)
+	} else {
+		err = doJSON(ctx, c.client, http.MethodPost, fmt.Sprintf("%s/issues/%d/comments", base, c.target.Number), header, payload, &result)
+	}
+	if err != nil {
+		return "", err
+	}
+	return result.HTMLURL, nil
+}
+
+// findComment pages through the pull request's comments looking for marker.
+func (c *GitHubCommenter) findComment(ctx context.Context, base string, header http.Header, marker string) (*gitHubComment, error) {
+	for page := 1; ; page++ {
+		var comments []gitHubComment
+		url := fmt.Sprintf("%s/issues/%d/comments?per_page=%d&page=%d", base, c.target.Number, pageSize, page)
+		if err := doJSON(ctx, c.client, http.MethodGet, url, header, nil, &comments); err != nil {
+			return nil, err
+		}
+		for i := range comments {
+			if strings.Contains(comments[i].Body, marker) {
+				return &comments[i], nil
+			}
+		}
+		if len(comments) < pageSize {
+			return nil, nil
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package ci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// Ensure GitLabCommenter implements usecases.PullRequestCommenter interface.
+var _ usecases.PullRequestCommenter = (*GitLabCommenter)(nil)
+
+// GitLabCommenter posts merge request notes through the GitLab REST API.
+type GitLabCommenter struct {
+	client *http.Client
+	target Target
+}
+
+// gitLabNote is the subset of a GitLab merge request note used here.
+type gitLabNote struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// UpsertComment updates the merge request note containing marker, or creates
+// one. GitLab does not return note URLs, so the returned URL is empty.
+func (c *GitLabCommenter) UpsertComment(ctx context.Context, marker, body string) (string, error) {
+	base := fmt.Sprintf("%s/projects/%s/merge_requests/%d/notes",
+		strings.TrimSuffix(c.target.APIURL, "/"), url.PathEscape(c.target.Repo), c.target.Number)
+	header := http.Header{"Private-Token": {c.target.Token}}
+
+	existing, err := c.findNote(ctx, base, header, marker)
+	if err != nil {
+		return "", err
+	}
+
+	payload := map[string]string{"body": body}
+	if existing != nil {
+		return "", doJSON(ctx, c.client, http.MethodPut, fmt.Sprintf("%s/%d", base, existing.ID), header, payload, nil)
+	}
+	return "", doJSON(ctx, c.client, http.MethodPost, base, header, payload, nil)
+}
+
+// findNote pages through the merge request's notes looking for marker.
+func (c *GitLabCommenter) findNote(ctx context.Context, base string, header http.Header, marker string) (*gitLabNote, error) {
+	for page := 1; ; page++ {
+		var notes []gitLabNote
+		if err := doJSON(ctx, c.client, http.MethodGet, fmt.Sprintf("%s?per_page=%d&page=%d", base, pageSize, page), header, nil, &notes); err != nil {
+			return nil, err
+		}
+		for i := range notes {
+			if strings.Contains(notes[i].Body, marker) {
+				return &notes[i], nil
+			}
+		}
+		if len(notes) < pageSize {
+			return nil, nil
+		}
+	}
+}
@@ -41,3 +41,29 @@ func (r *ProgressReporter) ReportSuccess(message string) {
 func (r *ProgressReporter) ReportInfo(message string) {
 	fmt.Printf("  ℹ %s\n", message)
 }
+
+// Compile-time interface check
+var _ usecases.ProgressReporter = (*QuietProgressReporter)(nil)
+
+// QuietProgressReporter reports errors only. It is used when progress output
+// would distort the result, e.g., while benchmarking builds.
+type QuietProgressReporter struct{}
+
+// NewQuietProgressReporter creates a new QuietProgressReporter.
+func NewQuietProgressReporter() *QuietProgressReporter {
+	return &QuietProgressReporter{}
+}
+
+// ReportProgress discards progress updates.
+func (r *QuietProgressReporter) ReportProgress(step string, current int, total int, message string) {}
+
+// ReportError reports an error.
+func (r *QuietProgressReporter) ReportError(err error) {
+	fmt.Printf("  ✗ Error: %v\n", err)
+}
+
+// ReportSuccess discards success messages.
+func (r *QuietProgressReporter) ReportSuccess(message string) {}
+
+// ReportInfo discards informational messages.
+func (r *QuietProgressReporter) ReportInfo(message string) {}
@@ -70,12 +70,12 @@ func (p *Prompts) PromptStringMulti(prompt string) ([]string, error) {
 
 // PromptYesNo asks the user for a yes/no response.
 func (p *Prompts) PromptYesNo(prompt string, defaultYes bool) bool {
-	defaultStr := "n"
+	choices := "y/N"
 	if defaultYes {
-		defaultStr = "y"
+		choices = "Y/n"
 	}
 
-	fmt.Printf("%s [%s/n]: ", prompt, defaultStr)
+	fmt.Printf("%s [%s]: ", prompt, choices)
 	input, err := p.reader.ReadString('\n')
 	if err != nil {
 		return defaultYes
@@ -29,9 +29,11 @@ func NewLoader(paths *XDGPathResolver) *Loader {
 	return &Loader{paths: paths}
 }
 
-// LoadConfig reads configuration from both global and project-local loko.toml files.
-// Global config is read first, then project-local config is merged on top.
-// Missing files are silently ignored; defaults from entities.DefaultProjectConfig() apply.
+// LoadConfig reads configuration from both global and project-local
+// loko.toml files. Global config is read first, then project-local config is
+// merged on top, then LOKO_* environment variables override them. Missing
+// files are silently ignored; defaults from entities.DefaultProjectConfig()
+// apply.
 func (l *Loader) LoadConfig(ctx context.Context, projectRoot string) (*entities.ProjectConfig, error) {
 	v := viper.New()
 	v.SetConfigType("toml")
@@ -53,7 +55,9 @@ func (l *Loader) LoadConfig(ctx context.Context, projectRoot string) (*entities.
 	// Merge project-local config on top of global.
 	projectConfigPath := filepath.Join(projectRoot, "loko.toml")
 	v.SetConfigFile(projectConfigPath)
+	projectLoaded := true
 	if err := v.MergeInConfig(); err != nil {
+		projectLoaded = false
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			if !os.IsNotExist(err) {
 				return nil, fmt.Errorf("failed to load project config: %w", err)
@@ -61,7 +65,16 @@ func (l *Loader) LoadConfig(ctx context.Context, projectRoot string) (*entities.
 		}
 	}
 
-	return viperToConfig(v), nil
+	config := viperToConfig(v)
+	// A loko.toml without schema_version predates it.
+	if projectLoaded && !v.IsSet("schema_version") {
+		config.SchemaVersion = entities.LegacySchemaVersion
+	}
+	// LOKO_* environment variables override both files.
+	if err := entities.ApplyConfigEnv(config, os.LookupEnv); err != nil {
+		return nil, err
+	}
+	return config, nil
 }
 
 // LoadGlobalConfig reads only the global config file (~/.config/loko/config.toml).
@@ -109,7 +122,10 @@ func (l *Loader) SaveGlobalConfig(ctx context.Context, config *entities.ProjectC
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	return writeConfigToFile(l.paths.ConfigFile(), config)
+	// The schema version belongs to a project, not to the defaults shared by all.
+	global := *config
+	global.SchemaVersion = 0
+	return writeConfigToFile(l.paths.ConfigFile(), &global)
 }
 
 // GetOutputFormats returns the enabled output formats from config.
@@ -135,9 +151,15 @@ func GetOutputFormats(config *entities.ProjectConfig) []string {
 func viperToConfig(v *viper.Viper) *entities.ProjectConfig {
 	config := entities.DefaultProjectConfig()
 
+	if v.IsSet("schema_version") {
+		config.SchemaVersion = v.GetInt("schema_version")
+	}
 	if v.IsSet("paths.source") {
 		config.SourceDir = v.GetString("paths.source")
 	}
+	if v.IsSet("paths.source_dirs") {
+		config.SourceDirs = v.GetStringSlice("paths.source_dirs")
+	}
 	if v.IsSet("paths.output") {
 		config.OutputDir = v.GetString("paths.output")
 	}
@@ -150,6 +172,15 @@ func viperToConfig(v *viper.Viper) *entities.ProjectConfig {
 	if v.IsSet("d2.cache") {
 		config.D2Cache = v.GetBool("d2.cache")
 	}
+	if v.IsSet("d2.style") {
+		config.D2Style = v.GetString("d2.style")
+	}
+	if v.IsSet("d2.legend") {
+		config.DiagramLegend = v.GetBool("d2.legend")
+	}
+	if v.IsSet("d2.metadata_footer") {
+		config.DiagramFooter = v.GetBool("d2.metadata_footer")
+	}
 	if v.IsSet("outputs.html") {
 		config.HTMLEnabled = v.GetBool("outputs.html")
 	}
@@ -165,6 +196,9 @@ func viperToConfig(v *viper.Viper) *entities.ProjectConfig {
 	if v.IsSet("build.max_workers") {
 		config.MaxWorkers = v.GetInt("build.max_workers")
 	}
+	if v.IsSet("build.minify") {
+		config.Minify = v.GetBool("build.minify")
+	}
 	if v.IsSet("server.serve_port") {
 		config.ServePort = v.GetInt("server.serve_port")
 	}
@@ -174,6 +208,154 @@ func viperToConfig(v *viper.Viper) *entities.ProjectConfig {
 	if v.IsSet("server.hot_reload") {
 		config.HotReload = v.GetBool("server.hot_reload")
 	}
+	if v.IsSet("site.head") {
+		config.CustomHead = v.GetString("site.head")
+	}
+	if v.IsSet("site.head_file") {
+		config.CustomHeadFile = v.GetString("site.head_file")
+	}
+	if v.IsSet("site.footer") {
+		config.CustomFooter = v.GetString("site.footer")
+	}
+	if v.IsSet("site.footer_file") {
+		config.CustomFooterFile = v.GetString("site.footer_file")
+	}
+	if v.IsSet("site.analytics") {
+		config.AnalyticsProvider = v.GetString("site.analytics")
+	}
+	if v.IsSet("site.analytics_id") {
+		config.AnalyticsID = v.GetString("site.analytics_id")
+	}
+	if v.IsSet("site.custom_fields") {
+		config.CustomFields = v.GetStringSlice("site.custom_fields")
+	}
+	if v.IsSet("site.base_path") {
+		config.BasePath = v.GetString("site.base_path")
+	}
+	if v.IsSet("site.pretty_urls") {
+		config.PrettyURLs = v.GetBool("site.pretty_urls")
+	}
+	if v.IsSet("site.layouts") {
+		config.PageLayouts = v.GetStringMapString("site.layouts")
+	}
+	if v.IsSet("issues.url_template") {
+		config.IssueURLTemplate = v.GetString("issues.url_template")
+	}
+	if v.IsSet("issues.tracker") {
+		config.IssueTracker = v.GetString("issues.tracker")
+	}
+	if v.IsSet("issues.tracker_url") {
+		config.IssueTrackerURL = v.GetString("issues.tracker_url")
+	}
+	if v.IsSet("encryption.recipients_file") {
+		config.RecipientsFile = v.GetString("encryption.recipients_file")
+	}
+	if v.IsSet("redaction.remove_tags") {
+		config.RedactTags = v.GetStringSlice("redaction.remove_tags")
+	}
+	if v.IsSet("redaction.strip_fields") {
+		config.RedactFields = v.GetStringSlice("redaction.strip_fields")
+	}
+	if v.IsSet("redaction.mask_patterns") {
+		config.RedactPatterns = v.GetStringSlice("redaction.mask_patterns")
+	}
+	if v.IsSet("redaction.mask") {
+		config.RedactMask = v.GetString("redaction.mask")
+	}
+	if v.IsSet("permissions.mcp_role") {
+		config.MCPRole = v.GetString("permissions.mcp_role")
+	}
+	if v.IsSet("permissions.mcp_allow") {
+		config.MCPAllowTools = v.GetStringSlice("permissions.mcp_allow")
+	}
+	if v.IsSet("permissions.mcp_deny") {
+		config.MCPDenyTools = v.GetStringSlice("permissions.mcp_deny")
+	}
+	if v.IsSet("git.auto_commit") {
+		config.GitAutoCommit = v.GetBool("git.auto_commit")
+	}
+	if v.IsSet("metrics.record_usage") {
+		config.RecordUsage = v.GetBool("metrics.record_usage")
+	}
+	if v.IsSet("plugins.renderer") {
+		config.PluginRenderer = v.GetString("plugins.renderer")
+	}
+	if v.IsSet("icons") {
+		config.TechnologyIcons = v.GetStringMapString("icons")
+	}
+	if v.IsSet("relationship_types") {
+		config.RelationshipKinds = v.GetStringMapString("relationship_types")
+	}
+	if v.IsSet("deployment.require_mapping") {
+		config.RequireDeployment = v.GetBool("deployment.require_mapping")
+	}
+	if v.IsSet("deployment.targets") {
+		config.DeploymentTargets = v.GetStringSlice("deployment.targets")
+	}
+	if v.IsSet("costs.currency") {
+		config.CostCurrency = v.GetString("costs.currency")
+	}
+	if v.IsSet("review.require_approval") {
+		config.RequireApproval = v.GetBool("review.require_approval")
+	}
+	if v.IsSet("archetypes") {
+		config.Archetypes = make(map[string]entities.ContainerArchetype)
+		for name := range v.GetStringMap("archetypes") {
+			key := "archetypes." + name
+			config.Archetypes[name] = entities.ContainerArchetype{
+				Technology:  v.GetString(key + ".technology"),
+				Description: v.GetString(key + ".description"),
+				Tags:        v.GetStringSlice(key + ".tags"),
+				Components:  v.GetStringSlice(key + ".components"),
+				Diagram:     v.GetString(key + ".diagram"),
+			}
+		}
+	}
+	if v.IsSet("naming.id_case") {
+		config.NamingIDCase = v.GetString("naming.id_case")
+	}
+	if v.IsSet("naming.max_name_length") {
+		config.NamingMaxNameLength = v.GetInt("naming.max_name_length")
+	}
+	if v.IsSet("naming.technology_separator") {
+		config.NamingTechSeparator = v.GetString("naming.technology_separator")
+	}
+	if v.IsSet("naming.domain_prefixes") {
+		config.NamingDomainPrefixes = v.GetStringMapString("naming.domain_prefixes")
+	}
+	if v.IsSet("relationship_descriptions.missing") {
+		config.RelDescriptionMissing = v.GetString("relationship_descriptions.missing")
+	}
+	if v.IsSet("relationship_descriptions.verb_first") {
+		config.RelDescriptionVerbFirst = v.GetString("relationship_descriptions.verb_first")
+	}
+	if v.IsSet("relationship_descriptions.placeholder") {
+		config.RelDescriptionPlaceholder = v.GetString("relationship_descriptions.placeholder")
+	}
+	if v.IsSet("relationship_descriptions.too_long") {
+		config.RelDescriptionTooLong = v.GetString("relationship_descriptions.too_long")
+	}
+	if v.IsSet("relationship_descriptions.max_length") {
+		config.RelDescriptionMaxLength = v.GetInt("relationship_descriptions.max_length")
+	}
+	if v.IsSet("notifications.webhook_url") {
+		config.NotifyWebhookURL = v.GetString("notifications.webhook_url")
+	}
+	if v.IsSet("notifications.smtp_host") {
+		config.NotifySMTPHost = v.GetString("notifications.smtp_host")
+	}
+	if v.IsSet("notifications.email_from") {
+		config.NotifyEmailFrom = v.GetString("notifications.email_from")
+	}
+	if v.IsSet("hooks.pre_build") {
+		config.PreBuildHooks = commands(v.Get("hooks.pre_build"))
+	}
+	if v.IsSet("hooks.post_build") {
+		config.PostBuildHooks = commands(v.Get("hooks.post_build"))
+	}
+	if v.IsSet("hooks.pre_validate") {
+		config.PreValidateHooks = commands(v.Get("hooks.pre_validate"))
+	}
 	if v.IsSet("project.template") {
 		config.Template = v.GetString("project.template")
 	}
@@ -181,24 +363,71 @@ func viperToConfig(v *viper.Viper) *entities.ProjectConfig {
 	return config
 }
 
+// commands decodes a hook setting, which is either a single command string
+// or an array of commands. Unlike GetStringSlice, a single string is not
+// split on whitespace.
+func commands(value any) []string {
+	switch value := value.(type) {
+	case string:
+		if value == "" {
+			return nil
+		}
+		return []string{value}
+	case []any:
+		out := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	case []string:
+		return value
+	}
+	return nil
+}
+
 // tomlConfig is the TOML serialization structure for SaveConfig/SaveGlobalConfig.
 type tomlConfig struct {
-	Paths   tomlPaths   `toml:"paths"`
-	D2      tomlD2      `toml:"d2"`
-	Outputs tomlOutputs `toml:"outputs"`
-	Build   tomlBuild   `toml:"build"`
-	Server  tomlServer  `toml:"server"`
+	SchemaVersion int                      `toml:"schema_version,omitempty"`
+	Paths         tomlPaths                `toml:"paths"`
+	D2            tomlD2                   `toml:"d2"`
+	Outputs       tomlOutputs              `toml:"outputs"`
+	Build         tomlBuild                `toml:"build"`
+	Server        tomlServer               `toml:"server"`
+	Site          tomlSite                 `toml:"site,omitempty"`
+	Issues        tomlIssues               `toml:"issues,omitempty"`
+	Encryption    tomlEncryption           `toml:"encryption,omitempty"`
+	Redaction     tomlRedaction            `toml:"redaction,omitempty"`
+	Permissions   tomlPermissions          `toml:"permissions,omitempty"`
+	Git           tomlGit                  `toml:"git,omitempty"`
+	Metrics       tomlMetrics              `toml:"metrics,omitempty"`
+	Plugins       tomlPlugins              `toml:"plugins,omitempty"`
+	Hooks         tomlHooks                `toml:"hooks,omitempty"`
+	Deployment    tomlDeployment           `toml:"deployment,omitempty"`
+	Costs         tomlCosts                `toml:"costs,omitempty"`
+	Review        tomlReview               `toml:"review,omitempty"`
+	Naming        tomlNaming               `toml:"naming,omitempty"`
+	RelDesc       tomlRelDescriptions      `toml:"relationship_descriptions,omitempty"`
+	Notifications tomlNotifications        `toml:"notifications,omitempty"`
+	Icons         map[string]string        `toml:"icons,omitempty"`
+	RelKinds      map[string]string        `toml:"relationship_types,omitempty"`
+	Archetypes    map[string]tomlArchetype `toml:"archetypes,omitempty"`
 }
 
 type tomlPaths struct {
-	Source string `toml:"source"`
-	Output string `toml:"output"`
+	Source     string   `toml:"source"`
+	SourceDirs []string `toml:"source_dirs,omitempty"`
+	Output     string   `toml:"output"`
 }
 
 type tomlD2 struct {
 	Theme  string `toml:"theme"`
 	Layout string `toml:"layout"`
 	Cache  bool   `toml:"cache"`
+	Style  string `toml:"style,omitempty"`
+	Legend bool   `toml:"legend"`
+	Footer bool   `toml:"metadata_footer"`
 }
 
 type tomlOutputs struct {
@@ -210,6 +439,103 @@ type tomlOutputs struct {
 type tomlBuild struct {
 	Parallel   bool `toml:"parallel"`
 	MaxWorkers int  `toml:"max_workers"`
+	Minify     bool `toml:"minify"`
+}
+
+type tomlSite struct {
+	Head         string            `toml:"head,omitempty"`
+	HeadFile     string            `toml:"head_file,omitempty"`
+	Footer       string            `toml:"footer,omitempty"`
+	FooterFile   string            `toml:"footer_file,omitempty"`
+	Analytics    string            `toml:"analytics,omitempty"`
+	AnalyticsID  string            `toml:"analytics_id,omitempty"`
+	CustomFields []string          `toml:"custom_fields,omitempty"`
+	BasePath     string            `toml:"base_path,omitempty"`
+	PrettyURLs   bool              `toml:"pretty_urls,omitempty"`
+	Layouts      map[string]string `toml:"layouts,omitempty"`
+}
+
+type tomlIssues struct {
+	URLTemplate string `toml:"url_template,omitempty"`
+	Tracker     string `toml:"tracker,omitempty"`
+	TrackerURL  string `toml:"tracker_url,omitempty"`
+}
+
+type tomlEncryption struct {
+	RecipientsFile string `toml:"recipients_file,omitempty"`
+}
+
+type tomlRedaction struct {
+	RemoveTags   []string `toml:"remove_tags,omitempty"`
+	StripFields  []string `toml:"strip_fields,omitempty"`
+	MaskPatterns []string `toml:"mask_patterns,omitempty"`
+	Mask         string   `toml:"mask,omitempty"`
+}
+
+type tomlPermissions struct {
+	MCPRole  string   `toml:"mcp_role,omitempty"`
+	MCPAllow []string `toml:"mcp_allow,omitempty"`
+	MCPDeny  []string `toml:"mcp_deny,omitempty"`
+}
+
+type tomlGit struct {
+	AutoCommit bool `toml:"auto_commit,omitempty"`
+}
+
+type tomlMetrics struct {
+	RecordUsage bool `toml:"record_usage,omitempty"`
+}
+
+type tomlPlugins struct {
+	Renderer string `toml:"renderer,omitempty"`
+}
+
+type tomlHooks struct {
+	PreBuild    []string `toml:"pre_build,omitempty"`
+	PostBuild   []string `toml:"post_build,omitempty"`
+	PreValidate []string `toml:"pre_validate,omitempty"`
+}
+
+type tomlDeployment struct {
+	RequireMapping bool     `toml:"require_mapping,omitempty"`
+	Targets        []string `toml:"targets,omitempty"`
+}
+
+type tomlCosts struct {
+	Currency string `toml:"currency,omitempty"`
+}
+
+type tomlReview struct {
+	RequireApproval bool `toml:"require_approval,omitempty"`
+}
+
+type tomlNaming struct {
+	IDCase              string            `toml:"id_case,omitempty"`
+	MaxNameLength       int               `toml:"max_name_length,omitempty"`
+	TechnologySeparator string            `toml:"technology_separator,omitempty"`
+	DomainPrefixes      map[string]string `toml:"domain_prefixes,omitempty"`
+}
+
+type tomlRelDescriptions struct {
+	Missing     string `toml:"missing,omitempty"`
+	VerbFirst   string `toml:"verb_first,omitempty"`
+	Placeholder string `toml:"placeholder,omitempty"`
+	TooLong     string `toml:"too_long,omitempty"`
+	MaxLength   int    `toml:"max_length,omitempty"`
+}
+
+type tomlArchetype struct {
+	Technology  string   `toml:"technology,omitempty"`
+	Description string   `toml:"description,omitempty"`
+	Tags        []string `toml:"tags,omitempty"`
+	Components  []string `toml:"components,omitempty"`
+	Diagram     string   `toml:"diagram,omitempty"`
+}
+
+type tomlNotifications struct {
+	WebhookURL string `toml:"webhook_url,omitempty"`
+	SMTPHost   string `toml:"smtp_host,omitempty"`
+	EmailFrom  string `toml:"email_from,omitempty"`
 }
 
 type tomlServer struct {
@@ -221,13 +547,18 @@ type tomlServer struct {
 // writeConfigToFile marshals a ProjectConfig to TOML and writes it to the given path.
 func writeConfigToFile(path string, config *entities.ProjectConfig) error {
 	tc := tomlConfig{
+		SchemaVersion: config.SchemaVersion,
 		Paths: tomlPaths{
-			Source: config.SourceDir,
-			Output: config.OutputDir,
+			Source:     config.SourceDir,
+			SourceDirs: config.SourceDirs,
+			Output:     config.OutputDir,
 		},
 		D2: tomlD2{
 			Theme:  config.D2Theme,
 			Layout: config.D2Layout,
+			Style:  config.D2Style,
+			Legend: config.DiagramLegend,
+			Footer: config.DiagramFooter,
 			Cache:  config.D2Cache,
 		},
 		Outputs: tomlOutputs{
@@ -238,12 +569,94 @@ func writeConfigToFile(path string, config *entities.ProjectConfig) error {
 		Build: tomlBuild{
 			Parallel:   config.Parallel,
 			MaxWorkers: config.MaxWorkers,
+			Minify:     config.Minify,
 		},
 		Server: tomlServer{
 			ServePort: config.ServePort,
 			APIPort:   config.APIPort,
 			HotReload: config.HotReload,
 		},
+		Site: tomlSite{
+			Head:         config.CustomHead,
+			HeadFile:     config.CustomHeadFile,
+			Footer:       config.CustomFooter,
+			FooterFile:   config.CustomFooterFile,
+			Analytics:    config.AnalyticsProvider,
+			AnalyticsID:  config.AnalyticsID,
+			CustomFields: config.CustomFields,
+			BasePath:     config.BasePath,
+			PrettyURLs:   config.PrettyURLs,
+			Layouts:      config.PageLayouts,
+		},
+		Issues: tomlIssues{
+			URLTemplate: config.IssueURLTemplate,
+			Tracker:     config.IssueTracker,
+			TrackerURL:  config.IssueTrackerURL,
+		},
+		Encryption: tomlEncryption{
+			RecipientsFile: config.RecipientsFile,
+		},
+		Redaction: tomlRedaction{
+			RemoveTags:   config.RedactTags,
+			StripFields:  config.RedactFields,
+			MaskPatterns: config.RedactPatterns,
+			Mask:         config.RedactMask,
+		},
+		Permissions: tomlPermissions{
+			MCPRole:  config.MCPRole,
+			MCPAllow: config.MCPAllowTools,
+			MCPDeny:  config.MCPDenyTools,
+		},
+		Git: tomlGit{
+			AutoCommit: config.GitAutoCommit,
+		},
+		Metrics: tomlMetrics{
+			RecordUsage: config.RecordUsage,
+		},
+		Plugins: tomlPlugins{
+			Renderer: config.PluginRenderer,
+		},
+		Hooks: tomlHooks{
+			PreBuild:    config.PreBuildHooks,
+			PostBuild:   config.PostBuildHooks,
+			PreValidate: config.PreValidateHooks,
+		},
+		Deployment: tomlDeployment{
+			RequireMapping: config.RequireDeployment,
+			Targets:        config.DeploymentTargets,
+		},
+		Costs: tomlCosts{
+			Currency: config.CostCurrency,
+		},
+		Review: tomlReview{
+			RequireApproval: config.RequireApproval,
+		},
+		Naming: tomlNaming{
+			IDCase:              config.NamingIDCase,
+			MaxNameLength:       config.NamingMaxNameLength,
+			TechnologySeparator: config.NamingTechSeparator,
+			DomainPrefixes:      config.NamingDomainPrefixes,
+		},
+		RelDesc: tomlRelDescriptions{
+			Missing:     config.RelDescriptionMissing,
+			VerbFirst:   config.RelDescriptionVerbFirst,
+			Placeholder: config.RelDescriptionPlaceholder,
+			TooLong:     config.RelDescriptionTooLong,
+			MaxLength:   config.RelDescriptionMaxLength,
+		},
+		Notifications: tomlNotifications{
+			WebhookURL: config.NotifyWebhookURL,
+			SMTPHost:   config.NotifySMTPHost,
+			EmailFrom:  config.NotifyEmailFrom,
+		},
+		Icons:    config.TechnologyIcons,
+		RelKinds: config.RelationshipKinds,
+	}
+	if len(config.Archetypes) > 0 {
+		tc.Archetypes = make(map[string]tomlArchetype, len(config.Archetypes))
+		for name, archetype := range config.Archetypes {
+			tc.Archetypes[name] = tomlArchetype(archetype)
+		}
 	}
 
 	data, err := toml.Marshal(tc)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
@@ -54,7 +55,9 @@ output = "./docs"
 [d2]
 theme = "dark"
 layout = "dagre"
+style = "classic"
 cache = false
+metadata_footer = false
 
 [outputs]
 html = true
@@ -64,11 +67,56 @@ pdf = false
 [build]
 parallel = false
 max_workers = 2
+minify = true
 
 [server]
 serve_port = 3000
 api_port = 3001
 hot_reload = false
+
+[hooks]
+pre_build = "make openapi-summary"
+post_build = ["./scripts/upload.sh", "echo done"]
+
+[relationship_types]
+deploys = "container -> system"
+
+[deployment]
+require_mapping = true
+targets = ["eks-prod", "lambda"]
+
+[costs]
+currency = "EUR"
+
+[review]
+require_approval = true
+
+[naming]
+id_case = "kebab"
+max_name_length = 40
+technology_separator = ","
+
+[naming.domain_prefixes]
+payments = "pay-"
+
+[relationship_descriptions]
+missing = "error"
+verb_first = "warning"
+max_length = 60
+
+[site.layouts]
+"tag:datastore" = "layouts/datastore.html"
+
+[archetypes.go-service]
+technology = "Go"
+tags = ["service"]
+components = ["handler", "repository"]
+diagram = "archetypes/go-service.d2"
+
+[notifications]
+webhook_url = "https://hooks.example.com/loko"
+smtp_host = "smtp.example.com:587"
+email_from = "loko@example.com"
 `
 	configPath := filepath.Join(tmpDir, "loko.toml")
 	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
@@ -81,6 +129,9 @@ hot_reload = false
 	}
 
 	// Verify loaded values
+	if config.SchemaVersion != entities.LegacySchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d for a loko.toml without schema_version", config.SchemaVersion, entities.LegacySchemaVersion)
+	}
 	if config.SourceDir != "./architecture" {
 		t.Errorf("SourceDir = %q, want %q", config.SourceDir, "./architecture")
 	}
@@ -93,6 +144,41 @@ hot_reload = false
 	if config.D2Layout != "dagre" {
 		t.Errorf("D2Layout = %q, want %q", config.D2Layout, "dagre")
 	}
+	if config.D2Style != "classic" {
+		t.Errorf("D2Style = %q, want %q", config.D2Style, "classic")
+	}
+	if !config.DiagramLegend || config.DiagramFooter {
+		t.Errorf("DiagramLegend, DiagramFooter = %v, %v; want true, false", config.DiagramLegend, config.DiagramFooter)
+	}
+	if config.RelationshipKinds["deploys"] != "container -> system" {
+		t.Errorf("RelationshipKinds = %v, want deploys", config.RelationshipKinds)
+	}
+	if !config.RequireDeployment || !slices.Equal(config.DeploymentTargets, []string{"eks-prod", "lambda"}) {
+		t.Errorf("RequireDeployment, DeploymentTargets = %v, %q", config.RequireDeployment, config.DeploymentTargets)
+	}
+	if config.CostCurrency != "EUR" {
+		t.Errorf("CostCurrency = %q, want EUR", config.CostCurrency)
+	}
+	if !config.RequireApproval {
+		t.Error("RequireApproval = false, want true")
+	}
+	if config.NamingIDCase != "kebab" || config.NamingMaxNameLength != 40 || config.NamingTechSeparator != "," || config.NamingDomainPrefixes["payments"] != "pay-" {
+		t.Errorf("naming = %q, %d, %q, %v", config.NamingIDCase, config.NamingMaxNameLength, config.NamingTechSeparator, config.NamingDomainPrefixes)
+	}
+	if config.RelDescriptionMissing != "error" || config.RelDescriptionVerbFirst != "warning" || config.RelDescriptionTooLong != "" || config.RelDescriptionMaxLength != 60 {
+		t.Errorf("relationship descriptions = %q, %q, %q, %d", config.RelDescriptionMissing, config.RelDescriptionVerbFirst, config.RelDescriptionTooLong, config.RelDescriptionMaxLength)
+	}
+	if config.PageLayouts["tag:datastore"] != "layouts/datastore.html" {
+		t.Errorf("PageLayouts = %v", config.PageLayouts)
+	}
+	archetype := config.Archetypes["go-service"]
+	if archetype.Technology != "Go" || !slices.Equal(archetype.Tags, []string{"service"}) ||
+		!slices.Equal(archetype.Components, []string{"handler", "repository"}) || archetype.Diagram != "archetypes/go-service.d2" {
+		t.Errorf("Archetypes = %+v", config.Archetypes)
+	}
+	if config.NotifyWebhookURL != "https://hooks.example.com/loko" || config.NotifySMTPHost != "smtp.example.com:587" || config.NotifyEmailFrom != "loko@example.com" {
+		t.Errorf("notifications = %q, %q, %q", config.NotifyWebhookURL, config.NotifySMTPHost, config.NotifyEmailFrom)
+	}
 	if config.D2Cache != false {
 		t.Errorf("D2Cache = %v, want false", config.D2Cache)
 	}
@@ -111,6 +197,9 @@ hot_reload = false
 	if config.MaxWorkers != 2 {
 		t.Errorf("MaxWorkers = %d, want 2", config.MaxWorkers)
 	}
+	if config.Minify != true {
+		t.Errorf("Minify = %v, want true", config.Minify)
+	}
 	if config.ServePort != 3000 {
 		t.Errorf("ServePort = %d, want 3000", config.ServePort)
 	}
@@ -120,6 +209,12 @@ hot_reload = false
 	if config.HotReload != false {
 		t.Errorf("HotReload = %v, want false", config.HotReload)
 	}
+	if len(config.PreBuildHooks) != 1 || config.PreBuildHooks[0] != "make openapi-summary" {
+		t.Errorf("PreBuildHooks = %q, want the single command unsplit", config.PreBuildHooks)
+	}
+	if len(config.PostBuildHooks) != 2 || config.PostBuildHooks[1] != "echo done" {
+		t.Errorf("PostBuildHooks = %q", config.PostBuildHooks)
+	}
 }
 
 func TestLoader_SaveConfig(t *testing.T) {
@@ -132,6 +227,9 @@ func TestLoader_SaveConfig(t *testing.T) {
 	config.OutputDir = "./custom-dist"
 	config.MarkdownEnabled = true
 	config.PDFEnabled = true
+	config.Archetypes = map[string]entities.ContainerArchetype{
+		"go-service": {Technology: "Go", Components: []string{"handler"}},
+	}
 
 	err := loader.SaveConfig(ctx, tmpDir, config)
 	if err != nil {
@@ -150,9 +248,15 @@ func TestLoader_SaveConfig(t *testing.T) {
 		t.Fatalf("LoadConfig failed: %v", err)
 	}
 
+	if loadedConfig.SchemaVersion != entities.CurrentSchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", loadedConfig.SchemaVersion, entities.CurrentSchemaVersion)
+	}
 	if loadedConfig.SourceDir != "./custom-src" {
 		t.Errorf("SourceDir = %q, want %q", loadedConfig.SourceDir, "./custom-src")
 	}
+	if archetype := loadedConfig.Archetypes["go-service"]; archetype.Technology != "Go" || !slices.Equal(archetype.Components, []string{"handler"}) {
+		t.Errorf("Archetypes = %+v", loadedConfig.Archetypes)
+	}
 	if loadedConfig.OutputDir != "./custom-dist" {
 		t.Errorf("OutputDir = %q, want %q", loadedConfig.OutputDir, "./custom-dist")
 	}
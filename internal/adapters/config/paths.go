@@ -42,6 +42,8 @@ func (r *XDGPathResolver) DataDir() string    { return r.paths.DataHome }
 func (r *XDGPathResolver) CacheDir() string   { return r.paths.CacheHome }
 func (r *XDGPathResolver) ConfigFile() string { return r.paths.ConfigFile() }
 func (r *XDGPathResolver) ThemesDir() string  { return r.paths.ThemesDir() }
+func (r *XDGPathResolver) PluginsDir() string { return r.paths.PluginsDir() }
+func (r *XDGPathResolver) IconsDir() string   { return r.paths.IconsDir() }
 
 // EnsureDir creates the directory if it doesn't exist (lazy creation on first write).
 func (r *XDGPathResolver) EnsureDir(path string) error {
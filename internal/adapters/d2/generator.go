@@ -2,8 +2,10 @@ package d2
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
@@ -12,14 +14,38 @@ import (
 
 // Generator generates D2 diagram source code from architecture entities.
 // It implements the DiagramGenerator interface from the core usecases layer.
-type Generator struct{}
+type Generator struct {
+	icons  *entities.IconRegistry // Technology icons for containers and components
+	style  *entities.DiagramStyle // C4 styling preset; nil keeps the plain style
+	people []*entities.Person     // People drawn in the context diagrams of the systems they use
+}
 
 // Compile-time interface check
 var _ usecases.DiagramGenerator = (*Generator)(nil)
 
-// NewGenerator creates a new D2 generator.
+// NewGenerator creates a new D2 generator that gives containers and
+// components the default icon for their technology.
 func NewGenerator() *Generator {
-	return &Generator{}
+	return &Generator{icons: entities.NewIconRegistry(nil)}
+}
+
+// SetIcons sets the technology icon registry, e.g. one with the [icons]
+// overrides from loko.toml. A nil registry disables icons.
+func (g *Generator) SetIcons(icons *entities.IconRegistry) {
+	g.icons = icons
+}
+
+// SetStyle sets the C4 styling preset, e.g. the [d2] style from loko.toml,
+// giving each element level its shape and colors and adding a legend. A nil
+// style keeps the plain style.
+func (g *Generator) SetStyle(style *entities.DiagramStyle) {
+	g.style = style
+}
+
+// SetPeople sets the people of the project. A system context diagram shows
+// the people that use the system in place of its key users.
+func (g *Generator) SetPeople(people []*entities.Person) {
+	g.people = people
 }
 
 // GenerateSystemContextDiagram creates a C4 Level 1 system context diagram.
@@ -32,17 +58,42 @@ func (g *Generator) GenerateSystemContextDiagram(system *entities.System) (strin
 	sb.WriteString(fmt.Sprintf("# System: %s\n", system.Name))
 	sb.WriteString(fmt.Sprintf("# Description: %s\n\n", system.Description))
 
-	sb.WriteString("direction: right\n\n")
+	sb.WriteString("direction: right\n")
+	g.writeBackground(&sb)
+	sb.WriteString("\n")
+
+	// People that use the system take the place of its key users
+	var people []*entities.Person
+	for _, person := range g.people {
+		if person.Uses(system.ID) {
+			people = append(people, person)
+		}
+	}
 
 	// Add users
 	sb.WriteString("# Primary users/actors\n")
-	if len(system.KeyUsers) > 0 {
-		for i, user := range system.KeyUsers {
-			userID := fmt.Sprintf("user_%d", i+1)
-			sb.WriteString(fmt.Sprintf("%s: \"%s\"\n", userID, user))
+	if len(people) > 0 {
+		for _, person := range people {
+			g.writePerson(&sb, person)
 		}
 	} else {
-		sb.WriteString("user: \"User/Actor\"\n")
+		users := system.KeyUsers
+		if len(users) == 0 {
+			users = []string{"User/Actor"}
+		}
+		for i, user := range users {
+			userID := "user"
+			if len(system.KeyUsers) > 0 {
+				userID = fmt.Sprintf("user_%d", i+1)
+			}
+			if g.style == nil {
+				sb.WriteString(fmt.Sprintf("%s: \"%s\"\n", userID, user))
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("%s: \"%s\" {\n", userID, user))
+			writeElementStyle(&sb, "  ", g.style.Person, false)
+			sb.WriteString("}\n")
+		}
 	}
 	sb.WriteString("\n")
 
@@ -50,16 +101,28 @@ func (g *Generator) GenerateSystemContextDiagram(system *entities.System) (strin
 	sb.WriteString("# Main system\n")
 	sb.WriteString(fmt.Sprintf("%s: \"%s\" {\n", system.ID, system.Name))
 	sb.WriteString(fmt.Sprintf("  description: \"%s\"\n", system.Description))
+	if g.style != nil {
+		writeElementStyle(&sb, "  ", g.style.System, false)
+	}
 	sb.WriteString("}\n\n")
 
 	// Add relationships with users
 	sb.WriteString("# User interactions\n")
-	if len(system.KeyUsers) > 0 {
+	switch {
+	case len(people) > 0:
+		for _, person := range people {
+			label := person.Relationships[system.ID]
+			if label == "" {
+				label = "Uses"
+			}
+			sb.WriteString(fmt.Sprintf("%s -> %s: \"%s\"\n", person.ID, system.ID, label))
+		}
+	case len(system.KeyUsers) > 0:
 		for i := range system.KeyUsers {
 			userID := fmt.Sprintf("user_%d", i+1)
 			sb.WriteString(fmt.Sprintf("%s -> %s: \"Uses\"\n", userID, system.ID))
 		}
-	} else {
+	default:
 		sb.WriteString(fmt.Sprintf("user -> %s: \"Uses\"\n", system.ID))
 	}
 	sb.WriteString("\n")
@@ -70,7 +133,11 @@ func (g *Generator) GenerateSystemContextDiagram(system *entities.System) (strin
 		for i, extSys := range system.ExternalSystems {
 			extID := fmt.Sprintf("external_%d", i+1)
 			sb.WriteString(fmt.Sprintf("%s: \"%s\" {\n", extID, extSys))
-			sb.WriteString("  style { fill: \"#FFF3E0\" }\n")
+			if g.style != nil {
+				writeElementStyle(&sb, "  ", g.style.External, false)
+			} else {
+				sb.WriteString("  style { fill: \"#FFF3E0\" }\n")
+			}
 			sb.WriteString("}\n")
 		}
 		sb.WriteString("\n")
@@ -82,6 +149,30 @@ func (g *Generator) GenerateSystemContextDiagram(system *entities.System) (strin
 		sb.WriteString("\n")
 	}
 
+	// Add related systems
+	if len(system.Relationships) > 0 {
+		sb.WriteString("# System relationships\n")
+		targets := slices.Sorted(maps.Keys(system.Relationships))
+		for i, target := range targets {
+			g.writeRelatedElement(&sb, fmt.Sprintf("related_%d", i+1), target)
+		}
+		sb.WriteString("\n")
+
+		for i, target := range targets {
+			sb.WriteString(fmt.Sprintf("%s -> related_%d: \"%s\"\n", system.ID, i+1, system.Relationships[target]))
+		}
+		sb.WriteString("\n")
+	}
+
+	if g.style != nil {
+		legend := []legendEntry{{"Person", g.style.Person, false}, {"Software System", g.style.System, false}}
+		if len(system.ExternalSystems) > 0 {
+			legend = append(legend, legendEntry{"External System", g.style.External, false})
+		}
+		writeLegend(&sb, g.style, legend)
+		return sb.String(), nil
+	}
+
 	// Styling
 	sb.WriteString("# Styling\n")
 	sb.WriteString(fmt.Sprintf("%s: {\n", system.ID))
@@ -104,7 +195,9 @@ func (g *Generator) GenerateContainerDiagram(system *entities.System) (string, e
 	sb.WriteString("# C4 Level 2 - Container View\n")
 	sb.WriteString(fmt.Sprintf("# System: %s\n\n", system.Name))
 
-	sb.WriteString("direction: right\n\n")
+	sb.WriteString("direction: right\n")
+	g.writeBackground(&sb)
+	sb.WriteString("\n")
 
 	// Add users
 	sb.WriteString("# External users\n")
@@ -112,9 +205,17 @@ func (g *Generator) GenerateContainerDiagram(system *entities.System) (string, e
 		for i, user := range system.KeyUsers {
 			userID := fmt.Sprintf("user_%d", i+1)
 			sb.WriteString(fmt.Sprintf("%s: \"%s\" {\n", userID, user))
-			sb.WriteString("  style { fill: \"#FFF3E0\" }\n")
+			if g.style != nil {
+				writeElementStyle(&sb, "  ", g.style.Person, false)
+			} else {
+				sb.WriteString("  style { fill: \"#FFF3E0\" }\n")
+			}
 			sb.WriteString("}\n")
 		}
+	} else if g.style != nil {
+		sb.WriteString("user: \"User/Actor\" {\n")
+		writeElementStyle(&sb, "  ", g.style.Person, false)
+		sb.WriteString("}\n")
 	} else {
 		sb.WriteString("user: \"User/Actor\" { style { fill: \"#FFF3E0\" } }\n")
 	}
@@ -123,6 +224,10 @@ func (g *Generator) GenerateContainerDiagram(system *entities.System) (string, e
 	// Add system as container group
 	sb.WriteString(fmt.Sprintf("%s: \"%s\" {\n", system.ID, system.Name))
 	sb.WriteString(fmt.Sprintf("  description: \"%s\"\n\n", system.Description))
+	if g.style != nil {
+		writeElementStyle(&sb, "  ", g.style.Boundary, true)
+		sb.WriteString("\n")
+	}
 
 	// Add containers
 	if system.ContainerCount() > 0 {
@@ -134,7 +239,14 @@ func (g *Generator) GenerateContainerDiagram(system *entities.System) (string, e
 			if container.Technology != "" {
 				sb.WriteString(fmt.Sprintf("    technology: \"%s\"\n", container.Technology))
 			}
-			sb.WriteString("    style { fill: \"#E3F2FD\" }\n")
+			if icon := g.icons.Icon(container.Technology); icon != "" {
+				sb.WriteString(fmt.Sprintf("    icon: \"%s\"\n", icon))
+			}
+			if g.style != nil {
+				writeElementStyle(&sb, "    ", g.style.Container, false)
+			} else {
+				sb.WriteString("    style { fill: \"#E3F2FD\" }\n")
+			}
 			sb.WriteString("  }\n")
 		}
 	} else {
@@ -154,8 +266,34 @@ func (g *Generator) GenerateContainerDiagram(system *entities.System) (string, e
 		sb.WriteString(fmt.Sprintf("user -> %s: \"Uses\"\n", system.ID))
 	}
 
-	// Container relationships (optional)
-	if system.ContainerCount() > 1 {
+	// Container relationships, to the system's own containers or to related
+	// elements drawn outside it
+	var interactions []string
+	related := make(map[string]string)
+	var relatedTargets []string
+	for _, container := range system.ListContainers() {
+		for _, target := range slices.Sorted(maps.Keys(container.Relationships)) {
+			dest := related[target]
+			if id := strings.TrimPrefix(target, system.ID+"/"); system.Containers[id] != nil {
+				dest = system.ID + "." + id
+			} else if dest == "" {
+				dest = fmt.Sprintf("related_%d", len(relatedTargets)+1)
+				related[target] = dest
+				relatedTargets = append(relatedTargets, target)
+			}
+			interactions = append(interactions, fmt.Sprintf("%s.%s -> %s: \"%s\"\n",
+				system.ID, container.ID, dest, container.Relationships[target]))
+		}
+	}
+	if len(interactions) > 0 {
+		sb.WriteString("\n# Container interactions\n")
+		for _, target := range relatedTargets {
+			g.writeRelatedElement(&sb, related[target], target)
+		}
+		for _, line := range interactions {
+			sb.WriteString(line)
+		}
+	} else if system.ContainerCount() > 1 {
 		sb.WriteString("\n# Container interactions (add as needed)\n")
 		containers := system.ListContainers()
 		if len(containers) >= 2 {
@@ -166,6 +304,19 @@ func (g *Generator) GenerateContainerDiagram(system *entities.System) (string, e
 
 	sb.WriteString("\n")
 
+	if g.style != nil {
+		legend := []legendEntry{
+			{"Person", g.style.Person, false},
+			{"Container", g.style.Container, false},
+			{"System Boundary", g.style.Boundary, true},
+		}
+		if len(relatedTargets) > 0 {
+			legend = append(legend, legendEntry{"Software System", g.style.System, false})
+		}
+		writeLegend(&sb, g.style, legend)
+		return sb.String(), nil
+	}
+
 	// System styling
 	sb.WriteString(fmt.Sprintf("%s: {\n", system.ID))
 	sb.WriteString("  style {\n")
@@ -186,7 +337,9 @@ func (g *Generator) GenerateComponentDiagram(container *entities.Container) (str
 	sb.WriteString("# C4 Level 3 - Component View\n")
 	sb.WriteString(fmt.Sprintf("# Container: %s\n\n", container.Name))
 
-	sb.WriteString("direction: right\n\n")
+	sb.WriteString("direction: right\n")
+	g.writeBackground(&sb)
+	sb.WriteString("\n")
 
 	// Add components
 	if container.ComponentCount() > 0 {
@@ -199,7 +352,14 @@ func (g *Generator) GenerateComponentDiagram(container *entities.Container) (str
 			if component.Technology != "" {
 				sb.WriteString(fmt.Sprintf("  technology: \"%s\"\n", component.Technology))
 			}
-			sb.WriteString("  style { fill: \"#E3F2FD\" }\n")
+			if icon := g.icons.Icon(component.Technology); icon != "" {
+				sb.WriteString(fmt.Sprintf("  icon: \"%s\"\n", icon))
+			}
+			if g.style != nil {
+				writeElementStyle(&sb, "  ", g.style.Component, false)
+			} else {
+				sb.WriteString("  style { fill: \"#E3F2FD\" }\n")
+			}
 			sb.WriteString("}\n")
 		}
 	} else {
@@ -220,6 +380,11 @@ func (g *Generator) GenerateComponentDiagram(container *entities.Container) (str
 
 	sb.WriteString("\n")
 
+	if g.style != nil {
+		writeLegend(&sb, g.style, []legendEntry{{"Component", g.style.Component, false}})
+		return sb.String(), nil
+	}
+
 	// Styling
 	sb.WriteString(fmt.Sprintf("%s: {\n", container.ID))
 	sb.WriteString("  style {\n")
@@ -231,6 +396,79 @@ func (g *Generator) GenerateComponentDiagram(container *entities.Container) (str
 	return sb.String(), nil
 }
 
+// legendEntry is one element kind shown in a diagram's legend.
+type legendEntry struct {
+	label  string
+	style  entities.ElementStyle
+	dashed bool
+}
+
+// writeRelatedElement writes the element a relationship points to, labeled
+// with the target's ID, e.g. "payments" or "payments/gateway".
+func (g *Generator) writeRelatedElement(sb *strings.Builder, id, target string) {
+	sb.WriteString(fmt.Sprintf("%s: \"%s\" {\n", id, target))
+	if g.style != nil {
+		writeElementStyle(sb, "  ", g.style.System, false)
+	} else {
+		sb.WriteString("  style { fill: \"#E1F5FF\" }\n")
+	}
+	sb.WriteString("}\n")
+}
+
+// writePerson writes the node of a person, drawn as a person shape in the
+// plain style too.
+func (g *Generator) writePerson(sb *strings.Builder, person *entities.Person) {
+	sb.WriteString(fmt.Sprintf("%s: \"%s\" {\n", person.ID, person.Name))
+	if g.style != nil {
+		writeElementStyle(sb, "  ", g.style.Person, false)
+	} else {
+		sb.WriteString("  shape: person\n")
+	}
+	sb.WriteString("}\n")
+}
+
+// writeBackground sets the diagram background when the style has one.
+func (g *Generator) writeBackground(sb *strings.Builder) {
+	if g.style != nil && g.style.Background != "" {
+		sb.WriteString(fmt.Sprintf("style.fill: \"%s\"\n", g.style.Background))
+	}
+}
+
+// writeElementStyle writes the shape and style lines for an element drawn
+// with es. Boundaries are dashed.
+func writeElementStyle(sb *strings.Builder, indent string, es entities.ElementStyle, dashed bool) {
+	if es.Shape != "" {
+		sb.WriteString(fmt.Sprintf("%sshape: %s\n", indent, es.Shape))
+	}
+	sb.WriteString(indent + "style {\n")
+	for _, prop := range []struct{ name, value string }{
+		{"fill", es.Fill}, {"stroke", es.Stroke}, {"font-color", es.FontColor},
+	} {
+		if prop.value != "" {
+			sb.WriteString(fmt.Sprintf("%s  %s: \"%s\"\n", indent, prop.name, prop.value))
+		}
+	}
+	if dashed {
+		sb.WriteString(indent + "  stroke-dash: 3\n")
+	}
+	sb.WriteString(indent + "}\n")
+}
+
+// writeLegend writes a key to the element styles used in the diagram,
+// placed in the bottom-right corner.
+func writeLegend(sb *strings.Builder, style *entities.DiagramStyle, entries []legendEntry) {
+	sb.WriteString("# Legend\n")
+	sb.WriteString("legend: \"Legend\" {\n")
+	sb.WriteString("  near: bottom-right\n")
+	writeElementStyle(sb, "  ", style.Boundary, true)
+	for i, entry := range entries {
+		sb.WriteString(fmt.Sprintf("  item_%d: \"%s\" {\n", i+1, entry.label))
+		writeElementStyle(sb, "    ", entry.style, entry.dashed)
+		sb.WriteString("  }\n")
+	}
+	sb.WriteString("}\n")
+}
+
 // UpdateSystemD2File updates the system's D2 diagram file with current containers.
 // This is called when containers are added/removed to keep the diagram in sync.
 // This is an adapter-level utility method beyond the core DiagramGenerator interface.
@@ -91,6 +91,7 @@ func TestGenerateContainerDiagram(t *testing.T) {
 		"api-gateway",
 		"REST API Gateway",
 		"Go + Gin",
+		`icon: "` + entities.BuiltinIcon("go") + `"`,
 	}
 
 	for _, elem := range expectedElements {
@@ -160,3 +161,176 @@ func contains(s, substr string) bool {
 			return false
 		}())
 }
+
+// TestGeneratorSetIcons tests icon overrides and disabling icons.
+func TestGeneratorSetIcons(t *testing.T) {
+	container, err := entities.NewContainer("api-gateway")
+	if err != nil {
+		t.Fatalf("failed to create container: %v", err)
+	}
+	component, err := entities.NewComponent("auth-handler")
+	if err != nil {
+		t.Fatalf("failed to create component: %v", err)
+	}
+	component.Technology = "JWT"
+	if err := container.AddComponent(component); err != nil {
+		t.Fatalf("failed to add component: %v", err)
+	}
+
+	gen := d2.NewGenerator()
+	gen.SetIcons(entities.NewIconRegistry(map[string]string{"jwt": "https://icons.example.com/jwt.svg"}))
+	result, err := gen.GenerateComponentDiagram(container)
+	if err != nil {
+		t.Fatalf("GenerateComponentDiagram() error = %v", err)
+	}
+	if !contains(result, `icon: "https://icons.example.com/jwt.svg"`) {
+		t.Errorf("GenerateComponentDiagram() missing configured icon:\n%s", result)
+	}
+
+	gen.SetIcons(nil)
+	if result, _ = gen.GenerateComponentDiagram(container); contains(result, "icon:") {
+		t.Errorf("GenerateComponentDiagram() with icons disabled has an icon:\n%s", result)
+	}
+}
+
+// TestGeneratorSetStyle tests that a styling preset gives each level its
+// shape and colors and adds a legend.
+func TestGeneratorSetStyle(t *testing.T) {
+	system, err := entities.NewSystem("payment-system")
+	if err != nil {
+		t.Fatalf("failed to create system: %v", err)
+	}
+	system.KeyUsers = []string{"Customer"}
+	system.ExternalSystems = []string{"Payment Gateway"}
+	container, err := entities.NewContainer("api-gateway")
+	if err != nil {
+		t.Fatalf("failed to create container: %v", err)
+	}
+	if err := system.AddContainer(container); err != nil {
+		t.Fatalf("failed to add container: %v", err)
+	}
+
+	classic, err := entities.LookupDiagramStyle("classic")
+	if err != nil {
+		t.Fatalf("LookupDiagramStyle() error = %v", err)
+	}
+	gen := d2.NewGenerator()
+	gen.SetStyle(classic)
+
+	contextDiagram, err := gen.GenerateSystemContextDiagram(system)
+	if err != nil {
+		t.Fatalf("GenerateSystemContextDiagram() error = %v", err)
+	}
+	for _, want := range []string{"shape: person", `fill: "#08427B"`, `fill: "#1168BD"`, `fill: "#999999"`, "near: bottom-right", `"External System"`} {
+		if !contains(contextDiagram, want) {
+			t.Errorf("GenerateSystemContextDiagram() missing %q:\n%s", want, contextDiagram)
+		}
+	}
+	if contains(contextDiagram, "#E1F5FF") {
+		t.Errorf("GenerateSystemContextDiagram() kept the plain style:\n%s", contextDiagram)
+	}
+
+	containers, err := gen.GenerateContainerDiagram(system)
+	if err != nil {
+		t.Fatalf("GenerateContainerDiagram() error = %v", err)
+	}
+	for _, want := range []string{`fill: "#438DD5"`, "stroke-dash: 3", `"System Boundary"`} {
+		if !contains(containers, want) {
+			t.Errorf("GenerateContainerDiagram() missing %q:\n%s", want, containers)
+		}
+	}
+
+	dark, _ := entities.LookupDiagramStyle("dark")
+	gen.SetStyle(dark)
+	components, err := gen.GenerateComponentDiagram(container)
+	if err != nil {
+		t.Fatalf("GenerateComponentDiagram() error = %v", err)
+	}
+	if !contains(components, `style.fill: "#121212"`) {
+		t.Errorf("GenerateComponentDiagram() missing dark background:\n%s", components)
+	}
+}
+
+// TestGenerateRelationships tests that system relationships are drawn in the
+// context diagram and container relationships in the container diagram.
+func TestGenerateRelationships(t *testing.T) {
+	system, err := entities.NewSystem("shop")
+	if err != nil {
+		t.Fatalf("failed to create system: %v", err)
+	}
+	system.AddRelationship("payments", "Charges orders")
+	api, _ := entities.NewContainer("api")
+	db, _ := entities.NewContainer("db")
+	api.AddRelationship("db", "Stores carts")
+	api.AddRelationship("shop/db", "Reads carts")
+	api.AddRelationship("payments/gateway", "Authorizes cards")
+	for _, c := range []*entities.Container{api, db} {
+		if err := system.AddContainer(c); err != nil {
+			t.Fatalf("failed to add container: %v", err)
+		}
+	}
+
+	gen := d2.NewGenerator()
+	contextDiagram, err := gen.GenerateSystemContextDiagram(system)
+	if err != nil {
+		t.Fatalf("GenerateSystemContextDiagram() error = %v", err)
+	}
+	for _, want := range []string{`related_1: "payments" {`, `shop -> related_1: "Charges orders"`} {
+		if !contains(contextDiagram, want) {
+			t.Errorf("GenerateSystemContextDiagram() missing %q:\n%s", want, contextDiagram)
+		}
+	}
+
+	containers, err := gen.GenerateContainerDiagram(system)
+	if err != nil {
+		t.Fatalf("GenerateContainerDiagram() error = %v", err)
+	}
+	for _, want := range []string{
+		`shop.api -> shop.db: "Stores carts"`,
+		`shop.api -> shop.db: "Reads carts"`,
+		`related_1: "payments/gateway" {`,
+		`shop.api -> related_1: "Authorizes cards"`,
+	} {
+		if !contains(containers, want) {
+			t.Errorf("GenerateContainerDiagram() missing %q:\n%s", want, containers)
+		}
+	}
+	if contains(containers, "add as needed") {
+		t.Errorf("GenerateContainerDiagram() kept the placeholder:\n%s", containers)
+	}
+}
+
+func TestGeneratePeople(t *testing.T) {
+	system, err := entities.NewSystem("shop")
+	if err != nil {
+		t.Fatalf("failed to create system: %v", err)
+	}
+	system.KeyUsers = []string{"Shopper"}
+	customer, _ := entities.NewPerson("Customer")
+	customer.AddRelationship("shop", "Places orders with")
+	admin, _ := entities.NewPerson("Admin")
+	admin.AddRelationship("shop/backoffice", "Manages products in")
+	auditor, _ := entities.NewPerson("Auditor")
+	auditor.AddRelationship("ledger", "Reviews")
+
+	gen := d2.NewGenerator()
+	gen.SetPeople([]*entities.Person{admin, auditor, customer})
+	source, err := gen.GenerateSystemContextDiagram(system)
+	if err != nil {
+		t.Fatalf("GenerateSystemContextDiagram() error = %v", err)
+	}
+	for _, want := range []string{
+		`customer: "Customer" {`,
+		`customer -> shop: "Places orders with"`,
+		`admin -> shop: "Uses"`,
+	} {
+		if !contains(source, want) {
+			t.Errorf("GenerateSystemContextDiagram() missing %q:\n%s", want, source)
+		}
+	}
+	for _, unwanted := range []string{"auditor", "Shopper"} {
+		if contains(source, unwanted) {
+			t.Errorf("GenerateSystemContextDiagram() contains %q:\n%s", unwanted, source)
+		}
+	}
+}
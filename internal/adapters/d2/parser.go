@@ -3,15 +3,25 @@ package d2
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
 	"oss.terrastruct.com/d2/d2graph"
 	"oss.terrastruct.com/d2/d2layouts/d2dagrelayout"
 	"oss.terrastruct.com/d2/d2lib"
+	d2log "oss.terrastruct.com/d2/lib/log"
 	"oss.terrastruct.com/d2/lib/textmeasure"
 )
 
+// Ensure D2Parser implements usecases.D2DiagramParser and
+// usecases.DiagramCompiler interfaces.
+var (
+	_ usecases.D2DiagramParser = (*D2Parser)(nil)
+	_ usecases.DiagramCompiler = (*D2Parser)(nil)
+)
+
 // D2Parser implements the D2Parser port interface using the official D2 library.
 // It parses D2 diagram source code and extracts relationship arrows.
 type D2Parser struct {
@@ -40,6 +50,59 @@ func (p *D2Parser) ParseRelationships(ctx context.Context, d2Source string) ([]e
 		return []entities.D2Relationship{}, nil
 	}
 
+	graph, err := compileGraph(ctx, d2Source)
+	if err != nil {
+		return nil, err
+	}
+
+	if graph == nil {
+		// Valid parse but no graph produced
+		return []entities.D2Relationship{}, nil
+	}
+
+	// Extract relationships from the compiled graph
+	relationships := extractRelationshipsFromGraph(graph)
+
+	return relationships, nil
+}
+
+// ParseShapes returns the dotted paths of the shapes declared in D2 source
+// code, parents before their children, e.g. "backend" and "backend.api".
+// Shapes created by connections are included.
+func (p *D2Parser) ParseShapes(ctx context.Context, d2Source string) ([]string, error) {
+	if strings.TrimSpace(d2Source) == "" {
+		return []string{}, nil
+	}
+
+	graph, err := compileGraph(ctx, d2Source)
+	if err != nil {
+		return nil, err
+	}
+
+	shapes := []string{}
+	if graph == nil {
+		return shapes, nil
+	}
+	for _, obj := range graph.Objects {
+		if id := getNodeID(obj); id != "" {
+			shapes = append(shapes, id)
+		}
+	}
+	return shapes, nil
+}
+
+// CompileDiagram compiles D2 source code, including its layout, without
+// rendering it, so broken diagrams are found without the d2 binary.
+func (p *D2Parser) CompileDiagram(ctx context.Context, d2Source string) error {
+	if strings.TrimSpace(d2Source) == "" {
+		return nil
+	}
+	_, err := compileGraph(ctx, d2Source)
+	return err
+}
+
+// compileGraph compiles D2 source into its graph without rendering it.
+func compileGraph(ctx context.Context, d2Source string) (*d2graph.Graph, error) {
 	// Create minimal compile options with a text ruler for dimension calculation
 	ruler, _ := textmeasure.NewRuler()
 	compileOpts := &d2lib.CompileOptions{
@@ -49,22 +112,17 @@ func (p *D2Parser) ParseRelationships(ctx context.Context, d2Source string) ([]e
 		},
 	}
 
+	// The layout engines log through the context and warn, with a stack
+	// trace, when it carries no logger; their debug output is of no use here
+	ctx = d2log.With(ctx, slog.New(slog.DiscardHandler))
+
 	// Compile D2 source using official library
 	// We only need the graph, not the rendered diagram (pass nil for renderOpts)
 	_, graph, err := d2lib.Compile(ctx, d2Source, compileOpts, nil)
 	if err != nil {
 		return nil, fmt.Errorf("D2 parse error: %w", err)
 	}
-
-	if graph == nil {
-		// Valid parse but no graph produced
-		return []entities.D2Relationship{}, nil
-	}
-
-	// Extract relationships from the compiled graph
-	relationships := extractRelationshipsFromGraph(graph)
-
-	return relationships, nil
+	return graph, nil
 }
 
 // extractRelationshipsFromGraph walks the D2 graph and extracts relationships (edges).
@@ -2,6 +2,8 @@ package d2
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -309,3 +311,49 @@ api -> db: Queries user data and session info
 		t.Errorf("Label = %q, whitespace not preserved correctly", relationships[0].Label)
 	}
 }
+
+// TestD2Parser_ParseShapes verifies shapes are listed with their nesting.
+func TestD2Parser_ParseShapes(t *testing.T) {
+	d2Source := `
+direction: right
+user: "User" { shape: person }
+backend: {
+  style.fill: "#E3F2FD"
+  api-server
+}
+backend.api-server -> database: Queries
+`
+
+	shapes, err := NewD2Parser().ParseShapes(context.Background(), d2Source)
+	if err != nil {
+		t.Fatalf("ParseShapes() error = %v", err)
+	}
+	want := []string{"user", "backend", "backend.api-server", "database"}
+	if !slices.Equal(shapes, want) {
+		t.Errorf("shapes = %q, want %q", shapes, want)
+	}
+
+	if _, err := NewD2Parser().ParseShapes(context.Background(), "a -> {"); err == nil {
+		t.Error("expected an error for invalid syntax")
+	}
+}
+
+// TestD2Parser_CompileDiagram verifies that compile errors carry their position.
+func TestD2Parser_CompileDiagram(t *testing.T) {
+	parser := NewD2Parser()
+	ctx := context.Background()
+
+	for _, source := range []string{"", "api -> db: Reads\n", "api: {\n  shape: cylinder\n}\n"} {
+		if err := parser.CompileDiagram(ctx, source); err != nil {
+			t.Errorf("CompileDiagram(%q) error = %v", source, err)
+		}
+	}
+
+	err := parser.CompileDiagram(ctx, "api -> db\napi: {\n  shape: not-a-shape\n}\n")
+	if err == nil {
+		t.Fatal("expected a compile error")
+	}
+	if !strings.Contains(err.Error(), "3:") {
+		t.Errorf("error %q lacks the line of the problem", err)
+	}
+}
@@ -40,6 +40,18 @@ func (r *Renderer) IsAvailable() bool {
 	return r.d2Path != ""
 }
 
+// Version returns the version reported by `d2 --version`, e.g. "v0.7.0".
+func (r *Renderer) Version(ctx context.Context) (string, error) {
+	if !r.IsAvailable() {
+		return "", fmt.Errorf("d2 binary not found in PATH")
+	}
+	out, err := exec.CommandContext(ctx, r.d2Path, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("d2 --version failed: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
 // RenderDiagram compiles D2 source code to SVG.
 // Returns SVG content or error if d2 binary missing or compilation fails.
 // Uses a default timeout of 30 seconds.
@@ -0,0 +1,94 @@
+package encoding
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// Corpus file names.
+const (
+	CorpusChunksFile   = "chunks.jsonl"   // {"id", "text"} per chunk, the input of embedding
+	CorpusMetadataFile = "metadata.jsonl" // Metadata per chunk, joined on "id"
+)
+
+// Ensure CorpusWriter implements usecases.CorpusWriter interface.
+var _ usecases.CorpusWriter = (*CorpusWriter)(nil)
+
+// CorpusWriter writes a RAG corpus as two JSON-lines files: the text of the
+// chunks and a metadata sidecar, one line per chunk in the same order. Most
+// vector store loaders read JSON lines directly.
+type CorpusWriter struct{}
+
+// NewCorpusWriter creates a new CorpusWriter instance.
+func NewCorpusWriter() *CorpusWriter {
+	return &CorpusWriter{}
+}
+
+// corpusText is a line of the chunks file.
+type corpusText struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+// WriteCorpus writes <outputDir>/chunks.jsonl and <outputDir>/metadata.jsonl
+// and returns their paths.
+func (w *CorpusWriter) WriteCorpus(ctx context.Context, outputDir string, chunks []usecases.CorpusChunk) ([]string, error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	texts := make([]any, len(chunks))
+	metadata := make([]any, len(chunks))
+	for i, chunk := range chunks {
+		texts[i] = corpusText{ID: chunk.ID, Text: chunk.Text}
+		metadata[i] = chunk
+	}
+
+	var paths []string
+	for _, file := range []struct {
+		name  string
+		lines []any
+	}{{CorpusChunksFile, texts}, {CorpusMetadataFile, metadata}} {
+		if err := ctx.Err(); err != nil {
+			return paths, err
+		}
+		path := filepath.Join(outputDir, file.name)
+		if err := writeJSONLines(path, file.lines); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// writeJSONLines writes each value as one line of JSON to path.
+func writeJSONLines(path string, values []any) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close %s: %w", path, closeErr)
+		}
+	}()
+
+	buf := bufio.NewWriter(f)
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	for _, value := range values {
+		if err := encoder.Encode(value); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
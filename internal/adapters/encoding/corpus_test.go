@@ -0,0 +1,57 @@
+package encoding
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+func TestCorpusWriterWriteCorpus(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "rag")
+	chunks := []usecases.CorpusChunk{
+		{ID: "payments", Type: "system", Name: "Payments", Breadcrumbs: []string{"shop", "Payments"}, Text: "# Payments (system)\n"},
+		{ID: "payments/api", Type: "container", Name: "API", Breadcrumbs: []string{"shop", "Payments", "API"}, Technology: "Go",
+			Relationships: []usecases.CorpusRelationship{{Direction: "outgoing", ID: "stripe", Description: "charges <cards>"}}, Text: "# API (container)\n"},
+	}
+
+	paths, err := NewCorpusWriter().WriteCorpus(context.Background(), dir, chunks)
+	if err != nil {
+		t.Fatalf("WriteCorpus failed: %v", err)
+	}
+	if len(paths) != 2 || filepath.Base(paths[0]) != CorpusChunksFile || filepath.Base(paths[1]) != CorpusMetadataFile {
+		t.Fatalf("unexpected paths: %v", paths)
+	}
+
+	texts, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":"payments","text":"# Payments (system)\n"}` + "\n" + `{"id":"payments/api","text":"# API (container)\n"}` + "\n"
+	if string(texts) != want {
+		t.Errorf("chunks.jsonl =\n%s\nwant\n%s", texts, want)
+	}
+
+	metadata, err := os.ReadFile(paths[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(metadata)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 metadata lines, got %d", len(lines))
+	}
+	var got usecases.CorpusChunk
+	if err := json.Unmarshal([]byte(lines[1]), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "payments/api" || got.Technology != "Go" || len(got.Relationships) != 1 || got.Relationships[0].Description != "charges <cards>" {
+		t.Errorf("metadata = %+v", got)
+	}
+	if strings.Contains(lines[1], `"text"`) {
+		t.Errorf("metadata should not repeat the text: %s", lines[1])
+	}
+}
@@ -0,0 +1,65 @@
+package encoding
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// Ensure CSVWriter implements usecases.InventoryWriter interface.
+var _ usecases.InventoryWriter = (*CSVWriter)(nil)
+
+// CSVWriter writes inventory sheets as RFC 4180 CSV files, one per sheet.
+type CSVWriter struct{}
+
+// NewCSVWriter creates a new CSVWriter instance.
+func NewCSVWriter() *CSVWriter {
+	return &CSVWriter{}
+}
+
+// WriteInventory writes each sheet to <outputDir>/<name>.csv with its header
+// as the first record, and returns the paths written in sheet order.
+func (w *CSVWriter) WriteInventory(ctx context.Context, outputDir string, sheets []usecases.InventorySheet) ([]string, error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	paths := make([]string, 0, len(sheets))
+	for _, sheet := range sheets {
+		if err := ctx.Err(); err != nil {
+			return paths, err
+		}
+		path := filepath.Join(outputDir, sheet.Name+".csv")
+		if err := writeCSV(path, sheet); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// writeCSV writes a single sheet to path.
+func writeCSV(path string, sheet usecases.InventorySheet) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close %s: %w", path, closeErr)
+		}
+	}()
+
+	cw := csv.NewWriter(f)
+	if err := cw.Write(sheet.Header); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := cw.WriteAll(sheet.Rows); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
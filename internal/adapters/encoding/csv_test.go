@@ -0,0 +1,48 @@
+package encoding
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+func TestCSVWriterWriteInventory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "inventory")
+	sheets := []usecases.InventorySheet{
+		{Name: "systems", Header: []string{"id", "name", "description"}, Rows: [][]string{
+			{"payments", "Payments", `Takes "card", bank and wallet payments`},
+			{"search", "Search", "Multi-line\ndescription"},
+		}},
+		{Name: "relationships", Header: []string{"source", "target"}},
+	}
+
+	paths, err := NewCSVWriter().WriteInventory(context.Background(), dir, sheets)
+	if err != nil {
+		t.Fatalf("WriteInventory failed: %v", err)
+	}
+	if len(paths) != 2 || filepath.Base(paths[0]) != "systems.csv" || filepath.Base(paths[1]) != "relationships.csv" {
+		t.Fatalf("unexpected paths: %v", paths)
+	}
+
+	got, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "id,name,description\n" +
+		"payments,Payments,\"Takes \"\"card\"\", bank and wallet payments\"\n" +
+		"search,Search,\"Multi-line\ndescription\"\n"
+	if string(got) != want {
+		t.Errorf("systems.csv =\n%s\nwant\n%s", got, want)
+	}
+
+	got, err = os.ReadFile(paths[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "source,target\n" {
+		t.Errorf("relationships.csv = %q, want header only", got)
+	}
+}
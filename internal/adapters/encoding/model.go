@@ -0,0 +1,62 @@
+package encoding
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	toon "github.com/toon-format/toon-go"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// Formats of EncodeModel.
+const (
+	ModelFormatJSON = "json"
+	ModelFormatTOON = "toon"
+	ModelFormatYAML = "yaml"
+)
+
+// ModelFormats lists the formats EncodeModel writes.
+var ModelFormats = []string{ModelFormatJSON, ModelFormatTOON, ModelFormatYAML}
+
+// EncodeModel serializes a model bundle as JSON, TOON or YAML. Every format
+// uses the field names of the JSON form, so one JSON Schema describes them
+// all. TOON and YAML list object keys in sorted order.
+func EncodeModel(bundle *entities.ModelBundle, format string) ([]byte, error) {
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode model: %w", err)
+	}
+	if format == ModelFormatJSON {
+		return append(data, '\n'), nil
+	}
+
+	// TOON and YAML encode structs by their Go field names, so the bundle is
+	// converted to its JSON form first.
+	var document any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to encode model: %w", err)
+	}
+	switch format {
+	case ModelFormatTOON:
+		out, err := toon.Marshal(document, toon.WithLengthMarkers(true))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode model as TOON: %w", err)
+		}
+		return append(out, '\n'), nil
+	case ModelFormatYAML:
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(document); err != nil {
+			return nil, fmt.Errorf("failed to encode model as YAML: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode model as YAML: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unsupported model format %q (supported: json, toon, yaml)", format)
+}
@@ -0,0 +1,79 @@
+package encoding
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func testModelBundle() *entities.ModelBundle {
+	return &entities.ModelBundle{
+		Schema:        entities.ModelBundleSchema,
+		SchemaVersion: entities.ModelBundleSchemaVersion,
+		Project:       entities.BundleProject{Name: "Shop"},
+		Systems: []entities.BundleSystem{{
+			BundleElement: entities.BundleElement{ID: "shop", Name: "Shop", Tags: []string{"core"}},
+			Containers: []entities.BundleContainer{{
+				BundleElement: entities.BundleElement{ID: "shop/api", Name: "API"},
+				Technology:    "Go",
+				Components:    []entities.BundleComponent{},
+			}},
+		}},
+		Relationships: []entities.Relationship{{ID: "1a2b3c4d", Source: "shop/api", Target: "shop/db", Label: "Stores orders in"}},
+		Diagrams:      []entities.BundleDiagram{},
+		Graph: entities.BundleGraph{
+			Nodes: []entities.BundleGraphNode{{ID: "shop", Type: "system", Level: 1}},
+			Edges: []entities.BundleGraphEdge{},
+		},
+	}
+}
+
+func TestEncodeModelFormatsShareFieldNames(t *testing.T) {
+	bundle := testModelBundle()
+
+	data, err := EncodeModel(bundle, ModelFormatJSON)
+	if err != nil {
+		t.Fatalf("EncodeModel(json) failed: %v", err)
+	}
+	var fromJSON map[string]any
+	if err := json.Unmarshal(data, &fromJSON); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	data, err = EncodeModel(bundle, ModelFormatYAML)
+	if err != nil {
+		t.Fatalf("EncodeModel(yaml) failed: %v", err)
+	}
+	var fromYAML map[string]any
+	if err := yaml.Unmarshal(data, &fromYAML); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, data)
+	}
+	for key := range fromJSON {
+		if _, ok := fromYAML[key]; !ok {
+			t.Errorf("YAML lacks key %q:\n%s", key, data)
+		}
+	}
+	if fromYAML["schema_version"] != entities.ModelBundleSchemaVersion {
+		t.Errorf("YAML schema_version = %v", fromYAML["schema_version"])
+	}
+
+	data, err = EncodeModel(bundle, ModelFormatTOON)
+	if err != nil {
+		t.Fatalf("EncodeModel(toon) failed: %v", err)
+	}
+	for _, want := range []string{"schema_version:", "technology: Go", `"$schema"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("TOON lacks %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestEncodeModelUnknownFormat(t *testing.T) {
+	if _, err := EncodeModel(testModelBundle(), "xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -1,5 +1,6 @@
 // Package encoding provides serialization adapters for loko.
-// It implements OutputEncoder for JSON and TOON (Token-Optimized Object Notation) formats.
+// It implements OutputEncoder for JSON and TOON (Token-Optimized Object Notation) formats
+// and InventoryWriter for CSV inventory exports.
 package encoding
 
 import (
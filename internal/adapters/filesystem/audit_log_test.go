@@ -0,0 +1,52 @@
+package filesystem
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestAuditLog_AppendAndEntries(t *testing.T) {
+	ctx := context.Background()
+	log := NewAuditLog(t.TempDir())
+
+	entries, err := log.Entries(ctx)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("expected empty log, got %d entries, err %v", len(entries), err)
+	}
+
+	first := &entities.AuditEntry{
+		Time:      time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC),
+		Source:    entities.AuditSourceMCP,
+		Actor:     "claude-code 1.0",
+		Action:    "create_system",
+		Arguments: map[string]any{"name": "Payments"},
+		Changes:   []entities.FileChange{{Path: "src/payments/system.md", Op: entities.FileAdded, Diff: "+# Payments\n"}},
+	}
+	second := &entities.AuditEntry{Source: entities.AuditSourceAPI, Actor: "ci", Action: "POST /api/v1/build", Error: "boom"}
+	for _, entry := range []*entities.AuditEntry{first, second} {
+		if err := log.Append(ctx, entry); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	entries, err = log.Entries(ctx)
+	if err != nil {
+		t.Fatalf("Entries failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	got := entries[0]
+	if !got.Time.Equal(first.Time) || got.Action != "create_system" || got.Arguments["name"] != "Payments" {
+		t.Errorf("first entry = %+v", got)
+	}
+	if len(got.Changes) != 1 || got.Changes[0].Op != entities.FileAdded || got.Changes[0].Diff != "+# Payments\n" {
+		t.Errorf("changes = %+v", got.Changes)
+	}
+	if entries[1].Error != "boom" || entries[1].Source != entities.AuditSourceAPI {
+		t.Errorf("second entry = %+v", entries[1])
+	}
+}
@@ -0,0 +1,160 @@
+package filesystem
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// BuildCacheDir is the build cache's directory relative to the project root.
+const BuildCacheDir = ".loko/cache"
+
+// Ensure BuildCache implements usecases.BuildCache interface.
+var _ usecases.BuildCache = (*BuildCache)(nil)
+
+// BuildCache implements the BuildCache port as files under .loko/cache/:
+// diagrams/<key>.svg for rendered diagrams and pages/<dir>.json for the page
+// keys of each output directory. A VERSION file records the loko version that
+// wrote the cache; a different version empties it on first use.
+type BuildCache struct {
+	dir     string
+	version string
+	once    sync.Once
+}
+
+// NewBuildCache creates the build cache of the project at projectRoot for
+// the given loko version.
+func NewBuildCache(projectRoot, version string) *BuildCache {
+	return &BuildCache{dir: filepath.Join(projectRoot, BuildCacheDir), version: version}
+}
+
+// open empties a cache written by another version of loko and records this one.
+// Failures leave the cache unusable for writes, which only costs cache misses.
+func (c *BuildCache) open() {
+	c.once.Do(func() {
+		versionFile := filepath.Join(c.dir, "VERSION")
+		if data, err := os.ReadFile(versionFile); err == nil && string(data) == c.version {
+			return
+		}
+		_ = os.RemoveAll(filepath.Join(c.dir, "diagrams"))
+		_ = os.RemoveAll(filepath.Join(c.dir, "pages"))
+		if err := os.MkdirAll(c.dir, 0755); err == nil {
+			_ = writeFileAtomic(versionFile, []byte(c.version))
+		}
+	})
+}
+
+// Diagram returns the SVG cached for key.
+func (c *BuildCache) Diagram(_ context.Context, key string) (string, bool) {
+	c.open()
+	data, err := os.ReadFile(c.diagramPath(key))
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// PutDiagram caches the SVG rendered for key.
+func (c *BuildCache) PutDiagram(_ context.Context, key, svg string) error {
+	c.open()
+	path := c.diagramPath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create diagram cache: %w", err)
+	}
+	if err := writeFileAtomic(path, []byte(svg)); err != nil {
+		return fmt.Errorf("failed to cache diagram: %w", err)
+	}
+	return nil
+}
+
+// Pages returns the page keys last recorded for outputDir. A missing or
+// unreadable record has none.
+func (c *BuildCache) Pages(_ context.Context, outputDir string) map[string]string {
+	c.open()
+	data, err := os.ReadFile(c.pagesPath(outputDir))
+	if err != nil {
+		return nil
+	}
+	var pages map[string]string
+	if err := json.Unmarshal(data, &pages); err != nil {
+		return nil
+	}
+	return pages
+}
+
+// PutPages replaces the page keys recorded for outputDir.
+func (c *BuildCache) PutPages(_ context.Context, outputDir string, pages map[string]string) error {
+	c.open()
+	data, err := json.MarshalIndent(pages, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode page cache: %w", err)
+	}
+	path := c.pagesPath(outputDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create page cache: %w", err)
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to write page cache: %w", err)
+	}
+	return nil
+}
+
+// Clear removes the cache directory.
+func (c *BuildCache) Clear(_ context.Context) error {
+	if err := os.RemoveAll(c.dir); err != nil {
+		return fmt.Errorf("failed to clear build cache: %w", err)
+	}
+	c.once = sync.Once{}
+	return nil
+}
+
+// diagramPath returns the file of the diagram cached for key. Keys are
+// hex-encoded hashes, so they are safe file names.
+func (c *BuildCache) diagramPath(key string) string {
+	return filepath.Join(c.dir, "diagrams", filepath.Base(key)+".svg")
+}
+
+// pagesPath returns the file of the page keys of outputDir, named by a hash
+// of its absolute path so every output directory has its own.
+func (c *BuildCache) pagesPath(outputDir string) string {
+	if abs, err := filepath.Abs(outputDir); err == nil {
+		outputDir = abs
+	}
+	sum := sha256.Sum256([]byte(outputDir))
+	return filepath.Join(c.dir, "pages", hex.EncodeToString(sum[:8])+".json")
+}
+
+// writeFileAtomic writes data to a temporary file in the directory of path
+// and renames it over path, so concurrent readers never see a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
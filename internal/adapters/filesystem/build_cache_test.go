@@ -0,0 +1,55 @@
+package filesystem
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildCache(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	outputDir := filepath.Join(root, "dist")
+
+	cache := NewBuildCache(root, "v1")
+	if _, ok := cache.Diagram(ctx, "abc"); ok {
+		t.Fatal("empty cache has a diagram")
+	}
+	if err := cache.PutDiagram(ctx, "abc", "<svg/>"); err != nil {
+		t.Fatalf("PutDiagram failed: %v", err)
+	}
+	if err := cache.PutPages(ctx, outputDir, map[string]string{"systems/shop.html": "k1"}); err != nil {
+		t.Fatalf("PutPages failed: %v", err)
+	}
+
+	// Entries persist across runs of the same version
+	cache = NewBuildCache(root, "v1")
+	if svg, ok := cache.Diagram(ctx, "abc"); !ok || svg != "<svg/>" {
+		t.Errorf("Diagram = %q, %v", svg, ok)
+	}
+	if pages := cache.Pages(ctx, outputDir); pages["systems/shop.html"] != "k1" {
+		t.Errorf("Pages = %v", pages)
+	}
+	if pages := cache.Pages(ctx, filepath.Join(root, "site")); len(pages) != 0 {
+		t.Errorf("Pages of another output directory = %v", pages)
+	}
+
+	// Another version of loko starts from an empty cache
+	cache = NewBuildCache(root, "v2")
+	if _, ok := cache.Diagram(ctx, "abc"); ok {
+		t.Error("diagram survived a version change")
+	}
+	if pages := cache.Pages(ctx, outputDir); len(pages) != 0 {
+		t.Errorf("pages survived a version change: %v", pages)
+	}
+
+	if err := cache.PutDiagram(ctx, "def", "<svg/>"); err != nil {
+		t.Fatalf("PutDiagram failed: %v", err)
+	}
+	if err := cache.Clear(ctx); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if _, ok := cache.Diagram(ctx, "def"); ok {
+		t.Error("diagram survived Clear")
+	}
+}
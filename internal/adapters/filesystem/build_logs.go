@@ -0,0 +1,184 @@
+package filesystem
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// BuildLogDir is the directory of the build logs relative to the project root.
+const BuildLogDir = ".loko/builds"
+
+// MaxBuildLogs is how many build logs are kept; creating a log removes the
+// oldest ones beyond it.
+const MaxBuildLogs = 50
+
+// buildLogExt is the extension of a build log file.
+const buildLogExt = ".jsonl"
+
+// Ensure BuildLogStore implements usecases.BuildLogs interface.
+var _ usecases.BuildLogs = (*BuildLogStore)(nil)
+
+// BuildLogStore implements the BuildLogs port as one JSON-lines file per
+// build in .loko/builds/. Build IDs are the date followed by a sequence
+// number, e.g. 20261016-0003, so they sort in creation order; files are
+// created exclusively, so concurrent loko processes never share an ID.
+type BuildLogStore struct {
+	dir string
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// NewBuildLogStore creates the build log store of the project at projectRoot.
+func NewBuildLogStore(projectRoot string) *BuildLogStore {
+	return &BuildLogStore{dir: filepath.Join(projectRoot, BuildLogDir), now: time.Now}
+}
+
+// Create creates an empty log under the next free ID of the day.
+func (s *BuildLogStore) Create(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create build log directory: %w", err)
+	}
+	ids, err := s.List(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	prefix := s.now().Format("20060102") + "-"
+	seq := 0
+	for _, id := range ids {
+		if n, err := strconv.Atoi(strings.TrimPrefix(id, prefix)); err == nil && strings.HasPrefix(id, prefix) && n > seq {
+			seq = n
+		}
+	}
+	for {
+		seq++
+		id := fmt.Sprintf("%s%04d", prefix, seq)
+		f, err := os.OpenFile(s.path(id), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to create build log: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return "", fmt.Errorf("failed to create build log: %w", err)
+		}
+		s.prune(append(ids, id))
+		return id, nil
+	}
+}
+
+// Append writes entry as a single JSON line at the end of the log of build id.
+func (s *BuildLogStore) Append(_ context.Context, id string, entry entities.BuildLogEntry) error {
+	if !validBuildID(id) {
+		return fmt.Errorf("%w: %q", entities.ErrBuildLogNotFound, id)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode build log entry: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path(id), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w: %q", entities.ErrBuildLogNotFound, id)
+		}
+		return fmt.Errorf("failed to open build log: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write build log: %w", err)
+	}
+	return f.Close()
+}
+
+// Entries reads the log of build id, oldest entry first.
+func (s *BuildLogStore) Entries(_ context.Context, id string) ([]entities.BuildLogEntry, error) {
+	if !validBuildID(id) {
+		return nil, fmt.Errorf("%w: %q", entities.ErrBuildLogNotFound, id)
+	}
+	f, err := os.Open(s.path(id))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %q", entities.ErrBuildLogNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to open build log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []entities.BuildLogEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry entities.BuildLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid build log entry on line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read build log: %w", err)
+	}
+	return entries, nil
+}
+
+// List returns the IDs of the stored logs, oldest first.
+func (s *BuildLogStore) List(_ context.Context) ([]string, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read build logs: %w", err)
+	}
+	var ids []string
+	for _, file := range files {
+		if id, ok := strings.CutSuffix(file.Name(), buildLogExt); ok && !file.IsDir() {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// prune removes the oldest of ids beyond MaxBuildLogs. Failures are ignored:
+// an extra log does no harm.
+func (s *BuildLogStore) prune(ids []string) {
+	slices.Sort(ids)
+	for len(ids) > MaxBuildLogs {
+		_ = os.Remove(s.path(ids[0]))
+		ids = ids[1:]
+	}
+}
+
+// path returns the file of the log of build id.
+func (s *BuildLogStore) path(id string) string {
+	return filepath.Join(s.dir, id+buildLogExt)
+}
+
+// validBuildID reports whether id can name a log file in the store.
+func validBuildID(id string) bool {
+	return id != "" && !strings.ContainsAny(id, `/\`) && !strings.Contains(id, "..")
+}
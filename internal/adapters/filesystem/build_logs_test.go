@@ -0,0 +1,101 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestBuildLogStore_CreateAppendEntries(t *testing.T) {
+	ctx := context.Background()
+	store := NewBuildLogStore(t.TempDir())
+	store.now = func() time.Time { return time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC) }
+
+	if ids, err := store.List(ctx); err != nil || len(ids) != 0 {
+		t.Fatalf("expected no logs, got %v, err %v", ids, err)
+	}
+
+	first, err := store.Create(ctx)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	second, err := store.Create(ctx)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if first != "20261016-0001" || second != "20261016-0002" {
+		t.Errorf("IDs = %q, %q", first, second)
+	}
+
+	entry := entities.BuildLogEntry{Level: entities.BuildLogProgress, Step: "Rendering diagrams", Current: 1, Total: 3, Message: "api.d2"}
+	if err := store.Append(ctx, second, entry); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if err := store.Append(ctx, second, entities.BuildLogEntry{Level: entities.BuildLogError, Message: "boom"}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	entries, err := store.Entries(ctx, second)
+	if err != nil {
+		t.Fatalf("Entries failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0] != entry || entries[1].Message != "boom" {
+		t.Errorf("entries = %+v", entries)
+	}
+	if entries, err := store.Entries(ctx, first); err != nil || len(entries) != 0 {
+		t.Errorf("empty log = %+v, %v", entries, err)
+	}
+
+	ids, err := store.List(ctx)
+	if err != nil || len(ids) != 2 || ids[0] != first || ids[1] != second {
+		t.Errorf("List = %v, %v", ids, err)
+	}
+}
+
+func TestBuildLogStore_NotFound(t *testing.T) {
+	ctx := context.Background()
+	store := NewBuildLogStore(t.TempDir())
+
+	for _, id := range []string{"20261016-0001", "../config", ""} {
+		if _, err := store.Entries(ctx, id); !errors.Is(err, entities.ErrBuildLogNotFound) {
+			t.Errorf("Entries(%q) error = %v", id, err)
+		}
+		if err := store.Append(ctx, id, entities.BuildLogEntry{}); !errors.Is(err, entities.ErrBuildLogNotFound) {
+			t.Errorf("Append(%q) error = %v", id, err)
+		}
+	}
+}
+
+func TestBuildLogStore_Prune(t *testing.T) {
+	ctx := context.Background()
+	store := NewBuildLogStore(t.TempDir())
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return day }
+
+	for i := range MaxBuildLogs + 2 {
+		if i == MaxBuildLogs {
+			day = day.AddDate(0, 0, 1)
+		}
+		if _, err := store.Create(ctx); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	ids, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(ids) != MaxBuildLogs {
+		t.Fatalf("kept %d logs, want %d", len(ids), MaxBuildLogs)
+	}
+	if want := fmt.Sprintf("20261001-%04d", 3); ids[0] != want {
+		t.Errorf("oldest kept = %q, want %q", ids[0], want)
+	}
+	if ids[len(ids)-1] != "20261002-0002" {
+		t.Errorf("newest = %q", ids[len(ids)-1])
+	}
+}
@@ -2,7 +2,10 @@ package filesystem
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
@@ -16,12 +19,17 @@ func loadConfig(configPath string) (*entities.ProjectConfig, error) {
 }
 
 // loadConfigWithName loads the loko.toml configuration file and extracts the project name.
-// If the file doesn't exist, returns default configuration.
+// If the file doesn't exist, returns default configuration. LOKO_* environment
+// variables override the settings of either.
 func loadConfigWithName(configPath string) (*entities.ProjectConfig, string, error) {
 	// Check if config file exists
 	if _, err := os.Stat(configPath); err != nil {
 		// Return default config if file doesn't exist
-		return entities.DefaultProjectConfig(), "", nil
+		config := entities.DefaultProjectConfig()
+		if err := entities.ApplyConfigEnv(config, os.LookupEnv); err != nil {
+			return nil, "", err
+		}
+		return config, "", nil
 	}
 
 	// Read config file
@@ -32,9 +40,13 @@ func loadConfigWithName(configPath string) (*entities.ProjectConfig, string, err
 
 	// Parse TOML (simple parser for now)
 	config := entities.DefaultProjectConfig()
+	config.SchemaVersion = entities.LegacySchemaVersion
 	projectName := ""
 	if err := parseTomlWithName(string(content), config, &projectName); err != nil {
-		return nil, "", fmt.Errorf("failed to parse config: %w", err)
+		return nil, "", fmt.Errorf("%w: %w", entities.ErrInvalidConfig, err)
+	}
+	if err := entities.ApplyConfigEnv(config, os.LookupEnv); err != nil {
+		return nil, "", err
 	}
 
 	return config, projectName, nil
@@ -65,6 +77,7 @@ func saveConfigWithProject(configPath string, project *entities.Project) error {
 // This is a minimal parser that handles the loko.toml format.
 func parseTomlWithName(content string, config *entities.ProjectConfig, projectName *string) error {
 	lines := strings.Split(content, "\n")
+	section := ""
 
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -74,8 +87,14 @@ func parseTomlWithName(content string, config *entities.ProjectConfig, projectNa
 			continue
 		}
 
-		// Skip section headers for now
+		// Keys are unique across sections, except in [icons] where every key
+		// is a technology name, [relationship_types] where every key is a
+		// relationship kind, [site.layouts] where every key selects pages and
+		// [naming.domain_prefixes] where every key is a domain. Each
+		// [archetypes.<name>] section holds the keys of one archetype, and
+		// [relationship_descriptions] keys are read within their section.
 		if strings.HasPrefix(line, "[") {
+			section = strings.TrimSpace(strings.Trim(line, "[]"))
 			continue
 		}
 
@@ -86,8 +105,45 @@ func parseTomlWithName(content string, config *entities.ProjectConfig, projectNa
 		}
 
 		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-		value = strings.Trim(value, "\"'")
+		rawValue := strings.TrimSpace(parts[1])
+		value := strings.Trim(rawValue, "\"'")
+
+		if section == "icons" {
+			if config.TechnologyIcons == nil {
+				config.TechnologyIcons = make(map[string]string)
+			}
+			config.TechnologyIcons[parseTomlString(key)] = parseTomlString(rawValue)
+			continue
+		}
+		if section == "site.layouts" {
+			if config.PageLayouts == nil {
+				config.PageLayouts = make(map[string]string)
+			}
+			config.PageLayouts[parseTomlString(key)] = parseTomlString(rawValue)
+			continue
+		}
+		if section == "naming.domain_prefixes" {
+			if config.NamingDomainPrefixes == nil {
+				config.NamingDomainPrefixes = make(map[string]string)
+			}
+			config.NamingDomainPrefixes[parseTomlString(key)] = parseTomlString(rawValue)
+			continue
+		}
+		if name, ok := strings.CutPrefix(section, "archetypes."); ok {
+			parseArchetypeKey(config, parseTomlString(name), key, rawValue)
+			continue
+		}
+		if section == "relationship_descriptions" {
+			parseRelDescriptionKey(config, key, rawValue)
+			continue
+		}
+		if section == "relationship_types" {
+			if config.RelationshipKinds == nil {
+				config.RelationshipKinds = make(map[string]string)
+			}
+			config.RelationshipKinds[parseTomlString(key)] = parseTomlString(rawValue)
+			continue
+		}
 
 		// Extract project name if present
 		if key == "name" && projectName != nil {
@@ -96,8 +152,14 @@ func parseTomlWithName(content string, config *entities.ProjectConfig, projectNa
 
 		// Map to config fields
 		switch key {
+		case "schema_version":
+			if n, err := parseInt(value); err == nil {
+				config.SchemaVersion = n
+			}
 		case "source":
 			config.SourceDir = value
+		case "source_dirs":
+			config.SourceDirs = parseTomlStringArray(rawValue)
 		case "output":
 			config.OutputDir = value
 		case "theme":
@@ -106,6 +168,12 @@ func parseTomlWithName(content string, config *entities.ProjectConfig, projectNa
 			config.D2Layout = value
 		case "cache":
 			config.D2Cache = value == "true"
+		case "style":
+			config.D2Style = value
+		case "legend":
+			config.DiagramLegend = value == "true"
+		case "metadata_footer":
+			config.DiagramFooter = value == "true"
 		case "parallel":
 			config.Parallel = value == "true"
 		case "max_workers":
@@ -113,6 +181,8 @@ func parseTomlWithName(content string, config *entities.ProjectConfig, projectNa
 			if n, err := parseInt(value); err == nil {
 				config.MaxWorkers = n
 			}
+		case "minify":
+			config.Minify = value == "true"
 		case "serve_port":
 			if n, err := parseInt(value); err == nil {
 				config.ServePort = n
@@ -123,6 +193,80 @@ func parseTomlWithName(content string, config *entities.ProjectConfig, projectNa
 			}
 		case "hot_reload":
 			config.HotReload = value == "true"
+		case "head":
+			config.CustomHead = parseTomlString(rawValue)
+		case "head_file":
+			config.CustomHeadFile = value
+		case "footer":
+			config.CustomFooter = parseTomlString(rawValue)
+		case "footer_file":
+			config.CustomFooterFile = value
+		case "analytics":
+			config.AnalyticsProvider = value
+		case "analytics_id":
+			config.AnalyticsID = value
+		case "custom_fields":
+			config.CustomFields = parseTomlStringArray(rawValue)
+		case "base_path":
+			config.BasePath = value
+		case "pretty_urls":
+			config.PrettyURLs = value == "true"
+		case "url_template":
+			config.IssueURLTemplate = value
+		case "tracker":
+			config.IssueTracker = value
+		case "tracker_url":
+			config.IssueTrackerURL = value
+		case "recipients_file":
+			config.RecipientsFile = value
+		case "remove_tags":
+			config.RedactTags = parseTomlStringArray(rawValue)
+		case "strip_fields":
+			config.RedactFields = parseTomlStringArray(rawValue)
+		case "mask_patterns":
+			config.RedactPatterns = parseTomlStringArray(rawValue)
+		case "mask":
+			config.RedactMask = parseTomlString(rawValue)
+		case "mcp_role":
+			config.MCPRole = value
+		case "mcp_allow":
+			config.MCPAllowTools = parseTomlStringArray(rawValue)
+		case "mcp_deny":
+			config.MCPDenyTools = parseTomlStringArray(rawValue)
+		case "auto_commit":
+			config.GitAutoCommit = value == "true"
+		case "record_usage":
+			config.RecordUsage = value == "true"
+		case "renderer":
+			config.PluginRenderer = value
+		case "pre_build":
+			config.PreBuildHooks = parseTomlCommands(rawValue)
+		case "post_build":
+			config.PostBuildHooks = parseTomlCommands(rawValue)
+		case "pre_validate":
+			config.PreValidateHooks = parseTomlCommands(rawValue)
+		case "require_mapping":
+			config.RequireDeployment = value == "true"
+		case "targets":
+			config.DeploymentTargets = parseTomlStringArray(rawValue)
+		case "currency":
+			config.CostCurrency = value
+		case "require_approval":
+			config.RequireApproval = value == "true"
+		case "id_case":
+			config.NamingIDCase = value
+		case "max_name_length":
+			if n, err := parseInt(value); err == nil {
+				config.NamingMaxNameLength = n
+			}
+		case "technology_separator":
+			config.NamingTechSeparator = parseTomlString(rawValue)
+		case "webhook_url":
+			config.NotifyWebhookURL = value
+		case "smtp_host":
+			config.NotifySMTPHost = value
+		case "email_from":
+			config.NotifyEmailFrom = value
 		}
 	}
 
@@ -133,6 +277,10 @@ func parseTomlWithName(content string, config *entities.ProjectConfig, projectNa
 func generateTomlWithProject(project *entities.Project) string {
 	var sb strings.Builder
 
+	if project.Config.SchemaVersion > 0 {
+		sb.WriteString(fmt.Sprintf("schema_version = %d\n\n", project.Config.SchemaVersion))
+	}
+
 	sb.WriteString("[project]\n")
 	sb.WriteString(fmt.Sprintf("name = %q\n", project.Name))
 	if project.Description != "" {
@@ -145,6 +293,9 @@ func generateTomlWithProject(project *entities.Project) string {
 
 	sb.WriteString("[paths]\n")
 	sb.WriteString(fmt.Sprintf("source = %q\n", project.Config.SourceDir))
+	if len(project.Config.SourceDirs) > 0 {
+		sb.WriteString(fmt.Sprintf("source_dirs = %s\n", formatTomlStringArray(project.Config.SourceDirs)))
+	}
 	sb.WriteString(fmt.Sprintf("output = %q\n", project.Config.OutputDir))
 	sb.WriteString("\n")
 
@@ -152,6 +303,11 @@ func generateTomlWithProject(project *entities.Project) string {
 	sb.WriteString(fmt.Sprintf("theme = %q\n", project.Config.D2Theme))
 	sb.WriteString(fmt.Sprintf("layout = %q\n", project.Config.D2Layout))
 	sb.WriteString(fmt.Sprintf("cache = %v\n", project.Config.D2Cache))
+	if project.Config.D2Style != "" {
+		sb.WriteString(fmt.Sprintf("style = %q\n", project.Config.D2Style))
+	}
+	sb.WriteString(fmt.Sprintf("legend = %v\n", project.Config.DiagramLegend))
+	sb.WriteString(fmt.Sprintf("metadata_footer = %v\n", project.Config.DiagramFooter))
 	sb.WriteString("\n")
 
 	sb.WriteString("[outputs]\n")
@@ -163,6 +319,7 @@ func generateTomlWithProject(project *entities.Project) string {
 	sb.WriteString("[build]\n")
 	sb.WriteString(fmt.Sprintf("parallel = %v\n", project.Config.Parallel))
 	sb.WriteString(fmt.Sprintf("max_workers = %d\n", project.Config.MaxWorkers))
+	sb.WriteString(fmt.Sprintf("minify = %v\n", project.Config.Minify))
 	sb.WriteString("\n")
 
 	sb.WriteString("[server]\n")
@@ -170,9 +327,408 @@ func generateTomlWithProject(project *entities.Project) string {
 	sb.WriteString(fmt.Sprintf("api_port = %d\n", project.Config.APIPort))
 	sb.WriteString(fmt.Sprintf("hot_reload = %v\n", project.Config.HotReload))
 
+	if site := generateSiteSection(project.Config); site != "" {
+		sb.WriteString("\n[site]\n")
+		sb.WriteString(site)
+	}
+
+	if len(project.Config.PageLayouts) > 0 {
+		sb.WriteString("\n[site.layouts]\n")
+		for _, selector := range slices.Sorted(maps.Keys(project.Config.PageLayouts)) {
+			sb.WriteString(fmt.Sprintf("%q = %q\n", selector, project.Config.PageLayouts[selector]))
+		}
+	}
+
+	if issues := generateIssuesSection(project.Config); issues != "" {
+		sb.WriteString("\n[issues]\n")
+		sb.WriteString(issues)
+	}
+
+	if project.Config.RecipientsFile != "" {
+		sb.WriteString("\n[encryption]\n")
+		sb.WriteString(fmt.Sprintf("recipients_file = %q\n", project.Config.RecipientsFile))
+	}
+
+	if redaction := generateRedactionSection(project.Config); redaction != "" {
+		sb.WriteString("\n[redaction]\n")
+		sb.WriteString(redaction)
+	}
+
+	if permissions := generatePermissionsSection(project.Config); permissions != "" {
+		sb.WriteString("\n[permissions]\n")
+		sb.WriteString(permissions)
+	}
+
+	if project.Config.GitAutoCommit {
+		sb.WriteString("\n[git]\nauto_commit = true\n")
+	}
+
+	if project.Config.RecordUsage {
+		sb.WriteString("\n[metrics]\nrecord_usage = true\n")
+	}
+
+	if project.Config.PluginRenderer != "" {
+		sb.WriteString("\n[plugins]\n")
+		sb.WriteString(fmt.Sprintf("renderer = %q\n", project.Config.PluginRenderer))
+	}
+
+	if hooks := generateHooksSection(project.Config); hooks != "" {
+		sb.WriteString("\n[hooks]\n")
+		sb.WriteString(hooks)
+	}
+
+	if deployment := generateDeploymentSection(project.Config); deployment != "" {
+		sb.WriteString("\n[deployment]\n")
+		sb.WriteString(deployment)
+	}
+
+	if project.Config.CostCurrency != "" {
+		sb.WriteString("\n[costs]\n")
+		sb.WriteString(fmt.Sprintf("currency = %q\n", project.Config.CostCurrency))
+	}
+
+	if project.Config.RequireApproval {
+		sb.WriteString("\n[review]\nrequire_approval = true\n")
+	}
+
+	if naming := generateNamingSection(project.Config); naming != "" {
+		sb.WriteString("\n[naming]\n")
+		sb.WriteString(naming)
+	}
+
+	if len(project.Config.NamingDomainPrefixes) > 0 {
+		sb.WriteString("\n[naming.domain_prefixes]\n")
+		for _, domain := range slices.Sorted(maps.Keys(project.Config.NamingDomainPrefixes)) {
+			sb.WriteString(fmt.Sprintf("%q = %q\n", domain, project.Config.NamingDomainPrefixes[domain]))
+		}
+	}
+
+	if descriptions := generateRelDescriptionsSection(project.Config); descriptions != "" {
+		sb.WriteString("\n[relationship_descriptions]\n")
+		sb.WriteString(descriptions)
+	}
+
+	if notifications := generateNotificationsSection(project.Config); notifications != "" {
+		sb.WriteString("\n[notifications]\n")
+		sb.WriteString(notifications)
+	}
+
+	if len(project.Config.TechnologyIcons) > 0 {
+		sb.WriteString("\n[icons]\n")
+		for _, name := range slices.Sorted(maps.Keys(project.Config.TechnologyIcons)) {
+			sb.WriteString(fmt.Sprintf("%q = %q\n", name, project.Config.TechnologyIcons[name]))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(project.Config.Archetypes)) {
+		sb.WriteString(fmt.Sprintf("\n[archetypes.%q]\n", name))
+		sb.WriteString(generateArchetypeSection(project.Config.Archetypes[name]))
+	}
+
+	if len(project.Config.RelationshipKinds) > 0 {
+		sb.WriteString("\n[relationship_types]\n")
+		for _, name := range slices.Sorted(maps.Keys(project.Config.RelationshipKinds)) {
+			sb.WriteString(fmt.Sprintf("%q = %q\n", name, project.Config.RelationshipKinds[name]))
+		}
+	}
+
 	return sb.String()
 }
 
+// generateSiteSection returns the [site] keys that are set, or "" if none are.
+func generateSiteSection(config *entities.ProjectConfig) string {
+	var sb strings.Builder
+	for _, kv := range []struct{ key, value string }{
+		{"head", config.CustomHead},
+		{"head_file", config.CustomHeadFile},
+		{"footer", config.CustomFooter},
+		{"footer_file", config.CustomFooterFile},
+		{"analytics", config.AnalyticsProvider},
+		{"analytics_id", config.AnalyticsID},
+		{"base_path", config.BasePath},
+	} {
+		if kv.value != "" {
+			sb.WriteString(fmt.Sprintf("%s = %q\n", kv.key, kv.value))
+		}
+	}
+	if len(config.CustomFields) > 0 {
+		sb.WriteString(fmt.Sprintf("custom_fields = %s\n", formatTomlStringArray(config.CustomFields)))
+	}
+	if config.PrettyURLs {
+		sb.WriteString("pretty_urls = true\n")
+	}
+	return sb.String()
+}
+
+// generateIssuesSection returns the [issues] keys that are set, or "" if none are.
+func generateIssuesSection(config *entities.ProjectConfig) string {
+	var sb strings.Builder
+	for _, kv := range []struct{ key, value string }{
+		{"url_template", config.IssueURLTemplate},
+		{"tracker", config.IssueTracker},
+		{"tracker_url", config.IssueTrackerURL},
+	} {
+		if kv.value != "" {
+			sb.WriteString(fmt.Sprintf("%s = %q\n", kv.key, kv.value))
+		}
+	}
+	return sb.String()
+}
+
+// generateRedactionSection returns the [redaction] keys that are set, or "" if none are.
+func generateRedactionSection(config *entities.ProjectConfig) string {
+	var sb strings.Builder
+	for _, kv := range []struct {
+		key    string
+		values []string
+	}{
+		{"remove_tags", config.RedactTags},
+		{"strip_fields", config.RedactFields},
+		{"mask_patterns", config.RedactPatterns},
+	} {
+		if len(kv.values) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%s = %s\n", kv.key, formatTomlStringArray(kv.values)))
+	}
+	if config.RedactMask != "" {
+		sb.WriteString(fmt.Sprintf("mask = %q\n", config.RedactMask))
+	}
+	return sb.String()
+}
+
+// generatePermissionsSection returns the [permissions] keys that are set, or "" if none are.
+func generatePermissionsSection(config *entities.ProjectConfig) string {
+	var sb strings.Builder
+	if config.MCPRole != "" {
+		sb.WriteString(fmt.Sprintf("mcp_role = %q\n", config.MCPRole))
+	}
+	if len(config.MCPAllowTools) > 0 {
+		sb.WriteString(fmt.Sprintf("mcp_allow = %s\n", formatTomlStringArray(config.MCPAllowTools)))
+	}
+	if len(config.MCPDenyTools) > 0 {
+		sb.WriteString(fmt.Sprintf("mcp_deny = %s\n", formatTomlStringArray(config.MCPDenyTools)))
+	}
+	return sb.String()
+}
+
+// generateHooksSection returns the [hooks] keys that are set, or "" if none are.
+func generateHooksSection(config *entities.ProjectConfig) string {
+	var sb strings.Builder
+	for _, kv := range []struct {
+		key      string
+		commands []string
+	}{
+		{"pre_build", config.PreBuildHooks},
+		{"post_build", config.PostBuildHooks},
+		{"pre_validate", config.PreValidateHooks},
+	} {
+		if len(kv.commands) > 0 {
+			sb.WriteString(fmt.Sprintf("%s = %s\n", kv.key, formatTomlStringArray(kv.commands)))
+		}
+	}
+	return sb.String()
+}
+
+// generateDeploymentSection returns the [deployment] keys that are set, or "" if none are.
+func generateDeploymentSection(config *entities.ProjectConfig) string {
+	var sb strings.Builder
+	if config.RequireDeployment {
+		sb.WriteString("require_mapping = true\n")
+	}
+	if len(config.DeploymentTargets) > 0 {
+		sb.WriteString(fmt.Sprintf("targets = %s\n", formatTomlStringArray(config.DeploymentTargets)))
+	}
+	return sb.String()
+}
+
+// generateNamingSection returns the [naming] keys that are set, or "" if none are.
+func generateNamingSection(config *entities.ProjectConfig) string {
+	var sb strings.Builder
+	if config.NamingIDCase != "" {
+		sb.WriteString(fmt.Sprintf("id_case = %q\n", config.NamingIDCase))
+	}
+	if config.NamingMaxNameLength > 0 {
+		sb.WriteString(fmt.Sprintf("max_name_length = %d\n", config.NamingMaxNameLength))
+	}
+	if config.NamingTechSeparator != "" {
+		sb.WriteString(fmt.Sprintf("technology_separator = %q\n", config.NamingTechSeparator))
+	}
+	return sb.String()
+}
+
+// generateRelDescriptionsSection returns the [relationship_descriptions]
+// keys that are set, or "" if none are.
+func generateRelDescriptionsSection(config *entities.ProjectConfig) string {
+	var sb strings.Builder
+	for _, rule := range []struct{ key, severity string }{
+		{"missing", config.RelDescriptionMissing},
+		{"verb_first", config.RelDescriptionVerbFirst},
+		{"placeholder", config.RelDescriptionPlaceholder},
+		{"too_long", config.RelDescriptionTooLong},
+	} {
+		if rule.severity != "" {
+			sb.WriteString(fmt.Sprintf("%s = %q\n", rule.key, rule.severity))
+		}
+	}
+	if config.RelDescriptionMaxLength > 0 {
+		sb.WriteString(fmt.Sprintf("max_length = %d\n", config.RelDescriptionMaxLength))
+	}
+	return sb.String()
+}
+
+// generateArchetypeSection returns the keys of an [archetypes.<name>] section
+// that are set.
+func generateArchetypeSection(archetype entities.ContainerArchetype) string {
+	var sb strings.Builder
+	if archetype.Technology != "" {
+		sb.WriteString(fmt.Sprintf("technology = %q\n", archetype.Technology))
+	}
+	if archetype.Description != "" {
+		sb.WriteString(fmt.Sprintf("description = %q\n", archetype.Description))
+	}
+	if len(archetype.Tags) > 0 {
+		sb.WriteString(fmt.Sprintf("tags = %s\n", formatTomlStringArray(archetype.Tags)))
+	}
+	if len(archetype.Components) > 0 {
+		sb.WriteString(fmt.Sprintf("components = %s\n", formatTomlStringArray(archetype.Components)))
+	}
+	if archetype.Diagram != "" {
+		sb.WriteString(fmt.Sprintf("diagram = %q\n", archetype.Diagram))
+	}
+	return sb.String()
+}
+
+// generateNotificationsSection returns the [notifications] keys that are set, or "" if none are.
+func generateNotificationsSection(config *entities.ProjectConfig) string {
+	var sb strings.Builder
+	if config.NotifyWebhookURL != "" {
+		sb.WriteString(fmt.Sprintf("webhook_url = %q\n", config.NotifyWebhookURL))
+	}
+	if config.NotifySMTPHost != "" {
+		sb.WriteString(fmt.Sprintf("smtp_host = %q\n", config.NotifySMTPHost))
+	}
+	if config.NotifyEmailFrom != "" {
+		sb.WriteString(fmt.Sprintf("email_from = %q\n", config.NotifyEmailFrom))
+	}
+	return sb.String()
+}
+
+// formatTomlStringArray encodes values as a single-line TOML array of strings.
+func formatTomlStringArray(values []string) string {
+	items := make([]string, len(values))
+	for i, value := range values {
+		items[i] = strconv.Quote(value)
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// parseTomlCommands decodes a hook setting, which is either a single command
+// string or an array of commands.
+func parseTomlCommands(raw string) []string {
+	if strings.HasPrefix(strings.TrimSpace(raw), "[") {
+		return parseTomlStringArray(raw)
+	}
+	if command := parseTomlString(raw); command != "" {
+		return []string{command}
+	}
+	return nil
+}
+
+// parseTomlStringArray decodes a single-line TOML array of strings such as
+// ["a", 'b']. Elements that are not strings are ignored.
+func parseTomlStringArray(raw string) []string {
+	raw = strings.TrimSpace(raw)
+	if !strings.HasPrefix(raw, "[") {
+		return nil
+	}
+	raw = raw[1:]
+
+	var values []string
+	for {
+		raw = strings.TrimLeft(raw, " \t,")
+		if raw == "" || raw[0] == ']' {
+			return values
+		}
+		switch raw[0] {
+		case '\'':
+			end := strings.IndexByte(raw[1:], '\'')
+			if end < 0 {
+				return values
+			}
+			values = append(values, raw[1:end+1])
+			raw = raw[end+2:]
+		case '"':
+			quoted, err := strconv.QuotedPrefix(raw)
+			if err != nil {
+				return values
+			}
+			value, _ := strconv.Unquote(quoted)
+			values = append(values, value)
+			raw = raw[len(quoted):]
+		default:
+			end := strings.IndexAny(raw, ",]")
+			if end < 0 {
+				return values
+			}
+			raw = raw[end:]
+		}
+	}
+}
+
+// parseTomlString decodes a single-line TOML string value. Literal strings
+// ('...') are taken verbatim and basic strings ("...") have their escapes
+// decoded, so HTML attributes can be written either way.
+func parseTomlString(raw string) string {
+	if len(raw) >= 2 && raw[0] == '\'' && raw[len(raw)-1] == '\'' {
+		return raw[1 : len(raw)-1]
+	}
+	if unquoted, err := strconv.Unquote(raw); err == nil {
+		return unquoted
+	}
+	return strings.Trim(raw, "\"'")
+}
+
+// parseRelDescriptionKey sets key of the [relationship_descriptions] section
+// from rawValue.
+func parseRelDescriptionKey(config *entities.ProjectConfig, key, rawValue string) {
+	switch key {
+	case "missing":
+		config.RelDescriptionMissing = parseTomlString(rawValue)
+	case "verb_first":
+		config.RelDescriptionVerbFirst = parseTomlString(rawValue)
+	case "placeholder":
+		config.RelDescriptionPlaceholder = parseTomlString(rawValue)
+	case "too_long":
+		config.RelDescriptionTooLong = parseTomlString(rawValue)
+	case "max_length":
+		if n, err := parseInt(strings.Trim(rawValue, "\"'")); err == nil {
+			config.RelDescriptionMaxLength = n
+		}
+	}
+}
+
+// parseArchetypeKey sets key of the archetype name from rawValue.
+func parseArchetypeKey(config *entities.ProjectConfig, name, key, rawValue string) {
+	if config.Archetypes == nil {
+		config.Archetypes = make(map[string]entities.ContainerArchetype)
+	}
+	archetype := config.Archetypes[name]
+	switch key {
+	case "technology":
+		archetype.Technology = parseTomlString(rawValue)
+	case "description":
+		archetype.Description = parseTomlString(rawValue)
+	case "tags":
+		archetype.Tags = parseTomlStringArray(rawValue)
+	case "components":
+		archetype.Components = parseTomlStringArray(rawValue)
+	case "diagram":
+		archetype.Diagram = parseTomlString(rawValue)
+	}
+	config.Archetypes[name] = archetype
+}
+
 // parseInt parses a string to an integer.
 func parseInt(s string) (int, error) {
 	var result int
@@ -0,0 +1,400 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestParseTomlSiteSection(t *testing.T) {
+	content := `[project]
+name = "demo"
+
+[site]
+head = '<link rel="icon" href="/favicon.ico">'
+footer = "<p class=\"legal\">© Acme</p>"
+footer_file = "site/footer.html"
+analytics = "plausible"
+analytics_id = "docs.example.com"
+custom_fields = ["owner", "compliance"]
+`
+	config := entities.DefaultProjectConfig()
+	var name string
+	if err := parseTomlWithName(content, config, &name); err != nil {
+		t.Fatalf("parseTomlWithName failed: %v", err)
+	}
+
+	if config.CustomHead != `<link rel="icon" href="/favicon.ico">` {
+		t.Errorf("CustomHead = %q", config.CustomHead)
+	}
+	if config.CustomFooter != `<p class="legal">© Acme</p>` {
+		t.Errorf("CustomFooter = %q", config.CustomFooter)
+	}
+	if config.CustomFooterFile != "site/footer.html" {
+		t.Errorf("CustomFooterFile = %q", config.CustomFooterFile)
+	}
+	if config.AnalyticsProvider != "plausible" || config.AnalyticsID != "docs.example.com" {
+		t.Errorf("analytics = %q/%q", config.AnalyticsProvider, config.AnalyticsID)
+	}
+	if !slices.Equal(config.CustomFields, []string{"owner", "compliance"}) {
+		t.Errorf("CustomFields = %q", config.CustomFields)
+	}
+}
+
+func TestGenerateTomlSiteSectionRoundTrip(t *testing.T) {
+	project := &entities.Project{Name: "demo", Config: entities.DefaultProjectConfig()}
+
+	if got := generateTomlWithProject(project); strings.Contains(got, "[site]") {
+		t.Error("expected no [site] section when nothing is customized")
+	}
+
+	project.Config.CustomHead = `<meta name="robots" content="noindex">`
+	project.Config.AnalyticsProvider = "google"
+	project.Config.AnalyticsID = "G-TEST"
+	project.Config.CustomFields = []string{"cost_center"}
+	project.Config.BasePath = "/architecture/"
+	project.Config.PrettyURLs = true
+
+	parsed := entities.DefaultProjectConfig()
+	if err := parseTomlWithName(generateTomlWithProject(project), parsed, nil); err != nil {
+		t.Fatalf("parseTomlWithName failed: %v", err)
+	}
+	if parsed.CustomHead != project.Config.CustomHead {
+		t.Errorf("CustomHead = %q, want %q", parsed.CustomHead, project.Config.CustomHead)
+	}
+	if parsed.AnalyticsProvider != "google" || parsed.AnalyticsID != "G-TEST" {
+		t.Errorf("analytics = %q/%q", parsed.AnalyticsProvider, parsed.AnalyticsID)
+	}
+	if !slices.Equal(parsed.CustomFields, project.Config.CustomFields) {
+		t.Errorf("CustomFields = %q", parsed.CustomFields)
+	}
+	if parsed.BasePath != "/architecture/" || !parsed.PrettyURLs {
+		t.Errorf("routing = %q/%v", parsed.BasePath, parsed.PrettyURLs)
+	}
+}
+
+func TestGenerateTomlIssuesSectionRoundTrip(t *testing.T) {
+	project := &entities.Project{Name: "demo", Config: entities.DefaultProjectConfig()}
+	project.Config.IssueURLTemplate = "https://acme.atlassian.net/browse/{id}"
+	project.Config.IssueTracker = "jira"
+
+	content := generateTomlWithProject(project)
+	if !strings.Contains(content, "[issues]") {
+		t.Fatalf("expected [issues] section, got:\n%s", content)
+	}
+
+	parsed := entities.DefaultProjectConfig()
+	if err := parseTomlWithName(content, parsed, nil); err != nil {
+		t.Fatalf("parseTomlWithName failed: %v", err)
+	}
+	if parsed.IssueURLTemplate != project.Config.IssueURLTemplate {
+		t.Errorf("IssueURLTemplate = %q, want %q", parsed.IssueURLTemplate, project.Config.IssueURLTemplate)
+	}
+	if parsed.IssueTracker != "jira" || parsed.IssueTrackerURL != "" {
+		t.Errorf("tracker = %q/%q", parsed.IssueTracker, parsed.IssueTrackerURL)
+	}
+}
+
+func TestParseTomlRedactionSection(t *testing.T) {
+	content := `[redaction]
+remove_tags = ["secret", 'internal-only']
+strip_fields = ["technology"]
+mask_patterns = ['[a-z0-9-]+\.corp\.example\.com', "10\\.\\d+\\.\\d+\\.\\d+"]
+mask = "███"
+`
+	config := entities.DefaultProjectConfig()
+	if err := parseTomlWithName(content, config, nil); err != nil {
+		t.Fatalf("parseTomlWithName failed: %v", err)
+	}
+
+	if got := strings.Join(config.RedactTags, "|"); got != "secret|internal-only" {
+		t.Errorf("RedactTags = %q", config.RedactTags)
+	}
+	if got := strings.Join(config.RedactFields, "|"); got != "technology" {
+		t.Errorf("RedactFields = %q", config.RedactFields)
+	}
+	want := []string{`[a-z0-9-]+\.corp\.example\.com`, `10\.\d+\.\d+\.\d+`}
+	if strings.Join(config.RedactPatterns, "|") != strings.Join(want, "|") {
+		t.Errorf("RedactPatterns = %q, want %q", config.RedactPatterns, want)
+	}
+	if config.RedactMask != "███" {
+		t.Errorf("RedactMask = %q", config.RedactMask)
+	}
+
+	project := &entities.Project{Name: "demo", Config: config}
+	parsed := entities.DefaultProjectConfig()
+	if err := parseTomlWithName(generateTomlWithProject(project), parsed, nil); err != nil {
+		t.Fatalf("parseTomlWithName failed: %v", err)
+	}
+	if strings.Join(parsed.RedactPatterns, "|") != strings.Join(want, "|") || parsed.RedactMask != "███" {
+		t.Errorf("round trip = %q/%q", parsed.RedactPatterns, parsed.RedactMask)
+	}
+}
+
+func TestParseTomlPluginsSection(t *testing.T) {
+	config := entities.DefaultProjectConfig()
+	if err := parseTomlWithName("[plugins]\nrenderer = \"kroki\"\n", config, nil); err != nil {
+		t.Fatalf("parseTomlWithName failed: %v", err)
+	}
+	if config.PluginRenderer != "kroki" {
+		t.Errorf("PluginRenderer = %q", config.PluginRenderer)
+	}
+
+	parsed := entities.DefaultProjectConfig()
+	project := &entities.Project{Name: "demo", Config: config}
+	if err := parseTomlWithName(generateTomlWithProject(project), parsed, nil); err != nil {
+		t.Fatalf("parseTomlWithName failed: %v", err)
+	}
+	if parsed.PluginRenderer != "kroki" {
+		t.Errorf("round trip PluginRenderer = %q", parsed.PluginRenderer)
+	}
+}
+
+func TestParseTomlGitSection(t *testing.T) {
+	config := entities.DefaultProjectConfig()
+	if err := parseTomlWithName("[git]\nauto_commit = true\n", config, nil); err != nil {
+		t.Fatalf("parseTomlWithName failed: %v", err)
+	}
+	if !config.GitAutoCommit {
+		t.Error("GitAutoCommit = false, want true")
+	}
+
+	parsed := entities.DefaultProjectConfig()
+	project := &entities.Project{Name: "demo", Config: config}
+	if err := parseTomlWithName(generateTomlWithProject(project), parsed, nil); err != nil {
+		t.Fatalf("parseTomlWithName failed: %v", err)
+	}
+	if !parsed.GitAutoCommit {
+		t.Error("round trip GitAutoCommit = false, want true")
+	}
+}
+
+func TestParseTomlSourceDirs(t *testing.T) {
+	config := entities.DefaultProjectConfig()
+	if err := parseTomlWithName("[paths]\nsource = \"./src\"\nsource_dirs = [\"../billing/src\", \"vendor/shared\"]\n", config, nil); err != nil {
+		t.Fatalf("parseTomlWithName failed: %v", err)
+	}
+	want := []string{"../billing/src", "vendor/shared"}
+	if !slices.Equal(config.SourceDirs, want) {
+		t.Errorf("SourceDirs = %v, want %v", config.SourceDirs, want)
+	}
+
+	parsed := entities.DefaultProjectConfig()
+	project := &entities.Project{Name: "demo", Config: config}
+	if err := parseTomlWithName(generateTomlWithProject(project), parsed, nil); err != nil {
+		t.Fatalf("parseTomlWithName failed: %v", err)
+	}
+	if !slices.Equal(parsed.SourceDirs, want) {
+		t.Errorf("round trip SourceDirs = %v, want %v", parsed.SourceDirs, want)
+	}
+}
+
+func TestParseTomlHooksSection(t *testing.T) {
+	content := `[hooks]
+pre_build = "make openapi-summary"
+post_build = ["./scripts/upload.sh $LOKO_OUTPUT_DIR", 'echo done']
+`
+	config := entities.DefaultProjectConfig()
+	if err := parseTomlWithName(content, config, nil); err != nil {
+		t.Fatalf("parseTomlWithName failed: %v", err)
+	}
+	if strings.Join(config.PreBuildHooks, "|") != "make openapi-summary" {
+		t.Errorf("PreBuildHooks = %q", config.PreBuildHooks)
+	}
+	if strings.Join(config.PostBuildHooks, "|") != "./scripts/upload.sh $LOKO_OUTPUT_DIR|echo done" {
+		t.Errorf("PostBuildHooks = %q", config.PostBuildHooks)
+	}
+
+	parsed := entities.DefaultProjectConfig()
+	project := &entities.Project{Name: "demo", Config: config}
+	if err := parseTomlWithName(generateTomlWithProject(project), parsed, nil); err != nil {
+		t.Fatalf("parseTomlWithName failed: %v", err)
+	}
+	if strings.Join(parsed.PostBuildHooks, "|") != strings.Join(config.PostBuildHooks, "|") || len(parsed.PreValidateHooks) != 0 {
+		t.Errorf("round trip = %q/%q", parsed.PostBuildHooks, parsed.PreValidateHooks)
+	}
+}
+
+func TestParseTomlIconsSection(t *testing.T) {
+	content := `[project]
+name = "demo"
+
+[icons]
+grpc = "https://icons.example.com/grpc.svg"
+"Spring Boot" = "https://icons.example.com/spring-boot.svg"
+redis = ""
+
+[server]
+serve_port = 9000
+`
+	config := entities.DefaultProjectConfig()
+	var name string
+	if err := parseTomlWithName(content, config, &name); err != nil {
+		t.Fatalf("parseTomlWithName failed: %v", err)
+	}
+	want := map[string]string{
+		"grpc":        "https://icons.example.com/grpc.svg",
+		"Spring Boot": "https://icons.example.com/spring-boot.svg",
+		"redis":       "",
+	}
+	if !maps.Equal(config.TechnologyIcons, want) {
+		t.Errorf("TechnologyIcons = %v, want %v", config.TechnologyIcons, want)
+	}
+	if name != "demo" || config.ServePort != 9000 {
+		t.Errorf("keys outside [icons] = %q/%d", name, config.ServePort)
+	}
+
+	parsed := entities.DefaultProjectConfig()
+	project := &entities.Project{Name: "demo", Config: config}
+	if err := parseTomlWithName(generateTomlWithProject(project), parsed, nil); err != nil {
+		t.Fatalf("parseTomlWithName failed: %v", err)
+	}
+	if !maps.Equal(parsed.TechnologyIcons, want) {
+		t.Errorf("round trip = %v", parsed.TechnologyIcons)
+	}
+}
+
+func TestParseTomlRelationshipTypesSection(t *testing.T) {
+	content := `[relationship_types]
+deploys = "container -> system"
+"triggers" = "component -> *"
+
+[d2]
+theme = "neutral-default"
+`
+	config := entities.DefaultProjectConfig()
+	if err := parseTomlWithName(content, config, nil); err != nil {
+		t.Fatalf("parseTomlWithName failed: %v", err)
+	}
+	want := map[string]string{
+		"deploys":  "container -> system",
+		"triggers": "component -> *",
+	}
+	if !maps.Equal(config.RelationshipKinds, want) {
+		t.Errorf("RelationshipKinds = %v, want %v", config.RelationshipKinds, want)
+	}
+	if config.D2Theme != "neutral-default" {
+		t.Errorf("keys outside [relationship_types]: D2Theme = %q", config.D2Theme)
+	}
+
+	parsed := entities.DefaultProjectConfig()
+	project := &entities.Project{Name: "demo", Config: config}
+	if err := parseTomlWithName(generateTomlWithProject(project), parsed, nil); err != nil {
+		t.Fatalf("parseTomlWithName failed: %v", err)
+	}
+	if !maps.Equal(parsed.RelationshipKinds, want) {
+		t.Errorf("round trip = %v", parsed.RelationshipKinds)
+	}
+}
+
+func TestLoadConfigSchemaVersion(t *testing.T) {
+	root := t.TempDir()
+	configPath := filepath.Join(root, "loko.toml")
+
+	// Without loko.toml a project is new, without schema_version it is legacy
+	if config, err := loadConfig(configPath); err != nil || config.SchemaVersion != entities.CurrentSchemaVersion {
+		t.Errorf("missing loko.toml: SchemaVersion = %d, %v", config.SchemaVersion, err)
+	}
+	writeSourceFiles(t, root, map[string]string{"loko.toml": "[project]\nname = \"demo\"\n"})
+	config, err := loadConfig(configPath)
+	if err != nil || config.SchemaVersion != entities.LegacySchemaVersion {
+		t.Errorf("loko.toml without schema_version: SchemaVersion = %d, %v", config.SchemaVersion, err)
+	}
+
+	config.SchemaVersion = 2
+	if err := saveConfigWithProject(configPath, &entities.Project{Name: "demo", Config: config}); err != nil {
+		t.Fatal(err)
+	}
+	if parsed, err := loadConfig(configPath); err != nil || parsed.SchemaVersion != 2 {
+		t.Errorf("round trip SchemaVersion = %d, %v", parsed.SchemaVersion, err)
+	}
+
+	writeSourceFiles(t, root, map[string]string{"loko.toml": fmt.Sprintf("schema_version = %d\n", entities.CurrentSchemaVersion+1)})
+	if _, err := NewProjectRepository().LoadProject(context.Background(), root); !errors.Is(err, entities.ErrSchemaTooNew) {
+		t.Errorf("LoadProject() error = %v, want ErrSchemaTooNew", err)
+	}
+}
+
+func TestLoadConfigEnvOverrides(t *testing.T) {
+	root := t.TempDir()
+	configPath := filepath.Join(root, "loko.toml")
+	writeSourceFiles(t, root, map[string]string{"loko.toml": "[paths]\nsource = \"./src\"\n\n[d2]\ntheme = \"dark-mauve\"\n"})
+	t.Setenv("LOKO_SOURCE_DIR", "./architecture")
+	t.Setenv("LOKO_D2_THEME", "terminal")
+
+	config, err := loadConfig(configPath)
+	if err != nil {
+		t.Fatalf("loadConfig failed: %v", err)
+	}
+	if config.SourceDir != "./architecture" || config.D2Theme != "terminal" {
+		t.Errorf("SourceDir = %q, D2Theme = %q, want the environment's", config.SourceDir, config.D2Theme)
+	}
+
+	t.Setenv("LOKO_BUILD_MAX_WORKERS", "many")
+	if _, err := loadConfig(configPath); !errors.Is(err, entities.ErrInvalidConfig) {
+		t.Errorf("loadConfig() error = %v, want ErrInvalidConfig", err)
+	}
+}
+
+func TestGenerateTomlDeploymentCostsReviewRoundTrip(t *testing.T) {
+	project := &entities.Project{Name: "demo", Config: entities.DefaultProjectConfig()}
+	if got := generateTomlWithProject(project); strings.Contains(got, "[deployment]") {
+		t.Error("expected no [deployment] section when nothing is customized")
+	}
+
+	project.Config.RequireDeployment = true
+	project.Config.DeploymentTargets = []string{"eks-prod", "lambda"}
+	project.Config.CostCurrency = "EUR"
+	project.Config.RequireApproval = true
+	project.Config.NotifyWebhookURL = "https://hooks.example.com/loko"
+	project.Config.NotifySMTPHost = "smtp.example.com:587"
+	project.Config.NotifyEmailFrom = "loko@example.com"
+	project.Config.NamingIDCase = "kebab"
+	project.Config.NamingMaxNameLength = 40
+	project.Config.NamingTechSeparator = ", "
+	project.Config.NamingDomainPrefixes = map[string]string{"payments": "pay-"}
+	project.Config.RelDescriptionMissing = "error"
+	project.Config.RelDescriptionTooLong = "warning"
+	project.Config.RelDescriptionMaxLength = 60
+	project.Config.PageLayouts = map[string]string{"external": "layouts/vendor.html", "tag:datastore": "layouts/datastore.html"}
+	project.Config.Archetypes = map[string]entities.ContainerArchetype{
+		"go-service": {Technology: "Go", Description: "A Go service", Tags: []string{"service"}, Components: []string{"handler", "repository"}, Diagram: "archetypes/go.d2"},
+	}
+	parsed := entities.DefaultProjectConfig()
+	if err := parseTomlWithName(generateTomlWithProject(project), parsed, nil); err != nil {
+		t.Fatalf("parseTomlWithName failed: %v", err)
+	}
+	if !parsed.RequireDeployment || !slices.Equal(parsed.DeploymentTargets, project.Config.DeploymentTargets) {
+		t.Errorf("deployment = %v/%q", parsed.RequireDeployment, parsed.DeploymentTargets)
+	}
+	if parsed.CostCurrency != "EUR" {
+		t.Errorf("CostCurrency = %q, want EUR", parsed.CostCurrency)
+	}
+	if !parsed.RequireApproval {
+		t.Error("RequireApproval = false, want true")
+	}
+	if parsed.NamingIDCase != "kebab" || parsed.NamingMaxNameLength != 40 || parsed.NamingTechSeparator != ", " || parsed.NamingDomainPrefixes["payments"] != "pay-" {
+		t.Errorf("naming = %q, %d, %q, %v", parsed.NamingIDCase, parsed.NamingMaxNameLength, parsed.NamingTechSeparator, parsed.NamingDomainPrefixes)
+	}
+	if parsed.RelDescriptionMissing != "error" || parsed.RelDescriptionVerbFirst != "" || parsed.RelDescriptionTooLong != "warning" || parsed.RelDescriptionMaxLength != 60 {
+		t.Errorf("relationship descriptions = %q, %q, %q, %d", parsed.RelDescriptionMissing, parsed.RelDescriptionVerbFirst, parsed.RelDescriptionTooLong, parsed.RelDescriptionMaxLength)
+	}
+	if !maps.Equal(parsed.PageLayouts, project.Config.PageLayouts) {
+		t.Errorf("PageLayouts = %v, want %v", parsed.PageLayouts, project.Config.PageLayouts)
+	}
+	archetype := parsed.Archetypes["go-service"]
+	if archetype.Technology != "Go" || archetype.Description != "A Go service" || !slices.Equal(archetype.Tags, []string{"service"}) ||
+		!slices.Equal(archetype.Components, []string{"handler", "repository"}) || archetype.Diagram != "archetypes/go.d2" {
+		t.Errorf("Archetypes = %+v", parsed.Archetypes)
+	}
+	if parsed.NotifyWebhookURL != project.Config.NotifyWebhookURL || parsed.NotifySMTPHost != project.Config.NotifySMTPHost || parsed.NotifyEmailFrom != project.Config.NotifyEmailFrom {
+		t.Errorf("notifications = %q/%q/%q", parsed.NotifyWebhookURL, parsed.NotifySMTPHost, parsed.NotifyEmailFrom)
+	}
+}
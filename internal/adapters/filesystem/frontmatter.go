@@ -0,0 +1,595 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// Ensure ProjectRepository implements usecases.FrontmatterEditor.
+var _ usecases.FrontmatterEditor = (*ProjectRepository)(nil)
+
+// frontmatterKeys are the frontmatter fields loko maps to element fields.
+// Every other top-level key is kept in the element's Metadata.
+var frontmatterKeys = map[string]bool{
+	"id":               true,
+	"name":             true,
+	"description":      true,
+	"technology":       true,
+	"tags":             true,
+	"issues":           true,
+	"controls":         true,
+	"export":           true,
+	"relationships":    true,
+	"code_annotations": true,
+	"dependencies":     true,
+	"aliases":          true,
+	"external":         true,
+	"review_status":    true,
+	"reviewed_by":      true,
+	"reviewed_at":      true,
+}
+
+// frontmatter holds the decoded top-level keys of an element's Markdown file.
+type frontmatter map[string]any
+
+// decodeFrontmatter decodes the frontmatter of the source at path with the
+// codec of pr. Frontmatter the codec rejects, such as an unquoted value
+// holding ": ", is read line by line instead, as loko did before parsing
+// YAML, with a warning naming the file.
+func (pr *ProjectRepository) decodeFrontmatter(path string, content []byte) (frontmatter, error) {
+	fields, _, err := pr.codec.Decode(content)
+	if err == nil {
+		return frontmatter(fields), nil
+	}
+	document, _, ok, splitErr := splitFrontmatter(content)
+	if !errors.Is(err, entities.ErrInvalidFrontmatter) || !ok || splitErr != nil {
+		return nil, err
+	}
+	pr.warn(fmt.Sprintf("%s: %v; reading it line by line (quote values containing \": \" to fix)", path, err))
+	return parseLegacyFrontmatter(document), nil
+}
+
+// parseLegacyFrontmatter reads the top-level keys of a frontmatter document
+// one line at a time: "key: value" scalars, with everything after the first
+// ": " as the value, "[a, b]" flow lists, and indented "- item" lists or
+// "key: value" maps under a key without a value. Lines it cannot read are
+// skipped.
+func parseLegacyFrontmatter(document []byte) frontmatter {
+	fm := make(frontmatter)
+	lines := strings.Split(strings.ReplaceAll(string(document), "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if line == "" || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "-") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			continue
+		}
+		if value = strings.TrimSpace(value); value != "" {
+			fm[key] = parseLegacyScalar(value)
+			continue
+		}
+
+		// A key without a value starts an indented list or map.
+		var items []any
+		entries := make(map[string]any)
+		for ; i+1 < len(lines) && (strings.HasPrefix(lines[i+1], " ") || strings.HasPrefix(lines[i+1], "- ")); i++ {
+			entry := strings.TrimSpace(lines[i+1])
+			if item, ok := strings.CutPrefix(entry, "- "); ok {
+				items = append(items, unquoteLegacy(strings.TrimSpace(item)))
+			} else if name, value, ok := strings.Cut(entry, ":"); ok {
+				entries[unquoteLegacy(strings.TrimSpace(name))] = unquoteLegacy(strings.TrimSpace(value))
+			}
+		}
+		switch {
+		case items != nil:
+			fm[key] = items
+		case len(entries) > 0:
+			fm[key] = entries
+		}
+	}
+	return fm
+}
+
+// parseLegacyScalar converts a frontmatter value read line by line to a
+// bool, int, float64, flow list or string. Quoted values are always strings.
+func parseLegacyScalar(value string) any {
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		items := []any{}
+		for item := range strings.SplitSeq(value[1:len(value)-1], ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, unquoteLegacy(item))
+			}
+		}
+		return items
+	}
+	if strings.HasPrefix(value, "\"") || strings.HasPrefix(value, "'") {
+		return unquoteLegacy(value)
+	}
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	return value
+}
+
+// unquoteLegacy strips the quotes around a value read line by line.
+func unquoteLegacy(value string) string {
+	if strings.HasPrefix(value, "\"") {
+		if s, err := strconv.Unquote(value); err == nil {
+			return s
+		}
+	}
+	return strings.Trim(value, "\"'")
+}
+
+// String returns the scalar key as a string, or "" when it is absent or not
+// a scalar.
+func (fm frontmatter) String(key string) string {
+	return frontmatterString(fm[key])
+}
+
+// List returns the items of the list key, or nil when it is absent or not a
+// list. Nested maps and lists among the items are skipped.
+func (fm frontmatter) List(key string) []string {
+	values, ok := fm[key].([]any)
+	if !ok {
+		return nil
+	}
+	items := make([]string, 0, len(values))
+	for _, value := range values {
+		if item := frontmatterString(value); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// StringMap returns the scalar entries of the map key, such as the
+// relationships of a component. It is empty, never nil, when key is absent.
+func (fm frontmatter) StringMap(key string) map[string]string {
+	entries := make(map[string]string)
+	values, _ := fm[key].(map[string]any)
+	for k, value := range values {
+		switch value.(type) {
+		case map[string]any, []any:
+			continue
+		}
+		entries[k] = frontmatterString(value)
+	}
+	return entries
+}
+
+// Flag reports whether key is true, e.g. "external: true". A quoted "true"
+// counts too.
+func (fm frontmatter) Flag(key string) bool {
+	return fm[key] == true || fm[key] == "true"
+}
+
+// Review returns the review_status, reviewed_by and reviewed_at keys of the
+// review workflow. An unknown status is kept for validation to report; an
+// invalid date is ignored.
+func (fm frontmatter) Review() entities.Review {
+	review := entities.Review{
+		Status:     entities.ReviewStatus(strings.ToLower(fm.String(entities.ReviewStatusField))),
+		ReviewedBy: fm.String(entities.ReviewedByField),
+	}
+	review.ReviewedAt, _ = entities.ParseReviewDate(fm.String(entities.ReviewedAtField))
+	return review
+}
+
+// Metadata returns the top-level keys loko does not know, such as
+// "owner: payments-team", a "compliance:" list or a map of contacts, with
+// their values as decoded, so they are written back unchanged. Lists of
+// strings become []string; keys without a value are left out.
+func (fm frontmatter) Metadata() map[string]any {
+	metadata := make(map[string]any)
+	for key, value := range fm {
+		if frontmatterKeys[key] || value == nil {
+			continue
+		}
+		if items, ok := value.([]any); ok {
+			if strs, ok := stringItems(items); ok {
+				value = strs
+			}
+		}
+		metadata[key] = value
+	}
+	return metadata
+}
+
+// frontmatterString returns a decoded scalar as a string, or "" for nil,
+// maps and lists. Unquoted dates, which YAML reads as timestamps, are
+// written as they were.
+func frontmatterString(value any) string {
+	switch v := value.(type) {
+	case nil, map[string]any, []any:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		if v.Equal(v.Truncate(24 * time.Hour)) {
+			return v.Format(entities.ReviewDateLayout)
+		}
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// stringItems returns items as strings when every item is one.
+func stringItems(items []any) ([]string, bool) {
+	strs := make([]string, len(items))
+	for i, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		strs[i] = s
+	}
+	return strs, true
+}
+
+// writeFrontmatterReview writes the review keys of review that are set.
+func writeFrontmatterReview(sb *strings.Builder, review entities.Review) {
+	if review.Status != "" {
+		sb.WriteString(fmt.Sprintf("%s: %q\n", entities.ReviewStatusField, review.Status))
+	}
+	if review.ReviewedBy != "" {
+		sb.WriteString(fmt.Sprintf("%s: %q\n", entities.ReviewedByField, review.ReviewedBy))
+	}
+	if !review.ReviewedAt.IsZero() {
+		sb.WriteString(fmt.Sprintf("%s: %q\n", entities.ReviewedAtField, review.ReviewedAt.Format(entities.ReviewDateLayout)))
+	}
+}
+
+// withFrontmatterMetadata adds the metadata keys missing from the
+// frontmatter of content, e.g. one rendered from a template, after its other
+// keys. Content without frontmatter is returned unchanged.
+func (pr *ProjectRepository) withFrontmatterMetadata(content string, metadata map[string]any) string {
+	if len(metadata) == 0 {
+		return content
+	}
+	return editFrontmatterContent(content, func(doc *frontmatterDocument) {
+		for _, key := range slices.Sorted(maps.Keys(metadata)) {
+			if !frontmatterKeys[key] && !doc.has(key) {
+				doc.set(key, metadataNode(metadata[key]))
+			}
+		}
+	})
+}
+
+// withFrontmatterMap adds the map key with entries to the frontmatter of
+// content, e.g. one rendered from a template, unless the key is present.
+func withFrontmatterMap(content, key string, entries map[string]string) string {
+	if len(entries) == 0 {
+		return content
+	}
+	return editFrontmatterContent(content, func(doc *frontmatterDocument) {
+		if !doc.has(key) {
+			doc.set(key, mapNode(entries))
+		}
+	})
+}
+
+// withFrontmatterList adds the list key with items to the frontmatter of
+// content, e.g. one rendered from a template, unless the key is present.
+func withFrontmatterList(content, key string, items []string) string {
+	if len(items) == 0 {
+		return content
+	}
+	return editFrontmatterContent(content, func(doc *frontmatterDocument) {
+		if !doc.has(key) {
+			doc.set(key, listNode(items))
+		}
+	})
+}
+
+// SetFrontmatterField sets a top-level scalar key in the frontmatter of the
+// Markdown file at path, replacing its value or adding it after the other
+// keys. A file without frontmatter gets one.
+func (pr *ProjectRepository) SetFrontmatterField(ctx context.Context, path, key, value string) error {
+	return editFrontmatter(path, func(doc *frontmatterDocument) error {
+		doc.set(key, quotedNode(value))
+		return nil
+	})
+}
+
+// SetFrontmatterList replaces the list under a top-level key in the
+// frontmatter of the Markdown file at path, or removes the key when items is
+// empty.
+func (pr *ProjectRepository) SetFrontmatterList(ctx context.Context, path, key string, items []string) error {
+	return editFrontmatter(path, func(doc *frontmatterDocument) error {
+		doc.set(key, listNode(items))
+		return nil
+	})
+}
+
+// SetFrontmatterMap replaces the map under a top-level key in the
+// frontmatter of the Markdown file at path with entries in sorted order, or
+// removes the key when entries is empty.
+func (pr *ProjectRepository) SetFrontmatterMap(ctx context.Context, path, key string, entries map[string]string) error {
+	return editFrontmatter(path, func(doc *frontmatterDocument) error {
+		doc.set(key, mapNode(entries))
+		return nil
+	})
+}
+
+// RemoveRelationship removes the entry for target from the relationships map
+// in the frontmatter of the Markdown file at path, and the map itself when it
+// becomes empty.
+func (pr *ProjectRepository) RemoveRelationship(ctx context.Context, path, target string) error {
+	return editFrontmatter(path, func(doc *frontmatterDocument) error {
+		relationships := doc.value("relationships")
+		if relationships != nil && relationships.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(relationships.Content); i += 2 {
+				if relationships.Content[i].Value != target {
+					continue
+				}
+				relationships.Content = slices.Delete(relationships.Content, i, i+2)
+				if len(relationships.Content) == 0 {
+					doc.set("relationships", nil) // Last entry: drop the empty map
+				}
+				doc.changed = true
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: no relationship to %q", filepath.Base(path), target)
+	})
+}
+
+// frontmatterDocument is the frontmatter of a Markdown file parsed as a YAML
+// node tree, so keys can be set and removed while the other keys keep their
+// values, quoting and comments.
+type frontmatterDocument struct {
+	root    *yaml.Node // Document node; nil for a file without frontmatter
+	fields  *yaml.Node // Mapping node of the top-level keys
+	body    []byte     // Content after the closing "---" line
+	crlf    bool       // Lines end in "\r\n"
+	found   bool       // The file has frontmatter
+	changed bool
+}
+
+// parseFrontmatterDocument parses the frontmatter of content. Content
+// without frontmatter gets an empty one, with all of content as its body.
+func parseFrontmatterDocument(content []byte) (*frontmatterDocument, error) {
+	document, body, ok, err := splitFrontmatter(content)
+	if err != nil {
+		return nil, err
+	}
+	doc := &frontmatterDocument{
+		fields: &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"},
+		body:   body,
+		crlf:   bytes.Contains(content, []byte("\r\n")),
+	}
+	if !ok {
+		return doc, nil
+	}
+	doc.found = true
+
+	root := &yaml.Node{}
+	if err := yaml.Unmarshal(document, root); err != nil {
+		return nil, fmt.Errorf("%w: %w", entities.ErrInvalidFrontmatter, err)
+	}
+	if len(root.Content) == 0 {
+		return doc, nil // Empty frontmatter
+	}
+	if root.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%w: expected keys and values", entities.ErrInvalidFrontmatter)
+	}
+	doc.root, doc.fields = root, root.Content[0]
+	return doc, nil
+}
+
+// index returns the position of key among the key and value nodes of the
+// top-level map, or -1.
+func (doc *frontmatterDocument) index(key string) int {
+	for i := 0; i+1 < len(doc.fields.Content); i += 2 {
+		if doc.fields.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// has reports whether the top-level key is present.
+func (doc *frontmatterDocument) has(key string) bool {
+	return doc.index(key) >= 0
+}
+
+// value returns the value node of the top-level key, or nil.
+func (doc *frontmatterDocument) value(key string) *yaml.Node {
+	if i := doc.index(key); i >= 0 {
+		return doc.fields.Content[i+1]
+	}
+	return nil
+}
+
+// set replaces the value of the top-level key, or adds the key after the
+// others. A nil value removes the key.
+func (doc *frontmatterDocument) set(key string, value *yaml.Node) {
+	i := doc.index(key)
+	switch {
+	case value == nil && i < 0:
+		return
+	case value == nil:
+		doc.fields.Content = slices.Delete(doc.fields.Content, i, i+2)
+	case i < 0:
+		doc.fields.Content = append(doc.fields.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	default:
+		value.LineComment = doc.fields.Content[i+1].LineComment
+		doc.fields.Content[i+1] = value
+	}
+	doc.changed = true
+}
+
+// bytes returns the file content with the edited frontmatter, in the line
+// endings of the original file.
+func (doc *frontmatterDocument) bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(frontmatterDelimiter + "\n")
+	if len(doc.fields.Content) > 0 {
+		node := doc.fields
+		if doc.root != nil {
+			node = doc.root // Keeps comments above and below the keys
+		}
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(node); err != nil {
+			return nil, fmt.Errorf("failed to encode frontmatter: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode frontmatter: %w", err)
+		}
+	}
+	buf.WriteString(frontmatterDelimiter + "\n")
+
+	out := buf.Bytes()
+	if doc.crlf {
+		out = bytes.ReplaceAll(out, []byte("\n"), []byte("\r\n"))
+	}
+	return append(out, doc.body...), nil
+}
+
+// editFrontmatter calls edit on the frontmatter of the Markdown file at path
+// and writes the file back when it changed anything. Only the frontmatter is
+// rewritten; the body is unchanged.
+func editFrontmatter(path string, edit func(doc *frontmatterDocument) error) error {
+	content, perm, err := readEditableSource(path)
+	if err != nil {
+		return err
+	}
+	doc, err := parseFrontmatterDocument(content)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	if err := edit(doc); err != nil {
+		return err
+	}
+	if !doc.changed {
+		return nil
+	}
+	edited, err := doc.bytes()
+	if err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, edited, perm); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// editFrontmatterContent calls edit on the frontmatter of content, e.g. one
+// rendered from a template. Content without valid frontmatter is returned
+// unchanged.
+func editFrontmatterContent(content string, edit func(doc *frontmatterDocument)) string {
+	doc, err := parseFrontmatterDocument([]byte(content))
+	if err != nil || !doc.found {
+		return content
+	}
+	edit(doc)
+	if !doc.changed {
+		return content
+	}
+	edited, err := doc.bytes()
+	if err != nil {
+		return content
+	}
+	return string(edited)
+}
+
+// quotedNode returns a double-quoted string node, the way loko writes
+// frontmatter values.
+func quotedNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value, Style: yaml.DoubleQuotedStyle}
+}
+
+// listNode returns a list of quoted items, or nil when items is empty.
+func listNode(items []string) *yaml.Node {
+	if len(items) == 0 {
+		return nil
+	}
+	list := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, item := range items {
+		list.Content = append(list.Content, quotedNode(item))
+	}
+	return list
+}
+
+// mapNode returns a map of quoted values in sorted key order, or nil when
+// entries is empty.
+func mapNode(entries map[string]string) *yaml.Node {
+	if len(entries) == 0 {
+		return nil
+	}
+	m := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, key := range slices.Sorted(maps.Keys(entries)) {
+		m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, quotedNode(entries[key]))
+	}
+	return m
+}
+
+// metadataNode returns the node of a metadata value: strings and lists of
+// strings quoted like the other keys, anything else as YAML encodes it. It
+// is nil for a nil value.
+func metadataNode(value any) *yaml.Node {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return quotedNode(v)
+	case []string:
+		return listNode(v)
+	case []any:
+		if strs, ok := stringItems(v); ok {
+			return listNode(strs)
+		}
+	}
+	node := &yaml.Node{}
+	if err := node.Encode(value); err != nil {
+		return quotedNode(fmt.Sprint(value))
+	}
+	return node
+}
+
+// readEditableSource reads a plaintext source file and its permissions.
+// Encrypted sources cannot be edited in place.
+func readEditableSource(path string) ([]byte, fs.FileMode, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) && sourceExists(path) {
+		return nil, 0, fmt.Errorf("%s: %w", filepath.Base(path), entities.ErrEncrypted)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	return content, info.Mode().Perm(), nil
+}
@@ -0,0 +1,84 @@
+package filesystem
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// Ensure YAMLFrontmatterCodec implements usecases.FrontmatterCodec.
+var _ usecases.FrontmatterCodec = (*YAMLFrontmatterCodec)(nil)
+
+// frontmatterDelimiter opens and closes the frontmatter of a Markdown file.
+const frontmatterDelimiter = "---"
+
+// YAMLFrontmatterCodec reads frontmatter with a YAML parser, so
+// sources may use any YAML there: nested maps, lists of maps, block scalars,
+// flow lists and comments.
+type YAMLFrontmatterCodec struct{}
+
+// NewYAMLFrontmatterCodec creates a new YAMLFrontmatterCodec instance.
+func NewYAMLFrontmatterCodec() *YAMLFrontmatterCodec {
+	return &YAMLFrontmatterCodec{}
+}
+
+// Decode splits content into its frontmatter fields and body. Frontmatter
+// starts at a first line of "---" and ends at the next; a missing closing
+// line or a document that is not a map is an error.
+func (c *YAMLFrontmatterCodec) Decode(content []byte) (map[string]any, []byte, error) {
+	document, body, ok, err := splitFrontmatter(content)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return map[string]any{}, content, nil
+	}
+
+	fields := map[string]any{}
+	if err := yaml.Unmarshal(document, &fields); err != nil {
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			return nil, nil, fmt.Errorf("%w: expected keys and values", entities.ErrInvalidFrontmatter)
+		}
+		return nil, nil, fmt.Errorf("%w: %w", entities.ErrInvalidFrontmatter, err)
+	}
+	if fields == nil {
+		fields = map[string]any{}
+	}
+	return fields, body, nil
+}
+
+// splitFrontmatter splits content into the YAML document between its
+// opening and closing "---" lines and the body after them. ok is false when
+// content has no frontmatter; a missing closing line is an error.
+func splitFrontmatter(content []byte) (document, body []byte, ok bool, err error) {
+	rest, ok := cutDelimiterLine(content)
+	if !ok {
+		return nil, content, false, nil
+	}
+	for offset := 0; ; {
+		line, next, found := bytes.Cut(rest[offset:], []byte("\n"))
+		if string(bytes.TrimRight(line, "\r")) == frontmatterDelimiter {
+			return rest[:offset], next, true, nil
+		}
+		if !found {
+			return nil, nil, false, fmt.Errorf("%w: no closing %q", entities.ErrInvalidFrontmatter, frontmatterDelimiter)
+		}
+		offset += len(line) + 1
+	}
+}
+
+// cutDelimiterLine returns content after its first line when that line is
+// "---".
+func cutDelimiterLine(content []byte) ([]byte, bool) {
+	line, rest, found := bytes.Cut(content, []byte("\n"))
+	if !found || string(bytes.TrimRight(line, "\r")) != frontmatterDelimiter {
+		return nil, false
+	}
+	return rest, true
+}
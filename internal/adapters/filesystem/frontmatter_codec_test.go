@@ -0,0 +1,77 @@
+package filesystem
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestYAMLFrontmatterCodecDecode(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantFields map[string]any
+		wantBody   string
+	}{
+		{"no frontmatter", "# Payments\n", map[string]any{}, "# Payments\n"},
+		{"empty", "---\n---\n# Payments\n", map[string]any{}, "# Payments\n"},
+		{"scalars", "---\nname: \"Payments\"\ntier: 1\n---\nbody\n", map[string]any{"name": "Payments", "tier": 1}, "body\n"},
+		{"crlf", "---\r\nname: Payments\r\n---\r\nbody\r\n", map[string]any{"name": "Payments"}, "body\r\n"},
+		{"closing line last", "---\nname: Payments\n---", map[string]any{"name": "Payments"}, ""},
+		{"comments and flow lists", "---\n# owner below\nowner: core # team\nexport: [pdf, markdown]\n---\n", map[string]any{"owner": "core", "export": []any{"pdf", "markdown"}}, ""},
+	}
+	codec := NewYAMLFrontmatterCodec()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, body, err := codec.Decode([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("fields = %#v, want %#v", fields, tt.wantFields)
+			}
+			if string(body) != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestYAMLFrontmatterCodecDecodeInvalid(t *testing.T) {
+	codec := NewYAMLFrontmatterCodec()
+	for name, content := range map[string]string{
+		"unclosed":      "---\nname: Payments\n# Payments\n",
+		"invalid yaml":  "---\ndescription: \"Says \"hi\"\"\n---\n",
+		"not a mapping": "---\n- pdf\n- markdown\n---\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := codec.Decode([]byte(content)); !errors.Is(err, entities.ErrInvalidFrontmatter) {
+				t.Errorf("Decode() error = %v, want ErrInvalidFrontmatter", err)
+			}
+		})
+	}
+}
+
+// TestYAMLFrontmatterCodecNestedValues verifies that nested maps, lists of
+// maps and block scalars are decoded as written.
+func TestYAMLFrontmatterCodecNestedValues(t *testing.T) {
+	content := "---\nowner: payments-team\ncontacts:\n  oncall: \"#payments\"\n  escalation: [ana, li]\nslos:\n  - name: availability\n    target: 99.9\n  - name: latency\n    target: 250\n    unit: ms\nnotes: |\n  line one\n  line two\n---\n"
+	want := map[string]any{
+		"owner":    "payments-team",
+		"contacts": map[string]any{"oncall": "#payments", "escalation": []any{"ana", "li"}},
+		"slos": []any{
+			map[string]any{"name": "availability", "target": 99.9},
+			map[string]any{"name": "latency", "target": 250, "unit": "ms"},
+		},
+		"notes": "line one\nline two\n",
+	}
+	fields, _, err := NewYAMLFrontmatterCodec().Decode([]byte(content))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("Decode() = %#v, want %#v", fields, want)
+	}
+}
@@ -0,0 +1,51 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// IDIndexFile is the ID index's path relative to the project root.
+const IDIndexFile = ".loko/ids.json"
+
+// Ensure IDIndexStore implements usecases.IDIndexStore interface.
+var _ usecases.IDIndexStore = (*IDIndexStore)(nil)
+
+// IDIndexStore implements the IDIndexStore port as an indented JSON file.
+type IDIndexStore struct {
+	path string
+}
+
+// NewIDIndexStore creates an ID index stored in .loko/ids.json under projectRoot.
+func NewIDIndexStore(projectRoot string) *IDIndexStore {
+	return &IDIndexStore{path: filepath.Join(projectRoot, IDIndexFile)}
+}
+
+// SaveIDIndex writes index to a temporary file renamed over ids.json, so
+// editors watching it never read a partial index. An unchanged index is not
+// rewritten, which spares watchers a reload after every rebuild.
+func (s *IDIndexStore) SaveIDIndex(_ context.Context, index *entities.IDIndex) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode ID index: %w", err)
+	}
+	data = append(data, '\n')
+
+	if current, err := os.ReadFile(s.path); err == nil && bytes.Equal(current, data) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create ID index directory: %w", err)
+	}
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("failed to write ID index: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestIDIndexStore_Save(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store := NewIDIndexStore(root)
+	path := filepath.Join(root, IDIndexFile)
+
+	index := &entities.IDIndex{SchemaVersion: "1", Elements: []entities.IDIndexEntry{{ID: "shop", Type: "system", Name: "Shop"}}}
+	if err := store.SaveIDIndex(ctx, index); err != nil {
+		t.Fatalf("SaveIDIndex failed: %v", err)
+	}
+	var saved entities.IDIndex
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &saved); err != nil || len(saved.Elements) != 1 || saved.Elements[0].ID != "shop" {
+		t.Fatalf("ids.json = %s, err %v", data, err)
+	}
+
+	// An unchanged index is not rewritten
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveIDIndex(ctx, index); err != nil {
+		t.Fatalf("SaveIDIndex failed: %v", err)
+	}
+	if info, _ := os.Stat(path); !info.ModTime().Equal(old) {
+		t.Error("unchanged index was rewritten")
+	}
+
+	index.Elements = append(index.Elements, entities.IDIndexEntry{ID: "shop/api", Type: "container", Name: "API", Parent: "shop"})
+	if err := store.SaveIDIndex(ctx, index); err != nil {
+		t.Fatalf("SaveIDIndex failed: %v", err)
+	}
+	if info, _ := os.Stat(path); info.ModTime().Equal(old) {
+		t.Error("changed index was not rewritten")
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
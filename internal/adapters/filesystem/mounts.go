@@ -0,0 +1,79 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// procMountsPath is the Linux mount table consulted by DetectPollingFS.
+const procMountsPath = "/proc/mounts"
+
+// pollingFSTypes lists file system types on which fsnotify is known to miss
+// events (network shares and VM/container bind mounts).
+var pollingFSTypes = map[string]bool{
+	"nfs":           true,
+	"nfs4":          true,
+	"cifs":          true,
+	"smb3":          true,
+	"smbfs":         true,
+	"9p":            true,
+	"vboxsf":        true,
+	"fakeowner":     true,
+	"grpcfuse":      true,
+	"fuse.grpcfuse": true,
+	"fuse.sshfs":    true,
+}
+
+// DetectPollingFS reports whether path lives on a file system where event-based
+// watching is unreliable. It returns the detected file system type when polling
+// is recommended. On platforms without /proc/mounts it always returns false.
+func DetectPollingFS(path string) (fsType string, needsPolling bool) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+
+	data, err := os.ReadFile(procMountsPath)
+	if err != nil {
+		return "", false
+	}
+
+	fsType = mountFSType(string(data), filepath.ToSlash(abs))
+	return fsType, pollingFSTypes[fsType]
+}
+
+// mountFSType returns the file system type of the longest mount point containing path.
+// mounts is the content of a /proc/mounts style table.
+func mountFSType(mounts, path string) string {
+	bestLen := -1
+	bestType := ""
+
+	for line := range strings.SplitSeq(mounts, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		// Mount points escape spaces as \040.
+		mountPoint := strings.ReplaceAll(fields[1], `\040`, " ")
+
+		if !pathWithin(path, mountPoint) || len(mountPoint) <= bestLen {
+			continue
+		}
+		bestLen = len(mountPoint)
+		bestType = fields[2]
+	}
+
+	return bestType
+}
+
+// pathWithin reports whether path equals dir or is nested below it.
+func pathWithin(path, dir string) bool {
+	if dir == "/" {
+		return strings.HasPrefix(path, "/")
+	}
+	return path == dir || strings.HasPrefix(path, dir+"/")
+}
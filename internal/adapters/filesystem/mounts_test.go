@@ -0,0 +1,41 @@
+package filesystem
+
+import "testing"
+
+func TestMountFSType(t *testing.T) {
+	mounts := `overlay / overlay rw,relatime 0 0
+proc /proc proc rw,nosuid 0 0
+server:/export /workspaces nfs4 rw,relatime 0 0
+/dev/sda1 /workspaces/local ext4 rw 0 0
+grpcfuse /mnt/my\040project fuse.grpcfuse rw 0 0
+`
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/home/user/project", "overlay"},
+		{"/workspaces/app", "nfs4"},
+		{"/workspaces", "nfs4"},
+		{"/workspaces/local/app", "ext4"},
+		{"/workspaces-other", "overlay"},
+		{"/mnt/my project/src", "fuse.grpcfuse"},
+	}
+	for _, tt := range tests {
+		if got := mountFSType(mounts, tt.path); got != tt.want {
+			t.Errorf("mountFSType(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPollingFSTypes(t *testing.T) {
+	for _, fsType := range []string{"nfs", "cifs", "fuse.grpcfuse", "9p"} {
+		if !pollingFSTypes[fsType] {
+			t.Errorf("expected %s to require polling", fsType)
+		}
+	}
+	for _, fsType := range []string{"ext4", "overlay", "apfs"} {
+		if pollingFSTypes[fsType] {
+			t.Errorf("expected %s not to require polling", fsType)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// elementDir returns the directory of an element under srcDir, given the
+// slash-separated IDs leading to it (e.g. "payments/billing/api"). Each
+// segment resolves to an existing directory whose name normalizes to it, so
+// that src/Payments/ is found for "payments" on case-sensitive filesystems as
+// it is on Windows and macOS, and saving does not create src/payments/ next
+// to it. Segments without such a directory are used as is.
+func elementDir(srcDir, idPath string) string {
+	dir := srcDir
+	for segment := range strings.SplitSeq(idPath, "/") {
+		if segment == "" {
+			continue
+		}
+		dir = filepath.Join(dir, resolveSegment(dir, segment))
+	}
+	return dir
+}
+
+// resolveSegment returns the name of the subdirectory of dir for the ID
+// segment.
+func resolveSegment(dir, segment string) string {
+	if info, err := os.Stat(filepath.Join(dir, segment)); err == nil && info.IsDir() {
+		return segment
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return segment
+	}
+	id := entities.NormalizeName(segment)
+	for _, entry := range entries {
+		if entry.IsDir() && entities.NormalizeName(entry.Name()) == id {
+			return entry.Name()
+		}
+	}
+	return segment
+}
+
+// siblingIDs detects sibling directories that map to the same ID, such as
+// Payments/ and payments/, or order_store/ and order-store/. They cannot
+// both exist on case-insensitive filesystems (Windows, macOS), and elsewhere
+// which of them is loaded would depend on directory order.
+type siblingIDs struct {
+	dir   string
+	names map[string]string // By ID
+}
+
+// newSiblingIDs starts the collision check of the subdirectories of dir.
+func newSiblingIDs(dir string) *siblingIDs {
+	return &siblingIDs{dir: dir, names: make(map[string]string)}
+}
+
+// add records the subdirectory name and returns an error wrapping
+// entities.ErrPathCollision if an earlier one maps to the same ID.
+func (s *siblingIDs) add(name string) error {
+	id := entities.NormalizeName(name)
+	if other, ok := s.names[id]; ok {
+		return fmt.Errorf("%w: %s and %s in %s both map to %q; rename one of them",
+			entities.ErrPathCollision, other, name, s.dir, id)
+	}
+	s.names[id] = name
+	return nil
+}
@@ -0,0 +1,151 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func writeSourceFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestElementDir(t *testing.T) {
+	src := t.TempDir()
+	writeSourceFiles(t, src, map[string]string{
+		"Payments/Card_API/container.md": "",
+		"orders/system.md":               "",
+	})
+
+	tests := []struct {
+		idPath string
+		want   string
+	}{
+		{"orders", "orders"},
+		{"payments", "Payments"},
+		{"payments/card-api", "Payments/Card_API"},
+		{"payments/card-api/new-component", "Payments/Card_API/new-component"},
+		{"billing", "billing"},
+	}
+	for _, tt := range tests {
+		if got := elementDir(src, tt.idPath); got != filepath.Join(src, filepath.FromSlash(tt.want)) {
+			t.Errorf("elementDir(%q) = %q, want %q", tt.idPath, got, tt.want)
+		}
+	}
+}
+
+// TestProjectRepository_MixedCaseDirectories verifies that elements in
+// directories whose names differ from their IDs by case or separators load
+// and save in place on every platform.
+func TestProjectRepository_MixedCaseDirectories(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	writeSourceFiles(t, root, map[string]string{
+		"src/Payments/system.md":                       "---\nname: \"Payments\"\n---\n",
+		"src/Payments/Card_API/container.md":           "---\nname: \"Card API\"\n---\n",
+		"src/Payments/Card_API/Tokenizer/component.md": "---\nname: \"Tokenizer\"\n---\n",
+	})
+	pr := NewProjectRepository()
+
+	sys, err := pr.LoadSystem(ctx, root, "payments")
+	if err != nil {
+		t.Fatalf("LoadSystem failed: %v", err)
+	}
+	if sys.Path != filepath.Join(root, "src", "Payments") {
+		t.Errorf("system Path = %q", sys.Path)
+	}
+	if _, err := pr.LoadComponent(ctx, root, "payments", "card-api", "tokenizer"); err != nil {
+		t.Fatalf("LoadComponent failed: %v", err)
+	}
+
+	sys.Description = "Card payments"
+	if err := pr.SaveSystem(ctx, root, sys); err != nil {
+		t.Fatalf("SaveSystem failed: %v", err)
+	}
+	cont, _ := entities.NewContainer("Card API")
+	if err := pr.SaveContainer(ctx, root, "payments", cont); err != nil {
+		t.Fatalf("SaveContainer failed: %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(root, "src"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "Payments" {
+		t.Errorf("src/ holds %d entries, want only Payments/", len(entries))
+	}
+	if cont.Path != filepath.Join(root, "src", "Payments", "Card_API") {
+		t.Errorf("container saved to %q", cont.Path)
+	}
+}
+
+func TestListSystems_PathCollision(t *testing.T) {
+	tests := []struct {
+		name     string
+		caseOnly bool // Cannot be set up on case-insensitive filesystems
+		files    map[string]string
+	}{
+		{"systems differing in case", true, map[string]string{
+			"src/Payments/system.md": "---\nname: \"Payments\"\n---\n",
+			"src/payments/system.md": "---\nname: \"Payments\"\n---\n",
+		}},
+		{"containers differing in separator", false, map[string]string{
+			"src/shop/system.md":              "---\nname: \"Shop\"\n---\n",
+			"src/shop/order_api/container.md": "---\nname: \"Order API\"\n---\n",
+			"src/shop/order-api/container.md": "---\nname: \"Order API\"\n---\n",
+		}},
+		{"components differing in case", true, map[string]string{
+			"src/shop/system.md":                "---\nname: \"Shop\"\n---\n",
+			"src/shop/api/container.md":         "---\nname: \"API\"\n---\n",
+			"src/shop/api/Handler/component.md": "---\nname: \"Handler\"\n---\n",
+			"src/shop/api/handler/component.md": "---\nname: \"Handler\"\n---\n",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			if tt.caseOnly && !caseSensitive(t, root) {
+				t.Skip("filesystem is case-insensitive")
+			}
+			writeSourceFiles(t, root, tt.files)
+			_, err := NewProjectRepository().ListSystems(context.Background(), root)
+			if !errors.Is(err, entities.ErrPathCollision) {
+				t.Errorf("ListSystems() error = %v, want ErrPathCollision", err)
+			}
+		})
+	}
+}
+
+// caseSensitive reports whether the filesystem holding dir tells file names
+// apart by case, as on Linux but not by default on Windows and macOS.
+func caseSensitive(t *testing.T, dir string) bool {
+	t.Helper()
+	writeSourceFiles(t, dir, map[string]string{"probe": ""})
+	defer func() { _ = os.Remove(filepath.Join(dir, "probe")) }()
+	_, err := os.Stat(filepath.Join(dir, "PROBE"))
+	return err != nil
+}
+
+func TestShouldIgnoreDir_CaseInsensitive(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{".Git", "Dist", "Node_Modules", filepath.Join(".LOKO", "cache")} {
+		if !shouldIgnoreDir(filepath.Join(root, dir), root) {
+			t.Errorf("shouldIgnoreDir(%q) = false, want true", dir)
+		}
+	}
+	if shouldIgnoreDir(filepath.Join(root, ".loko", "Templates"), root) {
+		t.Error("shouldIgnoreDir(.loko/Templates) = true, want false")
+	}
+}
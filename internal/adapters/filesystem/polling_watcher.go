@@ -0,0 +1,199 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// DefaultPollInterval is the scan interval used when none is configured.
+const DefaultPollInterval = time.Second
+
+// fileState is the part of a file's metadata compared between scans.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// PollingWatcher monitors the file system by periodically scanning the tree.
+//
+// It is a fallback for file systems where fsnotify misses events, such as NFS,
+// SMB and Docker/devcontainer bind mounts. It applies the same directory and
+// file filters as FileWatcher and reports changes once per scan.
+type PollingWatcher struct {
+	interval time.Duration
+	events   chan usecases.FileChangeEvent
+	done     chan struct{}
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	stopped  bool
+}
+
+// NewPollingWatcher creates a polling watcher that scans every interval.
+// A non-positive interval uses DefaultPollInterval.
+func NewPollingWatcher(interval time.Duration) *PollingWatcher {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	return &PollingWatcher{
+		interval: interval,
+		events:   make(chan usecases.FileChangeEvent, 10),
+		done:     make(chan struct{}),
+	}
+}
+
+// Interval returns the scan interval.
+func (pw *PollingWatcher) Interval() time.Duration {
+	return pw.interval
+}
+
+// Watch starts polling a directory for changes.
+// Returns a read-only channel of FileChangeEvent; returns error if setup fails.
+// The channel is closed when Stop() is called.
+func (pw *PollingWatcher) Watch(ctx context.Context, rootPath string) (<-chan usecases.FileChangeEvent, error) {
+	pw.mu.Lock()
+	if pw.stopped {
+		pw.mu.Unlock()
+		return nil, fmt.Errorf("watcher already stopped")
+	}
+	pw.mu.Unlock()
+
+	// Validate root path exists
+	info, err := os.Stat(rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root path: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("root path is not a directory")
+	}
+
+	// Take the baseline snapshot synchronously so changes made right after
+	// Watch returns are detected by the first scan.
+	snapshot := scanTree(rootPath)
+
+	pw.wg.Go(func() {
+		pw.poll(ctx, rootPath, snapshot)
+	})
+
+	return pw.events, nil
+}
+
+// Stop halts polling and closes the events channel.
+func (pw *PollingWatcher) Stop() error {
+	pw.mu.Lock()
+	if pw.stopped {
+		pw.mu.Unlock()
+		return nil
+	}
+	pw.stopped = true
+	pw.mu.Unlock()
+
+	close(pw.done)
+	pw.wg.Wait()
+	close(pw.events)
+
+	return nil
+}
+
+// poll rescans the tree every interval and emits events for differences.
+func (pw *PollingWatcher) poll(ctx context.Context, rootPath string, previous map[string]fileState) {
+	ticker := time.NewTicker(pw.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-pw.done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := scanTree(rootPath)
+			for _, evt := range diffSnapshots(previous, current) {
+				select {
+				case pw.events <- evt:
+				case <-pw.done:
+					return
+				case <-ctx.Done():
+					return
+				}
+			}
+			previous = current
+		}
+	}
+}
+
+// scanTree returns the state of every watched file under rootPath and the
+// source directories outside it, keyed by lowercase forward-slash path
+// relative to rootPath (matching FileWatcher event paths). Symlinked
+// directories are followed.
+func scanTree(rootPath string) map[string]fileState {
+	states := make(map[string]fileState)
+
+	for _, root := range watchRoots(rootPath) {
+		scanRoot(rootPath, root, states)
+	}
+	return states
+}
+
+// scanRoot adds the state of every watched file under root to states.
+func scanRoot(rootPath, root string, states map[string]fileState) {
+	_ = walkFollow(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip paths with errors
+		}
+
+		if info.IsDir() {
+			if path != root && shouldIgnoreDir(path, root) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relPath, err := filepath.Rel(rootPath, path)
+		if err != nil {
+			return nil
+		}
+		relPath = strings.ToLower(filepath.ToSlash(relPath))
+
+		if !shouldProcessFile(relPath) {
+			return nil
+		}
+
+		states[relPath] = fileState{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+}
+
+// diffSnapshots returns create, write and remove events between two scans, sorted by path.
+func diffSnapshots(previous, current map[string]fileState) []usecases.FileChangeEvent {
+	var events []usecases.FileChangeEvent
+
+	for path, state := range current {
+		old, existed := previous[path]
+		switch {
+		case !existed:
+			events = append(events, usecases.FileChangeEvent{Path: path, Op: "create"})
+		case !old.modTime.Equal(state.modTime) || old.size != state.size:
+			events = append(events, usecases.FileChangeEvent{Path: path, Op: "write"})
+		}
+	}
+
+	for path := range previous {
+		if _, exists := current[path]; !exists {
+			events = append(events, usecases.FileChangeEvent{Path: path, Op: "remove"})
+		}
+	}
+
+	slices.SortFunc(events, func(a, b usecases.FileChangeEvent) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return events
+}
@@ -0,0 +1,128 @@
+package filesystem
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestPollingWatcherDetectsChanges tests create, write and remove detection.
+func TestPollingWatcherDetectsChanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	existing := filepath.Join(tmpDir, "existing.md")
+	if err := os.WriteFile(existing, []byte("# Old"), 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	pw := NewPollingWatcher(20 * time.Millisecond)
+	defer func() {
+		if err := pw.Stop(); err != nil {
+			t.Errorf("Stop failed: %v", err)
+		}
+	}()
+
+	events, err := pw.Watch(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "new.d2"), []byte("a -> b"), 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "ignored.txt"), []byte("x"), 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	if err := os.Remove(existing); err != nil {
+		t.Fatalf("failed to remove file: %v", err)
+	}
+
+	got := make(map[string]string)
+	timeout := time.After(2 * time.Second)
+	for len(got) < 2 {
+		select {
+		case evt := <-events:
+			got[evt.Path] = evt.Op
+		case <-timeout:
+			t.Fatalf("timeout waiting for events, got %v", got)
+		}
+	}
+
+	if got["new.d2"] != "create" {
+		t.Errorf("expected create for new.d2, got %q", got["new.d2"])
+	}
+	if got["existing.md"] != "remove" {
+		t.Errorf("expected remove for existing.md, got %q", got["existing.md"])
+	}
+	if _, ok := got["ignored.txt"]; ok {
+		t.Error("unexpected event for ignored.txt")
+	}
+}
+
+// TestPollingWatcherIgnoresDist tests that ignored directories are not scanned.
+func TestPollingWatcherIgnoresDist(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "dist"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "dist", "index.md"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	states := scanTree(tmpDir)
+	if len(states) != 0 {
+		t.Errorf("expected dist/ to be ignored, got %v", states)
+	}
+}
+
+// TestDiffSnapshots tests the snapshot comparison.
+func TestDiffSnapshots(t *testing.T) {
+	now := time.Now()
+	previous := map[string]fileState{
+		"a.md": {modTime: now, size: 1},
+		"b.md": {modTime: now, size: 1},
+		"c.md": {modTime: now, size: 1},
+	}
+	current := map[string]fileState{
+		"a.md": {modTime: now, size: 1},
+		"b.md": {modTime: now.Add(time.Second), size: 1},
+		"d.md": {modTime: now, size: 1},
+	}
+
+	events := diffSnapshots(previous, current)
+	want := []string{"b.md:write", "c.md:remove", "d.md:create"}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %v", len(want), events)
+	}
+	for i, evt := range events {
+		if got := evt.Path + ":" + evt.Op; got != want[i] {
+			t.Errorf("event %d: expected %s, got %s", i, want[i], got)
+		}
+	}
+}
+
+// TestPollingWatcherStop tests that Stop closes the channel and is idempotent.
+func TestPollingWatcherStop(t *testing.T) {
+	pw := NewPollingWatcher(0)
+	if pw.Interval() != DefaultPollInterval {
+		t.Errorf("expected default interval, got %v", pw.Interval())
+	}
+
+	events, err := pw.Watch(context.Background(), t.TempDir())
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	if err := pw.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if err := pw.Stop(); err != nil {
+		t.Fatalf("second Stop failed: %v", err)
+	}
+	if _, ok := <-events; ok {
+		t.Error("expected closed channel after Stop")
+	}
+	if _, err := pw.Watch(context.Background(), t.TempDir()); err == nil {
+		t.Error("expected error when watching after stop")
+	}
+}
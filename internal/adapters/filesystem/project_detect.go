@@ -0,0 +1,128 @@
+package filesystem
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// invalidNameChars matches characters not allowed in project names.
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_\- ]+`)
+
+// majorVersionSuffix matches Go module major version path elements such as "v2".
+var majorVersionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// DetectProjectName infers a project name for an existing codebase at root.
+//
+// Sources are tried in order: the module path in go.mod, the "name" field of
+// package.json, the URL of the "origin" git remote, and finally the directory
+// name. Returns the name and the source it came from ("go.mod", "package.json",
+// "git remote" or "directory").
+func DetectProjectName(root string) (name, source string) {
+	if n := goModuleName(filepath.Join(root, "go.mod")); n != "" {
+		return n, "go.mod"
+	}
+	if n := packageJSONName(filepath.Join(root, "package.json")); n != "" {
+		return n, "package.json"
+	}
+	if n := gitRemoteName(filepath.Join(root, ".git", "config")); n != "" {
+		return n, "git remote"
+	}
+
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		abs = root
+	}
+	return sanitizeProjectName(filepath.Base(abs)), "directory"
+}
+
+// goModuleName returns the last meaningful element of the go.mod module path.
+func goModuleName(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		module, ok := strings.CutPrefix(line, "module ")
+		if !ok {
+			continue
+		}
+		module = strings.Trim(strings.TrimSpace(module), `"`)
+		elems := strings.Split(module, "/")
+		last := elems[len(elems)-1]
+		if len(elems) > 1 && majorVersionSuffix.MatchString(last) {
+			last = elems[len(elems)-2]
+		}
+		return sanitizeProjectName(last)
+	}
+	return ""
+}
+
+// packageJSONName returns the package name from package.json without its npm scope.
+func packageJSONName(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	var pkg struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return ""
+	}
+
+	name := pkg.Name
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return sanitizeProjectName(name)
+}
+
+// gitRemoteName returns the repository name of the "origin" remote in a git config file.
+func gitRemoteName(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	inOrigin := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+		if !inOrigin {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) != "url" {
+			continue
+		}
+
+		url := strings.TrimSuffix(strings.TrimSpace(value), "/")
+		url = strings.TrimSuffix(url, ".git")
+		if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+			url = url[i+1:]
+		}
+		return sanitizeProjectName(url)
+	}
+	return ""
+}
+
+// sanitizeProjectName replaces characters not allowed in project names with hyphens.
+func sanitizeProjectName(name string) string {
+	name = invalidNameChars.ReplaceAllString(name, "-")
+	return strings.Trim(name, "-_ ")
+}
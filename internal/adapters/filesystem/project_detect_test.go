@@ -0,0 +1,85 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectProjectName(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		wantName   string
+		wantSource string
+	}{
+		{
+			name:       "go module",
+			files:      map[string]string{"go.mod": "module github.com/acme/payments\n\ngo 1.25\n"},
+			wantName:   "payments",
+			wantSource: "go.mod",
+		},
+		{
+			name:       "go module with major version",
+			files:      map[string]string{"go.mod": "module github.com/acme/payments/v3\n"},
+			wantName:   "payments",
+			wantSource: "go.mod",
+		},
+		{
+			name:       "scoped npm package",
+			files:      map[string]string{"package.json": `{"name": "@acme/web.frontend", "version": "1.0.0"}`},
+			wantName:   "web-frontend",
+			wantSource: "package.json",
+		},
+		{
+			name: "git remote",
+			files: map[string]string{".git/config": `[core]
+	bare = false
+[remote "upstream"]
+	url = https://github.com/other/fork.git
+[remote "origin"]
+	url = git@github.com:acme/billing-service.git
+`},
+			wantName:   "billing-service",
+			wantSource: "git remote",
+		},
+		{
+			name:       "go.mod wins over package.json",
+			files:      map[string]string{"go.mod": "module example.com/api\n", "package.json": `{"name": "ui"}`},
+			wantName:   "api",
+			wantSource: "go.mod",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for rel, content := range tt.files {
+				path := filepath.Join(root, rel)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			name, source := DetectProjectName(root)
+			if name != tt.wantName || source != tt.wantSource {
+				t.Errorf("DetectProjectName() = (%q, %q), want (%q, %q)", name, source, tt.wantName, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestDetectProjectNameFallsBackToDirectory(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "inventory.api")
+	if err := os.Mkdir(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	name, source := DetectProjectName(root)
+	if name != "inventory-api" || source != "directory" {
+		t.Errorf("DetectProjectName() = (%q, %q), want (inventory-api, directory)", name, source)
+	}
+}
@@ -3,26 +3,42 @@ package filesystem
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
 	"github.com/madstone-tech/loko/internal/core/usecases"
 )
 
+// Ensure ProjectRepository implements usecases.PersonRepository.
+var _ usecases.PersonRepository = (*ProjectRepository)(nil)
+
 // ProjectRepository implements the ProjectRepository port using the file system.
 // Projects are stored in a directory structure with loko.toml configuration
 // and markdown files with YAML frontmatter.
 type ProjectRepository struct {
 	templateEngine usecases.TemplateEngine
+	encrypter      usecases.FileEncrypter    // Decrypts sources encrypted at rest; optional
+	history        usecases.HistoryProvider  // Dates and authors of element changes; optional
+	codec          usecases.FrontmatterCodec // Reads the frontmatter of sources
+	warnings       io.Writer                 // Receives warnings about sources read leniently; defaults to os.Stderr
 }
 
 // NewProjectRepository creates a new file system project repository.
 func NewProjectRepository() *ProjectRepository {
 	return &ProjectRepository{
 		templateEngine: nil, // Can be set with SetTemplateEngine if needed
+		codec:          NewYAMLFrontmatterCodec(),
 	}
 }
 
@@ -31,6 +47,118 @@ func (pr *ProjectRepository) SetTemplateEngine(te usecases.TemplateEngine) {
 	pr.templateEngine = te
 }
 
+// SetFrontmatterCodec replaces the YAML codec that reads the frontmatter of
+// element Markdown files.
+func (pr *ProjectRepository) SetFrontmatterCodec(codec usecases.FrontmatterCodec) {
+	pr.codec = codec
+}
+
+// SetWarnings sends warnings about sources loko can read only leniently,
+// such as frontmatter that is not valid YAML, to w instead of os.Stderr.
+func (pr *ProjectRepository) SetWarnings(w io.Writer) {
+	pr.warnings = w
+}
+
+// warn writes a warning about a source.
+func (pr *ProjectRepository) warn(message string) {
+	w := pr.warnings
+	if w == nil {
+		w = os.Stderr
+	}
+	fmt.Fprintf(w, "Warning: %s\n", message)
+}
+
+// SetEncrypter enables transparent decryption of sources encrypted with
+// `loko encrypt`. Without it, loading an encrypted project fails with
+// entities.ErrEncrypted.
+func (pr *ProjectRepository) SetEncrypter(enc usecases.FileEncrypter) {
+	pr.encrypter = enc
+}
+
+// SetHistory makes ListSystems and LoadSystem take CreatedAt, UpdatedAt and
+// Author from version control. Without it, or outside a repository, UpdatedAt
+// is the modification time of the element's Markdown file.
+func (pr *ProjectRepository) SetHistory(history usecases.HistoryProvider) {
+	pr.history = history
+}
+
+// applyHistory sets element times and authors from version control. It is
+// best-effort: without history the file modification times are kept.
+func (pr *ProjectRepository) applyHistory(ctx context.Context, projectRoot, sourceDir string, systems ...*entities.System) {
+	if pr.history == nil {
+		return
+	}
+	revisions, err := pr.history.ListRevisions(ctx, projectRoot, filepath.ToSlash(sourceDir))
+	if err != nil {
+		return
+	}
+	usecases.ApplySourceHistory(projectRoot, systems, usecases.HistoryByDirectory(revisions))
+}
+
+// ReadSource reads a source file. When only its encrypted counterpart
+// (path + ".age") exists, the file is decrypted transparently.
+func (pr *ProjectRepository) ReadSource(ctx context.Context, path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if !errors.Is(err, fs.ErrNotExist) {
+		return content, err
+	}
+
+	ciphertext, encErr := os.ReadFile(path + usecases.EncryptedExt)
+	if encErr != nil {
+		return nil, err
+	}
+	if pr.encrypter == nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), entities.ErrEncrypted)
+	}
+	plaintext, err := pr.encrypter.Decrypt(ctx, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", filepath.Base(path), err)
+	}
+	return plaintext, nil
+}
+
+// checkContentHash returns a ConflictError if the source file at path no
+// longer has the content hash an element was loaded with. An empty hash, as on
+// elements that were never loaded, skips the check.
+func (pr *ProjectRepository) checkContentHash(ctx context.Context, entity, id, path, hash string) error {
+	if hash == "" {
+		return nil
+	}
+	var actual string
+	content, err := pr.ReadSource(ctx, path)
+	switch {
+	case err == nil:
+		actual = entities.HashContent(content)
+	case !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("failed to check %s for changes: %w", filepath.Base(path), err)
+	}
+	if actual != hash {
+		return &entities.ConflictError{Entity: entity, ID: id, Path: path, Expected: hash, Actual: actual}
+	}
+	return nil
+}
+
+// sourceModTime returns the modification time of a source file in plaintext
+// or encrypted form, or the zero time if it does not exist.
+func sourceModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		if info, err = os.Stat(path + usecases.EncryptedExt); err != nil {
+			return time.Time{}
+		}
+	}
+	return info.ModTime()
+}
+
+// sourceExists reports whether a source file exists in plaintext or encrypted form.
+func sourceExists(path string) bool {
+	if _, err := os.Stat(path); err == nil {
+		return true
+	}
+	_, err := os.Stat(path + usecases.EncryptedExt)
+	return err == nil
+}
+
 // LoadProject retrieves a project by its root directory path.
 // Returns ErrProjectNotFound if the project doesn't exist.
 func (pr *ProjectRepository) LoadProject(ctx context.Context, projectRoot string) (*entities.Project, error) {
@@ -49,6 +177,9 @@ func (pr *ProjectRepository) LoadProject(ctx context.Context, projectRoot string
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
+	if config.SchemaVersion > entities.CurrentSchemaVersion {
+		return nil, fmt.Errorf("%w: schema_version %d, this loko supports up to %d", entities.ErrSchemaTooNew, config.SchemaVersion, entities.CurrentSchemaVersion)
+	}
 
 	// If project name not found in config, use directory name
 	if projectName == "" {
@@ -68,7 +199,7 @@ func (pr *ProjectRepository) LoadProject(ctx context.Context, projectRoot string
 	// Load systems from src directory
 	srcDir := filepath.Join(projectRoot, config.SourceDir)
 	if _, err := os.Stat(srcDir); err == nil {
-		systems, err := pr.loadSystems(ctx, srcDir)
+		systems, err := pr.loadSystems(ctx, sourceRoots(projectRoot, config))
 		if err != nil {
 			return nil, fmt.Errorf("failed to load systems: %w", err)
 		}
@@ -78,6 +209,16 @@ func (pr *ProjectRepository) LoadProject(ctx context.Context, projectRoot string
 				return nil, fmt.Errorf("failed to add system: %w", err)
 			}
 		}
+
+		people, err := pr.loadPeople(ctx, sourceRoots(projectRoot, config))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load people: %w", err)
+		}
+		for _, person := range people {
+			if err := project.AddPerson(person); err != nil {
+				return nil, fmt.Errorf("failed to add person: %w", err)
+			}
+		}
 	}
 
 	return project, nil
@@ -132,7 +273,7 @@ func (pr *ProjectRepository) SaveSystem(ctx context.Context, projectRoot string,
 	}
 
 	// Create system directory
-	systemDir := filepath.Join(projectRoot, config.SourceDir, system.ID)
+	systemDir := resolveSystemDir(projectRoot, config, system.ID)
 	if err := os.MkdirAll(systemDir, 0755); err != nil {
 		return fmt.Errorf("failed to create system directory: %w", err)
 	}
@@ -142,6 +283,9 @@ func (pr *ProjectRepository) SaveSystem(ctx context.Context, projectRoot string,
 	// Create system.md with YAML frontmatter
 	// Try template engine first, fall back to hardcoded generation
 	systemMdPath := filepath.Join(systemDir, "system.md")
+	if err := pr.checkContentHash(ctx, "System", system.ID, systemMdPath, system.ContentHash); err != nil {
+		return err
+	}
 	var content string
 	if pr.templateEngine != nil {
 		variables := map[string]string{
@@ -154,16 +298,23 @@ func (pr *ProjectRepository) SaveSystem(ctx context.Context, projectRoot string,
 		}
 		rendered, err := pr.templateEngine.RenderTemplate(context.Background(), "system.md", variables)
 		if err == nil {
-			content = rendered
+			// Values are inserted as is, so a quote in a description can
+			// leave frontmatter that no longer parses.
+			_, _, err = pr.codec.Decode([]byte(rendered))
+		}
+		if err == nil {
+			content = withFrontmatterMap(rendered, "relationships", system.Relationships)
 		} else {
 			content = pr.generateSystemMarkdown(system)
 		}
 	} else {
 		content = pr.generateSystemMarkdown(system)
 	}
+	content = pr.withFrontmatterMetadata(content, system.Metadata)
 	if err := os.WriteFile(systemMdPath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write system.md: %w", err)
 	}
+	system.ContentHash = entities.HashContent([]byte(content))
 
 	return nil
 }
@@ -190,7 +341,7 @@ func (pr *ProjectRepository) SaveContainer(ctx context.Context, projectRoot, sys
 	}
 
 	// Create container directory
-	containerDir := filepath.Join(projectRoot, config.SourceDir, systemName, container.ID)
+	containerDir := elementDir(resolveSystemDir(projectRoot, config, systemName), container.ID)
 	if err := os.MkdirAll(containerDir, 0755); err != nil {
 		return fmt.Errorf("failed to create container directory: %w", err)
 	}
@@ -200,6 +351,9 @@ func (pr *ProjectRepository) SaveContainer(ctx context.Context, projectRoot, sys
 	// Create container.md with YAML frontmatter
 	// Try template engine first, fall back to hardcoded generation
 	containerMdPath := filepath.Join(containerDir, "container.md")
+	if err := pr.checkContentHash(ctx, "Container", container.ID, containerMdPath, container.ContentHash); err != nil {
+		return err
+	}
 	var content string
 	if pr.templateEngine != nil {
 		variables := map[string]string{
@@ -210,16 +364,22 @@ func (pr *ProjectRepository) SaveContainer(ctx context.Context, projectRoot, sys
 		}
 		rendered, err := pr.templateEngine.RenderTemplate(context.Background(), "container.md", variables)
 		if err == nil {
-			content = rendered
+			_, _, err = pr.codec.Decode([]byte(rendered))
+		}
+		if err == nil {
+			content = withFrontmatterList(rendered, "tags", container.Tags)
+			content = withFrontmatterMap(content, "relationships", container.Relationships)
 		} else {
 			content = pr.generateContainerMarkdown(container)
 		}
 	} else {
 		content = pr.generateContainerMarkdown(container)
 	}
+	content = pr.withFrontmatterMetadata(content, container.Metadata)
 	if err := os.WriteFile(containerMdPath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write container.md: %w", err)
 	}
+	container.ContentHash = entities.HashContent([]byte(content))
 
 	return nil
 }
@@ -237,8 +397,12 @@ func (pr *ProjectRepository) ListSystems(ctx context.Context, projectRoot string
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	srcDir := filepath.Join(projectRoot, config.SourceDir)
-	return pr.loadSystems(ctx, srcDir)
+	systems, err := pr.loadSystems(ctx, sourceRoots(projectRoot, config))
+	if err != nil {
+		return nil, err
+	}
+	pr.applyHistory(ctx, projectRoot, config.SourceDir, systems...)
+	return systems, nil
 }
 
 // LoadSystem retrieves a system by name within a project.
@@ -258,8 +422,18 @@ func (pr *ProjectRepository) LoadSystem(ctx context.Context, projectRoot, system
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	systemDir := filepath.Join(projectRoot, config.SourceDir, systemName)
-	return pr.loadSystemFromDir(ctx, systemDir)
+	systemDir := resolveSystemDir(projectRoot, config, systemName)
+	systemPath, err := filepath.Rel(projectRoot, systemDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve system directory: %w", err)
+	}
+	system, err := pr.loadSystemFromDir(ctx, systemDir)
+	if err != nil {
+		return nil, err
+	}
+	system.SetDomain(entities.SystemDomain(systemName))
+	pr.applyHistory(ctx, projectRoot, systemPath, system)
+	return system, nil
 }
 
 // LoadContainer retrieves a container by name within a system.
@@ -283,7 +457,7 @@ func (pr *ProjectRepository) LoadContainer(ctx context.Context, projectRoot, sys
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	containerDir := filepath.Join(projectRoot, config.SourceDir, systemName, containerName)
+	containerDir := elementDir(resolveSystemDir(projectRoot, config, systemName), containerName)
 	return pr.loadContainerFromDir(ctx, containerDir)
 }
 
@@ -313,7 +487,7 @@ func (pr *ProjectRepository) SaveComponent(ctx context.Context, projectRoot, sys
 	}
 
 	// Create component directory
-	componentDir := filepath.Join(projectRoot, config.SourceDir, systemName, containerName, component.ID)
+	componentDir := elementDir(resolveSystemDir(projectRoot, config, systemName), containerName+"/"+entities.ComponentSourcePath(component.ID))
 	if err := os.MkdirAll(componentDir, 0755); err != nil {
 		return fmt.Errorf("failed to create component directory: %w", err)
 	}
@@ -324,6 +498,9 @@ func (pr *ProjectRepository) SaveComponent(ctx context.Context, projectRoot, sys
 	// T055: Use technology-specific template if component.ContentTemplate is set,
 	// otherwise fall back to the generic "component.md" template.
 	componentMdPath := filepath.Join(componentDir, "component.md")
+	if err := pr.checkContentHash(ctx, "Component", component.ID, componentMdPath, component.ContentHash); err != nil {
+		return err
+	}
 	var content string
 	if pr.templateEngine != nil {
 		variables := map[string]string{
@@ -342,6 +519,9 @@ func (pr *ProjectRepository) SaveComponent(ctx context.Context, projectRoot, sys
 			// Category-specific template not found; fall back to generic.
 			rendered, err = pr.templateEngine.RenderTemplate(context.Background(), "component.md", variables)
 		}
+		if err == nil {
+			_, _, err = pr.codec.Decode([]byte(rendered))
+		}
 		if err == nil {
 			content = rendered
 		} else {
@@ -350,12 +530,14 @@ func (pr *ProjectRepository) SaveComponent(ctx context.Context, projectRoot, sys
 	} else {
 		content = pr.generateComponentMarkdown(component)
 	}
+	content = pr.withFrontmatterMetadata(content, component.Metadata)
 	if err := os.WriteFile(componentMdPath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write component.md: %w", err)
 	}
+	component.ContentHash = entities.HashContent([]byte(content))
 
 	// Create basic D2 diagram template (optional - if it doesn't exist)
-	d2Path := filepath.Join(componentDir, component.ID+".d2")
+	d2Path := filepath.Join(componentDir, filepath.Base(componentDir)+".d2")
 	if _, err := os.Stat(d2Path); os.IsNotExist(err) {
 		var d2Content string
 
@@ -410,50 +592,254 @@ func (pr *ProjectRepository) LoadComponent(ctx context.Context, projectRoot, sys
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	componentDir := filepath.Join(projectRoot, config.SourceDir, systemName, containerName, componentName)
-	return pr.loadComponentFromDir(ctx, componentDir)
+	componentDir := elementDir(resolveSystemDir(projectRoot, config, systemName), containerName+"/"+entities.ComponentSourcePath(componentName))
+	component, err := pr.loadComponentFromDir(ctx, componentDir)
+	if err != nil {
+		return nil, err
+	}
+	if i := strings.LastIndex(componentName, entities.ComponentSeparator); i >= 0 {
+		component.SetParent(componentName[:i])
+	}
+	return component, nil
+}
+
+// ListPeople returns all people in a project.
+func (pr *ProjectRepository) ListPeople(ctx context.Context, projectRoot string) ([]*entities.Person, error) {
+	if projectRoot == "" {
+		return nil, fmt.Errorf("project root cannot be empty")
+	}
+
+	// Load config to get source directory
+	configPath := filepath.Join(projectRoot, "loko.toml")
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	return pr.loadPeople(ctx, sourceRoots(projectRoot, config))
+}
+
+// SavePerson persists a person to src/<person>/person.md.
+func (pr *ProjectRepository) SavePerson(ctx context.Context, projectRoot string, person *entities.Person) error {
+	if person == nil {
+		return fmt.Errorf("person cannot be nil")
+	}
+
+	if projectRoot == "" {
+		return fmt.Errorf("project root cannot be empty")
+	}
+
+	// Load config to get source directory
+	configPath := filepath.Join(projectRoot, "loko.toml")
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	personDir := person.Path
+	if personDir == "" {
+		roots := sourceRoots(projectRoot, config)
+		if len(roots) == 0 {
+			roots = []string{projectRoot}
+		}
+		personDir = elementDir(roots[0], person.ID)
+	}
+	if sourceExists(filepath.Join(personDir, "system.md")) {
+		return fmt.Errorf("%w: %s already holds the system %q", entities.ErrPathCollision, personDir, person.ID)
+	}
+	if err := os.MkdirAll(personDir, 0755); err != nil {
+		return fmt.Errorf("failed to create person directory: %w", err)
+	}
+
+	person.Path = personDir
+
+	personMdPath := filepath.Join(personDir, "person.md")
+	if err := pr.checkContentHash(ctx, "Person", person.ID, personMdPath, person.ContentHash); err != nil {
+		return err
+	}
+	content := pr.withFrontmatterMetadata(generatePersonMarkdown(person), person.Metadata)
+	if err := os.WriteFile(personMdPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write person.md: %w", err)
+	}
+	person.ContentHash = entities.HashContent([]byte(content))
+
+	return nil
 }
 
 // Helper functions
 
-// loadSystems loads all systems from a source directory.
-func (pr *ProjectRepository) loadSystems(ctx context.Context, srcDir string) ([]*entities.System, error) {
-	entries, err := os.ReadDir(srcDir)
+// loadSystems loads all systems from the source roots. A system reached
+// through several paths, e.g. a symlink into another source root, is loaded
+// once; different directories holding systems with the same ID fail with
+// entities.ErrPathCollision.
+func (pr *ProjectRepository) loadSystems(ctx context.Context, roots []string) ([]*entities.System, error) {
+	var systems []*entities.System
+	dirs := make(map[string]string) // By real path of the system directory
+	byID := make(map[string]string) // System directory by ID
+	for _, root := range roots {
+		rootSystems, err := pr.loadDomainSystems(ctx, root, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, sys := range rootSystems {
+			real := realPath(sys.Path)
+			if _, ok := dirs[real]; ok {
+				continue
+			}
+			if other, ok := byID[sys.ID]; ok {
+				return nil, fmt.Errorf("%w: %s and %s both hold the system %q; rename one of them",
+					entities.ErrPathCollision, other, sys.Path, sys.ID)
+			}
+			dirs[real] = sys.Path
+			byID[sys.ID] = sys.Path
+			systems = append(systems, sys)
+		}
+	}
+	return systems, nil
+}
+
+// loadDomainSystems loads the systems of a domain directory. Subdirectories
+// without a system.md are nested domains, so src/payments/billing/system.md
+// is the system "payments.billing".
+func (pr *ProjectRepository) loadDomainSystems(ctx context.Context, dir, domain string) ([]*entities.System, error) {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read source directory: %w", err)
 	}
 
 	var systems []*entities.System
+	siblings := newSiblingIDs(dir)
 	for _, entry := range entries {
-		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
-			sys, err := pr.loadSystemFromDir(ctx, filepath.Join(srcDir, entry.Name()))
+		if !followDir(dir, entry) || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if err := siblings.add(entry.Name()); err != nil {
+			return nil, err
+		}
+		path := filepath.Join(dir, entry.Name())
+		if sourceExists(filepath.Join(path, "person.md")) {
+			continue // People are loaded by loadPeople
+		}
+		if !sourceExists(filepath.Join(path, "system.md")) {
+			nested := entities.NormalizeName(entry.Name())
+			if domain != "" {
+				nested = domain + entities.DomainSeparator + nested
+			}
+			domainSystems, err := pr.loadDomainSystems(ctx, path, nested)
 			if err != nil {
-				// Log but continue loading other systems
-				continue
+				return nil, err
 			}
-			systems = append(systems, sys)
+			systems = append(systems, domainSystems...)
+			continue
+		}
+
+		sys, err := pr.loadSystemFromDir(ctx, path)
+		if failsLoad(err) {
+			return nil, err
+		}
+		if err != nil {
+			// Log but continue loading other systems
+			continue
 		}
+		sys.SetDomain(domain)
+		systems = append(systems, sys)
 	}
 
 	return systems, nil
 }
 
+// loadPeople loads the people of the source roots: the top-level
+// directories holding a person.md. Like systems, a person reached through
+// several paths is loaded once.
+func (pr *ProjectRepository) loadPeople(ctx context.Context, roots []string) ([]*entities.Person, error) {
+	var people []*entities.Person
+	dirs := make(map[string]bool) // By real path of the person directory
+	byID := make(map[string]string)
+	for _, root := range roots {
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read source directory: %w", err)
+		}
+		for _, entry := range entries {
+			if !followDir(root, entry) || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			path := filepath.Join(root, entry.Name())
+			if !sourceExists(filepath.Join(path, "person.md")) || dirs[realPath(path)] {
+				continue
+			}
+			person, err := pr.loadPersonFromDir(ctx, path)
+			if failsLoad(err) {
+				return nil, err
+			}
+			if err != nil {
+				continue
+			}
+			if other, ok := byID[person.ID]; ok {
+				return nil, fmt.Errorf("%w: %s and %s both hold the person %q; rename one of them",
+					entities.ErrPathCollision, other, path, person.ID)
+			}
+			dirs[realPath(path)] = true
+			byID[person.ID] = path
+			people = append(people, person)
+		}
+	}
+	return people, nil
+}
+
+// loadPersonFromDir loads a person from a directory.
+func (pr *ProjectRepository) loadPersonFromDir(ctx context.Context, personDir string) (*entities.Person, error) {
+	personMdPath := filepath.Join(personDir, "person.md")
+	content, err := pr.ReadSource(ctx, personMdPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read person.md: %w", err)
+	}
+
+	fm, err := pr.decodeFrontmatter(personMdPath, content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", personMdPath, err)
+	}
+	name := fm.String("name")
+	if name == "" {
+		name = filepath.Base(personDir)
+	}
+
+	person, err := entities.NewPerson(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create person: %w", err)
+	}
+
+	person.Description = fm.String("description")
+	person.Tags = fm.List("tags")
+	person.External = fm.Flag("external")
+	person.Relationships = fm.StringMap("relationships")
+	person.Metadata = fm.Metadata()
+	person.Path = personDir
+	person.ContentHash = entities.HashContent(content)
+
+	return person, nil
+}
+
 // loadSystemFromDir loads a system from a directory.
 func (pr *ProjectRepository) loadSystemFromDir(ctx context.Context, systemDir string) (*entities.System, error) {
 	// Check if system.md exists
 	systemMdPath := filepath.Join(systemDir, "system.md")
-	if _, err := os.Stat(systemMdPath); err != nil {
-		return nil, fmt.Errorf("system.md not found: %w", err)
+	if !sourceExists(systemMdPath) {
+		return nil, fmt.Errorf("system.md not found: %w", fs.ErrNotExist)
 	}
 
 	// Read system.md
-	content, err := os.ReadFile(systemMdPath)
+	content, err := pr.ReadSource(ctx, systemMdPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read system.md: %w", err)
 	}
 
 	// Parse frontmatter and create system
-	name, description, tags := pr.parseFrontmatterWithTags(string(content))
+	fm, err := pr.decodeFrontmatter(systemMdPath, content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", systemMdPath, err)
+	}
+	name := fm.String("name")
 	if name == "" {
 		name = filepath.Base(systemDir)
 	}
@@ -463,19 +849,34 @@ func (pr *ProjectRepository) loadSystemFromDir(ctx context.Context, systemDir st
 		return nil, fmt.Errorf("failed to create system: %w", err)
 	}
 
-	system.Description = description
-	system.Tags = tags
+	system.Description = fm.String("description")
+	system.Tags = fm.List("tags")
+	system.Issues = fm.List("issues")
+	system.Exports = fm.List("export")
+	system.Metadata = fm.Metadata()
+	system.Review = fm.Review()
+	system.External = fm.Flag("external")
+	system.Relationships = fm.StringMap("relationships")
+	system.UpdatedAt = sourceModTime(systemMdPath)
 	system.Path = systemDir
+	system.ContentHash = entities.HashContent(content)
 
 	// Load system diagram if it exists
-	system.Diagram = pr.loadDiagramFromDir(systemDir)
+	system.Diagram = pr.loadDiagramFromDir(ctx, systemDir)
 
 	// Load containers
 	entries, err := os.ReadDir(systemDir)
 	if err == nil {
+		siblings := newSiblingIDs(systemDir)
 		for _, entry := range entries {
-			if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			if followDir(systemDir, entry) && !strings.HasPrefix(entry.Name(), ".") {
+				if err := siblings.add(entry.Name()); err != nil {
+					return nil, err
+				}
 				container, err := pr.loadContainerFromDir(ctx, filepath.Join(systemDir, entry.Name()))
+				if failsLoad(err) {
+					return nil, err
+				}
 				if err == nil {
 					_ = system.AddContainer(container)
 				}
@@ -490,18 +891,22 @@ func (pr *ProjectRepository) loadSystemFromDir(ctx context.Context, systemDir st
 func (pr *ProjectRepository) loadContainerFromDir(ctx context.Context, containerDir string) (*entities.Container, error) {
 	// Check if container.md exists
 	containerMdPath := filepath.Join(containerDir, "container.md")
-	if _, err := os.Stat(containerMdPath); err != nil {
-		return nil, fmt.Errorf("container.md not found: %w", err)
+	if !sourceExists(containerMdPath) {
+		return nil, fmt.Errorf("container.md not found: %w", fs.ErrNotExist)
 	}
 
 	// Read container.md
-	content, err := os.ReadFile(containerMdPath)
+	content, err := pr.ReadSource(ctx, containerMdPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read container.md: %w", err)
 	}
 
 	// Parse frontmatter and create container
-	name, description := pr.parseFrontmatter(string(content))
+	fm, err := pr.decodeFrontmatter(containerMdPath, content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", containerMdPath, err)
+	}
+	name := fm.String("name")
 	if name == "" {
 		name = filepath.Base(containerDir)
 	}
@@ -511,100 +916,91 @@ func (pr *ProjectRepository) loadContainerFromDir(ctx context.Context, container
 		return nil, fmt.Errorf("failed to create container: %w", err)
 	}
 
-	container.Description = description
+	container.Description = fm.String("description")
+	if tags := fm.List("tags"); tags != nil {
+		container.Tags = tags
+	}
+	container.Issues = fm.List("issues")
+	container.Controls = fm.List("controls")
+	container.Relationships = fm.StringMap("relationships")
+	container.Metadata = fm.Metadata()
+	container.Review = fm.Review()
+	container.UpdatedAt = sourceModTime(containerMdPath)
 	container.Path = containerDir
+	container.ContentHash = entities.HashContent(content)
 
 	// Load container diagram if it exists
-	container.Diagram = pr.loadDiagramFromDir(containerDir)
+	container.Diagram = pr.loadDiagramFromDir(ctx, containerDir)
 
 	// Load components
-	entries, err := os.ReadDir(containerDir)
-	if err == nil {
-		for _, entry := range entries {
-			if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
-				component, err := pr.loadComponentFromDir(ctx, filepath.Join(containerDir, entry.Name()))
-				if err == nil {
-					_ = container.AddComponent(component)
-				}
-			}
-		}
+	if err := pr.loadComponents(ctx, containerDir, "", container); err != nil {
+		return nil, err
 	}
 
 	return container, nil
 }
 
-// parseFrontmatter extracts name, description, and tags from YAML frontmatter.
-// Format: ---\nname: ".."\ndescription: ".."\ntags:\n  - "tag"\n---\n
-func (pr *ProjectRepository) parseFrontmatter(content string) (name, description string) {
-	name, description, _ = pr.parseFrontmatterWithTags(content)
-	return name, description
-}
-
-// parseFrontmatterWithTags extracts name, description, and tags from YAML frontmatter.
-func (pr *ProjectRepository) parseFrontmatterWithTags(content string) (name, description string, tags []string) {
-	lines := strings.Split(content, "\n")
-	if len(lines) < 3 || lines[0] != "---" {
-		return "", "", nil
+// loadComponents adds the components in dir to container. Component
+// directories containing component directories of their own hold
+// sub-components, which are added with IDs prefixed by parent.
+func (pr *ProjectRepository) loadComponents(ctx context.Context, dir, parent string, container *entities.Container) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
 	}
-
-	inTags := false
-	for i := 1; i < len(lines); i++ {
-		line := lines[i]
-		if line == "---" {
-			break
-		}
-
-		if after, ok := strings.CutPrefix(line, "name:"); ok {
-			name = strings.TrimSpace(after)
-			name = strings.Trim(name, "\"'")
-			inTags = false
+	siblings := newSiblingIDs(dir)
+	for _, entry := range entries {
+		if !followDir(dir, entry) || strings.HasPrefix(entry.Name(), ".") {
 			continue
 		}
-
-		if after, ok := strings.CutPrefix(line, "description:"); ok {
-			description = strings.TrimSpace(after)
-			description = strings.Trim(description, "\"'")
-			inTags = false
+		if err := siblings.add(entry.Name()); err != nil {
+			return err
+		}
+		path := filepath.Join(dir, entry.Name())
+		component, err := pr.loadComponentFromDir(ctx, path)
+		if failsLoad(err) {
+			return err
+		}
+		if err != nil {
 			continue
 		}
-
-		if strings.TrimSpace(line) == "tags:" {
-			inTags = true
+		component.SetParent(parent)
+		if err := container.AddComponent(component); err != nil {
 			continue
 		}
-
-		if inTags {
-			trimmed := strings.TrimSpace(line)
-			if strings.HasPrefix(trimmed, "- ") {
-				tag := strings.TrimPrefix(trimmed, "- ")
-				tag = strings.Trim(tag, "\"'")
-				tags = append(tags, tag)
-			} else if trimmed != "" {
-				inTags = false // end of tags block
-			}
+		if err := pr.loadComponents(ctx, path, component.ID, container); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	return name, description, tags
+// failsLoad reports whether err, from loading one element, fails loading the
+// project rather than skipping the element: an element that cannot be
+// decrypted or parsed would otherwise silently disappear from the model.
+func failsLoad(err error) bool {
+	return errors.Is(err, entities.ErrEncrypted) ||
+		errors.Is(err, entities.ErrPathCollision) ||
+		errors.Is(err, entities.ErrInvalidFrontmatter)
 }
 
 // loadDiagramFromDir loads a D2 diagram from a directory if it exists.
 // Returns nil if no diagram file is found (diagram is optional).
-func (pr *ProjectRepository) loadDiagramFromDir(dirPath string) *entities.Diagram {
+func (pr *ProjectRepository) loadDiagramFromDir(ctx context.Context, dirPath string) *entities.Diagram {
 	// Check for system.d2 or container.d2
 	diagramPath := filepath.Join(dirPath, filepath.Base(dirPath)+".d2")
 
 	// Try alternate naming: just ".d2" in the directory
-	if _, err := os.Stat(diagramPath); err != nil {
+	if !sourceExists(diagramPath) {
 		diagramPath = filepath.Join(dirPath, "system.d2")
-		if _, err := os.Stat(diagramPath); err != nil {
+		if !sourceExists(diagramPath) {
 			// No diagram file found
 			return nil
 		}
 	}
 
 	// Read the D2 file
-	content, err := os.ReadFile(diagramPath)
+	content, err := pr.ReadSource(ctx, diagramPath)
 	if err != nil {
 		// If reading fails, just return nil (diagram is optional)
 		return nil
@@ -637,6 +1033,13 @@ func (pr *ProjectRepository) generateSystemMarkdown(system *entities.System) str
 			sb.WriteString(fmt.Sprintf("  - %q\n", tag))
 		}
 	}
+	writeFrontmatterList(&sb, "issues", system.Issues)
+	writeFrontmatterList(&sb, "export", system.Exports)
+	if system.External {
+		sb.WriteString("external: true\n")
+	}
+	writeFrontmatterReview(&sb, system.Review)
+	writeFrontmatterMap(&sb, "relationships", system.Relationships)
 	sb.WriteString("---\n\n")
 	sb.WriteString(fmt.Sprintf("# %s\n\n", system.Name))
 	if system.Description != "" {
@@ -721,6 +1124,57 @@ func (pr *ProjectRepository) generateSystemMarkdown(system *entities.System) str
 	return sb.String()
 }
 
+// generatePersonMarkdown generates markdown content for a person.
+func generatePersonMarkdown(person *entities.Person) string {
+	var sb strings.Builder
+
+	sb.WriteString("---\n")
+	sb.WriteString(fmt.Sprintf("name: %q\n", person.Name))
+	if person.Description != "" {
+		sb.WriteString(fmt.Sprintf("description: %q\n", person.Description))
+	}
+	writeFrontmatterList(&sb, "tags", person.Tags)
+	if person.External {
+		sb.WriteString("external: true\n")
+	}
+	writeFrontmatterMap(&sb, "relationships", person.Relationships)
+	sb.WriteString("---\n\n")
+	sb.WriteString(fmt.Sprintf("# %s\n\n", person.Name))
+	if person.Description != "" {
+		sb.WriteString(person.Description)
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// writeFrontmatterList writes a YAML list of quoted items, skipping empty lists.
+func writeFrontmatterList(sb *strings.Builder, key string, items []string) {
+	writeFrontmatterNode(sb, key, listNode(items))
+}
+
+// writeFrontmatterMap writes a YAML map of quoted values in sorted key
+// order, skipping empty maps.
+func writeFrontmatterMap(sb *strings.Builder, key string, entries map[string]string) {
+	writeFrontmatterNode(sb, key, mapNode(entries))
+}
+
+// writeFrontmatterNode writes the top-level key with value encoded as YAML,
+// so keys and values that need quoting are quoted. A nil value writes
+// nothing.
+func writeFrontmatterNode(sb *strings.Builder, key string, value *yaml.Node) {
+	if value == nil {
+		return
+	}
+	fields := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	fields.Content = []*yaml.Node{{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value}
+	// Encoding string nodes into a strings.Builder cannot fail.
+	encoder := yaml.NewEncoder(sb)
+	encoder.SetIndent(2)
+	_ = encoder.Encode(fields)
+	_ = encoder.Close()
+}
+
 // generateContainerMarkdown generates markdown content for a container.
 func (pr *ProjectRepository) generateContainerMarkdown(container *entities.Container) string {
 	var sb strings.Builder
@@ -733,6 +1187,11 @@ func (pr *ProjectRepository) generateContainerMarkdown(container *entities.Conta
 	if container.Technology != "" {
 		sb.WriteString(fmt.Sprintf("technology: %q\n", container.Technology))
 	}
+	writeFrontmatterList(&sb, "tags", container.Tags)
+	writeFrontmatterList(&sb, "issues", container.Issues)
+	writeFrontmatterList(&sb, "controls", container.Controls)
+	writeFrontmatterReview(&sb, container.Review)
+	writeFrontmatterMap(&sb, "relationships", container.Relationships)
 	sb.WriteString("---\n\n")
 	sb.WriteString(fmt.Sprintf("# %s\n\n", container.Name))
 	if container.Description != "" {
@@ -834,16 +1293,14 @@ func (pr *ProjectRepository) generateComponentMarkdown(component *entities.Compo
 			sb.WriteString(fmt.Sprintf("  - %q\n", tag))
 		}
 	}
-	if len(component.Relationships) > 0 {
-		sb.WriteString("relationships:\n")
-		for targetID, desc := range component.Relationships {
-			sb.WriteString(fmt.Sprintf("  %s: %q\n", targetID, desc))
-		}
-	}
+	writeFrontmatterList(&sb, "issues", component.Issues)
+	writeFrontmatterList(&sb, "controls", component.Controls)
+	writeFrontmatterReview(&sb, component.Review)
+	writeFrontmatterMap(&sb, "relationships", component.Relationships)
 	if len(component.CodeAnnotations) > 0 {
 		sb.WriteString("code_annotations:\n")
-		for path, desc := range component.CodeAnnotations {
-			sb.WriteString(fmt.Sprintf("  %q: %q\n", path, desc))
+		for _, path := range slices.Sorted(maps.Keys(component.CodeAnnotations)) {
+			sb.WriteString(fmt.Sprintf("  %q: %q\n", path, component.CodeAnnotations[path]))
 		}
 	}
 	if len(component.Dependencies) > 0 {
@@ -852,6 +1309,7 @@ func (pr *ProjectRepository) generateComponentMarkdown(component *entities.Compo
 			sb.WriteString(fmt.Sprintf("  - %q\n", dep))
 		}
 	}
+	writeFrontmatterList(&sb, "aliases", component.Aliases)
 	sb.WriteString("---\n\n")
 	sb.WriteString(fmt.Sprintf("# %s\n\n", component.Name))
 	if component.Description != "" {
@@ -895,8 +1353,8 @@ func (pr *ProjectRepository) generateComponentMarkdown(component *entities.Compo
 	if len(component.Relationships) > 0 {
 		sb.WriteString("### Component Relationships\n\n")
 		sb.WriteString("This component depends on:\n\n")
-		for targetID, desc := range component.Relationships {
-			sb.WriteString(fmt.Sprintf("- **%s**: %s\n", targetID, desc))
+		for _, targetID := range slices.Sorted(maps.Keys(component.Relationships)) {
+			sb.WriteString(fmt.Sprintf("- **%s**: %s\n", targetID, component.Relationships[targetID]))
 		}
 		sb.WriteString("\n")
 	}
@@ -911,8 +1369,8 @@ func (pr *ProjectRepository) generateComponentMarkdown(component *entities.Compo
 
 	if len(component.CodeAnnotations) > 0 {
 		sb.WriteString("### Code Locations\n\n")
-		for path, desc := range component.CodeAnnotations {
-			sb.WriteString(fmt.Sprintf("- `%s`: %s\n", path, desc))
+		for _, path := range slices.Sorted(maps.Keys(component.CodeAnnotations)) {
+			sb.WriteString(fmt.Sprintf("- `%s`: %s\n", path, component.CodeAnnotations[path]))
 		}
 		sb.WriteString("\n")
 	}
@@ -931,21 +1389,25 @@ func (pr *ProjectRepository) generateComponentMarkdown(component *entities.Compo
 }
 
 // loadComponentFromDir loads a component from a directory.
-func (pr *ProjectRepository) loadComponentFromDir(_ context.Context, componentDir string) (*entities.Component, error) {
+func (pr *ProjectRepository) loadComponentFromDir(ctx context.Context, componentDir string) (*entities.Component, error) {
 	// Check if component.md exists
 	componentMdPath := filepath.Join(componentDir, "component.md")
-	if _, err := os.Stat(componentMdPath); err != nil {
-		return nil, fmt.Errorf("component.md not found: %w", err)
+	if !sourceExists(componentMdPath) {
+		return nil, fmt.Errorf("component.md not found: %w", fs.ErrNotExist)
 	}
 
 	// Read component.md
-	content, err := os.ReadFile(componentMdPath)
+	content, err := pr.ReadSource(ctx, componentMdPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read component.md: %w", err)
 	}
 
 	// Parse frontmatter and create component
-	name, description, technology, tags, relationships, annotations, deps := pr.parseComponentFrontmatter(string(content))
+	fm, err := pr.decodeFrontmatter(componentMdPath, content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", componentMdPath, err)
+	}
+	name := fm.String("name")
 	if name == "" {
 		name = filepath.Base(componentDir)
 	}
@@ -955,128 +1417,23 @@ func (pr *ProjectRepository) loadComponentFromDir(_ context.Context, componentDi
 		return nil, fmt.Errorf("failed to create component: %w", err)
 	}
 
-	component.Description = description
-	component.Technology = technology
-	component.Tags = tags
-	component.Relationships = relationships
-	component.CodeAnnotations = annotations
-	component.Dependencies = deps
+	component.Description = fm.String("description")
+	component.Technology = fm.String("technology")
+	component.Tags = fm.List("tags")
+	component.Relationships = fm.StringMap("relationships")
+	component.CodeAnnotations = fm.StringMap("code_annotations")
+	component.Dependencies = fm.List("dependencies")
+	component.Issues = fm.List("issues")
+	component.Controls = fm.List("controls")
+	component.Aliases = fm.List("aliases")
+	component.Metadata = fm.Metadata()
+	component.Review = fm.Review()
+	component.UpdatedAt = sourceModTime(componentMdPath)
 	component.Path = componentDir
+	component.ContentHash = entities.HashContent(content)
 
 	// Load component diagram if it exists
-	component.Diagram = pr.loadDiagramFromDir(componentDir)
+	component.Diagram = pr.loadDiagramFromDir(ctx, componentDir)
 
 	return component, nil
 }
-
-// parseComponentFrontmatter extracts metadata from YAML frontmatter for components.
-func (pr *ProjectRepository) parseComponentFrontmatter(content string) (name, description, technology string, tags []string, relationships map[string]string, annotations map[string]string, dependencies []string) {
-	lines := strings.Split(content, "\n")
-	if len(lines) < 3 || lines[0] != "---" {
-		return "", "", "", []string{}, make(map[string]string), make(map[string]string), []string{}
-	}
-
-	relationships = make(map[string]string)
-	annotations = make(map[string]string)
-
-	for i := 1; i < len(lines); i++ {
-		line := lines[i]
-		if line == "---" {
-			break
-		}
-
-		if after, ok := strings.CutPrefix(line, "name:"); ok {
-			name = strings.TrimSpace(after)
-			name = strings.Trim(name, "\"'")
-		}
-
-		if after, ok := strings.CutPrefix(line, "description:"); ok {
-			description = strings.TrimSpace(after)
-			description = strings.Trim(description, "\"'")
-		}
-
-		if after, ok := strings.CutPrefix(line, "technology:"); ok {
-			technology = strings.TrimSpace(after)
-			technology = strings.Trim(technology, "\"'")
-		}
-
-		if strings.HasPrefix(line, "tags:") {
-			// Parse tags array from next lines
-			for j := i + 1; j < len(lines); j++ {
-				tagLine := lines[j]
-				if after, ok := strings.CutPrefix(tagLine, "  - "); ok {
-					tag := strings.TrimSpace(after)
-					tag = strings.Trim(tag, "\"'")
-					tags = append(tags, tag)
-				} else if !strings.HasPrefix(tagLine, " ") || tagLine == "---" {
-					// End of tags section
-					i = j - 1
-					break
-				}
-			}
-		}
-
-		if strings.HasPrefix(line, "relationships:") {
-			// Parse relationships map from next lines
-			for j := i + 1; j < len(lines); j++ {
-				relLine := lines[j]
-				if strings.HasPrefix(relLine, "  ") && strings.Contains(relLine, ":") {
-					// Parse "  componentid: \"description\"" format
-					relLine = strings.TrimPrefix(relLine, "  ")
-					parts := strings.SplitN(relLine, ":", 2)
-					if len(parts) == 2 {
-						targetID := strings.TrimSpace(parts[0])
-						desc := strings.TrimSpace(parts[1])
-						desc = strings.Trim(desc, "\"'")
-						relationships[targetID] = desc
-					}
-				} else if !strings.HasPrefix(relLine, " ") || relLine == "---" {
-					// End of relationships section
-					i = j - 1
-					break
-				}
-			}
-		}
-
-		if strings.HasPrefix(line, "code_annotations:") {
-			// Parse code annotations map from next lines
-			for j := i + 1; j < len(lines); j++ {
-				annLine := lines[j]
-				if strings.HasPrefix(annLine, "  ") && strings.Contains(annLine, ":") {
-					// Parse "  \"path\": \"description\"" format
-					annLine = strings.TrimPrefix(annLine, "  ")
-					parts := strings.SplitN(annLine, ":", 2)
-					if len(parts) == 2 {
-						path := strings.TrimSpace(parts[0])
-						path = strings.Trim(path, "\"'")
-						desc := strings.TrimSpace(parts[1])
-						desc = strings.Trim(desc, "\"'")
-						annotations[path] = desc
-					}
-				} else if !strings.HasPrefix(annLine, " ") || annLine == "---" {
-					// End of annotations section
-					i = j - 1
-					break
-				}
-			}
-		}
-
-		if strings.HasPrefix(line, "dependencies:") {
-			// Parse dependencies array from next lines
-			for j := i + 1; j < len(lines); j++ {
-				depLine := lines[j]
-				if after, ok := strings.CutPrefix(depLine, "  - "); ok {
-					dep := strings.TrimSpace(after)
-					dep = strings.Trim(dep, "\"'")
-					dependencies = append(dependencies, dep)
-				} else if !strings.HasPrefix(depLine, " ") || depLine == "---" {
-					// End of dependencies section
-					i = j - 1
-					break
-				}
-			}
-		}
-	}
-
-	return name, description, technology, tags, relationships, annotations, dependencies
-}
@@ -1,11 +1,33 @@
 package filesystem
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
 )
 
-// TestParseComponentFrontmatter_Relationships verifies that parseComponentFrontmatter
-// correctly extracts the relationships map from component frontmatter YAML.
+// mustDecodeFrontmatter decodes the frontmatter of content or fails the test.
+func mustDecodeFrontmatter(t *testing.T, pr *ProjectRepository, content string) frontmatter {
+	t.Helper()
+	fm, err := pr.decodeFrontmatter("component.md", []byte(content))
+	if err != nil {
+		t.Fatalf("decodeFrontmatter() error = %v", err)
+	}
+	return fm
+}
+
+// TestParseComponentFrontmatter_Relationships verifies that the relationships
+// map is decoded from component frontmatter YAML.
 // This covers T020 requirement for frontmatter relationship parsing.
 func TestParseComponentFrontmatter_Relationships(t *testing.T) {
 	tests := []struct {
@@ -97,11 +119,11 @@ relationships:
 		t.Run(tt.name, func(t *testing.T) {
 			pr := NewProjectRepository()
 
-			_, _, _, _, relationships, _, _ := pr.parseComponentFrontmatter(tt.frontmatter)
+			relationships := mustDecodeFrontmatter(t, pr, tt.frontmatter).StringMap("relationships")
 
 			// Verify relationship count
 			if len(relationships) != tt.expectedRelationshipsLen {
-				t.Errorf("parseComponentFrontmatter() relationships count = %d, want %d",
+				t.Errorf("relationships count = %d, want %d",
 					len(relationships), tt.expectedRelationshipsLen)
 			}
 
@@ -137,10 +159,10 @@ relationships:
 ---
 `
 	pr := NewProjectRepository()
-	_, _, _, _, relationships, _, _ := pr.parseComponentFrontmatter(frontmatter)
+	relationships := mustDecodeFrontmatter(t, pr, frontmatter).StringMap("relationships")
 
 	if relationships == nil {
-		t.Error("parseComponentFrontmatter() should return empty map, not nil")
+		t.Error("relationships should be an empty map, not nil")
 	}
 
 	if len(relationships) != 0 {
@@ -168,7 +190,10 @@ dependencies:
 ---
 `
 	pr := NewProjectRepository()
-	name, description, technology, tags, relationships, annotations, dependencies := pr.parseComponentFrontmatter(frontmatter)
+	fm := mustDecodeFrontmatter(t, pr, frontmatter)
+	name, description, technology := fm.String("name"), fm.String("description"), fm.String("technology")
+	tags, dependencies := fm.List("tags"), fm.List("dependencies")
+	relationships, annotations := fm.StringMap("relationships"), fm.StringMap("code_annotations")
 
 	// Verify all fields are parsed correctly
 	if name != "Order Service" {
@@ -209,3 +234,760 @@ dependencies:
 		t.Errorf("dependencies count = %d, want 1", len(dependencies))
 	}
 }
+
+// TestParseFrontmatterList_Issues verifies that issue references are read from
+// an "issues:" list and that the list ends at the next key.
+func TestParseFrontmatterList_Issues(t *testing.T) {
+	frontmatter := `---
+name: "Ledger"
+issues:
+  - PAY-123
+  - "https://github.com/acme/payments/issues/42"
+tags:
+  - core
+---
+`
+	pr := NewProjectRepository()
+
+	got := mustDecodeFrontmatter(t, pr, frontmatter).List("issues")
+	want := []string{"PAY-123", "https://github.com/acme/payments/issues/42"}
+	if !slices.Equal(got, want) {
+		t.Errorf("issues = %q, want %q", got, want)
+	}
+
+	if got := mustDecodeFrontmatter(t, pr, frontmatter).List("dependencies"); got != nil {
+		t.Errorf("missing key = %q, want nil", got)
+	}
+}
+
+// TestParseFrontmatterList_FlowStyle verifies that a list can also be given
+// inline, as in "export: [pdf, markdown]".
+func TestParseFrontmatterList_FlowStyle(t *testing.T) {
+	frontmatter := "---\nname: \"Payments\"\nexport: [pdf, \"markdown\"]\nexports_note: [x]\n---\n"
+	pr := NewProjectRepository()
+
+	if got := mustDecodeFrontmatter(t, pr, frontmatter).List("export"); !slices.Equal(got, []string{"pdf", "markdown"}) {
+		t.Errorf("export = %q, want [pdf markdown]", got)
+	}
+	if got := mustDecodeFrontmatter(t, pr, frontmatter).Metadata(); got["export"] != nil {
+		t.Errorf("export kept in metadata: %v", got["export"])
+	}
+}
+
+// TestGenerateComponentMarkdown_IssuesRoundTrip verifies generated frontmatter
+// keeps issue references and compliance controls.
+func TestGenerateComponentMarkdown_IssuesRoundTrip(t *testing.T) {
+	component, err := entities.NewComponent("Ledger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	component.Issues = []string{"PAY-123"}
+	component.Controls = []string{"CC6.1"}
+
+	pr := NewProjectRepository()
+	content := pr.generateComponentMarkdown(component)
+	if !strings.Contains(content, "issues:\n  - \"PAY-123\"\n") {
+		t.Errorf("expected issues list in frontmatter, got:\n%s", content)
+	}
+	if got := mustDecodeFrontmatter(t, pr, content).List("issues"); !slices.Equal(got, component.Issues) {
+		t.Errorf("issues = %q, want %q", got, component.Issues)
+	}
+	if got := mustDecodeFrontmatter(t, pr, content).List("controls"); !slices.Equal(got, component.Controls) {
+		t.Errorf("controls = %q, want %q", got, component.Controls)
+	}
+	if metadata := mustDecodeFrontmatter(t, pr, content).Metadata(); metadata["controls"] != nil {
+		t.Errorf("controls kept in metadata: %v", metadata)
+	}
+}
+
+// TestParseFrontmatterReview verifies the review workflow keys are read from
+// and written to frontmatter.
+func TestParseFrontmatterReview(t *testing.T) {
+	pr := NewProjectRepository()
+	content := "---\nname: API\nreview_status: Approved\nreviewed_by: \"Ana\"\nreviewed_at: 2026-03-04\n---\n"
+	review := mustDecodeFrontmatter(t, pr, content).Review()
+	want := entities.Review{Status: entities.ReviewApproved, ReviewedBy: "Ana", ReviewedAt: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)}
+	if review != want {
+		t.Errorf("review = %+v, want %+v", review, want)
+	}
+	if metadata := mustDecodeFrontmatter(t, pr, content).Metadata(); len(metadata) != 0 {
+		t.Errorf("review keys kept in metadata: %v", metadata)
+	}
+
+	container, _ := entities.NewContainer("API")
+	container.Review = want
+	if got := mustDecodeFrontmatter(t, pr, pr.generateContainerMarkdown(container)).Review(); got != want {
+		t.Errorf("round trip review = %+v, want %+v", got, want)
+	}
+}
+
+// TestGenerateSystemMarkdown_External verifies external systems keep their
+// flag when saved and loaded.
+func TestGenerateSystemMarkdown_External(t *testing.T) {
+	pr := NewProjectRepository()
+	system, _ := entities.NewSystem("Stripe")
+	if mustDecodeFrontmatter(t, pr, pr.generateSystemMarkdown(system)).Flag("external") {
+		t.Error("internal system written as external")
+	}
+	system.External = true
+	content := pr.generateSystemMarkdown(system)
+	if !mustDecodeFrontmatter(t, pr, content).Flag("external") {
+		t.Errorf("external flag not written:\n%s", content)
+	}
+	if metadata := mustDecodeFrontmatter(t, pr, content).Metadata(); len(metadata) != 0 {
+		t.Errorf("external kept in metadata: %v", metadata)
+	}
+	if !mustDecodeFrontmatter(t, pr, "---\nname: Stripe\nexternal: \"true\"\n---\n").Flag("external") {
+		t.Error("quoted true not read as true")
+	}
+}
+
+// TestGenerateComponentMarkdown_SortedMaps verifies relationships and code
+// annotations are written sorted, so that saving a component is repeatable.
+func TestGenerateComponentMarkdown_SortedMaps(t *testing.T) {
+	component, err := entities.NewComponent("Ledger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	component.Relationships = map[string]string{"zeta": "z", "alpha": "a", "mid": "m"}
+	component.CodeAnnotations = map[string]string{"src/z.go": "z", "src/a.go": "a"}
+
+	pr := NewProjectRepository()
+	content := pr.generateComponentMarkdown(component)
+	for i := 0; i < 10; i++ {
+		if again := pr.generateComponentMarkdown(component); again != content {
+			t.Fatalf("output differs between runs:\n%s\n---\n%s", content, again)
+		}
+	}
+	want := "relationships:\n  alpha: \"a\"\n  mid: \"m\"\n  zeta: \"z\"\ncode_annotations:\n  \"src/a.go\": \"a\"\n  \"src/z.go\": \"z\"\n"
+	if !strings.Contains(content, want) {
+		t.Errorf("expected sorted frontmatter maps, got:\n%s", content)
+	}
+}
+
+// TestParseFrontmatterMetadata verifies that unknown frontmatter keys are kept
+// as metadata and written back when the element is saved.
+func TestParseFrontmatterMetadata(t *testing.T) {
+	frontmatter := `---
+name: "Ledger"
+owner: "payments-team"
+tier: 1
+pci: true
+compliance:
+  - pci
+  - "sox"
+contacts:
+  oncall: "#payments"
+tags:
+  - core
+---
+`
+	pr := NewProjectRepository()
+	metadata := mustDecodeFrontmatter(t, pr, frontmatter).Metadata()
+	if metadata["owner"] != "payments-team" || metadata["tier"] != 1 || metadata["pci"] != true {
+		t.Errorf("scalars = %v", metadata)
+	}
+	if got, _ := metadata["compliance"].([]string); !slices.Equal(got, []string{"pci", "sox"}) {
+		t.Errorf("compliance = %v", metadata["compliance"])
+	}
+	if got, _ := metadata["contacts"].(map[string]any); got["oncall"] != "#payments" {
+		t.Errorf("contacts = %v", metadata["contacts"])
+	}
+	for _, key := range []string{"name", "tags"} {
+		if _, ok := metadata[key]; ok {
+			t.Errorf("metadata has %q", key)
+		}
+	}
+
+	component, err := entities.NewComponent("Ledger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	component.Metadata = metadata
+	content := pr.withFrontmatterMetadata(pr.generateComponentMarkdown(component), metadata)
+	if !strings.Contains(content, "owner: \"payments-team\"\npci: true\ntier: 1\n---") {
+		t.Errorf("expected metadata before the closing ---, got:\n%s", content)
+	}
+	if got := mustDecodeFrontmatter(t, pr, content).Metadata(); !reflect.DeepEqual(got, metadata) {
+		t.Errorf("round trip = %v, want %v", got, metadata)
+	}
+
+	// Keys already in a rendered template are not duplicated.
+	rendered := pr.withFrontmatterMetadata("---\nname: \"Ledger\"\nowner: \"core\"\n---\n# Ledger\n", metadata)
+	if strings.Count(rendered, "owner:") != 1 || !strings.HasSuffix(rendered, "tier: 1\n---\n# Ledger\n") {
+		t.Errorf("rendered = %q", rendered)
+	}
+
+	// A list is added only when its key is missing.
+	tagged := withFrontmatterList("---\nname: \"Orders\"\n---\n# Orders\n", "tags", []string{"service"})
+	if tagged != "---\nname: \"Orders\"\ntags:\n  - \"service\"\n---\n# Orders\n" {
+		t.Errorf("tagged = %q", tagged)
+	}
+	if got := withFrontmatterList(tagged, "tags", []string{"other"}); got != tagged {
+		t.Errorf("existing tags replaced: %q", got)
+	}
+}
+
+// prefixEncrypter is a reversible stand-in for age used by the encryption tests.
+type prefixEncrypter struct{}
+
+func (prefixEncrypter) Encrypt(_ context.Context, plaintext []byte) ([]byte, error) {
+	return append([]byte("enc:"), plaintext...), nil
+}
+
+func (prefixEncrypter) Decrypt(_ context.Context, ciphertext []byte) ([]byte, error) {
+	plaintext, ok := bytes.CutPrefix(ciphertext, []byte("enc:"))
+	if !ok {
+		return nil, errors.New("not encrypted")
+	}
+	return plaintext, nil
+}
+
+// TestListSystems_EncryptedSources verifies that sources encrypted at rest are
+// decrypted transparently when an encrypter is set, and refused otherwise.
+func TestListSystems_EncryptedSources(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"src/payments/system.md.age":               "enc:---\nname: \"Payments\"\n---\n",
+		"src/payments/system.d2.age":               "enc:api -> db",
+		"src/payments/api/container.md.age":        "enc:---\nname: \"API\"\n---\n",
+		"src/payments/api/ledger/component.md.age": "enc:---\nname: \"Ledger\"\nissues:\n  - PAY-1\n---\n",
+		"src/payments/api/plaintext/component.md":  "---\nname: \"Plaintext\"\n---\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+
+	pr := NewProjectRepository()
+	if _, err := pr.ListSystems(ctx, root); !errors.Is(err, entities.ErrEncrypted) {
+		t.Fatalf("ListSystems without encrypter error = %v, want ErrEncrypted", err)
+	}
+
+	pr.SetEncrypter(prefixEncrypter{})
+	systems, err := pr.ListSystems(ctx, root)
+	if err != nil {
+		t.Fatalf("ListSystems failed: %v", err)
+	}
+	if len(systems) != 1 || systems[0].Name != "Payments" {
+		t.Fatalf("systems = %+v, want Payments", systems)
+	}
+	if systems[0].Diagram == nil || systems[0].Diagram.Source != "api -> db" {
+		t.Errorf("system diagram = %+v, want decrypted source", systems[0].Diagram)
+	}
+	container := systems[0].Containers["api"]
+	if container == nil || len(container.Components) != 2 {
+		t.Fatalf("container = %+v, want API with 2 components", container)
+	}
+	if ledger := container.Components["ledger"]; ledger == nil || !slices.Equal(ledger.Issues, []string{"PAY-1"}) {
+		t.Errorf("ledger = %+v, want decrypted frontmatter", ledger)
+	}
+}
+
+// staticHistory is a HistoryProvider returning fixed revisions.
+type staticHistory []usecases.Revision
+
+func (h staticHistory) ListRevisions(_ context.Context, _, _ string) ([]usecases.Revision, error) {
+	return h, nil
+}
+
+// TestListSystems_SourceTimes verifies that elements get their file
+// modification time, replaced by version control dates and authors when a
+// history provider is set.
+func TestListSystems_SourceTimes(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "src", "payments", "api")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "src", "payments", "system.md"), []byte("---\nname: \"Payments\"\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "container.md"), []byte("---\nname: \"API\"\ntags:\n  - \"deployed-on:eks-prod\"\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(filepath.Join(dir, "container.md"), modified, modified); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	pr := NewProjectRepository()
+	systems, err := pr.ListSystems(ctx, root)
+	if err != nil {
+		t.Fatalf("ListSystems failed: %v", err)
+	}
+	if got := systems[0].Containers["api"].UpdatedAt; !got.Equal(modified) {
+		t.Errorf("container UpdatedAt = %v, want file mtime %v", got, modified)
+	}
+	if got := systems[0].Containers["api"].DeploymentTargets(); !slices.Equal(got, []string{"eks-prod"}) {
+		t.Errorf("container deployment targets = %q, want the tags of container.md", got)
+	}
+
+	created := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	updated := time.Date(2023, 3, 4, 0, 0, 0, 0, time.UTC)
+	pr.SetHistory(staticHistory{
+		{Author: "alice", Date: created, Changes: []usecases.FileRevision{{Path: "src/payments/api/container.md", Status: "added"}}},
+		{Author: "bob", Date: updated, Changes: []usecases.FileRevision{{Path: "src/payments/api/container.md", Status: "modified"}}},
+	})
+	systems, err = pr.ListSystems(ctx, root)
+	if err != nil {
+		t.Fatalf("ListSystems failed: %v", err)
+	}
+	container := systems[0].Containers["api"]
+	if !container.CreatedAt.Equal(created) || !container.UpdatedAt.Equal(updated) || container.Author != "bob" {
+		t.Errorf("container times = %v, %v by %q, want %v, %v by bob", container.CreatedAt, container.UpdatedAt, container.Author, created, updated)
+	}
+}
+
+// TestListSystems_Domains verifies that directories without a system.md are
+// loaded as domains of the systems below them.
+func TestListSystems_Domains(t *testing.T) {
+	root := t.TempDir()
+	for dir, name := range map[string]string{
+		"web":                    "Web",
+		"payments/billing":       "Billing",
+		"payments/cards/issuing": "Issuing",
+	} {
+		path := filepath.Join(root, "src", filepath.FromSlash(dir))
+		if err := os.MkdirAll(filepath.Join(path, "api"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "system.md"), []byte("---\nname: \""+name+"\"\n---\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "api", "container.md"), []byte("---\nname: \"API\"\n---\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+
+	pr := NewProjectRepository()
+	systems, err := pr.ListSystems(ctx, root)
+	if err != nil {
+		t.Fatalf("ListSystems failed: %v", err)
+	}
+	domains := make(map[string]string)
+	for _, sys := range systems {
+		domains[sys.ID] = sys.Domain
+		if api := sys.Containers["api"]; api == nil || api.ParentID != sys.ID {
+			t.Errorf("container of %s = %+v, want parent %s", sys.ID, api, sys.ID)
+		}
+	}
+	want := map[string]string{"web": "", "payments.billing": "payments", "payments.cards.issuing": "payments.cards"}
+	if len(domains) != len(want) {
+		t.Fatalf("systems = %v, want %v", domains, want)
+	}
+	for id, domain := range want {
+		if got, ok := domains[id]; !ok || got != domain {
+			t.Errorf("system %s domain = %q, want %q", id, got, domain)
+		}
+	}
+
+	sys, err := pr.LoadSystem(ctx, root, "payments.cards.issuing")
+	if err != nil {
+		t.Fatalf("LoadSystem failed: %v", err)
+	}
+	if sys.ID != "payments.cards.issuing" || sys.Name != "Issuing" {
+		t.Errorf("LoadSystem() = %s %q, want payments.cards.issuing Issuing", sys.ID, sys.Name)
+	}
+	if _, err := pr.LoadContainer(ctx, root, "payments.billing", "api"); err != nil {
+		t.Errorf("LoadContainer failed: %v", err)
+	}
+}
+
+// TestListSystems_SubComponents verifies that component directories inside a
+// component are loaded as its sub-components.
+func TestListSystems_SubComponents(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"src/shop/system.md":                                "---\nname: \"Shop\"\n---\n",
+		"src/shop/api/container.md":                         "---\nname: \"API\"\n---\n",
+		"src/shop/api/handler/component.md":                 "---\nname: \"Handler\"\n---\n",
+		"src/shop/api/handler/validator/component.md":       "---\nname: \"Validator\"\n---\n",
+		"src/shop/api/handler/validator/validator.d2":       "a -> b\n",
+		"src/shop/api/handler/validator/rules/component.md": "---\nname: \"Rules\"\n---\n",
+		"src/shop/api/handler/notes/readme.txt":             "not a component\n",
+	}
+	for path, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+
+	pr := NewProjectRepository()
+	systems, err := pr.ListSystems(ctx, root)
+	if err != nil {
+		t.Fatalf("ListSystems failed: %v", err)
+	}
+	components := systems[0].Containers["api"].Components
+	if len(components) != 3 {
+		t.Fatalf("components = %v, want handler, handler.validator and handler.validator.rules", components)
+	}
+	validator := components["handler.validator"]
+	if validator == nil || validator.Parent != "handler" || validator.Diagram == nil {
+		t.Errorf("handler.validator = %+v, want parent handler with a diagram", validator)
+	}
+	if rules := components["handler.validator.rules"]; rules == nil || rules.Parent != "handler.validator" {
+		t.Errorf("handler.validator.rules = %+v, want parent handler.validator", rules)
+	}
+
+	comp, err := pr.LoadComponent(ctx, root, "shop", "api", "handler.validator")
+	if err != nil {
+		t.Fatalf("LoadComponent failed: %v", err)
+	}
+	if comp.ID != "handler.validator" || comp.Name != "Validator" {
+		t.Errorf("LoadComponent() = %s %q, want handler.validator Validator", comp.ID, comp.Name)
+	}
+}
+
+// TestListSystems_Frontmatter verifies that nested metadata is loaded as
+// written, that frontmatter that is not valid YAML is read line by line with
+// a warning, and that frontmatter without a closing line fails the load,
+// naming the file, instead of dropping the element.
+func TestListSystems_Frontmatter(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"src/shop/system.md":             "---\nname: \"Shop\"\nslos:\n  - name: availability\n    target: 99.9\n---\n",
+		"src/shop/api/container.md":      "---\nname: \"API\"\n---\n",
+		"src/shop/api/cart/component.md": "---\nname: \"Cart\"\nrelationships:\n  db: \"Stores carts\"\n---\n",
+	}
+	for path, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+
+	pr := NewProjectRepository()
+	var warnings strings.Builder
+	pr.SetWarnings(&warnings)
+	systems, err := pr.ListSystems(ctx, root)
+	if err != nil {
+		t.Fatalf("ListSystems failed: %v", err)
+	}
+	want := []any{map[string]any{"name": "availability", "target": 99.9}}
+	if got := systems[0].Metadata["slos"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("slos = %#v, want %#v", got, want)
+	}
+
+	component := filepath.Join(root, "src", "shop", "api", "cart", "component.md")
+	if err := os.WriteFile(component, []byte("---\nname: \"Cart\"\ndescription: Backend: REST API for orders\ntags:\n  - api\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	systems, err = pr.ListSystems(ctx, root)
+	if err != nil {
+		t.Fatalf("ListSystems failed on frontmatter that is not valid YAML: %v", err)
+	}
+	cart := systems[0].Containers["api"].Components["cart"]
+	if cart.Description != "Backend: REST API for orders" || !slices.Equal(cart.Tags, []string{"api"}) {
+		t.Errorf("cart = %q %v, want the description and tags read line by line", cart.Description, cart.Tags)
+	}
+	if !strings.Contains(warnings.String(), component) {
+		t.Errorf("warnings = %q, want one naming %s", warnings.String(), component)
+	}
+
+	if err := os.WriteFile(component, []byte("---\nname: \"Cart\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = pr.ListSystems(ctx, root)
+	if !errors.Is(err, entities.ErrInvalidFrontmatter) || !strings.Contains(err.Error(), component) {
+		t.Errorf("ListSystems error = %v, want ErrInvalidFrontmatter naming %s", err, component)
+	}
+}
+
+// TestSave_SystemAndContainerRelationships verifies that system and container
+// relationships are written to their frontmatter and loaded back.
+func TestSave_SystemAndContainerRelationships(t *testing.T) {
+	root := t.TempDir()
+	ctx := context.Background()
+	pr := NewProjectRepository()
+
+	sys, _ := entities.NewSystem("Shop")
+	sys.AddRelationship("payments", "Charges orders")
+	if err := pr.SaveSystem(ctx, root, sys); err != nil {
+		t.Fatalf("SaveSystem failed: %v", err)
+	}
+	cont, _ := entities.NewContainer("API")
+	cont.AddRelationship("db", "Stores carts")
+	cont.AddRelationship("payments/gateway", "Authorizes cards")
+	if err := pr.SaveContainer(ctx, root, "shop", cont); err != nil {
+		t.Fatalf("SaveContainer failed: %v", err)
+	}
+
+	loaded, err := pr.LoadSystem(ctx, root, "shop")
+	if err != nil {
+		t.Fatalf("LoadSystem failed: %v", err)
+	}
+	if want := map[string]string{"payments": "Charges orders"}; !reflect.DeepEqual(loaded.Relationships, want) {
+		t.Errorf("system relationships = %v, want %v", loaded.Relationships, want)
+	}
+	if _, ok := loaded.Metadata["relationships"]; ok {
+		t.Error("relationships kept in metadata")
+	}
+	api, err := loaded.GetContainer("api")
+	if err != nil {
+		t.Fatalf("GetContainer failed: %v", err)
+	}
+	if !reflect.DeepEqual(api.Relationships, cont.Relationships) {
+		t.Errorf("container relationships = %v, want %v", api.Relationships, cont.Relationships)
+	}
+}
+
+func TestSave_Person(t *testing.T) {
+	root := t.TempDir()
+	ctx := context.Background()
+	pr := NewProjectRepository()
+
+	sys, _ := entities.NewSystem("Shop")
+	if err := pr.SaveSystem(ctx, root, sys); err != nil {
+		t.Fatalf("SaveSystem failed: %v", err)
+	}
+	person, _ := entities.NewPerson("Customer")
+	person.Description = "Buys things"
+	person.External = true
+	person.AddRelationship("shop", "Places orders with")
+	if err := pr.SavePerson(ctx, root, person); err != nil {
+		t.Fatalf("SavePerson failed: %v", err)
+	}
+
+	project, err := pr.LoadProject(ctx, root)
+	if err != nil {
+		t.Fatalf("LoadProject failed: %v", err)
+	}
+	if _, ok := project.Systems["customer"]; ok {
+		t.Error("person directory loaded as a domain or system")
+	}
+	loaded, ok := project.People["customer"]
+	if !ok {
+		t.Fatalf("People = %v, want customer", project.People)
+	}
+	if loaded.Description != "Buys things" || !loaded.External {
+		t.Errorf("loaded person = %+v", loaded)
+	}
+	if !reflect.DeepEqual(loaded.Relationships, person.Relationships) {
+		t.Errorf("relationships = %v, want %v", loaded.Relationships, person.Relationships)
+	}
+
+	clash, _ := entities.NewPerson("Shop")
+	if err := pr.SavePerson(ctx, root, clash); !errors.Is(err, entities.ErrPathCollision) {
+		t.Errorf("SavePerson over a system error = %v, want ErrPathCollision", err)
+	}
+}
+
+// TestSave_ContentHashConflict verifies that saving an element whose file
+// changed since it was loaded fails instead of overwriting the change.
+func TestSave_ContentHashConflict(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"src/shop/system.md":                "---\nname: \"Shop\"\n---\n",
+		"src/shop/api/container.md":         "---\nname: \"API\"\n---\n",
+		"src/shop/api/handler/component.md": "---\nname: \"Handler\"\n---\n",
+	}
+	for path, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+	pr := NewProjectRepository()
+
+	sys, err := pr.LoadSystem(ctx, root, "shop")
+	if err != nil {
+		t.Fatalf("LoadSystem failed: %v", err)
+	}
+	if sys.ContentHash != entities.HashContent([]byte(files["src/shop/system.md"])) {
+		t.Errorf("ContentHash = %q, want the hash of system.md", sys.ContentHash)
+	}
+
+	// An unchanged file saves and the hash follows the new content
+	sys.Description = "Online shop"
+	if err := pr.SaveSystem(ctx, root, sys); err != nil {
+		t.Fatalf("SaveSystem failed: %v", err)
+	}
+	saved, _ := os.ReadFile(filepath.Join(root, "src", "shop", "system.md"))
+	if sys.ContentHash != entities.HashContent(saved) {
+		t.Error("ContentHash not updated after save")
+	}
+
+	cont, err := pr.LoadContainer(ctx, root, "shop", "api")
+	if err != nil {
+		t.Fatalf("LoadContainer failed: %v", err)
+	}
+	comp, err := pr.LoadComponent(ctx, root, "shop", "api", "handler")
+	if err != nil {
+		t.Fatalf("LoadComponent failed: %v", err)
+	}
+	for path := range files {
+		if err := os.WriteFile(filepath.Join(root, filepath.FromSlash(path)), []byte("---\nname: \"Edited\"\n---\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, save := range map[string]func() error{
+		"system":    func() error { return pr.SaveSystem(ctx, root, sys) },
+		"container": func() error { return pr.SaveContainer(ctx, root, "shop", cont) },
+		"component": func() error { return pr.SaveComponent(ctx, root, "shop", "api", comp) },
+	} {
+		err := save()
+		var conflict *entities.ConflictError
+		if !errors.As(err, &conflict) || !errors.Is(err, entities.ErrConflict) {
+			t.Errorf("save %s error = %v, want a ConflictError", name, err)
+		}
+	}
+	for path := range files {
+		if content, _ := os.ReadFile(filepath.Join(root, filepath.FromSlash(path))); !strings.Contains(string(content), "Edited") {
+			t.Errorf("%s was overwritten despite the conflict", path)
+		}
+	}
+
+	// Elements that were never loaded have no hash and save unconditionally
+	fresh, _ := entities.NewSystem("Shop")
+	if err := pr.SaveSystem(ctx, root, fresh); err != nil {
+		t.Errorf("SaveSystem without a hash failed: %v", err)
+	}
+}
+
+// TestFrontmatterEditor verifies that fields and relationships are edited in
+// place, keeping the rest of the file.
+func TestFrontmatterEditor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "component.md")
+	content := "---\nname: \"Handler\"\ndescription:\nrelationships:\n  store: \"Reads\"\n  ledger: \"Posts\"\nowner: payments\n---\n\n# Handler\n\nHand-written notes.\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	pr := NewProjectRepository()
+
+	if err := pr.SetFrontmatterField(ctx, path, "description", "TODO: describe Handler"); err != nil {
+		t.Fatalf("SetFrontmatterField failed: %v", err)
+	}
+	if err := pr.SetFrontmatterField(ctx, path, "technology", "Go"); err != nil {
+		t.Fatalf("SetFrontmatterField failed: %v", err)
+	}
+	if err := pr.RemoveRelationship(ctx, path, "ledger"); err != nil {
+		t.Fatalf("RemoveRelationship failed: %v", err)
+	}
+	if err := pr.RemoveRelationship(ctx, path, "ledger"); err == nil {
+		t.Error("RemoveRelationship of a missing target should fail")
+	}
+
+	got, _ := os.ReadFile(path)
+	want := "---\nname: \"Handler\"\ndescription: \"TODO: describe Handler\"\nrelationships:\n  store: \"Reads\"\nowner: payments\ntechnology: \"Go\"\n---\n\n# Handler\n\nHand-written notes.\n"
+	if string(got) != want {
+		t.Errorf("file =\n%s\nwant\n%s", got, want)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// Removing the last relationship drops the empty map
+	if err := pr.RemoveRelationship(ctx, path, "store"); err != nil {
+		t.Fatalf("RemoveRelationship failed: %v", err)
+	}
+	if got, _ := os.ReadFile(path); strings.Contains(string(got), "relationships:") {
+		t.Errorf("file still has an empty relationships map:\n%s", got)
+	}
+
+	// Lists and maps replace the whole block, and empty ones remove the key
+	if err := pr.SetFrontmatterList(ctx, path, "aliases", []string{"payments/web/handler"}); err != nil {
+		t.Fatalf("SetFrontmatterList failed: %v", err)
+	}
+	if err := pr.SetFrontmatterMap(ctx, path, "relationships", map[string]string{"store": "Reads", "ledger": "Posts"}); err != nil {
+		t.Fatalf("SetFrontmatterMap failed: %v", err)
+	}
+	if err := pr.SetFrontmatterMap(ctx, path, "code_annotations", nil); err != nil {
+		t.Fatalf("SetFrontmatterMap failed: %v", err)
+	}
+	got, _ = os.ReadFile(path)
+	want = "---\nname: \"Handler\"\ndescription: \"TODO: describe Handler\"\nowner: payments\ntechnology: \"Go\"\naliases:\n  - \"payments/web/handler\"\nrelationships:\n  ledger: \"Posts\"\n  store: \"Reads\"\n---\n\n# Handler\n\nHand-written notes.\n"
+	if string(got) != want {
+		t.Errorf("file =\n%s\nwant\n%s", got, want)
+	}
+	if err := pr.SetFrontmatterList(ctx, path, "aliases", nil); err != nil {
+		t.Fatalf("SetFrontmatterList failed: %v", err)
+	}
+	if got, _ := os.ReadFile(path); strings.Contains(string(got), "aliases:") {
+		t.Errorf("file still has an empty aliases list:\n%s", got)
+	}
+}
+
+func TestFrontmatterEditor_KeepsYAMLValid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "component.md")
+	content := "---\nname: Handler # display name\ntags:\n- legacy\n- api\nrelationships:\n  \"store: primary\": \"Reads\"\n---\n\n# Handler\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	pr := NewProjectRepository()
+
+	if err := pr.SetFrontmatterList(ctx, path, "tags", []string{"deprecated"}); err != nil {
+		t.Fatalf("SetFrontmatterList failed: %v", err)
+	}
+	if err := pr.SetFrontmatterField(ctx, path, "name", "Handler: v2"); err != nil {
+		t.Fatalf("SetFrontmatterField failed: %v", err)
+	}
+	if err := pr.RemoveRelationship(ctx, path, "store: primary"); err != nil {
+		t.Fatalf("RemoveRelationship failed: %v", err)
+	}
+
+	got, _ := os.ReadFile(path)
+	fields, body, err := NewYAMLFrontmatterCodec().Decode(got)
+	if err != nil {
+		t.Fatalf("edited frontmatter is not valid YAML: %v\n%s", err, got)
+	}
+	if tags, _ := fields["tags"].([]any); len(tags) != 1 || tags[0] != "deprecated" {
+		t.Errorf("tags = %v, want [deprecated]", fields["tags"])
+	}
+	if fields["name"] != "Handler: v2" {
+		t.Errorf("name = %v, want %q", fields["name"], "Handler: v2")
+	}
+	if _, ok := fields["relationships"]; ok {
+		t.Errorf("relationships = %v, want none", fields["relationships"])
+	}
+	if !strings.Contains(string(got), "# display name") {
+		t.Errorf("comment was dropped:\n%s", got)
+	}
+	if string(body) != "\n# Handler\n" {
+		t.Errorf("body = %q, want %q", body, "\n# Handler\n")
+	}
+}
+
+func TestFrontmatterEditor_CRLF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "component.md")
+	content := "---\r\nname: \"Handler\"\r\ndescription:\r\n---\r\n\r\n# Handler\r\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	pr := NewProjectRepository()
+
+	if err := pr.SetFrontmatterField(ctx, path, "description", "Handles requests"); err != nil {
+		t.Fatalf("SetFrontmatterField failed: %v", err)
+	}
+
+	got, _ := os.ReadFile(path)
+	want := "---\r\nname: \"Handler\"\r\ndescription: \"Handles requests\"\r\n---\r\n\r\n# Handler\r\n"
+	if string(got) != want {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+}
@@ -15,9 +15,10 @@ import (
 // FilesystemRelationshipRepository implements the RelationshipRepository port
 // using the local file system.
 //
-// Relationships for a system are stored in:
+// Relationships for a system are stored next to its system.md, in whichever
+// source directory holds it:
 //
-//	<projectRoot>/src/<systemID>/relationships.toml
+//	<source>/<system path>/relationships.toml
 //
 // Writes are atomic: content is first written to a .tmp file, then
 // renamed to the target path (POSIX rename is atomic on the same filesystem).
@@ -29,8 +30,22 @@ func NewFilesystemRelationshipRepository() *FilesystemRelationshipRepository {
 }
 
 // relationshipsPath returns the canonical path for a system's relationships.toml.
+// Projects with a custom source directory that still have the file under
+// <projectRoot>/src/, where it used to be kept, keep using it there.
 func relationshipsPath(projectRoot, systemID string) string {
-	return filepath.Join(projectRoot, "src", systemID, "relationships.toml")
+	config, err := loadConfig(filepath.Join(projectRoot, "loko.toml"))
+	if err != nil {
+		config = entities.DefaultProjectConfig()
+	}
+	path := filepath.Join(resolveSystemDir(projectRoot, config, systemID), "relationships.toml")
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+	legacy := filepath.Join(projectRoot, "src", filepath.FromSlash(entities.SystemSourcePath(systemID)), "relationships.toml")
+	if _, err := os.Stat(legacy); err == nil {
+		return legacy
+	}
+	return path
 }
 
 // LoadRelationships reads all relationships for a system from relationships.toml.
@@ -0,0 +1,148 @@
+package filesystem
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// sourceRoots returns the source directories of the project at projectRoot:
+// the primary source directory first, then those of [paths] source_dirs,
+// skipping any that resolve to a directory already listed. Relative
+// directories are relative to the project root.
+func sourceRoots(projectRoot string, config *entities.ProjectConfig) []string {
+	seen := make(map[string]bool)
+	var roots []string
+	for _, dir := range append([]string{config.SourceDir}, config.SourceDirs...) {
+		if dir == "" {
+			continue
+		}
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(projectRoot, dir)
+		}
+		key := realPath(dir)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		roots = append(roots, dir)
+	}
+	return roots
+}
+
+// resolveSystemDir returns the directory of a system: the one holding its
+// system.md in the first source root that has it, or the directory in the
+// primary source root where a new system is created.
+func resolveSystemDir(projectRoot string, config *entities.ProjectConfig, systemID string) string {
+	roots := sourceRoots(projectRoot, config)
+	if len(roots) == 0 {
+		roots = []string{projectRoot}
+	}
+	for _, root := range roots {
+		dir := elementDir(root, entities.SystemSourcePath(systemID))
+		if sourceExists(filepath.Join(dir, "system.md")) {
+			return dir
+		}
+	}
+	return elementDir(roots[0], entities.SystemSourcePath(systemID))
+}
+
+// realPath returns path with symlinks resolved, or the cleaned absolute path
+// when it cannot be resolved (e.g. it does not exist yet).
+func realPath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// followDir reports whether the entry of dir is a directory to descend
+// into: a directory, or a symlink to a directory other than dir itself or
+// one of its ancestors, which would never end.
+func followDir(dir string, entry os.DirEntry) bool {
+	if entry.IsDir() {
+		return true
+	}
+	if entry.Type()&os.ModeSymlink == 0 {
+		return false
+	}
+	path := filepath.Join(dir, entry.Name())
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+	target, parent := realPath(path), realPath(dir)
+	return parent != target && !strings.HasPrefix(parent, target+string(filepath.Separator))
+}
+
+// walkFollow walks the tree at root like filepath.Walk, but also descends
+// into symlinked directories. Each directory is walked once, under the first
+// path it is reached by, so symlinks into the tree and symlink loops do not
+// report files twice.
+func walkFollow(root string, fn filepath.WalkFunc) error {
+	visited := make(map[string]bool)
+	var walk func(path string, info os.FileInfo) error
+	walk = func(path string, info os.FileInfo) error {
+		err := fn(path, info, nil)
+		if !info.IsDir() || err != nil {
+			if errors.Is(err, filepath.SkipDir) && info.IsDir() {
+				return nil
+			}
+			return err
+		}
+		real := realPath(path)
+		if visited[real] {
+			return nil
+		}
+		visited[real] = true
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil
+		}
+		for _, entry := range entries {
+			child := filepath.Join(path, entry.Name())
+			info, err := os.Stat(child) // Follows symlinks
+			if err != nil {
+				continue
+			}
+			if err := walk(child, info); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return fn(root, nil, err)
+	}
+	return walk(root, info)
+}
+
+// watchRoots returns the directories watched for the project at rootPath:
+// rootPath itself, then the source directories of its loko.toml outside it.
+// Source directories inside rootPath, symlinked or not, are reached by
+// walking rootPath.
+func watchRoots(rootPath string) []string {
+	roots := []string{rootPath}
+	config, err := loadConfig(filepath.Join(rootPath, "loko.toml"))
+	if err != nil {
+		return roots
+	}
+	for _, dir := range sourceRoots(rootPath, config) {
+		if rel, err := filepath.Rel(rootPath, dir); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			roots = append(roots, dir)
+		}
+	}
+	return roots
+}
@@ -0,0 +1,140 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// symlink creates a symlink or skips the test where that is not permitted,
+// e.g. on Windows without developer mode.
+func symlink(t *testing.T, target, link string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(link), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+}
+
+func systemIDs(systems []*entities.System) []string {
+	var ids []string
+	for _, sys := range systems {
+		ids = append(ids, sys.ID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestListSystems_SourceDirs(t *testing.T) {
+	ctx := context.Background()
+	base := t.TempDir()
+	root := filepath.Join(base, "docs")
+	writeSourceFiles(t, base, map[string]string{
+		"docs/loko.toml":                             "[paths]\nsource = \"./src\"\nsource_dirs = [\"../billing/src\", \"./src\"]\n",
+		"docs/src/shop/system.md":                    "---\nname: \"Shop\"\n---\n",
+		"billing/src/invoicing/system.md":            "---\nname: \"Invoicing\"\n---\n",
+		"billing/src/invoicing/api/container.md":     "---\nname: \"API\"\n---\n",
+		"billing/src/invoicing/api/pdf/component.md": "---\nname: \"PDF\"\n---\n",
+		"billing/src/invoicing/relationships.toml":   "",
+	})
+	pr := NewProjectRepository()
+
+	systems, err := pr.ListSystems(ctx, root)
+	if err != nil {
+		t.Fatalf("ListSystems failed: %v", err)
+	}
+	if got := systemIDs(systems); !slices.Equal(got, []string{"invoicing", "shop"}) {
+		t.Errorf("systems = %v, want [invoicing shop]", got)
+	}
+	if _, err := pr.LoadComponent(ctx, root, "invoicing", "api", "pdf"); err != nil {
+		t.Errorf("LoadComponent from a source_dirs root failed: %v", err)
+	}
+
+	// Elements of an existing system are saved where the system lives, new
+	// systems in the primary source directory
+	worker, _ := entities.NewContainer("Worker")
+	if err := pr.SaveContainer(ctx, root, "invoicing", worker); err != nil {
+		t.Fatalf("SaveContainer failed: %v", err)
+	}
+	if worker.Path != filepath.Join(base, "billing", "src", "invoicing", "worker") {
+		t.Errorf("container saved to %q", worker.Path)
+	}
+	web, _ := entities.NewSystem("Web")
+	if err := pr.SaveSystem(ctx, root, web); err != nil {
+		t.Fatalf("SaveSystem failed: %v", err)
+	}
+	if web.Path != filepath.Join(root, "src", "web") {
+		t.Errorf("system saved to %q", web.Path)
+	}
+	if got := relationshipsPath(root, "invoicing"); got != filepath.Join(base, "billing", "src", "invoicing", "relationships.toml") {
+		t.Errorf("relationshipsPath = %q", got)
+	}
+
+	// The same system ID in two source roots is ambiguous
+	writeSourceFiles(t, base, map[string]string{"billing/src/shop/system.md": "---\nname: \"Shop\"\n---\n"})
+	if _, err := pr.ListSystems(ctx, root); !errors.Is(err, entities.ErrPathCollision) {
+		t.Errorf("ListSystems() error = %v, want ErrPathCollision", err)
+	}
+}
+
+func TestListSystems_Symlinks(t *testing.T) {
+	ctx := context.Background()
+	base := t.TempDir()
+	root := filepath.Join(base, "docs")
+	writeSourceFiles(t, base, map[string]string{
+		"docs/src/shop/system.md":        "---\nname: \"Shop\"\n---\n",
+		"docs/src/shop/api/container.md": "---\nname: \"API\"\n---\n",
+		"payments/src/cards/system.md":   "---\nname: \"Cards\"\n---\n",
+		"shared/handler/component.md":    "---\nname: \"Handler\"\n---\n",
+	})
+	// A system in another repository, the same system again, a shared
+	// component and a loop back to the source directory
+	symlink(t, filepath.Join(base, "payments", "src", "cards"), filepath.Join(root, "src", "cards"))
+	symlink(t, filepath.Join(root, "src", "cards"), filepath.Join(root, "src", "cards-alias"))
+	symlink(t, filepath.Join(base, "shared", "handler"), filepath.Join(root, "src", "shop", "api", "handler"))
+	symlink(t, filepath.Join(root, "src"), filepath.Join(root, "src", "shop", "api", "loop"))
+
+	systems, err := NewProjectRepository().ListSystems(ctx, root)
+	if err != nil {
+		t.Fatalf("ListSystems failed: %v", err)
+	}
+	if got := systemIDs(systems); !slices.Equal(got, []string{"cards", "shop"}) {
+		t.Errorf("systems = %v, want [cards shop]", got)
+	}
+	for _, sys := range systems {
+		if sys.ID == "shop" && sys.Containers["api"].Components["handler"] == nil {
+			t.Error("symlinked component not loaded")
+		}
+	}
+
+	// Watchers see the files behind the symlinks once
+	states := scanTree(root)
+	for _, path := range []string{"src/cards/system.md", "src/shop/api/handler/component.md"} {
+		if _, ok := states[path]; !ok {
+			t.Errorf("scanTree missed %s", path)
+		}
+	}
+	if _, ok := states["src/cards-alias/system.md"]; ok {
+		t.Error("scanTree reported a directory twice")
+	}
+}
+
+func TestScanTree_SourceDirsOutsideRoot(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "docs")
+	writeSourceFiles(t, base, map[string]string{
+		"docs/loko.toml":                  "[paths]\nsource_dirs = [\"../billing/src\"]\n",
+		"billing/src/invoicing/system.md": "",
+	})
+
+	if _, ok := scanTree(root)["../billing/src/invoicing/system.md"]; !ok {
+		t.Error("scanTree missed a source directory outside the project root")
+	}
+}
@@ -0,0 +1,263 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// TrashDir is the trash's directory relative to the project root.
+const TrashDir = ".loko/trash"
+
+// trashEntryFile holds the TrashEntry inside each entry's directory, and
+// trashFilesDir the deleted paths.
+const (
+	trashEntryFile = "entry.json"
+	trashFilesDir  = "files"
+)
+
+// Ensure Trash implements usecases.Trash interface.
+var _ usecases.Trash = (*Trash)(nil)
+
+// Trash implements the Trash port in .loko/trash/. Each entry is a directory
+// named after the time of the deletion, holding an entry.json and, under
+// files/, the deleted paths at their location relative to the project root.
+type Trash struct {
+	projectRoot string
+	mu          sync.Mutex
+	now         func() time.Time
+}
+
+// NewTrash creates the trash of the project at projectRoot.
+func NewTrash(projectRoot string) *Trash {
+	return &Trash{projectRoot: projectRoot, now: time.Now}
+}
+
+// Move moves paths into a new trash entry. Nothing is moved if a path does
+// not exist or is outside the project.
+func (t *Trash) Move(_ context.Context, paths []string, reason string) (*entities.TrashEntry, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("nothing to move to the trash")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry := &entities.TrashEntry{Time: t.now().UTC(), Reason: reason}
+	for _, path := range paths {
+		rel, err := t.relPath(path)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Lstat(filepath.Join(t.projectRoot, rel)); err != nil {
+			return nil, fmt.Errorf("cannot move %s to the trash: %w", filepath.ToSlash(rel), err)
+		}
+		entry.Paths = append(entry.Paths, filepath.ToSlash(rel))
+	}
+
+	dir, err := t.newEntryDir(entry)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeTrashEntry(dir, entry); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+	for i, rel := range entry.Paths {
+		if err := moveTo(filepath.Join(t.projectRoot, filepath.FromSlash(rel)), filepath.Join(dir, trashFilesDir, filepath.FromSlash(rel))); err != nil {
+			// Put back what was already moved so the deletion is all or nothing
+			for _, moved := range entry.Paths[:i] {
+				_ = moveTo(filepath.Join(dir, trashFilesDir, filepath.FromSlash(moved)), filepath.Join(t.projectRoot, filepath.FromSlash(moved)))
+			}
+			_ = os.RemoveAll(dir)
+			return nil, fmt.Errorf("failed to move %s to the trash: %w", rel, err)
+		}
+	}
+	return entry, nil
+}
+
+// Entries reads the entries in the trash, oldest first. A missing trash has none.
+func (t *Trash) Entries(_ context.Context) ([]*entities.TrashEntry, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.entries()
+}
+
+// Restore moves the paths of entry id back into the project and removes the
+// entry.
+func (t *Trash) Restore(_ context.Context, id string) (*entities.TrashEntry, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, err := t.entry(id)
+	if err != nil {
+		return nil, err
+	}
+	dir := t.entryDir(id)
+	for _, rel := range entry.Paths {
+		if _, err := os.Lstat(filepath.Join(t.projectRoot, filepath.FromSlash(rel))); err == nil {
+			return nil, fmt.Errorf("cannot restore %s: %s exists again", id, rel)
+		}
+	}
+	for _, rel := range entry.Paths {
+		if err := moveTo(filepath.Join(dir, trashFilesDir, filepath.FromSlash(rel)), filepath.Join(t.projectRoot, filepath.FromSlash(rel))); err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", rel, err)
+		}
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return entry, fmt.Errorf("restored %s but failed to remove it from the trash: %w", id, err)
+	}
+	return entry, nil
+}
+
+// Purge permanently removes entry id, or every entry when id is empty.
+func (t *Trash) Purge(_ context.Context, id string) ([]*entities.TrashEntry, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var purge []*entities.TrashEntry
+	if id == "" {
+		entries, err := t.entries()
+		if err != nil {
+			return nil, err
+		}
+		purge = entries
+	} else {
+		entry, err := t.entry(id)
+		if err != nil {
+			return nil, err
+		}
+		purge = []*entities.TrashEntry{entry}
+	}
+
+	for i, entry := range purge {
+		if err := os.RemoveAll(t.entryDir(entry.ID)); err != nil {
+			return purge[:i], fmt.Errorf("failed to purge %s: %w", entry.ID, err)
+		}
+	}
+	return purge, nil
+}
+
+// entries reads every entry directory, oldest first.
+func (t *Trash) entries() ([]*entities.TrashEntry, error) {
+	dirs, err := os.ReadDir(filepath.Join(t.projectRoot, TrashDir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+
+	var entries []*entities.TrashEntry
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		entry, err := t.entry(d.Name())
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	slices.SortStableFunc(entries, func(a, b *entities.TrashEntry) int { return a.Time.Compare(b.Time) })
+	return entries, nil
+}
+
+// entry reads the entry.json of entry id.
+func (t *Trash) entry(id string) (*entities.TrashEntry, error) {
+	if id == "" || id != filepath.Base(id) || strings.HasPrefix(id, ".") {
+		return nil, fmt.Errorf("%w: %q", entities.ErrTrashEntryNotFound, id)
+	}
+	data, err := os.ReadFile(filepath.Join(t.entryDir(id), trashEntryFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %q", entities.ErrTrashEntryNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to read trash entry %s: %w", id, err)
+	}
+	var entry entities.TrashEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("invalid trash entry %s: %w", id, err)
+	}
+	entry.ID = id
+	return &entry, nil
+}
+
+// newEntryDir creates the directory of a new entry and sets its ID: the UTC
+// time of the deletion, with a counter appended when several deletions
+// happen within the same second.
+func (t *Trash) newEntryDir(entry *entities.TrashEntry) (string, error) {
+	if err := os.MkdirAll(filepath.Join(t.projectRoot, TrashDir), 0755); err != nil {
+		return "", fmt.Errorf("failed to create trash directory: %w", err)
+	}
+	base := entry.Time.Format("20060102-150405")
+	for n := 1; ; n++ {
+		entry.ID = base
+		if n > 1 {
+			entry.ID = fmt.Sprintf("%s-%d", base, n)
+		}
+		dir := t.entryDir(entry.ID)
+		err := os.Mkdir(dir, 0755)
+		if err == nil {
+			return dir, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return "", fmt.Errorf("failed to create trash entry: %w", err)
+		}
+	}
+}
+
+// entryDir returns the directory of entry id.
+func (t *Trash) entryDir(id string) string {
+	return filepath.Join(t.projectRoot, TrashDir, id)
+}
+
+// relPath returns path relative to the project root, rejecting paths outside
+// the project and inside .loko/.
+func (t *Trash) relPath(path string) (string, error) {
+	rel := filepath.Clean(path)
+	if filepath.IsAbs(path) {
+		var err error
+		if rel, err = filepath.Rel(t.projectRoot, path); err != nil {
+			return "", fmt.Errorf("cannot move %s to the trash: %w", path, err)
+		}
+	}
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("cannot move %s to the trash: not inside the project", path)
+	}
+	if first, _, _ := strings.Cut(filepath.ToSlash(rel), "/"); first == ".loko" {
+		return "", fmt.Errorf("cannot move %s to the trash: loko's own files are not trashed", path)
+	}
+	return rel, nil
+}
+
+// writeTrashEntry writes entry to the entry.json in dir.
+func writeTrashEntry(dir string, entry *entities.TrashEntry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode trash entry: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, trashEntryFile), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write trash entry: %w", err)
+	}
+	return nil
+}
+
+// moveTo renames from to to, creating the parent directories of to.
+func moveTo(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	return os.Rename(from, to)
+}
@@ -0,0 +1,97 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestTrash_MoveRestorePurge(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	componentDir := filepath.Join(root, "src", "shop", "api", "orders")
+	if err := os.MkdirAll(componentDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(componentDir, "component.md"), []byte("# Orders\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	trash := NewTrash(root)
+	trash.now = func() time.Time { return time.Date(2026, 10, 16, 14, 25, 1, 0, time.UTC) }
+
+	if _, err := trash.Move(ctx, []string{"../outside"}, ""); err == nil {
+		t.Error("Move() of a path outside the project should fail")
+	}
+	if _, err := trash.Move(ctx, []string{"src/missing"}, ""); err == nil {
+		t.Error("Move() of a missing path should fail")
+	}
+
+	entry, err := trash.Move(ctx, []string{componentDir}, "merge shop/api/orders into shop/api/order-store")
+	if err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if entry.ID != "20261016-142501" || len(entry.Paths) != 1 || entry.Paths[0] != "src/shop/api/orders" {
+		t.Errorf("entry = %+v", entry)
+	}
+	if _, err := os.Stat(componentDir); !os.IsNotExist(err) {
+		t.Error("moved directory still in the project")
+	}
+
+	// A second deletion in the same second gets its own entry
+	other := filepath.Join(root, "src", "shop", "notes.md")
+	if err := os.WriteFile(other, []byte("notes\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	second, err := trash.Move(ctx, []string{"src/shop/notes.md"}, "")
+	if err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if second.ID != "20261016-142501-2" {
+		t.Errorf("second ID = %q", second.ID)
+	}
+
+	entries, err := trash.Entries(ctx)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("Entries() = %d entries, err %v; want 2", len(entries), err)
+	}
+	if entries[0].Reason != "merge shop/api/orders into shop/api/order-store" {
+		t.Errorf("Reason = %q", entries[0].Reason)
+	}
+
+	// Restoring fails while the path has been recreated
+	if err := os.MkdirAll(componentDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trash.Restore(ctx, entry.ID); err == nil {
+		t.Error("Restore() over a recreated path should fail")
+	}
+	if err := os.Remove(componentDir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trash.Restore(ctx, entry.ID); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if content, err := os.ReadFile(filepath.Join(componentDir, "component.md")); err != nil || string(content) != "# Orders\n" {
+		t.Errorf("restored component.md = %q, err %v", content, err)
+	}
+	if _, err := trash.Restore(ctx, entry.ID); !errors.Is(err, entities.ErrTrashEntryNotFound) {
+		t.Errorf("Restore() of a restored entry error = %v, want ErrTrashEntryNotFound", err)
+	}
+
+	purged, err := trash.Purge(ctx, "")
+	if err != nil || len(purged) != 1 || purged[0].ID != second.ID {
+		t.Fatalf("Purge() = %+v, err %v", purged, err)
+	}
+	if entries, _ := trash.Entries(ctx); len(entries) != 0 {
+		t.Errorf("Entries() after purge = %d, want 0", len(entries))
+	}
+	if _, err := os.Stat(other); !os.IsNotExist(err) {
+		t.Error("purged file reappeared in the project")
+	}
+}
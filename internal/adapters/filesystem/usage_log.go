@@ -0,0 +1,84 @@
+package filesystem
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// UsageLogFile is the usage log's path relative to the project root.
+const UsageLogFile = ".loko/usage.log"
+
+// Ensure UsageLog implements usecases.UsageLog interface.
+var _ usecases.UsageLog = (*UsageLog)(nil)
+
+// UsageLog implements the UsageLog port as a JSON-lines file, one event per
+// line, like the audit log.
+type UsageLog struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewUsageLog creates a usage log stored in .loko/usage.log under projectRoot.
+func NewUsageLog(projectRoot string) *UsageLog {
+	return &UsageLog{path: filepath.Join(projectRoot, UsageLogFile)}
+}
+
+// Append writes event as a single JSON line at the end of the log.
+func (l *UsageLog) Append(_ context.Context, event *entities.UsageEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode usage event: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create usage log directory: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open usage log: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write usage log: %w", err)
+	}
+	return f.Close()
+}
+
+// Events reads every event in the log, oldest first. Lines that do not
+// parse, such as one cut short by a crash, are skipped.
+func (l *UsageLog) Events(_ context.Context) ([]*entities.UsageEvent, error) {
+	f, err := os.Open(l.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open usage log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var events []*entities.UsageEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event entities.UsageEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || event.Command == "" {
+			continue
+		}
+		events = append(events, &event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage log: %w", err)
+	}
+	return events, nil
+}
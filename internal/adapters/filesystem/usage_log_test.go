@@ -0,0 +1,53 @@
+package filesystem
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestUsageLog_AppendAndEvents(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	log := NewUsageLog(root)
+
+	events, err := log.Events(ctx)
+	if err != nil || len(events) != 0 {
+		t.Fatalf("expected empty log, got %d events, err %v", len(events), err)
+	}
+
+	build := &entities.UsageEvent{Time: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC), Command: "build", DurationMS: 1500}
+	if err := log.Append(ctx, build); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	// A line cut short by a crash does not hide the others
+	f, err := os.OpenFile(filepath.Join(root, UsageLogFile), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"time":"2026-10-01T10:00:00Z","comm` + "\n")
+	_ = f.Close()
+
+	if err := log.Append(ctx, &entities.UsageEvent{Command: "new system", ExitCode: 2}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	events, err = log.Events(ctx)
+	if err != nil {
+		t.Fatalf("Events failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if !events[0].Time.Equal(build.Time) || events[0].Command != "build" || events[0].Duration() != 1500*time.Millisecond {
+		t.Errorf("first event = %+v", events[0])
+	}
+	if events[1].Command != "new system" || events[1].ExitCode != 2 {
+		t.Errorf("second event = %+v", events[1])
+	}
+}
@@ -14,7 +14,8 @@ import (
 	"github.com/madstone-tech/loko/internal/core/usecases"
 )
 
-// FileWatcher monitors the file system for changes to .md and .d2 files.
+// FileWatcher monitors the file system for changes to .md and .d2 files,
+// loko.toml, and project templates and themes under .loko/.
 // It filters out unwanted directories and debounces rapid events.
 type FileWatcher struct {
 	watcher *fsnotify.Watcher
@@ -59,9 +60,12 @@ func (fw *FileWatcher) Watch(ctx context.Context, rootPath string) (<-chan useca
 		return nil, fmt.Errorf("root path is not a directory")
 	}
 
-	// Add root and all subdirectories to watcher
-	if err := fw.addRecursive(rootPath); err != nil {
-		return nil, fmt.Errorf("failed to add watch paths: %w", err)
+	// Add root, source directories outside it and all their subdirectories
+	// to watcher
+	for _, root := range watchRoots(rootPath) {
+		if err := fw.addRecursive(root); err != nil {
+			return nil, fmt.Errorf("failed to add watch paths: %w", err)
+		}
 	}
 
 	// Start background event processor
@@ -99,9 +103,10 @@ func (fw *FileWatcher) Stop() error {
 	return nil
 }
 
-// addRecursive adds the root path and all subdirectories to the watcher.
+// addRecursive adds the root path and all subdirectories to the watcher,
+// following symlinked directories.
 func (fw *FileWatcher) addRecursive(rootPath string) error {
-	return filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+	return walkFollow(rootPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil // Skip paths with errors
 		}
@@ -111,7 +116,7 @@ func (fw *FileWatcher) addRecursive(rootPath string) error {
 		}
 
 		// Skip ignored directories
-		if fw.shouldIgnoreDir(path, rootPath) {
+		if shouldIgnoreDir(path, rootPath) {
 			return filepath.SkipDir
 		}
 
@@ -125,20 +130,28 @@ func (fw *FileWatcher) addRecursive(rootPath string) error {
 }
 
 // shouldIgnoreDir returns true if the directory should not be watched.
-func (fw *FileWatcher) shouldIgnoreDir(path, rootPath string) bool {
+func shouldIgnoreDir(path, rootPath string) bool {
 	rel, err := filepath.Rel(rootPath, path)
 	if err != nil {
 		return true
 	}
 
-	// Normalize to forward slashes for consistent comparison
-	rel = filepath.ToSlash(rel)
+	// Normalize to lowercase forward slashes for consistent comparison, as
+	// for file events: .Git and Dist are the same directories on Windows
+	rel = strings.ToLower(filepath.ToSlash(rel))
+
+	// Within .loko/ only templates and themes affect the build
+	if rest, ok := strings.CutPrefix(rel, ".loko/"); ok {
+		top, _, _ := strings.Cut(rest, "/")
+		if top != "templates" && top != "themes" {
+			return true
+		}
+	}
 
 	// List of directories to ignore
 	ignoredDirs := map[string]bool{
 		"dist":          true,
 		".git":          true,
-		".loko":         true,
 		"node_modules":  true,
 		".venv":         true,
 		"venv":          true,
@@ -159,9 +172,9 @@ func (fw *FileWatcher) shouldIgnoreDir(path, rootPath string) bool {
 }
 
 // shouldProcessFile returns true if the file should trigger a change event.
-func (fw *FileWatcher) shouldProcessFile(path string) bool {
-	ext := strings.ToLower(filepath.Ext(path))
-	return ext == ".md" || ext == ".d2"
+// relPath is relative to the watched root, using forward slashes.
+func shouldProcessFile(relPath string) bool {
+	return usecases.ScopeForChange(relPath) != usecases.RebuildNone
 }
 
 // processEvents reads from fsnotify and sends debounced events.
@@ -190,17 +203,12 @@ func (fw *FileWatcher) processEvents(ctx context.Context, rootPath string) {
 			// Handle new directory creation (add to watcher)
 			if event.Op&fsnotify.Create == fsnotify.Create {
 				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
-					if !fw.shouldIgnoreDir(event.Name, rootPath) {
+					if !shouldIgnoreDir(event.Name, rootPath) {
 						_ = fw.watcher.Add(event.Name)
 					}
 				}
 			}
 
-			// Only process .md and .d2 files
-			if !fw.shouldProcessFile(event.Name) {
-				continue
-			}
-
 			// Convert absolute path to relative
 			relPath, err := filepath.Rel(rootPath, event.Name)
 			if err != nil {
@@ -211,6 +219,11 @@ func (fw *FileWatcher) processEvents(ctx context.Context, rootPath string) {
 			relPath = filepath.ToSlash(relPath)
 			relPath = strings.ToLower(relPath)
 
+			// Only process sources, config, templates and themes
+			if !shouldProcessFile(relPath) {
+				continue
+			}
+
 			// Map fsnotify operation to event operation
 			op := fw.mapOperation(event.Op)
 
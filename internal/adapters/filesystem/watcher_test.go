@@ -637,3 +637,57 @@ done:
 		}
 	}
 }
+
+// TestWatchConfigAndTemplates tests that loko.toml and .loko/templates changes
+// trigger events while other .loko content is ignored.
+func TestWatchConfigAndTemplates(t *testing.T) {
+	fw, err := NewFileWatcher()
+	if err != nil {
+		t.Fatalf("NewFileWatcher failed: %v", err)
+	}
+	defer stopWatcher(t, fw)
+
+	tmpDir := t.TempDir()
+	for _, dir := range []string{".loko/templates", ".loko/cache"} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	events, err := fw.Watch(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+
+	writes := map[string]string{
+		"loko.toml":                   "[project]\nname = \"x\"\n",
+		".loko/templates/system.html": "<html></html>",
+		".loko/cache/state.md":        "ignored",
+	}
+	for name, content := range writes {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	timeout := time.After(2 * time.Second)
+	for len(seen) < 2 {
+		select {
+		case evt := <-events:
+			seen[evt.Path] = true
+		case <-timeout:
+			t.Fatalf("timeout waiting for events, got %v", seen)
+		}
+	}
+
+	if !seen["loko.toml"] {
+		t.Error("expected event for loko.toml")
+	}
+	if !seen[".loko/templates/system.html"] {
+		t.Error("expected event for template change")
+	}
+	if seen[".loko/cache/state.md"] {
+		t.Error("unexpected event for ignored .loko/cache file")
+	}
+}
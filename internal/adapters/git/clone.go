@@ -0,0 +1,37 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// IsRepositoryURL reports whether source looks like a git repository URL
+// rather than the name of a built-in template.
+func IsRepositoryURL(source string) bool {
+	for _, prefix := range []string{"https://", "http://", "ssh://", "git://", "file://", "git@"} {
+		if strings.HasPrefix(source, prefix) {
+			return true
+		}
+	}
+	return strings.HasSuffix(source, ".git")
+}
+
+// Clone makes a shallow clone of the repository at url into destDir.
+// destDir must not exist or be empty.
+func Clone(ctx context.Context, url, destDir string) error {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		return fmt.Errorf("git is required to use a template repository: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, gitPath, "clone", "--depth", "1", "--quiet", url, destDir)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git clone failed: %w\nOutput: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsRepositoryURL(t *testing.T) {
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"https://github.com/acme/loko-template", true},
+		{"git@github.com:acme/loko-template.git", true},
+		{"file:///tmp/template", true},
+		{"../templates/ours.git", true},
+		{"microservices", false},
+		{"data-platform", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRepositoryURL(tt.source); got != tt.want {
+			t.Errorf("IsRepositoryURL(%q) = %v, want %v", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestClone(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	src := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", src}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Tester", "GIT_AUTHOR_EMAIL=t@example.com",
+			"GIT_COMMITTER_NAME=Tester", "GIT_COMMITTER_EMAIL=t@example.com",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+
+	run("init", "-q")
+	systemDir := filepath.Join(src, "src", "shop")
+	if err := os.MkdirAll(systemDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(systemDir, "system.md"), []byte("# Shop\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("commit", "-q", "-m", "template")
+
+	dest := filepath.Join(t.TempDir(), "clone")
+	if err := Clone(context.Background(), "file://"+src, dest); err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "src", "shop", "system.md")); err != nil {
+		t.Errorf("expected cloned file: %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// CommitFiles implements usecases.ChangeCommitter: it stages paths, relative
+// to projectRoot, and commits only them, so unrelated work in progress stays
+// uncommitted. Returns entities.ErrNoHistory if git is missing or projectRoot
+// is not inside a work tree.
+func (h *History) CommitFiles(ctx context.Context, projectRoot string, paths []string, message string) error {
+	if !h.IsAvailable() {
+		return entities.ErrNoHistory
+	}
+	check := exec.CommandContext(ctx, h.gitPath, "-C", projectRoot, "rev-parse", "--is-inside-work-tree")
+	if out, err := check.Output(); err != nil || strings.TrimSpace(string(out)) != "true" {
+		return entities.ErrNoHistory
+	}
+
+	// add -A stages removals as well; the pathspec limits the commit to paths
+	pathspec := append([]string{"--"}, paths...)
+	if err := h.run(ctx, projectRoot, append([]string{"add", "-A"}, pathspec...)...); err != nil {
+		return err
+	}
+	return h.run(ctx, projectRoot, append([]string{"commit", "--quiet", "-m", message}, pathspec...)...)
+}
+
+// run runs a git command in projectRoot, returning its output on failure.
+func (h *History) run(ctx context.Context, projectRoot string, args ...string) error {
+	cmd := exec.CommandContext(ctx, h.gitPath, append([]string{"-C", projectRoot}, args...)...)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s failed: %w\nOutput: %s", args[0], err, strings.TrimSpace(output.String()))
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestCommitFiles(t *testing.T) {
+	h := NewHistory()
+	if !h.IsAvailable() {
+		t.Skip("git not installed")
+	}
+	for _, kv := range [][2]string{
+		{"GIT_AUTHOR_NAME", "Tester"}, {"GIT_AUTHOR_EMAIL", "t@example.com"},
+		{"GIT_COMMITTER_NAME", "Tester"}, {"GIT_COMMITTER_EMAIL", "t@example.com"},
+	} {
+		t.Setenv(kv[0], kv[1])
+	}
+
+	dir := t.TempDir()
+	if err := h.CommitFiles(context.Background(), dir, []string{"loko.toml"}, "msg"); !errors.Is(err, entities.ErrNoHistory) {
+		t.Errorf("expected ErrNoHistory outside a repository, got %v", err)
+	}
+
+	write := func(rel, content string) {
+		t.Helper()
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run := gitRunner(t, dir)
+
+	run("init", "-q")
+	write("src/payments/old.md", "old\n")
+	write("notes.txt", "notes\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "init")
+
+	write("src/payments/system.md", "# Payments\n")
+	write("notes.txt", "work in progress\n")
+	if err := os.Remove(filepath.Join(dir, "src", "payments", "old.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	err := h.CommitFiles(context.Background(), dir, []string{"src/payments/system.md", "src/payments/old.md"}, "loko: create_system Payments\n\nSource: mcp\n")
+	if err != nil {
+		t.Fatalf("CommitFiles failed: %v", err)
+	}
+
+	if subject := run("log", "-1", "--format=%s"); subject != "loko: create_system Payments\n" {
+		t.Errorf("subject = %q", subject)
+	}
+	if files := run("show", "--name-status", "--format=", "HEAD"); files != "D\tsrc/payments/old.md\nA\tsrc/payments/system.md\n" {
+		t.Errorf("committed files = %q", files)
+	}
+	if status := run("status", "--porcelain"); !strings.Contains(status, " M notes.txt") {
+		t.Errorf("unrelated change was committed, status = %q", status)
+	}
+}
@@ -0,0 +1,136 @@
+// Package git provides adapters that shell out to the git CLI: a revision
+// history provider used to build the architecture timeline and to export past
+// revisions of a project, and cloning of project template repositories.
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// Field and record separators used in the git log format string.
+const (
+	recordSep = "\x1e"
+	fieldSep  = "\x1f"
+)
+
+// History implements usecases.HistoryProvider using `git log`.
+type History struct {
+	gitPath string // Path to git binary
+}
+
+// NewHistory creates a new git history provider.
+// It checks if git is available in PATH.
+func NewHistory() *History {
+	gitPath, _ := exec.LookPath("git")
+	return &History{gitPath: gitPath}
+}
+
+// IsAvailable checks if the git binary is installed and accessible.
+func (h *History) IsAvailable() bool {
+	return h.gitPath != ""
+}
+
+// ListRevisions returns revisions touching files under pathPrefix, oldest first.
+// Returns entities.ErrNoHistory if git is missing or projectRoot is not inside a work tree.
+func (h *History) ListRevisions(ctx context.Context, projectRoot, pathPrefix string) ([]usecases.Revision, error) {
+	if !h.IsAvailable() {
+		return nil, entities.ErrNoHistory
+	}
+
+	check := exec.CommandContext(ctx, h.gitPath, "-C", projectRoot, "rev-parse", "--is-inside-work-tree")
+	if out, err := check.Output(); err != nil || strings.TrimSpace(string(out)) != "true" {
+		return nil, entities.ErrNoHistory
+	}
+
+	// --relative makes paths relative to projectRoot even when the project lives
+	// in a subdirectory of the repository.
+	cmd := exec.CommandContext(ctx, h.gitPath, "-C", projectRoot, "log",
+		"--reverse", "--no-renames", "--name-status", "--relative",
+		"--format="+recordSep+"%H"+fieldSep+"%an"+fieldSep+"%aI"+fieldSep+"%s",
+		"--", pathPrefix,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		// A repository without commits has no history to report.
+		if strings.Contains(stderr.String(), "does not have any commits") {
+			return nil, entities.ErrNoHistory
+		}
+		return nil, fmt.Errorf("git log failed: %w\nOutput: %s", err, stderr.String())
+	}
+
+	return parseLog(string(out))
+}
+
+// parseLog parses the output of `git log --name-status` in the format produced by ListRevisions.
+func parseLog(output string) ([]usecases.Revision, error) {
+	var revisions []usecases.Revision
+
+	for _, record := range strings.Split(output, recordSep) {
+		record = strings.TrimSpace(record)
+		if record == "" {
+			continue
+		}
+
+		scanner := bufio.NewScanner(strings.NewReader(record))
+		if !scanner.Scan() {
+			continue
+		}
+		fields := strings.SplitN(scanner.Text(), fieldSep, 4)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("malformed git log header: %q", scanner.Text())
+		}
+		date, err := time.Parse(time.RFC3339, fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid commit date %q: %w", fields[2], err)
+		}
+
+		rev := usecases.Revision{
+			Commit:  fields[0],
+			Author:  fields[1],
+			Date:    date,
+			Message: fields[3],
+		}
+
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			status, filePath, ok := strings.Cut(line, "\t")
+			if !ok {
+				continue
+			}
+			rev.Changes = append(rev.Changes, usecases.FileRevision{
+				Path:   filePath,
+				Status: statusName(status),
+			})
+		}
+
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, nil
+}
+
+// statusName converts a git name-status letter into a FileRevision status.
+func statusName(status string) string {
+	switch {
+	case strings.HasPrefix(status, "A"):
+		return "added"
+	case strings.HasPrefix(status, "D"):
+		return "deleted"
+	default:
+		return "modified"
+	}
+}
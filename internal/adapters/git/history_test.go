@@ -0,0 +1,100 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestParseLog(t *testing.T) {
+	output := recordSep + "abc123" + fieldSep + "Ana" + fieldSep + "2026-01-02T10:00:00+00:00" + fieldSep + "add backend\n\n" +
+		"A\tsrc/backend/system.md\n" +
+		"M\tsrc/backend/api/container.md\n" +
+		recordSep + "def456" + fieldSep + "Ben" + fieldSep + "2026-01-03T10:00:00+00:00" + fieldSep + "remove api\n\n" +
+		"D\tsrc/backend/api/container.md\n"
+
+	revisions, err := parseLog(output)
+	if err != nil {
+		t.Fatalf("parseLog failed: %v", err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(revisions))
+	}
+	if revisions[0].Commit != "abc123" || revisions[0].Author != "Ana" || revisions[0].Message != "add backend" {
+		t.Errorf("unexpected first revision: %+v", revisions[0])
+	}
+	if len(revisions[0].Changes) != 2 || revisions[0].Changes[0].Status != "added" || revisions[0].Changes[1].Status != "modified" {
+		t.Errorf("unexpected changes: %+v", revisions[0].Changes)
+	}
+	if revisions[1].Changes[0].Status != "deleted" {
+		t.Errorf("expected deleted status, got %s", revisions[1].Changes[0].Status)
+	}
+}
+
+func TestParseLogMalformedHeader(t *testing.T) {
+	if _, err := parseLog(recordSep + "only-a-sha\n"); err == nil {
+		t.Error("expected error for malformed header")
+	}
+}
+
+func TestListRevisionsNotARepository(t *testing.T) {
+	h := NewHistory()
+	if !h.IsAvailable() {
+		t.Skip("git not installed")
+	}
+
+	_, err := h.ListRevisions(context.Background(), t.TempDir(), "src")
+	if !errors.Is(err, entities.ErrNoHistory) {
+		t.Errorf("expected ErrNoHistory, got %v", err)
+	}
+}
+
+func TestListRevisions(t *testing.T) {
+	h := NewHistory()
+	if !h.IsAvailable() {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Tester", "GIT_AUTHOR_EMAIL=t@example.com",
+			"GIT_COMMITTER_NAME=Tester", "GIT_COMMITTER_EMAIL=t@example.com",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+
+	run("init", "-q")
+	systemDir := filepath.Join(dir, "src", "backend")
+	if err := os.MkdirAll(systemDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(systemDir, "system.md"), []byte("# Backend\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("commit", "-q", "-m", "add backend")
+
+	revisions, err := h.ListRevisions(context.Background(), dir, "src")
+	if err != nil {
+		t.Fatalf("ListRevisions failed: %v", err)
+	}
+	if len(revisions) != 1 {
+		t.Fatalf("expected 1 revision, got %d", len(revisions))
+	}
+	if revisions[0].Author != "Tester" || revisions[0].Message != "add backend" {
+		t.Errorf("unexpected revision: %+v", revisions[0])
+	}
+	if len(revisions[0].Changes) != 1 || revisions[0].Changes[0].Path != "src/backend/system.md" {
+		t.Errorf("unexpected changes: %+v", revisions[0].Changes)
+	}
+}
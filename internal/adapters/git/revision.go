@@ -0,0 +1,68 @@
+package git
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// SourceRevision describes the commit a project is checked out at, for build
+// provenance: the URI is "git+<origin URL>" when an origin remote exists, and
+// uncommitted changes under projectRoot are flagged with a "dirty"
+// annotation. Returns entities.ErrNoHistory if git is missing, projectRoot is
+// not inside a work tree or the repository has no commits.
+func (h *History) SourceRevision(ctx context.Context, projectRoot string) (*entities.ResourceDescriptor, error) {
+	if !h.IsAvailable() {
+		return nil, entities.ErrNoHistory
+	}
+
+	out, err := exec.CommandContext(ctx, h.gitPath, "-C", projectRoot, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return nil, entities.ErrNoHistory
+	}
+	revision := &entities.ResourceDescriptor{
+		Digest: map[string]string{"gitCommit": strings.TrimSpace(string(out))},
+	}
+
+	if out, err := exec.CommandContext(ctx, h.gitPath, "-C", projectRoot, "remote", "get-url", "origin").Output(); err == nil {
+		revision.URI = "git+" + strings.TrimSpace(string(out))
+	} else {
+		revision.Name = "git"
+	}
+
+	status, err := exec.CommandContext(ctx, h.gitPath, "-C", projectRoot, "status", "--porcelain", "--", ".").Output()
+	if err == nil && len(strings.TrimSpace(string(status))) > 0 {
+		revision.Annotations = map[string]any{"dirty": true}
+	}
+	return revision, nil
+}
+
+// ChangedFiles returns the slash-separated paths, relative to projectRoot, of
+// files under projectRoot that differ from HEAD: modified, added, deleted and
+// untracked (but not ignored) files. Returns entities.ErrNoHistory if git is
+// missing, projectRoot is not inside a work tree or the repository has no
+// commits.
+func (h *History) ChangedFiles(ctx context.Context, projectRoot string) ([]string, error) {
+	if !h.IsAvailable() {
+		return nil, entities.ErrNoHistory
+	}
+
+	tracked, err := exec.CommandContext(ctx, h.gitPath, "-C", projectRoot, "diff", "--name-only", "--relative", "HEAD", "--", ".").Output()
+	if err != nil {
+		return nil, entities.ErrNoHistory
+	}
+	untracked, err := exec.CommandContext(ctx, h.gitPath, "-C", projectRoot, "ls-files", "--others", "--exclude-standard", "--", ".").Output()
+	if err != nil {
+		return nil, entities.ErrNoHistory
+	}
+
+	var files []string
+	for _, line := range strings.Split(string(tracked)+string(untracked), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
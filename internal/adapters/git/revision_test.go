@@ -0,0 +1,116 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// gitRunner returns a function that runs git in dir with a fixed identity
+// and returns its output.
+func gitRunner(t *testing.T, dir string) func(args ...string) string {
+	return func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Tester", "GIT_AUTHOR_EMAIL=t@example.com",
+			"GIT_COMMITTER_NAME=Tester", "GIT_COMMITTER_EMAIL=t@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return string(out)
+	}
+}
+
+func TestSourceRevision(t *testing.T) {
+	h := NewHistory()
+	if !h.IsAvailable() {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	if _, err := h.SourceRevision(context.Background(), dir); !errors.Is(err, entities.ErrNoHistory) {
+		t.Errorf("expected ErrNoHistory outside a repository, got %v", err)
+	}
+
+	run := gitRunner(t, dir)
+
+	run("init", "-q")
+	if err := os.WriteFile(filepath.Join(dir, "loko.toml"), []byte("[project]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("commit", "-q", "-m", "init")
+	run("remote", "add", "origin", "https://example.com/acme/arch.git")
+
+	revision, err := h.SourceRevision(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("SourceRevision failed: %v", err)
+	}
+	head := run("rev-parse", "HEAD")
+	if revision.Digest["gitCommit"]+"\n" != head || revision.URI != "git+https://example.com/acme/arch.git" {
+		t.Errorf("revision = %+v", revision)
+	}
+	if revision.Annotations["dirty"] != nil {
+		t.Error("clean tree reported dirty")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "loko.toml"), []byte("[project]\nname = \"x\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if revision, err = h.SourceRevision(context.Background(), dir); err != nil || revision.Annotations["dirty"] != true {
+		t.Errorf("expected dirty tree, got %+v, %v", revision, err)
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	h := NewHistory()
+	if !h.IsAvailable() {
+		t.Skip("git not installed")
+	}
+
+	repo := t.TempDir()
+	project := filepath.Join(repo, "docs")
+	write := func(rel, content string) {
+		t.Helper()
+		path := filepath.Join(repo, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run := gitRunner(t, repo)
+
+	run("init", "-q")
+	write("docs/src/payments/system.md", "# Payments\n")
+	write("docs/src/auth/system.md", "# Auth\n")
+	write("README.md", "readme\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "init")
+
+	write("docs/src/payments/system.md", "# Payments v2\n")
+	write("docs/src/payments/api/container.md", "# API\n")
+	write("README.md", "changed outside the project\n")
+	if err := os.Remove(filepath.Join(project, "src", "auth", "system.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := h.ChangedFiles(context.Background(), project)
+	if err != nil {
+		t.Fatalf("ChangedFiles failed: %v", err)
+	}
+	want := []string{"src/auth/system.md", "src/payments/system.md", "src/payments/api/container.md"}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Errorf("ChangedFiles = %v, want %v", files, want)
+	}
+}
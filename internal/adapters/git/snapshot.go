@@ -0,0 +1,102 @@
+package git
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// ExportRevision writes the project directory as it was at ref into destDir,
+// using `git archive` so the working tree is left untouched. When the project
+// lives in a subdirectory of the repository, only that subdirectory is exported
+// and destDir becomes its root. Returns entities.ErrNoHistory if git is missing
+// or projectRoot is not inside a work tree.
+func (h *History) ExportRevision(ctx context.Context, projectRoot, ref, destDir string) error {
+	if !h.IsAvailable() {
+		return entities.ErrNoHistory
+	}
+
+	// git archive must run from the top level; the project's prefix within the
+	// repository selects its subtree.
+	locate := exec.CommandContext(ctx, h.gitPath, "-C", projectRoot, "rev-parse", "--show-toplevel", "--show-prefix")
+	out, err := locate.Output()
+	if err != nil {
+		return entities.ErrNoHistory
+	}
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+	topLevel, prefix := lines[0], ""
+	if len(lines) > 1 {
+		prefix = strings.TrimSuffix(lines[1], "/")
+	}
+
+	cmd := exec.CommandContext(ctx, h.gitPath, "-C", topLevel, "archive", "--format=tar", ref+":"+prefix)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git archive %s failed: %w\nOutput: %s", ref, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return extractTar(&stdout, destDir)
+}
+
+// extractTar unpacks regular files and directories from r into destDir.
+// Entries that would escape destDir, symlinks and other special files are skipped.
+func extractTar(r io.Reader, destDir string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		name := filepath.FromSlash(hdr.Name)
+		if !filepath.IsLocal(name) {
+			continue
+		}
+		target := filepath.Join(destDir, name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", target, err)
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, tr, hdr.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// writeFile copies r into a new file at path, creating parent directories.
+func writeFile(path string, r io.Reader, perm os.FileMode) (err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close %s: %w", path, closeErr)
+		}
+	}()
+	if _, err := io.Copy(f, r); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
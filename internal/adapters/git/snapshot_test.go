@@ -0,0 +1,84 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestExportRevision(t *testing.T) {
+	h := NewHistory()
+	if !h.IsAvailable() {
+		t.Skip("git not installed")
+	}
+
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Tester", "GIT_AUTHOR_EMAIL=t@example.com",
+			"GIT_COMMITTER_NAME=Tester", "GIT_COMMITTER_EMAIL=t@example.com",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	write := func(rel, content string) {
+		t.Helper()
+		path := filepath.Join(repo, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The project lives in a subdirectory of the repository.
+	run("init", "-q")
+	write("docs/arch/src/backend/system.md", "# Backend v1\n")
+	write("README.md", "repo readme\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "v1")
+	run("tag", "v1")
+	write("docs/arch/src/backend/system.md", "# Backend v2\n")
+	run("commit", "-q", "-am", "v2")
+
+	dest := t.TempDir()
+	if err := h.ExportRevision(context.Background(), filepath.Join(repo, "docs", "arch"), "v1", dest); err != nil {
+		t.Fatalf("ExportRevision failed: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dest, "src", "backend", "system.md"))
+	if err != nil {
+		t.Fatalf("expected exported system.md: %v", err)
+	}
+	if string(got) != "# Backend v1\n" {
+		t.Errorf("system.md = %q, want v1 content", got)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "README.md")); !os.IsNotExist(err) {
+		t.Error("expected files outside the project directory to be excluded")
+	}
+
+	if err := h.ExportRevision(context.Background(), repo, "no-such-ref", t.TempDir()); err == nil {
+		t.Error("expected error for unknown ref")
+	}
+}
+
+func TestExportRevisionNotARepository(t *testing.T) {
+	h := NewHistory()
+	if !h.IsAvailable() {
+		t.Skip("git not installed")
+	}
+
+	err := h.ExportRevision(context.Background(), t.TempDir(), "HEAD", t.TempDir())
+	if !errors.Is(err, entities.ErrNoHistory) {
+		t.Errorf("expected ErrNoHistory, got %v", err)
+	}
+}
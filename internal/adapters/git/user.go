@@ -0,0 +1,20 @@
+package git
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+)
+
+// UserName returns the user.name configured for projectRoot, or "" when git
+// is missing or no name is configured.
+func (h *History) UserName(ctx context.Context, projectRoot string) string {
+	if !h.IsAvailable() {
+		return ""
+	}
+	out, err := exec.CommandContext(ctx, h.gitPath, "-C", projectRoot, "config", "user.name").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,24 @@
+package git
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUserName(t *testing.T) {
+	h := NewHistory()
+	if !h.IsAvailable() {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	run := gitRunner(t, dir)
+	run("init", "-q")
+	run("config", "user.name", "Ana Reviewer")
+
+	if got := h.UserName(context.Background(), dir); got != "Ana Reviewer" {
+		t.Errorf("UserName() = %q, want %q", got, "Ana Reviewer")
+	}
+	if got := (&History{}).UserName(context.Background(), dir); got != "" {
+		t.Errorf("UserName() without git = %q, want empty", got)
+	}
+}
@@ -0,0 +1,109 @@
+package html
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// fingerprintLength is the number of hex digits of the content hash embedded
+// in asset file names.
+const fingerprintLength = 10
+
+// staticAsset is a CSS or JavaScript file written alongside the generated pages.
+type staticAsset struct {
+	path    string // Logical slash-separated path referenced by templates, e.g. "styles/style.css"
+	content string
+}
+
+// staticAssets lists the assets written by writeAssets.
+var staticAssets = []staticAsset{
+	{path: "styles/style.css", content: cssContent},
+	{path: "js/main.js", content: jsContent},
+	{path: "js/graph.js", content: graphJSContent},
+	{path: "js/diagram-viewer.js", content: diagramViewerJSContent},
+}
+
+// fingerprintedPaths maps each logical asset path to its content-hashed path,
+// e.g. "styles/style.css" to "styles/style.3f2a9c01be.css". The hash changes
+// whenever the asset changes, so deployed sites can cache assets indefinitely.
+var fingerprintedPaths = func() map[string]string {
+	paths := make(map[string]string, len(staticAssets))
+	for _, a := range staticAssets {
+		paths[a.path] = fingerprint(a.path, a.content)
+	}
+	return paths
+}()
+
+// fingerprint inserts a hash of content before the extension of assetPath.
+func fingerprint(assetPath, content string) string {
+	sum := sha256.Sum256([]byte(content))
+	ext := path.Ext(assetPath)
+	return strings.TrimSuffix(assetPath, ext) + "." + hex.EncodeToString(sum[:])[:fingerprintLength] + ext
+}
+
+// assetPath returns the fingerprinted path of a static asset. It is exposed to
+// templates as the "asset" function: {{asset "styles/style.css"}}.
+func assetPath(logical string) (string, error) {
+	p, ok := fingerprintedPaths[logical]
+	if !ok {
+		return "", fmt.Errorf("unknown asset %q", logical)
+	}
+	return p, nil
+}
+
+// writeAssets writes CSS and JavaScript files under their fingerprinted names
+// and removes copies left behind by earlier builds with different content.
+func (b *Builder) writeAssets(outputDir string) error {
+	for _, a := range staticAssets {
+		current := fingerprintedPaths[a.path]
+		filePath := filepath.Join(outputDir, filepath.FromSlash(current))
+		if err := os.WriteFile(filePath, []byte(a.content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", a.path, err)
+		}
+		if err := removeStaleAssets(outputDir, a.path, current); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeStaleAssets deletes fingerprinted copies of logical other than current.
+func removeStaleAssets(outputDir, logical, current string) error {
+	ext := path.Ext(logical)
+	stem := strings.TrimSuffix(logical, ext)
+	pattern := filepath.Join(outputDir, filepath.FromSlash(stem)+".*"+ext)
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return fmt.Errorf("failed to list old %s files: %w", logical, err)
+	}
+	for _, match := range matches {
+		name := filepath.Base(match)
+		hash := strings.TrimSuffix(strings.TrimPrefix(name, path.Base(stem)+"."), ext)
+		if name == path.Base(current) || !isHex(hash, fingerprintLength) {
+			continue
+		}
+		if err := os.Remove(match); err != nil {
+			return fmt.Errorf("failed to remove stale asset %s: %w", match, err)
+		}
+	}
+	return nil
+}
+
+// isHex reports whether s is exactly n lower-case hex digits.
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
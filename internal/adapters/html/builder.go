@@ -4,29 +4,44 @@
 package html
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"text/template"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
 )
 
 // Builder implements the SiteBuilder interface by generating static HTML documentation.
 // It produces a complete website with index, system pages, diagrams, and search functionality.
 type Builder struct {
 	templates        *template.Template
-	cssTokens        map[string]string // Design system tokens for CSS generation
-	markdownRenderer *MarkdownRenderer // Renderer for markdown content
+	cssTokens        map[string]string                  // Design system tokens for CSS generation
+	markdownRenderer *MarkdownRenderer                  // Renderer for markdown content
+	timeline         *entities.Timeline                 // Optional architecture history for the timeline page
+	relationships    map[string][]entities.Relationship // relationships.toml entries by system ID
+	readSource       func(path string) ([]byte, error)  // Reads element Markdown files
+	router           *Router                            // Page files and link URLs; nil keeps the flat layout
+	stages           usecases.BuildStages               // Assets, pages and search index to build; zero builds all
+	layouts          map[string]bool                    // Selectors of the element pages with a layout, see WithLayouts
+	people           []*entities.Person                 // People of the project being built, listed on the systems they use
+	searchShards     map[string]searchShardState        // Search shards of the last build by system ID, reused when unchanged
+	cache            usecases.BuildCache                // Page keys of earlier builds; optional, see WithBuildCache
+	variant          string                             // Hash of the settings pages render with, see vary
+	pages            *pageCache                         // Page keys during BuildSite; nil when not caching
 }
 
+// precompiledTemplates parses the embedded templates once per process. Parsed
+// templates are safe for concurrent execution, so every Builder shares them.
+var precompiledTemplates = sync.OnceValues(parseTemplates)
+
 // NewBuilder creates a new HTML site builder with embedded templates.
 func NewBuilder() (*Builder, error) {
-	tmpl, err := parseTemplates()
+	tmpl, err := precompiledTemplates()
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse templates: %w", err)
 	}
@@ -35,9 +50,45 @@ func NewBuilder() (*Builder, error) {
 		templates:        tmpl,
 		cssTokens:        getDefaultCSSTokens(),
 		markdownRenderer: NewMarkdownRenderer("", ""),
+		readSource:       os.ReadFile,
+		variant:          templatesVariant(),
 	}, nil
 }
 
+// WithTimeline sets the architecture history rendered as timeline.html.
+// When the timeline is nil or empty, no timeline page is generated.
+func (b *Builder) WithTimeline(timeline *entities.Timeline) *Builder {
+	b.timeline = timeline
+	return b
+}
+
+// WithRelationships sets the relationships, keyed by system ID, listed on
+// each system page together with their technology.
+func (b *Builder) WithRelationships(relationships map[string][]entities.Relationship) *Builder {
+	b.relationships = relationships
+	return b
+}
+
+// WithSourceReader sets the function used to read element Markdown files, so
+// sources encrypted at rest can be decrypted while building.
+func (b *Builder) WithSourceReader(read func(path string) ([]byte, error)) *Builder {
+	b.readSource = read
+	return b
+}
+
+// WithRouter sets the base path and URL style of the site, see Router.
+func (b *Builder) WithRouter(router *Router) *Builder {
+	b.router = router
+	return b
+}
+
+// WithStages limits BuildSite to the assets, pages and search index selected
+// by stages, so e.g. templates can be iterated on without rewriting assets.
+func (b *Builder) WithStages(stages usecases.BuildStages) *Builder {
+	b.stages = stages
+	return b
+}
+
 // BuildSite generates HTML documentation from a project.
 // Creates an output directory with index.html, system pages, diagrams, and static assets.
 func (b *Builder) BuildSite(ctx context.Context, project *entities.Project, systems []*entities.System, outputDir string) error {
@@ -48,51 +99,62 @@ func (b *Builder) BuildSite(ctx context.Context, project *entities.Project, syst
 		return fmt.Errorf("output directory cannot be empty")
 	}
 
+	b.people = project.ListPeople()
+
 	// Create output directory structure
 	if err := b.createDirectories(outputDir); err != nil {
 		return fmt.Errorf("failed to create directories: %w", err)
 	}
 
 	// Write static assets (CSS and JS)
-	if err := b.writeAssets(outputDir); err != nil {
-		return fmt.Errorf("failed to write assets: %w", err)
+	if b.stages.Runs(usecases.StageAssets) {
+		if err := b.writeAssets(outputDir); err != nil {
+			return fmt.Errorf("failed to write assets: %w", err)
+		}
+	}
+
+	domains := entities.BuildDomains(systems)
+	if b.stages.Runs(usecases.StagePages) {
+		b.startPageCache(ctx, systems, outputDir)
+		if err := b.buildPages(ctx, project, systems, domains, outputDir); err != nil {
+			b.pages = nil
+			return err
+		}
+		b.finishPageCache(ctx)
+	}
+
+	// Build search index
+	if b.stages.Runs(usecases.StageSearchIndex) {
+		if err := b.buildSearchIndex(systems, domains, outputDir); err != nil {
+			return fmt.Errorf("failed to build search index: %w", err)
+		}
 	}
 
+	return nil
+}
+
+// buildPages generates every page of the site.
+func (b *Builder) buildPages(ctx context.Context, project *entities.Project, systems []*entities.System, domains []*entities.Domain, outputDir string) error {
 	// Build index page
-	if err := b.buildIndexPage(ctx, project, systems, outputDir); err != nil {
+	costs := usecases.NewBuildCostReport(project.Config).Execute(ctx, systems)
+	if err := b.buildIndexPage(ctx, project, systems, domains, costs, outputDir); err != nil {
 		return fmt.Errorf("failed to build index page: %w", err)
 	}
 
-	// Build system pages
-	for _, system := range systems {
-		if system == nil {
-			continue
-		}
-		containers := system.ListContainers()
-		if err := b.BuildSystemPage(ctx, system, containers, outputDir); err != nil {
-			return fmt.Errorf("failed to build system page for %s: %w", system.Name, err)
-		}
+	// Build domain pages above the system pages
+	if err := b.buildDomainPages(ctx, project, systems, domains, outputDir); err != nil {
+		return fmt.Errorf("failed to build domain pages: %w", err)
+	}
 
-		// Build container pages
-		for _, container := range containers {
-			if container == nil {
-				continue
-			}
-			components := container.ListComponents()
-			if err := b.BuildContainerPage(ctx, system, container, components, outputDir); err != nil {
-				return fmt.Errorf("failed to build container page for %s/%s: %w", system.Name, container.Name, err)
-			}
+	// Compute per-system KPI badges once for all system pages
+	kpis, err := usecases.NewComputeSystemKPIs().Execute(ctx, project, systems)
+	if err != nil {
+		return fmt.Errorf("failed to compute system KPIs: %w", err)
+	}
 
-			// Build component pages
-			for _, component := range components {
-				if component == nil {
-					continue
-				}
-				if err := b.BuildComponentPage(ctx, system, container, component, outputDir); err != nil {
-					return fmt.Errorf("failed to build component page for %s/%s/%s: %w", system.Name, container.Name, component.Name, err)
-				}
-			}
-		}
+	// Build system, container and component pages in parallel
+	if err := b.buildEntityPages(ctx, systems, kpis, outputDir); err != nil {
+		return err
 	}
 
 	// Build containers overview page
@@ -105,61 +167,99 @@ func (b *Builder) BuildSite(ctx context.Context, project *entities.Project, syst
 		return fmt.Errorf("failed to build components overview: %w", err)
 	}
 
-	// Build search index
-	if err := b.buildSearchIndex(systems, outputDir); err != nil {
-		return fmt.Errorf("failed to build search index: %w", err)
+	// Build interactive graph explorer page
+	if err := b.buildGraphPage(ctx, project, systems, outputDir); err != nil {
+		return fmt.Errorf("failed to build graph page: %w", err)
+	}
+
+	// Build architecture timeline page
+	if !b.timeline.IsEmpty() {
+		if err := b.buildTimelinePage(ctx, project, systems, outputDir); err != nil {
+			return fmt.Errorf("failed to build timeline page: %w", err)
+		}
 	}
 
+	// Build cost rollup page
+	if costs.HasCosts() {
+		if err := b.buildCostsPage(ctx, project, systems, costs, outputDir); err != nil {
+			return fmt.Errorf("failed to build costs page: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// buildEntityPage generates the page for a single system, container or component.
+// kpis supplies the badges shown on system pages, keyed by system ID.
+func (b *Builder) buildEntityPage(ctx context.Context, page entityPage, kpis map[string]*usecases.SystemKPIs, outputDir string) error {
+	switch {
+	case page.Component != nil:
+		if err := b.BuildComponentPage(ctx, page.System, page.Container, page.Component, outputDir); err != nil {
+			return fmt.Errorf("failed to build component page for %s/%s/%s: %w", page.System.Name, page.Container.Name, page.Component.Name, err)
+		}
+	case page.Container != nil:
+		if err := b.BuildContainerPage(ctx, page.System, page.Container, page.Container.ListComponents(), outputDir); err != nil {
+			return fmt.Errorf("failed to build container page for %s/%s: %w", page.System.Name, page.Container.Name, err)
+		}
+	default:
+		if err := b.buildSystemPage(page.System, page.System.ListContainers(), kpis[page.System.ID], outputDir); err != nil {
+			return fmt.Errorf("failed to build system page for %s: %w", page.System.Name, err)
+		}
+	}
 	return nil
 }
 
 // BuildSystemPage generates a single system HTML page with embedded diagrams.
+// Built on its own, the page's KPI badges omit validation warnings because the
+// rest of the architecture is not available to validate against.
 func (b *Builder) BuildSystemPage(_ context.Context, system *entities.System, containers []*entities.Container, outputDir string) error {
 	if system == nil {
 		return fmt.Errorf("system cannot be nil")
 	}
+	return b.buildSystemPage(system, containers, nil, outputDir)
+}
+
+// buildSystemPage renders a system page with the given KPI badges, computing
+// the system-local KPIs when kpis is nil.
+func (b *Builder) buildSystemPage(system *entities.System, containers []*entities.Container, kpis *usecases.SystemKPIs, outputDir string) error {
 	if outputDir == "" {
 		return fmt.Errorf("output directory cannot be empty")
 	}
+	if kpis == nil {
+		kpis = usecases.SystemKPIsFor(system)
+	}
 
-	// Try to read and render markdown content
-	markdownContent := ""
+	markdownPath := ""
 	if system.Path != "" {
-		markdownPath := filepath.Join(system.Path, "system.md")
-		if content, err := os.ReadFile(markdownPath); err == nil {
-			// Render markdown to HTML fragment (content only, no HTML wrapper)
-			fullHTML := b.markdownRenderer.RenderMarkdownToHTML(string(content))
-			// Extract just the content part (between <div class="container"> and </div>)
-			markdownContent = b.extractMarkdownContent(fullHTML)
-		}
+		markdownPath = filepath.Join(system.Path, "system.md")
 	}
 
-	// Prepare template data
+	// Prepare template data; writeElementPage adds the rendered markdown
 	data := map[string]any{
-		"System":          system,
-		"Containers":      containers,
-		"MarkdownContent": markdownContent,
-		"HasMarkdown":     markdownContent != "",
+		"System":        system,
+		"Containers":    containers,
+		"KPIs":          kpis,
+		"Relationships": b.relationships[system.ID],
+		"Users":         usersOf(b.people, system.ID),
 	}
 
-	// Render template
-	var buf bytes.Buffer
-	if err := b.templates.ExecuteTemplate(&buf, "system.html", data); err != nil {
-		return fmt.Errorf("failed to render system template: %w", err)
+	templateName := b.pageTemplate(LayoutSystem, system.Tags, system.External, "system.html")
+	if err := b.writeElementPage(outputDir, "systems/"+system.ID+".html", templateName, markdownPath, data); err != nil {
+		return fmt.Errorf("failed to write system page: %w", err)
 	}
 
-	// Write to file
-	systemsDir := filepath.Join(outputDir, "systems")
-	if err := os.MkdirAll(systemsDir, 0755); err != nil {
-		return fmt.Errorf("failed to create systems directory: %w", err)
-	}
+	return nil
+}
 
-	filePath := filepath.Join(systemsDir, system.ID+".html")
-	if err := os.WriteFile(filePath, buf.Bytes(), 0644); err != nil {
-		return fmt.Errorf("failed to write system page %s: %w", filePath, err)
+// usersOf returns the people that use the system with ID systemID.
+func usersOf(people []*entities.Person, systemID string) []*entities.Person {
+	var users []*entities.Person
+	for _, person := range people {
+		if person.Uses(systemID) {
+			users = append(users, person)
+		}
 	}
-
-	return nil
+	return users
 }
 
 // extractMarkdownContent extracts the content body from rendered HTML.
@@ -193,42 +293,21 @@ func (b *Builder) BuildContainerPage(_ context.Context, system *entities.System,
 		return fmt.Errorf("output directory cannot be empty")
 	}
 
-	// Try to read and render markdown content
-	markdownContent := ""
+	markdownPath := ""
 	if container.Path != "" {
-		markdownPath := filepath.Join(container.Path, "container.md")
-		if content, err := os.ReadFile(markdownPath); err == nil {
-			// Render markdown to HTML fragment (content only, no HTML wrapper)
-			fullHTML := b.markdownRenderer.RenderMarkdownToHTML(string(content))
-			// Extract just the content part (between <div class="container"> and </div>)
-			markdownContent = b.extractMarkdownContent(fullHTML)
-		}
+		markdownPath = filepath.Join(container.Path, "container.md")
 	}
 
-	// Prepare template data
+	// Prepare template data; writeElementPage adds the rendered markdown
 	data := map[string]any{
-		"System":          system,
-		"Container":       container,
-		"Components":      components,
-		"MarkdownContent": markdownContent,
-		"HasMarkdown":     markdownContent != "",
-	}
-
-	// Render template
-	var buf bytes.Buffer
-	if err := b.templates.ExecuteTemplate(&buf, "container.html", data); err != nil {
-		return fmt.Errorf("failed to render container template: %w", err)
+		"System":     system,
+		"Container":  container,
+		"Components": components,
 	}
 
-	// Write to file
-	containersDir := filepath.Join(outputDir, "containers")
-	if err := os.MkdirAll(containersDir, 0755); err != nil {
-		return fmt.Errorf("failed to create containers directory: %w", err)
-	}
-
-	filePath := filepath.Join(containersDir, system.ID+"_"+container.ID+".html")
-	if err := os.WriteFile(filePath, buf.Bytes(), 0644); err != nil {
-		return fmt.Errorf("failed to write container page %s: %w", filePath, err)
+	templateName := b.pageTemplate(LayoutContainer, container.Tags, false, "container.html")
+	if err := b.writeElementPage(outputDir, "containers/"+system.ID+"_"+container.ID+".html", templateName, markdownPath, data); err != nil {
+		return fmt.Errorf("failed to write container page: %w", err)
 	}
 
 	return nil
@@ -263,14 +342,8 @@ func (b *Builder) buildContainersOverview(_ context.Context, systems []*entities
 		"Systems":    systems,
 	}
 
-	var buf bytes.Buffer
-	if err := b.templates.ExecuteTemplate(&buf, "containers-overview.html", data); err != nil {
-		return fmt.Errorf("failed to render containers overview template: %w", err)
-	}
-
-	filePath := filepath.Join(outputDir, "containers.html")
-	if err := os.WriteFile(filePath, buf.Bytes(), 0644); err != nil {
-		return fmt.Errorf("failed to write containers overview page %s: %w", filePath, err)
+	if err := b.writePage(outputDir, "containers.html", "containers-overview.html", data); err != nil {
+		return fmt.Errorf("failed to write containers overview page: %w", err)
 	}
 
 	return nil
@@ -291,42 +364,23 @@ func (b *Builder) BuildComponentPage(_ context.Context, system *entities.System,
 		return fmt.Errorf("output directory cannot be empty")
 	}
 
-	// Try to read and render markdown content
-	markdownContent := ""
+	markdownPath := ""
 	if component.Path != "" {
-		markdownPath := filepath.Join(component.Path, "component.md")
-		if content, err := os.ReadFile(markdownPath); err == nil {
-			// Render markdown to HTML fragment (content only, no HTML wrapper)
-			fullHTML := b.markdownRenderer.RenderMarkdownToHTML(string(content))
-			// Extract just the content part (between <div class="container"> and </div>)
-			markdownContent = b.extractMarkdownContent(fullHTML)
-		}
+		markdownPath = filepath.Join(component.Path, "component.md")
 	}
 
-	// Prepare template data
+	// Prepare template data; writeElementPage adds the rendered markdown
 	data := map[string]any{
 		"System":          system,
 		"Container":       container,
 		"Component":       component,
-		"MarkdownContent": markdownContent,
-		"HasMarkdown":     markdownContent != "",
-	}
-
-	// Render template
-	var buf bytes.Buffer
-	if err := b.templates.ExecuteTemplate(&buf, "component.html", data); err != nil {
-		return fmt.Errorf("failed to render component template: %w", err)
+		"ParentComponent": container.Components[component.Parent],
+		"SubComponents":   container.SubComponents(component.ID),
 	}
 
-	// Write to file
-	componentsDir := filepath.Join(outputDir, "components")
-	if err := os.MkdirAll(componentsDir, 0755); err != nil {
-		return fmt.Errorf("failed to create components directory: %w", err)
-	}
-
-	filePath := filepath.Join(componentsDir, component.ID+".html")
-	if err := os.WriteFile(filePath, buf.Bytes(), 0644); err != nil {
-		return fmt.Errorf("failed to write component page %s: %w", filePath, err)
+	templateName := b.pageTemplate(LayoutComponent, component.Tags, false, "component.html")
+	if err := b.writeElementPage(outputDir, "components/"+component.ID+".html", templateName, markdownPath, data); err != nil {
+		return fmt.Errorf("failed to write component page: %w", err)
 	}
 
 	return nil
@@ -368,89 +422,33 @@ func (b *Builder) buildComponentsOverview(_ context.Context, systems []*entities
 		"Systems":    systems,
 	}
 
-	var buf bytes.Buffer
-	if err := b.templates.ExecuteTemplate(&buf, "components-overview.html", data); err != nil {
-		return fmt.Errorf("failed to render components overview template: %w", err)
-	}
-
-	filePath := filepath.Join(outputDir, "components.html")
-	if err := os.WriteFile(filePath, buf.Bytes(), 0644); err != nil {
-		return fmt.Errorf("failed to write components overview page %s: %w", filePath, err)
+	if err := b.writePage(outputDir, "components.html", "components-overview.html", data); err != nil {
+		return fmt.Errorf("failed to write components overview page: %w", err)
 	}
 
 	return nil
 }
 
-// buildIndexPage generates the project index page.
-func (b *Builder) buildIndexPage(_ context.Context, project *entities.Project, systems []*entities.System, outputDir string) error {
+// buildIndexPage generates the project index page with the system landscape
+// diagram, when rendered. When systems are grouped into domains, it lists the
+// top-level domains with the domain landscape diagram.
+func (b *Builder) buildIndexPage(_ context.Context, project *entities.Project, systems []*entities.System, domains []*entities.Domain, costs *usecases.CostReport, outputDir string) error {
 	data := map[string]any{
-		"Project": project,
-		"Systems": systems,
-	}
+		"Project":     project,
+		"Systems":     systems,
+		"Domains":     domains,
+		"People":      b.people,
+		"HasTimeline": !b.timeline.IsEmpty(),
+		"HasCosts":    costs.HasCosts(),
 
-	var buf bytes.Buffer
-	if err := b.templates.ExecuteTemplate(&buf, "index.html", data); err != nil {
-		return fmt.Errorf("failed to render index template: %w", err)
+		"SystemLandscapePath": renderedDiagram(usecases.LandscapeDiagramFile, outputDir),
 	}
-
-	filePath := filepath.Join(outputDir, "index.html")
-	if err := os.WriteFile(filePath, buf.Bytes(), 0644); err != nil {
-		return fmt.Errorf("failed to write index page: %w", err)
+	if len(domains) > 0 {
+		data["LandscapePath"] = renderedDomainDiagram("", outputDir)
 	}
 
-	return nil
-}
-
-// buildSearchIndex generates a JSON search index for client-side search.
-func (b *Builder) buildSearchIndex(systems []*entities.System, outputDir string) error {
-	type SearchResult struct {
-		Title       string `json:"title"`
-		URL         string `json:"url"`
-		Description string `json:"description"`
-		Type        string `json:"type"`
-	}
-
-	type SearchIndex struct {
-		Results []SearchResult `json:"results"`
-	}
-
-	var results []SearchResult
-
-	// Add systems to search index
-	for _, system := range systems {
-		if system == nil {
-			continue
-		}
-		results = append(results, SearchResult{
-			Title:       system.Name,
-			URL:         fmt.Sprintf("systems/%s.html", system.ID),
-			Description: system.Description,
-			Type:        "system",
-		})
-
-		// Add containers to search index
-		for _, container := range system.ListContainers() {
-			if container == nil {
-				continue
-			}
-			results = append(results, SearchResult{
-				Title:       container.Name,
-				URL:         fmt.Sprintf("systems/%s.html#%s", system.ID, container.ID),
-				Description: container.Description,
-				Type:        "container",
-			})
-		}
-	}
-
-	index := SearchIndex{Results: results}
-	data, err := json.MarshalIndent(index, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal search index: %w", err)
-	}
-
-	filePath := filepath.Join(outputDir, "search.json")
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write search index: %w", err)
+	if err := b.writePage(outputDir, "index.html", "index.html", data); err != nil {
+		return fmt.Errorf("failed to write index page: %w", err)
 	}
 
 	return nil
@@ -463,9 +461,11 @@ func (b *Builder) createDirectories(outputDir string) error {
 		filepath.Join(outputDir, "systems"),
 		filepath.Join(outputDir, "containers"),
 		filepath.Join(outputDir, "components"),
+		filepath.Join(outputDir, "domains"),
 		filepath.Join(outputDir, "diagrams"),
 		filepath.Join(outputDir, "styles"),
 		filepath.Join(outputDir, "js"),
+		filepath.Join(outputDir, usecases.SearchShardDir),
 	}
 
 	for _, dir := range dirs {
@@ -477,26 +477,14 @@ func (b *Builder) createDirectories(outputDir string) error {
 	return nil
 }
 
-// writeAssets writes CSS and JavaScript files to the output directory.
-func (b *Builder) writeAssets(outputDir string) error {
-	// Write CSS
-	cssPath := filepath.Join(outputDir, "styles", "style.css")
-	if err := os.WriteFile(cssPath, []byte(cssContent), 0644); err != nil {
-		return fmt.Errorf("failed to write CSS: %w", err)
-	}
-
-	// Write JavaScript
-	jsPath := filepath.Join(outputDir, "js", "main.js")
-	if err := os.WriteFile(jsPath, []byte(jsContent), 0644); err != nil {
-		return fmt.Errorf("failed to write JavaScript: %w", err)
-	}
-
-	return nil
-}
-
 // parseTemplates parses all embedded HTML templates.
 func parseTemplates() (*template.Template, error) {
-	tmpl := template.New("base")
+	tmpl := template.New("base").
+		Funcs(template.FuncMap{"asset": assetPath}).
+		Funcs(customizationFuncs("", "")).
+		Funcs(issueFuncs("")).
+		Funcs(iconFuncs(entities.NewIconRegistry(nil))).
+		Funcs(customFieldFuncs(nil))
 
 	// Parse all templates
 	for name, content := range templateMap {
@@ -509,6 +497,13 @@ func parseTemplates() (*template.Template, error) {
 	return tmpl, nil
 }
 
+// withFuncs returns a copy of the builder's templates with funcs bound; a nil
+// funcs only copies them. The templates are shared by every Builder, so they
+// are never changed in place. text/template's Clone cannot fail.
+func (b *Builder) withFuncs(funcs template.FuncMap) *template.Template {
+	return template.Must(b.templates.Clone()).Funcs(funcs)
+}
+
 // getDefaultCSSTokens returns the default design system tokens for CSS generation.
 func getDefaultCSSTokens() map[string]string {
 	return map[string]string{
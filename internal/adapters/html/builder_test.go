@@ -2,12 +2,17 @@ package html
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
 )
 
 // TestNewBuilder tests the NewBuilder factory function.
@@ -61,8 +66,8 @@ func TestBuildSiteBasic(t *testing.T) {
 		"index.html",
 		"systems/payment-service.html",
 		"search.json",
-		"styles/style.css",
-		"js/main.js",
+		fingerprintedPaths["styles/style.css"],
+		fingerprintedPaths["js/main.js"],
 	}
 
 	for _, file := range expectedFiles {
@@ -437,13 +442,94 @@ func TestSearchIndexContent(t *testing.T) {
 		t.Fatalf("failed to read search.json: %v", err)
 	}
 
-	// Verify it contains expected entries
-	contentStr := string(content)
-	if !contains(contentStr, "Payment Service") {
+	// Verify it contains expected entries, with containers in the shard of
+	// their system
+	var index searchIndex
+	if err := json.Unmarshal(content, &index); err != nil {
+		t.Fatalf("invalid search.json: %v", err)
+	}
+	if !contains(string(content), "Payment Service") {
 		t.Error("search index missing system name")
 	}
-	if !contains(contentStr, "API") {
-		t.Error("search index missing container name")
+	if len(index.Shards) != 1 || index.Shards[0].System != "payment" || index.Shards[0].Count != 1 {
+		t.Fatalf("shards = %+v, want one of payment", index.Shards)
+	}
+	shard, err := os.ReadFile(filepath.Join(tmpDir, filepath.FromSlash(index.Shards[0].Path)))
+	if err != nil {
+		t.Fatalf("failed to read search shard: %v", err)
+	}
+	var containerIndex searchIndex
+	if err := json.Unmarshal(shard, &containerIndex); err != nil {
+		t.Fatalf("invalid search shard: %v", err)
+	}
+	if len(containerIndex.Results) != 1 || containerIndex.Results[0].Title != "API" || containerIndex.Results[0].Type != "container" {
+		t.Errorf("search shard results = %+v, want container API", containerIndex.Results)
+	}
+	if !contains(string(shard), "REST API") {
+		t.Error("search shard missing container description")
+	}
+}
+
+// TestSearchIndexIncremental tests that rebuilds rewrite only the search
+// shards of systems whose sources changed and remove the stale ones.
+func TestSearchIndexIncremental(t *testing.T) {
+	tmpDir := t.TempDir()
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+	newSystem := func(id string) *entities.System {
+		api := &entities.Container{ID: "api", Name: "API", ContentHash: "sha256:a", Components: make(map[string]*entities.Component)}
+		return &entities.System{ID: id, Name: id, ContentHash: "sha256:s", Containers: map[string]*entities.Container{"api": api}}
+	}
+	shop, ledger := newSystem("shop"), newSystem("ledger")
+	shards := func() map[string]string {
+		content, err := os.ReadFile(filepath.Join(tmpDir, "search.json"))
+		if err != nil {
+			t.Fatalf("failed to read search.json: %v", err)
+		}
+		var index searchIndex
+		if err := json.Unmarshal(content, &index); err != nil {
+			t.Fatalf("invalid search.json: %v", err)
+		}
+		paths := make(map[string]string)
+		for _, shard := range index.Shards {
+			paths[shard.System] = filepath.Join(tmpDir, filepath.FromSlash(shard.Path))
+		}
+		return paths
+	}
+	build := func(systems ...*entities.System) {
+		if err := builder.BuildSite(context.Background(), &entities.Project{Name: "Test"}, systems, tmpDir); err != nil {
+			t.Fatalf("BuildSite failed: %v", err)
+		}
+	}
+
+	build(shop, ledger)
+	first := shards()
+	if err := os.WriteFile(first["ledger"], []byte(`{"results": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	shop.Containers["api"].Description = "Orders API"
+	shop.Containers["api"].ContentHash = "sha256:b"
+	build(shop, ledger)
+	second := shards()
+	if second["ledger"] != first["ledger"] {
+		t.Error("unchanged ledger shard was renamed")
+	}
+	if content, _ := os.ReadFile(second["ledger"]); string(content) != `{"results": []}` {
+		t.Error("unchanged ledger shard was rewritten")
+	}
+	if second["shop"] == first["shop"] {
+		t.Error("changed shop shard kept its name")
+	}
+	if _, err := os.Stat(first["shop"]); !os.IsNotExist(err) {
+		t.Error("stale shop shard was not removed")
+	}
+
+	build(shop)
+	if _, err := os.Stat(second["ledger"]); !os.IsNotExist(err) {
+		t.Error("shard of removed ledger was not removed")
 	}
 }
 
@@ -468,7 +554,7 @@ func TestAssetGeneration(t *testing.T) {
 	}
 
 	// Verify CSS file
-	cssPath := filepath.Join(tmpDir, "styles", "style.css")
+	cssPath := filepath.Join(tmpDir, fingerprintedPaths["styles/style.css"])
 	cssContent, err := os.ReadFile(cssPath)
 	if err != nil {
 		t.Fatalf("failed to read CSS: %v", err)
@@ -478,7 +564,7 @@ func TestAssetGeneration(t *testing.T) {
 	}
 
 	// Verify JS file
-	jsPath := filepath.Join(tmpDir, "js", "main.js")
+	jsPath := filepath.Join(tmpDir, fingerprintedPaths["js/main.js"])
 	jsContent, err := os.ReadFile(jsPath)
 	if err != nil {
 		t.Fatalf("failed to read JS: %v", err)
@@ -602,3 +688,1142 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+// TestBuildGraphPage tests that the graph explorer page and graph data are generated.
+func TestBuildGraphPage(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+
+	project := &entities.Project{
+		Name:    "Graph Test",
+		Systems: make(map[string]*entities.System),
+	}
+
+	components := map[string]*entities.Component{
+		"auth": {
+			ID:            "auth",
+			Name:          "Auth",
+			Tags:          []string{"security"},
+			Relationships: map[string]string{"store": "reads sessions"},
+		},
+		"store": {ID: "store", Name: "Store"},
+	}
+	containers := map[string]*entities.Container{
+		"api": {ID: "api", Name: "API", Technology: "Go", Components: components},
+	}
+	systems := []*entities.System{
+		{ID: "payment", Name: "Payment Service", Containers: containers},
+	}
+
+	if err := builder.BuildSite(ctx, project, systems, tmpDir); err != nil {
+		t.Fatalf("BuildSite failed: %v", err)
+	}
+
+	for _, file := range []string{"graph.html", "graph.json", fingerprintedPaths["js/graph.js"]} {
+		if _, err := os.Stat(filepath.Join(tmpDir, file)); os.IsNotExist(err) {
+			t.Errorf("expected file %s not found", file)
+		}
+	}
+
+	raw, err := os.ReadFile(filepath.Join(tmpDir, "graph.json"))
+	if err != nil {
+		t.Fatalf("failed to read graph.json: %v", err)
+	}
+	var data graphData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		t.Fatalf("graph.json is not valid JSON: %v", err)
+	}
+
+	if len(data.Nodes) != 4 {
+		t.Errorf("expected 4 nodes, got %d", len(data.Nodes))
+	}
+	if len(data.Edges) != 1 {
+		t.Fatalf("expected 1 edge, got %d", len(data.Edges))
+	}
+	if data.Edges[0].Source != "payment/api/auth" || data.Edges[0].Target != "payment/api/store" {
+		t.Errorf("unexpected edge %+v", data.Edges[0])
+	}
+	if len(data.Tags) != 1 || data.Tags[0] != "security" {
+		t.Errorf("expected tags [security], got %v", data.Tags)
+	}
+
+	urls := make(map[string]string)
+	for _, n := range data.Nodes {
+		urls[n.ID] = n.URL
+	}
+	if urls["payment"] != "systems/payment.html" {
+		t.Errorf("unexpected system URL %q", urls["payment"])
+	}
+	if urls["payment/api"] != "containers/payment_api.html" {
+		t.Errorf("unexpected container URL %q", urls["payment/api"])
+	}
+	if urls["payment/api/auth"] != "components/auth.html" {
+		t.Errorf("unexpected component URL %q", urls["payment/api/auth"])
+	}
+
+	page, err := os.ReadFile(filepath.Join(tmpDir, "graph.html"))
+	if err != nil {
+		t.Fatalf("failed to read graph.html: %v", err)
+	}
+	if !contains(string(page), "window.LOKO_GRAPH") {
+		t.Error("graph page missing inlined graph data")
+	}
+	if !contains(string(page), `<option value="security">`) {
+		t.Error("graph page missing tag filter option")
+	}
+}
+
+// TestBuildTimelinePage tests that a timeline page is generated when a timeline is set.
+func TestBuildTimelinePage(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+
+	timeline := entities.NewTimeline()
+	timeline.Add(entities.TimelineEvent{
+		Date:       time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		Kind:       entities.TimelineAdded,
+		EntityType: "system",
+		EntityID:   "payment",
+		Name:       "Payment Service",
+		Commit:     "0123456789abcdef",
+		Author:     "Ana",
+	})
+	timeline.Add(entities.TimelineEvent{
+		Date:       time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC),
+		Kind:       entities.TimelineRemoved,
+		EntityType: "container",
+		EntityID:   "payment/legacy",
+		Name:       "legacy",
+	})
+	builder.WithTimeline(timeline)
+
+	project := &entities.Project{Name: "Timeline Test", Systems: make(map[string]*entities.System)}
+	systems := []*entities.System{
+		{ID: "payment", Name: "Payment Service", Containers: make(map[string]*entities.Container)},
+	}
+
+	if err := builder.BuildSite(ctx, project, systems, tmpDir); err != nil {
+		t.Fatalf("BuildSite failed: %v", err)
+	}
+
+	page, err := os.ReadFile(filepath.Join(tmpDir, "timeline.html"))
+	if err != nil {
+		t.Fatalf("failed to read timeline.html: %v", err)
+	}
+	content := string(page)
+	for _, want := range []string{"2026-01-02", "2026-02-03", `<a href="systems/payment.html">Payment Service</a>`, "0123456", "<strong>legacy</strong>", "1 added, 0 deprecated, 1 removed"} {
+		if !contains(content, want) {
+			t.Errorf("timeline page missing %q", want)
+		}
+	}
+
+	index, err := os.ReadFile(filepath.Join(tmpDir, "index.html"))
+	if err != nil {
+		t.Fatalf("failed to read index.html: %v", err)
+	}
+	if !contains(string(index), "timeline.html") {
+		t.Error("index page missing timeline link")
+	}
+}
+
+// TestBuildSiteWithoutTimeline tests that no timeline page is generated by default.
+func TestBuildSiteWithoutTimeline(t *testing.T) {
+	tmpDir := t.TempDir()
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+
+	project := &entities.Project{Name: "No Timeline", Systems: make(map[string]*entities.System)}
+	if err := builder.BuildSite(context.Background(), project, nil, tmpDir); err != nil {
+		t.Fatalf("BuildSite failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "timeline.html")); !os.IsNotExist(err) {
+		t.Error("expected no timeline.html without a timeline")
+	}
+}
+
+// TestBuildCostsPage tests the cost rollup page and that it is only built
+// when a container declares a cost.
+func TestBuildCostsPage(t *testing.T) {
+	tmpDir := t.TempDir()
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+
+	project := &entities.Project{Name: "Costs Test", Systems: make(map[string]*entities.System)}
+	api := &entities.Container{ID: "api", Name: "API", Tags: []string{"cost:prod=1200", "cost:staging=300"}}
+	worker := &entities.Container{ID: "worker", Name: "Worker"}
+	systems := []*entities.System{
+		{ID: "payment", Name: "Payment Service", Containers: map[string]*entities.Container{"api": api, "worker": worker}},
+	}
+
+	if err := builder.BuildSite(context.Background(), project, systems, tmpDir); err != nil {
+		t.Fatalf("BuildSite failed: %v", err)
+	}
+	page, err := os.ReadFile(filepath.Join(tmpDir, "costs.html"))
+	if err != nil {
+		t.Fatalf("failed to read costs.html: %v", err)
+	}
+	content := string(page)
+	for _, want := range []string{"<th class=\"amount\">prod</th>", `<a href="containers/payment_api.html">API</a>`, "1,200.00", "1,500.00 USD", "<code>payment/worker</code>"} {
+		if !contains(content, want) {
+			t.Errorf("costs page missing %q", want)
+		}
+	}
+	index, _ := os.ReadFile(filepath.Join(tmpDir, "index.html"))
+	if !contains(string(index), "costs.html") {
+		t.Error("index page missing costs link")
+	}
+
+	// Without costs, there is no page
+	api.Tags = nil
+	tmpDir = t.TempDir()
+	if err := builder.BuildSite(context.Background(), project, systems, tmpDir); err != nil {
+		t.Fatalf("BuildSite failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "costs.html")); !os.IsNotExist(err) {
+		t.Error("expected no costs.html without costs")
+	}
+}
+
+// TestReviewBadges tests that entity pages show the review status of their
+// element, with the reviewer once approved.
+func TestReviewBadges(t *testing.T) {
+	component := &entities.Component{ID: "ledger", Name: "Ledger", Review: entities.Review{Status: entities.ReviewInReview}}
+	container := &entities.Container{ID: "api", Name: "API", Components: map[string]*entities.Component{"ledger": component}}
+	system := &entities.System{ID: "payments", Name: "Payments", Containers: map[string]*entities.Container{"api": container},
+		Review: entities.Review{Status: entities.ReviewApproved, ReviewedBy: "Ana", ReviewedAt: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)}}
+
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+	tmpDir := t.TempDir()
+	if err := builder.BuildSite(context.Background(), &entities.Project{Name: "Review"}, []*entities.System{system}, tmpDir); err != nil {
+		t.Fatalf("BuildSite failed: %v", err)
+	}
+	for page, want := range map[string]string{
+		"systems/payments.html":        `<p class="review-badge approved">approved by Ana on 2026-03-04</p>`,
+		"components/ledger.html":       `<p class="review-badge in-review">in-review</p>`,
+		"containers/payments_api.html": "",
+	} {
+		content, err := os.ReadFile(filepath.Join(tmpDir, page))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", page, err)
+		}
+		if want == "" && strings.Contains(string(content), "review-badge") {
+			t.Errorf("%s: expected no review badge without a status", page)
+		}
+		if want != "" && !strings.Contains(string(content), want) {
+			t.Errorf("%s: missing %q", page, want)
+		}
+	}
+}
+
+// TestEntityPages tests that pages are yielded depth-first and iteration stops early on request.
+func TestEntityPages(t *testing.T) {
+	component := &entities.Component{ID: "handler", Name: "Handler"}
+	container := &entities.Container{
+		ID:         "api",
+		Name:       "API",
+		Components: map[string]*entities.Component{"handler": component, "nil": nil},
+	}
+	systems := []*entities.System{
+		{ID: "svc", Name: "Service", Containers: map[string]*entities.Container{"api": container}},
+		nil,
+	}
+
+	var pages []entityPage
+	for page := range entityPages(systems) {
+		pages = append(pages, page)
+	}
+	if len(pages) != 3 {
+		t.Fatalf("expected 3 pages, got %d", len(pages))
+	}
+	if pages[0].Container != nil || pages[1].Container != container || pages[1].Component != nil || pages[2].Component != component {
+		t.Errorf("unexpected page order: %+v", pages)
+	}
+
+	count := 0
+	for range entityPages(systems) {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("expected iteration to stop after break, got %d pages", count)
+	}
+}
+
+// TestBuildSiteCancelled tests that page generation stops when the context is cancelled.
+func TestBuildSiteCancelled(t *testing.T) {
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	project := &entities.Project{Name: "Test Project"}
+	systems := []*entities.System{{ID: "svc", Name: "Service", Containers: make(map[string]*entities.Container)}}
+
+	if err := builder.BuildSite(ctx, project, systems, t.TempDir()); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// TestWritePageRenderError tests that no page is written when rendering fails.
+func TestWritePageRenderError(t *testing.T) {
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	filePath := filepath.Join(outputDir, "missing.html")
+	if err := builder.writePage(outputDir, "missing.html", "missing.html", nil); err == nil {
+		t.Fatal("expected error for unknown template")
+	}
+	if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+		t.Errorf("expected no page to be written, stat err: %v", err)
+	}
+}
+
+// TestBuildSiteManyPages tests that parallel page generation writes every entity page.
+func TestBuildSiteManyPages(t *testing.T) {
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+
+	var systems []*entities.System
+	for s := range 20 {
+		system := &entities.System{ID: fmt.Sprintf("sys-%d", s), Name: fmt.Sprintf("System %d", s), Containers: make(map[string]*entities.Container)}
+		for c := range 3 {
+			container := &entities.Container{ID: fmt.Sprintf("c-%d", c), Name: fmt.Sprintf("Container %d", c), Components: make(map[string]*entities.Component)}
+			for k := range 2 {
+				id := fmt.Sprintf("%s-%s-k%d", system.ID, container.ID, k)
+				container.Components[id] = &entities.Component{ID: id, Name: id}
+			}
+			system.Containers[container.ID] = container
+		}
+		systems = append(systems, system)
+	}
+
+	tmpDir := t.TempDir()
+	if err := builder.BuildSite(context.Background(), &entities.Project{Name: "Big"}, systems, tmpDir); err != nil {
+		t.Fatalf("BuildSite failed: %v", err)
+	}
+
+	for dir, want := range map[string]int{"systems": 20, "containers": 60, "components": 120} {
+		entries, err := os.ReadDir(filepath.Join(tmpDir, dir))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", dir, err)
+		}
+		if len(entries) != want {
+			t.Errorf("expected %d %s pages, got %d", want, dir, len(entries))
+		}
+	}
+}
+
+// TestSystemPageKPIs tests the aggregate badges rendered on system pages.
+func TestSystemPageKPIs(t *testing.T) {
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+
+	component := &entities.Component{ID: "ledger", Name: "Ledger", Relationships: map[string]string{"missing": "writes"}}
+	container := &entities.Container{ID: "api", Name: "API", Description: "Public API", Components: map[string]*entities.Component{"ledger": component}}
+	system := &entities.System{ID: "payments", Name: "Payments", Description: "Payments", Containers: map[string]*entities.Container{"api": container}}
+
+	tmpDir := t.TempDir()
+	if err := builder.BuildSite(context.Background(), &entities.Project{Name: "KPIs"}, []*entities.System{system}, tmpDir); err != nil {
+		t.Fatalf("BuildSite failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "systems", "payments.html"))
+	if err != nil {
+		t.Fatalf("failed to read system page: %v", err)
+	}
+	html := string(content)
+	for _, want := range []string{
+		`<span class="kpi-value">1</span> containers`,
+		`<span class="kpi-value">1</span> components`,
+		`<span class="kpi-value">66%</span> documented`,
+		`kpi-badge-warn"><span class="kpi-value">1</span> open warnings`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("system page missing %q", want)
+		}
+	}
+
+	// A standalone system page cannot validate against the rest of the
+	// architecture, so it omits the warnings badge.
+	standaloneDir := t.TempDir()
+	if err := builder.BuildSystemPage(context.Background(), system, system.ListContainers(), standaloneDir); err != nil {
+		t.Fatalf("BuildSystemPage failed: %v", err)
+	}
+	content, err = os.ReadFile(filepath.Join(standaloneDir, "systems", "payments.html"))
+	if err != nil {
+		t.Fatalf("failed to read system page: %v", err)
+	}
+	if !strings.Contains(string(content), "kpi-badges") || strings.Contains(string(content), "open warnings") {
+		t.Error("expected KPI badges without a warnings badge on a standalone system page")
+	}
+}
+
+// TestAssetFingerprinting tests that assets are written under content-hashed
+// names, pages reference them, and stale copies from earlier builds are removed.
+func TestAssetFingerprinting(t *testing.T) {
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+
+	cssPath := fingerprintedPaths["styles/style.css"]
+	if !strings.HasPrefix(cssPath, "styles/style.") || !strings.HasSuffix(cssPath, ".css") || cssPath == "styles/style.css" {
+		t.Fatalf("unexpected fingerprinted CSS path %q", cssPath)
+	}
+	if fingerprint("styles/style.css", "a") == fingerprint("styles/style.css", "b") {
+		t.Error("expected different content to produce different fingerprints")
+	}
+
+	tmpDir := t.TempDir()
+	stale := filepath.Join(tmpDir, "styles", "style.0123456789.css")
+	unrelated := filepath.Join(tmpDir, "styles", "style.custom.css")
+	for _, p := range []string{stale, unrelated} {
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("old"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	systems := []*entities.System{{ID: "svc", Name: "Service", Containers: make(map[string]*entities.Container)}}
+	if err := builder.BuildSite(context.Background(), &entities.Project{Name: "Test"}, systems, tmpDir); err != nil {
+		t.Fatalf("BuildSite failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, cssPath)); err != nil {
+		t.Errorf("expected fingerprinted CSS to exist: %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("expected stale fingerprinted CSS to be removed")
+	}
+	if _, err := os.Stat(unrelated); err != nil {
+		t.Error("expected non-fingerprinted file to be kept")
+	}
+
+	page, err := os.ReadFile(filepath.Join(tmpDir, "systems", "svc.html"))
+	if err != nil {
+		t.Fatalf("failed to read system page: %v", err)
+	}
+	for _, ref := range []string{"../" + cssPath, "../" + fingerprintedPaths["js/main.js"]} {
+		if !strings.Contains(string(page), ref) {
+			t.Errorf("expected system page to reference %s", ref)
+		}
+	}
+}
+
+// TestDiagramViewer tests that pages with diagrams load the diagram viewer.
+func TestDiagramViewer(t *testing.T) {
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+	container := &entities.Container{ID: "api", Name: "API", ParentID: "svc", Components: make(map[string]*entities.Component)}
+	container.Diagram = &entities.Diagram{ID: "api"}
+	container.DiagramPath = "diagrams/svc/api.svg"
+	system := &entities.System{ID: "svc", Name: "Service", Containers: map[string]*entities.Container{"api": container}}
+	system.Diagram = &entities.Diagram{ID: "svc"}
+	system.DiagramPath = "diagrams/svc.svg"
+
+	tmpDir := t.TempDir()
+	if err := builder.BuildSite(context.Background(), &entities.Project{Name: "Test"}, []*entities.System{system}, tmpDir); err != nil {
+		t.Fatalf("BuildSite failed: %v", err)
+	}
+
+	viewerPath := fingerprintedPaths["js/diagram-viewer.js"]
+	if _, err := os.Stat(filepath.Join(tmpDir, viewerPath)); err != nil {
+		t.Errorf("expected diagram viewer script to exist: %v", err)
+	}
+	for _, page := range []string{"systems/svc.html", "containers/svc_api.html"} {
+		content, err := os.ReadFile(filepath.Join(tmpDir, filepath.FromSlash(page)))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", page, err)
+		}
+		if !strings.Contains(string(content), `class="diagram-image"`) || !strings.Contains(string(content), viewerPath) {
+			t.Errorf("%s lacks a diagram or the diagram viewer script", page)
+		}
+	}
+}
+
+// TestPeople tests that the index page lists the project's people and system
+// pages list the people that use them.
+func TestPeople(t *testing.T) {
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+	project, _ := entities.NewProject("Test")
+	customer, _ := entities.NewPerson("Customer")
+	customer.Description = "Buys things"
+	customer.AddRelationship("shop", "Places orders with")
+	_ = project.AddPerson(customer)
+	shop := &entities.System{ID: "shop", Name: "Shop", Containers: make(map[string]*entities.Container)}
+	ledger := &entities.System{ID: "ledger", Name: "Ledger", Containers: make(map[string]*entities.Container)}
+
+	tmpDir := t.TempDir()
+	if err := builder.BuildSite(context.Background(), project, []*entities.System{shop, ledger}, tmpDir); err != nil {
+		t.Fatalf("BuildSite failed: %v", err)
+	}
+
+	read := func(page string) string {
+		content, err := os.ReadFile(filepath.Join(tmpDir, filepath.FromSlash(page)))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", page, err)
+		}
+		return string(content)
+	}
+	if index := read("index.html"); !strings.Contains(index, "<h2>People</h2>") || !strings.Contains(index, "Places orders with") {
+		t.Error("index page lacks the people section")
+	}
+	if page := read("systems/shop.html"); !strings.Contains(page, "<h2>Users</h2>") || !strings.Contains(page, "Buys things") {
+		t.Error("shop page lacks its users")
+	}
+	if page := read("systems/ledger.html"); strings.Contains(page, "<h2>Users</h2>") {
+		t.Error("ledger page lists users it does not have")
+	}
+}
+
+// memoryBuildCache is an in-memory usecases.BuildCache.
+type memoryBuildCache struct {
+	pages map[string]map[string]string
+}
+
+func (m *memoryBuildCache) Diagram(context.Context, string) (string, bool)   { return "", false }
+func (m *memoryBuildCache) PutDiagram(context.Context, string, string) error { return nil }
+func (m *memoryBuildCache) Pages(_ context.Context, dir string) map[string]string {
+	return m.pages[dir]
+}
+func (m *memoryBuildCache) Clear(context.Context) error { m.pages = nil; return nil }
+
+func (m *memoryBuildCache) PutPages(_ context.Context, dir string, pages map[string]string) error {
+	if m.pages == nil {
+		m.pages = make(map[string]map[string]string)
+	}
+	m.pages[dir] = pages
+	return nil
+}
+
+// TestPageCache tests that element pages are only regenerated when their
+// inputs change.
+func TestPageCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	cache := &memoryBuildCache{}
+	handler := &entities.Component{ID: "handler", Name: "Handler"}
+	api := &entities.Container{ID: "api", Name: "API", ParentID: "shop", Components: map[string]*entities.Component{"handler": handler}}
+	shop := &entities.System{ID: "shop", Name: "Shop", Containers: map[string]*entities.Container{"api": api}}
+	ledger := &entities.System{ID: "ledger", Name: "Ledger", Containers: make(map[string]*entities.Container)}
+	project := &entities.Project{Name: "Test"}
+
+	build := func(builder *Builder) {
+		t.Helper()
+		if err := builder.BuildSite(context.Background(), project, []*entities.System{shop, ledger}, tmpDir); err != nil {
+			t.Fatalf("BuildSite failed: %v", err)
+		}
+	}
+	newBuilder := func() *Builder {
+		t.Helper()
+		builder, err := NewBuilder()
+		if err != nil {
+			t.Fatalf("NewBuilder failed: %v", err)
+		}
+		return builder.WithBuildCache(cache)
+	}
+	pages := []string{"systems/shop.html", "systems/ledger.html", "containers/shop_api.html", "components/handler.html", "index.html"}
+	mark := func() {
+		t.Helper()
+		for _, page := range pages {
+			if err := os.WriteFile(filepath.Join(tmpDir, filepath.FromSlash(page)), []byte("stale"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	regenerated := func() []string {
+		t.Helper()
+		var got []string
+		for _, page := range pages {
+			content, err := os.ReadFile(filepath.Join(tmpDir, filepath.FromSlash(page)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != "stale" {
+				got = append(got, page)
+			}
+		}
+		return got
+	}
+
+	build(newBuilder())
+	if len(cache.pages[tmpDir]) != 4 {
+		t.Fatalf("cached pages = %v, want the 4 element pages", cache.pages[tmpDir])
+	}
+
+	// Nothing changed: only the index is regenerated
+	mark()
+	build(newBuilder())
+	if got := regenerated(); !slices.Equal(got, []string{"index.html"}) {
+		t.Errorf("unchanged build regenerated %v", got)
+	}
+
+	// A changed component regenerates the pages that show it
+	mark()
+	handler.Description = "Handles orders"
+	build(newBuilder())
+	if got := regenerated(); !slices.Equal(got, []string{"systems/shop.html", "containers/shop_api.html", "components/handler.html", "index.html"}) {
+		t.Errorf("component change regenerated %v", got)
+	}
+
+	// Changed site settings regenerate every page
+	mark()
+	build(newBuilder().WithCustomization("<meta name=\"x\">", ""))
+	if got := regenerated(); len(got) != len(pages) {
+		t.Errorf("customization change regenerated %v", got)
+	}
+
+	// A deleted page is written again
+	if err := os.Remove(filepath.Join(tmpDir, "systems", "ledger.html")); err != nil {
+		t.Fatal(err)
+	}
+	build(newBuilder().WithCustomization("<meta name=\"x\">", ""))
+	if _, err := os.Stat(filepath.Join(tmpDir, "systems", "ledger.html")); err != nil {
+		t.Errorf("deleted page was not regenerated: %v", err)
+	}
+}
+
+// TestWithCustomization tests that custom head and footer markup reaches every page
+// without affecting builders created without customization.
+func TestWithCustomization(t *testing.T) {
+	plain, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+	custom, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+	head := `<meta name="x-custom" content="1">`
+	footer := `<p class="legal">Internal use only</p>`
+	custom.WithCustomization(head, footer)
+
+	systems := []*entities.System{{ID: "svc", Name: "Service", Containers: make(map[string]*entities.Container)}}
+	project := &entities.Project{Name: "Test"}
+
+	customDir := t.TempDir()
+	if err := custom.BuildSite(context.Background(), project, systems, customDir); err != nil {
+		t.Fatalf("BuildSite failed: %v", err)
+	}
+	for _, page := range []string{"index.html", "systems/svc.html", "graph.html"} {
+		content, err := os.ReadFile(filepath.Join(customDir, page))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", page, err)
+		}
+		html := string(content)
+		if !strings.Contains(html, head+"\n</head>") {
+			t.Errorf("%s: expected custom head before </head>", page)
+		}
+		if !strings.Contains(html, footer) || strings.Index(html, footer) > strings.Index(html, "</footer>") {
+			t.Errorf("%s: expected custom footer inside <footer>", page)
+		}
+	}
+
+	plainDir := t.TempDir()
+	if err := plain.BuildSite(context.Background(), project, systems, plainDir); err != nil {
+		t.Fatalf("BuildSite failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(plainDir, "index.html"))
+	if err != nil {
+		t.Fatalf("failed to read index.html: %v", err)
+	}
+	if strings.Contains(string(content), "x-custom") {
+		t.Error("expected customization not to leak into other builders")
+	}
+}
+
+// TestWithLayouts tests that element pages use the layout of their first tag
+// with one, then the external layout, then the layout of their type.
+func TestWithLayouts(t *testing.T) {
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+	err = builder.WithLayouts(map[string]string{
+		"tag:datastore": `schema of {{.Container.Name}}`,
+		"tag:pii":       `pii {{.Container.Name}}`,
+		"external":      `vendor {{.System.Name}}`,
+		"component":     `component {{.Component.Name}} {{template "component.html" .}}`,
+	})
+	if err != nil {
+		t.Fatalf("WithLayouts failed: %v", err)
+	}
+
+	ledger := &entities.Component{ID: "ledger", Name: "Ledger"}
+	db := &entities.Container{ID: "db", Name: "DB", Tags: []string{"storage", "datastore", "pii"}, Components: map[string]*entities.Component{"ledger": ledger}}
+	api := &entities.Container{ID: "api", Name: "API"}
+	payments := &entities.System{ID: "payments", Name: "Payments", Tags: []string{"core"}, Containers: map[string]*entities.Container{"db": db, "api": api}}
+	stripe := &entities.System{ID: "stripe", Name: "Stripe", External: true}
+
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+	for _, system := range []*entities.System{payments, stripe} {
+		if err := builder.BuildSystemPage(ctx, system, system.ListContainers(), tmpDir); err != nil {
+			t.Fatalf("BuildSystemPage failed: %v", err)
+		}
+	}
+	for _, container := range []*entities.Container{db, api} {
+		if err := builder.BuildContainerPage(ctx, payments, container, container.ListComponents(), tmpDir); err != nil {
+			t.Fatalf("BuildContainerPage failed: %v", err)
+		}
+	}
+	if err := builder.BuildComponentPage(ctx, payments, db, ledger, tmpDir); err != nil {
+		t.Fatalf("BuildComponentPage failed: %v", err)
+	}
+
+	for page, want := range map[string]string{
+		"systems/stripe.html":         "vendor Stripe",
+		"containers/payments_db.html": "schema of DB",
+	} {
+		content, err := os.ReadFile(filepath.Join(tmpDir, filepath.FromSlash(page)))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", page, err)
+		}
+		if string(content) != want {
+			t.Errorf("%s = %q, want %q", page, content, want)
+		}
+	}
+	for _, page := range []string{"systems/payments.html", "containers/payments_api.html"} {
+		content, err := os.ReadFile(filepath.Join(tmpDir, filepath.FromSlash(page)))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", page, err)
+		}
+		if !strings.HasPrefix(strings.TrimSpace(string(content)), "<!DOCTYPE html>") {
+			t.Errorf("%s: expected the built-in page, got %q", page, content)
+		}
+	}
+	content, err := os.ReadFile(filepath.Join(tmpDir, "components", "ledger.html"))
+	if err != nil {
+		t.Fatalf("failed to read component page: %v", err)
+	}
+	if page := string(content); !strings.HasPrefix(page, "component Ledger") || !strings.Contains(page, "<!DOCTYPE html>") {
+		t.Errorf("expected the component layout wrapping the built-in page, got:\n%s", page)
+	}
+
+	if err := builder.WithLayouts(map[string]string{"database": "x"}); err == nil {
+		t.Error("expected an error for an unknown selector")
+	}
+	if err := builder.WithLayouts(map[string]string{"system": "{{.System"}); err == nil {
+		t.Error("expected an error for a template that does not parse")
+	}
+}
+
+// TestAnalyticsSnippet tests the supported analytics providers.
+func TestAnalyticsSnippet(t *testing.T) {
+	tests := []struct {
+		provider string
+		id       string
+		want     string
+		wantErr  bool
+	}{
+		{provider: "", want: ""},
+		{provider: "plausible", id: "docs.example.com", want: `data-domain="docs.example.com"`},
+		{provider: "Google", id: "G-ABC123", want: "gtag/js?id=G-ABC123"},
+		{provider: "plausible", wantErr: true},
+		{provider: "matomo", id: "1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			got, err := AnalyticsSnippet(tt.provider, tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AnalyticsSnippet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("AnalyticsSnippet() = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestIssueBadges tests that `issues:` references render as linked badges on
+// entity pages, using the URL template for ticket IDs.
+func TestIssueBadges(t *testing.T) {
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+	builder.WithIssueURLTemplate("https://acme.atlassian.net/browse/{id}")
+
+	component := &entities.Component{ID: "ledger", Name: "Ledger", Issues: []string{"https://github.com/acme/pay/issues/7"}}
+	container := &entities.Container{ID: "api", Name: "API", Issues: []string{"PAY-2"}, Components: map[string]*entities.Component{"ledger": component}}
+	system := &entities.System{ID: "payments", Name: "Payments", Issues: []string{"PAY-1"}, Containers: map[string]*entities.Container{"api": container}}
+
+	tmpDir := t.TempDir()
+	if err := builder.BuildSite(context.Background(), &entities.Project{Name: "Issues"}, []*entities.System{system}, tmpDir); err != nil {
+		t.Fatalf("BuildSite failed: %v", err)
+	}
+
+	for page, want := range map[string]string{
+		"systems/payments.html":        `<a class="issue-badge" href="https://acme.atlassian.net/browse/PAY-1" target="_blank" rel="noopener">PAY-1</a>`,
+		"containers/payments_api.html": `<a class="issue-badge" href="https://acme.atlassian.net/browse/PAY-2" target="_blank" rel="noopener">PAY-2</a>`,
+		"components/ledger.html":       `<a class="issue-badge" href="https://github.com/acme/pay/issues/7" target="_blank" rel="noopener">7</a>`,
+	} {
+		content, err := os.ReadFile(filepath.Join(tmpDir, page))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", page, err)
+		}
+		if !strings.Contains(string(content), want) {
+			t.Errorf("%s: missing %s", page, want)
+		}
+	}
+
+	// Without a URL template, ticket IDs are shown unlinked.
+	plain, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+	plainDir := t.TempDir()
+	if err := plain.BuildSystemPage(context.Background(), system, nil, plainDir); err != nil {
+		t.Fatalf("BuildSystemPage failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(plainDir, "systems", "payments.html"))
+	if err != nil {
+		t.Fatalf("failed to read system page: %v", err)
+	}
+	if !strings.Contains(string(content), `<span class="issue-badge">PAY-1</span>`) {
+		t.Error("expected unlinked badge without a URL template")
+	}
+}
+
+// TestTechnologyIcons tests that container pages show the icon for the
+// container's technology, honoring configured overrides.
+func TestTechnologyIcons(t *testing.T) {
+	container := &entities.Container{ID: "api", Name: "API", Technology: "Go + Gin"}
+	system := &entities.System{ID: "payments", Name: "Payments", Containers: map[string]*entities.Container{"api": container}}
+
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+	tmpDir := t.TempDir()
+	if err := builder.BuildSystemPage(context.Background(), system, system.ListContainers(), tmpDir); err != nil {
+		t.Fatalf("BuildSystemPage failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(tmpDir, "systems", "payments.html"))
+	if err != nil {
+		t.Fatalf("failed to read system page: %v", err)
+	}
+	if want := `<img class="tech-icon" src="` + entities.BuiltinIcon("go") + `"`; !strings.Contains(string(content), want) {
+		t.Errorf("system page missing default icon %s", want)
+	}
+
+	builder.WithTechnologyIcons(entities.NewIconRegistry(map[string]string{"go": ""}))
+	if err := builder.BuildSystemPage(context.Background(), system, system.ListContainers(), tmpDir); err != nil {
+		t.Fatalf("BuildSystemPage failed: %v", err)
+	}
+	content, err = os.ReadFile(filepath.Join(tmpDir, "systems", "payments.html"))
+	if err != nil {
+		t.Fatalf("failed to read system page: %v", err)
+	}
+	if strings.Contains(string(content), "tech-icon") {
+		t.Error("expected no icon after removing the go mapping")
+	}
+}
+
+// TestCustomFields tests that configured custom frontmatter fields are listed
+// on element pages.
+func TestCustomFields(t *testing.T) {
+	component := &entities.Component{ID: "ledger", Name: "Ledger", Metadata: map[string]any{
+		"cost_center": "CC-42",
+		"compliance":  []string{"pci", "sox"},
+		"internal":    "hidden",
+	}}
+	container := &entities.Container{ID: "api", Name: "API", Components: map[string]*entities.Component{"ledger": component}}
+	system := &entities.System{ID: "payments", Name: "Payments", Containers: map[string]*entities.Container{"api": container}}
+
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+	tmpDir := t.TempDir()
+	if err := builder.BuildComponentPage(context.Background(), system, container, component, tmpDir); err != nil {
+		t.Fatalf("BuildComponentPage failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(tmpDir, "components", "ledger.html"))
+	if err != nil {
+		t.Fatalf("failed to read component page: %v", err)
+	}
+	if strings.Contains(string(content), `<dl class="custom-fields">`) {
+		t.Error("expected no custom fields without configuration")
+	}
+
+	builder.WithCustomFields([]string{"compliance", "owner", "cost_center"})
+	if err := builder.BuildComponentPage(context.Background(), system, container, component, tmpDir); err != nil {
+		t.Fatalf("BuildComponentPage failed: %v", err)
+	}
+	content, err = os.ReadFile(filepath.Join(tmpDir, "components", "ledger.html"))
+	if err != nil {
+		t.Fatalf("failed to read component page: %v", err)
+	}
+	page := string(content)
+	compliance := strings.Index(page, "<dt>Compliance</dt>\n\t\t\t\t\t<dd>pci, sox</dd>")
+	costCenter := strings.Index(page, "<dt>Cost Center</dt>\n\t\t\t\t\t<dd>CC-42</dd>")
+	if compliance < 0 || costCenter < compliance {
+		t.Errorf("expected Compliance then Cost Center fields, got:\n%s", page)
+	}
+	if strings.Contains(page, "<dt>Owner</dt>") || strings.Contains(page, "hidden") {
+		t.Error("expected only configured fields the component has")
+	}
+}
+
+// TestLastUpdated tests that element pages show when and by whom the element
+// was last changed, when known.
+func TestLastUpdated(t *testing.T) {
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+	tmpDir := t.TempDir()
+
+	system := &entities.System{ID: "payments", Name: "Payments"}
+	if err := builder.BuildSystemPage(context.Background(), system, nil, tmpDir); err != nil {
+		t.Fatalf("BuildSystemPage failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(tmpDir, "systems", "payments.html"))
+	if err != nil {
+		t.Fatalf("failed to read system page: %v", err)
+	}
+	if strings.Contains(string(content), `class="last-updated"`) {
+		t.Error("expected no last updated line without a time")
+	}
+
+	system.UpdatedAt = time.Date(2024, 3, 2, 16, 45, 0, 0, time.UTC)
+	system.Author = "Jane Doe"
+	if err := builder.BuildSystemPage(context.Background(), system, nil, tmpDir); err != nil {
+		t.Fatalf("BuildSystemPage failed: %v", err)
+	}
+	content, err = os.ReadFile(filepath.Join(tmpDir, "systems", "payments.html"))
+	if err != nil {
+		t.Fatalf("failed to read system page: %v", err)
+	}
+	want := `Last updated <time datetime="2024-03-02T16:45:00Z">2024-03-02</time> by Jane Doe`
+	if !strings.Contains(string(content), want) {
+		t.Errorf("expected %q in system page", want)
+	}
+}
+
+// TestSystemPageRelationships tests that stored relationships are listed on
+// system pages with their technology.
+func TestSystemPageRelationships(t *testing.T) {
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+	builder.WithRelationships(map[string][]entities.Relationship{
+		"payments": {{Source: "payments/web", Target: "payments/api", Label: "Reads orders", Technology: "HTTPS/JSON"}},
+	})
+
+	system := &entities.System{ID: "payments", Name: "Payments"}
+	tmpDir := t.TempDir()
+	if err := builder.BuildSystemPage(context.Background(), system, nil, tmpDir); err != nil {
+		t.Fatalf("BuildSystemPage failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(tmpDir, "systems", "payments.html"))
+	if err != nil {
+		t.Fatalf("failed to read system page: %v", err)
+	}
+	for _, want := range []string{"<h2>Relationships</h2>", "<td>Reads orders</td>", "<td><code>HTTPS/JSON</code></td>"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("system page missing %s", want)
+		}
+	}
+}
+
+func TestDomainPages(t *testing.T) {
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+	systems := []*entities.System{
+		{ID: "web", Name: "Web"},
+		{ID: "payments.billing", Name: "Billing", Domain: "payments"},
+		{ID: "payments.cards.issuing", Name: "Issuing", Domain: "payments.cards"},
+	}
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "diagrams"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{usecases.LandscapeDiagramFile, "domains.svg", "domain_payments.svg"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, "diagrams", file), []byte("<svg></svg>"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := builder.BuildSite(context.Background(), &entities.Project{Name: "Shop"}, systems, tmpDir); err != nil {
+		t.Fatalf("BuildSite failed: %v", err)
+	}
+
+	pages := map[string][]string{
+		"index.html": {"<h2>Domains</h2>", `href="domains/payments.html"`, `src="diagrams/domains.svg"`, `src="diagrams/landscape.svg" alt="System Landscape"`},
+		filepath.Join("domains", "payments.html"): {
+			`src="../diagrams/domain_payments.svg"`, `href="payments.cards.html">Cards</a>`, `href="../systems/payments.billing.html">Billing</a>`,
+		},
+		filepath.Join("domains", "payments.cards.html"):   {`href="payments.html" class="breadcrumb-item">Payments</a>`, "Issuing"},
+		filepath.Join("systems", "payments.billing.html"): {`href="../domains/payments.html"`},
+		"search.json": {`"url": "domains/payments.cards.html"`},
+	}
+	for page, wants := range pages {
+		content, err := os.ReadFile(filepath.Join(tmpDir, page))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", page, err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %s", page, want)
+			}
+		}
+	}
+	if content, _ := os.ReadFile(filepath.Join(tmpDir, "domains", "payments.cards.html")); strings.Contains(string(content), "Domain Diagram") {
+		t.Error("payments.cards page shows a diagram that was not rendered")
+	}
+}
+
+func TestComponentPageSubComponents(t *testing.T) {
+	builder, err := NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+	system := &entities.System{ID: "shop", Name: "Shop"}
+	container, _ := entities.NewContainer("API")
+	handler, _ := entities.NewComponent("Handler")
+	validator, _ := entities.NewComponent("Validator")
+	validator.SetParent(handler.ID)
+	_ = container.AddComponent(handler)
+	_ = container.AddComponent(validator)
+
+	tmpDir := t.TempDir()
+	for _, comp := range []*entities.Component{handler, validator} {
+		if err := builder.BuildComponentPage(context.Background(), system, container, comp, tmpDir); err != nil {
+			t.Fatalf("BuildComponentPage failed: %v", err)
+		}
+	}
+
+	pages := map[string]string{
+		"handler.html":           `<h3><a href="handler.validator.html">Validator</a></h3>`,
+		"handler.validator.html": `<a href="handler.html" class="breadcrumb-item">Handler</a>`,
+	}
+	for page, want := range pages {
+		content, err := os.ReadFile(filepath.Join(tmpDir, "components", page))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", page, err)
+		}
+		if !strings.Contains(string(content), want) {
+			t.Errorf("%s missing %s", page, want)
+		}
+	}
+}
+
+// TestRouterLink tests how links are rewritten for base paths and pretty URLs.
+func TestRouterLink(t *testing.T) {
+	tests := []struct {
+		basePath string
+		pretty   bool
+		from     string
+		link     string
+		want     string
+	}{
+		{"", false, "systems/shop.html", "../index.html", "../index.html"},
+		{"", true, "systems/shop.html", "../index.html", "../../"},
+		{"", true, "index.html", "systems/shop.html#api", "systems/shop/#api"},
+		{"", true, "systems/shop.html", "#api", "#api"},
+		{"", true, "systems/shop.html", "../diagrams/shop.svg", "../../diagrams/shop.svg"},
+		{"", true, "systems/shop.html", "/styles/style.css", "../../styles/style.css"},
+		{"", true, "containers/shop_api.html", "shop_web.html", "../shop_web/"},
+		{"", true, "index.html", "https://example.com/x.html", "https://example.com/x.html"},
+		{"/architecture", false, "systems/shop.html", "../containers.html", "/architecture/containers.html"},
+		{"architecture/", true, "systems/shop.html", "../index.html", "/architecture/"},
+		{"/architecture/", true, "domains/pay.html", "../systems/shop.html?v=1#api", "/architecture/systems/shop/?v=1#api"},
+		{"/architecture/", true, "index.html", "/", "/architecture/"},
+		{"/architecture/", true, "index.html", "mailto:team@acme.com", "mailto:team@acme.com"},
+	}
+	for _, tt := range tests {
+		router, err := NewRouter(tt.basePath, tt.pretty)
+		if err != nil {
+			t.Fatalf("NewRouter(%q) failed: %v", tt.basePath, err)
+		}
+		if got := router.Link(tt.from, tt.link); got != tt.want {
+			t.Errorf("Link(%q, %q) with base %q, pretty %v = %q, want %q", tt.from, tt.link, tt.basePath, tt.pretty, got, tt.want)
+		}
+	}
+
+	if _, err := NewRouter("https://example.com/docs/", false); err == nil {
+		t.Error("expected an error for a base path with a scheme")
+	}
+}
+
+// TestBuildSiteRouting tests that sites built with a base path or pretty URLs
+// have no broken links.
+func TestBuildSiteRouting(t *testing.T) {
+	ctx := context.Background()
+	project := &entities.Project{Name: "Routed", Systems: make(map[string]*entities.System)}
+	system, _ := entities.NewSystem("Payments")
+	system.Domain = "finance"
+	container, _ := entities.NewContainer("API")
+	component, _ := entities.NewComponent("Handler")
+	_ = container.AddComponent(component)
+	_ = system.AddContainer(container)
+	systems := []*entities.System{system}
+
+	for _, basePath := range []string{"", "/architecture/"} {
+		router, err := NewRouter(basePath, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		builder, err := NewBuilder()
+		if err != nil {
+			t.Fatalf("NewBuilder failed: %v", err)
+		}
+		tmpDir := t.TempDir()
+		if err := builder.WithRouter(router).BuildSite(ctx, project, systems, tmpDir); err != nil {
+			t.Fatalf("BuildSite failed: %v", err)
+		}
+
+		for _, file := range []string{"index.html", "systems/payments/index.html", "containers/payments_api/index.html", "components/handler/index.html", "domains/finance/index.html", "graph/index.html"} {
+			if _, err := os.Stat(filepath.Join(tmpDir, file)); err != nil {
+				t.Errorf("base %q: expected %s: %v", basePath, file, err)
+			}
+		}
+
+		report, err := usecases.NewCheckSiteLinks().WithBasePath(basePath).Execute(ctx, tmpDir)
+		if err != nil {
+			t.Fatalf("CheckSiteLinks failed: %v", err)
+		}
+		if len(report.Broken) > 0 || report.Links == 0 {
+			t.Errorf("base %q: %d links, broken: %+v", basePath, report.Links, report.Broken)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package html
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// costRow is a row of the costs table: a container, or the total of a system.
+type costRow struct {
+	Name    string
+	URL     string
+	System  bool
+	Amounts []string // Per environment of the report, blank where none
+	Total   string
+}
+
+// buildCostsPage generates costs.html, the monthly costs of containers rolled
+// up per system and environment.
+func (b *Builder) buildCostsPage(_ context.Context, project *entities.Project, systems []*entities.System, costs *usecases.CostReport, outputDir string) error {
+	row := func(name, url string, byEnv map[string]float64, total float64) costRow {
+		r := costRow{Name: name, URL: url, Total: usecases.FormatCostAmount(total)}
+		for _, env := range costs.Environments {
+			amount := ""
+			if value, ok := byEnv[env]; ok {
+				amount = usecases.FormatCostAmount(value)
+			}
+			r.Amounts = append(r.Amounts, amount)
+		}
+		return r
+	}
+
+	var rows []costRow
+	for _, system := range costs.Systems {
+		systemRow := row(system.Name, graphNodeURL("system", []string{system.ID}), system.ByEnvironment, system.Total)
+		systemRow.System = true
+		rows = append(rows, systemRow)
+		for _, container := range system.Containers {
+			rows = append(rows, row(container.Name, graphNodeURL("container", strings.Split(container.ID, "/")), container.ByEnvironment, container.Total))
+		}
+	}
+
+	environments := make([]string, len(costs.Environments))
+	for i, env := range costs.Environments {
+		environments[i] = env
+		if env == "" {
+			environments[i] = "Other"
+		}
+	}
+
+	data := map[string]any{
+		"Project":      project,
+		"Systems":      systems,
+		"Currency":     costs.Currency,
+		"Environments": environments,
+		"Rows":         rows,
+		"Totals":       row("Total", "", costs.ByEnvironment, costs.Total),
+		"Unpriced":     costs.Unpriced,
+	}
+
+	if err := b.writePage(outputDir, "costs.html", "costs.html", data); err != nil {
+		return fmt.Errorf("failed to write costs page: %w", err)
+	}
+
+	return nil
+}
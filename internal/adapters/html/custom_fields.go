@@ -0,0 +1,66 @@
+package html
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// customField is a custom frontmatter field shown on an element page.
+type customField struct {
+	Label string
+	Value string
+}
+
+// customFieldFuncs returns the template functions that list the given custom
+// frontmatter fields of an element, in order. Fields an element lacks are
+// skipped, and no fields are listed when fields is empty.
+func customFieldFuncs(fields []string) template.FuncMap {
+	return template.FuncMap{
+		"customFields": func(metadata map[string]any) []customField {
+			var shown []customField
+			for _, key := range fields {
+				if value := formatFieldValue(metadata[key]); value != "" {
+					shown = append(shown, customField{Label: fieldLabel(key), Value: value})
+				}
+			}
+			return shown
+		},
+	}
+}
+
+// WithCustomFields sets the custom frontmatter fields, such as "owner" or
+// "compliance", listed on system, container and component pages.
+func (b *Builder) WithCustomFields(fields []string) *Builder {
+	b.templates = b.withFuncs(customFieldFuncs(fields))
+	b.vary("custom fields", fields...)
+	return b
+}
+
+// fieldLabel turns a frontmatter key such as "cost_center" into "Cost Center".
+func fieldLabel(key string) string {
+	words := strings.FieldsFunc(key, func(r rune) bool { return r == '_' || r == '-' })
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// formatFieldValue formats a metadata value for display; lists are joined
+// with commas.
+func formatFieldValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []string:
+		return strings.Join(v, ", ")
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
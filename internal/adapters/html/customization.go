@@ -0,0 +1,61 @@
+package html
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Analytics providers supported by AnalyticsSnippet.
+const (
+	AnalyticsPlausible = "plausible"
+	AnalyticsGoogle    = "google"
+)
+
+// AnalyticsSnippet returns the tracking script for an analytics provider.
+// For Plausible the id is the site domain; for Google Analytics it is the
+// measurement ID (e.g. "G-XXXXXXX"). An empty provider returns an empty snippet.
+func AnalyticsSnippet(provider, id string) (string, error) {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	if provider == "" {
+		return "", nil
+	}
+	if id == "" {
+		return "", fmt.Errorf("analytics provider %q requires an analytics_id", provider)
+	}
+	id = template.HTMLEscapeString(id)
+
+	switch provider {
+	case AnalyticsPlausible:
+		return fmt.Sprintf(`<script defer data-domain="%s" src="https://plausible.io/js/script.js"></script>`, id), nil
+	case AnalyticsGoogle:
+		return fmt.Sprintf(`<script async src="https://www.googletagmanager.com/gtag/js?id=%[1]s"></script>
+<script>
+window.dataLayer = window.dataLayer || [];
+function gtag(){dataLayer.push(arguments);}
+gtag('js', new Date());
+gtag('config', '%[1]s');
+</script>`, id), nil
+	default:
+		return "", fmt.Errorf("unsupported analytics provider %q (supported: %s, %s)", provider, AnalyticsPlausible, AnalyticsGoogle)
+	}
+}
+
+// customizationFuncs returns the template functions that emit user-supplied
+// markup. parseTemplates registers them with empty output; WithCustomization
+// rebinds them on the builder's own copy of the templates.
+func customizationFuncs(head, footer string) template.FuncMap {
+	return template.FuncMap{
+		"customHead":   func() string { return head },
+		"customFooter": func() string { return footer },
+	}
+}
+
+// WithCustomization injects raw HTML into every generated page: head is
+// appended to the <head> element (meta tags, stylesheets, analytics scripts)
+// and footer is appended to the page footer. The markup is not escaped.
+func (b *Builder) WithCustomization(head, footer string) *Builder {
+	b.templates = b.withFuncs(customizationFuncs(head, footer))
+	b.vary("customization", head, footer)
+	return b
+}
@@ -0,0 +1,57 @@
+package html
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// renderedDomainDiagram returns the site path of the rendered diagram of a
+// domain, or "" when BuildDocs did not render one.
+func renderedDomainDiagram(domainID, outputDir string) string {
+	return renderedDiagram(usecases.DomainDiagramFile(domainID), outputDir)
+}
+
+// renderedDiagram returns the site path of the rendered diagram fileName, or
+// "" when it was not rendered.
+func renderedDiagram(fileName, outputDir string) string {
+	if _, err := os.Stat(filepath.Join(outputDir, "diagrams", fileName)); err != nil {
+		return ""
+	}
+	return filepath.ToSlash(filepath.Join("diagrams", fileName))
+}
+
+// buildDomainPages generates domains/<id>.html for every domain, listing its
+// nested domains and systems with the domain diagram.
+func (b *Builder) buildDomainPages(_ context.Context, project *entities.Project, systems []*entities.System, domains []*entities.Domain, outputDir string) error {
+	var pages []*entities.Domain
+	for _, domain := range domains {
+		domain.Walk(func(d *entities.Domain) { pages = append(pages, d) })
+	}
+
+	for _, domain := range pages {
+		// Breadcrumb from the top-level domain down to the parent of this one
+		var parents []*entities.Domain
+		for id := entities.ParentDomain(domain.ID); id != ""; id = entities.ParentDomain(id) {
+			parents = append([]*entities.Domain{{ID: id, Name: entities.DomainName(id)}}, parents...)
+		}
+
+		data := map[string]any{
+			"Project":     project,
+			"Systems":     systems,
+			"Domain":      domain,
+			"Parents":     parents,
+			"DiagramPath": renderedDomainDiagram(domain.ID, outputDir),
+		}
+
+		if err := b.writePage(outputDir, "domains/"+domain.ID+".html", "domain.html", data); err != nil {
+			return fmt.Errorf("failed to write domain page for %s: %w", domain.ID, err)
+		}
+	}
+
+	return nil
+}
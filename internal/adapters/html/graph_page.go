@@ -0,0 +1,190 @@
+package html
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// graphData is the client-side representation of the architecture graph.
+// It is written to graph.json and embedded in graph.html.
+type graphData struct {
+	Nodes   []graphNodeData `json:"nodes"`
+	Edges   []graphEdgeData `json:"edges"`
+	Systems []string        `json:"systems"`
+	Tags    []string        `json:"tags"`
+}
+
+// graphNodeData describes a single node in the exported graph.
+type graphNodeData struct {
+	ID          string   `json:"id"`
+	Type        string   `json:"type"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Technology  string   `json:"technology,omitempty"`
+	System      string   `json:"system"`
+	Parent      string   `json:"parent,omitempty"`
+	Level       int      `json:"level"`
+	Tags        []string `json:"tags"`
+	URL         string   `json:"url"`
+}
+
+// graphEdgeData describes a single relationship in the exported graph.
+type graphEdgeData struct {
+	Source      string `json:"source"`
+	Target      string `json:"target"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+}
+
+// buildGraphPage writes graph.json and the interactive graph explorer page (graph.html).
+func (b *Builder) buildGraphPage(ctx context.Context, project *entities.Project, systems []*entities.System, outputDir string) error {
+	data, err := buildGraphData(ctx, project, systems)
+	if err != nil {
+		return err
+	}
+	for i, node := range data.Nodes {
+		if node.URL != "" {
+			data.Nodes[i].URL = b.router.URL("graph.html", node.URL)
+		}
+	}
+
+	graphJSON, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal graph data: %w", err)
+	}
+
+	jsonPath := filepath.Join(outputDir, "graph.json")
+	if err := os.WriteFile(jsonPath, graphJSON, 0644); err != nil {
+		return fmt.Errorf("failed to write graph data: %w", err)
+	}
+
+	// json.Marshal escapes <, > and & so the payload is safe to inline in a <script> tag.
+	tmplData := map[string]any{
+		"Project":   project,
+		"Systems":   systems,
+		"Graph":     data,
+		"GraphJSON": string(graphJSON),
+	}
+
+	if err := b.writePage(outputDir, "graph.html", "graph.html", tmplData); err != nil {
+		return fmt.Errorf("failed to write graph page: %w", err)
+	}
+
+	return nil
+}
+
+// buildGraphData converts the project into nodes and edges for the graph explorer.
+// Nodes and edges are sorted so the output is deterministic across builds.
+func buildGraphData(ctx context.Context, project *entities.Project, systems []*entities.System) (*graphData, error) {
+	graph, err := usecases.NewBuildArchitectureGraph().Execute(ctx, project, systems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build architecture graph: %w", err)
+	}
+
+	data := &graphData{
+		Nodes:   []graphNodeData{},
+		Edges:   []graphEdgeData{},
+		Systems: []string{},
+		Tags:    []string{},
+	}
+	tagSet := make(map[string]bool)
+
+	for id, node := range graph.Nodes {
+		parts, _ := entities.ParseQualifiedID(id)
+		systemID := ""
+		if len(parts) > 0 {
+			systemID = parts[0]
+		}
+
+		nodeData := graphNodeData{
+			ID:          id,
+			Type:        node.Type,
+			Name:        node.Name,
+			Description: node.Description,
+			Technology:  node.Metadata["technology"],
+			System:      systemID,
+			Parent:      node.ParentID,
+			Level:       node.Level,
+			Tags:        graphNodeTags(node),
+			URL:         graphNodeURL(node.Type, parts),
+		}
+		for _, tag := range nodeData.Tags {
+			tagSet[tag] = true
+		}
+		if node.Type == "system" {
+			data.Systems = append(data.Systems, systemID)
+		}
+		data.Nodes = append(data.Nodes, nodeData)
+	}
+
+	for _, edges := range graph.Edges {
+		for _, edge := range edges {
+			data.Edges = append(data.Edges, graphEdgeData{
+				Source:      edge.Source,
+				Target:      edge.Target,
+				Type:        edge.Type,
+				Description: edge.Description,
+			})
+		}
+	}
+
+	for tag := range tagSet {
+		data.Tags = append(data.Tags, tag)
+	}
+
+	slices.SortFunc(data.Nodes, func(a, b graphNodeData) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	slices.SortFunc(data.Edges, func(a, b graphEdgeData) int {
+		if c := strings.Compare(a.Source, b.Source); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Target, b.Target)
+	})
+	slices.Sort(data.Systems)
+	slices.Sort(data.Tags)
+
+	return data, nil
+}
+
+// graphNodeTags returns the tags of the entity backing a graph node.
+func graphNodeTags(node *entities.GraphNode) []string {
+	var tags []string
+	switch e := node.Data.(type) {
+	case *entities.Person:
+		tags = e.Tags
+	case *entities.System:
+		tags = e.Tags
+	case *entities.Container:
+		tags = e.Tags
+	case *entities.Component:
+		tags = e.Tags
+	}
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// graphNodeURL returns the site-relative page URL for a node, matching the
+// paths used by BuildSystemPage, BuildContainerPage and BuildComponentPage.
+func graphNodeURL(nodeType string, parts []string) string {
+	switch {
+	case nodeType == "system" && len(parts) >= 1:
+		return fmt.Sprintf("systems/%s.html", parts[0])
+	case nodeType == "container" && len(parts) >= 2:
+		return fmt.Sprintf("containers/%s_%s.html", parts[0], parts[1])
+	case nodeType == "component" && len(parts) >= 3:
+		return fmt.Sprintf("components/%s.html", parts[2])
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,24 @@
+package html
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// iconFuncs returns the template functions that show an icon next to a
+// container's technology. A nil registry shows no icons.
+func iconFuncs(icons *entities.IconRegistry) template.FuncMap {
+	return template.FuncMap{
+		"techIcon": icons.Icon,
+	}
+}
+
+// WithTechnologyIcons sets the registry that maps container technologies to
+// icons, e.g. one with the [icons] overrides from loko.toml.
+func (b *Builder) WithTechnologyIcons(icons *entities.IconRegistry) *Builder {
+	b.templates = b.withFuncs(iconFuncs(icons))
+	b.vary("icons", fmt.Sprint(icons)) // fmt prints the registry's map sorted by key
+	return b
+}
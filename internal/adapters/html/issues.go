@@ -0,0 +1,25 @@
+package html
+
+import (
+	"text/template"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// issueFuncs returns the template functions that render `issues:` frontmatter
+// as badges. Tickets given by ID link to urlTemplate; without a template only
+// URL references are linked.
+func issueFuncs(urlTemplate string) template.FuncMap {
+	return template.FuncMap{
+		"issueURL": func(ref string) string { return usecases.IssueURL(ref, urlTemplate) },
+		"issueKey": usecases.IssueKey,
+	}
+}
+
+// WithIssueURLTemplate sets the link used for issue badges referenced by ID,
+// e.g. "https://acme.atlassian.net/browse/{id}".
+func (b *Builder) WithIssueURLTemplate(urlTemplate string) *Builder {
+	b.templates = b.withFuncs(issueFuncs(urlTemplate))
+	b.vary("issues", urlTemplate)
+	return b
+}
@@ -0,0 +1,86 @@
+package html
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Selectors of the element pages a layout applies to, besides "tag:<name>".
+const (
+	LayoutSystem    = "system"    // System pages
+	LayoutContainer = "container" // Container pages
+	LayoutComponent = "component" // Component pages
+	LayoutExternal  = "external"  // Pages of external systems
+)
+
+// LayoutTagPrefix starts the selector of the pages of elements with a tag,
+// e.g. "tag:datastore".
+const LayoutTagPrefix = "tag:"
+
+// layoutTemplatePrefix names the templates parsed from layouts.
+const layoutTemplatePrefix = "layout:"
+
+// WithLayouts replaces the built-in template of the element pages that
+// layouts selects, e.g. "tag:datastore" or "external", by the text/template
+// source it maps the selector to. A layout receives the data of the page it
+// replaces and may use every template and function of the built-in pages,
+// such as {{template "component.html" .}} to fall back to the default page.
+//
+// An element with tags uses the layout of its first tag that has one, then
+// the "external" layout for external systems, then the layout of its type.
+// An unknown selector or a template that does not parse is an error.
+func (b *Builder) WithLayouts(layouts map[string]string) error {
+	if len(layouts) == 0 {
+		b.layouts = nil
+		b.vary("layouts")
+		return nil
+	}
+	tmpl := b.withFuncs(nil)
+	for _, selector := range slices.Sorted(maps.Keys(layouts)) {
+		if !validLayoutSelector(selector) {
+			return fmt.Errorf("unknown layout selector %q (expected %s, %s, %s, %s or %s<tag>)",
+				selector, LayoutSystem, LayoutContainer, LayoutComponent, LayoutExternal, LayoutTagPrefix)
+		}
+		if _, err := tmpl.New(layoutTemplatePrefix + selector).Parse(layouts[selector]); err != nil {
+			return fmt.Errorf("invalid layout %q: %w", selector, err)
+		}
+	}
+	b.templates = tmpl
+	b.layouts = make(map[string]bool, len(layouts))
+	for _, selector := range slices.Sorted(maps.Keys(layouts)) {
+		b.layouts[selector] = true
+		b.vary("layout", selector, layouts[selector])
+	}
+	return nil
+}
+
+// validLayoutSelector reports whether selector names element pages.
+func validLayoutSelector(selector string) bool {
+	switch selector {
+	case LayoutSystem, LayoutContainer, LayoutComponent, LayoutExternal:
+		return true
+	}
+	tag, ok := strings.CutPrefix(selector, LayoutTagPrefix)
+	return ok && tag != ""
+}
+
+// pageTemplate returns the template of the page of an element of kind, one of
+// LayoutSystem, LayoutContainer and LayoutComponent, with tags: the layout
+// selected for it, or defaultName.
+func (b *Builder) pageTemplate(kind string, tags []string, external bool, defaultName string) string {
+	selectors := make([]string, 0, len(tags)+2)
+	for _, tag := range tags {
+		selectors = append(selectors, LayoutTagPrefix+tag)
+	}
+	if external {
+		selectors = append(selectors, LayoutExternal)
+	}
+	for _, selector := range append(selectors, kind) {
+		if b.layouts[selector] {
+			return layoutTemplatePrefix + selector
+		}
+	}
+	return defaultName
+}
@@ -0,0 +1,151 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LiveReloadPath is the URL path of the live reload event stream.
+const LiveReloadPath = "/_loko/livereload"
+
+// liveReloadScript reloads the page when the event stream announces a rebuild.
+// EventSource reconnects on its own while the server restarts.
+const liveReloadScript = `<script>new EventSource("` + LiveReloadPath + `").onmessage = () => location.reload();</script>`
+
+// LiveReload reloads the pages open in browsers when the site is rebuilt. Its
+// handler streams a server-sent event to every open page on Reload, and Inject
+// adds the script listening for it to the HTML pages of a site.
+type LiveReload struct {
+	mu      sync.Mutex
+	clients map[chan struct{}]struct{}
+	closed  bool
+}
+
+// NewLiveReload creates a LiveReload with no open pages.
+func NewLiveReload() *LiveReload {
+	return &LiveReload{clients: make(map[chan struct{}]struct{})}
+}
+
+// Reload tells every open page to reload.
+func (lr *LiveReload) Reload() {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	for client := range lr.clients {
+		select {
+		case client <- struct{}{}:
+		default: // A reload is already pending
+		}
+	}
+}
+
+// Close ends the open event streams, so a server shutting down does not wait
+// for them.
+func (lr *LiveReload) Close() {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	lr.closed = true
+	for client := range lr.clients {
+		close(client)
+		delete(lr.clients, client)
+	}
+}
+
+// ServeHTTP streams a "reload" event each time Reload is called, until the
+// page is closed or Close is called.
+func (lr *LiveReload) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	client := make(chan struct{}, 1)
+	lr.mu.Lock()
+	if lr.closed {
+		lr.mu.Unlock()
+		http.Error(w, "server shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	lr.clients[client] = struct{}{}
+	lr.mu.Unlock()
+	defer func() {
+		lr.mu.Lock()
+		delete(lr.clients, client)
+		lr.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case _, ok := <-client:
+			if !ok {
+				return
+			}
+			if _, err := fmt.Fprint(w, "data: reload\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// Inject wraps a handler serving a site, adding the live reload script to its
+// HTML pages. Conditional and range headers are dropped so pages are always
+// served whole and current after a rebuild.
+func (lr *LiveReload) Inject(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, header := range []string{"If-Modified-Since", "If-None-Match", "Range"} {
+			r.Header.Del(header)
+		}
+		rec := &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		body := rec.body.Bytes()
+		if rec.status == http.StatusOK && strings.HasPrefix(rec.header.Get("Content-Type"), "text/html") {
+			body = injectScript(body)
+			rec.header.Set("Content-Length", strconv.Itoa(len(body)))
+		}
+		for key, values := range rec.header {
+			w.Header()[key] = values
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(rec.status)
+		_, _ = w.Write(body)
+	})
+}
+
+// injectScript inserts the live reload script before the closing body tag of
+// page, or appends it when the page has none.
+func injectScript(page []byte) []byte {
+	i := bytes.LastIndex(bytes.ToLower(page), []byte("</body>"))
+	if i < 0 {
+		return append(page, liveReloadScript...)
+	}
+	injected := make([]byte, 0, len(page)+len(liveReloadScript))
+	injected = append(injected, page[:i]...)
+	injected = append(injected, liveReloadScript...)
+	return append(injected, page[i:]...)
+}
+
+// bufferedResponse is an http.ResponseWriter holding the response in memory.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) WriteHeader(status int) { b.status = status }
+
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
@@ -0,0 +1,70 @@
+package html
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestLiveReloadInject(t *testing.T) {
+	site := fstest.MapFS{
+		"index.html": {Data: []byte("<html><body><h1>Home</h1></body></html>")},
+		"style.css":  {Data: []byte("body {}")},
+	}
+	srv := httptest.NewServer(NewLiveReload().Inject(http.FileServerFS(site)))
+	defer srv.Close()
+
+	get := func(path string) (*http.Response, string) {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		req.Header.Set("If-Modified-Since", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	resp, body := get("/")
+	if resp.StatusCode != http.StatusOK || body != "<html><body><h1>Home</h1>"+liveReloadScript+"</body></html>" {
+		t.Errorf("GET / = %d %q", resp.StatusCode, body)
+	}
+	if resp.ContentLength != int64(len(body)) {
+		t.Errorf("Content-Length = %d, want %d", resp.ContentLength, len(body))
+	}
+	if _, body := get("/style.css"); body != "body {}" {
+		t.Errorf("GET /style.css = %q", body)
+	}
+}
+
+func TestLiveReloadStream(t *testing.T) {
+	lr := NewLiveReload()
+	srv := httptest.NewServer(lr)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	lr.Reload()
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil || strings.TrimSpace(line) != "data: reload" {
+		t.Errorf("event = %q, %v", line, err)
+	}
+
+	lr.Close()
+	if _, err := io.ReadAll(resp.Body); err != nil {
+		t.Errorf("stream not ended cleanly: %v", err)
+	}
+}
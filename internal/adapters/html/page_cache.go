@@ -0,0 +1,275 @@
+package html
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// WithBuildCache skips regenerating the system, container and component
+// pages whose inputs are unchanged since the build that last wrote them to
+// the output directory: the element and its Markdown file, the data shown
+// with it and the templates and settings of the site. Index, overview and
+// graph pages, which depend on every element, are always regenerated.
+func (b *Builder) WithBuildCache(cache usecases.BuildCache) *Builder {
+	b.cache = cache
+	return b
+}
+
+// templatesVariant hashes the built-in templates and asset names, the initial
+// variant of every builder, so pages cached by a build with other templates
+// are regenerated.
+var templatesVariant = sync.OnceValue(func() string {
+	h := sha256.New()
+	for _, name := range slices.Sorted(maps.Keys(templateMap)) {
+		fmt.Fprintf(h, "%s\x00%s\x00", name, templateMap[name])
+	}
+	for _, logical := range slices.Sorted(maps.Keys(fingerprintedPaths)) {
+		fmt.Fprintf(h, "%s\x00", fingerprintedPaths[logical])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+})
+
+// vary records a setting that changes how pages render, such as custom
+// markup, so that pages cached under another setting are regenerated.
+func (b *Builder) vary(setting string, values ...string) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", b.variant, setting)
+	for _, value := range values {
+		fmt.Fprintf(h, "%d:%s", len(value), value)
+	}
+	b.variant = hex.EncodeToString(h.Sum(nil))
+}
+
+// pageCache holds the page keys of the last build to an output directory and
+// those of the pages of the current build, and the keys of the elements the
+// pages show.
+type pageCache struct {
+	outputDir string
+	previous  map[string]string // Page path to key, from the last build
+	elements  map[any]string    // *System, *Container or *Component to key
+
+	mu      sync.Mutex
+	current map[string]string // Page path to key, of this build
+}
+
+// startPageCache loads the page keys of the last build to outputDir and keys
+// the elements of systems, or disables the page cache without a build cache.
+func (b *Builder) startPageCache(ctx context.Context, systems []*entities.System, outputDir string) {
+	b.pages = nil
+	if b.cache == nil {
+		return
+	}
+	b.pages = &pageCache{
+		outputDir: outputDir,
+		previous:  b.cache.Pages(ctx, outputDir),
+		elements:  elementKeys(systems),
+		current:   make(map[string]string),
+	}
+}
+
+// finishPageCache records the page keys of this build and disables the page
+// cache until the next BuildSite. A failure to record them only costs
+// regenerating the pages next time.
+func (b *Builder) finishPageCache(ctx context.Context) {
+	if b.pages != nil {
+		_ = b.cache.PutPages(ctx, b.pages.outputDir, b.pages.current)
+	}
+	b.pages = nil
+}
+
+// elementKeys hashes every system, container and component of systems with
+// its descendants, so that a page showing an element changes key when any
+// element below it changes. Elements that cannot be encoded have no key.
+func elementKeys(systems []*entities.System) map[any]string {
+	keys := make(map[any]string)
+	encode := func(h hash.Hash, value any) bool {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return false
+		}
+		_, _ = h.Write(data)
+		return true
+	}
+	for _, system := range systems {
+		if system == nil {
+			continue
+		}
+		shallowSystem := *system
+		shallowSystem.Containers = nil
+		systemHash := sha256.New()
+		systemOK := encode(systemHash, shallowSystem)
+		for _, container := range system.ListContainers() {
+			if container == nil {
+				continue
+			}
+			shallowContainer := *container
+			shallowContainer.Components = nil
+			containerHash := sha256.New()
+			containerOK := encode(containerHash, shallowContainer)
+			for _, component := range container.ListComponents() {
+				if component == nil {
+					continue
+				}
+				componentHash := sha256.New()
+				if !encode(componentHash, component) {
+					containerOK = false
+					continue
+				}
+				key := hex.EncodeToString(componentHash.Sum(nil))
+				keys[component] = key
+				fmt.Fprintf(containerHash, "\x00%s", key)
+			}
+			if !containerOK {
+				systemOK = false
+				continue
+			}
+			key := hex.EncodeToString(containerHash.Sum(nil))
+			keys[container] = key
+			fmt.Fprintf(systemHash, "\x00%s", key)
+		}
+		if systemOK {
+			keys[system] = hex.EncodeToString(systemHash.Sum(nil))
+		}
+	}
+	return keys
+}
+
+// pageKey returns the cache key of a page rendered with templateName from the
+// Markdown source and data, which must not yet hold the rendered Markdown.
+// ok is false when the page cannot be cached, e.g. without a build cache.
+func (b *Builder) pageKey(page, templateName string, source []byte, data map[string]any) (key string, ok bool) {
+	if b.pages == nil {
+		return "", false
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00", b.variant, templateName, b.router.BasePath(), b.router.File(page))
+	fmt.Fprintf(h, "%d:%s", len(source), source)
+	for _, name := range slices.Sorted(maps.Keys(data)) {
+		value, ok := b.pages.valueKey(data[name])
+		if !ok {
+			return "", false
+		}
+		fmt.Fprintf(h, "\x00%s=%s", name, value)
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// valueKey returns the key of a value of page data: the element key of
+// elements and lists of them, and the JSON encoding of anything else.
+func (c *pageCache) valueKey(value any) (string, bool) {
+	switch v := value.(type) {
+	case *entities.System:
+		if v == nil {
+			return "null", true
+		}
+		return c.elementKey(v)
+	case *entities.Container:
+		if v == nil {
+			return "null", true
+		}
+		return c.elementKey(v)
+	case *entities.Component:
+		if v == nil {
+			return "null", true
+		}
+		return c.elementKey(v)
+	case []*entities.Container:
+		return c.listKey(len(v), func(i int) any { return v[i] })
+	case []*entities.Component:
+		return c.listKey(len(v), func(i int) any { return v[i] })
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// elementKey returns the key of a system, container or component.
+func (c *pageCache) elementKey(element any) (string, bool) {
+	key, ok := c.elements[element]
+	return key, ok
+}
+
+// listKey joins the keys of the n elements of a list.
+func (c *pageCache) listKey(n int, element func(i int) any) (string, bool) {
+	h := sha256.New()
+	for i := range n {
+		key, ok := c.valueKey(element(i))
+		if !ok {
+			return "", false
+		}
+		fmt.Fprintf(h, "%s\x00", key)
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// unchanged reports whether the last build wrote page from key and the file
+// is still there, and carries the page over to this build's keys if so.
+func (b *Builder) unchanged(page, key string) bool {
+	c := b.pages
+	if c.previous[page] != key {
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(c.outputDir, filepath.FromSlash(b.router.File(page)))); err != nil {
+		return false
+	}
+	c.record(page, key)
+	return true
+}
+
+// record sets the key of a page written by this build.
+func (c *pageCache) record(page, key string) {
+	c.mu.Lock()
+	c.current[page] = key
+	c.mu.Unlock()
+}
+
+// writeElementPage writes the page of a system, container or component,
+// rendering its Markdown file at markdownPath, if any, into data as
+// MarkdownContent. The page is left as it is when the page cache holds it
+// with the same inputs.
+func (b *Builder) writeElementPage(outputDir, page, templateName, markdownPath string, data map[string]any) error {
+	var source []byte
+	hasSource := false
+	if markdownPath != "" {
+		if content, err := b.readSource(markdownPath); err == nil {
+			source, hasSource = content, true
+		}
+	}
+
+	key, cacheable := b.pageKey(page, templateName, source, data)
+	if cacheable && b.unchanged(page, key) {
+		return nil
+	}
+
+	markdownContent := ""
+	if hasSource {
+		// Render markdown to HTML fragment (content only, no HTML wrapper)
+		fullHTML := b.markdownRenderer.RenderMarkdownToHTML(string(source))
+		// Extract just the content part (between <div class="container"> and </div>)
+		markdownContent = b.extractMarkdownContent(fullHTML)
+	}
+	data["MarkdownContent"] = markdownContent
+	data["HasMarkdown"] = markdownContent != ""
+
+	if err := b.writePage(outputDir, page, templateName, data); err != nil {
+		return err
+	}
+	if cacheable {
+		b.pages.record(page, key)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package html
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Router maps the pages of the site to output files and URLs. Templates link
+// pages by their logical path, such as "systems/payments.html", relative to
+// the page they are on; the router rewrites those links when the site is
+// served under a base path or with pretty URLs.
+//
+// With pretty URLs, systems/payments.html is written as
+// systems/payments/index.html and linked as systems/payments/. With a base
+// path such as /architecture/, internal links become absolute URLs under it,
+// so the site works behind a reverse proxy or as a GitHub Pages project site.
+// The zero configuration keeps the flat layout and relative links, which also
+// work when the site is opened from disk.
+type Router struct {
+	basePath string // "" or a path with leading and trailing slashes
+	pretty   bool
+}
+
+// NewRouter creates a Router for a site served under basePath, e.g.
+// "/architecture/" ("" or "/" for the root), with pretty URLs if pretty is set.
+func NewRouter(basePath string, pretty bool) (*Router, error) {
+	basePath = strings.TrimSpace(basePath)
+	if strings.Contains(basePath, "://") || strings.ContainsAny(basePath, "?#\\ ") {
+		return nil, fmt.Errorf("invalid base path %q: expected a URL path such as /architecture/", basePath)
+	}
+	basePath = strings.Trim(path.Clean("/"+basePath), "/")
+	if basePath != "" && basePath != "." {
+		basePath = "/" + basePath + "/"
+	} else {
+		basePath = ""
+	}
+	return &Router{basePath: basePath, pretty: pretty}, nil
+}
+
+// BasePath returns the path the site is served under, with leading and
+// trailing slashes, or "" for the root.
+func (r *Router) BasePath() string {
+	if r == nil {
+		return ""
+	}
+	return r.basePath
+}
+
+// active reports whether the router changes files or links at all.
+func (r *Router) active() bool {
+	return r != nil && (r.basePath != "" || r.pretty)
+}
+
+// File returns the slash-separated output file of a page, relative to the
+// output directory.
+func (r *Router) File(page string) string {
+	if r == nil || !r.pretty || path.Ext(page) != ".html" || path.Base(page) == "index.html" {
+		return page
+	}
+	return strings.TrimSuffix(page, ".html") + "/index.html"
+}
+
+// URL returns the link from the page at from to target, a site path relative
+// to the output directory that may carry a query and fragment, as in
+// "systems/payments.html#api".
+func (r *Router) URL(from, target string) string {
+	if !r.active() {
+		return target
+	}
+	return r.Link(from, "/"+target)
+}
+
+// Link rewrites a link found on the page at from. Relative links are resolved
+// against the page's logical path and root-relative ones against the output
+// directory. External links, fragments and links leaving the site are
+// returned unchanged.
+func (r *Router) Link(from, link string) string {
+	if !r.active() || isExternalLink(link) {
+		return link
+	}
+	u, err := url.Parse(link)
+	if err != nil || u.Path == "" || u.Scheme != "" || u.Host != "" {
+		return link
+	}
+
+	target := path.Join(path.Dir(from), u.Path)
+	if strings.HasPrefix(u.Path, "/") {
+		target = path.Clean(strings.TrimPrefix(u.Path, "/"))
+	}
+	if target == ".." || strings.HasPrefix(target, "../") {
+		return link
+	}
+	if strings.HasSuffix(u.Path, "/") || target == "." {
+		target = path.Join(target, "index.html")
+	}
+
+	// The path the browser requests: a directory for pretty page URLs
+	routed := r.File(target)
+	if r.pretty && path.Base(routed) == "index.html" {
+		routed = strings.TrimSuffix(routed, "index.html")
+	}
+
+	var out string
+	if r.basePath != "" {
+		out = r.basePath + routed
+	} else {
+		out = relativeURL(path.Dir(r.File(from)), routed)
+	}
+	if u.RawQuery != "" {
+		out += "?" + u.RawQuery
+	}
+	if u.Fragment != "" {
+		out += "#" + u.EscapedFragment()
+	}
+	return out
+}
+
+// relativeURL returns the relative URL from directory dir to target, both
+// relative to the output directory. A target ending in "/" is a directory.
+func relativeURL(dir, target string) string {
+	var from []string
+	if dir != "." && dir != "" {
+		from = strings.Split(dir, "/")
+	}
+	to := strings.Split(target, "/") // The last element is the file, or "" for a directory
+	for len(from) > 0 && len(to) > 1 && from[0] == to[0] {
+		from, to = from[1:], to[1:]
+	}
+	rel := strings.Repeat("../", len(from)) + strings.Join(to, "/")
+	if rel == "" {
+		return "./"
+	}
+	return rel
+}
+
+// isExternalLink reports whether a link leaves the site or is not a page
+// link at all: URLs with a scheme, protocol-relative URLs and fragments.
+func isExternalLink(link string) bool {
+	if link == "" || strings.HasPrefix(link, "#") || strings.HasPrefix(link, "//") {
+		return true
+	}
+	i := strings.IndexAny(link, ":/?#")
+	return i > 0 && link[i] == ':'
+}
+
+// pageTagPattern matches an HTML start tag.
+var pageTagPattern = regexp.MustCompile(`<[a-zA-Z][^>]*>`)
+
+// pageLinkPattern matches the href and src attributes of a tag, quoted or not.
+var pageLinkPattern = regexp.MustCompile(`(?i)(\s(?:href|src)\s*=\s*)(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+
+// rewriteLinks rewrites the href and src attributes of the page at from.
+// Script bodies are left alone; link data used by scripts is routed when it
+// is generated.
+func (r *Router) rewriteLinks(from string, content []byte) []byte {
+	if !r.active() {
+		return content
+	}
+	rewriteTags := func(s string) string {
+		return pageTagPattern.ReplaceAllStringFunc(s, func(tag string) string {
+			return pageLinkPattern.ReplaceAllStringFunc(tag, func(attr string) string {
+				m := pageLinkPattern.FindStringSubmatch(attr)
+				link := html.UnescapeString(m[2] + m[3] + m[4])
+				return m[1] + `"` + html.EscapeString(r.Link(from, link)) + `"`
+			})
+		})
+	}
+
+	var sb strings.Builder
+	rest := string(content)
+	for rest != "" {
+		// Markup up to the end of the next <script> start tag
+		n := len(rest)
+		if i := strings.Index(strings.ToLower(rest), "<script"); i >= 0 {
+			if j := strings.IndexByte(rest[i:], '>'); j >= 0 {
+				n = i + j + 1
+			}
+		}
+		sb.WriteString(rewriteTags(rest[:n]))
+		rest = rest[n:]
+
+		end := strings.Index(strings.ToLower(rest), "</script")
+		if end < 0 {
+			end = len(rest)
+		}
+		sb.WriteString(rest[:end])
+		rest = rest[end:]
+	}
+	return []byte(sb.String())
+}
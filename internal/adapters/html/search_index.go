@@ -0,0 +1,186 @@
+package html
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// searchIndex is the content of search.json and of each search shard.
+type searchIndex struct {
+	Results []searchResult `json:"results"`
+	Shards  []searchShard  `json:"shards,omitempty"`
+}
+
+// searchResult is a page of the site the client-side search can find.
+type searchResult struct {
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+}
+
+// searchShard is the entry of search.json for the shard holding the
+// containers of a system. The client fetches the shards of the systems a
+// search matches by name first, then the others as more results are needed.
+type searchShard struct {
+	System string `json:"system"`
+	Name   string `json:"name"`
+	Path   string `json:"path"` // Fingerprinted, relative to search.json, e.g. "search/shop.3f2a9c01be.json"
+	Count  int    `json:"count"`
+}
+
+// searchShardState is a shard written by an earlier build and the
+// fingerprint of the sources it was built from.
+type searchShardState struct {
+	sources string
+	shard   searchShard
+}
+
+// buildSearchIndex generates a JSON search index for client-side search.
+// search.json holds the domains and systems; the containers of each system
+// are in a shard of their own, rewritten only when the system changed since
+// the last build, so watch rebuilds of large sites reindex little.
+func (b *Builder) buildSearchIndex(systems []*entities.System, domains []*entities.Domain, outputDir string) error {
+	var index searchIndex
+
+	// Add domains to search index
+	for _, domain := range domains {
+		domain.Walk(func(d *entities.Domain) {
+			index.Results = append(index.Results, searchResult{
+				Title:       d.Name,
+				URL:         b.router.URL(usecases.SearchIndexFile, fmt.Sprintf("domains/%s.html", d.ID)),
+				Description: d.ID,
+				Type:        "domain",
+			})
+		})
+	}
+
+	// Add systems to search index, and their containers to its shards
+	states := make(map[string]searchShardState)
+	for _, system := range systems {
+		if system == nil {
+			continue
+		}
+		index.Results = append(index.Results, searchResult{
+			Title:       system.Name,
+			URL:         b.router.URL(usecases.SearchIndexFile, fmt.Sprintf("systems/%s.html", system.ID)),
+			Description: system.Description,
+			Type:        "system",
+		})
+		if len(system.Containers) == 0 {
+			continue
+		}
+
+		state, err := b.writeSearchShard(system, outputDir)
+		if err != nil {
+			return err
+		}
+		states[system.ID] = state
+		index.Shards = append(index.Shards, state.shard)
+	}
+	b.searchShards = states
+
+	if err := removeStaleShards(outputDir, index.Shards); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal search index: %w", err)
+	}
+
+	filePath := filepath.Join(outputDir, usecases.SearchIndexFile)
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+
+	return nil
+}
+
+// writeSearchShard writes the shard of a system's containers, unless the
+// previous build wrote it from the same sources and it still exists.
+func (b *Builder) writeSearchShard(system *entities.System, outputDir string) (searchShardState, error) {
+	sources := b.searchSources(system)
+	if state, ok := b.searchShards[system.ID]; ok && sources != "" && state.sources == sources {
+		if _, err := os.Stat(filepath.Join(outputDir, filepath.FromSlash(state.shard.Path))); err == nil {
+			return state, nil
+		}
+	}
+
+	var shard searchIndex
+	for _, container := range system.ListContainers() {
+		if container == nil {
+			continue
+		}
+		shard.Results = append(shard.Results, searchResult{
+			Title:       container.Name,
+			URL:         b.router.URL(usecases.SearchIndexFile, fmt.Sprintf("systems/%s.html#%s", system.ID, container.ID)),
+			Description: container.Description,
+			Type:        "container",
+		})
+	}
+
+	data, err := json.MarshalIndent(shard, "", "  ")
+	if err != nil {
+		return searchShardState{}, fmt.Errorf("failed to marshal search shard of %s: %w", system.ID, err)
+	}
+	shardPath := fingerprint(path.Join(usecases.SearchShardDir, system.ID+".json"), string(data))
+	if err := os.WriteFile(filepath.Join(outputDir, filepath.FromSlash(shardPath)), data, 0644); err != nil {
+		return searchShardState{}, fmt.Errorf("failed to write search shard of %s: %w", system.ID, err)
+	}
+
+	return searchShardState{
+		sources: sources,
+		shard:   searchShard{System: system.ID, Name: system.Name, Path: shardPath, Count: len(shard.Results)},
+	}, nil
+}
+
+// searchSources fingerprints what the shard of a system is built from: the
+// content hashes of the system's and its containers' Markdown files and the
+// link style of the site. It is empty, and the shard always rebuilt, when an
+// element was not loaded from a file.
+func (b *Builder) searchSources(system *entities.System) string {
+	if system.ContentHash == "" {
+		return ""
+	}
+	parts := []string{system.ContentHash, b.router.URL(usecases.SearchIndexFile, "systems/"+system.ID+".html")}
+	for _, container := range system.ListContainers() {
+		if container == nil || container.ContentHash == "" {
+			return ""
+		}
+		parts = append(parts, container.ID+"="+container.ContentHash)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// removeStaleShards deletes the files of the shard directory that are not
+// shards of the current index, such as those of removed systems or older
+// copies of changed ones.
+func removeStaleShards(outputDir string, shards []searchShard) error {
+	current := make(map[string]bool, len(shards))
+	for _, shard := range shards {
+		current[path.Base(shard.Path)] = true
+	}
+
+	dir := filepath.Join(outputDir, usecases.SearchShardDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to list search shards: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || current[entry.Name()] || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to remove stale search shard %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
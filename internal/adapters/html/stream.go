@@ -0,0 +1,131 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// entityPage identifies one system, container or component page to generate.
+// Container is nil for system pages; Component is nil for system and container pages.
+type entityPage struct {
+	System    *entities.System
+	Container *entities.Container
+	Component *entities.Component
+}
+
+// entityPages yields every system, container and component page in depth-first
+// order, containers and components sorted by ID, one at a time, so callers can
+// generate and release each page before moving on to the next. Nil entries
+// are skipped.
+func entityPages(systems []*entities.System) iter.Seq[entityPage] {
+	return func(yield func(entityPage) bool) {
+		for _, system := range systems {
+			if system == nil {
+				continue
+			}
+			if !yield(entityPage{System: system}) {
+				return
+			}
+			for _, container := range system.ListContainers() {
+				if container == nil {
+					continue
+				}
+				if !yield(entityPage{System: system, Container: container}) {
+					return
+				}
+				for _, component := range container.ListComponents() {
+					if component == nil {
+						continue
+					}
+					if !yield(entityPage{System: system, Container: container, Component: component}) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// maxPageWorkers bounds how many pages are generated concurrently.
+const maxPageWorkers = 8
+
+// bufferPool recycles page render buffers across pages and workers.
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// buildEntityPages generates all system, container and component pages using a
+// bounded pool of workers fed from entityPages. The first error cancels the
+// remaining work and is returned.
+func (b *Builder) buildEntityPages(ctx context.Context, systems []*entities.System, kpis map[string]*usecases.SystemKPIs, outputDir string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	pageCh := make(chan entityPage)
+	for range maxPageWorkers {
+		wg.Go(func() {
+			for page := range pageCh {
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := b.buildEntityPage(ctx, page, kpis, outputDir); err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		})
+	}
+
+	for page := range entityPages(systems) {
+		select {
+		case pageCh <- page:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(pageCh)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// writePage renders the named template into a pooled buffer and writes it as
+// page, a slash-separated path relative to outputDir that the router maps to
+// the output file. Nothing is written if rendering fails.
+func (b *Builder) writePage(outputDir, page, templateName string, data any) error {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer bufferPool.Put(buf)
+
+	if err := b.templates.ExecuteTemplate(buf, templateName, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", templateName, err)
+	}
+	filePath := filepath.Join(outputDir, filepath.FromSlash(b.router.File(page)))
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", page, err)
+	}
+	if err := os.WriteFile(filePath, b.router.rewriteLinks(page, buf.Bytes()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filePath, err)
+	}
+	return nil
+}
@@ -8,6 +8,10 @@ var templateMap = map[string]string{
 	"containers-overview.html": containersOverviewTemplate,
 	"component.html":           componentTemplate,
 	"components-overview.html": componentsOverviewTemplate,
+	"graph.html":               graphTemplate,
+	"timeline.html":            timelineTemplate,
+	"costs.html":               costsTemplate,
+	"domain.html":              domainTemplate,
 	"base.html":                baseTemplate,
 }
 
@@ -18,7 +22,8 @@ const baseTemplate = `<!DOCTYPE html>
 	<meta charset="UTF-8">
 	<meta name="viewport" content="width=device-width, initial-scale=1.0">
 	<title>{{.Title}} - {{.Project.Name}}</title>
-	<link rel="stylesheet" href="/styles/style.css">
+	<link rel="stylesheet" href="/{{asset "styles/style.css"}}">
+	{{customHead}}
 </head>
 <body>
 	<div class="container">
@@ -50,10 +55,11 @@ const baseTemplate = `<!DOCTYPE html>
 			{{template "content" .}}
 			<footer class="footer">
 				<p>Generated by <a href="https://github.com/madstone-tech/loko">loko</a></p>
+				{{customFooter}}
 			</footer>
 		</main>
 	</div>
-	<script src="/js/main.js"></script>
+	<script src="/{{asset "js/main.js"}}"></script>
 </body>
 </html>`
 
@@ -65,7 +71,8 @@ const indexTemplate = `{{define "index.html"}}
 	<meta charset="UTF-8">
 	<meta name="viewport" content="width=device-width, initial-scale=1.0">
 	<title>{{.Project.Name}} - Architecture Documentation</title>
-	<link rel="stylesheet" href="styles/style.css">
+	<link rel="stylesheet" href="{{asset "styles/style.css"}}">
+	{{customHead}}
 </head>
 <body>
 	<div class="container">
@@ -108,8 +115,32 @@ const indexTemplate = `{{define "index.html"}}
 				<p class="version">Version: <code>{{.Project.Version}}</code></p>
 				{{end}}
 
+				{{if .Domains}}
+				<section class="domains-section">
+					<h2>Domains</h2>
+					{{if .LandscapePath}}
+					<div class="diagram-container">
+						<img src="{{.LandscapePath}}" alt="Domain Landscape" class="diagram-image">
+					</div>
+					{{end}}
+					<div class="systems-grid">
+						{{range .Domains}}
+						<div class="system-card">
+							<h3><a href="domains/{{.ID}}.html">{{.Name}}</a></h3>
+							<p class="container-count">{{len .AllSystems}} system{{if ne (len .AllSystems) 1}}s{{end}}</p>
+						</div>
+						{{end}}
+					</div>
+				</section>
+				{{end}}
+
 				<section class="systems-section">
 					<h2>Systems</h2>
+					{{if .SystemLandscapePath}}
+					<div class="diagram-container">
+						<img src="{{.SystemLandscapePath}}" alt="System Landscape" class="diagram-image">
+					</div>
+					{{end}}
 					{{if .Systems}}
 					<div class="systems-grid">
 						{{range .Systems}}
@@ -138,11 +169,44 @@ const indexTemplate = `{{define "index.html"}}
 					{{end}}
 				</section>
 
+				{{if .People}}
+				<section class="people-section">
+					<h2>People</h2>
+					<div class="systems-grid">
+						{{range .People}}
+						<div class="system-card person-card">
+							<h3>{{.Name}}</h3>
+							{{if .Description}}
+							<p>{{.Description}}</p>
+							{{end}}
+							{{if .External}}
+							<div class="tags"><span class="tag">external</span></div>
+							{{end}}
+							{{if .Relationships}}
+							<ul class="person-uses">
+								{{range $target, $description := .Relationships}}
+								<li><code>{{$target}}</code>{{if $description}} — {{$description}}{{end}}</li>
+								{{end}}
+							</ul>
+							{{end}}
+						</div>
+						{{end}}
+					</div>
+				</section>
+				{{end}}
+
 			<section class="quick-links">
 				<h2>Quick Navigation</h2>
 				<div class="quick-links-grid">
 					<div><a href="containers.html" class="nav-link">View all Containers →</a></div>
 					<div><a href="components.html" class="nav-link">View all Components →</a></div>
+					<div><a href="graph.html" class="nav-link">Explore the Architecture Graph →</a></div>
+					{{if .HasTimeline}}
+					<div><a href="timeline.html" class="nav-link">Architecture Timeline →</a></div>
+					{{end}}
+					{{if .HasCosts}}
+					<div><a href="costs.html" class="nav-link">Monthly Costs →</a></div>
+					{{end}}
 				</div>
 			</section>
 
@@ -166,10 +230,12 @@ const indexTemplate = `{{define "index.html"}}
 			</article>
 			<footer class="footer">
 				<p>Generated by <a href="https://github.com/madstone-tech/loko">loko</a></p>
+				{{customFooter}}
 			</footer>
 		</main>
 	</div>
-	<script src="js/main.js"></script>
+	<script src="{{asset "js/main.js"}}"></script>
+	<script src="{{asset "js/diagram-viewer.js"}}"></script>
 </body>
 </html>
 {{end}}`
@@ -182,7 +248,8 @@ const systemTemplate = `{{define "system.html"}}
 	<meta charset="UTF-8">
 	<meta name="viewport" content="width=device-width, initial-scale=1.0">
 	<title>{{.System.Name}} - Architecture Documentation</title>
-	<link rel="stylesheet" href="../styles/style.css">
+	<link rel="stylesheet" href="../{{asset "styles/style.css"}}">
+	{{customHead}}
 </head>
 <body>
 	<div class="container">
@@ -212,10 +279,17 @@ const systemTemplate = `{{define "system.html"}}
 			<div class="breadcrumb">
 				<a href="../index.html" class="breadcrumb-item">Home</a>
 				<span class="breadcrumb-separator">/</span>
+				{{if .System.Domain}}
+				<a href="../domains/{{.System.Domain}}.html" class="breadcrumb-item">{{.System.Domain}}</a>
+				<span class="breadcrumb-separator">/</span>
+				{{end}}
 				<span class="breadcrumb-item active">{{.System.Name}}</span>
 			</div>
 			<article class="content">
 				<h1>{{.System.Name}}</h1>
+				{{with .System.Review}}{{if .Status}}
+				<p class="review-badge {{.Status}}">{{.Status}}{{if .IsApproved}}{{with .ReviewedBy}} by {{.}}{{end}}{{if not .ReviewedAt.IsZero}} on {{.ReviewedAt.Format "2006-01-02"}}{{end}}{{end}}</p>
+				{{end}}{{end}}
 				{{if .System.Description}}
 				<p class="description">{{.System.Description}}</p>
 				{{end}}
@@ -228,6 +302,42 @@ const systemTemplate = `{{define "system.html"}}
 				</div>
 				{{end}}
 
+				{{if .System.Issues}}
+				<div class="issue-badges">
+					{{range .System.Issues}}
+					{{$url := issueURL .}}
+					{{if $url}}<a class="issue-badge" href="{{$url}}" target="_blank" rel="noopener">{{issueKey .}}</a>{{else}}<span class="issue-badge">{{.}}</span>{{end}}
+					{{end}}
+				</div>
+				{{end}}
+
+				{{with customFields .System.Metadata}}
+				<dl class="custom-fields">
+					{{range .}}
+					<dt>{{.Label}}</dt>
+					<dd>{{.Value}}</dd>
+					{{end}}
+				</dl>
+				{{end}}
+
+				{{if not .System.UpdatedAt.IsZero}}
+				<p class="last-updated">Last updated <time datetime="{{.System.UpdatedAt.Format "2006-01-02T15:04:05Z07:00"}}">{{.System.UpdatedAt.Format "2006-01-02"}}</time>{{with .System.Author}} by {{.}}{{end}}</p>
+				{{end}}
+
+				{{with .KPIs}}
+				<div class="kpi-badges">
+					<span class="kpi-badge"><span class="kpi-value">{{.ContainerCount}}</span> containers</span>
+					<span class="kpi-badge"><span class="kpi-value">{{.ComponentCount}}</span> components</span>
+					<span class="kpi-badge{{if lt .Coverage 50}} kpi-badge-low{{end}}" title="{{.Documented}} of {{.Elements}} elements have a description"><span class="kpi-value">{{.Coverage}}%</span> documented</span>
+					{{if .Validated}}
+					<span class="kpi-badge{{if .Warnings}} kpi-badge-warn{{end}}"><span class="kpi-value">{{.Warnings}}</span> open warnings</span>
+					{{end}}
+					{{if not .LastModified.IsZero}}
+					<span class="kpi-badge">modified <span class="kpi-value">{{.LastModified.Format "2006-01-02"}}</span></span>
+					{{end}}
+				</div>
+				{{end}}
+
 			{{if .System.Diagram}}
 			<section class="diagram-section">
 				<h2>System Diagram</h2>
@@ -261,7 +371,7 @@ const systemTemplate = `{{define "system.html"}}
 							<p>{{.Description}}</p>
 							{{end}}
 							{{if .Technology}}
-							<p class="technology"><strong>Technology:</strong> <code>{{.Technology}}</code></p>
+							<p class="technology">{{with techIcon .Technology}}<img class="tech-icon" src="{{.}}" alt="" width="20" height="20" loading="lazy"> {{end}}<strong>Technology:</strong> <code>{{.Technology}}</code></p>
 							{{end}}
 							{{if .Tags}}
 							<div class="tags">
@@ -299,13 +409,47 @@ const systemTemplate = `{{define "system.html"}}
 				{{else}}
 				<p class="empty-state">No containers found in this system.</p>
 				{{end}}
+
+				{{if .Users}}
+				<section class="users-section">
+					<h2>Users</h2>
+					<ul class="person-uses">
+						{{range .Users}}
+						<li><strong>{{.Name}}</strong>{{if .External}} <span class="tag">external</span>{{end}}{{if .Description}} — {{.Description}}{{end}}</li>
+						{{end}}
+					</ul>
+				</section>
+				{{end}}
+
+				{{if .Relationships}}
+				<section class="relationships-section">
+					<h2>Relationships</h2>
+					<table class="relationships-table">
+						<thead>
+							<tr><th>From</th><th>To</th><th>Description</th><th>Technology</th></tr>
+						</thead>
+						<tbody>
+							{{range .Relationships}}
+							<tr>
+								<td><code>{{.Source}}</code></td>
+								<td><code>{{.Target}}</code></td>
+								<td>{{.Label}}</td>
+								<td>{{if .Technology}}<code>{{.Technology}}</code>{{end}}</td>
+							</tr>
+							{{end}}
+						</tbody>
+					</table>
+				</section>
+				{{end}}
 			</article>
 			<footer class="footer">
 				<p>Generated by <a href="https://github.com/madstone-tech/loko">loko</a></p>
+				{{customFooter}}
 			</footer>
 		</main>
 	</div>
-	<script src="../js/main.js"></script>
+	<script src="../{{asset "js/main.js"}}"></script>
+	<script src="../{{asset "js/diagram-viewer.js"}}"></script>
 </body>
 </html>
 {{end}}`
@@ -428,6 +572,34 @@ body {
 	box-shadow: 0 0 0 3px var(--color-primary-light);
 }
 
+.search-results {
+	list-style: none;
+	margin-top: var(--spacing-sm);
+	border: 1px solid var(--color-border);
+	border-radius: var(--border-radius);
+	background-color: var(--color-bg);
+	max-height: 320px;
+	overflow-y: auto;
+}
+
+.search-results li a {
+	display: block;
+	padding: var(--spacing-sm) var(--spacing-md);
+	color: var(--color-text);
+	text-decoration: none;
+	font-size: 0.875rem;
+}
+
+.search-results li a:hover {
+	background-color: var(--color-primary-light);
+}
+
+.search-result-type {
+	float: right;
+	font-size: 0.75rem;
+	color: var(--color-text-light);
+}
+
 .system-list {
 	list-style: none;
 }
@@ -591,6 +763,115 @@ body {
 	letter-spacing: 0.5px;
 }
 
+/* Issue badges */
+.issue-badges {
+	display: flex;
+	flex-wrap: wrap;
+	gap: var(--spacing-sm);
+	margin: var(--spacing-md) 0;
+}
+
+.issue-badge {
+	display: inline-block;
+	padding: var(--spacing-xs) var(--spacing-md);
+	border: 1px solid var(--color-border);
+	border-radius: var(--border-radius);
+	font-family: var(--font-mono);
+	font-size: 0.75rem;
+	color: var(--color-text);
+	text-decoration: none;
+}
+
+a.issue-badge:hover {
+	border-color: var(--color-primary);
+	color: var(--color-primary);
+}
+
+/* Review status badges */
+.review-badge {
+	display: inline-block;
+	margin: 0 0 var(--spacing-sm) 0;
+	padding: var(--spacing-xs) var(--spacing-md);
+	border-radius: var(--border-radius);
+	font-size: 0.75rem;
+	font-weight: 600;
+	text-transform: uppercase;
+	letter-spacing: 0.5px;
+	background: #fef3c7;
+	color: #92400e;
+}
+
+.review-badge.approved {
+	background: #d1fae5;
+	color: #065f46;
+}
+
+.review-badge.in-review {
+	background: #dbeafe;
+	color: #1e40af;
+}
+
+/* Custom frontmatter fields */
+.custom-fields {
+	display: grid;
+	grid-template-columns: max-content 1fr;
+	gap: var(--spacing-xs) var(--spacing-md);
+	margin: var(--spacing-md) 0;
+	font-size: 0.875rem;
+}
+
+.custom-fields dt {
+	font-weight: 600;
+	color: var(--color-text-light);
+}
+
+.custom-fields dd {
+	margin: 0;
+}
+
+.last-updated {
+	margin: var(--spacing-sm) 0;
+	font-size: 0.875rem;
+	color: var(--color-text-light);
+}
+
+/* KPI badges */
+.kpi-badges {
+	display: flex;
+	flex-wrap: wrap;
+	gap: var(--spacing-sm);
+	margin: var(--spacing-md) 0 var(--spacing-lg);
+}
+
+.kpi-badge {
+	display: inline-flex;
+	align-items: baseline;
+	gap: var(--spacing-xs);
+	padding: var(--spacing-xs) var(--spacing-md);
+	border: 1px solid var(--color-border);
+	border-radius: var(--border-radius);
+	background-color: var(--color-bg-alt);
+	color: var(--color-text-light);
+	font-size: 0.875rem;
+}
+
+.kpi-value {
+	color: var(--color-text);
+	font-weight: 600;
+}
+
+.kpi-badge-low {
+	border-color: var(--color-warning);
+}
+
+.kpi-badge-warn {
+	border-color: var(--color-error);
+}
+
+.kpi-badge-warn .kpi-value {
+	color: var(--color-error);
+}
+
 /* Cards */
 .systems-grid {
 	display: grid;
@@ -631,6 +912,16 @@ body {
 	color: var(--color-text-light);
 }
 
+.person-uses {
+	margin: var(--spacing-md) 0 0;
+	padding-left: var(--spacing-lg);
+	font-size: 0.875rem;
+}
+
+.users-section {
+	margin-top: var(--spacing-2xl);
+}
+
 .container-count {
 	font-size: 0.875rem;
 	color: var(--color-text-light);
@@ -699,6 +990,10 @@ body {
 	color: var(--color-text-light);
 }
 
+.technology .tech-icon {
+	vertical-align: middle;
+}
+
 .technology code {
 	background-color: var(--color-bg);
 	padding: var(--spacing-xs) var(--spacing-sm);
@@ -735,6 +1030,73 @@ body {
 	object-fit: contain;
 }
 
+/* Diagram Viewer */
+.diagram-viewer {
+	width: 100%;
+	outline: none;
+}
+
+.diagram-viewer:focus-visible {
+	box-shadow: 0 0 0 2px var(--color-primary-light);
+}
+
+.diagram-viewer:fullscreen {
+	display: flex;
+	flex-direction: column;
+	padding: var(--spacing-md);
+	background-color: var(--color-bg);
+}
+
+.diagram-toolbar {
+	display: flex;
+	justify-content: flex-end;
+	gap: var(--spacing-xs);
+	margin-bottom: var(--spacing-sm);
+}
+
+.diagram-button {
+	min-width: 2rem;
+	padding: var(--spacing-xs) var(--spacing-sm);
+	border: 1px solid var(--color-border);
+	border-radius: var(--border-radius);
+	background-color: var(--color-bg);
+	color: var(--color-text);
+	font-size: 0.85rem;
+	cursor: pointer;
+}
+
+.diagram-button:hover {
+	border-color: var(--color-primary);
+	color: var(--color-primary);
+}
+
+.diagram-viewport {
+	position: relative;
+	overflow: hidden;
+	display: flex;
+	align-items: center;
+	justify-content: center;
+	cursor: grab;
+	touch-action: none;
+}
+
+.diagram-viewport.panning {
+	cursor: grabbing;
+}
+
+.diagram-viewport .diagram-image {
+	transform-origin: 0 0;
+	user-select: none;
+}
+
+.diagram-viewer:fullscreen .diagram-viewport {
+	flex: 1;
+}
+
+.diagram-viewer:fullscreen .diagram-image {
+	max-height: 100%;
+}
+
 /* Markdown Documentation Section */
 .markdown-section {
 	margin-top: var(--spacing-2xl);
@@ -1166,6 +1528,39 @@ code {
 	font-size: 0.95rem;
 }
 
+.relationships-table {
+	width: 100%;
+	border-collapse: collapse;
+	margin-top: var(--spacing-lg);
+}
+
+.relationships-table th,
+.relationships-table td {
+	padding: var(--spacing-sm) var(--spacing-md);
+	border: 1px solid var(--color-border);
+	text-align: left;
+}
+
+.relationships-table th {
+	background-color: var(--color-primary-light);
+	color: var(--color-primary-dark);
+}
+
+.costs-table td.amount,
+.costs-table th.amount {
+	text-align: right;
+	font-family: var(--font-mono);
+}
+
+.costs-table tr.system-row td,
+.costs-table tr.total-row td {
+	font-weight: 600;
+}
+
+.costs-table tr.container-row td:first-child {
+	padding-left: var(--spacing-xl);
+}
+
 .relationship-description {
 	margin: 0;
 	font-size: 0.9rem;
@@ -1240,37 +1635,249 @@ code {
 	margin-bottom: var(--spacing-lg);
 	color: var(--color-text-secondary);
 	font-size: 0.95rem;
-}`
+}
 
-// jsContent contains the embedded JavaScript for interactivity.
-const jsContent = `// Search functionality
-document.addEventListener('DOMContentLoaded', function() {
-	const searchInput = document.getElementById('search');
-	if (searchInput) {
-		searchInput.addEventListener('input', function(e) {
-			const query = e.target.value.toLowerCase();
-			const items = document.querySelectorAll('.system-link, .container-link');
-			
-			items.forEach(item => {
-				const text = item.textContent.toLowerCase();
-				const parent = item.closest('li');
-				if (text.includes(query)) {
-					parent.style.display = '';
-				} else {
-					parent.style.display = 'none';
-				}
-			});
-		});
-	}
-});
+/* Graph Explorer */
+.graph-controls {
+	display: flex;
+	flex-wrap: wrap;
+	gap: var(--spacing-md);
+	align-items: center;
+	margin-bottom: var(--spacing-md);
+}
 
-// Smooth scroll for anchor links
-document.addEventListener('click', function(e) {
-	if (e.target.tagName === 'A' && e.target.getAttribute('href').startsWith('#')) {
-		e.preventDefault();
-		const target = document.querySelector(e.target.getAttribute('href'));
-		if (target) {
-			target.scrollIntoView({ behavior: 'smooth' });
+.graph-controls label {
+	font-size: 0.9rem;
+	color: var(--color-text-secondary);
+}
+
+.graph-controls select {
+	margin-left: var(--spacing-xs);
+	padding: var(--spacing-xs) var(--spacing-sm);
+	border: 1px solid var(--color-border);
+	border-radius: var(--border-radius);
+}
+
+.graph-canvas {
+	width: 100%;
+	height: 640px;
+	border: 1px solid var(--color-border);
+	border-radius: var(--border-radius);
+	background: var(--color-bg-alt);
+	cursor: grab;
+}
+
+.graph-canvas .graph-node {
+	cursor: pointer;
+}
+
+.graph-canvas .graph-node text {
+	font-size: 11px;
+	fill: var(--color-text);
+	pointer-events: none;
+}
+
+.graph-canvas .graph-edge {
+	stroke: #9ca3af;
+	stroke-width: 1.2;
+}
+
+.graph-canvas .graph-edge.contains {
+	stroke-dasharray: 4 3;
+	stroke: #d1d5db;
+}
+
+.graph-legend {
+	display: flex;
+	gap: var(--spacing-md);
+	margin-top: var(--spacing-sm);
+	font-size: 0.85rem;
+	color: var(--color-text-secondary);
+}
+
+.graph-legend-swatch {
+	display: inline-block;
+	width: 0.75rem;
+	height: 0.75rem;
+	border-radius: 50%;
+	margin-right: var(--spacing-xs);
+	vertical-align: middle;
+}
+
+.graph-tooltip {
+	min-height: 1.5rem;
+	margin-top: var(--spacing-sm);
+	font-size: 0.9rem;
+	color: var(--color-text-secondary);
+}
+
+/* Timeline */
+.timeline {
+	border-left: 2px solid var(--color-border);
+	margin-left: var(--spacing-sm);
+	padding-left: var(--spacing-lg);
+}
+
+.timeline-day h3 {
+	font-size: 1rem;
+	margin: var(--spacing-lg) 0 var(--spacing-sm);
+}
+
+.timeline-event {
+	display: flex;
+	gap: var(--spacing-sm);
+	align-items: baseline;
+	padding: var(--spacing-xs) 0;
+}
+
+.timeline-kind {
+	font-size: 0.75rem;
+	font-weight: 600;
+	text-transform: uppercase;
+	padding: 0 var(--spacing-xs);
+	border-radius: var(--border-radius);
+}
+
+.timeline-kind.added {
+	background: #d1fae5;
+	color: #065f46;
+}
+
+.timeline-kind.deprecated {
+	background: #fef3c7;
+	color: #92400e;
+}
+
+.timeline-kind.removed {
+	background: #fee2e2;
+	color: #991b1b;
+}
+
+.timeline-meta {
+	font-size: 0.85rem;
+	color: var(--color-text-secondary);
+}`
+
+// jsContent contains the embedded JavaScript for interactivity.
+const jsContent = `// Site search: search.json lists the domains and systems, and the shards
+// holding the containers of each system. A search fetches the shards of the
+// systems it matches first, then the others a few at a time until enough
+// results are shown; fetched shards are kept for later searches.
+const searchIndexURL = document.currentScript ? new URL('../search.json', document.currentScript.src) : null;
+const maxSearchResults = 20;
+const shardBatchSize = 4;
+let searchIndex = null;
+const searchShards = new Map();
+
+function fetchResults(url) {
+	return fetch(url)
+		.then(response => response.ok ? response.json() : {})
+		.then(index => index.results || [])
+		.catch(() => []);
+}
+
+function loadSearchIndex() {
+	if (!searchIndex) {
+		searchIndex = fetch(searchIndexURL)
+			.then(response => response.ok ? response.json() : {})
+			.catch(() => ({}))
+			.then(index => ({
+				results: index.results || [],
+				shards: index.shards || []
+			}));
+	}
+	return searchIndex;
+}
+
+function loadSearchShard(shard) {
+	if (!searchShards.has(shard.path)) {
+		searchShards.set(shard.path, fetchResults(new URL(shard.path, searchIndexURL)));
+	}
+	return searchShards.get(shard.path);
+}
+
+function searchSite(input, query) {
+	let list = input.parentNode.querySelector('.search-results');
+	if (!list) {
+		list = document.createElement('ul');
+		list.className = 'search-results';
+		input.parentNode.appendChild(list);
+	}
+	list.hidden = true;
+	list.replaceChildren();
+	if (!searchIndexURL || query.length < 2) {
+		return;
+	}
+
+	const matches = [];
+	const current = () => input.value.trim().toLowerCase() === query; // A later search replaces this one
+	const add = results => {
+		if (!current()) {
+			return;
+		}
+		results.forEach(result => {
+			if ((result.title + ' ' + (result.description || '')).toLowerCase().includes(query)) {
+				matches.push(result);
+			}
+		});
+		list.replaceChildren(...matches.slice(0, maxSearchResults).map(result => {
+			const item = document.createElement('li');
+			const link = document.createElement('a');
+			link.href = new URL(result.url, searchIndexURL).href;
+			link.textContent = result.title;
+			const type = document.createElement('span');
+			type.className = 'search-result-type';
+			type.textContent = result.type;
+			link.appendChild(type);
+			item.appendChild(link);
+			return item;
+		}));
+		list.hidden = matches.length === 0;
+	};
+	const addShards = shards => {
+		if (shards.length === 0 || matches.length >= maxSearchResults || !current()) {
+			return;
+		}
+		Promise.all(shards.slice(0, shardBatchSize).map(loadSearchShard)).then(batch => {
+			batch.forEach(add);
+			addShards(shards.slice(shardBatchSize));
+		});
+	};
+	loadSearchIndex().then(index => {
+		add(index.results);
+		const matching = shard => ((shard.name || '') + ' ' + shard.system).toLowerCase().includes(query);
+		addShards([...index.shards.filter(matching), ...index.shards.filter(shard => !matching(shard))]);
+	});
+}
+
+document.addEventListener('DOMContentLoaded', function() {
+	const searchInput = document.getElementById('search');
+	if (searchInput) {
+		searchInput.addEventListener('input', function(e) {
+			const query = e.target.value.toLowerCase();
+			const items = document.querySelectorAll('.system-link, .container-link');
+			
+			items.forEach(item => {
+				const text = item.textContent.toLowerCase();
+				const parent = item.closest('li');
+				if (text.includes(query)) {
+					parent.style.display = '';
+				} else {
+					parent.style.display = 'none';
+				}
+			});
+			searchSite(searchInput, query.trim());
+		});
+	}
+});
+
+// Smooth scroll for anchor links
+document.addEventListener('click', function(e) {
+	if (e.target.tagName === 'A' && e.target.getAttribute('href').startsWith('#')) {
+		e.preventDefault();
+		const target = document.querySelector(e.target.getAttribute('href'));
+		if (target) {
+			target.scrollIntoView({ behavior: 'smooth' });
 		}
 	}
 });
@@ -1300,6 +1907,161 @@ document.addEventListener('DOMContentLoaded', function() {
 	});
 });`
 
+// diagramViewerJSContent adds a toolbar to every diagram image with zoom,
+// pan, fullscreen and download buttons.
+const diagramViewerJSContent = `(function() {
+	const MIN_SCALE = 0.25;
+	const MAX_SCALE = 8;
+	const STEP = 1.25;
+
+	function button(label, title, onClick) {
+		const b = document.createElement('button');
+		b.type = 'button';
+		b.className = 'diagram-button';
+		b.textContent = label;
+		b.title = title;
+		b.setAttribute('aria-label', title);
+		b.addEventListener('click', onClick);
+		return b;
+	}
+
+	// fileName returns the name of the image file with its extension replaced by ext.
+	function fileName(img, ext) {
+		const name = decodeURIComponent(img.getAttribute('src').split(/[?#]/)[0].split('/').pop());
+		return name.replace(/\.[^.]*$/, '') + ext;
+	}
+
+	function download(href, name) {
+		const a = document.createElement('a');
+		a.href = href;
+		a.download = name;
+		document.body.appendChild(a);
+		a.click();
+		a.remove();
+	}
+
+	// downloadPNG rasterizes the diagram at twice its size on a white background.
+	function downloadPNG(img) {
+		const canvas = document.createElement('canvas');
+		canvas.width = (img.naturalWidth || img.width) * 2;
+		canvas.height = (img.naturalHeight || img.height) * 2;
+		const ctx = canvas.getContext('2d');
+		ctx.fillStyle = '#ffffff';
+		ctx.fillRect(0, 0, canvas.width, canvas.height);
+		ctx.drawImage(img, 0, 0, canvas.width, canvas.height);
+		try {
+			canvas.toBlob(blob => {
+				if (!blob) { return; }
+				const url = URL.createObjectURL(blob);
+				download(url, fileName(img, '.png'));
+				setTimeout(() => URL.revokeObjectURL(url), 1000);
+			}, 'image/png');
+		} catch (e) {
+			// Pages opened from disk cannot export the canvas; open the diagram instead
+			window.open(img.src);
+		}
+	}
+
+	function setup(img) {
+		const viewer = document.createElement('div');
+		viewer.className = 'diagram-viewer';
+		viewer.tabIndex = 0;
+		const toolbar = document.createElement('div');
+		toolbar.className = 'diagram-toolbar';
+		const viewport = document.createElement('div');
+		viewport.className = 'diagram-viewport';
+		img.parentNode.insertBefore(viewer, img);
+		viewport.appendChild(img);
+		viewer.appendChild(toolbar);
+		viewer.appendChild(viewport);
+		img.draggable = false;
+
+		// The image is translated and scaled from the top-left corner of its
+		// place in the viewport
+		const view = { x: 0, y: 0, scale: 1 };
+		function apply() {
+			img.style.transform = 'translate(' + view.x + 'px, ' + view.y + 'px) scale(' + view.scale + ')';
+		}
+		function zoom(factor, clientX, clientY) {
+			const rect = viewport.getBoundingClientRect();
+			const x = (clientX === undefined ? rect.left + rect.width / 2 : clientX) - rect.left - img.offsetLeft;
+			const y = (clientY === undefined ? rect.top + rect.height / 2 : clientY) - rect.top - img.offsetTop;
+			const scale = Math.min(MAX_SCALE, Math.max(MIN_SCALE, view.scale * factor));
+			view.x = x - (x - view.x) * scale / view.scale;
+			view.y = y - (y - view.y) * scale / view.scale;
+			view.scale = scale;
+			apply();
+		}
+		function reset() {
+			view.x = 0;
+			view.y = 0;
+			view.scale = 1;
+			apply();
+		}
+		function toggleFullscreen() {
+			if (document.fullscreenElement === viewer) {
+				document.exitFullscreen();
+			} else {
+				viewer.requestFullscreen();
+			}
+		}
+
+		toolbar.appendChild(button('+', 'Zoom in (+)', () => zoom(STEP)));
+		toolbar.appendChild(button('−', 'Zoom out (-)', () => zoom(1 / STEP)));
+		toolbar.appendChild(button('1:1', 'Reset zoom (0)', reset));
+		if (document.fullscreenEnabled && viewer.requestFullscreen) {
+			toolbar.appendChild(button('⛶', 'Fullscreen (f)', toggleFullscreen));
+		}
+		if (/\.svg$/i.test(img.getAttribute('src').split(/[?#]/)[0])) {
+			toolbar.appendChild(button('SVG', 'Download SVG', () => download(img.src, fileName(img, '.svg'))));
+		}
+		toolbar.appendChild(button('PNG', 'Download PNG', () => downloadPNG(img)));
+
+		// The wheel zooms with Ctrl or Cmd held, or in fullscreen, so that it
+		// still scrolls the page
+		viewport.addEventListener('wheel', e => {
+			if (!e.ctrlKey && !e.metaKey && document.fullscreenElement !== viewer) { return; }
+			e.preventDefault();
+			zoom(e.deltaY < 0 ? STEP : 1 / STEP, e.clientX, e.clientY);
+		}, { passive: false });
+		viewport.addEventListener('dblclick', e => zoom(STEP * STEP, e.clientX, e.clientY));
+
+		let drag = null;
+		viewport.addEventListener('pointerdown', e => {
+			if (e.button !== 0) { return; }
+			drag = { x: e.clientX - view.x, y: e.clientY - view.y };
+			viewport.setPointerCapture(e.pointerId);
+			viewport.classList.add('panning');
+		});
+		viewport.addEventListener('pointermove', e => {
+			if (!drag) { return; }
+			view.x = e.clientX - drag.x;
+			view.y = e.clientY - drag.y;
+			apply();
+		});
+		const endDrag = () => {
+			drag = null;
+			viewport.classList.remove('panning');
+		};
+		viewport.addEventListener('pointerup', endDrag);
+		viewport.addEventListener('pointercancel', endDrag);
+
+		viewer.addEventListener('keydown', e => {
+			if (e.target !== viewer) { return; }
+			const actions = { '+': () => zoom(STEP), '=': () => zoom(STEP), '-': () => zoom(1 / STEP), '0': reset, 'f': toggleFullscreen };
+			if (actions[e.key] && (e.key !== 'f' || document.fullscreenEnabled)) {
+				e.preventDefault();
+				actions[e.key]();
+			}
+		});
+		document.addEventListener('fullscreenchange', reset);
+	}
+
+	document.addEventListener('DOMContentLoaded', () => {
+		document.querySelectorAll('img.diagram-image').forEach(setup);
+	});
+})();`
+
 // containerTemplate is the container detail page template.
 const containerTemplate = `{{define "container.html"}}
 <!DOCTYPE html>
@@ -1308,7 +2070,8 @@ const containerTemplate = `{{define "container.html"}}
 	<meta charset="UTF-8">
 	<meta name="viewport" content="width=device-width, initial-scale=1.0">
 	<title>{{.Container.Name}} - {{.System.Name}} - Architecture Documentation</title>
-	<link rel="stylesheet" href="../styles/style.css">
+	<link rel="stylesheet" href="../{{asset "styles/style.css"}}">
+	{{customHead}}
 </head>
 <body>
 	<div class="container">
@@ -1346,12 +2109,15 @@ const containerTemplate = `{{define "container.html"}}
 			</div>
 			<article class="content">
 				<h1>{{.Container.Name}}</h1>
+				{{with .Container.Review}}{{if .Status}}
+				<p class="review-badge {{.Status}}">{{.Status}}{{if .IsApproved}}{{with .ReviewedBy}} by {{.}}{{end}}{{if not .ReviewedAt.IsZero}} on {{.ReviewedAt.Format "2006-01-02"}}{{end}}{{end}}</p>
+				{{end}}{{end}}
 				{{if .Container.Description}}
 				<p class="description">{{.Container.Description}}</p>
 				{{end}}
 
 				{{if .Container.Technology}}
-				<p class="technology"><strong>Technology:</strong> <code>{{.Container.Technology}}</code></p>
+				<p class="technology">{{with techIcon .Container.Technology}}<img class="tech-icon" src="{{.}}" alt="" width="20" height="20" loading="lazy"> {{end}}<strong>Technology:</strong> <code>{{.Container.Technology}}</code></p>
 				{{end}}
 
 				{{if .Container.Tags}}
@@ -1362,6 +2128,28 @@ const containerTemplate = `{{define "container.html"}}
 				</div>
 				{{end}}
 
+				{{if .Container.Issues}}
+				<div class="issue-badges">
+					{{range .Container.Issues}}
+					{{$url := issueURL .}}
+					{{if $url}}<a class="issue-badge" href="{{$url}}" target="_blank" rel="noopener">{{issueKey .}}</a>{{else}}<span class="issue-badge">{{.}}</span>{{end}}
+					{{end}}
+				</div>
+				{{end}}
+
+				{{with customFields .Container.Metadata}}
+				<dl class="custom-fields">
+					{{range .}}
+					<dt>{{.Label}}</dt>
+					<dd>{{.Value}}</dd>
+					{{end}}
+				</dl>
+				{{end}}
+
+				{{if not .Container.UpdatedAt.IsZero}}
+				<p class="last-updated">Last updated <time datetime="{{.Container.UpdatedAt.Format "2006-01-02T15:04:05Z07:00"}}">{{.Container.UpdatedAt.Format "2006-01-02"}}</time>{{with .Container.Author}} by {{.}}{{end}}</p>
+				{{end}}
+
 				{{if .HasMarkdown}}
 				<section class="markdown-section">
 					<h2>Documentation</h2>
@@ -1419,10 +2207,12 @@ const containerTemplate = `{{define "container.html"}}
 			</article>
 			<footer class="footer">
 				<p>Generated by <a href="https://github.com/madstone-tech/loko">loko</a></p>
+				{{customFooter}}
 			</footer>
 		</main>
 	</div>
-	<script src="../js/main.js"></script>
+	<script src="../{{asset "js/main.js"}}"></script>
+	<script src="../{{asset "js/diagram-viewer.js"}}"></script>
 </body>
 </html>
 {{end}}`
@@ -1435,7 +2225,8 @@ const containersOverviewTemplate = `{{define "containers-overview.html"}}
 	<meta charset="UTF-8">
 	<meta name="viewport" content="width=device-width, initial-scale=1.0">
 	<title>Containers - Architecture Documentation</title>
-	<link rel="stylesheet" href="styles/style.css">
+	<link rel="stylesheet" href="{{asset "styles/style.css"}}">
+	{{customHead}}
 </head>
 <body>
 	<div class="container">
@@ -1487,7 +2278,7 @@ const containersOverviewTemplate = `{{define "containers-overview.html"}}
 						<p class="description">{{.Container.Description}}</p>
 						{{end}}
 						{{if .Container.Technology}}
-						<p class="technology"><strong>Technology:</strong> <code>{{.Container.Technology}}</code></p>
+						<p class="technology">{{with techIcon .Container.Technology}}<img class="tech-icon" src="{{.}}" alt="" width="20" height="20" loading="lazy"> {{end}}<strong>Technology:</strong> <code>{{.Container.Technology}}</code></p>
 						{{end}}
 						{{if .Container.Tags}}
 						<div class="tags">
@@ -1508,10 +2299,11 @@ const containersOverviewTemplate = `{{define "containers-overview.html"}}
 			</article>
 			<footer class="footer">
 				<p>Generated by <a href="https://github.com/madstone-tech/loko">loko</a></p>
+				{{customFooter}}
 			</footer>
 		</main>
 	</div>
-	<script src="js/main.js"></script>
+	<script src="{{asset "js/main.js"}}"></script>
 </body>
 </html>
 {{end}}`
@@ -1524,7 +2316,8 @@ const componentTemplate = `{{define "component.html"}}
 	<meta charset="UTF-8">
 	<meta name="viewport" content="width=device-width, initial-scale=1.0">
 	<title>{{.Component.Name}} - {{.Container.Name}} - Architecture Documentation</title>
-	<link rel="stylesheet" href="../styles/style.css">
+	<link rel="stylesheet" href="../{{asset "styles/style.css"}}">
+	{{customHead}}
 </head>
 <body>
 	<div class="container">
@@ -1560,10 +2353,17 @@ const componentTemplate = `{{define "component.html"}}
 				<span class="breadcrumb-separator">/</span>
 				<a href="../containers/{{.System.ID}}_{{.Container.ID}}.html" class="breadcrumb-item">{{.Container.Name}}</a>
 				<span class="breadcrumb-separator">/</span>
+				{{with .ParentComponent}}
+				<a href="{{.ID}}.html" class="breadcrumb-item">{{.Name}}</a>
+				<span class="breadcrumb-separator">/</span>
+				{{end}}
 				<span class="breadcrumb-item active">{{.Component.Name}}</span>
 			</div>
 			<article class="content">
 				<h1>{{.Component.Name}}</h1>
+				{{with .Component.Review}}{{if .Status}}
+				<p class="review-badge {{.Status}}">{{.Status}}{{if .IsApproved}}{{with .ReviewedBy}} by {{.}}{{end}}{{if not .ReviewedAt.IsZero}} on {{.ReviewedAt.Format "2006-01-02"}}{{end}}{{end}}</p>
+				{{end}}{{end}}
 				{{if .Component.Description}}
 				<p class="description">{{.Component.Description}}</p>
 				{{end}}
@@ -1576,6 +2376,28 @@ const componentTemplate = `{{define "component.html"}}
 				</div>
 				{{end}}
 
+				{{if .Component.Issues}}
+				<div class="issue-badges">
+					{{range .Component.Issues}}
+					{{$url := issueURL .}}
+					{{if $url}}<a class="issue-badge" href="{{$url}}" target="_blank" rel="noopener">{{issueKey .}}</a>{{else}}<span class="issue-badge">{{.}}</span>{{end}}
+					{{end}}
+				</div>
+				{{end}}
+
+				{{with customFields .Component.Metadata}}
+				<dl class="custom-fields">
+					{{range .}}
+					<dt>{{.Label}}</dt>
+					<dd>{{.Value}}</dd>
+					{{end}}
+				</dl>
+				{{end}}
+
+				{{if not .Component.UpdatedAt.IsZero}}
+				<p class="last-updated">Last updated <time datetime="{{.Component.UpdatedAt.Format "2006-01-02T15:04:05Z07:00"}}">{{.Component.UpdatedAt.Format "2006-01-02"}}</time>{{with .Component.Author}} by {{.}}{{end}}</p>
+				{{end}}
+
 				{{if .HasMarkdown}}
 				<section class="markdown-section">
 					<h2>Documentation</h2>
@@ -1600,10 +2422,29 @@ const componentTemplate = `{{define "component.html"}}
 						<p>{{.Container.Description}}</p>
 						{{end}}
 						{{if .Container.Technology}}
-						<p class="technology"><strong>Technology:</strong> <code>{{.Container.Technology}}</code></p>
+						<p class="technology">{{with techIcon .Container.Technology}}<img class="tech-icon" src="{{.}}" alt="" width="20" height="20" loading="lazy"> {{end}}<strong>Technology:</strong> <code>{{.Container.Technology}}</code></p>
+						{{end}}
+					</div>
+				</section>
+
+				{{if .SubComponents}}
+				<section class="sub-components-section">
+					<h2>Sub-components</h2>
+					<div class="systems-grid">
+						{{range .SubComponents}}
+						<div class="system-card">
+							<h3><a href="{{.ID}}.html">{{.Name}}</a></h3>
+							{{if .Description}}
+							<p>{{.Description}}</p>
+							{{end}}
+							{{if .Technology}}
+							<p class="technology"><code>{{.Technology}}</code></p>
+							{{end}}
+						</div>
 						{{end}}
 					</div>
 				</section>
+				{{end}}
 
 			{{if .Component.Relationships}}
 			<section class="relationships-section">
@@ -1661,10 +2502,12 @@ const componentTemplate = `{{define "component.html"}}
 		</article>
 			<footer class="footer">
 				<p>Generated by <a href="https://github.com/madstone-tech/loko">loko</a></p>
+				{{customFooter}}
 			</footer>
 		</main>
 	</div>
-	<script src="../js/main.js"></script>
+	<script src="../{{asset "js/main.js"}}"></script>
+	<script src="../{{asset "js/diagram-viewer.js"}}"></script>
 </body>
 </html>
 {{end}}`
@@ -1677,7 +2520,8 @@ const componentsOverviewTemplate = `{{define "components-overview.html"}}
 	<meta charset="UTF-8">
 	<meta name="viewport" content="width=device-width, initial-scale=1.0">
 	<title>Components - Architecture Documentation</title>
-	<link rel="stylesheet" href="styles/style.css">
+	<link rel="stylesheet" href="{{asset "styles/style.css"}}">
+	{{customHead}}
 </head>
 <body>
 	<div class="container">
@@ -1744,10 +2588,547 @@ const componentsOverviewTemplate = `{{define "components-overview.html"}}
 			</article>
 			<footer class="footer">
 				<p>Generated by <a href="https://github.com/madstone-tech/loko">loko</a></p>
+				{{customFooter}}
+			</footer>
+		</main>
+	</div>
+	<script src="{{asset "js/main.js"}}"></script>
+</body>
+</html>
+{{end}}`
+
+// graphTemplate is the interactive architecture graph explorer page.
+// The graph payload is inlined so the page also works when opened from disk.
+const graphTemplate = `{{define "graph.html"}}
+<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="UTF-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1.0">
+	<title>Architecture Graph - {{.Project.Name}}</title>
+	<link rel="stylesheet" href="{{asset "styles/style.css"}}">
+	{{customHead}}
+</head>
+<body>
+	<div class="container">
+		<aside class="sidebar">
+			<div class="sidebar-header">
+				<h1><a href="index.html">{{.Project.Name}}</a></h1>
+			</div>
+			<nav class="sidebar-nav">
+				<div class="search-box">
+					<input type="text" id="search" placeholder="Search..." class="search-input">
+				</div>
+				<ul class="system-list">
+					{{range .Systems}}
+					{{if .}}
+					<li><a href="systems/{{.ID}}.html" class="system-link">{{.Name}}</a></li>
+					{{end}}
+					{{end}}
+				</ul>
+			</nav>
+		</aside>
+		<main class="main-content">
+			<div class="breadcrumb">
+				<a href="index.html" class="breadcrumb-item">Home</a>
+				<span class="breadcrumb-separator">/</span>
+				<span class="breadcrumb-item active">Graph</span>
+			</div>
+			<article class="content">
+				<h1>Architecture Graph</h1>
+				<p class="description">{{len .Graph.Nodes}} elements, {{len .Graph.Edges}} relationships. Click a node to open its page; drag to pan, scroll to zoom.</p>
+
+				<div class="graph-controls">
+					<label>Layout
+						<select id="graph-layout">
+							<option value="force">Force-directed</option>
+							<option value="hierarchy">Hierarchical</option>
+						</select>
+					</label>
+					<label>Type
+						<select id="graph-type">
+							<option value="">All</option>
+							<option value="system">Systems</option>
+							<option value="container">Containers</option>
+							<option value="component">Components</option>
+						</select>
+					</label>
+					<label>System
+						<select id="graph-system">
+							<option value="">All</option>
+							{{range .Graph.Systems}}
+							<option value="{{.}}">{{.}}</option>
+							{{end}}
+						</select>
+					</label>
+					<label>Tag
+						<select id="graph-tag">
+							<option value="">All</option>
+							{{range .Graph.Tags}}
+							<option value="{{.}}">{{.}}</option>
+							{{end}}
+						</select>
+					</label>
+					<label><input type="checkbox" id="graph-hierarchy" checked> Show containment</label>
+				</div>
+
+				<svg id="graph-canvas" class="graph-canvas" xmlns="http://www.w3.org/2000/svg"></svg>
+				<div id="graph-tooltip" class="graph-tooltip"></div>
+				<div class="graph-legend">
+					<span><span class="graph-legend-swatch" style="background:#2563eb"></span>System</span>
+					<span><span class="graph-legend-swatch" style="background:#10b981"></span>Container</span>
+					<span><span class="graph-legend-swatch" style="background:#f59e0b"></span>Component</span>
+				</div>
+			</article>
+			<footer class="footer">
+				<p>Generated by <a href="https://github.com/madstone-tech/loko">loko</a></p>
+				{{customFooter}}
+			</footer>
+		</main>
+	</div>
+	<script>window.LOKO_GRAPH = {{.GraphJSON}};</script>
+	<script src="{{asset "js/main.js"}}"></script>
+	<script src="{{asset "js/graph.js"}}"></script>
+</body>
+</html>
+{{end}}`
+
+// graphJSContent contains the client-side graph explorer (layout, filtering, navigation).
+const graphJSContent = `(function() {
+	const data = window.LOKO_GRAPH;
+	const svg = document.getElementById('graph-canvas');
+	if (!data || !svg) {
+		return;
+	}
+
+	const SVG_NS = 'http://www.w3.org/2000/svg';
+	const colors = { system: '#2563eb', container: '#10b981', component: '#f59e0b' };
+	const radius = { system: 14, container: 10, component: 7 };
+	const controls = {
+		layout: document.getElementById('graph-layout'),
+		type: document.getElementById('graph-type'),
+		system: document.getElementById('graph-system'),
+		tag: document.getElementById('graph-tag'),
+		hierarchy: document.getElementById('graph-hierarchy')
+	};
+	const tooltip = document.getElementById('graph-tooltip');
+	const view = { x: 0, y: 0, scale: 1 };
+
+	function visibleGraph() {
+		const type = controls.type.value;
+		const system = controls.system.value;
+		const tag = controls.tag.value;
+		const nodes = data.nodes.filter(n =>
+			(!type || n.type === type) &&
+			(!system || n.system === system) &&
+			(!tag || n.tags.indexOf(tag) !== -1));
+		const ids = new Set(nodes.map(n => n.id));
+		const edges = data.edges.filter(e => ids.has(e.source) && ids.has(e.target));
+		if (controls.hierarchy.checked) {
+			nodes.forEach(n => {
+				if (n.parent && ids.has(n.parent)) {
+					edges.push({ source: n.parent, target: n.id, type: 'contains' });
+				}
+			});
+		}
+		return { nodes: nodes, edges: edges };
+	}
+
+	function hierarchyLayout(nodes, width, height) {
+		const levels = {};
+		nodes.forEach(n => { (levels[n.level] = levels[n.level] || []).push(n); });
+		const keys = Object.keys(levels).sort();
+		keys.forEach((level, row) => {
+			const items = levels[level];
+			items.forEach((n, i) => {
+				n.x = (i + 1) * width / (items.length + 1);
+				n.y = (row + 1) * height / (keys.length + 1);
+			});
+		});
+	}
+
+	function forceLayout(nodes, edges, width, height) {
+		const byId = {};
+		nodes.forEach((n, i) => {
+			const angle = 2 * Math.PI * i / Math.max(nodes.length, 1);
+			n.x = width / 2 + Math.cos(angle) * width / 3;
+			n.y = height / 2 + Math.sin(angle) * height / 3;
+			n.vx = 0;
+			n.vy = 0;
+			byId[n.id] = n;
+		});
+		const k = Math.sqrt(width * height / Math.max(nodes.length, 1)) * 0.6;
+		for (let iter = 0; iter < 300; iter++) {
+			const cooling = 1 - iter / 300;
+			for (let i = 0; i < nodes.length; i++) {
+				for (let j = i + 1; j < nodes.length; j++) {
+					const a = nodes[i], b = nodes[j];
+					let dx = a.x - b.x, dy = a.y - b.y;
+					const dist = Math.max(Math.sqrt(dx * dx + dy * dy), 0.01);
+					const force = k * k / dist;
+					dx /= dist; dy /= dist;
+					a.vx += dx * force; a.vy += dy * force;
+					b.vx -= dx * force; b.vy -= dy * force;
+				}
+			}
+			edges.forEach(e => {
+				const a = byId[e.source], b = byId[e.target];
+				if (!a || !b) { return; }
+				let dx = b.x - a.x, dy = b.y - a.y;
+				const dist = Math.max(Math.sqrt(dx * dx + dy * dy), 0.01);
+				const force = dist * dist / k;
+				dx /= dist; dy /= dist;
+				a.vx += dx * force; a.vy += dy * force;
+				b.vx -= dx * force; b.vy -= dy * force;
+			});
+			nodes.forEach(n => {
+				const speed = Math.sqrt(n.vx * n.vx + n.vy * n.vy) || 1;
+				const step = Math.min(speed, 10 * cooling + 0.5);
+				n.x = Math.min(width - 20, Math.max(20, n.x + n.vx / speed * step));
+				n.y = Math.min(height - 20, Math.max(20, n.y + n.vy / speed * step));
+				n.vx = 0;
+				n.vy = 0;
+			});
+		}
+	}
+
+	function el(name, attrs) {
+		const node = document.createElementNS(SVG_NS, name);
+		Object.keys(attrs).forEach(key => node.setAttribute(key, attrs[key]));
+		return node;
+	}
+
+	function render() {
+		const width = svg.clientWidth || 960;
+		const height = svg.clientHeight || 640;
+		const graph = visibleGraph();
+		const nodes = graph.nodes.map(n => Object.assign({}, n));
+		if (controls.layout.value === 'hierarchy') {
+			hierarchyLayout(nodes, width, height);
+		} else {
+			forceLayout(nodes, graph.edges, width, height);
+		}
+		const byId = {};
+		nodes.forEach(n => { byId[n.id] = n; });
+
+		while (svg.firstChild) {
+			svg.removeChild(svg.firstChild);
+		}
+		const defs = el('defs', {});
+		const marker = el('marker', { id: 'arrow', viewBox: '0 0 10 10', refX: '18', refY: '5', markerWidth: '6', markerHeight: '6', orient: 'auto' });
+		marker.appendChild(el('path', { d: 'M 0 0 L 10 5 L 0 10 z', fill: '#9ca3af' }));
+		defs.appendChild(marker);
+		svg.appendChild(defs);
+
+		const root = el('g', { id: 'graph-root' });
+		svg.appendChild(root);
+
+		graph.edges.forEach(e => {
+			const a = byId[e.source], b = byId[e.target];
+			if (!a || !b) { return; }
+			const line = el('line', { x1: a.x, y1: a.y, x2: b.x, y2: b.y, class: 'graph-edge ' + e.type });
+			if (e.type !== 'contains') {
+				line.setAttribute('marker-end', 'url(#arrow)');
+			}
+			const title = el('title', {});
+			title.textContent = (a.name + ' → ' + b.name) + (e.description ? ': ' + e.description : '');
+			line.appendChild(title);
+			root.appendChild(line);
+		});
+
+		nodes.forEach(n => {
+			const g = el('g', { class: 'graph-node', transform: 'translate(' + n.x + ',' + n.y + ')' });
+			g.appendChild(el('circle', { r: radius[n.type] || 8, fill: colors[n.type] || '#6b7280' }));
+			const label = el('text', { x: (radius[n.type] || 8) + 4, y: 4 });
+			label.textContent = n.name;
+			g.appendChild(label);
+			g.addEventListener('mouseenter', () => {
+				tooltip.textContent = n.name + ' (' + n.type + ')' +
+					(n.technology ? ' [' + n.technology + ']' : '') +
+					(n.description ? ' — ' + n.description : '');
+			});
+			g.addEventListener('click', () => {
+				if (n.url) {
+					window.location.href = n.url;
+				}
+			});
+			root.appendChild(g);
+		});
+		applyView();
+	}
+
+	function applyView() {
+		const root = document.getElementById('graph-root');
+		if (root) {
+			root.setAttribute('transform', 'translate(' + view.x + ',' + view.y + ') scale(' + view.scale + ')');
+		}
+	}
+
+	let drag = null;
+	svg.addEventListener('mousedown', e => { drag = { x: e.clientX - view.x, y: e.clientY - view.y }; });
+	window.addEventListener('mouseup', () => { drag = null; });
+	window.addEventListener('mousemove', e => {
+		if (!drag) { return; }
+		view.x = e.clientX - drag.x;
+		view.y = e.clientY - drag.y;
+		applyView();
+	});
+	svg.addEventListener('wheel', e => {
+		e.preventDefault();
+		view.scale = Math.min(4, Math.max(0.2, view.scale * (e.deltaY < 0 ? 1.1 : 0.9)));
+		applyView();
+	}, { passive: false });
+
+	Object.keys(controls).forEach(key => controls[key].addEventListener('change', render));
+	render();
+})();`
+
+// timelineTemplate is the architecture evolution timeline page.
+const timelineTemplate = `{{define "timeline.html"}}
+<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="UTF-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1.0">
+	<title>Timeline - {{.Project.Name}}</title>
+	<link rel="stylesheet" href="{{asset "styles/style.css"}}">
+	{{customHead}}
+</head>
+<body>
+	<div class="container">
+		<aside class="sidebar">
+			<div class="sidebar-header">
+				<h1><a href="index.html">{{.Project.Name}}</a></h1>
+			</div>
+			<nav class="sidebar-nav">
+				<div class="search-box">
+					<input type="text" id="search" placeholder="Search..." class="search-input">
+				</div>
+				<ul class="system-list">
+					{{range .Systems}}
+					{{if .}}
+					<li><a href="systems/{{.ID}}.html" class="system-link">{{.Name}}</a></li>
+					{{end}}
+					{{end}}
+				</ul>
+			</nav>
+		</aside>
+		<main class="main-content">
+			<div class="breadcrumb">
+				<a href="index.html" class="breadcrumb-item">Home</a>
+				<span class="breadcrumb-separator">/</span>
+				<span class="breadcrumb-item active">Timeline</span>
+			</div>
+			<article class="content">
+				<h1>Architecture Timeline</h1>
+				<p class="description">{{.Added}} added, {{.Deprecated}} deprecated, {{.Removed}} removed, reconstructed from revision history.</p>
+
+				<div class="timeline">
+					{{range .Groups}}
+					<section class="timeline-day">
+						<h3>{{.Date}}</h3>
+						{{range .Events}}
+						<div class="timeline-event">
+							<span class="timeline-kind {{.Kind}}">{{.Kind}}</span>
+							<span>{{.EntityType}}
+								{{if .URL}}<a href="{{.URL}}">{{.Name}}</a>{{else}}<strong>{{.Name}}</strong>{{end}}
+								<code>{{.EntityID}}</code>
+							</span>
+							<span class="timeline-meta">{{if .ShortCommit}}{{.ShortCommit}}{{end}}{{if .Author}} by {{.Author}}{{end}}{{if .Message}} — {{.Message}}{{end}}</span>
+						</div>
+						{{end}}
+					</section>
+					{{end}}
+				</div>
+			</article>
+			<footer class="footer">
+				<p>Generated by <a href="https://github.com/madstone-tech/loko">loko</a></p>
+				{{customFooter}}
+			</footer>
+		</main>
+	</div>
+	<script src="{{asset "js/main.js"}}"></script>
+</body>
+</html>
+{{end}}`
+
+// costsTemplate is the monthly cost rollup page.
+const costsTemplate = `{{define "costs.html"}}
+<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="UTF-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1.0">
+	<title>Costs - {{.Project.Name}}</title>
+	<link rel="stylesheet" href="{{asset "styles/style.css"}}">
+	{{customHead}}
+</head>
+<body>
+	<div class="container">
+		<aside class="sidebar">
+			<div class="sidebar-header">
+				<h1><a href="index.html">{{.Project.Name}}</a></h1>
+			</div>
+			<nav class="sidebar-nav">
+				<div class="search-box">
+					<input type="text" id="search" placeholder="Search..." class="search-input">
+				</div>
+				<ul class="system-list">
+					{{range .Systems}}
+					{{if .}}
+					<li><a href="systems/{{.ID}}.html" class="system-link">{{.Name}}</a></li>
+					{{end}}
+					{{end}}
+				</ul>
+			</nav>
+		</aside>
+		<main class="main-content">
+			<div class="breadcrumb">
+				<a href="index.html" class="breadcrumb-item">Home</a>
+				<span class="breadcrumb-separator">/</span>
+				<span class="breadcrumb-item active">Costs</span>
+			</div>
+			<article class="content">
+				<h1>Monthly Costs</h1>
+				<p class="description">Monthly running costs declared by containers, in {{.Currency}}.</p>
+
+				<table class="relationships-table costs-table">
+					<thead>
+						<tr>
+							<th>System / Container</th>
+							{{range .Environments}}<th class="amount">{{.}}</th>{{end}}
+							<th class="amount">Total</th>
+						</tr>
+					</thead>
+					<tbody>
+						{{range .Rows}}
+						<tr class="{{if .System}}system-row{{else}}container-row{{end}}">
+							<td><a href="{{.URL}}">{{.Name}}</a></td>
+							{{range .Amounts}}<td class="amount">{{.}}</td>{{end}}
+							<td class="amount">{{.Total}}</td>
+						</tr>
+						{{end}}
+						<tr class="total-row">
+							<td>{{.Totals.Name}}</td>
+							{{range .Totals.Amounts}}<td class="amount">{{.}}</td>{{end}}
+							<td class="amount">{{.Totals.Total}} {{.Currency}}</td>
+						</tr>
+					</tbody>
+				</table>
+
+				{{if .Unpriced}}
+				<section class="relationships-section">
+					<h2>Containers Without a Cost</h2>
+					<ul>
+						{{range .Unpriced}}<li><code>{{.}}</code></li>{{end}}
+					</ul>
+				</section>
+				{{end}}
+			</article>
+			<footer class="footer">
+				<p>Generated by <a href="https://github.com/madstone-tech/loko">loko</a></p>
+				{{customFooter}}
+			</footer>
+		</main>
+	</div>
+	<script src="{{asset "js/main.js"}}"></script>
+</body>
+</html>
+{{end}}`
+
+// domainTemplate is the domain page template, listing the nested domains and
+// systems of a domain.
+const domainTemplate = `{{define "domain.html"}}
+<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="UTF-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1.0">
+	<title>{{.Domain.Name}} - {{.Project.Name}}</title>
+	<link rel="stylesheet" href="../{{asset "styles/style.css"}}">
+	{{customHead}}
+</head>
+<body>
+	<div class="container">
+		<aside class="sidebar">
+			<div class="sidebar-header">
+				<h1><a href="../index.html">{{.Project.Name}}</a></h1>
+			</div>
+			<nav class="sidebar-nav">
+				<div class="search-box">
+					<input type="text" id="search" placeholder="Search..." class="search-input">
+				</div>
+				<ul class="system-list">
+					{{range .Domain.AllSystems}}
+					<li><a href="../systems/{{.ID}}.html" class="system-link">{{.Name}}</a></li>
+					{{end}}
+				</ul>
+			</nav>
+		</aside>
+		<main class="main-content">
+			<div class="breadcrumb">
+				<a href="../index.html" class="breadcrumb-item">Home</a>
+				<span class="breadcrumb-separator">/</span>
+				{{range .Parents}}
+				<a href="{{.ID}}.html" class="breadcrumb-item">{{.Name}}</a>
+				<span class="breadcrumb-separator">/</span>
+				{{end}}
+				<span class="breadcrumb-item active">{{.Domain.Name}}</span>
+			</div>
+			<article class="content">
+				<h1>{{.Domain.Name}}</h1>
+				<p class="description"><code>{{.Domain.ID}}</code></p>
+
+				{{if .DiagramPath}}
+				<section class="diagram-section">
+					<h2>Domain Diagram</h2>
+					<div class="diagram-container">
+						<img src="../{{.DiagramPath}}" alt="{{.Domain.Name}} Diagram" class="diagram-image">
+					</div>
+				</section>
+				{{end}}
+
+				{{if .Domain.Domains}}
+				<section class="domains-section">
+					<h2>Domains</h2>
+					<div class="systems-grid">
+						{{range .Domain.Domains}}
+						<div class="system-card">
+							<h3><a href="{{.ID}}.html">{{.Name}}</a></h3>
+							<p class="container-count">{{len .AllSystems}} system{{if ne (len .AllSystems) 1}}s{{end}}</p>
+						</div>
+						{{end}}
+					</div>
+				</section>
+				{{end}}
+
+				{{if .Domain.Systems}}
+				<section class="systems-section">
+					<h2>Systems</h2>
+					<div class="systems-grid">
+						{{range .Domain.Systems}}
+						<div class="system-card">
+							<h3><a href="../systems/{{.ID}}.html">{{.Name}}</a></h3>
+							{{if .Description}}
+							<p>{{.Description}}</p>
+							{{end}}
+							{{if .Containers}}
+							<p class="container-count">{{len .Containers}} container{{if ne (len .Containers) 1}}s{{end}}</p>
+							{{end}}
+						</div>
+						{{end}}
+					</div>
+				</section>
+				{{end}}
+			</article>
+			<footer class="footer">
+				<p>Generated by <a href="https://github.com/madstone-tech/loko">loko</a></p>
+				{{customFooter}}
 			</footer>
 		</main>
 	</div>
-	<script src="js/main.js"></script>
+	<script src="../{{asset "js/main.js"}}"></script>
+	<script src="../{{asset "js/diagram-viewer.js"}}"></script>
 </body>
 </html>
 {{end}}`
@@ -0,0 +1,60 @@
+package html
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// timelineGroup holds the timeline events recorded on a single day.
+type timelineGroup struct {
+	Date   string
+	Events []timelineEntry
+}
+
+// timelineEntry is a timeline event with its resolved page URL.
+type timelineEntry struct {
+	entities.TimelineEvent
+	ShortCommit string
+	URL         string
+}
+
+// buildTimelinePage generates timeline.html from the builder's timeline.
+// Events are grouped by day, oldest first. Removed entities are not linked.
+func (b *Builder) buildTimelinePage(_ context.Context, project *entities.Project, systems []*entities.System, outputDir string) error {
+	var groups []timelineGroup
+	for _, event := range b.timeline.Events {
+		day := event.Date.Format("2006-01-02")
+		if len(groups) == 0 || groups[len(groups)-1].Date != day {
+			groups = append(groups, timelineGroup{Date: day})
+		}
+
+		entry := timelineEntry{TimelineEvent: event, ShortCommit: event.Commit}
+		if len(entry.ShortCommit) > 7 {
+			entry.ShortCommit = entry.ShortCommit[:7]
+		}
+		if event.Kind != entities.TimelineRemoved {
+			parts, _ := entities.ParseQualifiedID(event.EntityID)
+			entry.URL = graphNodeURL(event.EntityType, parts)
+		}
+
+		last := &groups[len(groups)-1]
+		last.Events = append(last.Events, entry)
+	}
+
+	data := map[string]any{
+		"Project":    project,
+		"Systems":    systems,
+		"Groups":     groups,
+		"Added":      b.timeline.CountByKind(entities.TimelineAdded),
+		"Deprecated": b.timeline.CountByKind(entities.TimelineDeprecated),
+		"Removed":    b.timeline.CountByKind(entities.TimelineRemoved),
+	}
+
+	if err := b.writePage(outputDir, "timeline.html", "timeline.html", data); err != nil {
+		return fmt.Errorf("failed to write timeline page: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,250 @@
+// Package icons provides the icon packs that templates reference by name:
+// packs built into loko and packs of SVG files installed under the XDG data
+// directory.
+package icons
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// Ensure Store implements usecases.IconStore interface.
+var _ usecases.IconStore = (*Store)(nil)
+
+// TechPack is the built-in pack of technology badges that pages and diagrams
+// show next to container and component technologies.
+const TechPack = "tech"
+
+//go:embed packs
+var packsFS embed.FS
+
+// builtinPacks maps the name of each built-in pack to its icons, keyed by
+// icon name.
+var builtinPacks = func() map[string]map[string][]byte {
+	packs := map[string]map[string][]byte{TechPack: {}}
+	for _, name := range entities.BuiltinIconNames() {
+		packs[TechPack][name] = []byte(entities.BuiltinIconSVG(name))
+	}
+	entries, _ := fs.ReadDir(packsFS, "packs")
+	for _, entry := range entries {
+		dir, _ := fs.Sub(packsFS, "packs/"+entry.Name())
+		packs[entry.Name()], _ = readIcons(dir)
+	}
+	return packs
+}()
+
+// Store implements the IconStore interface. Each installed pack lives in its
+// own directory, <dir>/<name>/, holding SVG files; the icon name is the
+// file's path relative to that directory without the .svg extension.
+type Store struct {
+	dir string
+}
+
+// NewStore creates an icon store rooted at dir, typically the icons
+// directory under the XDG data directory.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// List returns the installed and built-in packs sorted by name. A missing
+// store directory means no packs are installed.
+func (s *Store) List(ctx context.Context) ([]*entities.IconPack, error) {
+	installed, err := s.installed()
+	if err != nil {
+		return nil, err
+	}
+
+	var packs []*entities.IconPack
+	for _, name := range slices.Sorted(maps.Keys(installed)) {
+		packs = append(packs, &entities.IconPack{
+			Name:  name,
+			Icons: slices.Sorted(maps.Keys(installed[name])),
+			Dir:   filepath.Join(s.dir, name),
+		})
+	}
+	for name, icons := range builtinPacks {
+		if _, ok := installed[name]; !ok {
+			packs = append(packs, &entities.IconPack{Name: name, Icons: slices.Sorted(maps.Keys(icons)), Builtin: true})
+		}
+	}
+	slices.SortFunc(packs, func(a, b *entities.IconPack) int { return strings.Compare(a.Name, b.Name) })
+	return packs, nil
+}
+
+// Install copies the SVG files under the directory source into the pack
+// name, replacing an installed pack of that name. An empty name defaults to
+// the directory's base name. A source that is not a directory but names a
+// built-in pack writes that pack's icons instead.
+func (s *Store) Install(ctx context.Context, source, name string) (*entities.IconPack, error) {
+	var icons map[string][]byte
+	info, err := os.Stat(source)
+	switch {
+	case err == nil && info.IsDir():
+		abs, absErr := filepath.Abs(source)
+		if absErr != nil {
+			return nil, fmt.Errorf("failed to resolve icon pack path: %w", absErr)
+		}
+		if name == "" {
+			name = strings.ToLower(filepath.Base(abs))
+		}
+		if icons, err = readIcons(os.DirFS(abs)); err != nil {
+			return nil, fmt.Errorf("failed to read icon pack %s: %w", source, err)
+		}
+	case builtinPacks[source] != nil:
+		if name == "" {
+			name = source
+		}
+		icons = builtinPacks[source]
+	case err == nil:
+		return nil, fmt.Errorf("icon pack %s is not a directory", source)
+	default:
+		return nil, fmt.Errorf("icon pack not found: %s is neither a directory nor a built-in pack (%s)",
+			source, strings.Join(slices.Sorted(maps.Keys(builtinPacks)), ", "))
+	}
+
+	pack := &entities.IconPack{Name: name, Icons: slices.Sorted(maps.Keys(icons))}
+	if err := pack.Validate(); err != nil {
+		return nil, err
+	}
+	if len(icons) == 0 {
+		return nil, fmt.Errorf("icon pack %s has no SVG files", source)
+	}
+
+	target := filepath.Join(s.dir, name)
+	staging := target + ".installing"
+	if err := os.RemoveAll(staging); err != nil {
+		return nil, fmt.Errorf("failed to install icon pack: %w", err)
+	}
+	for _, icon := range pack.Icons {
+		file := filepath.Join(staging, filepath.FromSlash(icon)+".svg")
+		if err = os.MkdirAll(filepath.Dir(file), 0755); err == nil {
+			err = os.WriteFile(file, icons[icon], 0644)
+		}
+		if err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = os.RemoveAll(target)
+	}
+	if err == nil {
+		err = os.Rename(staging, target)
+	}
+	if err != nil {
+		_ = os.RemoveAll(staging)
+		return nil, fmt.Errorf("failed to install icon pack %s: %w", name, err)
+	}
+
+	pack.Dir = target
+	return pack, nil
+}
+
+// ResolveIcon returns the data URI of the icon referenced by ref, either
+// "pack/name" or a name found in a single pack. Installed packs take
+// precedence over built-in packs of the same name.
+func (s *Store) ResolveIcon(ref string) (string, error) {
+	packName, name := entities.SplitIconRef(ref)
+	if name == "" {
+		return "", fmt.Errorf("invalid icon reference %q: expected pack/name or name", ref)
+	}
+	installed, err := s.installed()
+	if err != nil {
+		return "", err
+	}
+	packs := maps.Clone(builtinPacks)
+	maps.Copy(packs, installed)
+
+	if packName != "" {
+		icons, ok := packs[packName]
+		if !ok {
+			return "", fmt.Errorf("unknown icon pack %q in icon reference %q; install it with loko icons install", packName, ref)
+		}
+		svg, ok := icons[name]
+		if !ok {
+			return "", fmt.Errorf("icon pack %s has no icon %q", packName, name)
+		}
+		return entities.IconDataURI(svg), nil
+	}
+
+	var found []string
+	for _, p := range slices.Sorted(maps.Keys(packs)) {
+		if _, ok := packs[p][name]; ok {
+			found = append(found, p)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("no icon pack has an icon %q", name)
+	case 1:
+		return entities.IconDataURI(packs[found[0]][name]), nil
+	default:
+		return "", fmt.Errorf("icon %q is in several packs (%s); reference it as pack/%s", name, strings.Join(found, ", "), name)
+	}
+}
+
+// installed reads the icons of the packs installed in the store, keyed by
+// pack name.
+func (s *Store) installed() (map[string]map[string][]byte, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read icons directory: %w", err)
+	}
+	packs := make(map[string]map[string][]byte)
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || strings.HasSuffix(entry.Name(), ".installing") {
+			continue
+		}
+		icons, err := readIcons(os.DirFS(filepath.Join(s.dir, entry.Name())))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read icon pack %s: %w", entry.Name(), err)
+		}
+		packs[entry.Name()] = icons
+	}
+	return packs, nil
+}
+
+// readIcons reads the SVG files of dir, keyed by their slash-separated path
+// without the .svg extension. A file that is not an SVG document is an error.
+func readIcons(dir fs.FS) (map[string][]byte, error) {
+	icons := make(map[string][]byte)
+	err := fs.WalkDir(dir, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != "." && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !strings.EqualFold(path.Ext(p), ".svg") {
+			return nil
+		}
+		content, err := fs.ReadFile(dir, p)
+		if err != nil {
+			return err
+		}
+		if !bytes.Contains(content, []byte("<svg")) {
+			return fmt.Errorf("%s is not an SVG document", p)
+		}
+		icons[strings.TrimSuffix(p, path.Ext(p))] = content
+		return nil
+	})
+	return icons, err
+}
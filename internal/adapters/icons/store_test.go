@@ -0,0 +1,112 @@
+package icons
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+const testSVG = `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 8 8"><circle cx="4" cy="4" r="3"/></svg>`
+
+func TestStoreInstallAndResolve(t *testing.T) {
+	ctx := context.Background()
+	source := filepath.Join(t.TempDir(), "AWS")
+	for file, content := range map[string]string{
+		"lambda.svg":       testSVG,
+		"storage/s3.svg":   testSVG,
+		"person.svg":       testSVG,
+		"README.md":        "not an icon",
+		".git/ignored.svg": "not an icon",
+	} {
+		path := filepath.Join(source, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store := NewStore(filepath.Join(t.TempDir(), "icons"))
+	pack, err := store.Install(ctx, source, "")
+	if err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	if pack.Name != "aws" || !slices.Equal(pack.Icons, []string{"lambda", "person", "storage/s3"}) {
+		t.Errorf("pack = %+v", pack)
+	}
+
+	for _, ref := range []string{"aws/lambda", "lambda", "aws/storage/s3", "essentials/person", "tech/go"} {
+		if uri, err := store.ResolveIcon(ref); err != nil || !strings.HasPrefix(uri, "data:image/svg+xml;base64,") {
+			t.Errorf("ResolveIcon(%q) = %q, %v", ref, uri, err)
+		}
+	}
+	if uri, _ := store.ResolveIcon("lambda"); uri != entities.IconDataURI([]byte(testSVG)) {
+		t.Errorf("ResolveIcon(lambda) = %q", uri)
+	}
+	for ref, want := range map[string]string{
+		"person":       "several packs (aws, essentials)",
+		"gcp/run":      `unknown icon pack "gcp"`,
+		"aws/ec2":      `no icon "ec2"`,
+		"nonexistent":  `no icon pack has an icon "nonexistent"`,
+		"essentials/":  "invalid icon reference",
+		"tech/missing": `no icon "missing"`,
+	} {
+		if _, err := store.ResolveIcon(ref); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ResolveIcon(%q) error = %v, want %q", ref, err, want)
+		}
+	}
+}
+
+func TestStoreInstallBuiltinAndList(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(filepath.Join(t.TempDir(), "icons"))
+
+	packs, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(packs) != 2 || packs[0].Name != "essentials" || !packs[0].Builtin || packs[1].Name != TechPack {
+		t.Fatalf("packs = %+v", packs)
+	}
+
+	// Installing a built-in pack writes its icons, which then take precedence
+	pack, err := store.Install(ctx, "essentials", "")
+	if err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	person := filepath.Join(pack.Dir, "person.svg")
+	if err := os.WriteFile(person, []byte(testSVG), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if uri, _ := store.ResolveIcon("essentials/person"); uri != entities.IconDataURI([]byte(testSVG)) {
+		t.Errorf("edited icon not resolved: %q", uri)
+	}
+	packs, err = store.List(ctx)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(packs) != 2 || packs[0].Builtin || packs[0].Dir != pack.Dir {
+		t.Errorf("packs = %+v", packs)
+	}
+
+	if _, err := store.Install(ctx, "no-such-pack", ""); err == nil || !strings.Contains(err.Error(), "essentials, tech") {
+		t.Errorf("Install(no-such-pack) error = %v", err)
+	}
+	if _, err := store.Install(ctx, "essentials", "Bad Name"); err == nil {
+		t.Error("Install with an invalid name succeeded")
+	}
+
+	bad := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bad, "x.svg"), []byte("GIF89a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Install(ctx, bad, "bad"); err == nil || !strings.Contains(err.Error(), "not an SVG") {
+		t.Errorf("Install(bad) error = %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+// Package issues provides issue tracker adapters used to check the status of
+// tickets referenced from `issues:` frontmatter. Jira is supported through its
+// REST API; credentials are read from the environment, never from loko.toml.
+package issues
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// Supported trackers.
+const (
+	TrackerJira = "jira"
+)
+
+// Environment variables holding tracker credentials.
+const (
+	EnvToken = "LOKO_ISSUE_TOKEN" // API token or personal access token
+	EnvUser  = "LOKO_ISSUE_USER"  // Account email; enables Jira Cloud basic auth
+)
+
+// Ensure JiraTracker implements usecases.IssueTracker interface.
+var _ usecases.IssueTracker = (*JiraTracker)(nil)
+
+// JiraTracker looks up issues through the Jira REST API (v2).
+//
+// Jira Cloud authenticates with the account email and an API token (basic
+// auth); Jira Server and Data Center accept a personal access token as a
+// bearer token. An empty user selects bearer auth.
+type JiraTracker struct {
+	client  *http.Client
+	baseURL string
+	user    string
+	token   string
+}
+
+// NewJiraTracker creates a JiraTracker for the Jira site at baseURL,
+// e.g. "https://acme.atlassian.net".
+func NewJiraTracker(baseURL, user, token string) *JiraTracker {
+	return &JiraTracker{
+		client:  &http.Client{Timeout: 30 * time.Second},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		user:    user,
+		token:   token,
+	}
+}
+
+// jiraIssue is the subset of a Jira issue used here.
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Status struct {
+			Name           string `json:"name"`
+			StatusCategory struct {
+				Key string `json:"key"`
+			} `json:"statusCategory"`
+		} `json:"status"`
+		Resolution *struct {
+			Name string `json:"name"`
+		} `json:"resolution"`
+	} `json:"fields"`
+}
+
+// IssueStatus fetches the status and resolution of the issue with the given
+// key. An issue is closed when its status is in Jira's "done" category, which
+// covers both completed and abandoned ("Won't Do") tickets.
+func (j *JiraTracker) IssueStatus(ctx context.Context, key string) (*usecases.IssueStatus, error) {
+	endpoint := fmt.Sprintf("%s/rest/api/2/issue/%s?fields=status,resolution", j.baseURL, url.PathEscape(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if j.user != "" {
+		req.SetBasicAuth(j.user, j.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+j.token)
+	}
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s failed: %w", endpoint, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%s: %w", key, entities.ErrIssueNotFound)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("GET %s returned %s: %s", endpoint, resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var issue jiraIssue
+	if err := json.NewDecoder(resp.Body).Decode(&issue); err != nil {
+		return nil, fmt.Errorf("failed to decode response from %s: %w", endpoint, err)
+	}
+
+	status := &usecases.IssueStatus{
+		Key:    issue.Key,
+		Status: issue.Fields.Status.Name,
+		Closed: issue.Fields.Status.StatusCategory.Key == "done",
+	}
+	if issue.Fields.Resolution != nil {
+		status.Resolution = issue.Fields.Resolution.Name
+	}
+	return status, nil
+}
+
+// NewTracker returns the IssueTracker configured by the [issues] section of
+// loko.toml, reading credentials with getenv. When baseURL is empty it is
+// derived from the scheme and host of urlTemplate.
+func NewTracker(kind, baseURL, urlTemplate string, getenv func(string) string) (usecases.IssueTracker, error) {
+	kind = strings.ToLower(kind)
+	switch kind {
+	case TrackerJira:
+	case "":
+		return nil, fmt.Errorf("issue tracker is not configured; set tracker in [issues]")
+	default:
+		return nil, fmt.Errorf("unsupported issue tracker %q (supported: %s)", kind, TrackerJira)
+	}
+
+	token := getenv(EnvToken)
+	if token == "" {
+		return nil, fmt.Errorf("issue tracker token is not set; export %s", EnvToken)
+	}
+
+	if baseURL == "" {
+		if u, err := url.Parse(urlTemplate); err == nil && u.Scheme != "" && u.Host != "" {
+			baseURL = u.Scheme + "://" + u.Host
+		}
+	}
+	if baseURL == "" {
+		return nil, fmt.Errorf("issue tracker URL is not set; set tracker_url in [issues]")
+	}
+
+	return NewJiraTracker(baseURL, getenv(EnvUser), token), nil
+}
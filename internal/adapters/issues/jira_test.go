@@ -0,0 +1,106 @@
+package issues
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func envFunc(env map[string]string) func(string) string {
+	return func(key string) string { return env[key] }
+}
+
+func TestJiraTrackerIssueStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me@acme.com" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/rest/api/2/issue/PAY-1":
+			_, _ = w.Write([]byte(`{"key":"PAY-1","fields":{"status":{"name":"Closed","statusCategory":{"key":"done"}},"resolution":{"name":"Won't Do"}}}`))
+		case "/rest/api/2/issue/PAY-2":
+			_, _ = w.Write([]byte(`{"key":"PAY-2","fields":{"status":{"name":"In Progress","statusCategory":{"key":"indeterminate"}},"resolution":null}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	tracker := NewJiraTracker(srv.URL+"/", "me@acme.com", "secret")
+	ctx := context.Background()
+
+	closed, err := tracker.IssueStatus(ctx, "PAY-1")
+	if err != nil {
+		t.Fatalf("IssueStatus(PAY-1) failed: %v", err)
+	}
+	if !closed.Closed || closed.Status != "Closed" || closed.Resolution != "Won't Do" {
+		t.Errorf("PAY-1 = %+v, want closed with resolution", closed)
+	}
+
+	open, err := tracker.IssueStatus(ctx, "PAY-2")
+	if err != nil {
+		t.Fatalf("IssueStatus(PAY-2) failed: %v", err)
+	}
+	if open.Closed || open.Resolution != "" {
+		t.Errorf("PAY-2 = %+v, want open", open)
+	}
+
+	if _, err := tracker.IssueStatus(ctx, "PAY-404"); !errors.Is(err, entities.ErrIssueNotFound) {
+		t.Errorf("IssueStatus(PAY-404) error = %v, want ErrIssueNotFound", err)
+	}
+
+	if _, err := NewJiraTracker(srv.URL, "", "secret").IssueStatus(ctx, "PAY-1"); err == nil || errors.Is(err, entities.ErrIssueNotFound) {
+		t.Errorf("expected auth error, got %v", err)
+	}
+}
+
+func TestJiraTrackerBearerAuth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer pat" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"key":"OPS-7","fields":{"status":{"name":"Done","statusCategory":{"key":"done"}}}}`))
+	}))
+	defer srv.Close()
+
+	status, err := NewJiraTracker(srv.URL, "", "pat").IssueStatus(context.Background(), "OPS-7")
+	if err != nil {
+		t.Fatalf("IssueStatus failed: %v", err)
+	}
+	if !status.Closed {
+		t.Errorf("status = %+v, want closed", status)
+	}
+}
+
+func TestNewTracker(t *testing.T) {
+	env := envFunc(map[string]string{EnvToken: "secret"})
+
+	tracker, err := NewTracker("Jira", "", "https://acme.atlassian.net/browse/{id}", env)
+	if err != nil {
+		t.Fatalf("NewTracker failed: %v", err)
+	}
+	if got := tracker.(*JiraTracker).baseURL; got != "https://acme.atlassian.net" {
+		t.Errorf("baseURL = %q, want derived from url_template", got)
+	}
+
+	tests := map[string]struct {
+		kind, baseURL, template string
+		env                     func(string) string
+	}{
+		"missing token":   {TrackerJira, "https://jira", "", envFunc(nil)},
+		"missing url":     {TrackerJira, "", "", env},
+		"no tracker":      {"", "https://jira", "", env},
+		"unknown tracker": {"linear", "https://jira", "", env},
+	}
+	for name, tt := range tests {
+		if _, err := NewTracker(tt.kind, tt.baseURL, tt.template, tt.env); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
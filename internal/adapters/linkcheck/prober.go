@@ -0,0 +1,67 @@
+// Package linkcheck provides the HTTP prober used to check the external links
+// of a built documentation site.
+package linkcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// DefaultTimeout is how long a URL may take to answer when no timeout is set.
+const DefaultTimeout = 10 * time.Second
+
+// Ensure HTTPProber implements usecases.LinkProber interface.
+var _ usecases.LinkProber = (*HTTPProber)(nil)
+
+// HTTPProber checks URLs with a HEAD request, falling back to GET for servers
+// that do not support HEAD. Any status below 400 counts as reachable.
+type HTTPProber struct {
+	client *http.Client
+}
+
+// NewHTTPProber creates an HTTPProber giving up on a URL after timeout, or
+// DefaultTimeout when timeout is not positive.
+func NewHTTPProber(timeout time.Duration) *HTTPProber {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &HTTPProber{client: &http.Client{Timeout: timeout}}
+}
+
+// Probe requests url and returns an error for network failures, timeouts and
+// error statuses.
+func (p *HTTPProber) Probe(ctx context.Context, url string) error {
+	status, err := p.request(ctx, http.MethodHead, url)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented || status == http.StatusForbidden) {
+		status, err = p.request(ctx, http.MethodGet, url)
+	}
+	if err != nil {
+		return err
+	}
+	if status >= 400 {
+		return fmt.Errorf("returned %d %s", status, http.StatusText(status))
+	}
+	return nil
+}
+
+// request sends a request and returns the response status.
+func (p *HTTPProber) request(ctx context.Context, method, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("invalid URL: %w", err)
+	}
+	req.Header.Set("User-Agent", "loko-link-checker")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%s failed: %w", method, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,40 @@
+package linkcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPProberProbe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+		case "/get-only":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	prober := NewHTTPProber(50 * time.Millisecond)
+	ctx := context.Background()
+
+	for _, path := range []string{"/ok", "/get-only"} {
+		if err := prober.Probe(ctx, srv.URL+path); err != nil {
+			t.Errorf("Probe(%s) failed: %v", path, err)
+		}
+	}
+	for _, path := range []string{"/missing", "/slow"} {
+		if err := prober.Probe(ctx, srv.URL+path); err == nil {
+			t.Errorf("Probe(%s) succeeded, want an error", path)
+		}
+	}
+}
@@ -1,16 +1,28 @@
 // Package markdown provides a Markdown documentation builder adapter.
 // It implements the MarkdownBuilder interface by producing a single README.md
-// file with complete architecture documentation.
+// file with complete architecture documentation, or an index README.md with a
+// docs/<system-id>/README.md file per system.
 package markdown
 
 import (
 	"context"
 	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
 )
 
+// Ensure Builder implements usecases.MarkdownBuilder.
+var _ usecases.MarkdownBuilder = (*Builder)(nil)
+
+// SystemsDir is the directory of the per-system files in the multi-file layout.
+const SystemsDir = "docs"
+
 // Builder implements the MarkdownBuilder interface by generating Markdown documentation.
 // It produces a single README.md with complete architecture hierarchy.
 type Builder struct{}
@@ -20,25 +32,69 @@ func NewBuilder() *Builder {
 	return &Builder{}
 }
 
+// style controls how a system is written.
+type style struct {
+	level  int    // Heading level of the system
+	root   string // Path from the file to the output root, for diagram images; no images if empty
+	github bool   // Add GitHub-flavored task lists and mermaid diagrams
+}
+
 // BuildMarkdown generates a single README.md with complete architecture.
 func (b *Builder) BuildMarkdown(ctx context.Context, project *entities.Project, systems []*entities.System) (string, error) {
+	return b.buildSingleFile(ctx, project, systems, style{level: 2})
+}
+
+// BuildMarkdownFiles generates the Markdown documentation laid out as
+// configured in opts, keyed by slash-separated path relative to the output
+// directory.
+func (b *Builder) BuildMarkdownFiles(ctx context.Context, project *entities.Project, systems []*entities.System, opts usecases.MarkdownOptions) (map[string]string, error) {
+	if !opts.PerSystem {
+		content, err := b.buildSingleFile(ctx, project, systems, style{level: 2, github: opts.GitHubFlavored})
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{"README.md": content}, nil
+	}
 	if project == nil {
-		return "", fmt.Errorf("project cannot be nil")
+		return nil, fmt.Errorf("project cannot be nil")
 	}
 
+	files := make(map[string]string, len(systems)+1)
 	var sb strings.Builder
+	writeProjectHeader(&sb, project)
+	sb.WriteString("## Systems\n\n")
+	for _, sys := range systems {
+		if sys == nil {
+			continue
+		}
+		file := path.Join(SystemsDir, sys.ID, "README.md")
+		sb.WriteString(fmt.Sprintf("- [%s](%s)", sys.Name, file))
+		if sys.Description != "" {
+			sb.WriteString(" - " + sys.Description)
+		}
+		sb.WriteString("\n")
 
-	// Project header
-	sb.WriteString(fmt.Sprintf("# %s\n\n", project.Name))
-
-	if project.Description != "" {
-		sb.WriteString(fmt.Sprintf("%s\n\n", project.Description))
+		var page strings.Builder
+		page.WriteString("[← Architecture overview](../../README.md)\n\n")
+		if err := b.writeSystem(ctx, &page, sys, sys.ListContainers(), style{level: 1, root: "../../", github: opts.GitHubFlavored}); err != nil {
+			return nil, fmt.Errorf("failed to build system markdown for %s: %w", sys.Name, err)
+		}
+		files[file] = page.String()
 	}
+	sb.WriteString("\n")
+	files["README.md"] = sb.String()
+	return files, nil
+}
 
-	if project.Version != "" {
-		sb.WriteString(fmt.Sprintf("**Version:** %s\n\n", project.Version))
+// buildSingleFile generates a README.md holding every system.
+func (b *Builder) buildSingleFile(ctx context.Context, project *entities.Project, systems []*entities.System, st style) (string, error) {
+	if project == nil {
+		return "", fmt.Errorf("project cannot be nil")
 	}
 
+	var sb strings.Builder
+	writeProjectHeader(&sb, project)
+
 	// Table of contents
 	sb.WriteString("## Table of Contents\n\n")
 	for _, sys := range systems {
@@ -60,31 +116,50 @@ func (b *Builder) BuildMarkdown(ctx context.Context, project *entities.Project,
 		if sys == nil {
 			continue
 		}
-		content, err := b.BuildSystemMarkdown(ctx, sys, sys.ListContainers())
-		if err != nil {
+		if err := b.writeSystem(ctx, &sb, sys, sys.ListContainers(), st); err != nil {
 			return "", fmt.Errorf("failed to build system markdown for %s: %w", sys.Name, err)
 		}
-		sb.WriteString(content)
 		sb.WriteString("\n---\n\n")
 	}
 
 	return sb.String(), nil
 }
 
+// writeProjectHeader writes the project title, description and version.
+func writeProjectHeader(sb *strings.Builder, project *entities.Project) {
+	sb.WriteString(fmt.Sprintf("# %s\n\n", project.Name))
+
+	if project.Description != "" {
+		sb.WriteString(fmt.Sprintf("%s\n\n", project.Description))
+	}
+
+	if project.Version != "" {
+		sb.WriteString(fmt.Sprintf("**Version:** %s\n\n", project.Version))
+	}
+}
+
 // BuildSystemMarkdown generates Markdown for a single system.
 func (b *Builder) BuildSystemMarkdown(ctx context.Context, system *entities.System, containers []*entities.Container) (string, error) {
-	if system == nil {
-		return "", fmt.Errorf("system cannot be nil")
+	var sb strings.Builder
+	if err := b.writeSystem(ctx, &sb, system, containers, style{level: 2}); err != nil {
+		return "", err
 	}
+	return sb.String(), nil
+}
 
-	var sb strings.Builder
+// writeSystem writes the Markdown of a system and its containers.
+func (b *Builder) writeSystem(ctx context.Context, sb *strings.Builder, system *entities.System, containers []*entities.Container, st style) error {
+	if system == nil {
+		return fmt.Errorf("system cannot be nil")
+	}
 
-	// System header (Level 2)
-	sb.WriteString(fmt.Sprintf("## %s\n\n", system.Name))
+	// System header
+	sb.WriteString(fmt.Sprintf("%s %s\n\n", heading(st.level), system.Name))
 
 	if system.Description != "" {
 		sb.WriteString(fmt.Sprintf("%s\n\n", system.Description))
 	}
+	writeDiagram(sb, system.Name, system.DiagramPath, st)
 
 	// System metadata
 	if len(system.Tags) > 0 {
@@ -107,39 +182,44 @@ func (b *Builder) BuildSystemMarkdown(ctx context.Context, system *entities.Syst
 		sb.WriteString("\n")
 	}
 
+	if st.github {
+		writeChecklist(sb, system, containers, st)
+	}
+
 	// Containers
 	if len(containers) > 0 {
-		sb.WriteString("### Containers\n\n")
+		sb.WriteString(fmt.Sprintf("%s Containers\n\n", heading(st.level+1)))
 
 		for _, container := range containers {
 			if container == nil {
 				continue
 			}
-			content, err := b.buildContainerMarkdown(ctx, container)
+			content, err := b.buildContainerMarkdown(ctx, container, st)
 			if err != nil {
-				return "", fmt.Errorf("failed to build container markdown for %s: %w", container.Name, err)
+				return fmt.Errorf("failed to build container markdown for %s: %w", container.Name, err)
 			}
 			sb.WriteString(content)
 		}
 	}
 
-	return sb.String(), nil
+	return nil
 }
 
 // buildContainerMarkdown generates Markdown for a single container.
-func (b *Builder) buildContainerMarkdown(_ context.Context, container *entities.Container) (string, error) {
+func (b *Builder) buildContainerMarkdown(_ context.Context, container *entities.Container, st style) (string, error) {
 	if container == nil {
 		return "", fmt.Errorf("container cannot be nil")
 	}
 
 	var sb strings.Builder
 
-	// Container header (Level 4)
-	sb.WriteString(fmt.Sprintf("#### %s\n\n", container.Name))
+	// Container header
+	sb.WriteString(fmt.Sprintf("%s %s\n\n", heading(st.level+2), container.Name))
 
 	if container.Description != "" {
 		sb.WriteString(fmt.Sprintf("%s\n\n", container.Description))
 	}
+	writeDiagram(&sb, container.Name, container.DiagramPath, st)
 
 	// Container metadata
 	if container.Technology != "" {
@@ -171,11 +251,103 @@ func (b *Builder) buildContainerMarkdown(_ context.Context, container *entities.
 			sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", comp.Name, desc, tech))
 		}
 		sb.WriteString("\n")
+		if st.github {
+			writeMermaid(&sb, components)
+		}
 	}
 
 	return sb.String(), nil
 }
 
+// heading returns the Markdown heading marker of the given level, capped at 6.
+func heading(level int) string {
+	return strings.Repeat("#", min(level, 6))
+}
+
+// writeDiagram embeds the rendered diagram of an element, given its path
+// relative to the output root, in files that know their way there.
+func writeDiagram(sb *strings.Builder, name, diagramPath string, st style) {
+	if st.root == "" || diagramPath == "" {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("![%s diagram](%s%s)\n\n", name, st.root, filepath.ToSlash(diagramPath)))
+}
+
+// writeChecklist writes a GitHub task list of what is documented about the
+// system and its containers.
+func writeChecklist(sb *strings.Builder, system *entities.System, containers []*entities.Container, st style) {
+	task := func(done bool, text string) {
+		mark := " "
+		if done {
+			mark = "x"
+		}
+		sb.WriteString(fmt.Sprintf("- [%s] %s\n", mark, text))
+	}
+
+	sb.WriteString(fmt.Sprintf("%s Documentation Checklist\n\n", heading(st.level+1)))
+	task(system.Description != "", "System description")
+	task(system.Diagram != nil, "System context diagram")
+	for _, container := range containers {
+		if container == nil {
+			continue
+		}
+		task(container.Description != "", container.Name+" description")
+		if total := len(container.Components); total > 0 {
+			described := 0
+			for _, comp := range container.Components {
+				if comp.Description != "" {
+					described++
+				}
+			}
+			task(described == total, fmt.Sprintf("%s components described (%d/%d)", container.Name, described, total))
+		}
+	}
+	sb.WriteString("\n")
+}
+
+// mermaidIDPattern matches the characters not allowed in mermaid node IDs.
+var mermaidIDPattern = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// writeMermaid writes a mermaid flowchart of the components and the
+// relationships between them, which GitHub renders as a diagram.
+func writeMermaid(sb *strings.Builder, components []*entities.Component) {
+	components = append([]*entities.Component(nil), components...)
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].ID < components[j].ID
+	})
+
+	nodeID := func(id string) string { return "c_" + mermaidIDPattern.ReplaceAllString(id, "_") }
+	label := func(s string) string { return strings.ReplaceAll(s, `"`, "#quot;") }
+
+	siblings := make(map[string]bool, len(components))
+	for _, comp := range components {
+		siblings[comp.ID] = true
+	}
+
+	sb.WriteString("```mermaid\nflowchart LR\n")
+	for _, comp := range components {
+		sb.WriteString(fmt.Sprintf("  %s[\"%s\"]\n", nodeID(comp.ID), label(comp.Name)))
+	}
+	for _, comp := range components {
+		targets := make([]string, 0, len(comp.Relationships))
+		for target := range comp.Relationships {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+		for _, target := range targets {
+			if !siblings[target] {
+				continue
+			}
+			if desc := comp.Relationships[target]; desc != "" {
+				sb.WriteString(fmt.Sprintf("  %s -->|\"%s\"| %s\n", nodeID(comp.ID), label(desc), nodeID(target)))
+			} else {
+				sb.WriteString(fmt.Sprintf("  %s --> %s\n", nodeID(comp.ID), nodeID(target)))
+			}
+		}
+	}
+	sb.WriteString("```\n\n")
+}
+
 // slugify converts a string to a URL-friendly slug.
 func slugify(s string) string {
 	s = strings.ToLower(s)
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
 )
 
 func TestBuilder_BuildMarkdown(t *testing.T) {
@@ -110,6 +111,87 @@ func TestBuilder_BuildSystemMarkdown(t *testing.T) {
 	}
 }
 
+func TestBuilder_BuildMarkdownFiles_PerSystem(t *testing.T) {
+	builder := NewBuilder()
+	ctx := context.Background()
+
+	project, _ := entities.NewProject("Shop")
+	payments, _ := entities.NewSystem("Payments")
+	payments.Description = "Card payments"
+	payments.DiagramPath = "diagrams/payments.svg"
+	api, _ := entities.NewContainer("API")
+	api.DiagramPath = "diagrams/payments_api.svg"
+	payments.AddContainer(api)
+
+	files, err := builder.BuildMarkdownFiles(ctx, project, []*entities.System{payments}, usecases.MarkdownOptions{PerSystem: true})
+	if err != nil {
+		t.Fatalf("BuildMarkdownFiles failed: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("got %d files, want README.md and docs/payments/README.md", len(files))
+	}
+	if index := files["README.md"]; !strings.Contains(index, "- [Payments](docs/payments/README.md) - Card payments") {
+		t.Errorf("README.md does not link the system file:\n%s", index)
+	}
+	page := files["docs/payments/README.md"]
+	checks := []string{
+		"[← Architecture overview](../../README.md)",
+		"# Payments\n",
+		"![Payments diagram](../../diagrams/payments.svg)",
+		"## Containers",
+		"### API",
+		"![API diagram](../../diagrams/payments_api.svg)",
+	}
+	for _, check := range checks {
+		if !strings.Contains(page, check) {
+			t.Errorf("Expected system file to contain %q", check)
+		}
+	}
+	if strings.Contains(page, "```mermaid") || strings.Contains(page, "- [ ]") {
+		t.Error("GitHub-flavored additions without GitHubFlavored")
+	}
+}
+
+func TestBuilder_BuildMarkdownFiles_GitHubFlavored(t *testing.T) {
+	builder := NewBuilder()
+	ctx := context.Background()
+
+	project, _ := entities.NewProject("Shop")
+	sys, _ := entities.NewSystem("Orders")
+	sys.Description = "Order handling"
+	api, _ := entities.NewContainer("API")
+	sys.AddContainer(api)
+	handler, _ := entities.NewComponent("Order Handler")
+	handler.Description = "Accepts orders"
+	handler.Relationships = map[string]string{"order-store": "saves \"orders\"", "payments/api/cards": "charges"}
+	store, _ := entities.NewComponent("Order Store")
+	api.AddComponent(handler)
+	api.AddComponent(store)
+
+	files, err := builder.BuildMarkdownFiles(ctx, project, []*entities.System{sys}, usecases.MarkdownOptions{GitHubFlavored: true})
+	if err != nil {
+		t.Fatalf("BuildMarkdownFiles failed: %v", err)
+	}
+	content, ok := files["README.md"]
+	if !ok || len(files) != 1 {
+		t.Fatalf("got files %v, want only README.md", len(files))
+	}
+	checks := []string{
+		"### Documentation Checklist",
+		"- [x] System description",
+		"- [ ] System context diagram",
+		"- [ ] API description",
+		"- [ ] API components described (1/2)",
+		"```mermaid\nflowchart LR\n  c_order_handler[\"Order Handler\"]\n  c_order_store[\"Order Store\"]\n" +
+			"  c_order_handler -->|\"saves #quot;orders#quot;\"| c_order_store\n```",
+	}
+	for _, check := range checks {
+		if !strings.Contains(content, check) {
+			t.Errorf("Expected markdown to contain %q\n%s", check, content)
+		}
+	}
+}
+
 func TestBuilder_NilProject(t *testing.T) {
 	builder := NewBuilder()
 	ctx := context.Background()
@@ -0,0 +1,56 @@
+package minify
+
+import "strings"
+
+// CSS removes comments and insignificant whitespace from a stylesheet.
+// Strings are preserved verbatim and the last semicolon of each block is dropped.
+func CSS(src string) string {
+	out := make([]byte, 0, len(src))
+	pendingSpace := false
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return string(out)
+			}
+			i += end + 3
+			pendingSpace = true
+
+		case isSpace(c):
+			pendingSpace = true
+
+		default:
+			if pendingSpace && len(out) > 0 && !cssNoSpaceAfter(out[len(out)-1]) && !cssNoSpaceBefore(c) {
+				out = append(out, ' ')
+			}
+			pendingSpace = false
+
+			if c == '"' || c == '\'' {
+				end := quotedEnd(src, i)
+				out = append(out, src[i:end]...)
+				i = end - 1
+				continue
+			}
+			if c == '}' && len(out) > 0 && out[len(out)-1] == ';' {
+				out = out[:len(out)-1]
+			}
+			out = append(out, c)
+		}
+	}
+
+	return string(out)
+}
+
+// cssNoSpaceAfter reports whether whitespace following c is insignificant.
+func cssNoSpaceAfter(c byte) bool {
+	return strings.IndexByte("{};,>:(", c) >= 0
+}
+
+// cssNoSpaceBefore reports whether whitespace preceding c is insignificant.
+// A colon is excluded because "a :hover" and "a:hover" select different elements.
+func cssNoSpaceBefore(c byte) bool {
+	return strings.IndexByte("{};,>)", c) >= 0
+}
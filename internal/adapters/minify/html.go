@@ -0,0 +1,120 @@
+package minify
+
+import "strings"
+
+// blockElements lists elements whose surrounding whitespace does not affect
+// rendering, so whitespace-only text next to them can be dropped.
+var blockElements = map[string]bool{
+	"!doctype": true, "html": true, "head": true, "body": true, "title": true,
+	"meta": true, "link": true, "script": true, "style": true, "noscript": true,
+	"div": true, "p": true, "section": true, "article": true, "aside": true,
+	"header": true, "footer": true, "nav": true, "main": true, "h1": true,
+	"h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "ul": true,
+	"ol": true, "li": true, "dl": true, "dt": true, "dd": true, "table": true,
+	"thead": true, "tbody": true, "tfoot": true, "tr": true, "th": true,
+	"td": true, "form": true, "fieldset": true, "figure": true,
+	"figcaption": true, "hr": true, "br": true, "details": true,
+	"summary": true, "blockquote": true, "svg": true,
+}
+
+// HTML removes comments and collapses whitespace in an HTML document.
+//
+// Runs of whitespace become a single space, and whitespace next to block-level
+// elements is dropped. The contents of pre and textarea elements are kept
+// verbatim; inline style and script contents are minified with CSS and JS.
+// Conditional comments ("<!--[if ...") are preserved.
+func HTML(src string) string {
+	var sb strings.Builder
+	sb.Grow(len(src))
+
+	pendingSpace := false
+	lastWasBlock := true // drop leading whitespace
+
+	for i := 0; i < len(src); {
+		if src[i] != '<' {
+			if isSpace(src[i]) {
+				pendingSpace = true
+				i++
+				continue
+			}
+			if pendingSpace && !lastWasBlock {
+				sb.WriteByte(' ')
+			}
+			pendingSpace = false
+			lastWasBlock = false
+			sb.WriteByte(src[i])
+			i++
+			continue
+		}
+
+		if strings.HasPrefix(src[i:], "<!--") {
+			end := strings.Index(src[i+4:], "-->")
+			next := len(src)
+			if end >= 0 {
+				next = i + 4 + end + 3
+			}
+			if strings.HasPrefix(src[i:], "<!--[if") {
+				sb.WriteString(src[i:next])
+			}
+			i = next
+			continue
+		}
+
+		end := tagEnd(src, i)
+		tag := src[i:end]
+		name := tagName(tag)
+		block := blockElements[name]
+
+		if pendingSpace && !block && !lastWasBlock {
+			sb.WriteByte(' ')
+		}
+		pendingSpace = false
+		lastWasBlock = block
+		sb.WriteString(tag)
+		i = end
+
+		if strings.HasPrefix(tag, "</") || strings.HasSuffix(tag, "/>") {
+			continue
+		}
+
+		// Raw-text elements: copy or minify their contents up to the closing tag.
+		var minifyBody func(string) string
+		switch name {
+		case "pre", "textarea":
+			minifyBody = func(s string) string { return s }
+		case "style":
+			minifyBody = CSS
+		case "script":
+			minifyBody = JS
+			if !isJavaScriptType(tag) {
+				minifyBody = func(s string) string { return s }
+			}
+		default:
+			continue
+		}
+
+		closing := closingTagIndex(src, i, name)
+		if closing < 0 {
+			closing = len(src)
+		}
+		sb.WriteString(minifyBody(src[i:closing]))
+		i = closing
+	}
+
+	return sb.String()
+}
+
+// isJavaScriptType reports whether a script tag holds JavaScript rather than
+// data such as JSON, based on its type attribute.
+func isJavaScriptType(tag string) bool {
+	lower := strings.ToLower(tag)
+	idx := strings.Index(lower, "type=")
+	if idx < 0 {
+		return true
+	}
+	value := strings.Trim(lower[idx+len("type="):], "\"' >/")
+	if end := strings.IndexAny(value, "\"' "); end >= 0 {
+		value = value[:end]
+	}
+	return value == "" || value == "module" || strings.Contains(value, "javascript")
+}
@@ -0,0 +1,76 @@
+package minify
+
+import "strings"
+
+// JS strips comment lines, blank lines and indentation from a script.
+//
+// The transformation is line-based and keeps every line break, so automatic
+// semicolon insertion behaves exactly as in the original source. Lines inside
+// multi-line template literals are copied verbatim.
+func JS(src string) string {
+	var out []string
+	inBlockComment := false
+	inTemplate := false
+
+	for _, raw := range strings.Split(src, "\n") {
+		if inTemplate {
+			out = append(out, raw)
+			inTemplate = endsInTemplate(raw, true)
+			continue
+		}
+
+		line := strings.TrimSpace(raw)
+		if inBlockComment {
+			end := strings.Index(line, "*/")
+			if end < 0 {
+				continue
+			}
+			line = strings.TrimSpace(line[end+2:])
+			inBlockComment = false
+		}
+		for strings.HasPrefix(line, "/*") {
+			end := strings.Index(line[2:], "*/")
+			if end < 0 {
+				inBlockComment = true
+				line = ""
+				break
+			}
+			line = strings.TrimSpace(line[end+4:])
+		}
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		out = append(out, line)
+		inTemplate = endsInTemplate(line, false)
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// endsInTemplate reports whether a template literal is still open at the end
+// of line, given whether one was open at its start. Quoted strings and line
+// comments are skipped so backticks inside them are ignored.
+func endsInTemplate(line string, inTemplate bool) bool {
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if inTemplate {
+			switch c {
+			case '\\':
+				i++
+			case '`':
+				inTemplate = false
+			}
+			continue
+		}
+		switch {
+		case c == '`':
+			inTemplate = true
+		case c == '"' || c == '\'':
+			i = quotedEnd(line, i) - 1
+		case c == '/' && i+1 < len(line) && line[i+1] == '/':
+			return false
+		}
+	}
+	return inTemplate
+}
@@ -0,0 +1,143 @@
+// Package minify provides an asset minifier adapter for generated sites.
+// It implements the AssetMinifier interface by shrinking HTML, CSS, JS and SVG
+// files in place using conservative, dependency-free transformations.
+package minify
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Minifier implements the AssetMinifier interface.
+type Minifier struct{}
+
+// NewMinifier creates a new asset minifier.
+func NewMinifier() *Minifier {
+	return &Minifier{}
+}
+
+// MinifyDir minifies every HTML, CSS, JS and SVG file under dir in place.
+// Files that would not shrink are left untouched. It returns the combined
+// size of the processed files before and after minification.
+func (m *Minifier) MinifyDir(ctx context.Context, dir string) (before, after int64, err error) {
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		minify := minifierFor(path)
+		if minify == nil {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		before += int64(len(content))
+
+		minified := minify(string(content))
+		if len(minified) >= len(content) {
+			after += int64(len(content))
+			return nil
+		}
+		if err := os.WriteFile(path, []byte(minified), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		after += int64(len(minified))
+		return nil
+	})
+	return before, after, err
+}
+
+// minifierFor returns the minification function for a file, or nil if the
+// file type is not minified.
+func minifierFor(path string) func(string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		return HTML
+	case ".css":
+		return CSS
+	case ".js":
+		return JS
+	case ".svg":
+		return SVG
+	default:
+		return nil
+	}
+}
+
+// isSpace reports whether c is an ASCII whitespace character.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// quotedEnd returns the index just past the quoted string starting at
+// src[start], honouring backslash escapes. Unterminated strings run to the end.
+func quotedEnd(src string, start int) int {
+	quote := src[start]
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+	return len(src)
+}
+
+// tagEnd returns the index just past the markup tag starting at src[start],
+// skipping '>' characters inside quoted attribute values.
+func tagEnd(src string, start int) int {
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '"', '\'':
+			i = quotedEnd(src, i) - 1
+		case '>':
+			return i + 1
+		}
+	}
+	return len(src)
+}
+
+// tagName returns the lower-cased element name of a tag such as "<div class=x>"
+// or "</div>", without the leading slash.
+func tagName(tag string) string {
+	name := strings.TrimPrefix(strings.TrimPrefix(tag, "<"), "/")
+	if i := strings.IndexFunc(name, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '/' || r == '>'
+	}); i >= 0 {
+		name = name[:i]
+	}
+	return strings.ToLower(name)
+}
+
+// closingTagIndex returns the index of the closing tag for name at or after
+// start, matched case-insensitively, or -1 if there is none.
+func closingTagIndex(src string, start int, name string) int {
+	for i := start; ; i += 2 {
+		idx := strings.Index(src[i:], "</")
+		if idx < 0 {
+			return -1
+		}
+		i += idx
+		after := i + 2 + len(name)
+		if after > len(src) || !strings.EqualFold(src[i+2:after], name) {
+			continue
+		}
+		if after == len(src) || src[after] == '>' || isSpace(src[after]) {
+			return i
+		}
+	}
+}
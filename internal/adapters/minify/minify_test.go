@@ -0,0 +1,146 @@
+package minify
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCSS(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"comments and whitespace", "/* header */\n.a {\n  color: red;\n  margin: 0 auto;\n}\n", ".a{color:red;margin:0 auto}"},
+		{"descendant pseudo-class", "a :hover { color: red }", "a :hover{color:red}"},
+		{"combinators and lists", ".a > .b , .c { x: 1 }", ".a>.b,.c{x:1}"},
+		{"media query", "@media (max-width: 600px) and (min-width: 1px) { .a { x: 1; } }", "@media (max-width:600px) and (min-width:1px){.a{x:1}}"},
+		{"strings preserved", `.a { content: "a  ;  /* b */" }`, `.a{content:"a  ;  /* b */"}`},
+		{"calc spacing preserved", ".a { width: calc(1px + 2px) }", ".a{width:calc(1px + 2px)}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CSS(tt.in); got != tt.want {
+				t.Errorf("CSS() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJS(t *testing.T) {
+	in := "// header\n(function() {\n\t/* block\n\t   comment */\n\tconst url = 'http://example.com'; // trailing\n\n\tconst tpl = `line one\n    indented`;\n\treturn url;\n})();\n"
+	want := "(function() {\nconst url = 'http://example.com'; // trailing\nconst tpl = `line one\n    indented`;\nreturn url;\n})();"
+
+	if got := JS(in); got != want {
+		t.Errorf("JS() = %q, want %q", got, want)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"block whitespace", "<div>\n  <p>\n    Hello   world\n  </p>\n</div>\n", "<div><p>Hello world</p></div>"},
+		{"inline whitespace kept", "<p><a href=\"#\">one</a> <a href=\"#\">two</a></p>", "<p><a href=\"#\">one</a> <a href=\"#\">two</a></p>"},
+		{"comments removed", "<div><!-- note --><span>x</span></div>", "<div><span>x</span></div>"},
+		{"conditional comment kept", "<!--[if IE]><p>IE</p><![endif]-->", "<!--[if IE]><p>IE</p><![endif]-->"},
+		{"pre preserved", "<pre>\n  a   b\n</pre>", "<pre>\n  a   b\n</pre>"},
+		{"style minified", "<style>\n  .a { color: red; }\n</style>", "<style>.a{color:red}</style>"},
+		{"json script preserved", "<script type=\"application/json\">\n  {\"a\": 1}\n</script>", "<script type=\"application/json\">\n  {\"a\": 1}\n</script>"},
+		{"quoted attribute with >", "<div title=\"a > b\">\n  x\n</div>", "<div title=\"a > b\">x</div>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTML(tt.in); got != tt.want {
+				t.Errorf("HTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSVG(t *testing.T) {
+	in := `<?xml version="1.0" encoding="utf-8"?>
+<!-- generated by d2 -->
+<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10">
+  <metadata><rdf>x</rdf></metadata>
+  <style type="text/css"><![CDATA[
+  .a { fill: red; }
+  ]]></style>
+  <g>
+    <g class="shape"><rect x="1" y="1"/></g>
+    <g id="empty">  </g>
+    <g><g></g></g>
+    <text x="1">Hello <tspan>big</tspan> world</text>
+  </g>
+</svg>`
+	want := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10"><style type="text/css"><![CDATA[ .a{fill:red}]]></style><g class="shape"><rect x="1" y="1"/></g><text x="1">Hello <tspan>big</tspan> world</text></svg>`
+
+	if got := SVG(in); got != want {
+		t.Errorf("SVG() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMinifyDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"index.html":        "<html>\n  <body>\n    <p>Hi</p>\n  </body>\n</html>\n",
+		"styles/style.css":  ".a {\n  color: red;\n}\n",
+		"diagrams/sys.svg":  "<svg>\n  <g>\n    <rect/>\n  </g>\n</svg>\n",
+		"diagrams/sys.d2":   "a -> b\n",
+		"already/small.css": ".a{x:1}",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	before, after, err := NewMinifier().MinifyDir(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("MinifyDir failed: %v", err)
+	}
+	if after >= before {
+		t.Errorf("expected output to shrink, before=%d after=%d", before, after)
+	}
+
+	want := map[string]string{
+		"index.html":        "<html><body><p>Hi</p></body></html>",
+		"styles/style.css":  ".a{color:red}",
+		"diagrams/sys.svg":  "<svg><rect/></svg>",
+		"diagrams/sys.d2":   "a -> b\n",
+		"already/small.css": ".a{x:1}",
+	}
+	for name, content := range want {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != content {
+			t.Errorf("%s = %q, want %q", name, got, content)
+		}
+	}
+}
+
+func TestMinifyDirCancelled(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte("<p> x </p>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, _, err := NewMinifier().MinifyDir(ctx, dir); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
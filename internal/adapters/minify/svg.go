@@ -0,0 +1,120 @@
+package minify
+
+import (
+	"regexp"
+	"strings"
+)
+
+// emptyGroupPattern matches group elements with no children.
+var emptyGroupPattern = regexp.MustCompile(`<g(\s[^<>]*)?(/>|>\s*</g>)`)
+
+// SVG optimises a rendered diagram in the style of SVGO.
+//
+// It removes the XML declaration, doctype, comments and metadata elements,
+// drops whitespace-only text between elements, unwraps attribute-less groups
+// and removes empty groups. Embedded stylesheets are minified with CSS; text and
+// script contents and other CDATA sections are kept verbatim.
+func SVG(src string) string {
+	var sb strings.Builder
+	sb.Grow(len(src))
+
+	// unwrapped records, for each open <g>, whether its tags were dropped.
+	var unwrapped []bool
+
+	for i := 0; i < len(src); {
+		if src[i] != '<' {
+			next := strings.IndexByte(src[i:], '<')
+			if next < 0 {
+				next = len(src) - i
+			}
+			if text := src[i : i+next]; strings.TrimSpace(text) != "" {
+				sb.WriteString(text)
+			}
+			i += next
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(src[i:], "<!--"):
+			i = skipPast(src, i, "-->")
+			continue
+		case strings.HasPrefix(src[i:], "<![CDATA["):
+			end := skipPast(src, i, "]]>")
+			sb.WriteString(src[i:end])
+			i = end
+			continue
+		case strings.HasPrefix(src[i:], "<?"):
+			i = skipPast(src, i, "?>")
+			continue
+		}
+
+		end := tagEnd(src, i)
+		tag := src[i:end]
+		name := tagName(tag)
+		closing := strings.HasPrefix(tag, "</")
+		selfClosing := strings.HasSuffix(tag, "/>")
+		i = end
+
+		switch {
+		case name == "!doctype":
+			continue
+
+		case name == "metadata" && !closing:
+			if !selfClosing {
+				i = skipPast(src, i, "</metadata>")
+			}
+			continue
+
+		case name == "g" && closing:
+			if n := len(unwrapped); n > 0 {
+				drop := unwrapped[n-1]
+				unwrapped = unwrapped[:n-1]
+				if drop {
+					continue
+				}
+			}
+
+		case name == "g" && !selfClosing:
+			bare := tag == "<g>"
+			unwrapped = append(unwrapped, bare)
+			if bare {
+				continue
+			}
+
+		case (name == "text" || name == "style" || name == "script") && !closing && !selfClosing:
+			body := closingTagIndex(src, i, name)
+			if body < 0 {
+				body = len(src)
+			}
+			sb.WriteString(tag)
+			if name == "style" {
+				sb.WriteString(CSS(src[i:body]))
+			} else {
+				sb.WriteString(src[i:body])
+			}
+			i = body
+			continue
+		}
+
+		sb.WriteString(tag)
+	}
+
+	out := sb.String()
+	for {
+		pruned := emptyGroupPattern.ReplaceAllString(out, "")
+		if pruned == out {
+			return out
+		}
+		out = pruned
+	}
+}
+
+// skipPast returns the index just past the first occurrence of marker at or
+// after start, or the end of src if marker does not occur.
+func skipPast(src string, start int, marker string) int {
+	idx := strings.Index(src[start:], marker)
+	if idx < 0 {
+		return len(src)
+	}
+	return start + idx + len(marker)
+}
@@ -0,0 +1,154 @@
+// Package notify provides the channels that tell element owners about
+// architecture changes in their area: a JSON webhook and email over SMTP. The
+// channels are configured in the [notifications] section of loko.toml; SMTP
+// credentials are read from the environment, never from loko.toml.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// Environment variables holding SMTP credentials.
+const (
+	EnvSMTPUser     = "LOKO_SMTP_USER"
+	EnvSMTPPassword = "LOKO_SMTP_PASSWORD"
+)
+
+// Ensure the notifiers implement usecases.ChangeNotifier interface.
+var (
+	_ usecases.ChangeNotifier = (*WebhookNotifier)(nil)
+	_ usecases.ChangeNotifier = (*EmailNotifier)(nil)
+)
+
+// WebhookNotifier posts each notification as JSON to a webhook URL.
+//
+// The payload is the notification with an added "text" field holding its
+// plain-text rendering, which chat services such as Slack and Mattermost
+// display as the message.
+type WebhookNotifier struct {
+	client *http.Client
+	url    string
+}
+
+// NewWebhookNotifier creates a WebhookNotifier posting to url.
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		client: &http.Client{Timeout: 30 * time.Second},
+		url:    url,
+	}
+}
+
+// webhookPayload is the JSON body posted to the webhook.
+type webhookPayload struct {
+	usecases.OwnerNotification
+	Text string `json:"text"`
+}
+
+// Notify posts notification to the webhook.
+func (w *WebhookNotifier) Notify(ctx context.Context, notification usecases.OwnerNotification) error {
+	body, err := json.Marshal(webhookPayload{
+		OwnerNotification: notification,
+		Text:              usecases.RenderOwnerNotification(notification),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("POST webhook failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("POST webhook returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// EmailNotifier emails each notification to its owner through an SMTP server.
+// Owners that are not email addresses, such as team names, are skipped.
+type EmailNotifier struct {
+	addr     string
+	from     string
+	auth     smtp.Auth
+	sendMail func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailNotifier creates an EmailNotifier sending from the address from
+// through the SMTP server at addr ("host:port"). Empty user disables
+// authentication.
+func NewEmailNotifier(addr, from, user, password string) *EmailNotifier {
+	n := &EmailNotifier{addr: addr, from: from, sendMail: smtp.SendMail}
+	if user != "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			host = addr
+		}
+		n.auth = smtp.PlainAuth("", user, password, host)
+	}
+	return n
+}
+
+// Notify emails notification to its owner when the owner is an address.
+func (e *EmailNotifier) Notify(_ context.Context, notification usecases.OwnerNotification) error {
+	to, err := mail.ParseAddress(notification.Owner)
+	if err != nil {
+		return nil
+	}
+	if err := e.sendMail(e.addr, e.auth, e.from, []string{to.Address}, emailMessage(e.from, to, notification)); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", to.Address, err)
+	}
+	return nil
+}
+
+// emailMessage builds the plain-text email for notification.
+func emailMessage(from string, to *mail.Address, notification usecases.OwnerNotification) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to.String())
+	fmt.Fprintf(&b, "Subject: Architecture changes since %s (%d)\r\n", notification.BaseRef, len(notification.Changes))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(usecases.RenderOwnerNotification(notification), "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// NewNotifiers returns the channels configured by the [notifications] section
+// of loko.toml, reading SMTP credentials with getenv.
+func NewNotifiers(config *entities.ProjectConfig, getenv func(string) string) ([]usecases.ChangeNotifier, error) {
+	var notifiers []usecases.ChangeNotifier
+	if config != nil && config.NotifyWebhookURL != "" {
+		notifiers = append(notifiers, NewWebhookNotifier(config.NotifyWebhookURL))
+	}
+	if config != nil && config.NotifySMTPHost != "" {
+		if config.NotifyEmailFrom == "" {
+			return nil, fmt.Errorf("notification sender is not set; set email_from in [notifications]")
+		}
+		notifiers = append(notifiers, NewEmailNotifier(config.NotifySMTPHost, config.NotifyEmailFrom, getenv(EnvSMTPUser), getenv(EnvSMTPPassword)))
+	}
+	if len(notifiers) == 0 {
+		return nil, fmt.Errorf("notifications are not configured; set webhook_url or smtp_host in [notifications]")
+	}
+	return notifiers, nil
+}
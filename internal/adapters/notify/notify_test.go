@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+func testNotification(owner string) usecases.OwnerNotification {
+	return usecases.OwnerNotification{
+		Owner:   owner,
+		BaseRef: "origin/main",
+		Changes: []usecases.ElementChange{{Kind: usecases.ChangeAdded, Type: "container", Element: "payments/api"}},
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var payload map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if payload["owner"] == "nobody" {
+			http.Error(w, "no such channel", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	notifier := NewWebhookNotifier(srv.URL)
+	if err := notifier.Notify(context.Background(), testNotification("payments-team")); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if payload["owner"] != "payments-team" || payload["base_ref"] != "origin/main" || len(payload["changes"].([]any)) != 1 {
+		t.Errorf("payload = %v", payload)
+	}
+	if text, _ := payload["text"].(string); !strings.Contains(text, "+ container payments/api") {
+		t.Errorf("text = %q", text)
+	}
+
+	if err := notifier.Notify(context.Background(), testNotification("nobody")); err == nil || !strings.Contains(err.Error(), "no such channel") {
+		t.Errorf("expected the webhook's error, got %v", err)
+	}
+}
+
+func TestEmailNotifier(t *testing.T) {
+	var sent []string
+	notifier := NewEmailNotifier("smtp.example.com:587", "loko@example.com", "", "")
+	notifier.sendMail = func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+		sent = append(sent, addr+" "+from+" "+strings.Join(to, ",")+"\n"+string(msg))
+		return nil
+	}
+
+	ctx := context.Background()
+	if err := notifier.Notify(ctx, testNotification("payments-team")); err != nil {
+		t.Fatalf("Notify(team) error = %v", err)
+	}
+	if err := notifier.Notify(ctx, testNotification("Alice <alice@example.com>")); err != nil {
+		t.Fatalf("Notify(address) error = %v", err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("sent %d emails, want 1 (teams are skipped)", len(sent))
+	}
+	for _, want := range []string{
+		"smtp.example.com:587 loko@example.com alice@example.com\n",
+		"To: \"Alice\" <alice@example.com>\r\n",
+		"Subject: Architecture changes since origin/main (1)\r\n",
+		"  + container payments/api\r\n",
+	} {
+		if !strings.Contains(sent[0], want) {
+			t.Errorf("email missing %q:\n%s", want, sent[0])
+		}
+	}
+}
+
+func TestNewNotifiers(t *testing.T) {
+	getenv := func(string) string { return "" }
+	config := entities.DefaultProjectConfig()
+	if _, err := NewNotifiers(config, getenv); err == nil {
+		t.Error("expected an error without channels")
+	}
+
+	config.NotifySMTPHost = "smtp.example.com:587"
+	if _, err := NewNotifiers(config, getenv); err == nil || !strings.Contains(err.Error(), "email_from") {
+		t.Errorf("expected a missing sender error, got %v", err)
+	}
+
+	config.NotifyEmailFrom = "loko@example.com"
+	config.NotifyWebhookURL = "https://hooks.example.com/loko"
+	notifiers, err := NewNotifiers(config, getenv)
+	if err != nil || len(notifiers) != 2 {
+		t.Errorf("NewNotifiers() = %d notifiers, %v", len(notifiers), err)
+	}
+}
@@ -0,0 +1,88 @@
+// Package oras provides an ArtifactRegistry adapter that shells out to the
+// oras CLI (https://oras.land). Registry credentials are shared with docker
+// and `oras login`, so documentation can be pushed wherever images are.
+package oras
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// ErrOrasNotAvailable indicates the oras binary is not installed.
+var ErrOrasNotAvailable = fmt.Errorf("oras is not installed or not in PATH")
+
+// Ensure Registry implements usecases.ArtifactRegistry interface.
+var _ usecases.ArtifactRegistry = (*Registry)(nil)
+
+// Registry implements the ArtifactRegistry interface by shelling out to oras.
+type Registry struct {
+	orasPath  string // Path to oras binary
+	plainHTTP bool   // Talk to the registry over HTTP, e.g. a local test registry
+}
+
+// NewRegistry creates an oras-backed artifact registry.
+func NewRegistry() *Registry {
+	orasPath, _ := exec.LookPath("oras")
+	return &Registry{orasPath: orasPath}
+}
+
+// SetPlainHTTP makes oras connect without TLS, for local registries.
+func (r *Registry) SetPlainHTTP(plainHTTP bool) {
+	r.plainHTTP = plainHTTP
+}
+
+// IsAvailable checks if the oras binary is installed and accessible.
+func (r *Registry) IsAvailable() bool {
+	return r.orasPath != ""
+}
+
+// Push uploads the layers in dir as an artifact tagged ref and returns the
+// manifest digest reported by oras.
+func (r *Registry) Push(ctx context.Context, ref, artifactType, dir string, layers []usecases.ArtifactLayer) (string, error) {
+	args := []string{"push", ref, "--artifact-type", artifactType}
+	for _, layer := range layers {
+		args = append(args, layer.Path+":"+layer.MediaType)
+	}
+
+	out, err := r.run(ctx, dir, args...)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if digest, ok := strings.CutPrefix(strings.TrimSpace(line), "Digest:"); ok {
+			return strings.TrimSpace(digest), nil
+		}
+	}
+	return "", nil
+}
+
+// Pull downloads the layers of the artifact tagged ref into dir.
+func (r *Registry) Pull(ctx context.Context, ref, dir string) error {
+	_, err := r.run(ctx, dir, "pull", ref, "--output", dir)
+	return err
+}
+
+// run executes oras in dir with the given arguments and returns its output.
+func (r *Registry) run(ctx context.Context, dir string, args ...string) (string, error) {
+	if !r.IsAvailable() {
+		return "", ErrOrasNotAvailable
+	}
+	if r.plainHTTP {
+		args = append(args, "--plain-http")
+	}
+
+	cmd := exec.CommandContext(ctx, r.orasPath, args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("oras %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,85 @@
+package oras
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// fakeOras writes a stand-in oras binary that records its arguments and
+// working directory and prints a push digest, so the adapter can be tested
+// without a registry.
+func fakeOras(t *testing.T) (orasPath, argsFile string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake oras script requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	argsFile = filepath.Join(dir, "args")
+	orasPath = filepath.Join(dir, "oras")
+	script := `#!/bin/sh
+echo "$(pwd) $@" > "` + argsFile + `"
+case "$1" in
+push) echo "Pushed [registry] $2"; echo "ArtifactType: $4"; echo "Digest: sha256:abc123" ;;
+pull) [ "$2" = "ghcr.io/acme/missing:v1" ] && { echo "Error: not found" >&2; exit 1; }; echo "Pulled $2" ;;
+esac
+`
+	if err := os.WriteFile(orasPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return orasPath, argsFile
+}
+
+func TestRegistryPush(t *testing.T) {
+	orasPath, argsFile := fakeOras(t)
+	r := &Registry{orasPath: orasPath}
+	dir, _ := filepath.EvalSymlinks(t.TempDir())
+
+	digest, err := r.Push(context.Background(), "ghcr.io/acme/docs:v1", "application/vnd.loko.docs.v1", dir, []usecases.ArtifactLayer{
+		{Path: "site.tar.gz", MediaType: "application/vnd.loko.site.v1.tar+gzip"},
+		{Path: "loko-model.json", MediaType: "application/vnd.loko.model.v1+json"},
+	})
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if digest != "sha256:abc123" {
+		t.Errorf("digest = %q", digest)
+	}
+
+	want := dir + " push ghcr.io/acme/docs:v1 --artifact-type application/vnd.loko.docs.v1 " +
+		"site.tar.gz:application/vnd.loko.site.v1.tar+gzip loko-model.json:application/vnd.loko.model.v1+json"
+	if args, _ := os.ReadFile(argsFile); strings.TrimSpace(string(args)) != want {
+		t.Errorf("push args = %q, want %q", args, want)
+	}
+}
+
+func TestRegistryPull(t *testing.T) {
+	orasPath, argsFile := fakeOras(t)
+	r := &Registry{orasPath: orasPath}
+	r.SetPlainHTTP(true)
+	dir, _ := filepath.EvalSymlinks(t.TempDir())
+
+	if err := r.Pull(context.Background(), "localhost:5000/docs:v1", dir); err != nil {
+		t.Fatalf("Pull failed: %v", err)
+	}
+	want := dir + " pull localhost:5000/docs:v1 --output " + dir + " --plain-http"
+	if args, _ := os.ReadFile(argsFile); strings.TrimSpace(string(args)) != want {
+		t.Errorf("pull args = %q, want %q", args, want)
+	}
+
+	if err := r.Pull(context.Background(), "ghcr.io/acme/missing:v1", dir); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected oras's error message, got %v", err)
+	}
+}
+
+func TestRegistryNotAvailable(t *testing.T) {
+	r := &Registry{}
+	if _, err := r.Push(context.Background(), "ghcr.io/acme/docs:v1", "", t.TempDir(), nil); err != ErrOrasNotAvailable {
+		t.Errorf("expected ErrOrasNotAvailable, got %v", err)
+	}
+}
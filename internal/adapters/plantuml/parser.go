@@ -0,0 +1,498 @@
+// Package plantuml reads architecture models from PlantUML C4 diagrams.
+package plantuml
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// Ensure Parser implements usecases.ExternalModelParser interface.
+var _ usecases.ExternalModelParser = (*Parser)(nil)
+
+// Extensions lists the file extensions of PlantUML sources.
+var Extensions = []string{".puml", ".plantuml", ".pu", ".wsd"}
+
+// macroPattern matches a macro call such as `Container(api, "API", "Go")`,
+// optionally opening a boundary with "{".
+var macroPattern = regexp.MustCompile(`^(\w+)\s*\((.*)\)\s*(\{)?$`)
+
+// elementKind is the C4 level of a PlantUML element.
+type elementKind int
+
+const (
+	kindPerson elementKind = iota
+	kindSystem
+	kindContainer
+	kindComponent
+)
+
+// element is a Person, System, Container or Component macro, or the system or
+// container a boundary stands for, merged across files by alias.
+type element struct {
+	kind        elementKind
+	alias       string
+	label       string
+	description string
+	technology  string
+	tags        []string
+	external    bool
+	parent      string // Alias of the enclosing boundary
+	file        string // First file declaring the element
+}
+
+// relation is a Rel macro.
+type relation struct {
+	from, to    string
+	label       string
+	technology  string
+	file        string
+	line        int
+	bidirection bool
+}
+
+// Parser reads the C4-PlantUML macros of a directory of diagrams:
+//
+//   - System, SystemDb, SystemQueue and their _Ext variants become systems,
+//     external for _Ext.
+//   - Container and Component macros become the containers and components of
+//     the System_Boundary or Container_Boundary they are declared in.
+//     A boundary stands for the system or container with its alias, or else
+//     its label, so a container diagram can detail a system of a context
+//     diagram.
+//   - Rel, BiRel and their directional variants become relationships.
+//     Persons are not modelled by loko: they become key users of the systems
+//     they relate to.
+//
+// Other macros, such as styles and layout options, and !include directives
+// are ignored. Elements a model cannot place, such as a container outside any
+// system boundary, are reported as warnings.
+type Parser struct{}
+
+// NewParser creates a new Parser instance.
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// ParseModel reads the PlantUML files under dir, or the file dir, into a
+// model without a project: it is imported into an existing one.
+func (p *Parser) ParseModel(ctx context.Context, dir string) (*usecases.ExternalModel, error) {
+	files, err := sourceFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no PlantUML files (%s) found in %s", strings.Join(Extensions, ", "), dir)
+	}
+
+	r := &reader{elements: make(map[string]*element)}
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := r.readFile(file); err != nil {
+			return nil, err
+		}
+	}
+	return r.model(), nil
+}
+
+// sourceFiles returns the PlantUML files under path, sorted.
+func sourceFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	var files []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && slices.Contains(Extensions, strings.ToLower(filepath.Ext(p))) {
+			files = append(files, p)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// reader accumulates the elements and relations of the files it reads.
+type reader struct {
+	elements  map[string]*element // By alias
+	order     []string            // Aliases in declaration order
+	relations []relation
+	warnings  []string
+}
+
+// readFile reads the macros of one file.
+func (r *reader) readFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	var (
+		boundaries []string // Aliases of the open boundaries; "" for a grouping one
+		pending    *string  // Boundary whose "{" is on the next line
+		inComment  bool
+	)
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if inComment {
+			if _, after, ok := strings.Cut(line, "'/"); ok {
+				inComment = false
+				line = strings.TrimSpace(after)
+			} else {
+				continue
+			}
+		}
+		if strings.HasPrefix(line, "/'") {
+			if !strings.Contains(line[2:], "'/") {
+				inComment = true
+			}
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "'") || strings.HasPrefix(line, "!") || strings.HasPrefix(line, "@") {
+			continue
+		}
+
+		switch {
+		case line == "{":
+			if pending != nil {
+				boundaries = append(boundaries, *pending)
+				pending = nil
+			}
+			continue
+		case strings.HasPrefix(line, "}"):
+			if len(boundaries) > 0 {
+				boundaries = boundaries[:len(boundaries)-1]
+			}
+			continue
+		}
+
+		m := macroPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		macro, args, opens := m[1], splitArgs(m[2]), m[3] == "{"
+		parent := ""
+		if len(boundaries) > 0 {
+			parent = boundaries[len(boundaries)-1]
+		}
+
+		if strings.HasSuffix(macro, "Boundary") {
+			alias := r.boundary(macro, args, parent, path)
+			if opens {
+				boundaries = append(boundaries, alias)
+			} else {
+				pending = &alias
+			}
+			continue
+		}
+		if strings.HasPrefix(macro, "Rel") || strings.HasPrefix(macro, "BiRel") {
+			r.relation(macro, args, path, lineNo)
+			continue
+		}
+		if kind, ok := elementKinds(macro); ok {
+			r.element(kind, macro, args, parent, path)
+		}
+	}
+	return scanner.Err()
+}
+
+// elementKinds returns the kind of element declared by macro.
+func elementKinds(macro string) (elementKind, bool) {
+	switch {
+	case strings.HasPrefix(macro, "Person"):
+		return kindPerson, true
+	case strings.HasPrefix(macro, "System"):
+		return kindSystem, true
+	case strings.HasPrefix(macro, "Container"):
+		return kindContainer, true
+	case strings.HasPrefix(macro, "Component"):
+		return kindComponent, true
+	}
+	return 0, false
+}
+
+// element records an element macro, merging it with an earlier declaration of
+// the same alias.
+func (r *reader) element(kind elementKind, macro string, args []string, parent, file string) {
+	positional, named := splitNamed(args)
+	if len(positional) < 2 {
+		return
+	}
+	e := &element{
+		kind:     kind,
+		alias:    positional[0],
+		label:    positional[1],
+		external: strings.Contains(macro, "_Ext"),
+		parent:   parent,
+		file:     file,
+	}
+	switch kind {
+	case kindPerson, kindSystem:
+		e.description = arg(positional, 2, named["descr"])
+	default:
+		e.technology = arg(positional, 2, named["techn"])
+		e.description = arg(positional, 3, named["descr"])
+	}
+	if tags := named["tags"]; tags != "" {
+		e.tags = strings.Split(tags, "+")
+	}
+	r.merge(e)
+}
+
+// boundary records a System_Boundary or Container_Boundary as the element it
+// stands for and returns the alias enclosed elements belong to. Other
+// boundaries group elements without standing for one and return parent.
+func (r *reader) boundary(macro string, args []string, parent, file string) string {
+	positional, _ := splitNamed(args)
+	if len(positional) < 2 {
+		return parent
+	}
+	var kind elementKind
+	switch macro {
+	case "System_Boundary":
+		kind = kindSystem
+	case "Container_Boundary":
+		kind = kindContainer
+	default:
+		return parent
+	}
+	alias, label := positional[0], positional[1]
+	if _, ok := r.elements[alias]; !ok {
+		// A boundary named like an element of another diagram stands for it
+		for _, other := range r.order {
+			if e := r.elements[other]; e.kind == kind && entities.NormalizeName(e.label) == entities.NormalizeName(label) {
+				return other
+			}
+		}
+	}
+	r.merge(&element{kind: kind, alias: alias, label: label, parent: parent, file: file})
+	return alias
+}
+
+// merge adds e, or completes the element with its alias with the fields it
+// lacks.
+func (r *reader) merge(e *element) {
+	existing, ok := r.elements[e.alias]
+	if !ok {
+		r.elements[e.alias] = e
+		r.order = append(r.order, e.alias)
+		return
+	}
+	if existing.description == "" {
+		existing.description = e.description
+	}
+	if existing.technology == "" {
+		existing.technology = e.technology
+	}
+	if existing.parent == "" {
+		existing.parent = e.parent
+	}
+	existing.external = existing.external || e.external
+	for _, tag := range e.tags {
+		if !slices.Contains(existing.tags, tag) {
+			existing.tags = append(existing.tags, tag)
+		}
+	}
+}
+
+// relation records a Rel macro. Rel_Back relates its elements the other way.
+func (r *reader) relation(macro string, args []string, file string, line int) {
+	positional, named := splitNamed(args)
+	if len(positional) < 2 {
+		return
+	}
+	rel := relation{
+		from:        positional[0],
+		to:          positional[1],
+		label:       arg(positional, 2, named["label"]),
+		technology:  arg(positional, 3, named["techn"]),
+		file:        file,
+		line:        line,
+		bidirection: strings.HasPrefix(macro, "BiRel"),
+	}
+	if strings.HasPrefix(macro, "Rel_Back") {
+		rel.from, rel.to = rel.to, rel.from
+	}
+	r.relations = append(r.relations, rel)
+}
+
+// model places the elements read in the C4 hierarchy.
+func (r *reader) model() *usecases.ExternalModel {
+	model := &usecases.ExternalModel{}
+
+	systems := make(map[string]*entities.System)       // By alias
+	containers := make(map[string]*entities.Container) // By alias
+	ids := make(map[string]string)                     // Qualified ID by alias
+	systemOf := make(map[string]string)                // System alias by alias
+
+	for _, alias := range r.order {
+		e := r.elements[alias]
+		if e.kind != kindSystem {
+			continue
+		}
+		system, err := entities.NewSystem(e.label)
+		if err != nil {
+			r.warn(e.file, "system %q: %v", e.label, err)
+			continue
+		}
+		system.Description = e.description
+		system.External = e.external
+		system.Tags = append(system.Tags, e.tags...)
+		systems[alias], ids[alias], systemOf[alias] = system, system.ID, alias
+		model.Systems = append(model.Systems, system)
+	}
+
+	for _, alias := range r.order {
+		e := r.elements[alias]
+		if e.kind != kindContainer {
+			continue
+		}
+		system := systems[e.parent]
+		if system == nil {
+			r.warn(e.file, "container %q is not inside a System_Boundary; skipped", e.label)
+			continue
+		}
+		container, err := entities.NewContainer(e.label)
+		if err != nil {
+			r.warn(e.file, "container %q: %v", e.label, err)
+			continue
+		}
+		container.Description = e.description
+		container.Technology = e.technology
+		container.Tags = append(container.Tags, e.tags...)
+		if err := system.AddContainer(container); err != nil {
+			r.warn(e.file, "container %q: %v", e.label, err)
+			continue
+		}
+		containers[alias] = container
+		ids[alias] = entities.QualifiedNodeID("container", system.ID, container.ID, "")
+		systemOf[alias] = e.parent
+	}
+
+	for _, alias := range r.order {
+		e := r.elements[alias]
+		if e.kind != kindComponent {
+			continue
+		}
+		container := containers[e.parent]
+		if container == nil {
+			r.warn(e.file, "component %q is not inside a Container_Boundary; skipped", e.label)
+			continue
+		}
+		component, err := entities.NewComponent(e.label)
+		if err != nil {
+			r.warn(e.file, "component %q: %v", e.label, err)
+			continue
+		}
+		component.Description = e.description
+		component.Technology = e.technology
+		component.Tags = append(component.Tags, e.tags...)
+		if err := container.AddComponent(component); err != nil {
+			r.warn(e.file, "component %q: %v", e.label, err)
+			continue
+		}
+		systemAlias := systemOf[e.parent]
+		ids[alias] = entities.QualifiedNodeID("component", systems[systemAlias].ID, container.ID, component.ID)
+		systemOf[alias] = systemAlias
+	}
+
+	for _, rel := range r.relations {
+		from, to := r.elements[rel.from], r.elements[rel.to]
+		switch {
+		case from != nil && from.kind == kindPerson:
+			if system := systems[systemOf[rel.to]]; system != nil && !slices.Contains(system.KeyUsers, from.label) {
+				system.KeyUsers = append(system.KeyUsers, from.label)
+			}
+			continue
+		case to != nil && to.kind == kindPerson:
+			continue
+		}
+		source, sourceOK := ids[rel.from]
+		target, targetOK := ids[rel.to]
+		if !sourceOK || !targetOK {
+			r.warn(fmt.Sprintf("%s:%d", rel.file, rel.line), "relationship %s -> %s relates an element that was not imported; skipped", rel.from, rel.to)
+			continue
+		}
+		model.Relationships = append(model.Relationships, usecases.ExternalRelationship{
+			Source:        source,
+			Target:        target,
+			Description:   rel.label,
+			Technology:    rel.technology,
+			Bidirectional: rel.bidirection,
+		})
+	}
+
+	model.Warnings = r.warnings
+	return model
+}
+
+// warn records a warning about where.
+func (r *reader) warn(where, format string, args ...any) {
+	r.warnings = append(r.warnings, where+": "+fmt.Sprintf(format, args...))
+}
+
+// splitArgs splits the arguments of a macro at the commas outside quotes and
+// unquotes them.
+func splitArgs(s string) []string {
+	var (
+		args    []string
+		current strings.Builder
+		quoted  bool
+	)
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			args = append(args, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" || len(args) > 0 {
+		args = append(args, rest)
+	}
+	return args
+}
+
+// splitNamed separates the positional arguments from the named ones, such as
+// $tags="db", returned by name without the "$".
+func splitNamed(args []string) ([]string, map[string]string) {
+	var positional []string
+	named := make(map[string]string)
+	for _, a := range args {
+		if name, value, ok := strings.Cut(a, "="); ok && strings.HasPrefix(name, "$") {
+			named[strings.TrimSpace(strings.TrimPrefix(name, "$"))] = strings.TrimSpace(value)
+			continue
+		}
+		positional = append(positional, a)
+	}
+	return positional, named
+}
+
+// arg returns the positional argument i, or fallback when there is none.
+func arg(args []string, i int, fallback string) string {
+	if i < len(args) && args[i] != "" {
+		return args[i]
+	}
+	return fallback
+}
@@ -0,0 +1,131 @@
+package plantuml
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+const contextDiagram = `@startuml
+!include <C4/C4_Context>
+' The shop and its neighbours
+Person(customer, "Customer", "Buys things")
+System(shop, "Online Shop", "Sells things", $tags="core")
+System_Ext(stripe, "Stripe", "Takes card payments")
+/' A multi-line
+   comment: System(ghost, "Ghost") '/
+Rel(customer, shop, "Orders from")
+Rel(shop, stripe, "Charges cards", "HTTPS")
+SHOW_LEGEND()
+@enduml
+`
+
+const containerDiagram = `@startuml
+!include <C4/C4_Container>
+System_Boundary(b1, "Online Shop") {
+  Container(web, "Web App", "Go", "Serves pages, and the API")
+  ContainerDb(db, "Orders DB", "PostgreSQL", "Stores orders")
+  Container_Boundary(webparts, "Web App")
+  {
+    Component(cart, "Cart", "Go")
+  }
+}
+Container(orphan, "Orphan", "Go")
+Rel_Back(db, web, "Reads", $techn="SQL")
+BiRel(web, stripe, "Talks to")
+Rel(web, missing, "Calls")
+@enduml
+`
+
+func writeDiagrams(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"context.puml":               contextDiagram,
+		"nested/containers.plantuml": containerDiagram,
+		"README.md":                  "System(readme, \"Not a diagram\")",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestParserParseModel(t *testing.T) {
+	model, err := NewParser().ParseModel(context.Background(), writeDiagrams(t))
+	if err != nil {
+		t.Fatalf("ParseModel failed: %v", err)
+	}
+
+	if len(model.Systems) != 2 {
+		t.Fatalf("expected 2 systems, got %d", len(model.Systems))
+	}
+	shop, stripe := model.Systems[0], model.Systems[1]
+	if shop.ID != "online-shop" || shop.Description != "Sells things" || !slices.Equal(shop.Tags, []string{"core"}) {
+		t.Errorf("shop = %+v", shop)
+	}
+	if !slices.Equal(shop.KeyUsers, []string{"Customer"}) {
+		t.Errorf("customer should be a key user of the shop, got %v", shop.KeyUsers)
+	}
+	if stripe.ID != "stripe" || !stripe.External {
+		t.Errorf("stripe should be external, got %+v", stripe)
+	}
+
+	// The boundary of the container diagram stands for the shop of the context one
+	if len(shop.Containers) != 2 {
+		t.Fatalf("expected the shop to hold 2 containers, got %v", shop.Containers)
+	}
+	web := shop.Containers["web-app"]
+	if web == nil || web.Technology != "Go" || web.Description != "Serves pages, and the API" {
+		t.Errorf("web = %+v", web)
+	}
+	if db := shop.Containers["orders-db"]; db == nil || db.Technology != "PostgreSQL" {
+		t.Errorf("db = %+v", db)
+	}
+	if web != nil && web.Components["cart"] == nil {
+		t.Errorf("cart should be a component of the web container, got %v", web.Components)
+	}
+
+	want := []usecases.ExternalRelationship{
+		{Source: "online-shop", Target: "stripe", Description: "Charges cards", Technology: "HTTPS"},
+		{Source: "online-shop/web-app", Target: "online-shop/orders-db", Description: "Reads", Technology: "SQL"},
+		{Source: "online-shop/web-app", Target: "stripe", Description: "Talks to", Bidirectional: true},
+	}
+	if !slices.Equal(model.Relationships, want) {
+		t.Errorf("relationships = %+v\nwant %+v", model.Relationships, want)
+	}
+
+	warnings := strings.Join(model.Warnings, "\n")
+	for _, w := range []string{`container "Orphan" is not inside a System_Boundary`, "relationship web -> missing"} {
+		if !strings.Contains(warnings, w) {
+			t.Errorf("warnings missing %q:\n%s", w, warnings)
+		}
+	}
+}
+
+func TestParserParseModelErrors(t *testing.T) {
+	if _, err := NewParser().ParseModel(context.Background(), t.TempDir()); err == nil {
+		t.Error("expected error for a directory without diagrams")
+	}
+	if _, err := NewParser().ParseModel(context.Background(), filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected error for a missing path")
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	got := splitArgs(`web, "Web, API", "Go", $tags="a+b"`)
+	want := []string{"web", "Web, API", "Go", "$tags=a+b"}
+	if !slices.Equal(got, want) {
+		t.Errorf("splitArgs = %q, want %q", got, want)
+	}
+}
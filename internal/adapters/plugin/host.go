@@ -0,0 +1,288 @@
+// Package plugin provides adapters for loko plugins: a store that installs and
+// discovers plugins under the XDG data directory, and a host that runs plugin
+// executables as subprocesses speaking JSON-RPC 2.0 over stdin/stdout, one
+// message per line. Plugins can be written in any language.
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// EnvProtocol is set in a plugin's environment to the protocol version loko
+// speaks, so an executable can tell it was started by loko.
+const EnvProtocol = "LOKO_PLUGIN_PROTOCOL"
+
+// methodShutdown is the notification sent to a plugin before its stdin is
+// closed.
+const methodShutdown = "shutdown"
+
+// Ensure Host implements usecases.PluginHost interface.
+var _ usecases.PluginHost = (*Host)(nil)
+
+// Info is the result of the initialize call: the plugin describing itself.
+type Info struct {
+	Name            string                      `json:"name"`
+	Version         string                      `json:"version,omitempty"`
+	Description     string                      `json:"description,omitempty"`
+	Capabilities    []entities.PluginCapability `json:"capabilities"`
+	ProtocolVersion int                         `json:"protocolVersion"`
+}
+
+// RPCError is an error returned by a plugin.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// Host implements the PluginHost interface. Each plugin is started on its
+// first call and kept running for later calls until Close.
+type Host struct {
+	stderr io.Writer // Receives the plugins' stderr; nil discards it
+
+	mu       sync.Mutex
+	sessions map[string]*session // Keyed by plugin executable path
+}
+
+// session is a running plugin process.
+type session struct {
+	mu     sync.Mutex // Serializes requests; the protocol has one in flight at a time
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	nextID int
+	info   Info
+}
+
+// NewHost creates a plugin host that passes the plugins' stderr through to
+// loko's stderr.
+func NewHost() *Host {
+	return &Host{stderr: os.Stderr, sessions: make(map[string]*session)}
+}
+
+// SetStderr sets where the plugins' stderr goes; nil discards it.
+func (h *Host) SetStderr(w io.Writer) {
+	h.stderr = w
+}
+
+// Executable returns the absolute path of plugin's executable.
+func Executable(plugin *entities.PluginManifest) string {
+	if filepath.IsAbs(plugin.Executable) {
+		return plugin.Executable
+	}
+	return filepath.Join(plugin.Dir, plugin.Executable)
+}
+
+// Initialize starts plugin, if it is not running yet, and returns the
+// description it gave in response to initialize.
+func (h *Host) Initialize(ctx context.Context, plugin *entities.PluginManifest) (*Info, error) {
+	s, err := h.session(ctx, plugin)
+	if err != nil {
+		return nil, err
+	}
+	info := s.info
+	return &info, nil
+}
+
+// Call invokes method on plugin with params and decodes the result into
+// result, which may be nil to ignore it.
+func (h *Host) Call(ctx context.Context, plugin *entities.PluginManifest, method string, params, result any) error {
+	s, err := h.session(ctx, plugin)
+	if err != nil {
+		return err
+	}
+	if err := s.call(ctx, method, params, result); err != nil {
+		if _, ok := err.(*RPCError); !ok {
+			// The process is unusable after a transport error; restart it
+			// on the next call.
+			h.drop(Executable(plugin), s)
+		}
+		return fmt.Errorf("plugin %s: %s failed: %w", plugin.Name, method, err)
+	}
+	return nil
+}
+
+// Close shuts down every running plugin.
+func (h *Host) Close() error {
+	h.mu.Lock()
+	sessions := h.sessions
+	h.sessions = make(map[string]*session)
+	h.mu.Unlock()
+
+	var firstErr error
+	for _, s := range sessions {
+		if err := s.close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// session returns the running process for plugin, starting and initializing
+// it if needed.
+func (h *Host) session(ctx context.Context, plugin *entities.PluginManifest) (*session, error) {
+	path := Executable(plugin)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s, ok := h.sessions[path]; ok {
+		return s, nil
+	}
+
+	cmd := exec.Command(path)
+	cmd.Dir = plugin.Dir
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d", EnvProtocol, entities.PluginProtocolVersion))
+	cmd.Stderr = h.stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start plugin %s: %w", plugin.Name, err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start plugin %s: %w", plugin.Name, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start plugin %s: %w", plugin.Name, err)
+	}
+	s := &session{cmd: cmd, stdin: stdin, stdout: bufio.NewReader(stdout)}
+
+	params := map[string]any{"protocolVersion": entities.PluginProtocolVersion}
+	if err := s.call(ctx, usecases.PluginMethodInitialize, params, &s.info); err != nil {
+		_ = s.kill()
+		return nil, fmt.Errorf("failed to initialize plugin %s: %w", plugin.Name, err)
+	}
+	if s.info.ProtocolVersion != entities.PluginProtocolVersion {
+		_ = s.kill()
+		return nil, fmt.Errorf("plugin %s speaks protocol version %d, loko speaks %d", plugin.Name, s.info.ProtocolVersion, entities.PluginProtocolVersion)
+	}
+
+	h.sessions[path] = s
+	return s, nil
+}
+
+// drop stops s and forgets it, if it is still the session for path.
+func (h *Host) drop(path string, s *session) {
+	h.mu.Lock()
+	if h.sessions[path] == s {
+		delete(h.sessions, path)
+	}
+	h.mu.Unlock()
+	_ = s.kill()
+}
+
+// request is a JSON-RPC 2.0 request or, without an ID, a notification.
+type request struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id,omitempty"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// response is a JSON-RPC 2.0 response.
+type response struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// call sends a request and waits for the response with the same ID. Lines
+// that are not responses to it, such as plugin log notifications, are
+// skipped. If ctx is done first, the process is killed.
+func (s *session) call(ctx context.Context, method string, params, result any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	id := s.nextID
+	if err := s.send(request{JSONRPC: "2.0", ID: id, Method: method, Params: params}); err != nil {
+		return err
+	}
+
+	type reply struct {
+		resp response
+		err  error
+	}
+	done := make(chan reply, 1)
+	go func() {
+		for {
+			line, err := s.stdout.ReadBytes('\n')
+			if err != nil {
+				if err == io.EOF {
+					err = fmt.Errorf("plugin exited")
+				}
+				done <- reply{err: err}
+				return
+			}
+			var resp response
+			if json.Unmarshal(line, &resp) == nil && resp.ID == id {
+				done <- reply{resp: resp}
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		_ = s.kill()
+		return ctx.Err()
+	case r := <-done:
+		if r.err != nil {
+			return r.err
+		}
+		if r.resp.Error != nil {
+			return r.resp.Error
+		}
+		if result == nil || len(r.resp.Result) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(r.resp.Result, result); err != nil {
+			return fmt.Errorf("invalid result: %w", err)
+		}
+		return nil
+	}
+}
+
+// send writes msg to the plugin as a single line.
+func (s *session) send(msg request) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	if _, err := s.stdin.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write to plugin: %w", err)
+	}
+	return nil
+}
+
+// close asks the plugin to shut down, closes its stdin and waits for it to
+// exit.
+func (s *session) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.send(request{JSONRPC: "2.0", Method: methodShutdown})
+	_ = s.stdin.Close()
+	return s.cmd.Wait()
+}
+
+// kill stops the plugin process without waiting for a graceful shutdown.
+func (s *session) kill() error {
+	_ = s.stdin.Close()
+	if s.cmd.Process != nil {
+		_ = s.cmd.Process.Kill()
+	}
+	return s.cmd.Wait()
+}
@@ -0,0 +1,150 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// fakePlugin writes a stand-in plugin executable that answers initialize,
+// render and validate over line-delimited JSON-RPC and records each start
+// and shutdown in logFile.
+func fakePlugin(t *testing.T, protocolVersion string) (path, logFile string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake plugin script requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	logFile = filepath.Join(dir, "log")
+	path = filepath.Join(dir, "loko-fake")
+	script := `#!/bin/sh
+echo "start $LOKO_PLUGIN_PROTOCOL" >> "` + logFile + `"
+while IFS= read -r line; do
+  id=$(printf '%s' "$line" | sed -n 's/^{"jsonrpc":"2.0","id":\([0-9]*\).*/\1/p')
+  case "$line" in
+  *'"method":"initialize"'*) echo '{"jsonrpc":"2.0","id":'$id',"result":{"name":"fake","version":"0.1.0","capabilities":["renderer","validator"],"protocolVersion":` + protocolVersion + `}}' ;;
+  *'"method":"render"'*) echo '{"jsonrpc":"2.0","method":"log","params":{"message":"rendering"}}'; echo '{"jsonrpc":"2.0","id":'$id',"result":{"svg":"<svg/>"}}' ;;
+  *'"method":"shutdown"'*) echo shutdown >> "` + logFile + `"; exit 0 ;;
+  *) echo '{"jsonrpc":"2.0","id":'$id',"error":{"code":-32601,"message":"method not found"}}' ;;
+  esac
+done
+`
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path, logFile
+}
+
+func TestHostCallsPlugin(t *testing.T) {
+	path, logFile := fakePlugin(t, "1")
+	host := NewHost()
+	host.SetStderr(nil)
+	manifest := &entities.PluginManifest{Name: "fake", Executable: path, Capabilities: []entities.PluginCapability{entities.PluginRenderer}}
+	renderer := NewRenderer(host, manifest)
+	ctx := context.Background()
+
+	for range 2 {
+		svg, err := renderer.RenderDiagram(ctx, "a -> b")
+		if err != nil {
+			t.Fatalf("RenderDiagram failed: %v", err)
+		}
+		if svg != "<svg/>" {
+			t.Errorf("svg = %q", svg)
+		}
+	}
+
+	err := host.Call(ctx, manifest, "export", nil, nil)
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32601 {
+		t.Errorf("expected method not found error, got %v", err)
+	}
+
+	if err := host.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	log, _ := os.ReadFile(logFile)
+	if string(log) != "start 1\nshutdown\n" {
+		t.Errorf("plugin log = %q, want one start and a shutdown", log)
+	}
+}
+
+func TestHostRejectsProtocolMismatch(t *testing.T) {
+	path, _ := fakePlugin(t, "2")
+	host := NewHost()
+	defer host.Close()
+	manifest := &entities.PluginManifest{Name: "fake", Executable: path}
+	if err := host.Call(context.Background(), manifest, "render", nil, nil); err == nil || !strings.Contains(err.Error(), "protocol version 2") {
+		t.Errorf("expected protocol version error, got %v", err)
+	}
+}
+
+func TestStoreInstallExecutable(t *testing.T) {
+	path, _ := fakePlugin(t, "1")
+	store := NewStore(filepath.Join(t.TempDir(), "plugins"))
+	ctx := context.Background()
+
+	if plugins, err := store.List(ctx); err != nil || len(plugins) != 0 {
+		t.Fatalf("List on empty store = %v, %v", plugins, err)
+	}
+
+	manifest, err := store.Install(ctx, path)
+	if err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	if manifest.Name != "fake" || manifest.Version != "0.1.0" || manifest.Executable != "loko-fake" {
+		t.Errorf("manifest = %+v", manifest)
+	}
+	if _, err := os.Stat(filepath.Join(manifest.Dir, "loko-fake")); err != nil {
+		t.Errorf("executable not copied: %v", err)
+	}
+
+	// Reinstalling replaces the plugin.
+	if _, err := store.Install(ctx, path); err != nil {
+		t.Fatalf("reinstall failed: %v", err)
+	}
+	plugins, err := store.List(ctx)
+	if err != nil || len(plugins) != 1 {
+		t.Fatalf("List = %v, %v", plugins, err)
+	}
+	if !plugins[0].Provides(entities.PluginValidator) || plugins[0].Dir != manifest.Dir {
+		t.Errorf("listed plugin = %+v", plugins[0])
+	}
+}
+
+func TestStoreInstallDirectory(t *testing.T) {
+	source := t.TempDir()
+	manifest := `{"name": "structurizr", "version": "1.0.0", "capabilities": ["exporter"], "protocolVersion": 1, "executable": "bin/plugin"}`
+	if err := os.WriteFile(filepath.Join(source, ManifestFile), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(source, "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(source, "bin", "plugin"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewStore(t.TempDir())
+	installed, err := store.Install(context.Background(), source)
+	if err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(installed.Dir, "bin", "plugin"))
+	if err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("executable not copied with its mode: %v", err)
+	}
+
+	escaping := `{"name": "evil", "capabilities": ["exporter"], "protocolVersion": 1, "executable": "../../bin/sh"}`
+	if err := os.WriteFile(filepath.Join(source, ManifestFile), []byte(escaping), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Install(context.Background(), source); err == nil {
+		t.Error("expected error for an executable outside the plugin directory")
+	}
+}
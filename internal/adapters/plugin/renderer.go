@@ -0,0 +1,70 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// Ensure Renderer implements usecases.DiagramRenderer interface.
+var _ usecases.DiagramRenderer = (*Renderer)(nil)
+
+// Renderer implements the DiagramRenderer port with a renderer plugin, in
+// place of the d2 CLI.
+type Renderer struct {
+	host   usecases.PluginHost
+	plugin *entities.PluginManifest
+}
+
+// NewRenderer creates a diagram renderer that calls plugin through host.
+func NewRenderer(host usecases.PluginHost, plugin *entities.PluginManifest) *Renderer {
+	return &Renderer{host: host, plugin: plugin}
+}
+
+// IsAvailable reports whether the plugin provides the renderer capability.
+func (r *Renderer) IsAvailable() bool {
+	return r.plugin != nil && r.plugin.Provides(entities.PluginRenderer)
+}
+
+// Version identifies the plugin and its version, e.g. "elk-renderer 1.2.0".
+// It fails when the manifest declares no version.
+func (r *Renderer) Version(_ context.Context) (string, error) {
+	if r.plugin == nil || r.plugin.Version == "" {
+		return "", fmt.Errorf("plugin declares no version")
+	}
+	return r.plugin.Name + " " + r.plugin.Version, nil
+}
+
+// RenderDiagram renders D2 source to SVG with a default timeout of 30 seconds.
+func (r *Renderer) RenderDiagram(ctx context.Context, d2Source string) (string, error) {
+	return r.RenderDiagramWithTimeout(ctx, d2Source, 30)
+}
+
+// RenderDiagramWithTimeout renders D2 source to SVG, giving up after
+// timeoutSec seconds.
+func (r *Renderer) RenderDiagramWithTimeout(ctx context.Context, d2Source string, timeoutSec int) (string, error) {
+	if strings.TrimSpace(d2Source) == "" {
+		return "", fmt.Errorf("d2 source cannot be empty or whitespace-only")
+	}
+	if !r.IsAvailable() {
+		return "", fmt.Errorf("plugin does not provide the renderer capability")
+	}
+	if timeoutSec > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeoutSec)*time.Second)
+		defer cancel()
+	}
+
+	var result struct {
+		SVG string `json:"svg"`
+	}
+	params := map[string]any{"source": d2Source, "timeoutSec": timeoutSec}
+	if err := r.host.Call(ctx, r.plugin, usecases.PluginMethodRender, params, &result); err != nil {
+		return "", err
+	}
+	return result.SVG, nil
+}
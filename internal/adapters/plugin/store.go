@@ -0,0 +1,218 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// ManifestFile is the name of the manifest in each plugin directory.
+const ManifestFile = "plugin.json"
+
+// Ensure Store implements usecases.PluginStore interface.
+var _ usecases.PluginStore = (*Store)(nil)
+
+// Store implements the PluginStore interface. Each plugin lives in its own
+// directory, <dir>/<name>/, holding plugin.json and the plugin executable.
+type Store struct {
+	dir string
+}
+
+// NewStore creates a plugin store rooted at dir, typically the plugins
+// directory under the XDG data directory.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// List returns the installed plugins sorted by name. A missing store
+// directory means no plugins are installed; an invalid manifest is an error.
+func (s *Store) List(ctx context.Context) ([]*entities.PluginManifest, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read plugins directory: %w", err)
+	}
+
+	var plugins []*entities.PluginManifest
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		dir := filepath.Join(s.dir, entry.Name())
+		if _, err := os.Stat(filepath.Join(dir, ManifestFile)); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		manifest, err := readManifest(dir)
+		if err != nil {
+			return nil, err
+		}
+		plugins = append(plugins, manifest)
+	}
+	slices.SortFunc(plugins, func(a, b *entities.PluginManifest) int { return strings.Compare(a.Name, b.Name) })
+	return plugins, nil
+}
+
+// Install copies the plugin at source into the store, replacing an installed
+// plugin of the same name. source is either a directory holding plugin.json
+// or a plugin executable, which is started once to describe itself.
+func (s *Store) Install(ctx context.Context, source string) (*entities.PluginManifest, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, fmt.Errorf("plugin not found: %w", err)
+	}
+
+	var manifest *entities.PluginManifest
+	if info.IsDir() {
+		manifest, err = readManifest(source)
+	} else {
+		manifest, err = describe(ctx, source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	target := filepath.Join(s.dir, manifest.Name)
+	staging := target + ".installing"
+	if err := os.RemoveAll(staging); err != nil {
+		return nil, fmt.Errorf("failed to install plugin: %w", err)
+	}
+	if info.IsDir() {
+		err = copyTree(source, staging)
+	} else {
+		err = copyFile(source, filepath.Join(staging, manifest.Executable), 0755)
+	}
+	if err == nil {
+		err = writeManifest(staging, manifest)
+	}
+	if err == nil {
+		err = os.RemoveAll(target)
+	}
+	if err == nil {
+		err = os.Rename(staging, target)
+	}
+	if err != nil {
+		_ = os.RemoveAll(staging)
+		return nil, fmt.Errorf("failed to install plugin %s: %w", manifest.Name, err)
+	}
+
+	manifest.Dir = target
+	return manifest, nil
+}
+
+// describe starts the executable at path and builds a manifest from its
+// response to initialize.
+func describe(ctx context.Context, path string) (*entities.PluginManifest, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve plugin path: %w", err)
+	}
+	probe := &entities.PluginManifest{
+		Name:       filepath.Base(abs),
+		Executable: abs,
+		Dir:        filepath.Dir(abs),
+	}
+
+	host := NewHost()
+	defer host.Close()
+	info, err := host.Initialize(ctx, probe)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &entities.PluginManifest{
+		Name:            info.Name,
+		Version:         info.Version,
+		Description:     info.Description,
+		Capabilities:    info.Capabilities,
+		ProtocolVersion: info.ProtocolVersion,
+		Executable:      filepath.Base(abs),
+	}
+	if err := manifest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid plugin %s: %w", probe.Name, err)
+	}
+	return manifest, nil
+}
+
+// readManifest reads and validates dir/plugin.json.
+func readManifest(dir string) (*entities.PluginManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin manifest: %w", err)
+	}
+	var manifest entities.PluginManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid plugin manifest %s: %w", filepath.Join(dir, ManifestFile), err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid plugin manifest %s: %w", filepath.Join(dir, ManifestFile), err)
+	}
+	if filepath.IsAbs(manifest.Executable) || !filepath.IsLocal(manifest.Executable) {
+		return nil, fmt.Errorf("invalid plugin manifest %s: executable must be a path inside the plugin directory", filepath.Join(dir, ManifestFile))
+	}
+	manifest.Dir = dir
+	return &manifest, nil
+}
+
+// writeManifest writes manifest to dir/plugin.json.
+func writeManifest(dir string, manifest *entities.PluginManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ManifestFile), append(data, '\n'), 0644)
+}
+
+// copyTree copies the regular files under src to dst, keeping their modes.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(filepath.Join(dst, rel), 0755)
+		}
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		return copyFile(path, filepath.Join(dst, rel), info.Mode().Perm())
+	})
+}
+
+// copyFile copies src to dst with the given permissions, creating dst's
+// directory.
+func copyFile(src, dst string, perm fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,50 @@
+// Package shell provides a HookRunner adapter that runs user-configured hook
+// commands through the platform shell: sh -c on Unix, cmd /C on Windows.
+package shell
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// Ensure HookRunner implements usecases.HookRunner interface.
+var _ usecases.HookRunner = (*HookRunner)(nil)
+
+// HookRunner implements the HookRunner interface.
+type HookRunner struct {
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// NewHookRunner creates a hook runner that streams hook output to loko's
+// stdout and stderr.
+func NewHookRunner() *HookRunner {
+	return &HookRunner{stdout: os.Stdout, stderr: os.Stderr}
+}
+
+// SetOutput sets where hook commands write their stdout and stderr.
+func (r *HookRunner) SetOutput(stdout, stderr io.Writer) {
+	r.stdout = stdout
+	r.stderr = stderr
+}
+
+// Run executes command through the shell in dir with env added to loko's
+// environment.
+func (r *HookRunner) Run(ctx context.Context, command, dir string, env []string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = r.stdout
+	cmd.Stderr = r.stderr
+	return cmd.Run()
+}
@@ -0,0 +1,35 @@
+package shell
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestHookRunner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test commands use POSIX shell syntax")
+	}
+	var stdout, stderr bytes.Buffer
+	r := NewHookRunner()
+	r.SetOutput(&stdout, &stderr)
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	err := r.Run(ctx, `echo "$LOKO_HOOK in $(pwd)"; echo oops >&2`, dir, []string{"LOKO_HOOK=post_build"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if got := strings.TrimSpace(stdout.String()); !strings.HasPrefix(got, "post_build in ") || !strings.HasSuffix(got, dir) {
+		t.Errorf("stdout = %q", got)
+	}
+	if strings.TrimSpace(stderr.String()) != "oops" {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+
+	if err := r.Run(ctx, "exit 3", dir, nil); err == nil || !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("expected exit status error, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+// Package signing provides Ed25519 adapters for signing and verifying build
+// provenance. Keys are PEM files as produced by
+// `openssl genpkey -algorithm ed25519` and `openssl pkey -pubout`.
+package signing
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// EnvSigningKey names the environment variable holding the path of the
+// private key used to sign provenance.
+const EnvSigningKey = "LOKO_SIGNING_KEY"
+
+// Ensure the key types implement the usecases interfaces.
+var (
+	_ usecases.ProvenanceSigner   = (*KeySigner)(nil)
+	_ usecases.ProvenanceVerifier = (*KeyVerifier)(nil)
+)
+
+// KeySigner implements the ProvenanceSigner interface with an Ed25519 key.
+type KeySigner struct {
+	key   ed25519.PrivateKey
+	keyID string
+}
+
+// NewKeySigner loads a PKCS #8 PEM Ed25519 private key from path.
+func NewKeySigner(path string) (*KeySigner, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key %s: %w", path, err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key %s is not an Ed25519 key", path)
+	}
+	keyID, err := KeyID(key.Public().(ed25519.PublicKey))
+	if err != nil {
+		return nil, err
+	}
+	return &KeySigner{key: key, keyID: keyID}, nil
+}
+
+// Sign returns the key ID and the Ed25519 signature of data.
+func (s *KeySigner) Sign(_ context.Context, data []byte) (string, []byte, error) {
+	return s.keyID, ed25519.Sign(s.key, data), nil
+}
+
+// KeyVerifier implements the ProvenanceVerifier interface with an Ed25519
+// public key.
+type KeyVerifier struct {
+	key ed25519.PublicKey
+}
+
+// NewKeyVerifier loads a PKIX PEM Ed25519 public key from path.
+func NewKeyVerifier(path string) (*KeyVerifier, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key %s: %w", path, err)
+	}
+	key, ok := parsed.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not an Ed25519 key", path)
+	}
+	return &KeyVerifier{key: key}, nil
+}
+
+// Verify returns an error unless sig is a valid signature of data.
+func (v *KeyVerifier) Verify(_ context.Context, data, sig []byte) error {
+	if !ed25519.Verify(v.key, data, sig) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// KeyID identifies a public key by the SHA-256 of its PKIX encoding.
+func KeyID(key ed25519.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode public key: %w", err)
+	}
+	sum := sha256.Sum256(der)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// readPEM returns the first PEM block in the file at path.
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s is not PEM encoded", path)
+	}
+	return block, nil
+}
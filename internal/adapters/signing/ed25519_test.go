@@ -0,0 +1,84 @@
+package signing
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeKeyPair writes a fresh Ed25519 key pair as PEM files.
+func writeKeyPair(t *testing.T, dir string) (privatePath, publicPath string) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privatePath = filepath.Join(dir, "signing.pem")
+	publicPath = filepath.Join(dir, "signing.pub.pem")
+	if err := os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return privatePath, publicPath
+}
+
+func TestSignAndVerify(t *testing.T) {
+	privatePath, publicPath := writeKeyPair(t, t.TempDir())
+	signer, err := NewKeySigner(privatePath)
+	if err != nil {
+		t.Fatalf("NewKeySigner failed: %v", err)
+	}
+	verifier, err := NewKeyVerifier(publicPath)
+	if err != nil {
+		t.Fatalf("NewKeyVerifier failed: %v", err)
+	}
+
+	ctx := context.Background()
+	keyID, sig, err := signer.Sign(ctx, []byte("statement"))
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if want, _ := KeyID(verifier.key); keyID != want {
+		t.Errorf("keyID = %q, want %q", keyID, want)
+	}
+	if err := verifier.Verify(ctx, []byte("statement"), sig); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+	if err := verifier.Verify(ctx, []byte("tampered"), sig); err == nil {
+		t.Error("expected tampered data to fail verification")
+	}
+
+	_, otherPublic := writeKeyPair(t, t.TempDir())
+	other, err := NewKeyVerifier(otherPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Verify(ctx, []byte("statement"), sig); err == nil {
+		t.Error("expected another key to fail verification")
+	}
+}
+
+func TestNewKeySignerRejectsPublicKey(t *testing.T) {
+	_, publicPath := writeKeyPair(t, t.TempDir())
+	if _, err := NewKeySigner(publicPath); err == nil {
+		t.Error("expected error loading a public key as the signing key")
+	}
+	if _, err := NewKeySigner(filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+		t.Error("expected error for a missing key")
+	}
+}
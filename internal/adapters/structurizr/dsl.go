@@ -0,0 +1,229 @@
+// Package structurizr exports the architecture as a Structurizr DSL workspace.
+package structurizr
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// Ensure DSLExporter implements usecases.ExternalModelExporter interface.
+var _ usecases.ExternalModelExporter = (*DSLExporter)(nil)
+
+// ExternalTag is the tag of the software systems loko marks as external.
+const ExternalTag = "External"
+
+// invalidIdentifierChars matches the characters a DSL identifier cannot hold.
+var invalidIdentifierChars = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// DSLExporter writes a model as a Structurizr DSL workspace.
+//
+// The C4 levels map one to one: the project becomes the workspace, systems
+// become software systems, containers containers and components components,
+// nested components flattened into their container. Relationships connect the
+// most specific elements loko knows them for. The views are a system
+// landscape, and the system context, container and component views of every
+// element with children, all laid out automatically.
+type DSLExporter struct{}
+
+// NewDSLExporter creates a new DSLExporter instance.
+func NewDSLExporter() *DSLExporter {
+	return &DSLExporter{}
+}
+
+// ExportModel writes model to w as a workspace.
+func (e *DSLExporter) ExportModel(ctx context.Context, model *usecases.ExternalModel, w io.Writer) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	d := &dslWriter{identifiers: make(map[string]string), taken: make(map[string]bool)}
+	d.workspace(model)
+	_, err := io.WriteString(w, d.sb.String())
+	return err
+}
+
+// dslWriter accumulates a workspace, assigning each element a unique
+// identifier derived from its qualified ID.
+type dslWriter struct {
+	sb          strings.Builder
+	identifiers map[string]string // By qualified ID
+	taken       map[string]bool
+}
+
+// workspace writes the workspace of model.
+func (d *dslWriter) workspace(model *usecases.ExternalModel) {
+	if args := quoted(model.Project.Name, model.Project.Description); args != "" {
+		d.line(0, "workspace %s {", args)
+	} else {
+		d.line(0, "workspace {")
+	}
+	d.line(1, "model {")
+	for _, system := range model.Systems {
+		d.system(system)
+	}
+	d.relationships(model.Relationships)
+	d.line(1, "}")
+	d.sb.WriteByte('\n')
+	d.views(model.Systems)
+	d.line(0, "}")
+}
+
+// system writes a software system with its containers.
+func (d *dslWriter) system(system *entities.System) {
+	tags := system.Tags
+	if system.External {
+		tags = append([]string{ExternalTag}, tags...)
+	}
+	id := d.identifier(system.ID)
+	args := quoted(system.Name, system.Description, strings.Join(tags, ","))
+	containers := system.ListContainers()
+	if len(containers) == 0 {
+		d.line(2, "%s = softwareSystem %s", id, args)
+		return
+	}
+	d.line(2, "%s = softwareSystem %s {", id, args)
+	for _, container := range containers {
+		if container != nil {
+			d.container(system.ID, container)
+		}
+	}
+	d.line(2, "}")
+}
+
+// container writes a container of the system systemID with its components.
+func (d *dslWriter) container(systemID string, container *entities.Container) {
+	qualifiedID := entities.QualifiedNodeID("container", systemID, container.ID, "")
+	id := d.identifier(qualifiedID)
+	args := quoted(container.Name, container.Description, container.Technology, strings.Join(container.Tags, ","))
+	components := container.ListComponents()
+	if len(components) == 0 {
+		d.line(3, "%s = container %s", id, args)
+		return
+	}
+	d.line(3, "%s = container %s {", id, args)
+	for _, component := range components {
+		if component == nil {
+			continue
+		}
+		componentID := d.identifier(entities.QualifiedNodeID("component", systemID, container.ID, component.ID))
+		d.line(4, "%s = component %s", componentID, quoted(component.Name, component.Description, component.Technology, strings.Join(component.Tags, ",")))
+	}
+	d.line(3, "}")
+}
+
+// relationships writes the relationships between elements of the model.
+// Those with an element left out of the model, such as a redacted one, and
+// those between an element and its parent, which Structurizr rejects, are
+// skipped.
+func (d *dslWriter) relationships(relationships []usecases.ExternalRelationship) {
+	if len(relationships) == 0 {
+		return
+	}
+	d.sb.WriteByte('\n')
+	for _, rel := range relationships {
+		source, sourceOK := d.identifiers[rel.Source]
+		target, targetOK := d.identifiers[rel.Target]
+		if !sourceOK || !targetOK || strings.HasPrefix(rel.Target, rel.Source+"/") || strings.HasPrefix(rel.Source, rel.Target+"/") {
+			continue
+		}
+		if rel.Description == "" {
+			d.line(2, "%s -> %s", source, target)
+		} else {
+			d.line(2, "%s -> %s %s", source, target, quoted(rel.Description))
+		}
+	}
+}
+
+// views writes the landscape and the views of every system and container
+// with children.
+func (d *dslWriter) views(systems []*entities.System) {
+	d.line(1, "views {")
+	d.view("systemLandscape", "", "landscape")
+	for _, system := range systems {
+		if system.External {
+			continue
+		}
+		id := d.identifiers[system.ID]
+		d.view("systemContext", id, id+"-context")
+		containers := system.ListContainers()
+		if len(containers) == 0 {
+			continue
+		}
+		d.view("container", id, id+"-containers")
+		for _, container := range containers {
+			if container == nil || len(container.Components) == 0 {
+				continue
+			}
+			containerID := d.identifiers[entities.QualifiedNodeID("container", system.ID, container.ID, "")]
+			d.view("component", containerID, containerID+"-components")
+		}
+	}
+	d.sb.WriteByte('\n')
+	d.line(2, "styles {")
+	d.line(3, "element %s {", quoted(ExternalTag))
+	d.line(4, "background #999999")
+	d.line(4, "color #ffffff")
+	d.line(3, "}")
+	d.line(2, "}")
+	d.line(1, "}")
+}
+
+// view writes a view of kind scoped to the element scope, if any.
+func (d *dslWriter) view(kind, scope, key string) {
+	if scope != "" {
+		kind += " " + scope
+	}
+	d.line(2, "%s %s {", kind, strconv.Quote(key))
+	d.line(3, "include *")
+	d.line(3, "autoLayout")
+	d.line(2, "}")
+}
+
+// identifier returns the DSL identifier of the element qualifiedID: the ID
+// with every run of characters outside [A-Za-z0-9_] replaced by "_", and
+// numbered when that collides with another element's.
+func (d *dslWriter) identifier(qualifiedID string) string {
+	if id, ok := d.identifiers[qualifiedID]; ok {
+		return id
+	}
+	base := strings.Trim(invalidIdentifierChars.ReplaceAllString(qualifiedID, "_"), "_")
+	if base == "" {
+		base = "element"
+	}
+	id := base
+	for n := 2; d.taken[id]; n++ {
+		id = base + "_" + strconv.Itoa(n)
+	}
+	d.identifiers[qualifiedID] = id
+	d.taken[id] = true
+	return id
+}
+
+// line writes a line indented by depth levels.
+func (d *dslWriter) line(depth int, format string, args ...any) {
+	d.sb.WriteString(strings.Repeat("    ", depth))
+	fmt.Fprintf(&d.sb, format, args...)
+	d.sb.WriteByte('\n')
+}
+
+// quoted returns the DSL strings of values separated by spaces, leaving out
+// trailing empty ones. Whitespace runs, including line breaks, become single
+// spaces, since a DSL string cannot span lines.
+func quoted(values ...string) string {
+	for len(values) > 0 && strings.TrimSpace(values[len(values)-1]) == "" {
+		values = values[:len(values)-1]
+	}
+	parts := make([]string, len(values))
+	for i, value := range values {
+		value = strings.Join(strings.Fields(value), " ")
+		value = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+		parts[i] = `"` + value + `"`
+	}
+	return strings.Join(parts, " ")
+}
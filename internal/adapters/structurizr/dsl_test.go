@@ -0,0 +1,103 @@
+package structurizr
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+func TestDSLExporterExportModel(t *testing.T) {
+	project, _ := entities.NewProject("Shop")
+	project.Description = `The "online" shop`
+
+	payments, _ := entities.NewSystem("Payments")
+	payments.SetDescription("Takes payments\nfrom customers")
+	api, _ := entities.NewContainer("API")
+	api.SetTechnology("Go")
+	handler, _ := entities.NewComponent("Handler")
+	ledger, _ := entities.NewComponent("Ledger")
+	_ = api.AddComponent(handler)
+	_ = api.AddComponent(ledger)
+	_ = payments.AddContainer(api)
+
+	stripe, _ := entities.NewSystem("Stripe")
+	stripe.SetExternal(true)
+
+	model := &usecases.ExternalModel{
+		Project: project,
+		Systems: []*entities.System{payments, stripe},
+		Relationships: []usecases.ExternalRelationship{
+			{Source: "payments/api/handler", Target: "payments/api/ledger", Description: "records payment"},
+			{Source: "payments/api", Target: "stripe"},
+			{Source: "payments/api/handler", Target: "payments/api"},   // Child to parent
+			{Source: "payments/api/handler", Target: "billing/api/db"}, // Not in the model
+		},
+	}
+
+	var out strings.Builder
+	if err := NewDSLExporter().ExportModel(context.Background(), model, &out); err != nil {
+		t.Fatalf("ExportModel failed: %v", err)
+	}
+
+	want := `workspace "Shop" "The \"online\" shop" {
+    model {
+        payments = softwareSystem "Payments" "Takes payments from customers" {
+            payments_api = container "API" "" "Go" {
+                payments_api_handler = component "Handler"
+                payments_api_ledger = component "Ledger"
+            }
+        }
+        stripe = softwareSystem "Stripe" "" "External"
+
+        payments_api_handler -> payments_api_ledger "records payment"
+        payments_api -> stripe
+    }
+
+    views {
+        systemLandscape "landscape" {
+            include *
+            autoLayout
+        }
+        systemContext payments "payments-context" {
+            include *
+            autoLayout
+        }
+        container payments "payments-containers" {
+            include *
+            autoLayout
+        }
+        component payments_api "payments_api-components" {
+            include *
+            autoLayout
+        }
+
+        styles {
+            element "External" {
+                background #999999
+                color #ffffff
+            }
+        }
+    }
+}
+`
+	if out.String() != want {
+		t.Errorf("workspace =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestDSLIdentifiers(t *testing.T) {
+	d := &dslWriter{identifiers: make(map[string]string), taken: make(map[string]bool)}
+	for _, tt := range []struct{ qualifiedID, want string }{
+		{"payments.cards/api", "payments_cards_api"},
+		{"payments-cards/api", "payments_cards_api_2"},
+		{"payments.cards/api", "payments_cards_api"},
+		{"--", "element"},
+	} {
+		if got := d.identifier(tt.qualifiedID); got != tt.want {
+			t.Errorf("identifier(%q) = %q, want %q", tt.qualifiedID, got, tt.want)
+		}
+	}
+}
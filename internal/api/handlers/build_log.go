@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// logBuild appends entry to the log of a build, in memory and, when the
+// build has one, in the build log store. A store failure is not a build
+// failure, so it is dropped; the in-memory log stays complete.
+func (h *Handlers) logBuild(buildID string, entry entities.BuildLogEntry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+
+	h.buildMutex.Lock()
+	status, ok := h.builds[buildID]
+	if !ok {
+		h.buildMutex.Unlock()
+		return
+	}
+	status.log = append(status.log, entry)
+	stored := status.stored
+	h.buildMutex.Unlock()
+
+	if stored {
+		_ = h.buildLogs.Append(h.buildCtx, buildID, entry)
+	}
+}
+
+// GetBuildLog handles GET /api/v1/build/{id}/log. The log is served as JSON
+// lines, one entry per line, from memory for the builds of this server and
+// from the build log store for older ones.
+func (h *Handlers) GetBuildLog(w http.ResponseWriter, r *http.Request) {
+	buildID := r.PathValue("id")
+	if buildID == "" {
+		WriteError(w, http.StatusBadRequest, "INVALID_INPUT", "build id required")
+		return
+	}
+
+	h.buildMutex.RLock()
+	status, ok := h.builds[buildID]
+	var entries []entities.BuildLogEntry
+	if ok {
+		entries = slices.Clone(status.log)
+	}
+	h.buildMutex.RUnlock()
+
+	if !ok {
+		if h.buildLogs == nil {
+			WriteError(w, http.StatusNotFound, "NOT_FOUND", "build log not found")
+			return
+		}
+		var err error
+		entries, err = h.buildLogs.Entries(r.Context(), buildID)
+		if errors.Is(err, entities.ErrBuildLogNotFound) {
+			WriteError(w, http.StatusNotFound, "NOT_FOUND", "build log not found")
+			return
+		}
+		if err != nil {
+			WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(w)
+	for _, entry := range entries {
+		_ = enc.Encode(entry)
+	}
+}
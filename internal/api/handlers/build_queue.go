@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// defaultMaxBuilds is how many builds run at once. A build already renders
+// diagrams in parallel, so more concurrent builds mostly compete for the CPU.
+const defaultMaxBuilds = 1
+
+// enqueueBuild appends status to the build queue. Only the newest build of
+// an output directory is worth finishing, so the queued builds of the same
+// directory are cancelled and its running build is told to stop. Returns the
+// IDs of the queued builds cancelled. The caller holds buildMutex.
+func (h *Handlers) enqueueBuild(status *buildStatus) []string {
+	dir := filepath.Clean(status.OutputDir)
+	var superseded []string
+	h.queue = slices.DeleteFunc(h.queue, func(queued *buildStatus) bool {
+		if filepath.Clean(queued.OutputDir) != dir {
+			return false
+		}
+		queued.Status = "cancelled"
+		queued.Error = "superseded by build " + status.ID
+		queued.EndTime = time.Now()
+		superseded = append(superseded, queued.ID)
+		return true
+	})
+	if running := h.running[dir]; running != nil {
+		running.supersededBy = status.ID
+		running.cancel()
+	}
+	h.queue = append(h.queue, status)
+	return superseded
+}
+
+// startQueuedBuilds starts queued builds, oldest first, while fewer than
+// maxBuilds run. A build waits while another one writes to its output
+// directory. The caller holds buildMutex.
+func (h *Handlers) startQueuedBuilds() {
+	for i := 0; i < len(h.queue) && len(h.running) < h.maxBuilds; {
+		status := h.queue[i]
+		dir := filepath.Clean(status.OutputDir)
+		if h.running[dir] != nil {
+			i++
+			continue
+		}
+		h.queue = slices.Delete(h.queue, i, i+1)
+
+		ctx, cancel := context.WithCancel(h.buildCtx)
+		status.Status = "building"
+		status.StartTime = time.Now()
+		status.cancel = cancel
+		h.running[dir] = status
+		go h.executeBuild(ctx, status)
+	}
+}
+
+// executeBuild runs a started build, records its result and starts the
+// builds that were waiting for it.
+func (h *Handlers) executeBuild(ctx context.Context, status *buildStatus) {
+	h.publish(entities.ModelEvent{Type: entities.EventBuildStarted, BuildID: status.ID})
+	err := h.runBuild(ctx, status.ID, status.request)
+	cancelled := ctx.Err() != nil
+
+	h.buildMutex.Lock()
+	status.cancel()
+	delete(h.running, filepath.Clean(status.OutputDir))
+	switch {
+	case err != nil && cancelled:
+		status.Status = "cancelled"
+		status.Error = "cancelled"
+		if status.supersededBy != "" {
+			status.Error = "superseded by build " + status.supersededBy
+		}
+	case err != nil:
+		status.Status = "failed"
+		status.Error = err.Error()
+	default:
+		status.Status = "complete"
+	}
+	status.EndTime = time.Now()
+	h.startQueuedBuilds()
+	h.buildMutex.Unlock()
+
+	h.publishBuildResult(status.ID)
+}
+
+// queuePosition returns the 1-based position of status in the build queue,
+// or 0 when it is not queued. The caller holds buildMutex.
+func (h *Handlers) queuePosition(status *buildStatus) int {
+	return slices.Index(h.queue, status) + 1
+}
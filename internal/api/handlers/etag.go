@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// modelETag returns the entity tag of a response built from systems and
+// fields, such as project attributes and the list order. It is derived
+// from the content hashes of the systems, containers and components, and the
+// history they expose, so it changes whenever the response would.
+func modelETag(systems []*entities.System, fields ...string) string {
+	h := sha256.New()
+	for _, field := range fields {
+		fmt.Fprintf(h, "%q\n", field)
+	}
+	for _, system := range systems {
+		if system == nil {
+			continue
+		}
+		writeElementHash(h, system.ID, system.ContentHash, system.CreatedAt, system.UpdatedAt, system.Author)
+		for _, container := range system.ListContainers() {
+			writeElementHash(h, container.ID, container.ContentHash, container.CreatedAt, container.UpdatedAt, container.Author)
+			for _, component := range container.ListComponents() {
+				writeElementHash(h, component.ID, component.ContentHash, component.CreatedAt, component.UpdatedAt, component.Author)
+			}
+		}
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// writeElementHash adds the identity, content hash and history of an element to h.
+func writeElementHash(h hash.Hash, id, contentHash string, created, updated time.Time, author string) {
+	fmt.Fprintf(h, "%s %s %d %d %q\n", id, contentHash, created.UnixNano(), updated.UnixNano(), author)
+}
+
+// notModified sets the ETag header to etag and, when the request's
+// If-None-Match lists it, answers 304 Not Modified and reports true.
+func notModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+	if !etagMatches(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches reports whether an If-None-Match header value lists etag,
+// comparing weakly as RFC 9110 requires for If-None-Match.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
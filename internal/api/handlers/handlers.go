@@ -4,12 +4,17 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/madstone-tech/loko/internal/adapters/d2"
 	"github.com/madstone-tech/loko/internal/adapters/html"
+	"github.com/madstone-tech/loko/internal/core/entities"
 	"github.com/madstone-tech/loko/internal/core/usecases"
 )
 
@@ -17,32 +22,91 @@ import (
 type Handlers struct {
 	projectRoot string
 	repo        usecases.ProjectRepository
+	events      usecases.EventPublisher // Optional: receives build events
+	buildLogs   usecases.BuildLogs      // Optional: keeps build logs across restarts
+
+	resolvePath func(path string) (string, error) // Optional: confines request paths to the sandbox
 
 	// Build tracking
 	builds     map[string]*buildStatus
 	buildMutex sync.RWMutex
 	buildID    int
+
+	// Build queue, guarded by buildMutex
+	queue     []*buildStatus          // Builds waiting to start, oldest first
+	running   map[string]*buildStatus // Running builds by output directory
+	maxBuilds int                     // How many builds run at once
+	buildCtx  context.Context         // Parent of the builds' contexts
+	runBuild  func(ctx context.Context, buildID string, req BuildRequest) error
 }
 
-// buildStatus tracks an in-progress or completed build.
+// buildStatus tracks a queued, in-progress or finished build.
 type buildStatus struct {
 	ID               string
-	Status           string // "building", "complete", "failed"
+	Status           string // "queued", "building", "complete", "failed", "cancelled"
+	QueuedAt         time.Time
 	StartTime        time.Time
 	EndTime          time.Time
 	FilesGenerated   int
 	DiagramsRendered int
 	OutputDir        string
 	Error            string
+
+	request      BuildRequest
+	cancel       context.CancelFunc       // Set while the build runs
+	supersededBy string                   // Build that cancelled this one
+	log          []entities.BuildLogEntry // Everything the build reported
+	stored       bool                     // Whether the log is also in buildLogs
 }
 
 // NewHandlers creates a new Handlers instance.
 func NewHandlers(projectRoot string, repo usecases.ProjectRepository) *Handlers {
-	return &Handlers{
+	h := &Handlers{
 		projectRoot: projectRoot,
 		repo:        repo,
 		builds:      make(map[string]*buildStatus),
+		running:     make(map[string]*buildStatus),
+		maxBuilds:   defaultMaxBuilds,
+		buildCtx:    context.Background(),
+	}
+	h.runBuild = h.build
+	return h
+}
+
+// WithEvents publishes build started, completed and failed events to events.
+func (h *Handlers) WithEvents(events usecases.EventPublisher) *Handlers {
+	h.events = events
+	return h
+}
+
+// WithBuildLogs records the log of every build in logs, which also issues
+// the build IDs, so logs stay retrievable after the server restarts.
+func (h *Handlers) WithBuildLogs(logs usecases.BuildLogs) *Handlers {
+	h.buildLogs = logs
+	return h
+}
+
+// WithBuildContext runs builds under ctx, so they are cancelled when it is,
+// e.g. on server shutdown.
+func (h *Handlers) WithBuildContext(ctx context.Context) *Handlers {
+	h.buildCtx = ctx
+	return h
+}
+
+// WithSandbox confines the output_dir of build requests to the paths resolve
+// accepts, building in the resolved path. Other paths are refused with 403.
+func (h *Handlers) WithSandbox(resolve func(path string) (string, error)) *Handlers {
+	h.resolvePath = resolve
+	return h
+}
+
+// publish sends event to the event publisher, if any.
+func (h *Handlers) publish(event entities.ModelEvent) {
+	if h.events == nil {
+		return
 	}
+	event.Time = time.Now()
+	h.events.Publish(event)
 }
 
 // GetProject handles GET /api/v1/project.
@@ -61,6 +125,10 @@ func (h *Handlers) GetProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if notModified(w, r, modelETag(systems, project.Name, project.Description, project.Version)) {
+		return
+	}
+
 	totalContainers := 0
 	totalComponents := 0
 	for _, sys := range systems {
@@ -85,6 +153,11 @@ func (h *Handlers) GetProject(w http.ResponseWriter, r *http.Request) {
 func (h *Handlers) ListSystems(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	order, ok := sortOrder(w, r)
+	if !ok {
+		return
+	}
+
 	project, _ := h.repo.LoadProject(ctx, h.projectRoot)
 
 	systems, err := h.repo.ListSystems(ctx, h.projectRoot)
@@ -92,23 +165,20 @@ func (h *Handlers) ListSystems(w http.ResponseWriter, r *http.Request) {
 		WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list systems")
 		return
 	}
-
-	summaries := make([]SystemSummary, 0, len(systems))
-	for _, sys := range systems {
-		summaries = append(summaries, SystemSummary{
-			ID:             sys.ID,
-			Name:           sys.Name,
-			Description:    sys.Description,
-			ContainerCount: sys.ContainerCount(),
-			ComponentCount: sys.ComponentCount(),
-			Tags:           sys.Tags,
-		})
-	}
+	entities.SortSystems(systems, order)
 
 	projectName := ""
 	if project != nil {
 		projectName = project.Name
 	}
+	if notModified(w, r, modelETag(systems, projectName, order)) {
+		return
+	}
+
+	summaries := make([]SystemSummary, 0, len(systems))
+	for _, sys := range systems {
+		summaries = append(summaries, systemSummary(sys))
+	}
 
 	resp := SystemsResponse{
 		Success:     true,
@@ -129,15 +199,24 @@ func (h *Handlers) GetSystem(w http.ResponseWriter, r *http.Request) {
 		WriteError(w, http.StatusBadRequest, "INVALID_INPUT", "system id required")
 		return
 	}
+	order, ok := sortOrder(w, r)
+	if !ok {
+		return
+	}
 
 	system, err := h.repo.LoadSystem(ctx, h.projectRoot, systemID)
 	if err != nil {
 		WriteError(w, http.StatusNotFound, "NOT_FOUND", "system not found")
 		return
 	}
+	if notModified(w, r, modelETag([]*entities.System{system}, order)) {
+		return
+	}
 
-	containers := make([]ContainerSummary, 0)
-	for _, cont := range system.ListContainers() {
+	listed := system.ListContainers()
+	entities.SortContainers(listed, order)
+	containers := make([]ContainerSummary, 0, len(listed))
+	for _, cont := range listed {
 		containers = append(containers, ContainerSummary{
 			ID:             cont.ID,
 			Name:           cont.Name,
@@ -145,29 +224,290 @@ func (h *Handlers) GetSystem(w http.ResponseWriter, r *http.Request) {
 			Technology:     cont.Technology,
 			ComponentCount: cont.ComponentCount(),
 			Tags:           cont.Tags,
+			CreatedAt:      cont.CreatedAt,
+			UpdatedAt:      cont.UpdatedAt,
+			Author:         cont.Author,
+			ContentHash:    cont.ContentHash,
 		})
 	}
 
+	summary := systemSummary(system)
 	resp := SystemDetailResponse{
-		Success: true,
-		System: &SystemSummary{
-			ID:             system.ID,
-			Name:           system.Name,
-			Description:    system.Description,
-			ContainerCount: system.ContainerCount(),
-			ComponentCount: system.ComponentCount(),
-			Tags:           system.Tags,
-		},
+		Success:    true,
+		System:     &summary,
 		Containers: containers,
 	}
 
 	WriteJSON(w, http.StatusOK, resp)
 }
 
-// TriggerBuild handles POST /api/v1/build.
-func (h *Handlers) TriggerBuild(w http.ResponseWriter, r *http.Request) {
+// ListComponents handles GET /api/v1/systems/{id}/containers/{cid}/components.
+func (h *Handlers) ListComponents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	systemID := r.PathValue("id")
+	containerID := r.PathValue("cid")
+
+	if systemID == "" || containerID == "" {
+		WriteError(w, http.StatusBadRequest, "INVALID_INPUT", "system id and container id required")
+		return
+	}
+	order, ok := sortOrder(w, r)
+	if !ok {
+		return
+	}
+
+	system, err := h.repo.LoadSystem(ctx, h.projectRoot, systemID)
+	if err != nil || system == nil {
+		WriteError(w, http.StatusNotFound, "NOT_FOUND", "system not found")
+		return
+	}
+	container, ok := system.Containers[containerID]
+	if !ok {
+		WriteError(w, http.StatusNotFound, "NOT_FOUND", "container not found")
+		return
+	}
+
+	listed := container.ListComponents()
+	entities.SortComponents(listed, order)
+	components := make([]ComponentSummary, 0, len(listed))
+	for _, comp := range listed {
+		components = append(components, ComponentSummary{
+			ID:          comp.ID,
+			Name:        comp.Name,
+			Description: comp.Description,
+			Technology:  comp.Technology,
+			Tags:        comp.Tags,
+			CreatedAt:   comp.CreatedAt,
+			UpdatedAt:   comp.UpdatedAt,
+			Author:      comp.Author,
+			ContentHash: comp.ContentHash,
+		})
+	}
+
+	resp := ComponentsResponse{
+		Success:     true,
+		SystemID:    system.ID,
+		ContainerID: container.ID,
+		Components:  components,
+		TotalCount:  len(components),
+	}
+
+	WriteJSON(w, http.StatusOK, resp)
+}
+
+// Pagination limits for GET /api/v1/elements.
+const (
+	defaultElementsLimit = 100
+	maxElementsLimit     = 1000
+)
+
+// ListElements handles GET /api/v1/elements, listing systems, containers and
+// components in one flat, paginated list. Elements are ordered by system,
+// then container, then component, each by name.
+func (h *Handlers) ListElements(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	query := r.URL.Query()
 
+	elemType := query.Get("type")
+	if elemType != "" && elemType != "system" && elemType != "container" && elemType != "component" {
+		WriteError(w, http.StatusBadRequest, "INVALID_INPUT", "type must be one of: system, container, component")
+		return
+	}
+	limit, ok := queryInt(w, r, "limit", defaultElementsLimit)
+	if !ok {
+		return
+	}
+	limit = min(limit, maxElementsLimit)
+	offset, ok := queryInt(w, r, "offset", 0)
+	if !ok {
+		return
+	}
+
+	systems, err := h.repo.ListSystems(ctx, h.projectRoot)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list systems")
+		return
+	}
+
+	tag, tech := query.Get("tag"), query.Get("tech")
+	matches := func(element ElementSummary) bool {
+		if elemType != "" && element.Type != elemType {
+			return false
+		}
+		if tech != "" && !strings.EqualFold(element.Technology, tech) {
+			return false
+		}
+		return tag == "" || slices.ContainsFunc(element.Tags, func(t string) bool { return strings.EqualFold(t, tag) })
+	}
+
+	elements := make([]ElementSummary, 0)
+	add := func(element ElementSummary) {
+		if matches(element) {
+			elements = append(elements, element)
+		}
+	}
+	entities.SortSystems(systems, entities.SortByName)
+	for _, sys := range systems {
+		add(ElementSummary{
+			ID:          sys.ID,
+			Type:        "system",
+			Name:        sys.Name,
+			Description: sys.Description,
+			Tags:        sys.Tags,
+			UpdatedAt:   sys.UpdatedAt,
+			Author:      sys.Author,
+		})
+		containers := sys.ListContainers()
+		entities.SortContainers(containers, entities.SortByName)
+		for _, cont := range containers {
+			containerID := sys.ID + "/" + cont.ID
+			add(ElementSummary{
+				ID:          containerID,
+				Type:        "container",
+				Name:        cont.Name,
+				Description: cont.Description,
+				Technology:  cont.Technology,
+				Tags:        cont.Tags,
+				ParentID:    sys.ID,
+				UpdatedAt:   cont.UpdatedAt,
+				Author:      cont.Author,
+			})
+			components := cont.ListComponents()
+			entities.SortComponents(components, entities.SortByName)
+			for _, comp := range components {
+				add(ElementSummary{
+					ID:          containerID + "/" + comp.ID,
+					Type:        "component",
+					Name:        comp.Name,
+					Description: comp.Description,
+					Technology:  comp.Technology,
+					Tags:        comp.Tags,
+					ParentID:    containerID,
+					UpdatedAt:   comp.UpdatedAt,
+					Author:      comp.Author,
+				})
+			}
+		}
+	}
+
+	total := len(elements)
+	page := elements[min(offset, total):min(offset+limit, total)]
+
+	resp := ElementsResponse{
+		Success:    true,
+		Elements:   page,
+		TotalCount: total,
+		Limit:      limit,
+		Offset:     offset,
+	}
+
+	WriteJSON(w, http.StatusOK, resp)
+}
+
+// queryInt returns a non-negative integer query parameter, or def when it is
+// absent. It writes a 400 response and returns false for an invalid value.
+func queryInt(w http.ResponseWriter, r *http.Request, name string, def int) (int, bool) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, true
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		WriteError(w, http.StatusBadRequest, "INVALID_INPUT", name+" must be a non-negative integer")
+		return 0, false
+	}
+	return n, true
+}
+
+// QueryArchitecture handles GET /api/v1/architecture, returning the same
+// token-efficient views as the query_architecture MCP tool.
+func (h *Handlers) QueryArchitecture(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	detail := r.URL.Query().Get("detail")
+	if detail == "" {
+		detail = "structure"
+	}
+	if detail != "summary" && detail != "structure" && detail != "full" {
+		WriteError(w, http.StatusBadRequest, "INVALID_INPUT", "detail must be one of: summary, structure, full")
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "text"
+	}
+	if format != "text" && format != "json" && format != "toon" {
+		WriteError(w, http.StatusBadRequest, "INVALID_INPUT", "format must be one of: text, json, toon")
+		return
+	}
+
+	result, err := usecases.NewQueryArchitecture(h.repo).ExecuteWithFormat(ctx, h.projectRoot, detail, format)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to query architecture")
+		return
+	}
+
+	resp := ArchitectureResponse{
+		Success:       true,
+		Detail:        result.Detail,
+		Format:        result.Format,
+		Text:          result.Text,
+		TokenEstimate: result.TokenEstimate,
+	}
+	if format == "json" {
+		resp.Data = result.RawData
+	}
+
+	WriteJSON(w, http.StatusOK, resp)
+}
+
+// GetSchema handles GET /api/v1/schemas/{name}, serving the JSON Schema of
+// element frontmatter or loko.toml for editors.
+func (h *Handlers) GetSchema(w http.ResponseWriter, r *http.Request) {
+	schema, err := entities.JSONSchema(r.PathValue("name"))
+	if err != nil {
+		WriteError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(schema)
+}
+
+// sortOrder returns the ?sort= list order, "name" by default. It writes a 400
+// response and returns false for an unknown order.
+func sortOrder(w http.ResponseWriter, r *http.Request) (string, bool) {
+	order := r.URL.Query().Get("sort")
+	if order == "" {
+		return entities.SortByName, true
+	}
+	if !entities.IsSortOrder(order) {
+		WriteError(w, http.StatusBadRequest, "INVALID_INPUT", "sort must be one of: name, updated, created")
+		return "", false
+	}
+	return order, true
+}
+
+// systemSummary summarizes a system for API responses.
+func systemSummary(sys *entities.System) SystemSummary {
+	return SystemSummary{
+		ID:             sys.ID,
+		Name:           sys.Name,
+		Description:    sys.Description,
+		ContainerCount: sys.ContainerCount(),
+		ComponentCount: sys.ComponentCount(),
+		Tags:           sys.Tags,
+		CreatedAt:      sys.CreatedAt,
+		UpdatedAt:      sys.UpdatedAt,
+		Author:         sys.Author,
+		ContentHash:    sys.ContentHash,
+	}
+}
+
+// TriggerBuild handles POST /api/v1/build.
+func (h *Handlers) TriggerBuild(w http.ResponseWriter, r *http.Request) {
 	// Parse request body
 	var req BuildRequest
 	if r.Body != nil {
@@ -181,59 +521,90 @@ func (h *Handlers) TriggerBuild(w http.ResponseWriter, r *http.Request) {
 	if req.Format == "" {
 		req.Format = "html"
 	}
+	if h.resolvePath != nil {
+		outputDir, err := h.resolvePath(req.OutputDir)
+		if err != nil {
+			WriteError(w, http.StatusForbidden, "PATH_NOT_PERMITTED", err.Error())
+			return
+		}
+		req.OutputDir = outputDir
+	}
+	if project, err := h.repo.LoadProject(r.Context(), h.projectRoot); err == nil {
+		if err := usecases.EnsurePlaintextAllowed(project, h.projectRoot, req.AllowPlaintext); err != nil {
+			WriteError(w, http.StatusForbidden, "ENCRYPTED_SOURCES", err.Error())
+			return
+		}
+	}
+
+	// The build log store issues the ID, so it names the stored log
+	var logID string
+	if h.buildLogs != nil {
+		id, err := h.buildLogs.Create(r.Context())
+		if err == nil {
+			logID = id
+		}
+	}
 
-	// Create build ID
+	// Create build ID and queue the build
 	h.buildMutex.Lock()
-	h.buildID++
-	buildID := formatBuildID(h.buildID)
+	buildID := logID
+	for buildID == "" || (logID == "" && h.builds[buildID] != nil) {
+		h.buildID++
+		buildID = formatBuildID(h.buildID)
+	}
 	status := &buildStatus{
 		ID:        buildID,
-		Status:    "building",
-		StartTime: time.Now(),
+		Status:    "queued",
+		QueuedAt:  time.Now(),
 		OutputDir: req.OutputDir,
+		request:   req,
+		stored:    logID != "",
 	}
 	h.builds[buildID] = status
+	superseded := h.enqueueBuild(status)
+	h.startQueuedBuilds()
+	resp := BuildResponse{
+		Success:       true,
+		BuildID:       buildID,
+		Status:        status.Status,
+		QueuePosition: h.queuePosition(status),
+		Message:       "Build started",
+	}
 	h.buildMutex.Unlock()
 
-	// Start build in background
-	go h.executeBuild(ctx, buildID, req)
-
-	// Return immediately with build ID
-	resp := BuildResponse{
-		Success: true,
-		BuildID: buildID,
-		Status:  "building",
-		Message: "Build started",
+	for _, id := range superseded {
+		h.publishBuildResult(id)
+	}
+	if resp.Status == "queued" {
+		resp.Message = "Build queued"
 	}
 
+	// Return immediately with build ID
 	WriteJSON(w, http.StatusAccepted, resp)
 }
 
-// executeBuild runs the build process.
-func (h *Handlers) executeBuild(ctx context.Context, buildID string, req BuildRequest) {
-	h.buildMutex.Lock()
-	status := h.builds[buildID]
-	h.buildMutex.Unlock()
-
+// build loads the project and builds its documentation as req asks.
+func (h *Handlers) build(ctx context.Context, buildID string, req BuildRequest) error {
 	// Load project and systems
 	project, err := h.repo.LoadProject(ctx, h.projectRoot)
 	if err != nil {
-		h.failBuild(buildID, "failed to load project: "+err.Error())
-		return
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	// Sources may have been encrypted while the build was queued
+	if err := usecases.EnsurePlaintextAllowed(project, h.projectRoot, req.AllowPlaintext); err != nil {
+		return err
 	}
 
 	systems, err := h.repo.ListSystems(ctx, h.projectRoot)
 	if err != nil {
-		h.failBuild(buildID, "failed to list systems: "+err.Error())
-		return
+		return fmt.Errorf("failed to list systems: %w", err)
 	}
 
 	// Create adapters
 	renderer := d2.NewRenderer()
 	siteBuilder, err := html.NewBuilder()
 	if err != nil {
-		h.failBuild(buildID, "failed to create site builder: "+err.Error())
-		return
+		return fmt.Errorf("failed to create site builder: %w", err)
 	}
 
 	// Create progress reporter that updates build status
@@ -244,18 +615,33 @@ func (h *Handlers) executeBuild(ctx context.Context, buildID string, req BuildRe
 
 	// Execute build
 	buildDocs := usecases.NewBuildDocs(renderer, siteBuilder, progressReporter)
-	err = buildDocs.Execute(ctx, project, systems, req.OutputDir)
-
-	h.buildMutex.Lock()
-	defer h.buildMutex.Unlock()
+	return buildDocs.Execute(ctx, project, systems, req.OutputDir)
+}
 
-	if err != nil {
-		status.Status = "failed"
-		status.Error = err.Error()
-	} else {
-		status.Status = "complete"
+// publishBuildResult logs the final status of a build and publishes a build
+// completed, failed or cancelled event for it.
+func (h *Handlers) publishBuildResult(buildID string) {
+	h.buildMutex.RLock()
+	event := entities.ModelEvent{Type: entities.EventBuildCompleted, BuildID: buildID}
+	entry := entities.BuildLogEntry{Level: entities.BuildLogSuccess, Message: "build complete"}
+	if status, ok := h.builds[buildID]; ok {
+		switch status.Status {
+		case "failed":
+			event.Type = entities.EventBuildFailed
+			event.Error = status.Error
+			entry = entities.BuildLogEntry{Level: entities.BuildLogError, Message: "build failed: " + status.Error}
+		case "cancelled":
+			event.Type = entities.EventBuildCancelled
+			event.Error = status.Error
+			entry = entities.BuildLogEntry{Level: entities.BuildLogError, Message: "build cancelled"}
+			if status.Error != "cancelled" {
+				entry.Message += ": " + status.Error
+			}
+		}
 	}
-	status.EndTime = time.Now()
+	h.buildMutex.RUnlock()
+	h.logBuild(buildID, entry)
+	h.publish(event)
 }
 
 // failBuild marks a build as failed.
@@ -281,36 +667,44 @@ func (h *Handlers) GetBuildStatus(w http.ResponseWriter, r *http.Request) {
 
 	h.buildMutex.RLock()
 	status, ok := h.builds[buildID]
-	h.buildMutex.RUnlock()
-
 	if !ok {
+		h.buildMutex.RUnlock()
 		WriteError(w, http.StatusNotFound, "NOT_FOUND", "build not found")
 		return
 	}
 
 	var durationMS int64
-	if !status.EndTime.IsZero() {
+	switch {
+	case status.StartTime.IsZero():
+		// Not started yet
+	case !status.EndTime.IsZero():
 		durationMS = status.EndTime.Sub(status.StartTime).Milliseconds()
-	} else {
+	default:
 		durationMS = time.Since(status.StartTime).Milliseconds()
 	}
 
 	resp := BuildResponse{
-		Success:          status.Status != "failed",
+		Success:          status.Status != "failed" && status.Status != "cancelled",
 		BuildID:          status.ID,
 		Status:           status.Status,
+		QueuePosition:    h.queuePosition(status),
 		DurationMS:       durationMS,
 		OutputDir:        status.OutputDir,
 		FilesGenerated:   status.FilesGenerated,
 		DiagramsRendered: status.DiagramsRendered,
 		Error:            status.Error,
 	}
+	h.buildMutex.RUnlock()
 
-	switch status.Status {
+	switch resp.Status {
 	case "complete":
 		resp.Message = "Build completed successfully"
 	case "failed":
 		resp.Message = "Build failed"
+	case "cancelled":
+		resp.Message = "Build cancelled"
+	case "queued":
+		resp.Message = fmt.Sprintf("Build queued (position %d)", resp.QueuePosition)
 	default:
 		resp.Message = "Build in progress"
 	}
@@ -385,19 +779,21 @@ type buildProgressReporter struct {
 }
 
 func (r *buildProgressReporter) ReportProgress(step string, current, total int, message string) {
-	// Update build status with progress
+	r.handler.logBuild(r.buildID, entities.BuildLogEntry{Level: entities.BuildLogProgress, Step: step, Current: current, Total: total, Message: message})
 }
 
 func (r *buildProgressReporter) ReportError(err error) {
+	r.handler.logBuild(r.buildID, entities.BuildLogEntry{Level: entities.BuildLogError, Message: err.Error()})
 	r.handler.failBuild(r.buildID, err.Error())
 }
 
 func (r *buildProgressReporter) ReportSuccess(message string) {
 	// Build success is handled by executeBuild
+	r.handler.logBuild(r.buildID, entities.BuildLogEntry{Level: entities.BuildLogSuccess, Message: message})
 }
 
 func (r *buildProgressReporter) ReportInfo(message string) {
-	// Log info if needed
+	r.handler.logBuild(r.buildID, entities.BuildLogEntry{Level: entities.BuildLogInfo, Message: message})
 }
 
 // formatBuildID generates a build ID.
@@ -434,9 +830,10 @@ func WriteError(w http.ResponseWriter, status int, code, message string) {
 
 // BuildRequest is the request body for POST /api/v1/build.
 type BuildRequest struct {
-	Format      string `json:"format,omitempty"`
-	Incremental bool   `json:"incremental,omitempty"`
-	OutputDir   string `json:"output_dir,omitempty"`
+	Format         string `json:"format,omitempty"`
+	Incremental    bool   `json:"incremental,omitempty"`
+	OutputDir      string `json:"output_dir,omitempty"`
+	AllowPlaintext bool   `json:"allow_plaintext,omitempty"` // Build even when the sources are encrypted at rest
 }
 
 // BuildResponse is the response for POST /api/v1/build.
@@ -444,6 +841,7 @@ type BuildResponse struct {
 	Success          bool   `json:"success"`
 	BuildID          string `json:"build_id,omitempty"`
 	Status           string `json:"status"`
+	QueuePosition    int    `json:"queue_position,omitempty"` // 1 for the next build to start; set while queued
 	DurationMS       int64  `json:"duration_ms,omitempty"`
 	OutputDir        string `json:"output_dir,omitempty"`
 	FilesGenerated   int    `json:"files_generated,omitempty"`
@@ -454,12 +852,16 @@ type BuildResponse struct {
 
 // SystemSummary is a summary of a system for API responses.
 type SystemSummary struct {
-	ID             string   `json:"id"`
-	Name           string   `json:"name"`
-	Description    string   `json:"description,omitempty"`
-	ContainerCount int      `json:"container_count"`
-	ComponentCount int      `json:"component_count"`
-	Tags           []string `json:"tags,omitempty"`
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description,omitempty"`
+	ContainerCount int       `json:"container_count"`
+	ComponentCount int       `json:"component_count"`
+	Tags           []string  `json:"tags,omitempty"`
+	CreatedAt      time.Time `json:"created_at,omitzero"`
+	UpdatedAt      time.Time `json:"updated_at,omitzero"`
+	Author         string    `json:"author,omitempty"`
+	ContentHash    string    `json:"content_hash,omitempty"`
 }
 
 // SystemsResponse is the response for GET /api/v1/systems.
@@ -472,12 +874,72 @@ type SystemsResponse struct {
 
 // ContainerSummary is a summary of a container for API responses.
 type ContainerSummary struct {
-	ID             string   `json:"id"`
-	Name           string   `json:"name"`
-	Description    string   `json:"description,omitempty"`
-	Technology     string   `json:"technology,omitempty"`
-	ComponentCount int      `json:"component_count"`
-	Tags           []string `json:"tags,omitempty"`
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description,omitempty"`
+	Technology     string    `json:"technology,omitempty"`
+	ComponentCount int       `json:"component_count"`
+	Tags           []string  `json:"tags,omitempty"`
+	CreatedAt      time.Time `json:"created_at,omitzero"`
+	UpdatedAt      time.Time `json:"updated_at,omitzero"`
+	Author         string    `json:"author,omitempty"`
+	ContentHash    string    `json:"content_hash,omitempty"`
+}
+
+// ComponentSummary is a summary of a component for API responses.
+type ComponentSummary struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Technology  string    `json:"technology,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitzero"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero"`
+	Author      string    `json:"author,omitempty"`
+	ContentHash string    `json:"content_hash,omitempty"`
+}
+
+// ComponentsResponse is the response for
+// GET /api/v1/systems/:id/containers/:cid/components.
+type ComponentsResponse struct {
+	Success     bool               `json:"success"`
+	SystemID    string             `json:"system_id"`
+	ContainerID string             `json:"container_id"`
+	Components  []ComponentSummary `json:"components"`
+	TotalCount  int                `json:"total_count"`
+}
+
+// ElementSummary is a system, container or component in the flattened
+// element listing. ID and ParentID are slash-separated qualified IDs.
+type ElementSummary struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"` // "system", "container" or "component"
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Technology  string    `json:"technology,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	ParentID    string    `json:"parent_id,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero"`
+	Author      string    `json:"author,omitempty"`
+}
+
+// ElementsResponse is the response for GET /api/v1/elements.
+type ElementsResponse struct {
+	Success    bool             `json:"success"`
+	Elements   []ElementSummary `json:"elements"`
+	TotalCount int              `json:"total_count"` // Matching elements across all pages
+	Limit      int              `json:"limit"`
+	Offset     int              `json:"offset"`
+}
+
+// ArchitectureResponse is the response for GET /api/v1/architecture.
+type ArchitectureResponse struct {
+	Success       bool   `json:"success"`
+	Detail        string `json:"detail"`
+	Format        string `json:"format"`
+	Text          string `json:"text"`
+	TokenEstimate int    `json:"token_estimate"`
+	Data          any    `json:"data,omitempty"` // Structured view, for format=json
 }
 
 // SystemDetailResponse is the response for GET /api/v1/systems/:id.
@@ -3,10 +3,14 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
 )
@@ -162,6 +166,154 @@ func TestGetSystem(t *testing.T) {
 	}
 }
 
+func TestListComponents(t *testing.T) {
+	project, systems := createTestProject()
+	comp, _ := entities.NewComponent("Auth Handler")
+	comp.Technology = "Go"
+	systems[0].Containers["api"].AddComponent(comp)
+	repo := &MockProjectRepository{project: project, systems: systems}
+	h := NewHandlers(".", repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/systems/authservice/containers/api/components", nil)
+	req.SetPathValue("id", "authservice")
+	req.SetPathValue("cid", "api")
+	w := httptest.NewRecorder()
+
+	h.ListComponents(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var resp ComponentsResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	if resp.TotalCount != 1 || resp.Components[0].Name != "Auth Handler" {
+		t.Errorf("expected Auth Handler component, got %+v", resp.Components)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/systems/authservice/containers/missing/components", nil)
+	req.SetPathValue("id", "authservice")
+	req.SetPathValue("cid", "missing")
+	w = httptest.NewRecorder()
+	h.ListComponents(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown container, got %d", w.Code)
+	}
+}
+
+func TestListElements(t *testing.T) {
+	project, systems := createTestProject()
+	repo := &MockProjectRepository{project: project, systems: systems}
+	h := NewHandlers(".", repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/elements", nil)
+	w := httptest.NewRecorder()
+
+	h.ListElements(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var resp ElementsResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	var ids []string
+	for _, elem := range resp.Elements {
+		ids = append(ids, elem.ID)
+	}
+	if got := strings.Join(ids, ","); got != "authservice,authservice/api,userservice" {
+		t.Errorf("expected elements in hierarchy order, got %s", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/elements?tech=go&limit=1&offset=0", nil)
+	w = httptest.NewRecorder()
+	h.ListElements(w, req)
+
+	resp = ElementsResponse{}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.TotalCount != 1 || len(resp.Elements) != 1 || resp.Elements[0].ParentID != "authservice" {
+		t.Errorf("expected the Go container, got %+v", resp)
+	}
+
+	for _, query := range []string{"type=person", "limit=-1", "offset=x"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/elements?"+query, nil)
+		w := httptest.NewRecorder()
+		h.ListElements(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestQueryArchitecture(t *testing.T) {
+	project, systems := createTestProject()
+	repo := &MockProjectRepository{project: project, systems: systems}
+	h := NewHandlers(".", repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/architecture?detail=structure&format=toon", nil)
+	w := httptest.NewRecorder()
+
+	h.QueryArchitecture(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var resp ArchitectureResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	if resp.Detail != "structure" || resp.Format != "toon" {
+		t.Errorf("expected structure/toon, got %s/%s", resp.Detail, resp.Format)
+	}
+	if !strings.Contains(resp.Text, "S:AuthService") {
+		t.Errorf("expected TOON text listing AuthService, got %q", resp.Text)
+	}
+	if resp.TokenEstimate == 0 {
+		t.Error("expected a token estimate")
+	}
+
+	for _, query := range []string{"detail=everything", "format=xml"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/architecture?"+query, nil)
+		w := httptest.NewRecorder()
+		h.QueryArchitecture(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestGetSchema(t *testing.T) {
+	h := NewHandlers(".", &MockProjectRepository{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/schemas/config", nil)
+	req.SetPathValue("name", "config")
+	w := httptest.NewRecorder()
+
+	h.GetSchema(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/schema+json" {
+		t.Errorf("expected schema content type, got %s", ct)
+	}
+	var schema map[string]any
+	json.NewDecoder(w.Body).Decode(&schema)
+	if schema["title"] != "loko.toml" {
+		t.Errorf("expected loko.toml schema, got %v", schema["title"])
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/schemas/person", nil)
+	req.SetPathValue("name", "person")
+	w = httptest.NewRecorder()
+	h.GetSchema(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown schema, got %d", w.Code)
+	}
+}
+
 func TestValidate(t *testing.T) {
 	project, systems := createTestProject()
 	repo := &MockProjectRepository{project: project, systems: systems}
@@ -192,6 +344,12 @@ func TestTriggerBuild(t *testing.T) {
 	project, systems := createTestProject()
 	repo := &MockProjectRepository{project: project, systems: systems}
 	h := NewHandlers(".", repo)
+	// The build itself is stubbed so the test writes no site
+	started := make(chan BuildRequest, 1)
+	h.runBuild = func(_ context.Context, _ string, req BuildRequest) error {
+		started <- req
+		return nil
+	}
 
 	body := strings.NewReader(`{"format":"html","output_dir":"dist"}`)
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/build", body)
@@ -203,6 +361,14 @@ func TestTriggerBuild(t *testing.T) {
 	if w.Code != http.StatusAccepted {
 		t.Errorf("expected status 202, got %d", w.Code)
 	}
+	select {
+	case got := <-started:
+		if got.Format != "html" || got.OutputDir != "dist" {
+			t.Errorf("build started with %+v, want html into dist", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("build was not started")
+	}
 
 	var resp BuildResponse
 	json.NewDecoder(w.Body).Decode(&resp)
@@ -217,3 +383,255 @@ func TestTriggerBuild(t *testing.T) {
 		t.Error("expected build ID to be set")
 	}
 }
+
+func TestTriggerBuildSandbox(t *testing.T) {
+	project, systems := createTestProject()
+	repo := &MockProjectRepository{project: project, systems: systems}
+	h := NewHandlers(".", repo).WithSandbox(func(path string) (string, error) {
+		if strings.Contains(path, "..") {
+			return "", errors.New("path is outside the sandbox roots")
+		}
+		return "/srv/project/" + path, nil
+	})
+
+	body := strings.NewReader(`{"format":"html","output_dir":"../../etc"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/build", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	h.TriggerBuild(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d", w.Code)
+	}
+	var resp ErrorResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Code != "PATH_NOT_PERMITTED" {
+		t.Errorf("expected code PATH_NOT_PERMITTED, got %q", resp.Code)
+	}
+}
+
+func TestTriggerBuildEncryptedSources(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "src", "shop"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "src", "shop", "system.md.age"), []byte("age"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	project, systems := createTestProject()
+	h := NewHandlers(root, &MockProjectRepository{project: project, systems: systems})
+	started := make(chan BuildRequest, 1)
+	h.runBuild = func(_ context.Context, _ string, req BuildRequest) error {
+		started <- req
+		return nil
+	}
+	trigger := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/build", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		h.TriggerBuild(w, req)
+		return w
+	}
+
+	w := trigger(`{"output_dir":"dist"}`)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d", w.Code)
+	}
+	var resp ErrorResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Code != "ENCRYPTED_SOURCES" {
+		t.Errorf("expected code ENCRYPTED_SOURCES, got %q", resp.Code)
+	}
+
+	if w := trigger(`{"output_dir":"dist","allow_plaintext":true}`); w.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202 with allow_plaintext, got %d", w.Code)
+	}
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("build was not started")
+	}
+}
+
+func TestConditionalGet(t *testing.T) {
+	project, systems := createTestProject()
+	systems[0].ContentHash = entities.HashContent([]byte("v1"))
+	repo := &MockProjectRepository{project: project, systems: systems}
+	h := NewHandlers(".", repo)
+
+	get := func(handler http.HandlerFunc, path, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if strings.HasPrefix(path, "/api/v1/systems/") {
+			req.SetPathValue("id", strings.TrimPrefix(path, "/api/v1/systems/"))
+		}
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w
+	}
+
+	for _, tc := range []struct {
+		handler http.HandlerFunc
+		path    string
+	}{
+		{h.GetProject, "/api/v1/project"},
+		{h.ListSystems, "/api/v1/systems"},
+		{h.GetSystem, "/api/v1/systems/authservice"},
+	} {
+		first := get(tc.handler, tc.path, "")
+		etag := first.Header().Get("ETag")
+		if first.Code != http.StatusOK || etag == "" {
+			t.Fatalf("%s: status %d, ETag %q", tc.path, first.Code, etag)
+		}
+
+		if w := get(tc.handler, tc.path, `"other", W/`+etag); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+			t.Errorf("%s: unchanged model answered %d with %d bytes", tc.path, w.Code, w.Body.Len())
+		}
+
+		systems[0].ContentHash = entities.HashContent([]byte(etag))
+		if w := get(tc.handler, tc.path, etag); w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+			t.Errorf("%s: changed model answered %d with ETag %q", tc.path, w.Code, w.Header().Get("ETag"))
+		}
+	}
+
+	// Each sort order has its own tag
+	byName := get(h.ListSystems, "/api/v1/systems", "").Header().Get("ETag")
+	if byUpdated := get(h.ListSystems, "/api/v1/systems?sort=updated", "").Header().Get("ETag"); byUpdated == byName {
+		t.Error("sort orders share an ETag")
+	}
+}
+
+func TestBuildQueue(t *testing.T) {
+	h := NewHandlers(".", &MockProjectRepository{})
+	release := make(chan struct{})
+	h.runBuild = func(ctx context.Context, buildID string, req BuildRequest) error {
+		select {
+		case <-release:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	trigger := func(outputDir string) BuildResponse {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/build", strings.NewReader(`{"output_dir":"`+outputDir+`"}`))
+		w := httptest.NewRecorder()
+		h.TriggerBuild(w, req)
+		var resp BuildResponse
+		_ = json.NewDecoder(w.Body).Decode(&resp)
+		return resp
+	}
+	status := func(buildID string) BuildResponse {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/build/"+buildID, nil)
+		req.SetPathValue("id", buildID)
+		w := httptest.NewRecorder()
+		h.GetBuildStatus(w, req)
+		var resp BuildResponse
+		_ = json.NewDecoder(w.Body).Decode(&resp)
+		return resp
+	}
+	waitFor := func(buildID, want string) BuildResponse {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			resp := status(buildID)
+			if resp.Status == want {
+				return resp
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("build %s is %s, want %s", buildID, resp.Status, want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	first := trigger("dist")
+	if first.Status != "building" {
+		t.Fatalf("first build is %s, want building", first.Status)
+	}
+	site := trigger("site")
+	if site.Status != "queued" || site.QueuePosition != 1 {
+		t.Errorf("second build = %s at %d, want queued at 1", site.Status, site.QueuePosition)
+	}
+
+	// A newer build of dist supersedes the running one, then waits its turn
+	second := trigger("dist")
+	if second.QueuePosition != 2 {
+		t.Errorf("newer dist build queued at %d, want 2", second.QueuePosition)
+	}
+	if resp := waitFor(first.BuildID, "cancelled"); resp.Success || !strings.Contains(resp.Error, second.BuildID) {
+		t.Errorf("superseded build = %+v", resp)
+	}
+	waitFor(site.BuildID, "building")
+	if resp := status(second.BuildID); resp.QueuePosition != 1 || resp.Message != "Build queued (position 1)" {
+		t.Errorf("newer dist build = %+v, want queued at 1", resp)
+	}
+
+	// Queued builds of the same output directory are replaced
+	third := trigger("dist")
+	if resp := status(second.BuildID); resp.Status != "cancelled" {
+		t.Errorf("replaced queued build is %s, want cancelled", resp.Status)
+	}
+
+	close(release)
+	waitFor(site.BuildID, "complete")
+	waitFor(third.BuildID, "complete")
+}
+
+func TestGetBuildLog(t *testing.T) {
+	h := NewHandlers(".", &MockProjectRepository{})
+	done := make(chan struct{})
+	h.runBuild = func(ctx context.Context, buildID string, req BuildRequest) error {
+		defer close(done)
+		reporter := &buildProgressReporter{handler: h, buildID: buildID}
+		reporter.ReportProgress("Rendering diagrams", 1, 1, "api.d2")
+		return errors.New("d2 failed")
+	}
+
+	w := httptest.NewRecorder()
+	h.TriggerBuild(w, httptest.NewRequest(http.MethodPost, "/api/v1/build", strings.NewReader(`{}`)))
+	var build BuildResponse
+	_ = json.NewDecoder(w.Body).Decode(&build)
+	<-done
+
+	getLog := func(buildID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/build/"+buildID+"/log", nil)
+		req.SetPathValue("id", buildID)
+		w := httptest.NewRecorder()
+		h.GetBuildLog(w, req)
+		return w
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	var lines []string
+	for {
+		w = getLog(build.BuildID)
+		lines = strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		if len(lines) == 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("log = %q", w.Body.String())
+	}
+	var progress, outcome entities.BuildLogEntry
+	_ = json.Unmarshal([]byte(lines[0]), &progress)
+	_ = json.Unmarshal([]byte(lines[1]), &outcome)
+	if progress.Level != entities.BuildLogProgress || progress.Step != "Rendering diagrams" || progress.Time.IsZero() {
+		t.Errorf("progress entry = %+v", progress)
+	}
+	if outcome.Level != entities.BuildLogError || outcome.Message != "build failed: d2 failed" {
+		t.Errorf("outcome entry = %+v", outcome)
+	}
+
+	if w := getLog("missing"); w.Code != http.StatusNotFound {
+		t.Errorf("unknown build = %d", w.Code)
+	}
+}
@@ -2,13 +2,23 @@
 package middleware
 
 import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strings"
 	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
 )
 
+// maxAuditedBodySize limits how much of a request body is recorded in the audit log.
+const maxAuditedBodySize = 1 << 20
+
 // writeJSONError writes a JSON error response with the proper Content-Type header.
 func writeJSONError(w http.ResponseWriter, statusCode int, errMsg, code string) {
 	w.Header().Set("Content-Type", "application/json")
@@ -18,6 +28,13 @@ func writeJSONError(w http.ResponseWriter, statusCode int, errMsg, code string)
 
 // Auth returns middleware that validates bearer token authentication.
 func Auth(apiKey string) func(http.Handler) http.Handler {
+	return AuthRoles(map[string]entities.Role{apiKey: entities.RoleEditor})
+}
+
+// AuthRoles returns middleware that validates bearer tokens against keys,
+// which maps each API key to its role. Reader keys may only make read
+// requests (GET, HEAD and OPTIONS).
+func AuthRoles(keys map[string]entities.Role) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Skip auth for health check
@@ -26,8 +43,12 @@ func Auth(apiKey string) func(http.Handler) http.Handler {
 				return
 			}
 
-			// Get Authorization header
+			// Get Authorization header. Browsers cannot set headers on
+			// WebSocket connections, so those may pass ?token= instead.
 			authHeader := r.Header.Get("Authorization")
+			if token := r.URL.Query().Get("token"); authHeader == "" && token != "" && isWebSocketUpgrade(r) {
+				authHeader = "Bearer " + token
+			}
 			if authHeader == "" {
 				writeJSONError(w, http.StatusUnauthorized, "missing authorization header", "UNAUTHORIZED")
 				return
@@ -40,16 +61,113 @@ func Auth(apiKey string) func(http.Handler) http.Handler {
 				return
 			}
 
-			if parts[1] != apiKey {
+			role, ok := lookupKey(keys, parts[1])
+			if !ok {
 				writeJSONError(w, http.StatusUnauthorized, "invalid api key", "UNAUTHORIZED")
 				return
 			}
+			if !role.CanModify() && !readOnlyMethod(r.Method) {
+				writeJSONError(w, http.StatusForbidden, "api key is read-only", "FORBIDDEN")
+				return
+			}
 
 			next.ServeHTTP(w, r)
 		})
 	}
 }
 
+// lookupKey returns the role of token, comparing every key in constant time.
+func lookupKey(keys map[string]entities.Role, token string) (entities.Role, bool) {
+	var role entities.Role
+	found := false
+	for key, keyRole := range keys {
+		if key != "" && subtle.ConstantTimeCompare([]byte(key), []byte(token)) == 1 {
+			role, found = keyRole, true
+		}
+	}
+	return role, found
+}
+
+// readOnlyMethod reports whether method only reads resources.
+func readOnlyMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// Auditor records write requests and the changes they make.
+type Auditor interface {
+	Track(ctx context.Context, entry *entities.AuditEntry, change func() error) error
+}
+
+// Audit returns middleware that records every write request (any method but
+// GET, HEAD and OPTIONS) in the audit log. The caller is identified by the
+// X-Loko-Actor header, falling back to the User-Agent.
+func Audit(auditor Auditor) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if readOnlyMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			entry := &entities.AuditEntry{
+				Source:    entities.AuditSourceAPI,
+				Actor:     auditActor(r),
+				Action:    r.Method + " " + r.URL.Path,
+				Arguments: auditArguments(r),
+			}
+			wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			err := auditor.Track(r.Context(), entry, func() error {
+				next.ServeHTTP(wrapped, r)
+				if wrapped.statusCode >= http.StatusBadRequest {
+					return fmt.Errorf("%d %s", wrapped.statusCode, http.StatusText(wrapped.statusCode))
+				}
+				return nil
+			})
+			if err != nil && entry.Error == "" {
+				_, _ = fmt.Fprintf(os.Stderr, "audit: %v\n", err)
+			}
+		})
+	}
+}
+
+// auditActor identifies the caller of a request.
+func auditActor(r *http.Request) string {
+	actor := r.Header.Get("X-Loko-Actor")
+	if actor == "" {
+		actor = r.UserAgent()
+	}
+	if actor == "" {
+		actor = "unknown"
+	}
+	return actor + " (" + r.RemoteAddr + ")"
+}
+
+// auditArguments returns the JSON object in the request body, or the raw body
+// under "body" when it is not one. The body is restored for the handler.
+func auditArguments(r *http.Request) map[string]any {
+	if r.Body == nil {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxAuditedBodySize))
+	if err != nil {
+		return nil
+	}
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	var args map[string]any
+	if err := json.Unmarshal(body, &args); err != nil {
+		return map[string]any{"body": string(body)}
+	}
+	return args
+}
+
 // Logger returns middleware that logs HTTP requests.
 func Logger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -76,7 +194,8 @@ func CORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-None-Match")
+		w.Header().Set("Access-Control-Expose-Headers", "ETag")
 
 		// Handle preflight requests
 		if r.Method == http.MethodOptions {
@@ -112,3 +231,14 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.statusCode = code
 	rw.ResponseWriter.WriteHeader(code)
 }
+
+// Unwrap returns the wrapped writer, so http.ResponseController can hijack
+// WebSocket connections.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// isWebSocketUpgrade reports whether r asks to switch to WebSocket.
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// okHandler answers every request it is reached with 200 OK.
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+// TestAuthRoles verifies that reader keys may only read, editor keys may
+// read and write, and unknown or missing keys are rejected.
+func TestAuthRoles(t *testing.T) {
+	keys := map[string]entities.Role{
+		"reader-key": entities.RoleReader,
+		"editor-key": entities.RoleEditor,
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		header string
+		want   int
+	}{
+		{"reader GET", http.MethodGet, "/api/v1/project", "Bearer reader-key", http.StatusOK},
+		{"reader HEAD", http.MethodHead, "/api/v1/project", "Bearer reader-key", http.StatusOK},
+		{"reader OPTIONS", http.MethodOptions, "/api/v1/project", "Bearer reader-key", http.StatusOK},
+		{"reader POST", http.MethodPost, "/api/v1/build", "Bearer reader-key", http.StatusForbidden},
+		{"reader PUT", http.MethodPut, "/api/v1/systems/api", "Bearer reader-key", http.StatusForbidden},
+		{"reader DELETE", http.MethodDelete, "/api/v1/systems/api", "Bearer reader-key", http.StatusForbidden},
+		{"editor GET", http.MethodGet, "/api/v1/project", "Bearer editor-key", http.StatusOK},
+		{"editor POST", http.MethodPost, "/api/v1/build", "Bearer editor-key", http.StatusOK},
+		{"editor DELETE", http.MethodDelete, "/api/v1/systems/api", "Bearer editor-key", http.StatusOK},
+		{"lowercase scheme", http.MethodGet, "/api/v1/project", "bearer editor-key", http.StatusOK},
+		{"unknown key", http.MethodGet, "/api/v1/project", "Bearer other-key", http.StatusUnauthorized},
+		{"empty key", http.MethodGet, "/api/v1/project", "Bearer ", http.StatusUnauthorized},
+		{"missing header", http.MethodGet, "/api/v1/project", "", http.StatusUnauthorized},
+		{"basic scheme", http.MethodGet, "/api/v1/project", "Basic editor-key", http.StatusUnauthorized},
+		{"health without key", http.MethodGet, "/health", "", http.StatusOK},
+	}
+
+	handler := AuthRoles(keys)(okHandler)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+// TestAuth verifies that the single key of legacy configurations keeps full
+// access.
+func TestAuth(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		header string
+		want   int
+	}{
+		{"GET", http.MethodGet, "Bearer secret", http.StatusOK},
+		{"POST", http.MethodPost, "Bearer secret", http.StatusOK},
+		{"PUT", http.MethodPut, "Bearer secret", http.StatusOK},
+		{"DELETE", http.MethodDelete, "Bearer secret", http.StatusOK},
+		{"wrong key", http.MethodGet, "Bearer other", http.StatusUnauthorized},
+	}
+
+	handler := Auth("secret")(okHandler)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/project", nil)
+			req.Header.Set("Authorization", tt.header)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+// TestAuthRolesWebSocketToken verifies that only WebSocket upgrades may pass
+// the key in the query string.
+func TestAuthRolesWebSocketToken(t *testing.T) {
+	handler := AuthRoles(map[string]entities.Role{"reader-key": entities.RoleReader})(okHandler)
+
+	tests := []struct {
+		name    string
+		upgrade bool
+		want    int
+	}{
+		{"websocket upgrade", true, http.StatusOK},
+		{"plain request", false, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/ws?token=reader-key", nil)
+			if tt.upgrade {
+				req.Header.Set("Connection", "Upgrade")
+				req.Header.Set("Upgrade", "websocket")
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+// TestLookupKey verifies that tokens match whole keys and that an empty key
+// never matches.
+func TestLookupKey(t *testing.T) {
+	keys := map[string]entities.Role{
+		"reader-key": entities.RoleReader,
+		"editor-key": entities.RoleEditor,
+		"":           entities.RoleEditor,
+	}
+
+	tests := []struct {
+		token    string
+		wantRole entities.Role
+		wantOK   bool
+	}{
+		{"reader-key", entities.RoleReader, true},
+		{"editor-key", entities.RoleEditor, true},
+		{"editor", "", false},
+		{"editor-key-2", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			role, ok := lookupKey(keys, tt.token)
+			if role != tt.wantRole || ok != tt.wantOK {
+				t.Errorf("lookupKey(%q) = %q, %v, want %q, %v", tt.token, role, ok, tt.wantRole, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestReadOnlyMethod verifies which methods a reader key may use.
+func TestReadOnlyMethod(t *testing.T) {
+	tests := []struct {
+		method string
+		want   bool
+	}{
+		{http.MethodGet, true},
+		{http.MethodHead, true},
+		{http.MethodOptions, true},
+		{http.MethodPost, false},
+		{http.MethodPut, false},
+		{http.MethodPatch, false},
+		{http.MethodDelete, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			if got := readOnlyMethod(tt.method); got != tt.want {
+				t.Errorf("readOnlyMethod(%q) = %v, want %v", tt.method, got, tt.want)
+			}
+		})
+	}
+}
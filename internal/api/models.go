@@ -25,12 +25,15 @@ type BuildResponse struct {
 
 // SystemSummary is a summary of a system for API responses.
 type SystemSummary struct {
-	ID             string   `json:"id"`
-	Name           string   `json:"name"`
-	Description    string   `json:"description,omitempty"`
-	ContainerCount int      `json:"container_count"`
-	ComponentCount int      `json:"component_count"`
-	Tags           []string `json:"tags,omitempty"`
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description,omitempty"`
+	ContainerCount int       `json:"container_count"`
+	ComponentCount int       `json:"component_count"`
+	Tags           []string  `json:"tags,omitempty"`
+	CreatedAt      time.Time `json:"created_at,omitzero"`
+	UpdatedAt      time.Time `json:"updated_at,omitzero"`
+	Author         string    `json:"author,omitempty"`
 }
 
 // SystemsResponse is the response for GET /api/v1/systems.
@@ -43,12 +46,70 @@ type SystemsResponse struct {
 
 // ContainerSummary is a summary of a container for API responses.
 type ContainerSummary struct {
-	ID             string   `json:"id"`
-	Name           string   `json:"name"`
-	Description    string   `json:"description,omitempty"`
-	Technology     string   `json:"technology,omitempty"`
-	ComponentCount int      `json:"component_count"`
-	Tags           []string `json:"tags,omitempty"`
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description,omitempty"`
+	Technology     string    `json:"technology,omitempty"`
+	ComponentCount int       `json:"component_count"`
+	Tags           []string  `json:"tags,omitempty"`
+	CreatedAt      time.Time `json:"created_at,omitzero"`
+	UpdatedAt      time.Time `json:"updated_at,omitzero"`
+	Author         string    `json:"author,omitempty"`
+}
+
+// ComponentSummary is a summary of a component for API responses.
+type ComponentSummary struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Technology  string    `json:"technology,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitzero"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero"`
+	Author      string    `json:"author,omitempty"`
+}
+
+// ComponentsResponse is the response for
+// GET /api/v1/systems/:id/containers/:cid/components.
+type ComponentsResponse struct {
+	Success     bool               `json:"success"`
+	SystemID    string             `json:"system_id"`
+	ContainerID string             `json:"container_id"`
+	Components  []ComponentSummary `json:"components"`
+	TotalCount  int                `json:"total_count"`
+}
+
+// ElementSummary is a system, container or component in the flattened
+// element listing. ID and ParentID are slash-separated qualified IDs.
+type ElementSummary struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"` // "system", "container" or "component"
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Technology  string    `json:"technology,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	ParentID    string    `json:"parent_id,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero"`
+	Author      string    `json:"author,omitempty"`
+}
+
+// ElementsResponse is the response for GET /api/v1/elements.
+type ElementsResponse struct {
+	Success    bool             `json:"success"`
+	Elements   []ElementSummary `json:"elements"`
+	TotalCount int              `json:"total_count"` // Matching elements across all pages
+	Limit      int              `json:"limit"`
+	Offset     int              `json:"offset"`
+}
+
+// ArchitectureResponse is the response for GET /api/v1/architecture.
+type ArchitectureResponse struct {
+	Success       bool   `json:"success"`
+	Detail        string `json:"detail"`
+	Format        string `json:"format"`
+	Text          string `json:"text"`
+	TokenEstimate int    `json:"token_estimate"`
+	Data          any    `json:"data,omitempty"` // Structured view, for format=json
 }
 
 // SystemDetailResponse is the response for GET /api/v1/systems/:id.
@@ -9,16 +9,23 @@ import (
 
 	"github.com/madstone-tech/loko/internal/api/handlers"
 	"github.com/madstone-tech/loko/internal/api/middleware"
+	"github.com/madstone-tech/loko/internal/api/websocket"
+	"github.com/madstone-tech/loko/internal/core/entities"
 	"github.com/madstone-tech/loko/internal/core/usecases"
 )
 
 // ServerConfig holds configuration for the API server.
 type ServerConfig struct {
-	Port         int
-	ProjectRoot  string
-	APIKey       string // Optional API key for authentication
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
+	Port           int
+	ProjectRoot    string
+	APIKey         string                            // Optional API key for authentication
+	ReadAPIKey     string                            // Optional API key limited to read requests
+	Auditor        middleware.Auditor                // Optional: records write requests in the audit log
+	BuildLogs      usecases.BuildLogs                // Optional: keeps build logs across restarts
+	Sandbox        func(path string) (string, error) // Optional: confines request paths such as output_dir
+	AllowedOrigins []string                          // Optional: browser origins besides the server's own that may open /api/v1/ws
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
 }
 
 // DefaultConfig returns a default server configuration.
@@ -35,6 +42,7 @@ func DefaultConfig() ServerConfig {
 type Server struct {
 	config     ServerConfig
 	repo       usecases.ProjectRepository
+	events     *websocket.Hub
 	httpServer *http.Server
 	startTime  time.Time
 }
@@ -44,16 +52,34 @@ func NewServer(config ServerConfig, repo usecases.ProjectRepository) *Server {
 	return &Server{
 		config:    config,
 		repo:      repo,
+		events:    websocket.NewHub(),
 		startTime: time.Now(),
 	}
 }
 
+// Events returns the publisher whose events are pushed to /api/v1/ws clients.
+func (s *Server) Events() usecases.EventPublisher {
+	return s.events
+}
+
+// apiKeys maps the configured API keys to their roles.
+func (s *Server) apiKeys() map[string]entities.Role {
+	keys := make(map[string]entities.Role)
+	if s.config.ReadAPIKey != "" {
+		keys[s.config.ReadAPIKey] = entities.RoleReader
+	}
+	if s.config.APIKey != "" {
+		keys[s.config.APIKey] = entities.RoleEditor
+	}
+	return keys
+}
+
 // Start starts the HTTP server.
 func (s *Server) Start(ctx context.Context) error {
 	mux := http.NewServeMux()
 
 	// Create handlers
-	h := handlers.NewHandlers(s.config.ProjectRoot, s.repo)
+	h := handlers.NewHandlers(s.config.ProjectRoot, s.repo).WithEvents(s.events).WithBuildLogs(s.config.BuildLogs).WithBuildContext(ctx).WithSandbox(s.config.Sandbox)
 
 	// Health check (no auth required)
 	mux.HandleFunc("GET /health", s.handleHealth)
@@ -62,16 +88,27 @@ func (s *Server) Start(ctx context.Context) error {
 	mux.HandleFunc("GET /api/v1/project", h.GetProject)
 	mux.HandleFunc("GET /api/v1/systems", h.ListSystems)
 	mux.HandleFunc("GET /api/v1/systems/{id}", h.GetSystem)
+	mux.HandleFunc("GET /api/v1/systems/{id}/containers/{cid}/components", h.ListComponents)
+	mux.HandleFunc("GET /api/v1/elements", h.ListElements)
+	mux.HandleFunc("GET /api/v1/architecture", h.QueryArchitecture)
 	mux.HandleFunc("POST /api/v1/build", h.TriggerBuild)
 	mux.HandleFunc("GET /api/v1/build/{id}", h.GetBuildStatus)
+	mux.HandleFunc("GET /api/v1/build/{id}/log", h.GetBuildLog)
 	mux.HandleFunc("GET /api/v1/validate", h.Validate)
+	mux.HandleFunc("GET /api/v1/schemas/{name}", h.GetSchema)
+	mux.HandleFunc("GET /api/v1/ws", websocket.Handler(s.events, s.config.AllowedOrigins...))
 
 	// Apply middleware chain
 	var handler http.Handler = mux
 
-	// Add auth middleware if API key is configured
-	if s.config.APIKey != "" {
-		handler = middleware.Auth(s.config.APIKey)(handler)
+	// Audit inside auth so only authenticated writes are recorded
+	if s.config.Auditor != nil {
+		handler = middleware.Audit(s.config.Auditor)(handler)
+	}
+
+	// Add auth middleware if API keys are configured
+	if keys := s.apiKeys(); len(keys) > 0 {
+		handler = middleware.AuthRoles(keys)(handler)
 	}
 
 	// Add common middleware
@@ -105,6 +142,8 @@ func (s *Server) Start(ctx context.Context) error {
 
 // Shutdown gracefully shuts down the server.
 func (s *Server) Shutdown() error {
+	// Hijacked WebSocket connections outlive the HTTP server; end them first
+	s.events.Close()
 	if s.httpServer == nil {
 		return nil
 	}
@@ -0,0 +1,194 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// pipeConn returns a server connection and the client end of a pipe.
+func pipeConn(t *testing.T) (*conn, net.Conn) {
+	t.Helper()
+	server, client := net.Pipe()
+	t.Cleanup(func() {
+		_ = server.Close()
+		_ = client.Close()
+	})
+	_ = client.SetDeadline(time.Now().Add(5 * time.Second))
+	return &conn{netConn: server, reader: bufio.NewReader(server)}, client
+}
+
+// maskedFrame encodes a final client frame, masking payload with mask.
+func maskedFrame(opcode byte, payload []byte, mask [4]byte) []byte {
+	frame := []byte{0x80 | opcode, 0x80 | byte(len(payload))}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	return frame
+}
+
+// readServerFrame reads one frame written by the server and checks that it
+// is final and unmasked.
+func readServerFrame(t *testing.T, r io.Reader) (byte, []byte) {
+	t.Helper()
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		t.Fatalf("read frame header: %v", err)
+	}
+	if header[0]&0x80 == 0 {
+		t.Errorf("frame header %#x is not final", header[0])
+	}
+	if header[1]&0x80 != 0 {
+		t.Errorf("server frame is masked")
+	}
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(r, ext); err != nil {
+			t.Fatalf("read 16-bit length: %v", err)
+		}
+		length = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(r, ext); err != nil {
+			t.Fatalf("read 64-bit length: %v", err)
+		}
+		length = binary.BigEndian.Uint64(ext)
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		t.Fatalf("read payload: %v", err)
+	}
+	return header[0] & 0x0F, payload
+}
+
+// TestWriteFrameLengths verifies the 7-bit, 16-bit and 64-bit payload length
+// encodings at their boundaries.
+func TestWriteFrameLengths(t *testing.T) {
+	tests := []struct {
+		length   int
+		wantByte byte // Second header byte
+	}{
+		{0, 0},
+		{125, 125},
+		{126, 126},
+		{0xFFFF, 126},
+		{0x10000, 127},
+	}
+
+	for _, tt := range tests {
+		c, client := pipeConn(t)
+		payload := bytes.Repeat([]byte{'x'}, tt.length)
+		errc := make(chan error, 1)
+		go func() { errc <- c.writeFrame(opText, payload) }()
+
+		reader := bufio.NewReader(client)
+		header, err := reader.Peek(2)
+		if err != nil {
+			t.Fatalf("length %d: peek header: %v", tt.length, err)
+		}
+		if header[1] != tt.wantByte {
+			t.Errorf("length %d: length byte = %d, want %d", tt.length, header[1], tt.wantByte)
+		}
+		opcode, got := readServerFrame(t, reader)
+		if err := <-errc; err != nil {
+			t.Fatalf("length %d: writeFrame() error = %v", tt.length, err)
+		}
+		if opcode != opText || !bytes.Equal(got, payload) {
+			t.Errorf("length %d: got opcode %#x and %d bytes", tt.length, opcode, len(got))
+		}
+	}
+}
+
+// TestReadFrame verifies that client frames are unmasked and that unmasked
+// or oversized frames are rejected.
+func TestReadFrame(t *testing.T) {
+	tests := []struct {
+		name        string
+		frame       []byte
+		wantOpcode  byte
+		wantPayload string
+		wantErr     bool
+	}{
+		{"masked ping", maskedFrame(opPing, []byte("hello"), [4]byte{1, 2, 3, 4}), opPing, "hello", false},
+		{"masked close", maskedFrame(opClose, []byte{0x03, 0xE8}, [4]byte{0xA, 0xB, 0xC, 0xD}), opClose, "\x03\xe8", false},
+		{"empty payload", maskedFrame(opPing, nil, [4]byte{9, 9, 9, 9}), opPing, "", false},
+		{"unmasked", []byte{0x80 | opPing, 5, 'h', 'e', 'l', 'l', 'o'}, 0, "", true},
+		{"too large", []byte{0x80 | opText, 0x80 | 126, 0, 200}, 0, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, client := pipeConn(t)
+			go func() { _, _ = client.Write(tt.frame) }()
+
+			opcode, payload, err := c.readFrame()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readFrame() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if opcode != tt.wantOpcode || string(payload) != tt.wantPayload {
+				t.Errorf("readFrame() = %#x %q, want %#x %q", opcode, payload, tt.wantOpcode, tt.wantPayload)
+			}
+		})
+	}
+}
+
+// TestReadLoop verifies that pings are answered and that the close code the
+// handler sends follows the client's close frame or protocol error.
+func TestReadLoop(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame []byte
+		want  uint16
+	}{
+		{"close with code", maskedFrame(opClose, []byte{0x03, 0xE9}, [4]byte{1, 2, 3, 4}), closeGoingAway},
+		{"close without code", maskedFrame(opClose, nil, [4]byte{1, 2, 3, 4}), closeNormal},
+		{"unmasked frame", []byte{0x80 | opText, 0}, closeProtocol},
+		{"oversized frame", []byte{0x80 | opText, 0x80 | 127}, closeTooBig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, client := pipeConn(t)
+			closed := make(chan uint16, 1)
+			go c.readLoop(closed)
+
+			// Pings are answered with a pong echoing their payload.
+			go func() { _, _ = client.Write(maskedFrame(opPing, []byte("beat"), [4]byte{7, 7, 7, 7})) }()
+			opcode, payload := readServerFrame(t, client)
+			if opcode != opPong || string(payload) != "beat" {
+				t.Errorf("reply = %#x %q, want pong \"beat\"", opcode, payload)
+			}
+
+			if _, err := client.Write(tt.frame); err != nil && !errors.Is(err, io.ErrClosedPipe) {
+				t.Fatalf("write frame: %v", err)
+			}
+			select {
+			case code := <-closed:
+				if code != tt.want {
+					t.Errorf("close code = %d, want %d", code, tt.want)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("readLoop did not report a close code")
+			}
+		})
+	}
+}
+
+// TestClose verifies that the close frame carries the status code.
+func TestClose(t *testing.T) {
+	c, client := pipeConn(t)
+	go c.close(closeGoingAway)
+
+	opcode, payload := readServerFrame(t, client)
+	if opcode != opClose || len(payload) != 2 || binary.BigEndian.Uint16(payload) != closeGoingAway {
+		t.Errorf("close frame = %#x %v, want close %d", opcode, payload, closeGoingAway)
+	}
+}
@@ -0,0 +1,100 @@
+package websocket
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Close status codes (RFC 6455 section 7.4.1).
+const (
+	closeNormal     = 1000
+	closeGoingAway  = 1001
+	closeProtocol   = 1002
+	closeTooBig     = 1009
+	closeUnexpected = 1011
+)
+
+// pingInterval is how often idle connections are pinged to detect dead
+// clients and keep proxies from timing them out.
+const pingInterval = 30 * time.Second
+
+// Handler returns an HTTP handler that upgrades requests to WebSocket and
+// streams every event published to hub as a JSON text message, until the
+// client disconnects or the hub is closed. Messages from the client other
+// than ping and close are ignored.
+//
+// Browsers may only connect from the server's own origin or one of
+// allowedOrigins, given as "scheme://host[:port]"; "*" allows any origin.
+func Handler(hub *Hub, allowedOrigins ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrade(w, r, allowedOrigins)
+		if err != nil {
+			return
+		}
+		events, unsubscribe := hub.Subscribe()
+		defer unsubscribe()
+
+		closed := make(chan uint16, 1)
+		go c.readLoop(closed)
+
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case code := <-closed:
+				c.close(code)
+				return
+			case event, ok := <-events:
+				if !ok {
+					c.close(closeGoingAway)
+					return
+				}
+				message, err := json.Marshal(event)
+				if err != nil {
+					c.close(closeUnexpected)
+					return
+				}
+				if err := c.writeFrame(opText, message); err != nil {
+					_ = c.netConn.Close()
+					return
+				}
+			case <-ticker.C:
+				if err := c.writeFrame(opPing, nil); err != nil {
+					_ = c.netConn.Close()
+					return
+				}
+			}
+		}
+	}
+}
+
+// readLoop answers pings and reports the close code to send once the client
+// closes the connection or breaks the protocol.
+func (c *conn) readLoop(closed chan<- uint16) {
+	for {
+		opcode, payload, err := c.readFrame()
+		switch {
+		case errors.Is(err, errTooLarge):
+			closed <- closeTooBig
+			return
+		case err != nil:
+			closed <- closeProtocol
+			return
+		case opcode == opClose:
+			code := uint16(closeNormal)
+			if len(payload) >= 2 {
+				code = binary.BigEndian.Uint16(payload)
+			}
+			closed <- code
+			return
+		case opcode == opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				closed <- closeGoingAway
+				return
+			}
+		}
+	}
+}
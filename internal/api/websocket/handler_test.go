@@ -0,0 +1,211 @@
+package websocket
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	xwebsocket "golang.org/x/net/websocket"
+)
+
+// TestHandlerStreamsEvents dials the handler, completes the handshake and
+// checks that published events arrive as JSON text frames.
+func TestHandlerStreamsEvents(t *testing.T) {
+	hub := NewHub()
+	server := httptest.NewServer(Handler(hub))
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	request := "GET / HTTP/1.1\r\nHost: loko\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n"
+	if _, err := conn.Write([]byte(request)); err != nil {
+		t.Fatalf("write handshake: %v", err)
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("read handshake: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("status = %d, want 101", resp.StatusCode)
+	}
+	// The sample key and accept value from RFC 6455 section 1.3.
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("Sec-WebSocket-Accept = %q", got)
+	}
+
+	for hub.Subscribers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	hub.Publish(entities.ModelEvent{Type: entities.EventEntityUpdated, Entity: entities.ElementSystem, ID: "payments"})
+
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		t.Fatalf("read frame: %v", err)
+	}
+	if header[0] != 0x80|opText {
+		t.Fatalf("frame header = %#x, want final text frame", header[0])
+	}
+	payload := make([]byte, header[1])
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		t.Fatalf("read payload: %v", err)
+	}
+	var event entities.ModelEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		t.Fatalf("decode event %q: %v", payload, err)
+	}
+	if event.Type != entities.EventEntityUpdated || event.ID != "payments" {
+		t.Errorf("event = %+v", event)
+	}
+
+	// A masked close frame from the client ends the stream.
+	if _, err := conn.Write([]byte{0x80 | opClose, 0x80, 0, 0, 0, 0}); err != nil {
+		t.Fatalf("write close: %v", err)
+	}
+	if _, err := io.ReadFull(reader, header); err != nil || header[0] != 0x80|opClose {
+		t.Errorf("expected close frame, got %#x (%v)", header[0], err)
+	}
+}
+
+// TestHandlerWithClient streams events to an independent RFC 6455 client,
+// which masks its frames and checks the server's framing, including the
+// extended payload lengths, and the close handshake in both directions.
+func TestHandlerWithClient(t *testing.T) {
+	hub := NewHub()
+	server := httptest.NewServer(Handler(hub))
+	defer server.Close()
+	client, err := xwebsocket.Dial("ws"+strings.TrimPrefix(server.URL, "http"), "", server.URL)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	_ = client.SetDeadline(time.Now().Add(5 * time.Second))
+	for hub.Subscribers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// A ping from the client must not disturb the stream.
+	client.PayloadType = xwebsocket.PingFrame
+	if _, err := client.Write([]byte("ping")); err != nil {
+		t.Fatalf("write ping: %v", err)
+	}
+
+	for _, id := range []string{"payments", strings.Repeat("a", 200), strings.Repeat("b", 70000)} {
+		hub.Publish(entities.ModelEvent{Type: entities.EventEntityUpdated, Entity: entities.ElementSystem, ID: id})
+		var event entities.ModelEvent
+		if err := xwebsocket.JSON.Receive(client, &event); err != nil {
+			t.Fatalf("receive event of %d bytes: %v", len(id), err)
+		}
+		if event.ID != id {
+			t.Errorf("event ID has %d bytes, want %d", len(event.ID), len(id))
+		}
+	}
+
+	// Closing the hub sends a close frame, which ends the client's reads.
+	hub.Close()
+	var event entities.ModelEvent
+	if err := xwebsocket.JSON.Receive(client, &event); err != io.EOF {
+		t.Errorf("receive after hub close = %v, want EOF", err)
+	}
+	_ = client.Close()
+
+	// A client closing its connection unsubscribes it.
+	hub = NewHub()
+	server = httptest.NewServer(Handler(hub))
+	defer server.Close()
+	client, err = xwebsocket.Dial("ws"+strings.TrimPrefix(server.URL, "http"), "", server.URL)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	for hub.Subscribers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for hub.Subscribers() != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := hub.Subscribers(); n != 0 {
+		t.Errorf("subscribers after client close = %d, want 0", n)
+	}
+}
+
+func TestHandlerRejectsPlainRequests(t *testing.T) {
+	w := httptest.NewRecorder()
+	Handler(NewHub())(w, httptest.NewRequest(http.MethodGet, "/api/v1/ws", nil))
+	if w.Code != http.StatusUpgradeRequired {
+		t.Errorf("status = %d, want 426", w.Code)
+	}
+}
+
+func TestHandlerChecksOrigin(t *testing.T) {
+	tests := []struct {
+		origin string
+		want   int
+	}{
+		{"", http.StatusSwitchingProtocols},
+		{"http://loko", http.StatusSwitchingProtocols},
+		{"https://docs.example.com", http.StatusSwitchingProtocols},
+		{"https://evil.example.com", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			hub := NewHub()
+			defer hub.Close()
+			server := httptest.NewServer(Handler(hub, "https://docs.example.com"))
+			defer server.Close()
+
+			conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+			if err != nil {
+				t.Fatalf("dial: %v", err)
+			}
+			defer conn.Close()
+			_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+			request := "GET / HTTP/1.1\r\nHost: loko\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+				"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n"
+			if tt.origin != "" {
+				request += "Origin: " + tt.origin + "\r\n"
+			}
+			if _, err := conn.Write([]byte(request + "\r\n")); err != nil {
+				t.Fatalf("write handshake: %v", err)
+			}
+			resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+			if err != nil {
+				t.Fatalf("read handshake: %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestHubClose(t *testing.T) {
+	hub := NewHub()
+	events, unsubscribe := hub.Subscribe()
+	defer unsubscribe()
+
+	hub.Close()
+	if _, ok := <-events; ok {
+		t.Error("expected subscriber channel to be closed")
+	}
+	late, _ := hub.Subscribe()
+	if _, ok := <-late; ok {
+		t.Error("expected closed channel after Close")
+	}
+}
@@ -0,0 +1,81 @@
+// Package websocket pushes model events to API clients over WebSocket
+// connections (RFC 6455).
+package websocket
+
+import (
+	"sync"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind before
+// further events are dropped for it.
+const subscriberBuffer = 64
+
+// Hub fans model events out to subscribers. It implements
+// usecases.EventPublisher.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[chan entities.ModelEvent]struct{}
+	closed      bool
+}
+
+// NewHub creates a Hub without subscribers.
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[chan entities.ModelEvent]struct{})}
+}
+
+// Publish sends event to every subscriber without blocking. A subscriber
+// whose buffer is full misses the event.
+func (h *Hub) Publish(event entities.ModelEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel receiving published events and a function
+// that unsubscribes. The channel is closed on unsubscribing or when the hub
+// is closed.
+func (h *Hub) Subscribe() (<-chan entities.ModelEvent, func()) {
+	ch := make(chan entities.ModelEvent, subscriberBuffer)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	h.subscribers[ch] = struct{}{}
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subscribers[ch]; ok {
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Close closes every subscriber channel, ending their streams. Later
+// subscribers receive a closed channel.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for ch := range h.subscribers {
+		delete(h.subscribers, ch)
+		close(ch)
+	}
+}
+
+// Subscribers returns the number of current subscribers.
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers)
+}
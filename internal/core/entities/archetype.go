@@ -0,0 +1,12 @@
+package entities
+
+// ContainerArchetype is a typed container defined in the [archetypes] of
+// loko.toml, e.g. "go-service": the defaults of every container created from
+// it, so that dozens of similar services are scaffolded alike.
+type ContainerArchetype struct {
+	Technology  string   // Technology of the container, unless given when creating it
+	Description string   // Description of the container, unless given when creating it
+	Tags        []string // Tags of the container
+	Components  []string // Names of the components created in the container
+	Diagram     string   // D2 file copied as the container diagram, relative to the project root
+}
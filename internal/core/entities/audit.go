@@ -0,0 +1,40 @@
+package entities
+
+import "time"
+
+// AuditSource identifies the interface through which a change was made.
+type AuditSource string
+
+const (
+	AuditSourceMCP AuditSource = "mcp" // MCP tool call
+	AuditSourceAPI AuditSource = "api" // HTTP API write request
+)
+
+// FileChangeOp describes what happened to a file.
+type FileChangeOp string
+
+const (
+	FileAdded    FileChangeOp = "added"
+	FileModified FileChangeOp = "modified"
+	FileRemoved  FileChangeOp = "removed"
+)
+
+// AuditEntry records one mutating MCP tool call or API write: who made it,
+// what was requested, when, and the source files it changed.
+type AuditEntry struct {
+	Time      time.Time      `json:"time"`
+	Source    AuditSource    `json:"source"`
+	Actor     string         `json:"actor"`          // MCP client or API caller, e.g. "claude-code 1.0"
+	User      string         `json:"user,omitempty"` // OS user running loko
+	Action    string         `json:"action"`         // Tool name or "METHOD /path"
+	Arguments map[string]any `json:"arguments,omitempty"`
+	Changes   []FileChange   `json:"changes,omitempty"`
+	Error     string         `json:"error,omitempty"` // Set when the call failed
+}
+
+// FileChange is a change to one project source file.
+type FileChange struct {
+	Path string       `json:"path"` // Relative to the project root
+	Op   FileChangeOp `json:"op"`
+	Diff string       `json:"diff,omitempty"` // Changed lines prefixed with "-" or "+"
+}
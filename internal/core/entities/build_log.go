@@ -0,0 +1,25 @@
+package entities
+
+import "time"
+
+// BuildLogLevel is the kind of a build log entry, after the ProgressReporter
+// method that reported it.
+type BuildLogLevel string
+
+const (
+	BuildLogProgress BuildLogLevel = "progress"
+	BuildLogInfo     BuildLogLevel = "info"
+	BuildLogSuccess  BuildLogLevel = "success"
+	BuildLogError    BuildLogLevel = "error"
+)
+
+// BuildLogEntry is one report of a documentation build, recorded so that a
+// failed build can be debugged after the fact.
+type BuildLogEntry struct {
+	Time    time.Time     `json:"time"`
+	Level   BuildLogLevel `json:"level"`
+	Step    string        `json:"step,omitempty"`    // Set for progress entries
+	Current int           `json:"current,omitempty"` // Progress within Total
+	Total   int           `json:"total,omitempty"`
+	Message string        `json:"message"`
+}
@@ -9,6 +9,6 @@ type C4Entity interface {
 	// GetName returns the entity's display name
 	GetName() string
 
-	// GetEntityType returns the C4 entity type: "system", "container", or "component"
+	// GetEntityType returns the C4 entity type: "person", "system", "container", or "component"
 	GetEntityType() string
 }
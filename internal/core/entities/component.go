@@ -1,11 +1,30 @@
 package entities
 
+import (
+	"strings"
+	"time"
+)
+
+// ComponentSeparator separates a sub-component's ID from the ID of its parent
+// component: the sub-component "validator" of "handler" has the ID
+// "handler.validator" and lives in the directory handler/validator/.
+const ComponentSeparator = "."
+
+// MaxComponentNesting is how many levels of sub-components a component may
+// have.
+const MaxComponentNesting = 1
+
 // Component represents a C4 component - the lowest level of the hierarchy.
 // Components are code-level abstractions within a container.
 type Component struct {
-	// ID is the unique identifier (used in file paths)
+	// ID is the unique identifier (used in file paths), prefixed with the
+	// parent component's ID for sub-components, e.g. "handler.validator"
 	ID string `json:"id" toon:"id"`
 
+	// Parent is the ID of the component containing this sub-component, or
+	// empty for a component directly in its container
+	Parent string `json:"parent,omitempty" toon:"parent,omitempty"`
+
 	// Name is the display name
 	Name string `json:"name" toon:"name"`
 
@@ -18,6 +37,12 @@ type Component struct {
 	// Tags for categorization and filtering
 	Tags []string `json:"tags" toon:"tags,omitempty"`
 
+	// Issues references tracker tickets by ID (e.g. "PAY-123") or URL
+	Issues []string `json:"issues,omitempty" toon:"issues,omitempty"`
+
+	// Controls lists the IDs of the compliance controls it implements (e.g. "CC6.1")
+	Controls []string `json:"controls,omitempty" toon:"controls,omitempty"`
+
 	// Relationships to other components (maps component ID to relationship description)
 	Relationships map[string]string `json:"relationships" toon:"relationships,omitempty"`
 
@@ -27,6 +52,10 @@ type Component struct {
 	// Dependencies lists external packages/libraries this component depends on (e.g., "github.com/golang-jwt/jwt")
 	Dependencies []string `json:"dependencies" toon:"dependencies,omitempty"`
 
+	// Aliases are the qualified IDs of components merged into this one;
+	// relationships to them resolve to this component
+	Aliases []string `json:"aliases,omitempty" toon:"aliases,omitempty"`
+
 	// Diagram is the optional component diagram
 	Diagram *Diagram `json:"diagram,omitempty" toon:"diagram,omitempty"`
 
@@ -39,6 +68,23 @@ type Component struct {
 	// Path is the filesystem path to this component's directory
 	Path string `json:"path" toon:"path,omitempty"`
 
+	// ContentHash is the hash of component.md when the component was loaded or last
+	// saved. A save fails with a ConflictError if the file no longer has it.
+	ContentHash string `json:"content_hash,omitempty" toon:"content_hash,omitempty"`
+
+	// CreatedAt is when the element's sources were first committed; zero if unknown
+	CreatedAt time.Time `json:"created_at,omitzero" toon:"created_at,omitempty"`
+
+	// Review is the element's approval status in the review workflow
+	Review Review `json:"review,omitzero" toon:"review,omitempty"`
+
+	// UpdatedAt is when the element's sources last changed, from version
+	// control or else the file's modification time
+	UpdatedAt time.Time `json:"updated_at,omitzero" toon:"updated_at,omitempty"`
+
+	// Author is who last changed the element's sources, from version control
+	Author string `json:"author,omitempty" toon:"author,omitempty"`
+
 	// ContentTemplate is the name of the template file to use when generating
 	// component.md (e.g., "compute", "datastore"). Empty means use the default.
 	// This field is transient — not persisted to frontmatter.
@@ -70,8 +116,12 @@ func (c *Component) Validate() error {
 		errs.Add("Component", "Name", c.Name, "invalid name", err)
 	}
 
-	if err := ValidateID(c.ID); err != nil {
-		errs.Add("Component", "ID", c.ID, "invalid id", err)
+	// Each level of a sub-component ID is validated like a component ID
+	for _, segment := range strings.Split(c.ID, ComponentSeparator) {
+		if err := ValidateID(segment); err != nil {
+			errs.Add("Component", "ID", c.ID, "invalid id", err)
+			break
+		}
 	}
 
 	if c.Diagram != nil {
@@ -86,6 +136,32 @@ func (c *Component) Validate() error {
 	return nil
 }
 
+// SetParent makes the component a sub-component of the component parentID,
+// re-prefixing its ID. An empty parentID makes it a top-level component.
+func (c *Component) SetParent(parentID string) {
+	local := c.ID
+	if i := strings.LastIndex(c.ID, ComponentSeparator); i >= 0 {
+		local = c.ID[i+len(ComponentSeparator):]
+	}
+	c.Parent = parentID
+	c.ID = local
+	if parentID != "" {
+		c.ID = parentID + ComponentSeparator + local
+	}
+}
+
+// Depth returns how deeply the component is nested: 0 for a component
+// directly in its container, 1 for a sub-component.
+func (c *Component) Depth() int {
+	return strings.Count(c.ID, ComponentSeparator)
+}
+
+// ComponentSourcePath returns the slash-separated directory of a component
+// relative to its container: "handler/validator" for "handler.validator".
+func ComponentSourcePath(componentID string) string {
+	return strings.ReplaceAll(componentID, ComponentSeparator, "/")
+}
+
 // SetDescription sets the component description.
 func (c *Component) SetDescription(desc string) {
 	c.Description = desc
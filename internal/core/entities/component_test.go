@@ -289,3 +289,44 @@ func TestComponent_EntityTypeMethods(t *testing.T) {
 		t.Errorf("Expected entity type 'component', got %q", comp.GetEntityType())
 	}
 }
+
+// TestComponent_SubComponents tests nesting a component in another.
+func TestComponent_SubComponents(t *testing.T) {
+	container, _ := NewContainer("API")
+	handler, _ := NewComponent("Handler")
+	validator, _ := NewComponent("Validator")
+	auth, _ := NewComponent("Auth")
+	validator.SetParent(handler.ID)
+	auth.SetParent(handler.ID)
+	for _, comp := range []*Component{handler, validator, auth} {
+		if err := container.AddComponent(comp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if validator.ID != "handler.validator" || validator.Parent != "handler" {
+		t.Errorf("ID, Parent = %q, %q, want handler.validator, handler", validator.ID, validator.Parent)
+	}
+	if handler.Depth() != 0 || validator.Depth() != 1 {
+		t.Errorf("Depth() = %d, %d, want 0, 1", handler.Depth(), validator.Depth())
+	}
+	if err := validator.Validate(); err != nil {
+		t.Errorf("Validate() of a sub-component = %v", err)
+	}
+	if got := ComponentSourcePath(validator.ID); got != "handler/validator" {
+		t.Errorf("ComponentSourcePath() = %q, want handler/validator", got)
+	}
+
+	subs := container.SubComponents("handler")
+	if len(subs) != 2 || subs[0] != auth || subs[1] != validator {
+		t.Errorf("SubComponents() = %v, want auth and validator", subs)
+	}
+	if got := container.SubComponents(""); got != nil {
+		t.Errorf("SubComponents(\"\") = %v, want nil", got)
+	}
+
+	validator.SetParent("")
+	if validator.ID != "validator" || validator.Parent != "" {
+		t.Errorf("ID, Parent = %q, %q after clearing, want validator", validator.ID, validator.Parent)
+	}
+}
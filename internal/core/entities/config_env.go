@@ -0,0 +1,166 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ConfigEnvPrefix starts the name of every environment variable overriding a
+// loko.toml setting: the setting "d2.theme" is overridden by LOKO_D2_THEME.
+const ConfigEnvPrefix = "LOKO_"
+
+// configEnvAliases are short variable names for the most common overrides,
+// by setting. The full name of a setting wins when both are set.
+var configEnvAliases = map[string]string{
+	"paths.source": "LOKO_SOURCE_DIR",
+	"paths.output": "LOKO_OUTPUT_DIR",
+	"d2.theme":     "LOKO_THEME",
+	"d2.layout":    "LOKO_DIAGRAM_ENGINE",
+}
+
+// configSetting is a scalar or list setting of loko.toml that an environment
+// variable can override.
+type configSetting struct {
+	key string // "section.key", as in loko.toml
+	set func(config *ProjectConfig, value string) error
+}
+
+// configSettings lists the overridable settings in the order of loko.toml.
+// Tables such as [icons] and the [hooks] commands cannot be overridden.
+var configSettings = []configSetting{
+	stringSetting("paths.source", func(c *ProjectConfig) *string { return &c.SourceDir }),
+	listSetting("paths.source_dirs", func(c *ProjectConfig) *[]string { return &c.SourceDirs }),
+	stringSetting("paths.output", func(c *ProjectConfig) *string { return &c.OutputDir }),
+	stringSetting("d2.theme", func(c *ProjectConfig) *string { return &c.D2Theme }),
+	stringSetting("d2.layout", func(c *ProjectConfig) *string { return &c.D2Layout }),
+	boolSetting("d2.cache", func(c *ProjectConfig) *bool { return &c.D2Cache }),
+	stringSetting("d2.style", func(c *ProjectConfig) *string { return &c.D2Style }),
+	boolSetting("d2.legend", func(c *ProjectConfig) *bool { return &c.DiagramLegend }),
+	boolSetting("d2.metadata_footer", func(c *ProjectConfig) *bool { return &c.DiagramFooter }),
+	boolSetting("outputs.html", func(c *ProjectConfig) *bool { return &c.HTMLEnabled }),
+	boolSetting("outputs.markdown", func(c *ProjectConfig) *bool { return &c.MarkdownEnabled }),
+	boolSetting("outputs.pdf", func(c *ProjectConfig) *bool { return &c.PDFEnabled }),
+	boolSetting("build.parallel", func(c *ProjectConfig) *bool { return &c.Parallel }),
+	intSetting("build.max_workers", func(c *ProjectConfig) *int { return &c.MaxWorkers }),
+	boolSetting("build.minify", func(c *ProjectConfig) *bool { return &c.Minify }),
+	intSetting("server.serve_port", func(c *ProjectConfig) *int { return &c.ServePort }),
+	intSetting("server.api_port", func(c *ProjectConfig) *int { return &c.APIPort }),
+	boolSetting("server.hot_reload", func(c *ProjectConfig) *bool { return &c.HotReload }),
+	stringSetting("site.head", func(c *ProjectConfig) *string { return &c.CustomHead }),
+	stringSetting("site.head_file", func(c *ProjectConfig) *string { return &c.CustomHeadFile }),
+	stringSetting("site.footer", func(c *ProjectConfig) *string { return &c.CustomFooter }),
+	stringSetting("site.footer_file", func(c *ProjectConfig) *string { return &c.CustomFooterFile }),
+	stringSetting("site.analytics", func(c *ProjectConfig) *string { return &c.AnalyticsProvider }),
+	stringSetting("site.analytics_id", func(c *ProjectConfig) *string { return &c.AnalyticsID }),
+	listSetting("site.custom_fields", func(c *ProjectConfig) *[]string { return &c.CustomFields }),
+	stringSetting("site.base_path", func(c *ProjectConfig) *string { return &c.BasePath }),
+	boolSetting("site.pretty_urls", func(c *ProjectConfig) *bool { return &c.PrettyURLs }),
+	stringSetting("issues.url_template", func(c *ProjectConfig) *string { return &c.IssueURLTemplate }),
+	stringSetting("issues.tracker", func(c *ProjectConfig) *string { return &c.IssueTracker }),
+	stringSetting("issues.tracker_url", func(c *ProjectConfig) *string { return &c.IssueTrackerURL }),
+	boolSetting("deployment.require_mapping", func(c *ProjectConfig) *bool { return &c.RequireDeployment }),
+	listSetting("deployment.targets", func(c *ProjectConfig) *[]string { return &c.DeploymentTargets }),
+	stringSetting("costs.currency", func(c *ProjectConfig) *string { return &c.CostCurrency }),
+	boolSetting("review.require_approval", func(c *ProjectConfig) *bool { return &c.RequireApproval }),
+	stringSetting("naming.id_case", func(c *ProjectConfig) *string { return &c.NamingIDCase }),
+	intSetting("naming.max_name_length", func(c *ProjectConfig) *int { return &c.NamingMaxNameLength }),
+	stringSetting("naming.technology_separator", func(c *ProjectConfig) *string { return &c.NamingTechSeparator }),
+	stringSetting("relationship_descriptions.missing", func(c *ProjectConfig) *string { return &c.RelDescriptionMissing }),
+	stringSetting("relationship_descriptions.verb_first", func(c *ProjectConfig) *string { return &c.RelDescriptionVerbFirst }),
+	stringSetting("relationship_descriptions.placeholder", func(c *ProjectConfig) *string { return &c.RelDescriptionPlaceholder }),
+	stringSetting("relationship_descriptions.too_long", func(c *ProjectConfig) *string { return &c.RelDescriptionTooLong }),
+	intSetting("relationship_descriptions.max_length", func(c *ProjectConfig) *int { return &c.RelDescriptionMaxLength }),
+	stringSetting("notifications.webhook_url", func(c *ProjectConfig) *string { return &c.NotifyWebhookURL }),
+	stringSetting("notifications.smtp_host", func(c *ProjectConfig) *string { return &c.NotifySMTPHost }),
+	stringSetting("notifications.email_from", func(c *ProjectConfig) *string { return &c.NotifyEmailFrom }),
+	stringSetting("encryption.recipients_file", func(c *ProjectConfig) *string { return &c.RecipientsFile }),
+	listSetting("redaction.remove_tags", func(c *ProjectConfig) *[]string { return &c.RedactTags }),
+	listSetting("redaction.strip_fields", func(c *ProjectConfig) *[]string { return &c.RedactFields }),
+	listSetting("redaction.mask_patterns", func(c *ProjectConfig) *[]string { return &c.RedactPatterns }),
+	stringSetting("redaction.mask", func(c *ProjectConfig) *string { return &c.RedactMask }),
+	stringSetting("permissions.mcp_role", func(c *ProjectConfig) *string { return &c.MCPRole }),
+	listSetting("permissions.mcp_allow", func(c *ProjectConfig) *[]string { return &c.MCPAllowTools }),
+	listSetting("permissions.mcp_deny", func(c *ProjectConfig) *[]string { return &c.MCPDenyTools }),
+	boolSetting("git.auto_commit", func(c *ProjectConfig) *bool { return &c.GitAutoCommit }),
+	boolSetting("metrics.record_usage", func(c *ProjectConfig) *bool { return &c.RecordUsage }),
+	stringSetting("plugins.renderer", func(c *ProjectConfig) *string { return &c.PluginRenderer }),
+}
+
+// ConfigEnvName returns the environment variable overriding the loko.toml
+// setting key, e.g. LOKO_BUILD_MAX_WORKERS for "build.max_workers".
+func ConfigEnvName(key string) string {
+	return ConfigEnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// ApplyConfigEnv overrides the settings of config with the LOKO_* variables
+// found by lookup, typically os.LookupEnv. Lists are comma-separated. A value
+// that does not fit its setting is an ErrInvalidConfig naming the variable.
+func ApplyConfigEnv(config *ProjectConfig, lookup func(string) (string, bool)) error {
+	if config == nil || lookup == nil {
+		return nil
+	}
+	for _, setting := range configSettings {
+		name := ConfigEnvName(setting.key)
+		value, ok := lookup(name)
+		if alias := configEnvAliases[setting.key]; !ok && alias != "" {
+			name = alias
+			value, ok = lookup(alias)
+		}
+		if !ok {
+			continue
+		}
+		if err := setting.set(config, strings.TrimSpace(value)); err != nil {
+			return fmt.Errorf("%w: %s=%q: %w", ErrInvalidConfig, name, value, err)
+		}
+	}
+	return nil
+}
+
+// stringSetting overrides a string field with the value as is.
+func stringSetting(key string, field func(*ProjectConfig) *string) configSetting {
+	return configSetting{key: key, set: func(c *ProjectConfig, value string) error {
+		*field(c) = value
+		return nil
+	}}
+}
+
+// boolSetting overrides a bool field with a value such as "true" or "0".
+func boolSetting(key string, field func(*ProjectConfig) *bool) configSetting {
+	return configSetting{key: key, set: func(c *ProjectConfig, value string) error {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("expected true or false")
+		}
+		*field(c) = b
+		return nil
+	}}
+}
+
+// intSetting overrides an int field.
+func intSetting(key string, field func(*ProjectConfig) *int) configSetting {
+	return configSetting{key: key, set: func(c *ProjectConfig, value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.New("expected a whole number")
+		}
+		*field(c) = n
+		return nil
+	}}
+}
+
+// listSetting overrides a list field with the comma-separated items of the
+// value; an empty value empties the list.
+func listSetting(key string, field func(*ProjectConfig) *[]string) configSetting {
+	return configSetting{key: key, set: func(c *ProjectConfig, value string) error {
+		var items []string
+		for item := range strings.SplitSeq(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		*field(c) = items
+		return nil
+	}}
+}
@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestApplyConfigEnv(t *testing.T) {
+	env := map[string]string{
+		"LOKO_OUTPUT_DIR":         "./public",
+		"LOKO_DIAGRAM_ENGINE":     "dagre",
+		"LOKO_THEME":              "dark-mauve",
+		"LOKO_D2_THEME":           "terminal", // The full name wins over LOKO_THEME
+		"LOKO_BUILD_MAX_WORKERS":  "8",
+		"LOKO_BUILD_MINIFY":       "true",
+		"LOKO_SITE_CUSTOM_FIELDS": "sla, tier,",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	config := DefaultProjectConfig()
+	if err := ApplyConfigEnv(config, lookup); err != nil {
+		t.Fatalf("ApplyConfigEnv failed: %v", err)
+	}
+	if config.OutputDir != "./public" || config.D2Layout != "dagre" || config.D2Theme != "terminal" {
+		t.Errorf("OutputDir = %q, D2Layout = %q, D2Theme = %q", config.OutputDir, config.D2Layout, config.D2Theme)
+	}
+	if config.MaxWorkers != 8 || !config.Minify {
+		t.Errorf("MaxWorkers = %d, Minify = %v", config.MaxWorkers, config.Minify)
+	}
+	if !slices.Equal(config.CustomFields, []string{"sla", "tier"}) {
+		t.Errorf("CustomFields = %q", config.CustomFields)
+	}
+	if config.SourceDir != "./src" {
+		t.Errorf("unset variable changed SourceDir to %q", config.SourceDir)
+	}
+}
+
+func TestApplyConfigEnvInvalid(t *testing.T) {
+	lookup := func(name string) (string, bool) {
+		return "often", name == "LOKO_SERVER_HOT_RELOAD"
+	}
+	err := ApplyConfigEnv(DefaultProjectConfig(), lookup)
+	if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), "LOKO_SERVER_HOT_RELOAD") {
+		t.Errorf("ApplyConfigEnv() error = %v, want ErrInvalidConfig naming the variable", err)
+	}
+}
+
+func TestConfigEnvName(t *testing.T) {
+	if got := ConfigEnvName("naming.max_name_length"); got != "LOKO_NAMING_MAX_NAME_LENGTH" {
+		t.Errorf("ConfigEnvName() = %q", got)
+	}
+}
@@ -1,5 +1,12 @@
 package entities
 
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
 // Container represents a C4 container - a deployable unit within a system.
 // Examples: API server, database, web app, mobile app.
 type Container struct {
@@ -18,7 +25,17 @@ type Container struct {
 	// Tags for categorization and filtering
 	Tags []string `json:"tags" toon:"tags,omitempty"`
 
-	// Components within this container
+	// Issues references tracker tickets by ID (e.g. "PAY-123") or URL
+	Issues []string `json:"issues,omitempty" toon:"issues,omitempty"`
+
+	// Controls lists the IDs of the compliance controls it implements (e.g. "CC6.1")
+	Controls []string `json:"controls,omitempty" toon:"controls,omitempty"`
+
+	// Relationships to other containers (maps container ID to relationship description)
+	Relationships map[string]string `json:"relationships,omitempty" toon:"relationships,omitempty"`
+
+	// Components within this container, including sub-components by their
+	// dotted IDs
 	Components map[string]*Component `json:"components" toon:"components"`
 
 	// Diagram is the container diagram
@@ -33,6 +50,23 @@ type Container struct {
 	// Path is the filesystem path to this container's directory
 	Path string `json:"path" toon:"path,omitempty"`
 
+	// ContentHash is the hash of container.md when the container was loaded or last
+	// saved. A save fails with a ConflictError if the file no longer has it.
+	ContentHash string `json:"content_hash,omitempty" toon:"content_hash,omitempty"`
+
+	// CreatedAt is when the element's sources were first committed; zero if unknown
+	CreatedAt time.Time `json:"created_at,omitzero" toon:"created_at,omitempty"`
+
+	// Review is the element's approval status in the review workflow
+	Review Review `json:"review,omitzero" toon:"review,omitempty"`
+
+	// UpdatedAt is when the element's sources last changed, from version
+	// control or else the file's modification time
+	UpdatedAt time.Time `json:"updated_at,omitzero" toon:"updated_at,omitempty"`
+
+	// Author is who last changed the element's sources, from version control
+	Author string `json:"author,omitempty" toon:"author,omitempty"`
+
 	// ParentID is the ID of the parent system
 	ParentID string `json:"parent_id" toon:"parent_id,omitempty"`
 }
@@ -115,12 +149,26 @@ func (c *Container) RemoveComponent(id string) error {
 	return nil
 }
 
-// ListComponents returns all components in order.
+// ListComponents returns all components, sorted by ID so that output
+// generated from them is stable between runs.
 func (c *Container) ListComponents() []*Component {
 	result := make([]*Component, 0, len(c.Components))
+	for _, id := range slices.Sorted(maps.Keys(c.Components)) {
+		result = append(result, c.Components[id])
+	}
+	return result
+}
+
+// SubComponents returns the direct sub-components of the component
+// componentID, sorted by ID.
+func (c *Container) SubComponents(componentID string) []*Component {
+	var result []*Component
 	for _, comp := range c.Components {
-		result = append(result, comp)
+		if comp.Parent == componentID && componentID != "" {
+			result = append(result, comp)
+		}
 	}
+	slices.SortFunc(result, func(a, b *Component) int { return strings.Compare(a.ID, b.ID) })
 	return result
 }
 
@@ -159,6 +207,38 @@ func (c *Container) HasTag(tag string) bool {
 	return false
 }
 
+// DeploymentTagPrefix prefixes the tags that map a container to the
+// deployment targets it runs on, e.g. "deployed-on:eks-prod".
+const DeploymentTagPrefix = "deployed-on:"
+
+// DeploymentTargets returns the deployment targets named by the container's
+// deployed-on: tags, in tag order.
+func (c *Container) DeploymentTargets() []string {
+	var targets []string
+	for _, t := range c.Tags {
+		if target, ok := strings.CutPrefix(t, DeploymentTagPrefix); ok && strings.TrimSpace(target) != "" {
+			targets = append(targets, strings.TrimSpace(target))
+		}
+	}
+	return targets
+}
+
+// AddRelationship adds a relationship to another container.
+func (c *Container) AddRelationship(targetContainerID, description string) {
+	if targetContainerID == "" {
+		return
+	}
+	if c.Relationships == nil {
+		c.Relationships = make(map[string]string)
+	}
+	c.Relationships[targetContainerID] = description
+}
+
+// RemoveRelationship removes a relationship to another container.
+func (c *Container) RemoveRelationship(targetContainerID string) {
+	delete(c.Relationships, targetContainerID)
+}
+
 // GetID returns the container's unique identifier (implements C4Entity).
 func (c *Container) GetID() string {
 	return c.ID
@@ -1,6 +1,7 @@
 package entities
 
 import (
+	"slices"
 	"testing"
 )
 
@@ -80,6 +81,9 @@ func TestContainer_Components(t *testing.T) {
 	if len(list) != 2 {
 		t.Errorf("ListComponents() returned %d, want 2", len(list))
 	}
+	if len(list) == 2 && list[0].ID > list[1].ID {
+		t.Errorf("ListComponents() = [%s %s], want sorted by ID", list[0].ID, list[1].ID)
+	}
 
 	// Remove
 	if err := cont.RemoveComponent("authhandler"); err != nil {
@@ -135,3 +139,30 @@ func TestContainer_Tags(t *testing.T) {
 		t.Error("HasTag(nonexistent) should return false")
 	}
 }
+
+func TestContainer_DeploymentTargets(t *testing.T) {
+	cont, _ := NewContainer("API")
+	cont.AddTag("backend")
+	cont.AddTag("deployed-on:eks-prod")
+	cont.AddTag("deployed-on: lambda")
+	cont.AddTag("deployed-on:")
+
+	if got := cont.DeploymentTargets(); !slices.Equal(got, []string{"eks-prod", "lambda"}) {
+		t.Errorf("DeploymentTargets() = %q", got)
+	}
+}
+
+func TestContainer_Relationships(t *testing.T) {
+	cont, _ := NewContainer("API")
+	cont.AddRelationship("database", "Reads and writes orders")
+	cont.AddRelationship("", "ignored")
+
+	if got := cont.Relationships; len(got) != 1 || got["database"] != "Reads and writes orders" {
+		t.Errorf("Relationships = %v", got)
+	}
+
+	cont.RemoveRelationship("database")
+	if len(cont.Relationships) != 0 {
+		t.Errorf("Relationships after remove = %v", cont.Relationships)
+	}
+}
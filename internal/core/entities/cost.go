@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Cost annotations of containers: a monthly_cost frontmatter field, or cost
+// tags such as "cost:1200" and, per environment, "cost:prod=1200".
+const (
+	MonthlyCostField = "monthly_cost"
+	CostTagPrefix    = "cost:"
+)
+
+// MonthlyCost is a container's monthly running cost in one environment. An
+// empty Environment is a cost not attributed to any environment.
+type MonthlyCost struct {
+	Environment string
+	Amount      float64
+}
+
+// MonthlyCosts returns the monthly costs declared by the container's
+// monthly_cost field and cost: tags, in that order. Amounts may use "_" or ","
+// as thousands separators. Returns an error naming the first malformed or
+// negative amount.
+func (c *Container) MonthlyCosts() ([]MonthlyCost, error) {
+	var costs []MonthlyCost
+	if value, ok := c.Metadata[MonthlyCostField]; ok {
+		amount, err := parseCostAmount(fmt.Sprint(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %v: %w", MonthlyCostField, value, err)
+		}
+		costs = append(costs, MonthlyCost{Amount: amount})
+	}
+	for _, tag := range c.Tags {
+		value, ok := strings.CutPrefix(tag, CostTagPrefix)
+		if !ok {
+			continue
+		}
+		env, amountText, found := strings.Cut(value, "=")
+		if !found {
+			env, amountText = "", value
+		}
+		amount, err := parseCostAmount(amountText)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cost tag %q: %w", tag, err)
+		}
+		costs = append(costs, MonthlyCost{Environment: strings.TrimSpace(env), Amount: amount})
+	}
+	return costs, nil
+}
+
+// parseCostAmount parses a non-negative amount such as "1200", "1,200.50" or
+// "1_200".
+func parseCostAmount(text string) (float64, error) {
+	text = strings.NewReplacer(",", "", "_", "").Replace(strings.TrimSpace(text))
+	amount, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected a number")
+	}
+	if amount < 0 {
+		return 0, fmt.Errorf("must not be negative")
+	}
+	return amount, nil
+}
@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestContainer_MonthlyCosts(t *testing.T) {
+	cont, _ := NewContainer("API")
+	cont.Metadata[MonthlyCostField] = 1200
+	cont.Tags = []string{"backend", "cost:prod=1,500.50", "cost:staging = 300", "cost:80"}
+
+	costs, err := cont.MonthlyCosts()
+	if err != nil {
+		t.Fatalf("MonthlyCosts() error = %v", err)
+	}
+	want := []MonthlyCost{{"", 1200}, {"prod", 1500.5}, {"staging", 300}, {"", 80}}
+	if !slices.Equal(costs, want) {
+		t.Errorf("MonthlyCosts() = %v, want %v", costs, want)
+	}
+
+	for _, tag := range []string{"cost:lots", "cost:prod=-5"} {
+		cont.Tags = []string{tag}
+		if _, err := cont.MonthlyCosts(); err == nil {
+			t.Errorf("MonthlyCosts() with %q: expected an error", tag)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ElementStyle is how one kind of C4 element is drawn in generated diagrams.
+type ElementStyle struct {
+	Shape     string // D2 shape, e.g. "person"; "" draws a rectangle
+	Fill      string
+	Stroke    string
+	FontColor string
+}
+
+// DiagramStyle is a C4 styling preset applied to generated D2 diagrams, with
+// a style per element level and for the boundaries that group them.
+type DiagramStyle struct {
+	Name       string
+	Background string // Diagram background; "" keeps the D2 theme's
+
+	Person    ElementStyle
+	System    ElementStyle
+	External  ElementStyle // Systems outside the one being documented
+	Container ElementStyle
+	Component ElementStyle
+	Boundary  ElementStyle // System and container boundaries, drawn dashed
+}
+
+// diagramStyles are the built-in presets, selected with [d2] style.
+var diagramStyles = []DiagramStyle{
+	{
+		// The blue boxes of the C4 model's reference diagrams.
+		Name:      "classic",
+		Person:    ElementStyle{Shape: "person", Fill: "#08427B", Stroke: "#073B6F", FontColor: "#FFFFFF"},
+		System:    ElementStyle{Fill: "#1168BD", Stroke: "#0B4884", FontColor: "#FFFFFF"},
+		External:  ElementStyle{Fill: "#999999", Stroke: "#8A8A8A", FontColor: "#FFFFFF"},
+		Container: ElementStyle{Fill: "#438DD5", Stroke: "#3C7FC0", FontColor: "#FFFFFF"},
+		Component: ElementStyle{Fill: "#85BBF0", Stroke: "#78A8D8", FontColor: "#000000"},
+		Boundary:  ElementStyle{Fill: "#FFFFFF", Stroke: "#444444", FontColor: "#444444"},
+	},
+	{
+		Name:      "neutral",
+		Person:    ElementStyle{Shape: "person", Fill: "#E0E0E0", Stroke: "#616161", FontColor: "#212121"},
+		System:    ElementStyle{Fill: "#F5F5F5", Stroke: "#424242", FontColor: "#212121"},
+		External:  ElementStyle{Fill: "#FFFFFF", Stroke: "#9E9E9E", FontColor: "#616161"},
+		Container: ElementStyle{Fill: "#EEEEEE", Stroke: "#616161", FontColor: "#212121"},
+		Component: ElementStyle{Fill: "#FAFAFA", Stroke: "#757575", FontColor: "#212121"},
+		Boundary:  ElementStyle{Fill: "#FFFFFF", Stroke: "#9E9E9E", FontColor: "#616161"},
+	},
+	{
+		Name:       "dark",
+		Background: "#121212",
+		Person:     ElementStyle{Shape: "person", Fill: "#0D47A1", Stroke: "#90CAF9", FontColor: "#E3F2FD"},
+		System:     ElementStyle{Fill: "#1565C0", Stroke: "#90CAF9", FontColor: "#E3F2FD"},
+		External:   ElementStyle{Fill: "#37474F", Stroke: "#78909C", FontColor: "#CFD8DC"},
+		Container:  ElementStyle{Fill: "#1E88E5", Stroke: "#BBDEFB", FontColor: "#FFFFFF"},
+		Component:  ElementStyle{Fill: "#263238", Stroke: "#64B5F6", FontColor: "#E3F2FD"},
+		Boundary:   ElementStyle{Fill: "#121212", Stroke: "#546E7A", FontColor: "#B0BEC5"},
+	},
+}
+
+// DiagramStyleNames returns the names of the built-in diagram style presets.
+func DiagramStyleNames() []string {
+	names := make([]string, len(diagramStyles))
+	for i, style := range diagramStyles {
+		names[i] = style.Name
+	}
+	return names
+}
+
+// LookupDiagramStyle returns the preset with the given name (case-insensitive).
+// An empty name returns nil, leaving generated diagrams unstyled.
+func LookupDiagramStyle(name string) (*DiagramStyle, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return nil, nil
+	}
+	i := slices.IndexFunc(diagramStyles, func(style DiagramStyle) bool { return style.Name == name })
+	if i < 0 {
+		return nil, fmt.Errorf("unknown diagram style %q (expected %s)", name, strings.Join(DiagramStyleNames(), ", "))
+	}
+	style := diagramStyles[i]
+	return &style, nil
+}
@@ -0,0 +1,33 @@
+package entities
+
+import "testing"
+
+func TestLookupDiagramStyle(t *testing.T) {
+	for _, name := range DiagramStyleNames() {
+		style, err := LookupDiagramStyle(name)
+		if err != nil || style == nil || style.Name != name {
+			t.Errorf("LookupDiagramStyle(%q) = %v, %v", name, style, err)
+			continue
+		}
+		if style.Person.Shape != "person" {
+			t.Errorf("%s: Person.Shape = %q, want person", name, style.Person.Shape)
+		}
+	}
+
+	if style, err := LookupDiagramStyle(" Classic "); err != nil || style.Name != "classic" {
+		t.Errorf("LookupDiagramStyle is not case-insensitive: %v, %v", style, err)
+	}
+	if style, err := LookupDiagramStyle(""); err != nil || style != nil {
+		t.Errorf("LookupDiagramStyle(\"\") = %v, %v; want nil, nil", style, err)
+	}
+	if _, err := LookupDiagramStyle("pastel"); err == nil {
+		t.Error("expected error for unknown style")
+	}
+
+	// Callers get a copy they may change without affecting the preset.
+	style, _ := LookupDiagramStyle("classic")
+	style.System.Fill = "#000000"
+	if again, _ := LookupDiagramStyle("classic"); again.System.Fill == "#000000" {
+		t.Error("LookupDiagramStyle returned a shared preset")
+	}
+}
@@ -0,0 +1,124 @@
+package entities
+
+import (
+	"slices"
+	"strings"
+)
+
+// DomainSeparator separates the domains of a namespaced system ID: the system
+// "billing" in the domain "payments" has the ID "payments.billing" and lives
+// in src/payments/billing/. Domains nest to any depth.
+const DomainSeparator = "."
+
+// Domain groups the systems of a namespace, e.g. all systems under
+// src/payments/.
+type Domain struct {
+	// ID is the dot-separated namespace, e.g. "payments" or "payments.cards"
+	ID string `json:"id" toon:"id"`
+
+	// Name is the display name derived from the last segment of the ID
+	Name string `json:"name" toon:"name"`
+
+	// Systems directly in this domain, sorted by ID
+	Systems []*System `json:"systems" toon:"systems"`
+
+	// Domains nested directly in this domain, sorted by ID
+	Domains []*Domain `json:"domains,omitempty" toon:"domains,omitempty"`
+}
+
+// SystemDomain returns the domain of a system ID: "payments.cards" for
+// "payments.cards.billing", or "" for a top-level system.
+func SystemDomain(systemID string) string {
+	domain, _ := splitNamespace(systemID)
+	return domain
+}
+
+// SystemSourcePath returns the slash-separated directory of a system relative
+// to the source directory: "payments/billing" for "payments.billing".
+func SystemSourcePath(systemID string) string {
+	return strings.ReplaceAll(systemID, DomainSeparator, "/")
+}
+
+// ParentDomain returns the domain containing a domain, or "" for a
+// top-level domain.
+func ParentDomain(domainID string) string {
+	parent, _ := splitNamespace(domainID)
+	return parent
+}
+
+// splitNamespace splits an ID at its last DomainSeparator.
+func splitNamespace(id string) (namespace, local string) {
+	i := strings.LastIndex(id, DomainSeparator)
+	if i < 0 {
+		return "", id
+	}
+	return id[:i], id[i+len(DomainSeparator):]
+}
+
+// BuildDomains groups namespaced systems into their domains and returns the
+// top-level domains sorted by ID. Every namespace gets a domain, including
+// intermediate ones without systems of their own. Top-level systems belong to
+// no domain; without namespaced systems BuildDomains returns nil.
+func BuildDomains(systems []*System) []*Domain {
+	domains := make(map[string]*Domain)
+	var ensure func(id string) *Domain
+	ensure = func(id string) *Domain {
+		if d, ok := domains[id]; ok {
+			return d
+		}
+		d := &Domain{ID: id, Name: DomainName(id)}
+		domains[id] = d
+		if parent := ParentDomain(id); parent != "" {
+			p := ensure(parent)
+			p.Domains = append(p.Domains, d)
+		}
+		return d
+	}
+
+	for _, sys := range systems {
+		if sys == nil || sys.Domain == "" {
+			continue
+		}
+		d := ensure(sys.Domain)
+		d.Systems = append(d.Systems, sys)
+	}
+
+	var top []*Domain
+	for id, d := range domains {
+		slices.SortFunc(d.Systems, func(a, b *System) int { return strings.Compare(a.ID, b.ID) })
+		slices.SortFunc(d.Domains, func(a, b *Domain) int { return strings.Compare(a.ID, b.ID) })
+		if ParentDomain(id) == "" {
+			top = append(top, d)
+		}
+	}
+	slices.SortFunc(top, func(a, b *Domain) int { return strings.Compare(a.ID, b.ID) })
+	return top
+}
+
+// AllSystems returns the systems of the domain and of its nested domains.
+func (d *Domain) AllSystems() []*System {
+	systems := slices.Clone(d.Systems)
+	for _, nested := range d.Domains {
+		systems = append(systems, nested.AllSystems()...)
+	}
+	return systems
+}
+
+// Walk calls fn for the domain and every nested domain, parents first.
+func (d *Domain) Walk(fn func(*Domain)) {
+	fn(d)
+	for _, nested := range d.Domains {
+		nested.Walk(fn)
+	}
+}
+
+// DomainName returns the display name of a domain, derived from the last
+// segment of its ID: "payments.order-management" becomes "Order Management".
+func DomainName(domainID string) string {
+	_, segment := splitNamespace(domainID)
+	words := strings.Fields(strings.ReplaceAll(segment, "-", " "))
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
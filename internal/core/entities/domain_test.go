@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"testing"
+)
+
+func TestSystemDomain(t *testing.T) {
+	tests := []struct {
+		id, domain, path string
+	}{
+		{"payments", "", "payments"},
+		{"payments.billing", "payments", "payments/billing"},
+		{"payments.cards.billing", "payments.cards", "payments/cards/billing"},
+	}
+	for _, tt := range tests {
+		if got := SystemDomain(tt.id); got != tt.domain {
+			t.Errorf("SystemDomain(%q) = %q, want %q", tt.id, got, tt.domain)
+		}
+		if got := SystemSourcePath(tt.id); got != tt.path {
+			t.Errorf("SystemSourcePath(%q) = %q, want %q", tt.id, got, tt.path)
+		}
+	}
+	if got := DomainName("payments.order-management"); got != "Order Management" {
+		t.Errorf("DomainName() = %q, want Order Management", got)
+	}
+}
+
+func TestSystemSetDomain(t *testing.T) {
+	sys, _ := NewSystem("Billing")
+	api, _ := NewContainer("API")
+	if err := sys.AddContainer(api); err != nil {
+		t.Fatal(err)
+	}
+
+	sys.SetDomain("payments")
+	if sys.ID != "payments.billing" || sys.Domain != "payments" {
+		t.Errorf("ID, Domain = %q, %q, want payments.billing, payments", sys.ID, sys.Domain)
+	}
+	if api.ParentID != "payments.billing" {
+		t.Errorf("container ParentID = %q, want payments.billing", api.ParentID)
+	}
+	if err := sys.Validate(); err != nil {
+		t.Errorf("Validate() of a namespaced system = %v", err)
+	}
+
+	sys.SetDomain("")
+	if sys.ID != "billing" || sys.Domain != "" {
+		t.Errorf("ID, Domain = %q, %q after clearing, want billing", sys.ID, sys.Domain)
+	}
+}
+
+func TestBuildDomains(t *testing.T) {
+	var systems []*System
+	for _, id := range []string{"web", "payments.billing", "payments.cards.issuing", "payments.cards.auth", "shipping.tracking"} {
+		sys := &System{ID: id, Domain: SystemDomain(id)}
+		systems = append(systems, sys)
+	}
+
+	if got := BuildDomains(systems[:1]); got != nil {
+		t.Errorf("BuildDomains() without namespaces = %v, want nil", got)
+	}
+
+	domains := BuildDomains(systems)
+	if len(domains) != 2 || domains[0].ID != "payments" || domains[1].ID != "shipping" {
+		t.Fatalf("top-level domains = %v, want payments and shipping", domains)
+	}
+	payments := domains[0]
+	if payments.Name != "Payments" || len(payments.Systems) != 1 || payments.Systems[0].ID != "payments.billing" {
+		t.Errorf("payments = %+v", payments)
+	}
+	if len(payments.Domains) != 1 || payments.Domains[0].ID != "payments.cards" {
+		t.Fatalf("payments domains = %v, want payments.cards", payments.Domains)
+	}
+	cards := payments.Domains[0]
+	if len(cards.Systems) != 2 || cards.Systems[0].ID != "payments.cards.auth" {
+		t.Errorf("payments.cards systems are not sorted: %v", cards.Systems)
+	}
+	if got := len(payments.AllSystems()); got != 3 {
+		t.Errorf("AllSystems() = %d systems, want 3", got)
+	}
+
+	var walked []string
+	payments.Walk(func(d *Domain) { walked = append(walked, d.ID) })
+	if len(walked) != 2 || walked[0] != "payments" || walked[1] != "payments.cards" {
+		t.Errorf("Walk() = %v, want payments, payments.cards", walked)
+	}
+}
@@ -3,6 +3,8 @@
 package entities
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strings"
@@ -24,6 +26,20 @@ var (
 	ErrInvalidHierarchy   = errors.New("invalid C4 hierarchy")
 	ErrProjectNotFound    = errors.New("project not found")
 	ErrInvalidD2          = errors.New("invalid D2 syntax")
+	ErrNoHistory          = errors.New("no revision history available")
+	ErrUnknownPreset      = errors.New("unknown project template")
+	ErrIssueNotFound      = errors.New("issue not found")
+	ErrEncrypted          = errors.New("file is encrypted and no decryption key is available")
+	ErrConflict           = errors.New("file changed since it was loaded")
+	ErrTrashEntryNotFound = errors.New("trash entry not found")
+	ErrBuildLogNotFound   = errors.New("build log not found")
+	ErrPathCollision      = errors.New("directories map to the same ID")
+	ErrReservedName       = errors.New("name is reserved by the operating system")
+	ErrSchemaTooNew       = errors.New("project schema is newer than this version of loko supports")
+	ErrInvalidConfig      = errors.New("invalid configuration")
+	ErrPathOutsideSandbox = errors.New("path is outside the sandbox roots")
+	ErrInvalidProposal    = errors.New("invalid architecture proposal")
+	ErrInvalidFrontmatter = errors.New("invalid frontmatter")
 )
 
 // ValidationError represents a validation error with context.
@@ -117,3 +133,28 @@ func (e *DuplicateError) Error() string {
 	}
 	return fmt.Sprintf("%s '%s' already exists", e.Entity, e.ID)
 }
+
+// ConflictError reports a save rejected because the element's file changed
+// since it was loaded: saving would overwrite someone else's edit.
+type ConflictError struct {
+	Entity   string // Entity type (e.g., "System", "Container")
+	ID       string
+	Path     string // File that changed
+	Expected string // Content hash the save expected
+	Actual   string // Content hash of the file; empty if it was deleted
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s '%s' changed since it was loaded (%s); reload it and apply the change again", e.Entity, e.ID, e.Path)
+}
+
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// HashContent returns the content hash of a source file, as recorded in the
+// ContentHash of the element loaded from it.
+func HashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -31,6 +31,9 @@ package entities
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 )
 
 // ArchitectureGraph represents the C4 model as a directed graph.
@@ -66,8 +69,8 @@ type GraphNode struct {
 	// ID is the unique identifier (normalized name)
 	ID string
 
-	// Type is the C4 level (System, Container, Component)
-	Type string // "system", "container", "component"
+	// Type is the C4 element kind (Person, System, Container, Component)
+	Type string // "person", "system", "container", "component"
 
 	// Name is the display name
 	Name string
@@ -82,7 +85,7 @@ type GraphNode struct {
 	ParentID string
 
 	// Data holds reference to the actual entity
-	// Type must be *System, *Container, or *Component (implements C4Entity)
+	// Type must be *Person, *System, *Container, or *Component (implements C4Entity)
 	Data C4Entity
 
 	// Metadata for additional properties
@@ -183,6 +186,12 @@ func (ag *ArchitectureGraph) AddNode(node *GraphNode) error {
 	return nil
 }
 
+// AddAlias makes alias resolve to the node nodeID, e.g. the old ID of a
+// component merged into it.
+func (ag *ArchitectureGraph) AddAlias(alias, nodeID string) {
+	ag.ShortIDMap[alias] = append(ag.ShortIDMap[alias], nodeID)
+}
+
 // GetNode retrieves a node by ID.
 func (ag *ArchitectureGraph) GetNode(id string) *GraphNode {
 	return ag.Nodes[id]
@@ -429,6 +438,9 @@ func (ag *ArchitectureGraph) GetPath(source, target string) []*GraphNode {
 // - System: returns systemID
 // - Container: returns systemID/containerID
 // - Component: returns systemID/containerID/componentID
+//
+// The system ID of a namespaced system carries its domains to any depth,
+// separated by DomainSeparator: "payments.cards.billing/api/handler".
 func QualifiedNodeID(nodeType, systemID, containerID, nodeID string) string {
 	switch nodeType {
 	case "system":
@@ -449,7 +461,12 @@ func QualifiedNodeID(nodeType, systemID, containerID, nodeID string) string {
 }
 
 // ParseQualifiedID parses a qualified ID into its component parts and determines node type.
-// Returns the parts slice and the inferred node type.
+// Returns the parts slice and the inferred node type. The domains of a
+// namespaced system stay in the first part; use SystemDomain to split them.
+//
+// A path deeper than three parts is a component of a namespaced system
+// written as its directory path, to any depth: "payments/cards/billing/api/handler"
+// parses as "payments.cards.billing", "api", "handler".
 func ParseQualifiedID(qualifiedID string) (parts []string, nodeType string) {
 	if qualifiedID == "" {
 		return []string{}, ""
@@ -465,7 +482,9 @@ func ParseQualifiedID(qualifiedID string) (parts []string, nodeType string) {
 	case 3:
 		nodeType = "component"
 	default:
-		nodeType = "unknown"
+		n := len(parts)
+		parts = []string{strings.Join(parts[:n-2], DomainSeparator), parts[n-2], parts[n-1]}
+		nodeType = "component"
 	}
 
 	return parts, nodeType
@@ -498,22 +517,22 @@ func splitID(id string) []string {
 	return parts
 }
 
-// GetNodesByLevel returns all nodes at a specific C4 level.
+// GetNodesByLevel returns all nodes at a specific C4 level, sorted by ID.
 func (ag *ArchitectureGraph) GetNodesByLevel(level int) []*GraphNode {
 	var nodes []*GraphNode
-	for _, node := range ag.Nodes {
-		if node.Level == level {
+	for _, id := range slices.Sorted(maps.Keys(ag.Nodes)) {
+		if node := ag.Nodes[id]; node.Level == level {
 			nodes = append(nodes, node)
 		}
 	}
 	return nodes
 }
 
-// GetNodesByType returns all nodes of a specific type.
+// GetNodesByType returns all nodes of a specific type, sorted by ID.
 func (ag *ArchitectureGraph) GetNodesByType(nodeType string) []*GraphNode {
 	var nodes []*GraphNode
-	for _, node := range ag.Nodes {
-		if node.Type == nodeType {
+	for _, id := range slices.Sorted(maps.Keys(ag.Nodes)) {
+		if node := ag.Nodes[id]; node.Type == nodeType {
 			nodes = append(nodes, node)
 		}
 	}
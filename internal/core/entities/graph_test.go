@@ -376,6 +376,18 @@ func TestParseQualifiedID(t *testing.T) {
 			expectedParts: []string{"backend", "api", "auth"},
 			expectedType:  "component",
 		},
+		{
+			name:          "namespaced component ID",
+			qualifiedID:   "payments.billing/api/handler",
+			expectedParts: []string{"payments.billing", "api", "handler"},
+			expectedType:  "component",
+		},
+		{
+			name:          "component path of a namespaced system",
+			qualifiedID:   "payments/cards/billing/api/handler",
+			expectedParts: []string{"payments.cards.billing", "api", "handler"},
+			expectedType:  "component",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"regexp"
+	"strings"
+)
+
+// IconRefPrefix starts a template variable referencing an icon by name, as
+// in {{icon:essentials/person}}.
+const IconRefPrefix = "icon:"
+
+// IconPack is a named set of SVG icons that templates reference by name.
+// Installed packs live in their own directory under the XDG data directory;
+// built-in packs ship with loko and need no installation.
+type IconPack struct {
+	Name    string
+	Icons   []string // Icon names, sorted, e.g. "person" or "aws/lambda"
+	Builtin bool     // Shipped with loko and not installed
+
+	// Dir is the directory the pack is installed in, or "" for a built-in
+	// pack that is not installed.
+	Dir string
+}
+
+var iconPackNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Validate checks that the pack name can be used as a directory name and in
+// icon references.
+func (p *IconPack) Validate() error {
+	if !iconPackNamePattern.MatchString(p.Name) {
+		return NewValidationError("IconPack", "Name", p.Name, "icon pack name must be lowercase letters, digits, '-' or '_'", nil)
+	}
+	return nil
+}
+
+// SplitIconRef splits an icon reference such as "essentials/person" into
+// its pack and icon name. A reference without a pack, such as "person",
+// returns an empty pack and is looked up in every pack.
+func SplitIconRef(ref string) (pack, name string) {
+	if pack, name, ok := strings.Cut(strings.TrimSpace(ref), "/"); ok {
+		return pack, name
+	}
+	return "", strings.TrimSpace(ref)
+}
@@ -0,0 +1,24 @@
+package entities
+
+// IDIndexSchemaVersion is the version of the ids.json format. Fields may be
+// added within a version; removing or changing the meaning of a field bumps it.
+const IDIndexSchemaVersion = "1"
+
+// IDIndex lists every element of a project a relationship can name, for
+// editors, the language server and agents to complete relationship targets
+// while frontmatter is being written. It is written as .loko/ids.json by
+// `loko build` and kept current by `loko watch`.
+type IDIndex struct {
+	SchemaVersion string         `json:"schema_version"`
+	Elements      []IDIndexEntry `json:"elements"` // Sorted by ID
+}
+
+// IDIndexEntry is an element of an ID index.
+type IDIndexEntry struct {
+	ID          string   `json:"id"`   // Qualified, e.g. "shop/api/handler"
+	Type        string   `json:"type"` // "person", "system", "container" or "component"
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Parent      string   `json:"parent,omitempty"`  // Qualified ID of the enclosing element
+	Aliases     []string `json:"aliases,omitempty"` // Old IDs of components merged into this one
+}
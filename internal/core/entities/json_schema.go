@@ -0,0 +1,353 @@
+package entities
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// JSON Schema names accepted by JSONSchema: the frontmatter of each element
+// kind's Markdown file, loko.toml and the model bundle of loko export model.
+const (
+	SchemaSystem    = "system"
+	SchemaContainer = "container"
+	SchemaComponent = "component"
+	SchemaConfig    = "config"
+	SchemaModel     = "model"
+)
+
+// jsonSchemaDraft is the JSON Schema dialect of the generated schemas, the
+// newest one editors such as VS Code fully support.
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// JSONSchemaNames returns the names of the available JSON Schemas.
+func JSONSchemaNames() []string {
+	return []string{SchemaSystem, SchemaContainer, SchemaComponent, SchemaConfig, SchemaModel}
+}
+
+// JSONSchema returns the named JSON Schema, for editor autocomplete and
+// validation of element frontmatter and loko.toml, and for tools consuming
+// the model bundle.
+func JSONSchema(name string) (map[string]any, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case SchemaSystem:
+		return frontmatterSchema("loko system", "Frontmatter of a system.md file.", map[string]any{
+			"export": map[string]any{
+				"type":        "array",
+				"description": "Formats in which loko build also writes this system as a standalone document to exports/<system-id> in the output.",
+				"items":       map[string]any{"type": "string", "enum": []string{"pdf", "markdown"}},
+			},
+			"relationships": stringMapSchema("Systems this one relates to, mapped to a description of the relationship."),
+		}), nil
+	case SchemaContainer:
+		return frontmatterSchema("loko container", "Frontmatter of a container.md file.", map[string]any{
+			"technology":    stringSchema("Technology stack, e.g. \"Go\" or \"PostgreSQL\"."),
+			"relationships": stringMapSchema("Containers this one relates to, mapped to a description of the relationship."),
+		}), nil
+	case SchemaComponent:
+		return frontmatterSchema("loko component", "Frontmatter of a component.md file.", map[string]any{
+			"id":               stringSchema("Component ID; defaults to the normalized name."),
+			"technology":       stringSchema("Technology stack, e.g. \"Go\" or \"PostgreSQL\"."),
+			"relationships":    stringMapSchema("Components this one relates to, mapped to a description of the relationship."),
+			"code_annotations": stringMapSchema("Source paths implementing the component, mapped to a description."),
+			"dependencies":     stringListSchema("External libraries or services the component depends on."),
+			"aliases":          stringListSchema("Qualified IDs of components merged into this one by loko merge; relationships to them resolve here."),
+		}), nil
+	case SchemaConfig:
+		return configSchema(), nil
+	case SchemaModel:
+		return modelSchema(), nil
+	}
+	return nil, fmt.Errorf("unknown schema %q (expected %s)", name, strings.Join(JSONSchemaNames(), ", "))
+}
+
+// frontmatterSchema returns the schema of an element's frontmatter: the
+// fields common to all elements, extra, and custom fields of any scalar or
+// string list value.
+func frontmatterSchema(title, description string, extra map[string]any) map[string]any {
+	properties := map[string]any{
+		"name":        stringSchema("Display name."),
+		"description": stringSchema("One-line summary shown in listings and diagrams."),
+		"tags":        stringListSchema("Labels used for filtering, e.g. \"critical\" or \"event\"."),
+		"issues":      stringListSchema("Issue tracker IDs, e.g. \"PAY-123\", linked with [issues] url_template."),
+	}
+	maps.Copy(properties, extra)
+	return map[string]any{
+		"$schema":     jsonSchemaDraft,
+		"title":       title,
+		"description": description + " Other keys are kept as custom fields.",
+		"type":        "object",
+		"required":    []string{"name"},
+		"properties":  properties,
+		"additionalProperties": map[string]any{
+			"description": "Custom field, listed on pages with [site] custom_fields and searchable as meta.<key>=<value>.",
+			"anyOf": []any{
+				map[string]any{"type": []string{"string", "number", "boolean"}},
+				map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			},
+		},
+	}
+}
+
+// configSchema returns the schema of loko.toml.
+func configSchema() map[string]any {
+	commands := map[string]any{
+		"anyOf": []any{
+			map[string]any{"type": "string"},
+			map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		},
+	}
+	hook := func(description string) map[string]any {
+		return withDescription(commands, description)
+	}
+	kinds := strings.Join(elementKinds, "|")
+
+	return map[string]any{
+		"$schema":     jsonSchemaDraft,
+		"title":       "loko.toml",
+		"description": "loko project configuration.",
+		"type":        "object",
+		"properties": map[string]any{
+			"schema_version": intSchema("Version of the project layout and frontmatter formats, 1 if missing. Upgrade with `loko migrate`.", LegacySchemaVersion),
+			"project": section("Project metadata displayed in documentation.", map[string]any{
+				"name":        stringSchema("Project name."),
+				"description": stringSchema("Project description."),
+				"version":     stringSchema("Documentation version."),
+			}),
+			"paths": section("Directory configuration.", map[string]any{
+				"source":      withDefault(stringSchema("Source directory for architecture files."), "./src"),
+				"source_dirs": stringListSchema("Additional source directories, e.g. of other repositories in a monorepo. New elements are created in source."),
+				"output":      withDefault(stringSchema("Output directory for generated documentation."), "./dist"),
+			}),
+			"d2": section("Diagram rendering.", map[string]any{
+				"theme":           withDefault(stringSchema("D2 theme name."), "neutral-default"),
+				"layout":          withDefault(enumSchema("Layout engine.", "elk", "dagre", "tala"), "elk"),
+				"cache":           withDefault(boolSchema("Cache rendered diagrams for faster rebuilds."), true),
+				"style":           enumSchema("C4 styling preset for generated diagrams.", DiagramStyleNames()...),
+				"legend":          withDefault(boolSchema("Add a legend of the shapes and edge types used to every rendered diagram."), true),
+				"metadata_footer": withDefault(boolSchema("Add a project, element, date and version footer to every rendered diagram."), true),
+			}),
+			"outputs": section("Output formats.", map[string]any{
+				"html":     withDefault(boolSchema("Generate the HTML documentation site."), true),
+				"markdown": withDefault(boolSchema("Generate a single README.md file."), false),
+				"pdf":      withDefault(boolSchema("Generate a PDF (requires veve-cli)."), false),
+			}),
+			"build": section("Build settings.", map[string]any{
+				"parallel":    withDefault(boolSchema("Render diagrams in parallel."), true),
+				"max_workers": withDefault(intSchema("Maximum number of parallel workers.", 1), 4),
+				"minify":      withDefault(boolSchema("Minify generated HTML, CSS and JS and optimize SVG diagrams."), false),
+			}),
+			"server": section("Development servers.", map[string]any{
+				"serve_port": withDefault(intSchema("Port of the preview server (loko serve).", 1), 8080),
+				"api_port":   withDefault(intSchema("Port of the API server (loko api).", 1), 8081),
+				"hot_reload": withDefault(boolSchema("Reload the browser on changes."), true),
+			}),
+			"site": section("HTML site customization.", map[string]any{
+				"head":          stringSchema("HTML appended to <head>."),
+				"head_file":     stringSchema("File whose contents are appended to <head>, relative to the project root."),
+				"footer":        stringSchema("HTML appended to the page footer."),
+				"footer_file":   stringSchema("File whose contents are appended to the footer, relative to the project root."),
+				"analytics":     enumSchema("Analytics provider.", "plausible", "google"),
+				"analytics_id":  stringSchema("Plausible site domain or Google Analytics measurement ID."),
+				"custom_fields": stringListSchema("Custom frontmatter fields listed on system, container and component pages."),
+				"base_path":     stringSchema("URL path the site is served under, e.g. /architecture/ for a GitHub Pages project site."),
+				"pretty_urls":   withDefault(boolSchema("Write pages as <name>/index.html and link them without the .html extension."), false),
+				"layouts": map[string]any{
+					"type":                 "object",
+					"description":          "Page templates replacing the built-in ones, relative to the project root, by element type (system, container, component), external for external systems or tag:<name> for elements with a tag.",
+					"propertyNames":        map[string]any{"pattern": "^(system|container|component|external|tag:.+)$"},
+					"additionalProperties": map[string]any{"type": "string"},
+				},
+			}),
+			"issues": section("Issue tracker links.", map[string]any{
+				"url_template": stringSchema("Link for ticket IDs; {id} is replaced by the ID."),
+				"tracker":      enumSchema("Issue tracker API used by loko validate --check-issues.", "jira"),
+				"tracker_url":  stringSchema("Tracker base URL; defaults to the host of url_template."),
+			}),
+			"encryption": section("Encryption of sources at rest.", map[string]any{
+				"recipients_file": withDefault(stringSchema("File of age or SSH public keys to encrypt to, relative to the project root."), ".loko/recipients.txt"),
+			}),
+			"redaction": section("Redacted exports for external audiences.", map[string]any{
+				"remove_tags":   stringListSchema("Elements with any of these tags are removed with their children."),
+				"strip_fields":  stringListSchema("Element fields to empty, e.g. \"description\" or \"metadata\"."),
+				"mask_patterns": stringListSchema("Regular expressions replaced by mask."),
+				"mask":          withDefault(stringSchema("Replacement text for masked content."), "[redacted]"),
+			}),
+			"permissions": section("MCP tool permissions.", map[string]any{
+				"mcp_role":  withDefault(enumSchema("reader hides every tool that changes the model.", string(RoleReader), string(RoleEditor)), string(RoleEditor)),
+				"mcp_allow": stringListSchema("Glob patterns of the tools sessions may use."),
+				"mcp_deny":  stringListSchema("Glob patterns of the tools sessions may not use."),
+			}),
+			"git": section("Git integration.", map[string]any{
+				"auto_commit": withDefault(boolSchema("Commit the files changed by each successful MCP tool call or API write."), false),
+			}),
+			"plugins": section("Plugins.", map[string]any{
+				"renderer": stringSchema("Renderer plugin used in place of the d2 CLI."),
+			}),
+			"hooks": section("Shell commands run around builds and validation.", map[string]any{
+				"pre_build":    hook("Run before a build reads sources; a failing command aborts the build."),
+				"post_build":   hook("Run after a successful build; a failing command fails the build."),
+				"pre_validate": hook("Run before loko validate reads sources."),
+			}),
+			"icons": map[string]any{
+				"type":                 "object",
+				"description":          "Technology names mapped to icon URLs or paths, overriding the built-in icons.",
+				"additionalProperties": map[string]any{"type": "string"},
+			},
+			"relationship_types": map[string]any{
+				"type":        "object",
+				"description": "Custom relationship kinds, defined as \"sources -> targets\" element kinds; a built-in kind of the same name is replaced.",
+				"additionalProperties": map[string]any{
+					"type":        "string",
+					"description": "Comma-separated element kinds (" + kinds + ") or * on each side.",
+					"pattern":     "->",
+				},
+			},
+		},
+	}
+}
+
+// modelSchema returns the schema of a ModelBundle, as written by loko export
+// model and loko build --format json.
+func modelSchema() map[string]any {
+	object := func(description string, required []string, properties map[string]any) map[string]any {
+		return map[string]any{
+			"type":        "object",
+			"description": description,
+			"required":    required,
+			"properties":  properties,
+		}
+	}
+	list := func(description string, items map[string]any) map[string]any {
+		return map[string]any{"type": "array", "description": description, "items": items}
+	}
+	element := func(description string, extra map[string]any) map[string]any {
+		properties := map[string]any{
+			"id":          stringSchema("Qualified ID: \"system\", \"system/container\" or \"system/container/component\"."),
+			"name":        stringSchema("Display name."),
+			"description": stringSchema("One-line summary."),
+			"tags":        stringListSchema("Labels used for filtering."),
+			"issues":      stringListSchema("Issue tracker IDs."),
+			"metadata":    map[string]any{"type": "object", "description": "Custom frontmatter fields."},
+		}
+		maps.Copy(properties, extra)
+		return object(description, []string{"id", "name"}, properties)
+	}
+
+	component := element("A component of a container.", map[string]any{
+		"parent":           stringSchema("Qualified ID of the parent component."),
+		"technology":       stringSchema("Technology stack."),
+		"relationships":    stringMapSchema("Targets as written in frontmatter, mapped to a description of the relationship."),
+		"code_annotations": stringMapSchema("Source paths implementing the component, mapped to a description."),
+		"dependencies":     stringListSchema("External libraries or services the component depends on."),
+		"aliases":          stringListSchema("Qualified IDs of components merged into this one."),
+	})
+	container := element("A container of a system.", map[string]any{
+		"technology": stringSchema("Technology stack."),
+		"components": list("Components of the container, sorted by ID.", component),
+	})
+	system := element("A system and its elements.", map[string]any{
+		"domain":     stringSchema("Business domain of the system."),
+		"containers": list("Containers of the system, sorted by ID.", container),
+	})
+	relationship := object("A relationship of a system's relationships.toml.", []string{"id", "source", "target", "label"}, map[string]any{
+		"id":         stringSchema("Hash of the source, target and label."),
+		"source":     stringSchema("Element path of the source, e.g. \"shop/api\"."),
+		"target":     stringSchema("Element path of the target."),
+		"label":      stringSchema("Description of the relationship."),
+		"type":       enumSchema("Interaction pattern.", "sync", "async", "event"),
+		"technology": stringSchema("Technology used, e.g. \"gRPC\"."),
+		"direction":  enumSchema("Direction of the relationship.", "forward", "bidirectional"),
+		"kind":       stringSchema("Semantic relationship type, e.g. \"uses\" or \"reads\"."),
+	})
+	diagram := object("The D2 diagram of an element.", []string{"element", "level", "hash"}, map[string]any{
+		"element":  stringSchema("Qualified ID of the element."),
+		"level":    enumSchema("Kind of the element.", "system", "container", "component"),
+		"hash":     stringSchema("SHA-256 of the D2 source."),
+		"rendered": stringSchema("SVG path relative to the output directory, if rendered."),
+	})
+	node := object("An element of the graph.", []string{"id", "type", "level"}, map[string]any{
+		"id":     stringSchema("Qualified ID."),
+		"type":   enumSchema("Kind of the element.", "system", "container", "component"),
+		"level":  map[string]any{"type": "integer", "description": "C4 level.", "enum": []int{1, 2, 3}},
+		"parent": stringSchema("Qualified ID of the parent element."),
+	})
+	edge := object("A dependency between two elements of the graph.", []string{"source", "target", "type"}, map[string]any{
+		"source":      stringSchema("Qualified ID of the source."),
+		"target":      stringSchema("Qualified ID of the target."),
+		"type":        stringSchema("Edge type, e.g. \"depends-on\"."),
+		"description": stringSchema("Description of the relationship."),
+	})
+
+	schema := object("The architecture model of a loko project, written by loko export model and loko build --format json. Fields may be added within a schema_version.",
+		[]string{"$schema", "schema_version", "project", "systems", "relationships", "diagrams", "graph"},
+		map[string]any{
+			"$schema":        map[string]any{"const": ModelBundleSchema, "description": "Identifies the model bundle format."},
+			"schema_version": map[string]any{"const": ModelBundleSchemaVersion, "description": "Version of the format; consumers should reject versions they do not know."},
+			"project": object("The project.", []string{"name"}, map[string]any{
+				"name":        stringSchema("Project name."),
+				"description": stringSchema("Project description."),
+				"version":     stringSchema("Documentation version."),
+			}),
+			"systems":       list("Systems, sorted by ID.", system),
+			"relationships": list("Relationships of the systems' relationships.toml files.", relationship),
+			"diagrams":      list("The diagram of each element that has one.", diagram),
+			"graph": object("The model as nodes and dependency edges.", []string{"nodes", "edges"}, map[string]any{
+				"nodes": list("Elements, sorted by ID.", node),
+				"edges": list("Dependencies, sorted by source and target.", edge),
+			}),
+		})
+	schema["$schema"] = jsonSchemaDraft
+	schema["$id"] = ModelBundleSchema
+	schema["title"] = "loko model"
+	return schema
+}
+
+// section returns the schema of a TOML table with the given keys.
+func section(description string, properties map[string]any) map[string]any {
+	return map[string]any{
+		"type":                 "object",
+		"description":          description,
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+func stringSchema(description string) map[string]any {
+	return map[string]any{"type": "string", "description": description}
+}
+
+func boolSchema(description string) map[string]any {
+	return map[string]any{"type": "boolean", "description": description}
+}
+
+func intSchema(description string, minimum int) map[string]any {
+	return map[string]any{"type": "integer", "description": description, "minimum": minimum}
+}
+
+func enumSchema(description string, values ...string) map[string]any {
+	return map[string]any{"type": "string", "description": description, "enum": values}
+}
+
+func stringListSchema(description string) map[string]any {
+	return map[string]any{"type": "array", "description": description, "items": map[string]any{"type": "string"}}
+}
+
+// stringMapSchema returns the schema of an object with string values.
+func stringMapSchema(description string) map[string]any {
+	return map[string]any{"type": "object", "description": description, "additionalProperties": map[string]any{"type": "string"}}
+}
+
+// withDefault returns a copy of schema with a default value.
+func withDefault(schema map[string]any, value any) map[string]any {
+	schema = maps.Clone(schema)
+	schema["default"] = value
+	return schema
+}
+
+// withDescription returns a copy of schema with a description.
+func withDescription(schema map[string]any, description string) map[string]any {
+	schema = maps.Clone(schema)
+	schema["description"] = description
+	return schema
+}
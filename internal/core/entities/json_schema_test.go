@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	for _, name := range JSONSchemaNames() {
+		schema, err := JSONSchema(name)
+		if err != nil {
+			t.Fatalf("JSONSchema(%q) error: %v", name, err)
+		}
+		if schema["$schema"] != jsonSchemaDraft {
+			t.Errorf("%s: $schema = %v", name, schema["$schema"])
+		}
+		if _, err := json.Marshal(schema); err != nil {
+			t.Errorf("%s: marshal: %v", name, err)
+		}
+	}
+
+	for _, name := range JSONSchemaNames()[:3] {
+		schema, _ := JSONSchema(name)
+		if _, ok := schema["properties"].(map[string]any)["relationships"]; !ok {
+			t.Errorf("%s schema lacks relationships", name)
+		}
+	}
+	system, _ := JSONSchema(SchemaSystem)
+	if _, ok := system["properties"].(map[string]any)["technology"]; ok {
+		t.Error("system schema should not have technology")
+	}
+
+	config, _ := JSONSchema(SchemaConfig)
+	d2 := config["properties"].(map[string]any)["d2"].(map[string]any)
+	style := d2["properties"].(map[string]any)["style"].(map[string]any)
+	if !slices.Equal(style["enum"].([]string), DiagramStyleNames()) {
+		t.Errorf("d2.style enum = %v, want %v", style["enum"], DiagramStyleNames())
+	}
+
+	model, _ := JSONSchema(SchemaModel)
+	if model["$id"] != ModelBundleSchema {
+		t.Errorf("model $id = %v, want %s", model["$id"], ModelBundleSchema)
+	}
+	data, _ := json.Marshal(ModelBundle{})
+	var bundle map[string]any
+	_ = json.Unmarshal(data, &bundle)
+	properties := model["properties"].(map[string]any)
+	for key := range bundle {
+		if _, ok := properties[key]; !ok {
+			t.Errorf("model schema lacks %q", key)
+		}
+	}
+
+	if _, err := JSONSchema("person"); err == nil {
+		t.Error("expected error for unknown schema")
+	}
+}
@@ -0,0 +1,104 @@
+package entities
+
+// ModelBundleSchemaVersion is the version of the model.json format. Fields
+// may be added within a version; removing or changing the meaning of a field
+// bumps it, so consumers should reject versions they do not know.
+const ModelBundleSchemaVersion = "1"
+
+// ModelBundleSchema identifies the model.json format in its "$schema" field.
+const ModelBundleSchema = "https://github.com/madstone-tech/loko/model/v1"
+
+// ModelBundle is the whole architecture model of a project in one document,
+// loko's stable interchange format for external tools. It is written as
+// model.json by `loko build --format json`. Element IDs are qualified:
+// "system", "system/container" and "system/container/component".
+type ModelBundle struct {
+	Schema        string         `json:"$schema"`
+	SchemaVersion string         `json:"schema_version"`
+	Project       BundleProject  `json:"project"`
+	Systems       []BundleSystem `json:"systems"`
+
+	// Relationships are those of the systems' relationships.toml files
+	Relationships []Relationship `json:"relationships"`
+
+	// Diagrams lists the D2 diagram of each element that has one
+	Diagrams []BundleDiagram `json:"diagrams"`
+
+	// Graph is the model as nodes and dependency edges, merged from
+	// component frontmatter and relationships.toml
+	Graph BundleGraph `json:"graph"`
+}
+
+// BundleProject describes the project of a model bundle.
+type BundleProject struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version,omitempty"`
+}
+
+// BundleElement holds the fields common to systems, containers and components
+// in a model bundle.
+type BundleElement struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+	Issues      []string       `json:"issues,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty"` // Custom frontmatter fields
+}
+
+// BundleSystem is a system of a model bundle.
+type BundleSystem struct {
+	BundleElement
+	Domain     string            `json:"domain,omitempty"`
+	Containers []BundleContainer `json:"containers"`
+}
+
+// BundleContainer is a container of a model bundle.
+type BundleContainer struct {
+	BundleElement
+	Technology string            `json:"technology,omitempty"`
+	Components []BundleComponent `json:"components"`
+}
+
+// BundleComponent is a component of a model bundle.
+type BundleComponent struct {
+	BundleElement
+	Parent          string            `json:"parent,omitempty"` // Qualified ID of the parent component
+	Technology      string            `json:"technology,omitempty"`
+	Relationships   map[string]string `json:"relationships,omitempty"` // Target as written in frontmatter to description
+	CodeAnnotations map[string]string `json:"code_annotations,omitempty"`
+	Dependencies    []string          `json:"dependencies,omitempty"`
+	Aliases         []string          `json:"aliases,omitempty"`
+}
+
+// BundleDiagram describes the diagram of an element.
+type BundleDiagram struct {
+	Element  string `json:"element"`            // Qualified ID
+	Level    string `json:"level"`              // "system", "container" or "component"
+	Hash     string `json:"hash"`               // SHA-256 of the D2 source
+	Rendered string `json:"rendered,omitempty"` // SVG path relative to the output directory, if rendered
+}
+
+// BundleGraph is the architecture graph of a model bundle.
+type BundleGraph struct {
+	Nodes []BundleGraphNode `json:"nodes"`
+	Edges []BundleGraphEdge `json:"edges"`
+}
+
+// BundleGraphNode is an element in the graph of a model bundle.
+type BundleGraphNode struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`  // "system", "container" or "component"
+	Level  int    `json:"level"` // C4 level: 1, 2 or 3
+	Parent string `json:"parent,omitempty"`
+}
+
+// BundleGraphEdge is a dependency between two elements in the graph of a
+// model bundle.
+type BundleGraphEdge struct {
+	Source      string `json:"source"`
+	Target      string `json:"target"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+}
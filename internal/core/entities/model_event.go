@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// ModelEventType identifies what happened to the architecture model.
+type ModelEventType string
+
+const (
+	EventEntityCreated  ModelEventType = "entity.created"
+	EventEntityUpdated  ModelEventType = "entity.updated"
+	EventEntityDeleted  ModelEventType = "entity.deleted"
+	EventBuildStarted   ModelEventType = "build.started"
+	EventBuildCompleted ModelEventType = "build.completed"
+	EventBuildFailed    ModelEventType = "build.failed"
+	EventBuildCancelled ModelEventType = "build.cancelled"
+)
+
+// ModelEvent is a change to the model or a documentation build, pushed to
+// live subscribers such as dashboards and editor extensions.
+type ModelEvent struct {
+	Type    ModelEventType `json:"type"`
+	Time    time.Time      `json:"time"`
+	Entity  string         `json:"entity,omitempty"`   // Element kind: system, container or component
+	ID      string         `json:"id,omitempty"`       // Qualified element ID, e.g. "payments/api"
+	BuildID string         `json:"build_id,omitempty"` // Set for build events
+	Error   string         `json:"error,omitempty"`    // Set for failed and cancelled builds
+}
@@ -0,0 +1,21 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OwnerField is the frontmatter field naming the team or person that owns an
+// element, such as "payments-team" or "alice@example.com". Containers and
+// components without one belong to the owner of their parent.
+const OwnerField = "owner"
+
+// OwnerOf returns the owner declared in an element's metadata, or "" when it
+// declares none.
+func OwnerOf(metadata map[string]any) string {
+	value, ok := metadata[OwnerField]
+	if !ok || value == nil {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprint(value))
+}
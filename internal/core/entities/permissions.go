@@ -0,0 +1,61 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Role is the level of access granted to an API token or MCP session.
+type Role string
+
+const (
+	RoleReader Role = "reader" // May query the architecture but not change it
+	RoleEditor Role = "editor" // May query and change the architecture
+)
+
+// ParseRole parses a role name. An empty name is an editor, the access loko
+// grants when no permissions are configured.
+func ParseRole(name string) (Role, error) {
+	switch Role(strings.ToLower(strings.TrimSpace(name))) {
+	case "", RoleEditor:
+		return RoleEditor, nil
+	case RoleReader:
+		return RoleReader, nil
+	default:
+		return "", fmt.Errorf("unknown role %q (expected %s or %s)", name, RoleReader, RoleEditor)
+	}
+}
+
+// CanModify reports whether the role may change the architecture.
+func (r Role) CanModify() bool {
+	return r != RoleReader
+}
+
+// ToolPolicy decides which MCP tools a session may list and call.
+type ToolPolicy struct {
+	Role  Role
+	Allow []string // Glob patterns of permitted tools; empty permits all
+	Deny  []string // Glob patterns of forbidden tools; take precedence over Allow
+}
+
+// Allows reports whether the policy permits the named tool. mutating tells
+// whether the tool changes the architecture, which readers may not do.
+func (p ToolPolicy) Allows(tool string, mutating bool) bool {
+	if mutating && !p.Role.CanModify() {
+		return false
+	}
+	for _, pattern := range p.Deny {
+		if NewGlobMatcher(pattern).Match(tool) {
+			return false
+		}
+	}
+	if len(p.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range p.Allow {
+		if NewGlobMatcher(pattern).Match(tool) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package entities
+
+import "testing"
+
+func TestParseRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Role
+		wantErr bool
+	}{
+		{"", RoleEditor, false},
+		{"editor", RoleEditor, false},
+		{" Reader ", RoleReader, false},
+		{"admin", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRole(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseRole(%q) = %q, %v", tt.name, got, err)
+		}
+	}
+}
+
+func TestToolPolicy_Allows(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   ToolPolicy
+		tool     string
+		mutating bool
+		want     bool
+	}{
+		{"editor default", ToolPolicy{Role: RoleEditor}, "create_system", true, true},
+		{"reader query", ToolPolicy{Role: RoleReader}, "query_project", false, true},
+		{"reader mutation", ToolPolicy{Role: RoleReader}, "create_system", true, false},
+		{"denied by glob", ToolPolicy{Role: RoleEditor, Deny: []string{"delete_*"}}, "delete_relationship", true, false},
+		{"not denied", ToolPolicy{Role: RoleEditor, Deny: []string{"delete_*"}}, "create_relationship", true, true},
+		{"allowlisted", ToolPolicy{Role: RoleEditor, Allow: []string{"query_*", "search_elements"}}, "search_elements", false, true},
+		{"not allowlisted", ToolPolicy{Role: RoleEditor, Allow: []string{"query_*"}}, "build_docs", false, false},
+		{"deny beats allow", ToolPolicy{Role: RoleEditor, Allow: []string{"*"}, Deny: []string{"build_docs"}}, "build_docs", false, false},
+		{"allow does not lift role", ToolPolicy{Role: RoleReader, Allow: []string{"update_*"}}, "update_system", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Allows(tt.tool, tt.mutating); got != tt.want {
+				t.Errorf("Allows(%q) = %v, want %v", tt.tool, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package entities
+
+import (
+	"slices"
+	"strings"
+)
+
+// Person represents a C4 person - a user or actor of the systems, such as a
+// customer or an operator. People are drawn in system context diagrams.
+type Person struct {
+	// ID is the unique identifier (used in file paths)
+	ID string `json:"id" toon:"id"`
+
+	// Name is the display name
+	Name string `json:"name" toon:"name"`
+
+	// Description explains who this person is
+	Description string `json:"description" toon:"description,omitempty"`
+
+	// Tags for categorization and filtering
+	Tags []string `json:"tags" toon:"tags,omitempty"`
+
+	// External indicates if the person is outside the organization, e.g. a customer
+	External bool `json:"external" toon:"external,omitempty"`
+
+	// Relationships to systems, containers or components (maps element ID to
+	// relationship description)
+	Relationships map[string]string `json:"relationships,omitempty" toon:"relationships,omitempty"`
+
+	// Metadata holds additional frontmatter fields
+	Metadata map[string]any `json:"metadata" toon:"metadata,omitempty"`
+
+	// Path is the filesystem path to this person's directory
+	Path string `json:"path" toon:"path,omitempty"`
+
+	// ContentHash is the hash of person.md when the person was loaded or last
+	// saved. A save fails with a ConflictError if the file no longer has it.
+	ContentHash string `json:"content_hash,omitempty" toon:"content_hash,omitempty"`
+}
+
+// NewPerson creates a new person with the given name.
+func NewPerson(name string) (*Person, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, NewValidationError("Person", "Name", name, "invalid name", err)
+	}
+
+	return &Person{
+		ID:       NormalizeName(name),
+		Name:     name,
+		Tags:     []string{},
+		Metadata: make(map[string]any),
+	}, nil
+}
+
+// Validate checks if the person is valid.
+func (p *Person) Validate() error {
+	var errs ValidationErrors
+
+	if err := ValidateName(p.Name); err != nil {
+		errs.Add("Person", "Name", p.Name, "invalid name", err)
+	}
+
+	if err := ValidateID(p.ID); err != nil {
+		errs.Add("Person", "ID", p.ID, "invalid id", err)
+	}
+
+	if errs.HasErrors() {
+		return errs
+	}
+	return nil
+}
+
+// AddTag adds a tag to the person.
+func (p *Person) AddTag(tag string) {
+	if !slices.Contains(p.Tags, tag) {
+		p.Tags = append(p.Tags, tag)
+	}
+}
+
+// AddRelationship adds a relationship to a system, container or component.
+func (p *Person) AddRelationship(targetID, description string) {
+	if targetID == "" {
+		return
+	}
+	if p.Relationships == nil {
+		p.Relationships = make(map[string]string)
+	}
+	p.Relationships[targetID] = description
+}
+
+// RemoveRelationship removes a relationship to an element.
+func (p *Person) RemoveRelationship(targetID string) {
+	delete(p.Relationships, targetID)
+}
+
+// Uses reports whether the person has a relationship to the system with ID
+// systemID or to one of its elements, e.g. "payments" or "payments/api".
+func (p *Person) Uses(systemID string) bool {
+	for target := range p.Relationships {
+		if target == systemID || strings.HasPrefix(target, systemID+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// GetID returns the person's unique identifier (implements C4Entity).
+func (p *Person) GetID() string {
+	return p.ID
+}
+
+// GetName returns the person's display name (implements C4Entity).
+func (p *Person) GetName() string {
+	return p.Name
+}
+
+// GetEntityType returns "person" (implements C4Entity).
+func (p *Person) GetEntityType() string {
+	return "person"
+}
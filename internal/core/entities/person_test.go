@@ -0,0 +1,73 @@
+package entities
+
+import (
+	"testing"
+)
+
+func TestNewPerson(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantID  string
+		wantErr bool
+	}{
+		{"valid simple", "Customer", "customer", false},
+		{"valid with spaces", "Support Agent", "support-agent", false},
+		{"empty", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			person, err := NewPerson(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewPerson(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				return
+			}
+			if err == nil && person.ID != tt.wantID {
+				t.Errorf("NewPerson(%q).ID = %q, want %q", tt.input, person.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestPerson_Uses(t *testing.T) {
+	person, _ := NewPerson("Customer")
+	person.AddRelationship("payments/api", "Pays via")
+	person.AddRelationship("", "ignored")
+
+	if len(person.Relationships) != 1 {
+		t.Errorf("Relationships = %v", person.Relationships)
+	}
+	if !person.Uses("payments") {
+		t.Error("Uses(payments) = false, want true")
+	}
+	if person.Uses("pay") {
+		t.Error("Uses(pay) = true, want false")
+	}
+
+	person.RemoveRelationship("payments/api")
+	if person.Uses("payments") {
+		t.Error("Uses(payments) after remove = true, want false")
+	}
+}
+
+func TestProject_People(t *testing.T) {
+	project, _ := NewProject("Shop")
+	customer, _ := NewPerson("Customer")
+	admin, _ := NewPerson("Admin")
+
+	if err := project.AddPerson(customer); err != nil {
+		t.Fatalf("AddPerson() error = %v", err)
+	}
+	if err := project.AddPerson(admin); err != nil {
+		t.Fatalf("AddPerson() error = %v", err)
+	}
+	if err := project.AddPerson(customer); err == nil {
+		t.Error("AddPerson() of a duplicate should fail")
+	}
+
+	people := project.ListPeople()
+	if len(people) != 2 || people[0].ID != "admin" || people[1].ID != "customer" {
+		t.Errorf("ListPeople() = %v, want admin and customer", people)
+	}
+}
@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// PluginProtocolVersion is the version of the subprocess protocol loko speaks
+// with plugins. Plugins report the version they implement when initialized.
+const PluginProtocolVersion = 1
+
+// PluginCapability is an extension point a plugin implements.
+type PluginCapability string
+
+const (
+	PluginRenderer  PluginCapability = "renderer"  // Renders D2 source to SVG in place of the d2 CLI
+	PluginExporter  PluginCapability = "exporter"  // Writes the model in a custom output format
+	PluginValidator PluginCapability = "validator" // Reports additional architecture issues
+	PluginImporter  PluginCapability = "importer"  // Creates source files from an external system
+)
+
+// PluginCapabilities lists every capability in a stable order.
+var PluginCapabilities = []PluginCapability{PluginRenderer, PluginExporter, PluginValidator, PluginImporter}
+
+// pluginNamePattern restricts plugin names to safe directory names.
+var pluginNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// PluginManifest describes an installed plugin. It is stored as plugin.json
+// in the plugin's directory under the XDG data directory.
+type PluginManifest struct {
+	Name            string             `json:"name"`
+	Version         string             `json:"version,omitempty"`
+	Description     string             `json:"description,omitempty"`
+	Capabilities    []PluginCapability `json:"capabilities"`
+	ProtocolVersion int                `json:"protocolVersion"`
+
+	// Executable is the plugin program, relative to Dir in plugin.json.
+	Executable string `json:"executable"`
+
+	// Dir is the directory the plugin is installed in. It is not stored.
+	Dir string `json:"-"`
+}
+
+// Provides reports whether the plugin implements capability.
+func (m *PluginManifest) Provides(capability PluginCapability) bool {
+	return slices.Contains(m.Capabilities, capability)
+}
+
+// Validate checks that the manifest names a usable plugin for this version
+// of loko.
+func (m *PluginManifest) Validate() error {
+	if !pluginNamePattern.MatchString(m.Name) {
+		return NewValidationError("PluginManifest", "Name", m.Name, "plugin name must be lowercase letters, digits, '-' or '_'", nil)
+	}
+	if m.Executable == "" {
+		return NewValidationError("PluginManifest", "Executable", "", "plugin executable is required", nil)
+	}
+	if len(m.Capabilities) == 0 {
+		return NewValidationError("PluginManifest", "Capabilities", "", "plugin must declare at least one capability", nil)
+	}
+	for _, capability := range m.Capabilities {
+		if !slices.Contains(PluginCapabilities, capability) {
+			return NewValidationError("PluginManifest", "Capabilities", string(capability), fmt.Sprintf("unknown capability (expected one of %v)", PluginCapabilities), nil)
+		}
+	}
+	if m.ProtocolVersion != PluginProtocolVersion {
+		return NewValidationError("PluginManifest", "ProtocolVersion", fmt.Sprint(m.ProtocolVersion), fmt.Sprintf("unsupported plugin protocol version (loko speaks %d)", PluginProtocolVersion), nil)
+	}
+	return nil
+}
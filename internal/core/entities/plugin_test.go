@@ -0,0 +1,41 @@
+package entities
+
+import "testing"
+
+func TestPluginManifest_Validate(t *testing.T) {
+	valid := func() *PluginManifest {
+		return &PluginManifest{
+			Name:            "structurizr",
+			Capabilities:    []PluginCapability{PluginExporter, PluginImporter},
+			ProtocolVersion: PluginProtocolVersion,
+			Executable:      "loko-structurizr",
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(m *PluginManifest)
+		wantErr bool
+	}{
+		{"valid", func(m *PluginManifest) {}, false},
+		{"path in name", func(m *PluginManifest) { m.Name = "../evil" }, true},
+		{"uppercase name", func(m *PluginManifest) { m.Name = "Structurizr" }, true},
+		{"no executable", func(m *PluginManifest) { m.Executable = "" }, true},
+		{"no capabilities", func(m *PluginManifest) { m.Capabilities = nil }, true},
+		{"unknown capability", func(m *PluginManifest) { m.Capabilities = []PluginCapability{"publisher"} }, true},
+		{"future protocol", func(m *PluginManifest) { m.ProtocolVersion = PluginProtocolVersion + 1 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := valid()
+			tt.modify(m)
+			if err := m.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if m := valid(); !m.Provides(PluginImporter) || m.Provides(PluginRenderer) {
+		t.Error("Provides() does not match the declared capabilities")
+	}
+}
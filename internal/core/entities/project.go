@@ -1,6 +1,10 @@
 package entities
 
-import "time"
+import (
+	"maps"
+	"slices"
+	"time"
+)
 
 // Project represents the root of a loko architecture documentation project.
 // It corresponds to a loko.toml file and its directory structure.
@@ -17,6 +21,9 @@ type Project struct {
 	// Systems within this project
 	Systems map[string]*System `json:"systems" toon:"systems"`
 
+	// People who use the systems, by ID
+	People map[string]*Person `json:"people,omitempty" toon:"people,omitempty"`
+
 	// Config holds the parsed loko.toml configuration
 	Config *ProjectConfig `jsonon:"config,omitempty"`
 
@@ -35,9 +42,14 @@ type Project struct {
 
 // ProjectConfig holds the loko.toml configuration values.
 type ProjectConfig struct {
+	// SchemaVersion is the version of the project's layout and frontmatter
+	// formats; LegacySchemaVersion when loko.toml does not record one
+	SchemaVersion int // Default: CurrentSchemaVersion
+
 	// Paths configuration
-	SourceDir string // Default: "./src"
-	OutputDir string // Default: "./dist"
+	SourceDir  string   // Default: "./src"
+	SourceDirs []string // Additional source directories, e.g. of other repositories in a monorepo
+	OutputDir  string   // Default: "./dist"
 
 	// Template configuration
 	Template string // Default: "standard-3layer"
@@ -46,6 +58,11 @@ type ProjectConfig struct {
 	D2Theme  string // Default: "neutral-default"
 	D2Layout string // Default: "elk"
 	D2Cache  bool   // Default: true
+	D2Style  string // C4 styling preset for generated diagrams: "classic", "neutral" or "dark"; empty keeps the plain style
+
+	// Annotations added to every rendered diagram
+	DiagramLegend bool // Legend of the node and edge types used; Default: true
+	DiagramFooter bool // Footer with project, entity, date and loko version; Default: true
 
 	// Output configuration
 	HTMLEnabled     bool // Default: true
@@ -55,6 +72,93 @@ type ProjectConfig struct {
 	// Build configuration
 	Parallel   bool // Default: true
 	MaxWorkers int  // Default: 4
+	Minify     bool // Default: false
+
+	// Site customization, injected into every generated HTML page
+	CustomHead        string   // Raw HTML appended to <head>
+	CustomHeadFile    string   // File whose contents are appended to <head>, relative to the project root
+	CustomFooter      string   // Raw HTML appended to the page footer
+	CustomFooterFile  string   // File whose contents are appended to the footer, relative to the project root
+	AnalyticsProvider string   // "plausible" or "google"; empty disables analytics
+	AnalyticsID       string   // Plausible site domain or Google measurement ID
+	CustomFields      []string // Custom frontmatter fields listed on element pages, in order
+	BasePath          string   // URL path the site is served under, e.g. "/architecture/"; empty for the root
+	PrettyURLs        bool     // Write pages as <name>/index.html and link them as <name>/
+
+	// Page layouts: element type, "external" or "tag:<name>" -> template file
+	// of the matching element pages, relative to the project root
+	PageLayouts map[string]string
+
+	// Issue tracker references from `issues:` frontmatter
+	IssueURLTemplate string // Link for ticket IDs; "{id}" is replaced by the ID
+	IssueTracker     string // "jira" enables ticket status checks; empty disables them
+	IssueTrackerURL  string // Tracker base URL; defaults to the host of IssueURLTemplate
+
+	// Encryption at rest
+	RecipientsFile string // age/SSH public keys for `loko encrypt`; Default: ".loko/recipients.txt"
+
+	// Redaction applied to published output built with --redact
+	RedactTags     []string // Elements with any of these tags are removed
+	RedactFields   []string // Element fields emptied, e.g. "technology", "database"
+	RedactPatterns []string // Regular expressions masked in all text, e.g. internal host names
+	RedactMask     string   // Replacement for masked text; Default: "[redacted]"
+
+	// Permissions for MCP sessions
+	MCPRole       string   // "reader" or "editor"; Default: editor
+	MCPAllowTools []string // Glob patterns of tools agents may use; empty allows all
+	MCPDenyTools  []string // Glob patterns of tools agents may not use, e.g. "delete_*"
+
+	// Git integration
+	GitAutoCommit bool // Commit the files changed by each successful MCP tool call or API write; Default: false
+
+	// Local usage metrics, never transmitted
+	RecordUsage bool // Record the commands run and their durations in .loko/usage.log; Default: false
+
+	// Technology icons shown in generated diagrams and pages
+	TechnologyIcons map[string]string // Technology name -> icon URL; overrides the defaults, "" removes one
+
+	// Custom relationship kinds: name -> "sources -> targets", e.g. "component -> event"
+	RelationshipKinds map[string]string
+
+	// Deployment mapping of containers through deployed-on:<target> tags
+	RequireDeployment bool     // Validation reports containers of internal systems without a target
+	DeploymentTargets []string // Known targets; when set, other targets are errors
+
+	// Cost reporting of the monthly_cost fields and cost: tags of containers
+	CostCurrency string // Currency code of cost amounts; Default: "USD"
+
+	// Review workflow of the review_status frontmatter of elements
+	RequireApproval bool // Validation reports elements that are not approved
+
+	// Container archetypes used by loko new container --archetype
+	Archetypes map[string]ContainerArchetype // Archetype name -> defaults of its containers
+
+	// Naming conventions checked by loko lint and validation
+	NamingIDCase         string            // "kebab" requires kebab-case directory names; empty disables the check
+	NamingMaxNameLength  int               // Longest allowed element name; 0 disables the check
+	NamingTechSeparator  string            // Separator of technology lists, e.g. ","; empty disables the check
+	NamingDomainPrefixes map[string]string // Domain -> prefix required of the IDs of its systems
+
+	// Quality rules of relationship descriptions checked by validation: the
+	// severity, "error", "warning" or "off", of each rule; empty is off
+	RelDescriptionMissing     string // Relationships without a description
+	RelDescriptionVerbFirst   string // Descriptions not starting with a verb
+	RelDescriptionPlaceholder string // Placeholder descriptions such as "TODO" or "talks to"
+	RelDescriptionTooLong     string // Descriptions longer than RelDescriptionMaxLength
+	RelDescriptionMaxLength   int    // Longest allowed description; 0 uses the default of 80
+
+	// Notifications of architecture changes to the owners of changed elements
+	NotifyWebhookURL string // Receives one JSON payload per owner; empty disables webhooks
+	NotifySMTPHost   string // "host:port" of the mail server emailing owners that are addresses; empty disables email
+	NotifyEmailFrom  string // Sender address of notification emails
+
+	// Plugins
+	PluginRenderer string // Renderer plugin used in place of the d2 CLI; empty uses d2
+
+	// Hooks: shell commands run from the project root around builds and validation
+	PreBuildHooks    []string // Run before building; a failure aborts the build
+	PostBuildHooks   []string // Run after a successful build
+	PreValidateHooks []string // Run before validating; a failure aborts validation
 
 	// Server configuration
 	ServePort int  // Default: 8080
@@ -65,12 +169,15 @@ type ProjectConfig struct {
 // DefaultProjectConfig returns the default configuration.
 func DefaultProjectConfig() *ProjectConfig {
 	return &ProjectConfig{
+		SchemaVersion:   CurrentSchemaVersion,
 		SourceDir:       "./src",
 		OutputDir:       "./dist",
 		Template:        "standard-3layer",
 		D2Theme:         "neutral-default",
 		D2Layout:        "elk",
 		D2Cache:         true,
+		DiagramLegend:   true,
+		DiagramFooter:   true,
 		HTMLEnabled:     true,
 		MarkdownEnabled: false,
 		PDFEnabled:      false,
@@ -114,6 +221,12 @@ func (p *Project) Validate() error {
 		}
 	}
 
+	for _, person := range p.People {
+		if err := person.Validate(); err != nil {
+			errs.Add("Project", "Person", person.ID, "invalid person", err)
+		}
+	}
+
 	if errs.HasErrors() {
 		return errs
 	}
@@ -154,15 +267,62 @@ func (p *Project) RemoveSystem(id string) error {
 	return nil
 }
 
-// ListSystems returns all systems.
+// ListSystems returns all systems, sorted by ID so that output generated
+// from them is stable between runs.
 func (p *Project) ListSystems() []*System {
 	result := make([]*System, 0, len(p.Systems))
-	for _, sys := range p.Systems {
-		result = append(result, sys)
+	for _, id := range slices.Sorted(maps.Keys(p.Systems)) {
+		result = append(result, p.Systems[id])
+	}
+	return result
+}
+
+// AddPerson adds a person to this project.
+func (p *Project) AddPerson(person *Person) error {
+	if person == nil {
+		return NewValidationError("Project", "Person", "", "person cannot be nil", nil)
+	}
+
+	if _, exists := p.People[person.ID]; exists {
+		return &DuplicateError{Entity: "Person", ID: person.ID, Parent: p.Name}
+	}
+
+	if p.People == nil {
+		p.People = make(map[string]*Person)
+	}
+	p.People[person.ID] = person
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// ListPeople returns all people, sorted by ID.
+func (p *Project) ListPeople() []*Person {
+	result := make([]*Person, 0, len(p.People))
+	for _, id := range slices.Sorted(maps.Keys(p.People)) {
+		result = append(result, p.People[id])
 	}
 	return result
 }
 
+// SourceDir returns the source directory of the project, relative to its
+// root: [paths] source, or "src" without a configuration.
+func (p *Project) SourceDir() string {
+	if p.Config != nil && p.Config.SourceDir != "" {
+		return p.Config.SourceDir
+	}
+	return "src"
+}
+
+// SourceDirs returns the source directories of the project: SourceDir first,
+// then those of [paths] source_dirs as configured.
+func (p *Project) SourceDirs() []string {
+	dirs := []string{p.SourceDir()}
+	if p.Config != nil {
+		dirs = append(dirs, p.Config.SourceDirs...)
+	}
+	return dirs
+}
+
 // SystemCount returns the number of systems.
 func (p *Project) SystemCount() int {
 	return len(p.Systems)
@@ -0,0 +1,194 @@
+package entities
+
+import (
+	"slices"
+)
+
+// ProjectPreset is a starter architecture scaffolded by `loko init --template`.
+// It describes example systems, containers, components and relationships so a
+// new project has a working model to build and explore immediately.
+type ProjectPreset struct {
+	Name        string
+	Description string
+	Systems     []PresetSystem
+}
+
+// PresetSystem is a system scaffolded by a preset.
+type PresetSystem struct {
+	Name          string
+	Description   string
+	Tags          []string
+	Containers    []PresetContainer
+	Relationships []PresetRelationship
+}
+
+// PresetContainer is a container scaffolded by a preset.
+type PresetContainer struct {
+	Name        string
+	Description string
+	Technology  string
+	Components  []PresetComponent
+}
+
+// PresetComponent is a component scaffolded by a preset.
+type PresetComponent struct {
+	Name        string
+	Description string
+	Technology  string
+}
+
+// PresetRelationship connects two elements of the same system. Source and
+// Target are element names relative to the system: "container" or
+// "container/component".
+type PresetRelationship struct {
+	Source     string
+	Target     string
+	Label      string
+	Type       string
+	Technology string
+}
+
+// FindPreset returns the built-in preset with the given name.
+func FindPreset(name string) (*ProjectPreset, error) {
+	for _, preset := range builtinPresets() {
+		if preset.Name == NormalizeName(name) {
+			return &preset, nil
+		}
+	}
+	return nil, ErrUnknownPreset
+}
+
+// PresetNames returns the names of all built-in presets in sorted order.
+func PresetNames() []string {
+	var names []string
+	for _, preset := range builtinPresets() {
+		names = append(names, preset.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// builtinPresets returns the presets shipped with loko.
+func builtinPresets() []ProjectPreset {
+	return []ProjectPreset{
+		{
+			Name:        "minimal",
+			Description: "A single system with one container",
+			Systems: []PresetSystem{
+				{
+					Name:        "My System",
+					Description: "Replace this with a description of your system",
+					Containers: []PresetContainer{
+						{Name: "Application", Description: "The main application", Technology: "Go"},
+					},
+				},
+			},
+		},
+		{
+			Name:        "monolith",
+			Description: "A layered web application backed by a relational database",
+			Systems: []PresetSystem{
+				{
+					Name:        "Web Shop",
+					Description: "Online store serving customers through a single deployable application",
+					Tags:        []string{"monolith"},
+					Containers: []PresetContainer{
+						{
+							Name:        "Web App",
+							Description: "Server-rendered web application",
+							Technology:  "Ruby on Rails",
+							Components: []PresetComponent{
+								{Name: "Controllers", Description: "Handle HTTP requests", Technology: "Rails Controllers"},
+								{Name: "Services", Description: "Business logic", Technology: "Ruby"},
+								{Name: "Repositories", Description: "Data access layer", Technology: "ActiveRecord"},
+							},
+						},
+						{Name: "Background Jobs", Description: "Asynchronous work such as emails and reports", Technology: "Sidekiq"},
+						{Name: "Database", Description: "Primary relational data store", Technology: "PostgreSQL"},
+						{Name: "Cache", Description: "Session and fragment cache", Technology: "Redis"},
+					},
+					Relationships: []PresetRelationship{
+						{Source: "web-app/controllers", Target: "web-app/services", Label: "Calls"},
+						{Source: "web-app/services", Target: "web-app/repositories", Label: "Reads and writes data"},
+						{Source: "web-app", Target: "database", Label: "Reads and writes", Technology: "SQL"},
+						{Source: "web-app", Target: "cache", Label: "Caches sessions", Technology: "RESP"},
+						{Source: "web-app", Target: "background-jobs", Label: "Enqueues jobs", Type: "async"},
+						{Source: "background-jobs", Target: "database", Label: "Reads and writes", Technology: "SQL"},
+					},
+				},
+			},
+		},
+		{
+			Name:        "microservices",
+			Description: "Independently deployable services communicating over HTTP and events",
+			Systems: []PresetSystem{
+				{
+					Name:        "Commerce Platform",
+					Description: "Order management built from independently deployable services",
+					Tags:        []string{"microservices"},
+					Containers: []PresetContainer{
+						{Name: "API Gateway", Description: "Routes and authenticates external requests", Technology: "Envoy"},
+						{
+							Name:        "Order Service",
+							Description: "Accepts and tracks customer orders",
+							Technology:  "Go",
+							Components: []PresetComponent{
+								{Name: "Order Handler", Description: "HTTP API for orders", Technology: "Go net/http"},
+								{Name: "Order Repository", Description: "Persists orders", Technology: "Go pgx"},
+								{Name: "Event Publisher", Description: "Publishes order events", Technology: "Kafka client"},
+							},
+						},
+						{Name: "Payment Service", Description: "Charges customers for orders", Technology: "Go"},
+						{Name: "Notification Service", Description: "Sends order confirmations", Technology: "Node.js"},
+						{Name: "Event Bus", Description: "Carries domain events between services", Technology: "Kafka"},
+						{Name: "Order Database", Description: "Stores orders", Technology: "PostgreSQL"},
+					},
+					Relationships: []PresetRelationship{
+						{Source: "api-gateway", Target: "order-service", Label: "Routes order requests", Technology: "HTTPS"},
+						{Source: "order-service", Target: "payment-service", Label: "Requests payment", Technology: "gRPC"},
+						{Source: "order-service", Target: "order-database", Label: "Stores orders", Technology: "SQL"},
+						{Source: "order-service", Target: "event-bus", Label: "Publishes OrderPlaced", Type: "event"},
+						{Source: "event-bus", Target: "notification-service", Label: "Delivers OrderPlaced", Type: "event"},
+						{Source: "order-service/order-handler", Target: "order-service/order-repository", Label: "Saves orders"},
+						{Source: "order-service/order-handler", Target: "order-service/event-publisher", Label: "Emits events"},
+					},
+				},
+			},
+		},
+		{
+			Name:        "data-platform",
+			Description: "Ingestion, processing and serving layers of an analytics platform",
+			Systems: []PresetSystem{
+				{
+					Name:        "Data Platform",
+					Description: "Collects, transforms and serves analytical data",
+					Tags:        []string{"data"},
+					Containers: []PresetContainer{
+						{Name: "Ingestion", Description: "Receives events from source systems", Technology: "Kafka Connect"},
+						{Name: "Raw Storage", Description: "Immutable landing zone for raw data", Technology: "Amazon S3"},
+						{
+							Name:        "Processing",
+							Description: "Batch and streaming transformations",
+							Technology:  "Apache Spark",
+							Components: []PresetComponent{
+								{Name: "Cleansing Job", Description: "Validates and deduplicates raw records", Technology: "PySpark"},
+								{Name: "Aggregation Job", Description: "Builds analytical aggregates", Technology: "PySpark"},
+							},
+						},
+						{Name: "Warehouse", Description: "Curated analytical tables", Technology: "Snowflake"},
+						{Name: "Orchestrator", Description: "Schedules pipelines", Technology: "Apache Airflow"},
+						{Name: "BI Dashboards", Description: "Reporting for business users", Technology: "Metabase"},
+					},
+					Relationships: []PresetRelationship{
+						{Source: "ingestion", Target: "raw-storage", Label: "Lands raw events", Type: "async"},
+						{Source: "processing", Target: "raw-storage", Label: "Reads raw data"},
+						{Source: "processing", Target: "warehouse", Label: "Loads curated tables", Technology: "JDBC"},
+						{Source: "orchestrator", Target: "processing", Label: "Triggers jobs"},
+						{Source: "bi-dashboards", Target: "warehouse", Label: "Queries", Technology: "SQL"},
+						{Source: "processing/cleansing-job", Target: "processing/aggregation-job", Label: "Feeds cleansed data"},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestFindPreset(t *testing.T) {
+	preset, err := FindPreset("Microservices")
+	if err != nil {
+		t.Fatalf("FindPreset() error = %v", err)
+	}
+	if preset.Name != "microservices" {
+		t.Errorf("Name = %q, want microservices", preset.Name)
+	}
+
+	if _, err := FindPreset("nope"); !errors.Is(err, ErrUnknownPreset) {
+		t.Errorf("FindPreset(nope) error = %v, want ErrUnknownPreset", err)
+	}
+}
+
+func TestPresetNames(t *testing.T) {
+	want := []string{"data-platform", "microservices", "minimal", "monolith"}
+	if got := PresetNames(); !slices.Equal(got, want) {
+		t.Errorf("PresetNames() = %v, want %v", got, want)
+	}
+}
+
+// TestPresetRelationshipsReferenceElements ensures every preset relationship
+// points at a container or component defined by the same system.
+func TestPresetRelationshipsReferenceElements(t *testing.T) {
+	for _, preset := range builtinPresets() {
+		for _, sys := range preset.Systems {
+			elements := make(map[string]bool)
+			for _, cont := range sys.Containers {
+				contID := NormalizeName(cont.Name)
+				elements[contID] = true
+				for _, comp := range cont.Components {
+					elements[contID+"/"+NormalizeName(comp.Name)] = true
+				}
+			}
+			for _, rel := range sys.Relationships {
+				if !elements[rel.Source] || !elements[rel.Target] {
+					t.Errorf("%s: relationship %s -> %s references unknown element", preset.Name, rel.Source, rel.Target)
+				}
+			}
+		}
+	}
+}
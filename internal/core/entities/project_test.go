@@ -1,6 +1,7 @@
 package entities
 
 import (
+	"slices"
 	"testing"
 )
 
@@ -113,6 +114,9 @@ func TestProject_Systems(t *testing.T) {
 	if len(list) != 2 {
 		t.Errorf("ListSystems() returned %d, want 2", len(list))
 	}
+	if len(list) == 2 && list[0].ID > list[1].ID {
+		t.Errorf("ListSystems() = [%s %s], want sorted by ID", list[0].ID, list[1].ID)
+	}
 
 	// Remove
 	if err := proj.RemoveSystem("payment"); err != nil {
@@ -194,6 +198,37 @@ func TestProject_Counts(t *testing.T) {
 	}
 }
 
+func TestProject_SourceDirs(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *ProjectConfig
+		wantDir  string
+		wantDirs []string
+	}{
+		{"no config", nil, "src", []string{"src"}},
+		{"empty source", &ProjectConfig{}, "src", []string{"src"}},
+		{"source", &ProjectConfig{SourceDir: "./architecture"}, "./architecture", []string{"./architecture"}},
+		{
+			"source dirs",
+			&ProjectConfig{SourceDir: "./src", SourceDirs: []string{"../billing/src", "/work/shared"}},
+			"./src",
+			[]string{"./src", "../billing/src", "/work/shared"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proj := &Project{Config: tt.config}
+			if got := proj.SourceDir(); got != tt.wantDir {
+				t.Errorf("SourceDir() = %q, want %q", got, tt.wantDir)
+			}
+			if got := proj.SourceDirs(); !slices.Equal(got, tt.wantDirs) {
+				t.Errorf("SourceDirs() = %q, want %q", got, tt.wantDirs)
+			}
+		})
+	}
+}
+
 func TestProject_Setters(t *testing.T) {
 	proj, _ := NewProject("MyProject")
 	initialUpdate := proj.UpdatedAt
@@ -0,0 +1,92 @@
+package entities
+
+import (
+	"strconv"
+	"time"
+)
+
+// Identifiers used in the provenance statements loko writes next to built
+// documentation. The statement follows in-toto Statement v1 with a SLSA
+// Provenance v1 predicate; signed statements are wrapped in a DSSE envelope.
+const (
+	InTotoStatementType     = "https://in-toto.io/Statement/v1"
+	InTotoPayloadType       = "application/vnd.in-toto+json"
+	SLSAProvenancePredicate = "https://slsa.dev/provenance/v1"
+	LokoBuildType           = "https://github.com/madstone-tech/loko/build/v1"
+	LokoBuilderID           = "https://github.com/madstone-tech/loko"
+)
+
+// ProvenanceStatement attests that the subjects (the built files) were
+// produced by a loko build from the resolved dependencies (the model sources).
+type ProvenanceStatement struct {
+	Type          string               `json:"_type"`
+	Subject       []ResourceDescriptor `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     SLSAProvenance       `json:"predicate"`
+}
+
+// ResourceDescriptor identifies a file or revision by name and digest.
+type ResourceDescriptor struct {
+	Name        string            `json:"name,omitempty"`
+	URI         string            `json:"uri,omitempty"`
+	Digest      map[string]string `json:"digest"` // Algorithm to hex digest, e.g. "sha256" or "gitCommit"
+	Annotations map[string]any    `json:"annotations,omitempty"`
+}
+
+// SLSAProvenance is the SLSA Provenance v1 predicate.
+type SLSAProvenance struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+// BuildDefinition describes the inputs of a build.
+type BuildDefinition struct {
+	BuildType            string               `json:"buildType"`
+	ExternalParameters   map[string]any       `json:"externalParameters"`
+	ResolvedDependencies []ResourceDescriptor `json:"resolvedDependencies,omitempty"`
+}
+
+// RunDetails describes the tools that ran a build and when.
+type RunDetails struct {
+	Builder  ProvenanceBuilder `json:"builder"`
+	Metadata BuildMetadata     `json:"metadata"`
+}
+
+// ProvenanceBuilder identifies the build tool and the versions of its parts.
+type ProvenanceBuilder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version,omitempty"` // e.g. {"loko": "0.3.0", "d2": "v0.7.0"}
+}
+
+// BuildMetadata records when a build ran.
+type BuildMetadata struct {
+	StartedOn  time.Time `json:"startedOn"`
+	FinishedOn time.Time `json:"finishedOn"`
+}
+
+// DSSEEnvelope is a Dead Simple Signing Envelope around a signed statement.
+// Payload and signatures are base64-encoded in JSON.
+type DSSEEnvelope struct {
+	PayloadType string          `json:"payloadType"`
+	Payload     []byte          `json:"payload"`
+	Signatures  []DSSESignature `json:"signatures"`
+}
+
+// DSSESignature is one signature over an envelope's pre-authentication encoding.
+type DSSESignature struct {
+	KeyID string `json:"keyid,omitempty"`
+	Sig   []byte `json:"sig"`
+}
+
+// DSSEPreAuthEncoding returns the bytes DSSE signatures are computed over:
+// "DSSEv1 <len(type)> <type> <len(payload)> <payload>".
+func DSSEPreAuthEncoding(payloadType string, payload []byte) []byte {
+	pae := []byte("DSSEv1 ")
+	pae = strconv.AppendInt(pae, int64(len(payloadType)), 10)
+	pae = append(pae, ' ')
+	pae = append(pae, payloadType...)
+	pae = append(pae, ' ')
+	pae = strconv.AppendInt(pae, int64(len(payload)), 10)
+	pae = append(pae, ' ')
+	return append(pae, payload...)
+}
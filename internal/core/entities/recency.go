@@ -0,0 +1,61 @@
+package entities
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Element list orders accepted by SortSystems.
+const (
+	SortByName    = "name"
+	SortByUpdated = "updated" // Most recently changed first
+	SortByCreated = "created" // Most recently created first
+)
+
+// IsSortOrder reports whether order is one of the element list orders.
+func IsSortOrder(order string) bool {
+	return order == SortByName || order == SortByUpdated || order == SortByCreated
+}
+
+// SortSystems orders systems by name or by recency. Elements with equal or
+// unknown times keep name order, after those with a known time.
+func SortSystems(systems []*System, order string) {
+	sortElements(systems, order, func(s *System) (string, time.Time, time.Time) {
+		return s.Name, s.CreatedAt, s.UpdatedAt
+	})
+}
+
+// SortContainers orders containers like SortSystems.
+func SortContainers(containers []*Container, order string) {
+	sortElements(containers, order, func(c *Container) (string, time.Time, time.Time) {
+		return c.Name, c.CreatedAt, c.UpdatedAt
+	})
+}
+
+// SortComponents orders components like SortSystems.
+func SortComponents(components []*Component, order string) {
+	sortElements(components, order, func(c *Component) (string, time.Time, time.Time) {
+		return c.Name, c.CreatedAt, c.UpdatedAt
+	})
+}
+
+// sortElements sorts elements by the time selected by order, newest first,
+// then by name.
+func sortElements[T any](elements []T, order string, fields func(T) (name string, created, updated time.Time)) {
+	slices.SortStableFunc(elements, func(a, b T) int {
+		nameA, createdA, updatedA := fields(a)
+		nameB, createdB, updatedB := fields(b)
+		switch order {
+		case SortByUpdated:
+			if c := updatedB.Compare(updatedA); c != 0 {
+				return c
+			}
+		case SortByCreated:
+			if c := createdB.Compare(createdA); c != 0 {
+				return c
+			}
+		}
+		return strings.Compare(strings.ToLower(nameA), strings.ToLower(nameB))
+	})
+}
@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSortSystems(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	systems := []*System{
+		{Name: "billing", CreatedAt: day(1), UpdatedAt: day(5)},
+		{Name: "Auth", CreatedAt: day(3), UpdatedAt: day(4)},
+		{Name: "ledger"},
+		{Name: "catalog", CreatedAt: day(2), UpdatedAt: day(9)},
+	}
+	names := func() string {
+		var s string
+		for _, sys := range systems {
+			s += sys.Name + " "
+		}
+		return s
+	}
+
+	SortSystems(systems, SortByUpdated)
+	if got := names(); got != "catalog billing Auth ledger " {
+		t.Errorf("updated order = %q", got)
+	}
+	SortSystems(systems, SortByCreated)
+	if got := names(); got != "Auth catalog billing ledger " {
+		t.Errorf("created order = %q", got)
+	}
+	SortSystems(systems, SortByName)
+	if got := names(); got != "Auth billing catalog ledger " {
+		t.Errorf("name order = %q", got)
+	}
+}
@@ -35,6 +35,11 @@ type Relationship struct {
 
 	// Direction is one of: "forward", "bidirectional". Defaults to "forward".
 	Direction string `toml:"direction,omitempty"  json:"direction,omitempty"`
+
+	// Kind is the semantic relationship type, such as "uses", "reads" or
+	// "publishes" (see RelationshipKind). Optional; `loko validate` checks it
+	// against the kinds of the source and target elements.
+	Kind string `toml:"kind,omitempty"       json:"kind,omitempty"`
 }
 
 // RelationshipsFile is the top-level TOML structure for relationships.toml.
@@ -69,6 +74,13 @@ func WithRelDirection(dir string) RelationshipOption {
 	}
 }
 
+// WithRelKind sets the semantic relationship kind, e.g. "publishes".
+func WithRelKind(kind string) RelationshipOption {
+	return func(r *Relationship) {
+		r.Kind = strings.ToLower(strings.TrimSpace(kind))
+	}
+}
+
 // validRelTypes is the set of valid relationship Type values.
 var validRelTypes = map[string]bool{
 	"sync":  true,
@@ -180,6 +192,16 @@ func GenerateRelationshipID(source, target, label string) string {
 	return fmt.Sprintf("%x", sum[:4]) // 4 bytes → 8 hex chars
 }
 
+// EdgeLabel returns the label shown on the relationship's diagram edge. As
+// in C4 diagrams, the technology follows the label in brackets on its own
+// line, e.g. "Reads orders\n[HTTPS/JSON]".
+func (r Relationship) EdgeLabel() string {
+	if strings.TrimSpace(r.Technology) == "" {
+		return r.Label
+	}
+	return r.Label + "\n[" + r.Technology + "]"
+}
+
 // RelationshipToD2Edge converts a Relationship to a D2 edge declaration string.
 //
 // D2 syntax rules applied:
@@ -188,6 +210,7 @@ func GenerateRelationshipID(source, target, label string) string {
 //   - Async type: adds style.animated: true
 //   - Event type: adds style.stroke-dash: 5
 //   - Sync (default): plain quoted label
+//   - Technology (if set): second label line, e.g. "[gRPC]"
 //
 // The function uses only the last path segment of Source/Target as D2 node references
 // (i.e., "backend/api-lambda" → "api-lambda") to match the node IDs used in D2 files.
@@ -199,14 +222,15 @@ func RelationshipToD2Edge(rel Relationship) string {
 
 	src := lastPathSegment(rel.Source)
 	tgt := lastPathSegment(rel.Target)
+	label := rel.EdgeLabel()
 
 	switch rel.Type {
 	case "async":
-		return fmt.Sprintf("%s %s %s: { label: %q; style.animated: true }\n", src, arrow, tgt, rel.Label)
+		return fmt.Sprintf("%s %s %s: { label: %q; style.animated: true }\n", src, arrow, tgt, label)
 	case "event":
-		return fmt.Sprintf("%s %s %s: { label: %q; style.stroke-dash: 5 }\n", src, arrow, tgt, rel.Label)
+		return fmt.Sprintf("%s %s %s: { label: %q; style.stroke-dash: 5 }\n", src, arrow, tgt, label)
 	default: // "sync" or empty
-		return fmt.Sprintf("%s %s %s: %q\n", src, arrow, tgt, rel.Label)
+		return fmt.Sprintf("%s %s %s: %q\n", src, arrow, tgt, label)
 	}
 }
 
@@ -0,0 +1,129 @@
+package entities
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Element kinds a relationship kind may connect. An element can be of
+// several kinds: a component tagged "event" is both a component and an event.
+const (
+	ElementSystem    = "system"
+	ElementContainer = "container"
+	ElementComponent = "component"
+	ElementEvent     = "event" // A container or component tagged "event", such as a topic or queue
+)
+
+// elementKinds lists the valid element kinds.
+var elementKinds = []string{ElementSystem, ElementContainer, ElementComponent, ElementEvent}
+
+// RelationshipKind is a semantic relationship type such as "reads" or
+// "publishes", restricting the kinds of element it may connect. The
+// relationship reads "source <kind> target", e.g. "billing publishes
+// invoice-created".
+type RelationshipKind struct {
+	Name    string
+	Sources []string // Element kinds allowed as source; empty allows any
+	Targets []string // Element kinds allowed as target; empty allows any
+}
+
+// defaultRelationshipKinds are the built-in relationship kinds.
+var defaultRelationshipKinds = []RelationshipKind{
+	{Name: "uses"},
+	{Name: "reads", Targets: []string{ElementContainer, ElementComponent}},
+	{Name: "writes", Targets: []string{ElementContainer, ElementComponent}},
+	{Name: "publishes", Sources: []string{ElementComponent}, Targets: []string{ElementEvent}},
+	{Name: "subscribes", Sources: []string{ElementComponent}, Targets: []string{ElementEvent}},
+	{Name: "implements", Sources: []string{ElementComponent}, Targets: []string{ElementContainer, ElementComponent}},
+}
+
+// ParseRelationshipKind parses a custom kind definition of the form
+// "sources -> targets", where each side is a comma-separated list of element
+// kinds or "*" for any, e.g. "component -> container, component".
+func ParseRelationshipKind(name, definition string) (RelationshipKind, error) {
+	kind := RelationshipKind{Name: strings.ToLower(strings.TrimSpace(name))}
+	if kind.Name == "" {
+		return kind, fmt.Errorf("relationship kind name cannot be empty")
+	}
+	sources, targets, ok := strings.Cut(definition, "->")
+	if !ok {
+		return kind, fmt.Errorf("relationship kind %q: expected \"sources -> targets\", got %q", kind.Name, definition)
+	}
+	var err error
+	if kind.Sources, err = parseElementKinds(sources); err != nil {
+		return kind, fmt.Errorf("relationship kind %q: %w", kind.Name, err)
+	}
+	if kind.Targets, err = parseElementKinds(targets); err != nil {
+		return kind, fmt.Errorf("relationship kind %q: %w", kind.Name, err)
+	}
+	return kind, nil
+}
+
+// parseElementKinds parses a comma-separated list of element kinds. "*" or
+// an empty list allows any kind and returns nil.
+func parseElementKinds(list string) ([]string, error) {
+	var kinds []string
+	for _, field := range strings.Split(list, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		switch {
+		case field == "*":
+			return nil, nil
+		case field == "":
+			continue
+		case !slices.Contains(elementKinds, field):
+			return nil, fmt.Errorf("unknown element kind %q (expected %s or *)", field, strings.Join(elementKinds, ", "))
+		}
+		kinds = append(kinds, field)
+	}
+	return kinds, nil
+}
+
+// RelationshipKinds returns the built-in relationship kinds together with the
+// custom definitions from [relationship_types], keyed by name. A custom
+// definition replaces a built-in kind of the same name.
+func RelationshipKinds(custom map[string]string) (map[string]RelationshipKind, error) {
+	kinds := make(map[string]RelationshipKind, len(defaultRelationshipKinds)+len(custom))
+	for _, kind := range defaultRelationshipKinds {
+		kinds[kind.Name] = kind
+	}
+	for _, name := range slices.Sorted(maps.Keys(custom)) {
+		kind, err := ParseRelationshipKind(name, custom[name])
+		if err != nil {
+			return nil, err
+		}
+		kinds[kind.Name] = kind
+	}
+	return kinds, nil
+}
+
+// Allows reports whether an element of the source kinds may relate to an
+// element of the target kinds.
+func (k RelationshipKind) Allows(source, target []string) bool {
+	return kindAllowed(k.Sources, source) && kindAllowed(k.Targets, target)
+}
+
+// String returns the definition in the form accepted by ParseRelationshipKind.
+func (k RelationshipKind) String() string {
+	side := func(kinds []string) string {
+		if len(kinds) == 0 {
+			return "*"
+		}
+		return strings.Join(kinds, ", ")
+	}
+	return side(k.Sources) + " -> " + side(k.Targets)
+}
+
+// kindAllowed reports whether any of an element's kinds is allowed.
+func kindAllowed(allowed, kinds []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, kind := range kinds {
+		if slices.Contains(allowed, kind) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseRelationshipKind(t *testing.T) {
+	kind, err := ParseRelationshipKind(" Deploys ", "container -> system, Container")
+	if err != nil {
+		t.Fatalf("ParseRelationshipKind failed: %v", err)
+	}
+	if kind.Name != "deploys" || !slices.Equal(kind.Sources, []string{"container"}) ||
+		!slices.Equal(kind.Targets, []string{"system", "container"}) {
+		t.Errorf("ParseRelationshipKind = %+v", kind)
+	}
+	if got := kind.String(); got != "container -> system, container" {
+		t.Errorf("String() = %q", got)
+	}
+
+	wildcard, err := ParseRelationshipKind("triggers", "component -> *")
+	if err != nil || wildcard.Targets != nil || wildcard.String() != "component -> *" {
+		t.Errorf("ParseRelationshipKind(*) = %+v, %v", wildcard, err)
+	}
+
+	for _, definition := range []string{"component", "component -> queue", "person -> *"} {
+		if _, err := ParseRelationshipKind("bad", definition); err == nil {
+			t.Errorf("expected error for %q", definition)
+		}
+	}
+	if _, err := ParseRelationshipKind(" ", "* -> *"); err == nil {
+		t.Error("expected error for empty name")
+	}
+}
+
+func TestRelationshipKinds(t *testing.T) {
+	kinds, err := RelationshipKinds(map[string]string{"reads": "* -> *", "deploys": "container -> system"})
+	if err != nil {
+		t.Fatalf("RelationshipKinds failed: %v", err)
+	}
+	for _, name := range []string{"uses", "reads", "writes", "publishes", "subscribes", "implements", "deploys"} {
+		if _, ok := kinds[name]; !ok {
+			t.Errorf("missing kind %q", name)
+		}
+	}
+	if !kinds["reads"].Allows([]string{ElementSystem}, []string{ElementSystem}) {
+		t.Error("custom definition did not replace the built-in reads")
+	}
+
+	if _, err := RelationshipKinds(map[string]string{"deploys": "container"}); err == nil {
+		t.Error("expected error for invalid custom definition")
+	}
+}
+
+func TestRelationshipKindAllows(t *testing.T) {
+	kinds, _ := RelationshipKinds(nil)
+	component := []string{ElementComponent}
+	event := []string{ElementComponent, ElementEvent}
+
+	tests := []struct {
+		kind           string
+		source, target []string
+		want           bool
+	}{
+		{"uses", []string{ElementSystem}, []string{ElementSystem}, true},
+		{"publishes", component, event, true},
+		{"publishes", event, component, false},
+		{"subscribes", component, component, false},
+		{"reads", component, []string{ElementContainer}, true},
+		{"reads", component, []string{ElementSystem}, false},
+		{"implements", []string{ElementContainer}, component, false},
+	}
+	for _, tt := range tests {
+		if got := kinds[tt.kind].Allows(tt.source, tt.target); got != tt.want {
+			t.Errorf("%s.Allows(%v, %v) = %v, want %v", tt.kind, tt.source, tt.target, got, tt.want)
+		}
+	}
+}
@@ -235,6 +235,17 @@ func TestRelationshipToD2Edge(t *testing.T) {
 			},
 			wantParts: []string{"api", "->", "worker", "style.animated: true", `"Dispatch"`},
 		},
+		{
+			name: "technology — second label line",
+			rel: Relationship{
+				Source:     "system/web",
+				Target:     "system/api",
+				Label:      "Reads orders",
+				Type:       "async",
+				Technology: "HTTPS/JSON",
+			},
+			wantParts: []string{"web", "->", "api", `label: "Reads orders\n[HTTPS/JSON]"`},
+		},
 		{
 			name: "event forward — stroke-dash",
 			rel: Relationship{
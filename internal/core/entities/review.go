@@ -0,0 +1,63 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReviewStatus is the approval status of an element in the review workflow.
+type ReviewStatus string
+
+// Review statuses, in workflow order.
+const (
+	ReviewDraft    ReviewStatus = "draft"
+	ReviewInReview ReviewStatus = "in-review"
+	ReviewApproved ReviewStatus = "approved"
+)
+
+// Frontmatter keys of the review workflow.
+const (
+	ReviewStatusField = "review_status"
+	ReviewedByField   = "reviewed_by"
+	ReviewedAtField   = "reviewed_at"
+)
+
+// ReviewDateLayout is the format of reviewed_at in frontmatter. Timestamps in
+// RFC 3339 format are accepted too.
+const ReviewDateLayout = "2006-01-02"
+
+// Review is the review state of an element, from its review_status,
+// reviewed_by and reviewed_at frontmatter.
+type Review struct {
+	Status     ReviewStatus `json:"status,omitempty" toon:"status,omitempty"`
+	ReviewedBy string       `json:"reviewed_by,omitempty" toon:"reviewed_by,omitempty"`
+	ReviewedAt time.Time    `json:"reviewed_at,omitzero" toon:"reviewed_at,omitempty"`
+}
+
+// ParseReviewStatus parses a review_status value.
+func ParseReviewStatus(value string) (ReviewStatus, error) {
+	switch status := ReviewStatus(strings.ToLower(strings.TrimSpace(value))); status {
+	case ReviewDraft, ReviewInReview, ReviewApproved:
+		return status, nil
+	}
+	return "", fmt.Errorf("invalid review status %q (expected %s, %s or %s)", value, ReviewDraft, ReviewInReview, ReviewApproved)
+}
+
+// ParseReviewDate parses a reviewed_at value, a date or an RFC 3339 timestamp.
+func ParseReviewDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(ReviewDateLayout, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid review date %q (expected YYYY-MM-DD)", value)
+	}
+	return t, nil
+}
+
+// IsApproved reports whether the review status is approved.
+func (r Review) IsApproved() bool {
+	return r.Status == ReviewApproved
+}
@@ -0,0 +1,10 @@
+package entities
+
+// CurrentSchemaVersion is the version of the project layout and frontmatter
+// formats this build of loko reads and writes, recorded as schema_version in
+// loko.toml. `loko migrate` upgrades projects of older versions.
+const CurrentSchemaVersion = 3
+
+// LegacySchemaVersion is the version of projects whose loko.toml predates
+// schema_version.
+const LegacySchemaVersion = 1
@@ -1,5 +1,7 @@
 package entities
 
+import "strings"
+
 // SearchElementsRequest represents a request to search architecture elements.
 // Used by the search_elements MCP tool to filter and query elements.
 type SearchElementsRequest struct {
@@ -8,6 +10,8 @@ type SearchElementsRequest struct {
 
 	// Query is the search pattern (supports glob wildcards: *, ?).
 	// Examples: "payment*", "api-*", "*-service"
+	// Terms of the form "meta.key=value" are moved to Metadata, e.g.
+	// "payment* meta.compliance=pci"; a query of only such terms matches any name.
 	Query string
 
 	// Type filters by element type (system, container, component).
@@ -22,6 +26,10 @@ type SearchElementsRequest struct {
 	// Empty string means no tag filter.
 	Tag string
 
+	// Metadata filters by custom frontmatter fields: each key must have the
+	// value (case-insensitive), or contain it when the field is a list.
+	Metadata map[string]string
+
 	// Limit sets the maximum number of results to return.
 	// Default: 20, Maximum: 100
 	Limit int
@@ -61,6 +69,9 @@ type SearchElement struct {
 	// Tags are labels assigned to the element (e.g., ["critical", "production"]).
 	Tags []string
 
+	// Metadata holds the element's custom frontmatter fields.
+	Metadata map[string]any
+
 	// ParentID is the qualified ID of the parent element (if any).
 	// Components have a container parent, containers have a system parent.
 	ParentID string
@@ -72,6 +83,7 @@ func (r *SearchElementsRequest) Validate() error {
 		return NewValidationError("SearchElementsRequest", "project_root", "", "project root is required", nil)
 	}
 
+	r.extractMetadataFilters()
 	if r.Query == "" {
 		return NewValidationError("SearchElementsRequest", "query", "", "query pattern is required", nil)
 	}
@@ -99,6 +111,33 @@ func (r *SearchElementsRequest) Validate() error {
 	return nil
 }
 
+// extractMetadataFilters moves "meta.key=value" terms from the query to
+// Metadata.
+func (r *SearchElementsRequest) extractMetadataFilters() {
+	fields := strings.Fields(r.Query)
+	var pattern []string
+	found := false
+	for _, field := range fields {
+		key, value, ok := strings.Cut(strings.TrimPrefix(field, "meta."), "=")
+		if !strings.HasPrefix(field, "meta.") || !ok || key == "" {
+			pattern = append(pattern, field)
+			continue
+		}
+		if r.Metadata == nil {
+			r.Metadata = make(map[string]string)
+		}
+		r.Metadata[key] = value
+		found = true
+	}
+	if !found {
+		return
+	}
+	r.Query = strings.Join(pattern, " ")
+	if r.Query == "" {
+		r.Query = "*"
+	}
+}
+
 // FindRelationshipsRequest represents a request to find relationships between elements.
 // Used by the find_relationships MCP tool to query architecture graph edges.
 type FindRelationshipsRequest struct {
@@ -1,13 +1,28 @@
 package entities
 
-import "slices"
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ProjectPathField is the frontmatter field of an external system maintained
+// in another loko project: the path of that project's root, relative to the
+// root of the project referencing it.
+const ProjectPathField = "project_path"
 
 // System represents a C4 system - a high-level abstraction.
 // Examples: "Payment System", "Order Management System".
 type System struct {
-	// ID is the unique identifier (used in file paths)
+	// ID is the unique identifier (used in file paths), prefixed with the
+	// system's domain for namespaced systems, e.g. "payments.billing"
 	ID string `json:"id" toon:"id"`
 
+	// Domain is the dot-separated namespace of the system, e.g. "payments";
+	// empty for top-level systems
+	Domain string `json:"domain,omitempty" toon:"domain,omitempty"`
+
 	// Name is the display name
 	Name string `json:"name" toon:"name"`
 
@@ -17,6 +32,13 @@ type System struct {
 	// Tags for categorization and filtering
 	Tags []string `json:"tags" toon:"tags,omitempty"`
 
+	// Issues references tracker tickets by ID (e.g. "PAY-123") or URL
+	Issues []string `json:"issues,omitempty" toon:"issues,omitempty"`
+
+	// Exports lists the formats ("pdf", "markdown") in which the build also
+	// writes this system as a standalone document to exports/ in the output
+	Exports []string `json:"exports,omitempty" toon:"exports,omitempty"`
+
 	// Responsibilities lists key responsibilities of this system
 	Responsibilities []string `json:"responsibilities" toon:"responsibilities,omitempty"`
 
@@ -38,6 +60,9 @@ type System struct {
 	// ExternalSystems lists external systems this system integrates with
 	ExternalSystems []string `json:"external_systems" toon:"external_systems,omitempty"`
 
+	// Relationships to other systems (maps system ID to relationship description)
+	Relationships map[string]string `json:"relationships,omitempty" toon:"relationships,omitempty"`
+
 	// Containers within this system
 	Containers map[string]*Container `json:"containers" toon:"containers"`
 
@@ -53,6 +78,23 @@ type System struct {
 	// Path is the filesystem path to this system's directory
 	Path string `json:"path" toon:"path,omitempty"`
 
+	// ContentHash is the hash of system.md when the system was loaded or last
+	// saved. A save fails with a ConflictError if the file no longer has it.
+	ContentHash string `json:"content_hash,omitempty" toon:"content_hash,omitempty"`
+
+	// CreatedAt is when the element's sources were first committed; zero if unknown
+	CreatedAt time.Time `json:"created_at,omitzero" toon:"created_at,omitempty"`
+
+	// Review is the element's approval status in the review workflow
+	Review Review `json:"review,omitzero" toon:"review,omitempty"`
+
+	// UpdatedAt is when the element's sources last changed, from version
+	// control or else the file's modification time
+	UpdatedAt time.Time `json:"updated_at,omitzero" toon:"updated_at,omitempty"`
+
+	// Author is who last changed the element's sources, from version control
+	Author string `json:"author,omitempty" toon:"author,omitempty"`
+
 	// External indicates if this is an external system (not owned by us)
 	External bool `json:"external" toon:"external,omitempty"`
 }
@@ -84,8 +126,12 @@ func (s *System) Validate() error {
 		errs.Add("System", "Name", s.Name, "invalid name", err)
 	}
 
-	if err := ValidateID(s.ID); err != nil {
-		errs.Add("System", "ID", s.ID, "invalid id", err)
+	// Each domain of a namespaced ID is validated like the system's own ID
+	for _, segment := range strings.Split(s.ID, DomainSeparator) {
+		if err := ValidateID(segment); err != nil {
+			errs.Add("System", "ID", s.ID, "invalid id", err)
+			break
+		}
 	}
 
 	if s.Diagram != nil {
@@ -122,6 +168,21 @@ func (s *System) AddContainer(cont *Container) error {
 	return nil
 }
 
+// SetDomain moves the system into a domain, such as "payments" or
+// "payments.cards", prefixing its ID with the domain: the system "billing"
+// becomes "payments.billing". An empty domain makes it a top-level system.
+func (s *System) SetDomain(domain string) {
+	_, local := splitNamespace(s.ID)
+	s.Domain = domain
+	s.ID = local
+	if domain != "" {
+		s.ID = domain + DomainSeparator + local
+	}
+	for _, cont := range s.Containers {
+		cont.ParentID = s.ID
+	}
+}
+
 // GetContainer retrieves a container by ID.
 func (s *System) GetContainer(id string) (*Container, error) {
 	cont, exists := s.Containers[id]
@@ -140,11 +201,12 @@ func (s *System) RemoveContainer(id string) error {
 	return nil
 }
 
-// ListContainers returns all containers in order.
+// ListContainers returns all containers, sorted by ID so that output
+// generated from them is stable between runs.
 func (s *System) ListContainers() []*Container {
 	result := make([]*Container, 0, len(s.Containers))
-	for _, cont := range s.Containers {
-		result = append(result, cont)
+	for _, id := range slices.Sorted(maps.Keys(s.Containers)) {
+		result = append(result, s.Containers[id])
 	}
 	return result
 }
@@ -239,6 +301,22 @@ func (s *System) AddExternalSystem(sys string) {
 	}
 }
 
+// AddRelationship adds a relationship to another system.
+func (s *System) AddRelationship(targetSystemID, description string) {
+	if targetSystemID == "" {
+		return
+	}
+	if s.Relationships == nil {
+		s.Relationships = make(map[string]string)
+	}
+	s.Relationships[targetSystemID] = description
+}
+
+// RemoveRelationship removes a relationship to another system.
+func (s *System) RemoveRelationship(targetSystemID string) {
+	delete(s.Relationships, targetSystemID)
+}
+
 // GetID returns the system's unique identifier (implements C4Entity).
 func (s *System) GetID() string {
 	return s.ID
@@ -85,6 +85,9 @@ func TestSystem_Containers(t *testing.T) {
 	if len(list) != 2 {
 		t.Errorf("ListContainers() returned %d, want 2", len(list))
 	}
+	if len(list) == 2 && list[0].ID > list[1].ID {
+		t.Errorf("ListContainers() = [%s %s], want sorted by ID", list[0].ID, list[1].ID)
+	}
 
 	// Remove
 	if err := sys.RemoveContainer("api"); err != nil {
@@ -190,3 +193,18 @@ func TestSystem_Tags(t *testing.T) {
 		t.Error("HasTag(core) should return true")
 	}
 }
+
+func TestSystem_Relationships(t *testing.T) {
+	sys, _ := NewSystem("Checkout")
+	sys.AddRelationship("payments", "Charges orders")
+	sys.AddRelationship("", "ignored")
+
+	if got := sys.Relationships; len(got) != 1 || got["payments"] != "Charges orders" {
+		t.Errorf("Relationships = %v", got)
+	}
+
+	sys.RemoveRelationship("payments")
+	if len(sys.Relationships) != 0 {
+		t.Errorf("Relationships after remove = %v", sys.Relationships)
+	}
+}
@@ -0,0 +1,231 @@
+package entities
+
+import (
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// iconBadge is an icon of the built-in icon pack: the initials of a
+// technology on its brand color.
+type iconBadge struct {
+	label string
+	fill  string
+	text  string // Label color; white if empty
+}
+
+// iconPack is the built-in icon pack. Its icons are embedded in diagrams and
+// pages as data URIs, so generated sites need no network access to show them.
+var iconPack = map[string]iconBadge{
+	"go":            {label: "Go", fill: "#00ADD8"},
+	"python":        {label: "Py", fill: "#3776AB"},
+	"java":          {label: "J", fill: "#E76F00"},
+	"kotlin":        {label: "Kt", fill: "#7F52FF"},
+	"scala":         {label: "Sc", fill: "#DC322F"},
+	"javascript":    {label: "JS", fill: "#F7DF1E", text: "#000000"},
+	"typescript":    {label: "TS", fill: "#3178C6"},
+	"nodejs":        {label: "N", fill: "#5FA04E"},
+	"rust":          {label: "Rs", fill: "#CE422B"},
+	"ruby":          {label: "Rb", fill: "#CC342D"},
+	"rails":         {label: "Ra", fill: "#D30001"},
+	"php":           {label: "PHP", fill: "#777BB4"},
+	"csharp":        {label: "C#", fill: "#512BD4"},
+	"dotnet":        {label: "NET", fill: "#512BD4"},
+	"elixir":        {label: "Ex", fill: "#4B275F"},
+	"swift":         {label: "Sw", fill: "#F05138"},
+	"react":         {label: "Re", fill: "#61DAFB", text: "#000000"},
+	"vue":           {label: "V", fill: "#42B883"},
+	"angular":       {label: "A", fill: "#DD0031"},
+	"spring":        {label: "Sp", fill: "#6DB33F"},
+	"django":        {label: "Dj", fill: "#092E20"},
+	"flask":         {label: "Fl", fill: "#000000"},
+	"graphql":       {label: "GQL", fill: "#E10098"},
+	"postgresql":    {label: "PG", fill: "#4169E1"},
+	"mysql":         {label: "My", fill: "#4479A1"},
+	"mariadb":       {label: "Ma", fill: "#003545"},
+	"sqlite":        {label: "SQ", fill: "#003B57"},
+	"mongodb":       {label: "Mg", fill: "#47A248"},
+	"redis":         {label: "Rd", fill: "#DC382D"},
+	"cassandra":     {label: "Ca", fill: "#1287B1"},
+	"dynamodb":      {label: "DDB", fill: "#4053D6"},
+	"elasticsearch": {label: "ES", fill: "#005571"},
+	"kafka":         {label: "K", fill: "#231F20"},
+	"rabbitmq":      {label: "RMQ", fill: "#FF6600"},
+	"nginx":         {label: "NX", fill: "#009639"},
+	"docker":        {label: "Dk", fill: "#2496ED"},
+	"kubernetes":    {label: "K8s", fill: "#326CE5"},
+	"terraform":     {label: "TF", fill: "#844FBA"},
+}
+
+// BuiltinIcon returns the data URI of the icon named name in the built-in
+// icon pack, e.g. "go" or "postgresql", or "" if there is none.
+func BuiltinIcon(name string) string {
+	svg := BuiltinIconSVG(name)
+	if svg == "" {
+		return ""
+	}
+	return IconDataURI([]byte(svg))
+}
+
+// BuiltinIconSVG returns the SVG document of the icon named name in the
+// built-in icon pack, or "" if there is none.
+func BuiltinIconSVG(name string) string {
+	badge, ok := iconPack[name]
+	if !ok {
+		return ""
+	}
+	text, size := badge.text, 14
+	if text == "" {
+		text = "#FFFFFF"
+	}
+	if len(badge.label) > 2 {
+		size = 10
+	}
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="32" height="32" viewBox="0 0 32 32">`+
+		`<rect width="32" height="32" rx="6" fill="%s"/>`+
+		`<text x="16" y="16" dy=".35em" text-anchor="middle" font-family="system-ui,sans-serif" font-size="%d" font-weight="700" fill="%s">%s</text></svg>`,
+		badge.fill, size, text, badge.label)
+}
+
+// BuiltinIconNames returns the names of the icons of the built-in icon pack,
+// sorted.
+func BuiltinIconNames() []string {
+	return slices.Sorted(maps.Keys(iconPack))
+}
+
+// IconDataURI returns the data URI embedding an SVG icon.
+func IconDataURI(svg []byte) string {
+	return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString(svg)
+}
+
+// defaultIcons maps lowercase technology names to icons of the iconPack.
+var defaultIcons = map[string]string{
+	// Languages and runtimes
+	"go":         "go",
+	"golang":     "go",
+	"python":     "python",
+	"java":       "java",
+	"kotlin":     "kotlin",
+	"scala":      "scala",
+	"javascript": "javascript",
+	"js":         "javascript",
+	"typescript": "typescript",
+	"ts":         "typescript",
+	"node":       "nodejs",
+	"node.js":    "nodejs",
+	"nodejs":     "nodejs",
+	"rust":       "rust",
+	"ruby":       "ruby",
+	"rails":      "rails",
+	"php":        "php",
+	"c#":         "csharp",
+	"csharp":     "csharp",
+	".net":       "dotnet",
+	"dotnet":     "dotnet",
+	"elixir":     "elixir",
+	"swift":      "swift",
+
+	// Frameworks
+	"react":   "react",
+	"vue":     "vue",
+	"vue.js":  "vue",
+	"angular": "angular",
+	"spring":  "spring",
+	"django":  "django",
+	"flask":   "flask",
+	"graphql": "graphql",
+
+	// Data stores
+	"postgres":      "postgresql",
+	"postgresql":    "postgresql",
+	"mysql":         "mysql",
+	"mariadb":       "mariadb",
+	"sqlite":        "sqlite",
+	"mongo":         "mongodb",
+	"mongodb":       "mongodb",
+	"redis":         "redis",
+	"cassandra":     "cassandra",
+	"dynamodb":      "dynamodb",
+	"elasticsearch": "elasticsearch",
+
+	// Messaging and infrastructure
+	"kafka":      "kafka",
+	"rabbitmq":   "rabbitmq",
+	"nginx":      "nginx",
+	"docker":     "docker",
+	"kubernetes": "kubernetes",
+	"k8s":        "kubernetes",
+	"terraform":  "terraform",
+}
+
+// maxIconPhraseWords is the longest technology name, in words, the registry
+// matches (e.g. "spring boot" or "azure service bus").
+const maxIconPhraseWords = 3
+
+// IconRegistry maps container and component technologies, such as
+// "Go 1.22 / gRPC" or "PostgreSQL 15", to icon URLs for diagrams and pages.
+type IconRegistry struct {
+	icons map[string]string // Lowercase technology name -> icon URL
+}
+
+// NewIconRegistry creates a registry of the default icons for common
+// languages, frameworks, data stores and infrastructure, with overrides
+// (technology name -> icon URL) taking precedence. An empty URL removes the
+// default icon for that name.
+func NewIconRegistry(overrides map[string]string) *IconRegistry {
+	r := &IconRegistry{icons: make(map[string]string, len(defaultIcons)+len(overrides))}
+	for name, icon := range defaultIcons {
+		r.icons[name] = BuiltinIcon(icon)
+	}
+	for name, url := range overrides {
+		name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+		if url == "" {
+			delete(r.icons, name)
+		} else {
+			r.icons[name] = url
+		}
+	}
+	return r
+}
+
+// Icon returns the icon URL for technology, or "" if none matches. Names are
+// matched case-insensitively as whole words, ignoring version numbers; the
+// earliest name in technology wins, so "Go / PostgreSQL" gets the Go icon.
+func (r *IconRegistry) Icon(technology string) string {
+	if r == nil || technology == "" {
+		return ""
+	}
+	words := iconWords(technology)
+	for i := range words {
+		// Prefer the longest phrase starting at this word.
+		for n := min(maxIconPhraseWords, len(words)-i); n > 0; n-- {
+			if url, ok := r.icons[strings.Join(words[i:i+n], " ")]; ok {
+				return url
+			}
+		}
+		if trimmed := strings.TrimRight(words[i], "0123456789."); trimmed != words[i] && trimmed != "" {
+			if url, ok := r.icons[trimmed]; ok { // e.g. "python3"
+				return url
+			}
+		}
+	}
+	return ""
+}
+
+// iconWords splits a technology description into lowercase words, keeping
+// the characters of names such as "node.js", "c#" and "c++".
+func iconWords(technology string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(technology), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != '#' && r != '+'
+	})
+	words := fields[:0]
+	for _, field := range fields {
+		if field = strings.TrimRight(field, "."); field != "" && field != "+" {
+			words = append(words, field)
+		}
+	}
+	return words
+}
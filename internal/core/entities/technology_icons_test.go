@@ -0,0 +1,67 @@
+package entities
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestIconRegistryIcon(t *testing.T) {
+	r := NewIconRegistry(map[string]string{
+		"Spring Boot": "https://icons.example.com/spring-boot.svg",
+		"Redis":       "",
+		"grpc":        "https://icons.example.com/grpc.svg",
+	})
+
+	tests := []struct {
+		technology string
+		want       string
+	}{
+		{"Go", BuiltinIcon("go")},
+		{"Go 1.22 / PostgreSQL", BuiltinIcon("go")},
+		{"PostgreSQL 15", BuiltinIcon("postgresql")},
+		{"Apache Kafka", BuiltinIcon("kafka")},
+		{"Node.js + Express", BuiltinIcon("nodejs")},
+		{"C#", BuiltinIcon("csharp")},
+		{"Python3", BuiltinIcon("python")},
+		{"Java / Spring Boot", BuiltinIcon("java")},
+		{"spring  boot", "https://icons.example.com/spring-boot.svg"},
+		{"gRPC", "https://icons.example.com/grpc.svg"},
+		{"Redis", ""},
+		{"Gopher", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := r.Icon(tt.technology); got != tt.want {
+			t.Errorf("Icon(%q) = %q, want %q", tt.technology, got, tt.want)
+		}
+	}
+
+	var nilRegistry *IconRegistry
+	if got := nilRegistry.Icon("Go"); got != "" {
+		t.Errorf("nil registry Icon = %q, want empty", got)
+	}
+}
+
+func TestBuiltinIcon(t *testing.T) {
+	for name, icon := range defaultIcons {
+		if BuiltinIcon(icon) == "" {
+			t.Errorf("default icon %q for %q is not in the icon pack", icon, name)
+		}
+	}
+
+	uri, ok := strings.CutPrefix(BuiltinIcon("go"), "data:image/svg+xml;base64,")
+	if !ok {
+		t.Fatalf("BuiltinIcon(go) = %q, want an SVG data URI", BuiltinIcon("go"))
+	}
+	svg, err := base64.StdEncoding.DecodeString(uri)
+	if err != nil {
+		t.Fatalf("failed to decode icon: %v", err)
+	}
+	if !strings.HasPrefix(string(svg), "<svg ") || !strings.Contains(string(svg), ">Go</text>") {
+		t.Errorf("icon = %s", svg)
+	}
+	if got := BuiltinIcon("cobol"); got != "" {
+		t.Errorf("BuiltinIcon(cobol) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,88 @@
+package entities
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// TimelineEventKind describes what happened to an entity at a point in time.
+type TimelineEventKind string
+
+const (
+	// TimelineAdded marks the first appearance of an entity.
+	TimelineAdded TimelineEventKind = "added"
+	// TimelineDeprecated marks an entity as deprecated (tagged "deprecated").
+	TimelineDeprecated TimelineEventKind = "deprecated"
+	// TimelineRemoved marks the removal of an entity from the model.
+	TimelineRemoved TimelineEventKind = "removed"
+)
+
+// TimelineEvent records a single change in the evolution of the architecture.
+type TimelineEvent struct {
+	// Date is when the change was recorded (commit author date).
+	Date time.Time `json:"date"`
+
+	// Kind is one of added, deprecated, removed.
+	Kind TimelineEventKind `json:"kind"`
+
+	// EntityType is the C4 level: system, container, or component.
+	EntityType string `json:"entity_type"`
+
+	// EntityID is the qualified entity path (e.g., "backend/api/auth").
+	EntityID string `json:"entity_id"`
+
+	// Name is the display name if known, otherwise the last path segment.
+	Name string `json:"name"`
+
+	// Commit is the revision that introduced the change.
+	Commit string `json:"commit,omitempty"`
+
+	// Author is the author of the revision.
+	Author string `json:"author,omitempty"`
+
+	// Message is the revision summary line.
+	Message string `json:"message,omitempty"`
+}
+
+// Timeline is the chronological history of an architecture model.
+type Timeline struct {
+	// Events in chronological order (oldest first) once Sort has been called.
+	Events []TimelineEvent `json:"events"`
+}
+
+// NewTimeline creates an empty timeline.
+func NewTimeline() *Timeline {
+	return &Timeline{Events: []TimelineEvent{}}
+}
+
+// Add appends an event to the timeline.
+func (t *Timeline) Add(event TimelineEvent) {
+	t.Events = append(t.Events, event)
+}
+
+// Sort orders events chronologically, breaking ties by entity ID so output is stable.
+func (t *Timeline) Sort() {
+	slices.SortStableFunc(t.Events, func(a, b TimelineEvent) int {
+		if c := a.Date.Compare(b.Date); c != 0 {
+			return c
+		}
+		return strings.Compare(a.EntityID, b.EntityID)
+	})
+}
+
+// CountByKind returns the number of events of the given kind.
+func (t *Timeline) CountByKind(kind TimelineEventKind) int {
+	count := 0
+	for _, e := range t.Events {
+		if e.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// IsEmpty reports whether the timeline has no events.
+func (t *Timeline) IsEmpty() bool {
+	return t == nil || len(t.Events) == 0
+}
@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimelineSortAndCount(t *testing.T) {
+	timeline := NewTimeline()
+	if !timeline.IsEmpty() {
+		t.Error("expected new timeline to be empty")
+	}
+
+	jan := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	timeline.Add(TimelineEvent{Date: feb, Kind: TimelineRemoved, EntityID: "b"})
+	timeline.Add(TimelineEvent{Date: jan, Kind: TimelineAdded, EntityID: "b"})
+	timeline.Add(TimelineEvent{Date: jan, Kind: TimelineAdded, EntityID: "a"})
+	timeline.Sort()
+
+	got := []string{timeline.Events[0].EntityID, timeline.Events[1].EntityID, string(timeline.Events[2].Kind)}
+	want := []string{"a", "b", "removed"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("position %d: expected %s, got %s", i, want[i], got[i])
+		}
+	}
+
+	if n := timeline.CountByKind(TimelineAdded); n != 2 {
+		t.Errorf("expected 2 added events, got %d", n)
+	}
+	if n := timeline.CountByKind(TimelineDeprecated); n != 0 {
+		t.Errorf("expected 0 deprecated events, got %d", n)
+	}
+
+	var nilTimeline *Timeline
+	if !nilTimeline.IsEmpty() {
+		t.Error("expected nil timeline to be empty")
+	}
+}
@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+// TrashEntry records one deletion moved to the project's trash instead of
+// being removed permanently, so that it can be restored.
+type TrashEntry struct {
+	ID     string    `json:"id"`               // Directory name under .loko/trash/, derived from Time
+	Time   time.Time `json:"time"`             // When the paths were deleted
+	Paths  []string  `json:"paths"`            // Deleted files and directories, slash-separated and relative to the project root
+	Reason string    `json:"reason,omitempty"` // What deleted them, e.g. "merge payments.api.auth into payments.api.login"
+}
@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// UsageEvent records one run of a loko command in the opt-in local usage log:
+// which command ran, when, how long it took and whether it succeeded. Its
+// arguments are not recorded, and the log never leaves the project.
+type UsageEvent struct {
+	Time       time.Time `json:"time"`
+	Command    string    `json:"command"`     // Command path without "loko", e.g. "build" or "new system"
+	DurationMS int64     `json:"duration_ms"` // Wall time of the run
+	ExitCode   int       `json:"exit_code"`
+}
+
+// Duration returns the wall time of the run.
+func (e *UsageEvent) Duration() time.Duration {
+	return time.Duration(e.DurationMS) * time.Millisecond
+}
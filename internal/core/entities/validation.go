@@ -14,6 +14,10 @@ var (
 	// idPattern is stricter: lowercase alphanumeric and hyphens only
 	// Used for file/directory names
 	idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9\-]*$`)
+
+	// reservedNames are device names Windows does not allow as file or
+	// directory names, whatever their case or extension
+	reservedNames = regexp.MustCompile(`^(con|prn|aux|nul|com[0-9]|lpt[0-9])$`)
 )
 
 // ValidateName checks if a name is valid for display purposes.
@@ -25,6 +29,9 @@ func ValidateName(name string) error {
 	if !namePattern.MatchString(name) {
 		return ErrInvalidName
 	}
+	if reservedNames.MatchString(NormalizeName(name)) {
+		return ErrReservedName
+	}
 	return nil
 }
 
@@ -36,6 +43,9 @@ func ValidateID(id string) error {
 	if !idPattern.MatchString(id) {
 		return ErrInvalidName
 	}
+	if reservedNames.MatchString(id) {
+		return ErrReservedName
+	}
 	return nil
 }
 
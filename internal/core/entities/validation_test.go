@@ -21,6 +21,9 @@ func TestValidateName(t *testing.T) {
 		{"special chars", "Payment@Service", true},
 		{"starts with hyphen", "-payment", true},
 		{"starts with underscore", "_payment", true},
+		{"windows device name", "CON", true},
+		{"windows device name with space", "Com1 ", true},
+		{"windows device name prefix", "Console", false},
 	}
 
 	for _, tt := range tests {
@@ -48,6 +51,8 @@ func TestValidateID(t *testing.T) {
 		{"spaces", "payment service", true},
 		{"underscores", "payment_service", true},
 		{"starts with hyphen", "-payment", true},
+		{"windows device name", "nul", true},
+		{"windows device name prefix", "lpt10", false},
 	}
 
 	for _, tt := range tests {
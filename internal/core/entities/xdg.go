@@ -29,6 +29,16 @@ func (p XDGPaths) ThemesDir() string {
 	return filepath.Join(p.DataHome, "themes")
 }
 
+// PluginsDir returns the path to the plugins directory.
+func (p XDGPaths) PluginsDir() string {
+	return filepath.Join(p.DataHome, "plugins")
+}
+
+// IconsDir returns the path to the icon packs directory.
+func (p XDGPaths) IconsDir() string {
+	return filepath.Join(p.DataHome, "icons")
+}
+
 // CacheDir returns the cache directory path (same as CacheHome).
 func (p XDGPaths) CacheDir() string {
 	return p.CacheHome
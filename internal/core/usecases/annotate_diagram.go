@@ -0,0 +1,137 @@
+package usecases
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// diagramShapes names the D2 shapes listed in diagram legends, in legend order.
+var diagramShapes = []struct{ shape, label string }{
+	{"person", "Person"},
+	{"cylinder", "Database"},
+	{"queue", "Queue"},
+	{"stored_data", "Data store"},
+	{"page", "Document"},
+	{"cloud", "Cloud service"},
+}
+
+var (
+	// shapePattern matches a shape declaration, e.g. "shape: cylinder".
+	shapePattern = regexp.MustCompile(`\bshape\s*:\s*"?([a-z_]+)`)
+	// legendPattern matches a legend already declared at the top level.
+	legendPattern = regexp.MustCompile(`(?m)^(loko_)?legend\s*:`)
+	// quotedPattern matches quoted D2 strings, which may contain arrows.
+	quotedPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'[^']*'`)
+)
+
+// AnnotateDiagram appends a legend of the node and edge types a diagram uses
+// and a footer naming the project, the entity, the generation date and the
+// loko version to D2 source before it is rendered, so exported images remain
+// self-describing.
+type AnnotateDiagram struct {
+	projectName string
+	version     string
+	legend      bool
+	footer      bool
+	now         func() time.Time
+}
+
+// NewAnnotateDiagram creates an AnnotateDiagram use case. legend and footer
+// enable the two annotations.
+func NewAnnotateDiagram(projectName, version string, legend, footer bool) *AnnotateDiagram {
+	return &AnnotateDiagram{
+		projectName: projectName,
+		version:     version,
+		legend:      legend,
+		footer:      footer,
+		now:         time.Now,
+	}
+}
+
+// Execute returns source with the enabled annotations appended. entity
+// describes the diagram's subject, e.g. "container Payments/API". The legend
+// is skipped when the diagram has one or uses no shape or edge worth
+// explaining.
+func (uc *AnnotateDiagram) Execute(source, entity string) string {
+	var sb strings.Builder
+	sb.WriteString(source)
+	if !strings.HasSuffix(source, "\n") {
+		sb.WriteString("\n")
+	}
+
+	if uc.legend && !legendPattern.MatchString(source) {
+		if entries := legendEntries(source); len(entries) > 0 {
+			sb.WriteString("\n# Legend added by loko\n")
+			sb.WriteString("loko_legend: |md\n")
+			sb.WriteString("  **Legend**\n\n")
+			for _, entry := range entries {
+				sb.WriteString("  - " + entry + "\n")
+			}
+			sb.WriteString("|\n")
+			sb.WriteString("loko_legend.near: bottom-right\n")
+		}
+	}
+
+	if uc.footer {
+		parts := []string{uc.projectName, entity, "generated " + uc.now().Format(time.DateOnly)}
+		if uc.version != "" {
+			parts = append(parts, "loko "+uc.version)
+		}
+		parts = slices.DeleteFunc(parts, func(part string) bool { return part == "" })
+		sb.WriteString("\n# Footer added by loko\n")
+		sb.WriteString(fmt.Sprintf("loko_footer: %q {\n", strings.Join(parts, " · ")))
+		sb.WriteString("  shape: text\n")
+		sb.WriteString("  near: bottom-center\n")
+		sb.WriteString("  style.font-size: 12\n")
+		sb.WriteString("}\n")
+	}
+
+	return sb.String()
+}
+
+// legendEntries describes the notable shapes and the kinds of edges used in
+// source, in a fixed order.
+func legendEntries(source string) []string {
+	var entries []string
+	shapes := make(map[string]bool)
+	for _, match := range shapePattern.FindAllStringSubmatch(source, -1) {
+		shapes[match[1]] = true
+	}
+	for _, s := range diagramShapes {
+		if shapes[s.shape] {
+			entries = append(entries, s.label)
+		}
+	}
+
+	var directed, bidirectional, undirected bool
+	for _, line := range strings.Split(source, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = quotedPattern.ReplaceAllString(line, `""`)
+		if strings.Contains(line, "<->") {
+			bidirectional = true
+			line = strings.ReplaceAll(line, "<->", " ")
+		}
+		if strings.Contains(line, "->") || strings.Contains(line, "<-") {
+			directed = true
+		}
+		if strings.Contains(line, "--") {
+			undirected = true
+		}
+	}
+	if directed {
+		entries = append(entries, "A → B: A depends on B")
+	}
+	if bidirectional {
+		entries = append(entries, "A ↔ B: two-way interaction")
+	}
+	if undirected {
+		entries = append(entries, "A — B: association")
+	}
+	return entries
+}
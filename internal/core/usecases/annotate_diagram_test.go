@@ -0,0 +1,105 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestAnnotateDiagram(t *testing.T) {
+	source := `# api -> db is documented below
+user: "Customer" { shape: person }
+db: "Ledger" { shape: cylinder }
+api -> db: "Reads from"
+api <-> cache
+note: "a -- b"
+`
+	uc := NewAnnotateDiagram("Payments", "1.4.0", true, true)
+	uc.now = func() time.Time { return time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC) }
+
+	got := uc.Execute(source, "container Payments/API")
+	if !strings.HasPrefix(got, source) {
+		t.Fatal("Execute changed the original source")
+	}
+	for _, want := range []string{
+		"loko_legend: |md",
+		"  - Person\n  - Database\n  - A → B: A depends on B\n  - A ↔ B: two-way interaction\n|",
+		"loko_legend.near: bottom-right",
+		`loko_footer: "Payments · container Payments/API · generated 2026-03-14 · loko 1.4.0" {`,
+		"near: bottom-center",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Execute() missing %q:\n%s", want, got)
+		}
+	}
+	// Arrows in comments and strings are not edges.
+	if strings.Contains(got, "association") {
+		t.Errorf("Execute() listed an association from quoted text:\n%s", got)
+	}
+}
+
+func TestAnnotateDiagramToggles(t *testing.T) {
+	source := "a -> b\n"
+
+	if got := NewAnnotateDiagram("Payments", "dev", false, false).Execute(source, "system Payments"); got != source {
+		t.Errorf("Execute() with annotations disabled = %q, want source unchanged", got)
+	}
+
+	got := NewAnnotateDiagram("", "", false, true).Execute(source, "system Payments")
+	if strings.Contains(got, "loko_legend") {
+		t.Error("Execute() added a legend with legends disabled")
+	}
+	if !strings.Contains(got, `loko_footer: "system Payments · generated `) || strings.Contains(got, "· loko") {
+		t.Errorf("Execute() footer should omit an empty project and version:\n%s", got)
+	}
+
+	// Diagrams with their own legend, such as generated styled ones, keep it.
+	styled := "a -> b\nlegend: \"Legend\" {\n  near: bottom-right\n}\n"
+	if got := NewAnnotateDiagram("Payments", "dev", true, false).Execute(styled, "system Payments"); strings.Contains(got, "loko_legend") {
+		t.Errorf("Execute() added a second legend:\n%s", got)
+	}
+}
+
+// sourceRecorder is a DiagramRenderer that records the sources it renders.
+type sourceRecorder struct {
+	mu      sync.Mutex
+	sources []string
+}
+
+func (r *sourceRecorder) RenderDiagram(_ context.Context, source string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sources = append(r.sources, source)
+	return "<svg></svg>", nil
+}
+
+func (r *sourceRecorder) RenderDiagramWithTimeout(ctx context.Context, source string, _ int) (string, error) {
+	return r.RenderDiagram(ctx, source)
+}
+
+func (r *sourceRecorder) IsAvailable() bool { return true }
+
+func TestBuildDocsWithDiagramAnnotations(t *testing.T) {
+	system := &entities.System{ID: "payments", Name: "Payments", Diagram: &entities.Diagram{Source: "a -> b\n"}}
+	renderer := &sourceRecorder{}
+
+	uc := NewBuildDocs(renderer, &MockSiteBuilder{}, &MockProgressReporter{}).
+		WithDiagramAnnotations(NewAnnotateDiagram("Acme", "1.0.0", true, true))
+	if err := uc.renderDiagrams(context.Background(), []*entities.System{system}, t.TempDir()); err != nil {
+		t.Fatalf("renderDiagrams failed: %v", err)
+	}
+
+	if len(renderer.sources) != 1 {
+		t.Fatalf("rendered %d diagrams, want 1", len(renderer.sources))
+	}
+	if !strings.Contains(renderer.sources[0], `loko_footer: "Acme · system Payments · generated `) {
+		t.Errorf("rendered source missing footer:\n%s", renderer.sources[0])
+	}
+	if system.Diagram.Source != "a -> b\n" {
+		t.Error("annotations leaked into the model's diagram source")
+	}
+}
@@ -0,0 +1,287 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// PullRequestCommentMarker is embedded in the pull request comment so later CI
+// runs find and update it instead of posting a new one.
+const PullRequestCommentMarker = "<!-- loko:architecture-report -->"
+
+// maxCommentItems caps each list in the pull request comment to keep it
+// readable and within provider size limits.
+const maxCommentItems = 50
+
+// Violation is a validation error or warning attributed to one element.
+type Violation struct {
+	Severity string // "error" or "warning"
+	Code     string // Validation check, e.g. "dangling_reference"
+	Element  string // Qualified element ID; empty for project-wide issues
+}
+
+// PullRequestReport summarizes how a pull request changes the architecture.
+type PullRequestReport struct {
+	BaseRef string
+	Diff    *ArchitectureDiff
+
+	// NewViolations are errors and warnings present at head but not at base.
+	NewViolations []Violation
+	// Errors and Warnings count all violations at head, including existing ones.
+	Errors   int
+	Warnings int
+
+	// CommentURL is the URL of the posted comment, when one was posted.
+	CommentURL string
+}
+
+// AnnotatePullRequest compares the project at a base revision with the working
+// tree, validates both, and optionally posts the result as a pull request comment.
+type AnnotatePullRequest struct {
+	repo         ProjectRepository
+	revisions    RevisionExporter
+	commenter    PullRequestCommenter // Optional: when nil the report is only returned
+	graphBuilder *BuildArchitectureGraph
+}
+
+// NewAnnotatePullRequest creates a new AnnotatePullRequest use case.
+func NewAnnotatePullRequest(repo ProjectRepository, revisions RevisionExporter) *AnnotatePullRequest {
+	return &AnnotatePullRequest{
+		repo:         repo,
+		revisions:    revisions,
+		graphBuilder: NewBuildArchitectureGraph(),
+	}
+}
+
+// WithCommenter sets the provider used to post or update the comment.
+func (uc *AnnotatePullRequest) WithCommenter(commenter PullRequestCommenter) *AnnotatePullRequest {
+	uc.commenter = commenter
+	return uc
+}
+
+// WithGraphBuilder sets the graph builder used for both revisions.
+func (uc *AnnotatePullRequest) WithGraphBuilder(graphBuilder *BuildArchitectureGraph) *AnnotatePullRequest {
+	uc.graphBuilder = graphBuilder
+	return uc
+}
+
+// Execute builds the report for the changes between baseRef and projectRoot
+// and posts it when a commenter is configured.
+func (uc *AnnotatePullRequest) Execute(ctx context.Context, projectRoot, baseRef string) (*PullRequestReport, error) {
+	base, head, err := loadRevisions(ctx, uc.repo, uc.revisions, uc.graphBuilder, projectRoot, baseRef)
+	if err != nil {
+		return nil, err
+	}
+
+	validator := NewValidateArchitecture()
+	baseViolations := violations(base.graph, validator.Execute(base.graph, base.systems))
+	headViolations := violations(head.graph, validator.Execute(head.graph, head.systems))
+
+	report := &PullRequestReport{
+		BaseRef: baseRef,
+		Diff:    DiffArchitecture(base.graph, head.graph),
+	}
+	for _, v := range headViolations {
+		if v.Severity == "error" {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+		if !slices.Contains(baseViolations, v) {
+			report.NewViolations = append(report.NewViolations, v)
+		}
+	}
+
+	if uc.commenter != nil {
+		url, err := uc.commenter.UpsertComment(ctx, PullRequestCommentMarker, RenderPullRequestComment(report))
+		if err != nil {
+			return report, fmt.Errorf("failed to post comment: %w", err)
+		}
+		report.CommentURL = url
+	}
+
+	return report, nil
+}
+
+// revisionModel is the architecture of a project at one revision.
+type revisionModel struct {
+	graph   *entities.ArchitectureGraph
+	systems []*entities.System
+}
+
+// loadRevisions loads the project at baseRef, exported to a temporary
+// directory through revisions, and the project in the working tree at
+// projectRoot.
+func loadRevisions(ctx context.Context, repo ProjectRepository, revisions RevisionExporter, graphBuilder *BuildArchitectureGraph, projectRoot, baseRef string) (base, head revisionModel, err error) {
+	if baseRef == "" {
+		return base, head, fmt.Errorf("base ref cannot be empty")
+	}
+
+	baseDir, err := os.MkdirTemp("", "loko-base-*")
+	if err != nil {
+		return base, head, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(baseDir) }()
+
+	if err := revisions.ExportRevision(ctx, projectRoot, baseRef, baseDir); err != nil {
+		return base, head, fmt.Errorf("failed to export base revision %s: %w", baseRef, err)
+	}
+
+	if base, err = loadRevisionModel(ctx, repo, graphBuilder, baseDir); err != nil {
+		return base, head, fmt.Errorf("failed to load base revision %s: %w", baseRef, err)
+	}
+	head, err = loadRevisionModel(ctx, repo, graphBuilder, projectRoot)
+	return base, head, err
+}
+
+// loadRevisionModel loads the project at root and builds its architecture graph.
+// A revision without a source directory yet is treated as an empty architecture.
+func loadRevisionModel(ctx context.Context, repo ProjectRepository, graphBuilder *BuildArchitectureGraph, root string) (revisionModel, error) {
+	project, err := repo.LoadProject(ctx, root)
+	if err != nil {
+		return revisionModel{}, fmt.Errorf("failed to load project: %w", err)
+	}
+
+	systems, err := repo.ListSystems(ctx, root)
+	if err != nil {
+		sourceDir := "src"
+		if project.Config != nil && project.Config.SourceDir != "" {
+			sourceDir = project.Config.SourceDir
+		}
+		if _, statErr := os.Stat(filepath.Join(root, sourceDir)); !errors.Is(statErr, fs.ErrNotExist) {
+			return revisionModel{}, fmt.Errorf("failed to list systems: %w", err)
+		}
+		systems = nil
+	}
+
+	graph, err := graphBuilder.Execute(ctx, project, systems)
+	if err != nil {
+		return revisionModel{}, fmt.Errorf("failed to build architecture graph: %w", err)
+	}
+	return revisionModel{graph: graph, systems: systems}, nil
+}
+
+// violations flattens a validation report's errors and warnings into one
+// entry per affected element, sorted by severity, code and element.
+func violations(graph *entities.ArchitectureGraph, report *ArchitectureReport) []Violation {
+	var result []Violation
+	for _, issue := range report.Issues {
+		if issue.Severity != "error" && issue.Severity != "warning" {
+			continue
+		}
+		if len(issue.Affected) == 0 {
+			result = append(result, Violation{Severity: issue.Severity, Code: issue.Code})
+			continue
+		}
+		for _, id := range issue.Affected {
+			if qualified, ok := graph.ResolveID(id); ok && !strings.Contains(id, "/") {
+				id = qualified
+			}
+			result = append(result, Violation{Severity: issue.Severity, Code: issue.Code, Element: id})
+		}
+	}
+	slices.SortFunc(result, func(a, b Violation) int {
+		return cmp.Or(cmp.Compare(a.Severity, b.Severity), cmp.Compare(a.Code, b.Code), cmp.Compare(a.Element, b.Element))
+	})
+	return slices.Compact(result)
+}
+
+// RenderPullRequestComment formats the report as a Markdown comment that
+// starts with PullRequestCommentMarker.
+func RenderPullRequestComment(report *PullRequestReport) string {
+	var b strings.Builder
+	diff := report.Diff
+	if diff == nil {
+		diff = &ArchitectureDiff{}
+	}
+
+	b.WriteString(PullRequestCommentMarker + "\n")
+	b.WriteString("## Architecture changes\n\n")
+	fmt.Fprintf(&b, "Compared with `%s`.\n\n", report.BaseRef)
+
+	if diff.IsEmpty() {
+		b.WriteString("No systems, containers, components or dependencies changed.\n\n")
+	} else {
+		b.WriteString("| | Added | Removed |\n|---|---:|---:|\n")
+		fmt.Fprintf(&b, "| Systems | %d | %d |\n", len(diff.AddedSystems), len(diff.RemovedSystems))
+		fmt.Fprintf(&b, "| Containers | %d | %d |\n", len(diff.AddedContainers), len(diff.RemovedContainers))
+		fmt.Fprintf(&b, "| Components | %d | %d |\n", len(diff.AddedComponents), len(diff.RemovedComponents))
+		fmt.Fprintf(&b, "| Dependencies | %d | %d |\n\n", len(diff.AddedDependencies), len(diff.RemovedDependencies))
+
+		writeIDList(&b, "Added systems", diff.AddedSystems)
+		writeIDList(&b, "Removed systems", diff.RemovedSystems)
+		writeIDList(&b, "Added containers", diff.AddedContainers)
+		writeIDList(&b, "Removed containers", diff.RemovedContainers)
+		writeIDList(&b, "Added components", diff.AddedComponents)
+		writeIDList(&b, "Removed components", diff.RemovedComponents)
+		writeList(&b, "New dependencies", diff.AddedDependencies, formatDependency)
+		writeList(&b, "Removed dependencies", diff.RemovedDependencies, formatDependency)
+	}
+
+	b.WriteString("### Rule violations\n\n")
+	if len(report.NewViolations) == 0 {
+		b.WriteString("No new validation errors or warnings.")
+	} else {
+		fmt.Fprintf(&b, "This change introduces %d new issue(s):\n\n", len(report.NewViolations))
+		writeItems(&b, report.NewViolations, formatViolation)
+	}
+	fmt.Fprintf(&b, "\n\nTotal at head: %d error(s), %d warning(s).\n", report.Errors, report.Warnings)
+
+	return b.String()
+}
+
+// writeIDList writes a titled bullet list of element IDs, skipping empty lists.
+func writeIDList(b *strings.Builder, title string, ids []string) {
+	writeList(b, title, ids, func(id string) string { return "`" + id + "`" })
+}
+
+// writeList writes a titled bullet list, skipping empty lists.
+func writeList[T any](b *strings.Builder, title string, items []T, format func(T) string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "### %s\n\n", title)
+	writeItems(b, items, format)
+	b.WriteString("\n\n")
+}
+
+// writeItems writes up to maxCommentItems bullets and notes how many were omitted.
+func writeItems[T any](b *strings.Builder, items []T, format func(T) string) {
+	for i, item := range items {
+		if i == maxCommentItems {
+			fmt.Fprintf(b, "\n- …and %d more", len(items)-maxCommentItems)
+			break
+		}
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("- " + format(item))
+	}
+}
+
+// formatDependency renders a dependency as "`source` → `target` — description".
+func formatDependency(d Dependency) string {
+	s := "`" + d.Source + "` → `" + d.Target + "`"
+	if d.Description != "" {
+		s += " — " + d.Description
+	}
+	return s
+}
+
+// formatViolation renders a violation as "**severity** `code`: `element`".
+func formatViolation(v Violation) string {
+	s := "**" + v.Severity + "** `" + v.Code + "`"
+	if v.Element != "" {
+		s += ": `" + v.Element + "`"
+	}
+	return s
+}
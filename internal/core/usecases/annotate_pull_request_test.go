@@ -0,0 +1,158 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// mockRevisionExporter records the revision it was asked to export.
+type mockRevisionExporter struct {
+	ref string
+	err error
+}
+
+func (m *mockRevisionExporter) ExportRevision(_ context.Context, _, ref, _ string) error {
+	m.ref = ref
+	return m.err
+}
+
+// mockCommenter records the last comment it was asked to post.
+type mockCommenter struct {
+	marker string
+	body   string
+}
+
+func (m *mockCommenter) UpsertComment(_ context.Context, marker, body string) (string, error) {
+	m.marker = marker
+	m.body = body
+	return "https://example.com/pr/1#comment", nil
+}
+
+// prFixture returns a base architecture with an API and a worker container,
+// and a head that removes the worker, adds a ledger the handler depends on,
+// and adds an audit component with a dangling reference.
+func prFixture(head bool) []*entities.System {
+	system, _ := entities.NewSystem("Payments")
+	api, _ := entities.NewContainer("API")
+	handler, _ := entities.NewComponent("Handler")
+	_ = api.AddComponent(handler)
+	_ = system.AddContainer(api)
+
+	if !head {
+		worker, _ := entities.NewContainer("Worker")
+		_ = system.AddContainer(worker)
+		return []*entities.System{system}
+	}
+
+	ledger, _ := entities.NewComponent("Ledger")
+	audit, _ := entities.NewComponent("Audit")
+	handler.AddRelationship("payments/api/ledger", "records payment")
+	audit.AddRelationship("ghost", "reads")
+	_ = api.AddComponent(ledger)
+	_ = api.AddComponent(audit)
+	return []*entities.System{system}
+}
+
+func newPRRepo(headRoot string) *MockProjectRepository {
+	return &MockProjectRepository{
+		LoadProjectFunc: func(_ context.Context, root string) (*entities.Project, error) {
+			return entities.NewProject("payments")
+		},
+		ListSystemsFunc: func(_ context.Context, root string) ([]*entities.System, error) {
+			return prFixture(root == headRoot), nil
+		},
+	}
+}
+
+func TestDiffArchitecture(t *testing.T) {
+	ctx := context.Background()
+	project, _ := entities.NewProject("payments")
+	base, _ := NewBuildArchitectureGraph().Execute(ctx, project, prFixture(false))
+	head, _ := NewBuildArchitectureGraph().Execute(ctx, project, prFixture(true))
+
+	diff := DiffArchitecture(base, head)
+	if !slices.Equal(diff.AddedComponents, []string{"payments/api/audit", "payments/api/ledger"}) {
+		t.Errorf("AddedComponents = %v", diff.AddedComponents)
+	}
+	if !slices.Equal(diff.RemovedContainers, []string{"payments/worker"}) {
+		t.Errorf("RemovedContainers = %v", diff.RemovedContainers)
+	}
+	want := []Dependency{{Source: "payments/api/handler", Target: "payments/api/ledger", Description: "records payment"}}
+	if !slices.Equal(diff.AddedDependencies, want) {
+		t.Errorf("AddedDependencies = %v, want %v", diff.AddedDependencies, want)
+	}
+	if len(diff.AddedSystems)+len(diff.RemovedSystems)+len(diff.RemovedComponents)+len(diff.RemovedDependencies) != 0 {
+		t.Errorf("unexpected changes: %+v", diff)
+	}
+
+	if !DiffArchitecture(head, head).IsEmpty() {
+		t.Error("expected identical graphs to produce an empty diff")
+	}
+}
+
+func TestAnnotatePullRequest(t *testing.T) {
+	revisions := &mockRevisionExporter{}
+	commenter := &mockCommenter{}
+
+	report, err := NewAnnotatePullRequest(newPRRepo("head"), revisions).
+		WithCommenter(commenter).
+		Execute(context.Background(), "head", "origin/main")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if revisions.ref != "origin/main" {
+		t.Errorf("exported ref = %q, want origin/main", revisions.ref)
+	}
+	wantViolations := []Violation{{Severity: "error", Code: "dangling_reference", Element: "payments/api/audit"}}
+	if !slices.Equal(report.NewViolations, wantViolations) {
+		t.Errorf("NewViolations = %+v, want %+v", report.NewViolations, wantViolations)
+	}
+	if report.Errors != 1 || report.CommentURL == "" {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	if commenter.marker != PullRequestCommentMarker || !strings.HasPrefix(commenter.body, PullRequestCommentMarker) {
+		t.Errorf("comment does not start with the marker: %q", commenter.body)
+	}
+	for _, want := range []string{
+		"Compared with `origin/main`.",
+		"| Components | 2 | 0 |",
+		"### Removed containers\n\n- `payments/worker`",
+		"- `payments/api/handler` → `payments/api/ledger` — records payment",
+		"- **error** `dangling_reference`: `payments/api/audit`",
+		"Total at head: 1 error(s), 0 warning(s).",
+	} {
+		if !strings.Contains(commenter.body, want) {
+			t.Errorf("comment missing %q:\n%s", want, commenter.body)
+		}
+	}
+}
+
+func TestAnnotatePullRequestErrors(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := NewAnnotatePullRequest(newPRRepo("head"), &mockRevisionExporter{}).Execute(ctx, "head", ""); err == nil {
+		t.Error("expected error for empty base ref")
+	}
+
+	exportErr := errors.New("unknown revision")
+	_, err := NewAnnotatePullRequest(newPRRepo("head"), &mockRevisionExporter{err: exportErr}).Execute(ctx, "head", "nope")
+	if !errors.Is(err, exportErr) {
+		t.Errorf("expected wrapped export error, got %v", err)
+	}
+}
+
+func TestRenderPullRequestCommentNoChanges(t *testing.T) {
+	body := RenderPullRequestComment(&PullRequestReport{BaseRef: "main", Diff: &ArchitectureDiff{}})
+	for _, want := range []string{"No systems, containers, components or dependencies changed.", "No new validation errors or warnings."} {
+		if !strings.Contains(body, want) {
+			t.Errorf("comment missing %q:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// ApplyProjectPresetResult summarizes what a preset scaffolded.
+type ApplyProjectPresetResult struct {
+	Systems       int
+	Containers    int
+	Components    int
+	Relationships int
+}
+
+// ApplyProjectPreset scaffolds the example model of a ProjectPreset into an
+// initialized project. Entities are created through ScaffoldEntity and
+// relationships through CreateRelationship, so the result is identical to
+// running the equivalent `loko new` and `loko relationship add` commands.
+type ApplyProjectPreset struct {
+	projectRepo      ProjectRepository
+	relRepo          RelationshipRepository
+	diagramGenerator DiagramGenerator
+}
+
+// NewApplyProjectPreset creates a new ApplyProjectPreset use case.
+func NewApplyProjectPreset(projectRepo ProjectRepository, relRepo RelationshipRepository) *ApplyProjectPreset {
+	return &ApplyProjectPreset{
+		projectRepo: projectRepo,
+		relRepo:     relRepo,
+	}
+}
+
+// WithDiagramGenerator sets the optional generator used to write D2 diagrams.
+func (uc *ApplyProjectPreset) WithDiagramGenerator(dg DiagramGenerator) *ApplyProjectPreset {
+	uc.diagramGenerator = dg
+	return uc
+}
+
+// Execute scaffolds every system, container, component and relationship of
+// the preset into the project at projectRoot.
+func (uc *ApplyProjectPreset) Execute(
+	ctx context.Context,
+	projectRoot string,
+	preset *entities.ProjectPreset,
+) (*ApplyProjectPresetResult, error) {
+	if preset == nil {
+		return nil, fmt.Errorf("preset cannot be nil")
+	}
+
+	var opts []ScaffoldEntityOption
+	if uc.diagramGenerator != nil {
+		opts = append(opts, WithDiagramGenerator(uc.diagramGenerator))
+	}
+	scaffold := NewScaffoldEntity(uc.projectRepo, opts...)
+	createRel := NewCreateRelationship(uc.relRepo)
+
+	result := &ApplyProjectPresetResult{}
+
+	for _, sys := range preset.Systems {
+		created, err := scaffold.Execute(ctx, &ScaffoldEntityRequest{
+			ProjectRoot: projectRoot,
+			EntityType:  "system",
+			Name:        sys.Name,
+			Description: sys.Description,
+			Tags:        sys.Tags,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scaffold system %s: %w", sys.Name, err)
+		}
+		systemID := created.EntityID
+		result.Systems++
+
+		for _, cont := range sys.Containers {
+			createdCont, err := scaffold.Execute(ctx, &ScaffoldEntityRequest{
+				ProjectRoot: projectRoot,
+				EntityType:  "container",
+				ParentPath:  []string{systemID},
+				Name:        cont.Name,
+				Description: cont.Description,
+				Technology:  cont.Technology,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to scaffold container %s: %w", cont.Name, err)
+			}
+			result.Containers++
+
+			for _, comp := range cont.Components {
+				if _, err := scaffold.Execute(ctx, &ScaffoldEntityRequest{
+					ProjectRoot: projectRoot,
+					EntityType:  "component",
+					ParentPath:  []string{systemID, createdCont.EntityID},
+					Name:        comp.Name,
+					Description: comp.Description,
+					Technology:  comp.Technology,
+				}); err != nil {
+					return nil, fmt.Errorf("failed to scaffold component %s: %w", comp.Name, err)
+				}
+				result.Components++
+			}
+		}
+
+		for _, rel := range sys.Relationships {
+			if _, err := createRel.Execute(ctx, &CreateRelationshipRequest{
+				ProjectRoot: projectRoot,
+				SystemID:    systemID,
+				Source:      systemID + "/" + rel.Source,
+				Target:      systemID + "/" + rel.Target,
+				Label:       rel.Label,
+				Type:        rel.Type,
+				Technology:  rel.Technology,
+			}); err != nil {
+				return nil, fmt.Errorf("failed to create relationship %s -> %s: %w", rel.Source, rel.Target, err)
+			}
+			result.Relationships++
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// newPresetProjectRepository returns a MockProjectRepository that keeps saved
+// systems in memory so scaffolded containers and components can find their parents.
+func newPresetProjectRepository(t *testing.T) *MockProjectRepository {
+	t.Helper()
+	project, err := entities.NewProject("demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	systems := make(map[string]*entities.System)
+
+	return &MockProjectRepository{
+		LoadProjectFunc: func(ctx context.Context, projectRoot string) (*entities.Project, error) {
+			return project, nil
+		},
+		SaveSystemFunc: func(ctx context.Context, projectRoot string, system *entities.System) error {
+			systems[system.ID] = system
+			return nil
+		},
+		LoadSystemFunc: func(ctx context.Context, projectRoot, systemName string) (*entities.System, error) {
+			sys, ok := systems[systemName]
+			if !ok {
+				return nil, entities.ErrSystemNotFound
+			}
+			return sys, nil
+		},
+	}
+}
+
+func TestApplyProjectPresetBuiltins(t *testing.T) {
+	for _, name := range entities.PresetNames() {
+		t.Run(name, func(t *testing.T) {
+			preset, err := entities.FindPreset(name)
+			if err != nil {
+				t.Fatalf("FindPreset(%q) error = %v", name, err)
+			}
+
+			relRepo := newMockRelationshipRepository()
+			uc := NewApplyProjectPreset(newPresetProjectRepository(t), relRepo).
+				WithDiagramGenerator(&mockDiagramGenerator{})
+
+			result, err := uc.Execute(context.Background(), t.TempDir(), preset)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			var wantContainers, wantComponents, wantRels int
+			for _, sys := range preset.Systems {
+				wantContainers += len(sys.Containers)
+				wantRels += len(sys.Relationships)
+				for _, cont := range sys.Containers {
+					wantComponents += len(cont.Components)
+				}
+			}
+
+			if result.Systems != len(preset.Systems) {
+				t.Errorf("Systems = %d, want %d", result.Systems, len(preset.Systems))
+			}
+			if result.Containers != wantContainers {
+				t.Errorf("Containers = %d, want %d", result.Containers, wantContainers)
+			}
+			if result.Components != wantComponents {
+				t.Errorf("Components = %d, want %d", result.Components, wantComponents)
+			}
+			if result.Relationships != wantRels {
+				t.Errorf("Relationships = %d, want %d", result.Relationships, wantRels)
+			}
+		})
+	}
+}
+
+func TestApplyProjectPresetQualifiesRelationships(t *testing.T) {
+	preset, err := entities.FindPreset("monolith")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	relRepo := newMockRelationshipRepository()
+	root := t.TempDir()
+	uc := NewApplyProjectPreset(newPresetProjectRepository(t), relRepo)
+	if _, err := uc.Execute(context.Background(), root, preset); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	rels, _ := relRepo.LoadRelationships(context.Background(), root, "web-shop")
+	if len(rels) == 0 {
+		t.Fatal("expected relationships for web-shop")
+	}
+	if rels[0].Source != "web-shop/web-app/controllers" {
+		t.Errorf("Source = %q, want system-qualified path", rels[0].Source)
+	}
+}
+
+func TestApplyProjectPresetNil(t *testing.T) {
+	uc := NewApplyProjectPreset(&MockProjectRepository{}, newMockRelationshipRepository())
+	if _, err := uc.Execute(context.Background(), t.TempDir(), nil); err == nil {
+		t.Error("expected error for nil preset")
+	}
+}
@@ -0,0 +1,93 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// ApproveElement records the approval of a system, container or component in
+// its frontmatter: review_status becomes approved, with the reviewer and the
+// date of the review.
+type ApproveElement struct {
+	editor FrontmatterEditor
+}
+
+// NewApproveElement creates an ApproveElement use case.
+func NewApproveElement(editor FrontmatterEditor) *ApproveElement {
+	return &ApproveElement{editor: editor}
+}
+
+// Execute approves the element id of systems on behalf of reviewer at the
+// given time, and returns its qualified ID. id is a system ID, a
+// "system/container" ID, or a qualified or unique component ID.
+func (uc *ApproveElement) Execute(ctx context.Context, systems []*entities.System, id, reviewer string, at time.Time) (string, error) {
+	qualifiedID, path, err := reviewSource(systems, id)
+	if err != nil {
+		return "", err
+	}
+
+	fields := []struct{ key, value string }{
+		{entities.ReviewStatusField, string(entities.ReviewApproved)},
+		{entities.ReviewedByField, strings.TrimSpace(reviewer)},
+		{entities.ReviewedAtField, at.Format(entities.ReviewDateLayout)},
+	}
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		if err := uc.editor.SetFrontmatterField(ctx, path, field.key, field.value); err != nil {
+			return "", fmt.Errorf("failed to approve %s: %w", qualifiedID, err)
+		}
+	}
+	return qualifiedID, nil
+}
+
+// reviewSource returns the qualified ID and the Markdown file of the element
+// id of systems.
+func reviewSource(systems []*entities.System, id string) (qualifiedID, path string, err error) {
+	parts := strings.Split(strings.Trim(id, "/"), "/")
+	var dir, file string
+	switch len(parts) {
+	case 1:
+		for _, system := range systems {
+			if system != nil && system.ID == parts[0] {
+				qualifiedID, dir, file = system.ID, system.Path, "system.md"
+			}
+		}
+		if qualifiedID == "" {
+			// Not a system: a unique component ID
+			components, shortIDs := indexComponents(systems)
+			if qualifiedID, err = resolveComponentID(components, shortIDs, id); err != nil {
+				return "", "", fmt.Errorf("no system or component %q", id)
+			}
+			dir, file = components[qualifiedID].Path, "component.md"
+		}
+	case 2:
+		for _, system := range systems {
+			if system == nil || system.ID != parts[0] {
+				continue
+			}
+			if container := system.Containers[parts[1]]; container != nil {
+				qualifiedID, dir, file = system.ID+"/"+container.ID, container.Path, "container.md"
+			}
+		}
+		if qualifiedID == "" {
+			return "", "", fmt.Errorf("container %q not found", id)
+		}
+	default:
+		components, shortIDs := indexComponents(systems)
+		if qualifiedID, err = resolveComponentID(components, shortIDs, id); err != nil {
+			return "", "", err
+		}
+		dir, file = components[qualifiedID].Path, "component.md"
+	}
+	if dir == "" {
+		return "", "", fmt.Errorf("%s was not loaded from a project", qualifiedID)
+	}
+	return qualifiedID, filepath.Join(dir, file), nil
+}
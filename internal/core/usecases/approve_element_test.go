@@ -0,0 +1,50 @@
+package usecases
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestApproveElement(t *testing.T) {
+	system, _ := entities.NewSystem("Payments")
+	system.Path = "/src/payments"
+	container, _ := entities.NewContainer("API")
+	container.Path = "/src/payments/api"
+	handler, _ := entities.NewComponent("Handler")
+	handler.Path = "/src/payments/api/handler"
+	_ = container.AddComponent(handler)
+	_ = system.AddContainer(container)
+	systems := []*entities.System{system}
+
+	at := time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		id       string
+		reviewer string
+		want     string
+		edits    []string
+	}{
+		{"payments", "Ana", "payments", []string{"payments/system.md set review_status=approved", "payments/system.md set reviewed_by=Ana", "payments/system.md set reviewed_at=2026-03-04"}},
+		{"payments/api", "", "payments/api", []string{"api/container.md set review_status=approved", "api/container.md set reviewed_at=2026-03-04"}},
+		{"handler", "Ana", "payments/api/handler", []string{"handler/component.md set review_status=approved", "handler/component.md set reviewed_by=Ana", "handler/component.md set reviewed_at=2026-03-04"}},
+	}
+	for _, tt := range tests {
+		editor := &recordingEditor{}
+		got, err := NewApproveElement(editor).Execute(context.Background(), systems, tt.id, tt.reviewer, at)
+		if err != nil {
+			t.Fatalf("Execute(%q) error = %v", tt.id, err)
+		}
+		if got != tt.want || !slices.Equal(editor.edits, tt.edits) {
+			t.Errorf("Execute(%q) = %q, edits %q; want %q, %q", tt.id, got, editor.edits, tt.want, tt.edits)
+		}
+	}
+
+	for _, id := range []string{"billing", "payments/web", "payments/api/missing"} {
+		if _, err := NewApproveElement(&recordingEditor{}).Execute(context.Background(), systems, id, "Ana", at); err == nil {
+			t.Errorf("Execute(%q) expected an error", id)
+		}
+	}
+}
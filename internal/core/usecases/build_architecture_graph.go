@@ -3,7 +3,11 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -57,12 +61,12 @@ func NewBuildArchitectureGraphFull(d2Parser D2Parser, relRepo RelationshipReposi
 // Execute builds an ArchitectureGraph from the given project and systems.
 //
 // The graph includes:
-// - Nodes for all systems, containers, and components
+// - Nodes for all systems, containers, and components, and the project's people
 // - Hierarchy edges (parent-child relationships)
-// - Relationship edges (component dependencies from frontmatter, D2, and relationships.toml)
+// - Relationship edges (dependencies from frontmatter, D2, and relationships.toml)
 //
 // C4 Level mapping:
-// - Level 1: Systems
+// - Level 1: Systems and people
 // - Level 2: Containers
 // - Level 3: Components
 func (uc *BuildArchitectureGraph) Execute(
@@ -77,8 +81,24 @@ func (uc *BuildArchitectureGraph) Execute(
 	graph := entities.NewArchitectureGraph()
 
 	// First pass: Add all nodes (systems, containers, components)
-	// Track component entities and their qualified node IDs for relationship resolution
-	componentToQualifiedID := make(map[*entities.Component]string)
+	// Track component entities and their qualified node IDs, in model order,
+	// for relationship resolution
+	type qualifiedComponent struct {
+		component *entities.Component
+		id        string
+	}
+	var components []qualifiedComponent
+
+	// Systems and containers with frontmatter relationships, in model order
+	type qualifiedRelationships struct {
+		id            string
+		relationships map[string]string
+	}
+	var elements []qualifiedRelationships
+
+	// Directories of the system and container diagrams, whose D2 files may
+	// draw relationships at those levels
+	var diagrams []diagramScope
 
 	for _, system := range systems {
 		if system == nil {
@@ -98,9 +118,23 @@ func (uc *BuildArchitectureGraph) Execute(
 		if err := graph.AddNode(systemNode); err != nil {
 			return nil, fmt.Errorf("failed to add system node: %w", err)
 		}
+		if system.Path != "" {
+			diagrams = append(diagrams, diagramScope{dir: system.Path, id: systemNode.ID})
+		}
+		if len(system.Relationships) > 0 {
+			elements = append(elements, qualifiedRelationships{systemNode.ID, system.Relationships})
+		}
+
+		// Namespaced systems also resolve by their directory path, so
+		// "payments/billing/api/handler" refers to "payments.billing/api/handler"
+		sourcePath := ""
+		if system.Domain != "" {
+			sourcePath = entities.SystemSourcePath(system.ID)
+			graph.AddAlias(sourcePath, systemNode.ID)
+		}
 
 		// Add container nodes
-		for _, container := range system.Containers {
+		for _, container := range system.ListContainers() {
 			if container == nil {
 				continue
 			}
@@ -121,20 +155,35 @@ func (uc *BuildArchitectureGraph) Execute(
 			if err := graph.AddNode(containerNode); err != nil {
 				return nil, fmt.Errorf("failed to add container node: %w", err)
 			}
+			if container.Path != "" {
+				diagrams = append(diagrams, diagramScope{dir: container.Path, id: containerNode.ID})
+			}
+			if len(container.Relationships) > 0 {
+				elements = append(elements, qualifiedRelationships{containerNode.ID, container.Relationships})
+			}
+			if sourcePath != "" {
+				graph.AddAlias(sourcePath+"/"+container.ID, containerNode.ID)
+			}
 
 			// Add component nodes
-			for _, component := range container.Components {
+			for _, component := range container.ListComponents() {
 				if component == nil {
 					continue
 				}
 
+				// Sub-components are children of their parent component
+				parentID := entities.QualifiedNodeID("container", system.ID, container.ID, "")
+				if component.Parent != "" {
+					parentID = entities.QualifiedNodeID("component", system.ID, container.ID, component.Parent)
+				}
+
 				componentNode := &entities.GraphNode{
 					ID:          entities.QualifiedNodeID("component", system.ID, container.ID, component.ID),
 					Type:        "component",
 					Name:        component.Name,
 					Description: component.Description,
 					Level:       3,
-					ParentID:    entities.QualifiedNodeID("container", system.ID, container.ID, ""),
+					ParentID:    parentID,
 					Data:        component,
 					Metadata: map[string]string{
 						"technology": component.Technology,
@@ -145,21 +194,57 @@ func (uc *BuildArchitectureGraph) Execute(
 					return nil, fmt.Errorf("failed to add component node: %w", err)
 				}
 
+				// Sub-components also resolve by their own ID and their
+				// directory path, e.g. "validator" and "shop/api/handler/validator"
+				if component.Parent != "" {
+					local := strings.TrimPrefix(component.ID, component.Parent+entities.ComponentSeparator)
+					graph.AddAlias(local, componentNode.ID)
+					graph.AddAlias(system.ID+"/"+container.ID+"/"+entities.ComponentSourcePath(component.ID), componentNode.ID)
+				}
+				if sourcePath != "" {
+					graph.AddAlias(sourcePath+"/"+container.ID+"/"+entities.ComponentSourcePath(component.ID), componentNode.ID)
+				}
+
+				// Old IDs of components merged into this one resolve to it
+				for _, alias := range component.Aliases {
+					graph.AddAlias(alias, componentNode.ID)
+				}
+
 				// Track component and its qualified ID for relationship processing in second pass
-				componentToQualifiedID[component] = componentNode.ID
+				components = append(components, qualifiedComponent{component, componentNode.ID})
 			}
 		}
 	}
 
+	// People use the systems; their relationships name the systems,
+	// containers or components they interact with
+	for _, person := range project.ListPeople() {
+		personNode := &entities.GraphNode{
+			ID:          person.ID,
+			Type:        "person",
+			Name:        person.Name,
+			Description: person.Description,
+			Level:       1,
+			Data:        person,
+			Metadata: map[string]string{
+				"external": strconv.FormatBool(person.External),
+			},
+		}
+
+		if err := graph.AddNode(personNode); err != nil {
+			return nil, fmt.Errorf("failed to add person node: %w", err)
+		}
+		if len(person.Relationships) > 0 {
+			elements = append(elements, qualifiedRelationships{personNode.ID, person.Relationships})
+		}
+	}
+
 	// Second pass: Union merge relationships from frontmatter and D2, then deduplicate.
 	// Key: "sourceQualifiedID->targetQualifiedID" — used to deduplicate by (source, target).
 	edgeSeen := make(map[string]bool)
-	var edgeMu sync.Mutex // guards edgeSeen and graph.AddEdge
 
 	addEdgeIfNew := func(sourceQualifiedID, targetQualifiedID, description string) {
 		key := sourceQualifiedID + "->" + targetQualifiedID
-		edgeMu.Lock()
-		defer edgeMu.Unlock()
 		if edgeSeen[key] {
 			return // T036: deduplicate by (source, target)
 		}
@@ -201,59 +286,80 @@ func (uc *BuildArchitectureGraph) Execute(
 		return "", false // not found
 	}
 
-	// T035 source 1: frontmatter relationships
-	for component, sourceQualifiedID := range componentToQualifiedID {
-		for relatedID, relDescription := range component.Relationships {
-			targetQualifiedID, ok := resolveTarget(relatedID, sourceQualifiedID)
+	// T035 source 1: frontmatter relationships, by target ID so edges are
+	// added in the same order on every build
+	for _, qc := range components {
+		for _, relatedID := range slices.Sorted(maps.Keys(qc.component.Relationships)) {
+			targetQualifiedID, ok := resolveTarget(relatedID, qc.id)
+			if !ok {
+				continue
+			}
+			addEdgeIfNew(qc.id, targetQualifiedID, qc.component.Relationships[relatedID])
+		}
+	}
+
+	// System, container and person relationships name a sibling first, so "db" in a
+	// container of "shop" is the container "shop/db" even when other systems
+	// have a "db" too, then any element by qualified or short ID
+	for _, e := range elements {
+		for _, relatedID := range slices.Sorted(maps.Keys(e.relationships)) {
+			targetQualifiedID, ok := resolveSibling(graph, relatedID, e.id)
 			if !ok {
+				targetQualifiedID, ok = resolveTarget(relatedID, e.id)
+			}
+			if !ok || targetQualifiedID == e.id {
 				continue
 			}
-			addEdgeIfNew(sourceQualifiedID, targetQualifiedID, relDescription)
+			addEdgeIfNew(e.id, targetQualifiedID, e.relationships[relatedID])
 		}
 	}
 
 	// T035 source 2: D2 file relationships (if parser is configured)
 	// T037: Worker pool — up to 10 goroutines parse D2 files concurrently.
+	// Their edges are added once all are parsed: component diagrams first, in
+	// component order, then system and container diagrams in model order.
 	if uc.d2Parser != nil {
-		const maxWorkers = 10
-		sem := make(chan struct{}, maxWorkers)
-		var wg sync.WaitGroup
-
-		for component, sourceQualifiedID := range componentToQualifiedID {
-			if component.Path == "" {
-				continue // no filesystem path, skip D2 parsing
+		dirs := make([]string, 0, len(components)+len(diagrams))
+		for _, qc := range components {
+			dirs = append(dirs, qc.component.Path) // "" when there is no filesystem path
+		}
+		for _, d := range diagrams {
+			dirs = append(dirs, d.dir)
+		}
+		parsed := uc.parseD2Dirs(ctx, dirs)
+
+		for i, qc := range components {
+			for _, d2Rel := range parsed[i] {
+				// Only attribute relationships whose D2 source matches this component.
+				// This prevents cross-component contamination when a D2 file covers
+				// multiple nodes.
+				if d2Rel.Source != qc.component.ID {
+					continue
+				}
+				targetQualifiedID, ok := resolveTarget(d2Rel.Target, qc.id)
+				if !ok {
+					continue
+				}
+				addEdgeIfNew(qc.id, targetQualifiedID, d2Rel.Label)
 			}
-			// Capture loop variables for goroutine
-			comp := component
-			srcQID := sourceQualifiedID
-
-			wg.Add(1)
-			sem <- struct{}{} // acquire slot
-			go func() {
-				defer wg.Done()
-				defer func() { <-sem }() // release slot
-
-				d2Rels, err := uc.parseComponentD2(ctx, comp.Path)
-				if err != nil {
-					// T033: graceful degradation — log warning, continue
-					return
+		}
+
+		// Hand-drawn system context and container diagrams relate the
+		// elements they show; shapes that are not in the model, such as
+		// users and external services, are skipped.
+		for i, d := range diagrams {
+			for _, d2Rel := range parsed[len(components)+i] {
+				sourceID, ok := resolveDiagramNode(graph, d2Rel.Source, d.id)
+				if !ok {
+					continue
 				}
-				for _, d2Rel := range d2Rels {
-					// Only attribute relationships whose D2 source matches this component.
-					// This prevents cross-component contamination when a D2 file covers
-					// multiple nodes.
-					if d2Rel.Source != comp.ID {
-						continue
-					}
-					targetQualifiedID, ok := resolveTarget(d2Rel.Target, srcQID)
-					if !ok {
-						continue
-					}
-					addEdgeIfNew(srcQID, targetQualifiedID, d2Rel.Label)
+				targetID, ok := resolveDiagramNode(graph, d2Rel.Target, d.id)
+				if !ok || targetID == sourceID {
+					continue
 				}
-			}()
+				addEdgeIfNew(sourceID, targetID, d2Rel.Label)
+			}
 		}
-		wg.Wait()
 	}
 
 	// T019: Load relationships from RelationshipRepository (relationships.toml)
@@ -348,32 +454,122 @@ func expandNodeToComponents(nodeID string, graph *entities.ArchitectureGraph) []
 	}
 }
 
-// parseComponentD2 reads the D2 diagram file for a component (if present) and
-// returns the relationships defined there. Returns nil, nil when no D2 file exists.
-func (uc *BuildArchitectureGraph) parseComponentD2(ctx context.Context, componentPath string) ([]entities.D2Relationship, error) {
-	// Look for any .d2 file inside the component directory
-	entries, err := os.ReadDir(componentPath)
+// resolveSibling resolves relatedID to a node relative to the parent of the
+// node id or one of its ancestors, e.g. "db" to "shop/db" for "shop/api".
+func resolveSibling(graph *entities.ArchitectureGraph, relatedID, id string) (string, bool) {
+	for scope := graph.ParentMap[id]; scope != ""; scope = graph.ParentMap[scope] {
+		if graph.Nodes[scope+"/"+relatedID] != nil {
+			return scope + "/" + relatedID, true
+		}
+	}
+	return "", false
+}
+
+// diagramScope is the directory of a system or container diagram and the
+// qualified ID of the element it belongs to.
+type diagramScope struct {
+	dir string
+	id  string
+}
+
+// parseD2Dirs parses the D2 files of each directory with a pool of up to 10
+// workers and returns their relationships by directory index. Empty
+// directories, missing ones and files that fail to parse yield none (T033:
+// graceful degradation).
+func (uc *BuildArchitectureGraph) parseD2Dirs(ctx context.Context, dirs []string) [][]entities.D2Relationship {
+	const maxWorkers = 10
+	sem := make(chan struct{}, maxWorkers)
+	var wg sync.WaitGroup
+	parsed := make([][]entities.D2Relationship, len(dirs))
+
+	for i, dir := range dirs {
+		if dir == "" {
+			continue // no filesystem path, skip D2 parsing
+		}
+
+		wg.Add(1)
+		sem <- struct{}{} // acquire slot
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }() // release slot
+
+			d2Rels, err := uc.parseComponentD2(ctx, dir)
+			if err != nil {
+				return
+			}
+			parsed[i] = d2Rels
+		}()
+	}
+	wg.Wait()
+	return parsed
+}
+
+// parseComponentD2 reads the D2 diagram files directly inside dir, such as a
+// component, container or system directory, and returns the relationships
+// defined there. Returns nil, nil when no D2 file exists.
+func (uc *BuildArchitectureGraph) parseComponentD2(ctx context.Context, dir string) ([]entities.D2Relationship, error) {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
 		// Directory not accessible — treat as no D2 file (graceful degradation)
 		return nil, nil
 	}
 
+	var rels []entities.D2Relationship
 	for _, entry := range entries {
-		if entry.IsDir() {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".d2") {
 			continue
 		}
-		name := entry.Name()
-		if strings.HasSuffix(name, ".d2") {
-			d2Path := componentPath + "/" + name
-			data, err := os.ReadFile(d2Path)
-			if err != nil {
-				return nil, err
-			}
-			return uc.d2Parser.ParseRelationships(ctx, string(data))
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
 		}
+		fileRels, err := uc.d2Parser.ParseRelationships(ctx, string(data))
+		if err != nil {
+			return nil, err
+		}
+		rels = append(rels, fileRels...)
+	}
+	return rels, nil // nil when no D2 file was found — valid state
+}
+
+// resolveDiagramNode resolves the ID of a shape in the diagram of the element
+// scope, such as "api" or "payments.api" in the diagram of system "payments",
+// to a qualified graph node ID. The shape's path is tried relative to the
+// element and each of its ancestors, then as a qualified ID or alias, then by
+// its last segment when that names a single element of the same system. Each
+// attempt also tries the normalized form of the shape names, e.g.
+// "api-server" for "API Server".
+func resolveDiagramNode(graph *entities.ArchitectureGraph, d2ID, scope string) (string, bool) {
+	segments := strings.Split(d2ID, ".")
+	normalized := make([]string, len(segments))
+	for i, segment := range segments {
+		normalized[i] = entities.NormalizeName(segment)
+	}
+	system := scope
+	for graph.ParentMap[system] != "" {
+		system = graph.ParentMap[system]
 	}
 
-	return nil, nil // no D2 file found — valid state
+	for _, parts := range [][]string{segments, normalized} {
+		path := strings.Join(parts, "/")
+		for s := scope; s != ""; s = graph.ParentMap[s] {
+			if graph.Nodes[s+"/"+path] != nil {
+				return s + "/" + path, true
+			}
+		}
+		if graph.Nodes[path] != nil {
+			return path, true
+		}
+		if id, ok := graph.ResolveID(path); ok && len(parts) > 1 {
+			return id, true
+		}
+		// A bare shape name, such as "user", may be an actor outside the
+		// model, so it only matches within the diagram's system
+		if id, ok := graph.ResolveID(parts[len(parts)-1]); ok && strings.HasPrefix(id, system+"/") {
+			return id, true
+		}
+	}
+	return "", false
 }
 
 // GetSystemGraph returns a subgraph containing only a specific system and its descendants.
@@ -428,8 +624,8 @@ func (uc *BuildArchitectureGraph) AnalyzeDependencies(
 ) *entities.DependencyReport {
 	report := entities.NewDependencyReport()
 
-	// Count nodes by level
-	systems := graph.GetNodesByLevel(1)
+	// Count nodes by level; people share level 1 with systems
+	systems := graph.GetNodesByType("system")
 	containers := graph.GetNodesByLevel(2)
 	components := graph.GetNodesByLevel(3)
 
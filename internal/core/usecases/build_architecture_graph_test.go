@@ -2,7 +2,9 @@ package usecases
 
 import (
 	"context"
+	"maps"
 	"os"
+	"slices"
 	"testing"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
@@ -913,3 +915,227 @@ func TestBuildArchitectureGraph_MixedContainerComponent(t *testing.T) {
 		t.Errorf("compDB: expected 2 dependents, got %d: %v", len(dependents), dependents)
 	}
 }
+
+// TestBuildArchitectureGraph_SubComponents verifies that sub-components are
+// children of their parent component and resolve by their own ID and
+// directory path.
+func TestBuildArchitectureGraph_SubComponents(t *testing.T) {
+	project, _ := entities.NewProject("test-project")
+	system, _ := entities.NewSystem("Shop")
+	container, _ := entities.NewContainer("API")
+	_ = system.AddContainer(container)
+
+	handler, _ := entities.NewComponent("Handler")
+	validator, _ := entities.NewComponent("Validator")
+	validator.SetParent(handler.ID)
+	store, _ := entities.NewComponent("Store")
+	store.AddRelationship("validator", "validated by")
+	for _, comp := range []*entities.Component{handler, validator, store} {
+		_ = container.AddComponent(comp)
+	}
+
+	graph, err := NewBuildArchitectureGraph().Execute(context.Background(), project, []*entities.System{system})
+	if err != nil {
+		t.Fatalf("failed to build graph: %v", err)
+	}
+
+	validatorID := "shop/api/handler.validator"
+	if graph.GetNode(validatorID) == nil {
+		t.Fatalf("sub-component node %s missing", validatorID)
+	}
+	if parent := graph.ParentMap[validatorID]; parent != "shop/api/handler" {
+		t.Errorf("parent of %s = %q, want shop/api/handler", validatorID, parent)
+	}
+	for _, id := range []string{"validator", "handler.validator", "shop/api/handler/validator"} {
+		if got, ok := graph.ResolveID(id); !ok || got != validatorID {
+			t.Errorf("ResolveID(%q) = %q, %v, want %s", id, got, ok, validatorID)
+		}
+	}
+	if deps := graph.GetDependencies("shop/api/store"); len(deps) != 1 || deps[0].ID != validatorID {
+		t.Errorf("store dependencies = %v, want %s", deps, validatorID)
+	}
+}
+
+// TestBuildArchitectureGraph_StableEdgeOrder checks that edges are added in
+// the same order on every build, whatever the map iteration order.
+func TestBuildArchitectureGraph_StableEdgeOrder(t *testing.T) {
+	project, _ := entities.NewProject("test-project")
+	system, _ := entities.NewSystem("Shop")
+	container, _ := entities.NewContainer("API")
+	_ = system.AddContainer(container)
+	for _, name := range []string{"Handler", "Auth", "Cart", "Orders", "Payments", "Search"} {
+		component, _ := entities.NewComponent(name)
+		_ = container.AddComponent(component)
+	}
+	handler := container.Components["handler"]
+	for _, target := range []string{"search", "auth", "payments", "cart", "orders"} {
+		handler.AddRelationship(target, "uses "+target)
+	}
+
+	source := entities.QualifiedNodeID("component", system.ID, container.ID, "handler")
+	var want []string
+	for i := 0; i < 20; i++ {
+		graph, err := NewBuildArchitectureGraph().Execute(context.Background(), project, []*entities.System{system})
+		if err != nil {
+			t.Fatalf("failed to build graph: %v", err)
+		}
+		var targets []string
+		for _, edge := range graph.Edges[source] {
+			targets = append(targets, edge.Target)
+		}
+		if i == 0 {
+			want = targets
+			if len(want) != 5 || !slices.IsSorted(want) {
+				t.Fatalf("edge targets = %v, want the 5 targets sorted", want)
+			}
+		} else if !slices.Equal(targets, want) {
+			t.Fatalf("build %d edge targets = %v, want %v", i, targets, want)
+		}
+	}
+}
+
+// TestBuildArchitectureGraph_SystemAndContainerD2 verifies that relationships
+// drawn in system and container diagrams are resolved against the diagram's
+// element and added to the graph, and that shapes outside the model are
+// skipped.
+func TestBuildArchitectureGraph_SystemAndContainerD2(t *testing.T) {
+	project, _ := entities.NewProject("test-project")
+
+	payments, _ := entities.NewSystem("Payments")
+	payments.Path = t.TempDir()
+	api, _ := entities.NewContainer("API")
+	api.Path = t.TempDir()
+	database, _ := entities.NewContainer("Database")
+	handler, _ := entities.NewComponent("Handler")
+	_ = api.AddComponent(handler)
+	_ = payments.AddContainer(api)
+	_ = payments.AddContainer(database)
+
+	billing, _ := entities.NewSystem("Billing")
+	web, _ := entities.NewContainer("Web")
+	user, _ := entities.NewComponent("User")
+	_ = web.AddComponent(user)
+	_ = billing.AddContainer(web)
+
+	rel := func(source, target, label string) entities.D2Relationship {
+		r, _ := entities.NewD2Relationship(source, target, label)
+		return *r
+	}
+	systemD2 := writeD2File(t, payments.Path, "system.d2", "system diagram")
+	containerD2 := writeD2File(t, api.Path, "api.d2", "container diagram")
+	mock := &mockD2Parser{byContent: map[string][]entities.D2Relationship{
+		systemD2: {
+			rel("api", "database", "Reads"),
+			rel("payments.api", "billing", "Invoices"),
+			rel("payments", "billing", "Bills"),
+			rel("user", "payments", "Uses"), // an actor, not billing's User component
+		},
+		containerD2: {
+			rel("handler", "database", "Queries"),
+			rel("API Gateway", "handler", "Routes"),
+			rel("handler", "handler", "Recurses"),
+		},
+	}}
+
+	graph, err := NewBuildArchitectureGraphWithD2(mock).Execute(context.Background(), project, []*entities.System{payments, billing})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	var got []string
+	for _, source := range slices.Sorted(maps.Keys(graph.Edges)) {
+		for _, edge := range graph.Edges[source] {
+			got = append(got, edge.Source+" -> "+edge.Target+": "+edge.Description)
+		}
+	}
+	want := []string{
+		"payments -> billing: Bills",
+		"payments/api -> payments/database: Reads",
+		"payments/api -> billing: Invoices",
+		"payments/api/handler -> payments/database: Queries",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("edges = %q, want %q", got, want)
+	}
+}
+
+// TestBuildArchitectureGraph_SystemAndContainerRelationships verifies that
+// frontmatter relationships of systems and containers become edges, with
+// container targets resolved within their own system first.
+func TestBuildArchitectureGraph_SystemAndContainerRelationships(t *testing.T) {
+	project, _ := entities.NewProject("test-project")
+
+	shop, _ := entities.NewSystem("Shop")
+	shopAPI, _ := entities.NewContainer("API")
+	shopDB, _ := entities.NewContainer("DB")
+	_ = shop.AddContainer(shopAPI)
+	_ = shop.AddContainer(shopDB)
+
+	payments, _ := entities.NewSystem("Payments")
+	paymentsDB, _ := entities.NewContainer("DB")
+	gateway, _ := entities.NewContainer("Gateway")
+	_ = payments.AddContainer(paymentsDB)
+	_ = payments.AddContainer(gateway)
+
+	shop.AddRelationship("payments", "Charges orders")
+	shop.AddRelationship("shop", "Ignored self reference")
+	shopAPI.AddRelationship("db", "Stores carts")
+	shopAPI.AddRelationship("gateway", "Authorizes cards")
+	shopAPI.AddRelationship("unknown", "Skipped")
+
+	graph, err := NewBuildArchitectureGraph().Execute(context.Background(), project, []*entities.System{shop, payments})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	var got []string
+	for _, source := range slices.Sorted(maps.Keys(graph.Edges)) {
+		for _, edge := range graph.Edges[source] {
+			got = append(got, edge.Source+" -> "+edge.Target+": "+edge.Description)
+		}
+	}
+	want := []string{
+		"shop -> payments: Charges orders",
+		"shop/api -> shop/db: Stores carts",
+		"shop/api -> payments/gateway: Authorizes cards",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("edges = %q, want %q", got, want)
+	}
+}
+
+func TestBuildArchitectureGraph_People(t *testing.T) {
+	project, _ := entities.NewProject("test-project")
+
+	shop, _ := entities.NewSystem("Shop")
+	api, _ := entities.NewContainer("API")
+	_ = shop.AddContainer(api)
+
+	customer, _ := entities.NewPerson("Customer")
+	customer.External = true
+	customer.AddRelationship("shop", "Places orders with")
+	customer.AddRelationship("shop/api", "Calls")
+	_ = project.AddPerson(customer)
+
+	uc := NewBuildArchitectureGraph()
+	graph, err := uc.Execute(context.Background(), project, []*entities.System{shop})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	node := graph.Nodes["customer"]
+	if node == nil || node.Type != "person" || node.Metadata["external"] != "true" {
+		t.Fatalf("person node = %+v", node)
+	}
+	var got []string
+	for _, edge := range graph.GetOutgoingEdges("customer") {
+		got = append(got, edge.Target+": "+edge.Description)
+	}
+	if want := []string{"shop: Places orders with", "shop/api: Calls"}; !slices.Equal(got, want) {
+		t.Errorf("person edges = %q, want %q", got, want)
+	}
+
+	if report := uc.AnalyzeDependencies(graph); report.SystemsCount != 1 {
+		t.Errorf("SystemsCount = %d, want 1", report.SystemsCount)
+	}
+}
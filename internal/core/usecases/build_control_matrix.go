@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// ControlMatrix traces compliance controls to the elements implementing them.
+type ControlMatrix struct {
+	Controls []ControlTrace `json:"controls"` // By control ID
+}
+
+// ControlTrace is a compliance control and the containers and components
+// whose controls frontmatter lists it.
+type ControlTrace struct {
+	ID           string               `json:"id"`
+	Implementers []ControlImplementer `json:"implementers"` // By qualified ID
+}
+
+// ControlImplementer is a container or component implementing a control.
+type ControlImplementer struct {
+	Type string `json:"type"` // "container" or "component"
+	ID   string `json:"id"`   // Qualified ID, such as "payments/api/auth"
+	Name string `json:"name"`
+}
+
+// BuildControlMatrix builds the traceability matrix of compliance controls,
+// such as SOC 2 or ISO 27001 controls, from the controls frontmatter of
+// containers and components, so auditors can see which parts of the
+// architecture implement each control.
+type BuildControlMatrix struct{}
+
+// NewBuildControlMatrix creates a BuildControlMatrix use case.
+func NewBuildControlMatrix() *BuildControlMatrix {
+	return &BuildControlMatrix{}
+}
+
+// Execute builds the control matrix of systems. Control IDs are trimmed and
+// compared exactly.
+func (uc *BuildControlMatrix) Execute(_ context.Context, systems []*entities.System) *ControlMatrix {
+	implementers := make(map[string][]ControlImplementer)
+	add := func(controls []string, implementer ControlImplementer) {
+		for _, control := range controls {
+			control = strings.TrimSpace(control)
+			if control == "" || slices.Contains(implementers[control], implementer) {
+				continue
+			}
+			implementers[control] = append(implementers[control], implementer)
+		}
+	}
+
+	for _, system := range systems {
+		if system == nil {
+			continue
+		}
+		for _, container := range system.ListContainers() {
+			add(container.Controls, ControlImplementer{
+				Type: "container",
+				ID:   system.ID + "/" + container.ID,
+				Name: container.Name,
+			})
+			for _, component := range container.ListComponents() {
+				add(component.Controls, ControlImplementer{
+					Type: "component",
+					ID:   system.ID + "/" + container.ID + "/" + component.ID,
+					Name: component.Name,
+				})
+			}
+		}
+	}
+
+	matrix := &ControlMatrix{}
+	for _, id := range slices.Sorted(maps.Keys(implementers)) {
+		trace := ControlTrace{ID: id, Implementers: implementers[id]}
+		slices.SortFunc(trace.Implementers, func(a, b ControlImplementer) int { return strings.Compare(a.ID, b.ID) })
+		matrix.Controls = append(matrix.Controls, trace)
+	}
+	return matrix
+}
@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestBuildControlMatrix(t *testing.T) {
+	payments, _ := entities.NewSystem("Payments")
+	api, _ := entities.NewContainer("API")
+	api.Controls = []string{"CC6.1", " A.8.24 "}
+	auth, _ := entities.NewComponent("Auth")
+	auth.Controls = []string{"CC6.1", "CC6.1"}
+	_ = api.AddComponent(auth)
+	_ = payments.AddContainer(api)
+
+	billing, _ := entities.NewSystem("Billing")
+	ledger, _ := entities.NewContainer("Ledger")
+	ledger.Controls = []string{"CC6.1"}
+	_ = billing.AddContainer(ledger)
+
+	matrix := NewBuildControlMatrix().Execute(context.Background(), []*entities.System{payments, nil, billing})
+	if len(matrix.Controls) != 2 || matrix.Controls[0].ID != "A.8.24" || matrix.Controls[1].ID != "CC6.1" {
+		t.Fatalf("Controls = %+v", matrix.Controls)
+	}
+
+	var ids []string
+	for _, implementer := range matrix.Controls[1].Implementers {
+		ids = append(ids, implementer.Type+" "+implementer.ID)
+	}
+	want := []string{"container billing/ledger", "container payments/api", "component payments/api/auth"}
+	if !slices.Equal(ids, want) {
+		t.Errorf("CC6.1 implementers = %q, want %q", ids, want)
+	}
+}
@@ -2,12 +2,19 @@ package usecases
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
 )
@@ -24,6 +31,9 @@ const (
 	FormatPDF OutputFormat = "pdf"
 	// FormatTOON generates TOON (Token-Optimized Object Notation) format for LLM consumption.
 	FormatTOON OutputFormat = "toon"
+	// FormatJSONBundle generates model.json, the whole model in loko's stable
+	// interchange format for external tools (see entities.ModelBundle).
+	FormatJSONBundle OutputFormat = "json"
 )
 
 // BuildDocsOptions configures what output formats to generate.
@@ -31,6 +41,25 @@ type BuildDocsOptions struct {
 	// Formats specifies which output formats to generate.
 	// If empty, defaults to HTML only.
 	Formats []OutputFormat
+
+	// Markdown configures the markdown output.
+	Markdown MarkdownOptions
+
+	// Stages selects the build stages to run; the zero value runs them all.
+	// Diagrams that are not rendered link to the SVG files of a previous build.
+	Stages BuildStages
+}
+
+// MarkdownOptions configures the layout and dialect of the markdown output.
+type MarkdownOptions struct {
+	// PerSystem writes docs/<system-id>/README.md for each system, with its
+	// diagrams embedded, and a README.md linking them, instead of a single
+	// README.md.
+	PerSystem bool
+
+	// GitHubFlavored adds GitHub-flavored Markdown: a documentation task list
+	// per system and a mermaid diagram of the components of each container.
+	GitHubFlavored bool
 }
 
 // DefaultBuildDocsOptions returns the default build options (HTML only).
@@ -46,7 +75,7 @@ func DefaultBuildDocsOptions() BuildDocsOptions {
 // 1. Iterates through all systems, containers, and components
 // 2. Renders D2 diagrams to SVG using the DiagramRenderer (C4 levels 1-3)
 // 3. Calls the SiteBuilder to generate HTML documentation
-// 4. Optionally generates Markdown and PDF outputs
+// 4. Optionally generates Markdown and PDF outputs, and per-system exports
 // 5. Reports progress via ProgressReporter
 type BuildDocs struct {
 	diagramRenderer  DiagramRenderer
@@ -55,6 +84,12 @@ type BuildDocs struct {
 	pdfRenderer      PDFRenderer
 	outputEncoder    OutputEncoder
 	progressReporter ProgressReporter
+	minifier         AssetMinifier
+	timings          *BuildTimings
+	annotator        *AnnotateDiagram
+	relationships    map[string][]entities.Relationship
+	buildCache       BuildCache // Rendered diagrams of earlier builds; optional
+	rendererVersion  string     // Version of diagramRenderer, part of diagram cache keys
 }
 
 // NewBuildDocs creates a new BuildDocs use case with the given adapters.
@@ -88,6 +123,68 @@ func (uc *BuildDocs) WithOutputEncoder(oe OutputEncoder) *BuildDocs {
 	return uc
 }
 
+// WithMinifier minifies the generated HTML site after it is built.
+func (uc *BuildDocs) WithMinifier(m AssetMinifier) *BuildDocs {
+	uc.minifier = m
+	return uc
+}
+
+// WithTimings records the time spent in each build phase into timings.
+func (uc *BuildDocs) WithTimings(timings *BuildTimings) *BuildDocs {
+	uc.timings = timings
+	return uc
+}
+
+// WithDiagramAnnotations adds a legend and metadata footer, as configured in
+// annotator, to every diagram before it is rendered.
+func (uc *BuildDocs) WithDiagramAnnotations(annotator *AnnotateDiagram) *BuildDocs {
+	uc.annotator = annotator
+	return uc
+}
+
+// WithRelationships sets the relationships.toml entries of each system, keyed
+// by system ID, included in the JSON model bundle.
+func (uc *BuildDocs) WithRelationships(relationships map[string][]entities.Relationship) *BuildDocs {
+	uc.relationships = relationships
+	return uc
+}
+
+// WithBuildCache reuses diagrams rendered by earlier builds from the same D2
+// source with the same renderer version, and caches the diagrams it renders.
+// The cache is not used when rendererVersion is empty, since a renderer
+// upgrade could change every diagram.
+func (uc *BuildDocs) WithBuildCache(cache BuildCache, rendererVersion string) *BuildDocs {
+	uc.buildCache = cache
+	uc.rendererVersion = rendererVersion
+	return uc
+}
+
+// DiagramCacheKey returns the build cache key of the diagram rendered from
+// source by the given renderer version: a SHA-256 of both.
+func DiagramCacheKey(rendererVersion, source string) string {
+	sum := sha256.Sum256([]byte(rendererVersion + "\x00" + source))
+	return hex.EncodeToString(sum[:])
+}
+
+// renderDiagram renders source to SVG, or returns the SVG the build cache
+// holds for it with cached set. A failure to cache a rendered diagram is not
+// an error; the diagram is rendered again next time.
+func (uc *BuildDocs) renderDiagram(ctx context.Context, source string) (svg string, cached bool, err error) {
+	if uc.buildCache == nil || uc.rendererVersion == "" {
+		svg, err = uc.diagramRenderer.RenderDiagram(ctx, source)
+		return svg, false, err
+	}
+	key := DiagramCacheKey(uc.rendererVersion, source)
+	if svg, ok := uc.buildCache.Diagram(ctx, key); ok {
+		return svg, true, nil
+	}
+	if svg, err = uc.diagramRenderer.RenderDiagram(ctx, source); err != nil {
+		return "", false, err
+	}
+	_ = uc.buildCache.PutDiagram(ctx, key, svg)
+	return svg, false, nil
+}
+
 // Execute performs a complete documentation build.
 //
 // It:
@@ -115,10 +212,15 @@ func (uc *BuildDocs) Execute(
 	if err := uc.renderDiagrams(ctx, systems, outputDir); err != nil {
 		return err
 	}
+	if err := uc.renderLandscapeDiagrams(ctx, project, systems, outputDir); err != nil {
+		return err
+	}
 
 	// Build the site
 	uc.progressReporter.ReportProgress("Building site", len(systems), len(systems), "Generating HTML documentation...")
+	stopPages := uc.timings.Track(PhasePages)
 	err := uc.siteBuilder.BuildSite(ctx, project, systems, outputDir)
+	stopPages()
 	if err != nil {
 		uc.progressReporter.ReportError(fmt.Errorf("failed to build site: %w", err))
 		return fmt.Errorf("failed to build site: %w", err)
@@ -128,6 +230,123 @@ func (uc *BuildDocs) Execute(
 	return nil
 }
 
+// RebuildPages regenerates the HTML pages without re-rendering diagrams.
+//
+// It is used when only page templates changed: diagrams already rendered into
+// outputDir/diagrams are reused and linked from the regenerated pages.
+func (uc *BuildDocs) RebuildPages(
+	ctx context.Context,
+	project *entities.Project,
+	systems []*entities.System,
+	outputDir string,
+) error {
+	if project == nil {
+		return fmt.Errorf("project cannot be nil")
+	}
+
+	linkRenderedDiagrams(systems, outputDir)
+
+	uc.progressReporter.ReportInfo("Re-rendering pages...")
+	if err := uc.siteBuilder.BuildSite(ctx, project, systems, outputDir); err != nil {
+		uc.progressReporter.ReportError(fmt.Errorf("failed to build site: %w", err))
+		return fmt.Errorf("failed to build site: %w", err)
+	}
+
+	uc.progressReporter.ReportSuccess(fmt.Sprintf("Pages re-rendered in %s", outputDir))
+	return nil
+}
+
+// ExecuteIncremental rebuilds the documentation after a batch of source changes.
+//
+// Only diagrams of the systems listed in dirtySystems (see ChangeSet) are
+// re-rendered; the diagrams of every other system are reused from outputDir.
+// Pages are regenerated for all systems so navigation and indexes stay consistent.
+func (uc *BuildDocs) ExecuteIncremental(
+	ctx context.Context,
+	project *entities.Project,
+	systems []*entities.System,
+	outputDir string,
+	dirtySystems []string,
+) error {
+	if project == nil {
+		return fmt.Errorf("project cannot be nil")
+	}
+	if len(systems) == 0 {
+		uc.progressReporter.ReportInfo("No systems found to build")
+		return nil
+	}
+
+	dirty := make(map[string]bool, len(dirtySystems))
+	for _, id := range dirtySystems {
+		dirty[strings.ToLower(id)] = true
+	}
+
+	var changed, unchanged []*entities.System
+	for _, sys := range systems {
+		if sys == nil {
+			continue
+		}
+		// ChangeSet marks the first directory under src/, which for a
+		// namespaced system is its top-level domain
+		topDir, _, _ := strings.Cut(entities.SystemSourcePath(sys.ID), "/")
+		if dirty[strings.ToLower(sys.ID)] || dirty[strings.ToLower(topDir)] {
+			changed = append(changed, sys)
+		} else {
+			unchanged = append(unchanged, sys)
+		}
+	}
+
+	uc.progressReporter.ReportInfo(fmt.Sprintf("Rebuilding %d of %d systems...", len(changed), len(systems)))
+
+	if err := uc.renderDiagrams(ctx, changed, outputDir); err != nil {
+		return err
+	}
+	linkRenderedDiagrams(unchanged, outputDir)
+	if len(changed) > 0 {
+		if err := uc.renderLandscapeDiagrams(ctx, project, systems, outputDir); err != nil {
+			return err
+		}
+	}
+
+	if err := uc.siteBuilder.BuildSite(ctx, project, systems, outputDir); err != nil {
+		uc.progressReporter.ReportError(fmt.Errorf("failed to build site: %w", err))
+		return fmt.Errorf("failed to build site: %w", err)
+	}
+
+	uc.progressReporter.ReportSuccess(fmt.Sprintf("Documentation rebuilt in %s", outputDir))
+	return nil
+}
+
+// linkRenderedDiagrams sets DiagramPath on entities whose SVG already exists in
+// outputDir, using the same file naming as renderDiagrams.
+func linkRenderedDiagrams(systems []*entities.System, outputDir string) {
+	exists := func(fileName string) (string, bool) {
+		if _, err := os.Stat(filepath.Join(outputDir, "diagrams", fileName)); err != nil {
+			return "", false
+		}
+		return filepath.Join("diagrams", fileName), true
+	}
+
+	for _, sys := range systems {
+		if sys == nil {
+			continue
+		}
+		if p, ok := exists(fmt.Sprintf("%s.svg", sys.ID)); ok && sys.Diagram != nil {
+			sys.DiagramPath = p
+		}
+		for _, container := range sys.Containers {
+			if p, ok := exists(fmt.Sprintf("%s_%s.svg", sys.ID, container.ID)); ok && container.Diagram != nil {
+				container.DiagramPath = p
+			}
+			for _, component := range container.Components {
+				if p, ok := exists(fmt.Sprintf("%s_%s_%s.svg", sys.ID, container.ID, component.ID)); ok && component.Diagram != nil {
+					component.DiagramPath = p
+				}
+			}
+		}
+	}
+}
+
 // ExecuteWithFormats performs a documentation build with specified output formats.
 func (uc *BuildDocs) ExecuteWithFormats(
 	ctx context.Context,
@@ -167,20 +386,40 @@ func (uc *BuildDocs) ExecuteWithFormats(
 		}
 	}
 
-	// First, render diagrams (needed for HTML and PDF)
-	needsDiagrams := containsFormat(formats, FormatHTML) || containsFormat(formats, FormatPDF)
-	if needsDiagrams && len(systems) > 0 {
+	// First, render diagrams (needed for HTML and PDF), unless that stage is
+	// skipped: the pages then link to the SVG files of a previous build.
+	needsDiagrams := containsFormat(formats, FormatHTML) || containsFormat(formats, FormatPDF) || exportsPDF(systems) ||
+		(containsFormat(formats, FormatMarkdown) && options.Markdown.PerSystem)
+	stages := options.Stages
+	switch {
+	case !needsDiagrams || len(systems) == 0:
+	case !stages.Runs(StageDiagrams):
+		linkRenderedDiagrams(systems, outputDir)
+	default:
 		if err := uc.renderDiagrams(ctx, systems, outputDir); err != nil {
 			return err
 		}
+		if err := uc.renderLandscapeDiagrams(ctx, project, systems, outputDir); err != nil {
+			return err
+		}
 	}
 
-	// Build each format
+	// Build each format. Only the HTML site has stages of its own; the other
+	// formats are built with its pages.
 	for _, format := range formats {
+		if format != FormatHTML && !stages.Runs(StagePages) {
+			continue
+		}
 		switch format {
 		case FormatHTML:
+			if !stages.Runs(StageAssets) && !stages.Runs(StagePages) && !stages.Runs(StageSearchIndex) {
+				continue
+			}
 			uc.progressReporter.ReportInfo("Building HTML documentation...")
-			if err := uc.siteBuilder.BuildSite(ctx, project, systems, outputDir); err != nil {
+			stopPages := uc.timings.Track(PhasePages)
+			err := uc.siteBuilder.BuildSite(ctx, project, systems, outputDir)
+			stopPages()
+			if err != nil {
 				uc.progressReporter.ReportError(fmt.Errorf("failed to build HTML: %w", err))
 				return fmt.Errorf("failed to build HTML: %w", err)
 			}
@@ -188,21 +427,29 @@ func (uc *BuildDocs) ExecuteWithFormats(
 
 		case FormatMarkdown:
 			uc.progressReporter.ReportInfo("Building Markdown documentation...")
-			content, err := uc.markdownBuilder.BuildMarkdown(ctx, project, systems)
+			stopMarkdown := uc.timings.Track(PhaseMarkdown)
+			files, err := uc.markdownBuilder.BuildMarkdownFiles(ctx, project, systems, options.Markdown)
+			stopMarkdown()
 			if err != nil {
 				uc.progressReporter.ReportError(fmt.Errorf("failed to build markdown: %w", err))
 				return fmt.Errorf("failed to build markdown: %w", err)
 			}
 
-			// Write README.md
-			readmePath := filepath.Join(outputDir, "README.md")
-			if err := os.MkdirAll(outputDir, 0755); err != nil {
-				return fmt.Errorf("failed to create output directory: %w", err)
+			// Write README.md and, per system, docs/<system-id>/README.md
+			for _, name := range slices.Sorted(maps.Keys(files)) {
+				filePath := filepath.Join(outputDir, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+					return fmt.Errorf("failed to create output directory: %w", err)
+				}
+				if err := os.WriteFile(filePath, []byte(files[name]), 0644); err != nil {
+					return fmt.Errorf("failed to write %s: %w", name, err)
+				}
 			}
-			if err := os.WriteFile(readmePath, []byte(content), 0644); err != nil {
-				return fmt.Errorf("failed to write README.md: %w", err)
+			if len(files) == 1 {
+				uc.progressReporter.ReportSuccess("Markdown documentation built: README.md")
+			} else {
+				uc.progressReporter.ReportSuccess(fmt.Sprintf("Markdown documentation built: README.md and %d system files", len(files)-1))
 			}
-			uc.progressReporter.ReportSuccess("Markdown documentation built: README.md")
 
 		case FormatPDF:
 			uc.progressReporter.ReportInfo("Building PDF documentation...")
@@ -216,7 +463,10 @@ func (uc *BuildDocs) ExecuteWithFormats(
 			}
 
 			pdfPath := filepath.Join(outputDir, "architecture.pdf")
-			if err := uc.pdfRenderer.RenderPDF(ctx, htmlPath, pdfPath); err != nil {
+			stopPDF := uc.timings.Track(PhasePDF)
+			err := uc.pdfRenderer.RenderPDF(ctx, htmlPath, pdfPath)
+			stopPDF()
+			if err != nil {
 				uc.progressReporter.ReportError(fmt.Errorf("failed to build PDF: %w", err))
 				return fmt.Errorf("failed to build PDF: %w", err)
 			}
@@ -224,6 +474,7 @@ func (uc *BuildDocs) ExecuteWithFormats(
 
 		case FormatTOON:
 			uc.progressReporter.ReportInfo("Building TOON documentation...")
+			stopTOON := uc.timings.Track(PhaseTOON)
 			// Build architecture graph for TOON export
 			graphBuilder := NewBuildArchitectureGraph()
 			graph, err := graphBuilder.Execute(ctx, project, systems)
@@ -247,7 +498,32 @@ func (uc *BuildDocs) ExecuteWithFormats(
 			if err := os.WriteFile(toonPath, toonData, 0644); err != nil {
 				return fmt.Errorf("failed to write architecture.toon: %w", err)
 			}
+			stopTOON()
 			uc.progressReporter.ReportSuccess("TOON documentation built: architecture.toon")
+
+		case FormatJSONBundle:
+			uc.progressReporter.ReportInfo("Building JSON model bundle...")
+			if err := uc.writeModelBundle(ctx, project, systems, outputDir); err != nil {
+				uc.progressReporter.ReportError(err)
+				return err
+			}
+			uc.progressReporter.ReportSuccess("JSON model bundle built: model.json")
+		}
+	}
+
+	if stages.Runs(StageExports) {
+		stopExports := uc.timings.Track(PhaseExports)
+		err := uc.exportSystems(ctx, project, systems, outputDir)
+		stopExports()
+		if err != nil {
+			uc.progressReporter.ReportError(err)
+			return err
+		}
+	}
+
+	if uc.minifier != nil && slices.Contains(formats, FormatHTML) && stages.Runs(StageMinify) {
+		if err := uc.minifySite(ctx, outputDir); err != nil {
+			return err
 		}
 	}
 
@@ -255,6 +531,46 @@ func (uc *BuildDocs) ExecuteWithFormats(
 	return nil
 }
 
+// writeModelBundle writes the model bundle of the project to model.json.
+func (uc *BuildDocs) writeModelBundle(ctx context.Context, project *entities.Project, systems []*entities.System, outputDir string) error {
+	defer uc.timings.Track(PhaseJSONBundle)()
+
+	bundle, err := NewBuildModelBundle().Execute(ctx, project, systems, uc.relationships)
+	if err != nil {
+		return fmt.Errorf("failed to build model bundle: %w", err)
+	}
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode model bundle: %w", err)
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "model.json"), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write model.json: %w", err)
+	}
+	return nil
+}
+
+// minifySite minifies the generated site in place and reports the size saved.
+func (uc *BuildDocs) minifySite(ctx context.Context, outputDir string) error {
+	defer uc.timings.Track(PhaseMinify)()
+
+	uc.progressReporter.ReportInfo("Minifying site assets...")
+	before, after, err := uc.minifier.MinifyDir(ctx, outputDir)
+	if err != nil {
+		uc.progressReporter.ReportError(fmt.Errorf("failed to minify site: %w", err))
+		return fmt.Errorf("failed to minify site: %w", err)
+	}
+
+	saved := 0.0
+	if before > 0 {
+		saved = float64(before-after) / float64(before) * 100
+	}
+	uc.progressReporter.ReportSuccess(fmt.Sprintf("Minified site: %d KB → %d KB (-%.1f%%)", before/1024, after/1024, saved))
+	return nil
+}
+
 // diagramJob represents a single diagram rendering task.
 type diagramJob struct {
 	source        string // D2 source code to render
@@ -267,6 +583,7 @@ type diagramJob struct {
 type diagramResult struct {
 	index      int
 	svgContent string
+	cached     bool // Taken from the build cache rather than rendered
 	err        error
 }
 
@@ -296,7 +613,7 @@ func (uc *BuildDocs) renderDiagrams(
 			setters = append(setters, func(path string) { s.DiagramPath = path })
 		}
 
-		for _, container := range sys.Containers {
+		for _, container := range sys.ListContainers() {
 			if container.Diagram != nil {
 				fileName := fmt.Sprintf("%s_%s.svg", sys.ID, container.ID)
 				jobs = append(jobs, diagramJob{
@@ -308,7 +625,7 @@ func (uc *BuildDocs) renderDiagrams(
 				setters = append(setters, func(path string) { c.DiagramPath = path })
 			}
 
-			for _, component := range container.Components {
+			for _, component := range container.ListComponents() {
 				if component.Diagram != nil {
 					enhancedSource, err := enhancer.Execute(component, container, sys)
 					if err != nil {
@@ -333,6 +650,15 @@ func (uc *BuildDocs) renderDiagrams(
 		return nil
 	}
 
+	// Diagram file writes happen on this goroutine while workers keep rendering;
+	// their time is reported separately and excluded from rendering.
+	start := time.Now()
+	var writeTime time.Duration
+	defer func() {
+		uc.timings.Add(PhaseDiagrams, time.Since(start)-writeTime)
+		uc.timings.Add(PhaseDiagramWrites, writeTime)
+	}()
+
 	uc.progressReporter.ReportInfo(fmt.Sprintf("Rendering %d diagrams...", len(jobs)))
 
 	// Create diagrams directory once
@@ -344,18 +670,34 @@ func (uc *BuildDocs) renderDiagrams(
 	// Determine worker count
 	numWorkers := min(8, len(jobs))
 
-	// Channel-based worker pool
+	// Channel-based worker pool. The result channel is bounded by the worker
+	// count so at most a handful of rendered SVGs are held in memory at once;
+	// each is written to disk and dropped before more results are accepted.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // unblocks workers if we return early on an error
+
 	jobCh := make(chan int, len(jobs))
-	resultCh := make(chan diagramResult, len(jobs))
+	resultCh := make(chan diagramResult, numWorkers)
 
 	// Start workers
 	var wg sync.WaitGroup
 	for range numWorkers {
 		wg.Go(func() {
 			for idx := range jobCh {
+				if ctx.Err() != nil {
+					return
+				}
 				job := jobs[idx]
-				svgContent, err := uc.diagramRenderer.RenderDiagram(ctx, job.source)
-				resultCh <- diagramResult{index: idx, svgContent: svgContent, err: err}
+				source := job.source
+				if uc.annotator != nil {
+					source = uc.annotator.Execute(source, job.label)
+				}
+				svgContent, cached, err := uc.renderDiagram(ctx, source)
+				select {
+				case resultCh <- diagramResult{index: idx, svgContent: svgContent, cached: cached, err: err}:
+				case <-ctx.Done():
+					return
+				}
 			}
 		})
 	}
@@ -373,9 +715,12 @@ func (uc *BuildDocs) renderDiagrams(
 	}()
 
 	// Collect results
-	completed := 0
+	completed, cached := 0, 0
 	for result := range resultCh {
 		completed++
+		if result.cached {
+			cached++
+		}
 		job := jobs[result.index]
 
 		if result.err != nil {
@@ -389,6 +734,7 @@ func (uc *BuildDocs) renderDiagrams(
 		)
 
 		// Write SVG to disk
+		writeStart := time.Now()
 		diagramPath := filepath.Join(diagramsDir, job.fileName)
 		if err := os.WriteFile(diagramPath, []byte(result.svgContent), 0644); err != nil {
 			return fmt.Errorf("failed to save diagram for %s: %w", job.label, err)
@@ -403,12 +749,134 @@ func (uc *BuildDocs) renderDiagrams(
 				return fmt.Errorf("failed to save D2 source for %s: %w", job.label, err)
 			}
 		}
+		writeTime += time.Since(writeStart)
 
 		// Set diagram path on entity
 		setters[result.index](filepath.Join("diagrams", job.fileName))
 	}
 
-	uc.progressReporter.ReportProgress("Diagrams", len(jobs), len(jobs), "All diagrams rendered")
+	// Workers stop early when the caller's context is cancelled.
+	if completed < len(jobs) {
+		return fmt.Errorf("diagram rendering interrupted: %w", ctx.Err())
+	}
+
+	message := "All diagrams rendered"
+	if cached > 0 {
+		message = fmt.Sprintf("All diagrams rendered, %d unchanged since the last build", cached)
+	}
+	uc.progressReporter.ReportProgress("Diagrams", len(jobs), len(jobs), message)
+	return nil
+}
+
+// renderLandscapeDiagrams renders the diagrams spanning systems: the system
+// landscape diagram, from the relationships of their elements rolled up to
+// systems, when there are several systems, and the domain landscape diagram
+// and the diagram of every domain when systems are grouped into domains; see
+// GenerateRollupDiagram and GenerateDomainDiagram.
+func (uc *BuildDocs) renderLandscapeDiagrams(
+	ctx context.Context,
+	project *entities.Project,
+	systems []*entities.System,
+	outputDir string,
+) error {
+	domains := entities.BuildDomains(systems)
+	if len(domains) == 0 && len(systems) < 2 {
+		return nil
+	}
+
+	graph, err := NewBuildArchitectureGraphWithRelRepo(loadedRelationships(uc.relationships)).Execute(ctx, project, systems)
+	if err != nil {
+		return fmt.Errorf("failed to build architecture graph: %w", err)
+	}
+
+	diagramsDir := filepath.Join(outputDir, "diagrams")
+	if err := os.MkdirAll(diagramsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create diagrams directory: %w", err)
+	}
+
+	if len(systems) > 1 {
+		if err := uc.renderSystemLandscape(ctx, graph, filepath.Join(diagramsDir, LandscapeDiagramFile)); err != nil {
+			return err
+		}
+	}
+	if len(domains) == 0 {
+		return nil
+	}
+
+	ids := []string{""}
+	for _, domain := range domains {
+		domain.Walk(func(d *entities.Domain) { ids = append(ids, d.ID) })
+	}
+	for i, id := range ids {
+		label := "domain landscape"
+		if id != "" {
+			label = fmt.Sprintf("domain %s", id)
+		}
+		source := GenerateDomainDiagram(id, systems, graph)
+		if err := uc.writeLandscapeDiagram(ctx, source, label, filepath.Join(diagramsDir, DomainDiagramFile(id))); err != nil {
+			return err
+		}
+		uc.progressReporter.ReportProgress(fmt.Sprintf("Rendered %s", label), i+1, len(ids), "Rendering domain diagrams")
+	}
+	return nil
+}
+
+// renderSystemLandscape renders the relationships between systems rolled up
+// from graph to path. Without relationships between systems there is nothing
+// to draw, and without a diagram renderer the landscape is skipped with a
+// warning rather than failing the build; either way a landscape left by an
+// earlier build is removed.
+func (uc *BuildDocs) renderSystemLandscape(ctx context.Context, graph *entities.ArchitectureGraph, path string) error {
+	edges, err := RollupGraph(graph, RollupSystem)
+	if err != nil {
+		return err
+	}
+	if len(edges) == 0 || uc.diagramRenderer == nil || !uc.diagramRenderer.IsAvailable() {
+		if len(edges) > 0 {
+			uc.progressReporter.ReportInfo("Warning: d2 not available, skipping the system landscape diagram")
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove stale system landscape: %w", err)
+		}
+		return nil
+	}
+
+	source, err := GenerateRollupDiagram(graph, RollupSystem)
+	if err != nil {
+		return err
+	}
+	return uc.writeLandscapeDiagram(ctx, source, "system landscape", path)
+}
+
+// loadedRelationships serves relationships.toml entries already loaded, keyed
+// by system ID, to the graph builder.
+type loadedRelationships map[string][]entities.Relationship
+
+func (r loadedRelationships) LoadRelationships(_ context.Context, _, systemID string) ([]entities.Relationship, error) {
+	return r[systemID], nil
+}
+
+func (r loadedRelationships) SaveRelationships(context.Context, string, string, []entities.Relationship) error {
+	return fmt.Errorf("relationships are read-only during a build")
+}
+
+func (r loadedRelationships) DeleteElement(context.Context, string, string, string) error {
+	return fmt.Errorf("relationships are read-only during a build")
+}
+
+// writeLandscapeDiagram annotates and renders the D2 source of a diagram
+// spanning systems and saves the SVG to path.
+func (uc *BuildDocs) writeLandscapeDiagram(ctx context.Context, source, label, path string) error {
+	if uc.annotator != nil {
+		source = uc.annotator.Execute(source, label)
+	}
+	svgContent, _, err := uc.renderDiagram(ctx, source)
+	if err != nil {
+		return fmt.Errorf("failed to render diagram for %s: %w", label, err)
+	}
+	if err := os.WriteFile(path, []byte(svgContent), 0644); err != nil {
+		return fmt.Errorf("failed to save diagram for %s: %w", label, err)
+	}
 	return nil
 }
 
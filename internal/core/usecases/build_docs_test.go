@@ -2,7 +2,12 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -522,6 +527,20 @@ func (m *MockMarkdownBuilder) BuildMarkdown(ctx context.Context, project *entiti
 	return "# Test Markdown", nil
 }
 
+func (m *MockMarkdownBuilder) BuildMarkdownFiles(ctx context.Context, project *entities.Project, systems []*entities.System, opts MarkdownOptions) (map[string]string, error) {
+	content, err := m.BuildMarkdown(ctx, project, systems)
+	if err != nil {
+		return nil, err
+	}
+	files := map[string]string{"README.md": content}
+	if opts.PerSystem {
+		for _, sys := range systems {
+			files["docs/"+sys.ID+"/README.md"] = "# " + sys.Name
+		}
+	}
+	return files, nil
+}
+
 func (m *MockMarkdownBuilder) BuildSystemMarkdown(ctx context.Context, system *entities.System, containers []*entities.Container) (string, error) {
 	if m.buildSystemMarkdownFunc != nil {
 		return m.buildSystemMarkdownFunc(ctx, system, containers)
@@ -1028,3 +1047,457 @@ func TestGenerateComponentTable_NilComponents(t *testing.T) {
 		t.Errorf("Expected empty string for nil components, got: %q", result)
 	}
 }
+
+// TestBuildDocsRebuildPages tests that page-only rebuilds skip diagram rendering
+// and link diagrams that were already rendered.
+func TestBuildDocsRebuildPages(t *testing.T) {
+	renderer := &MockDiagramRenderer{}
+	siteBuilder := &MockSiteBuilder{}
+	uc := NewBuildDocs(renderer, siteBuilder, &MockProgressReporter{})
+
+	outputDir := t.TempDir()
+	diagramsDir := filepath.Join(outputDir, "diagrams")
+	if err := os.MkdirAll(diagramsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(diagramsDir, "sys.svg"), []byte("<svg></svg>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	system := &entities.System{
+		ID:         "sys",
+		Name:       "Sys",
+		Diagram:    &entities.Diagram{Source: "a -> b"},
+		Containers: map[string]*entities.Container{"api": {ID: "api", Name: "API", Diagram: &entities.Diagram{Source: "x"}}},
+	}
+	project := &entities.Project{Name: "p"}
+
+	if err := uc.RebuildPages(context.Background(), project, []*entities.System{system}, outputDir); err != nil {
+		t.Fatalf("RebuildPages failed: %v", err)
+	}
+	if renderer.renderCount.Load() != 0 {
+		t.Errorf("expected no diagram renders, got %d", renderer.renderCount.Load())
+	}
+	if siteBuilder.buildCount != 1 {
+		t.Errorf("expected site to be built once, got %d", siteBuilder.buildCount)
+	}
+	if system.DiagramPath != filepath.Join("diagrams", "sys.svg") {
+		t.Errorf("expected system diagram to be linked, got %q", system.DiagramPath)
+	}
+	if system.Containers["api"].DiagramPath != "" {
+		t.Errorf("expected unrendered container diagram to stay unlinked, got %q", system.Containers["api"].DiagramPath)
+	}
+}
+
+func TestBuildDocsExecuteIncremental(t *testing.T) {
+	renderer := &MockDiagramRenderer{}
+	siteBuilder := &MockSiteBuilder{}
+	uc := NewBuildDocs(renderer, siteBuilder, &MockProgressReporter{})
+
+	outputDir := t.TempDir()
+	diagramsDir := filepath.Join(outputDir, "diagrams")
+	if err := os.MkdirAll(diagramsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(diagramsDir, "billing.svg"), []byte("<svg></svg>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	orders := &entities.System{
+		ID:      "orders",
+		Name:    "Orders",
+		Diagram: &entities.Diagram{Source: "a -> b"},
+		Containers: map[string]*entities.Container{
+			"api": {ID: "api", Name: "API", Diagram: &entities.Diagram{Source: "x -> y"}},
+		},
+	}
+	billing := &entities.System{
+		ID:      "billing",
+		Name:    "Billing",
+		Diagram: &entities.Diagram{Source: "c -> d"},
+	}
+	project := &entities.Project{Name: "p"}
+
+	err := uc.ExecuteIncremental(context.Background(), project, []*entities.System{orders, billing}, outputDir, []string{"orders"})
+	if err != nil {
+		t.Fatalf("ExecuteIncremental failed: %v", err)
+	}
+	if got := renderer.renderCount.Load(); got != 2 {
+		t.Errorf("expected 2 diagram renders for the dirty system, got %d", got)
+	}
+	if siteBuilder.buildCount != 1 {
+		t.Errorf("expected site to be built once, got %d", siteBuilder.buildCount)
+	}
+	if billing.DiagramPath != filepath.Join("diagrams", "billing.svg") {
+		t.Errorf("expected clean system diagram to be reused, got %q", billing.DiagramPath)
+	}
+	if orders.DiagramPath == "" {
+		t.Error("expected dirty system diagram to be rendered")
+	}
+}
+
+// mockBuildCache is an in-memory BuildCache.
+type mockBuildCache struct {
+	mu       sync.Mutex
+	diagrams map[string]string
+	pages    map[string]map[string]string
+}
+
+func (m *mockBuildCache) Diagram(_ context.Context, key string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	svg, ok := m.diagrams[key]
+	return svg, ok
+}
+
+func (m *mockBuildCache) PutDiagram(_ context.Context, key, svg string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.diagrams == nil {
+		m.diagrams = make(map[string]string)
+	}
+	m.diagrams[key] = svg
+	return nil
+}
+
+func (m *mockBuildCache) Pages(_ context.Context, outputDir string) map[string]string {
+	return m.pages[outputDir]
+}
+
+func (m *mockBuildCache) PutPages(_ context.Context, outputDir string, pages map[string]string) error {
+	if m.pages == nil {
+		m.pages = make(map[string]map[string]string)
+	}
+	m.pages[outputDir] = pages
+	return nil
+}
+
+func (m *mockBuildCache) Clear(context.Context) error {
+	m.diagrams, m.pages = nil, nil
+	return nil
+}
+
+func TestBuildDocsDiagramCache(t *testing.T) {
+	newSystem := func(source string) *entities.System {
+		return &entities.System{
+			ID:      "orders",
+			Name:    "Orders",
+			Diagram: &entities.Diagram{Source: source},
+			Containers: map[string]*entities.Container{
+				"api": {ID: "api", Name: "API", Diagram: &entities.Diagram{Source: "x -> y"}},
+			},
+		}
+	}
+	project := &entities.Project{Name: "p"}
+	cache := &mockBuildCache{}
+	renderer := &MockDiagramRenderer{}
+	build := func(system *entities.System, version string) {
+		t.Helper()
+		uc := NewBuildDocs(renderer, &MockSiteBuilder{}, &MockProgressReporter{}).WithBuildCache(cache, version)
+		if err := uc.Execute(context.Background(), project, []*entities.System{system}, t.TempDir()); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if system.DiagramPath == "" {
+			t.Error("expected the system diagram to be linked")
+		}
+	}
+
+	build(newSystem("a -> b"), "v0.7.0")
+	if got := renderer.renderCount.Load(); got != 2 {
+		t.Fatalf("first build rendered %d diagrams, want 2", got)
+	}
+
+	// Only the changed diagram is rendered again
+	build(newSystem("a -> c"), "v0.7.0")
+	if got := renderer.renderCount.Load(); got != 3 {
+		t.Errorf("second build rendered %d diagrams in total, want 3", got)
+	}
+
+	// A renderer upgrade renders every diagram again
+	build(newSystem("a -> c"), "v0.7.1")
+	if got := renderer.renderCount.Load(); got != 5 {
+		t.Errorf("upgraded build rendered %d diagrams in total, want 5", got)
+	}
+
+	// Without a renderer version the cache is not used
+	build(newSystem("a -> c"), "")
+	if got := renderer.renderCount.Load(); got != 7 {
+		t.Errorf("unversioned build rendered %d diagrams in total, want 7", got)
+	}
+}
+
+func TestBuildDocsWithTimings(t *testing.T) {
+	timings := NewBuildTimings()
+	uc := NewBuildDocs(&MockDiagramRenderer{}, &MockSiteBuilder{}, &MockProgressReporter{}).
+		WithTimings(timings)
+
+	system := &entities.System{ID: "sys", Name: "Sys", Diagram: &entities.Diagram{Source: "a -> b"}}
+	project := &entities.Project{Name: "p"}
+
+	err := uc.ExecuteWithFormats(context.Background(), project, []*entities.System{system}, t.TempDir(), DefaultBuildDocsOptions())
+	if err != nil {
+		t.Fatalf("ExecuteWithFormats failed: %v", err)
+	}
+
+	recorded := make(map[string]bool)
+	for _, p := range timings.Phases() {
+		recorded[p.Phase] = true
+	}
+	for _, phase := range []string{PhaseDiagrams, PhaseDiagramWrites, PhasePages} {
+		if !recorded[phase] {
+			t.Errorf("expected phase %q to be recorded, got %v", phase, timings.Phases())
+		}
+	}
+}
+
+func TestBuildDocsRenderDiagramsStopsOnError(t *testing.T) {
+	var systems []*entities.System
+	for i := range 100 {
+		id := fmt.Sprintf("sys-%d", i)
+		systems = append(systems, &entities.System{ID: id, Name: id, Diagram: &entities.Diagram{Source: "a -> b"}})
+	}
+
+	renderer := &MockDiagramRenderer{err: errors.New("d2 failed")}
+	uc := NewBuildDocs(renderer, &MockSiteBuilder{}, &MockProgressReporter{})
+
+	if err := uc.renderDiagrams(context.Background(), systems, t.TempDir()); err == nil {
+		t.Fatal("expected render error")
+	}
+	if got := renderer.renderCount.Load(); got >= int64(len(systems)) {
+		t.Errorf("expected workers to stop after the first error, rendered %d diagrams", got)
+	}
+}
+
+func TestBuildDocsRenderDiagramsCancelled(t *testing.T) {
+	systems := []*entities.System{
+		{ID: "a", Name: "A", Diagram: &entities.Diagram{Source: "a -> b"}},
+		{ID: "b", Name: "B", Diagram: &entities.Diagram{Source: "a -> b"}},
+	}
+	uc := NewBuildDocs(&MockDiagramRenderer{}, &MockSiteBuilder{}, &MockProgressReporter{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := uc.renderDiagrams(ctx, systems, t.TempDir())
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// mockMinifier records the directory it was asked to minify.
+type mockMinifier struct {
+	dir string
+}
+
+func (m *mockMinifier) MinifyDir(_ context.Context, dir string) (int64, int64, error) {
+	m.dir = dir
+	return 2048, 1024, nil
+}
+
+func TestBuildDocsWithMinifier(t *testing.T) {
+	minifier := &mockMinifier{}
+	uc := NewBuildDocs(&MockDiagramRenderer{}, &MockSiteBuilder{}, &MockProgressReporter{}).
+		WithMinifier(minifier)
+
+	outputDir := t.TempDir()
+	system := &entities.System{ID: "sys", Name: "Sys"}
+	err := uc.ExecuteWithFormats(context.Background(), &entities.Project{Name: "p"}, []*entities.System{system}, outputDir, DefaultBuildDocsOptions())
+	if err != nil {
+		t.Fatalf("ExecuteWithFormats failed: %v", err)
+	}
+	if minifier.dir != outputDir {
+		t.Errorf("expected site in %s to be minified, got %q", outputDir, minifier.dir)
+	}
+}
+
+func TestBuildDocsStages(t *testing.T) {
+	newSystem := func() *entities.System {
+		return &entities.System{ID: "sys", Name: "Sys", Diagram: &entities.Diagram{Source: "a -> b"}}
+	}
+
+	t.Run("only diagrams", func(t *testing.T) {
+		renderer := &MockDiagramRenderer{}
+		siteBuilder := &MockSiteBuilder{}
+		minifier := &mockMinifier{}
+		uc := NewBuildDocs(renderer, siteBuilder, &MockProgressReporter{}).WithMinifier(minifier)
+
+		stages, err := ParseBuildStages([]string{"diagrams"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		options := BuildDocsOptions{Formats: []OutputFormat{FormatHTML}, Stages: stages}
+		if err := uc.ExecuteWithFormats(context.Background(), &entities.Project{Name: "p"}, []*entities.System{newSystem()}, t.TempDir(), options); err != nil {
+			t.Fatalf("ExecuteWithFormats failed: %v", err)
+		}
+		if renderer.renderCount.Load() == 0 {
+			t.Error("expected diagrams to be rendered")
+		}
+		if siteBuilder.buildCount != 0 {
+			t.Errorf("expected no site build, got %d", siteBuilder.buildCount)
+		}
+		if minifier.dir != "" {
+			t.Error("expected no minification")
+		}
+	})
+
+	t.Run("skip diagrams", func(t *testing.T) {
+		renderer := &MockDiagramRenderer{}
+		siteBuilder := &MockSiteBuilder{}
+		uc := NewBuildDocs(renderer, siteBuilder, &MockProgressReporter{})
+
+		outputDir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(outputDir, "diagrams"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(outputDir, "diagrams", "sys.svg"), []byte("<svg></svg>"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		stages, err := ParseBuildStages(nil, []string{"diagrams"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		system := newSystem()
+		options := BuildDocsOptions{Formats: []OutputFormat{FormatHTML}, Stages: stages}
+		if err := uc.ExecuteWithFormats(context.Background(), &entities.Project{Name: "p"}, []*entities.System{system}, outputDir, options); err != nil {
+			t.Fatalf("ExecuteWithFormats failed: %v", err)
+		}
+		if renderer.renderCount.Load() != 0 {
+			t.Errorf("expected no diagram renders, got %d", renderer.renderCount.Load())
+		}
+		if siteBuilder.buildCount != 1 {
+			t.Errorf("expected site to be built once, got %d", siteBuilder.buildCount)
+		}
+		if system.DiagramPath != filepath.Join("diagrams", "sys.svg") {
+			t.Errorf("expected previous diagram to be linked, got %q", system.DiagramPath)
+		}
+	})
+}
+
+func TestBuildDocsSystemExports(t *testing.T) {
+	var pdfs []string
+	siteBuilder := &MockSiteBuilder{}
+	progress := &MockProgressReporter{}
+	uc := NewBuildDocs(&MockDiagramRenderer{}, siteBuilder, progress).
+		WithMarkdownBuilder(&MockMarkdownBuilder{}).
+		WithPDFRenderer(&MockPDFRenderer{renderPDFFunc: func(_ context.Context, htmlPath, outputPath string) error {
+			pdfs = append(pdfs, htmlPath+" -> "+outputPath)
+			return nil
+		}})
+
+	outputDir := t.TempDir()
+	systems := []*entities.System{
+		{ID: "payments", Name: "Payments", Exports: []string{"pdf", "Markdown", "docx"}},
+		{ID: "orders", Name: "Orders"},
+	}
+	opts := BuildDocsOptions{Formats: []OutputFormat{FormatTOON}}
+	if err := uc.WithOutputEncoder(&MockOutputEncoder{}).ExecuteWithFormats(context.Background(), &entities.Project{Name: "p"}, systems, outputDir, opts); err != nil {
+		t.Fatalf("ExecuteWithFormats failed: %v", err)
+	}
+
+	if content, err := os.ReadFile(filepath.Join(outputDir, "exports", "payments.md")); err != nil || string(content) != "# Test System Markdown" {
+		t.Errorf("exports/payments.md = %q, err %v", content, err)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "exports", "orders.md")); !os.IsNotExist(err) {
+		t.Error("exported a system that requested no exports")
+	}
+	want := filepath.Join(outputDir, "systems", "payments.html") + " -> " + filepath.Join(outputDir, "exports", "payments.pdf")
+	if len(pdfs) != 1 || pdfs[0] != want {
+		t.Errorf("rendered PDFs = %q, want [%q]", pdfs, want)
+	}
+	// Without HTML output the site is built for the PDF's system page
+	if siteBuilder.buildCount != 1 {
+		t.Errorf("site built %d times, want 1", siteBuilder.buildCount)
+	}
+	if !slices.ContainsFunc(progress.infos, func(info string) bool { return strings.Contains(info, `"docx"`) }) {
+		t.Errorf("no warning about the unknown export format in %q", progress.infos)
+	}
+
+	// Without veve-cli, PDF exports are skipped with a warning
+	pdfs = nil
+	uc.WithPDFRenderer(&MockPDFRenderer{isAvailableFunc: func() bool { return false }})
+	if err := uc.ExecuteWithFormats(context.Background(), &entities.Project{Name: "p"}, systems, t.TempDir(), DefaultBuildDocsOptions()); err != nil {
+		t.Fatalf("ExecuteWithFormats without veve-cli failed: %v", err)
+	}
+	if len(pdfs) != 0 {
+		t.Errorf("rendered PDFs without veve-cli: %q", pdfs)
+	}
+}
+
+func TestBuildDocsMarkdownPerSystem(t *testing.T) {
+	renderer := &MockDiagramRenderer{}
+	uc := NewBuildDocs(renderer, &MockSiteBuilder{}, &MockProgressReporter{}).
+		WithMarkdownBuilder(&MockMarkdownBuilder{})
+
+	outputDir := t.TempDir()
+	systems := []*entities.System{{ID: "payments", Name: "Payments", Diagram: &entities.Diagram{Source: "a -> b"}}}
+	opts := BuildDocsOptions{Formats: []OutputFormat{FormatMarkdown}, Markdown: MarkdownOptions{PerSystem: true}}
+	if err := uc.ExecuteWithFormats(context.Background(), &entities.Project{Name: "p"}, systems, outputDir, opts); err != nil {
+		t.Fatalf("ExecuteWithFormats failed: %v", err)
+	}
+
+	for _, name := range []string{"README.md", filepath.Join("docs", "payments", "README.md")} {
+		if _, err := os.Stat(filepath.Join(outputDir, name)); err != nil {
+			t.Errorf("%s not written: %v", name, err)
+		}
+	}
+	// The system files embed the diagrams, so they are rendered
+	if renderer.renderCount.Load() == 0 {
+		t.Error("diagrams not rendered for per-system markdown")
+	}
+}
+
+func TestBuildDocsSystemLandscape(t *testing.T) {
+	systems := func(related bool) []*entities.System {
+		shop, _ := entities.NewSystem("Shop")
+		billing, _ := entities.NewSystem("Billing")
+		if related {
+			shop.Relationships = map[string]string{billing.ID: "records payments in"}
+		}
+		return []*entities.System{shop, billing}
+	}
+	landscape := func(outputDir string) bool {
+		_, err := os.Stat(filepath.Join(outputDir, "diagrams", LandscapeDiagramFile))
+		return err == nil
+	}
+
+	t.Run("no relationships between systems", func(t *testing.T) {
+		renderer := &MockDiagramRenderer{}
+		outputDir := t.TempDir()
+		uc := NewBuildDocs(renderer, &MockSiteBuilder{}, &MockProgressReporter{})
+		if err := uc.Execute(context.Background(), &entities.Project{Name: "p"}, systems(false), outputDir); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if renderer.renderCount.Load() != 0 || landscape(outputDir) {
+			t.Errorf("expected no landscape, got %d renders", renderer.renderCount.Load())
+		}
+	})
+
+	t.Run("relationships between systems", func(t *testing.T) {
+		renderer := &MockDiagramRenderer{}
+		outputDir := t.TempDir()
+		uc := NewBuildDocs(renderer, &MockSiteBuilder{}, &MockProgressReporter{})
+		if err := uc.Execute(context.Background(), &entities.Project{Name: "p"}, systems(true), outputDir); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if renderer.renderCount.Load() != 1 || !landscape(outputDir) {
+			t.Errorf("expected the landscape to be rendered once, got %d renders", renderer.renderCount.Load())
+		}
+	})
+
+	t.Run("no d2 binary", func(t *testing.T) {
+		renderer := &MockDiagramRenderer{err: errors.New("d2 binary not found in PATH")}
+		reporter := &MockProgressReporter{}
+		outputDir := t.TempDir()
+		uc := NewBuildDocs(renderer, &MockSiteBuilder{}, reporter)
+		if err := uc.Execute(context.Background(), &entities.Project{Name: "p"}, systems(true), outputDir); err != nil {
+			t.Fatalf("Execute failed without d2: %v", err)
+		}
+		if landscape(outputDir) {
+			t.Error("expected no landscape without d2")
+		}
+		if !slices.ContainsFunc(reporter.infos, func(info string) bool { return strings.Contains(info, "landscape") }) {
+			t.Errorf("expected a warning about the skipped landscape, got %v", reporter.infos)
+		}
+	})
+}
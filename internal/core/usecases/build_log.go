@@ -0,0 +1,104 @@
+package usecases
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// BuildLogReporter is a ProgressReporter that records every report of a build
+// in its log, and passes it on to another reporter, e.g. the console.
+//
+// Failing to write the log does not fail the build: the first error is kept
+// and returned by Err, and later entries are still attempted.
+type BuildLogReporter struct {
+	ctx  context.Context
+	logs BuildLogs
+	id   string
+	next ProgressReporter // Optional
+	now  func() time.Time
+
+	mu  sync.Mutex
+	err error
+}
+
+// Compile-time interface check
+var _ ProgressReporter = (*BuildLogReporter)(nil)
+
+// NewBuildLogReporter creates a reporter recording to the log of build id in
+// logs and forwarding to next, which may be nil.
+func NewBuildLogReporter(ctx context.Context, logs BuildLogs, id string, next ProgressReporter) *BuildLogReporter {
+	return &BuildLogReporter{ctx: ctx, logs: logs, id: id, next: next, now: time.Now}
+}
+
+// ID returns the ID of the build whose log is recorded.
+func (r *BuildLogReporter) ID() string {
+	return r.id
+}
+
+// ReportProgress records and forwards a progress update.
+func (r *BuildLogReporter) ReportProgress(step string, current, total int, message string) {
+	r.Record(entities.BuildLogEntry{Level: entities.BuildLogProgress, Step: step, Current: current, Total: total, Message: message})
+	if r.next != nil {
+		r.next.ReportProgress(step, current, total, message)
+	}
+}
+
+// ReportError records and forwards an error.
+func (r *BuildLogReporter) ReportError(err error) {
+	r.Record(entities.BuildLogEntry{Level: entities.BuildLogError, Message: err.Error()})
+	if r.next != nil {
+		r.next.ReportError(err)
+	}
+}
+
+// ReportSuccess records and forwards a success message.
+func (r *BuildLogReporter) ReportSuccess(message string) {
+	r.Record(entities.BuildLogEntry{Level: entities.BuildLogSuccess, Message: message})
+	if r.next != nil {
+		r.next.ReportSuccess(message)
+	}
+}
+
+// ReportInfo records and forwards an informational message.
+func (r *BuildLogReporter) ReportInfo(message string) {
+	r.Record(entities.BuildLogEntry{Level: entities.BuildLogInfo, Message: message})
+	if r.next != nil {
+		r.next.ReportInfo(message)
+	}
+}
+
+// Record appends entry to the log without forwarding it, stamping it with
+// the current time unless it has one. Use it for the outcome of a build that
+// the caller reports itself.
+func (r *BuildLogReporter) Record(entry entities.BuildLogEntry) {
+	if entry.Time.IsZero() {
+		entry.Time = r.now().UTC()
+	}
+	err := r.logs.Append(r.ctx, r.id, entry)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil && r.err == nil {
+		r.err = err
+	}
+}
+
+// Finish records the outcome of the build: err as an error entry, or a
+// success entry when err is nil.
+func (r *BuildLogReporter) Finish(err error) {
+	if err != nil {
+		r.Record(entities.BuildLogEntry{Level: entities.BuildLogError, Message: "build failed: " + err.Error()})
+		return
+	}
+	r.Record(entities.BuildLogEntry{Level: entities.BuildLogSuccess, Message: "build complete"})
+}
+
+// Err returns the first error writing the log, if any.
+func (r *BuildLogReporter) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
@@ -0,0 +1,85 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+type memoryBuildLogs struct {
+	entries map[string][]entities.BuildLogEntry
+	err     error
+}
+
+func (l *memoryBuildLogs) Create(context.Context) (string, error) {
+	return "b1", nil
+}
+
+func (l *memoryBuildLogs) Append(_ context.Context, id string, entry entities.BuildLogEntry) error {
+	if l.err != nil {
+		return l.err
+	}
+	if l.entries == nil {
+		l.entries = make(map[string][]entities.BuildLogEntry)
+	}
+	l.entries[id] = append(l.entries[id], entry)
+	return nil
+}
+
+func (l *memoryBuildLogs) Entries(_ context.Context, id string) ([]entities.BuildLogEntry, error) {
+	entries, ok := l.entries[id]
+	if !ok {
+		return nil, entities.ErrBuildLogNotFound
+	}
+	return entries, nil
+}
+
+func (l *memoryBuildLogs) List(context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func TestBuildLogReporter(t *testing.T) {
+	logs := &memoryBuildLogs{}
+	console := &MockProgressReporter{}
+	r := NewBuildLogReporter(context.Background(), logs, "b1", console)
+
+	r.ReportProgress("Rendering diagrams", 1, 2, "api.d2")
+	r.ReportInfo("skipped 1 diagram")
+	r.ReportError(errors.New("d2 failed"))
+	r.Finish(errors.New("1 diagram failed"))
+
+	entries := logs.entries["b1"]
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries, got %+v", entries)
+	}
+	if e := entries[0]; e.Level != entities.BuildLogProgress || e.Step != "Rendering diagrams" || e.Current != 1 || e.Total != 2 || e.Time.IsZero() {
+		t.Errorf("progress entry = %+v", e)
+	}
+	if e := entries[3]; e.Level != entities.BuildLogError || e.Message != "build failed: 1 diagram failed" {
+		t.Errorf("outcome entry = %+v", e)
+	}
+	if len(console.steps) != 1 || len(console.infos) != 1 || len(console.errors) != 1 {
+		t.Errorf("forwarded steps %v, infos %v, errors %v", console.steps, console.infos, console.errors)
+	}
+	if r.Err() != nil {
+		t.Errorf("Err = %v", r.Err())
+	}
+}
+
+func TestBuildLogReporter_WriteErrorDoesNotStopForwarding(t *testing.T) {
+	logs := &memoryBuildLogs{err: errors.New("disk full")}
+	console := &MockProgressReporter{}
+	r := NewBuildLogReporter(context.Background(), logs, "b1", console)
+
+	r.ReportSuccess("built")
+	r.Finish(nil)
+
+	if r.Err() == nil || r.Err().Error() != "disk full" {
+		t.Errorf("Err = %v", r.Err())
+	}
+	if len(console.successes) != 1 {
+		t.Errorf("forwarded successes = %v", console.successes)
+	}
+}
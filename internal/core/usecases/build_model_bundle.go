@@ -0,0 +1,189 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// BuildModelBundle assembles the model bundle written as model.json: the
+// elements of every system, their relationships.toml entries, the metadata of
+// their diagrams and the architecture graph. Everything is sorted by ID so
+// that unchanged models produce identical bundles.
+type BuildModelBundle struct{}
+
+// NewBuildModelBundle creates a new BuildModelBundle use case.
+func NewBuildModelBundle() *BuildModelBundle {
+	return &BuildModelBundle{}
+}
+
+// Execute builds the bundle of the project's systems. relationships holds the
+// relationships.toml entries of each system, keyed by system ID; entries
+// whose elements do not exist are listed but left out of the graph.
+func (uc *BuildModelBundle) Execute(
+	ctx context.Context,
+	project *entities.Project,
+	systems []*entities.System,
+	relationships map[string][]entities.Relationship,
+) (*entities.ModelBundle, error) {
+	if project == nil {
+		return nil, fmt.Errorf("project cannot be nil")
+	}
+
+	bundle := &entities.ModelBundle{
+		Schema:        entities.ModelBundleSchema,
+		SchemaVersion: entities.ModelBundleSchemaVersion,
+		Project: entities.BundleProject{
+			Name:        project.Name,
+			Description: project.Description,
+			Version:     project.Version,
+		},
+		Systems:       []entities.BundleSystem{},
+		Relationships: []entities.Relationship{},
+		Diagrams:      []entities.BundleDiagram{},
+	}
+
+	systems = slices.DeleteFunc(slices.Clone(systems), func(s *entities.System) bool { return s == nil })
+	slices.SortFunc(systems, func(a, b *entities.System) int { return cmp.Compare(a.ID, b.ID) })
+	for _, sys := range systems {
+		bundle.Systems = append(bundle.Systems, uc.system(bundle, sys))
+	}
+
+	graph, err := NewBuildArchitectureGraph().Execute(ctx, project, systems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build architecture graph: %w", err)
+	}
+	for _, sys := range systems {
+		for _, rel := range relationships[sys.ID] {
+			bundle.Relationships = append(bundle.Relationships, rel)
+			source, ok := resolveNode(graph, rel.Source)
+			if !ok {
+				continue
+			}
+			target, ok := resolveNode(graph, rel.Target)
+			if !ok || source == target {
+				continue
+			}
+			_ = graph.AddEdge(&entities.GraphEdge{
+				Source:        source,
+				Target:        target,
+				Type:          "depends-on",
+				Description:   rel.Label,
+				Bidirectional: rel.Direction == "bidirectional",
+			})
+		}
+	}
+	bundle.Graph = bundleGraph(graph)
+
+	return bundle, nil
+}
+
+// system converts a system and its elements, recording their diagrams in
+// bundle.
+func (uc *BuildModelBundle) system(bundle *entities.ModelBundle, sys *entities.System) entities.BundleSystem {
+	out := entities.BundleSystem{
+		BundleElement: bundleElement(sys.ID, sys.Name, sys.Description, sys.Tags, sys.Issues, sys.Metadata),
+		Domain:        sys.Domain,
+		Containers:    []entities.BundleContainer{},
+	}
+	addBundleDiagram(bundle, sys.ID, "system", sys.Diagram, sys.DiagramPath)
+
+	for _, id := range slices.Sorted(maps.Keys(sys.Containers)) {
+		container := sys.Containers[id]
+		if container == nil {
+			continue
+		}
+		containerID := entities.QualifiedNodeID("container", sys.ID, container.ID, "")
+		outContainer := entities.BundleContainer{
+			BundleElement: bundleElement(containerID, container.Name, container.Description, container.Tags, container.Issues, container.Metadata),
+			Technology:    container.Technology,
+			Components:    []entities.BundleComponent{},
+		}
+		addBundleDiagram(bundle, containerID, "container", container.Diagram, container.DiagramPath)
+
+		for _, id := range slices.Sorted(maps.Keys(container.Components)) {
+			component := container.Components[id]
+			if component == nil {
+				continue
+			}
+			componentID := entities.QualifiedNodeID("component", sys.ID, container.ID, component.ID)
+			outComponent := entities.BundleComponent{
+				BundleElement:   bundleElement(componentID, component.Name, component.Description, component.Tags, component.Issues, component.Metadata),
+				Technology:      component.Technology,
+				Relationships:   component.Relationships,
+				CodeAnnotations: component.CodeAnnotations,
+				Dependencies:    component.Dependencies,
+				Aliases:         component.Aliases,
+			}
+			if component.Parent != "" {
+				outComponent.Parent = entities.QualifiedNodeID("component", sys.ID, container.ID, component.Parent)
+			}
+			addBundleDiagram(bundle, componentID, "component", component.Diagram, component.DiagramPath)
+			outContainer.Components = append(outContainer.Components, outComponent)
+		}
+		out.Containers = append(out.Containers, outContainer)
+	}
+	return out
+}
+
+// bundleElement returns the common fields of an element, leaving out empty
+// collections.
+func bundleElement(id, name, description string, tags, issues []string, metadata map[string]any) entities.BundleElement {
+	element := entities.BundleElement{ID: id, Name: name, Description: description, Tags: tags, Issues: issues}
+	if len(metadata) > 0 {
+		element.Metadata = metadata
+	}
+	return element
+}
+
+// addBundleDiagram records the diagram of an element, if it has one.
+func addBundleDiagram(bundle *entities.ModelBundle, elementID, level string, diagram *entities.Diagram, renderedPath string) {
+	if diagram == nil {
+		return
+	}
+	bundle.Diagrams = append(bundle.Diagrams, entities.BundleDiagram{
+		Element:  elementID,
+		Level:    level,
+		Hash:     entities.HashContent([]byte(diagram.Source)),
+		Rendered: filepath.ToSlash(renderedPath),
+	})
+}
+
+// resolveNode returns the qualified ID of the graph node an element path such
+// as "shop/api" or a short component ID refers to.
+func resolveNode(graph *entities.ArchitectureGraph, path string) (string, bool) {
+	if node := graph.GetNode(path); node != nil {
+		return node.ID, true
+	}
+	return graph.ResolveID(path)
+}
+
+// bundleGraph converts the graph to its bundle form, sorted by node ID and
+// then edge target.
+func bundleGraph(graph *entities.ArchitectureGraph) entities.BundleGraph {
+	out := entities.BundleGraph{Nodes: []entities.BundleGraphNode{}, Edges: []entities.BundleGraphEdge{}}
+	for _, id := range slices.Sorted(maps.Keys(graph.Nodes)) {
+		node := graph.Nodes[id]
+		out.Nodes = append(out.Nodes, entities.BundleGraphNode{ID: node.ID, Type: node.Type, Level: node.Level, Parent: node.ParentID})
+
+		edges := slices.Clone(graph.Edges[id])
+		slices.SortFunc(edges, func(a, b *entities.GraphEdge) int {
+			return cmp.Or(strings.Compare(a.Target, b.Target), strings.Compare(a.Type, b.Type))
+		})
+		for _, edge := range edges {
+			out.Edges = append(out.Edges, entities.BundleGraphEdge{
+				Source:      edge.Source,
+				Target:      edge.Target,
+				Type:        edge.Type,
+				Description: edge.Description,
+			})
+		}
+	}
+	return out
+}
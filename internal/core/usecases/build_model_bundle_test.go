@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestBuildModelBundle(t *testing.T) {
+	project, _ := entities.NewProject("shop")
+	system, _ := entities.NewSystem("Orders")
+	system.Diagram = &entities.Diagram{Source: "a -> b"}
+	system.DiagramPath = filepath.Join("diagrams", "orders.svg")
+	api, _ := entities.NewContainer("API")
+	_ = system.AddContainer(api)
+	handler, _ := entities.NewComponent("Handler")
+	handler.Relationships = map[string]string{"store": "saves orders"}
+	handler.Metadata = map[string]any{"owner": "team-a"}
+	store, _ := entities.NewComponent("Store")
+	_ = api.AddComponent(handler)
+	_ = api.AddComponent(store)
+	worker, _ := entities.NewContainer("Worker")
+	_ = system.AddContainer(worker)
+
+	relationships := map[string][]entities.Relationship{"orders": {
+		{ID: "1", Source: "orders/worker", Target: "orders/api/store", Label: "reads orders"},
+		{ID: "2", Source: "orders/api/missing", Target: "orders/api/store", Label: "dangling"},
+	}}
+	bundle, err := NewBuildModelBundle().Execute(context.Background(), project, []*entities.System{system}, relationships)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if bundle.SchemaVersion != entities.ModelBundleSchemaVersion || bundle.Project.Name != "shop" {
+		t.Errorf("header = %q %q", bundle.SchemaVersion, bundle.Project.Name)
+	}
+	if len(bundle.Systems) != 1 || len(bundle.Systems[0].Containers) != 2 {
+		t.Fatalf("systems = %+v", bundle.Systems)
+	}
+	components := bundle.Systems[0].Containers[0].Components
+	if len(components) != 2 || components[0].ID != "orders/api/handler" || components[0].Metadata["owner"] != "team-a" {
+		t.Errorf("components = %+v", components)
+	}
+	if len(bundle.Relationships) != 2 {
+		t.Errorf("relationships = %d, want 2", len(bundle.Relationships))
+	}
+	if len(bundle.Diagrams) != 1 || bundle.Diagrams[0].Element != "orders" || bundle.Diagrams[0].Rendered != "diagrams/orders.svg" {
+		t.Errorf("diagrams = %+v", bundle.Diagrams)
+	}
+
+	var edges []string
+	for _, edge := range bundle.Graph.Edges {
+		edges = append(edges, edge.Source+" -> "+edge.Target+": "+edge.Description)
+	}
+	want := []string{"orders/api/handler -> orders/api/store: saves orders", "orders/worker -> orders/api/store: reads orders"}
+	if !slices.Equal(edges, want) {
+		t.Errorf("edges = %q, want %q", edges, want)
+	}
+	if len(bundle.Graph.Nodes) != 5 || bundle.Graph.Nodes[0].ID != "orders" {
+		t.Errorf("nodes = %+v", bundle.Graph.Nodes)
+	}
+}
+
+func TestBuildDocsJSONBundle(t *testing.T) {
+	uc := NewBuildDocs(&MockDiagramRenderer{}, &MockSiteBuilder{}, &MockProgressReporter{})
+	outputDir := t.TempDir()
+	systems := []*entities.System{{ID: "orders", Name: "Orders"}}
+	opts := BuildDocsOptions{Formats: []OutputFormat{FormatJSONBundle}}
+	if err := uc.ExecuteWithFormats(context.Background(), &entities.Project{Name: "shop"}, systems, outputDir, opts); err != nil {
+		t.Fatalf("ExecuteWithFormats failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "model.json"))
+	if err != nil {
+		t.Fatalf("model.json not written: %v", err)
+	}
+	var bundle map[string]any
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		t.Fatalf("model.json is not JSON: %v", err)
+	}
+	if bundle["schema_version"] != entities.ModelBundleSchemaVersion || bundle["$schema"] != entities.ModelBundleSchema {
+		t.Errorf("schema = %v %v", bundle["$schema"], bundle["schema_version"])
+	}
+}
@@ -0,0 +1,64 @@
+package usecases
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// BuildStage is a step of the build pipeline that can be run alone, with
+// loko build --only, or left out, with --skip.
+type BuildStage string
+
+// Build stages, in pipeline order.
+const (
+	StageDiagrams    BuildStage = "diagrams"     // D2 sources rendered to SVG
+	StageAssets      BuildStage = "assets"       // CSS and JavaScript of the HTML site
+	StagePages       BuildStage = "pages"        // HTML pages and the other output formats
+	StageSearchIndex BuildStage = "search-index" // search.json and search shards of the HTML site
+	StageExports     BuildStage = "exports"      // Per-system exports requested in frontmatter
+	StageMinify      BuildStage = "minify"       // Minification of the HTML site
+)
+
+// AllBuildStages lists every build stage in pipeline order.
+var AllBuildStages = []BuildStage{StageDiagrams, StageAssets, StagePages, StageSearchIndex, StageExports, StageMinify}
+
+// BuildStages selects the build stages to run. The zero value runs them all.
+type BuildStages struct {
+	skipped []BuildStage
+}
+
+// ParseBuildStages selects the stages named in only, or every stage but those
+// named in skip. Naming an unknown stage, or using both lists, is an error.
+func ParseBuildStages(only, skip []string) (BuildStages, error) {
+	if len(only) > 0 && len(skip) > 0 {
+		return BuildStages{}, fmt.Errorf("--only and --skip cannot be combined")
+	}
+	for _, name := range append(slices.Clone(only), skip...) {
+		if !slices.Contains(AllBuildStages, BuildStage(name)) {
+			names := make([]string, len(AllBuildStages))
+			for i, stage := range AllBuildStages {
+				names[i] = string(stage)
+			}
+			return BuildStages{}, fmt.Errorf("unknown build stage %q (expected one of %s)", name, strings.Join(names, ", "))
+		}
+	}
+
+	var stages BuildStages
+	for _, stage := range AllBuildStages {
+		if slices.Contains(skip, string(stage)) || (len(only) > 0 && !slices.Contains(only, string(stage))) {
+			stages.skipped = append(stages.skipped, stage)
+		}
+	}
+	return stages, nil
+}
+
+// Runs reports whether stage is selected.
+func (s BuildStages) Runs(stage BuildStage) bool {
+	return !slices.Contains(s.skipped, stage)
+}
+
+// Skipped returns the stages left out, in pipeline order.
+func (s BuildStages) Skipped() []BuildStage {
+	return slices.Clone(s.skipped)
+}
@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseBuildStages(t *testing.T) {
+	tests := []struct {
+		name    string
+		only    []string
+		skip    []string
+		want    []BuildStage // Stages that run
+		wantErr string
+	}{
+		{name: "all by default", want: AllBuildStages},
+		{name: "only", only: []string{"diagrams", "pages"}, want: []BuildStage{StageDiagrams, StagePages}},
+		{name: "skip", skip: []string{"search-index", "assets"}, want: []BuildStage{StageDiagrams, StagePages, StageExports, StageMinify}},
+		{name: "unknown stage", skip: []string{"search"}, wantErr: `unknown build stage "search"`},
+		{name: "only and skip", only: []string{"pages"}, skip: []string{"assets"}, wantErr: "cannot be combined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stages, err := ParseBuildStages(tt.only, tt.skip)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseBuildStages failed: %v", err)
+			}
+			var got []BuildStage
+			for _, stage := range AllBuildStages {
+				if stages.Runs(stage) {
+					got = append(got, stage)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("running stages = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildStagesZeroValueRunsAll(t *testing.T) {
+	var stages BuildStages
+	for _, stage := range AllBuildStages {
+		if !stages.Runs(stage) {
+			t.Errorf("zero BuildStages skips %s", stage)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// deprecatedTag is the tag that marks an entity as deprecated on the timeline.
+const deprecatedTag = "deprecated"
+
+// BuildTimeline reconstructs how the architecture evolved from revision history.
+//
+// Each system.md, container.md and component.md file marks the existence of an
+// entity: the revision that adds the file is the "added" event and the revision
+// that deletes it is the "removed" event. Entities currently tagged "deprecated"
+// get a "deprecated" event at the last revision that touched their file.
+type BuildTimeline struct {
+	history HistoryProvider
+}
+
+// NewBuildTimeline creates a new BuildTimeline use case.
+func NewBuildTimeline(history HistoryProvider) *BuildTimeline {
+	return &BuildTimeline{history: history}
+}
+
+// Execute returns the timeline for the project. When the project has no
+// revision history (entities.ErrNoHistory) an empty timeline is returned.
+func (uc *BuildTimeline) Execute(
+	ctx context.Context,
+	project *entities.Project,
+	systems []*entities.System,
+) (*entities.Timeline, error) {
+	if project == nil {
+		return nil, fmt.Errorf("project cannot be nil")
+	}
+
+	srcPrefix := "src"
+	if project.Config != nil && project.Config.SourceDir != "" {
+		srcPrefix = path.Clean(strings.ReplaceAll(project.Config.SourceDir, "\\", "/"))
+	}
+
+	revisions, err := uc.history.ListRevisions(ctx, project.Path, srcPrefix)
+	if err != nil {
+		if errors.Is(err, entities.ErrNoHistory) {
+			return entities.NewTimeline(), nil
+		}
+		return nil, fmt.Errorf("reading revision history: %w", err)
+	}
+
+	names := entityNames(systems)
+	timeline := entities.NewTimeline()
+	lastTouched := make(map[string]Revision)
+
+	for _, rev := range revisions {
+		for _, change := range rev.Changes {
+			entityType, entityID, ok := classifyEntityFile(srcPrefix, change.Path)
+			if !ok {
+				continue
+			}
+			lastTouched[entityID] = rev
+
+			var kind entities.TimelineEventKind
+			switch change.Status {
+			case "added":
+				kind = entities.TimelineAdded
+			case "deleted":
+				kind = entities.TimelineRemoved
+			default:
+				continue
+			}
+
+			timeline.Add(newTimelineEvent(rev, kind, entityType, entityID, names))
+		}
+	}
+
+	for _, entityID := range deprecatedEntities(systems) {
+		rev, ok := lastTouched[entityID]
+		if !ok {
+			continue
+		}
+		_, entityType := entities.ParseQualifiedID(entityID)
+		timeline.Add(newTimelineEvent(rev, entities.TimelineDeprecated, entityType, entityID, names))
+	}
+
+	timeline.Sort()
+	return timeline, nil
+}
+
+// newTimelineEvent builds an event for entityID from a revision.
+func newTimelineEvent(
+	rev Revision,
+	kind entities.TimelineEventKind,
+	entityType, entityID string,
+	names map[string]string,
+) entities.TimelineEvent {
+	name, ok := names[entityID]
+	if !ok {
+		name = path.Base(entityID)
+	}
+	return entities.TimelineEvent{
+		Date:       rev.Date,
+		Kind:       kind,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Name:       name,
+		Commit:     rev.Commit,
+		Author:     rev.Author,
+		Message:    rev.Message,
+	}
+}
+
+// classifyEntityFile maps a source file path to the entity it defines.
+// Only the entity's primary markdown file counts (system.md, container.md, component.md).
+func classifyEntityFile(srcPrefix, filePath string) (entityType, entityID string, ok bool) {
+	rel, found := strings.CutPrefix(filePath, srcPrefix+"/")
+	if !found {
+		return "", "", false
+	}
+
+	dir, file := path.Split(rel)
+	segments := strings.Split(strings.Trim(dir, "/"), "/")
+
+	switch {
+	case file == "system.md" && len(segments) == 1 && segments[0] != "":
+		return "system", segments[0], true
+	case file == "container.md" && len(segments) == 2:
+		return "container", strings.Join(segments, "/"), true
+	case file == "component.md" && len(segments) == 3:
+		return "component", strings.Join(segments, "/"), true
+	default:
+		return "", "", false
+	}
+}
+
+// entityNames maps qualified entity IDs to display names for the current model.
+func entityNames(systems []*entities.System) map[string]string {
+	names := make(map[string]string)
+	for _, sys := range systems {
+		if sys == nil {
+			continue
+		}
+		names[sys.ID] = sys.Name
+		for _, cont := range sys.Containers {
+			if cont == nil {
+				continue
+			}
+			names[sys.ID+"/"+cont.ID] = cont.Name
+			for _, comp := range cont.Components {
+				if comp == nil {
+					continue
+				}
+				names[sys.ID+"/"+cont.ID+"/"+comp.ID] = comp.Name
+			}
+		}
+	}
+	return names
+}
+
+// deprecatedEntities returns the sorted qualified IDs of entities tagged deprecated.
+func deprecatedEntities(systems []*entities.System) []string {
+	var ids []string
+	for _, sys := range systems {
+		if sys == nil {
+			continue
+		}
+		if sys.HasTag(deprecatedTag) {
+			ids = append(ids, sys.ID)
+		}
+		for _, cont := range sys.Containers {
+			if cont == nil {
+				continue
+			}
+			if cont.HasTag(deprecatedTag) {
+				ids = append(ids, sys.ID+"/"+cont.ID)
+			}
+			for _, comp := range cont.Components {
+				if comp != nil && comp.HasTag(deprecatedTag) {
+					ids = append(ids, sys.ID+"/"+cont.ID+"/"+comp.ID)
+				}
+			}
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
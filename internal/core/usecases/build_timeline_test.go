@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// MockHistoryProvider mocks the HistoryProvider interface.
+type MockHistoryProvider struct {
+	revisions  []Revision
+	err        error
+	lastPrefix string
+}
+
+func (m *MockHistoryProvider) ListRevisions(ctx context.Context, projectRoot, pathPrefix string) ([]Revision, error) {
+	m.lastPrefix = pathPrefix
+	return m.revisions, m.err
+}
+
+func TestBuildTimeline_AddedDeprecatedRemoved(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 12, 0, 0, 0, time.UTC) }
+	history := &MockHistoryProvider{revisions: []Revision{
+		{Commit: "c1", Author: "ana", Date: day(1), Message: "initial", Changes: []FileRevision{
+			{Path: "src/backend/system.md", Status: "added"},
+			{Path: "src/backend/api/container.md", Status: "added"},
+			{Path: "src/backend/api/container.d2", Status: "added"},
+		}},
+		{Commit: "c2", Author: "ben", Date: day(2), Message: "legacy worker", Changes: []FileRevision{
+			{Path: "src/backend/worker/container.md", Status: "added"},
+			{Path: "src/backend/api/auth/component.md", Status: "added"},
+		}},
+		{Commit: "c3", Author: "ana", Date: day(3), Message: "deprecate api", Changes: []FileRevision{
+			{Path: "src/backend/api/container.md", Status: "modified"},
+		}},
+		{Commit: "c4", Author: "ben", Date: day(4), Message: "drop worker", Changes: []FileRevision{
+			{Path: "src/backend/worker/container.md", Status: "deleted"},
+		}},
+	}}
+
+	api := &entities.Container{ID: "api", Name: "API", Tags: []string{"deprecated"},
+		Components: map[string]*entities.Component{"auth": {ID: "auth", Name: "Auth"}}}
+	systems := []*entities.System{{ID: "backend", Name: "Backend",
+		Containers: map[string]*entities.Container{"api": api}}}
+	project := &entities.Project{Name: "p", Path: "/tmp/p", Config: entities.DefaultProjectConfig()}
+
+	timeline, err := NewBuildTimeline(history).Execute(context.Background(), project, systems)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if history.lastPrefix != "src" {
+		t.Errorf("expected path prefix %q, got %q", "src", history.lastPrefix)
+	}
+
+	want := []struct {
+		kind entities.TimelineEventKind
+		id   string
+		name string
+	}{
+		{entities.TimelineAdded, "backend", "Backend"},
+		{entities.TimelineAdded, "backend/api", "API"},
+		{entities.TimelineAdded, "backend/api/auth", "Auth"},
+		{entities.TimelineAdded, "backend/worker", "worker"},
+		{entities.TimelineDeprecated, "backend/api", "API"},
+		{entities.TimelineRemoved, "backend/worker", "worker"},
+	}
+	if len(timeline.Events) != len(want) {
+		t.Fatalf("expected %d events, got %d: %+v", len(want), len(timeline.Events), timeline.Events)
+	}
+	for i, w := range want {
+		got := timeline.Events[i]
+		if got.Kind != w.kind || got.EntityID != w.id || got.Name != w.name {
+			t.Errorf("event %d: expected %s %s (%s), got %s %s (%s)", i, w.kind, w.id, w.name, got.Kind, got.EntityID, got.Name)
+		}
+	}
+	if timeline.Events[4].Commit != "c3" {
+		t.Errorf("expected deprecation at last touching commit c3, got %s", timeline.Events[4].Commit)
+	}
+}
+
+func TestBuildTimeline_NoHistory(t *testing.T) {
+	history := &MockHistoryProvider{err: entities.ErrNoHistory}
+	project := &entities.Project{Name: "p", Path: "/tmp/p"}
+
+	timeline, err := NewBuildTimeline(history).Execute(context.Background(), project, nil)
+	if err != nil {
+		t.Fatalf("expected no error without history, got %v", err)
+	}
+	if !timeline.IsEmpty() {
+		t.Errorf("expected empty timeline, got %d events", len(timeline.Events))
+	}
+}
+
+func TestBuildTimeline_NilProject(t *testing.T) {
+	_, err := NewBuildTimeline(&MockHistoryProvider{}).Execute(context.Background(), nil, nil)
+	if err == nil {
+		t.Error("expected error for nil project")
+	}
+}
+
+func TestClassifyEntityFile(t *testing.T) {
+	tests := []struct {
+		path     string
+		wantType string
+		wantID   string
+		wantOK   bool
+	}{
+		{"src/sys/system.md", "system", "sys", true},
+		{"src/sys/api/container.md", "container", "sys/api", true},
+		{"src/sys/api/auth/component.md", "component", "sys/api/auth", true},
+		{"src/sys/api/auth/auth.d2", "", "", false},
+		{"src/system.md", "", "", false},
+		{"docs/sys/system.md", "", "", false},
+	}
+	for _, tt := range tests {
+		gotType, gotID, gotOK := classifyEntityFile("src", tt.path)
+		if gotType != tt.wantType || gotID != tt.wantID || gotOK != tt.wantOK {
+			t.Errorf("classifyEntityFile(%q) = (%q, %q, %v), want (%q, %q, %v)",
+				tt.path, gotType, gotID, gotOK, tt.wantType, tt.wantID, tt.wantOK)
+		}
+	}
+}
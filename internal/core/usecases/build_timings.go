@@ -0,0 +1,88 @@
+package usecases
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Build phases recorded in BuildTimings.
+const (
+	PhaseLoad          = "project loading"
+	PhaseDiagrams      = "diagram rendering"
+	PhaseDiagramWrites = "diagram file writes"
+	PhasePages         = "page generation"
+	PhaseMarkdown      = "markdown generation"
+	PhasePDF           = "pdf generation"
+	PhaseTOON          = "toon export"
+	PhaseJSONBundle    = "json model bundle"
+	PhaseExports       = "system exports"
+	PhaseMinify        = "minification"
+)
+
+// PhaseTiming is the total time spent in one build phase.
+type PhaseTiming struct {
+	Phase    string
+	Duration time.Duration
+}
+
+// BuildTimings accumulates time spent per build phase. It is safe for
+// concurrent use, and a nil *BuildTimings ignores all recordings so callers
+// need not check whether timing is enabled.
+type BuildTimings struct {
+	mu     sync.Mutex
+	phases map[string]time.Duration
+}
+
+// NewBuildTimings creates an empty BuildTimings.
+func NewBuildTimings() *BuildTimings {
+	return &BuildTimings{phases: make(map[string]time.Duration)}
+}
+
+// Add adds d to the total of phase.
+func (t *BuildTimings) Add(phase string, d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.phases[phase] += d
+}
+
+// Track starts timing phase and returns a function that records the elapsed
+// time when called: defer timings.Track(PhasePages)().
+func (t *BuildTimings) Track(phase string) func() {
+	start := time.Now()
+	return func() { t.Add(phase, time.Since(start)) }
+}
+
+// Phases returns the recorded phases, slowest first.
+func (t *BuildTimings) Phases() []PhaseTiming {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make([]PhaseTiming, 0, len(t.phases))
+	for phase, d := range t.phases {
+		result = append(result, PhaseTiming{Phase: phase, Duration: d})
+	}
+	slices.SortFunc(result, func(a, b PhaseTiming) int {
+		if c := cmp.Compare(b.Duration, a.Duration); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Phase, b.Phase)
+	})
+	return result
+}
+
+// Total returns the sum of all recorded phases.
+func (t *BuildTimings) Total() time.Duration {
+	var total time.Duration
+	for _, p := range t.Phases() {
+		total += p.Duration
+	}
+	return total
+}
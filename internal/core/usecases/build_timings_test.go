@@ -0,0 +1,51 @@
+package usecases
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBuildTimings(t *testing.T) {
+	timings := NewBuildTimings()
+	timings.Add(PhasePages, 10*time.Millisecond)
+	timings.Add(PhaseDiagrams, 30*time.Millisecond)
+	timings.Add(PhasePages, 5*time.Millisecond)
+
+	phases := timings.Phases()
+	if len(phases) != 2 {
+		t.Fatalf("expected 2 phases, got %d", len(phases))
+	}
+	if phases[0].Phase != PhaseDiagrams || phases[0].Duration != 30*time.Millisecond {
+		t.Errorf("expected slowest phase first, got %+v", phases[0])
+	}
+	if phases[1].Duration != 15*time.Millisecond {
+		t.Errorf("expected accumulated page time 15ms, got %v", phases[1].Duration)
+	}
+	if timings.Total() != 45*time.Millisecond {
+		t.Errorf("Total() = %v, want 45ms", timings.Total())
+	}
+}
+
+func TestBuildTimingsConcurrent(t *testing.T) {
+	timings := NewBuildTimings()
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Go(func() { timings.Add(PhaseDiagrams, time.Millisecond) })
+	}
+	wg.Wait()
+
+	if got := timings.Total(); got != 50*time.Millisecond {
+		t.Errorf("Total() = %v, want 50ms", got)
+	}
+}
+
+func TestBuildTimingsNil(t *testing.T) {
+	var timings *BuildTimings
+	timings.Add(PhasePages, time.Second)
+	timings.Track(PhaseLoad)()
+
+	if timings.Phases() != nil || timings.Total() != 0 {
+		t.Error("expected nil timings to record nothing")
+	}
+}
@@ -0,0 +1,293 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// ElementSelector selects systems, containers and components by their
+// attributes. Every criterion that is set must match.
+type ElementSelector struct {
+	Type       string            // "system", "container" or "component"; empty selects all
+	Tags       []string          // Tags the element must all have
+	Technology string            // Technology, compared case-insensitively
+	IDPattern  string            // Glob over the qualified ID, e.g. "payments/*"
+	Metadata   map[string]string // Custom frontmatter fields, matched as by search
+}
+
+// ParseElementSelector parses selector terms such as "tag:legacy",
+// "type:container", "technology:Go", "id:payments/*" or, for a custom
+// frontmatter field, "owner:payments-team". All terms must match.
+func ParseElementSelector(terms []string) (*ElementSelector, error) {
+	selector := &ElementSelector{}
+	for _, term := range terms {
+		key, value, ok := strings.Cut(term, ":")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid selector %q: expected key:value, e.g. tag:legacy", term)
+		}
+		switch key {
+		case "type":
+			if value != "system" && value != "container" && value != "component" {
+				return nil, fmt.Errorf("invalid selector %q: type must be system, container or component", term)
+			}
+			selector.Type = value
+		case "tag":
+			selector.Tags = append(selector.Tags, value)
+		case "technology":
+			selector.Technology = value
+		case "id":
+			selector.IDPattern = value
+		default:
+			if selector.Metadata == nil {
+				selector.Metadata = make(map[string]string)
+			}
+			selector.Metadata[key] = value
+		}
+	}
+	return selector, nil
+}
+
+// IsEmpty reports whether the selector has no criteria and so selects every
+// element.
+func (s *ElementSelector) IsEmpty() bool {
+	return s == nil || (s.Type == "" && len(s.Tags) == 0 && s.Technology == "" && s.IDPattern == "" && len(s.Metadata) == 0)
+}
+
+// matches reports whether element meets every criterion of the selector.
+func (s *ElementSelector) matches(element editableElement) bool {
+	if s.Type != "" && element.elementType != s.Type {
+		return false
+	}
+	for _, tag := range s.Tags {
+		if !slices.Contains(element.tags, tag) {
+			return false
+		}
+	}
+	if s.Technology != "" && !strings.EqualFold(element.technology, s.Technology) {
+		return false
+	}
+	if s.IDPattern != "" && !entities.NewGlobMatcher(strings.ToLower(s.IDPattern)).Match(strings.ToLower(element.id)) {
+		return false
+	}
+	return matchesMetadata(element.metadata, s.Metadata)
+}
+
+// BulkEditRequest describes a metadata change applied to every selected element.
+type BulkEditRequest struct {
+	Selector   *ElementSelector
+	Set        map[string]string // Scalar frontmatter fields to set, e.g. technology
+	AddTags    []string
+	RemoveTags []string
+}
+
+// FrontmatterEdit is the planned change of one element's frontmatter.
+type FrontmatterEdit struct {
+	Element string        // Qualified element ID
+	Path    string        // Markdown file of the element
+	Fields  []FieldChange // Scalar fields set, sorted by key
+	OldTags []string
+	NewTags []string
+}
+
+// FieldChange is a frontmatter field set to a new value.
+type FieldChange struct {
+	Key    string
+	Before string // Empty when the field was not set
+	After  string
+}
+
+// TagsChanged reports whether the edit changes the element's tags.
+func (e FrontmatterEdit) TagsChanged() bool {
+	return !slices.Equal(e.OldTags, e.NewTags)
+}
+
+// Diff renders the edit as the frontmatter lines it removes (prefixed with
+// "-") and adds (prefixed with "+").
+func (e FrontmatterEdit) Diff() string {
+	var sb strings.Builder
+	for _, field := range e.Fields {
+		if field.Before != "" {
+			fmt.Fprintf(&sb, "-%s: %q\n", field.Key, field.Before)
+		}
+		fmt.Fprintf(&sb, "+%s: %q\n", field.Key, field.After)
+	}
+	if e.TagsChanged() {
+		if len(e.OldTags) > 0 {
+			fmt.Fprintf(&sb, "-tags: [%s]\n", strings.Join(e.OldTags, ", "))
+		}
+		if len(e.NewTags) > 0 {
+			fmt.Fprintf(&sb, "+tags: [%s]\n", strings.Join(e.NewTags, ", "))
+		}
+	}
+	return sb.String()
+}
+
+// bulkEditFields are the built-in frontmatter fields a bulk edit may set.
+// Other built-in fields are lists, references or review state with dedicated
+// commands; any custom field may be set.
+var bulkEditFields = []string{"name", "description", "technology"}
+
+// reservedFrontmatterFields are the built-in fields a bulk edit may not set.
+var reservedFrontmatterFields = []string{
+	"id", "tags", "issues", "controls", "export", "relationships", "code_annotations",
+	"dependencies", "aliases", entities.ReviewStatusField, entities.ReviewedByField, entities.ReviewedAtField,
+}
+
+// editableElement is a system, container or component a bulk edit may change.
+type editableElement struct {
+	id          string
+	elementType string
+	path        string
+	name        string
+	description string
+	technology  string
+	tags        []string
+	metadata    map[string]any
+}
+
+// BulkEdit applies the same frontmatter change to every element matching a
+// selector, such as tagging all legacy containers as deprecated, in one pass.
+type BulkEdit struct {
+	editor FrontmatterEditor
+}
+
+// NewBulkEdit creates a BulkEdit use case.
+func NewBulkEdit(editor FrontmatterEditor) *BulkEdit {
+	return &BulkEdit{editor: editor}
+}
+
+// Plan returns the edits req makes to the elements of systems, sorted by
+// element. Selected elements the request does not change are left out, and
+// technology is not set on systems, which have none.
+func (uc *BulkEdit) Plan(systems []*entities.System, req BulkEditRequest) ([]FrontmatterEdit, error) {
+	if req.Selector.IsEmpty() {
+		return nil, fmt.Errorf("a selector is required; use id:* to select every element")
+	}
+	if len(req.Set) == 0 && len(req.AddTags) == 0 && len(req.RemoveTags) == 0 {
+		return nil, fmt.Errorf("nothing to change: set a field or add or remove a tag")
+	}
+	for key := range req.Set {
+		if key == "" || slices.Contains(reservedFrontmatterFields, key) {
+			return nil, fmt.Errorf("field %q cannot be set in bulk (settable: %s and custom fields)", key, strings.Join(bulkEditFields, ", "))
+		}
+	}
+
+	var edits []FrontmatterEdit
+	for _, element := range editableElements(systems) {
+		if !req.Selector.matches(element) {
+			continue
+		}
+
+		edit := FrontmatterEdit{Element: element.id, OldTags: element.tags, NewTags: slices.Clone(element.tags)}
+		for _, key := range slices.Sorted(maps.Keys(req.Set)) {
+			if key == "technology" && element.elementType == "system" {
+				continue
+			}
+			before, err := element.field(key)
+			if err != nil {
+				return nil, err
+			}
+			if after := req.Set[key]; after != before {
+				edit.Fields = append(edit.Fields, FieldChange{Key: key, Before: before, After: after})
+			}
+		}
+		edit.NewTags = slices.DeleteFunc(edit.NewTags, func(tag string) bool { return slices.Contains(req.RemoveTags, tag) })
+		for _, tag := range req.AddTags {
+			if !slices.Contains(edit.NewTags, tag) {
+				edit.NewTags = append(edit.NewTags, tag)
+			}
+		}
+
+		if len(edit.Fields) == 0 && !edit.TagsChanged() {
+			continue
+		}
+		if element.path == "" {
+			return nil, fmt.Errorf("%s was not loaded from a project", element.id)
+		}
+		edit.Path = element.path
+		edits = append(edits, edit)
+	}
+	return edits, nil
+}
+
+// Apply writes the planned edits to the elements' frontmatter.
+func (uc *BulkEdit) Apply(ctx context.Context, edits []FrontmatterEdit) error {
+	for _, edit := range edits {
+		for _, field := range edit.Fields {
+			if err := uc.editor.SetFrontmatterField(ctx, edit.Path, field.Key, field.After); err != nil {
+				return fmt.Errorf("failed to edit %s: %w", edit.Element, err)
+			}
+		}
+		if edit.TagsChanged() {
+			if err := uc.editor.SetFrontmatterList(ctx, edit.Path, "tags", edit.NewTags); err != nil {
+				return fmt.Errorf("failed to edit %s: %w", edit.Element, err)
+			}
+		}
+	}
+	return nil
+}
+
+// field returns the current value of the scalar frontmatter field key.
+func (e editableElement) field(key string) (string, error) {
+	switch key {
+	case "name":
+		return e.name, nil
+	case "description":
+		return e.description, nil
+	case "technology":
+		return e.technology, nil
+	}
+	switch value := e.metadata[key].(type) {
+	case nil:
+		return "", nil
+	case []string, []any:
+		return "", fmt.Errorf("%s: field %q is a list and cannot be set in bulk", e.id, key)
+	default:
+		return fmt.Sprint(value), nil
+	}
+}
+
+// editableElements lists the elements of systems with their Markdown files,
+// in ID order with each system followed by its containers and components.
+func editableElements(systems []*entities.System) []editableElement {
+	sorted := slices.DeleteFunc(slices.Clone(systems), func(s *entities.System) bool { return s == nil })
+	slices.SortFunc(sorted, func(a, b *entities.System) int { return strings.Compare(a.ID, b.ID) })
+
+	source := func(dir, file string) string {
+		if dir == "" {
+			return ""
+		}
+		return filepath.Join(dir, file)
+	}
+
+	var elements []editableElement
+	for _, system := range sorted {
+		elements = append(elements, editableElement{
+			id: system.ID, elementType: "system", path: source(system.Path, "system.md"),
+			name: system.Name, description: system.Description, tags: system.Tags, metadata: system.Metadata,
+		})
+		for _, container := range system.ListContainers() {
+			containerID := system.ID + "/" + container.ID
+			elements = append(elements, editableElement{
+				id: containerID, elementType: "container", path: source(container.Path, "container.md"),
+				name: container.Name, description: container.Description, technology: container.Technology,
+				tags: container.Tags, metadata: container.Metadata,
+			})
+			for _, component := range container.ListComponents() {
+				elements = append(elements, editableElement{
+					id: containerID + "/" + component.ID, elementType: "component", path: source(component.Path, "component.md"),
+					name: component.Name, description: component.Description, technology: component.Technology,
+					tags: component.Tags, metadata: component.Metadata,
+				})
+			}
+		}
+	}
+	return elements
+}
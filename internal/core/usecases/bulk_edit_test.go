@@ -0,0 +1,97 @@
+package usecases
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestBulkEdit(t *testing.T) {
+	root := t.TempDir()
+	system, _ := entities.NewSystem("Payments")
+	system.Path = filepath.Join(root, "payments")
+	system.Tags = []string{"legacy"}
+	api, _ := entities.NewContainer("API")
+	api.Path = filepath.Join(system.Path, "api")
+	api.Technology = "Java 7"
+	api.Tags = []string{"legacy", "critical"}
+	api.Metadata = map[string]any{"owner": "payments-team"}
+	worker, _ := entities.NewContainer("Worker")
+	worker.Path = filepath.Join(system.Path, "worker")
+	worker.Technology = "Java 8"
+	worker.Tags = []string{"legacy", "deprecated"}
+	web, _ := entities.NewContainer("Web")
+	web.Path = filepath.Join(system.Path, "web")
+	_ = system.AddContainer(api)
+	_ = system.AddContainer(worker)
+	_ = system.AddContainer(web)
+	systems := []*entities.System{system}
+
+	selector, err := ParseElementSelector([]string{"tag:legacy"})
+	if err != nil {
+		t.Fatalf("ParseElementSelector() error = %v", err)
+	}
+	editor := &recordingEditor{}
+	uc := NewBulkEdit(editor)
+	edits, err := uc.Plan(systems, BulkEditRequest{
+		Selector: selector,
+		Set:      map[string]string{"technology": "Java 8"},
+		AddTags:  []string{"deprecated"},
+	})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	// The worker is already up to date, and systems have no technology
+	var ids []string
+	for _, edit := range edits {
+		ids = append(ids, edit.Element)
+	}
+	if !slices.Equal(ids, []string{"payments", "payments/api"}) {
+		t.Fatalf("edited %q", ids)
+	}
+	if diff := edits[1].Diff(); diff != "-technology: \"Java 7\"\n+technology: \"Java 8\"\n-tags: [legacy, critical]\n+tags: [legacy, critical, deprecated]\n" {
+		t.Errorf("Diff() =\n%s", diff)
+	}
+
+	if err := uc.Apply(context.Background(), edits); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	want := []string{
+		"payments/system.md set tags=[legacy,deprecated]",
+		"api/container.md set technology=Java 8",
+		"api/container.md set tags=[legacy,critical,deprecated]",
+	}
+	if !slices.Equal(editor.edits, want) {
+		t.Errorf("edits = %q, want %q", editor.edits, want)
+	}
+
+	// Custom fields select and are set like built-in ones
+	selector, _ = ParseElementSelector([]string{"type:container", "owner:payments-team"})
+	edits, err = uc.Plan(systems, BulkEditRequest{Selector: selector, Set: map[string]string{"owner": "platform-team"}, RemoveTags: []string{"critical"}})
+	if err != nil || len(edits) != 1 || edits[0].Diff() != "-owner: \"payments-team\"\n+owner: \"platform-team\"\n-tags: [legacy, critical]\n+tags: [legacy]\n" {
+		t.Errorf("Plan(owner) = %+v, %v", edits, err)
+	}
+
+	for name, req := range map[string]BulkEditRequest{
+		"no selector":   {Selector: &ElementSelector{}, AddTags: []string{"x"}},
+		"no change":     {Selector: selector},
+		"reserved":      {Selector: selector, Set: map[string]string{"dependencies": "x"}},
+		"list metadata": {Selector: &ElementSelector{IDPattern: "payments/api"}, Set: map[string]string{"owner": "x"}},
+	} {
+		if name == "list metadata" {
+			api.Metadata["owner"] = []string{"a", "b"}
+		}
+		if _, err := uc.Plan(systems, req); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	for _, term := range []string{"legacy", "type:service", "tag:"} {
+		if _, err := ParseElementSelector([]string{term}); err == nil {
+			t.Errorf("ParseElementSelector(%q): expected an error", term)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package usecases
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// ChangeSet coalesces a burst of file change events into a single rebuild.
+//
+// It records every changed file once, the widest RebuildScope among them, and
+// the set of systems, containers and components ("dirty entities") whose
+// source files changed. A git checkout touching hundreds of files becomes one
+// ChangeSet instead of hundreds of rebuilds.
+type ChangeSet struct {
+	srcPrefixes []string
+	files       map[string]string // path -> last operation
+	scope       RebuildScope
+	systems     map[string]bool
+	containers  map[string]bool
+	components  map[string]bool
+}
+
+// NewChangeSet creates an empty change set for a project whose sources live
+// in sourceDirs (e.g., "./src" and the [paths] source_dirs), relative to the
+// project root.
+func NewChangeSet(sourceDirs ...string) *ChangeSet {
+	cs := &ChangeSet{}
+	for _, dir := range sourceDirs {
+		prefix := strings.ToLower(path.Clean(strings.ReplaceAll(dir, "\\", "/")))
+		if prefix == "." || prefix == "" {
+			prefix = "src"
+		}
+		cs.srcPrefixes = append(cs.srcPrefixes, prefix)
+	}
+	if len(cs.srcPrefixes) == 0 {
+		cs.srcPrefixes = []string{"src"}
+	}
+	cs.Reset()
+	return cs
+}
+
+// Reset clears the change set so it can collect the next burst.
+func (cs *ChangeSet) Reset() {
+	cs.files = make(map[string]string)
+	cs.scope = RebuildNone
+	cs.systems = make(map[string]bool)
+	cs.containers = make(map[string]bool)
+	cs.components = make(map[string]bool)
+}
+
+// Add records a change event and marks the entities it affects as dirty.
+func (cs *ChangeSet) Add(event FileChangeEvent) {
+	if event.Path == "" {
+		return
+	}
+
+	p := strings.ToLower(path.Clean(event.Path))
+	cs.files[p] = event.Op
+	cs.scope = cs.scope.Merge(ScopeForChange(p))
+
+	var rel string
+	ok := false
+	for _, prefix := range cs.srcPrefixes {
+		if rel, ok = strings.CutPrefix(p, prefix+"/"); ok {
+			break
+		}
+	}
+	if !ok {
+		return
+	}
+
+	// src/<system>/<container>/<component>/<file>: the directories above the
+	// file identify the most specific entity that changed.
+	segments := strings.Split(path.Dir(rel), "/")
+	if len(segments) == 0 || segments[0] == "." {
+		return
+	}
+
+	cs.systems[segments[0]] = true
+	if len(segments) >= 2 {
+		cs.containers[segments[0]+"/"+segments[1]] = true
+	}
+	if len(segments) >= 3 {
+		cs.components[segments[0]+"/"+segments[1]+"/"+segments[2]] = true
+	}
+}
+
+// IsEmpty reports whether no changes have been recorded.
+func (cs *ChangeSet) IsEmpty() bool {
+	return len(cs.files) == 0
+}
+
+// FileCount returns the number of distinct files changed.
+func (cs *ChangeSet) FileCount() int {
+	return len(cs.files)
+}
+
+// Scope returns the widest rebuild scope among the recorded changes.
+func (cs *ChangeSet) Scope() RebuildScope {
+	return cs.scope
+}
+
+// DirtySystems returns the sorted IDs of systems with changed sources.
+func (cs *ChangeSet) DirtySystems() []string {
+	return sortedKeys(cs.systems)
+}
+
+// DirtyContainers returns the sorted "system/container" paths with changed sources.
+func (cs *ChangeSet) DirtyContainers() []string {
+	return sortedKeys(cs.containers)
+}
+
+// DirtyComponents returns the sorted "system/container/component" paths with changed sources.
+func (cs *ChangeSet) DirtyComponents() []string {
+	return sortedKeys(cs.components)
+}
+
+// Summary returns a one-line description such as
+// "37 files changed, rebuilding 5 systems".
+func (cs *ChangeSet) Summary() string {
+	files := pluralize(cs.FileCount(), "file", "files")
+
+	switch {
+	case cs.scope == RebuildFull:
+		return fmt.Sprintf("%s changed, configuration updated, rebuilding everything", files)
+	case cs.scope == RebuildPages:
+		return fmt.Sprintf("%s changed, re-rendering pages", files)
+	case len(cs.systems) > 0 && cs.scope == RebuildContent:
+		return fmt.Sprintf("%s changed, rebuilding %s", files, pluralize(len(cs.systems), "system", "systems"))
+	default:
+		return fmt.Sprintf("%s changed, rebuilding", files)
+	}
+}
+
+// pluralize formats a count with the singular or plural noun.
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// sortedKeys returns the keys of a set in sorted order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestChangeSetDirtyEntities(t *testing.T) {
+	cs := NewChangeSet("./src")
+
+	cs.Add(FileChangeEvent{Path: "src/orders/system.md", Op: "write"})
+	cs.Add(FileChangeEvent{Path: "src/orders/api/container.md", Op: "write"})
+	cs.Add(FileChangeEvent{Path: "src/orders/api/handler/component.d2", Op: "create"})
+	cs.Add(FileChangeEvent{Path: "src/Billing/system.d2", Op: "write"})
+	cs.Add(FileChangeEvent{Path: "README.md", Op: "write"})
+	cs.Add(FileChangeEvent{Path: "src/orders/system.md", Op: "write"}) // duplicate
+
+	if got := cs.FileCount(); got != 5 {
+		t.Errorf("FileCount() = %d, want 5", got)
+	}
+	if got, want := cs.DirtySystems(), []string{"billing", "orders"}; !slices.Equal(got, want) {
+		t.Errorf("DirtySystems() = %v, want %v", got, want)
+	}
+	if got, want := cs.DirtyContainers(), []string{"orders/api"}; !slices.Equal(got, want) {
+		t.Errorf("DirtyContainers() = %v, want %v", got, want)
+	}
+	if got, want := cs.DirtyComponents(), []string{"orders/api/handler"}; !slices.Equal(got, want) {
+		t.Errorf("DirtyComponents() = %v, want %v", got, want)
+	}
+	if cs.Scope() != RebuildContent {
+		t.Errorf("Scope() = %v, want %v", cs.Scope(), RebuildContent)
+	}
+}
+
+func TestChangeSetCustomSourceDir(t *testing.T) {
+	cs := NewChangeSet("docs/arch")
+	cs.Add(FileChangeEvent{Path: "docs/arch/payments/system.md", Op: "write"})
+	cs.Add(FileChangeEvent{Path: "src/ignored/system.md", Op: "write"})
+
+	if got, want := cs.DirtySystems(), []string{"payments"}; !slices.Equal(got, want) {
+		t.Errorf("DirtySystems() = %v, want %v", got, want)
+	}
+}
+
+func TestChangeSetSourceDirs(t *testing.T) {
+	cs := NewChangeSet("src", "../billing/src")
+	cs.Add(FileChangeEvent{Path: "src/shop/api/container.md", Op: "write"})
+	cs.Add(FileChangeEvent{Path: "../billing/src/invoicing/system.md", Op: "create"})
+
+	if got, want := cs.DirtySystems(), []string{"invoicing", "shop"}; !slices.Equal(got, want) {
+		t.Errorf("DirtySystems() = %v, want %v", got, want)
+	}
+}
+
+func TestChangeSetSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []FileChangeEvent
+		want   string
+	}{
+		{
+			name:   "single file",
+			events: []FileChangeEvent{{Path: "src/orders/system.md"}},
+			want:   "1 file changed, rebuilding 1 system",
+		},
+		{
+			name: "config change",
+			events: []FileChangeEvent{
+				{Path: "src/orders/system.md"},
+				{Path: "loko.toml"},
+			},
+			want: "2 files changed, configuration updated, rebuilding everything",
+		},
+		{
+			name:   "template change",
+			events: []FileChangeEvent{{Path: ".loko/templates/system.html"}},
+			want:   "1 file changed, re-rendering pages",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := NewChangeSet("src")
+			for _, e := range tt.events {
+				cs.Add(e)
+			}
+			if got := cs.Summary(); got != tt.want {
+				t.Errorf("Summary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangeSetBurst(t *testing.T) {
+	cs := NewChangeSet("src")
+	for i := range 37 {
+		cs.Add(FileChangeEvent{Path: fmt.Sprintf("src/sys%d/c%d/component.md", i%5, i), Op: "write"})
+	}
+
+	if got, want := cs.Summary(), "37 files changed, rebuilding 5 systems"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+
+	cs.Reset()
+	if !cs.IsEmpty() || cs.Scope() != RebuildNone || len(cs.DirtySystems()) != 0 {
+		t.Error("expected Reset to clear the change set")
+	}
+}
@@ -0,0 +1,164 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// IssueURLPlaceholder is replaced by the ticket ID in an issue URL template.
+const IssueURLPlaceholder = "{id}"
+
+// IssueKey returns the ticket ID of an issue reference. References are either
+// plain IDs ("PAY-123") or URLs, whose last path segment is taken as the ID.
+func IssueKey(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if !isIssueURL(ref) {
+		return ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	if key := path.Base(strings.TrimRight(u.Path, "/")); key != "." && key != "/" {
+		return key
+	}
+	return ref
+}
+
+// IssueURL returns the link for an issue reference. URLs are returned as is;
+// IDs are substituted for IssueURLPlaceholder in urlTemplate. An ID yields an
+// empty string when no template is configured.
+func IssueURL(ref, urlTemplate string) string {
+	ref = strings.TrimSpace(ref)
+	if isIssueURL(ref) {
+		return ref
+	}
+	if urlTemplate == "" || ref == "" {
+		return ""
+	}
+	if !strings.Contains(urlTemplate, IssueURLPlaceholder) {
+		return strings.TrimRight(urlTemplate, "/") + "/" + url.PathEscape(ref)
+	}
+	return strings.ReplaceAll(urlTemplate, IssueURLPlaceholder, url.PathEscape(ref))
+}
+
+// isIssueURL reports whether an issue reference is a link rather than an ID.
+func isIssueURL(ref string) bool {
+	return strings.HasPrefix(ref, "https://") || strings.HasPrefix(ref, "http://")
+}
+
+// IssueReference is a ticket referenced from an element's frontmatter.
+type IssueReference struct {
+	Element string // Qualified element ID
+	Issue   string // Reference as written in frontmatter
+
+	// Status is the ticket's current state; nil when the lookup failed.
+	Status *IssueStatus
+	// Err is the lookup error, if any.
+	Err error
+}
+
+// IssueReferenceReport lists the issue references that need attention.
+type IssueReferenceReport struct {
+	// Checked is the number of references looked up.
+	Checked int
+	// Stale references point at tickets that are closed, abandoned or deleted.
+	Stale []IssueReference
+	// Failed references could not be checked, e.g. because of an auth error.
+	Failed []IssueReference
+}
+
+// CheckIssueReferences finds elements whose `issues:` frontmatter points at
+// tickets that are no longer open, so stale links can be cleaned up.
+type CheckIssueReferences struct {
+	tracker IssueTracker
+}
+
+// NewCheckIssueReferences creates a new CheckIssueReferences use case.
+func NewCheckIssueReferences(tracker IssueTracker) *CheckIssueReferences {
+	return &CheckIssueReferences{tracker: tracker}
+}
+
+// Execute looks up every issue referenced by the systems, their containers and
+// components. Each ticket is fetched once however many elements reference it.
+// Results are sorted by element and issue.
+func (uc *CheckIssueReferences) Execute(ctx context.Context, systems []*entities.System) (*IssueReferenceReport, error) {
+	if uc.tracker == nil {
+		return nil, fmt.Errorf("issue tracker cannot be nil")
+	}
+
+	refs := collectIssueReferences(systems)
+	report := &IssueReferenceReport{Checked: len(refs)}
+
+	type lookup struct {
+		status *IssueStatus
+		err    error
+	}
+	cache := make(map[string]lookup)
+
+	for _, ref := range refs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		key := IssueKey(ref.Issue)
+		result, ok := cache[key]
+		if !ok {
+			result.status, result.err = uc.tracker.IssueStatus(ctx, key)
+			cache[key] = result
+		}
+		ref.Status, ref.Err = result.status, result.err
+
+		switch {
+		case errors.Is(ref.Err, entities.ErrIssueNotFound):
+			ref.Status = nil
+			report.Stale = append(report.Stale, ref)
+		case ref.Err != nil:
+			ref.Status = nil
+			report.Failed = append(report.Failed, ref)
+		case ref.Status != nil && ref.Status.Closed:
+			report.Stale = append(report.Stale, ref)
+		}
+	}
+
+	return report, nil
+}
+
+// collectIssueReferences returns the issue references of every element,
+// sorted by qualified element ID and issue.
+func collectIssueReferences(systems []*entities.System) []IssueReference {
+	var refs []IssueReference
+	add := func(element string, issues []string) {
+		for _, issue := range issues {
+			if strings.TrimSpace(issue) != "" {
+				refs = append(refs, IssueReference{Element: element, Issue: issue})
+			}
+		}
+	}
+
+	for _, system := range systems {
+		if system == nil {
+			continue
+		}
+		add(system.ID, system.Issues)
+		for _, container := range system.ListContainers() {
+			add(entities.QualifiedNodeID("container", system.ID, container.ID, ""), container.Issues)
+			for _, component := range container.ListComponents() {
+				add(entities.QualifiedNodeID("component", system.ID, container.ID, component.ID), component.Issues)
+			}
+		}
+	}
+
+	slices.SortStableFunc(refs, func(a, b IssueReference) int {
+		return cmp.Or(cmp.Compare(a.Element, b.Element), cmp.Compare(a.Issue, b.Issue))
+	})
+	return refs
+}
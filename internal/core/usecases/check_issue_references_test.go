@@ -0,0 +1,118 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+type mockIssueTracker struct {
+	statuses map[string]*IssueStatus
+	errs     map[string]error
+	calls    map[string]int
+}
+
+func (m *mockIssueTracker) IssueStatus(_ context.Context, key string) (*IssueStatus, error) {
+	if m.calls == nil {
+		m.calls = make(map[string]int)
+	}
+	m.calls[key]++
+	if err, ok := m.errs[key]; ok {
+		return nil, err
+	}
+	if status, ok := m.statuses[key]; ok {
+		return status, nil
+	}
+	return nil, fmt.Errorf("%s: %w", key, entities.ErrIssueNotFound)
+}
+
+func TestIssueURL(t *testing.T) {
+	tests := []struct {
+		ref, template, want string
+	}{
+		{"PAY-123", "https://acme.atlassian.net/browse/{id}", "https://acme.atlassian.net/browse/PAY-123"},
+		{"PAY-123", "https://acme.atlassian.net/browse/", "https://acme.atlassian.net/browse/PAY-123"},
+		{"PAY-123", "", ""},
+		{"https://github.com/acme/pay/issues/4", "https://acme.atlassian.net/browse/{id}", "https://github.com/acme/pay/issues/4"},
+		{"a b", "https://tracker/{id}", "https://tracker/a%20b"},
+	}
+	for _, tt := range tests {
+		if got := IssueURL(tt.ref, tt.template); got != tt.want {
+			t.Errorf("IssueURL(%q, %q) = %q, want %q", tt.ref, tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestIssueKey(t *testing.T) {
+	tests := map[string]string{
+		"PAY-123": "PAY-123",
+		" PAY-9 ": "PAY-9",
+		"https://acme.atlassian.net/browse/PAY-123":  "PAY-123",
+		"https://acme.atlassian.net/browse/PAY-123/": "PAY-123",
+	}
+	for ref, want := range tests {
+		if got := IssueKey(ref); got != want {
+			t.Errorf("IssueKey(%q) = %q, want %q", ref, got, want)
+		}
+	}
+}
+
+func TestCheckIssueReferences(t *testing.T) {
+	system, _ := entities.NewSystem("Payments")
+	system.Issues = []string{"PAY-1"}
+	container, _ := entities.NewContainer("API")
+	container.Issues = []string{"PAY-2"}
+	component, _ := entities.NewComponent("Ledger")
+	component.Issues = []string{"https://acme.atlassian.net/browse/PAY-1", "PAY-3", "PAY-4"}
+	_ = container.AddComponent(component)
+	_ = system.AddContainer(container)
+
+	tracker := &mockIssueTracker{
+		statuses: map[string]*IssueStatus{
+			"PAY-1": {Key: "PAY-1", Status: "Done", Resolution: "Won't Do", Closed: true},
+			"PAY-2": {Key: "PAY-2", Status: "In Progress"},
+		},
+		errs: map[string]error{"PAY-4": errors.New("401 Unauthorized")},
+	}
+
+	report, err := NewCheckIssueReferences(tracker).Execute(context.Background(), []*entities.System{system, nil})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if report.Checked != 5 {
+		t.Errorf("Checked = %d, want 5", report.Checked)
+	}
+	if tracker.calls["PAY-1"] != 1 {
+		t.Errorf("PAY-1 looked up %d times, want 1", tracker.calls["PAY-1"])
+	}
+
+	var stale []string
+	for _, ref := range report.Stale {
+		stale = append(stale, ref.Element+" "+ref.Issue)
+	}
+	want := []string{
+		"payments PAY-1",
+		"payments/api/ledger PAY-3",
+		"payments/api/ledger https://acme.atlassian.net/browse/PAY-1",
+	}
+	if fmt.Sprint(stale) != fmt.Sprint(want) {
+		t.Errorf("Stale = %q, want %q", stale, want)
+	}
+	if report.Stale[1].Status != nil || !errors.Is(report.Stale[1].Err, entities.ErrIssueNotFound) {
+		t.Errorf("missing ticket should have nil status and ErrIssueNotFound, got %+v", report.Stale[1])
+	}
+
+	if len(report.Failed) != 1 || report.Failed[0].Issue != "PAY-4" {
+		t.Errorf("Failed = %+v, want PAY-4", report.Failed)
+	}
+}
+
+func TestCheckIssueReferences_NilTracker(t *testing.T) {
+	if _, err := NewCheckIssueReferences(nil).Execute(context.Background(), nil); err == nil {
+		t.Error("expected error for nil tracker")
+	}
+}
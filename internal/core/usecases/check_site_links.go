@@ -0,0 +1,291 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io/fs"
+	"maps"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// SearchIndexFile is the client-side search index of the HTML site. Its
+// result URLs are relative to the root of the output directory, or absolute
+// when the site has a base path.
+const SearchIndexFile = "search.json"
+
+// SearchShardDir holds the search shards of the HTML site, one per system
+// with containers, listed in the "shards" of the search index. Their file
+// names carry a hash of their content, like the site's assets.
+const SearchShardDir = "search"
+
+// BrokenLink is a link of the built site that does not resolve.
+type BrokenLink struct {
+	Page   string // Page the link is on, slash-separated and relative to the output directory
+	Link   string // The href or src as written
+	Reason string
+}
+
+// LinkCheckReport is the result of checking the links of a built site.
+type LinkCheckReport struct {
+	Pages    int          // HTML pages scanned
+	Links    int          // Internal links and search result URLs checked
+	External int          // Distinct external URLs probed
+	Broken   []BrokenLink // Sorted by page and link
+}
+
+// CheckSiteLinks verifies that the links of a built HTML site resolve: the
+// hrefs and srcs of every page, diagram images included, and the URLs of the
+// search index must name files within the output directory. With a prober,
+// external http(s) links are fetched too.
+type CheckSiteLinks struct {
+	prober   LinkProber
+	basePath string
+}
+
+// NewCheckSiteLinks creates a new CheckSiteLinks use case.
+func NewCheckSiteLinks() *CheckSiteLinks {
+	return &CheckSiteLinks{}
+}
+
+// WithExternalProber checks external links with prober.
+func (uc *CheckSiteLinks) WithExternalProber(prober LinkProber) *CheckSiteLinks {
+	uc.prober = prober
+	return uc
+}
+
+// tagPattern matches an HTML start tag.
+var tagPattern = regexp.MustCompile(`<[a-zA-Z][^>]*>`)
+
+// WithBasePath resolves root-relative links of a site served under basePath,
+// such as "/architecture/", against the output directory.
+func (uc *CheckSiteLinks) WithBasePath(basePath string) *CheckSiteLinks {
+	uc.basePath = strings.Trim(basePath, "/")
+	return uc
+}
+
+// linkAttrPattern matches the href and src attributes of a tag, quoted or not.
+var linkAttrPattern = regexp.MustCompile(`(?i)\s(?:href|src)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+
+// scriptBodyPattern matches the body of script and style elements, whose
+// markup-like strings are not links of the page.
+var scriptBodyPattern = regexp.MustCompile(`(?is)(<(script|style)\b[^>]*>).*?</(?:script|style)\s*>`)
+
+// Execute scans the HTML pages of outputDir and its search index. Each
+// external URL is probed once however many pages link to it.
+func (uc *CheckSiteLinks) Execute(ctx context.Context, outputDir string) (*LinkCheckReport, error) {
+	report := &LinkCheckReport{}
+	external := make(map[string][]BrokenLink) // URL to the links to it
+
+	err := filepath.WalkDir(outputDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(p), ".html") {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(outputDir, p)
+		if err != nil {
+			return err
+		}
+		page := filepath.ToSlash(rel)
+		report.Pages++
+
+		body := scriptBodyPattern.ReplaceAllString(string(content), "$1")
+		for _, tag := range tagPattern.FindAllString(body, -1) {
+			for _, m := range linkAttrPattern.FindAllStringSubmatch(tag, -1) {
+				link := html.UnescapeString(m[1] + m[2] + m[3])
+				switch linkKind(link) {
+				case "internal":
+					report.Links++
+					if reason := uc.resolve(outputDir, path.Dir(page), link); reason != "" {
+						report.Broken = append(report.Broken, BrokenLink{Page: page, Link: link, Reason: reason})
+					}
+				case "external":
+					external[link] = append(external[link], BrokenLink{Page: page, Link: link})
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", outputDir, err)
+	}
+
+	if err := uc.checkSearchIndex(outputDir, report); err != nil {
+		return nil, err
+	}
+
+	if uc.prober != nil {
+		for _, link := range slices.Sorted(maps.Keys(external)) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			report.External++
+			target := link
+			if strings.HasPrefix(target, "//") {
+				target = "https:" + target
+			}
+			if err := uc.prober.Probe(ctx, target); err != nil {
+				for _, broken := range external[link] {
+					broken.Reason = err.Error()
+					report.Broken = append(report.Broken, broken)
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(report.Broken, func(a, b BrokenLink) int {
+		return cmp.Or(cmp.Compare(a.Page, b.Page), cmp.Compare(a.Link, b.Link))
+	})
+	report.Broken = slices.Compact(report.Broken)
+	return report, nil
+}
+
+// checkSearchIndex checks the result URLs of the search index and its shards,
+// if the site has one.
+func (uc *CheckSiteLinks) checkSearchIndex(outputDir string, report *LinkCheckReport) error {
+	content, err := os.ReadFile(filepath.Join(outputDir, SearchIndexFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read search index: %w", err)
+	}
+	var index siteSearchIndex
+	if err := json.Unmarshal(content, &index); err != nil {
+		return fmt.Errorf("failed to parse search index: %w", err)
+	}
+	files := []siteSearchFile{{SearchIndexFile, index.Results}}
+	for _, shard := range index.Shards {
+		file, err := readSearchShard(outputDir, shard.Path)
+		if err != nil {
+			report.Broken = append(report.Broken, BrokenLink{Page: SearchIndexFile, Link: shard.Path, Reason: err.Error()})
+			continue
+		}
+		files = append(files, file)
+	}
+	for _, file := range files {
+		for _, result := range file.results {
+			if linkKind(result.URL) != "internal" {
+				continue
+			}
+			report.Links++
+			if reason := uc.resolve(outputDir, ".", result.URL); reason != "" {
+				report.Broken = append(report.Broken, BrokenLink{Page: file.path, Link: result.URL, Reason: reason})
+			}
+		}
+	}
+	return nil
+}
+
+// siteSearchIndex is the content of search.json and of its shards.
+type siteSearchIndex struct {
+	Results []siteSearchResult `json:"results"`
+	Shards  []struct {
+		Path string `json:"path"` // Relative to the output directory
+	} `json:"shards"`
+}
+
+// siteSearchResult is a result of the search index.
+type siteSearchResult struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// siteSearchFile is the search index or one of its shards with its results.
+type siteSearchFile struct {
+	path    string // Slash-separated, relative to the output directory
+	results []siteSearchResult
+}
+
+// readSearchShard reads the results of a shard listed in the search index.
+func readSearchShard(outputDir, shardPath string) (siteSearchFile, error) {
+	content, err := os.ReadFile(filepath.Join(outputDir, filepath.FromSlash(shardPath)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return siteSearchFile{}, fmt.Errorf("search shard not found in the output directory")
+	}
+	if err != nil {
+		return siteSearchFile{}, fmt.Errorf("failed to read search shard: %w", err)
+	}
+	var shard siteSearchIndex
+	if err := json.Unmarshal(content, &shard); err != nil {
+		return siteSearchFile{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return siteSearchFile{shardPath, shard.Results}, nil
+}
+
+// resolve returns why a link from a page in pageDir does not resolve to a
+// file of outputDir, or "" when it does. Links to a directory resolve to its
+// index.html.
+func (uc *CheckSiteLinks) resolve(outputDir, pageDir, link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "malformed URL"
+	}
+	if u.Path == "" {
+		return "" // A fragment or query of the page itself
+	}
+
+	target := path.Join(pageDir, u.Path)
+	if strings.HasPrefix(u.Path, "/") {
+		target = path.Clean(strings.TrimPrefix(u.Path, "/"))
+		if uc.basePath != "" {
+			rest, ok := strings.CutPrefix(target, uc.basePath)
+			if !ok || (rest != "" && !strings.HasPrefix(rest, "/")) {
+				return "points outside the base path /" + uc.basePath + "/"
+			}
+			target = path.Clean("." + rest)
+		}
+	}
+	if target == ".." || strings.HasPrefix(target, "../") {
+		return "points outside the output directory"
+	}
+
+	full := filepath.Join(outputDir, filepath.FromSlash(target))
+	info, err := os.Stat(full)
+	if err == nil && info.IsDir() {
+		_, err = os.Stat(filepath.Join(full, "index.html"))
+	}
+	if err != nil {
+		return "not found in the output directory"
+	}
+	return ""
+}
+
+// linkKind classifies a link as "internal" (a path within the site),
+// "external" (an http, https or protocol-relative URL) or "" for links that
+// are not checked, such as fragments, mailto: and data: URLs.
+func linkKind(link string) string {
+	link = strings.TrimSpace(link)
+	switch {
+	case link == "" || strings.HasPrefix(link, "#") || strings.HasPrefix(link, "?"):
+		return ""
+	case strings.HasPrefix(link, "//"):
+		return "external"
+	}
+	if i := strings.IndexAny(link, ":/?#"); i > 0 && link[i] == ':' {
+		scheme := strings.ToLower(link[:i])
+		if scheme == "http" || scheme == "https" {
+			return "external"
+		}
+		return ""
+	}
+	return "internal"
+}
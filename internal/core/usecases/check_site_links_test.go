@@ -0,0 +1,102 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeProber fails the URLs of its set and records the URLs probed.
+type fakeProber struct {
+	broken map[string]bool
+	probed []string
+}
+
+func (p *fakeProber) Probe(_ context.Context, url string) error {
+	p.probed = append(p.probed, url)
+	if p.broken[url] {
+		return errors.New("returned 404 Not Found")
+	}
+	return nil
+}
+
+func TestCheckSiteLinks(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"index.html": `<a href="systems/shop.html">Shop</a> <a href="systems/gone.html#api">Gone</a>
+<a href="#top">Top</a> <a href="mailto:team@acme.com">Mail</a> <a href="https://example.com/dead">Dead</a>
+<pre><code>&lt;a href="not-a-link.html"&gt;</code></pre>
+<script>el.innerHTML = '<a href="' + url + '">';</script>`,
+		"systems/shop.html": `<link rel="stylesheet" href="/styles/style.css"><img src="../diagrams/shop.svg">
+<img src=../diagrams/missing.svg> <a href="../../outside.html">Out</a> <a href="/">Home</a>
+<a href="https://example.com/dead">Dead</a> <a href="//cdn.example.com/ok.js">CDN</a>`,
+		"styles/style.css":  "",
+		"diagrams/shop.svg": "<svg/>",
+		"search.json":       `{"results": [{"url": "systems/shop.html#api"}, {"url": "domains/payments.html"}], "shards": [{"path": "search/shop.json"}]}`,
+		"search/shop.json":  `{"results": [{"url": "systems/shop.html#api"}, {"url": "containers/gone.html"}]}`,
+	})
+
+	report, err := NewCheckSiteLinks().Execute(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if report.Pages != 2 || report.External != 0 {
+		t.Errorf("pages = %d, external = %d, want 2 and 0", report.Pages, report.External)
+	}
+	var got []string
+	for _, b := range report.Broken {
+		got = append(got, b.Page+" "+b.Link)
+	}
+	want := []string{
+		"index.html systems/gone.html#api",
+		"search.json domains/payments.html",
+		"search/shop.json containers/gone.html",
+		"systems/shop.html ../../outside.html",
+		"systems/shop.html ../diagrams/missing.svg",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("broken = %q, want %q", got, want)
+	}
+	if !strings.Contains(report.Broken[3].Reason, "outside") {
+		t.Errorf("reason = %q", report.Broken[3].Reason)
+	}
+
+	// External links are probed once each
+	prober := &fakeProber{broken: map[string]bool{"https://example.com/dead": true}}
+	report, err = NewCheckSiteLinks().WithExternalProber(prober).Execute(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if !slices.Equal(prober.probed, []string{"https://cdn.example.com/ok.js", "https://example.com/dead"}) {
+		t.Errorf("probed = %q", prober.probed)
+	}
+	if report.External != 2 || len(report.Broken) != len(want)+2 {
+		t.Errorf("external = %d, broken = %+v", report.External, report.Broken)
+	}
+}
+
+func TestCheckSiteLinks_BasePath(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"index.html":                `<a href="/docs/systems/shop/#api">Shop</a> <a href="/docs/">Home</a> <a href="/other/">Other</a>`,
+		"systems/shop/index.html":   `<a href="/docs/index.html">Home</a> <a href="/docsx/a.html">Prefix</a>`,
+		"diagrams/system-shop.svg":  "<svg/>",
+		SearchIndexFile:             `{"results": [{"url": "/docs/systems/shop/"}]}`,
+		"systems/shop/diagram.html": `<img src="/docs/diagrams/system-shop.svg">`,
+	})
+
+	report, err := NewCheckSiteLinks().WithBasePath("/docs/").Execute(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	var got []string
+	for _, b := range report.Broken {
+		got = append(got, b.Page+" "+b.Link)
+	}
+	want := []string{"index.html /other/", "systems/shop/index.html /docsx/a.html"}
+	if !slices.Equal(got, want) {
+		t.Errorf("broken = %q, want %q", got, want)
+	}
+}
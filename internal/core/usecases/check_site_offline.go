@@ -0,0 +1,136 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ExternalResource is a resource of the built site loaded from another host,
+// which an air-gapped browser cannot fetch.
+type ExternalResource struct {
+	File string // File referencing it, slash-separated and relative to the output directory
+	URL  string
+}
+
+// CheckSiteOffline verifies that a built HTML site works without network
+// access: its pages, stylesheets and SVG diagrams must not load scripts,
+// styles, fonts or images from external URLs. Links the reader follows, such
+// as a footer link to a repository, are not resources and are allowed.
+type CheckSiteOffline struct{}
+
+// NewCheckSiteOffline creates a new CheckSiteOffline use case.
+func NewCheckSiteOffline() *CheckSiteOffline {
+	return &CheckSiteOffline{}
+}
+
+// resourceAttrPattern matches the attributes of a tag that may reference a
+// resource, quoted or not.
+var resourceAttrPattern = regexp.MustCompile(`(?i)\s((?:xlink:)?href|src|poster|data)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+
+// tagNamePattern matches the name of an HTML or SVG start tag.
+var tagNamePattern = regexp.MustCompile(`^<([a-zA-Z][\w:-]*)`)
+
+// styleBodyPattern matches the body of a style element.
+var styleBodyPattern = regexp.MustCompile(`(?is)<style\b[^>]*>(.*?)</style\s*>`)
+
+// cssURLPattern matches the url() references and @import rules of CSS.
+var cssURLPattern = regexp.MustCompile(`(?i)(?:url\(\s*["']?|@import\s+["'])([^"')\s]+)`)
+
+// Execute scans the HTML, SVG and CSS files of outputDir and returns the
+// external resources they load, sorted by file and URL.
+func (uc *CheckSiteOffline) Execute(ctx context.Context, outputDir string) ([]ExternalResource, error) {
+	var found []ExternalResource
+	err := filepath.WalkDir(outputDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(p))
+		if d.IsDir() || (ext != ".html" && ext != ".svg" && ext != ".css") {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(outputDir, p)
+		if err != nil {
+			return err
+		}
+		file := filepath.ToSlash(rel)
+		for _, u := range externalResources(ext, string(content)) {
+			found = append(found, ExternalResource{File: file, URL: u})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", outputDir, err)
+	}
+
+	slices.SortFunc(found, func(a, b ExternalResource) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.URL, b.URL))
+	})
+	return slices.Compact(found), nil
+}
+
+// externalResources returns the external URLs loaded by a file with extension
+// ext (".html", ".svg" or ".css").
+func externalResources(ext, content string) []string {
+	var urls []string
+	addCSS := func(css string) {
+		for _, m := range cssURLPattern.FindAllStringSubmatch(css, -1) {
+			if linkKind(m[1]) == "external" {
+				urls = append(urls, m[1])
+			}
+		}
+	}
+	if ext == ".css" {
+		addCSS(content)
+		return urls
+	}
+
+	for _, m := range styleBodyPattern.FindAllStringSubmatch(content, -1) {
+		addCSS(m[1])
+	}
+	body := scriptBodyPattern.ReplaceAllString(content, "$1")
+	for _, tag := range tagPattern.FindAllString(body, -1) {
+		name := strings.ToLower(tagNamePattern.FindStringSubmatch(tag)[1])
+		for _, m := range resourceAttrPattern.FindAllStringSubmatch(tag, -1) {
+			link := strings.TrimSpace(html.UnescapeString(m[2] + m[3] + m[4]))
+			if isResourceAttr(name, strings.ToLower(m[1])) && linkKind(link) == "external" {
+				urls = append(urls, link)
+			}
+		}
+		if i := strings.Index(strings.ToLower(tag), " style="); i >= 0 {
+			addCSS(html.UnescapeString(tag[i:]))
+		}
+	}
+	return urls
+}
+
+// isResourceAttr reports whether attribute attr of a tag named name loads a
+// resource rather than linking to a page: any src or poster, the data of an
+// object, and the href of link elements and of SVG images and uses.
+func isResourceAttr(name, attr string) bool {
+	switch attr {
+	case "src", "poster":
+		return true
+	case "data":
+		return name == "object"
+	}
+	switch name {
+	case "link", "image", "use", "script", "feimage":
+		return true
+	}
+	return false
+}
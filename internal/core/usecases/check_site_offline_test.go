@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestCheckSiteOffline(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"index.html": `<link rel="stylesheet" href="styles/style.css"><link rel="icon" href="https://cdn.example.com/favicon.ico">
+<script defer src="https://plausible.io/js/script.js"></script>
+<script>fetch("https://api.example.com/" + id); el.innerHTML = '<img src="https://example.com/x.png">';</script>
+<style>@import "https://fonts.example.com/inter.css"; body { background: url(img/bg.png) }</style>
+<a href="https://github.com/madstone-tech/loko">loko</a> <img src="data:image/svg+xml;base64,PHN2Zy8+">
+<div style="background-image: url('//cdn.example.com/bg.png')"></div>`,
+		"styles/style.css": `@font-face { src: url("https://fonts.example.com/inter.woff2") } a { background: url(../img/a.png) }`,
+		"diagrams/shop.svg": `<svg xmlns="http://www.w3.org/2000/svg"><image href="https://icons.example.com/go.svg"/>
+<image xlink:href="data:image/png;base64,AAAA"/><a href="https://example.com/docs"><text>Docs</text></a></svg>`,
+		"js/main.js": `fetch("https://api.example.com/")`,
+	})
+
+	found, err := NewCheckSiteOffline().Execute(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	var got []string
+	for _, r := range found {
+		got = append(got, r.File+" "+r.URL)
+	}
+	want := []string{
+		"diagrams/shop.svg https://icons.example.com/go.svg",
+		"index.html //cdn.example.com/bg.png",
+		"index.html https://cdn.example.com/favicon.ico",
+		"index.html https://fonts.example.com/inter.css",
+		"index.html https://plausible.io/js/script.js",
+		"styles/style.css https://fonts.example.com/inter.woff2",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("external resources = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,131 @@
+package usecases
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// DefaultCostCurrency is the currency of cost reports when [costs] in
+// loko.toml names none.
+const DefaultCostCurrency = "USD"
+
+// CostReport is the rollup of the monthly costs declared by containers.
+type CostReport struct {
+	Currency      string             `json:"currency"`
+	Total         float64            `json:"total"`
+	Environments  []string           `json:"environments"`   // Sorted; "" (not attributed to an environment) last when present
+	ByEnvironment map[string]float64 `json:"by_environment"` // Total per environment
+	Systems       []SystemCost       `json:"systems"`        // Systems with at least one cost, by ID
+	Unpriced      []string           `json:"unpriced"`       // Containers of internal systems without a cost, as system/container
+	Invalid       []string           `json:"invalid"`        // Malformed cost annotations, as "system/container: error"
+}
+
+// SystemCost is the monthly cost of a system and its containers.
+type SystemCost struct {
+	ID            string             `json:"id"`
+	Name          string             `json:"name"`
+	Total         float64            `json:"total"`
+	ByEnvironment map[string]float64 `json:"by_environment"`
+	Containers    []ContainerCost    `json:"containers"` // Containers with a cost, by ID
+}
+
+// ContainerCost is the monthly cost of a container.
+type ContainerCost struct {
+	ID            string             `json:"id"` // Slash-separated system/container ID
+	Name          string             `json:"name"`
+	Total         float64            `json:"total"`
+	ByEnvironment map[string]float64 `json:"by_environment"`
+}
+
+// BuildCostReport aggregates the monthly_cost fields and cost: tags of
+// containers per container, system and environment, so the architecture
+// doubles as a high-level FinOps inventory.
+type BuildCostReport struct {
+	currency string
+}
+
+// NewBuildCostReport creates a BuildCostReport use case reporting in the
+// currency of [costs] in config.
+func NewBuildCostReport(config *entities.ProjectConfig) *BuildCostReport {
+	uc := &BuildCostReport{currency: DefaultCostCurrency}
+	if config != nil && config.CostCurrency != "" {
+		uc.currency = config.CostCurrency
+	}
+	return uc
+}
+
+// Execute builds the cost report of systems. Containers of external systems
+// are not priced by the project; their costs are included when declared, but
+// missing ones are not reported as unpriced.
+func (uc *BuildCostReport) Execute(_ context.Context, systems []*entities.System) *CostReport {
+	report := &CostReport{Currency: uc.currency, ByEnvironment: make(map[string]float64)}
+	sorted := slices.DeleteFunc(slices.Clone(systems), func(s *entities.System) bool { return s == nil })
+	slices.SortFunc(sorted, func(a, b *entities.System) int { return strings.Compare(a.ID, b.ID) })
+
+	for _, system := range sorted {
+		systemCost := SystemCost{ID: system.ID, Name: system.Name, ByEnvironment: make(map[string]float64)}
+		for _, container := range system.ListContainers() {
+			id := system.ID + "/" + container.ID
+			costs, err := container.MonthlyCosts()
+			if err != nil {
+				report.Invalid = append(report.Invalid, id+": "+err.Error())
+				continue
+			}
+			if len(costs) == 0 {
+				if !system.External {
+					report.Unpriced = append(report.Unpriced, id)
+				}
+				continue
+			}
+
+			containerCost := ContainerCost{ID: id, Name: container.Name, ByEnvironment: make(map[string]float64)}
+			for _, cost := range costs {
+				containerCost.Total += cost.Amount
+				containerCost.ByEnvironment[cost.Environment] += cost.Amount
+				systemCost.ByEnvironment[cost.Environment] += cost.Amount
+				report.ByEnvironment[cost.Environment] += cost.Amount
+			}
+			systemCost.Total += containerCost.Total
+			systemCost.Containers = append(systemCost.Containers, containerCost)
+		}
+		if len(systemCost.Containers) > 0 {
+			report.Total += systemCost.Total
+			report.Systems = append(report.Systems, systemCost)
+		}
+	}
+
+	report.Environments = slices.SortedFunc(maps.Keys(report.ByEnvironment), func(a, b string) int {
+		if (a == "") != (b == "") {
+			if a == "" {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(a, b)
+	})
+	return report
+}
+
+// HasCosts reports whether any container declares a cost.
+func (r *CostReport) HasCosts() bool {
+	return r != nil && len(r.Systems) > 0
+}
+
+// FormatCostAmount formats a cost amount with two decimals and thousands
+// separators, such as "1,200.00".
+func FormatCostAmount(amount float64) string {
+	whole, cents, _ := strings.Cut(strconv.FormatFloat(amount, 'f', 2, 64), ".")
+	var sb strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String() + "." + cents
+}
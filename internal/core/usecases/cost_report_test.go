@@ -0,0 +1,66 @@
+package usecases
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestBuildCostReport(t *testing.T) {
+	payments, _ := entities.NewSystem("Payments")
+	api, _ := entities.NewContainer("API")
+	api.Tags = []string{"cost:prod=1,200", "cost:staging=300"}
+	database, _ := entities.NewContainer("Database")
+	database.Metadata = map[string]any{entities.MonthlyCostField: 500}
+	worker, _ := entities.NewContainer("Worker")
+	broken, _ := entities.NewContainer("Broken")
+	broken.Tags = []string{"cost:lots"}
+	_ = payments.AddContainer(api)
+	_ = payments.AddContainer(database)
+	_ = payments.AddContainer(worker)
+	_ = payments.AddContainer(broken)
+
+	stripe, _ := entities.NewSystem("Stripe")
+	stripe.External = true
+	gateway, _ := entities.NewContainer("Gateway")
+	_ = stripe.AddContainer(gateway)
+
+	config := entities.DefaultProjectConfig()
+	config.CostCurrency = "EUR"
+	report := NewBuildCostReport(config).Execute(context.Background(), []*entities.System{stripe, payments})
+
+	if report.Currency != "EUR" || report.Total != 2000 {
+		t.Errorf("Currency, Total = %q, %v", report.Currency, report.Total)
+	}
+	if !slices.Equal(report.Environments, []string{"prod", "staging", ""}) {
+		t.Errorf("Environments = %q", report.Environments)
+	}
+	if report.ByEnvironment["prod"] != 1200 || report.ByEnvironment[""] != 500 {
+		t.Errorf("ByEnvironment = %v", report.ByEnvironment)
+	}
+	if len(report.Systems) != 1 || report.Systems[0].ID != "payments" || len(report.Systems[0].Containers) != 2 {
+		t.Fatalf("Systems = %+v", report.Systems)
+	}
+	if c := report.Systems[0].Containers[0]; c.ID != "payments/api" || c.Total != 1500 || c.ByEnvironment["staging"] != 300 {
+		t.Errorf("api cost = %+v", c)
+	}
+	// External containers are never unpriced
+	if !slices.Equal(report.Unpriced, []string{"payments/worker"}) {
+		t.Errorf("Unpriced = %q", report.Unpriced)
+	}
+	if len(report.Invalid) != 1 || report.Invalid[0] != `payments/broken: invalid cost tag "cost:lots": expected a number` {
+		t.Errorf("Invalid = %q", report.Invalid)
+	}
+
+	if NewBuildCostReport(nil).Execute(context.Background(), nil).Currency != DefaultCostCurrency {
+		t.Error("expected the default currency without config")
+	}
+
+	for amount, want := range map[float64]string{0: "0.00", 999.5: "999.50", 1200: "1,200.00", 1234567.891: "1,234,567.89"} {
+		if got := FormatCostAmount(amount); got != want {
+			t.Errorf("FormatCostAmount(%v) = %q, want %q", amount, got, want)
+		}
+	}
+}
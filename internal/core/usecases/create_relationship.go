@@ -35,6 +35,9 @@ type CreateRelationshipRequest struct {
 
 	// Direction is "forward" or "bidirectional" (optional).
 	Direction string
+
+	// Kind is the semantic relationship kind, e.g. "reads" or "publishes" (optional).
+	Kind string
 }
 
 // CreateRelationship creates a new C4 model relationship between two elements,
@@ -43,7 +46,8 @@ type CreateRelationshipRequest struct {
 // The operation is idempotent: if a relationship with the same source+target+label
 // already exists (determined by ID hash), it is returned without error.
 type CreateRelationship struct {
-	repo RelationshipRepository
+	repo  RelationshipRepository
+	kinds *ValidateRelationshipKinds
 }
 
 // NewCreateRelationship creates a new CreateRelationship use case.
@@ -51,6 +55,12 @@ func NewCreateRelationship(repo RelationshipRepository) *CreateRelationship {
 	return &CreateRelationship{repo: repo}
 }
 
+// WithKinds rejects relationships whose kind is not one of kinds.
+func (uc *CreateRelationship) WithKinds(kinds *ValidateRelationshipKinds) *CreateRelationship {
+	uc.kinds = kinds
+	return uc
+}
+
 // Execute creates and persists a relationship, then updates the D2 diagram.
 // Returns the created (or existing duplicate) Relationship.
 func (uc *CreateRelationship) Execute(
@@ -67,11 +77,19 @@ func (uc *CreateRelationship) Execute(
 	if req.Direction != "" {
 		opts = append(opts, entities.WithRelDirection(req.Direction))
 	}
+	if req.Kind != "" {
+		opts = append(opts, entities.WithRelKind(req.Kind))
+	}
 
 	rel, err := entities.NewRelationship(req.Source, req.Target, req.Label, opts...)
 	if err != nil {
 		return nil, err
 	}
+	if uc.kinds != nil {
+		if err := uc.kinds.CheckKind(rel.Kind); err != nil {
+			return nil, err
+		}
+	}
 
 	// 2. Load existing relationships for the system.
 	existing, err := uc.repo.LoadRelationships(ctx, req.ProjectRoot, req.SystemID)
@@ -108,15 +126,16 @@ func (uc *CreateRelationship) Execute(
 func D2DiagramPath(projectRoot, systemID string, rel *entities.Relationship) string {
 	srcParts := strings.Split(rel.Source, "/")
 	tgtParts := strings.Split(rel.Target, "/")
+	systemDir := filepath.Join(projectRoot, "src", filepath.FromSlash(entities.SystemSourcePath(systemID)))
 
 	// Same container (3-segment paths, same system + container)
 	if len(srcParts) == 3 && len(tgtParts) == 3 &&
 		srcParts[0] == tgtParts[0] && srcParts[1] == tgtParts[1] {
-		return filepath.Join(projectRoot, "src", systemID, srcParts[1], "container.d2")
+		return filepath.Join(systemDir, srcParts[1], "container.d2")
 	}
 
 	// Everything else → system.d2
-	return filepath.Join(projectRoot, "src", systemID, "system.d2")
+	return filepath.Join(systemDir, "system.d2")
 }
 
 // updateD2File regenerates the edges section of the target D2 file from the
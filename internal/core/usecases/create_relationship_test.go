@@ -3,6 +3,7 @@ package usecases
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
@@ -146,6 +147,34 @@ func TestCreateRelationship_WithOptions(t *testing.T) {
 	}
 }
 
+func TestCreateRelationship_WithKinds(t *testing.T) {
+	repo := newMockRelationshipRepository()
+	kinds, _ := NewValidateRelationshipKinds(nil)
+	uc := NewCreateRelationship(repo).WithKinds(kinds)
+	ctx := context.Background()
+
+	req := &CreateRelationshipRequest{
+		ProjectRoot: "/tmp/proj",
+		SystemID:    "sys",
+		Source:      "sys/api",
+		Target:      "sys/db",
+		Label:       "loads orders",
+		Kind:        " Reads ",
+	}
+	rel, err := uc.Execute(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rel.Kind != "reads" {
+		t.Errorf("Kind: got %q, want %q", rel.Kind, "reads")
+	}
+
+	req.Kind = "calls"
+	if _, err := uc.Execute(ctx, req); err == nil || !strings.Contains(err.Error(), "unknown relationship kind") {
+		t.Errorf("expected unknown kind error, got %v", err)
+	}
+}
+
 func TestCreateRelationship_RepoLoadError(t *testing.T) {
 	repo := newMockRelationshipRepository()
 	repo.LoadErr = errors.New("disk failure")
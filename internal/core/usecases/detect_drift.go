@@ -3,7 +3,9 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
@@ -66,8 +68,8 @@ func (uc *DetectDrift) Execute(ctx context.Context, req *DetectDriftRequest) (*D
 
 	// Check each system, container, and component
 	for _, system := range systems {
-		for _, container := range system.Containers {
-			for _, component := range container.Components {
+		for _, container := range system.ListContainers() {
+			for _, component := range container.ListComponents() {
 				componentsChecked++
 
 				// Check orphaned relationships
@@ -122,7 +124,7 @@ func (uc *DetectDrift) checkOrphanedRelationships(component *entities.Component,
 	}
 
 	// Check each relationship
-	for targetID := range component.Relationships {
+	for _, targetID := range slices.Sorted(maps.Keys(component.Relationships)) {
 		if !allComponentIDs[targetID] {
 			issue := entities.NewDriftIssue(
 				component.ID,
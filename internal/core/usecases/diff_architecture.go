@@ -0,0 +1,128 @@
+package usecases
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// ArchitectureDiff lists the elements and dependencies that differ between
+// two versions of an architecture. Element IDs are qualified graph node IDs.
+type ArchitectureDiff struct {
+	AddedSystems      []string
+	RemovedSystems    []string
+	AddedContainers   []string
+	RemovedContainers []string
+	AddedComponents   []string
+	RemovedComponents []string
+
+	AddedDependencies   []Dependency
+	RemovedDependencies []Dependency
+}
+
+// Dependency is a relationship edge between two qualified element IDs.
+type Dependency struct {
+	Source      string
+	Target      string
+	Description string
+}
+
+// IsEmpty reports whether the two architectures are structurally identical.
+func (d *ArchitectureDiff) IsEmpty() bool {
+	return len(d.AddedSystems) == 0 && len(d.RemovedSystems) == 0 &&
+		len(d.AddedContainers) == 0 && len(d.RemovedContainers) == 0 &&
+		len(d.AddedComponents) == 0 && len(d.RemovedComponents) == 0 &&
+		len(d.AddedDependencies) == 0 && len(d.RemovedDependencies) == 0
+}
+
+// DiffArchitecture compares the base and head architecture graphs. A nil graph
+// is treated as empty. All lists are sorted for stable output.
+func DiffArchitecture(base, head *entities.ArchitectureGraph) *ArchitectureDiff {
+	if base == nil {
+		base = entities.NewArchitectureGraph()
+	}
+	if head == nil {
+		head = entities.NewArchitectureGraph()
+	}
+
+	diff := &ArchitectureDiff{}
+	for id, node := range head.Nodes {
+		if _, ok := base.Nodes[id]; !ok {
+			diff.appendNode(node.Type, id, true)
+		}
+	}
+	for id, node := range base.Nodes {
+		if _, ok := head.Nodes[id]; !ok {
+			diff.appendNode(node.Type, id, false)
+		}
+	}
+
+	baseDeps := dependencies(base)
+	headDeps := dependencies(head)
+	for key, dep := range headDeps {
+		if _, ok := baseDeps[key]; !ok {
+			diff.AddedDependencies = append(diff.AddedDependencies, dep)
+		}
+	}
+	for key, dep := range baseDeps {
+		if _, ok := headDeps[key]; !ok {
+			diff.RemovedDependencies = append(diff.RemovedDependencies, dep)
+		}
+	}
+
+	for _, ids := range [][]string{
+		diff.AddedSystems, diff.RemovedSystems,
+		diff.AddedContainers, diff.RemovedContainers,
+		diff.AddedComponents, diff.RemovedComponents,
+	} {
+		slices.Sort(ids)
+	}
+	byEndpoints := func(a, b Dependency) int {
+		return cmp.Or(cmp.Compare(a.Source, b.Source), cmp.Compare(a.Target, b.Target))
+	}
+	slices.SortFunc(diff.AddedDependencies, byEndpoints)
+	slices.SortFunc(diff.RemovedDependencies, byEndpoints)
+
+	return diff
+}
+
+// appendNode records an added or removed node under its C4 level.
+func (d *ArchitectureDiff) appendNode(nodeType, id string, added bool) {
+	var list *[]string
+	switch nodeType {
+	case "system":
+		list = &d.RemovedSystems
+		if added {
+			list = &d.AddedSystems
+		}
+	case "container":
+		list = &d.RemovedContainers
+		if added {
+			list = &d.AddedContainers
+		}
+	case "component":
+		list = &d.RemovedComponents
+		if added {
+			list = &d.AddedComponents
+		}
+	default:
+		return
+	}
+	*list = append(*list, id)
+}
+
+// dependencies indexes a graph's relationship edges by "source->target".
+func dependencies(graph *entities.ArchitectureGraph) map[string]Dependency {
+	deps := make(map[string]Dependency)
+	for _, edges := range graph.Edges {
+		for _, edge := range edges {
+			deps[edge.Source+"->"+edge.Target] = Dependency{
+				Source:      edge.Source,
+				Target:      edge.Target,
+				Description: edge.Description,
+			}
+		}
+	}
+	return deps
+}
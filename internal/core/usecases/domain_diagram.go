@@ -0,0 +1,119 @@
+package usecases
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// GenerateDomainDiagram generates the D2 source of the diagram of a domain:
+// its nested domains and systems as nodes, connected by the relationships of
+// their components. An empty domainID generates the landscape diagram of the
+// top-level domains and systems.
+//
+// Edges are aggregated: one edge per pair of nodes, labelled with the number
+// of relationships it stands for. graph may be nil, in which case the diagram
+// has no edges.
+func GenerateDomainDiagram(domainID string, systems []*entities.System, graph *entities.ArchitectureGraph) string {
+	// nodeOf maps each system inside domainID to the node it is drawn in
+	nodeOf := make(map[string]string)
+	labels := make(map[string]string)
+	domains := make(map[string]bool)
+	for _, sys := range systems {
+		if sys == nil || !inDomain(sys.Domain, domainID) {
+			continue
+		}
+		node := sys.ID
+		if sys.Domain != domainID {
+			node = childDomain(sys.Domain, domainID)
+			domains[node] = true
+			labels[node] = entities.DomainName(node)
+		} else {
+			labels[node] = sys.Name
+		}
+		nodeOf[sys.ID] = node
+	}
+
+	type pair struct{ source, target string }
+	counts := make(map[pair]int)
+	if graph != nil {
+		for _, edges := range graph.Edges {
+			for _, edge := range edges {
+				source, _ := entities.ParseQualifiedID(edge.Source)
+				target, _ := entities.ParseQualifiedID(edge.Target)
+				if len(source) == 0 || len(target) == 0 {
+					continue
+				}
+				from, okFrom := nodeOf[source[0]]
+				to, okTo := nodeOf[target[0]]
+				if okFrom && okTo && from != to {
+					counts[pair{from, to}]++
+				}
+			}
+		}
+	}
+
+	var sb strings.Builder
+	if domainID == "" {
+		sb.WriteString("# Domain Landscape\n\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("# Domain: %s\n\n", domainID))
+	}
+	sb.WriteString("direction: right\n\n")
+
+	for _, node := range slices.Sorted(maps.Keys(labels)) {
+		// Keys are quoted: D2 would nest the segments of a dotted ID
+		sb.WriteString(fmt.Sprintf("%q: %q {\n", node, labels[node]))
+		if domains[node] {
+			sb.WriteString("  shape: package\n")
+		}
+		sb.WriteString("}\n")
+	}
+
+	edges := slices.SortedFunc(maps.Keys(counts), func(a, b pair) int {
+		return strings.Compare(a.source+"\x00"+a.target, b.source+"\x00"+b.target)
+	})
+	if len(edges) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, e := range edges {
+		label := "1 relationship"
+		if counts[e] > 1 {
+			label = fmt.Sprintf("%d relationships", counts[e])
+		}
+		sb.WriteString(fmt.Sprintf("%q -> %q: %q\n", e.source, e.target, label))
+	}
+	return sb.String()
+}
+
+// DomainDiagramFile returns the SVG file name of the diagram of a domain, or
+// of the landscape diagram for an empty domainID.
+func DomainDiagramFile(domainID string) string {
+	if domainID == "" {
+		return "domains.svg"
+	}
+	return fmt.Sprintf("domain_%s.svg", domainID)
+}
+
+// inDomain reports whether the domain of a system is domainID or nested in it.
+func inDomain(systemDomain, domainID string) bool {
+	return domainID == "" || systemDomain == domainID ||
+		strings.HasPrefix(systemDomain, domainID+entities.DomainSeparator)
+}
+
+// childDomain returns the domain directly inside domainID that contains
+// systemDomain.
+func childDomain(systemDomain, domainID string) string {
+	rest := systemDomain
+	if domainID != "" {
+		rest = strings.TrimPrefix(systemDomain, domainID+entities.DomainSeparator)
+	}
+	first, _, _ := strings.Cut(rest, entities.DomainSeparator)
+	if domainID == "" {
+		return first
+	}
+	return domainID + entities.DomainSeparator + first
+}
@@ -0,0 +1,106 @@
+package usecases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// domainSystems returns a top-level system and three systems in nested
+// domains of payments.
+func domainSystems() []*entities.System {
+	var systems []*entities.System
+	for id, name := range map[string]string{
+		"web":                    "Web",
+		"payments.billing":       "Billing",
+		"payments.cards.issuing": "Issuing",
+		"payments.cards.auth":    "Auth",
+	} {
+		systems = append(systems, &entities.System{ID: id, Name: name, Domain: entities.SystemDomain(id)})
+	}
+	return systems
+}
+
+func TestGenerateDomainDiagram(t *testing.T) {
+	graph := entities.NewArchitectureGraph()
+	for _, edge := range []struct{ source, target string }{
+		{"web/ui/checkout", "payments.billing/api/invoices"},
+		{"payments.billing/api/invoices", "payments.cards.issuing/api/cards"},
+		{"payments.billing/api/ledger", "payments.cards.auth/api/tokens"},
+		{"payments.cards.issuing/api/cards", "payments.cards.auth/api/tokens"},
+	} {
+		graph.Edges[edge.source] = append(graph.Edges[edge.source], &entities.GraphEdge{Source: edge.source, Target: edge.target})
+	}
+
+	tests := []struct {
+		name     string
+		domainID string
+		want     []string
+		notWant  []string
+	}{
+		{
+			name:     "landscape",
+			domainID: "",
+			want: []string{
+				`"payments": "Payments" {`, "shape: package", `"web": "Web" {`,
+				`"web" -> "payments": "1 relationship"`,
+			},
+			notWant: []string{`"payments.billing"`},
+		},
+		{
+			name:     "domain",
+			domainID: "payments",
+			want: []string{
+				"# Domain: payments", `"payments.billing": "Billing" {`, `"payments.cards": "Cards" {`,
+				`"payments.billing" -> "payments.cards": "2 relationships"`,
+			},
+			notWant: []string{`"web"`},
+		},
+		{
+			name:     "nested domain",
+			domainID: "payments.cards",
+			want: []string{
+				`"payments.cards.auth": "Auth" {`, `"payments.cards.issuing": "Issuing" {`,
+				`"payments.cards.issuing" -> "payments.cards.auth": "1 relationship"`,
+			},
+			notWant: []string{"shape: package", `"payments.billing"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GenerateDomainDiagram(tt.domainID, domainSystems(), graph)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("diagram missing %s:\n%s", want, got)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("diagram contains %s:\n%s", notWant, got)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildDocsRendersDomainDiagrams(t *testing.T) {
+	renderer := &MockDiagramRenderer{}
+	uc := NewBuildDocs(renderer, &MockSiteBuilder{}, &MockProgressReporter{})
+	outputDir := t.TempDir()
+
+	if err := uc.Execute(context.Background(), &entities.Project{Name: "p"}, domainSystems(), outputDir); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	for _, file := range []string{"domains.svg", "domain_payments.svg", "domain_payments.cards.svg"} {
+		if _, err := os.Stat(filepath.Join(outputDir, "diagrams", file)); err != nil {
+			t.Errorf("%s not rendered: %v", file, err)
+		}
+	}
+	if got := renderer.renderCount.Load(); got != 3 {
+		t.Errorf("expected 3 domain diagram renders, got %d", got)
+	}
+}
@@ -0,0 +1,171 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// EncryptedExt is appended to the name of a source file encrypted at rest,
+// e.g. "system.md" becomes "system.md.age".
+const EncryptedExt = ".age"
+
+// encryptableExts lists the source file types holding architecture details.
+var encryptableExts = []string{".md", ".d2"}
+
+// EncryptProject encrypts a project's Markdown and D2 sources in place so
+// sensitive infrastructure details are not stored in plaintext. Each file is
+// replaced by its encrypted counterpart with EncryptedExt appended.
+type EncryptProject struct {
+	encrypter FileEncrypter
+}
+
+// NewEncryptProject creates a new EncryptProject use case.
+func NewEncryptProject(encrypter FileEncrypter) *EncryptProject {
+	return &EncryptProject{encrypter: encrypter}
+}
+
+// Execute encrypts every plaintext source file under sourceDir and returns the
+// paths of the encrypted files. Files that are already encrypted are skipped,
+// so it is safe to run again after adding new elements.
+func (uc *EncryptProject) Execute(ctx context.Context, sourceDir string) ([]string, error) {
+	if uc.encrypter == nil {
+		return nil, fmt.Errorf("encrypter cannot be nil")
+	}
+
+	return transformSources(ctx, sourceDir, isPlaintextSource, func(path string, data []byte) (string, []byte, error) {
+		ciphertext, err := uc.encrypter.Encrypt(ctx, data)
+		return path + EncryptedExt, ciphertext, err
+	})
+}
+
+// DecryptProject restores the plaintext sources of a project encrypted with
+// EncryptProject.
+type DecryptProject struct {
+	encrypter FileEncrypter
+}
+
+// NewDecryptProject creates a new DecryptProject use case.
+func NewDecryptProject(encrypter FileEncrypter) *DecryptProject {
+	return &DecryptProject{encrypter: encrypter}
+}
+
+// Execute decrypts every encrypted source file under sourceDir and returns the
+// paths of the restored plaintext files.
+func (uc *DecryptProject) Execute(ctx context.Context, sourceDir string) ([]string, error) {
+	if uc.encrypter == nil {
+		return nil, fmt.Errorf("encrypter cannot be nil")
+	}
+
+	return transformSources(ctx, sourceDir, isEncryptedSource, func(path string, data []byte) (string, []byte, error) {
+		plaintext, err := uc.encrypter.Decrypt(ctx, data)
+		return strings.TrimSuffix(path, EncryptedExt), plaintext, err
+	})
+}
+
+// HasEncryptedSources reports whether any source file under sourceDir is
+// encrypted. A missing sourceDir has none.
+func HasEncryptedSources(sourceDir string) (bool, error) {
+	found := false
+	err := filepath.WalkDir(sourceDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && path == sourceDir {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if !d.IsDir() && isEncryptedSource(path) {
+			found = true
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to scan source directory: %w", err)
+	}
+	return found, nil
+}
+
+// ErrEncryptedSources is returned by EnsurePlaintextAllowed for a project
+// whose sources are encrypted at rest.
+var ErrEncryptedSources = errors.New("project sources are encrypted; refusing to produce unencrypted output")
+
+// EnsurePlaintextAllowed refuses to write or serve decrypted documentation for
+// a project with encrypted sources in any of its source directories, unless
+// allow is set. Every caller that builds or serves a project checks it first.
+func EnsurePlaintextAllowed(project *entities.Project, projectRoot string, allow bool) error {
+	if allow || project == nil {
+		return nil
+	}
+	for _, dir := range project.SourceDirs() {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(projectRoot, dir)
+		}
+		encrypted, err := HasEncryptedSources(dir)
+		if err != nil {
+			return err
+		}
+		if encrypted {
+			return ErrEncryptedSources
+		}
+	}
+	return nil
+}
+
+// isPlaintextSource reports whether path is a source file that can be encrypted.
+func isPlaintextSource(path string) bool {
+	return slices.Contains(encryptableExts, filepath.Ext(path))
+}
+
+// isEncryptedSource reports whether path is an encrypted source file.
+func isEncryptedSource(path string) bool {
+	return strings.HasSuffix(path, EncryptedExt) && isPlaintextSource(strings.TrimSuffix(path, EncryptedExt))
+}
+
+// transformSources rewrites each file under sourceDir selected by match. The
+// new file is written before the original is removed, so an interrupted run
+// never loses content. Paths are processed in lexical order.
+func transformSources(
+	ctx context.Context,
+	sourceDir string,
+	match func(path string) bool,
+	transform func(path string, data []byte) (string, []byte, error),
+) ([]string, error) {
+	var written []string
+	err := filepath.WalkDir(sourceDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !match(path) {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		target, content, err := transform(path, data)
+		if err != nil {
+			return fmt.Errorf("failed to process %s: %w", path, err)
+		}
+		if err := os.WriteFile(target, content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", target, err)
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		written = append(written, target)
+		return nil
+	})
+	return written, err
+}
@@ -0,0 +1,141 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// prefixEncrypter is a reversible stand-in for a real cipher.
+type prefixEncrypter struct{}
+
+var cipherPrefix = []byte("encrypted:")
+
+func (prefixEncrypter) Encrypt(_ context.Context, plaintext []byte) ([]byte, error) {
+	return append(slices.Clone(cipherPrefix), plaintext...), nil
+}
+
+func (prefixEncrypter) Decrypt(_ context.Context, ciphertext []byte) ([]byte, error) {
+	plaintext, ok := bytes.CutPrefix(ciphertext, cipherPrefix)
+	if !ok {
+		return nil, errors.New("not encrypted")
+	}
+	return plaintext, nil
+}
+
+func TestEncryptProjectRoundTrip(t *testing.T) {
+	srcDir := t.TempDir()
+	files := map[string]string{
+		"payments/system.md":                  "---\nname: Payments\n---\n",
+		"payments/payments.d2":                "api -> db",
+		"payments/api/container.md":           "---\nname: API\n---\n",
+		"payments/relationships.toml":         "[[relationships]]\n",
+		"payments/api/ledger/notes.txt":       "not a source file",
+		"payments/api/ledger/component.md":    "---\nname: Ledger\n---\n",
+		"payments/api/ledger/component.d2.md": "odd but still markdown",
+	}
+	for name, content := range files {
+		path := filepath.Join(srcDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if found, err := HasEncryptedSources(srcDir); err != nil || found {
+		t.Fatalf("HasEncryptedSources before encrypt = %v, %v; want false", found, err)
+	}
+
+	ctx := context.Background()
+	encrypted, err := NewEncryptProject(prefixEncrypter{}).Execute(ctx, srcDir)
+	if err != nil {
+		t.Fatalf("EncryptProject failed: %v", err)
+	}
+	if len(encrypted) != 5 {
+		t.Errorf("encrypted %d files, want 5: %v", len(encrypted), encrypted)
+	}
+
+	systemMd := filepath.Join(srcDir, "payments/system.md")
+	if _, err := os.Stat(systemMd); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("plaintext system.md should be removed, stat err = %v", err)
+	}
+	if data, _ := os.ReadFile(systemMd + EncryptedExt); !bytes.HasPrefix(data, cipherPrefix) {
+		t.Errorf("system.md.age = %q, want encrypted content", data)
+	}
+	for _, untouched := range []string{"payments/relationships.toml", "payments/api/ledger/notes.txt"} {
+		if _, err := os.Stat(filepath.Join(srcDir, untouched)); err != nil {
+			t.Errorf("%s should be left as is: %v", untouched, err)
+		}
+	}
+
+	if found, err := HasEncryptedSources(srcDir); err != nil || !found {
+		t.Fatalf("HasEncryptedSources after encrypt = %v, %v; want true", found, err)
+	}
+
+	// Running again only encrypts new plaintext files.
+	again, err := NewEncryptProject(prefixEncrypter{}).Execute(ctx, srcDir)
+	if err != nil || len(again) != 0 {
+		t.Errorf("second EncryptProject = %v, %v; want nothing to do", again, err)
+	}
+
+	decrypted, err := NewDecryptProject(prefixEncrypter{}).Execute(ctx, srcDir)
+	if err != nil {
+		t.Fatalf("DecryptProject failed: %v", err)
+	}
+	if len(decrypted) != 5 {
+		t.Errorf("decrypted %d files, want 5", len(decrypted))
+	}
+	for name, content := range files {
+		data, err := os.ReadFile(filepath.Join(srcDir, name))
+		if err != nil || string(data) != content {
+			t.Errorf("%s = %q, %v; want original content", name, data, err)
+		}
+	}
+}
+
+func TestHasEncryptedSourcesMissingDir(t *testing.T) {
+	found, err := HasEncryptedSources(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || found {
+		t.Errorf("HasEncryptedSources = %v, %v; want false, nil", found, err)
+	}
+}
+
+func TestEncryptProjectNilEncrypter(t *testing.T) {
+	if _, err := NewEncryptProject(nil).Execute(context.Background(), t.TempDir()); err == nil {
+		t.Error("expected error for nil encrypter")
+	}
+	if _, err := NewDecryptProject(nil).Execute(context.Background(), t.TempDir()); err == nil {
+		t.Error("expected error for nil encrypter")
+	}
+}
+
+func TestEnsurePlaintextAllowedSourceDirs(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Only an extra source directory holds encrypted sources
+	extra := filepath.Join(root, "services", "billing")
+	if err := os.MkdirAll(extra, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(extra, "system.md.age"), []byte("age"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	project := &entities.Project{Name: "p", Config: &entities.ProjectConfig{SourceDir: "src", SourceDirs: []string{"services"}}}
+
+	if err := EnsurePlaintextAllowed(project, root, false); !errors.Is(err, ErrEncryptedSources) {
+		t.Errorf("EnsurePlaintextAllowed = %v, want ErrEncryptedSources", err)
+	}
+	if err := EnsurePlaintextAllowed(project, root, true); err != nil {
+		t.Errorf("EnsurePlaintextAllowed with allow = %v, want nil", err)
+	}
+}
@@ -18,6 +18,7 @@ import (
 //   - The focal component is visually highlighted (accent fill + thicker border).
 //   - All intra-container relationships (from every component's Relationships map) are
 //     rendered as directed edges with labels.
+//   - Sub-components are drawn nested inside their parent component.
 //   - Code annotations and external dependencies for the focal component are appended.
 type EnhanceComponentDiagram struct{}
 
@@ -53,7 +54,9 @@ func (uc *EnhanceComponentDiagram) Execute(
 	sb.WriteString("direction: right\n\n")
 
 	// Emit all sibling components as labelled nodes.
-	// Sort for deterministic output.
+	// Sort for deterministic output. Sub-components have dotted IDs
+	// ("handler.validator"), which D2 draws inside their parent component;
+	// sorting declares each parent before its sub-components.
 	components := container.ListComponents()
 	sort.Slice(components, func(i, j int) bool {
 		return components[i].ID < components[j].ID
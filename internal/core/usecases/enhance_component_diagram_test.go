@@ -356,3 +356,26 @@ func TestEnhanceComponentDiagramWithQuotesInDescription(t *testing.T) {
 		t.Error("Enhanced diagram failed to escape quotes in description")
 	}
 }
+
+func TestEnhanceComponentDiagramNestsSubComponents(t *testing.T) {
+	system, container, auth, _, _ := buildTestScaffold()
+	jwt, _ := entities.NewComponent("JWT")
+	jwt.SetParent(auth.ID)
+	jwt.AddRelationship("auth-cache", "Caches tokens")
+	_ = container.AddComponent(jwt)
+
+	uc := NewEnhanceComponentDiagram()
+	result, err := uc.Execute(jwt, container, system)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	parent := strings.Index(result, "authentication: \"Authentication\" {")
+	child := strings.Index(result, "authentication.jwt: \"JWT\" {")
+	if parent < 0 || child < parent {
+		t.Errorf("expected the sub-component declared after its parent:\n%s", result)
+	}
+	if !strings.Contains(result, "authentication.jwt -> auth-cache: \"Caches tokens\"") {
+		t.Errorf("expected the sub-component's relationship edge:\n%s", result)
+	}
+}
@@ -0,0 +1,294 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// CorpusChunk is one entry of a corpus for retrieval-augmented generation:
+// the text of one element, ready to embed, and the metadata describing it.
+type CorpusChunk struct {
+	ID            string               `json:"id"`   // Qualified ID, e.g. "payments/api"
+	Type          string               `json:"type"` // "system", "container" or "component"
+	Name          string               `json:"name"`
+	Breadcrumbs   []string             `json:"breadcrumbs"` // Names from the project down to the element
+	Technology    string               `json:"technology,omitempty"`
+	Tags          []string             `json:"tags,omitempty"`
+	Relationships []CorpusRelationship `json:"relationships,omitempty"`
+	Source        string               `json:"source,omitempty"` // Markdown file the element is documented in
+	Text          string               `json:"-"`
+}
+
+// CorpusRelationship is a relationship of the element of a chunk.
+type CorpusRelationship struct {
+	Direction   string `json:"direction"` // "outgoing" or "incoming"
+	ID          string `json:"id"`        // Qualified ID of the related element
+	Description string `json:"description,omitempty"`
+}
+
+// placeholderPattern matches the template placeholders of element Markdown
+// files, such as {{container_table}}.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*\w+\s*\}\}`)
+
+// ExportCorpus writes the architecture as a chunked corpus for vector stores,
+// so teams can build assistants grounded on their model.
+//
+// Every system, container and component is one chunk whose text stands on its
+// own: the element's name and place in the hierarchy, its fields, its
+// children, its relationships and the prose of its Markdown file. Chunks are
+// in model order, systems by ID with their containers and components, so
+// repeated exports of an unchanged project are identical.
+type ExportCorpus struct {
+	writer       CorpusWriter
+	graphBuilder *BuildArchitectureGraph
+	redaction    *RedactArchitecture               // Optional: masks relationship descriptions and prose
+	readSource   func(path string) ([]byte, error) // Reads element Markdown files
+}
+
+// NewExportCorpus creates a new ExportCorpus use case. Relationships are
+// taken from component frontmatter unless WithGraphBuilder supplies a builder
+// that also reads D2 files or relationships.toml.
+func NewExportCorpus(writer CorpusWriter) *ExportCorpus {
+	return &ExportCorpus{
+		writer:       writer,
+		graphBuilder: NewBuildArchitectureGraph(),
+		readSource:   os.ReadFile,
+	}
+}
+
+// WithGraphBuilder sets the graph builder used to collect relationships.
+func (uc *ExportCorpus) WithGraphBuilder(graphBuilder *BuildArchitectureGraph) *ExportCorpus {
+	uc.graphBuilder = graphBuilder
+	return uc
+}
+
+// WithRedaction masks relationship descriptions and the prose of Markdown
+// files, which the redacted systems do not cover.
+func (uc *ExportCorpus) WithRedaction(redaction *RedactArchitecture) *ExportCorpus {
+	uc.redaction = redaction
+	return uc
+}
+
+// WithSourceReader sets the function used to read element Markdown files, so
+// sources encrypted at rest can be decrypted while exporting.
+func (uc *ExportCorpus) WithSourceReader(read func(path string) ([]byte, error)) *ExportCorpus {
+	uc.readSource = read
+	return uc
+}
+
+// Execute builds the chunks and writes them to outputDir. It returns the
+// paths of the files written.
+func (uc *ExportCorpus) Execute(
+	ctx context.Context,
+	project *entities.Project,
+	systems []*entities.System,
+	outputDir string,
+) ([]string, error) {
+	if outputDir == "" {
+		return nil, fmt.Errorf("output directory cannot be empty")
+	}
+
+	chunks, err := uc.Chunks(ctx, project, systems)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := uc.writer.WriteCorpus(ctx, outputDir, chunks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write corpus: %w", err)
+	}
+	return paths, nil
+}
+
+// Chunks returns one chunk per system, container and component.
+func (uc *ExportCorpus) Chunks(
+	ctx context.Context,
+	project *entities.Project,
+	systems []*entities.System,
+) ([]CorpusChunk, error) {
+	model, err := NewExportExternalModel(nil).
+		WithGraphBuilder(uc.graphBuilder).
+		WithRedaction(uc.redaction).
+		Model(ctx, project, systems)
+	if err != nil {
+		return nil, err
+	}
+
+	outgoing := make(map[string][]CorpusRelationship)
+	incoming := make(map[string][]CorpusRelationship)
+	for _, rel := range model.Relationships {
+		outgoing[rel.Source] = append(outgoing[rel.Source], CorpusRelationship{Direction: "outgoing", ID: rel.Target, Description: rel.Description})
+		incoming[rel.Target] = append(incoming[rel.Target], CorpusRelationship{Direction: "incoming", ID: rel.Source, Description: rel.Description})
+	}
+	names := make(map[string]string)
+	for _, system := range model.Systems {
+		names[system.ID] = system.Name
+		for _, container := range system.ListContainers() {
+			containerID := entities.QualifiedNodeID("container", system.ID, container.ID, "")
+			names[containerID] = container.Name
+			for _, component := range container.ListComponents() {
+				names[entities.QualifiedNodeID("component", system.ID, container.ID, component.ID)] = component.Name
+			}
+		}
+	}
+
+	var chunks []CorpusChunk
+	add := func(chunk CorpusChunk, fields [][2]string, children []string, markdownFile string) {
+		chunk.Relationships = slices.Concat(outgoing[chunk.ID], incoming[chunk.ID])
+		if markdownFile != "" {
+			chunk.Source = uc.sourcePath(project, markdownFile)
+		}
+		chunk.Text = uc.chunkText(chunk, fields, children, names, markdownFile)
+		chunks = append(chunks, chunk)
+	}
+
+	for _, system := range model.Systems {
+		crumbs := []string{project.Name, system.Name}
+		containers := system.ListContainers()
+		var children []string
+		for _, container := range containers {
+			children = append(children, container.Name)
+		}
+		external := ""
+		if system.External {
+			external = "yes"
+		}
+		add(CorpusChunk{ID: system.ID, Type: "system", Name: system.Name, Breadcrumbs: crumbs, Tags: system.Tags}, [][2]string{
+			{"Description", system.Description},
+			{"External", external},
+			{"Domain", system.Domain},
+			{"Language", system.PrimaryLanguage},
+			{"Framework", system.Framework},
+			{"Database", system.Database},
+			{"Responsibilities", strings.Join(system.Responsibilities, "; ")},
+			{"Key users", strings.Join(system.KeyUsers, ", ")},
+			{"External systems", strings.Join(system.ExternalSystems, ", ")},
+		}, children, markdownPath(system.Path, "system.md"))
+
+		for _, container := range containers {
+			containerID := entities.QualifiedNodeID("container", system.ID, container.ID, "")
+			containerCrumbs := append(crumbs[:len(crumbs):len(crumbs)], container.Name)
+			components := container.ListComponents()
+			var componentNames []string
+			for _, component := range components {
+				componentNames = append(componentNames, component.Name)
+			}
+			add(CorpusChunk{ID: containerID, Type: "container", Name: container.Name, Breadcrumbs: containerCrumbs, Technology: container.Technology, Tags: container.Tags}, [][2]string{
+				{"Description", container.Description},
+				{"Technology", container.Technology},
+			}, componentNames, markdownPath(container.Path, "container.md"))
+
+			for _, component := range components {
+				add(CorpusChunk{
+					ID:          entities.QualifiedNodeID("component", system.ID, container.ID, component.ID),
+					Type:        "component",
+					Name:        component.Name,
+					Breadcrumbs: append(containerCrumbs[:len(containerCrumbs):len(containerCrumbs)], component.Name),
+					Technology:  component.Technology,
+					Tags:        component.Tags,
+				}, [][2]string{
+					{"Description", component.Description},
+					{"Technology", component.Technology},
+				}, nil, markdownPath(component.Path, "component.md"))
+			}
+		}
+	}
+	return chunks, nil
+}
+
+// chunkText returns the text of chunk: a heading placing the element in the
+// hierarchy, its non-empty fields, its children and relationships, then the
+// prose of markdownFile.
+func (uc *ExportCorpus) chunkText(chunk CorpusChunk, fields [][2]string, children []string, names map[string]string, markdownFile string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s (%s)\n\n", chunk.Name, chunk.Type)
+	fmt.Fprintf(&sb, "Location: %s\n", strings.Join(chunk.Breadcrumbs, " > "))
+	fmt.Fprintf(&sb, "ID: %s\n", chunk.ID)
+	for _, field := range fields {
+		if field[1] != "" {
+			fmt.Fprintf(&sb, "%s: %s\n", field[0], field[1])
+		}
+	}
+	if len(chunk.Tags) > 0 {
+		fmt.Fprintf(&sb, "Tags: %s\n", strings.Join(chunk.Tags, ", "))
+	}
+	if len(children) > 0 {
+		kind := map[string]string{"system": "Containers", "container": "Components"}[chunk.Type]
+		fmt.Fprintf(&sb, "%s: %s\n", kind, strings.Join(children, ", "))
+	}
+
+	if len(chunk.Relationships) > 0 {
+		sb.WriteString("\nRelationships:\n")
+		for _, rel := range chunk.Relationships {
+			verb := "Uses"
+			if rel.Direction == "incoming" {
+				verb = "Used by"
+			}
+			fmt.Fprintf(&sb, "- %s %s (%s)", verb, cmp.Or(names[rel.ID], rel.ID), rel.ID)
+			if rel.Description != "" {
+				fmt.Fprintf(&sb, ": %s", rel.Description)
+			}
+			sb.WriteByte('\n')
+		}
+	}
+
+	if prose := uc.prose(markdownFile); prose != "" {
+		sb.WriteString("\n")
+		sb.WriteString(prose)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// prose returns the Markdown of path without its frontmatter and template
+// placeholders, or "" when it cannot be read.
+func (uc *ExportCorpus) prose(path string) string {
+	if path == "" || uc.readSource == nil {
+		return ""
+	}
+	content, err := uc.readSource(path)
+	if err != nil {
+		return ""
+	}
+	body := string(content)
+	if rest, ok := strings.CutPrefix(body, "---\n"); ok {
+		if _, after, found := strings.Cut(rest, "\n---"); found {
+			body = after
+			if i := strings.IndexByte(body, '\n'); i >= 0 {
+				body = body[i+1:]
+			}
+		}
+	}
+	body = strings.TrimSpace(placeholderPattern.ReplaceAllString(body, ""))
+	if uc.redaction != nil {
+		body = uc.redaction.MaskText(body)
+	}
+	return body
+}
+
+// sourcePath returns path relative to the project root, with forward slashes.
+func (uc *ExportCorpus) sourcePath(project *entities.Project, path string) string {
+	if project.Path != "" {
+		if rel, err := filepath.Rel(project.Path, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// markdownPath returns the Markdown file name in the element directory dir,
+// or "" for an element not loaded from disk.
+func markdownPath(dir, name string) string {
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, name)
+}
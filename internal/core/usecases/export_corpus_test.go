@@ -0,0 +1,82 @@
+package usecases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// mockCorpusWriter records the chunks it is asked to write.
+type mockCorpusWriter struct {
+	chunks []CorpusChunk
+}
+
+func (m *mockCorpusWriter) WriteCorpus(_ context.Context, outputDir string, chunks []CorpusChunk) ([]string, error) {
+	m.chunks = chunks
+	return []string{outputDir + "/chunks.jsonl"}, nil
+}
+
+func TestExportCorpusChunks(t *testing.T) {
+	project, systems := inventoryFixture()
+	project.Path = "/srv/shop"
+	systems[0].Path = "/srv/shop/src/payments"
+	sources := map[string]string{
+		"/srv/shop/src/payments/system.md": "---\nname: Payments\n---\n\n# Payments\n\nSettles card payments nightly.\n\n{{container_table}}\n",
+	}
+	read := func(path string) ([]byte, error) {
+		if content, ok := sources[path]; ok {
+			return []byte(content), nil
+		}
+		return nil, os.ErrNotExist
+	}
+
+	writer := &mockCorpusWriter{}
+	if _, err := NewExportCorpus(writer).WithSourceReader(read).Execute(context.Background(), project, systems, "dist"); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	var ids []string
+	for _, chunk := range writer.chunks {
+		ids = append(ids, chunk.ID)
+	}
+	want := "auth payments payments/api payments/api/handler payments/api/ledger payments/worker"
+	if strings.Join(ids, " ") != want {
+		t.Fatalf("chunk IDs = %v, want %s", ids, want)
+	}
+
+	payments := writer.chunks[1]
+	if payments.Source != filepath.ToSlash("src/payments/system.md") {
+		t.Errorf("Source = %q", payments.Source)
+	}
+	for _, text := range []string{
+		"# Payments (system)",
+		"Location: inventory > Payments",
+		"Description: Handles payments",
+		"Tags: core, pci",
+		"Containers: API, Worker",
+		"Settles card payments nightly.",
+	} {
+		if !strings.Contains(payments.Text, text) {
+			t.Errorf("system text lacks %q:\n%s", text, payments.Text)
+		}
+	}
+	if strings.Contains(payments.Text, "{{") || strings.Contains(payments.Text, "name: Payments") {
+		t.Errorf("system text keeps frontmatter or placeholders:\n%s", payments.Text)
+	}
+
+	handler := writer.chunks[3]
+	if strings.Join(handler.Breadcrumbs, " > ") != "inventory > Payments > API > Handler" {
+		t.Errorf("Breadcrumbs = %v", handler.Breadcrumbs)
+	}
+	if len(handler.Relationships) != 1 || handler.Relationships[0].Direction != "outgoing" || handler.Relationships[0].ID != "payments/api/ledger" {
+		t.Errorf("Relationships = %+v", handler.Relationships)
+	}
+	if !strings.Contains(handler.Text, "- Uses Ledger (payments/api/ledger): records payment") {
+		t.Errorf("handler text lacks its relationship:\n%s", handler.Text)
+	}
+	if ledger := writer.chunks[4]; !strings.Contains(ledger.Text, "- Used by Handler (payments/api/handler)") {
+		t.Errorf("ledger text lacks its incoming relationship:\n%s", ledger.Text)
+	}
+}
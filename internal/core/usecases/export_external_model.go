@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// ExternalModel is the architecture handed to an ExternalModelExporter: the
+// project with its systems sorted by ID and the relationships between their
+// elements. An ExternalModelParser returns one without a project, with the
+// Warnings about what it could not map.
+type ExternalModel struct {
+	Project       *entities.Project
+	Systems       []*entities.System
+	Relationships []ExternalRelationship
+	Warnings      []string
+}
+
+// ExternalRelationship relates two elements of an ExternalModel by their
+// qualified IDs: "system", "system/container" or "system/container/component".
+type ExternalRelationship struct {
+	Source        string
+	Target        string
+	Description   string
+	Technology    string // Set by parsers only
+	Bidirectional bool   // Set by parsers only
+}
+
+// ExportExternalModel writes the architecture in the language of another
+// modelling tool, for teams that keep their models in both.
+type ExportExternalModel struct {
+	exporter     ExternalModelExporter
+	graphBuilder *BuildArchitectureGraph
+	redaction    *RedactArchitecture // Optional: masks relationship descriptions
+}
+
+// NewExportExternalModel creates a new ExportExternalModel use case.
+// Relationships are taken from component frontmatter unless WithGraphBuilder
+// supplies a builder that also reads D2 files or relationships.toml.
+func NewExportExternalModel(exporter ExternalModelExporter) *ExportExternalModel {
+	return &ExportExternalModel{
+		exporter:     exporter,
+		graphBuilder: NewBuildArchitectureGraph(),
+	}
+}
+
+// WithGraphBuilder sets the graph builder used to collect relationships.
+func (uc *ExportExternalModel) WithGraphBuilder(graphBuilder *BuildArchitectureGraph) *ExportExternalModel {
+	uc.graphBuilder = graphBuilder
+	return uc
+}
+
+// WithRedaction masks relationship descriptions, which may come from
+// relationships.toml rather than the already redacted systems.
+func (uc *ExportExternalModel) WithRedaction(redaction *RedactArchitecture) *ExportExternalModel {
+	uc.redaction = redaction
+	return uc
+}
+
+// Execute writes the model of project and systems to w.
+func (uc *ExportExternalModel) Execute(
+	ctx context.Context,
+	project *entities.Project,
+	systems []*entities.System,
+	w io.Writer,
+) error {
+	model, err := uc.Model(ctx, project, systems)
+	if err != nil {
+		return err
+	}
+	if err := uc.exporter.ExportModel(ctx, model, w); err != nil {
+		return fmt.Errorf("failed to export model: %w", err)
+	}
+	return nil
+}
+
+// Model returns the model handed to the exporter.
+func (uc *ExportExternalModel) Model(
+	ctx context.Context,
+	project *entities.Project,
+	systems []*entities.System,
+) (*ExternalModel, error) {
+	if project == nil {
+		return nil, fmt.Errorf("project cannot be nil")
+	}
+
+	systems = slices.DeleteFunc(slices.Clone(systems), func(s *entities.System) bool { return s == nil })
+	slices.SortFunc(systems, func(a, b *entities.System) int { return cmp.Compare(a.ID, b.ID) })
+
+	graph, err := uc.graphBuilder.Execute(ctx, project, systems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build architecture graph: %w", err)
+	}
+
+	model := &ExternalModel{Project: project, Systems: systems}
+	for _, row := range relationshipSheet(graph).Rows {
+		description := row[3]
+		if uc.redaction != nil {
+			description = uc.redaction.MaskText(description)
+		}
+		model.Relationships = append(model.Relationships, ExternalRelationship{
+			Source:      row[0],
+			Target:      row[1],
+			Description: description,
+		})
+	}
+	return model, nil
+}
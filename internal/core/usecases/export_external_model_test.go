@@ -0,0 +1,60 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+// mockModelExporter records the model it is asked to export.
+type mockModelExporter struct {
+	model *ExternalModel
+	err   error
+}
+
+func (m *mockModelExporter) ExportModel(_ context.Context, model *ExternalModel, w io.Writer) error {
+	m.model = model
+	if m.err != nil {
+		return m.err
+	}
+	_, err := io.WriteString(w, model.Project.Name)
+	return err
+}
+
+func TestExportExternalModel(t *testing.T) {
+	project, systems := inventoryFixture()
+	exporter := &mockModelExporter{}
+
+	var out bytes.Buffer
+	if err := NewExportExternalModel(exporter).Execute(context.Background(), project, systems, &out); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if out.String() != "inventory" {
+		t.Errorf("output = %q, want the exporter's", out.String())
+	}
+
+	model := exporter.model
+	if len(model.Systems) != 2 || model.Systems[0].ID != "auth" || model.Systems[1].ID != "payments" {
+		t.Fatalf("expected non-nil systems sorted by ID, got %v", model.Systems)
+	}
+	want := ExternalRelationship{Source: "payments/api/handler", Target: "payments/api/ledger", Description: "records payment"}
+	if len(model.Relationships) != 1 || model.Relationships[0] != want {
+		t.Errorf("relationships = %+v, want [%+v]", model.Relationships, want)
+	}
+}
+
+func TestExportExternalModelErrors(t *testing.T) {
+	project, systems := inventoryFixture()
+
+	if err := NewExportExternalModel(&mockModelExporter{}).Execute(context.Background(), nil, systems, io.Discard); err == nil {
+		t.Error("expected error for nil project")
+	}
+
+	failure := errors.New("disk full")
+	err := NewExportExternalModel(&mockModelExporter{err: failure}).Execute(context.Background(), project, systems, io.Discard)
+	if !errors.Is(err, failure) {
+		t.Errorf("expected exporter error to be wrapped, got %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// InventorySheet is one table of an architecture inventory, such as the list
+// of systems or components. Every row has one value per header column.
+type InventorySheet struct {
+	Name   string // File stem, e.g. "systems"
+	Header []string
+	Rows   [][]string
+}
+
+// Inventory sheet columns. The order is part of the export format: new columns
+// are only ever appended so spreadsheets and CMDB imports keep working.
+var (
+	systemColumns       = []string{"id", "name", "description", "external", "language", "framework", "database", "tags", "containers", "components"}
+	containerColumns    = []string{"id", "system_id", "name", "description", "technology", "tags", "components"}
+	componentColumns    = []string{"id", "system_id", "container_id", "name", "description", "technology", "tags", "dependencies"}
+	relationshipColumns = []string{"source", "target", "type", "description"}
+)
+
+// listSeparator joins multi-valued fields such as tags within a single cell.
+const listSeparator = ";"
+
+// ExportInventory writes the architecture as flat inventory sheets for
+// architects who track their estate in spreadsheets and CMDBs.
+//
+// Rows are sorted by ID so repeated exports of an unchanged project are
+// identical. Containers and components use qualified IDs ("system/container",
+// "system/container/component") so they stay unique across systems.
+type ExportInventory struct {
+	writer       InventoryWriter
+	graphBuilder *BuildArchitectureGraph
+	redaction    *RedactArchitecture // Optional: masks relationship descriptions
+}
+
+// NewExportInventory creates a new ExportInventory use case. Relationships are
+// taken from component frontmatter unless WithGraphBuilder supplies a builder
+// that also reads D2 files or relationships.toml.
+func NewExportInventory(writer InventoryWriter) *ExportInventory {
+	return &ExportInventory{
+		writer:       writer,
+		graphBuilder: NewBuildArchitectureGraph(),
+	}
+}
+
+// WithGraphBuilder sets the graph builder used to collect relationships.
+func (uc *ExportInventory) WithGraphBuilder(graphBuilder *BuildArchitectureGraph) *ExportInventory {
+	uc.graphBuilder = graphBuilder
+	return uc
+}
+
+// WithRedaction masks relationship descriptions, which may come from
+// relationships.toml rather than the already redacted systems.
+func (uc *ExportInventory) WithRedaction(redaction *RedactArchitecture) *ExportInventory {
+	uc.redaction = redaction
+	return uc
+}
+
+// Execute builds the inventory sheets and writes them to outputDir.
+// It returns the paths of the files written.
+func (uc *ExportInventory) Execute(
+	ctx context.Context,
+	project *entities.Project,
+	systems []*entities.System,
+	outputDir string,
+) ([]string, error) {
+	if outputDir == "" {
+		return nil, fmt.Errorf("output directory cannot be empty")
+	}
+
+	sheets, err := uc.Sheets(ctx, project, systems)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := uc.writer.WriteInventory(ctx, outputDir, sheets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write inventory: %w", err)
+	}
+	return paths, nil
+}
+
+// Sheets returns the systems, containers, components and relationships sheets.
+func (uc *ExportInventory) Sheets(
+	ctx context.Context,
+	project *entities.Project,
+	systems []*entities.System,
+) ([]InventorySheet, error) {
+	if project == nil {
+		return nil, fmt.Errorf("project cannot be nil")
+	}
+
+	systems = slices.DeleteFunc(slices.Clone(systems), func(s *entities.System) bool { return s == nil })
+	slices.SortFunc(systems, func(a, b *entities.System) int { return cmp.Compare(a.ID, b.ID) })
+
+	graph, err := uc.graphBuilder.Execute(ctx, project, systems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build architecture graph: %w", err)
+	}
+
+	systemSheet := InventorySheet{Name: "systems", Header: systemColumns}
+	containerSheet := InventorySheet{Name: "containers", Header: containerColumns}
+	componentSheet := InventorySheet{Name: "components", Header: componentColumns}
+
+	for _, system := range systems {
+		containers := system.ListContainers()
+		componentCount := 0
+
+		for _, container := range containers {
+			containerID := entities.QualifiedNodeID("container", system.ID, container.ID, "")
+			components := container.ListComponents()
+			componentCount += len(components)
+
+			containerSheet.Rows = append(containerSheet.Rows, []string{
+				containerID,
+				system.ID,
+				container.Name,
+				container.Description,
+				container.Technology,
+				strings.Join(container.Tags, listSeparator),
+				strconv.Itoa(len(components)),
+			})
+
+			for _, component := range components {
+				componentSheet.Rows = append(componentSheet.Rows, []string{
+					entities.QualifiedNodeID("component", system.ID, container.ID, component.ID),
+					system.ID,
+					containerID,
+					component.Name,
+					component.Description,
+					component.Technology,
+					strings.Join(component.Tags, listSeparator),
+					strings.Join(component.Dependencies, listSeparator),
+				})
+			}
+		}
+
+		systemSheet.Rows = append(systemSheet.Rows, []string{
+			system.ID,
+			system.Name,
+			system.Description,
+			strconv.FormatBool(system.External),
+			system.PrimaryLanguage,
+			system.Framework,
+			system.Database,
+			strings.Join(system.Tags, listSeparator),
+			strconv.Itoa(len(containers)),
+			strconv.Itoa(componentCount),
+		})
+	}
+
+	relationships := relationshipSheet(graph)
+	if uc.redaction != nil {
+		for _, row := range relationships.Rows {
+			row[3] = uc.redaction.MaskText(row[3])
+		}
+	}
+
+	return []InventorySheet{systemSheet, containerSheet, componentSheet, relationships}, nil
+}
+
+// relationshipSheet lists the graph's relationship edges sorted by source and target.
+func relationshipSheet(graph *entities.ArchitectureGraph) InventorySheet {
+	sheet := InventorySheet{Name: "relationships", Header: relationshipColumns}
+
+	var edges []*entities.GraphEdge
+	for _, outgoing := range graph.Edges {
+		edges = append(edges, outgoing...)
+	}
+	slices.SortFunc(edges, func(a, b *entities.GraphEdge) int {
+		return cmp.Or(cmp.Compare(a.Source, b.Source), cmp.Compare(a.Target, b.Target), cmp.Compare(a.Type, b.Type))
+	})
+
+	for _, edge := range edges {
+		sheet.Rows = append(sheet.Rows, []string{edge.Source, edge.Target, edge.Type, edge.Description})
+	}
+	return sheet
+}
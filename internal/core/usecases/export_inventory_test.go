@@ -0,0 +1,128 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// mockInventoryWriter records the sheets it is asked to write.
+type mockInventoryWriter struct {
+	outputDir string
+	sheets    []InventorySheet
+	err       error
+}
+
+func (m *mockInventoryWriter) WriteInventory(_ context.Context, outputDir string, sheets []InventorySheet) ([]string, error) {
+	m.outputDir = outputDir
+	m.sheets = sheets
+	if m.err != nil {
+		return nil, m.err
+	}
+	paths := make([]string, len(sheets))
+	for i, sheet := range sheets {
+		paths[i] = outputDir + "/" + sheet.Name + ".csv"
+	}
+	return paths, nil
+}
+
+func inventoryFixture() (*entities.Project, []*entities.System) {
+	project, _ := entities.NewProject("inventory")
+
+	payments, _ := entities.NewSystem("Payments")
+	payments.SetDescription("Handles payments")
+	payments.AddTag("core")
+	payments.AddTag("pci")
+	worker, _ := entities.NewContainer("Worker")
+	api, _ := entities.NewContainer("API")
+	api.SetTechnology("Go")
+	handler, _ := entities.NewComponent("Handler")
+	ledger, _ := entities.NewComponent("Ledger")
+	handler.AddRelationship("ledger", "records payment")
+	_ = api.AddComponent(ledger)
+	_ = api.AddComponent(handler)
+	_ = payments.AddContainer(worker)
+	_ = payments.AddContainer(api)
+
+	auth, _ := entities.NewSystem("Auth")
+
+	return project, []*entities.System{payments, nil, auth}
+}
+
+func TestExportInventorySheets(t *testing.T) {
+	project, systems := inventoryFixture()
+
+	sheets, err := NewExportInventory(&mockInventoryWriter{}).Sheets(context.Background(), project, systems)
+	if err != nil {
+		t.Fatalf("Sheets failed: %v", err)
+	}
+
+	var names []string
+	for _, sheet := range sheets {
+		names = append(names, sheet.Name)
+		for i, row := range sheet.Rows {
+			if len(row) != len(sheet.Header) {
+				t.Errorf("%s row %d has %d values, want %d", sheet.Name, i, len(row), len(sheet.Header))
+			}
+		}
+	}
+	if !slices.Equal(names, []string{"systems", "containers", "components", "relationships"}) {
+		t.Fatalf("unexpected sheets: %v", names)
+	}
+
+	want := map[string][][]string{
+		"systems": {
+			{"auth", "Auth", "", "false", "", "", "", "", "0", "0"},
+			{"payments", "Payments", "Handles payments", "false", "", "", "", "core;pci", "2", "2"},
+		},
+		"containers": {
+			{"payments/api", "payments", "API", "", "Go", "", "2"},
+			{"payments/worker", "payments", "Worker", "", "", "", "0"},
+		},
+		"components": {
+			{"payments/api/handler", "payments", "payments/api", "Handler", "", "", "", ""},
+			{"payments/api/ledger", "payments", "payments/api", "Ledger", "", "", "", ""},
+		},
+		"relationships": {
+			{"payments/api/handler", "payments/api/ledger", "depends-on", "records payment"},
+		},
+	}
+	for _, sheet := range sheets {
+		if !slices.EqualFunc(sheet.Rows, want[sheet.Name], slices.Equal[[]string]) {
+			t.Errorf("%s rows = %q, want %q", sheet.Name, sheet.Rows, want[sheet.Name])
+		}
+	}
+}
+
+func TestExportInventoryExecute(t *testing.T) {
+	project, systems := inventoryFixture()
+	writer := &mockInventoryWriter{}
+
+	paths, err := NewExportInventory(writer).Execute(context.Background(), project, systems, "out")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if writer.outputDir != "out" || len(writer.sheets) != 4 || len(paths) != 4 {
+		t.Errorf("unexpected write: dir=%q sheets=%d paths=%v", writer.outputDir, len(writer.sheets), paths)
+	}
+}
+
+func TestExportInventoryErrors(t *testing.T) {
+	project, systems := inventoryFixture()
+	ctx := context.Background()
+
+	if _, err := NewExportInventory(&mockInventoryWriter{}).Execute(ctx, nil, systems, "out"); err == nil {
+		t.Error("expected error for nil project")
+	}
+	if _, err := NewExportInventory(&mockInventoryWriter{}).Execute(ctx, project, systems, ""); err == nil {
+		t.Error("expected error for empty output directory")
+	}
+
+	writeErr := errors.New("disk full")
+	if _, err := NewExportInventory(&mockInventoryWriter{err: writeErr}).Execute(ctx, project, systems, "out"); !errors.Is(err, writeErr) {
+		t.Errorf("expected wrapped write error, got %v", err)
+	}
+}
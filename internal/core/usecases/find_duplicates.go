@@ -0,0 +1,161 @@
+package usecases
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// Thresholds above which two components are reported as likely duplicates.
+const (
+	duplicateNameThreshold        = 0.8 // Edit-distance similarity of the names
+	duplicateDescriptionThreshold = 0.6 // Word overlap of the descriptions
+	duplicateDescriptionMinWords  = 4   // Shorter descriptions are not compared
+)
+
+// FindDuplicates finds components that likely model the same thing in
+// different containers: components with the same or similar names, or with
+// similar descriptions.
+type FindDuplicates struct{}
+
+// DuplicateCandidate is a pair of components that are likely duplicates.
+type DuplicateCandidate struct {
+	A      string  // Qualified ID of the first component, sorted before B
+	B      string  // Qualified ID of the second component
+	Score  float64 // Similarity between 0 and 1
+	Reason string  // Why the pair was reported, e.g. "same name"
+}
+
+// NewFindDuplicates creates a FindDuplicates use case.
+func NewFindDuplicates() *FindDuplicates {
+	return &FindDuplicates{}
+}
+
+// Execute returns the likely duplicate components of systems, most similar
+// first. Components of the same container are never reported: their IDs are
+// already unique.
+func (uc *FindDuplicates) Execute(systems []*entities.System) []DuplicateCandidate {
+	type entry struct {
+		id        string
+		container string
+		name      string
+		words     map[string]bool
+	}
+	var entries []entry
+	for _, sys := range systems {
+		if sys == nil {
+			continue
+		}
+		for _, cont := range sys.Containers {
+			for _, comp := range cont.Components {
+				entries = append(entries, entry{
+					id:        sys.ID + "/" + cont.ID + "/" + comp.ID,
+					container: sys.ID + "/" + cont.ID,
+					name:      normalizeName(comp.Name),
+					words:     descriptionWords(comp.Description),
+				})
+			}
+		}
+	}
+	slices.SortFunc(entries, func(a, b entry) int { return strings.Compare(a.id, b.id) })
+
+	var candidates []DuplicateCandidate
+	for i, a := range entries {
+		for _, b := range entries[i+1:] {
+			if a.container == b.container {
+				continue
+			}
+			candidate := DuplicateCandidate{A: a.id, B: b.id}
+			if name := nameSimilarity(a.name, b.name); name == 1 {
+				candidate.Score, candidate.Reason = 1, "same name"
+			} else if name >= duplicateNameThreshold {
+				candidate.Score, candidate.Reason = name, fmt.Sprintf("similar names (%.0f%%)", name*100)
+			}
+			if description := wordSimilarity(a.words, b.words); description >= duplicateDescriptionThreshold && description > candidate.Score {
+				candidate.Score, candidate.Reason = description, fmt.Sprintf("similar descriptions (%.0f%%)", description*100)
+			}
+			if candidate.Reason != "" {
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b DuplicateCandidate) int { return cmp.Compare(b.Score, a.Score) })
+	return candidates
+}
+
+// normalizeName lowercases a name and drops everything but letters and
+// digits, so "Order Service" and "order-service" compare equal.
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// nameSimilarity returns 1 minus the edit distance of two normalized names
+// relative to the longer one.
+func nameSimilarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	ra, rb := []rune(a), []rune(b)
+	return 1 - float64(editDistance(ra, rb))/float64(max(len(ra), len(rb)))
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// descriptionWords returns the distinct lowercase words of a description
+// longer than two letters, or nil when there are too few to compare.
+func descriptionWords(description string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(word) > 2 {
+			words[word] = true
+		}
+	}
+	if len(words) < duplicateDescriptionMinWords {
+		return nil
+	}
+	return words
+}
+
+// wordSimilarity returns the Jaccard index of two word sets.
+func wordSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,59 @@
+package usecases
+
+import (
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// duplicatesModel returns a system whose containers hold the named
+// components, each described by description.
+func duplicatesModel(t *testing.T, containers map[string]map[string]string) *entities.System {
+	t.Helper()
+	system, _ := entities.NewSystem("Shop")
+	for containerName, components := range containers {
+		container, _ := entities.NewContainer(containerName)
+		for name, description := range components {
+			component, _ := entities.NewComponent(name)
+			component.Description = description
+			if err := container.AddComponent(component); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := system.AddContainer(container); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return system
+}
+
+func TestFindDuplicates(t *testing.T) {
+	system := duplicatesModel(t, map[string]map[string]string{
+		"API": {
+			"Order Store":  "",
+			"Payment":      "Charges customer cards through the payment provider",
+			"Notification": "Sends emails",
+		},
+		"Worker": {
+			"order-store":  "",
+			"Billing":      "Charges customer cards through the external payment provider",
+			"Notifier":     "Sends emails",
+			"Order Stores": "",
+		},
+	})
+
+	got := NewFindDuplicates().Execute([]*entities.System{system})
+	want := []struct{ a, b, reason string }{
+		{"shop/api/order-store", "shop/worker/order-store", "same name"},
+		{"shop/api/order-store", "shop/worker/order-stores", "similar names (91%)"},
+		{"shop/api/payment", "shop/worker/billing", "similar descriptions (88%)"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Execute() = %+v, want %d candidates", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].A != w.a || got[i].B != w.b || got[i].Reason != w.reason {
+			t.Errorf("candidate %d = %+v, want %s %s (%s)", i, got[i], w.a, w.b, w.reason)
+		}
+	}
+}
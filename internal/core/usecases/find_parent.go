@@ -0,0 +1,134 @@
+package usecases
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// maxSuggestions is how many close matches a not-found error suggests.
+const maxSuggestions = 3
+
+// FindSystem returns the system of systems whose ID or name is ref, or an
+// error suggesting the IDs of close matches.
+func FindSystem(systems []*entities.System, ref string) (*entities.System, error) {
+	ids := make([]string, 0, len(systems))
+	for _, system := range systems {
+		if matchesElement(ref, system.ID, system.Name) {
+			return system, nil
+		}
+		ids = append(ids, system.ID)
+	}
+	return nil, elementNotFoundError("system", ref, "", ids)
+}
+
+// FindContainer returns the container whose ID or name is ref, with its
+// system. With systemRef it is looked up in that system only; without it the
+// container must be unique across systems.
+func FindContainer(systems []*entities.System, systemRef, ref string) (*entities.System, *entities.Container, error) {
+	if systemRef != "" {
+		system, err := FindSystem(systems, systemRef)
+		if err != nil {
+			return nil, nil, err
+		}
+		systems = []*entities.System{system}
+	}
+
+	var (
+		matches    []*entities.Container
+		matchOwner []*entities.System
+		ids        []string
+	)
+	for _, system := range systems {
+		for _, container := range system.ListContainers() {
+			if matchesElement(ref, container.ID, container.Name) {
+				matches = append(matches, container)
+				matchOwner = append(matchOwner, system)
+			}
+			ids = append(ids, container.ID)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		in := ""
+		if systemRef != "" {
+			in = " in system " + systems[0].ID
+		}
+		return nil, nil, elementNotFoundError("container", ref, in, ids)
+	case 1:
+		return matchOwner[0], matches[0], nil
+	default:
+		owners := make([]string, len(matchOwner))
+		for i, system := range matchOwner {
+			owners[i] = system.ID
+		}
+		return nil, nil, fmt.Errorf("container %q is ambiguous — it exists in systems %s; name its system too", ref, strings.Join(owners, ", "))
+	}
+}
+
+// FindComponent returns the component of container whose ID or name is ref,
+// or an error suggesting the IDs of close matches.
+func FindComponent(container *entities.Container, ref string) (*entities.Component, error) {
+	ids := make([]string, 0, len(container.Components))
+	for _, component := range container.ListComponents() {
+		if matchesElement(ref, component.ID, component.Name) {
+			return component, nil
+		}
+		ids = append(ids, component.ID)
+	}
+	return nil, elementNotFoundError("component", ref, " in container "+container.ID, ids)
+}
+
+// matchesElement reports whether ref names the element with id and name,
+// either as typed or as the ID it normalizes to.
+func matchesElement(ref, id, name string) bool {
+	return ref == id || strings.EqualFold(ref, name) || entities.NormalizeName(ref) == id
+}
+
+// elementNotFoundError reports that no kind named ref exists in scope, suggesting
+// the close matches among ids, or listing them all when there are few.
+func elementNotFoundError(kind, ref, scope string, ids []string) error {
+	if suggestions := closestIDs(ref, ids); len(suggestions) > 0 {
+		return fmt.Errorf("%s %q not found%s — did you mean %s?", kind, ref, scope, strings.Join(suggestions, " or "))
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("%s %q not found%s — there are none yet", kind, ref, scope)
+	}
+	if len(ids) <= 2*maxSuggestions {
+		return fmt.Errorf("%s %q not found%s — available: %s", kind, ref, scope, strings.Join(ids, ", "))
+	}
+	return fmt.Errorf("%s %q not found%s", kind, ref, scope)
+}
+
+// closestIDs returns up to maxSuggestions of ids close to ref: containing it,
+// contained in it, or a few edits away, closest first.
+func closestIDs(ref string, ids []string) []string {
+	ref = entities.NormalizeName(ref)
+	if ref == "" {
+		return nil
+	}
+	type candidate struct {
+		id       string
+		distance int
+	}
+	var candidates []candidate
+	for _, id := range ids {
+		distance := editDistance([]rune(ref), []rune(id))
+		if distance <= max(1, len(ref)/3) || strings.Contains(id, ref) || strings.Contains(ref, id) {
+			candidates = append(candidates, candidate{id, distance})
+		}
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return cmp.Or(cmp.Compare(a.distance, b.distance), cmp.Compare(a.id, b.id))
+	})
+
+	var closest []string
+	for _, c := range candidates[:min(len(candidates), maxSuggestions)] {
+		closest = append(closest, c.id)
+	}
+	return closest
+}
@@ -0,0 +1,96 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func findParentFixture(t *testing.T) []*entities.System {
+	t.Helper()
+	var systems []*entities.System
+	for _, spec := range []struct{ system, container string }{
+		{"Payments", "API"},
+		{"Payments", "Worker"},
+		{"Orders", "API"},
+		{"Orders", "Order Store"},
+	} {
+		var system *entities.System
+		for _, s := range systems {
+			if s.Name == spec.system {
+				system = s
+			}
+		}
+		if system == nil {
+			system, _ = entities.NewSystem(spec.system)
+			systems = append(systems, system)
+		}
+		container, _ := entities.NewContainer(spec.container)
+		if err := system.AddContainer(container); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return systems
+}
+
+func TestFindSystem(t *testing.T) {
+	systems := findParentFixture(t)
+
+	for _, ref := range []string{"payments", "Payments", "PAYMENTS"} {
+		if system, err := FindSystem(systems, ref); err != nil || system.ID != "payments" {
+			t.Errorf("FindSystem(%q) = %v, %v", ref, system, err)
+		}
+	}
+
+	_, err := FindSystem(systems, "Paymnts")
+	if err == nil || !strings.Contains(err.Error(), `did you mean payments?`) {
+		t.Errorf("err = %v, want a suggestion of payments", err)
+	}
+	_, err = FindSystem(systems, "inventory")
+	if err == nil || !strings.Contains(err.Error(), "available: payments, orders") {
+		t.Errorf("err = %v, want the list of systems", err)
+	}
+	_, err = FindSystem(nil, "payments")
+	if err == nil || !strings.Contains(err.Error(), "there are none yet") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestFindContainer(t *testing.T) {
+	systems := findParentFixture(t)
+
+	system, container, err := FindContainer(systems, "", "Order Store")
+	if err != nil || system.ID != "orders" || container.ID != "order-store" {
+		t.Errorf("FindContainer = %v, %v, %v", system, container, err)
+	}
+
+	if _, _, err := FindContainer(systems, "", "api"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("err = %v, want ambiguous", err)
+	}
+	system, container, err = FindContainer(systems, "orders", "api")
+	if err != nil || system.ID != "orders" || container.ID != "api" {
+		t.Errorf("FindContainer in orders = %v, %v, %v", system, container, err)
+	}
+
+	_, _, err = FindContainer(systems, "payments", "wroker")
+	if err == nil || !strings.Contains(err.Error(), "in system payments — did you mean worker?") {
+		t.Errorf("err = %v, want a suggestion of worker", err)
+	}
+	if _, _, err := FindContainer(systems, "billing", "api"); err == nil || !strings.Contains(err.Error(), `system "billing" not found`) {
+		t.Errorf("err = %v, want the system not found", err)
+	}
+}
+
+func TestFindComponent(t *testing.T) {
+	container, _ := entities.NewContainer("API")
+	component, _ := entities.NewComponent("Auth Handler")
+	_ = container.AddComponent(component)
+
+	if found, err := FindComponent(container, "auth handler"); err != nil || found != component {
+		t.Errorf("FindComponent = %v, %v", found, err)
+	}
+	if _, err := FindComponent(container, "auth"); err == nil || !strings.Contains(err.Error(), "did you mean auth-handler?") {
+		t.Errorf("err = %v, want a suggestion of auth-handler", err)
+	}
+}
@@ -2,6 +2,8 @@ package usecases
 
 import (
 	"context"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
@@ -60,13 +62,13 @@ func (uc *FindRelationships) Execute(ctx context.Context, req entities.FindRelat
 	}
 
 	// Iterate through all edges in the graph
-	for sourceID, edges := range graph.Edges {
+	for _, sourceID := range slices.Sorted(maps.Keys(graph.Edges)) {
 		// Check source pattern
 		if sourceMatcher != nil && !sourceMatcher.Match(sourceID) {
 			continue
 		}
 
-		for _, edge := range edges {
+		for _, edge := range graph.Edges[sourceID] {
 			// Check target pattern
 			if targetMatcher != nil && !targetMatcher.Match(edge.Target) {
 				continue
@@ -0,0 +1,164 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// Codes of the problems FixArchitecture remediates.
+const (
+	FixMissingDiagram     = "missing_diagram"
+	FixDanglingReference  = "dangling_reference"
+	FixMissingDescription = "missing_description"
+)
+
+// FixArchitecture finds common problems that have an automated remediation:
+// elements without a diagram, relationships to components that do not exist
+// and elements without a description.
+type FixArchitecture struct {
+	editor    FrontmatterEditor
+	generator DiagramGenerator // Optional: missing diagrams are not fixed without it
+}
+
+// ArchitectureFix is the remediation of one problem.
+type ArchitectureFix struct {
+	Code        string // FixMissingDiagram, FixDanglingReference or FixMissingDescription
+	Element     string // Slash-separated ID of the element fixed
+	Description string // What applying the fix does
+	Path        string // File the fix writes
+
+	value string // Diagram source, relationship target or description
+}
+
+// NewFixArchitecture creates a FixArchitecture use case.
+func NewFixArchitecture(editor FrontmatterEditor, generator DiagramGenerator) *FixArchitecture {
+	return &FixArchitecture{editor: editor, generator: generator}
+}
+
+// Plan returns the fixes for the problems found in systems, element by
+// element in hierarchy order. graph resolves relationship targets; a target
+// is dangling when it is neither a qualified nor a short ID in graph. Without
+// a graph no target is dangling.
+func (uc *FixArchitecture) Plan(graph *entities.ArchitectureGraph, systems []*entities.System) ([]ArchitectureFix, error) {
+	var fixes []ArchitectureFix
+	sorted := slices.DeleteFunc(slices.Clone(systems), func(s *entities.System) bool { return s == nil })
+	slices.SortFunc(sorted, func(a, b *entities.System) int { return strings.Compare(a.ID, b.ID) })
+
+	for _, system := range sorted {
+		if system.Path == "" {
+			continue
+		}
+		if system.Diagram == nil && uc.generator != nil {
+			source, err := uc.generator.GenerateSystemContextDiagram(system)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate system context diagram: %w", err)
+			}
+			fixes = append(fixes, diagramFix(system.ID, filepath.Join(system.Path, "system.d2"), source))
+		}
+		if system.Description == "" {
+			fixes = append(fixes, descriptionFix(system.ID, system.Name, filepath.Join(system.Path, "system.md")))
+		}
+
+		for _, container := range system.ListContainers() {
+			if container.Path == "" {
+				continue
+			}
+			containerID := system.ID + "/" + container.ID
+			if container.Diagram == nil && uc.generator != nil {
+				source, err := uc.generator.GenerateContainerDiagram(system)
+				if err != nil {
+					return nil, fmt.Errorf("failed to generate container diagram: %w", err)
+				}
+				path := filepath.Join(container.Path, filepath.Base(container.Path)+".d2")
+				fixes = append(fixes, diagramFix(containerID, path, source))
+			}
+			if container.Description == "" {
+				fixes = append(fixes, descriptionFix(containerID, container.Name, filepath.Join(container.Path, "container.md")))
+			}
+
+			for _, component := range container.ListComponents() {
+				if component.Path == "" {
+					continue
+				}
+				componentID := containerID + "/" + component.ID
+				markdown := filepath.Join(component.Path, "component.md")
+				if component.Diagram == nil && uc.generator != nil {
+					source, err := uc.generator.GenerateComponentDiagram(container)
+					if err != nil {
+						return nil, fmt.Errorf("failed to generate component diagram: %w", err)
+					}
+					fixes = append(fixes, diagramFix(componentID, filepath.Join(component.Path, filepath.Base(component.Path)+".d2"), source))
+				}
+				if component.Description == "" {
+					fixes = append(fixes, descriptionFix(componentID, component.Name, markdown))
+				}
+				for _, target := range slices.Sorted(maps.Keys(component.Relationships)) {
+					if graph == nil || resolvesInGraph(graph, target) {
+						continue
+					}
+					fixes = append(fixes, ArchitectureFix{
+						Code:        FixDanglingReference,
+						Element:     componentID,
+						Description: fmt.Sprintf("Remove relationship to unknown component %q", target),
+						Path:        markdown,
+						value:       target,
+					})
+				}
+			}
+		}
+	}
+	return fixes, nil
+}
+
+// Apply applies a fix returned by Plan. Diagrams are never overwritten.
+func (uc *FixArchitecture) Apply(ctx context.Context, fix ArchitectureFix) error {
+	switch fix.Code {
+	case FixMissingDiagram:
+		if _, err := os.Stat(fix.Path + EncryptedExt); err == nil {
+			return fmt.Errorf("%s: %w", filepath.Base(fix.Path), entities.ErrEncrypted)
+		}
+		file, err := os.OpenFile(fix.Path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Base(fix.Path), err)
+		}
+		if _, err := file.WriteString(fix.value); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to write %s: %w", filepath.Base(fix.Path), err)
+		}
+		return file.Close()
+	case FixDanglingReference:
+		return uc.editor.RemoveRelationship(ctx, fix.Path, fix.value)
+	case FixMissingDescription:
+		return uc.editor.SetFrontmatterField(ctx, fix.Path, "description", fix.value)
+	}
+	return fmt.Errorf("unknown fix %q", fix.Code)
+}
+
+// diagramFix creates a diagram from generated source.
+func diagramFix(element, path, source string) ArchitectureFix {
+	return ArchitectureFix{
+		Code:        FixMissingDiagram,
+		Element:     element,
+		Description: fmt.Sprintf("Create %s from the default template", filepath.Base(path)),
+		Path:        path,
+		value:       source,
+	}
+}
+
+// descriptionFix adds a placeholder description flagged as TODO.
+func descriptionFix(element, name, path string) ArchitectureFix {
+	return ArchitectureFix{
+		Code:        FixMissingDescription,
+		Element:     element,
+		Description: "Add a placeholder description marked TODO",
+		Path:        path,
+		value:       fmt.Sprintf("TODO: describe %s", name),
+	}
+}
@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// recordingEditor is a FrontmatterEditor recording its edits.
+type recordingEditor struct {
+	edits []string
+}
+
+// editedFile returns the parent directory and name of an edited file.
+func editedFile(path string) string {
+	return filepath.Base(filepath.Dir(path)) + "/" + filepath.Base(path)
+}
+
+func (e *recordingEditor) SetFrontmatterField(ctx context.Context, path, key, value string) error {
+	e.edits = append(e.edits, editedFile(path)+" set "+key+"="+value)
+	return nil
+}
+
+func (e *recordingEditor) SetFrontmatterList(ctx context.Context, path, key string, items []string) error {
+	e.edits = append(e.edits, editedFile(path)+" set "+key+"=["+strings.Join(items, ",")+"]")
+	return nil
+}
+
+func (e *recordingEditor) SetFrontmatterMap(ctx context.Context, path, key string, entries map[string]string) error {
+	var pairs []string
+	for _, entry := range slices.Sorted(maps.Keys(entries)) {
+		pairs = append(pairs, entry+":"+entries[entry])
+	}
+	e.edits = append(e.edits, editedFile(path)+" set "+key+"={"+strings.Join(pairs, ",")+"}")
+	return nil
+}
+
+func (e *recordingEditor) RemoveRelationship(ctx context.Context, path, target string) error {
+	e.edits = append(e.edits, editedFile(path)+" remove "+target)
+	return nil
+}
+
+func TestFixArchitecture(t *testing.T) {
+	root := t.TempDir()
+	system, _ := entities.NewSystem("Payments")
+	system.Description = "Takes payments"
+	system.Path = filepath.Join(root, "payments")
+	system.Diagram = &entities.Diagram{}
+	container, _ := entities.NewContainer("API")
+	container.Description = "Public API"
+	container.Path = filepath.Join(system.Path, "api")
+	container.Diagram = &entities.Diagram{}
+	handler, _ := entities.NewComponent("Handler")
+	handler.Path = filepath.Join(container.Path, "handler")
+	handler.Diagram = &entities.Diagram{}
+	handler.Relationships = map[string]string{"store": "Reads", "ledger": "Posts"}
+	store, _ := entities.NewComponent("Store")
+	store.Description = "Persists payments"
+	store.Path = filepath.Join(container.Path, "store")
+	for _, c := range []*entities.Component{handler, store} {
+		if err := container.AddComponent(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := system.AddContainer(container); err != nil {
+		t.Fatal(err)
+	}
+	systems := []*entities.System{system}
+
+	project, _ := entities.NewProject("shop")
+	graph, err := NewBuildArchitectureGraph().Execute(context.Background(), project, systems)
+	if err != nil {
+		t.Fatalf("build graph: %v", err)
+	}
+
+	editor := &recordingEditor{}
+	uc := NewFixArchitecture(editor, &mockDiagramGenerator{})
+	fixes, err := uc.Plan(graph, systems)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	want := []struct{ code, element string }{
+		{FixMissingDescription, "payments/api/handler"},
+		{FixDanglingReference, "payments/api/handler"},
+		{FixMissingDiagram, "payments/api/store"},
+	}
+	if len(fixes) != len(want) {
+		t.Fatalf("Plan() = %+v, want %d fixes", fixes, len(want))
+	}
+	for i, w := range want {
+		if fixes[i].Code != w.code || fixes[i].Element != w.element {
+			t.Errorf("fix %d = %s %s, want %s %s", i, fixes[i].Code, fixes[i].Element, w.code, w.element)
+		}
+	}
+
+	ctx := context.Background()
+	if err := os.MkdirAll(store.Path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, fix := range fixes {
+		if err := uc.Apply(ctx, fix); err != nil {
+			t.Fatalf("Apply(%s) error = %v", fix.Code, err)
+		}
+	}
+	wantEdits := []string{"handler/component.md set description=TODO: describe Handler", "handler/component.md remove ledger"}
+	if len(editor.edits) != 2 || editor.edits[0] != wantEdits[0] || editor.edits[1] != wantEdits[1] {
+		t.Errorf("edits = %q, want %q", editor.edits, wantEdits)
+	}
+	if source, err := os.ReadFile(filepath.Join(store.Path, "store.d2")); err != nil || string(source) != "component diagram" {
+		t.Errorf("store.d2 = %q, %v", source, err)
+	}
+
+	// Existing diagrams are never overwritten
+	if err := uc.Apply(ctx, fixes[2]); err == nil {
+		t.Error("Apply() of an existing diagram should fail")
+	}
+}
@@ -0,0 +1,288 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// ProvenanceFile is the name of the provenance statement written to the root
+// of the output directory. It is not listed among its own subjects.
+const ProvenanceFile = "provenance.intoto.json"
+
+// ProvenanceRequest describes a finished build for GenerateProvenance.
+type ProvenanceRequest struct {
+	ProjectRoot string
+	SourceDir   string // Relative to ProjectRoot
+	OutputDir   string
+
+	// Revision is the source commit the build ran from, if known.
+	Revision *entities.ResourceDescriptor
+
+	// Versions of the tools that produced the output, e.g. "loko" and "d2".
+	Versions map[string]string
+
+	// Parameters are the build options that shaped the output, e.g. formats.
+	Parameters map[string]any
+
+	StartedOn time.Time
+}
+
+// GenerateProvenance writes an in-toto provenance statement for built
+// documentation: the digest of every output file, the source commit and the
+// digests of loko.toml and every source file, and the loko and d2 versions.
+// With a signer, the statement is wrapped in a signed DSSE envelope.
+type GenerateProvenance struct {
+	signer ProvenanceSigner
+	now    func() time.Time
+}
+
+// NewGenerateProvenance creates a new GenerateProvenance use case.
+func NewGenerateProvenance() *GenerateProvenance {
+	return &GenerateProvenance{now: time.Now}
+}
+
+// WithSigner signs the statement with signer.
+func (uc *GenerateProvenance) WithSigner(signer ProvenanceSigner) *GenerateProvenance {
+	uc.signer = signer
+	return uc
+}
+
+// Execute writes ProvenanceFile into req.OutputDir and returns its path.
+func (uc *GenerateProvenance) Execute(ctx context.Context, req ProvenanceRequest) (string, error) {
+	subjects, err := hashTree(req.OutputDir, "", func(rel string) bool { return rel == ProvenanceFile })
+	if err != nil {
+		return "", fmt.Errorf("failed to hash output: %w", err)
+	}
+
+	var dependencies []entities.ResourceDescriptor
+	if req.Revision != nil {
+		dependencies = append(dependencies, *req.Revision)
+	}
+	if config, err := hashFile(filepath.Join(req.ProjectRoot, "loko.toml")); err == nil {
+		dependencies = append(dependencies, entities.ResourceDescriptor{Name: "loko.toml", Digest: config})
+	}
+	sources, err := hashTree(filepath.Join(req.ProjectRoot, req.SourceDir), filepath.ToSlash(req.SourceDir), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash sources: %w", err)
+	}
+	dependencies = append(dependencies, sources...)
+
+	statement := entities.ProvenanceStatement{
+		Type:          entities.InTotoStatementType,
+		Subject:       subjects,
+		PredicateType: entities.SLSAProvenancePredicate,
+		Predicate: entities.SLSAProvenance{
+			BuildDefinition: entities.BuildDefinition{
+				BuildType:            entities.LokoBuildType,
+				ExternalParameters:   req.Parameters,
+				ResolvedDependencies: dependencies,
+			},
+			RunDetails: entities.RunDetails{
+				Builder: entities.ProvenanceBuilder{ID: entities.LokoBuilderID, Version: req.Versions},
+				Metadata: entities.BuildMetadata{
+					StartedOn:  req.StartedOn.UTC(),
+					FinishedOn: uc.now().UTC(),
+				},
+			},
+		},
+	}
+
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode provenance: %w", err)
+	}
+	content := payload
+	if uc.signer != nil {
+		keyID, sig, err := uc.signer.Sign(ctx, entities.DSSEPreAuthEncoding(entities.InTotoPayloadType, payload))
+		if err != nil {
+			return "", fmt.Errorf("failed to sign provenance: %w", err)
+		}
+		content, err = json.MarshalIndent(entities.DSSEEnvelope{
+			PayloadType: entities.InTotoPayloadType,
+			Payload:     payload,
+			Signatures:  []entities.DSSESignature{{KeyID: keyID, Sig: sig}},
+		}, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode provenance: %w", err)
+		}
+	} else {
+		content, _ = json.MarshalIndent(statement, "", "  ")
+	}
+
+	path := filepath.Join(req.OutputDir, ProvenanceFile)
+	if err := os.WriteFile(path, append(content, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write provenance: %w", err)
+	}
+	return path, nil
+}
+
+// ProvenanceReport is the result of checking built documentation against
+// its provenance statement.
+type ProvenanceReport struct {
+	Statement *entities.ProvenanceStatement
+	Signed    bool // The statement is wrapped in a DSSE envelope
+	Verified  bool // A signature was checked against the verifier's key
+
+	Modified []string // Subjects whose content no longer matches
+	Missing  []string // Subjects no longer present
+	Unlisted []string // Files present but not covered by the statement
+}
+
+// OK reports whether every file matches the statement.
+func (r *ProvenanceReport) OK() bool {
+	return len(r.Modified) == 0 && len(r.Missing) == 0 && len(r.Unlisted) == 0
+}
+
+// VerifyProvenance checks that a documentation directory matches the
+// provenance statement written by GenerateProvenance, and, with a verifier,
+// that the statement was signed by the expected key.
+type VerifyProvenance struct {
+	verifier ProvenanceVerifier
+}
+
+// NewVerifyProvenance creates a new VerifyProvenance use case.
+func NewVerifyProvenance() *VerifyProvenance {
+	return &VerifyProvenance{}
+}
+
+// WithVerifier requires a valid signature from verifier's key.
+func (uc *VerifyProvenance) WithVerifier(verifier ProvenanceVerifier) *VerifyProvenance {
+	uc.verifier = verifier
+	return uc
+}
+
+// Execute reads ProvenanceFile from dir and compares the files in dir with
+// its subjects. It returns an error when the statement is unreadable or,
+// with a verifier, unsigned or not signed by the expected key.
+func (uc *VerifyProvenance) Execute(ctx context.Context, dir string) (*ProvenanceReport, error) {
+	content, err := os.ReadFile(filepath.Join(dir, ProvenanceFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read provenance: %w", err)
+	}
+
+	report := &ProvenanceReport{}
+	payload := content
+	var envelope entities.DSSEEnvelope
+	if err := json.Unmarshal(content, &envelope); err == nil && envelope.PayloadType != "" {
+		if envelope.PayloadType != entities.InTotoPayloadType {
+			return nil, fmt.Errorf("unsupported provenance payload type %q", envelope.PayloadType)
+		}
+		report.Signed = true
+		payload = envelope.Payload
+	}
+
+	if uc.verifier != nil {
+		if !report.Signed {
+			return nil, fmt.Errorf("provenance is not signed")
+		}
+		pae := entities.DSSEPreAuthEncoding(envelope.PayloadType, envelope.Payload)
+		for _, sig := range envelope.Signatures {
+			if uc.verifier.Verify(ctx, pae, sig.Sig) == nil {
+				report.Verified = true
+				break
+			}
+		}
+		if !report.Verified {
+			return nil, fmt.Errorf("provenance signature does not match the key")
+		}
+	}
+
+	var statement entities.ProvenanceStatement
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return nil, fmt.Errorf("failed to parse provenance: %w", err)
+	}
+	if statement.Type != entities.InTotoStatementType {
+		return nil, fmt.Errorf("unsupported statement type %q", statement.Type)
+	}
+	report.Statement = &statement
+
+	actual, err := hashTree(dir, "", func(rel string) bool { return rel == ProvenanceFile })
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash %s: %w", dir, err)
+	}
+	digests := make(map[string]string, len(actual))
+	for _, file := range actual {
+		digests[file.Name] = file.Digest["sha256"]
+	}
+	for _, subject := range statement.Subject {
+		digest, ok := digests[subject.Name]
+		switch {
+		case !ok:
+			report.Missing = append(report.Missing, subject.Name)
+		case digest != subject.Digest["sha256"]:
+			report.Modified = append(report.Modified, subject.Name)
+		}
+		delete(digests, subject.Name)
+	}
+	for name := range digests {
+		report.Unlisted = append(report.Unlisted, name)
+	}
+	slices.Sort(report.Unlisted)
+	return report, nil
+}
+
+// hashTree returns the SHA-256 digest of every regular file under root,
+// named by its slash-separated path relative to root and joined to prefix,
+// sorted by name. Files for which skip returns true are left out. A missing
+// root has no files.
+func hashTree(root, prefix string, skip func(rel string) bool) ([]entities.ResourceDescriptor, error) {
+	var files []entities.ResourceDescriptor
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && path == root {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if skip != nil && skip(rel) {
+			return nil
+		}
+		digest, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+		name := rel
+		if prefix != "" && prefix != "." {
+			name = strings.TrimSuffix(prefix, "/") + "/" + rel
+		}
+		files = append(files, entities.ResourceDescriptor{Name: name, Digest: digest})
+		return nil
+	})
+	slices.SortFunc(files, func(a, b entities.ResourceDescriptor) int { return strings.Compare(a.Name, b.Name) })
+	return files, err
+}
+
+// hashFile returns the SHA-256 digest of the file at path.
+func hashFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return map[string]string{"sha256": hex.EncodeToString(h.Sum(nil))}, nil
+}
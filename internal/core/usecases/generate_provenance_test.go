@@ -0,0 +1,136 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// prefixSigner signs by prefixing data with its key, which is enough to tell
+// matching, mismatching and tampered signatures apart.
+type prefixSigner struct{ key string }
+
+func (s prefixSigner) Sign(_ context.Context, data []byte) (string, []byte, error) {
+	return s.key, append([]byte(s.key), data...), nil
+}
+
+func (s prefixSigner) Verify(_ context.Context, data, sig []byte) error {
+	if !bytes.Equal(sig, append([]byte(s.key), data...)) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGenerateAndVerifyProvenance(t *testing.T) {
+	projectRoot := t.TempDir()
+	outputDir := filepath.Join(projectRoot, "dist")
+	writeTree(t, projectRoot, map[string]string{
+		"loko.toml":                 "[project]\nname = \"demo\"\n",
+		"src/payments/system.md":    "---\nname: Payments\n---\n",
+		"dist/index.html":           "<h1>Demo</h1>",
+		"dist/systems/payments.svg": "<svg/>",
+	})
+
+	ctx := context.Background()
+	uc := NewGenerateProvenance().WithSigner(prefixSigner{key: "k1"})
+	uc.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	path, err := uc.Execute(ctx, ProvenanceRequest{
+		ProjectRoot: projectRoot,
+		SourceDir:   "src",
+		OutputDir:   outputDir,
+		Revision:    &entities.ResourceDescriptor{URI: "git+https://example.com/arch.git", Digest: map[string]string{"gitCommit": "abc123"}},
+		Versions:    map[string]string{"loko": "1.0.0", "d2": "v0.7.0"},
+		Parameters:  map[string]any{"formats": []string{"html"}},
+		StartedOn:   time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if path != filepath.Join(outputDir, ProvenanceFile) {
+		t.Errorf("path = %q", path)
+	}
+
+	report, err := NewVerifyProvenance().WithVerifier(prefixSigner{key: "k1"}).Execute(ctx, outputDir)
+	if err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+	if !report.Signed || !report.Verified || !report.OK() {
+		t.Errorf("report = %+v", report)
+	}
+
+	statement := report.Statement
+	var subjects []string
+	for _, s := range statement.Subject {
+		subjects = append(subjects, s.Name)
+	}
+	if !slices.Equal(subjects, []string{"index.html", "systems/payments.svg"}) {
+		t.Errorf("subjects = %v", subjects)
+	}
+	var deps []string
+	for _, d := range statement.Predicate.BuildDefinition.ResolvedDependencies {
+		deps = append(deps, d.Name+d.URI)
+	}
+	if !slices.Equal(deps, []string{"git+https://example.com/arch.git", "loko.toml", "src/payments/system.md"}) {
+		t.Errorf("dependencies = %v", deps)
+	}
+	if statement.Predicate.RunDetails.Builder.Version["d2"] != "v0.7.0" {
+		t.Errorf("builder = %+v", statement.Predicate.RunDetails.Builder)
+	}
+
+	if _, err := NewVerifyProvenance().WithVerifier(prefixSigner{key: "k2"}).Execute(ctx, outputDir); err == nil {
+		t.Error("expected verification with another key to fail")
+	}
+
+	// Tamper with the output.
+	writeTree(t, outputDir, map[string]string{"index.html": "<h1>Changed</h1>", "extra.html": "x"})
+	if err := os.Remove(filepath.Join(outputDir, "systems", "payments.svg")); err != nil {
+		t.Fatal(err)
+	}
+	report, err = NewVerifyProvenance().Execute(ctx, outputDir)
+	if err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+	if report.OK() || report.Verified {
+		t.Error("expected tampered output to be reported")
+	}
+	if !slices.Equal(report.Modified, []string{"index.html"}) || !slices.Equal(report.Missing, []string{"systems/payments.svg"}) || !slices.Equal(report.Unlisted, []string{"extra.html"}) {
+		t.Errorf("report = modified %v, missing %v, unlisted %v", report.Modified, report.Missing, report.Unlisted)
+	}
+}
+
+func TestVerifyProvenanceRequiresSignature(t *testing.T) {
+	outputDir := t.TempDir()
+	writeTree(t, outputDir, map[string]string{"index.html": "x"})
+	ctx := context.Background()
+	if _, err := NewGenerateProvenance().Execute(ctx, ProvenanceRequest{ProjectRoot: t.TempDir(), SourceDir: "src", OutputDir: outputDir}); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	report, err := NewVerifyProvenance().Execute(ctx, outputDir)
+	if err != nil || report.Signed || !report.OK() {
+		t.Errorf("unsigned report = %+v, %v", report, err)
+	}
+	if _, err := NewVerifyProvenance().WithVerifier(prefixSigner{key: "k1"}).Execute(ctx, outputDir); err == nil {
+		t.Error("expected unsigned provenance to fail verification with a key")
+	}
+}
@@ -0,0 +1,173 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// SyntheticProjectSize describes the shape of a generated project: Systems
+// systems, each with Containers containers of Components components.
+type SyntheticProjectSize struct {
+	Systems    int
+	Containers int
+	Components int
+}
+
+// Validate checks that every dimension is usable.
+func (s SyntheticProjectSize) Validate() error {
+	if s.Systems < 1 {
+		return fmt.Errorf("systems must be at least 1, got %d", s.Systems)
+	}
+	if s.Containers < 0 || s.Components < 0 {
+		return fmt.Errorf("containers and components cannot be negative")
+	}
+	return nil
+}
+
+// Entities returns the total number of systems, containers and components.
+func (s SyntheticProjectSize) Entities() int {
+	containers := s.Systems * s.Containers
+	return s.Systems + containers + containers*s.Components
+}
+
+// GenerateSyntheticProject writes a project of a given size to disk, used to
+// measure build performance. Entities are saved directly through the
+// ProjectRepository rather than ScaffoldEntity, which reloads the whole
+// project for every entity and would dominate generation time.
+type GenerateSyntheticProject struct {
+	projectRepo      ProjectRepository
+	diagramGenerator DiagramGenerator
+}
+
+// NewGenerateSyntheticProject creates a new GenerateSyntheticProject use case.
+func NewGenerateSyntheticProject(projectRepo ProjectRepository) *GenerateSyntheticProject {
+	return &GenerateSyntheticProject{projectRepo: projectRepo}
+}
+
+// WithDiagramGenerator sets the optional generator used to write a D2 diagram
+// for every entity. Without it the project has no diagrams.
+func (uc *GenerateSyntheticProject) WithDiagramGenerator(dg DiagramGenerator) *GenerateSyntheticProject {
+	uc.diagramGenerator = dg
+	return uc
+}
+
+// Execute generates the project at projectRoot.
+func (uc *GenerateSyntheticProject) Execute(
+	ctx context.Context,
+	projectRoot string,
+	size SyntheticProjectSize,
+) (*entities.Project, error) {
+	if err := size.Validate(); err != nil {
+		return nil, err
+	}
+
+	project, err := entities.NewProject("bench")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create project: %w", err)
+	}
+	project.Path = projectRoot
+	project.Description = fmt.Sprintf("Synthetic project with %d entities", size.Entities())
+
+	if err := uc.projectRepo.SaveProject(ctx, project); err != nil {
+		return nil, fmt.Errorf("failed to save project: %w", err)
+	}
+
+	for i := range size.Systems {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := uc.generateSystem(ctx, projectRoot, i, size); err != nil {
+			return nil, err
+		}
+	}
+
+	return project, nil
+}
+
+// generateSystem saves the i-th system with its containers and components.
+func (uc *GenerateSyntheticProject) generateSystem(ctx context.Context, projectRoot string, i int, size SyntheticProjectSize) error {
+	system, err := entities.NewSystem(fmt.Sprintf("System %03d", i+1))
+	if err != nil {
+		return fmt.Errorf("failed to create system: %w", err)
+	}
+	system.Description = fmt.Sprintf("Synthetic system %d", i+1)
+
+	if err := uc.projectRepo.SaveSystem(ctx, projectRoot, system); err != nil {
+		return fmt.Errorf("failed to save system: %w", err)
+	}
+
+	for j := range size.Containers {
+		container, err := entities.NewContainer(fmt.Sprintf("Container %03d", j+1))
+		if err != nil {
+			return fmt.Errorf("failed to create container: %w", err)
+		}
+		container.Description = fmt.Sprintf("Synthetic container %d of %s", j+1, system.Name)
+		container.Technology = "Go"
+
+		if err := uc.projectRepo.SaveContainer(ctx, projectRoot, system.ID, container); err != nil {
+			return fmt.Errorf("failed to save container: %w", err)
+		}
+
+		for k := range size.Components {
+			component, err := entities.NewComponent(fmt.Sprintf("Component %03d", k+1))
+			if err != nil {
+				return fmt.Errorf("failed to create component: %w", err)
+			}
+			component.Description = fmt.Sprintf("Synthetic component %d of %s", k+1, container.Name)
+			component.Technology = "Go"
+
+			if err := uc.projectRepo.SaveComponent(ctx, projectRoot, system.ID, container.ID, component); err != nil {
+				return fmt.Errorf("failed to save component: %w", err)
+			}
+			if err := container.AddComponent(component); err != nil {
+				return fmt.Errorf("failed to add component: %w", err)
+			}
+		}
+
+		if err := system.AddContainer(container); err != nil {
+			return fmt.Errorf("failed to add container: %w", err)
+		}
+	}
+
+	if uc.diagramGenerator == nil {
+		return nil
+	}
+	return uc.writeDiagrams(system)
+}
+
+// writeDiagrams writes the system, container and component D2 files.
+func (uc *GenerateSyntheticProject) writeDiagrams(system *entities.System) error {
+	source, err := uc.diagramGenerator.GenerateSystemContextDiagram(system)
+	if err != nil {
+		return fmt.Errorf("failed to generate system diagram: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(system.Path, "system.d2"), []byte(source), 0644); err != nil {
+		return fmt.Errorf("failed to write D2 diagram: %w", err)
+	}
+
+	for _, container := range system.Containers {
+		source, err := uc.diagramGenerator.GenerateContainerDiagram(system)
+		if err != nil {
+			return fmt.Errorf("failed to generate container diagram: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(container.Path, "container.d2"), []byte(source), 0644); err != nil {
+			return fmt.Errorf("failed to write D2 diagram: %w", err)
+		}
+
+		for _, component := range container.Components {
+			source, err := uc.diagramGenerator.GenerateComponentDiagram(container)
+			if err != nil {
+				return fmt.Errorf("failed to generate component diagram: %w", err)
+			}
+			if err := os.WriteFile(filepath.Join(component.Path, "component.d2"), []byte(source), 0644); err != nil {
+				return fmt.Errorf("failed to write D2 diagram: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestSyntheticProjectSize(t *testing.T) {
+	size := SyntheticProjectSize{Systems: 3, Containers: 4, Components: 5}
+	if got := size.Entities(); got != 3+12+60 {
+		t.Errorf("Entities() = %d, want 75", got)
+	}
+
+	if err := (SyntheticProjectSize{Systems: 0}).Validate(); err == nil {
+		t.Error("expected error for zero systems")
+	}
+	if err := (SyntheticProjectSize{Systems: 1, Components: -1}).Validate(); err == nil {
+		t.Error("expected error for negative components")
+	}
+}
+
+func TestGenerateSyntheticProject(t *testing.T) {
+	var systems []*entities.System
+	repo := &MockProjectRepository{
+		SaveSystemFunc: func(ctx context.Context, projectRoot string, system *entities.System) error {
+			systems = append(systems, system)
+			return nil
+		},
+	}
+
+	size := SyntheticProjectSize{Systems: 2, Containers: 3, Components: 4}
+	project, err := NewGenerateSyntheticProject(repo).Execute(context.Background(), t.TempDir(), size)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if project.Name != "bench" {
+		t.Errorf("project name = %q, want bench", project.Name)
+	}
+
+	if len(systems) != 2 {
+		t.Fatalf("saved %d systems, want 2", len(systems))
+	}
+	for _, sys := range systems {
+		if len(sys.Containers) != 3 {
+			t.Errorf("%s has %d containers, want 3", sys.ID, len(sys.Containers))
+		}
+		for _, cont := range sys.Containers {
+			if len(cont.Components) != 4 {
+				t.Errorf("%s/%s has %d components, want 4", sys.ID, cont.ID, len(cont.Components))
+			}
+		}
+	}
+}
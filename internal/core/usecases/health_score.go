@@ -0,0 +1,223 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// Files written by WriteHealthBadge to the root of the output directory.
+const (
+	HealthBadgeFile = "badge.svg"   // Badge showing the health score
+	HealthFile      = "health.json" // Score and its parts, readable by the shields.io endpoint badge
+)
+
+// DefaultStaleAfter is how long a system's sources may go unchanged before
+// the system counts as stale in the health score.
+const DefaultStaleAfter = 180 * 24 * time.Hour
+
+// Weights of the parts of the health score, out of 100.
+const (
+	healthWeightCoverage   = 40
+	healthWeightValidation = 30
+	healthWeightFreshness  = 15
+	healthWeightDiagrams   = 15
+)
+
+// HealthScore is the documentation health of a project: a composite score
+// from 0 to 100 and the parts it is computed from, each also from 0 to 100.
+type HealthScore struct {
+	Score int    `json:"score"`
+	Grade string `json:"grade"` // "A", "B", "C", "D" or "F"
+
+	Coverage   int `json:"coverage"`   // Elements with a description
+	Validation int `json:"validation"` // Decreases with validation errors and warnings per element
+	Freshness  int `json:"freshness"`  // Systems whose sources changed within the stale period
+	Diagrams   int `json:"diagrams"`   // Systems and containers with a diagram
+
+	Elements     int `json:"elements"`
+	Errors       int `json:"errors"`
+	Warnings     int `json:"warnings"`
+	StaleSystems int `json:"stale_systems"`
+
+	// Shields.io endpoint badge fields, so health.json can be served as is:
+	// https://img.shields.io/endpoint?url=<site>/health.json
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// ComputeHealthScore computes the documentation health score of a project
+// from its documentation coverage, validation errors and warnings, how
+// recently its systems changed and which of its systems and containers have
+// diagrams, so repositories can show the health of their architecture
+// documentation in a badge.
+type ComputeHealthScore struct {
+	staleAfter time.Duration
+	now        func() time.Time
+}
+
+// NewComputeHealthScore creates a new ComputeHealthScore use case counting
+// systems unchanged for DefaultStaleAfter as stale.
+func NewComputeHealthScore() *ComputeHealthScore {
+	return &ComputeHealthScore{staleAfter: DefaultStaleAfter, now: time.Now}
+}
+
+// WithStaleAfter counts systems unchanged for longer than staleAfter as
+// stale; a non-positive duration keeps the default.
+func (uc *ComputeHealthScore) WithStaleAfter(staleAfter time.Duration) *ComputeHealthScore {
+	if staleAfter > 0 {
+		uc.staleAfter = staleAfter
+	}
+	return uc
+}
+
+// Execute computes the health score of the systems of project.
+func (uc *ComputeHealthScore) Execute(ctx context.Context, project *entities.Project, systems []*entities.System) (*HealthScore, error) {
+	if project == nil {
+		return nil, fmt.Errorf("project cannot be nil")
+	}
+	systems = slices.DeleteFunc(slices.Clone(systems), func(s *entities.System) bool { return s == nil })
+
+	graph, err := NewBuildArchitectureGraph().Execute(ctx, project, systems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build architecture graph: %w", err)
+	}
+	report := NewValidateArchitecture().Execute(graph, systems)
+
+	health := &HealthScore{Errors: report.Errors, Warnings: report.Warnings}
+	var documented, diagrammed, diagrammable, dated, fresh int
+	cutoff := uc.now().Add(-uc.staleAfter)
+	for _, system := range systems {
+		kpis := SystemKPIsFor(system)
+		health.Elements += kpis.Elements
+		documented += kpis.Documented
+
+		diagrammable++
+		if system.Diagram != nil {
+			diagrammed++
+		}
+		for _, container := range system.ListContainers() {
+			diagrammable++
+			if container.Diagram != nil {
+				diagrammed++
+			}
+		}
+
+		// Systems without sources on disk have no age.
+		if kpis.LastModified.IsZero() {
+			continue
+		}
+		dated++
+		if kpis.LastModified.After(cutoff) {
+			fresh++
+		} else {
+			health.StaleSystems++
+		}
+	}
+
+	health.Coverage = percent(documented, health.Elements)
+	health.Diagrams = percent(diagrammed, diagrammable)
+	health.Freshness = percent(fresh, dated)
+	// An error weighs three warnings; one issue per element scores zero.
+	health.Validation = 100
+	if health.Elements > 0 {
+		penalty := (3*health.Errors + health.Warnings) * 100 / health.Elements
+		health.Validation = max(0, 100-penalty)
+	}
+	if health.Elements == 0 {
+		health.Coverage, health.Diagrams, health.Freshness = 0, 0, 0
+	}
+
+	health.Score = (health.Coverage*healthWeightCoverage +
+		health.Validation*healthWeightValidation +
+		health.Freshness*healthWeightFreshness +
+		health.Diagrams*healthWeightDiagrams) / 100
+	health.Grade, health.Color = healthGrade(health.Score)
+	health.SchemaVersion = 1
+	health.Label = "architecture docs"
+	health.Message = fmt.Sprintf("%d%%", health.Score)
+	return health, nil
+}
+
+// percent returns part out of whole as a percentage from 0 to 100, or 100
+// when there is nothing to measure.
+func percent(part, whole int) int {
+	if whole == 0 {
+		return 100
+	}
+	return part * 100 / whole
+}
+
+// healthGrade returns the letter grade of score and the color of its badge.
+func healthGrade(score int) (grade, color string) {
+	switch {
+	case score >= 90:
+		return "A", "brightgreen"
+	case score >= 80:
+		return "B", "green"
+	case score >= 70:
+		return "C", "yellowgreen"
+	case score >= 60:
+		return "D", "yellow"
+	default:
+		return "F", "red"
+	}
+}
+
+// badgeColors maps the shields.io color names of health grades to hex values.
+var badgeColors = map[string]string{
+	"brightgreen": "#4c1",
+	"green":       "#97ca00",
+	"yellowgreen": "#a4a61d",
+	"yellow":      "#dfb317",
+	"red":         "#e05d44",
+}
+
+// HealthBadgeSVG returns a flat badge, in the style of shields.io, showing
+// the label and message of health.
+func HealthBadgeSVG(health *HealthScore) string {
+	// Text widths are estimated at 7px per character of 11px Verdana.
+	labelWidth := 7*len(health.Label) + 10
+	messageWidth := 7*len(health.Message) + 10
+	width := labelWidth + messageWidth
+	label, message := html.EscapeString(health.Label), html.EscapeString(health.Message)
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">
+<title>%[4]s: %[5]s</title>
+<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)"><rect width="%[2]d" height="20" fill="#555"/><rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/><rect width="%[1]d" height="20" fill="url(#s)"/></g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="%[7]d" y="15" fill="#010101" fill-opacity=".3">%[4]s</text><text x="%[7]d" y="14">%[4]s</text>
+<text x="%[8]d" y="15" fill="#010101" fill-opacity=".3">%[5]s</text><text x="%[8]d" y="14">%[5]s</text>
+</g>
+</svg>
+`, width, labelWidth, messageWidth, label, message, badgeColors[health.Color], labelWidth/2, labelWidth+messageWidth/2)
+}
+
+// WriteHealthBadge writes HealthBadgeFile and HealthFile for health into
+// outputDir.
+func WriteHealthBadge(outputDir string, health *HealthScore) error {
+	data, err := json.MarshalIndent(health, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode health score: %w", err)
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, HealthBadgeFile), []byte(HealthBadgeSVG(health)), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", HealthBadgeFile, err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, HealthFile), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", HealthFile, err)
+	}
+	return nil
+}
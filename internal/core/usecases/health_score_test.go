@@ -0,0 +1,118 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestComputeHealthScore(t *testing.T) {
+	project, _ := entities.NewProject("health")
+
+	payments, _ := entities.NewSystem("Payments")
+	payments.SetDescription("Handles payments")
+	payments.Diagram = &entities.Diagram{}
+	api, _ := entities.NewContainer("API")
+	api.SetDescription("Public API")
+	handler, _ := entities.NewComponent("Handler")
+	handler.SetDescription("Request handler")
+	ledger, _ := entities.NewComponent("Ledger")
+	ledger.AddRelationship("missing-component", "writes entries")
+	_ = api.AddComponent(handler)
+	_ = api.AddComponent(ledger)
+	worker, _ := entities.NewContainer("Worker")
+	_ = payments.AddContainer(api)
+	_ = payments.AddContainer(worker)
+
+	dir := t.TempDir()
+	payments.Path = dir
+	modTime := time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)
+	file := filepath.Join(dir, "system.md")
+	if err := os.WriteFile(file, []byte("# Payments"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(file, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	empty, _ := entities.NewSystem("Empty")
+
+	uc := NewComputeHealthScore()
+	uc.now = func() time.Time { return modTime.Add(30 * 24 * time.Hour) }
+	health, err := uc.Execute(context.Background(), project, []*entities.System{payments, empty, nil})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if health.Elements != 6 || health.Coverage != 50 {
+		t.Errorf("coverage = %d%% of %d elements, want 50%% of 6", health.Coverage, health.Elements)
+	}
+	if health.Diagrams != 25 {
+		t.Errorf("Diagrams = %d, want 25", health.Diagrams)
+	}
+	if health.Freshness != 100 || health.StaleSystems != 0 {
+		t.Errorf("freshness = %d%%, %d stale, want 100%%, 0", health.Freshness, health.StaleSystems)
+	}
+	wantValidation := max(0, 100-(3*health.Errors+health.Warnings)*100/6)
+	if health.Errors+health.Warnings == 0 || health.Validation != wantValidation {
+		t.Errorf("validation = %d%% with %d errors, %d warnings, want %d%%", health.Validation, health.Errors, health.Warnings, wantValidation)
+	}
+	wantScore := (50*40 + wantValidation*30 + 100*15 + 25*15) / 100
+	if health.Score != wantScore || health.Message != fmt.Sprintf("%d%%", wantScore) || health.SchemaVersion != 1 {
+		t.Errorf("score = %d (%q), want %d", health.Score, health.Message, wantScore)
+	}
+
+	uc.WithStaleAfter(7 * 24 * time.Hour)
+	stale, err := uc.Execute(context.Background(), project, []*entities.System{payments})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if stale.Freshness != 0 || stale.StaleSystems != 1 {
+		t.Errorf("freshness = %d%%, %d stale, want 0%%, 1", stale.Freshness, stale.StaleSystems)
+	}
+}
+
+func TestComputeHealthScoreNilProject(t *testing.T) {
+	if _, err := NewComputeHealthScore().Execute(context.Background(), nil, nil); err == nil {
+		t.Error("expected error for nil project")
+	}
+}
+
+func TestWriteHealthBadge(t *testing.T) {
+	dir := t.TempDir()
+	health := &HealthScore{Score: 72, Grade: "C", Color: "yellowgreen", SchemaVersion: 1, Label: "architecture docs", Message: "72%"}
+	if err := WriteHealthBadge(dir, health); err != nil {
+		t.Fatalf("WriteHealthBadge failed: %v", err)
+	}
+
+	badge, err := os.ReadFile(filepath.Join(dir, HealthBadgeFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<svg", "architecture docs", "72%", badgeColors["yellowgreen"]} {
+		if !strings.Contains(string(badge), want) {
+			t.Errorf("badge missing %q:\n%s", want, badge)
+		}
+	}
+	endpoint, err := os.ReadFile(filepath.Join(dir, HealthFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(endpoint), `"schemaVersion": 1`) || !strings.Contains(string(endpoint), `"color": "yellowgreen"`) {
+		t.Errorf("health.json = %s", endpoint)
+	}
+}
+
+func TestHealthGrade(t *testing.T) {
+	for score, want := range map[int]string{100: "A", 90: "A", 85: "B", 70: "C", 60: "D", 55: "F", 10: "F"} {
+		if grade, _ := healthGrade(score); grade != want {
+			t.Errorf("healthGrade(%d) = %q, want %q", score, grade, want)
+		}
+	}
+}
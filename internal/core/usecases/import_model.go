@@ -0,0 +1,157 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// ImportResult reports what ImportModel did, by qualified element ID.
+type ImportResult struct {
+	Created       []string // Elements written to disk
+	Skipped       []string // Elements that already existed and were left as they are
+	Relationships int      // Relationships created or already present
+	Warnings      []string // What the parser could not map
+}
+
+// ImportModel creates the elements and relationships of a model read from
+// another modelling tool, such as PlantUML C4 diagrams, in a project.
+//
+// Importing adds to the project without changing it: an element that already
+// exists is skipped, its imported children are still created, and an existing
+// relationship is kept as is. Importing the same sources twice is a no-op.
+type ImportModel struct {
+	parser        ExternalModelParser
+	repo          ProjectRepository
+	relationships *CreateRelationship // Optional: without it relationships are not imported
+	dryRun        bool
+}
+
+// NewImportModel creates a new ImportModel use case.
+func NewImportModel(parser ExternalModelParser, repo ProjectRepository) *ImportModel {
+	return &ImportModel{parser: parser, repo: repo}
+}
+
+// WithRelationships imports relationships into the relationships.toml of
+// their source's system.
+func (uc *ImportModel) WithRelationships(repo RelationshipRepository) *ImportModel {
+	uc.relationships = NewCreateRelationship(repo)
+	return uc
+}
+
+// WithDryRun reports what would be imported without writing anything.
+func (uc *ImportModel) WithDryRun(dryRun bool) *ImportModel {
+	uc.dryRun = dryRun
+	return uc
+}
+
+// Execute imports the model read from path into the project at projectRoot.
+func (uc *ImportModel) Execute(ctx context.Context, projectRoot, path string) (*ImportResult, error) {
+	model, err := uc.parser.ParseModel(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	existing, err := uc.repo.ListSystems(ctx, projectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list systems: %w", err)
+	}
+	systems := make(map[string]*entities.System, len(existing))
+	for _, system := range existing {
+		if system != nil {
+			systems[system.ID] = system
+		}
+	}
+
+	result := &ImportResult{Warnings: model.Warnings}
+	for _, imported := range model.Systems {
+		if err := uc.importSystem(ctx, projectRoot, imported, systems[imported.ID], result); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, rel := range model.Relationships {
+		if uc.relationships == nil {
+			break
+		}
+		if uc.dryRun {
+			result.Relationships++
+			continue
+		}
+		label := rel.Description
+		if label == "" {
+			label = "Uses"
+		}
+		parts, _ := entities.ParseQualifiedID(rel.Source)
+		req := &CreateRelationshipRequest{
+			ProjectRoot: projectRoot,
+			SystemID:    parts[0],
+			Source:      rel.Source,
+			Target:      rel.Target,
+			Label:       label,
+			Technology:  rel.Technology,
+		}
+		if rel.Bidirectional {
+			req.Direction = "bidirectional"
+		}
+		if _, err := uc.relationships.Execute(ctx, req); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("relationship %s -> %s: %v", rel.Source, rel.Target, err))
+			continue
+		}
+		result.Relationships++
+	}
+	return result, nil
+}
+
+// importSystem creates imported, or the children of current when the
+// system already exists.
+func (uc *ImportModel) importSystem(
+	ctx context.Context,
+	projectRoot string,
+	imported, current *entities.System,
+	result *ImportResult,
+) error {
+	if current != nil {
+		result.Skipped = append(result.Skipped, imported.ID)
+	} else {
+		result.Created = append(result.Created, imported.ID)
+		if !uc.dryRun {
+			if err := uc.repo.SaveSystem(ctx, projectRoot, imported); err != nil {
+				return fmt.Errorf("failed to save system %s: %w", imported.ID, err)
+			}
+		}
+	}
+
+	for _, container := range imported.ListContainers() {
+		containerID := entities.QualifiedNodeID("container", imported.ID, container.ID, "")
+		var currentContainer *entities.Container
+		if current != nil {
+			currentContainer = current.Containers[container.ID]
+		}
+		if currentContainer != nil {
+			result.Skipped = append(result.Skipped, containerID)
+		} else {
+			result.Created = append(result.Created, containerID)
+			if !uc.dryRun {
+				if err := uc.repo.SaveContainer(ctx, projectRoot, imported.ID, container); err != nil {
+					return fmt.Errorf("failed to save container %s: %w", containerID, err)
+				}
+			}
+		}
+
+		for _, component := range container.ListComponents() {
+			componentID := entities.QualifiedNodeID("component", imported.ID, container.ID, component.ID)
+			if currentContainer != nil && currentContainer.Components[component.ID] != nil {
+				result.Skipped = append(result.Skipped, componentID)
+				continue
+			}
+			result.Created = append(result.Created, componentID)
+			if !uc.dryRun {
+				if err := uc.repo.SaveComponent(ctx, projectRoot, imported.ID, container.ID, component); err != nil {
+					return fmt.Errorf("failed to save component %s: %w", componentID, err)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// mockModelParser returns a fixed model.
+type mockModelParser struct {
+	model *ExternalModel
+	err   error
+}
+
+func (m *mockModelParser) ParseModel(context.Context, string) (*ExternalModel, error) {
+	return m.model, m.err
+}
+
+// importRepository records the elements saved by ImportModel.
+type importRepository struct {
+	MockProjectRepository
+	saved []string
+}
+
+func (r *importRepository) SaveSystem(_ context.Context, _ string, system *entities.System) error {
+	r.saved = append(r.saved, system.ID)
+	return nil
+}
+
+func (r *importRepository) SaveContainer(_ context.Context, _, systemName string, container *entities.Container) error {
+	r.saved = append(r.saved, systemName+"/"+container.ID)
+	return nil
+}
+
+func (r *importRepository) SaveComponent(_ context.Context, _, systemName, containerName string, component *entities.Component) error {
+	r.saved = append(r.saved, systemName+"/"+containerName+"/"+component.ID)
+	return nil
+}
+
+// importFixture returns a model of a shop with a web container calling an
+// external payment system, and an existing project holding the shop and its
+// web container.
+func importFixture(t *testing.T) (*ExternalModel, []*entities.System) {
+	t.Helper()
+	shop, _ := entities.NewSystem("Shop")
+	web, _ := entities.NewContainer("Web")
+	checkout, _ := entities.NewComponent("Checkout")
+	_ = web.AddComponent(checkout)
+	_ = shop.AddContainer(web)
+	db, _ := entities.NewContainer("DB")
+	_ = shop.AddContainer(db)
+	payments, _ := entities.NewSystem("Payments")
+	payments.External = true
+
+	model := &ExternalModel{
+		Systems: []*entities.System{shop, payments},
+		Relationships: []ExternalRelationship{
+			{Source: "shop/web", Target: "payments", Description: "Charges cards", Technology: "HTTPS"},
+			{Source: "shop/web", Target: "shop/db"},
+		},
+		Warnings: []string{"a.puml: container \"Cache\" is not inside a System_Boundary; skipped"},
+	}
+
+	existingShop, _ := entities.NewSystem("Shop")
+	existingWeb, _ := entities.NewContainer("Web")
+	_ = existingShop.AddContainer(existingWeb)
+	return model, []*entities.System{existingShop}
+}
+
+func TestImportModel(t *testing.T) {
+	model, existing := importFixture(t)
+	repo := &importRepository{}
+	repo.ListSystemsFunc = func(context.Context, string) ([]*entities.System, error) { return existing, nil }
+	rels := newMockRelationshipRepository()
+	root := t.TempDir()
+
+	result, err := NewImportModel(&mockModelParser{model: model}, repo).
+		WithRelationships(rels).
+		Execute(context.Background(), root, "diagrams")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	wantCreated := []string{"shop/db", "shop/web/checkout", "payments"}
+	if !slices.Equal(result.Created, wantCreated) || !slices.Equal(repo.saved, wantCreated) {
+		t.Errorf("created = %v, saved = %v, want %v", result.Created, repo.saved, wantCreated)
+	}
+	if want := []string{"shop", "shop/web"}; !slices.Equal(result.Skipped, want) {
+		t.Errorf("skipped = %v, want %v", result.Skipped, want)
+	}
+	if result.Relationships != 2 || len(result.Warnings) != 1 {
+		t.Errorf("relationships = %d, warnings = %v", result.Relationships, result.Warnings)
+	}
+
+	stored := rels.stored(root, "shop")
+	if len(stored) != 2 {
+		t.Fatalf("expected 2 relationships in the shop system, got %+v", stored)
+	}
+	if stored[0].Label != "Charges cards" || stored[0].Technology != "HTTPS" {
+		t.Errorf("first relationship = %+v", stored[0])
+	}
+	if stored[1].Label != "Uses" {
+		t.Errorf("unlabelled relationship should default to Uses, got %q", stored[1].Label)
+	}
+
+	// Importing again creates the relationships no second time
+	if _, err := NewImportModel(&mockModelParser{model: model}, repo).WithRelationships(rels).Execute(context.Background(), root, "diagrams"); err != nil {
+		t.Fatalf("second Execute failed: %v", err)
+	}
+	if stored := rels.stored(root, "shop"); len(stored) != 2 {
+		t.Errorf("re-import duplicated relationships: %+v", stored)
+	}
+}
+
+func TestImportModelDryRun(t *testing.T) {
+	model, _ := importFixture(t)
+	repo := &importRepository{}
+	rels := newMockRelationshipRepository()
+
+	result, err := NewImportModel(&mockModelParser{model: model}, repo).
+		WithRelationships(rels).
+		WithDryRun(true).
+		Execute(context.Background(), t.TempDir(), "diagrams")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(result.Created) != 5 || result.Relationships != 2 {
+		t.Errorf("created = %v, relationships = %d", result.Created, result.Relationships)
+	}
+	if len(repo.saved) != 0 || len(rels.SaveCalls) != 0 {
+		t.Errorf("dry run wrote %v and %d relationship files", repo.saved, len(rels.SaveCalls))
+	}
+}
+
+func TestImportModelParseError(t *testing.T) {
+	failure := errors.New("no PlantUML files")
+	_, err := NewImportModel(&mockModelParser{err: failure}, &importRepository{}).Execute(context.Background(), t.TempDir(), "diagrams")
+	if !errors.Is(err, failure) {
+		t.Errorf("expected parser error to be wrapped, got %v", err)
+	}
+}
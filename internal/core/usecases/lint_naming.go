@@ -0,0 +1,198 @@
+package usecases
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// IDCaseKebab requires element IDs in kebab-case, e.g. "payment-service".
+const IDCaseKebab = "kebab"
+
+// kebabPattern matches a kebab-case ID.
+var kebabPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// technologySeparatorPattern matches a separator of a technology list with
+// the spaces around it, as in "Go, gRPC" or "Go ;gRPC".
+var technologySeparatorPattern = regexp.MustCompile(`\s*[,;|]\s*`)
+
+// LintNaming checks the names of elements against the [naming] conventions
+// of loko.toml: kebab-case IDs, a maximum name length, one separator in
+// technology lists and a required ID prefix for the systems of a domain.
+// Every issue is a warning suggesting the fix.
+//
+// The ID of an element is the name of its source directory, which the
+// element's URLs and references are derived from.
+type LintNaming struct {
+	idCase         string
+	maxNameLength  int
+	techSeparator  string
+	domainPrefixes map[string]string
+}
+
+// NewLintNaming creates a LintNaming use case with the rules of config.
+func NewLintNaming(config *entities.ProjectConfig) (*LintNaming, error) {
+	uc := &LintNaming{}
+	if config == nil {
+		return uc, nil
+	}
+	switch config.NamingIDCase {
+	case "", IDCaseKebab:
+	default:
+		return nil, fmt.Errorf("%w: [naming] id_case %q (expected %q)", entities.ErrInvalidConfig, config.NamingIDCase, IDCaseKebab)
+	}
+	if config.NamingMaxNameLength < 0 {
+		return nil, fmt.Errorf("%w: [naming] max_name_length %d", entities.ErrInvalidConfig, config.NamingMaxNameLength)
+	}
+	uc.idCase = config.NamingIDCase
+	uc.maxNameLength = config.NamingMaxNameLength
+	uc.techSeparator = config.NamingTechSeparator
+	uc.domainPrefixes = config.NamingDomainPrefixes
+	return uc, nil
+}
+
+// HasRules reports whether any naming convention is configured.
+func (uc *LintNaming) HasRules() bool {
+	return uc.idCase != "" || uc.maxNameLength > 0 || uc.techSeparator != "" || len(uc.domainPrefixes) > 0
+}
+
+// Execute adds a warning to report for every naming convention an element
+// of systems breaks.
+func (uc *LintNaming) Execute(systems []*entities.System, report *ArchitectureReport) {
+	sorted := slices.DeleteFunc(slices.Clone(systems), func(s *entities.System) bool { return s == nil })
+	slices.SortFunc(sorted, func(a, b *entities.System) int { return strings.Compare(a.ID, b.ID) })
+
+	for _, system := range sorted {
+		systemDir := elementDirName(system.Path, system.ID)
+		uc.checkID(system.ID, systemDir, report)
+		uc.checkName(system.ID, system.Name, report)
+		uc.checkDomainPrefix(system, systemDir, report)
+		for _, container := range system.ListContainers() {
+			containerID := system.ID + "/" + container.ID
+			uc.checkID(containerID, elementDirName(container.Path, container.ID), report)
+			uc.checkName(containerID, container.Name, report)
+			uc.checkTechnology(containerID, container.Technology, report)
+			for _, component := range container.ListComponents() {
+				componentID := containerID + "/" + component.ID
+				uc.checkID(componentID, elementDirName(component.Path, component.ID), report)
+				uc.checkName(componentID, component.Name, report)
+				uc.checkTechnology(componentID, component.Technology, report)
+			}
+		}
+	}
+}
+
+// checkID reports the element id whose directory dir is not in the
+// configured case.
+func (uc *LintNaming) checkID(id, dir string, report *ArchitectureReport) {
+	if uc.idCase != IDCaseKebab || kebabPattern.MatchString(dir) {
+		return
+	}
+	report.AddIssue(ArchitectureIssue{
+		Severity:    "warning",
+		Code:        "id_not_kebab_case",
+		Title:       "ID not in kebab-case",
+		Description: fmt.Sprintf("%s: directory %q is not kebab-case", id, dir),
+		Affected:    []string{id},
+		Suggestion:  fmt.Sprintf("Rename the directory %q to %q", dir, KebabCase(dir)),
+	})
+}
+
+// checkName reports the element id whose name is longer than allowed.
+func (uc *LintNaming) checkName(id, name string, report *ArchitectureReport) {
+	length := len([]rune(name))
+	if uc.maxNameLength == 0 || length <= uc.maxNameLength {
+		return
+	}
+	report.AddIssue(ArchitectureIssue{
+		Severity:    "warning",
+		Code:        "name_too_long",
+		Title:       "Name too long",
+		Description: fmt.Sprintf("%s: name %q has %d characters, more than %d", id, name, length, uc.maxNameLength),
+		Affected:    []string{id},
+		Suggestion:  fmt.Sprintf("Shorten the name to %d characters or fewer", uc.maxNameLength),
+	})
+}
+
+// checkTechnology reports the element id whose technology list is not
+// separated by the configured separator alone.
+func (uc *LintNaming) checkTechnology(id, technology string, report *ArchitectureReport) {
+	if uc.techSeparator == "" || technology == "" {
+		return
+	}
+	fixed := technologySeparatorPattern.ReplaceAllString(strings.TrimSpace(technology), uc.techSeparator)
+	if fixed == technology {
+		return
+	}
+	report.AddIssue(ArchitectureIssue{
+		Severity:    "warning",
+		Code:        "technology_separator",
+		Title:       "Inconsistent technology separator",
+		Description: fmt.Sprintf("%s: technology %q does not separate its items with %q", id, technology, uc.techSeparator),
+		Affected:    []string{id},
+		Suggestion:  fmt.Sprintf("Set technology to %q", fixed),
+	})
+}
+
+// checkDomainPrefix reports the system whose directory dir lacks the prefix
+// required in its domain. A prefix configured for a domain also applies to
+// its subdomains, the most specific one winning.
+func (uc *LintNaming) checkDomainPrefix(system *entities.System, dir string, report *ArchitectureReport) {
+	if system.Domain == "" {
+		return
+	}
+	domain, prefix := "", ""
+	for d, p := range uc.domainPrefixes {
+		if (system.Domain == d || strings.HasPrefix(system.Domain, d+".")) && len(d) > len(domain) {
+			domain, prefix = d, p
+		}
+	}
+	if prefix == "" || strings.HasPrefix(dir, prefix) {
+		return
+	}
+	report.AddIssue(ArchitectureIssue{
+		Severity:    "warning",
+		Code:        "missing_domain_prefix",
+		Title:       "Missing domain prefix",
+		Description: fmt.Sprintf("%s: systems of domain %q must start with %q", system.ID, domain, prefix),
+		Affected:    []string{system.ID},
+		Suggestion:  fmt.Sprintf("Rename the directory %q to %q", dir, prefix+dir),
+	})
+}
+
+// elementDirName returns the name of the source directory of an element, or
+// id when it was not loaded from disk.
+func elementDirName(path, id string) string {
+	if path == "" {
+		return id
+	}
+	return filepath.Base(path)
+}
+
+// KebabCase converts s to kebab-case: words split at spaces, punctuation and
+// lower-to-upper case changes, lowercased and joined with hyphens, as in
+// "PaymentService_v2" to "payment-service-v2".
+func KebabCase(s string) string {
+	var sb strings.Builder
+	var prev rune
+	for _, r := range s {
+		switch {
+		case unicode.IsUpper(r):
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(unicode.ToLower(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			sb.WriteRune(r)
+		default:
+			sb.WriteByte('-')
+		}
+		prev = r
+	}
+	return entities.NormalizeName(sb.String())
+}
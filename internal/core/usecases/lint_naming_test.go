@@ -0,0 +1,97 @@
+package usecases
+
+import (
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestLintNaming(t *testing.T) {
+	system, _ := entities.NewSystem("Payments")
+	system.Path = "/project/src/finance/cards/Payments_Core"
+	container, _ := entities.NewContainer("API")
+	container.Path = "/project/src/finance/cards/Payments_Core/api"
+	container.Technology = "Go, gRPC"
+	component, _ := entities.NewComponent("Authorization Request Handler")
+	component.Path = "/project/src/finance/cards/Payments_Core/api/authHandler"
+	component.Technology = "Go,net/http"
+	_ = container.AddComponent(component)
+	_ = system.AddContainer(container)
+	system.SetDomain("finance.cards")
+
+	uc, err := NewLintNaming(&entities.ProjectConfig{
+		NamingIDCase:         "kebab",
+		NamingMaxNameLength:  20,
+		NamingTechSeparator:  ",",
+		NamingDomainPrefixes: map[string]string{"finance": "fin-", "finance.cards": "card-"},
+	})
+	if err != nil {
+		t.Fatalf("NewLintNaming failed: %v", err)
+	}
+	if !uc.HasRules() {
+		t.Error("HasRules() = false")
+	}
+	report := &ArchitectureReport{}
+	uc.Execute([]*entities.System{system}, report)
+
+	want := []struct{ code, affected, suggestion string }{
+		{"id_not_kebab_case", "finance.cards.payments", `Rename the directory "Payments_Core" to "payments-core"`},
+		{"missing_domain_prefix", "finance.cards.payments", `Rename the directory "Payments_Core" to "card-Payments_Core"`},
+		{"technology_separator", "finance.cards.payments/api", `Set technology to "Go,gRPC"`},
+		{"id_not_kebab_case", "finance.cards.payments/api/authorization-request-handler", `Rename the directory "authHandler" to "auth-handler"`},
+		{"name_too_long", "finance.cards.payments/api/authorization-request-handler", "Shorten the name to 20 characters or fewer"},
+	}
+	if len(report.Issues) != len(want) {
+		t.Fatalf("got %d issues, want %d: %+v", len(report.Issues), len(want), report.Issues)
+	}
+	for i, w := range want {
+		issue := report.Issues[i]
+		if issue.Code != w.code || issue.Affected[0] != w.affected || issue.Suggestion != w.suggestion {
+			t.Errorf("issue %d = %+v, want %s on %s suggesting %s", i, issue, w.code, w.affected, w.suggestion)
+		}
+		if issue.Severity != "warning" {
+			t.Errorf("issue %d severity = %q", i, issue.Severity)
+		}
+	}
+}
+
+func TestLintNaming_NoRules(t *testing.T) {
+	system, _ := entities.NewSystem("Payments Platform")
+	system.Path = "/project/src/PaymentsPlatform"
+
+	uc, err := NewLintNaming(&entities.ProjectConfig{})
+	if err != nil {
+		t.Fatalf("NewLintNaming failed: %v", err)
+	}
+	if uc.HasRules() {
+		t.Error("HasRules() = true")
+	}
+	report := &ArchitectureReport{}
+	uc.Execute([]*entities.System{system}, report)
+	if len(report.Issues) != 0 {
+		t.Errorf("issues = %+v", report.Issues)
+	}
+}
+
+func TestNewLintNaming_InvalidConfig(t *testing.T) {
+	if _, err := NewLintNaming(&entities.ProjectConfig{NamingIDCase: "snake"}); err == nil {
+		t.Error("expected error for unknown id_case")
+	}
+	if _, err := NewLintNaming(&entities.ProjectConfig{NamingMaxNameLength: -1}); err == nil {
+		t.Error("expected error for negative max_name_length")
+	}
+}
+
+func TestKebabCase(t *testing.T) {
+	tests := map[string]string{
+		"PaymentService_v2": "payment-service-v2",
+		"auth handler":      "auth-handler",
+		"OAuth2Client":      "oauth2-client",
+		"already-kebab":     "already-kebab",
+	}
+	for in, want := range tests {
+		if got := KebabCase(in); got != want {
+			t.Errorf("KebabCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// MergeComponents merges a duplicate component into another: the component
+// kept takes over the duplicate's fields, relationships to the duplicate are
+// rewritten to the component kept, and the duplicate's ID is recorded as an
+// alias so that references to it keep resolving.
+type MergeComponents struct {
+	editor        FrontmatterEditor
+	relationships RelationshipRepository // Optional: relationships.toml is not rewritten without it
+	trash         Trash                  // Optional: the duplicate is removed permanently without it
+}
+
+// MergePlan describes the edits of a merge.
+type MergePlan struct {
+	Keep      string   // Qualified ID of the component kept
+	Duplicate string   // Qualified ID of the component removed
+	Aliases   []string // Aliases of the component kept after the merge
+	Inbound   []string // Qualified IDs of the components whose relationships are rewritten
+	Changes   []string // Human-readable list of the edits
+
+	keep      *entities.Component
+	duplicate *entities.Component
+	merged    *entities.Component        // Fields of keep after the merge
+	inbound   map[string]*inboundRewrite // By qualified ID
+	systems   []*entities.System
+}
+
+// inboundRewrite is the relationships map of a component after the merge.
+type inboundRewrite struct {
+	path          string
+	relationships map[string]string
+}
+
+// NewMergeComponents creates a MergeComponents use case.
+func NewMergeComponents(editor FrontmatterEditor, relationships RelationshipRepository) *MergeComponents {
+	return &MergeComponents{editor: editor, relationships: relationships}
+}
+
+// WithTrash moves the duplicate's directory to trash instead of removing it.
+func (uc *MergeComponents) WithTrash(trash Trash) *MergeComponents {
+	uc.trash = trash
+	return uc
+}
+
+// Plan computes the merge of the component duplicateID into keepID. Both are
+// qualified or unique component IDs.
+//
+// Empty fields of the component kept are filled from the duplicate; lists and
+// maps are combined, keeping the kept component's value on conflict.
+// Relationships between the two components are dropped.
+func (uc *MergeComponents) Plan(systems []*entities.System, keepID, duplicateID string) (*MergePlan, error) {
+	components, shortIDs := indexComponents(systems)
+	keepQID, err := resolveComponentID(components, shortIDs, keepID)
+	if err != nil {
+		return nil, err
+	}
+	duplicateQID, err := resolveComponentID(components, shortIDs, duplicateID)
+	if err != nil {
+		return nil, err
+	}
+	if keepQID == duplicateQID {
+		return nil, fmt.Errorf("cannot merge %s into itself", keepQID)
+	}
+	keep, duplicate := components[keepQID], components[duplicateQID]
+	if keep.Path == "" || duplicate.Path == "" {
+		return nil, fmt.Errorf("components loaded from a project are required")
+	}
+
+	plan := &MergePlan{
+		Keep:      keepQID,
+		Duplicate: duplicateQID,
+		keep:      keep,
+		duplicate: duplicate,
+		inbound:   make(map[string]*inboundRewrite),
+		systems:   systems,
+	}
+
+	// Targets that mean the duplicate: its qualified ID, its aliases and its
+	// component ID when no other component shares it
+	duplicateRefs := map[string]bool{duplicateQID: true}
+	for _, alias := range duplicate.Aliases {
+		duplicateRefs[alias] = true
+	}
+	if len(shortIDs[duplicate.ID]) == 1 {
+		duplicateRefs[duplicate.ID] = true
+	}
+	selfRefs := maps.Clone(duplicateRefs)
+	selfRefs[keepQID] = true
+	for _, alias := range keep.Aliases {
+		selfRefs[alias] = true
+	}
+	if len(shortIDs[keep.ID]) == 1 || keep.ID == duplicate.ID {
+		selfRefs[keep.ID] = true
+	}
+
+	merged := *keep
+	merged.Description = cmp.Or(keep.Description, duplicate.Description)
+	merged.Technology = cmp.Or(keep.Technology, duplicate.Technology)
+	merged.Tags = unionStrings(keep.Tags, duplicate.Tags)
+	merged.Dependencies = unionStrings(keep.Dependencies, duplicate.Dependencies)
+	merged.Issues = unionStrings(keep.Issues, duplicate.Issues)
+	merged.Controls = unionStrings(keep.Controls, duplicate.Controls)
+	merged.Aliases = slices.DeleteFunc(unionStrings(keep.Aliases, []string{duplicateQID}, duplicate.Aliases),
+		func(alias string) bool { return alias == keepQID })
+	merged.CodeAnnotations = unionMaps(keep.CodeAnnotations, duplicate.CodeAnnotations)
+	merged.Relationships = unionMaps(keep.Relationships, duplicate.Relationships)
+	maps.DeleteFunc(merged.Relationships, func(target, _ string) bool { return selfRefs[target] })
+	plan.merged = &merged
+	plan.Aliases = merged.Aliases
+
+	if merged.Description != keep.Description {
+		plan.Changes = append(plan.Changes, fmt.Sprintf("Copy the description of %s", duplicateQID))
+	}
+	if merged.Technology != keep.Technology {
+		plan.Changes = append(plan.Changes, fmt.Sprintf("Copy the technology of %s", duplicateQID))
+	}
+	for _, field := range []struct {
+		name          string
+		before, after int
+	}{
+		{"tag(s)", len(keep.Tags), len(merged.Tags)},
+		{"dependency(ies)", len(keep.Dependencies), len(merged.Dependencies)},
+		{"issue(s)", len(keep.Issues), len(merged.Issues)},
+		{"control(s)", len(keep.Controls), len(merged.Controls)},
+		{"code annotation(s)", len(keep.CodeAnnotations), len(merged.CodeAnnotations)},
+	} {
+		if field.after > field.before {
+			plan.Changes = append(plan.Changes, fmt.Sprintf("Add %d %s of %s", field.after-field.before, field.name, duplicateQID))
+		}
+	}
+	if !maps.Equal(merged.Relationships, keep.Relationships) {
+		plan.Changes = append(plan.Changes, fmt.Sprintf("Combine the relationships of %s", duplicateQID))
+	}
+	plan.Changes = append(plan.Changes, fmt.Sprintf("Record %s as an alias of %s", duplicateQID, keepQID))
+
+	for _, id := range slices.Sorted(maps.Keys(components)) {
+		comp := components[id]
+		if id == keepQID || id == duplicateQID || comp.Path == "" {
+			continue
+		}
+		rewritten := make(map[string]string, len(comp.Relationships))
+		changed := false
+		for _, target := range slices.Sorted(maps.Keys(comp.Relationships)) {
+			label := comp.Relationships[target]
+			if duplicateRefs[target] {
+				target, changed = keepQID, true
+			}
+			if _, ok := rewritten[target]; !ok || comp.Relationships[target] == label {
+				rewritten[target] = label
+			}
+		}
+		if changed {
+			plan.inbound[id] = &inboundRewrite{path: filepath.Join(comp.Path, "component.md"), relationships: rewritten}
+			plan.Inbound = append(plan.Inbound, id)
+			plan.Changes = append(plan.Changes, fmt.Sprintf("Point the relationships of %s at %s", id, keepQID))
+		}
+	}
+	plan.Changes = append(plan.Changes, fmt.Sprintf("Delete %s", duplicate.Path))
+	return plan, nil
+}
+
+// Apply performs a merge returned by Plan: it edits the frontmatter of the
+// component kept and of the components relating to the duplicate, rewrites
+// relationships.toml and deletes the duplicate's directory last, moving it
+// to the trash when one is configured.
+func (uc *MergeComponents) Apply(ctx context.Context, projectRoot string, plan *MergePlan) error {
+	keep, merged := plan.keep, plan.merged
+	path := filepath.Join(keep.Path, "component.md")
+
+	for _, field := range []struct{ key, before, after string }{
+		{"description", keep.Description, merged.Description},
+		{"technology", keep.Technology, merged.Technology},
+	} {
+		if field.after != field.before {
+			if err := uc.editor.SetFrontmatterField(ctx, path, field.key, field.after); err != nil {
+				return fmt.Errorf("failed to update %s: %w", plan.Keep, err)
+			}
+		}
+	}
+	for _, list := range []struct {
+		key           string
+		before, after []string
+	}{
+		{"tags", keep.Tags, merged.Tags},
+		{"dependencies", keep.Dependencies, merged.Dependencies},
+		{"issues", keep.Issues, merged.Issues},
+		{"controls", keep.Controls, merged.Controls},
+		{"aliases", keep.Aliases, merged.Aliases},
+	} {
+		if !slices.Equal(list.after, list.before) {
+			if err := uc.editor.SetFrontmatterList(ctx, path, list.key, list.after); err != nil {
+				return fmt.Errorf("failed to update %s: %w", plan.Keep, err)
+			}
+		}
+	}
+	for _, m := range []struct {
+		key           string
+		before, after map[string]string
+	}{
+		{"relationships", keep.Relationships, merged.Relationships},
+		{"code_annotations", keep.CodeAnnotations, merged.CodeAnnotations},
+	} {
+		if !maps.Equal(m.after, m.before) {
+			if err := uc.editor.SetFrontmatterMap(ctx, path, m.key, m.after); err != nil {
+				return fmt.Errorf("failed to update %s: %w", plan.Keep, err)
+			}
+		}
+	}
+
+	for _, id := range plan.Inbound {
+		rewrite := plan.inbound[id]
+		if err := uc.editor.SetFrontmatterMap(ctx, rewrite.path, "relationships", rewrite.relationships); err != nil {
+			return fmt.Errorf("failed to update %s: %w", id, err)
+		}
+	}
+
+	if uc.relationships != nil {
+		for _, sys := range plan.systems {
+			if sys == nil {
+				continue
+			}
+			if err := uc.rewriteRelationships(ctx, projectRoot, sys.ID, plan); err != nil {
+				return err
+			}
+		}
+	}
+
+	if uc.trash != nil {
+		dir, err := filepath.Rel(projectRoot, plan.duplicate.Path)
+		if err != nil {
+			return fmt.Errorf("failed to delete %s: %w", plan.Duplicate, err)
+		}
+		reason := fmt.Sprintf("merge %s into %s", plan.Duplicate, plan.Keep)
+		if _, err := uc.trash.Move(ctx, []string{dir}, reason); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", plan.Duplicate, err)
+		}
+		return nil
+	}
+	if err := os.RemoveAll(plan.duplicate.Path); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", plan.Duplicate, err)
+	}
+	return nil
+}
+
+// rewriteRelationships points the relationships.toml entries of a system
+// that involve the duplicate at the component kept, dropping those that
+// become self-references or duplicates.
+func (uc *MergeComponents) rewriteRelationships(ctx context.Context, projectRoot, systemID string, plan *MergePlan) error {
+	rels, err := uc.relationships.LoadRelationships(ctx, projectRoot, systemID)
+	if err != nil {
+		return fmt.Errorf("failed to load relationships of %s: %w", systemID, err)
+	}
+	rewritten := make([]entities.Relationship, 0, len(rels))
+	seen := make(map[string]bool)
+	changed := false
+	for _, rel := range rels {
+		if rel.Source == plan.Duplicate || rel.Target == plan.Duplicate {
+			changed = true
+			if rel.Source == plan.Duplicate {
+				rel.Source = plan.Keep
+			}
+			if rel.Target == plan.Duplicate {
+				rel.Target = plan.Keep
+			}
+			if rel.Source == rel.Target {
+				continue
+			}
+			rel.ID = entities.GenerateRelationshipID(rel.Source, rel.Target, rel.Label)
+		}
+		if seen[rel.ID] {
+			continue
+		}
+		seen[rel.ID] = true
+		rewritten = append(rewritten, rel)
+	}
+	if !changed {
+		return nil
+	}
+	if err := uc.relationships.SaveRelationships(ctx, projectRoot, systemID, rewritten); err != nil {
+		return fmt.Errorf("failed to save relationships of %s: %w", systemID, err)
+	}
+	return nil
+}
+
+// indexComponents returns the components of systems by qualified ID and the
+// qualified IDs of each component ID.
+func indexComponents(systems []*entities.System) (map[string]*entities.Component, map[string][]string) {
+	components := make(map[string]*entities.Component)
+	shortIDs := make(map[string][]string)
+	for _, sys := range systems {
+		if sys == nil {
+			continue
+		}
+		for _, cont := range sys.ListContainers() {
+			for _, comp := range cont.ListComponents() {
+				id := sys.ID + "/" + cont.ID + "/" + comp.ID
+				components[id] = comp
+				shortIDs[comp.ID] = append(shortIDs[comp.ID], id)
+			}
+		}
+	}
+	return components, shortIDs
+}
+
+// resolveComponentID returns the qualified ID of a qualified or unique
+// component ID.
+func resolveComponentID(components map[string]*entities.Component, shortIDs map[string][]string, id string) (string, error) {
+	if _, ok := components[id]; ok {
+		return id, nil
+	}
+	switch candidates := shortIDs[id]; len(candidates) {
+	case 0:
+		return "", fmt.Errorf("component %q not found", id)
+	case 1:
+		return candidates[0], nil
+	default:
+		slices.Sort(candidates)
+		return "", fmt.Errorf("component %q is ambiguous: use one of %s", id, strings.Join(candidates, ", "))
+	}
+}
+
+// unionStrings returns the distinct items of lists in order of first
+// appearance.
+func unionStrings(lists ...[]string) []string {
+	var union []string
+	for _, list := range lists {
+		for _, item := range list {
+			if !slices.Contains(union, item) {
+				union = append(union, item)
+			}
+		}
+	}
+	return union
+}
+
+// unionMaps returns the entries of a and the entries of b whose keys are not
+// in a.
+func unionMaps(a, b map[string]string) map[string]string {
+	union := maps.Clone(b)
+	if union == nil {
+		union = make(map[string]string)
+	}
+	maps.Copy(union, a)
+	return union
+}
@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestMergeComponents(t *testing.T) {
+	root := t.TempDir()
+	system, _ := entities.NewSystem("Shop")
+	system.Path = filepath.Join(root, "src", "shop")
+	api, _ := entities.NewContainer("API")
+	worker, _ := entities.NewContainer("Worker")
+	newComponent := func(container *entities.Container, name string) *entities.Component {
+		component, _ := entities.NewComponent(name)
+		component.Path = filepath.Join(system.Path, container.ID, component.ID)
+		if err := container.AddComponent(component); err != nil {
+			t.Fatal(err)
+		}
+		return component
+	}
+	store := newComponent(api, "Order Store")
+	store.Tags = []string{"db"}
+	store.Relationships = map[string]string{"shop/worker/orders": "Replicates to"}
+	orders := newComponent(worker, "Orders")
+	orders.Description = "Persists orders"
+	orders.Tags = []string{"db", "postgres"}
+	orders.Aliases = []string{"shop/legacy/orders"}
+	orders.Relationships = map[string]string{"shop/api/handler": "Notifies"}
+	handler := newComponent(api, "Handler")
+	handler.Relationships = map[string]string{"orders": "Writes", "shop/legacy/orders": "Reads"}
+	for _, c := range []*entities.Container{api, worker} {
+		if err := system.AddContainer(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(orders.Path, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	editor := &recordingEditor{}
+	rels := newMockRelationshipRepository()
+	kept, _ := entities.NewRelationship("shop/api/handler", "shop/api/order-store", "Writes")
+	moved, _ := entities.NewRelationship("shop/api/handler", "shop/worker/orders", "Writes")
+	self, _ := entities.NewRelationship("shop/api/order-store", "shop/worker/orders", "Replicates to")
+	rels.seed(root, "shop", []entities.Relationship{*kept, *moved, *self})
+
+	uc := NewMergeComponents(editor, rels)
+	if _, err := uc.Plan([]*entities.System{system}, "order-store", "order-store"); err == nil {
+		t.Error("Plan() of a component into itself should fail")
+	}
+	if _, err := uc.Plan([]*entities.System{system}, "order-store", "ledger"); err == nil {
+		t.Error("Plan() of an unknown component should fail")
+	}
+	plan, err := uc.Plan([]*entities.System{system}, "order-store", "shop/worker/orders")
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if plan.Keep != "shop/api/order-store" || !slices.Equal(plan.Inbound, []string{"shop/api/handler"}) {
+		t.Errorf("plan = %+v", plan)
+	}
+
+	if err := uc.Apply(context.Background(), root, plan); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	wantEdits := []string{
+		"order-store/component.md set description=Persists orders",
+		"order-store/component.md set tags=[db,postgres]",
+		"order-store/component.md set aliases=[shop/worker/orders,shop/legacy/orders]",
+		"order-store/component.md set relationships={shop/api/handler:Notifies}",
+		"handler/component.md set relationships={shop/api/order-store:Writes}",
+	}
+	if !slices.Equal(editor.edits, wantEdits) {
+		t.Errorf("edits =\n%q\nwant\n%q", editor.edits, wantEdits)
+	}
+
+	stored := rels.stored(root, "shop")
+	if len(stored) != 1 || stored[0].ID != kept.ID {
+		t.Errorf("relationships.toml = %+v, want only %s", stored, kept.ID)
+	}
+	if _, err := os.Stat(orders.Path); !os.IsNotExist(err) {
+		t.Errorf("duplicate directory still exists: %v", err)
+	}
+}
@@ -0,0 +1,295 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// BackupsDir is the directory, relative to the project root, where
+// MigrateProject keeps the original of every file it changes.
+const BackupsDir = ".loko/backups"
+
+// Migration upgrades projects of schema version From to From+1.
+type Migration struct {
+	From        int
+	Description string
+
+	// Apply rewrites files, the project's loko.toml and source files keyed by
+	// slash-separated path relative to the project root; deleting a key
+	// removes the file. sourceDir is the primary source directory, cleaned
+	// and slash-separated.
+	Apply func(files map[string]string, sourceDir string) error
+}
+
+// Migrations upgrade projects from entities.LegacySchemaVersion to
+// entities.CurrentSchemaVersion, in order. A format change that would make
+// loko misread existing projects bumps CurrentSchemaVersion and appends the
+// migration rewriting them.
+var Migrations = []Migration{
+	{
+		From:        1,
+		Description: "Move relationships.toml files from ./src next to their system in the source directory",
+		Apply:       migrateLegacyRelationships,
+	},
+	{
+		From:        2,
+		Description: "Rewrite inline tags and dependencies lists in frontmatter as block lists",
+		Apply:       migrateFlowLists,
+	},
+}
+
+// MigrateProjectResult describes the migration of a project.
+type MigrateProjectResult struct {
+	From    int                   // Schema version before the migration
+	To      int                   // Schema version after the migration
+	Applied []string              // Descriptions of the migrations applied, in order
+	Changes []entities.FileChange // Files added, modified or removed
+
+	// BackupDir holds the original of every modified or removed file,
+	// relative to the project root; empty when nothing was written
+	BackupDir string
+}
+
+// MigrateProject upgrades a project to entities.CurrentSchemaVersion by
+// applying the Migrations from its schema version on and recording the new
+// version in loko.toml. Every file it modifies or removes is first copied to
+// .loko/backups/migrate-<time>/ so that a migration can be undone by copying
+// the files back.
+type MigrateProject struct {
+	projectRoot string
+	config      *entities.ProjectConfig
+	migrations  []Migration
+	dryRun      bool
+	now         func() time.Time
+}
+
+// NewMigrateProject creates a MigrateProject use case for the project at
+// projectRoot, whose loko.toml was loaded into config.
+func NewMigrateProject(projectRoot string, config *entities.ProjectConfig) *MigrateProject {
+	return &MigrateProject{
+		projectRoot: projectRoot,
+		config:      config,
+		migrations:  Migrations,
+		now:         time.Now,
+	}
+}
+
+// WithDryRun plans the migration without writing any file.
+func (uc *MigrateProject) WithDryRun(dryRun bool) *MigrateProject {
+	uc.dryRun = dryRun
+	return uc
+}
+
+// Execute migrates the project. A project already at the current schema
+// version is left untouched; one of a newer version returns
+// entities.ErrSchemaTooNew.
+func (uc *MigrateProject) Execute(ctx context.Context) (*MigrateProjectResult, error) {
+	if uc.config == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	version := uc.config.SchemaVersion
+	if version == 0 {
+		version = entities.LegacySchemaVersion
+	}
+	if version > entities.CurrentSchemaVersion {
+		return nil, fmt.Errorf("%w: schema_version %d, this loko supports up to %d", entities.ErrSchemaTooNew, version, entities.CurrentSchemaVersion)
+	}
+
+	result := &MigrateProjectResult{From: version, To: entities.CurrentSchemaVersion}
+	if version == entities.CurrentSchemaVersion {
+		return result, nil
+	}
+
+	sourceDir := path.Clean(filepath.ToSlash(uc.config.SourceDir))
+	before, err := uc.snapshot(sourceDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project files: %w", err)
+	}
+	if _, ok := before["loko.toml"]; !ok {
+		return nil, fmt.Errorf("%w: no loko.toml in %s", entities.ErrProjectNotFound, uc.projectRoot)
+	}
+
+	after := maps.Clone(before)
+	for _, m := range uc.migrations {
+		if m.From < version || m.From >= entities.CurrentSchemaVersion {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := m.Apply(after, sourceDir); err != nil {
+			return nil, fmt.Errorf("migration from schema version %d failed: %w", m.From, err)
+		}
+		result.Applied = append(result.Applied, m.Description)
+	}
+	after["loko.toml"] = setSchemaVersion(after["loko.toml"], entities.CurrentSchemaVersion)
+	result.Changes = DiffSnapshots(before, after)
+
+	if uc.dryRun {
+		return result, nil
+	}
+	result.BackupDir = path.Join(BackupsDir, "migrate-"+uc.now().Format("20060102-150405"))
+	if err := uc.write(before, after, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// snapshot returns loko.toml and the files of the source directory, plus the
+// relationships.toml files under ./src that older versions read when the
+// source directory was elsewhere.
+func (uc *MigrateProject) snapshot(sourceDir string) (map[string]string, error) {
+	files, err := snapshotSources(uc.projectRoot, sourceDir)
+	if err != nil || sourceDir == "src" {
+		return files, err
+	}
+	legacy, err := snapshotSources(uc.projectRoot, "src")
+	if err != nil {
+		return nil, err
+	}
+	for p, content := range legacy {
+		if path.Base(p) == "relationships.toml" {
+			files[p] = content
+		}
+	}
+	return files, nil
+}
+
+// write backs up the files that change, then applies the changes.
+func (uc *MigrateProject) write(before, after map[string]string, result *MigrateProjectResult) error {
+	backupDir := filepath.Join(uc.projectRoot, filepath.FromSlash(result.BackupDir))
+	for _, change := range result.Changes {
+		if change.Op == entities.FileAdded {
+			continue
+		}
+		// Source directories outside the project root are backed up under _up/.
+		backup := filepath.Join(backupDir, filepath.FromSlash(strings.ReplaceAll(change.Path, "../", "_up/")))
+		if err := writeMigratedFile(backup, before[change.Path]); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", change.Path, err)
+		}
+	}
+
+	for _, change := range result.Changes {
+		target := filepath.Join(uc.projectRoot, filepath.FromSlash(change.Path))
+		var err error
+		if change.Op == entities.FileRemoved {
+			err = os.Remove(target)
+		} else {
+			err = writeMigratedFile(target, after[change.Path])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to migrate %s (originals are in %s): %w", change.Path, result.BackupDir, err)
+		}
+	}
+	return nil
+}
+
+// writeMigratedFile writes content to path, creating its directory.
+func writeMigratedFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0o644)
+}
+
+// schemaVersionPattern matches the schema_version line of loko.toml.
+var schemaVersionPattern = regexp.MustCompile(`(?m)^schema_version\s*=.*$`)
+
+// setSchemaVersion records version in the content of loko.toml, replacing
+// the schema_version line or adding one at the top, before any table.
+func setSchemaVersion(content string, version int) string {
+	line := fmt.Sprintf("schema_version = %d", version)
+	if schemaVersionPattern.MatchString(content) {
+		return schemaVersionPattern.ReplaceAllString(content, line)
+	}
+	return line + "\n\n" + content
+}
+
+// migrateLegacyRelationships moves the relationships.toml of each system of a
+// source directory other than ./src from ./src/<system>/ to the system's
+// directory. Older versions only read relationships from ./src.
+func migrateLegacyRelationships(files map[string]string, sourceDir string) error {
+	if sourceDir == "src" || path.IsAbs(sourceDir) {
+		return nil
+	}
+	for _, p := range slices.Sorted(maps.Keys(files)) {
+		rest, ok := strings.CutPrefix(p, "src/")
+		if !ok || path.Base(rest) != "relationships.toml" {
+			continue
+		}
+		systemDir := path.Join(sourceDir, path.Dir(rest))
+		if _, ok := files[path.Join(systemDir, "system.md")]; !ok {
+			continue
+		}
+		target := path.Join(systemDir, "relationships.toml")
+		if _, ok := files[target]; ok {
+			continue // The system's own file takes precedence
+		}
+		files[target] = files[p]
+		delete(files, p)
+	}
+	return nil
+}
+
+// flowListPattern matches a top-level frontmatter list written inline, such
+// as `tags: [api, "critical"]`, which loko reads as a block list only.
+var flowListPattern = regexp.MustCompile(`^(tags|dependencies):\s*\[(.*)\]\s*$`)
+
+// migrateFlowLists rewrites the inline tags and dependencies lists in the
+// frontmatter of the Markdown files of the source directory as block lists.
+func migrateFlowLists(files map[string]string, sourceDir string) error {
+	for p, content := range files {
+		if (sourceDir != "." && !strings.HasPrefix(p, sourceDir+"/")) || path.Ext(p) != ".md" {
+			continue
+		}
+		files[p] = rewriteFlowLists(content)
+	}
+	return nil
+}
+
+// rewriteFlowLists rewrites the inline lists of a file's frontmatter as
+// block lists, dropping empty ones.
+func rewriteFlowLists(content string) string {
+	lines := strings.Split(content, "\n")
+	if len(lines) < 3 || lines[0] != "---" {
+		return content
+	}
+
+	out := []string{lines[0]}
+	for i := 1; i < len(lines); i++ {
+		line := lines[i]
+		if line == "---" {
+			out = append(out, lines[i:]...)
+			break
+		}
+		m := flowListPattern.FindStringSubmatch(line)
+		if m == nil {
+			out = append(out, line)
+			continue
+		}
+		var items []string
+		for item := range strings.SplitSeq(m[2], ",") {
+			if item = strings.Trim(strings.TrimSpace(item), "\"'"); item != "" {
+				items = append(items, item)
+			}
+		}
+		if len(items) == 0 {
+			continue
+		}
+		out = append(out, m[1]+":")
+		for _, item := range items {
+			out = append(out, fmt.Sprintf("  - %q", item))
+		}
+	}
+	return strings.Join(out, "\n")
+}
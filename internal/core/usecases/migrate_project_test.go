@@ -0,0 +1,101 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestMigrateProject(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	lokoToml := "[project]\nname = \"shop\"\n\n[paths]\nsource = \"./architecture\"\n"
+	systemMd := "---\nname: \"Shop\"\ntags: [core, \"critical\"]\n---\n# Shop\n"
+	relationships := "[[relationships]]\nsource = \"shop/api\"\ntarget = \"shop/db\"\n"
+	for path, content := range map[string]string{
+		"loko.toml":                          lokoToml,
+		"architecture/shop/system.md":        systemMd,
+		"architecture/shop/api/api.md":       "---\nname: \"API\"\ndependencies: []\n---\n",
+		"src/shop/relationships.toml":        relationships,
+		"src/unknown/relationships.toml":     relationships,
+		"architecture/shop/api/notes.txt":    "tags: [kept]\n",
+		"architecture/shop/api/container.d2": "api: API\n",
+	} {
+		writeTestFile(t, filepath.Join(root, path), content)
+	}
+	config := entities.DefaultProjectConfig()
+	config.SchemaVersion = entities.LegacySchemaVersion
+	config.SourceDir = "./architecture"
+
+	// A dry run plans the changes without writing them
+	plan, err := NewMigrateProject(root, config).WithDryRun(true).Execute(ctx)
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if plan.From != 1 || plan.To != entities.CurrentSchemaVersion || len(plan.Applied) != 2 || plan.BackupDir != "" {
+		t.Errorf("dry run = %+v", plan)
+	}
+	var paths []string
+	for _, change := range plan.Changes {
+		paths = append(paths, string(change.Op)+" "+change.Path)
+	}
+	want := "modified architecture/shop/api/api.md, added architecture/shop/relationships.toml, modified architecture/shop/system.md, modified loko.toml, removed src/shop/relationships.toml"
+	if got := strings.Join(paths, ", "); got != want {
+		t.Errorf("changes = %s\nwant %s", got, want)
+	}
+	if content, _ := os.ReadFile(filepath.Join(root, "loko.toml")); string(content) != lokoToml {
+		t.Error("dry run wrote loko.toml")
+	}
+
+	uc := NewMigrateProject(root, config)
+	uc.now = func() time.Time { return time.Date(2026, 10, 16, 14, 25, 1, 0, time.UTC) }
+	result, err := uc.Execute(ctx)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.BackupDir != ".loko/backups/migrate-20261016-142501" {
+		t.Errorf("BackupDir = %q", result.BackupDir)
+	}
+	for path, want := range map[string]string{
+		"loko.toml":                                       "schema_version = 3\n\n" + lokoToml,
+		"architecture/shop/system.md":                     "---\nname: \"Shop\"\ntags:\n  - \"core\"\n  - \"critical\"\n---\n# Shop\n",
+		"architecture/shop/api/api.md":                    "---\nname: \"API\"\n---\n",
+		"architecture/shop/relationships.toml":            relationships,
+		"src/unknown/relationships.toml":                  relationships,
+		"architecture/shop/api/notes.txt":                 "tags: [kept]\n",
+		result.BackupDir + "/loko.toml":                   lokoToml,
+		result.BackupDir + "/src/shop/relationships.toml": relationships,
+		result.BackupDir + "/architecture/shop/system.md": systemMd,
+	} {
+		if content, err := os.ReadFile(filepath.Join(root, path)); err != nil || string(content) != want {
+			t.Errorf("%s = %q, %v; want %q", path, content, err, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "src", "shop", "relationships.toml")); !os.IsNotExist(err) {
+		t.Error("legacy relationships.toml not removed")
+	}
+
+	// Migrating a current project changes nothing
+	config.SchemaVersion = entities.CurrentSchemaVersion
+	if result, err := NewMigrateProject(root, config).Execute(ctx); err != nil || len(result.Changes) != 0 {
+		t.Errorf("Execute on a current project = %+v, %v", result, err)
+	}
+
+	config.SchemaVersion = entities.CurrentSchemaVersion + 1
+	if _, err := NewMigrateProject(root, config).Execute(ctx); !errors.Is(err, entities.ErrSchemaTooNew) {
+		t.Errorf("Execute on a newer project: error = %v, want ErrSchemaTooNew", err)
+	}
+}
+
+func TestSetSchemaVersion(t *testing.T) {
+	got := setSchemaVersion("schema_version = 2\n\n[project]\nname = \"x\"\n", 3)
+	if want := "schema_version = 3\n\n[project]\nname = \"x\"\n"; got != want {
+		t.Errorf("setSchemaVersion = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,220 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// Kinds of ElementChange.
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// ElementChange is a change to one element or dependency.
+type ElementChange struct {
+	Kind    string `json:"kind"`    // ChangeAdded, ChangeRemoved or ChangeModified
+	Type    string `json:"type"`    // "system", "container", "component" or "dependency"
+	Element string `json:"element"` // Qualified element ID, or "source -> target" for a dependency
+}
+
+// OwnerNotification summarizes the changes in the area of one owner.
+type OwnerNotification struct {
+	Owner   string          `json:"owner"`
+	BaseRef string          `json:"base_ref"`
+	Changes []ElementChange `json:"changes"` // Sorted by element, then kind
+}
+
+// NotifyOwners compares the project at a base revision with the working tree
+// and notifies the owners of the changed elements, so distributed teams learn
+// about architecture edits in their area.
+type NotifyOwners struct {
+	repo         ProjectRepository
+	revisions    RevisionExporter
+	notifiers    []ChangeNotifier // Optional: without any the notifications are only returned
+	graphBuilder *BuildArchitectureGraph
+}
+
+// NewNotifyOwners creates a new NotifyOwners use case.
+func NewNotifyOwners(repo ProjectRepository, revisions RevisionExporter) *NotifyOwners {
+	return &NotifyOwners{
+		repo:         repo,
+		revisions:    revisions,
+		graphBuilder: NewBuildArchitectureGraph(),
+	}
+}
+
+// WithNotifier adds a channel the notifications are delivered through.
+func (uc *NotifyOwners) WithNotifier(notifier ChangeNotifier) *NotifyOwners {
+	uc.notifiers = append(uc.notifiers, notifier)
+	return uc
+}
+
+// WithGraphBuilder sets the graph builder used for both revisions.
+func (uc *NotifyOwners) WithGraphBuilder(graphBuilder *BuildArchitectureGraph) *NotifyOwners {
+	uc.graphBuilder = graphBuilder
+	return uc
+}
+
+// Execute builds one notification per owner of an element changed between
+// baseRef and projectRoot and delivers each through every notifier. Delivery
+// failures do not stop other deliveries; they are returned joined, together
+// with the notifications.
+func (uc *NotifyOwners) Execute(ctx context.Context, projectRoot, baseRef string) ([]OwnerNotification, error) {
+	base, head, err := loadRevisions(ctx, uc.repo, uc.revisions, uc.graphBuilder, projectRoot, baseRef)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := GroupChangesByOwner(baseRef, DiffArchitecture(base.graph, head.graph), base.systems, head.systems)
+
+	var errs []error
+	for _, notification := range notifications {
+		for _, notifier := range uc.notifiers {
+			if err := notifier.Notify(ctx, notification); err != nil {
+				errs = append(errs, fmt.Errorf("failed to notify %s: %w", notification.Owner, err))
+			}
+		}
+	}
+	return notifications, errors.Join(errs...)
+}
+
+// ownedElement is an element with the owners of its area: its own owner, then
+// those of its parent component, container and system.
+type ownedElement struct {
+	elementType string
+	owners      []string
+	signature   string // Name, description, technology and tags, to detect edits
+}
+
+// GroupChangesByOwner attributes the changes between baseSystems and
+// headSystems to owners, sorted by owner. A change is reported to the owner of
+// the element and to the owners of its parents. Added and modified elements
+// are attributed by their owners at head, removed ones by those at base, and
+// dependencies by the owners of their source. Changes outside any owner's
+// area are not reported.
+func GroupChangesByOwner(baseRef string, diff *ArchitectureDiff, baseSystems, headSystems []*entities.System) []OwnerNotification {
+	base := indexOwnedElements(baseSystems)
+	head := indexOwnedElements(headSystems)
+
+	changes := make(map[string][]ElementChange)
+	record := func(index map[string]ownedElement, id string, change ElementChange) {
+		for _, owner := range index[id].owners {
+			changes[owner] = append(changes[owner], change)
+		}
+	}
+
+	if diff != nil {
+		for _, list := range []struct {
+			ids         []string
+			elementType string
+			kind        string
+		}{
+			{diff.AddedSystems, "system", ChangeAdded},
+			{diff.AddedContainers, "container", ChangeAdded},
+			{diff.AddedComponents, "component", ChangeAdded},
+			{diff.RemovedSystems, "system", ChangeRemoved},
+			{diff.RemovedContainers, "container", ChangeRemoved},
+			{diff.RemovedComponents, "component", ChangeRemoved},
+		} {
+			index := head
+			if list.kind == ChangeRemoved {
+				index = base
+			}
+			for _, id := range list.ids {
+				record(index, id, ElementChange{Kind: list.kind, Type: list.elementType, Element: id})
+			}
+		}
+		for _, dep := range diff.AddedDependencies {
+			record(head, dep.Source, ElementChange{Kind: ChangeAdded, Type: "dependency", Element: dep.Source + " -> " + dep.Target})
+		}
+		for _, dep := range diff.RemovedDependencies {
+			record(base, dep.Source, ElementChange{Kind: ChangeRemoved, Type: "dependency", Element: dep.Source + " -> " + dep.Target})
+		}
+	}
+
+	for id, element := range head {
+		if previous, ok := base[id]; ok && previous.signature != element.signature {
+			record(head, id, ElementChange{Kind: ChangeModified, Type: element.elementType, Element: id})
+		}
+	}
+
+	var notifications []OwnerNotification
+	for _, owner := range slices.Sorted(maps.Keys(changes)) {
+		ownerChanges := changes[owner]
+		slices.SortFunc(ownerChanges, func(a, b ElementChange) int {
+			return cmp.Or(cmp.Compare(a.Element, b.Element), cmp.Compare(a.Kind, b.Kind))
+		})
+		notifications = append(notifications, OwnerNotification{Owner: owner, BaseRef: baseRef, Changes: ownerChanges})
+	}
+	return notifications
+}
+
+// indexOwnedElements indexes the elements of systems by qualified ID.
+func indexOwnedElements(systems []*entities.System) map[string]ownedElement {
+	index := make(map[string]ownedElement)
+	for _, system := range systems {
+		if system == nil {
+			continue
+		}
+		systemOwners := appendOwner(nil, entities.OwnerOf(system.Metadata))
+		index[system.ID] = ownedElement{
+			elementType: "system",
+			owners:      systemOwners,
+			signature:   elementSignature(system.Name, system.Description, "", system.Tags),
+		}
+		for _, container := range system.Containers {
+			containerOwners := appendOwner(slices.Clone(systemOwners), entities.OwnerOf(container.Metadata))
+			index[system.ID+"/"+container.ID] = ownedElement{
+				elementType: "container",
+				owners:      containerOwners,
+				signature:   elementSignature(container.Name, container.Description, container.Technology, container.Tags),
+			}
+			for _, component := range container.Components {
+				owners := slices.Clone(containerOwners)
+				if parent, ok := container.Components[component.Parent]; ok && component.Parent != "" {
+					owners = appendOwner(owners, entities.OwnerOf(parent.Metadata))
+				}
+				index[system.ID+"/"+container.ID+"/"+component.ID] = ownedElement{
+					elementType: "component",
+					owners:      appendOwner(owners, entities.OwnerOf(component.Metadata)),
+					signature:   elementSignature(component.Name, component.Description, component.Technology, component.Tags),
+				}
+			}
+		}
+	}
+	return index
+}
+
+// appendOwner adds owner to owners unless it is empty or already present.
+func appendOwner(owners []string, owner string) []string {
+	if owner == "" || slices.Contains(owners, owner) {
+		return owners
+	}
+	return append(owners, owner)
+}
+
+// elementSignature joins the fields of an element whose edits are reported.
+func elementSignature(name, description, technology string, tags []string) string {
+	return strings.Join([]string{name, description, technology, strings.Join(tags, ",")}, "\x00")
+}
+
+// RenderOwnerNotification formats a notification as plain text, one change
+// per line, for chat messages and emails.
+func RenderOwnerNotification(notification OwnerNotification) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Architecture changes in the area of %s since %s:\n\n", notification.Owner, notification.BaseRef)
+	symbols := map[string]string{ChangeAdded: "+", ChangeRemoved: "-", ChangeModified: "~"}
+	for _, change := range notification.Changes {
+		fmt.Fprintf(&b, "  %s %s %s\n", symbols[change.Kind], change.Type, change.Element)
+	}
+	return b.String()
+}
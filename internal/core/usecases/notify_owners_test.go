@@ -0,0 +1,104 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// recordingNotifier records the owners it notified and fails with err.
+type recordingNotifier struct {
+	owners []string
+	err    error
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, notification OwnerNotification) error {
+	n.owners = append(n.owners, notification.Owner)
+	return n.err
+}
+
+// ownersFixture returns a payments system owned by platform-team whose API is
+// owned by alice@example.com. The head edits the API's description, adds a
+// ledger the handler depends on and removes the worker.
+func ownersFixture(head bool) []*entities.System {
+	system, _ := entities.NewSystem("Payments")
+	system.Metadata = map[string]any{entities.OwnerField: "platform-team"}
+	api, _ := entities.NewContainer("API")
+	api.Metadata = map[string]any{entities.OwnerField: "alice@example.com"}
+	handler, _ := entities.NewComponent("Handler")
+	_ = api.AddComponent(handler)
+	_ = system.AddContainer(api)
+
+	unowned, _ := entities.NewSystem("Legacy")
+	if !head {
+		worker, _ := entities.NewContainer("Worker")
+		_ = system.AddContainer(worker)
+		return []*entities.System{system, unowned}
+	}
+
+	api.Description = "Serves payments"
+	ledger, _ := entities.NewComponent("Ledger")
+	handler.AddRelationship("payments/api/ledger", "records payment")
+	_ = api.AddComponent(ledger)
+	batch, _ := entities.NewContainer("Batch")
+	_ = unowned.AddContainer(batch)
+	return []*entities.System{system, unowned}
+}
+
+func TestNotifyOwners(t *testing.T) {
+	repo := &MockProjectRepository{
+		LoadProjectFunc: func(_ context.Context, _ string) (*entities.Project, error) {
+			return entities.NewProject("payments")
+		},
+		ListSystemsFunc: func(_ context.Context, root string) ([]*entities.System, error) {
+			return ownersFixture(root == "head"), nil
+		},
+	}
+	notifier := &recordingNotifier{}
+	failing := &recordingNotifier{err: errors.New("unreachable")}
+
+	notifications, err := NewNotifyOwners(repo, &mockRevisionExporter{}).
+		WithNotifier(notifier).
+		WithNotifier(failing).
+		Execute(context.Background(), "head", "origin/main")
+	if err == nil || !strings.Contains(err.Error(), "failed to notify alice@example.com: unreachable") {
+		t.Errorf("Execute() error = %v, want joined delivery errors", err)
+	}
+	if len(notifications) != 2 || !slices.Equal(notifier.owners, []string{"alice@example.com", "platform-team"}) {
+		t.Fatalf("notified %q, notifications = %+v", notifier.owners, notifications)
+	}
+
+	// The API owner sees changes in the API; the system owner sees them too,
+	// plus the removed worker. Changes to the unowned system are not reported.
+	alice := []ElementChange{
+		{Kind: ChangeModified, Type: "container", Element: "payments/api"},
+		{Kind: ChangeAdded, Type: "dependency", Element: "payments/api/handler -> payments/api/ledger"},
+		{Kind: ChangeAdded, Type: "component", Element: "payments/api/ledger"},
+	}
+	if notifications[0].BaseRef != "origin/main" || !slices.Equal(notifications[0].Changes, alice) {
+		t.Errorf("alice changes = %+v", notifications[0].Changes)
+	}
+	platform := append(slices.Clone(alice), ElementChange{Kind: ChangeRemoved, Type: "container", Element: "payments/worker"})
+	if !slices.Equal(notifications[1].Changes, platform) {
+		t.Errorf("platform-team changes = %+v", notifications[1].Changes)
+	}
+
+	text := RenderOwnerNotification(notifications[1])
+	for _, want := range []string{
+		"Architecture changes in the area of platform-team since origin/main:",
+		"  ~ container payments/api\n",
+		"  - container payments/worker\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("rendered notification missing %q:\n%s", want, text)
+		}
+	}
+
+	if _, err := NewNotifyOwners(repo, &mockRevisionExporter{}).Execute(context.Background(), "head", ""); err == nil {
+		t.Error("expected error for empty base ref")
+	}
+}
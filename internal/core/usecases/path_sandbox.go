@@ -0,0 +1,96 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// PathSandbox confines the paths that server clients pass, such as the
+// project_root and output_dir of MCP tool calls, to a set of root
+// directories, so loko can be hosted for untrusted agents.
+//
+// Paths are canonicalized before they are checked: made absolute, cleaned of
+// "." and "..", and resolved through symbolic links, so neither traversal
+// nor a link pointing out of a root escapes the sandbox.
+type PathSandbox struct {
+	roots []string // Canonical; relative paths resolve against the first
+}
+
+// NewPathSandbox creates a sandbox of roots, which must be existing
+// directories. Relative paths are resolved against the first root.
+func NewPathSandbox(roots ...string) (*PathSandbox, error) {
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("sandbox needs at least one root")
+	}
+	sandbox := &PathSandbox{}
+	for _, root := range roots {
+		canonical, err := canonicalPath(root)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sandbox root %q: %w", root, err)
+		}
+		if info, err := os.Stat(canonical); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("invalid sandbox root %q: not a directory", root)
+		}
+		sandbox.roots = append(sandbox.roots, canonical)
+	}
+	return sandbox, nil
+}
+
+// Roots returns the canonical roots of the sandbox.
+func (s *PathSandbox) Roots() []string {
+	return append([]string(nil), s.roots...)
+}
+
+// Resolve returns the canonical form of path, a relative one resolved against
+// the first root. A path outside every root is an error wrapping
+// entities.ErrPathOutsideSandbox. The path need not exist, so it can name an
+// output directory to create.
+func (s *PathSandbox) Resolve(path string) (string, error) {
+	if strings.ContainsRune(path, 0) {
+		return "", fmt.Errorf("%w: %q", entities.ErrPathOutsideSandbox, path)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(s.roots[0], path)
+	}
+	canonical, err := canonicalPath(path)
+	if err != nil {
+		return "", err
+	}
+	for _, root := range s.roots {
+		if rel, err := filepath.Rel(root, canonical); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return canonical, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", entities.ErrPathOutsideSandbox, path)
+}
+
+// canonicalPath returns path made absolute and cleaned, with the symbolic
+// links of its longest existing prefix resolved.
+func canonicalPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	existing, rest := abs, ""
+	for {
+		resolved, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			return filepath.Join(resolved, rest), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return abs, nil
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+}
@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestPathSandbox(t *testing.T) {
+	root := t.TempDir()
+	other := t.TempDir()
+	outside := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "src"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	sandbox, err := NewPathSandbox(root, other)
+	if err != nil {
+		t.Fatalf("NewPathSandbox failed: %v", err)
+	}
+	canonicalRoot, _ := filepath.EvalSymlinks(root)
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string // Empty when the path is rejected
+		wantErr bool
+	}{
+		{name: "relative", path: "src", want: filepath.Join(canonicalRoot, "src")},
+		{name: "root itself", path: ".", want: canonicalRoot},
+		{name: "missing output dir", path: "dist/site", want: filepath.Join(canonicalRoot, "dist", "site")},
+		{name: "second root", path: other, want: mustEvalSymlinks(t, other)},
+		{name: "traversal", path: "../elsewhere", wantErr: true},
+		{name: "absolute outside", path: outside, wantErr: true},
+		{name: "symlink out", path: "escape/dist", wantErr: true},
+		{name: "NUL byte", path: "src\x00", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sandbox.Resolve(tt.path)
+			if tt.wantErr {
+				if !errors.Is(err, entities.ErrPathOutsideSandbox) {
+					t.Fatalf("expected ErrPathOutsideSandbox, got %q, %v", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve(%q) failed: %v", tt.path, err)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPathSandboxInvalidRoot(t *testing.T) {
+	if _, err := NewPathSandbox(); err == nil {
+		t.Error("expected error without roots")
+	}
+	file := filepath.Join(t.TempDir(), "loko.toml")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPathSandbox(file); err == nil {
+		t.Error("expected error for a root that is not a directory")
+	}
+}
+
+func mustEvalSymlinks(t *testing.T, path string) string {
+	t.Helper()
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resolved
+}
@@ -2,6 +2,7 @@ package usecases
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
@@ -42,6 +43,15 @@ type ProjectRepository interface {
 	SaveComponent(ctx context.Context, projectRoot, systemName, containerName string, component *entities.Component) error
 }
 
+// PersonRepository stores the people (C4 users and actors) of a project.
+type PersonRepository interface {
+	// ListPeople returns all people in a project.
+	ListPeople(ctx context.Context, projectRoot string) ([]*entities.Person, error)
+
+	// SavePerson persists a person to disk.
+	SavePerson(ctx context.Context, projectRoot string, person *entities.Person) error
+}
+
 // TemplateEngine defines the interface for rendering templates using variable substitution.
 //
 // Implementations MUST support template discovery from both global (~/.loko/templates/)
@@ -181,6 +191,59 @@ type PDFRenderer interface {
 	IsAvailable() bool
 }
 
+// AssetMinifier defines the interface for shrinking generated site assets.
+//
+// Implementations MUST rewrite files in place and leave the site functionally
+// unchanged; files that cannot be minified safely are skipped.
+type AssetMinifier interface {
+	// MinifyDir minifies HTML, CSS, JS and SVG files under dir.
+	// Returns the combined size of the processed files before and after.
+	MinifyDir(ctx context.Context, dir string) (before, after int64, err error)
+}
+
+// InventoryWriter defines the interface for writing tabular architecture
+// inventories (systems, containers, components, relationships).
+//
+// Implementations MUST write one file per sheet and preserve the header and
+// row order they are given.
+type InventoryWriter interface {
+	// WriteInventory writes each sheet to outputDir and returns the paths written.
+	WriteInventory(ctx context.Context, outputDir string, sheets []InventorySheet) ([]string, error)
+}
+
+// CorpusWriter defines the interface for writing a chunked corpus for
+// retrieval-augmented generation (RAG).
+//
+// Implementations MUST write the text of the chunks and their metadata in the
+// order given, keyed by chunk ID, so the two can be joined after embedding.
+type CorpusWriter interface {
+	// WriteCorpus writes chunks to outputDir and returns the paths written.
+	WriteCorpus(ctx context.Context, outputDir string, chunks []CorpusChunk) ([]string, error)
+}
+
+// ExternalModelExporter defines the interface for serializing the architecture
+// model to the language of another modelling tool, such as Structurizr DSL.
+//
+// Implementations MUST map systems, containers and components to the tool's
+// C4 levels and write elements and relationships in the order given, so
+// repeated exports of an unchanged project are identical.
+type ExternalModelExporter interface {
+	// ExportModel writes model to w.
+	ExportModel(ctx context.Context, model *ExternalModel, w io.Writer) error
+}
+
+// ExternalModelParser defines the interface for reading an architecture
+// model from the sources of another modelling tool, such as PlantUML C4
+// diagrams.
+//
+// Implementations MUST return systems holding their containers and
+// components, relationships between the qualified IDs of those elements, and
+// a warning for every element or relationship they skip rather than fail.
+type ExternalModelParser interface {
+	// ParseModel reads the model of the file or directory at path.
+	ParseModel(ctx context.Context, path string) (*ExternalModel, error)
+}
+
 // ConfigLoader defines the interface for loading and parsing configuration files.
 //
 // Implementations MUST support loko.toml (TOML format) with hierarchical config
@@ -275,12 +338,18 @@ type MCPServer interface {
 
 // MarkdownBuilder defines the interface for generating Markdown documentation.
 //
-// Implementations MUST produce a single README.md file with complete architecture
-// documentation suitable for version control and viewing in text editors.
+// Implementations MUST produce a README.md file with complete architecture
+// documentation, or linking per-system files, suitable for version control
+// and viewing in text editors.
 type MarkdownBuilder interface {
 	// BuildMarkdown generates a single README.md with complete architecture.
 	BuildMarkdown(ctx context.Context, project *entities.Project, systems []*entities.System) (content string, err error)
 
+	// BuildMarkdownFiles generates the Markdown documentation laid out as
+	// configured in opts, keyed by slash-separated path relative to the
+	// output directory.
+	BuildMarkdownFiles(ctx context.Context, project *entities.Project, systems []*entities.System, opts MarkdownOptions) (files map[string]string, err error)
+
 	// BuildSystemMarkdown generates Markdown for a single system.
 	BuildSystemMarkdown(ctx context.Context, system *entities.System, containers []*entities.Container) (content string, err error)
 }
@@ -320,6 +389,14 @@ type PathResolver interface {
 	// ThemesDir returns the path to the themes directory.
 	// Returns DataDir()/themes/
 	ThemesDir() string
+
+	// PluginsDir returns the path to the plugins directory.
+	// Returns DataDir()/plugins/
+	PluginsDir() string
+
+	// IconsDir returns the path to the icon packs directory.
+	// Returns DataDir()/icons/
+	IconsDir() string
 }
 
 // ThemeLoader loads and lists available themes.
@@ -424,3 +501,361 @@ type D2Parser interface {
 	// - Partial parse success: Return relationships successfully parsed + log warnings
 	ParseRelationships(ctx context.Context, d2Source string) ([]entities.D2Relationship, error)
 }
+
+// D2DiagramParser parses both the shapes and the relationship arrows of D2
+// diagrams.
+type D2DiagramParser interface {
+	D2Parser
+
+	// ParseShapes returns the dotted paths of the shapes declared in D2 source
+	// code, e.g. "backend.api-server", parents before their children.
+	// Returns an error if parsing fails.
+	ParseShapes(ctx context.Context, d2Source string) ([]string, error)
+}
+
+// DiagramCompiler checks that D2 diagrams compile, without rendering them.
+type DiagramCompiler interface {
+	// CompileDiagram compiles D2 source code, including its layout, and
+	// returns the compile error, if any. Nothing is written.
+	CompileDiagram(ctx context.Context, d2Source string) error
+}
+
+// HistoryProvider reads the revision history of a project's source files.
+//
+// Implementations typically shell out to git. Paths in returned entries MUST be
+// relative to projectRoot using forward slashes. Implementations MUST return
+// entities.ErrNoHistory when projectRoot is not under version control.
+type HistoryProvider interface {
+	// ListRevisions returns revisions touching files under pathPrefix, oldest first.
+	ListRevisions(ctx context.Context, projectRoot, pathPrefix string) ([]Revision, error)
+}
+
+// Revision is a single entry in a project's revision history.
+type Revision struct {
+	// Commit is the revision identifier (e.g., git SHA).
+	Commit string
+	// Author is the revision author name.
+	Author string
+	// Date is the author date of the revision.
+	Date time.Time
+	// Message is the revision summary line.
+	Message string
+	// Changes lists the files touched by the revision.
+	Changes []FileRevision
+}
+
+// FileRevision describes how a single file changed within a revision.
+type FileRevision struct {
+	// Path relative to the project root, using forward slashes.
+	Path string
+	// Status is one of: added, modified, deleted
+	Status string
+}
+
+// RevisionExporter materializes a past revision of a project on disk.
+//
+// Implementations typically use `git archive`. Implementations MUST return
+// entities.ErrNoHistory when projectRoot is not under version control.
+type RevisionExporter interface {
+	// ExportRevision writes the project directory as it was at ref into destDir,
+	// so that destDir can be loaded like the project root.
+	ExportRevision(ctx context.Context, projectRoot, ref, destDir string) error
+}
+
+// PullRequestCommenter posts a summary comment on a pull or merge request.
+//
+// Implementations talk to a code hosting provider's API (GitHub, GitLab).
+// They MUST update the existing comment containing marker instead of adding a
+// new one, so repeated CI runs keep a single comment up to date.
+type PullRequestCommenter interface {
+	// UpsertComment creates or updates the comment identified by marker and
+	// returns its URL when the provider reports one.
+	UpsertComment(ctx context.Context, marker, body string) (string, error)
+}
+
+// ChangeNotifier tells the owner of changed elements what changed in their area.
+//
+// Implementations deliver through a channel such as a webhook or email. An
+// implementation that cannot reach an owner (e.g. email for an owner that is
+// not an address) MUST skip it and return nil.
+type ChangeNotifier interface {
+	// Notify delivers the summary of the changes in one owner's area.
+	Notify(ctx context.Context, notification OwnerNotification) error
+}
+
+// IssueTracker looks up the status of tickets referenced from `issues:` frontmatter.
+//
+// Implementations talk to an issue tracker's API (e.g. Jira). They MUST return
+// an error wrapping entities.ErrIssueNotFound when the ticket does not exist.
+type IssueTracker interface {
+	// IssueStatus returns the current status of the ticket with the given key.
+	IssueStatus(ctx context.Context, key string) (*IssueStatus, error)
+}
+
+// IssueStatus is the state of a tracker ticket.
+type IssueStatus struct {
+	// Key is the ticket ID, e.g. "PAY-123".
+	Key string
+	// Status is the tracker's workflow status name, e.g. "In Progress".
+	Status string
+	// Resolution explains how a closed ticket ended, e.g. "Won't Do"; empty while open.
+	Resolution string
+	// Closed reports whether the ticket is done or abandoned.
+	Closed bool
+}
+
+// FileEncrypter encrypts architecture source files at rest.
+//
+// Implementations typically shell out to `age`, encrypting to the project's
+// recipients (age or SSH public keys) and decrypting with the local identity.
+type FileEncrypter interface {
+	// Encrypt returns plaintext encrypted to the configured recipients.
+	Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
+	// Decrypt returns the plaintext of ciphertext using the local identity.
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// AuditLog stores the audit trail of architecture modifications.
+//
+// Implementations append entries durably (e.g. JSON lines in .loko/audit.log)
+// and return them oldest first.
+type AuditLog interface {
+	// Append adds an entry to the log.
+	Append(ctx context.Context, entry *entities.AuditEntry) error
+	// Entries returns every entry, oldest first. A missing log has none.
+	Entries(ctx context.Context) ([]*entities.AuditEntry, error)
+}
+
+// UsageLog stores the opt-in local record of command usage.
+//
+// Implementations append events durably (e.g. JSON lines in .loko/usage.log)
+// and return them oldest first. They MUST NOT send them anywhere.
+type UsageLog interface {
+	// Append adds an event to the log.
+	Append(ctx context.Context, event *entities.UsageEvent) error
+	// Events returns every event, oldest first. A missing log has none.
+	Events(ctx context.Context) ([]*entities.UsageEvent, error)
+}
+
+// Trash keeps deleted files and directories of a project so that they can be
+// restored.
+//
+// Implementations move the paths out of the source tree (e.g. to
+// .loko/trash/<timestamp>/) instead of removing them. Use cases that delete
+// elements MUST delete through a Trash when one is configured.
+type Trash interface {
+	// Move moves paths, relative to the project root or absolute paths
+	// inside it, to the trash as a single entry.
+	Move(ctx context.Context, paths []string, reason string) (*entities.TrashEntry, error)
+	// Entries returns the entries in the trash, oldest first.
+	Entries(ctx context.Context) ([]*entities.TrashEntry, error)
+	// Restore moves the paths of entry id back to where they were deleted
+	// from and removes the entry. It fails without restoring anything if a
+	// path has been recreated since.
+	Restore(ctx context.Context, id string) (*entities.TrashEntry, error)
+	// Purge permanently removes entry id, or every entry when id is empty,
+	// and returns the entries removed.
+	Purge(ctx context.Context, id string) ([]*entities.TrashEntry, error)
+}
+
+// BuildLogs stores the structured logs of documentation builds, so a failed
+// build can be inspected without running it again.
+//
+// Implementations keep one log per build (e.g. JSON lines in
+// .loko/builds/<id>.jsonl), append to it as the build reports progress, and
+// may drop the oldest logs.
+type BuildLogs interface {
+	// Create starts the log of a new build and returns the build's ID.
+	Create(ctx context.Context) (string, error)
+	// Append adds entry to the log of build id.
+	Append(ctx context.Context, id string, entry entities.BuildLogEntry) error
+	// Entries returns the log of build id, oldest first. It fails with
+	// entities.ErrBuildLogNotFound for an unknown build.
+	Entries(ctx context.Context, id string) ([]entities.BuildLogEntry, error)
+	// List returns the IDs of the stored logs, oldest first.
+	List(ctx context.Context) ([]string, error)
+}
+
+// BuildCache keeps what earlier builds produced, keyed by a hash of the
+// inputs it was produced from, so that builds skip unchanged work: rendering
+// diagrams whose D2 source and renderer are the same, and regenerating pages
+// whose content and templates are the same.
+//
+// Implementations persist entries across runs (e.g. under .loko/cache/) and
+// MUST discard them all when the version of loko that wrote them changes.
+type BuildCache interface {
+	// Diagram returns the SVG cached for key, see DiagramCacheKey.
+	Diagram(ctx context.Context, key string) (svg string, ok bool)
+	// PutDiagram caches the SVG rendered for key.
+	PutDiagram(ctx context.Context, key, svg string) error
+	// Pages returns the input keys of the pages the last build wrote to
+	// outputDir, by page path. It is empty when nothing is cached.
+	Pages(ctx context.Context, outputDir string) map[string]string
+	// PutPages replaces the input keys of the pages written to outputDir.
+	PutPages(ctx context.Context, outputDir string, pages map[string]string) error
+	// Clear removes every entry.
+	Clear(ctx context.Context) error
+}
+
+// IDIndexStore stores the ID index of a project, which editors and agents read
+// to complete relationship targets.
+//
+// Implementations replace the index atomically (e.g. .loko/ids.json), so a
+// reader never sees a partial file, and may skip writing an unchanged index.
+type IDIndexStore interface {
+	// SaveIDIndex replaces the stored index with index.
+	SaveIDIndex(ctx context.Context, index *entities.IDIndex) error
+}
+
+// ChangeCommitter records changes to project files in version control.
+//
+// Implementations commit only the given paths, leaving other changes in the
+// working tree and index alone.
+type ChangeCommitter interface {
+	// CommitFiles commits the current content of paths, relative to
+	// projectRoot, with message. Paths that no longer exist are committed as
+	// removals.
+	CommitFiles(ctx context.Context, projectRoot string, paths []string, message string) error
+}
+
+// ArtifactRegistry stores documentation artifacts in an OCI registry.
+//
+// Implementations typically shell out to `oras`, reusing the registry
+// credentials of the local container tooling, so published artifacts can be
+// signed and verified with existing workflows (e.g. cosign).
+type ArtifactRegistry interface {
+	// Push uploads the given files of dir as the layers of an artifact of
+	// artifactType tagged ref, and returns the digest of its manifest.
+	Push(ctx context.Context, ref, artifactType, dir string, layers []ArtifactLayer) (string, error)
+	// Pull downloads the layers of the artifact tagged ref into dir.
+	Pull(ctx context.Context, ref, dir string) error
+}
+
+// ArtifactLayer is a single file of an OCI artifact.
+type ArtifactLayer struct {
+	// Path of the file relative to the pushed directory; it becomes the layer title.
+	Path string
+	// MediaType identifies the layer content, e.g. "application/vnd.loko.site.v1.tar+gzip".
+	MediaType string
+}
+
+// ProvenanceSigner signs provenance statements for built documentation.
+//
+// Implementations hold a private key (e.g. an Ed25519 PEM file) and return a
+// stable key ID so verifiers can select the matching public key.
+type ProvenanceSigner interface {
+	// Sign returns the key ID and the signature of data.
+	Sign(ctx context.Context, data []byte) (keyID string, sig []byte, err error)
+}
+
+// ProvenanceVerifier checks signatures made by a ProvenanceSigner.
+type ProvenanceVerifier interface {
+	// Verify returns an error unless sig is a valid signature of data.
+	Verify(ctx context.Context, data, sig []byte) error
+}
+
+// PluginStore installs and discovers plugins.
+//
+// Implementations keep each plugin in its own directory under the XDG data
+// directory (e.g. ~/.local/share/loko/plugins/<name>/plugin.json).
+type PluginStore interface {
+	// List returns the installed plugins sorted by name.
+	List(ctx context.Context) ([]*entities.PluginManifest, error)
+	// Install copies the plugin at source (a plugin directory or executable)
+	// into the store, replacing an installed plugin of the same name.
+	Install(ctx context.Context, source string) (*entities.PluginManifest, error)
+}
+
+// IconResolver resolves icon references in templates.
+type IconResolver interface {
+	// ResolveIcon returns the data URI of the icon referenced by ref, either
+	// "pack/name" or a name found in a single pack.
+	ResolveIcon(ref string) (string, error)
+}
+
+// IconStore installs and discovers icon packs.
+//
+// Implementations keep each installed pack in its own directory of SVG files
+// under the XDG data directory (e.g. ~/.local/share/loko/icons/<pack>/) and
+// also offer the packs built into loko.
+type IconStore interface {
+	IconResolver
+
+	// List returns the installed and built-in packs sorted by name. An
+	// installed pack hides the built-in pack of the same name.
+	List(ctx context.Context) ([]*entities.IconPack, error)
+	// Install copies the SVG files of the directory source into the pack
+	// name, replacing an installed pack of that name. A source naming a
+	// built-in pack writes its icons, so they can be edited.
+	Install(ctx context.Context, source, name string) (*entities.IconPack, error)
+}
+
+// PluginHost calls into plugin processes.
+//
+// Implementations run the plugin executable as a subprocess speaking
+// JSON-RPC 2.0 over stdin/stdout, so plugins can be written in any language.
+type PluginHost interface {
+	// Call invokes method on plugin with params and decodes the result into result.
+	Call(ctx context.Context, plugin *entities.PluginManifest, method string, params, result any) error
+}
+
+// HookRunner runs the shell commands configured in [hooks].
+//
+// Implementations run each command through the platform shell and stream its
+// output to the user.
+type HookRunner interface {
+	// Run executes command in dir with env added to loko's environment.
+	// Returns an error if the command cannot start or exits non-zero.
+	Run(ctx context.Context, command, dir string, env []string) error
+}
+
+// EventPublisher delivers model events to live subscribers.
+//
+// Implementations MUST NOT block the caller: events for a subscriber that
+// cannot keep up may be dropped.
+type EventPublisher interface {
+	// Publish sends event to every current subscriber.
+	Publish(event entities.ModelEvent)
+}
+
+// FrontmatterEditor edits the frontmatter of element Markdown files in place.
+//
+// Implementations MUST keep the rest of the file, including the body and
+// other keys, unchanged.
+type FrontmatterEditor interface {
+	// SetFrontmatterField sets a top-level scalar key, adding it (and the
+	// frontmatter) when missing.
+	SetFrontmatterField(ctx context.Context, path, key, value string) error
+	// SetFrontmatterList replaces a top-level "- item" list, removing the key
+	// when items is empty.
+	SetFrontmatterList(ctx context.Context, path, key string, items []string) error
+	// SetFrontmatterMap replaces a top-level map of quoted values, removing
+	// the key when entries is empty.
+	SetFrontmatterMap(ctx context.Context, path, key string, entries map[string]string) error
+	// RemoveRelationship removes the entry for target from the relationships map.
+	RemoveRelationship(ctx context.Context, path, target string) error
+}
+
+// FrontmatterCodec reads the YAML frontmatter that opens element Markdown
+// files, between two "---" lines. Edits are written by the FrontmatterEditor,
+// which keeps the comments and styles of the lines it does not change.
+//
+// Implementations MUST decode values losslessly, including nested maps and
+// lists of maps.
+type FrontmatterCodec interface {
+	// Decode splits content into its frontmatter fields and the body after
+	// the closing "---". Content without frontmatter has no fields and is
+	// all body; malformed frontmatter is an error wrapping
+	// entities.ErrInvalidFrontmatter.
+	Decode(content []byte) (fields map[string]any, body []byte, err error)
+}
+
+// LinkProber checks the external links of a built site.
+//
+// Implementations MUST give up on a URL after a timeout so that an
+// unresponsive host cannot stall the build.
+type LinkProber interface {
+	// Probe returns an error when url cannot be fetched or answers with an
+	// error status.
+	Probe(ctx context.Context, url string) error
+}
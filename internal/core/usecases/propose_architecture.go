@@ -0,0 +1,347 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// ArchitectureProposal is a hierarchy of systems, containers and components
+// with the relationships between them, created together by
+// ProposeArchitecture.
+type ArchitectureProposal struct {
+	Systems       []ProposedSystem
+	Relationships []ProposedRelationship
+}
+
+// ProposedSystem is a system of a proposal. A system that already exists is
+// not rewritten; its proposed containers are added to it.
+type ProposedSystem struct {
+	Name        string
+	Description string
+	Tags        []string
+	Containers  []ProposedContainer
+}
+
+// ProposedContainer is a container of a proposed system. A container that
+// already exists is not rewritten; its proposed components are added to it.
+type ProposedContainer struct {
+	Name        string
+	Description string
+	Technology  string
+	Tags        []string
+	Components  []ProposedComponent
+}
+
+// ProposedComponent is a component of a proposed container.
+type ProposedComponent struct {
+	Name        string
+	Description string
+	Technology  string
+	Tags        []string
+}
+
+// ProposedRelationship relates two elements of the proposal or of the
+// project by path: "system/container" or "system/container/component", with
+// names or IDs.
+type ProposedRelationship struct {
+	Source     string
+	Target     string
+	Label      string
+	Type       string
+	Technology string
+}
+
+// ProposalResult reports what ProposeArchitecture created, by qualified ID.
+type ProposalResult struct {
+	Created       []string
+	Existing      []string // Systems and containers reused as they were
+	Relationships []*entities.Relationship
+}
+
+// ProposeArchitecture creates every element and relationship of a proposal,
+// or none of them. The whole proposal is checked before anything is written;
+// if a write then fails, the project sources are restored as they were, so
+// an agent never leaves a half-created hierarchy behind.
+type ProposeArchitecture struct {
+	repo     ProjectRepository
+	relRepo  RelationshipRepository
+	scaffold *ScaffoldEntity
+}
+
+// NewProposeArchitecture creates a new ProposeArchitecture use case. The
+// options configure the ScaffoldEntity that creates each element.
+func NewProposeArchitecture(repo ProjectRepository, relRepo RelationshipRepository, opts ...ScaffoldEntityOption) *ProposeArchitecture {
+	return &ProposeArchitecture{
+		repo:     repo,
+		relRepo:  relRepo,
+		scaffold: NewScaffoldEntity(repo, opts...),
+	}
+}
+
+// Execute creates proposal in the project at projectRoot.
+func (uc *ProposeArchitecture) Execute(ctx context.Context, projectRoot string, proposal *ArchitectureProposal) (*ProposalResult, error) {
+	if proposal == nil || len(proposal.Systems)+len(proposal.Relationships) == 0 {
+		return nil, fmt.Errorf("proposal is empty")
+	}
+	project, err := uc.repo.LoadProject(ctx, projectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load project: %w", err)
+	}
+	systems, err := uc.repo.ListSystems(ctx, projectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list systems: %w", err)
+	}
+
+	plan, err := planProposal(proposal, systems)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint, err := newSourceCheckpoint(projectRoot, project.SourceDirs()...)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := uc.apply(ctx, projectRoot, proposal, plan)
+	if err != nil {
+		if restoreErr := checkpoint.Restore(); restoreErr != nil {
+			return nil, fmt.Errorf("%w; rolling back also failed: %w", err, restoreErr)
+		}
+		return nil, fmt.Errorf("%w; no changes were made", err)
+	}
+	return result, nil
+}
+
+// proposalPlan is a proposal checked against the project.
+type proposalPlan struct {
+	existing      map[string]bool // Qualified IDs of the elements that already exist
+	relationships []CreateRelationshipRequest
+}
+
+// planProposal checks that every element of proposal has a valid name that
+// is unique in its parent, and that every relationship relates elements of
+// the proposal or of systems. All problems are reported together.
+func planProposal(proposal *ArchitectureProposal, systems []*entities.System) (*proposalPlan, error) {
+	plan := &proposalPlan{existing: make(map[string]bool)}
+	known := make(map[string]bool) // Qualified IDs of the existing and proposed elements
+	for _, system := range systems {
+		known[system.ID] = true
+		plan.existing[system.ID] = true
+		for _, container := range system.ListContainers() {
+			id := system.ID + "/" + container.ID
+			known[id], plan.existing[id] = true, true
+			for _, component := range container.ListComponents() {
+				known[id+"/"+component.ID] = true
+			}
+		}
+	}
+
+	var problems []string
+	proposed := make(map[string]bool)
+	add := func(kind, parent, name string) string {
+		id := entities.NormalizeName(name)
+		if err := entities.ValidateName(name); err != nil || id == "" {
+			problems = append(problems, fmt.Sprintf("%s %q: invalid name", kind, name))
+			return ""
+		}
+		if parent != "" {
+			id = parent + "/" + id
+		}
+		if proposed[id] {
+			problems = append(problems, fmt.Sprintf("%s %s is proposed twice", kind, id))
+			return ""
+		}
+		proposed[id] = true
+		if kind == "component" && known[id] {
+			problems = append(problems, fmt.Sprintf("component %s already exists", id))
+		}
+		known[id] = true
+		return id
+	}
+	for _, system := range proposal.Systems {
+		systemID := add("system", "", system.Name)
+		if systemID == "" {
+			continue
+		}
+		for _, container := range system.Containers {
+			containerID := add("container", systemID, container.Name)
+			if containerID == "" {
+				continue
+			}
+			for _, component := range container.Components {
+				add("component", containerID, component.Name)
+			}
+		}
+	}
+
+	for i, rel := range proposal.Relationships {
+		source, target := normalizeElementPath(rel.Source), normalizeElementPath(rel.Target)
+		for _, path := range []string{source, target} {
+			if !known[path] {
+				problems = append(problems, fmt.Sprintf("relationship %d: element %q is neither in the proposal nor in the project", i+1, path))
+			}
+		}
+		label := rel.Label
+		if label == "" {
+			label = "Uses"
+		}
+		req := CreateRelationshipRequest{
+			SystemID:   strings.SplitN(source, "/", 2)[0],
+			Source:     source,
+			Target:     target,
+			Label:      label,
+			Type:       rel.Type,
+			Technology: rel.Technology,
+		}
+		var opts []entities.RelationshipOption
+		if req.Type != "" {
+			opts = append(opts, entities.WithRelType(req.Type))
+		}
+		if _, err := entities.NewRelationship(req.Source, req.Target, req.Label, opts...); err != nil {
+			problems = append(problems, fmt.Sprintf("relationship %d: %v", i+1, err))
+		}
+		plan.relationships = append(plan.relationships, req)
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", entities.ErrInvalidProposal, strings.Join(problems, "; "))
+	}
+	return plan, nil
+}
+
+// apply creates the elements and relationships of a checked proposal.
+func (uc *ProposeArchitecture) apply(ctx context.Context, projectRoot string, proposal *ArchitectureProposal, plan *proposalPlan) (*ProposalResult, error) {
+	result := &ProposalResult{}
+	scaffold := func(req *ScaffoldEntityRequest, id string) error {
+		if plan.existing[id] {
+			result.Existing = append(result.Existing, id)
+			return nil
+		}
+		req.ProjectRoot = projectRoot
+		if _, err := uc.scaffold.Execute(ctx, req); err != nil {
+			return fmt.Errorf("failed to create %s %s: %w", req.EntityType, id, err)
+		}
+		result.Created = append(result.Created, id)
+		return nil
+	}
+
+	for _, system := range proposal.Systems {
+		systemID := entities.NormalizeName(system.Name)
+		if err := scaffold(&ScaffoldEntityRequest{
+			EntityType: "system", Name: system.Name, Description: system.Description, Tags: system.Tags,
+		}, systemID); err != nil {
+			return nil, err
+		}
+		for _, container := range system.Containers {
+			containerID := systemID + "/" + entities.NormalizeName(container.Name)
+			if err := scaffold(&ScaffoldEntityRequest{
+				EntityType: "container", ParentPath: []string{systemID}, Name: container.Name,
+				Description: container.Description, Technology: container.Technology, Tags: container.Tags,
+			}, containerID); err != nil {
+				return nil, err
+			}
+			for _, component := range container.Components {
+				if err := scaffold(&ScaffoldEntityRequest{
+					EntityType: "component", ParentPath: strings.Split(containerID, "/"), Name: component.Name,
+					Description: component.Description, Technology: component.Technology, Tags: component.Tags,
+				}, containerID+"/"+entities.NormalizeName(component.Name)); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	create := NewCreateRelationship(uc.relRepo)
+	for _, req := range plan.relationships {
+		req.ProjectRoot = projectRoot
+		rel, err := create.Execute(ctx, &req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create relationship %s -> %s: %w", req.Source, req.Target, err)
+		}
+		result.Relationships = append(result.Relationships, rel)
+	}
+	return result, nil
+}
+
+// normalizeElementPath normalizes each segment of a slash-separated element
+// path, so elements can be named as well as identified.
+func normalizeElementPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = entities.NormalizeName(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// sourceCheckpoint records the project sources, the files that
+// snapshotSources reads, so a change spanning several files can be undone.
+type sourceCheckpoint struct {
+	projectRoot string
+	sourceDirs  []string
+	files       map[string]string
+}
+
+// newSourceCheckpoint records the current sources of the project under
+// sourceDirs.
+func newSourceCheckpoint(projectRoot string, sourceDirs ...string) (*sourceCheckpoint, error) {
+	files, err := snapshotSources(projectRoot, sourceDirs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project sources: %w", err)
+	}
+	return &sourceCheckpoint{projectRoot: projectRoot, sourceDirs: sourceDirs, files: files}, nil
+}
+
+// Restore puts the sources back as they were recorded: files added since are
+// removed, with the directories left empty, and files changed or removed are
+// rewritten.
+func (c *sourceCheckpoint) Restore() error {
+	current, err := snapshotSources(c.projectRoot, c.sourceDirs...)
+	if err != nil {
+		return fmt.Errorf("failed to read project sources: %w", err)
+	}
+	var errs []error
+	for _, change := range DiffSnapshots(c.files, current) {
+		path := filepath.Join(c.projectRoot, filepath.FromSlash(change.Path))
+		if change.Op != entities.FileAdded {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			errs = append(errs, os.WriteFile(path, []byte(c.files[change.Path]), 0644))
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+		c.removeEmptyDirs(filepath.Dir(path))
+	}
+	return errors.Join(errs...)
+}
+
+// removeEmptyDirs removes dir and its parents while they are empty, up to
+// the source directory holding them. Symlinked directories are kept.
+func (c *sourceCheckpoint) removeEmptyDirs(dir string) {
+	var root string
+	for _, sourceDir := range c.sourceDirs {
+		if !filepath.IsAbs(sourceDir) {
+			sourceDir = filepath.Join(c.projectRoot, sourceDir)
+		}
+		if strings.HasPrefix(dir, sourceDir+string(filepath.Separator)) {
+			root = sourceDir
+			break
+		}
+	}
+	for ; root != "" && dir != root; dir = filepath.Dir(dir) {
+		if info, err := os.Lstat(dir); err != nil || !info.IsDir() || os.Remove(dir) != nil {
+			break // Not empty, or a symlink
+		}
+	}
+}
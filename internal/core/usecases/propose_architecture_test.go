@@ -0,0 +1,262 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// proposalRepository keeps systems in memory and writes an element file for
+// each one saved under <root>/src, failing to save the component named
+// failComponent.
+type proposalRepository struct {
+	MockProjectRepository
+	root          string
+	systems       map[string]*entities.System
+	failComponent string
+}
+
+func newProposalRepository(t *testing.T) *proposalRepository {
+	t.Helper()
+	repo := &proposalRepository{root: t.TempDir(), systems: make(map[string]*entities.System)}
+	repo.LoadProjectFunc = func(context.Context, string) (*entities.Project, error) {
+		return entities.NewProject("proposal")
+	}
+	repo.ListSystemsFunc = func(context.Context, string) ([]*entities.System, error) {
+		var systems []*entities.System
+		for _, system := range repo.systems {
+			systems = append(systems, system)
+		}
+		return systems, nil
+	}
+	repo.LoadSystemFunc = func(_ context.Context, _, id string) (*entities.System, error) {
+		if system := repo.systems[id]; system != nil {
+			return system, nil
+		}
+		return nil, entities.ErrSystemNotFound
+	}
+	return repo
+}
+
+func (r *proposalRepository) write(rel string) error {
+	path := filepath.Join(r.root, "src", rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(rel), 0644)
+}
+
+func (r *proposalRepository) SaveSystem(_ context.Context, _ string, system *entities.System) error {
+	r.systems[system.ID] = system
+	return r.write(system.ID + "/system.md")
+}
+
+func (r *proposalRepository) SaveContainer(_ context.Context, _, systemID string, container *entities.Container) error {
+	return r.write(systemID + "/" + container.ID + "/container.md")
+}
+
+func (r *proposalRepository) SaveComponent(_ context.Context, _, systemID, containerID string, component *entities.Component) error {
+	if component.Name == r.failComponent {
+		return errors.New("disk full")
+	}
+	return r.write(systemID + "/" + containerID + "/" + component.ID + "/component.md")
+}
+
+// sourceFiles returns the files under <root>/src, relative to it.
+func (r *proposalRepository) sourceFiles(t *testing.T) []string {
+	t.Helper()
+	var files []string
+	_ = filepath.WalkDir(filepath.Join(r.root, "src"), func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(filepath.Join(r.root, "src"), path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return files
+}
+
+// shopProposal proposes a web container with two components in the existing
+// shop system, and a new payments system the web container calls.
+func shopProposal() *ArchitectureProposal {
+	return &ArchitectureProposal{
+		Systems: []ProposedSystem{
+			{Name: "Shop", Containers: []ProposedContainer{{
+				Name: "Web", Technology: "Go",
+				Components: []ProposedComponent{{Name: "Cart"}, {Name: "Checkout"}},
+			}}},
+			{Name: "Payments", Description: "Takes payments"},
+		},
+		Relationships: []ProposedRelationship{
+			{Source: "Shop/Web/Checkout", Target: "payments", Label: "Charges cards", Technology: "HTTPS"},
+			{Source: "shop/web", Target: "shop/db"},
+		},
+	}
+}
+
+func seedShop(t *testing.T, repo *proposalRepository) {
+	t.Helper()
+	shop, _ := entities.NewSystem("Shop")
+	db, _ := entities.NewContainer("DB")
+	_ = shop.AddContainer(db)
+	if err := repo.SaveSystem(context.Background(), repo.root, shop); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProposeArchitecture(t *testing.T) {
+	repo := newProposalRepository(t)
+	seedShop(t, repo)
+	rels := newMockRelationshipRepository()
+
+	result, err := NewProposeArchitecture(repo, rels).Execute(context.Background(), repo.root, shopProposal())
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	wantCreated := []string{"shop/web", "shop/web/cart", "shop/web/checkout", "payments"}
+	if !slices.Equal(result.Created, wantCreated) {
+		t.Errorf("created = %v, want %v", result.Created, wantCreated)
+	}
+	if !slices.Equal(result.Existing, []string{"shop"}) {
+		t.Errorf("existing = %v, want [shop]", result.Existing)
+	}
+	if len(result.Relationships) != 2 || result.Relationships[1].Label != "Uses" {
+		t.Fatalf("relationships = %+v", result.Relationships)
+	}
+	if stored := rels.stored(repo.root, "shop"); len(stored) != 2 || stored[0].Source != "shop/web/checkout" {
+		t.Errorf("stored relationships = %+v", stored)
+	}
+}
+
+func TestProposeArchitectureInvalid(t *testing.T) {
+	repo := newProposalRepository(t)
+	seedShop(t, repo)
+	proposal := shopProposal()
+	proposal.Systems = append(proposal.Systems, ProposedSystem{Name: "Payments"}, ProposedSystem{Name: "   "})
+	proposal.Relationships = append(proposal.Relationships, ProposedRelationship{Source: "shop/web", Target: "ledger"})
+
+	_, err := NewProposeArchitecture(repo, newMockRelationshipRepository()).Execute(context.Background(), repo.root, proposal)
+	if !errors.Is(err, entities.ErrInvalidProposal) {
+		t.Fatalf("expected ErrInvalidProposal, got %v", err)
+	}
+	for _, want := range []string{"system payments is proposed twice", `system "   "`, `element "ledger"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not report %q", err, want)
+		}
+	}
+	if files := repo.sourceFiles(t); !slices.Equal(files, []string{"shop/system.md"}) {
+		t.Errorf("an invalid proposal wrote %v", files)
+	}
+
+	if _, err := NewProposeArchitecture(repo, nil).Execute(context.Background(), repo.root, &ArchitectureProposal{}); err == nil {
+		t.Error("expected error for an empty proposal")
+	}
+}
+
+func TestProposeArchitectureRollsBack(t *testing.T) {
+	repo := newProposalRepository(t)
+	seedShop(t, repo)
+	repo.failComponent = "Checkout"
+	rels := newMockRelationshipRepository()
+
+	_, err := NewProposeArchitecture(repo, rels).Execute(context.Background(), repo.root, shopProposal())
+	if err == nil || !strings.Contains(err.Error(), "disk full") || !strings.Contains(err.Error(), "no changes were made") {
+		t.Fatalf("expected the save error and a rollback, got %v", err)
+	}
+	if files := repo.sourceFiles(t); !slices.Equal(files, []string{"shop/system.md"}) {
+		t.Errorf("rollback left %v", files)
+	}
+	if _, err := os.Stat(filepath.Join(repo.root, "src", "shop", "web")); !os.IsNotExist(err) {
+		t.Errorf("rollback left the web container directory: %v", err)
+	}
+	if len(rels.SaveCalls) != 0 {
+		t.Errorf("relationships were saved before the failure: %+v", rels.SaveCalls)
+	}
+}
+
+func TestSourceCheckpointRestore(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(root, "src", rel)
+		_ = os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("shop/system.md", "original")
+	write("shop/system.d2", "a -> b")
+
+	checkpoint, err := newSourceCheckpoint(root, "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	write("shop/system.md", "changed")
+	_ = os.Remove(filepath.Join(root, "src", "shop", "system.d2"))
+	write("shop/web/api/container.md", "added")
+
+	if err := checkpoint.Restore(); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	for rel, want := range map[string]string{"shop/system.md": "original", "shop/system.d2": "a -> b"} {
+		if content, _ := os.ReadFile(filepath.Join(root, "src", rel)); string(content) != want {
+			t.Errorf("%s = %q, want %q", rel, content, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "src", "shop", "web")); !os.IsNotExist(err) {
+		t.Errorf("directories of added files should be removed: %v", err)
+	}
+}
+
+func TestSourceCheckpointRestore_SourceDirs(t *testing.T) {
+	root := t.TempDir()
+	shared := t.TempDir()
+	write := func(path, content string) {
+		_ = os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// shop is symlinked into src; billing is in a second source directory
+	write(filepath.Join(shared, "shop", "system.md"), "shop")
+	write(filepath.Join(root, "services", "billing", "system.md"), "billing")
+	_ = os.MkdirAll(filepath.Join(root, "src"), 0755)
+	if err := os.Symlink(filepath.Join(shared, "shop"), filepath.Join(root, "src", "shop")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	checkpoint, err := newSourceCheckpoint(root, "src", "services")
+	if err != nil {
+		t.Fatal(err)
+	}
+	write(filepath.Join(root, "src", "shop", "system.md"), "changed")
+	write(filepath.Join(root, "src", "shop", "api", "container.md"), "added")
+	write(filepath.Join(root, "services", "billing", "system.md"), "changed")
+	write(filepath.Join(root, "services", "billing", "core", "container.md"), "added")
+
+	if err := checkpoint.Restore(); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	for path, want := range map[string]string{
+		filepath.Join(shared, "shop", "system.md"):              "shop",
+		filepath.Join(root, "services", "billing", "system.md"): "billing",
+	} {
+		if content, _ := os.ReadFile(path); string(content) != want {
+			t.Errorf("%s = %q, want %q", path, content, want)
+		}
+	}
+	for _, dir := range []string{filepath.Join(shared, "shop", "api"), filepath.Join(root, "services", "billing", "core")} {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("directories of added files should be removed: %v", err)
+		}
+	}
+	if info, err := os.Lstat(filepath.Join(root, "src", "shop")); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("symlinked system should be kept: %v", err)
+	}
+}
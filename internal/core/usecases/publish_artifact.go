@@ -0,0 +1,241 @@
+package usecases
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// OCI media types of the documentation artifacts pushed by PublishArtifact.
+const (
+	DocsArtifactType    = "application/vnd.loko.docs.v1"
+	SiteLayerMediaType  = "application/vnd.loko.site.v1.tar+gzip"
+	ModelLayerMediaType = "application/vnd.loko.model.v1+json"
+)
+
+// File names of the artifact layers. The model snapshot keeps its name when
+// pulled so it can be told apart from the site's own files.
+const (
+	SiteArchiveName   = "site.tar.gz"
+	ModelSnapshotName = "loko-model.json"
+)
+
+// ParseArtifactRef returns the registry reference of an "oci://" URL such as
+// oci://ghcr.io/acme/arch-docs:v1. A reference without a scheme is returned
+// as is.
+func ParseArtifactRef(url string) (string, error) {
+	ref := strings.TrimPrefix(url, "oci://")
+	if strings.Contains(ref, "://") {
+		return "", fmt.Errorf("unsupported artifact URL %q (expected oci://registry/repository:tag)", url)
+	}
+	if ref == "" || !strings.Contains(ref, "/") {
+		return "", fmt.Errorf("invalid artifact reference %q (expected oci://registry/repository:tag)", url)
+	}
+	return ref, nil
+}
+
+// PublishArtifact packages a built documentation site, and optionally a JSON
+// snapshot of the model, as an OCI artifact so documentation can be deployed
+// through an existing container registry.
+type PublishArtifact struct {
+	registry ArtifactRegistry
+}
+
+// NewPublishArtifact creates a new PublishArtifact use case.
+func NewPublishArtifact(registry ArtifactRegistry) *PublishArtifact {
+	return &PublishArtifact{registry: registry}
+}
+
+// Execute pushes siteDir as a gzipped tarball layer, plus model as a second
+// layer when it is not empty, to ref and returns the manifest digest.
+func (uc *PublishArtifact) Execute(ctx context.Context, ref, siteDir string, model []byte) (string, error) {
+	if uc.registry == nil {
+		return "", fmt.Errorf("artifact registry cannot be nil")
+	}
+
+	staging, err := os.MkdirTemp("", "loko-push-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	if err := writeSiteArchive(filepath.Join(staging, SiteArchiveName), siteDir); err != nil {
+		return "", err
+	}
+	layers := []ArtifactLayer{{Path: SiteArchiveName, MediaType: SiteLayerMediaType}}
+
+	if len(model) > 0 {
+		if err := os.WriteFile(filepath.Join(staging, ModelSnapshotName), model, 0644); err != nil {
+			return "", fmt.Errorf("failed to write model snapshot: %w", err)
+		}
+		layers = append(layers, ArtifactLayer{Path: ModelSnapshotName, MediaType: ModelLayerMediaType})
+	}
+
+	digest, err := uc.registry.Push(ctx, ref, DocsArtifactType, staging, layers)
+	if err != nil {
+		return "", fmt.Errorf("failed to push %s: %w", ref, err)
+	}
+	return digest, nil
+}
+
+// FetchArtifact retrieves documentation published with PublishArtifact.
+type FetchArtifact struct {
+	registry ArtifactRegistry
+}
+
+// NewFetchArtifact creates a new FetchArtifact use case.
+func NewFetchArtifact(registry ArtifactRegistry) *FetchArtifact {
+	return &FetchArtifact{registry: registry}
+}
+
+// Execute pulls ref and unpacks the site into destDir, next to the model
+// snapshot if the artifact has one. It returns the number of site files.
+func (uc *FetchArtifact) Execute(ctx context.Context, ref, destDir string) (int, error) {
+	if uc.registry == nil {
+		return 0, fmt.Errorf("artifact registry cannot be nil")
+	}
+
+	staging, err := os.MkdirTemp("", "loko-pull-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	if err := uc.registry.Pull(ctx, ref, staging); err != nil {
+		return 0, fmt.Errorf("failed to pull %s: %w", ref, err)
+	}
+
+	archive, err := os.Open(filepath.Join(staging, SiteArchiveName))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, fmt.Errorf("%s is not a loko documentation artifact (no %s layer)", ref, SiteArchiveName)
+		}
+		return 0, fmt.Errorf("failed to open site archive: %w", err)
+	}
+	defer archive.Close()
+
+	count, err := extractSiteArchive(archive, destDir)
+	if err != nil {
+		return count, err
+	}
+
+	model, err := os.ReadFile(filepath.Join(staging, ModelSnapshotName))
+	switch {
+	case err == nil:
+		if err := os.WriteFile(filepath.Join(destDir, ModelSnapshotName), model, 0644); err != nil {
+			return count, fmt.Errorf("failed to write model snapshot: %w", err)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return count, fmt.Errorf("failed to read model snapshot: %w", err)
+	}
+	return count, nil
+}
+
+// writeSiteArchive writes the regular files under siteDir to a gzipped
+// tarball at path, with slash-separated names relative to siteDir.
+func writeSiteArchive(path, siteDir string) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create site archive: %w", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close site archive: %w", closeErr)
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	err = filepath.WalkDir(siteDir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(siteDir, file)
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{
+			Name:    filepath.ToSlash(rel),
+			Mode:    int64(info.Mode().Perm()),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		src, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %w", siteDir, err)
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to archive %s: %w", siteDir, err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to archive %s: %w", siteDir, err)
+	}
+	return nil
+}
+
+// extractSiteArchive unpacks the regular files of a gzipped tarball into
+// destDir and returns how many it wrote. Entries that would escape destDir
+// are rejected.
+func extractSiteArchive(r io.Reader, destDir string) (int, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read site archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	count := 0
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, fmt.Errorf("failed to read site archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := filepath.FromSlash(hdr.Name)
+		if !filepath.IsLocal(name) {
+			return count, fmt.Errorf("site archive entry %q escapes the output directory", hdr.Name)
+		}
+		target := filepath.Join(destDir, name)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return count, fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return count, fmt.Errorf("failed to read %s from site archive: %w", hdr.Name, err)
+		}
+		if err := os.WriteFile(target, data, hdr.FileInfo().Mode().Perm()|0600); err != nil {
+			return count, fmt.Errorf("failed to write %s: %w", target, err)
+		}
+		count++
+	}
+}
@@ -0,0 +1,127 @@
+package usecases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// memoryRegistry keeps pushed layers in memory, keyed by reference.
+type memoryRegistry struct {
+	artifacts map[string]map[string][]byte
+	types     map[string]string // artifact type by reference
+	media     map[string]string // media type by layer path
+}
+
+func newMemoryRegistry() *memoryRegistry {
+	return &memoryRegistry{
+		artifacts: make(map[string]map[string][]byte),
+		types:     make(map[string]string),
+		media:     make(map[string]string),
+	}
+}
+
+func (r *memoryRegistry) Push(_ context.Context, ref, artifactType, dir string, layers []ArtifactLayer) (string, error) {
+	files := make(map[string][]byte)
+	for _, layer := range layers {
+		data, err := os.ReadFile(filepath.Join(dir, layer.Path))
+		if err != nil {
+			return "", err
+		}
+		files[layer.Path] = data
+		r.media[layer.Path] = layer.MediaType
+	}
+	r.artifacts[ref] = files
+	r.types[ref] = artifactType
+	return "sha256:test", nil
+}
+
+func (r *memoryRegistry) Pull(_ context.Context, ref, dir string) error {
+	for path, data := range r.artifacts[ref] {
+		if err := os.WriteFile(filepath.Join(dir, path), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestPublishAndFetchArtifact(t *testing.T) {
+	siteDir := t.TempDir()
+	files := map[string]string{
+		"index.html":               "<h1>Architecture</h1>",
+		"systems/payments.html":    "<h1>Payments</h1>",
+		"diagrams/payments/c1.svg": "<svg/>",
+	}
+	for path, content := range files {
+		full := filepath.Join(siteDir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registry := newMemoryRegistry()
+	ctx := context.Background()
+	digest, err := NewPublishArtifact(registry).Execute(ctx, "ghcr.io/acme/docs:v1", siteDir, []byte(`{"name":"acme"}`))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if digest != "sha256:test" {
+		t.Errorf("digest = %q", digest)
+	}
+	if registry.types["ghcr.io/acme/docs:v1"] != DocsArtifactType {
+		t.Errorf("artifact type = %q", registry.types["ghcr.io/acme/docs:v1"])
+	}
+	if registry.media[SiteArchiveName] != SiteLayerMediaType || registry.media[ModelSnapshotName] != ModelLayerMediaType {
+		t.Errorf("layer media types = %v", registry.media)
+	}
+
+	destDir := filepath.Join(t.TempDir(), "site")
+	count, err := NewFetchArtifact(registry).Execute(ctx, "ghcr.io/acme/docs:v1", destDir)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if count != len(files) {
+		t.Errorf("extracted %d files, want %d", count, len(files))
+	}
+	for path, content := range files {
+		got, err := os.ReadFile(filepath.Join(destDir, path))
+		if err != nil || string(got) != content {
+			t.Errorf("%s = %q, %v; want %q", path, got, err, content)
+		}
+	}
+	if got, _ := os.ReadFile(filepath.Join(destDir, ModelSnapshotName)); string(got) != `{"name":"acme"}` {
+		t.Errorf("model snapshot = %q", got)
+	}
+}
+
+func TestFetchArtifactNotDocs(t *testing.T) {
+	registry := newMemoryRegistry()
+	registry.artifacts["ghcr.io/acme/app:v1"] = map[string][]byte{"binary": []byte("x")}
+	if _, err := NewFetchArtifact(registry).Execute(context.Background(), "ghcr.io/acme/app:v1", t.TempDir()); err == nil {
+		t.Error("expected error for an artifact without a site layer")
+	}
+}
+
+func TestParseArtifactRef(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{"oci://ghcr.io/acme/arch-docs:v1", "ghcr.io/acme/arch-docs:v1", false},
+		{"localhost:5000/docs:latest", "localhost:5000/docs:latest", false},
+		{"https://ghcr.io/acme/docs", "", true},
+		{"oci://", "", true},
+		{"oci://docs", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseArtifactRef(tt.url)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseArtifactRef(%q) = %q, %v", tt.url, got, err)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
 )
@@ -193,21 +194,21 @@ func buildRawData(project *entities.Project, systems []*entities.System, detail
 			for _, cont := range sys.ListContainers() {
 				components := make([]map[string]any, 0)
 				for _, comp := range cont.ListComponents() {
-					components = append(components, map[string]any{
+					components = append(components, withContentHash(withRecency(map[string]any{
 						"id":          comp.ID,
 						"name":        comp.Name,
 						"description": comp.Description,
 						"technology":  comp.Technology,
-					})
+					}, comp.UpdatedAt, comp.Author), comp.ContentHash))
 				}
 
-				containers = append(containers, map[string]any{
+				containers = append(containers, withContentHash(withRecency(map[string]any{
 					"id":          cont.ID,
 					"name":        cont.Name,
 					"description": cont.Description,
 					"technology":  cont.Technology,
 					"components":  components,
-				})
+				}, cont.UpdatedAt, cont.Author), cont.ContentHash))
 				totalContainers++
 				totalComponents += len(components)
 			}
@@ -224,7 +225,7 @@ func buildRawData(project *entities.Project, systems []*entities.System, detail
 			if len(sys.Tags) > 0 {
 				sysData["tags"] = sys.Tags
 			}
-			systemList = append(systemList, sysData)
+			systemList = append(systemList, withContentHash(withRecency(sysData, sys.UpdatedAt, sys.Author), sys.ContentHash))
 		}
 		data["systems"] = systemList
 		data["total_containers"] = totalContainers
@@ -234,6 +235,35 @@ func buildRawData(project *entities.Project, systems []*entities.System, detail
 	return data
 }
 
+// withRecency adds the date an element was last updated, and by whom, to its
+// data when known.
+func withRecency(data map[string]any, updated time.Time, author string) map[string]any {
+	if !updated.IsZero() {
+		data["updated_at"] = updated.Format(time.DateOnly)
+	}
+	if author != "" {
+		data["author"] = author
+	}
+	return data
+}
+
+// withContentHash adds the content hash of an element to its data when known,
+// so that agents can pass it back as the expected hash of an update.
+func withContentHash(data map[string]any, hash string) map[string]any {
+	if hash != "" {
+		data["content_hash"] = hash
+	}
+	return data
+}
+
+// toonRecency formats the last update of an element as a "~date" suffix.
+func toonRecency(data map[string]any) string {
+	if updated, _ := data["updated_at"].(string); updated != "" {
+		return "~" + updated
+	}
+	return ""
+}
+
 // formatAsJSON formats data as indented JSON.
 func formatAsJSON(data any) string {
 	bytes, err := json.MarshalIndent(data, "", "  ")
@@ -305,7 +335,7 @@ func formatAsTOON(data any, detail string) string {
 		if systems, ok := dataMap["systems"].([]map[string]any); ok {
 			for _, sys := range systems {
 				sysName, _ := sys["name"].(string)
-				sb.WriteString(fmt.Sprintf("S:%s\n", sysName))
+				sb.WriteString(fmt.Sprintf("S:%s%s\n", sysName, toonRecency(sys)))
 
 				if containers, ok := sys["containers"].([]map[string]any); ok {
 					for _, cont := range containers {
@@ -315,12 +345,12 @@ func formatAsTOON(data any, detail string) string {
 						if tech != "" {
 							sb.WriteString(fmt.Sprintf("[%s]", tech))
 						}
-						sb.WriteString("\n")
+						sb.WriteString(toonRecency(cont) + "\n")
 
 						if components, ok := cont["components"].([]map[string]any); ok {
 							for _, comp := range components {
 								compName, _ := comp["name"].(string)
-								sb.WriteString(fmt.Sprintf("    K:%s\n", compName))
+								sb.WriteString(fmt.Sprintf("    K:%s%s\n", compName, toonRecency(comp)))
 							}
 						}
 					}
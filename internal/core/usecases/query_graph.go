@@ -0,0 +1,221 @@
+package usecases
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// GraphNodeRef is an element of a graph query result. Depth is the number of
+// relationships between it and the queried element.
+type GraphNodeRef struct {
+	ID    string `json:"id"              toon:"id"`
+	Name  string `json:"name"            toon:"name"`
+	Type  string `json:"type"            toon:"type"`
+	Depth int    `json:"depth,omitempty" toon:"depth,omitempty"`
+	Owner string `json:"owner,omitempty" toon:"owner,omitempty"`
+}
+
+// GraphPathStep is an element on a path and the description of the
+// relationship leading to the next element, empty on the last one.
+type GraphPathStep struct {
+	ID          string `json:"id"                    toon:"id"`
+	Name        string `json:"name"                  toon:"name"`
+	Type        string `json:"type"                  toon:"type"`
+	Description string `json:"description,omitempty" toon:"description,omitempty"`
+}
+
+// ImpactReport is the blast radius of a change to an element: every element
+// depending on it, or on one of its children, directly or transitively.
+type ImpactReport struct {
+	Entity   string         `json:"entity"   toon:"entity"`
+	Impacted []GraphNodeRef `json:"impacted" toon:"impacted"` // Nearest first, then by ID
+	Systems  []string       `json:"systems"  toon:"systems"`  // Systems of the impacted elements
+	Owners   []string       `json:"owners"   toon:"owners"`   // Owners of the impacted elements
+}
+
+// QueryGraph answers questions about the relationships of an architecture
+// graph: what an element depends on, what depends on it, how two elements
+// are connected and what a change to an element impacts. Elements are given
+// by qualified ID, such as "shop/api/cart", or by unambiguous short ID.
+type QueryGraph struct {
+	graph *entities.ArchitectureGraph
+}
+
+// NewQueryGraph creates a QueryGraph use case over graph.
+func NewQueryGraph(graph *entities.ArchitectureGraph) *QueryGraph {
+	return &QueryGraph{graph: graph}
+}
+
+// Resolve returns the qualified ID of the element id refers to.
+func (uc *QueryGraph) Resolve(id string) (string, error) {
+	if uc.graph == nil {
+		return "", fmt.Errorf("element %q not found: the graph is empty", id)
+	}
+	if qualified, ok := resolveNode(uc.graph, id); ok {
+		return qualified, nil
+	}
+	if candidates := uc.graph.ShortIDMap[id]; len(candidates) > 1 {
+		return "", fmt.Errorf("element %q is ambiguous, use one of: %s", id, strings.Join(slices.Sorted(slices.Values(candidates)), ", "))
+	}
+	return "", fmt.Errorf("element %q not found", id)
+}
+
+// Dependencies returns the elements id depends on: directly, or also
+// transitively when transitive is set.
+func (uc *QueryGraph) Dependencies(id string, transitive bool) ([]GraphNodeRef, error) {
+	return uc.walk(id, transitive, func(edge *entities.GraphEdge) string { return edge.Target }, uc.graph.GetOutgoingEdges)
+}
+
+// Dependents returns the elements depending on id: directly, or also
+// transitively when transitive is set.
+func (uc *QueryGraph) Dependents(id string, transitive bool) ([]GraphNodeRef, error) {
+	return uc.walk(id, transitive, func(edge *entities.GraphEdge) string { return edge.Source }, uc.graph.GetIncomingEdges)
+}
+
+// walk lists the elements reached from id along edges, one level deep unless
+// transitive is set.
+func (uc *QueryGraph) walk(id string, transitive bool, next func(*entities.GraphEdge) string, edges func(string) []*entities.GraphEdge) ([]GraphNodeRef, error) {
+	start, err := uc.Resolve(id)
+	if err != nil {
+		return nil, err
+	}
+	maxDepth := 1
+	if transitive {
+		maxDepth = -1
+	}
+	return uc.reach([]string{start}, maxDepth, next, edges), nil
+}
+
+// reach returns the elements reached from the start elements along edges
+// within maxDepth steps, or any number of steps when maxDepth is negative.
+// The start elements are left out.
+func (uc *QueryGraph) reach(start []string, maxDepth int, next func(*entities.GraphEdge) string, edges func(string) []*entities.GraphEdge) []GraphNodeRef {
+	depths := make(map[string]int, len(start))
+	for _, id := range start {
+		depths[id] = 0
+	}
+	queue := slices.Clone(start)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if maxDepth >= 0 && depths[current] >= maxDepth {
+			continue
+		}
+		for _, edge := range edges(current) {
+			neighbor := next(edge)
+			if _, seen := depths[neighbor]; seen || uc.graph.GetNode(neighbor) == nil {
+				continue
+			}
+			depths[neighbor] = depths[current] + 1
+			queue = append(queue, neighbor)
+		}
+	}
+
+	refs := []GraphNodeRef{}
+	for id, depth := range depths {
+		if depth == 0 {
+			continue
+		}
+		node := uc.graph.GetNode(id)
+		refs = append(refs, GraphNodeRef{ID: id, Name: node.Name, Type: node.Type, Depth: depth, Owner: NodeOwner(uc.graph, id)})
+	}
+	slices.SortFunc(refs, func(a, b GraphNodeRef) int {
+		return cmp.Or(cmp.Compare(a.Depth, b.Depth), strings.Compare(a.ID, b.ID))
+	})
+	return refs
+}
+
+// FindPath returns a shortest chain of relationships from source to target,
+// or nil when target cannot be reached from source.
+func (uc *QueryGraph) FindPath(source, target string) ([]GraphPathStep, error) {
+	from, err := uc.Resolve(source)
+	if err != nil {
+		return nil, err
+	}
+	to, err := uc.Resolve(target)
+	if err != nil {
+		return nil, err
+	}
+	nodes := uc.graph.GetPath(from, to)
+	if nodes == nil {
+		return nil, nil
+	}
+	steps := make([]GraphPathStep, len(nodes))
+	for i, node := range nodes {
+		steps[i] = GraphPathStep{ID: node.ID, Name: node.Name, Type: node.Type}
+		if i+1 < len(nodes) {
+			steps[i].Description = edgeDescription(uc.graph, node.ID, nodes[i+1].ID)
+		}
+	}
+	return steps, nil
+}
+
+// Impact returns the elements a change to id impacts: those depending on it
+// or on one of its children, within maxDepth relationships, or at any
+// distance when maxDepth is not positive.
+func (uc *QueryGraph) Impact(id string, maxDepth int) (*ImpactReport, error) {
+	entity, err := uc.Resolve(id)
+	if err != nil {
+		return nil, err
+	}
+	if maxDepth <= 0 {
+		maxDepth = -1
+	}
+	start := []string{entity}
+	for _, descendant := range uc.graph.GetDescendants(entity) {
+		start = append(start, descendant.ID)
+	}
+
+	report := &ImpactReport{
+		Entity:   entity,
+		Impacted: uc.reach(start, maxDepth, func(edge *entities.GraphEdge) string { return edge.Source }, uc.graph.GetIncomingEdges),
+		Systems:  []string{},
+		Owners:   []string{},
+	}
+	for _, ref := range report.Impacted {
+		if system := owningSystemID(uc.graph, ref.ID); system != "" && !slices.Contains(report.Systems, system) {
+			report.Systems = append(report.Systems, system)
+		}
+		if ref.Owner != "" && !slices.Contains(report.Owners, ref.Owner) {
+			report.Owners = append(report.Owners, ref.Owner)
+		}
+	}
+	slices.Sort(report.Systems)
+	slices.Sort(report.Owners)
+	return report, nil
+}
+
+// edgeDescription returns the description of the relationship from source
+// to target.
+func edgeDescription(graph *entities.ArchitectureGraph, source, target string) string {
+	for _, edge := range graph.GetOutgoingEdges(source) {
+		if edge.Target == target && edge.Description != "" {
+			return edge.Description
+		}
+	}
+	return ""
+}
+
+// NodeOwner returns the owner of the element id: the owner its frontmatter
+// declares, or else that of its nearest ancestor declaring one.
+func NodeOwner(graph *entities.ArchitectureGraph, id string) string {
+	for node := graph.GetNode(id); node != nil; node = graph.GetParent(node.ID) {
+		var metadata map[string]any
+		switch entity := node.Data.(type) {
+		case *entities.System:
+			metadata = entity.Metadata
+		case *entities.Container:
+			metadata = entity.Metadata
+		case *entities.Component:
+			metadata = entity.Metadata
+		}
+		if owner := entities.OwnerOf(metadata); owner != "" {
+			return owner
+		}
+	}
+	return ""
+}
@@ -0,0 +1,146 @@
+package usecases
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// queryGraphFixture builds a graph where the web container depends on the
+// cart component, which depends on the ledger component of another system:
+//
+//	shop/web -> shop/api/cart -> billing/core/ledger
+func queryGraphFixture(t *testing.T) *entities.ArchitectureGraph {
+	t.Helper()
+	project, _ := entities.NewProject("query")
+
+	shop, _ := entities.NewSystem("Shop")
+	shop.Metadata = map[string]any{entities.OwnerField: "team-shop"}
+	web, _ := entities.NewContainer("Web")
+	web.Relationships = map[string]string{"shop/api/cart": "adds items to"}
+	api, _ := entities.NewContainer("API")
+	cart, _ := entities.NewComponent("Cart")
+	cart.AddRelationship("billing/core/ledger", "records payments in")
+	_ = api.AddComponent(cart)
+	_ = shop.AddContainer(web)
+	_ = shop.AddContainer(api)
+
+	billing, _ := entities.NewSystem("Billing")
+	core, _ := entities.NewContainer("Core")
+	core.Metadata = map[string]any{entities.OwnerField: "team-billing"}
+	ledger, _ := entities.NewComponent("Ledger")
+	_ = core.AddComponent(ledger)
+	_ = billing.AddContainer(core)
+
+	graph, err := NewBuildArchitectureGraph().Execute(context.Background(), project, []*entities.System{shop, billing})
+	if err != nil {
+		t.Fatalf("failed to build graph: %v", err)
+	}
+	return graph
+}
+
+func refIDs(refs []GraphNodeRef) []string {
+	ids := make([]string, len(refs))
+	for i, ref := range refs {
+		ids[i] = ref.ID
+	}
+	return ids
+}
+
+func TestQueryGraphDependencies(t *testing.T) {
+	query := NewQueryGraph(queryGraphFixture(t))
+
+	direct, err := query.Dependencies("shop/web", false)
+	if err != nil {
+		t.Fatalf("Dependencies failed: %v", err)
+	}
+	if got := refIDs(direct); !slices.Equal(got, []string{"shop/api/cart"}) {
+		t.Errorf("direct dependencies = %v, want [shop/api/cart]", got)
+	}
+
+	transitive, err := query.Dependencies("shop/web", true)
+	if err != nil {
+		t.Fatalf("Dependencies failed: %v", err)
+	}
+	if got := refIDs(transitive); !slices.Equal(got, []string{"shop/api/cart", "billing/core/ledger"}) {
+		t.Errorf("transitive dependencies = %v, want [shop/api/cart billing/core/ledger]", got)
+	}
+	if ledger := transitive[1]; ledger.Depth != 2 || ledger.Owner != "team-billing" {
+		t.Errorf("ledger = %+v, want depth 2 and owner team-billing", ledger)
+	}
+}
+
+func TestQueryGraphDependentsByShortID(t *testing.T) {
+	query := NewQueryGraph(queryGraphFixture(t))
+
+	dependents, err := query.Dependents("ledger", true)
+	if err != nil {
+		t.Fatalf("Dependents failed: %v", err)
+	}
+	if got := refIDs(dependents); !slices.Equal(got, []string{"shop/api/cart", "shop/web"}) {
+		t.Errorf("dependents = %v, want [shop/api/cart shop/web]", got)
+	}
+	if owner := dependents[0].Owner; owner != "team-shop" {
+		t.Errorf("cart owner = %q, want team-shop inherited from its system", owner)
+	}
+}
+
+func TestQueryGraphResolveErrors(t *testing.T) {
+	query := NewQueryGraph(queryGraphFixture(t))
+
+	if _, err := query.Resolve("nowhere"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Resolve(nowhere) error = %v, want not found", err)
+	}
+}
+
+func TestQueryGraphFindPath(t *testing.T) {
+	query := NewQueryGraph(queryGraphFixture(t))
+
+	path, err := query.FindPath("shop/web", "ledger")
+	if err != nil {
+		t.Fatalf("FindPath failed: %v", err)
+	}
+	want := []GraphPathStep{
+		{ID: "shop/web", Name: "Web", Type: "container", Description: "adds items to"},
+		{ID: "shop/api/cart", Name: "Cart", Type: "component", Description: "records payments in"},
+		{ID: "billing/core/ledger", Name: "Ledger", Type: "component"},
+	}
+	if !slices.Equal(path, want) {
+		t.Errorf("path = %+v, want %+v", path, want)
+	}
+
+	none, err := query.FindPath("ledger", "shop/web")
+	if err != nil {
+		t.Fatalf("FindPath failed: %v", err)
+	}
+	if none != nil {
+		t.Errorf("reverse path = %+v, want none", none)
+	}
+}
+
+func TestQueryGraphImpact(t *testing.T) {
+	query := NewQueryGraph(queryGraphFixture(t))
+
+	// Changing the billing system impacts what depends on its ledger.
+	report, err := query.Impact("billing", 0)
+	if err != nil {
+		t.Fatalf("Impact failed: %v", err)
+	}
+	if got := refIDs(report.Impacted); !slices.Equal(got, []string{"shop/api/cart", "shop/web"}) {
+		t.Errorf("impacted = %v, want [shop/api/cart shop/web]", got)
+	}
+	if !slices.Equal(report.Systems, []string{"shop"}) || !slices.Equal(report.Owners, []string{"team-shop"}) {
+		t.Errorf("systems = %v, owners = %v, want [shop], [team-shop]", report.Systems, report.Owners)
+	}
+
+	limited, err := query.Impact("billing/core/ledger", 1)
+	if err != nil {
+		t.Fatalf("Impact failed: %v", err)
+	}
+	if got := refIDs(limited.Impacted); !slices.Equal(got, []string{"shop/api/cart"}) {
+		t.Errorf("impacted within 1 = %v, want [shop/api/cart]", got)
+	}
+}
@@ -0,0 +1,69 @@
+package usecases
+
+import (
+	"path"
+	"strings"
+)
+
+// RebuildScope describes how much of the generated site must be regenerated
+// after a source change. Scopes are ordered: a larger scope includes the work
+// of every smaller one.
+type RebuildScope int
+
+const (
+	// RebuildNone means the change does not affect the generated output.
+	RebuildNone RebuildScope = iota
+	// RebuildPages re-renders pages only; rendered diagrams are reused.
+	RebuildPages
+	// RebuildContent re-renders diagrams and pages from changed .md/.d2 sources.
+	RebuildContent
+	// RebuildFull reloads the project configuration and rebuilds everything.
+	RebuildFull
+)
+
+// String returns a human-readable name for the scope.
+func (s RebuildScope) String() string {
+	switch s {
+	case RebuildPages:
+		return "pages"
+	case RebuildContent:
+		return "content"
+	case RebuildFull:
+		return "full"
+	default:
+		return "none"
+	}
+}
+
+// Merge returns the larger of two scopes.
+func (s RebuildScope) Merge(other RebuildScope) RebuildScope {
+	return max(s, other)
+}
+
+// ScopeForChange returns the rebuild scope triggered by a change to relPath,
+// a path relative to the project root using forward slashes.
+//
+//   - loko.toml and theme files (.loko/themes/) require a full rebuild
+//   - templates (.loko/templates/) require pages to be re-rendered
+//   - .md and .d2 sources require content to be rebuilt
+func ScopeForChange(relPath string) RebuildScope {
+	p := strings.ToLower(path.Clean(relPath))
+
+	switch {
+	case p == "loko.toml":
+		return RebuildFull
+	case strings.HasPrefix(p, ".loko/themes/"):
+		return RebuildFull
+	case strings.HasPrefix(p, ".loko/templates/"):
+		return RebuildPages
+	case strings.HasPrefix(p, ".loko/"):
+		return RebuildNone
+	}
+
+	switch path.Ext(p) {
+	case ".md", ".d2":
+		return RebuildContent
+	default:
+		return RebuildNone
+	}
+}
@@ -0,0 +1,36 @@
+package usecases
+
+import "testing"
+
+func TestScopeForChange(t *testing.T) {
+	tests := []struct {
+		path string
+		want RebuildScope
+	}{
+		{"loko.toml", RebuildFull},
+		{".loko/themes/dark.toml", RebuildFull},
+		{".loko/templates/system.md", RebuildPages},
+		{".loko/cache/state.json", RebuildNone},
+		{"src/backend/system.md", RebuildContent},
+		{"src/backend/api/api.d2", RebuildContent},
+		{"src/backend/notes.txt", RebuildNone},
+		{"README.MD", RebuildContent},
+	}
+	for _, tt := range tests {
+		if got := ScopeForChange(tt.path); got != tt.want {
+			t.Errorf("ScopeForChange(%q) = %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRebuildScopeMerge(t *testing.T) {
+	if got := RebuildPages.Merge(RebuildContent); got != RebuildContent {
+		t.Errorf("expected content, got %s", got)
+	}
+	if got := RebuildFull.Merge(RebuildPages); got != RebuildFull {
+		t.Errorf("expected full, got %s", got)
+	}
+	if got := RebuildNone.Merge(RebuildNone); got != RebuildNone {
+		t.Errorf("expected none, got %s", got)
+	}
+}
@@ -0,0 +1,328 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// maxAuditedFileSize skips large files, such as rendered images, when
+// snapshotting sources.
+const maxAuditedFileSize = 1 << 20
+
+// maxDiffCells bounds the line-diff table. Larger changes are recorded as the
+// whole old content removed and the new content added.
+const maxDiffCells = 4_000_000
+
+// RecordAudit appends an entry to the audit log for every tracked change,
+// including a line diff of each source file the change touched, so teams can
+// trace which agent or user changed the model. With a committer, the files of
+// every successful change are also committed to version control.
+type RecordAudit struct {
+	log         AuditLog
+	committer   ChangeCommitter // Optional: commits the files of each successful change
+	projectRoot string
+	sourceDir   string // Relative to projectRoot
+	user        string // OS user recorded when an entry has none
+	now         func() time.Time
+
+	mu sync.Mutex // Serializes tracked changes so their snapshots don't interleave
+}
+
+// NewRecordAudit creates a RecordAudit use case that diffs loko.toml and the
+// files under sourceDir (relative to projectRoot).
+func NewRecordAudit(log AuditLog, projectRoot, sourceDir string) *RecordAudit {
+	return &RecordAudit{
+		log:         log,
+		projectRoot: projectRoot,
+		sourceDir:   sourceDir,
+		user:        currentUser(),
+		now:         time.Now,
+	}
+}
+
+// WithCommitter commits the files changed by each successful change with a
+// message naming the action and the element it changed.
+func (uc *RecordAudit) WithCommitter(committer ChangeCommitter) *RecordAudit {
+	uc.committer = committer
+	return uc
+}
+
+// currentUser returns the name of the OS user running loko, or "" if unknown.
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// Track runs change and appends entry, completed with the time, the source
+// files changed and any error, to the audit log. It returns change's error.
+// A change that succeeded but could not be logged returns the logging error.
+func (uc *RecordAudit) Track(ctx context.Context, entry *entities.AuditEntry, change func() error) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	// A failed snapshot must not block the change; the entry notes it instead.
+	before, snapshotErr := uc.snapshot()
+
+	changeErr := change()
+
+	entry.Time = uc.now().UTC()
+	if entry.User == "" {
+		entry.User = uc.user
+	}
+	if changeErr != nil {
+		entry.Error = changeErr.Error()
+	}
+	after, err := uc.snapshot()
+	if snapshotErr == nil {
+		snapshotErr = err
+	}
+	if snapshotErr == nil {
+		entry.Changes = DiffSnapshots(before, after)
+	} else if entry.Error == "" {
+		entry.Error = fmt.Sprintf("changes not recorded: %v", snapshotErr)
+	}
+
+	if err := uc.log.Append(ctx, entry); err != nil && changeErr == nil {
+		return fmt.Errorf("change applied but not audited: %w", err)
+	}
+
+	if uc.committer != nil && changeErr == nil && len(entry.Changes) > 0 {
+		paths := make([]string, len(entry.Changes))
+		for i, change := range entry.Changes {
+			paths[i] = change.Path
+		}
+		if err := uc.committer.CommitFiles(ctx, uc.projectRoot, paths, CommitMessage(entry)); err != nil {
+			return fmt.Errorf("change applied but not committed: %w", err)
+		}
+	}
+	return changeErr
+}
+
+// CommitMessage returns the commit message of an audited change: a subject
+// naming the action and the element from its arguments, e.g.
+// "loko: create_component Auth Handler in payments/api", and a body with the
+// caller and the files changed.
+func CommitMessage(entry *entities.AuditEntry) string {
+	arg := func(key string) string {
+		value, _ := entry.Arguments[key].(string)
+		return value
+	}
+
+	subject := "loko: " + entry.Action
+	if source, target := arg("source"), arg("target"); source != "" && target != "" {
+		subject += " " + source + " -> " + target
+	} else {
+		var path []string
+		for _, key := range []string{"system_name", "container_name", "component_name"} {
+			if value := arg(key); value != "" {
+				path = append(path, value)
+			}
+		}
+		element := arg("name")
+		if element == "" && len(path) > 0 {
+			element, path = path[len(path)-1], path[:len(path)-1]
+		}
+		if element != "" {
+			subject += " " + element
+		}
+		if len(path) > 0 {
+			subject += " in " + strings.Join(path, "/")
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(subject)
+	sb.WriteString("\n\n")
+	fmt.Fprintf(&sb, "Source: %s\nActor: %s\n", entry.Source, entry.Actor)
+	if entry.User != "" {
+		fmt.Fprintf(&sb, "User: %s\n", entry.User)
+	}
+	sb.WriteString("\n")
+	for _, change := range entry.Changes {
+		fmt.Fprintf(&sb, "%-8s %s\n", change.Op, change.Path)
+	}
+	return sb.String()
+}
+
+// snapshot returns the contents of loko.toml and the source files keyed by
+// slash-separated path relative to the project root.
+func (uc *RecordAudit) snapshot() (map[string]string, error) {
+	return snapshotSources(uc.projectRoot, uc.sourceDir)
+}
+
+// snapshotSources returns the contents of loko.toml and the files under
+// sourceDirs keyed by slash-separated path relative to projectRoot. Relative
+// directories are relative to projectRoot, and missing ones are skipped.
+// Symlinked directories are followed, each real directory read once. Hidden
+// directories and files larger than maxAuditedFileSize are skipped.
+func snapshotSources(projectRoot string, sourceDirs ...string) (map[string]string, error) {
+	files := make(map[string]string)
+	if content, err := os.ReadFile(filepath.Join(projectRoot, "loko.toml")); err == nil {
+		files["loko.toml"] = string(content)
+	}
+
+	visited := make(map[string]bool)
+	for _, dir := range sourceDirs {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(projectRoot, dir)
+		}
+		if err := snapshotDir(projectRoot, dir, files, visited); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return files, err
+		}
+	}
+	return files, nil
+}
+
+// snapshotDir adds the files under dir to files, following symlinks.
+// visited holds the real paths of the directories already read, so a
+// directory reached twice, through another source directory or a symlink
+// cycle, is read once.
+func snapshotDir(projectRoot, dir string, files map[string]string, visited map[string]bool) error {
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	if visited[real] {
+		return nil
+	}
+	visited[real] = true
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		info, err := os.Stat(path)
+		if err != nil {
+			continue // Dangling symlink
+		}
+		if info.IsDir() {
+			if strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			if err := snapshotDir(projectRoot, path, files, visited); err != nil {
+				return err
+			}
+			continue
+		}
+		if !info.Mode().IsRegular() || info.Size() > maxAuditedFileSize {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(projectRoot, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(content)
+	}
+	return nil
+}
+
+// DiffSnapshots returns the files added, modified or removed between two
+// snapshots keyed by path, sorted by path.
+func DiffSnapshots(before, after map[string]string) []entities.FileChange {
+	var changes []entities.FileChange
+	for path, content := range after {
+		old, existed := before[path]
+		switch {
+		case !existed:
+			changes = append(changes, entities.FileChange{Path: path, Op: entities.FileAdded, Diff: DiffLines("", content)})
+		case old != content:
+			changes = append(changes, entities.FileChange{Path: path, Op: entities.FileModified, Diff: DiffLines(old, content)})
+		}
+	}
+	for path, content := range before {
+		if _, exists := after[path]; !exists {
+			changes = append(changes, entities.FileChange{Path: path, Op: entities.FileRemoved, Diff: DiffLines(content, "")})
+		}
+	}
+	slices.SortFunc(changes, func(a, b entities.FileChange) int { return strings.Compare(a.Path, b.Path) })
+	return changes
+}
+
+// DiffLines returns the lines removed from before (prefixed with "-") and
+// added in after (prefixed with "+"), in file order. Unchanged lines are
+// omitted.
+func DiffLines(before, after string) string {
+	a, b := splitLines(before), splitLines(after)
+
+	// Trim the common prefix and suffix; edits are usually local.
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		a, b = a[1:], b[1:]
+	}
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+
+	var sb strings.Builder
+	write := func(prefix, line string) {
+		sb.WriteString(prefix)
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			write("-", line)
+		}
+		for _, line := range b {
+			write("+", line)
+		}
+		return sb.String()
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i, j = i+1, j+1
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			write("-", a[i])
+			i++
+		default:
+			write("+", b[j])
+			j++
+		}
+	}
+	return sb.String()
+}
+
+// splitLines splits content into lines without their terminators.
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
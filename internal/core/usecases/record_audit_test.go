@@ -0,0 +1,164 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+type memoryAuditLog struct {
+	entries []*entities.AuditEntry
+	err     error
+}
+
+func (l *memoryAuditLog) Append(_ context.Context, entry *entities.AuditEntry) error {
+	if l.err != nil {
+		return l.err
+	}
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+func (l *memoryAuditLog) Entries(context.Context) ([]*entities.AuditEntry, error) {
+	return l.entries, nil
+}
+
+func TestRecordAudit_Track(t *testing.T) {
+	root := t.TempDir()
+	systemMd := filepath.Join(root, "src", "payments", "system.md")
+	if err := os.MkdirAll(filepath.Dir(systemMd), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(systemMd, []byte("---\nname: Payments\n---\n# Payments\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	log := &memoryAuditLog{}
+	uc := NewRecordAudit(log, root, "src")
+	entry := &entities.AuditEntry{Source: entities.AuditSourceMCP, Actor: "agent", Action: "update_system"}
+	err := uc.Track(context.Background(), entry, func() error {
+		if err := os.WriteFile(systemMd, []byte("---\nname: Payments\ndescription: Card payments\n---\n# Payments\n"), 0644); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(root, "src", "payments", "relationships.toml"), []byte("[[relationships]]\n"), 0644)
+	})
+	if err != nil {
+		t.Fatalf("Track failed: %v", err)
+	}
+
+	if len(log.entries) != 1 || entry.Time.IsZero() {
+		t.Fatalf("expected one timestamped entry, got %d", len(log.entries))
+	}
+	want := []entities.FileChange{
+		{Path: "src/payments/relationships.toml", Op: entities.FileAdded, Diff: "+[[relationships]]\n"},
+		{Path: "src/payments/system.md", Op: entities.FileModified, Diff: "+description: Card payments\n"},
+	}
+	if len(entry.Changes) != len(want) {
+		t.Fatalf("changes = %+v", entry.Changes)
+	}
+	for i := range want {
+		if entry.Changes[i] != want[i] {
+			t.Errorf("change %d = %+v, want %+v", i, entry.Changes[i], want[i])
+		}
+	}
+
+	failed := &entities.AuditEntry{Action: "create_system"}
+	if err := uc.Track(context.Background(), failed, func() error { return errors.New("invalid name") }); err == nil {
+		t.Error("expected change error")
+	}
+	if failed.Error != "invalid name" || len(failed.Changes) != 0 || len(log.entries) != 2 {
+		t.Errorf("failed entry = %+v", failed)
+	}
+
+	log.err = errors.New("disk full")
+	if err := uc.Track(context.Background(), &entities.AuditEntry{}, func() error { return nil }); err == nil {
+		t.Error("expected audit write error")
+	}
+}
+
+type recordingCommitter struct {
+	paths    []string
+	messages []string
+}
+
+func (c *recordingCommitter) CommitFiles(_ context.Context, _ string, paths []string, message string) error {
+	c.paths = append(c.paths, paths...)
+	c.messages = append(c.messages, message)
+	return nil
+}
+
+func TestRecordAudit_TrackCommits(t *testing.T) {
+	root := t.TempDir()
+	systemMd := filepath.Join(root, "src", "payments", "system.md")
+	if err := os.MkdirAll(filepath.Dir(systemMd), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	committer := &recordingCommitter{}
+	uc := NewRecordAudit(&memoryAuditLog{}, root, "src").WithCommitter(committer)
+	entry := &entities.AuditEntry{Source: entities.AuditSourceMCP, Actor: "agent", Action: "create_system", Arguments: map[string]any{"name": "Payments"}}
+	if err := uc.Track(context.Background(), entry, func() error {
+		return os.WriteFile(systemMd, []byte("# Payments\n"), 0644)
+	}); err != nil {
+		t.Fatalf("Track failed: %v", err)
+	}
+
+	// Failed changes and changes without files are not committed
+	_ = uc.Track(context.Background(), &entities.AuditEntry{Action: "create_system"}, func() error {
+		_ = os.WriteFile(systemMd, []byte("# Broken\n"), 0644)
+		return errors.New("invalid name")
+	})
+	_ = uc.Track(context.Background(), &entities.AuditEntry{Action: "build_docs"}, func() error { return nil })
+
+	if len(committer.messages) != 1 || len(committer.paths) != 1 || committer.paths[0] != "src/payments/system.md" {
+		t.Fatalf("commits = %+v", committer)
+	}
+	if !strings.HasPrefix(committer.messages[0], "loko: create_system Payments\n\nSource: mcp\nActor: agent\n") {
+		t.Errorf("message = %q", committer.messages[0])
+	}
+}
+
+func TestCommitMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"created element", map[string]any{"system_name": "payments", "container_name": "api", "name": "Auth Handler"}, "loko: create_component Auth Handler in payments/api"},
+		{"updated element", map[string]any{"system_name": "payments", "container_name": "api", "component_name": "auth"}, "loko: create_component auth in payments/api"},
+		{"relationship", map[string]any{"system_name": "payments", "source": "payments/api", "target": "payments/db"}, "loko: create_component payments/api -> payments/db"},
+		{"no element", nil, "loko: create_component"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := CommitMessage(&entities.AuditEntry{Action: "create_component", Arguments: tt.args})
+			if subject, _, _ := strings.Cut(message, "\n"); subject != tt.want {
+				t.Errorf("subject = %q, want %q", subject, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name, before, after, want string
+	}{
+		{"unchanged", "a\nb\n", "a\nb\n", ""},
+		{"added", "", "a\nb\n", "+a\n+b\n"},
+		{"removed", "a\nb\n", "", "-a\n-b\n"},
+		{"replaced line", "a\nb\nc\n", "a\nB\nc\n", "-b\n+B\n"},
+		{"interleaved", "a\nb\nc\nd\n", "a\nc\nd\ne\n", "-b\n+e\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffLines(tt.before, tt.after); got != tt.want {
+				t.Errorf("DiffLines = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
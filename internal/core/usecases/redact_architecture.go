@@ -0,0 +1,342 @@
+package usecases
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// DefaultRedactionMask replaces masked text when [redaction] mask is not set.
+const DefaultRedactionMask = "[redacted]"
+
+// RedactableFields lists the element fields [redaction] strip_fields may empty.
+// Names match the frontmatter keys.
+var RedactableFields = []string{
+	"description", "technology", "language", "framework", "database",
+	"responsibilities", "dependencies", "key_users", "external_systems",
+	"code_annotations", "issues", "metadata",
+}
+
+// RedactArchitecture derives a public-safe copy of the architecture for
+// published output. Elements carrying a removed tag are dropped together with
+// their children and the relationships pointing at them, configured fields
+// are emptied, and text matching a mask pattern (for example internal host
+// names) is replaced by the mask. The source model is never modified.
+type RedactArchitecture struct {
+	removeTags  []string
+	stripFields map[string]bool
+	patterns    []*regexp.Regexp
+	mask        string
+
+	// removedIDs holds the qualified IDs of removed elements and removedNames
+	// masks mentions of them in the remaining text. Both are set by Execute.
+	removedIDs   map[string]bool
+	removedNames *regexp.Regexp
+}
+
+// NewRedactArchitecture creates a RedactArchitecture use case from the
+// [redaction] settings in config. It fails on unknown field names and
+// invalid patterns so a misconfigured profile never publishes silently.
+func NewRedactArchitecture(config *entities.ProjectConfig) (*RedactArchitecture, error) {
+	uc := &RedactArchitecture{stripFields: make(map[string]bool), mask: DefaultRedactionMask}
+	if config == nil {
+		return uc, nil
+	}
+
+	uc.removeTags = config.RedactTags
+	for _, field := range config.RedactFields {
+		if !slices.Contains(RedactableFields, field) {
+			return nil, fmt.Errorf("unknown redaction field %q (valid: %s)", field, strings.Join(RedactableFields, ", "))
+		}
+		uc.stripFields[field] = true
+	}
+	for _, pattern := range config.RedactPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		uc.patterns = append(uc.patterns, re)
+	}
+	if config.RedactMask != "" {
+		uc.mask = config.RedactMask
+	}
+	return uc, nil
+}
+
+// Enabled reports whether any redaction rule is configured.
+func (uc *RedactArchitecture) Enabled() bool {
+	return len(uc.removeTags) > 0 || len(uc.stripFields) > 0 || len(uc.patterns) > 0
+}
+
+// Execute returns a redacted deep copy of systems. Names of removed elements
+// are remembered so MaskText also masks mentions of them elsewhere.
+func (uc *RedactArchitecture) Execute(systems []*entities.System) []*entities.System {
+	removed := make(map[string]bool) // qualified IDs of removed elements
+	var names []string
+	for _, sys := range systems {
+		if sys == nil {
+			continue
+		}
+		if uc.removed(sys.Tags) {
+			removed[sys.ID] = true
+			names = append(names, sys.Name)
+			continue
+		}
+		for _, container := range sys.ListContainers() {
+			containerID := sys.ID + "/" + container.ID
+			if uc.removed(container.Tags) {
+				removed[containerID] = true
+				names = append(names, container.Name)
+				continue
+			}
+			for _, component := range container.ListComponents() {
+				if uc.removed(component.Tags) {
+					removed[containerID+"/"+component.ID] = true
+					names = append(names, component.Name)
+				}
+			}
+		}
+	}
+	uc.removedIDs = removed
+	uc.removedNames = namesPattern(names)
+
+	redacted := make([]*entities.System, 0, len(systems))
+	for _, sys := range systems {
+		if sys == nil || removed[sys.ID] {
+			continue
+		}
+		redacted = append(redacted, uc.redactSystem(sys, removed))
+	}
+	return redacted
+}
+
+// RedactProject returns a copy of project with a masked description and
+// systems, typically the result of Execute, as its systems.
+func (uc *RedactArchitecture) RedactProject(project *entities.Project, systems []*entities.System) *entities.Project {
+	if project == nil {
+		return nil
+	}
+	out := *project
+	out.Description = uc.MaskText(project.Description)
+	out.Metadata = uc.metadata(project.Metadata)
+	out.Systems = make(map[string]*entities.System, len(systems))
+	for _, sys := range systems {
+		out.Systems[sys.ID] = sys
+	}
+	return &out
+}
+
+// RedactRelationships returns a copy of relationships (relationships.toml
+// entries) without those touching an element removed by Execute, with masked
+// labels and, when the technology field is stripped, no technology.
+func (uc *RedactArchitecture) RedactRelationships(relationships []entities.Relationship) []entities.Relationship {
+	var out []entities.Relationship
+	for _, rel := range relationships {
+		if pathRemoved(rel.Source, uc.removedIDs) || pathRemoved(rel.Target, uc.removedIDs) {
+			continue
+		}
+		rel.Label = uc.MaskText(rel.Label)
+		rel.Technology = uc.text("technology", rel.Technology)
+		out = append(out, rel)
+	}
+	return out
+}
+
+// MaskText replaces every match of the mask patterns, and every mention of an
+// element removed by Execute, with the mask.
+func (uc *RedactArchitecture) MaskText(text string) string {
+	for _, re := range uc.patterns {
+		text = re.ReplaceAllLiteralString(text, uc.mask)
+	}
+	if uc.removedNames != nil {
+		text = uc.removedNames.ReplaceAllLiteralString(text, uc.mask)
+	}
+	return text
+}
+
+// removed reports whether tags include a removed tag (case-insensitive).
+func (uc *RedactArchitecture) removed(tags []string) bool {
+	for _, tag := range tags {
+		for _, removeTag := range uc.removeTags {
+			if strings.EqualFold(tag, removeTag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (uc *RedactArchitecture) redactSystem(sys *entities.System, removed map[string]bool) *entities.System {
+	out := *sys
+	out.Name = uc.MaskText(sys.Name)
+	out.Description = uc.text("description", sys.Description)
+	out.PrimaryLanguage = uc.text("language", sys.PrimaryLanguage)
+	out.Framework = uc.text("framework", sys.Framework)
+	out.Database = uc.text("database", sys.Database)
+	out.Responsibilities = uc.list("responsibilities", sys.Responsibilities)
+	out.Dependencies = uc.list("dependencies", sys.Dependencies)
+	out.KeyUsers = uc.list("key_users", sys.KeyUsers)
+	out.ExternalSystems = uc.list("external_systems", sys.ExternalSystems)
+	out.Issues = uc.list("issues", sys.Issues)
+	out.Metadata = uc.metadata(sys.Metadata)
+	out.Tags = slices.Clone(sys.Tags)
+	out.Diagram = uc.diagram(sys.Diagram)
+
+	out.Containers = make(map[string]*entities.Container, len(sys.Containers))
+	for id, container := range sys.Containers {
+		if !removed[sys.ID+"/"+container.ID] {
+			out.Containers[id] = uc.redactContainer(sys.ID, container, removed)
+		}
+	}
+	return &out
+}
+
+func (uc *RedactArchitecture) redactContainer(systemID string, container *entities.Container, removed map[string]bool) *entities.Container {
+	out := *container
+	out.Name = uc.MaskText(container.Name)
+	out.Description = uc.text("description", container.Description)
+	out.Technology = uc.text("technology", container.Technology)
+	out.Issues = uc.list("issues", container.Issues)
+	out.Metadata = uc.metadata(container.Metadata)
+	out.Tags = slices.Clone(container.Tags)
+	out.Diagram = uc.diagram(container.Diagram)
+
+	containerID := systemID + "/" + container.ID
+	out.Components = make(map[string]*entities.Component, len(container.Components))
+	for id, component := range container.Components {
+		if !removed[containerID+"/"+component.ID] {
+			out.Components[id] = uc.redactComponent(containerID, component, removed)
+		}
+	}
+	return &out
+}
+
+func (uc *RedactArchitecture) redactComponent(containerID string, component *entities.Component, removed map[string]bool) *entities.Component {
+	out := *component
+	out.Name = uc.MaskText(component.Name)
+	out.Description = uc.text("description", component.Description)
+	out.Technology = uc.text("technology", component.Technology)
+	out.Dependencies = uc.list("dependencies", component.Dependencies)
+	out.Issues = uc.list("issues", component.Issues)
+	out.Metadata = uc.metadata(component.Metadata)
+	out.Tags = slices.Clone(component.Tags)
+	out.Diagram = uc.diagram(component.Diagram)
+
+	if component.CodeAnnotations != nil && !uc.stripFields["code_annotations"] {
+		out.CodeAnnotations = make(map[string]string, len(component.CodeAnnotations))
+		for path, note := range component.CodeAnnotations {
+			out.CodeAnnotations[path] = uc.MaskText(note)
+		}
+	} else {
+		out.CodeAnnotations = nil
+	}
+
+	if component.Relationships != nil {
+		out.Relationships = make(map[string]string, len(component.Relationships))
+		for target, description := range component.Relationships {
+			if !relationshipRemoved(containerID, target, removed) {
+				out.Relationships[target] = uc.MaskText(description)
+			}
+		}
+	}
+	return &out
+}
+
+// text returns value masked, or empty when field is stripped.
+func (uc *RedactArchitecture) text(field, value string) string {
+	if uc.stripFields[field] {
+		return ""
+	}
+	return uc.MaskText(value)
+}
+
+// list returns a masked copy of values, or nil when field is stripped.
+func (uc *RedactArchitecture) list(field string, values []string) []string {
+	if uc.stripFields[field] || values == nil {
+		return nil
+	}
+	out := make([]string, len(values))
+	for i, value := range values {
+		out[i] = uc.MaskText(value)
+	}
+	return out
+}
+
+// metadata returns a copy of extra frontmatter fields with string values masked.
+func (uc *RedactArchitecture) metadata(metadata map[string]any) map[string]any {
+	if uc.stripFields["metadata"] || metadata == nil {
+		return nil
+	}
+	out := maps.Clone(metadata)
+	for key, value := range out {
+		if s, ok := value.(string); ok {
+			out[key] = uc.MaskText(s)
+		}
+	}
+	return out
+}
+
+// diagram returns a copy of diagram with its D2 source masked.
+func (uc *RedactArchitecture) diagram(diagram *entities.Diagram) *entities.Diagram {
+	if diagram == nil {
+		return nil
+	}
+	out := *diagram
+	out.Source = uc.MaskText(diagram.Source)
+	return &out
+}
+
+// relationshipRemoved reports whether a relationship target, given as a
+// component ID within the source's container or as a qualified path, refers
+// to a removed element.
+func relationshipRemoved(containerID, target string, removed map[string]bool) bool {
+	if !strings.Contains(target, "/") {
+		return removed[containerID+"/"+target]
+	}
+	return pathRemoved(target, removed)
+}
+
+// pathRemoved reports whether a qualified element path, or any element
+// containing it, was removed.
+func pathRemoved(path string, removed map[string]bool) bool {
+	for {
+		if removed[path] {
+			return true
+		}
+		i := strings.LastIndex(path, "/")
+		if i < 0 {
+			return false
+		}
+		path = path[:i]
+	}
+}
+
+// namesPattern matches any of names as a whole word, longest first, or
+// returns nil when there are no names.
+func namesPattern(names []string) *regexp.Regexp {
+	names = slices.DeleteFunc(names, func(name string) bool { return strings.TrimSpace(name) == "" })
+	if len(names) == 0 {
+		return nil
+	}
+	slices.SortFunc(names, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+	wordChar := regexp.MustCompile(`\w`)
+	alternatives := make([]string, len(names))
+	for i, name := range names {
+		alt := regexp.QuoteMeta(name)
+		// Anchor on word boundaries only where the name starts or ends with a
+		// word character; "\b" never matches after a trailing "+" or ")".
+		if wordChar.MatchString(name[:1]) {
+			alt = `\b` + alt
+		}
+		if wordChar.MatchString(name[len(name)-1:]) {
+			alt += `\b`
+		}
+		alternatives[i] = alt
+	}
+	return regexp.MustCompile(`(?:` + strings.Join(alternatives, "|") + `)`)
+}
@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestRedactArchitecture(t *testing.T) {
+	payments, _ := entities.NewSystem("Payments")
+	payments.SetDescription("Runs on pay-01.corp.example.com")
+	api, _ := entities.NewContainer("API")
+	api.Technology = "Go"
+	api.Diagram = &entities.Diagram{Source: "api -> db: pay-01.corp.example.com"}
+	handler, _ := entities.NewComponent("Handler")
+	handler.AddRelationship("fraud-check", "calls Fraud Check")
+	handler.AddRelationship("ledger", "writes entries")
+	fraud, _ := entities.NewComponent("Fraud Check")
+	fraud.Tags = []string{"Secret"}
+	ledger, _ := entities.NewComponent("Ledger")
+	_ = api.AddComponent(handler)
+	_ = api.AddComponent(fraud)
+	_ = api.AddComponent(ledger)
+	_ = payments.AddContainer(api)
+
+	vault, _ := entities.NewSystem("Vault")
+	vault.Tags = []string{"secret"}
+
+	uc, err := NewRedactArchitecture(&entities.ProjectConfig{
+		RedactTags:     []string{"secret"},
+		RedactFields:   []string{"technology"},
+		RedactPatterns: []string{`[a-z0-9-]+\.corp\.example\.com`},
+	})
+	if err != nil {
+		t.Fatalf("NewRedactArchitecture failed: %v", err)
+	}
+	if !uc.Enabled() {
+		t.Fatal("expected redaction to be enabled")
+	}
+
+	systems := uc.Execute([]*entities.System{payments, vault})
+	if len(systems) != 1 || systems[0].ID != payments.ID {
+		t.Fatalf("expected only Payments to remain, got %d systems", len(systems))
+	}
+	got := systems[0]
+	if got.Description != "Runs on [redacted]" {
+		t.Errorf("Description = %q", got.Description)
+	}
+	container := got.Containers[api.ID]
+	if container.Technology != "" {
+		t.Errorf("Technology = %q, want stripped", container.Technology)
+	}
+	if container.Diagram.Source != "api -> db: [redacted]" {
+		t.Errorf("Diagram.Source = %q", container.Diagram.Source)
+	}
+	if _, ok := container.Components[fraud.ID]; ok {
+		t.Error("expected component tagged Secret to be removed")
+	}
+	rels := container.Components[handler.ID].Relationships
+	if _, ok := rels["fraud-check"]; ok || rels["ledger"] != "writes entries" {
+		t.Errorf("Relationships = %v", rels)
+	}
+	if text := uc.MaskText("Ledger calls Fraud Check on vault.corp.example.com"); text != "Ledger calls [redacted] on [redacted]" {
+		t.Errorf("MaskText = %q", text)
+	}
+
+	stored := uc.RedactRelationships([]entities.Relationship{
+		{Source: "payments/api/handler", Target: "payments/api/fraud-check", Label: "calls"},
+		{Source: "payments/api", Target: "vault", Label: "reads secrets"},
+		{Source: "payments/api/handler", Target: "payments/api/ledger", Label: "writes to pay-01.corp.example.com", Technology: "gRPC"},
+	})
+	if len(stored) != 1 || stored[0].Label != "writes to [redacted]" || stored[0].Technology != "" {
+		t.Errorf("RedactRelationships = %+v", stored)
+	}
+
+	// The source model is untouched.
+	if payments.Description != "Runs on pay-01.corp.example.com" || api.Technology != "Go" || len(api.Components) != 3 {
+		t.Error("source model was modified")
+	}
+	if len(handler.Relationships) != 2 {
+		t.Errorf("source relationships modified: %v", handler.Relationships)
+	}
+}
+
+func TestNewRedactArchitecture_InvalidConfig(t *testing.T) {
+	if _, err := NewRedactArchitecture(&entities.ProjectConfig{RedactFields: []string{"hostname"}}); err == nil || !strings.Contains(err.Error(), "hostname") {
+		t.Errorf("expected unknown field error, got %v", err)
+	}
+	if _, err := NewRedactArchitecture(&entities.ProjectConfig{RedactPatterns: []string{"("}}); err == nil {
+		t.Error("expected invalid pattern error")
+	}
+
+	uc, err := NewRedactArchitecture(nil)
+	if err != nil || uc.Enabled() {
+		t.Errorf("nil config: enabled=%v err=%v", uc != nil && uc.Enabled(), err)
+	}
+}
@@ -16,6 +16,7 @@ import (
 type RenderMarkdownDocs struct {
 	markdownRenderer MarkdownRenderer
 	progressReporter ProgressReporter
+	readSource       func(path string) ([]byte, error) // Reads element Markdown files
 }
 
 // NewRenderMarkdownDocs creates a new RenderMarkdownDocs use case.
@@ -26,9 +27,17 @@ func NewRenderMarkdownDocs(
 	return &RenderMarkdownDocs{
 		markdownRenderer: markdownRenderer,
 		progressReporter: progressReporter,
+		readSource:       os.ReadFile,
 	}
 }
 
+// WithSourceReader replaces os.ReadFile for reading element Markdown files,
+// e.g. to decrypt sources encrypted at rest or to redact their content.
+func (uc *RenderMarkdownDocs) WithSourceReader(read func(path string) ([]byte, error)) *RenderMarkdownDocs {
+	uc.readSource = read
+	return uc
+}
+
 // Execute renders all markdown files in a project to HTML.
 // It iterates through systems, containers, and components,
 // reads their associated markdown files, and renders them as HTML with embedded diagrams.
@@ -75,7 +84,7 @@ func (uc *RenderMarkdownDocs) Execute(
 		}
 
 		// Render container markdowns
-		for _, container := range sys.Containers {
+		for _, container := range sys.ListContainers() {
 			count++
 			uc.progressReporter.ReportProgress(
 				fmt.Sprintf("Rendering container markdown: %s/%s", sys.Name, container.Name),
@@ -90,7 +99,7 @@ func (uc *RenderMarkdownDocs) Execute(
 			}
 
 			// Render component markdowns
-			for _, component := range container.Components {
+			for _, component := range container.ListComponents() {
 				count++
 				uc.progressReporter.ReportProgress(
 					fmt.Sprintf("Rendering component markdown: %s/%s/%s", sys.Name, container.Name, component.Name),
@@ -114,7 +123,7 @@ func (uc *RenderMarkdownDocs) Execute(
 // renderSystemMarkdown renders a system's markdown to HTML.
 func (uc *RenderMarkdownDocs) renderSystemMarkdown(_ context.Context, system *entities.System, outputDir string) error {
 	markdownPath := filepath.Join(system.Path, "system.md")
-	content, err := os.ReadFile(markdownPath)
+	content, err := uc.readSource(markdownPath)
 	if err != nil {
 		// If markdown file doesn't exist, skip rendering
 		if os.IsNotExist(err) {
@@ -150,7 +159,7 @@ func (uc *RenderMarkdownDocs) renderSystemMarkdown(_ context.Context, system *en
 // renderContainerMarkdown renders a container's markdown to HTML.
 func (uc *RenderMarkdownDocs) renderContainerMarkdown(_ context.Context, system *entities.System, container *entities.Container, outputDir string) error {
 	markdownPath := filepath.Join(container.Path, "container.md")
-	content, err := os.ReadFile(markdownPath)
+	content, err := uc.readSource(markdownPath)
 	if err != nil {
 		// If markdown file doesn't exist, skip rendering
 		if os.IsNotExist(err) {
@@ -186,7 +195,7 @@ func (uc *RenderMarkdownDocs) renderContainerMarkdown(_ context.Context, system
 // renderComponentMarkdown renders a component's markdown to HTML.
 func (uc *RenderMarkdownDocs) renderComponentMarkdown(_ context.Context, system *entities.System, container *entities.Container, component *entities.Component, outputDir string) error {
 	markdownPath := filepath.Join(component.Path, "component.md")
-	content, err := os.ReadFile(markdownPath)
+	content, err := uc.readSource(markdownPath)
 	if err != nil {
 		// If markdown file doesn't exist, skip rendering
 		if os.IsNotExist(err) {
@@ -0,0 +1,108 @@
+package usecases
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// rollupGraphFixture builds a graph of two systems whose components call each
+// other, plus a relationship inside the shop system and a customer using it.
+func rollupGraphFixture() *entities.ArchitectureGraph {
+	graph := entities.NewArchitectureGraph()
+	nodes := []*entities.GraphNode{
+		{ID: "customer", Type: "person", Name: "Customer"},
+		{ID: "shop", Type: "system", Name: "Shop"},
+		{ID: "shop/api", Type: "container", Name: "API", ParentID: "shop"},
+		{ID: "shop/api/cart", Type: "component", Name: "Cart", ParentID: "shop/api"},
+		{ID: "shop/api/checkout", Type: "component", Name: "Checkout", ParentID: "shop/api"},
+		{ID: "shop/db", Type: "container", Name: "DB", ParentID: "shop"},
+		{ID: "billing", Type: "system", Name: "Billing"},
+		{ID: "billing/core", Type: "container", Name: "Core", ParentID: "billing"},
+		{ID: "billing/core/ledger", Type: "component", Name: "Ledger", ParentID: "billing/core"},
+	}
+	for _, node := range nodes {
+		_ = graph.AddNode(node)
+	}
+	for _, edge := range []*entities.GraphEdge{
+		{Source: "customer", Target: "shop", Description: "buys from"},
+		{Source: "shop/api/cart", Target: "billing/core/ledger", Description: "records payments in"},
+		{Source: "shop/api/checkout", Target: "billing/core/ledger", Description: "settles in"},
+		{Source: "shop/api/cart", Target: "shop/api/checkout", Description: "hands over to"},
+		{Source: "shop/api", Target: "shop/db", Description: "stores carts in"},
+	} {
+		_ = graph.AddEdge(edge)
+	}
+	return graph
+}
+
+func TestRollupGraph(t *testing.T) {
+	graph := rollupGraphFixture()
+
+	systems, err := RollupGraph(graph, RollupSystem)
+	if err != nil {
+		t.Fatalf("RollupGraph failed: %v", err)
+	}
+	want := []RollupEdge{
+		{Source: "customer", Target: "shop", Count: 1, Descriptions: []string{"buys from"}},
+		{Source: "shop", Target: "billing", Count: 2, Descriptions: []string{"records payments in", "settles in"}},
+	}
+	if !slices.EqualFunc(systems, want, rollupEdgeEqual) {
+		t.Errorf("system rollup = %+v, want %+v", systems, want)
+	}
+
+	containers, err := RollupGraph(graph, RollupContainer)
+	if err != nil {
+		t.Fatalf("RollupGraph failed: %v", err)
+	}
+	want = []RollupEdge{
+		{Source: "shop/api", Target: "billing/core", Count: 2, Descriptions: []string{"records payments in", "settles in"}},
+		{Source: "shop/api", Target: "shop/db", Count: 1, Descriptions: []string{"stores carts in"}},
+	}
+	if !slices.EqualFunc(containers, want, rollupEdgeEqual) {
+		t.Errorf("container rollup = %+v, want %+v", containers, want)
+	}
+
+	all, err := RollupGraph(graph, RollupNone)
+	if err != nil {
+		t.Fatalf("RollupGraph failed: %v", err)
+	}
+	if len(all) != 5 {
+		t.Errorf("got %d relationships without rollup, want 5", len(all))
+	}
+
+	if _, err := RollupGraph(graph, "component"); err == nil {
+		t.Error("expected an error for an unsupported level")
+	}
+}
+
+func rollupEdgeEqual(a, b RollupEdge) bool {
+	return a.Source == b.Source && a.Target == b.Target && a.Count == b.Count && slices.Equal(a.Descriptions, b.Descriptions)
+}
+
+func TestGenerateRollupDiagram(t *testing.T) {
+	source, err := GenerateRollupDiagram(rollupGraphFixture(), RollupSystem)
+	if err != nil {
+		t.Fatalf("GenerateRollupDiagram failed: %v", err)
+	}
+	for _, want := range []string{
+		"# System Landscape",
+		"\"customer\": \"Customer\" {\n  shape: person\n}",
+		`"billing": "Billing" {`,
+		`"customer" -> "shop": "buys from"`,
+		`"shop" -> "billing": "2 relationships"`,
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("diagram does not contain %q:\n%s", want, source)
+		}
+	}
+	if strings.Contains(source, "shop/api") {
+		t.Errorf("system landscape should not draw containers:\n%s", source)
+	}
+
+	if _, err := GenerateRollupDiagram(rollupGraphFixture(), RollupNone); err == nil {
+		t.Error("expected an error without a rollup level")
+	}
+}
@@ -0,0 +1,108 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// HookEvent names a point in a command at which [hooks] commands run.
+type HookEvent string
+
+const (
+	HookPreBuild    HookEvent = "pre_build"
+	HookPostBuild   HookEvent = "post_build"
+	HookPreValidate HookEvent = "pre_validate"
+)
+
+// HookContext describes the build or validation a hook runs for. It is
+// passed to hook commands as LOKO_* environment variables.
+type HookContext struct {
+	ProjectRoot string   // Absolute project root; hooks run from here
+	SourceDir   string   // Source directory relative to ProjectRoot
+	SourceDirs  []string // SourceDir followed by the [paths] source_dirs
+	OutputDir   string   // Absolute output directory; empty for pre_validate
+	Formats     []string // Output formats being built, e.g. "html", "markdown"
+
+	// Changed entities: sorted system IDs and "system/container" and
+	// "system/container/component" paths whose sources changed, e.g. since
+	// the last commit.
+	ChangedSystems    []string
+	ChangedContainers []string
+	ChangedComponents []string
+}
+
+// NewHookContext returns a hook context for the sources in sourceDirs, the
+// source directory first, whose changed entities are the entities changes
+// marks dirty. changes may be nil.
+func NewHookContext(projectRoot string, sourceDirs []string, changes *ChangeSet) HookContext {
+	hc := HookContext{ProjectRoot: projectRoot, SourceDirs: sourceDirs}
+	if len(sourceDirs) > 0 {
+		hc.SourceDir = sourceDirs[0]
+	}
+	if changes != nil {
+		hc.ChangedSystems = changes.DirtySystems()
+		hc.ChangedContainers = changes.DirtyContainers()
+		hc.ChangedComponents = changes.DirtyComponents()
+	}
+	return hc
+}
+
+// Env returns the LOKO_* environment variables describing the hook. Lists are
+// space-separated; element IDs never contain spaces.
+func (hc HookContext) Env(event HookEvent) []string {
+	changed := make([]string, 0, len(hc.ChangedSystems)+len(hc.ChangedContainers)+len(hc.ChangedComponents))
+	changed = append(changed, hc.ChangedSystems...)
+	changed = append(changed, hc.ChangedContainers...)
+	changed = append(changed, hc.ChangedComponents...)
+
+	return []string{
+		"LOKO_HOOK=" + string(event),
+		"LOKO_PROJECT_ROOT=" + hc.ProjectRoot,
+		"LOKO_SOURCE_DIR=" + hc.SourceDir,
+		"LOKO_SOURCE_DIRS=" + strings.Join(hc.SourceDirs, ","),
+		"LOKO_OUTPUT_DIR=" + hc.OutputDir,
+		"LOKO_FORMATS=" + strings.Join(hc.Formats, ","),
+		"LOKO_CHANGED_ENTITIES=" + strings.Join(changed, " "),
+		"LOKO_CHANGED_SYSTEMS=" + strings.Join(hc.ChangedSystems, " "),
+		"LOKO_CHANGED_CONTAINERS=" + strings.Join(hc.ChangedContainers, " "),
+		"LOKO_CHANGED_COMPONENTS=" + strings.Join(hc.ChangedComponents, " "),
+	}
+}
+
+// RunHooks runs the [hooks] commands configured for an event.
+type RunHooks struct {
+	runner HookRunner
+	hooks  map[HookEvent][]string
+}
+
+// NewRunHooks creates a RunHooks use case for the hooks in config, which may
+// be nil.
+func NewRunHooks(runner HookRunner, config *entities.ProjectConfig) *RunHooks {
+	uc := &RunHooks{runner: runner, hooks: make(map[HookEvent][]string)}
+	if config != nil {
+		uc.hooks[HookPreBuild] = config.PreBuildHooks
+		uc.hooks[HookPostBuild] = config.PostBuildHooks
+		uc.hooks[HookPreValidate] = config.PreValidateHooks
+	}
+	return uc
+}
+
+// Has reports whether any command is configured for event.
+func (uc *RunHooks) Has(event HookEvent) bool {
+	return len(uc.hooks[event]) > 0
+}
+
+// Execute runs the commands for event in order from the project root and
+// stops at the first one that fails.
+func (uc *RunHooks) Execute(ctx context.Context, event HookEvent, hc HookContext) error {
+	env := hc.Env(event)
+	for _, command := range uc.hooks[event] {
+		if err := uc.runner.Run(ctx, command, hc.ProjectRoot, env); err != nil {
+			return fmt.Errorf("%s hook %q failed: %w", event, command, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// recordingRunner records the hook commands it runs and fails the ones
+// listed in fail.
+type recordingRunner struct {
+	commands []string
+	env      []string
+	fail     map[string]bool
+}
+
+func (r *recordingRunner) Run(_ context.Context, command, _ string, env []string) error {
+	r.commands = append(r.commands, command)
+	r.env = env
+	if r.fail[command] {
+		return errors.New("exit status 1")
+	}
+	return nil
+}
+
+func TestRunHooks(t *testing.T) {
+	config := &entities.ProjectConfig{
+		PreBuildHooks:  []string{"make openapi", "false", "never"},
+		PostBuildHooks: []string{"./upload.sh"},
+	}
+	runner := &recordingRunner{fail: map[string]bool{"false": true}}
+	uc := NewRunHooks(runner, config)
+	ctx := context.Background()
+
+	if uc.Has(HookPreValidate) || !uc.Has(HookPostBuild) {
+		t.Error("Has reported the wrong events")
+	}
+
+	err := uc.Execute(ctx, HookPreBuild, HookContext{ProjectRoot: "/p"})
+	if err == nil || err.Error() != `pre_build hook "false" failed: exit status 1` {
+		t.Errorf("Execute error = %v", err)
+	}
+	if !slices.Equal(runner.commands, []string{"make openapi", "false"}) {
+		t.Errorf("commands = %v, want to stop at the failing hook", runner.commands)
+	}
+
+	if err := uc.Execute(ctx, HookPreValidate, HookContext{}); err != nil {
+		t.Errorf("Execute with no hooks = %v", err)
+	}
+}
+
+func TestHookContextEnv(t *testing.T) {
+	changes := NewChangeSet("./src", "shared")
+	changes.Add(FileChangeEvent{Path: "src/payments/api/handler/component.md", Op: "write"})
+	changes.Add(FileChangeEvent{Path: "src/auth/system.md", Op: "write"})
+	changes.Add(FileChangeEvent{Path: "shared/identity/system.md", Op: "write"})
+
+	hc := NewHookContext("/work", []string{"./src", "shared"}, changes)
+	hc.OutputDir = "/work/dist"
+	hc.Formats = []string{"html", "markdown"}
+	env := hc.Env(HookPostBuild)
+
+	for _, want := range []string{
+		"LOKO_HOOK=post_build",
+		"LOKO_SOURCE_DIR=./src",
+		"LOKO_SOURCE_DIRS=./src,shared",
+		"LOKO_OUTPUT_DIR=/work/dist",
+		"LOKO_FORMATS=html,markdown",
+		"LOKO_CHANGED_SYSTEMS=auth identity payments",
+		"LOKO_CHANGED_ENTITIES=auth identity payments payments/api payments/api/handler",
+	} {
+		if !slices.Contains(env, want) {
+			t.Errorf("env missing %q: %v", want, env)
+		}
+	}
+}
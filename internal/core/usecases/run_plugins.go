@@ -0,0 +1,169 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// Plugin protocol methods called by loko. "initialize" is sent by the host
+// before the first call to a plugin process.
+const (
+	PluginMethodInitialize = "initialize"
+	PluginMethodRender     = "render"
+	PluginMethodExport     = "export"
+	PluginMethodValidate   = "validate"
+	PluginMethodImport     = "import"
+)
+
+// PluginModel is the architecture model sent to exporter and validator plugins.
+type PluginModel struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Version     string             `json:"version,omitempty"`
+	Systems     []*entities.System `json:"systems"`
+}
+
+// NewPluginModel returns the model of project and systems sent to plugins.
+func NewPluginModel(project *entities.Project, systems []*entities.System) PluginModel {
+	return PluginModel{
+		Name:        project.Name,
+		Description: project.Description,
+		Version:     project.Version,
+		Systems:     systems,
+	}
+}
+
+// PluginIssue is an architecture issue reported by a validator plugin.
+type PluginIssue struct {
+	Severity    string   `json:"severity"` // "error", "warning" or "info"
+	Code        string   `json:"code"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Affected    []string `json:"affected,omitempty"`
+	Suggestion  string   `json:"suggestion,omitempty"`
+}
+
+// RunPluginValidators runs every validator plugin over the model and adds
+// the issues they report to an architecture report.
+type RunPluginValidators struct {
+	host PluginHost
+}
+
+// NewRunPluginValidators creates a new RunPluginValidators use case.
+func NewRunPluginValidators(host PluginHost) *RunPluginValidators {
+	return &RunPluginValidators{host: host}
+}
+
+// Execute calls each validator in plugins and appends its issues to report,
+// with codes prefixed by the plugin name (e.g. "naming/missing_owner").
+// Plugins without the validator capability are skipped.
+func (uc *RunPluginValidators) Execute(ctx context.Context, plugins []*entities.PluginManifest, project *entities.Project, systems []*entities.System, report *ArchitectureReport) error {
+	model := NewPluginModel(project, systems)
+	for _, plugin := range plugins {
+		if !plugin.Provides(entities.PluginValidator) {
+			continue
+		}
+		var result struct {
+			Issues []PluginIssue `json:"issues"`
+		}
+		if err := uc.host.Call(ctx, plugin, PluginMethodValidate, map[string]any{"model": model}, &result); err != nil {
+			return fmt.Errorf("validator plugin %s failed: %w", plugin.Name, err)
+		}
+		for _, issue := range result.Issues {
+			report.AddIssue(ArchitectureIssue{
+				Severity:    issue.Severity,
+				Code:        plugin.Name + "/" + issue.Code,
+				Title:       issue.Title,
+				Description: issue.Description,
+				Affected:    issue.Affected,
+				Suggestion:  issue.Suggestion,
+			})
+		}
+	}
+	return nil
+}
+
+// ExportWithPlugin writes the model in a format provided by an exporter plugin.
+type ExportWithPlugin struct {
+	host PluginHost
+}
+
+// NewExportWithPlugin creates a new ExportWithPlugin use case.
+func NewExportWithPlugin(host PluginHost) *ExportWithPlugin {
+	return &ExportWithPlugin{host: host}
+}
+
+// Execute asks plugin to export the model into outputDir and returns the
+// paths of the files it wrote. options are passed through to the plugin.
+func (uc *ExportWithPlugin) Execute(ctx context.Context, plugin *entities.PluginManifest, project *entities.Project, systems []*entities.System, outputDir string, options map[string]string) ([]string, error) {
+	if !plugin.Provides(entities.PluginExporter) {
+		return nil, fmt.Errorf("plugin %s is not an exporter", plugin.Name)
+	}
+	var result struct {
+		Files []string `json:"files"`
+	}
+	params := map[string]any{
+		"model":     NewPluginModel(project, systems),
+		"outputDir": outputDir,
+		"options":   options,
+	}
+	if err := uc.host.Call(ctx, plugin, PluginMethodExport, params, &result); err != nil {
+		return nil, fmt.Errorf("exporter plugin %s failed: %w", plugin.Name, err)
+	}
+	return result.Files, nil
+}
+
+// ImportWithPlugin creates architecture sources from an external system
+// through an importer plugin.
+type ImportWithPlugin struct {
+	host PluginHost
+}
+
+// NewImportWithPlugin creates a new ImportWithPlugin use case.
+func NewImportWithPlugin(host PluginHost) *ImportWithPlugin {
+	return &ImportWithPlugin{host: host}
+}
+
+// Execute asks plugin to write Markdown and D2 sources under sourceDir (an
+// absolute path) and returns the paths of the files it wrote. args are the
+// command-line arguments given after the plugin name.
+func (uc *ImportWithPlugin) Execute(ctx context.Context, plugin *entities.PluginManifest, projectRoot, sourceDir string, args []string) ([]string, error) {
+	if !plugin.Provides(entities.PluginImporter) {
+		return nil, fmt.Errorf("plugin %s is not an importer", plugin.Name)
+	}
+	var result struct {
+		Files []string `json:"files"`
+	}
+	params := map[string]any{
+		"projectRoot": projectRoot,
+		"sourceDir":   sourceDir,
+		"args":        args,
+	}
+	if err := uc.host.Call(ctx, plugin, PluginMethodImport, params, &result); err != nil {
+		return nil, fmt.Errorf("importer plugin %s failed: %w", plugin.Name, err)
+	}
+	return result.Files, nil
+}
+
+// FindPlugin returns the plugin named name, or an error listing the
+// installed plugins that provide capability.
+func FindPlugin(plugins []*entities.PluginManifest, name string, capability entities.PluginCapability) (*entities.PluginManifest, error) {
+	var candidates []string
+	for _, plugin := range plugins {
+		if plugin.Name == name {
+			if !plugin.Provides(capability) {
+				return nil, fmt.Errorf("plugin %s does not provide the %s capability", name, capability)
+			}
+			return plugin, nil
+		}
+		if plugin.Provides(capability) {
+			candidates = append(candidates, plugin.Name)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("plugin %s is not installed (no %s plugins installed; see loko plugin install)", name, capability)
+	}
+	return nil, fmt.Errorf("plugin %s is not installed (installed %s plugins: %v)", name, capability, candidates)
+}
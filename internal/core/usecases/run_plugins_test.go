@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// scriptedHost answers plugin calls from canned JSON results and records
+// the methods and params it received.
+type scriptedHost struct {
+	results map[string]string // method -> JSON result
+	calls   []string
+	params  []map[string]any
+}
+
+func (h *scriptedHost) Call(_ context.Context, plugin *entities.PluginManifest, method string, params, result any) error {
+	h.calls = append(h.calls, plugin.Name+"."+method)
+	data, _ := json.Marshal(params)
+	var decoded map[string]any
+	_ = json.Unmarshal(data, &decoded)
+	h.params = append(h.params, decoded)
+
+	raw, ok := h.results[method]
+	if !ok {
+		return errors.New("method not found")
+	}
+	return json.Unmarshal([]byte(raw), result)
+}
+
+func TestRunPluginValidators(t *testing.T) {
+	host := &scriptedHost{results: map[string]string{
+		PluginMethodValidate: `{"issues": [
+			{"severity": "error", "code": "missing_owner", "title": "Payments has no owner", "affected": ["payments"]},
+			{"severity": "notice", "code": "style", "title": "Odd severity"}
+		]}`,
+	}}
+	plugins := []*entities.PluginManifest{
+		{Name: "ownership", Capabilities: []entities.PluginCapability{entities.PluginValidator}},
+		{Name: "structurizr", Capabilities: []entities.PluginCapability{entities.PluginExporter}},
+	}
+	project := &entities.Project{Name: "acme"}
+	systems := []*entities.System{{ID: "payments", Name: "Payments"}}
+
+	report := &ArchitectureReport{}
+	if err := NewRunPluginValidators(host).Execute(context.Background(), plugins, project, systems, report); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if strings.Join(host.calls, ",") != "ownership.validate" {
+		t.Errorf("calls = %v, want only the validator", host.calls)
+	}
+	model := host.params[0]["model"].(map[string]any)
+	if model["name"] != "acme" || len(model["systems"].([]any)) != 1 {
+		t.Errorf("model = %v", model)
+	}
+	if report.Total != 2 || report.Errors != 1 || report.Warnings != 1 || report.IsValid {
+		t.Errorf("report counts = %+v", report)
+	}
+	if report.Issues[0].Code != "ownership/missing_owner" || report.Issues[1].Severity != "warning" {
+		t.Errorf("issues = %+v", report.Issues)
+	}
+}
+
+func TestExportAndImportWithPlugin(t *testing.T) {
+	host := &scriptedHost{results: map[string]string{
+		PluginMethodExport: `{"files": ["out/workspace.dsl"]}`,
+		PluginMethodImport: `{"files": ["src/payments/system.md"]}`,
+	}}
+	plugin := &entities.PluginManifest{Name: "structurizr", Capabilities: []entities.PluginCapability{entities.PluginExporter}}
+	ctx := context.Background()
+
+	files, err := NewExportWithPlugin(host).Execute(ctx, plugin, &entities.Project{Name: "acme"}, nil, "out", map[string]string{"theme": "dark"})
+	if err != nil || len(files) != 1 || files[0] != "out/workspace.dsl" {
+		t.Errorf("export = %v, %v", files, err)
+	}
+	if host.params[0]["outputDir"] != "out" || host.params[0]["options"].(map[string]any)["theme"] != "dark" {
+		t.Errorf("export params = %v", host.params[0])
+	}
+
+	if _, err := NewImportWithPlugin(host).Execute(ctx, plugin, "/p", "/p/src", nil); err == nil {
+		t.Error("expected error importing with a plugin that is not an importer")
+	}
+	plugin.Capabilities = append(plugin.Capabilities, entities.PluginImporter)
+	files, err = NewImportWithPlugin(host).Execute(ctx, plugin, "/p", "/p/src", []string{"--workspace", "w.json"})
+	if err != nil || len(files) != 1 {
+		t.Errorf("import = %v, %v", files, err)
+	}
+}
+
+func TestFindPlugin(t *testing.T) {
+	plugins := []*entities.PluginManifest{
+		{Name: "structurizr", Capabilities: []entities.PluginCapability{entities.PluginExporter}},
+		{Name: "ownership", Capabilities: []entities.PluginCapability{entities.PluginValidator}},
+	}
+	if plugin, err := FindPlugin(plugins, "structurizr", entities.PluginExporter); err != nil || plugin.Name != "structurizr" {
+		t.Errorf("FindPlugin = %v, %v", plugin, err)
+	}
+	if _, err := FindPlugin(plugins, "ownership", entities.PluginExporter); err == nil {
+		t.Error("expected error for a plugin without the capability")
+	}
+	if _, err := FindPlugin(plugins, "backstage", entities.PluginExporter); err == nil || !strings.Contains(err.Error(), "structurizr") {
+		t.Errorf("expected error listing installed exporters, got %v", err)
+	}
+}
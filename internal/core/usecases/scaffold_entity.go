@@ -5,21 +5,26 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
 )
 
 // ScaffoldEntityRequest defines the input for the ScaffoldEntity use case.
 type ScaffoldEntityRequest struct {
-	ProjectRoot     string   // filesystem path to project
-	EntityType      string   // "system" | "container" | "component"
-	ParentPath      []string // hierarchy path: [] for system, [system] for container, [system, container] for component
-	Name            string   // entity display name
-	Description     string   // optional description
-	Technology      string   // optional technology string
-	Tags            []string // optional tags
-	Template        string   // template name (empty = use project default)
-	ContentTemplate string   // T055: technology-specific component content template (e.g. "compute", "datastore")
+	ProjectRoot     string            // filesystem path to project
+	EntityType      string            // "person" | "system" | "container" | "component"
+	ParentPath      []string          // hierarchy path: [] for system, [system] for container, [system, container] for component, [system, container, component] for sub-component
+	Domain          string            // optional dot-separated domain of a system, e.g. "payments.cards"
+	Name            string            // entity display name
+	Description     string            // optional description
+	Technology      string            // optional technology string
+	Tags            []string          // optional tags
+	Template        string            // template name (empty = use project default)
+	ContentTemplate string            // T055: technology-specific component content template (e.g. "compute", "datastore")
+	DiagramSource   string            // optional D2 source of a container's diagram, e.g. from an archetype, written instead of a generated one
+	External        bool              // person only: outside the organization, e.g. a customer
+	Relationships   map[string]string // person only: elements the person uses, with descriptions
 }
 
 // ScaffoldEntityResult defines the output of the ScaffoldEntity use case.
@@ -34,6 +39,7 @@ type ScaffoldEntity struct {
 	projectRepo      ProjectRepository
 	templateEngine   TemplateEngine
 	diagramGenerator DiagramGenerator
+	personRepo       PersonRepository
 	logger           Logger
 }
 
@@ -54,6 +60,14 @@ func WithDiagramGenerator(dg DiagramGenerator) ScaffoldEntityOption {
 	}
 }
 
+// WithPersonRepository sets the repository people are saved to, needed to
+// scaffold the entity type "person".
+func WithPersonRepository(repo PersonRepository) ScaffoldEntityOption {
+	return func(s *ScaffoldEntity) {
+		s.personRepo = repo
+	}
+}
+
 // WithLogger sets the optional logger.
 func WithLogger(l Logger) ScaffoldEntityOption {
 	return func(s *ScaffoldEntity) {
@@ -93,6 +107,10 @@ func (uc *ScaffoldEntity) Execute(ctx context.Context, req *ScaffoldEntityReques
 	}
 
 	switch req.EntityType {
+	case "person":
+		if err := uc.scaffoldPerson(ctx, req, project, result); err != nil {
+			return nil, err
+		}
 	case "system":
 		if err := uc.scaffoldSystem(ctx, req, project, result); err != nil {
 			return nil, err
@@ -109,8 +127,8 @@ func (uc *ScaffoldEntity) Execute(ctx context.Context, req *ScaffoldEntityReques
 		return nil, fmt.Errorf("unknown entity type: %s", req.EntityType)
 	}
 
-	// Optionally render templates
-	if uc.templateEngine != nil && req.Template != "" {
+	// Optionally render templates; people have none
+	if uc.templateEngine != nil && req.Template != "" && req.EntityType != "person" {
 		if err := uc.renderTemplates(ctx, req, result); err != nil {
 			return nil, fmt.Errorf("failed to render templates: %w", err)
 		}
@@ -123,12 +141,62 @@ func (uc *ScaffoldEntity) Execute(ctx context.Context, req *ScaffoldEntityReques
 	return result, nil
 }
 
+func (uc *ScaffoldEntity) scaffoldPerson(ctx context.Context, req *ScaffoldEntityRequest, project *entities.Project, result *ScaffoldEntityResult) error {
+	if uc.personRepo == nil {
+		return fmt.Errorf("cannot create people: no person repository configured")
+	}
+
+	// Create person entity
+	person, err := entities.NewPerson(req.Name)
+	if err != nil {
+		return fmt.Errorf("failed to create person: %w", err)
+	}
+
+	// Set optional fields
+	person.Description = req.Description
+	person.External = req.External
+	if len(req.Tags) > 0 {
+		person.Tags = req.Tags
+	}
+	for target, description := range req.Relationships {
+		person.AddRelationship(target, description)
+	}
+
+	// People and systems share the top level of the source directory
+	if _, exists := project.Systems[person.ID]; exists {
+		return fmt.Errorf("failed to add person to project: a system with ID %q already exists", person.ID)
+	}
+	if err := project.AddPerson(person); err != nil {
+		return fmt.Errorf("failed to add person to project: %w", err)
+	}
+
+	// Save person
+	if err := uc.personRepo.SavePerson(ctx, req.ProjectRoot, person); err != nil {
+		return fmt.Errorf("failed to save person: %w", err)
+	}
+
+	result.EntityID = person.ID
+	result.FilesCreated = append(result.FilesCreated, filepath.Join(person.Path, "person.md"))
+
+	return nil
+}
+
 func (uc *ScaffoldEntity) scaffoldSystem(ctx context.Context, req *ScaffoldEntityRequest, project *entities.Project, result *ScaffoldEntityResult) error {
 	// Create system entity
 	system, err := entities.NewSystem(req.Name)
 	if err != nil {
 		return fmt.Errorf("failed to create system: %w", err)
 	}
+	if req.Domain != "" {
+		segments := strings.Split(req.Domain, entities.DomainSeparator)
+		for i, segment := range segments {
+			segments[i] = entities.NormalizeName(segment)
+		}
+		system.SetDomain(strings.Join(segments, entities.DomainSeparator))
+		if err := system.Validate(); err != nil {
+			return fmt.Errorf("invalid domain %q: %w", req.Domain, err)
+		}
+	}
 
 	// Set optional fields
 	system.Description = req.Description
@@ -137,7 +205,7 @@ func (uc *ScaffoldEntity) scaffoldSystem(ctx context.Context, req *ScaffoldEntit
 	}
 
 	// Set path
-	system.Path = filepath.Join(req.ProjectRoot, project.Config.SourceDir, system.ID)
+	system.Path = filepath.Join(req.ProjectRoot, project.Config.SourceDir, filepath.FromSlash(entities.SystemSourcePath(system.ID)))
 
 	// Add to project
 	if err := project.AddSystem(system); err != nil {
@@ -218,11 +286,14 @@ func (uc *ScaffoldEntity) scaffoldContainer(ctx context.Context, req *ScaffoldEn
 	result.EntityID = container.ID
 	result.FilesCreated = append(result.FilesCreated, filepath.Join(container.Path, "container.toml"))
 
-	// Generate container diagram (shows all containers in system)
-	if uc.diagramGenerator != nil {
-		d2Source, err := uc.diagramGenerator.GenerateContainerDiagram(system)
-		if err != nil {
-			return fmt.Errorf("failed to generate container diagram: %w", err)
+	// Generate container diagram (shows all containers in system), unless
+	// the request provides one
+	if d2Source := req.DiagramSource; d2Source != "" || uc.diagramGenerator != nil {
+		if d2Source == "" {
+			d2Source, err = uc.diagramGenerator.GenerateContainerDiagram(system)
+			if err != nil {
+				return fmt.Errorf("failed to generate container diagram: %w", err)
+			}
 		}
 
 		d2Path := filepath.Join(container.Path, "container.d2")
@@ -265,6 +336,17 @@ func (uc *ScaffoldEntity) scaffoldComponent(ctx context.Context, req *ScaffoldEn
 	if err != nil {
 		return fmt.Errorf("failed to create component: %w", err)
 	}
+	if len(req.ParentPath) > 2 {
+		parentID := entities.NormalizeName(req.ParentPath[2])
+		parent, ok := container.Components[parentID]
+		if !ok {
+			return fmt.Errorf("component %s not found in container %s", parentID, containerID)
+		}
+		if parent.Depth() >= entities.MaxComponentNesting {
+			return fmt.Errorf("component %s is a sub-component: components nest at most %d level(s)", parentID, entities.MaxComponentNesting)
+		}
+		component.SetParent(parent.ID)
+	}
 
 	// Set optional fields
 	component.Description = req.Description
@@ -280,7 +362,7 @@ func (uc *ScaffoldEntity) scaffoldComponent(ctx context.Context, req *ScaffoldEn
 	}
 
 	// Set path
-	component.Path = filepath.Join(container.Path, component.ID)
+	component.Path = filepath.Join(container.Path, filepath.FromSlash(entities.ComponentSourcePath(component.ID)))
 
 	// Add to container
 	if err := container.AddComponent(component); err != nil {
@@ -2,6 +2,8 @@ package usecases
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
@@ -110,6 +112,21 @@ func (m *mockLogger) WithFields(keysAndValues ...any) Logger {
 	return m
 }
 
+// mockPersonRepository is a test double for PersonRepository.
+type mockPersonRepository struct {
+	saved []*entities.Person
+}
+
+func (m *mockPersonRepository) ListPeople(ctx context.Context, projectRoot string) ([]*entities.Person, error) {
+	return m.saved, nil
+}
+
+func (m *mockPersonRepository) SavePerson(ctx context.Context, projectRoot string, person *entities.Person) error {
+	person.Path = filepath.Join(projectRoot, "src", person.ID)
+	m.saved = append(m.saved, person)
+	return nil
+}
+
 // TestNewScaffoldEntity tests creating a ScaffoldEntity use case.
 func TestNewScaffoldEntity(t *testing.T) {
 	mockRepo := &MockProjectRepository{}
@@ -209,6 +226,89 @@ func TestScaffoldEntityExecuteSystem(t *testing.T) {
 	}
 }
 
+// TestScaffoldEntityExecutePerson tests scaffolding a person, which fails
+// without a person repository and when a system has its ID.
+func TestScaffoldEntityExecutePerson(t *testing.T) {
+	project, _ := entities.NewProject("test-project")
+	shop, _ := entities.NewSystem("Shop")
+	_ = project.AddSystem(shop)
+	mockRepo := &MockProjectRepository{}
+	mockRepo.LoadProjectFunc = func(ctx context.Context, projectRoot string) (*entities.Project, error) {
+		return project, nil
+	}
+	req := &ScaffoldEntityRequest{
+		ProjectRoot:   "/test/project",
+		EntityType:    "person",
+		Name:          "Customer",
+		External:      true,
+		Relationships: map[string]string{"shop": "Places orders with"},
+	}
+
+	if _, err := NewScaffoldEntity(mockRepo).Execute(context.Background(), req); err == nil {
+		t.Error("Execute() without a person repository should fail")
+	}
+
+	people := &mockPersonRepository{}
+	uc := NewScaffoldEntity(mockRepo, WithPersonRepository(people))
+	result, err := uc.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.EntityID != "customer" || len(people.saved) != 1 {
+		t.Fatalf("result = %+v, saved = %v", result, people.saved)
+	}
+	if person := people.saved[0]; !person.External || !person.Uses("shop") {
+		t.Errorf("saved person = %+v", person)
+	}
+
+	req.Name = "Shop"
+	if _, err := uc.Execute(context.Background(), req); err == nil {
+		t.Error("Execute() of a person with a system's ID should fail")
+	}
+}
+
+// TestScaffoldEntityExecuteSystemInDomain tests scaffolding a system in a
+// nested domain directory.
+func TestScaffoldEntityExecuteSystemInDomain(t *testing.T) {
+	project, _ := entities.NewProject("test-project")
+	project.Config.SourceDir = "src"
+	var saved *entities.System
+	mockRepo := &MockProjectRepository{}
+	mockRepo.LoadProjectFunc = func(ctx context.Context, projectRoot string) (*entities.Project, error) {
+		return project, nil
+	}
+	mockRepo.SaveSystemFunc = func(ctx context.Context, projectRoot string, system *entities.System) error {
+		saved = system
+		return nil
+	}
+
+	uc := NewScaffoldEntity(mockRepo)
+	result, err := uc.Execute(context.Background(), &ScaffoldEntityRequest{
+		ProjectRoot: "/test/project",
+		EntityType:  "system",
+		Name:        "Billing",
+		Domain:      "Payments.Cards",
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.EntityID != "payments.cards.billing" || saved.Domain != "payments.cards" {
+		t.Errorf("system = %q in %q, want payments.cards.billing in payments.cards", result.EntityID, saved.Domain)
+	}
+	if want := filepath.Join("/test/project", "src", "payments", "cards", "billing"); saved.Path != want {
+		t.Errorf("system path = %q, want %q", saved.Path, want)
+	}
+
+	if _, err := uc.Execute(context.Background(), &ScaffoldEntityRequest{
+		ProjectRoot: "/test/project",
+		EntityType:  "system",
+		Name:        "Ledger",
+		Domain:      "payments..cards",
+	}); err == nil {
+		t.Error("Execute() with an empty domain segment succeeded, want error")
+	}
+}
+
 // TestScaffoldEntityExecuteContainer tests scaffolding a container.
 func TestScaffoldEntityExecuteContainer(t *testing.T) {
 	project, _ := entities.NewProject("test-project")
@@ -254,6 +354,47 @@ func TestScaffoldEntityExecuteContainer(t *testing.T) {
 	}
 }
 
+// TestScaffoldEntityContainerDiagramSource tests that a container's diagram
+// source, e.g. from an archetype, replaces the generated diagram.
+func TestScaffoldEntityContainerDiagramSource(t *testing.T) {
+	project, _ := entities.NewProject("test-project")
+	system, _ := entities.NewSystem("Payment Service")
+	system.Path = t.TempDir()
+
+	mockRepo := &MockProjectRepository{}
+	mockRepo.LoadProjectFunc = func(ctx context.Context, projectRoot string) (*entities.Project, error) {
+		return project, nil
+	}
+	mockRepo.LoadSystemFunc = func(ctx context.Context, projectRoot, systemName string) (*entities.System, error) {
+		return system, nil
+	}
+	generator := &mockDiagramGenerator{}
+	generator.generateContainerDiagramFunc = func(system *entities.System) (string, error) {
+		t.Error("expected the diagram source to be used instead of a generated diagram")
+		return "", nil
+	}
+
+	uc := NewScaffoldEntity(mockRepo, WithDiagramGenerator(generator))
+	result, err := uc.Execute(context.Background(), &ScaffoldEntityRequest{
+		ProjectRoot:   "/test/project",
+		EntityType:    "container",
+		ParentPath:    []string{"Payment Service"},
+		Name:          "Orders",
+		DiagramSource: "handler -> repository\n",
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	content, err := os.ReadFile(result.DiagramPath)
+	if err != nil {
+		t.Fatalf("failed to read diagram: %v", err)
+	}
+	if string(content) != "handler -> repository\n" {
+		t.Errorf("diagram = %q", content)
+	}
+}
+
 // TestScaffoldEntityExecuteComponent tests scaffolding a component.
 func TestScaffoldEntityExecuteComponent(t *testing.T) {
 	project, _ := entities.NewProject("test-project")
@@ -301,6 +442,55 @@ func TestScaffoldEntityExecuteComponent(t *testing.T) {
 	}
 }
 
+// TestScaffoldEntityExecuteSubComponent tests scaffolding a component inside
+// another component, and that sub-components cannot nest further.
+func TestScaffoldEntityExecuteSubComponent(t *testing.T) {
+	project, _ := entities.NewProject("test-project")
+	system, _ := entities.NewSystem("Payment Service")
+	container, _ := entities.NewContainer("API Server")
+	container.Path = "/test/project/src/payment-service/api-server"
+	system.AddContainer(container)
+	handler, _ := entities.NewComponent("Handler")
+	container.AddComponent(handler)
+
+	mockRepo := &MockProjectRepository{}
+	mockRepo.LoadProjectFunc = func(ctx context.Context, projectRoot string) (*entities.Project, error) {
+		return project, nil
+	}
+	mockRepo.LoadSystemFunc = func(ctx context.Context, projectRoot, systemName string) (*entities.System, error) {
+		return system, nil
+	}
+
+	uc := NewScaffoldEntity(mockRepo)
+	scaffold := func(parent string) (*ScaffoldEntityResult, error) {
+		return uc.Execute(context.Background(), &ScaffoldEntityRequest{
+			ProjectRoot: "/test/project",
+			EntityType:  "component",
+			ParentPath:  []string{"Payment Service", "API Server", parent},
+			Name:        "Validator",
+		})
+	}
+
+	result, err := scaffold("Handler")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.EntityID != "handler.validator" {
+		t.Errorf("expected entity ID 'handler.validator', got %q", result.EntityID)
+	}
+	validator := container.Components["handler.validator"]
+	if want := filepath.Join(container.Path, "handler", "validator"); validator == nil || validator.Path != want {
+		t.Errorf("sub-component = %+v, want path %s", validator, want)
+	}
+
+	if _, err := scaffold("handler.validator"); err == nil {
+		t.Error("Execute() nesting in a sub-component succeeded, want error")
+	}
+	if _, err := scaffold("missing"); err == nil {
+		t.Error("Execute() with an unknown parent component succeeded, want error")
+	}
+}
+
 // TestScaffoldEntityWithTemplate tests scaffolding with template rendering.
 func TestScaffoldEntityWithTemplate(t *testing.T) {
 	project, _ := entities.NewProject("test-project")
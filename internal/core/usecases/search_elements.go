@@ -2,13 +2,17 @@ package usecases
 
 import (
 	"context"
+	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
 )
 
 // SearchElements searches for architecture elements matching the given criteria.
-// This use case supports filtering by name pattern (glob), type, technology, and tags.
+// This use case supports filtering by name pattern (glob), type, technology,
+// tags and custom frontmatter fields.
 type SearchElements struct {
 	repo       ProjectRepository
 	buildGraph *BuildArchitectureGraph
@@ -46,7 +50,8 @@ func (uc *SearchElements) Execute(ctx context.Context, req entities.SearchElemen
 	if req.Type == "" || req.Type == "system" {
 		for _, sys := range systems {
 			qualifiedID := sys.Name // Systems use their name as ID
-			if uc.matchesElement(matcher, qualifiedID, sys.Name, "system", sys.Description, "", sys.Tags, req) {
+			if uc.matchesElement(matcher, qualifiedID, sys.Name, "system", sys.Description, "", sys.Tags, req) &&
+				matchesMetadata(sys.Metadata, req.Metadata) {
 				totalMatched++
 				if len(results) < req.Limit {
 					results = append(results, entities.SearchElement{
@@ -56,6 +61,7 @@ func (uc *SearchElements) Execute(ctx context.Context, req entities.SearchElemen
 						Description: sys.Description,
 						Technology:  "",
 						Tags:        sys.Tags,
+						Metadata:    sys.Metadata,
 						ParentID:    "",
 					})
 				}
@@ -66,9 +72,10 @@ func (uc *SearchElements) Execute(ctx context.Context, req entities.SearchElemen
 	// Search containers
 	if req.Type == "" || req.Type == "container" {
 		for _, sys := range systems {
-			for _, cont := range sys.Containers {
+			for _, cont := range sys.ListContainers() {
 				qualifiedID := sys.Name + "/" + cont.Name
-				if uc.matchesElement(matcher, qualifiedID, cont.Name, "container", cont.Description, cont.Technology, cont.Tags, req) {
+				if uc.matchesElement(matcher, qualifiedID, cont.Name, "container", cont.Description, cont.Technology, cont.Tags, req) &&
+					matchesMetadata(cont.Metadata, req.Metadata) {
 					totalMatched++
 					if len(results) < req.Limit {
 						results = append(results, entities.SearchElement{
@@ -78,6 +85,7 @@ func (uc *SearchElements) Execute(ctx context.Context, req entities.SearchElemen
 							Description: cont.Description,
 							Technology:  cont.Technology,
 							Tags:        cont.Tags,
+							Metadata:    cont.Metadata,
 							ParentID:    sys.Name,
 						})
 					}
@@ -89,10 +97,16 @@ func (uc *SearchElements) Execute(ctx context.Context, req entities.SearchElemen
 	// Search components
 	if req.Type == "" || req.Type == "component" {
 		for _, sys := range systems {
-			for _, cont := range sys.Containers {
-				for _, comp := range cont.Components {
-					qualifiedID := sys.Name + "/" + cont.Name + "/" + comp.Name
-					if uc.matchesElement(matcher, qualifiedID, comp.Name, "component", comp.Description, comp.Technology, comp.Tags, req) {
+			for _, cont := range sys.ListContainers() {
+				for _, comp := range cont.ListComponents() {
+					// Sub-components are found under their parent component
+					parentID := sys.Name + "/" + cont.Name
+					if parent := cont.Components[comp.Parent]; parent != nil {
+						parentID += "/" + parent.Name
+					}
+					qualifiedID := parentID + "/" + comp.Name
+					if uc.matchesElement(matcher, qualifiedID, comp.Name, "component", comp.Description, comp.Technology, comp.Tags, req) &&
+						matchesMetadata(comp.Metadata, req.Metadata) {
 						totalMatched++
 						if len(results) < req.Limit {
 							results = append(results, entities.SearchElement{
@@ -102,7 +116,8 @@ func (uc *SearchElements) Execute(ctx context.Context, req entities.SearchElemen
 								Description: comp.Description,
 								Technology:  comp.Technology,
 								Tags:        comp.Tags,
-								ParentID:    sys.Name + "/" + cont.Name,
+								Metadata:    comp.Metadata,
+								ParentID:    parentID,
 							})
 						}
 					}
@@ -159,6 +174,44 @@ func (uc *SearchElements) matchesElement(
 	return true
 }
 
+// matchesMetadata reports whether metadata has every filtered value. List
+// fields match when any item does; comparison is case-insensitive.
+func matchesMetadata(metadata map[string]any, filters map[string]string) bool {
+	for key, want := range filters {
+		switch value := metadata[key].(type) {
+		case nil:
+			return false
+		case []string:
+			if !containsFold(value, want) {
+				return false
+			}
+		case []any:
+			items := make([]string, len(value))
+			for i, item := range value {
+				items[i] = fmt.Sprint(item)
+			}
+			if !containsFold(items, want) {
+				return false
+			}
+		default:
+			if !strings.EqualFold(fmt.Sprint(value), want) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// containsFold reports whether items contains s, ignoring case.
+func containsFold(items []string, s string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
 // buildMessage creates a helpful message about the search results.
 func (uc *SearchElements) buildMessage(totalMatched, returned int, req entities.SearchElementsRequest) string {
 	if totalMatched == 0 {
@@ -179,6 +232,9 @@ func (uc *SearchElements) buildMessage(totalMatched, returned int, req entities.
 	if req.Tag != "" {
 		filters = append(filters, "tag="+req.Tag)
 	}
+	for _, key := range slices.Sorted(maps.Keys(req.Metadata)) {
+		filters = append(filters, "meta."+key+"="+req.Metadata[key])
+	}
 
 	if len(filters) > 0 {
 		return formatMessage("Found %d elements matching '%s' with filters: %s", totalMatched, req.Query, strings.Join(filters, ", "))
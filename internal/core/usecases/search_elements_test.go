@@ -51,6 +51,7 @@ func TestSearchElementsExecute(t *testing.T) {
 	comp2.Description = "Processes payments"
 	comp2.Technology = "Go"
 	comp2.Tags = []string{"finance", "core"}
+	comp2.Metadata = map[string]any{"compliance": []string{"PCI", "sox"}, "tier": 1}
 
 	// Build hierarchy
 	cont1.AddComponent(comp1)
@@ -94,6 +95,44 @@ func TestSearchElementsExecute(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "search by metadata in query",
+			request: entities.SearchElementsRequest{
+				ProjectRoot: "/test/project",
+				Query:       "meta.compliance=pci meta.tier=1",
+			},
+			setupMocks: func(m *MockProjectRepository) {
+				m.ListSystemsFunc = func(ctx context.Context, projectRoot string) ([]*entities.System, error) {
+					return []*entities.System{sys1}, nil
+				}
+			},
+			validate: func(t *testing.T, result *entities.SearchElementsResponse) {
+				if result.TotalMatched != 1 || result.Results[0].Name != "Payment Processor" {
+					t.Fatalf("expected only Payment Processor, got %+v", result.Results)
+				}
+				if result.Results[0].Metadata["tier"] != 1 {
+					t.Errorf("metadata = %v", result.Results[0].Metadata)
+				}
+			},
+		},
+		{
+			name: "search by missing metadata",
+			request: entities.SearchElementsRequest{
+				ProjectRoot: "/test/project",
+				Query:       "payment*",
+				Metadata:    map[string]string{"compliance": "hipaa"},
+			},
+			setupMocks: func(m *MockProjectRepository) {
+				m.ListSystemsFunc = func(ctx context.Context, projectRoot string) ([]*entities.System, error) {
+					return []*entities.System{sys1}, nil
+				}
+			},
+			validate: func(t *testing.T, result *entities.SearchElementsResponse) {
+				if result.TotalMatched != 0 {
+					t.Errorf("expected no matches, got %+v", result.Results)
+				}
+			},
+		},
 		{
 			name: "search by name pattern",
 			request: entities.SearchElementsRequest{
@@ -253,6 +292,37 @@ func TestSearchElementsExecute(t *testing.T) {
 	}
 }
 
+// TestSearchElementsSubComponents tests that sub-components are found under
+// their parent component.
+func TestSearchElementsSubComponents(t *testing.T) {
+	sys, _ := entities.NewSystem("Shop")
+	api, _ := entities.NewContainer("API")
+	handler, _ := entities.NewComponent("Handler")
+	validator, _ := entities.NewComponent("Validator")
+	validator.SetParent(handler.ID)
+	api.AddComponent(handler)
+	api.AddComponent(validator)
+	sys.AddContainer(api)
+
+	mockRepo := &MockProjectRepository{}
+	mockRepo.ListSystemsFunc = func(ctx context.Context, projectRoot string) ([]*entities.System, error) {
+		return []*entities.System{sys}, nil
+	}
+	result, err := NewSearchElements(mockRepo).Execute(context.Background(), entities.SearchElementsRequest{
+		ProjectRoot: "/test/project",
+		Query:       "valid*",
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(result.Results) != 1 {
+		t.Fatalf("expected only Validator, got %+v", result.Results)
+	}
+	if got := result.Results[0]; got.ID != "Shop/API/Handler/Validator" || got.ParentID != "Shop/API/Handler" {
+		t.Errorf("result ID, ParentID = %q, %q, want Shop/API/Handler/Validator, Shop/API/Handler", got.ID, got.ParentID)
+	}
+}
+
 // TestSearchElementsMatchesElement tests the matchesElement helper function.
 func TestSearchElementsMatchesElement(t *testing.T) {
 	uc := NewSearchElements(&MockProjectRepository{})
@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// SourceHistory is when the files in one element directory were first and
+// last changed, and by whom, according to version control.
+type SourceHistory struct {
+	Created time.Time
+	Updated time.Time
+	Author  string
+}
+
+// HistoryByDirectory summarizes revisions, oldest first, by the directory of
+// each changed file. Keys are slash-separated and relative to the project root.
+func HistoryByDirectory(revisions []Revision) map[string]SourceHistory {
+	history := make(map[string]SourceHistory)
+	for _, rev := range revisions {
+		for _, change := range rev.Changes {
+			dir := path.Dir(change.Path)
+			h, seen := history[dir]
+			if !seen {
+				h.Created = rev.Date
+			}
+			h.Updated = rev.Date
+			h.Author = rev.Author
+			history[dir] = h
+		}
+	}
+	return history
+}
+
+// ApplySourceHistory sets CreatedAt, UpdatedAt and Author on every element
+// whose directory has history. Elements without history are left unchanged.
+func ApplySourceHistory(projectRoot string, systems []*entities.System, history map[string]SourceHistory) {
+	lookup := func(dir string) (SourceHistory, bool) {
+		rel, err := filepath.Rel(projectRoot, dir)
+		if dir == "" || err != nil {
+			return SourceHistory{}, false
+		}
+		h, ok := history[filepath.ToSlash(rel)]
+		return h, ok
+	}
+
+	for _, sys := range systems {
+		if h, ok := lookup(sys.Path); ok {
+			sys.CreatedAt, sys.UpdatedAt, sys.Author = h.Created, h.Updated, h.Author
+		}
+		for _, cont := range sys.Containers {
+			if h, ok := lookup(cont.Path); ok {
+				cont.CreatedAt, cont.UpdatedAt, cont.Author = h.Created, h.Updated, h.Author
+			}
+			for _, comp := range cont.Components {
+				if h, ok := lookup(comp.Path); ok {
+					comp.CreatedAt, comp.UpdatedAt, comp.Author = h.Created, h.Updated, h.Author
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package usecases
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestApplySourceHistory(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	revisions := []Revision{
+		{Author: "ana", Date: day(1), Changes: []FileRevision{
+			{Path: "src/payments/system.md", Status: "added"},
+			{Path: "src/payments/api/container.md", Status: "added"},
+		}},
+		{Author: "ben", Date: day(4), Changes: []FileRevision{
+			{Path: "src/payments/api/api.d2", Status: "modified"},
+		}},
+	}
+
+	root := filepath.FromSlash("/work/project")
+	payments := &entities.System{Name: "Payments", Path: filepath.Join(root, "src", "payments")}
+	api := &entities.Container{Name: "API", Path: filepath.Join(root, "src", "payments", "api")}
+	ledger := &entities.Component{Name: "Ledger", Path: filepath.Join(root, "src", "payments", "api", "ledger"), UpdatedAt: day(2)}
+	api.Components = map[string]*entities.Component{"ledger": ledger}
+	payments.Containers = map[string]*entities.Container{"api": api}
+
+	ApplySourceHistory(root, []*entities.System{payments}, HistoryByDirectory(revisions))
+
+	if !payments.CreatedAt.Equal(day(1)) || !payments.UpdatedAt.Equal(day(1)) || payments.Author != "ana" {
+		t.Errorf("system = %v/%v/%q", payments.CreatedAt, payments.UpdatedAt, payments.Author)
+	}
+	if !api.CreatedAt.Equal(day(1)) || !api.UpdatedAt.Equal(day(4)) || api.Author != "ben" {
+		t.Errorf("container = %v/%v/%q", api.CreatedAt, api.UpdatedAt, api.Author)
+	}
+	if !ledger.CreatedAt.IsZero() || !ledger.UpdatedAt.Equal(day(2)) || ledger.Author != "" {
+		t.Errorf("component without history = %v/%v/%q", ledger.CreatedAt, ledger.UpdatedAt, ledger.Author)
+	}
+}
@@ -0,0 +1,397 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// SplitSystem extracts a system into a new standalone project. The system's
+// directory, with its containers, components, diagrams and relationships,
+// moves to the new project, and the original keeps an external system stub
+// whose project_path points at it. References to the system's containers and components from
+// the rest of the original are pointed at the stub; in the new project,
+// references to the systems left behind are pointed at external stubs of
+// them that point back.
+type SplitSystem struct {
+	repo          ProjectRepository
+	editor        FrontmatterEditor
+	relationships RelationshipRepository // Optional: relationships.toml is not rewritten without it
+	trash         Trash                  // Optional: the system's directory is removed permanently without it
+}
+
+// SplitPlan describes the edits of a split.
+type SplitPlan struct {
+	System   string   // ID of the system extracted
+	Target   string   // Root directory of the new project
+	Inbound  []string // Components of the original whose relationships are pointed at the stub
+	Outbound []string // Components of the system whose relationships are pointed at stubs in the new project
+	Stubs    []string // Systems added to the new project as external stubs
+	Changes  []string // Human-readable list of the edits
+
+	// RelationshipFiles are the systems whose relationships.toml entries
+	// cross the split; the extracted system's own file is rewritten in the
+	// new project.
+	RelationshipFiles []string
+
+	project  *entities.Project
+	root     string // Absolute root of the original project
+	system   *entities.System
+	systems  []*entities.System
+	inbound  map[string]*inboundRewrite // By qualified ID
+	outbound map[string]*inboundRewrite // By qualified ID; paths in the original project
+}
+
+// NewSplitSystem creates a SplitSystem use case.
+func NewSplitSystem(repo ProjectRepository, editor FrontmatterEditor, relationships RelationshipRepository) *SplitSystem {
+	return &SplitSystem{repo: repo, editor: editor, relationships: relationships}
+}
+
+// WithTrash moves the system's directory to trash instead of removing it.
+func (uc *SplitSystem) WithTrash(trash Trash) *SplitSystem {
+	uc.trash = trash
+	return uc
+}
+
+// Plan computes the split of the system systemID of project into a new
+// project at target, which must not exist yet or be empty and must be
+// outside the project's source directory.
+func (uc *SplitSystem) Plan(ctx context.Context, project *entities.Project, systems []*entities.System, systemID, target string) (*SplitPlan, error) {
+	var system *entities.System
+	for _, s := range systems {
+		if s != nil && s.ID == systemID {
+			system = s
+		}
+	}
+	switch {
+	case system == nil:
+		return nil, fmt.Errorf("system %q not found", systemID)
+	case system.External:
+		return nil, fmt.Errorf("system %s is external; only systems modeled in this project can be split", systemID)
+	case system.Path == "" || project.Path == "":
+		return nil, fmt.Errorf("a system loaded from a project is required")
+	}
+
+	target, err := filepath.Abs(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target %q: %w", target, err)
+	}
+	root, err := filepath.Abs(project.Path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project path %q: %w", project.Path, err)
+	}
+	if entries, err := os.ReadDir(target); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("%s already exists and is not empty", target)
+	}
+	sourceDir := filepath.Join(root, project.SourceDir())
+	if rel, err := filepath.Rel(sourceDir, target); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("%s is inside the source directory of the project", target)
+	}
+
+	plan := &SplitPlan{
+		System:   system.ID,
+		Target:   target,
+		project:  project,
+		root:     root,
+		system:   system,
+		systems:  systems,
+		inbound:  make(map[string]*inboundRewrite),
+		outbound: make(map[string]*inboundRewrite),
+	}
+	plan.Changes = append(plan.Changes, fmt.Sprintf("Create a project at %s with system %s", target, system.ID))
+
+	systemIDs := make(map[string]bool)
+	for _, s := range systems {
+		if s != nil {
+			systemIDs[s.ID] = true
+		}
+	}
+	components, shortIDs := indexComponents(systems)
+	stubs := make(map[string]bool)
+	for _, id := range slices.Sorted(maps.Keys(components)) {
+		comp := components[id]
+		if comp.Path == "" {
+			continue
+		}
+		inSystem := strings.HasPrefix(id, system.ID+"/")
+
+		// Relationships crossing the boundary are collapsed to the system on
+		// the other side, which is a stub after the split.
+		rewritten := make(map[string]string, len(comp.Relationships))
+		changed := false
+		for _, target := range slices.Sorted(maps.Keys(comp.Relationships)) {
+			label := comp.Relationships[target]
+			if other := relationshipSystem(target, systemIDs, shortIDs); other != "" && (other == system.ID) != inSystem {
+				if inSystem {
+					stubs[other] = true
+				}
+				if target != other {
+					target, changed = other, true
+				}
+			}
+			if _, ok := rewritten[target]; !ok {
+				rewritten[target] = label
+			}
+		}
+		if !changed {
+			continue
+		}
+		rewrite := &inboundRewrite{path: filepath.Join(comp.Path, "component.md"), relationships: rewritten}
+		if inSystem {
+			plan.outbound[id] = rewrite
+			plan.Outbound = append(plan.Outbound, id)
+			plan.Changes = append(plan.Changes, fmt.Sprintf("Point the relationships of %s outside %s at external systems in the new project", id, system.ID))
+		} else {
+			plan.inbound[id] = rewrite
+			plan.Inbound = append(plan.Inbound, id)
+			plan.Changes = append(plan.Changes, fmt.Sprintf("Point the relationships of %s at the external system %s", id, system.ID))
+		}
+	}
+
+	if uc.relationships != nil {
+		for _, s := range systems {
+			if s == nil {
+				continue
+			}
+			rels, err := uc.relationships.LoadRelationships(ctx, project.Path, s.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load relationships of %s: %w", s.ID, err)
+			}
+			crosses := false
+			for _, rel := range rels {
+				for _, endpoint := range []string{rel.Source, rel.Target} {
+					other := relationshipSystem(endpoint, systemIDs, shortIDs)
+					if other == "" || (other == system.ID) == (s.ID == system.ID) {
+						continue // Unresolved, or on the same side of the split as the file
+					}
+					crosses = true
+					if s.ID == system.ID {
+						stubs[other] = true
+					}
+				}
+			}
+			if crosses {
+				plan.RelationshipFiles = append(plan.RelationshipFiles, s.ID)
+				plan.Changes = append(plan.Changes, fmt.Sprintf("Point the relationships.toml entries of %s across the split at external systems", s.ID))
+			}
+		}
+	}
+
+	plan.Stubs = slices.Sorted(maps.Keys(stubs))
+	for _, id := range plan.Stubs {
+		plan.Changes = append(plan.Changes, fmt.Sprintf("Add %s to the new project as an external system", id))
+	}
+	plan.Changes = append(plan.Changes,
+		fmt.Sprintf("Delete %s", system.Path),
+		fmt.Sprintf("Replace %s with an external system in %s", system.ID, filepath.ToSlash(relativePath(root, target))))
+	return plan, nil
+}
+
+// Apply performs a split returned by Plan: it creates the new project, copies
+// the system into it and adds the external stubs there, then replaces the
+// system in the original project with its stub, moving its directory to the
+// trash when one is configured, and rewrites the references to it.
+func (uc *SplitSystem) Apply(ctx context.Context, plan *SplitPlan) error {
+	project, system := plan.project, plan.system
+
+	newProject, err := entities.NewProject(system.Name)
+	if err != nil {
+		return fmt.Errorf("failed to create the new project: %w", err)
+	}
+	config := *project.Config
+	newProject.Description = system.Description
+	newProject.Config = &config
+	newProject.Path = plan.Target
+	if err := uc.repo.SaveProject(ctx, newProject); err != nil {
+		return fmt.Errorf("failed to create the new project: %w", err)
+	}
+
+	// Save a copy of the system to create its directory in the new project's
+	// layout, then copy the original files over it.
+	copied := *system
+	copied.ContentHash = ""
+	if err := uc.repo.SaveSystem(ctx, plan.Target, &copied); err != nil {
+		return fmt.Errorf("failed to create %s in the new project: %w", system.ID, err)
+	}
+	if err := copySourceTree(system.Path, copied.Path); err != nil {
+		return fmt.Errorf("failed to copy %s: %w", system.ID, err)
+	}
+
+	for _, id := range plan.Outbound {
+		rewrite := plan.outbound[id]
+		rel, err := filepath.Rel(system.Path, rewrite.path)
+		if err != nil {
+			return fmt.Errorf("failed to update %s: %w", id, err)
+		}
+		if err := uc.editor.SetFrontmatterMap(ctx, filepath.Join(copied.Path, rel), "relationships", rewrite.relationships); err != nil {
+			return fmt.Errorf("failed to update %s: %w", id, err)
+		}
+	}
+	for _, s := range plan.systems {
+		if s != nil && slices.Contains(plan.Stubs, s.ID) {
+			if err := uc.repo.SaveSystem(ctx, plan.Target, externalStub(s, relativePath(plan.Target, plan.root))); err != nil {
+				return fmt.Errorf("failed to add %s to the new project: %w", s.ID, err)
+			}
+		}
+	}
+
+	if uc.relationships != nil && slices.Contains(plan.RelationshipFiles, system.ID) {
+		if err := uc.rewriteRelationships(ctx, plan.Target, system.ID, plan, true); err != nil {
+			return err
+		}
+	}
+
+	if uc.trash != nil {
+		dir, err := filepath.Rel(project.Path, system.Path)
+		if err != nil {
+			return fmt.Errorf("failed to delete %s: %w", system.ID, err)
+		}
+		if _, err := uc.trash.Move(ctx, []string{dir}, fmt.Sprintf("split %s to %s", system.ID, plan.Target)); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", system.ID, err)
+		}
+	} else if err := os.RemoveAll(system.Path); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", system.ID, err)
+	}
+	if err := uc.repo.SaveSystem(ctx, project.Path, externalStub(system, relativePath(plan.root, plan.Target))); err != nil {
+		return fmt.Errorf("failed to add the external system %s: %w", system.ID, err)
+	}
+
+	for _, id := range plan.Inbound {
+		rewrite := plan.inbound[id]
+		if err := uc.editor.SetFrontmatterMap(ctx, rewrite.path, "relationships", rewrite.relationships); err != nil {
+			return fmt.Errorf("failed to update %s: %w", id, err)
+		}
+	}
+	if uc.relationships != nil {
+		for _, id := range plan.RelationshipFiles {
+			if id == system.ID {
+				continue
+			}
+			if err := uc.rewriteRelationships(ctx, project.Path, id, plan, false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rewriteRelationships collapses the endpoints of the relationships.toml
+// entries of systemID in the project at root that cross the split boundary
+// to the system on the other side. outbound selects the entries of the
+// extracted system, in the new project.
+func (uc *SplitSystem) rewriteRelationships(ctx context.Context, root, systemID string, plan *SplitPlan, outbound bool) error {
+	rels, err := uc.relationships.LoadRelationships(ctx, root, systemID)
+	if err != nil {
+		return fmt.Errorf("failed to load relationships of %s: %w", systemID, err)
+	}
+	systemIDs := make(map[string]bool)
+	for _, s := range plan.systems {
+		if s != nil {
+			systemIDs[s.ID] = true
+		}
+	}
+	_, shortIDs := indexComponents(plan.systems)
+	collapse := func(endpoint string) string {
+		if other := relationshipSystem(endpoint, systemIDs, shortIDs); other != "" && (other == plan.System) != outbound {
+			return other
+		}
+		return endpoint
+	}
+
+	rewritten := make([]entities.Relationship, 0, len(rels))
+	seen := make(map[string]bool)
+	changed := false
+	for _, rel := range rels {
+		if source, target := collapse(rel.Source), collapse(rel.Target); source != rel.Source || target != rel.Target {
+			rel.Source, rel.Target, changed = source, target, true
+			rel.ID = entities.GenerateRelationshipID(rel.Source, rel.Target, rel.Label)
+		}
+		if seen[rel.ID] {
+			continue
+		}
+		seen[rel.ID] = true
+		rewritten = append(rewritten, rel)
+	}
+	if !changed {
+		return nil
+	}
+	if err := uc.relationships.SaveRelationships(ctx, root, systemID, rewritten); err != nil {
+		return fmt.Errorf("failed to save relationships of %s: %w", systemID, err)
+	}
+	return nil
+}
+
+// relationshipSystem returns the ID of the system of the element a
+// relationship endpoint refers to: a system ID, a qualified ID, or a unique
+// component ID. Returns "" for endpoints that resolve to no system.
+func relationshipSystem(endpoint string, systemIDs map[string]bool, shortIDs map[string][]string) string {
+	if systemIDs[endpoint] {
+		return endpoint
+	}
+	if id, _, ok := strings.Cut(endpoint, "/"); ok {
+		if systemIDs[id] {
+			return id
+		}
+		return ""
+	}
+	if qualifiedIDs := shortIDs[endpoint]; len(qualifiedIDs) == 1 {
+		id, _, _ := strings.Cut(qualifiedIDs[0], "/")
+		return id
+	}
+	return ""
+}
+
+// externalStub returns an external system standing for system, which is
+// maintained in the project at projectPath.
+func externalStub(system *entities.System, projectPath string) *entities.System {
+	stub, _ := entities.NewSystem(system.Name)
+	stub.ID = system.ID
+	stub.Domain = system.Domain
+	stub.Description = system.Description
+	stub.Tags = slices.Clone(system.Tags)
+	stub.External = true
+	stub.Metadata = map[string]any{entities.ProjectPathField: filepath.ToSlash(projectPath)}
+	return stub
+}
+
+// relativePath returns target relative to base, or target when it has no
+// relative form.
+func relativePath(base, target string) string {
+	if rel, err := filepath.Rel(base, target); err == nil {
+		return rel
+	}
+	return target
+}
+
+// copySourceTree copies the regular files under src to dst, keeping their
+// permissions and replacing files dst already has.
+func copySourceTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(filepath.Join(dst, rel), 0755)
+		}
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dst, rel), data, info.Mode().Perm())
+	})
+}
@@ -0,0 +1,120 @@
+package usecases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestSplitSystem(t *testing.T) {
+	root := t.TempDir()
+	project, _ := entities.NewProject("Acme")
+	project.Path = root
+
+	newSystem := func(name string) *entities.System {
+		system, _ := entities.NewSystem(name)
+		system.Path = filepath.Join(root, "src", system.ID)
+		return system
+	}
+	newComponent := func(system *entities.System, container, name string, rels map[string]string) {
+		c, _ := entities.NewContainer(container)
+		c.Path = filepath.Join(system.Path, c.ID)
+		comp, _ := entities.NewComponent(name)
+		comp.Path = filepath.Join(c.Path, comp.ID)
+		comp.Relationships = rels
+		_ = c.AddComponent(comp)
+		_ = system.AddContainer(c)
+	}
+	shop := newSystem("Shop")
+	newComponent(shop, "API", "Checkout", map[string]string{"payments/gateway/charge": "Charges", "shop/api/cart": "Reads"})
+	payments := newSystem("Payments")
+	newComponent(payments, "Gateway", "Charge", map[string]string{"checkout": "Notifies"})
+	systems := []*entities.System{shop, payments}
+
+	chargeDir := filepath.Join(payments.Path, "gateway", "charge")
+	if err := os.MkdirAll(chargeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(chargeDir, "component.md"), []byte("---\nname: Charge\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var saved []string
+	repo := &MockProjectRepository{
+		SaveSystemFunc: func(ctx context.Context, projectRoot string, system *entities.System) error {
+			system.Path = filepath.Join(projectRoot, "src", system.ID)
+			if system.External {
+				saved = append(saved, filepath.Base(projectRoot)+": "+system.ID+" -> "+system.Metadata[entities.ProjectPathField].(string))
+			}
+			return os.MkdirAll(system.Path, 0755)
+		},
+	}
+	editor := &recordingEditor{}
+	rels := newMockRelationshipRepository()
+	charges, _ := entities.NewRelationship("shop/api/checkout", "payments/gateway/charge", "Charges")
+	notifies, _ := entities.NewRelationship("payments/gateway/charge", "shop/api/checkout", "Notifies")
+	rels.seed(root, "shop", []entities.Relationship{*charges})
+	rels.seed(root, "payments", []entities.Relationship{*notifies})
+
+	uc := NewSplitSystem(repo, editor, rels)
+	ctx := context.Background()
+	target := filepath.Join(t.TempDir(), "payments")
+	for name, args := range map[string][2]string{
+		"unknown system": {"billing", target},
+		"inside source":  {"payments", filepath.Join(root, "src", "extracted")},
+	} {
+		if _, err := uc.Plan(ctx, project, systems, args[0], args[1]); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	plan, err := uc.Plan(ctx, project, systems, "payments", target)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if !slices.Equal(plan.Inbound, []string{"shop/api/checkout"}) || !slices.Equal(plan.Outbound, []string{"payments/gateway/charge"}) {
+		t.Errorf("Inbound = %q, Outbound = %q", plan.Inbound, plan.Outbound)
+	}
+	if !slices.Equal(plan.Stubs, []string{"shop"}) || !slices.Equal(plan.RelationshipFiles, []string{"shop", "payments"}) {
+		t.Errorf("Stubs = %q, RelationshipFiles = %q", plan.Stubs, plan.RelationshipFiles)
+	}
+
+	// relationships.toml is copied with the system's files
+	rels.seed(target, "payments", []entities.Relationship{*notifies})
+	if err := uc.Apply(ctx, plan); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(target, "src", "payments", "gateway", "charge", "component.md")); err != nil {
+		t.Errorf("component not copied: %v", err)
+	}
+	if _, err := os.Stat(chargeDir); !os.IsNotExist(err) {
+		t.Errorf("system directory not removed: %v", err)
+	}
+	relTarget, _ := filepath.Rel(root, target)
+	relRoot, _ := filepath.Rel(target, root)
+	wantSaved := []string{
+		"payments: shop -> " + filepath.ToSlash(relRoot),
+		filepath.Base(root) + ": payments -> " + filepath.ToSlash(relTarget),
+	}
+	if !slices.Equal(saved, wantSaved) {
+		t.Errorf("external systems = %q, want %q", saved, wantSaved)
+	}
+	wantEdits := []string{
+		"charge/component.md set relationships={shop:Notifies}",
+		"checkout/component.md set relationships={payments:Charges,shop/api/cart:Reads}",
+	}
+	if !slices.Equal(editor.edits, wantEdits) {
+		t.Errorf("edits = %q, want %q", editor.edits, wantEdits)
+	}
+	if got := rels.stored(target, "payments"); len(got) != 1 || got[0].Target != "shop" {
+		t.Errorf("new project relationships = %+v", got)
+	}
+	if got := rels.stored(root, "shop"); len(got) != 1 || got[0].Target != "payments" {
+		t.Errorf("original relationships = %+v", got)
+	}
+}
@@ -0,0 +1,185 @@
+package usecases
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// stagingBaseFile records, inside a staging area, the project sources the
+// area was copied from.
+const stagingBaseFile = ".loko/staging-base.json"
+
+// StagingArea holds the changes of an MCP session in a copy of the project
+// under .loko/staging/<session>/ until a human commits them to the project or
+// discards them. Tools pointed at Root() read and write the copy.
+//
+// Only loko.toml and the files under the source directory are staged, with
+// the same limits as the audit log: hidden directories and files larger than
+// 1 MiB are neither copied nor committed.
+type StagingArea struct {
+	projectRoot string
+	sourceDir   string // Relative to projectRoot
+	session     string
+
+	mu sync.Mutex // Serializes commits and discards
+}
+
+// NewStagingArea creates the staging area of a session for the project at
+// projectRoot, whose sources are under sourceDir (relative to projectRoot).
+// The session name is normalized to a single directory name.
+func NewStagingArea(projectRoot, sourceDir, session string) (*StagingArea, error) {
+	session = entities.NormalizeName(session)
+	if session == "" {
+		return nil, fmt.Errorf("staging session name is required")
+	}
+	return &StagingArea{projectRoot: projectRoot, sourceDir: sourceDir, session: session}, nil
+}
+
+// Session returns the normalized session name.
+func (s *StagingArea) Session() string {
+	return s.session
+}
+
+// Root returns the project root of the staged copy.
+func (s *StagingArea) Root() string {
+	return filepath.Join(s.projectRoot, ".loko", "staging", s.session)
+}
+
+// Open copies the project sources into the staging area unless the session
+// already has one, in which case its staged changes are kept.
+func (s *StagingArea) Open() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := os.Stat(filepath.Join(s.Root(), stagingBaseFile)); err == nil {
+		return nil
+	}
+	return s.reset()
+}
+
+// Changes returns the files staged for the project: added, modified or
+// removed since the staging area was opened, sorted by path.
+func (s *StagingArea) Changes() ([]entities.FileChange, error) {
+	base, staged, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return DiffSnapshots(base, staged), nil
+}
+
+// Commit writes the staged changes to the project and starts a new staging
+// area from the result. It fails without writing anything if a file it would
+// change was also changed in the project since the staging area was opened.
+func (s *StagingArea) Commit() ([]entities.FileChange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	base, staged, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	current, err := snapshotSources(s.projectRoot, s.sourceDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project sources: %w", err)
+	}
+
+	changes := DiffSnapshots(base, staged)
+	var conflicts []string
+	for _, change := range changes {
+		was, existed := base[change.Path]
+		is, exists := current[change.Path]
+		if existed != exists || was != is {
+			conflicts = append(conflicts, change.Path)
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("files changed in the project since staging began: %s", strings.Join(conflicts, ", "))
+	}
+
+	for _, change := range changes {
+		path := filepath.Join(s.projectRoot, filepath.FromSlash(change.Path))
+		if change.Op == entities.FileRemoved {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("failed to remove %s: %w", change.Path, err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory for %s: %w", change.Path, err)
+		}
+		if err := os.WriteFile(path, []byte(staged[change.Path]), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", change.Path, err)
+		}
+	}
+
+	if err := s.reset(); err != nil {
+		return changes, fmt.Errorf("changes committed but staging not restarted: %w", err)
+	}
+	return changes, nil
+}
+
+// Discard drops the staged changes and starts a new staging area from the
+// project.
+func (s *StagingArea) Discard() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.reset()
+}
+
+// reset replaces the staging area with a fresh copy of the project sources.
+func (s *StagingArea) reset() error {
+	if err := os.RemoveAll(s.Root()); err != nil {
+		return fmt.Errorf("failed to remove staging area: %w", err)
+	}
+	files, err := snapshotSources(s.projectRoot, s.sourceDir)
+	if err != nil {
+		return fmt.Errorf("failed to read project sources: %w", err)
+	}
+	for rel, content := range files {
+		path := filepath.Join(s.Root(), filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create staging area: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to stage %s: %w", rel, err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(s.Root(), filepath.Dir(stagingBaseFile)), 0755); err != nil {
+		return fmt.Errorf("failed to create staging area: %w", err)
+	}
+
+	base, err := json.Marshal(files)
+	if err != nil {
+		return fmt.Errorf("failed to encode staging base: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.Root(), stagingBaseFile), base, 0644); err != nil {
+		return fmt.Errorf("failed to write staging base: %w", err)
+	}
+	return nil
+}
+
+// load returns the project sources the staging area was copied from and its
+// current sources.
+func (s *StagingArea) load() (base, staged map[string]string, err error) {
+	content, err := os.ReadFile(filepath.Join(s.Root(), stagingBaseFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("staging area %q is not open: %w", s.session, err)
+	}
+	if err := json.Unmarshal(content, &base); err != nil {
+		return nil, nil, fmt.Errorf("invalid staging base: %w", err)
+	}
+	staged, err = snapshotSources(s.Root(), s.sourceDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read staged sources: %w", err)
+	}
+	return base, staged, nil
+}
@@ -0,0 +1,129 @@
+package usecases
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStagingArea_CommitAndDiscard(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "loko.toml"), "[project]\nname = \"demo\"\n")
+	writeTestFile(t, filepath.Join(root, "src", "payments", "system.md"), "---\nname: Payments\n---\n")
+	writeTestFile(t, filepath.Join(root, "src", "payments", "old.md"), "old\n")
+
+	area, err := NewStagingArea(root, "src", "Review 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if area.Session() != "review-1" {
+		t.Errorf("Session() = %q, want review-1", area.Session())
+	}
+	if err := area.Open(); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	staged := area.Root()
+	writeTestFile(t, filepath.Join(staged, "src", "payments", "system.md"), "---\nname: Payments\ndescription: Cards\n---\n")
+	writeTestFile(t, filepath.Join(staged, "src", "payments", "api", "container.md"), "---\nname: API\n---\n")
+	if err := os.Remove(filepath.Join(staged, "src", "payments", "old.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopening keeps the staged changes
+	if err := area.Open(); err != nil {
+		t.Fatal(err)
+	}
+	changes, err := area.Changes()
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	want := []entities.FileChange{
+		{Path: "src/payments/api/container.md", Op: entities.FileAdded},
+		{Path: "src/payments/old.md", Op: entities.FileRemoved},
+		{Path: "src/payments/system.md", Op: entities.FileModified, Diff: "+description: Cards\n"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Changes() = %+v, want %d changes", changes, len(want))
+	}
+	for i, w := range want {
+		if changes[i].Path != w.Path || changes[i].Op != w.Op || (w.Diff != "" && changes[i].Diff != w.Diff) {
+			t.Errorf("change %d = %+v, want %+v", i, changes[i], w)
+		}
+	}
+
+	// Nothing reaches the project before the commit
+	if content, _ := os.ReadFile(filepath.Join(root, "src", "payments", "system.md")); strings.Contains(string(content), "Cards") {
+		t.Error("staged change written to the project before commit")
+	}
+
+	if _, err := area.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(root, "src", "payments", "system.md")); !strings.Contains(string(content), "Cards") {
+		t.Error("modified file not committed")
+	}
+	if _, err := os.Stat(filepath.Join(root, "src", "payments", "api", "container.md")); err != nil {
+		t.Error("added file not committed")
+	}
+	if _, err := os.Stat(filepath.Join(root, "src", "payments", "old.md")); !os.IsNotExist(err) {
+		t.Error("removed file still in the project")
+	}
+	if changes, _ := area.Changes(); len(changes) != 0 {
+		t.Errorf("Changes() after commit = %+v, want none", changes)
+	}
+
+	writeTestFile(t, filepath.Join(staged, "src", "payments", "system.md"), "discarded\n")
+	if err := area.Discard(); err != nil {
+		t.Fatalf("Discard() error = %v", err)
+	}
+	if changes, _ := area.Changes(); len(changes) != 0 {
+		t.Errorf("Changes() after discard = %+v, want none", changes)
+	}
+	if content, _ := os.ReadFile(filepath.Join(root, "src", "payments", "system.md")); string(content) == "discarded\n" {
+		t.Error("discarded change written to the project")
+	}
+}
+
+func TestStagingArea_CommitConflict(t *testing.T) {
+	root := t.TempDir()
+	systemMd := filepath.Join(root, "src", "payments", "system.md")
+	writeTestFile(t, systemMd, "---\nname: Payments\n---\n")
+
+	area, err := NewStagingArea(root, "src", "agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := area.Open(); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(area.Root(), "src", "payments", "system.md"), "staged\n")
+	writeTestFile(t, systemMd, "edited by hand\n")
+
+	_, err = area.Commit()
+	if err == nil || !strings.Contains(err.Error(), "src/payments/system.md") {
+		t.Fatalf("Commit() error = %v, want conflict on system.md", err)
+	}
+	if content, _ := os.ReadFile(systemMd); string(content) != "edited by hand\n" {
+		t.Errorf("conflicting commit overwrote the project: %q", content)
+	}
+}
+
+func TestNewStagingArea_RequiresSession(t *testing.T) {
+	if _, err := NewStagingArea(t.TempDir(), "src", " "); err == nil {
+		t.Error("expected an error for an empty session name")
+	}
+}
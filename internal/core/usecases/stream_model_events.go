@@ -0,0 +1,96 @@
+package usecases
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// elementMarkers are the files defining an element, by directory depth below
+// the source directory.
+var elementMarkers = []struct{ kind, file string }{
+	{entities.ElementSystem, "system.md"},
+	{entities.ElementContainer, "container.md"},
+	{entities.ElementComponent, "component.md"},
+}
+
+// StreamModelEvents publishes entity created, updated and deleted events for
+// source changes reported by a file watcher.
+type StreamModelEvents struct {
+	watcher   FileWatcher
+	publisher EventPublisher
+	now       func() time.Time
+}
+
+// NewStreamModelEvents creates a StreamModelEvents use case.
+func NewStreamModelEvents(watcher FileWatcher, publisher EventPublisher) *StreamModelEvents {
+	return &StreamModelEvents{watcher: watcher, publisher: publisher, now: time.Now}
+}
+
+// Execute watches projectRoot and publishes events until ctx is cancelled or
+// the watcher stops. sourceDir is the project's source directory, relative to
+// projectRoot.
+func (uc *StreamModelEvents) Execute(ctx context.Context, projectRoot, sourceDir string) error {
+	changes, err := uc.watcher.Watch(ctx, projectRoot)
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case change, ok := <-changes:
+			if !ok {
+				return nil
+			}
+			if event, ok := EntityEventForChange(sourceDir, change); ok {
+				event.Time = uc.now()
+				uc.publisher.Publish(event)
+			}
+		}
+	}
+}
+
+// EntityEventForChange maps a source file change to an entity event. Creating
+// or removing an element's own Markdown file (system.md, container.md or
+// component.md, possibly encrypted) creates or deletes the element; any
+// other .md or .d2 change updates it. Other changes report false.
+func EntityEventForChange(sourceDir string, change FileChangeEvent) (entities.ModelEvent, bool) {
+	prefix := path.Clean(strings.ReplaceAll(sourceDir, "\\", "/"))
+	if prefix == "." || prefix == "" {
+		prefix = "src"
+	}
+	rel, ok := strings.CutPrefix(path.Clean(change.Path), prefix+"/")
+	if !ok || change.Op == "chmod" {
+		return entities.ModelEvent{}, false
+	}
+
+	dir, file := path.Split(rel)
+	segments := strings.Split(strings.TrimSuffix(dir, "/"), "/")
+	if dir == "" || len(segments) > len(elementMarkers) {
+		return entities.ModelEvent{}, false
+	}
+	marker := elementMarkers[len(segments)-1]
+	event := entities.ModelEvent{
+		Type:   entities.EventEntityUpdated,
+		Entity: marker.kind,
+		ID:     strings.Join(segments, "/"),
+	}
+
+	if strings.TrimSuffix(file, EncryptedExt) == marker.file {
+		switch change.Op {
+		case "create":
+			event.Type = entities.EventEntityCreated
+		case "remove", "rename":
+			event.Type = entities.EventEntityDeleted
+		}
+		return event, true
+	}
+	if ScopeForChange(strings.TrimSuffix(rel, EncryptedExt)) != RebuildContent {
+		return entities.ModelEvent{}, false
+	}
+	return event, true
+}
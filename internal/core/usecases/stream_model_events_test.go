@@ -0,0 +1,68 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestEntityEventForChange(t *testing.T) {
+	tests := []struct {
+		path, op   string
+		want       entities.ModelEventType
+		entity, id string
+	}{
+		{"src/orders/system.md", "create", entities.EventEntityCreated, entities.ElementSystem, "orders"},
+		{"src/orders/system.md", "write", entities.EventEntityUpdated, entities.ElementSystem, "orders"},
+		{"src/orders/api/container.md.age", "remove", entities.EventEntityDeleted, entities.ElementContainer, "orders/api"},
+		{"src/orders/api/handler/component.md", "rename", entities.EventEntityDeleted, entities.ElementComponent, "orders/api/handler"},
+		{"src/orders/api/api.d2", "create", entities.EventEntityUpdated, entities.ElementContainer, "orders/api"},
+		{"src/orders/notes.md", "remove", entities.EventEntityUpdated, entities.ElementSystem, "orders"},
+		{"src/orders/system.md", "chmod", "", "", ""},
+		{"src/orders/logo.png", "write", "", "", ""},
+		{"src/README.md", "write", "", "", ""},
+		{"src/a/b/c/d/deep.md", "write", "", "", ""},
+		{"loko.toml", "write", "", "", ""},
+	}
+	for _, tt := range tests {
+		event, ok := EntityEventForChange("./src", FileChangeEvent{Path: tt.path, Op: tt.op})
+		if ok != (tt.want != "") {
+			t.Errorf("%s %s: ok = %v, want %v", tt.op, tt.path, ok, tt.want != "")
+			continue
+		}
+		if event.Type != tt.want || event.Entity != tt.entity || event.ID != tt.id {
+			t.Errorf("%s %s = %s %s %q, want %s %s %q", tt.op, tt.path, event.Type, event.Entity, event.ID, tt.want, tt.entity, tt.id)
+		}
+	}
+}
+
+// channelWatcher is a FileWatcher replaying events from a channel.
+type channelWatcher chan FileChangeEvent
+
+func (w channelWatcher) Watch(context.Context, string) (<-chan FileChangeEvent, error) { return w, nil }
+func (w channelWatcher) Stop() error                                                   { return nil }
+
+// recordingPublisher is an EventPublisher collecting published events.
+type recordingPublisher []entities.ModelEvent
+
+func (p *recordingPublisher) Publish(event entities.ModelEvent) { *p = append(*p, event) }
+
+func TestStreamModelEvents(t *testing.T) {
+	changes := make(channelWatcher, 3)
+	changes <- FileChangeEvent{Path: "src/orders/system.md", Op: "create"}
+	changes <- FileChangeEvent{Path: "loko.toml", Op: "write"}
+	changes <- FileChangeEvent{Path: "src/orders/api/container.md", Op: "write"}
+	close(changes)
+
+	var published recordingPublisher
+	if err := NewStreamModelEvents(changes, &published).Execute(context.Background(), ".", "src"); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(published) != 2 {
+		t.Fatalf("published %d events, want 2: %+v", len(published), published)
+	}
+	if published[0].Type != entities.EventEntityCreated || published[1].ID != "orders/api" || published[1].Time.IsZero() {
+		t.Errorf("unexpected events: %+v", published)
+	}
+}
@@ -0,0 +1,273 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// Markers of the managed block of a D2 diagram, the region SyncDiagrams
+// rewrites. The rest of the diagram is never changed.
+const (
+	ManagedBlockBegin = "# loko:managed:begin"
+	ManagedBlockEnd   = "# loko:managed:end"
+)
+
+// elementFields are the children loko's diagram templates give element
+// shapes for their description and technology. D2 draws them as shapes, but
+// they name no element.
+var elementFields = map[string]bool{"description": true, "technology": true}
+
+// DiagramSync is the reconciliation of one diagram with the model.
+type DiagramSync struct {
+	Element string   // Slash-separated ID of the system or container the diagram shows
+	Path    string   // The .d2 file
+	Shapes  []string // Shapes of the managed block, for elements the rest of the diagram lacks
+	Edges   []string // Connections of the managed block, as "source -> target"
+	Stale   []string // Shapes outside the managed block nested in an element but naming none
+	Changed bool     // Whether syncing rewrites the file
+
+	source string // The synced diagram
+}
+
+// SyncDiagrams reconciles the D2 diagrams of systems and containers with the
+// model. A diagram shows the children of its element: the containers of a
+// system or the components of a container. Each relationship of the model
+// between two children, lifted from their descendants, must be drawn; those
+// the diagram lacks are added, with any missing shapes, to its managed block.
+// Shapes of elements removed from the model are flagged, not deleted.
+type SyncDiagrams struct {
+	parser D2DiagramParser
+}
+
+// NewSyncDiagrams creates a SyncDiagrams use case.
+func NewSyncDiagrams(parser D2DiagramParser) *SyncDiagrams {
+	return &SyncDiagrams{parser: parser}
+}
+
+// Plan returns the syncs of the diagrams of systems that change or have stale
+// shapes, in hierarchy order. graph supplies the relationships and should be
+// built from frontmatter and relationships.toml only, so that edges drawn
+// solely in diagrams are not mistaken for the model. Elements without a
+// diagram are skipped.
+func (uc *SyncDiagrams) Plan(ctx context.Context, graph *entities.ArchitectureGraph, systems []*entities.System) ([]DiagramSync, error) {
+	var syncs []DiagramSync
+	add := func(scope string, diagram *entities.Diagram) error {
+		if diagram == nil || diagram.SourcePath == "" {
+			return nil
+		}
+		sync, err := uc.plan(ctx, graph, scope, diagram)
+		if err != nil {
+			return fmt.Errorf("failed to sync %s: %w", filepath.Base(diagram.SourcePath), err)
+		}
+		if sync.Changed || len(sync.Stale) > 0 {
+			syncs = append(syncs, sync)
+		}
+		return nil
+	}
+
+	sorted := slices.DeleteFunc(slices.Clone(systems), func(s *entities.System) bool { return s == nil })
+	slices.SortFunc(sorted, func(a, b *entities.System) int { return strings.Compare(a.ID, b.ID) })
+	for _, system := range sorted {
+		if err := add(system.ID, system.Diagram); err != nil {
+			return nil, err
+		}
+		for _, container := range system.ListContainers() {
+			if err := add(system.ID+"/"+container.ID, container.Diagram); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return syncs, nil
+}
+
+// Apply writes a diagram synced by Plan.
+func (uc *SyncDiagrams) Apply(sync DiagramSync) error {
+	if !sync.Changed {
+		return nil
+	}
+	if _, err := os.Stat(sync.Path + EncryptedExt); err == nil {
+		return fmt.Errorf("%s: %w", filepath.Base(sync.Path), entities.ErrEncrypted)
+	}
+	if err := os.WriteFile(sync.Path, []byte(sync.source), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(sync.Path), err)
+	}
+	return nil
+}
+
+// plan reconciles the diagram of the element scope.
+func (uc *SyncDiagrams) plan(ctx context.Context, graph *entities.ArchitectureGraph, scope string, diagram *entities.Diagram) (DiagramSync, error) {
+	sync := DiagramSync{Element: scope, Path: diagram.SourcePath}
+	before, after, found, err := splitManagedBlock(diagram.Source)
+	if err != nil {
+		return sync, err
+	}
+	drawn := before + after
+
+	shapes, err := uc.parser.ParseShapes(ctx, drawn)
+	if err != nil {
+		return sync, err
+	}
+	rels, err := uc.parser.ParseRelationships(ctx, drawn)
+	if err != nil {
+		return sync, err
+	}
+
+	// child returns the child of scope that id is or descends from
+	child := func(id string) string {
+		for ; id != ""; id = graph.ParentMap[id] {
+			if graph.ParentMap[id] == scope {
+				return id
+			}
+		}
+		return ""
+	}
+
+	// Children drawn, and the dotted prefix their shapes are nested under
+	present := make(map[string]bool)
+	prefixes := make(map[string]int)
+	for _, shape := range shapes {
+		id, ok := resolveDiagramNode(graph, shape, scope)
+		if !ok {
+			if i := strings.LastIndex(shape, "."); i > 0 && !elementFields[shape[i+1:]] {
+				if _, ok := resolveDiagramNode(graph, shape[:i], scope); ok {
+					sync.Stale = append(sync.Stale, shape)
+				}
+			}
+			continue
+		}
+		if c := child(id); c != "" {
+			present[c] = true
+			if c == id {
+				prefixes[shape[:strings.LastIndex(shape, ".")+1]]++
+			}
+		}
+	}
+	drawnEdges := make(map[[2]string]bool)
+	for _, rel := range rels {
+		source, ok := resolveDiagramNode(graph, rel.Source, scope)
+		if !ok {
+			continue
+		}
+		target, ok := resolveDiagramNode(graph, rel.Target, scope)
+		if !ok {
+			continue
+		}
+		drawnEdges[[2]string{child(source), child(target)}] = true
+	}
+
+	// Relationships of the model between children, labelled by the first
+	type edge struct{ source, target, label string }
+	var missing []edge
+	seen := make(map[[2]string]bool)
+	for _, source := range slices.Sorted(maps.Keys(graph.Edges)) {
+		for _, e := range graph.Edges[source] {
+			key := [2]string{child(e.Source), child(e.Target)}
+			if key[0] == "" || key[1] == "" || key[0] == key[1] || seen[key] {
+				continue
+			}
+			seen[key] = true
+			if !drawnEdges[key] {
+				missing = append(missing, edge{key[0], key[1], e.Description})
+			}
+		}
+	}
+	slices.SortStableFunc(missing, func(a, b edge) int {
+		return cmp.Or(strings.Compare(a.source, b.source), strings.Compare(a.target, b.target))
+	})
+
+	// Shapes are nested like the children already drawn
+	prefix := ""
+	for _, p := range slices.Sorted(maps.Keys(prefixes)) {
+		if prefixes[p] > prefixes[prefix] {
+			prefix = p
+		}
+	}
+	shapeID := func(id string) string {
+		return prefix + id[strings.LastIndex(id, "/")+1:]
+	}
+
+	var block strings.Builder
+	for _, e := range missing {
+		for _, id := range []string{e.source, e.target} {
+			if present[id] {
+				continue
+			}
+			present[id] = true
+			name := id
+			if node := graph.GetNode(id); node != nil && node.Name != "" {
+				name = node.Name
+			}
+			sync.Shapes = append(sync.Shapes, shapeID(id))
+			block.WriteString(fmt.Sprintf("%s: %q\n", shapeID(id), name))
+		}
+	}
+	for _, e := range missing {
+		connection := shapeID(e.source) + " -> " + shapeID(e.target)
+		sync.Edges = append(sync.Edges, connection)
+		if e.label == "" {
+			block.WriteString(connection + "\n")
+		} else {
+			block.WriteString(fmt.Sprintf("%s: %q\n", connection, e.label))
+		}
+	}
+
+	switch {
+	case block.Len() > 0:
+		managed := ManagedBlockBegin + "\n" +
+			"# Kept in sync with the model by loko sync diagrams; edits here are overwritten.\n" +
+			block.String() + ManagedBlockEnd + "\n"
+		if found {
+			sync.source = before + managed + after
+		} else {
+			sync.source = strings.TrimRight(diagram.Source, "\n") + "\n\n" + managed
+		}
+	case found:
+		sync.source = before + after
+		if strings.TrimSpace(after) == "" {
+			sync.source = strings.TrimRight(before, "\n") + "\n"
+		}
+	default:
+		sync.source = diagram.Source
+	}
+	sync.Changed = sync.source != diagram.Source
+	return sync, nil
+}
+
+// splitManagedBlock returns the source before and after the managed block,
+// markers included in neither, and whether the source has one.
+func splitManagedBlock(source string) (before, after string, found bool, err error) {
+	lines := strings.SplitAfter(source, "\n")
+	begin, end := -1, -1
+	for i, line := range lines {
+		switch strings.TrimSpace(line) {
+		case ManagedBlockBegin:
+			if begin >= 0 {
+				return "", "", false, fmt.Errorf("line %d: nested %s", i+1, ManagedBlockBegin)
+			}
+			begin = i
+		case ManagedBlockEnd:
+			if begin < 0 {
+				return "", "", false, fmt.Errorf("line %d: %s without %s", i+1, ManagedBlockEnd, ManagedBlockBegin)
+			}
+			end = i
+		}
+		if end >= 0 {
+			break
+		}
+	}
+	switch {
+	case begin < 0:
+		return source, "", false, nil
+	case end < 0:
+		return "", "", false, fmt.Errorf("line %d: %s without %s", begin+1, ManagedBlockBegin, ManagedBlockEnd)
+	}
+	return strings.Join(lines[:begin], ""), strings.Join(lines[end+1:], ""), true, nil
+}
@@ -0,0 +1,132 @@
+package usecases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// lineD2Parser reads a line-based subset of D2: "a.b: label" declares shapes
+// and "a -> b: label" connects them.
+type lineD2Parser struct{}
+
+func (lineD2Parser) ParseRelationships(_ context.Context, source string) ([]entities.D2Relationship, error) {
+	var rels []entities.D2Relationship
+	for _, line := range strings.Split(source, "\n") {
+		if from, to, ok := strings.Cut(line, " -> "); ok && !strings.HasPrefix(line, "#") {
+			to, label, _ := strings.Cut(to, ":")
+			rels = append(rels, entities.D2Relationship{Source: from, Target: to, Label: strings.TrimSpace(label)})
+		}
+	}
+	return rels, nil
+}
+
+func (p lineD2Parser) ParseShapes(ctx context.Context, source string) ([]string, error) {
+	var shapes []string
+	add := func(shape string) {
+		parts := strings.Split(strings.TrimSpace(shape), ".")
+		for i := range parts {
+			if s := strings.Join(parts[:i+1], "."); !slices.Contains(shapes, s) {
+				shapes = append(shapes, s)
+			}
+		}
+	}
+	rels, _ := p.ParseRelationships(ctx, source)
+	for _, line := range strings.Split(source, "\n") {
+		if id, _, ok := strings.Cut(line, ":"); ok && !strings.HasPrefix(line, "#") && !strings.Contains(id, "->") {
+			add(id)
+		}
+	}
+	for _, rel := range rels {
+		add(rel.Source)
+		add(rel.Target)
+	}
+	return shapes, nil
+}
+
+func TestSyncDiagrams(t *testing.T) {
+	project, _ := entities.NewProject("test-project")
+	payments, _ := entities.NewSystem("Payments")
+	api, _ := entities.NewContainer("API")
+	database, _ := entities.NewContainer("Database")
+	handler, _ := entities.NewComponent("Handler")
+	validator, _ := entities.NewComponent("Validator")
+	store, _ := entities.NewComponent("Store")
+	handler.AddRelationship("store", "Reads orders")
+	handler.AddRelationship("validator", "Validates")
+	_ = api.AddComponent(handler)
+	_ = api.AddComponent(validator)
+	_ = database.AddComponent(store)
+	_ = payments.AddContainer(api)
+	_ = payments.AddContainer(database)
+
+	dir := t.TempDir()
+	diagram := func(name, source string) *entities.Diagram {
+		d, _ := entities.NewDiagram(filepath.Join(dir, name))
+		d.SetSource(source)
+		if err := os.WriteFile(d.SourcePath, []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	payments.Diagram = diagram("system.d2", "user: \"User\"\npayments.api: \"API\"\npayments.legacy: \"Legacy\"\npayments.api.description: \"Serves orders\"\nuser -> payments.api: Uses\n")
+	api.Diagram = diagram("api.d2", "handler -> validator\n\n"+ManagedBlockBegin+"\nold: \"Old\"\n"+ManagedBlockEnd+"\n")
+	database.Diagram = diagram("database.d2", "store: \"Store\"\n")
+
+	graph, err := NewBuildArchitectureGraph().Execute(context.Background(), project, []*entities.System{payments})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	uc := NewSyncDiagrams(lineD2Parser{})
+	syncs, err := uc.Plan(context.Background(), graph, []*entities.System{payments})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if len(syncs) != 2 || syncs[0].Element != "payments" || syncs[1].Element != "payments/api" {
+		t.Fatalf("syncs = %+v", syncs)
+	}
+
+	system := syncs[0]
+	if !slices.Equal(system.Shapes, []string{"payments.database"}) ||
+		!slices.Equal(system.Edges, []string{"payments.api -> payments.database"}) ||
+		!slices.Equal(system.Stale, []string{"payments.legacy"}) {
+		t.Errorf("system sync = %+v", system)
+	}
+	want := payments.Diagram.Source + "\n" + ManagedBlockBegin + "\n" +
+		"# Kept in sync with the model by loko sync diagrams; edits here are overwritten.\n" +
+		"payments.database: \"Database\"\n" +
+		"payments.api -> payments.database: \"Reads orders\"\n" + ManagedBlockEnd + "\n"
+	if system.source != want {
+		t.Errorf("system source =\n%s\nwant\n%s", system.source, want)
+	}
+
+	// An obsolete managed block is removed
+	if container := syncs[1]; !container.Changed || container.source != "handler -> validator\n" {
+		t.Errorf("container sync = %+v", container)
+	}
+
+	// Syncing again leaves the diagram unchanged
+	if err := uc.Apply(system); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	content, _ := os.ReadFile(system.Path)
+	payments.Diagram.SetSource(string(content))
+	syncs, err = uc.Plan(context.Background(), graph, []*entities.System{payments})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if syncs[0].Changed || len(syncs[0].Stale) != 1 {
+		t.Errorf("resync = %+v", syncs[0])
+	}
+
+	// An unterminated block is an error
+	api.Diagram.SetSource(ManagedBlockBegin + "\nold\n")
+	if _, err := uc.Plan(context.Background(), graph, []*entities.System{payments}); err == nil {
+		t.Error("expected an error for an unterminated managed block")
+	}
+}
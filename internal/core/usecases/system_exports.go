@@ -0,0 +1,103 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// ExportsDir is the directory of the build output holding the standalone
+// per-system documents requested with "export:" in system frontmatter.
+const ExportsDir = "exports"
+
+// systemExportFormats returns the formats listed in the system's Exports, and
+// the entries that name no export format.
+func systemExportFormats(system *entities.System) (formats []OutputFormat, unknown []string) {
+	for _, name := range system.Exports {
+		var format OutputFormat
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "pdf":
+			format = FormatPDF
+		case "markdown", "md":
+			format = FormatMarkdown
+		default:
+			unknown = append(unknown, name)
+			continue
+		}
+		if !containsFormat(formats, format) {
+			formats = append(formats, format)
+		}
+	}
+	return formats, unknown
+}
+
+// exportsPDF reports whether any system requests a PDF export.
+func exportsPDF(systems []*entities.System) bool {
+	for _, system := range systems {
+		if formats, _ := systemExportFormats(system); containsFormat(formats, FormatPDF) {
+			return true
+		}
+	}
+	return false
+}
+
+// exportSystems writes exports/<system-id>.md and exports/<system-id>.pdf to
+// outputDir for the systems requesting them. A PDF is rendered from the
+// system's page of the HTML site, which is built first if it is missing.
+// Exports whose adapter is not configured, such as PDFs without veve-cli,
+// and unknown formats are reported and skipped rather than failing the build.
+func (uc *BuildDocs) exportSystems(ctx context.Context, project *entities.Project, systems []*entities.System, outputDir string) error {
+	exportsDir := filepath.Join(outputDir, ExportsDir)
+	siteBuilt := false
+	for _, system := range systems {
+		formats, unknown := systemExportFormats(system)
+		for _, name := range unknown {
+			uc.progressReporter.ReportInfo(fmt.Sprintf("Warning: system %s: unknown export format %q (want pdf or markdown), skipping", system.ID, name))
+		}
+		for _, format := range formats {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			switch format {
+			case FormatMarkdown:
+				if uc.markdownBuilder == nil {
+					uc.progressReporter.ReportInfo(fmt.Sprintf("Warning: system %s: markdown builder not configured, skipping markdown export", system.ID))
+					continue
+				}
+				content, err := uc.markdownBuilder.BuildSystemMarkdown(ctx, system, system.ListContainers())
+				if err != nil {
+					return fmt.Errorf("failed to export system %s to markdown: %w", system.ID, err)
+				}
+				if err := os.MkdirAll(exportsDir, 0755); err != nil {
+					return fmt.Errorf("failed to create exports directory: %w", err)
+				}
+				if err := os.WriteFile(filepath.Join(exportsDir, system.ID+".md"), []byte(content), 0644); err != nil {
+					return fmt.Errorf("failed to write %s.md: %w", system.ID, err)
+				}
+				uc.progressReporter.ReportSuccess(fmt.Sprintf("Exported %s/%s.md", ExportsDir, system.ID))
+
+			case FormatPDF:
+				if uc.pdfRenderer == nil || !uc.pdfRenderer.IsAvailable() {
+					uc.progressReporter.ReportInfo(fmt.Sprintf("Warning: system %s: PDF renderer (veve-cli) not available, skipping PDF export", system.ID))
+					continue
+				}
+				htmlPath := filepath.Join(outputDir, "systems", system.ID+".html")
+				if _, err := os.Stat(htmlPath); os.IsNotExist(err) && !siteBuilt {
+					if err := uc.siteBuilder.BuildSite(ctx, project, systems, outputDir); err != nil {
+						return fmt.Errorf("failed to build HTML for PDF exports: %w", err)
+					}
+					siteBuilt = true
+				}
+				if err := uc.pdfRenderer.RenderPDF(ctx, htmlPath, filepath.Join(exportsDir, system.ID+".pdf")); err != nil {
+					return fmt.Errorf("failed to export system %s to PDF: %w", system.ID, err)
+				}
+				uc.progressReporter.ReportSuccess(fmt.Sprintf("Exported %s/%s.pdf", ExportsDir, system.ID))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// SystemKPIs holds the aggregate health indicators shown on a system's landing page.
+type SystemKPIs struct {
+	ContainerCount int
+	ComponentCount int
+
+	// Documented is the number of elements (the system, its containers and
+	// components) that have a description, out of Elements.
+	Documented int
+	Elements   int
+
+	// Warnings counts open validation errors and warnings affecting the system.
+	// It is only meaningful when Validated is true.
+	Warnings  int
+	Validated bool
+
+	// LastModified is the most recent modification time of any file under the
+	// system's source directory; zero when the system has no path on disk.
+	LastModified time.Time
+}
+
+// Coverage returns the documentation coverage as a percentage from 0 to 100.
+func (k *SystemKPIs) Coverage() int {
+	if k == nil || k.Elements == 0 {
+		return 0
+	}
+	return k.Documented * 100 / k.Elements
+}
+
+// ComputeSystemKPIs aggregates per-system health indicators during a build:
+// element counts, documentation coverage, open validation warnings and the
+// last modification time of the system's source files.
+type ComputeSystemKPIs struct{}
+
+// NewComputeSystemKPIs creates a new ComputeSystemKPIs use case.
+func NewComputeSystemKPIs() *ComputeSystemKPIs {
+	return &ComputeSystemKPIs{}
+}
+
+// Execute returns the KPIs of every system keyed by system ID. Validation
+// runs once over the whole architecture so cross-system references resolve.
+func (uc *ComputeSystemKPIs) Execute(
+	ctx context.Context,
+	project *entities.Project,
+	systems []*entities.System,
+) (map[string]*SystemKPIs, error) {
+	if project == nil {
+		return nil, fmt.Errorf("project cannot be nil")
+	}
+
+	systems = slices.DeleteFunc(slices.Clone(systems), func(s *entities.System) bool { return s == nil })
+
+	graph, err := NewBuildArchitectureGraph().Execute(ctx, project, systems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build architecture graph: %w", err)
+	}
+	report := NewValidateArchitecture().Execute(graph, systems)
+
+	kpis := make(map[string]*SystemKPIs, len(systems))
+	for _, system := range systems {
+		k := SystemKPIsFor(system)
+		k.Validated = true
+		kpis[system.ID] = k
+	}
+
+	for _, issue := range report.Issues {
+		if issue.Severity != "error" && issue.Severity != "warning" {
+			continue
+		}
+		for _, id := range issue.Affected {
+			if k, ok := kpis[owningSystemID(graph, id)]; ok {
+				k.Warnings++
+			}
+		}
+	}
+
+	return kpis, nil
+}
+
+// SystemKPIsFor computes the KPIs of a single system that do not require
+// validating the rest of the architecture. Warnings are left unset.
+func SystemKPIsFor(system *entities.System) *SystemKPIs {
+	k := &SystemKPIs{Elements: 1}
+	if system.Description != "" {
+		k.Documented++
+	}
+
+	for _, container := range system.Containers {
+		if container == nil {
+			continue
+		}
+		k.ContainerCount++
+		k.Elements++
+		if container.Description != "" {
+			k.Documented++
+		}
+		for _, component := range container.Components {
+			if component == nil {
+				continue
+			}
+			k.ComponentCount++
+			k.Elements++
+			if component.Description != "" {
+				k.Documented++
+			}
+		}
+	}
+
+	k.LastModified = latestModTime(system.Path)
+	return k
+}
+
+// owningSystemID returns the system ID of a qualified or short node ID, or
+// an empty string when a short ID cannot be resolved unambiguously.
+func owningSystemID(graph *entities.ArchitectureGraph, id string) string {
+	if !strings.Contains(id, "/") {
+		qualified, ok := graph.ResolveID(id)
+		if !ok {
+			return ""
+		}
+		id = qualified
+	}
+	parts, _ := entities.ParseQualifiedID(id)
+	if len(parts) == 0 {
+		return ""
+	}
+	return parts[0]
+}
+
+// latestModTime returns the newest modification time of any file under dir.
+// Unreadable entries are skipped; an empty or missing dir yields the zero time.
+func latestModTime(dir string) time.Time {
+	var latest time.Time
+	if dir == "" {
+		return latest
+	}
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	return latest
+}
@@ -0,0 +1,93 @@
+package usecases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestComputeSystemKPIs(t *testing.T) {
+	project, _ := entities.NewProject("kpis")
+
+	payments, _ := entities.NewSystem("Payments")
+	payments.SetDescription("Handles payments")
+	api, _ := entities.NewContainer("API")
+	api.SetDescription("Public API")
+	handler, _ := entities.NewComponent("Handler")
+	handler.SetDescription("Request handler")
+	ledger, _ := entities.NewComponent("Ledger")
+	ledger.AddRelationship("missing-component", "writes entries")
+	_ = api.AddComponent(handler)
+	_ = api.AddComponent(ledger)
+	worker, _ := entities.NewContainer("Worker")
+	_ = payments.AddContainer(api)
+	_ = payments.AddContainer(worker)
+
+	dir := t.TempDir()
+	payments.Path = dir
+	modTime := time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)
+	file := filepath.Join(dir, "system.md")
+	if err := os.WriteFile(file, []byte("# Payments"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(file, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	empty, _ := entities.NewSystem("Empty")
+
+	kpis, err := NewComputeSystemKPIs().Execute(context.Background(), project, []*entities.System{payments, empty, nil})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	got := kpis[payments.ID]
+	if got == nil {
+		t.Fatalf("missing KPIs for %s", payments.ID)
+	}
+	if got.ContainerCount != 2 || got.ComponentCount != 2 {
+		t.Errorf("counts = %d containers, %d components, want 2, 2", got.ContainerCount, got.ComponentCount)
+	}
+	if got.Documented != 3 || got.Elements != 5 || got.Coverage() != 60 {
+		t.Errorf("coverage = %d/%d (%d%%), want 3/5 (60%%)", got.Documented, got.Elements, got.Coverage())
+	}
+	if !got.Validated || got.Warnings != 1 {
+		t.Errorf("warnings = %d (validated %v), want 1 (validated true)", got.Warnings, got.Validated)
+	}
+	if !got.LastModified.Equal(modTime) {
+		t.Errorf("LastModified = %v, want %v", got.LastModified, modTime)
+	}
+
+	other := kpis[empty.ID]
+	if other == nil {
+		t.Fatalf("missing KPIs for %s", empty.ID)
+	}
+	if other.Warnings != 0 || other.Coverage() != 0 || !other.LastModified.IsZero() {
+		t.Errorf("unexpected KPIs for empty system: %+v", other)
+	}
+}
+
+func TestComputeSystemKPIsNilProject(t *testing.T) {
+	if _, err := NewComputeSystemKPIs().Execute(context.Background(), nil, nil); err == nil {
+		t.Error("expected error for nil project")
+	}
+}
+
+func TestSystemKPIsFor(t *testing.T) {
+	system, _ := entities.NewSystem("Solo")
+	container, _ := entities.NewContainer("Web")
+	container.SetDescription("Web app")
+	_ = system.AddContainer(container)
+
+	got := SystemKPIsFor(system)
+	if got.Validated {
+		t.Error("expected Validated to be false")
+	}
+	if got.ContainerCount != 1 || got.ComponentCount != 0 || got.Coverage() != 50 {
+		t.Errorf("unexpected KPIs: %+v (coverage %d%%)", got, got.Coverage())
+	}
+}
@@ -0,0 +1,125 @@
+package usecases
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// DefaultUsageWeeks is how many weeks a usage report covers by default.
+const DefaultUsageWeeks = 8
+
+// buildCommand is the command whose durations usage reports track.
+const buildCommand = "build"
+
+// UsageReport summarizes the local usage log over recent weeks.
+type UsageReport struct {
+	Since    time.Time      `json:"since,omitzero"` // First event of the log
+	Runs     int            `json:"runs"`           // Runs in the reported weeks
+	Commands []CommandUsage `json:"commands"`       // Most run first
+	Weeks    []UsageWeek    `json:"weeks"`          // Oldest first, including weeks without runs
+}
+
+// CommandUsage is how often a command ran in the reported weeks.
+type CommandUsage struct {
+	Command  string    `json:"command"`
+	Runs     int       `json:"runs"`
+	Failures int       `json:"failures"` // Runs with a non-zero exit code
+	LastRun  time.Time `json:"last_run"`
+}
+
+// UsageWeek is the usage of one week, starting on Monday.
+type UsageWeek struct {
+	Start          time.Time `json:"start"`
+	Runs           int       `json:"runs"`
+	Builds         int       `json:"builds"`
+	AverageBuildMS int64     `json:"average_build_ms,omitempty"` // Mean duration of the week's builds
+}
+
+// AverageBuild returns the mean duration of the week's builds.
+func (w UsageWeek) AverageBuild() time.Duration {
+	return time.Duration(w.AverageBuildMS) * time.Millisecond
+}
+
+// BuildUsageReport summarizes the opt-in usage log: how often each command
+// runs and how builds evolve week by week, so teams can tell whether their
+// documentation is kept up to date.
+type BuildUsageReport struct {
+	weeks int
+}
+
+// NewBuildUsageReport creates a BuildUsageReport use case covering the last
+// weeks weeks, DefaultUsageWeeks when weeks is not positive.
+func NewBuildUsageReport(weeks int) *BuildUsageReport {
+	if weeks <= 0 {
+		weeks = DefaultUsageWeeks
+	}
+	return &BuildUsageReport{weeks: weeks}
+}
+
+// Execute summarizes events, oldest first, over the weeks up to the one
+// containing now.
+func (uc *BuildUsageReport) Execute(events []*entities.UsageEvent, now time.Time) *UsageReport {
+	report := &UsageReport{Commands: []CommandUsage{}}
+	start := weekStart(now).AddDate(0, 0, -7*(uc.weeks-1))
+	for i := range uc.weeks {
+		report.Weeks = append(report.Weeks, UsageWeek{Start: start.AddDate(0, 0, 7*i)})
+	}
+
+	buildTime := make([]time.Duration, uc.weeks)
+	byCommand := make(map[string]*CommandUsage)
+	for _, event := range events {
+		if report.Since.IsZero() || event.Time.Before(report.Since) {
+			report.Since = event.Time
+		}
+		t := event.Time.In(now.Location())
+		if t.Before(start) || t.After(now) {
+			continue
+		}
+		i := len(report.Weeks) - 1
+		for t.Before(report.Weeks[i].Start) {
+			i--
+		}
+		report.Weeks[i].Runs++
+		report.Runs++
+		if event.Command == buildCommand {
+			report.Weeks[i].Builds++
+			buildTime[i] += event.Duration()
+		}
+
+		usage := byCommand[event.Command]
+		if usage == nil {
+			usage = &CommandUsage{Command: event.Command}
+			byCommand[event.Command] = usage
+		}
+		usage.Runs++
+		if event.ExitCode != 0 {
+			usage.Failures++
+		}
+		if event.Time.After(usage.LastRun) {
+			usage.LastRun = event.Time
+		}
+	}
+
+	for i := range report.Weeks {
+		if report.Weeks[i].Builds > 0 {
+			report.Weeks[i].AverageBuildMS = (buildTime[i] / time.Duration(report.Weeks[i].Builds)).Milliseconds()
+		}
+	}
+	for _, usage := range byCommand {
+		report.Commands = append(report.Commands, *usage)
+	}
+	slices.SortFunc(report.Commands, func(a, b CommandUsage) int {
+		return cmp.Or(cmp.Compare(b.Runs, a.Runs), cmp.Compare(a.Command, b.Command))
+	})
+	return report
+}
+
+// weekStart returns midnight of the Monday starting the week of t.
+func weekStart(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	year, month, day := t.Date()
+	return time.Date(year, month, day-daysSinceMonday, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,62 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestBuildUsageReport(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC) // A Thursday
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 9, 0, 0, 0, time.UTC) }
+	events := []*entities.UsageEvent{
+		{Time: day(time.August, 3), Command: "build", DurationMS: 9000}, // Before the reported weeks
+		{Time: day(time.October, 5), Command: "build", DurationMS: 1000},
+		{Time: day(time.October, 6), Command: "build", DurationMS: 3000},
+		{Time: day(time.October, 6), Command: "new system", ExitCode: 2},
+		{Time: day(time.October, 13), Command: "build", DurationMS: 1500},
+		{Time: day(time.October, 14), Command: "validate"},
+	}
+
+	report := NewBuildUsageReport(2).Execute(events, now)
+
+	if !report.Since.Equal(day(time.August, 3)) {
+		t.Errorf("Since = %v, want the first event", report.Since)
+	}
+	if report.Runs != 5 {
+		t.Errorf("Runs = %d, want 5", report.Runs)
+	}
+	if len(report.Weeks) != 2 {
+		t.Fatalf("expected 2 weeks, got %d", len(report.Weeks))
+	}
+	if first := report.Weeks[0]; !first.Start.Equal(time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)) ||
+		first.Runs != 3 || first.Builds != 2 || first.AverageBuild() != 2*time.Second {
+		t.Errorf("first week = %+v", first)
+	}
+	if last := report.Weeks[1]; last.Runs != 2 || last.Builds != 1 || last.AverageBuildMS != 1500 {
+		t.Errorf("last week = %+v", last)
+	}
+
+	want := []CommandUsage{
+		{Command: "build", Runs: 3, LastRun: day(time.October, 13)},
+		{Command: "new system", Runs: 1, Failures: 1, LastRun: day(time.October, 6)},
+		{Command: "validate", Runs: 1, LastRun: day(time.October, 14)},
+	}
+	if len(report.Commands) != len(want) {
+		t.Fatalf("commands = %+v", report.Commands)
+	}
+	for i := range want {
+		if got := report.Commands[i]; got.Command != want[i].Command || got.Runs != want[i].Runs ||
+			got.Failures != want[i].Failures || !got.LastRun.Equal(want[i].LastRun) {
+			t.Errorf("commands[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestBuildUsageReportEmpty(t *testing.T) {
+	report := NewBuildUsageReport(0).Execute(nil, time.Now())
+	if len(report.Weeks) != DefaultUsageWeeks || report.Runs != 0 || !report.Since.IsZero() {
+		t.Errorf("unexpected report of an empty log: %+v", report)
+	}
+}
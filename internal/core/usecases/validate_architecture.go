@@ -2,6 +2,9 @@ package usecases
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
 )
@@ -12,6 +15,8 @@ import (
 // 2. Isolated components (no relationships)
 // 3. Overly coupled components (too many relationships)
 // 4. Missing relationships (dangling references)
+// 5. Likely duplicate components across containers
+// 6. Sub-components nested deeper than one level
 type ValidateArchitecture struct{}
 
 // NewValidateArchitecture creates a new ValidateArchitecture use case.
@@ -22,7 +27,7 @@ func NewValidateArchitecture() *ValidateArchitecture {
 // ArchitectureIssue represents a single architecture violation or concern.
 type ArchitectureIssue struct {
 	Severity    string   // "error", "warning", "info"
-	Code        string   // "circular_dependency", "isolated_component", "high_coupling", "dangling_reference", "missing_component"
+	Code        string   // "circular_dependency", "isolated_component", "high_coupling", "dangling_reference", "missing_component", "possible_duplicate"
 	Title       string   // Human-readable title
 	Description string   // Detailed description
 	Affected    []string // IDs of affected components
@@ -48,6 +53,7 @@ type ArchitectureReport struct {
 // - High coupling (components with many relationships)
 // - Dangling references (relationships to non-existent components)
 // - Missing components in the hierarchy
+// - Likely duplicate components (see FindDuplicates)
 func (uc *ValidateArchitecture) Execute(
 	graph *entities.ArchitectureGraph,
 	systems []*entities.System,
@@ -83,6 +89,35 @@ func (uc *ValidateArchitecture) Execute(
 	// Check for dangling references
 	uc.checkDanglingReferences(graph, systems, report)
 
+	// Check for likely duplicates
+	uc.checkPossibleDuplicates(systems, report)
+
+	// Check for sub-components nested too deeply
+	uc.checkComponentNesting(systems, report)
+
+	report.summarize()
+	return report
+}
+
+// AddIssue appends an issue found outside the built-in checks, such as by a
+// validator plugin, and updates the counts and summary. Issues with an
+// unknown severity count as warnings.
+func (report *ArchitectureReport) AddIssue(issue ArchitectureIssue) {
+	switch issue.Severity {
+	case "error":
+		report.Errors++
+	case "info":
+		report.Infos++
+	default:
+		issue.Severity = "warning"
+		report.Warnings++
+	}
+	report.Issues = append(report.Issues, issue)
+	report.summarize()
+}
+
+// summarize sets the validity, total and summary from the issue counts.
+func (report *ArchitectureReport) summarize() {
 	// Determine overall validity
 	report.IsValid = report.Errors == 0
 	report.Total = len(report.Issues)
@@ -94,8 +129,6 @@ func (uc *ValidateArchitecture) Execute(
 		report.Summary = fmt.Sprintf("Architecture has %d issue(s): %d error(s), %d warning(s), %d info(s)",
 			report.Total, report.Errors, report.Warnings, report.Infos)
 	}
-
-	return report
 }
 
 // checkCircularDependencies detects cycles in the dependency graph.
@@ -108,7 +141,7 @@ func (uc *ValidateArchitecture) checkCircularDependencies(
 	recStack := make(map[string]bool)         // Recursion stack for cycle detection
 	cyclePathMap := make(map[string][]string) // Store the cycle path for each node
 
-	for nodeID := range graph.Nodes {
+	for _, nodeID := range slices.Sorted(maps.Keys(graph.Nodes)) {
 		if !visited[nodeID] {
 			uc.dfs(nodeID, graph, visited, recStack, []string{}, report, cyclePathMap)
 		}
@@ -194,8 +227,8 @@ func (uc *ValidateArchitecture) checkIsolatedComponents(
 	isolated := make([]string, 0)
 
 	// Only check components (level 3) - systems and containers don't have relationship edges
-	for nodeID, node := range graph.Nodes {
-		if node.Type != "component" {
+	for _, nodeID := range slices.Sorted(maps.Keys(graph.Nodes)) {
+		if graph.Nodes[nodeID].Type != "component" {
 			continue // Skip systems and containers
 		}
 
@@ -231,8 +264,8 @@ func (uc *ValidateArchitecture) checkHighCoupling(
 	highlyCoupled := make([]string, 0)
 
 	// Only check components (level 3) - systems and containers don't have relationship edges
-	for nodeID, node := range graph.Nodes {
-		if node.Type != "component" {
+	for _, nodeID := range slices.Sorted(maps.Keys(graph.Nodes)) {
+		if graph.Nodes[nodeID].Type != "component" {
 			continue // Skip systems and containers
 		}
 
@@ -266,11 +299,11 @@ func (uc *ValidateArchitecture) checkDanglingReferences(
 
 	// Check all components in all systems
 	for _, sys := range systems {
-		for _, container := range sys.Containers {
-			for _, comp := range container.Components {
-				for targetID := range comp.Relationships {
+		for _, container := range sys.ListContainers() {
+			for _, comp := range container.ListComponents() {
+				for _, targetID := range slices.Sorted(maps.Keys(comp.Relationships)) {
 					// Check if target exists in graph
-					if _, exists := graph.Nodes[targetID]; !exists {
+					if !resolvesInGraph(graph, targetID) {
 						if _, ok := danglingRefs[comp.ID]; !ok {
 							danglingRefs[comp.ID] = make([]string, 0)
 						}
@@ -284,9 +317,9 @@ func (uc *ValidateArchitecture) checkDanglingReferences(
 	if len(danglingRefs) > 0 {
 		affected := make([]string, 0)
 		var description string
-		for comp, targets := range danglingRefs {
+		for _, comp := range slices.Sorted(maps.Keys(danglingRefs)) {
 			affected = append(affected, comp)
-			description += fmt.Sprintf("  %s references: %v\n", comp, targets)
+			description += fmt.Sprintf("  %s references: %v\n", comp, danglingRefs[comp])
 		}
 
 		issue := ArchitectureIssue{
@@ -302,6 +335,60 @@ func (uc *ValidateArchitecture) checkDanglingReferences(
 	}
 }
 
+// checkPossibleDuplicates reports components that likely model the same
+// thing in different containers.
+func (uc *ValidateArchitecture) checkPossibleDuplicates(
+	systems []*entities.System,
+	report *ArchitectureReport,
+) {
+	for _, candidate := range NewFindDuplicates().Execute(systems) {
+		report.Issues = append(report.Issues, ArchitectureIssue{
+			Severity:    "info",
+			Code:        "possible_duplicate",
+			Title:       fmt.Sprintf("Possible duplicate components: %s", candidate.Reason),
+			Description: fmt.Sprintf("%s and %s may model the same component.", candidate.A, candidate.B),
+			Affected:    []string{candidate.A, candidate.B},
+			Suggestion:  fmt.Sprintf("If they are the same, merge them with: loko merge %s %s", candidate.A, candidate.B),
+		})
+		report.Infos++
+	}
+}
+
+// checkComponentNesting reports sub-components nested deeper than
+// entities.MaxComponentNesting.
+func (uc *ValidateArchitecture) checkComponentNesting(
+	systems []*entities.System,
+	report *ArchitectureReport,
+) {
+	var affected []string
+	for _, sys := range systems {
+		if sys == nil {
+			continue
+		}
+		for _, container := range sys.Containers {
+			for _, comp := range container.Components {
+				if comp.Depth() > entities.MaxComponentNesting {
+					affected = append(affected, entities.QualifiedNodeID("component", sys.ID, container.ID, comp.ID))
+				}
+			}
+		}
+	}
+	if len(affected) == 0 {
+		return
+	}
+	slices.Sort(affected)
+
+	report.Issues = append(report.Issues, ArchitectureIssue{
+		Severity:    "error",
+		Code:        "component_too_deep",
+		Title:       fmt.Sprintf("%d component(s) nested too deeply", len(affected)),
+		Description: fmt.Sprintf("Components may have sub-components, but sub-components cannot have their own (at most %d level): %s", entities.MaxComponentNesting, strings.Join(affected, ", ")),
+		Affected:    affected,
+		Suggestion:  "Move these components up into their container or their parent's parent component.",
+	})
+	report.Errors++
+}
+
 // Print outputs the validation report to stdout.
 func (report *ArchitectureReport) Print() {
 	fmt.Println()
@@ -366,3 +453,9 @@ func (report *ArchitectureReport) GetIssuesByCode(code string) []ArchitectureIss
 	}
 	return filtered
 }
+
+// resolvesInGraph reports whether a relationship target is a qualified or
+// short ID of a node in graph.
+func resolvesInGraph(graph *entities.ArchitectureGraph, targetID string) bool {
+	return graph.Nodes[targetID] != nil || len(graph.ShortIDMap[targetID]) > 0
+}
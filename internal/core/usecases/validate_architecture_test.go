@@ -1,6 +1,7 @@
 package usecases
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
@@ -236,6 +237,41 @@ func TestValidateArchitectureDanglingReference(t *testing.T) {
 	}
 }
 
+func TestValidateArchitectureShortIDReference(t *testing.T) {
+	sys, _ := entities.NewSystem("Service")
+	cont, _ := entities.NewContainer("API")
+	comp, _ := entities.NewComponent("Service A")
+	comp.AddRelationship("service-b", "calls")
+	cont.AddComponent(comp)
+	sys.AddContainer(cont)
+
+	// The short ID resolves to the qualified node
+	graph := entities.NewArchitectureGraph()
+	graph.Nodes["service/api/service-b"] = &entities.GraphNode{ID: "service/api/service-b", Name: "Service B", Type: "component", Level: 3}
+	graph.ShortIDMap["service-b"] = []string{"service/api/service-b"}
+
+	report := NewValidateArchitecture().Execute(graph, []*entities.System{sys})
+	if issues := report.GetIssuesByCode("dangling_reference"); len(issues) != 0 {
+		t.Errorf("short ID reported as dangling: %+v", issues)
+	}
+}
+
+func TestValidateArchitecturePossibleDuplicate(t *testing.T) {
+	sys := duplicatesModel(t, map[string]map[string]string{
+		"API":    {"Order Store": ""},
+		"Worker": {"order-store": ""},
+	})
+
+	report := NewValidateArchitecture().Execute(entities.NewArchitectureGraph(), []*entities.System{sys})
+	issues := report.GetIssuesByCode("possible_duplicate")
+	if len(issues) != 1 || issues[0].Severity != "info" {
+		t.Fatalf("possible_duplicate issues = %+v, want 1 info", issues)
+	}
+	if want := "loko merge shop/api/order-store shop/worker/order-store"; !strings.Contains(issues[0].Suggestion, want) {
+		t.Errorf("suggestion = %q, want it to contain %q", issues[0].Suggestion, want)
+	}
+}
+
 func TestValidateArchitectureReportSummary(t *testing.T) {
 	uc := NewValidateArchitecture()
 
@@ -290,3 +326,26 @@ func containsID(ids []string, target string) bool {
 	}
 	return false
 }
+
+func TestValidateArchitectureComponentTooDeep(t *testing.T) {
+	sys := duplicatesModel(t, map[string]map[string]string{"API": {"Handler": ""}})
+	api := sys.Containers["api"]
+	parent := "handler"
+	for _, name := range []string{"Validator", "Rules"} {
+		comp, _ := entities.NewComponent(name)
+		comp.SetParent(parent)
+		if err := api.AddComponent(comp); err != nil {
+			t.Fatal(err)
+		}
+		parent = comp.ID
+	}
+
+	report := NewValidateArchitecture().Execute(entities.NewArchitectureGraph(), []*entities.System{sys})
+	issues := report.GetIssuesByCode("component_too_deep")
+	if len(issues) != 1 || issues[0].Severity != "error" {
+		t.Fatalf("component_too_deep issues = %+v, want 1 error", issues)
+	}
+	if affected := issues[0].Affected; len(affected) != 1 || affected[0] != "shop/api/handler.validator.rules" {
+		t.Errorf("affected = %v, want shop/api/handler.validator.rules", affected)
+	}
+}
@@ -0,0 +1,73 @@
+package usecases
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// ValidateDeploymentMapping checks that every container of an internal
+// system maps to at least one deployment target through a deployed-on:<target>
+// tag, so ops reviews catch services nobody documented running anywhere.
+// Containers of external systems are not deployed by the project and are
+// skipped.
+type ValidateDeploymentMapping struct {
+	targets []string
+}
+
+// NewValidateDeploymentMapping creates a ValidateDeploymentMapping use case
+// with the known deployment targets of [deployment] in config. Without known
+// targets, any target is accepted.
+func NewValidateDeploymentMapping(config *entities.ProjectConfig) *ValidateDeploymentMapping {
+	uc := &ValidateDeploymentMapping{}
+	if config != nil {
+		uc.targets = config.DeploymentTargets
+	}
+	return uc
+}
+
+// Execute adds a warning to report for every unmapped container and an error
+// for every deployed-on: tag naming an unknown target.
+func (uc *ValidateDeploymentMapping) Execute(systems []*entities.System, report *ArchitectureReport) {
+	sorted := slices.DeleteFunc(slices.Clone(systems), func(s *entities.System) bool { return s == nil })
+	slices.SortFunc(sorted, func(a, b *entities.System) int { return strings.Compare(a.ID, b.ID) })
+
+	for _, system := range sorted {
+		if system.External {
+			continue
+		}
+		for _, container := range system.ListContainers() {
+			id := system.ID + "/" + container.ID
+			targets := container.DeploymentTargets()
+			if len(targets) == 0 {
+				report.AddIssue(ArchitectureIssue{
+					Severity:    "warning",
+					Code:        "unmapped_container",
+					Title:       "Container without deployment target",
+					Description: fmt.Sprintf("Container %s is not mapped to a deployment target", id),
+					Affected:    []string{id},
+					Suggestion:  fmt.Sprintf("Add a tag such as %s<target> to %s", entities.DeploymentTagPrefix, id),
+				})
+				continue
+			}
+			if len(uc.targets) == 0 {
+				continue
+			}
+			for _, target := range targets {
+				if slices.Contains(uc.targets, target) {
+					continue
+				}
+				report.AddIssue(ArchitectureIssue{
+					Severity:    "error",
+					Code:        "unknown_deployment_target",
+					Title:       "Unknown deployment target",
+					Description: fmt.Sprintf("Container %s is deployed on %q, which is not a known target (%s)", id, target, strings.Join(uc.targets, ", ")),
+					Affected:    []string{id},
+					Suggestion:  "Fix the tag or add the target to [deployment] targets in loko.toml",
+				})
+			}
+		}
+	}
+}
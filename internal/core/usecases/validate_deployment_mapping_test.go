@@ -0,0 +1,44 @@
+package usecases
+
+import (
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestValidateDeploymentMapping(t *testing.T) {
+	orders, _ := entities.NewSystem("Orders")
+	api, _ := entities.NewContainer("API")
+	api.AddTag("deployed-on:eks-prod")
+	worker, _ := entities.NewContainer("Worker")
+	worker.AddTag("deployed-on:ec2")
+	db, _ := entities.NewContainer("Database")
+	_ = orders.AddContainer(api)
+	_ = orders.AddContainer(worker)
+	_ = orders.AddContainer(db)
+
+	stripe, _ := entities.NewSystem("Stripe")
+	stripe.SetExternal(true)
+	gateway, _ := entities.NewContainer("Gateway")
+	_ = stripe.AddContainer(gateway)
+	systems := []*entities.System{stripe, orders}
+
+	// Any target is accepted without known targets
+	report := &ArchitectureReport{IsValid: true}
+	NewValidateDeploymentMapping(nil).Execute(systems, report)
+	if report.Warnings != 1 || len(report.Issues) != 1 {
+		t.Fatalf("issues = %+v, want 1 warning", report.Issues)
+	}
+	if issue := report.Issues[0]; issue.Code != "unmapped_container" || issue.Affected[0] != "orders/database" {
+		t.Errorf("unmapped = %+v", issue)
+	}
+
+	report = &ArchitectureReport{IsValid: true}
+	NewValidateDeploymentMapping(&entities.ProjectConfig{DeploymentTargets: []string{"eks-prod"}}).Execute(systems, report)
+	if report.Errors != 1 || report.Warnings != 1 {
+		t.Fatalf("issues = %+v, want 1 error and 1 warning", report.Issues)
+	}
+	if issue := report.Issues[1]; issue.Code != "unknown_deployment_target" || issue.Affected[0] != "orders/worker" {
+		t.Errorf("unknown target = %+v", issue)
+	}
+}
@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// ValidateDiagramRendering compiles every diagram a build would render, so a
+// broken diagram is reported by validate instead of failing a long build
+// halfway. The .d2 sources are compiled as written, so the line numbers of
+// compile errors match the files.
+type ValidateDiagramRendering struct {
+	compiler    DiagramCompiler
+	projectRoot string
+}
+
+// NewValidateDiagramRendering creates a ValidateDiagramRendering use case
+// reporting diagram paths relative to projectRoot.
+func NewValidateDiagramRendering(compiler DiagramCompiler, projectRoot string) *ValidateDiagramRendering {
+	return &ValidateDiagramRendering{compiler: compiler, projectRoot: projectRoot}
+}
+
+// Execute adds an error to report for every diagram of systems that does not
+// compile. It returns an error only when ctx is cancelled.
+func (uc *ValidateDiagramRendering) Execute(ctx context.Context, systems []*entities.System, report *ArchitectureReport) error {
+	sorted := slices.DeleteFunc(slices.Clone(systems), func(s *entities.System) bool { return s == nil })
+	slices.SortFunc(sorted, func(a, b *entities.System) int { return strings.Compare(a.ID, b.ID) })
+
+	for _, system := range sorted {
+		if err := uc.check(ctx, system.ID, system.Diagram, report); err != nil {
+			return err
+		}
+		for _, container := range system.ListContainers() {
+			containerID := system.ID + "/" + container.ID
+			if err := uc.check(ctx, containerID, container.Diagram, report); err != nil {
+				return err
+			}
+			for _, component := range container.ListComponents() {
+				if err := uc.check(ctx, containerID+"/"+component.ID, component.Diagram, report); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// check compiles diagram, the diagram of element id, and reports it when it
+// fails. Elements without a diagram are skipped.
+func (uc *ValidateDiagramRendering) check(ctx context.Context, id string, diagram *entities.Diagram, report *ArchitectureReport) error {
+	if diagram == nil {
+		return nil
+	}
+	err := uc.compiler.CompileDiagram(ctx, diagram.Source)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if err == nil {
+		return nil
+	}
+
+	path := diagram.SourcePath
+	if rel, relErr := filepath.Rel(uc.projectRoot, path); relErr == nil && !strings.HasPrefix(rel, "..") {
+		path = filepath.ToSlash(rel)
+	}
+	report.AddIssue(ArchitectureIssue{
+		Severity:    "error",
+		Code:        "diagram_compile_error",
+		Title:       "Diagram does not compile",
+		Description: fmt.Sprintf("%s: %v", path, err),
+		Affected:    []string{id},
+		Suggestion:  "Fix the D2 source; the build cannot render this diagram",
+	})
+	return nil
+}
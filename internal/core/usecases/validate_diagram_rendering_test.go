@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+type fakeDiagramCompiler struct {
+	compiled []string
+}
+
+func (c *fakeDiagramCompiler) CompileDiagram(_ context.Context, d2Source string) error {
+	c.compiled = append(c.compiled, d2Source)
+	if strings.Contains(d2Source, "{{") {
+		return errors.New("2:1: unexpected text")
+	}
+	return nil
+}
+
+func TestValidateDiagramRendering(t *testing.T) {
+	root := t.TempDir()
+	diagram := func(path, source string) *entities.Diagram {
+		d, err := entities.NewDiagram(filepath.Join(root, path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		d.SetSource(source)
+		return d
+	}
+
+	system, _ := entities.NewSystem("Payments")
+	system.Diagram = diagram("src/payments/system.d2", "api -> db")
+	container, _ := entities.NewContainer("API")
+	container.Diagram = diagram("src/payments/api/api.d2", "a -> {{")
+	component, _ := entities.NewComponent("Handler")
+	container.Components = map[string]*entities.Component{component.ID: component}
+	system.Containers = map[string]*entities.Container{container.ID: container}
+
+	compiler := &fakeDiagramCompiler{}
+	report := &ArchitectureReport{}
+	if err := NewValidateDiagramRendering(compiler, root).Execute(context.Background(), []*entities.System{system}, report); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if len(compiler.compiled) != 2 {
+		t.Errorf("compiled %d diagrams, want 2", len(compiler.compiled))
+	}
+	if report.Errors != 1 || len(report.Issues) != 1 {
+		t.Fatalf("issues = %+v", report.Issues)
+	}
+	issue := report.Issues[0]
+	if issue.Code != "diagram_compile_error" || issue.Description != "src/payments/api/api.d2: 2:1: unexpected text" {
+		t.Errorf("issue = %+v", issue)
+	}
+	if len(issue.Affected) != 1 || issue.Affected[0] != "payments/api" {
+		t.Errorf("affected = %v", issue.Affected)
+	}
+}
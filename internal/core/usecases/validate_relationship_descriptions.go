@@ -0,0 +1,238 @@
+package usecases
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// Severities of the relationship description rules in loko.toml.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityOff     = "off"
+)
+
+// DefaultDescriptionMaxLength is the longest relationship description allowed
+// when [relationship_descriptions] sets too_long but not max_length.
+const DefaultDescriptionMaxLength = 80
+
+// placeholderDescriptions are descriptions, lowercased and without trailing
+// punctuation, that say nothing about the relationship: markers left to fill
+// in and the generic labels copied from one arrow to the next. The bare verbs
+// "Uses" and "Calls" are not placeholders, since loko writes them itself as
+// default labels of the relationships it creates.
+var placeholderDescriptions = map[string]bool{
+	"todo": true, "tbd": true, "tbc": true, "fixme": true, "xxx": true,
+	"...": true, "?": true, "-": true, "n/a": true, "none": true,
+	"description": true, "relationship": true, "label": true,
+	"depends on": true, "connects to": true, "talks to": true,
+	"interacts with": true, "communicates with": true,
+}
+
+// placeholderMarkerPattern matches placeholder markers anywhere in a
+// description, as in "Sends orders (TODO: which queue?)".
+var placeholderMarkerPattern = regexp.MustCompile(`(?i)\b(todo|tbd|fixme|xxx)\b|lorem ipsum`)
+
+// nonVerbs are words that cannot start a description with a verb: articles,
+// determiners, pronouns, prepositions and conjunctions.
+var nonVerbs = map[string]bool{
+	"a": true, "an": true, "the": true, "this": true, "that": true, "these": true, "those": true,
+	"all": true, "each": true, "every": true, "some": true, "any": true, "no": true,
+	"it": true, "its": true, "they": true, "their": true, "we": true, "our": true,
+	"i": true, "my": true, "you": true, "your": true, "he": true, "his": true, "she": true, "her": true,
+	"via": true, "for": true, "to": true, "from": true, "with": true, "by": true, "of": true,
+	"on": true, "in": true, "at": true, "into": true, "over": true, "through": true, "per": true,
+	"and": true, "or": true, "but": true, "if": true, "when": true, "then": true,
+}
+
+// ValidateRelationshipDescriptions checks the descriptions of relationships
+// against the [relationship_descriptions] rules of loko.toml: that they are
+// set, start with a verb, are no longer than allowed and are not placeholder
+// text. Each rule reports issues at the severity it is configured with and
+// is off until it is set. Diagrams and the relationship graph read poorly
+// when descriptions are missing or copied from arrow to arrow.
+type ValidateRelationshipDescriptions struct {
+	missing     string
+	verbFirst   string
+	placeholder string
+	tooLong     string
+	maxLength   int
+}
+
+// NewValidateRelationshipDescriptions creates a
+// ValidateRelationshipDescriptions use case with the rules of config.
+func NewValidateRelationshipDescriptions(config *entities.ProjectConfig) (*ValidateRelationshipDescriptions, error) {
+	uc := &ValidateRelationshipDescriptions{maxLength: DefaultDescriptionMaxLength}
+	if config == nil {
+		return uc, nil
+	}
+	rules := []struct {
+		key      string
+		severity string
+		field    *string
+	}{
+		{"missing", config.RelDescriptionMissing, &uc.missing},
+		{"verb_first", config.RelDescriptionVerbFirst, &uc.verbFirst},
+		{"placeholder", config.RelDescriptionPlaceholder, &uc.placeholder},
+		{"too_long", config.RelDescriptionTooLong, &uc.tooLong},
+	}
+	for _, rule := range rules {
+		switch rule.severity {
+		case "", SeverityOff:
+		case SeverityError, SeverityWarning:
+			*rule.field = rule.severity
+		default:
+			return nil, fmt.Errorf("%w: [relationship_descriptions] %s %q (expected %q, %q or %q)",
+				entities.ErrInvalidConfig, rule.key, rule.severity, SeverityError, SeverityWarning, SeverityOff)
+		}
+	}
+	if config.RelDescriptionMaxLength < 0 {
+		return nil, fmt.Errorf("%w: [relationship_descriptions] max_length %d", entities.ErrInvalidConfig, config.RelDescriptionMaxLength)
+	}
+	if config.RelDescriptionMaxLength > 0 {
+		uc.maxLength = config.RelDescriptionMaxLength
+	}
+	return uc, nil
+}
+
+// HasRules reports whether any description rule is configured.
+func (uc *ValidateRelationshipDescriptions) HasRules() bool {
+	return uc.missing != "" || uc.verbFirst != "" || uc.placeholder != "" || uc.tooLong != ""
+}
+
+// Execute adds an issue to report for every rule a relationship description
+// breaks. It checks the relationships of graph, drawn in D2 diagrams or
+// declared in frontmatter, and the stored relationships, of relationships.toml,
+// that do not relate the same elements.
+func (uc *ValidateRelationshipDescriptions) Execute(graph *entities.ArchitectureGraph, relationships []entities.Relationship, report *ArchitectureReport) {
+	if !uc.HasRules() {
+		return
+	}
+	checked := make(map[string]bool)
+	if graph != nil {
+		for _, source := range slices.Sorted(maps.Keys(graph.Edges)) {
+			edges := slices.Clone(graph.Edges[source])
+			slices.SortFunc(edges, func(a, b *entities.GraphEdge) int { return strings.Compare(a.Target, b.Target) })
+			for _, edge := range edges {
+				checked[edge.Source+"->"+edge.Target] = true
+				uc.check(edge.Source, edge.Target, edge.Description, report)
+			}
+		}
+	}
+	for _, rel := range relationships {
+		if checked[rel.Source+"->"+rel.Target] {
+			continue
+		}
+		checked[rel.Source+"->"+rel.Target] = true
+		uc.check(rel.Source, rel.Target, rel.Label, report)
+	}
+}
+
+// check reports the rules the description of the relationship from source
+// to target breaks. A placeholder is not also reported for its first word.
+func (uc *ValidateRelationshipDescriptions) check(source, target, description string, report *ArchitectureReport) {
+	relationship := source + " -> " + target
+	affected := []string{source, target}
+	description = strings.TrimSpace(description)
+
+	if description == "" {
+		if uc.missing != "" {
+			report.AddIssue(ArchitectureIssue{
+				Severity:    uc.missing,
+				Code:        "missing_relationship_description",
+				Title:       "Relationship without description",
+				Description: fmt.Sprintf("%s has no description", relationship),
+				Affected:    affected,
+				Suggestion:  "Describe what the source does with the target, e.g. \"Reads orders from\"",
+			})
+		}
+		return
+	}
+
+	placeholder := IsPlaceholderDescription(description)
+	if placeholder && uc.placeholder != "" {
+		report.AddIssue(ArchitectureIssue{
+			Severity:    uc.placeholder,
+			Code:        "placeholder_relationship_description",
+			Title:       "Placeholder relationship description",
+			Description: fmt.Sprintf("%s: %q is placeholder text", relationship, description),
+			Affected:    affected,
+			Suggestion:  "Replace it with what the source does with the target",
+		})
+	}
+	if !placeholder && uc.verbFirst != "" && !StartsWithVerb(description) {
+		report.AddIssue(ArchitectureIssue{
+			Severity:    uc.verbFirst,
+			Code:        "relationship_description_not_verb_first",
+			Title:       "Relationship description does not start with a verb",
+			Description: fmt.Sprintf("%s: %q does not start with a verb", relationship, description),
+			Affected:    affected,
+			Suggestion:  "Start with what the source does, e.g. \"Sends\", \"Reads\" or \"Publishes\"",
+		})
+	}
+	if length := len([]rune(description)); uc.tooLong != "" && length > uc.maxLength {
+		report.AddIssue(ArchitectureIssue{
+			Severity:    uc.tooLong,
+			Code:        "relationship_description_too_long",
+			Title:       "Relationship description too long",
+			Description: fmt.Sprintf("%s: description has %d characters, more than %d", relationship, length, uc.maxLength),
+			Affected:    affected,
+			Suggestion:  fmt.Sprintf("Shorten it to %d characters or fewer and move details to the element's documentation", uc.maxLength),
+		})
+	}
+}
+
+// IsPlaceholderDescription reports whether description is placeholder text,
+// such as "TODO" or "TBD", or a generic label such as "talks to" that only
+// repeats what the arrow says.
+func IsPlaceholderDescription(description string) bool {
+	normalized := strings.ToLower(strings.TrimSpace(description))
+	if placeholderDescriptions[normalized] {
+		return true
+	}
+	if placeholderDescriptions[strings.TrimRight(normalized, ".!:; ")] {
+		return true
+	}
+	return placeholderMarkerPattern.MatchString(description)
+}
+
+// StartsWithVerb reports whether description may start with a verb. Without
+// a dictionary it is a heuristic: the description does not when its first
+// word is an article, determiner, pronoun, preposition or conjunction ("The
+// orders API", "via HTTPS"), an acronym ("REST calls") or a number, or when
+// it does not start with a word at all.
+func StartsWithVerb(description string) bool {
+	fields := strings.Fields(description)
+	if len(fields) == 0 {
+		return false
+	}
+	word := strings.TrimRightFunc(fields[0], func(r rune) bool { return !unicode.IsLetter(r) })
+	if word == "" || !unicode.IsLetter([]rune(word)[0]) {
+		return false
+	}
+	if nonVerbs[strings.ToLower(word)] {
+		return false
+	}
+	return !isAcronym(word)
+}
+
+// isAcronym reports whether word has two or more letters, all upper case,
+// as in "API" or "HTTP2".
+func isAcronym(word string) bool {
+	letters := 0
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			if !unicode.IsUpper(r) {
+				return false
+			}
+			letters++
+		}
+	}
+	return letters >= 2
+}
@@ -0,0 +1,129 @@
+package usecases
+
+import (
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestValidateRelationshipDescriptions(t *testing.T) {
+	graph := entities.NewArchitectureGraph()
+	for _, id := range []string{"shop", "shop/api", "shop/db", "shop/queue", "shop/web", "payments"} {
+		if err := graph.AddNode(&entities.GraphNode{ID: id, Type: "container", Name: id}); err != nil {
+			t.Fatalf("AddNode failed: %v", err)
+		}
+	}
+	for _, edge := range []*entities.GraphEdge{
+		{Source: "shop/web", Target: "shop/api", Description: "Sends orders to"},
+		{Source: "shop/api", Target: "shop/db", Description: ""},
+		{Source: "shop/api", Target: "shop/queue", Description: "TBD"},
+		{Source: "shop/api", Target: "payments", Description: "via HTTPS, charging each order once the customer confirmed it"},
+	} {
+		if err := graph.AddEdge(edge); err != nil {
+			t.Fatalf("AddEdge failed: %v", err)
+		}
+	}
+	stored := []entities.Relationship{
+		{Source: "shop/api", Target: "shop/db", Label: "Stores orders in"}, // Checked in the graph
+		{Source: "shop/web", Target: "shop/queue", Label: "talks to"},
+	}
+
+	uc, err := NewValidateRelationshipDescriptions(&entities.ProjectConfig{
+		RelDescriptionMissing:     "error",
+		RelDescriptionVerbFirst:   "warning",
+		RelDescriptionPlaceholder: "error",
+		RelDescriptionTooLong:     "warning",
+		RelDescriptionMaxLength:   40,
+	})
+	if err != nil {
+		t.Fatalf("NewValidateRelationshipDescriptions failed: %v", err)
+	}
+	report := &ArchitectureReport{}
+	uc.Execute(graph, stored, report)
+
+	want := []struct{ code, severity, source, target string }{
+		{"relationship_description_not_verb_first", "warning", "shop/api", "payments"},
+		{"relationship_description_too_long", "warning", "shop/api", "payments"},
+		{"missing_relationship_description", "error", "shop/api", "shop/db"},
+		{"placeholder_relationship_description", "error", "shop/api", "shop/queue"},
+		{"placeholder_relationship_description", "error", "shop/web", "shop/queue"},
+	}
+	if len(report.Issues) != len(want) {
+		t.Fatalf("got %d issues, want %d: %+v", len(report.Issues), len(want), report.Issues)
+	}
+	for i, w := range want {
+		issue := report.Issues[i]
+		if issue.Code != w.code || issue.Severity != w.severity || issue.Affected[0] != w.source || issue.Affected[1] != w.target {
+			t.Errorf("issue %d = %+v, want %s %s on %s -> %s", i, issue, w.severity, w.code, w.source, w.target)
+		}
+	}
+	if report.Errors != 3 || report.Warnings != 2 {
+		t.Errorf("errors, warnings = %d, %d; want 3, 2", report.Errors, report.Warnings)
+	}
+}
+
+func TestValidateRelationshipDescriptions_NoRules(t *testing.T) {
+	graph := entities.NewArchitectureGraph()
+	_ = graph.AddNode(&entities.GraphNode{ID: "a", Type: "system", Name: "A"})
+	_ = graph.AddNode(&entities.GraphNode{ID: "b", Type: "system", Name: "B"})
+	_ = graph.AddEdge(&entities.GraphEdge{Source: "a", Target: "b"})
+
+	uc, err := NewValidateRelationshipDescriptions(&entities.ProjectConfig{RelDescriptionMissing: "off"})
+	if err != nil {
+		t.Fatalf("NewValidateRelationshipDescriptions failed: %v", err)
+	}
+	if uc.HasRules() {
+		t.Error("HasRules() = true")
+	}
+	report := &ArchitectureReport{}
+	uc.Execute(graph, nil, report)
+	if len(report.Issues) != 0 {
+		t.Errorf("issues = %+v", report.Issues)
+	}
+}
+
+func TestNewValidateRelationshipDescriptions_InvalidConfig(t *testing.T) {
+	if _, err := NewValidateRelationshipDescriptions(&entities.ProjectConfig{RelDescriptionVerbFirst: "info"}); err == nil {
+		t.Error("expected error for unknown severity")
+	}
+	if _, err := NewValidateRelationshipDescriptions(&entities.ProjectConfig{RelDescriptionMaxLength: -1}); err == nil {
+		t.Error("expected error for negative max_length")
+	}
+}
+
+func TestStartsWithVerb(t *testing.T) {
+	tests := map[string]bool{
+		"Reads orders from":     true,
+		"validates tokens via":  true,
+		"Publishes OrderPlaced": true,
+		"The orders API":        false,
+		"via HTTPS":             false,
+		"REST calls":            false,
+		"3 retries":             false,
+		"[async] sends":         false,
+	}
+	for description, want := range tests {
+		if got := StartsWithVerb(description); got != want {
+			t.Errorf("StartsWithVerb(%q) = %v, want %v", description, got, want)
+		}
+	}
+}
+
+func TestIsPlaceholderDescription(t *testing.T) {
+	tests := map[string]bool{
+		"TODO":                        true,
+		"Talks to.":                   true,
+		"Uses":                        false,
+		"depends on":                  true,
+		"Sends orders (TBD: format)":  true,
+		"Uses the payment API":        false,
+		"Stores todos in":             false,
+		"Reads the to-do list from":   false,
+		"Charges cards through Adyen": false,
+	}
+	for description, want := range tests {
+		if got := IsPlaceholderDescription(description); got != want {
+			t.Errorf("IsPlaceholderDescription(%q) = %v, want %v", description, got, want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package usecases
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// ValidateRelationshipKinds checks that relationships with a semantic kind
+// connect the kinds of element it allows, e.g. that only components publish,
+// and only to event elements.
+type ValidateRelationshipKinds struct {
+	kinds map[string]entities.RelationshipKind
+}
+
+// NewValidateRelationshipKinds creates a ValidateRelationshipKinds use case
+// with the built-in kinds and the [relationship_types] definitions in config.
+func NewValidateRelationshipKinds(config *entities.ProjectConfig) (*ValidateRelationshipKinds, error) {
+	var custom map[string]string
+	if config != nil {
+		custom = config.RelationshipKinds
+	}
+	kinds, err := entities.RelationshipKinds(custom)
+	if err != nil {
+		return nil, fmt.Errorf("%w: [relationship_types]: %w", entities.ErrInvalidConfig, err)
+	}
+	return &ValidateRelationshipKinds{kinds: kinds}, nil
+}
+
+// CheckKind returns an error naming the known kinds when kind is set but
+// unknown.
+func (uc *ValidateRelationshipKinds) CheckKind(kind string) error {
+	if kind == "" {
+		return nil
+	}
+	if _, ok := uc.kinds[kind]; !ok {
+		return fmt.Errorf("unknown relationship kind %q (expected %s, or define it in [relationship_types])",
+			kind, strings.Join(slices.Sorted(maps.Keys(uc.kinds)), ", "))
+	}
+	return nil
+}
+
+// Execute adds an error to report for every relationship of an unknown kind
+// or between elements its kind does not allow. Relationships whose elements
+// do not exist are left to the dangling reference check.
+func (uc *ValidateRelationshipKinds) Execute(systems []*entities.System, relationships []entities.Relationship, report *ArchitectureReport) {
+	for _, rel := range relationships {
+		if rel.Kind == "" {
+			continue
+		}
+		affected := []string{rel.Source, rel.Target}
+		if err := uc.CheckKind(rel.Kind); err != nil {
+			report.AddIssue(ArchitectureIssue{
+				Severity:    "error",
+				Code:        "unknown_relationship_kind",
+				Title:       "Unknown relationship kind",
+				Description: fmt.Sprintf("%s -> %s: %v", rel.Source, rel.Target, err),
+				Affected:    affected,
+				Suggestion:  "Use a built-in kind or add it to [relationship_types] in loko.toml",
+			})
+			continue
+		}
+
+		source, target := ElementKinds(systems, rel.Source), ElementKinds(systems, rel.Target)
+		if source == nil || target == nil {
+			continue
+		}
+		kind := uc.kinds[rel.Kind]
+		if kind.Allows(source, target) {
+			continue
+		}
+
+		suggestion := fmt.Sprintf("%q relates %s", kind.Name, kind)
+		if kind.Allows(target, source) {
+			suggestion = fmt.Sprintf("Swap source and target: %s %s %s", rel.Target, kind.Name, rel.Source)
+		}
+		report.AddIssue(ArchitectureIssue{
+			Severity: "error",
+			Code:     "invalid_relationship_kind",
+			Title:    "Relationship kind does not fit its elements",
+			Description: fmt.Sprintf("%s (%s) %s %s (%s) is not allowed",
+				rel.Source, strings.Join(source, ", "), kind.Name, rel.Target, strings.Join(target, ", ")),
+			Affected:   affected,
+			Suggestion: suggestion,
+		})
+	}
+}
+
+// ElementKinds returns the kinds of the element at a slash-separated path
+// ("system", "system/container" or "system/container/component"), or nil
+// when no such element exists. Containers and components tagged "event" are
+// also events.
+func ElementKinds(systems []*entities.System, path string) []string {
+	parts := strings.Split(path, "/")
+	for _, sys := range systems {
+		if sys == nil || sys.ID != parts[0] {
+			continue
+		}
+		if len(parts) == 1 {
+			return []string{entities.ElementSystem}
+		}
+		container, ok := sys.Containers[parts[1]]
+		if !ok {
+			return nil
+		}
+		if len(parts) == 2 {
+			return withEventKind([]string{entities.ElementContainer}, container.Tags)
+		}
+		component, ok := container.Components[parts[2]]
+		if !ok || len(parts) > 3 {
+			return nil
+		}
+		return withEventKind([]string{entities.ElementComponent}, component.Tags)
+	}
+	return nil
+}
+
+// withEventKind adds the event kind when tags include "event".
+func withEventKind(kinds, tags []string) []string {
+	for _, tag := range tags {
+		if strings.EqualFold(tag, entities.ElementEvent) {
+			return append(kinds, entities.ElementEvent)
+		}
+	}
+	return kinds
+}
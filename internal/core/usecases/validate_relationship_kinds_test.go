@@ -0,0 +1,64 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestValidateRelationshipKinds(t *testing.T) {
+	orders, _ := entities.NewSystem("Orders")
+	api, _ := entities.NewContainer("API")
+	checkout, _ := entities.NewComponent("Checkout")
+	placed, _ := entities.NewComponent("Order Placed")
+	placed.Tags = []string{"Event"}
+	_ = api.AddComponent(checkout)
+	_ = api.AddComponent(placed)
+	_ = orders.AddContainer(api)
+	systems := []*entities.System{orders}
+
+	uc, err := NewValidateRelationshipKinds(&entities.ProjectConfig{
+		RelationshipKinds: map[string]string{"deploys": "container -> system"},
+	})
+	if err != nil {
+		t.Fatalf("NewValidateRelationshipKinds failed: %v", err)
+	}
+
+	component := orders.ID + "/" + api.ID + "/" + checkout.ID
+	event := orders.ID + "/" + api.ID + "/" + placed.ID
+	container := orders.ID + "/" + api.ID
+	rels := []entities.Relationship{
+		{Source: component, Target: event, Kind: "publishes"},
+		{Source: container, Target: orders.ID, Kind: "deploys"},
+		{Source: component, Target: container},
+		{Source: component, Target: "missing/thing", Kind: "reads"},
+		{Source: event, Target: component, Kind: "publishes"},
+		{Source: component, Target: orders.ID, Kind: "calls"},
+	}
+	report := &ArchitectureReport{IsValid: true}
+	uc.Execute(systems, rels, report)
+
+	if report.Errors != 2 || len(report.Issues) != 2 {
+		t.Fatalf("issues = %+v, want 2 errors", report.Issues)
+	}
+	reversed, unknown := report.Issues[0], report.Issues[1]
+	if reversed.Code != "invalid_relationship_kind" || !strings.HasPrefix(reversed.Suggestion, "Swap source and target") {
+		t.Errorf("reversed publishes = %+v", reversed)
+	}
+	if unknown.Code != "unknown_relationship_kind" || !strings.Contains(unknown.Description, "deploys") {
+		t.Errorf("unknown kind = %+v", unknown)
+	}
+	if report.IsValid {
+		t.Error("report should be invalid")
+	}
+}
+
+func TestNewValidateRelationshipKinds_InvalidConfig(t *testing.T) {
+	_, err := NewValidateRelationshipKinds(&entities.ProjectConfig{
+		RelationshipKinds: map[string]string{"deploys": "container"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "[relationship_types]") {
+		t.Errorf("err = %v, want [relationship_types] error", err)
+	}
+}
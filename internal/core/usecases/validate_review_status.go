@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// ValidateReviewStatus checks that every element about to be published has
+// been approved in the review workflow. An element without a review_status
+// inherits the status of its parent, so approving a system approves the
+// containers and components that were not reviewed on their own; systems
+// without a status are drafts. An element is reported where its status is
+// set, not again for each element inheriting it.
+type ValidateReviewStatus struct{}
+
+// NewValidateReviewStatus creates a ValidateReviewStatus use case.
+func NewValidateReviewStatus() *ValidateReviewStatus {
+	return &ValidateReviewStatus{}
+}
+
+// Execute adds an error to report for every element that is not approved and
+// for every invalid review_status.
+func (uc *ValidateReviewStatus) Execute(systems []*entities.System, report *ArchitectureReport) {
+	sorted := slices.DeleteFunc(slices.Clone(systems), func(s *entities.System) bool { return s == nil })
+	slices.SortFunc(sorted, func(a, b *entities.System) int { return strings.Compare(a.ID, b.ID) })
+
+	for _, system := range sorted {
+		systemStatus := uc.check(system.ID, system.Review, "", report)
+		for _, container := range system.ListContainers() {
+			containerID := system.ID + "/" + container.ID
+			containerStatus := uc.check(containerID, container.Review, systemStatus, report)
+			for _, component := range container.ListComponents() {
+				uc.check(containerID+"/"+component.ID, component.Review, containerStatus, report)
+			}
+		}
+	}
+}
+
+// check reports the element id unless it is approved or inherits the status
+// of its parent, and returns its effective status. inherited is empty for
+// systems.
+func (uc *ValidateReviewStatus) check(id string, review entities.Review, inherited entities.ReviewStatus, report *ArchitectureReport) entities.ReviewStatus {
+	if review.Status == "" && inherited != "" {
+		return inherited
+	}
+	status := entities.ReviewDraft
+	if review.Status != "" {
+		parsed, err := entities.ParseReviewStatus(string(review.Status))
+		if err != nil {
+			report.AddIssue(ArchitectureIssue{
+				Severity:    "error",
+				Code:        "invalid_review_status",
+				Title:       "Invalid review status",
+				Description: fmt.Sprintf("%s: %v", id, err),
+				Affected:    []string{id},
+				Suggestion:  fmt.Sprintf("Set %s to %s, %s or %s", entities.ReviewStatusField, entities.ReviewDraft, entities.ReviewInReview, entities.ReviewApproved),
+			})
+			return entities.ReviewDraft
+		}
+		status = parsed
+	}
+	if status != entities.ReviewApproved {
+		report.AddIssue(ArchitectureIssue{
+			Severity:    "error",
+			Code:        "unapproved_element",
+			Title:       "Element not approved",
+			Description: fmt.Sprintf("%s is %s and cannot be published", id, status),
+			Affected:    []string{id},
+			Suggestion:  fmt.Sprintf("Review it and run loko review approve %s", id),
+		})
+	}
+	return status
+}
@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestValidateReviewStatus(t *testing.T) {
+	payments, _ := entities.NewSystem("Payments")
+	payments.Review.Status = entities.ReviewApproved
+	api, _ := entities.NewContainer("API")
+	handler, _ := entities.NewComponent("Handler")
+	handler.Review.Status = entities.ReviewInReview
+	_ = api.AddComponent(handler)
+	worker, _ := entities.NewContainer("Worker")
+	worker.Review.Status = "done"
+	_ = payments.AddContainer(api)
+	_ = payments.AddContainer(worker)
+
+	billing, _ := entities.NewSystem("Billing")
+	ledger, _ := entities.NewContainer("Ledger")
+	_ = billing.AddContainer(ledger)
+
+	report := &ArchitectureReport{}
+	NewValidateReviewStatus().Execute([]*entities.System{payments, billing}, report)
+
+	var got []string
+	for _, issue := range report.Issues {
+		got = append(got, issue.Code+" "+issue.Affected[0])
+	}
+	// The API inherits the approval of Payments; the Ledger inherits the
+	// draft status of Billing and is covered by its issue.
+	want := []string{
+		"unapproved_element billing",
+		"unapproved_element payments/api/handler",
+		"invalid_review_status payments/worker",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("issues = %q, want %q", got, want)
+	}
+}
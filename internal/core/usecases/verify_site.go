@@ -0,0 +1,236 @@
+package usecases
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// SiteProblem is a reason a built site fails its smoke test.
+type SiteProblem struct {
+	Path   string // File concerned, slash-separated and relative to the output directory
+	Reason string
+}
+
+// SiteVerifyReport is the result of smoke testing a built site.
+type SiteVerifyReport struct {
+	Pages         int           // HTML pages scanned
+	Diagrams      int           // SVG files checked
+	SearchEntries int           // Results in the search index
+	Problems      []SiteProblem // Sorted by path
+}
+
+// OK reports whether the site passed every check.
+func (r *SiteVerifyReport) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// VerifySite smoke tests a built HTML site, e.g. as a CI gate after loko
+// build: the home page, a page and diagram for every element of the model,
+// a search index whose results resolve, SVG files that hold a drawing, and
+// pages free of scaffolding placeholders.
+type VerifySite struct {
+	basePath string
+}
+
+// NewVerifySite creates a new VerifySite use case.
+func NewVerifySite() *VerifySite {
+	return &VerifySite{}
+}
+
+// WithBasePath resolves the root-relative URLs of the search index of a site
+// served under basePath, such as "/architecture/".
+func (uc *VerifySite) WithBasePath(basePath string) *VerifySite {
+	uc.basePath = basePath
+	return uc
+}
+
+// placeholderPatterns match text of the scaffolding templates, and of
+// validate --fix, that is meant to be replaced before publishing.
+var placeholderPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\{\{\s*[A-Za-z_][A-Za-z0-9_]*\s*\}\}`),
+	regexp.MustCompile(`TODO: describe\b`),
+}
+
+// htmlCommentPattern matches an HTML comment.
+var htmlCommentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+// Execute checks the site in outputDir. When systems is nil, the pages and
+// diagrams of the model's elements are not checked, e.g. for a site verified
+// without its sources.
+func (uc *VerifySite) Execute(ctx context.Context, outputDir string, systems []*entities.System) (*SiteVerifyReport, error) {
+	if info, err := os.Stat(outputDir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", outputDir)
+	}
+	report := &SiteVerifyReport{}
+	problem := func(file, reason string, args ...any) {
+		report.Problems = append(report.Problems, SiteProblem{Path: file, Reason: fmt.Sprintf(reason, args...)})
+	}
+
+	if !nonEmptyFile(filepath.Join(outputDir, "index.html")) {
+		problem("index.html", "missing or empty home page")
+	}
+	if systems != nil {
+		uc.checkElements(outputDir, systems, problem)
+	}
+	if err := uc.checkSearchIndex(outputDir, report, problem); err != nil {
+		return nil, err
+	}
+
+	err := filepath.WalkDir(outputDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(p))
+		if ext != ".html" && ext != ".svg" {
+			return nil
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(outputDir, p)
+		if err != nil {
+			return err
+		}
+		file := filepath.ToSlash(rel)
+
+		if ext == ".svg" {
+			report.Diagrams++
+			switch {
+			case len(bytes.TrimSpace(content)) == 0:
+				problem(file, "empty SVG")
+			case !bytes.Contains(content, []byte("<svg")):
+				problem(file, "not an SVG drawing")
+			}
+			return nil
+		}
+
+		report.Pages++
+		text := scriptBodyPattern.ReplaceAllString(string(content), "$1")
+		text = htmlCommentPattern.ReplaceAllString(text, "")
+		for _, pattern := range placeholderPatterns {
+			if match := pattern.FindString(text); match != "" {
+				problem(file, "scaffolding placeholder %q", match)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", outputDir, err)
+	}
+
+	slices.SortStableFunc(report.Problems, func(a, b SiteProblem) int {
+		return cmp.Compare(a.Path, b.Path)
+	})
+	return report, nil
+}
+
+// checkElements reports the missing pages and diagrams of the elements of
+// systems. A page may be written flat, as systems/payments.html, or with
+// pretty URLs, as systems/payments/index.html.
+func (uc *VerifySite) checkElements(outputDir string, systems []*entities.System, problem func(file, reason string, args ...any)) {
+	page := func(file, element string) {
+		pretty := strings.TrimSuffix(file, ".html") + "/index.html"
+		if !nonEmptyFile(filepath.Join(outputDir, filepath.FromSlash(file))) && !nonEmptyFile(filepath.Join(outputDir, filepath.FromSlash(pretty))) {
+			problem(file, "missing page of %s", element)
+		}
+	}
+	diagram := func(d *entities.Diagram, file, element string) {
+		if d == nil || strings.TrimSpace(d.Source) == "" {
+			return
+		}
+		file = path.Join("diagrams", file)
+		if _, err := os.Stat(filepath.Join(outputDir, filepath.FromSlash(file))); err != nil {
+			problem(file, "missing diagram of %s", element)
+		}
+	}
+
+	for _, system := range systems {
+		if system == nil {
+			continue
+		}
+		page("systems/"+system.ID+".html", "system "+system.ID)
+		diagram(system.Diagram, system.ID+".svg", "system "+system.ID)
+		for _, container := range system.ListContainers() {
+			containerID := system.ID + "/" + container.ID
+			page("containers/"+system.ID+"_"+container.ID+".html", "container "+containerID)
+			diagram(container.Diagram, system.ID+"_"+container.ID+".svg", "container "+containerID)
+			for _, component := range container.ListComponents() {
+				componentID := containerID + "/" + component.ID
+				page("components/"+component.ID+".html", "component "+componentID)
+				diagram(component.Diagram, system.ID+"_"+container.ID+"_"+component.ID+".svg", "component "+componentID)
+			}
+		}
+	}
+}
+
+// checkSearchIndex reports a missing or malformed search index or shard,
+// results without a title or URL, and result URLs that do not resolve.
+func (uc *VerifySite) checkSearchIndex(outputDir string, report *SiteVerifyReport, problem func(file, reason string, args ...any)) error {
+	content, err := os.ReadFile(filepath.Join(outputDir, SearchIndexFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		problem(SearchIndexFile, "missing search index")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read search index: %w", err)
+	}
+
+	var index siteSearchIndex
+	if err := json.Unmarshal(content, &index); err != nil {
+		problem(SearchIndexFile, "invalid JSON: %v", err)
+		return nil
+	}
+	if len(index.Results) == 0 {
+		problem(SearchIndexFile, "search index has no results")
+	}
+	files := []siteSearchFile{{SearchIndexFile, index.Results}}
+	for _, shard := range index.Shards {
+		file, err := readSearchShard(outputDir, shard.Path)
+		if err != nil {
+			problem(SearchIndexFile, "shard %s: %v", shard.Path, err)
+			continue
+		}
+		files = append(files, file)
+	}
+
+	links := NewCheckSiteLinks().WithBasePath(uc.basePath)
+	for _, file := range files {
+		report.SearchEntries += len(file.results)
+		for i, result := range file.results {
+			switch {
+			case strings.TrimSpace(result.Title) == "":
+				problem(file.path, "result %d has no title", i+1)
+			case result.URL == "":
+				problem(file.path, "result %q has no URL", result.Title)
+			case linkKind(result.URL) == "internal":
+				if reason := links.resolve(outputDir, ".", result.URL); reason != "" {
+					problem(file.path, "result %q links to %s: %s", result.Title, result.URL, reason)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// nonEmptyFile reports whether name is a regular file with content.
+func nonEmptyFile(name string) bool {
+	info, err := os.Stat(name)
+	return err == nil && info.Mode().IsRegular() && info.Size() > 0
+}
@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+func TestVerifySite(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"index.html":                 `<h1>Home</h1><script>render("{{title}}")</script><!-- {{SystemName}} -->`,
+		"systems/shop.html":          `<h1>Shop</h1><p>TODO: describe Shop</p>`,
+		"containers/shop_api.html":   `<h1>API</h1>`,
+		"components/auth/index.html": `<h1>Auth</h1><p>Uses {{Framework}}</p>`,
+		"diagrams/shop.svg":          `<svg xmlns="http://www.w3.org/2000/svg"></svg>`,
+		"diagrams/shop_api.svg":      "",
+		"search.json": `{"results": [{"title": "Shop", "url": "systems/shop.html"}, {"title": "Gone", "url": "systems/gone.html"}, {"url": "index.html"}],
+			"shards": [{"system": "shop", "path": "search/shop.0123456789.json"}, {"system": "old", "path": "search/old.0123456789.json"}]}`,
+		"search/shop.0123456789.json": `{"results": [{"title": "API", "url": "systems/shop.html#api"}, {"title": "Worker", "url": "systems/worker.html"}]}`,
+	})
+
+	shop, _ := entities.NewSystem("Shop")
+	shop.Diagram = &entities.Diagram{Source: "a -> b"}
+	api, _ := entities.NewContainer("API")
+	api.Diagram = &entities.Diagram{Source: "a -> b"}
+	auth, _ := entities.NewComponent("Auth")
+	auth.Diagram = &entities.Diagram{Source: "a -> b"}
+	cache, _ := entities.NewComponent("Cache")
+	api.Components = map[string]*entities.Component{auth.ID: auth, cache.ID: cache}
+	shop.Containers = map[string]*entities.Container{api.ID: api}
+
+	report, err := NewVerifySite().Execute(context.Background(), dir, []*entities.System{shop})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if report.Pages != 4 || report.Diagrams != 2 || report.SearchEntries != 5 {
+		t.Errorf("pages = %d, diagrams = %d, search entries = %d", report.Pages, report.Diagrams, report.SearchEntries)
+	}
+
+	var got []string
+	for _, p := range report.Problems {
+		got = append(got, p.Path+": "+p.Reason)
+	}
+	want := []string{
+		"components/auth/index.html: scaffolding placeholder \"{{Framework}}\"",
+		"components/cache.html: missing page of component shop/api/cache",
+		"diagrams/shop_api.svg: empty SVG",
+		"diagrams/shop_api_auth.svg: missing diagram of component shop/api/auth",
+		"search.json: shard search/old.0123456789.json: search shard not found in the output directory",
+		"search.json: result \"Gone\" links to systems/gone.html: not found in the output directory",
+		"search.json: result 3 has no title",
+		"search/shop.0123456789.json: result \"Worker\" links to systems/worker.html: not found in the output directory",
+		"systems/shop.html: scaffolding placeholder \"TODO: describe\"",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("problems =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestVerifySite_WithoutModel(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"index.html":  `<h1>Home</h1>`,
+		"search.json": `not json`,
+	})
+
+	report, err := NewVerifySite().Execute(context.Background(), dir, nil)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(report.Problems) != 1 || report.Problems[0].Path != SearchIndexFile {
+		t.Errorf("problems = %+v", report.Problems)
+	}
+
+	if _, err := NewVerifySite().Execute(context.Background(), dir+"/missing", nil); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
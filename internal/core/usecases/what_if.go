@@ -0,0 +1,223 @@
+package usecases
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// WhatIfEdge is a relationship, or a path of relationships, between two
+// elements.
+type WhatIfEdge struct {
+	Source      string `json:"source"`
+	Target      string `json:"target"`
+	Description string `json:"description,omitempty"`
+}
+
+// WhatIfReport is the outcome of a hypothetical change to an architecture
+// graph.
+type WhatIfReport struct {
+	Change string `json:"change"` // "remove <id>" or "add-edge <source> <target>"
+
+	// Removed lists the elements a removal takes away: the element and its
+	// children.
+	Removed []string `json:"removed,omitempty"`
+	// BrokenRelationships lists the relationships of remaining elements to
+	// removed ones.
+	BrokenRelationships []WhatIfEdge `json:"broken_relationships"`
+	// BrokenPaths lists the remaining elements that reached each other,
+	// directly or transitively, and no longer do.
+	BrokenPaths []WhatIfEdge `json:"broken_paths"`
+	// NewCycles lists the dependency cycles the change creates, each from
+	// and back to the same element.
+	NewCycles [][]string `json:"new_cycles"`
+
+	Impacted []GraphNodeRef `json:"impacted"` // Elements whose dependencies change, nearest first
+	Owners   []string       `json:"owners"`   // Owners of the impacted elements
+}
+
+// WhatIf applies hypothetical changes to an in-memory copy of an
+// architecture graph and reports what they would break: relationships and
+// paths that no longer resolve, dependency cycles they create and the
+// elements and owners they impact. The graph itself is left unchanged and
+// nothing is written to disk.
+type WhatIf struct {
+	graph *entities.ArchitectureGraph
+}
+
+// NewWhatIf creates a WhatIf use case over graph.
+func NewWhatIf(graph *entities.ArchitectureGraph) *WhatIf {
+	return &WhatIf{graph: graph}
+}
+
+// Remove reports what removing the element id, and its children with it,
+// would break.
+func (uc *WhatIf) Remove(id string) (*WhatIfReport, error) {
+	query := NewQueryGraph(uc.graph)
+	entity, err := query.Resolve(id)
+	if err != nil {
+		return nil, err
+	}
+	impact, err := query.Impact(entity, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := map[string]bool{entity: true}
+	for _, descendant := range uc.graph.GetDescendants(entity) {
+		removed[descendant.ID] = true
+	}
+	after := cloneGraph(uc.graph)
+	for _, nodeID := range slices.Sorted(maps.Keys(removed)) {
+		if err := after.RemoveNode(nodeID); err != nil {
+			return nil, fmt.Errorf("failed to remove %s: %w", nodeID, err)
+		}
+	}
+
+	report := &WhatIfReport{
+		Change:              "remove " + entity,
+		Removed:             slices.Sorted(maps.Keys(removed)),
+		BrokenRelationships: []WhatIfEdge{},
+		BrokenPaths:         []WhatIfEdge{},
+		NewCycles:           [][]string{},
+		Impacted:            impact.Impacted,
+		Owners:              impact.Owners,
+	}
+	for _, target := range report.Removed {
+		for _, edge := range uc.graph.GetIncomingEdges(target) {
+			if !removed[edge.Source] {
+				report.BrokenRelationships = append(report.BrokenRelationships, WhatIfEdge{Source: edge.Source, Target: target, Description: edge.Description})
+			}
+		}
+	}
+	sortWhatIfEdges(report.BrokenRelationships)
+
+	// Only elements depending on a removed one can lose a path.
+	for _, ref := range impact.Impacted {
+		reachedAfter := reachable(after, ref.ID)
+		for _, target := range slices.Sorted(maps.Keys(reachable(uc.graph, ref.ID))) {
+			if !removed[target] && !reachedAfter[target] {
+				report.BrokenPaths = append(report.BrokenPaths, WhatIfEdge{Source: ref.ID, Target: target})
+			}
+		}
+	}
+	sortWhatIfEdges(report.BrokenPaths)
+	return report, nil
+}
+
+// AddEdge reports what a new relationship from source to target would
+// change: the cycle it closes, if any, and the elements that would gain
+// dependencies, source and those depending on it. The owner of target is
+// counted as impacted too.
+func (uc *WhatIf) AddEdge(source, target, description string) (*WhatIfReport, error) {
+	query := NewQueryGraph(uc.graph)
+	from, err := query.Resolve(source)
+	if err != nil {
+		return nil, err
+	}
+	to, err := query.Resolve(target)
+	if err != nil {
+		return nil, err
+	}
+	if from == to {
+		return nil, fmt.Errorf("an element cannot depend on itself: %s", from)
+	}
+	for _, edge := range uc.graph.GetOutgoingEdges(from) {
+		if edge.Target == to {
+			return nil, fmt.Errorf("%s already depends on %s", from, to)
+		}
+	}
+
+	report := &WhatIfReport{
+		Change:              fmt.Sprintf("add-edge %s %s", from, to),
+		BrokenRelationships: []WhatIfEdge{},
+		BrokenPaths:         []WhatIfEdge{},
+		NewCycles:           [][]string{},
+		Owners:              []string{},
+	}
+	// The new edge closes a cycle when target already reaches source.
+	if path := uc.graph.GetPath(to, from); path != nil {
+		cycle := []string{from}
+		for _, node := range path {
+			cycle = append(cycle, node.ID)
+		}
+		report.NewCycles = append(report.NewCycles, cycle)
+	}
+
+	after := cloneGraph(uc.graph)
+	if err := after.AddEdge(&entities.GraphEdge{Source: from, Target: to, Type: "depends-on", Description: description}); err != nil {
+		return nil, fmt.Errorf("failed to add %s -> %s: %w", from, to, err)
+	}
+	dependents, err := NewQueryGraph(after).Dependents(from, true)
+	if err != nil {
+		return nil, err
+	}
+	sourceNode := uc.graph.GetNode(from)
+	report.Impacted = append([]GraphNodeRef{{ID: from, Name: sourceNode.Name, Type: sourceNode.Type, Owner: NodeOwner(uc.graph, from)}}, dependents...)
+	// Elements of the new cycle already depend on target.
+	report.Impacted = slices.DeleteFunc(report.Impacted, func(ref GraphNodeRef) bool { return ref.ID == to })
+
+	owners := make(map[string]bool)
+	for _, ref := range report.Impacted {
+		if ref.Owner != "" {
+			owners[ref.Owner] = true
+		}
+	}
+	if owner := NodeOwner(uc.graph, to); owner != "" {
+		owners[owner] = true
+	}
+	report.Owners = append(report.Owners, slices.Sorted(maps.Keys(owners))...)
+	return report, nil
+}
+
+// cloneGraph returns a copy of graph whose nodes and edges can be added and
+// removed without changing graph.
+func cloneGraph(graph *entities.ArchitectureGraph) *entities.ArchitectureGraph {
+	clone := entities.NewArchitectureGraph()
+	for _, id := range slices.Sorted(maps.Keys(graph.Nodes)) {
+		node := *graph.Nodes[id]
+		_ = clone.AddNode(&node)
+	}
+	for _, source := range slices.Sorted(maps.Keys(graph.Edges)) {
+		for _, edge := range graph.Edges[source] {
+			// The reverse of a bidirectional edge is an edge of its own.
+			copied := *edge
+			copied.Bidirectional = false
+			_ = clone.AddEdge(&copied)
+		}
+	}
+	// Aliases of merged elements resolve in the copy too.
+	for shortID, ids := range graph.ShortIDMap {
+		clone.ShortIDMap[shortID] = slices.Clone(ids)
+	}
+	return clone
+}
+
+// reachable returns the elements id reaches through relationships, directly
+// or transitively.
+func reachable(graph *entities.ArchitectureGraph, id string) map[string]bool {
+	reached := make(map[string]bool)
+	queue := []string{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, edge := range graph.GetOutgoingEdges(current) {
+			if !reached[edge.Target] && edge.Target != id {
+				reached[edge.Target] = true
+				queue = append(queue, edge.Target)
+			}
+		}
+	}
+	return reached
+}
+
+// sortWhatIfEdges sorts edges by source and target.
+func sortWhatIfEdges(edges []WhatIfEdge) {
+	slices.SortFunc(edges, func(a, b WhatIfEdge) int {
+		return cmp.Or(strings.Compare(a.Source, b.Source), strings.Compare(a.Target, b.Target))
+	})
+}
@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWhatIfRemove(t *testing.T) {
+	graph := queryGraphFixture(t)
+	nodes, edges := len(graph.Nodes), graph.EdgeCount()
+
+	report, err := NewWhatIf(graph).Remove("shop/api")
+	if err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if !slices.Equal(report.Removed, []string{"shop/api", "shop/api/cart"}) {
+		t.Errorf("removed = %v, want [shop/api shop/api/cart]", report.Removed)
+	}
+	wantBroken := []WhatIfEdge{{Source: "shop/web", Target: "shop/api/cart", Description: "adds items to"}}
+	if !slices.Equal(report.BrokenRelationships, wantBroken) {
+		t.Errorf("broken relationships = %+v, want %+v", report.BrokenRelationships, wantBroken)
+	}
+	wantPaths := []WhatIfEdge{{Source: "shop/web", Target: "billing/core/ledger"}}
+	if !slices.Equal(report.BrokenPaths, wantPaths) {
+		t.Errorf("broken paths = %+v, want %+v", report.BrokenPaths, wantPaths)
+	}
+	if len(report.NewCycles) != 0 {
+		t.Errorf("new cycles = %v, want none", report.NewCycles)
+	}
+	if got := refIDs(report.Impacted); !slices.Equal(got, []string{"shop/web"}) || !slices.Equal(report.Owners, []string{"team-shop"}) {
+		t.Errorf("impacted = %v, owners = %v, want [shop/web], [team-shop]", got, report.Owners)
+	}
+
+	if len(graph.Nodes) != nodes || graph.EdgeCount() != edges {
+		t.Errorf("graph changed to %d nodes, %d edges, want %d, %d", len(graph.Nodes), graph.EdgeCount(), nodes, edges)
+	}
+}
+
+func TestWhatIfAddEdge(t *testing.T) {
+	graph := queryGraphFixture(t)
+	edges := graph.EdgeCount()
+	whatIf := NewWhatIf(graph)
+
+	report, err := whatIf.AddEdge("ledger", "web", "notifies")
+	if err != nil {
+		t.Fatalf("AddEdge failed: %v", err)
+	}
+	wantCycle := []string{"billing/core/ledger", "shop/web", "shop/api/cart", "billing/core/ledger"}
+	if len(report.NewCycles) != 1 || !slices.Equal(report.NewCycles[0], wantCycle) {
+		t.Errorf("new cycles = %v, want [%v]", report.NewCycles, wantCycle)
+	}
+	if got := refIDs(report.Impacted); !slices.Equal(got, []string{"billing/core/ledger", "shop/api/cart"}) {
+		t.Errorf("impacted = %v, want [billing/core/ledger shop/api/cart]", got)
+	}
+	if !slices.Equal(report.Owners, []string{"team-billing", "team-shop"}) {
+		t.Errorf("owners = %v, want [team-billing team-shop]", report.Owners)
+	}
+	if graph.EdgeCount() != edges {
+		t.Errorf("graph changed to %d edges, want %d", graph.EdgeCount(), edges)
+	}
+
+	acyclic, err := whatIf.AddEdge("web", "ledger", "")
+	if err != nil {
+		t.Fatalf("AddEdge failed: %v", err)
+	}
+	if len(acyclic.NewCycles) != 0 {
+		t.Errorf("new cycles = %v, want none", acyclic.NewCycles)
+	}
+
+	if _, err := whatIf.AddEdge("web", "shop/api/cart", ""); err == nil {
+		t.Error("expected an error for an existing relationship")
+	}
+	if _, err := whatIf.AddEdge("web", "web", ""); err == nil {
+		t.Error("expected an error for a relationship to itself")
+	}
+}
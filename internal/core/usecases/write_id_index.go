@@ -0,0 +1,59 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// WriteIDIndex stores the ID index of a project: the qualified ID, type and
+// display name of every person, system, container and component, taken from
+// the architecture graph so that IDs match what relationships resolve to.
+type WriteIDIndex struct {
+	store IDIndexStore
+}
+
+// NewWriteIDIndex creates a new WriteIDIndex use case.
+func NewWriteIDIndex(store IDIndexStore) *WriteIDIndex {
+	return &WriteIDIndex{store: store}
+}
+
+// Execute builds the index of the project's people and systems, stores it
+// and returns it.
+func (uc *WriteIDIndex) Execute(ctx context.Context, project *entities.Project, systems []*entities.System) (*entities.IDIndex, error) {
+	if project == nil {
+		return nil, fmt.Errorf("project cannot be nil")
+	}
+
+	graph, err := NewBuildArchitectureGraph().Execute(ctx, project, systems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build architecture graph: %w", err)
+	}
+
+	index := &entities.IDIndex{
+		SchemaVersion: entities.IDIndexSchemaVersion,
+		Elements:      make([]entities.IDIndexEntry, 0, len(graph.Nodes)),
+	}
+	for _, node := range graph.Nodes {
+		entry := entities.IDIndexEntry{
+			ID:          node.ID,
+			Type:        node.Type,
+			Name:        node.Name,
+			Description: node.Description,
+			Parent:      node.ParentID,
+		}
+		if comp, ok := node.Data.(*entities.Component); ok && len(comp.Aliases) > 0 {
+			entry.Aliases = slices.Sorted(slices.Values(comp.Aliases))
+		}
+		index.Elements = append(index.Elements, entry)
+	}
+	slices.SortFunc(index.Elements, func(a, b entities.IDIndexEntry) int { return cmp.Compare(a.ID, b.ID) })
+
+	if err := uc.store.SaveIDIndex(ctx, index); err != nil {
+		return nil, fmt.Errorf("failed to save ID index: %w", err)
+	}
+	return index, nil
+}
@@ -0,0 +1,61 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+type mockIDIndexStore struct {
+	saved *entities.IDIndex
+}
+
+func (m *mockIDIndexStore) SaveIDIndex(_ context.Context, index *entities.IDIndex) error {
+	m.saved = index
+	return nil
+}
+
+func TestWriteIDIndex(t *testing.T) {
+	project, _ := entities.NewProject("shop")
+	customer, _ := entities.NewPerson("Customer")
+	customer.AddRelationship("orders", "Places orders with")
+	_ = project.AddPerson(customer)
+
+	system, _ := entities.NewSystem("Orders")
+	system.Description = "Takes orders"
+	api, _ := entities.NewContainer("API")
+	_ = system.AddContainer(api)
+	handler, _ := entities.NewComponent("Handler")
+	handler.Aliases = []string{"orders/api/old-handler"}
+	_ = api.AddComponent(handler)
+
+	store := &mockIDIndexStore{}
+	index, err := NewWriteIDIndex(store).Execute(context.Background(), project, []*entities.System{system})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if store.saved != index || index.SchemaVersion != entities.IDIndexSchemaVersion {
+		t.Fatalf("saved = %+v", store.saved)
+	}
+
+	want := []entities.IDIndexEntry{
+		{ID: "customer", Type: "person", Name: "Customer"},
+		{ID: "orders", Type: "system", Name: "Orders", Description: "Takes orders"},
+		{ID: "orders/api", Type: "container", Name: "API", Parent: "orders"},
+		{ID: "orders/api/handler", Type: "component", Name: "Handler", Parent: "orders/api", Aliases: []string{"orders/api/old-handler"}},
+	}
+	if len(index.Elements) != len(want) {
+		t.Fatalf("elements = %+v", index.Elements)
+	}
+	for i, entry := range index.Elements {
+		if entry.ID != want[i].ID || entry.Type != want[i].Type || entry.Name != want[i].Name ||
+			entry.Description != want[i].Description || entry.Parent != want[i].Parent || len(entry.Aliases) != len(want[i].Aliases) {
+			t.Errorf("elements[%d] = %+v, want %+v", i, entry, want[i])
+		}
+	}
+
+	if _, err := NewWriteIDIndex(store).Execute(context.Background(), nil, nil); err == nil {
+		t.Error("expected error for nil project")
+	}
+}
@@ -0,0 +1,360 @@
+package lsp
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+// elementFiles maps the Markdown file of each element kind to its JSON
+// Schema name.
+var elementFiles = map[string]string{
+	"system.md":    entities.SchemaSystem,
+	"container.md": entities.SchemaContainer,
+	"component.md": entities.SchemaComponent,
+}
+
+// listKeys and mapKeys are the frontmatter keys loko reads only as "- item"
+// lists and indented "key: value" maps; inline values are ignored when
+// loading. Other lists, such as issues and export, may also be written
+// inline as "[a, b]".
+var (
+	listKeys = []string{"tags", "dependencies"}
+	mapKeys  = []string{"relationships", "code_annotations"}
+)
+
+// d2ErrorPattern matches the "line:column: message" of a D2 compile error.
+var d2ErrorPattern = regexp.MustCompile(`(\d+):(\d+): (.+)$`)
+
+// componentRef is a component of the model with its qualified ID.
+type componentRef struct {
+	id        string // system/container/component
+	file      string // Path of component.md
+	component *entities.Component
+	container *entities.Container
+	system    *entities.System
+}
+
+// modelIndex indexes the components of the model for resolving
+// relationship targets.
+type modelIndex struct {
+	components map[string]*componentRef // By qualified ID
+	shortIDs   map[string][]string      // Component ID to qualified IDs
+	aliases    map[string]string        // Old ID of a merged component to qualified ID
+	files      map[string]string        // component.md path to qualified ID
+}
+
+// newModelIndex indexes the components of systems.
+func newModelIndex(systems []*entities.System) *modelIndex {
+	idx := &modelIndex{
+		components: make(map[string]*componentRef),
+		shortIDs:   make(map[string][]string),
+		aliases:    make(map[string]string),
+		files:      make(map[string]string),
+	}
+	for _, sys := range systems {
+		if sys == nil {
+			continue
+		}
+		for _, cont := range sys.ListContainers() {
+			for _, comp := range cont.ListComponents() {
+				ref := &componentRef{
+					id:        sys.ID + "/" + cont.ID + "/" + comp.ID,
+					component: comp,
+					container: cont,
+					system:    sys,
+				}
+				if comp.Path != "" {
+					ref.file = filepath.Join(comp.Path, "component.md")
+					if abs, err := filepath.Abs(ref.file); err == nil {
+						ref.file = abs
+					}
+					idx.files[ref.file] = ref.id
+				}
+				idx.components[ref.id] = ref
+				idx.shortIDs[comp.ID] = append(idx.shortIDs[comp.ID], ref.id)
+				for _, alias := range comp.Aliases {
+					idx.aliases[alias] = ref.id
+				}
+			}
+		}
+	}
+	return idx
+}
+
+// resolve returns the component a relationship target of source refers to:
+// a qualified ID, the alias of a merged component, or a component ID that is
+// unique once source is excluded. ambiguous is set when several components
+// share the ID.
+func (idx *modelIndex) resolve(target, source string) (ref *componentRef, ambiguous bool) {
+	if ref, ok := idx.components[target]; ok {
+		return ref, false
+	}
+	if id, ok := idx.aliases[target]; ok {
+		return idx.components[id], false
+	}
+	candidates := slices.DeleteFunc(slices.Clone(idx.shortIDs[target]), func(id string) bool { return id == source })
+	switch len(candidates) {
+	case 0:
+		return nil, false
+	case 1:
+		return idx.components[candidates[0]], false
+	}
+	return nil, true
+}
+
+// ids returns the qualified IDs of all components in sorted order.
+func (idx *modelIndex) ids() []string {
+	return slices.Sorted(maps.Keys(idx.components))
+}
+
+// diagnose returns the problems in a document: frontmatter errors in element
+// Markdown files and compile errors in D2 files.
+func (s *Server) diagnose(ctx context.Context, uri, text string) []Diagnostic {
+	path := uriToPath(uri)
+	if filepath.Ext(path) == ".d2" {
+		return s.diagnoseD2(ctx, text)
+	}
+	if _, ok := elementFiles[filepath.Base(path)]; !ok {
+		return []Diagnostic{}
+	}
+
+	lines := splitLines(text)
+	fm, invalid := parseFrontmatter(lines)
+	if fm == nil {
+		return []Diagnostic{{
+			Range:    wholeLine(lines, 0),
+			Severity: severityWarning,
+			Source:   "loko",
+			Message:  "Missing frontmatter: start the file with --- and a name: line",
+		}}
+	}
+
+	diagnostics := []Diagnostic{}
+	add := func(r Range, severity int, format string, args ...any) {
+		diagnostics = append(diagnostics, Diagnostic{Range: r, Severity: severity, Source: "loko", Message: fmt.Sprintf(format, args...)})
+	}
+	if fm.end < 0 {
+		add(wholeLine(lines, 0), severityError, "Frontmatter is not closed with ---")
+	}
+	for _, line := range invalid {
+		add(wholeLine(lines, line), severityError, "Expected \"key: value\" or an indented list or map entry")
+	}
+	if name := fm.key("name"); name == nil || strings.Trim(name.value, "\"'") == "" {
+		add(wholeLine(lines, 0), severityWarning, "Missing name: the directory name is used instead")
+	}
+	for _, key := range fm.keys {
+		if key.value == "" {
+			continue
+		}
+		if slices.Contains(listKeys, key.name) {
+			add(wholeLine(lines, key.line), severityWarning, "%s is ignored: write one \"  - item\" line per entry", key.name)
+		} else if slices.Contains(mapKeys, key.name) {
+			add(wholeLine(lines, key.line), severityWarning, "%s is ignored: write one indented \"key: value\" line per entry", key.name)
+		}
+	}
+
+	if relationships := fm.key("relationships"); relationships != nil {
+		if idx := s.model(ctx); idx != nil {
+			source := idx.files[path]
+			for _, entry := range relationships.entries {
+				if entry.key == "" {
+					continue
+				}
+				r := lineRange(lines, entry.line, entry.start, entry.end)
+				if ref, ambiguous := idx.resolve(entry.key, source); ambiguous {
+					add(r, severityWarning, "Ambiguous relationship target %q: use one of %s",
+						entry.key, strings.Join(idx.shortIDs[entry.key], ", "))
+				} else if ref == nil {
+					add(r, severityError, "Unknown relationship target %q", entry.key)
+				}
+			}
+		}
+	}
+	return diagnostics
+}
+
+// diagnoseD2 returns the compile errors of D2 source.
+func (s *Server) diagnoseD2(ctx context.Context, text string) []Diagnostic {
+	diagnostics := []Diagnostic{}
+	if s.parser == nil {
+		return diagnostics
+	}
+	_, err := s.parser.ParseRelationships(ctx, text)
+	if err == nil {
+		return diagnostics
+	}
+
+	lines := splitLines(text)
+	for _, message := range strings.Split(err.Error(), "\n") {
+		match := d2ErrorPattern.FindStringSubmatch(message)
+		if match == nil {
+			continue
+		}
+		line, _ := strconv.Atoi(match[1])
+		col, _ := strconv.Atoi(match[2])
+		line, col = max(line-1, 0), max(col-1, 0)
+		r := wholeLine(lines, line)
+		if line < len(lines) {
+			r = lineRange(lines, line, col, len(lines[line]))
+		}
+		diagnostics = append(diagnostics, Diagnostic{Range: r, Severity: severityError, Source: "d2", Message: match[3]})
+	}
+	if len(diagnostics) == 0 {
+		diagnostics = append(diagnostics, Diagnostic{Range: wholeLine(lines, 0), Severity: severityError, Source: "d2", Message: err.Error()})
+	}
+	return diagnostics
+}
+
+// relationshipAt returns the relationship entry under pos in an element's
+// frontmatter, or nil.
+func relationshipAt(text string, pos Position) *frontmatterEntry {
+	fm, _ := parseFrontmatter(splitLines(text))
+	if fm == nil {
+		return nil
+	}
+	key := fm.keyAt(pos.Line)
+	if key == nil || key.name != "relationships" {
+		return nil
+	}
+	return key.entryAt(pos.Line)
+}
+
+// complete proposes the qualified IDs of the components a relationship may
+// target when the cursor is on a relationship key.
+func (s *Server) complete(ctx context.Context, uri, text string, pos Position) []CompletionItem {
+	items := []CompletionItem{}
+	lines := splitLines(text)
+	fm, _ := parseFrontmatter(lines)
+	if fm == nil || pos.Line >= len(lines) {
+		return items
+	}
+	key := fm.keyAt(pos.Line)
+	line := lines[pos.Line]
+	if key == nil || key.name != "relationships" || key.line == pos.Line || !strings.HasPrefix(line, " ") {
+		return items
+	}
+	cursor := byteOffset(line, pos.Character)
+	start := len(line) - len(strings.TrimLeft(line, " "))
+	end := cursor
+	if colon := strings.Index(line, ":"); colon >= 0 {
+		if cursor > colon {
+			return items // Completing the description
+		}
+		end = colon
+	}
+	idx := s.model(ctx)
+	if idx == nil {
+		return items
+	}
+
+	source := idx.files[uriToPath(uri)]
+	edit := lineRange(lines, pos.Line, start, end)
+	for _, id := range idx.ids() {
+		if id == source {
+			continue
+		}
+		ref := idx.components[id]
+		items = append(items, CompletionItem{
+			Label:         id,
+			Kind:          completionKindReference,
+			Detail:        ref.component.Name,
+			Documentation: ref.component.Description,
+			TextEdit:      &TextEdit{Range: edit, NewText: id},
+		})
+	}
+	return items
+}
+
+// definition returns the component.md of the relationship target under pos.
+func (s *Server) definition(ctx context.Context, uri, text string, pos Position) *Location {
+	entry := relationshipAt(text, pos)
+	idx := s.model(ctx)
+	if entry == nil || idx == nil {
+		return nil
+	}
+	ref, _ := idx.resolve(entry.key, idx.files[uriToPath(uri)])
+	if ref == nil || ref.file == "" {
+		return nil
+	}
+	return &Location{URI: pathToURI(ref.file)}
+}
+
+// hover describes the relationship target or the frontmatter key under pos.
+func (s *Server) hover(ctx context.Context, uri, text string, pos Position) *Hover {
+	lines := splitLines(text)
+	fm, _ := parseFrontmatter(lines)
+	if fm == nil || pos.Line >= len(lines) {
+		return nil
+	}
+	key := fm.keyAt(pos.Line)
+	if key == nil {
+		return nil
+	}
+
+	if key.line == pos.Line {
+		schema, ok := elementFiles[filepath.Base(uriToPath(uri))]
+		if !ok {
+			return nil
+		}
+		description := keyDescription(schema, key.name)
+		if description == "" {
+			return nil
+		}
+		r := lineRange(lines, key.line, 0, len(key.name))
+		return &Hover{Contents: MarkupContent{Kind: "markdown", Value: fmt.Sprintf("**%s**\n\n%s", key.name, description)}, Range: &r}
+	}
+
+	entry := relationshipAt(text, pos)
+	idx := s.model(ctx)
+	if entry == nil || idx == nil {
+		return nil
+	}
+	ref, _ := idx.resolve(entry.key, idx.files[uriToPath(uri)])
+	if ref == nil {
+		return nil
+	}
+	r := lineRange(lines, entry.line, entry.start, entry.end)
+	return &Hover{Contents: MarkupContent{Kind: "markdown", Value: describeComponent(ref)}, Range: &r}
+}
+
+// describeComponent returns Markdown describing a component.
+func describeComponent(ref *componentRef) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**%s** `%s`\n\n", ref.component.Name, ref.id)
+	if ref.component.Technology != "" {
+		fmt.Fprintf(&sb, "Technology: %s\n\n", ref.component.Technology)
+	}
+	if ref.component.Description != "" {
+		sb.WriteString(ref.component.Description + "\n\n")
+	}
+	fmt.Fprintf(&sb, "Component of %s in %s", ref.container.Name, ref.system.Name)
+	return sb.String()
+}
+
+// keyDescription returns the JSON Schema description of a frontmatter key,
+// or the custom field description for keys loko does not know.
+func keyDescription(schema, key string) string {
+	s, err := entities.JSONSchema(schema)
+	if err != nil {
+		return ""
+	}
+	if properties, ok := s["properties"].(map[string]any); ok {
+		if property, ok := properties[key].(map[string]any); ok {
+			description, _ := property["description"].(string)
+			return description
+		}
+	}
+	if custom, ok := s["additionalProperties"].(map[string]any); ok {
+		description, _ := custom["description"].(string)
+		return description
+	}
+	return ""
+}
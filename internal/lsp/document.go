@@ -0,0 +1,208 @@
+package lsp
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+// frontmatter is the YAML frontmatter of an element's Markdown file, split
+// into its top-level keys.
+type frontmatter struct {
+	end  int // Line of the closing "---", or -1 when it is missing
+	keys []frontmatterKey
+}
+
+// frontmatterKey is a top-level frontmatter key and the indented lines that
+// follow it, such as list items or relationships.
+type frontmatterKey struct {
+	name    string
+	value   string // Inline value, empty when the key starts a list or map
+	line    int
+	entries []frontmatterEntry
+}
+
+// frontmatterEntry is an indented "key: value" or "- item" line.
+type frontmatterEntry struct {
+	key   string // Map key or list item, unquoted
+	line  int
+	start int // Byte offsets of key in the line
+	end   int
+}
+
+// parseFrontmatter splits the frontmatter of lines into keys, or returns nil
+// when the document does not start with "---". Lines that are neither keys
+// nor indented are returned as invalid.
+func parseFrontmatter(lines []string) (fm *frontmatter, invalid []int) {
+	if len(lines) == 0 || lines[0] != "---" {
+		return nil, nil
+	}
+	fm = &frontmatter{end: -1}
+	for i := 1; i < len(lines); i++ {
+		line := lines[i]
+		if line == "---" {
+			fm.end = i
+			break
+		}
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+		case strings.HasPrefix(line, " ") || strings.HasPrefix(line, "-"):
+			if len(fm.keys) == 0 {
+				invalid = append(invalid, i)
+				continue
+			}
+			last := &fm.keys[len(fm.keys)-1]
+			last.entries = append(last.entries, parseEntry(line, i))
+		default:
+			name, value, ok := strings.Cut(line, ":")
+			if !ok {
+				invalid = append(invalid, i)
+				continue
+			}
+			fm.keys = append(fm.keys, frontmatterKey{
+				name:  strings.TrimSpace(name),
+				value: strings.TrimSpace(value),
+				line:  i,
+			})
+		}
+	}
+	return fm, invalid
+}
+
+// parseEntry parses an indented frontmatter line.
+func parseEntry(line string, lineNo int) frontmatterEntry {
+	start := len(line) - len(strings.TrimLeft(line, " "))
+	if rest, ok := strings.CutPrefix(line[start:], "- "); ok {
+		start += 2
+		return quotedEntry(strings.TrimSpace(rest), lineNo, start)
+	}
+	key, _, _ := strings.Cut(line[start:], ":")
+	return quotedEntry(strings.TrimRight(key, " "), lineNo, start)
+}
+
+// quotedEntry returns an entry for key at start, without surrounding quotes.
+func quotedEntry(key string, line, start int) frontmatterEntry {
+	entry := frontmatterEntry{key: key, line: line, start: start, end: start + len(key)}
+	if unquoted := strings.Trim(key, "\"'"); len(unquoted) != len(key) {
+		entry.key = unquoted
+		entry.start++
+		entry.end = entry.start + len(unquoted)
+	}
+	return entry
+}
+
+// key returns the named top-level key, or nil.
+func (fm *frontmatter) key(name string) *frontmatterKey {
+	for i := range fm.keys {
+		if fm.keys[i].name == name {
+			return &fm.keys[i]
+		}
+	}
+	return nil
+}
+
+// keyAt returns the top-level key whose block contains line, or nil when
+// line is outside the frontmatter.
+func (fm *frontmatter) keyAt(line int) *frontmatterKey {
+	if line <= 0 || (fm.end >= 0 && line >= fm.end) {
+		return nil
+	}
+	var found *frontmatterKey
+	for i := range fm.keys {
+		if fm.keys[i].line > line {
+			break
+		}
+		found = &fm.keys[i]
+	}
+	return found
+}
+
+// entryAt returns the entry of key on line, or nil.
+func (k *frontmatterKey) entryAt(line int) *frontmatterEntry {
+	for i := range k.entries {
+		if k.entries[i].line == line {
+			return &k.entries[i]
+		}
+	}
+	return nil
+}
+
+// splitLines splits a document into lines without line terminators.
+func splitLines(text string) []string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+// character converts a byte offset in line to the UTF-16 character offset
+// LSP positions use.
+func character(line string, offset int) int {
+	offset = min(offset, len(line))
+	n := 0
+	for _, r := range line[:offset] {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// byteOffset converts a UTF-16 character offset in line to a byte offset.
+func byteOffset(line string, char int) int {
+	n := 0
+	for i, r := range line {
+		if n >= char {
+			return i
+		}
+		n += utf16.RuneLen(r)
+	}
+	return len(line)
+}
+
+// lineRange returns the range of the bytes start to end of a line.
+func lineRange(lines []string, line, start, end int) Range {
+	text := ""
+	if line < len(lines) {
+		text = lines[line]
+	}
+	return Range{
+		Start: Position{Line: line, Character: character(text, start)},
+		End:   Position{Line: line, Character: character(text, end)},
+	}
+}
+
+// wholeLine returns the range of a whole line.
+func wholeLine(lines []string, line int) Range {
+	end := 0
+	if line < len(lines) {
+		end = len(lines[line])
+	}
+	return lineRange(lines, line, 0, end)
+}
+
+// uriToPath converts a file URI to a filesystem path.
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+	path := u.Path
+	if len(path) >= 3 && path[0] == '/' && path[2] == ':' {
+		path = path[1:] // Windows drive letter, e.g. /C:/project
+	}
+	return filepath.Clean(filepath.FromSlash(path))
+}
+
+// pathToURI converts a filesystem path to a file URI.
+func pathToURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
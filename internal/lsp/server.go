@@ -0,0 +1,222 @@
+// Package lsp provides a Language Server Protocol server for editing loko
+// models by hand: diagnostics for element frontmatter and D2 files,
+// completion of relationship targets, go-to-definition and hover.
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// SystemLister loads the systems of a project.
+type SystemLister interface {
+	ListSystems(ctx context.Context, projectRoot string) ([]*entities.System, error)
+}
+
+// Server implements the LSP server for loko.
+// It communicates with editors via JSON-RPC 2.0 over stdio.
+type Server struct {
+	ProjectRoot string
+	systems     SystemLister
+	parser      usecases.D2Parser // Optional: reports D2 compile errors
+	input       *bufio.Reader
+	output      io.Writer
+
+	documents map[string]string // Open documents by URI
+	index     *modelIndex       // Loaded on first use, reset when files are saved
+	shutdown  bool
+}
+
+// NewServer creates a new LSP server. parser may be nil to skip D2
+// diagnostics.
+func NewServer(projectRoot string, systems SystemLister, parser usecases.D2Parser, input io.Reader, output io.Writer) *Server {
+	if input == nil {
+		input = os.Stdin
+	}
+	if output == nil {
+		output = os.Stdout
+	}
+
+	return &Server{
+		ProjectRoot: projectRoot,
+		systems:     systems,
+		parser:      parser,
+		input:       bufio.NewReader(input),
+		output:      output,
+		documents:   make(map[string]string),
+	}
+}
+
+// Run starts the LSP server, reading messages from input and writing
+// responses and diagnostics to output until the client sends exit or closes
+// input.
+func (s *Server) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		msg, err := readMessage(s.input)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if errors.Is(err, errParse) {
+			_ = s.reply(nil, nil, &responseError{Code: codeParseError, Message: "Parse error"})
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if msg.Method == "exit" {
+			return nil
+		}
+		if msg.ID == nil {
+			s.handleNotification(ctx, msg)
+			continue
+		}
+		result, rpcErr := s.handleRequest(ctx, msg)
+		if err := s.reply(msg.ID, result, rpcErr); err != nil {
+			return err
+		}
+	}
+}
+
+// handleRequest processes a request and returns its result.
+func (s *Server) handleRequest(ctx context.Context, msg *message) (any, *responseError) {
+	if s.shutdown {
+		return nil, &responseError{Code: codeInvalidRequest, Message: "Server is shutting down"}
+	}
+
+	switch msg.Method {
+	case "initialize":
+		return map[string]any{
+			"serverInfo": map[string]any{"name": "loko"},
+			"capabilities": map[string]any{
+				"textDocumentSync":   map[string]any{"openClose": true, "change": 1, "save": true},
+				"completionProvider": map[string]any{"triggerCharacters": []string{"/"}},
+				"definitionProvider": true,
+				"hoverProvider":      true,
+			},
+		}, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/completion", "textDocument/definition", "textDocument/hover":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: fmt.Sprintf("Invalid params: %v", err)}
+		}
+		uri := params.TextDocument.URI
+		text, ok := s.documents[uri]
+		if !ok {
+			return nil, nil
+		}
+		switch msg.Method {
+		case "textDocument/completion":
+			return s.complete(ctx, uri, text, params.Position), nil
+		case "textDocument/definition":
+			if location := s.definition(ctx, uri, text, params.Position); location != nil {
+				return location, nil
+			}
+		default:
+			if hover := s.hover(ctx, uri, text, params.Position); hover != nil {
+				return hover, nil
+			}
+		}
+		return nil, nil
+	}
+	return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("Method not found: %s", msg.Method)}
+}
+
+// handleNotification processes a notification. Unknown notifications are
+// ignored.
+func (s *Server) handleNotification(ctx context.Context, msg *message) {
+	var params struct {
+		TextDocument struct {
+			URI  string `json:"uri"`
+			Text string `json:"text"`
+		} `json:"textDocument"`
+		ContentChanges []struct {
+			Text string `json:"text"`
+		} `json:"contentChanges"`
+	}
+	if len(msg.Params) > 0 {
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid %s params: %v\n", msg.Method, err)
+			return
+		}
+	}
+	uri := params.TextDocument.URI
+
+	switch msg.Method {
+	case "textDocument/didOpen":
+		s.documents[uri] = params.TextDocument.Text
+		s.publishDiagnostics(ctx, uri)
+	case "textDocument/didChange":
+		// Full document sync: the last change holds the whole text
+		if n := len(params.ContentChanges); n > 0 {
+			s.documents[uri] = params.ContentChanges[n-1].Text
+			s.publishDiagnostics(ctx, uri)
+		}
+	case "textDocument/didSave", "workspace/didChangeWatchedFiles":
+		// The model changed on disk: reload it and recheck every open document
+		s.index = nil
+		for _, uri := range slices.Sorted(maps.Keys(s.documents)) {
+			s.publishDiagnostics(ctx, uri)
+		}
+	case "textDocument/didClose":
+		delete(s.documents, uri)
+		_ = s.notify("textDocument/publishDiagnostics", map[string]any{"uri": uri, "diagnostics": []Diagnostic{}})
+	}
+}
+
+// model returns the index of the project's components, loading it when
+// needed, or nil when the project cannot be loaded.
+func (s *Server) model(ctx context.Context) *modelIndex {
+	if s.index == nil && s.systems != nil {
+		systems, err := s.systems.ListSystems(ctx, s.ProjectRoot)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to load project: %v\n", err)
+			return nil
+		}
+		s.index = newModelIndex(systems)
+	}
+	return s.index
+}
+
+// publishDiagnostics sends the diagnostics of an open document.
+func (s *Server) publishDiagnostics(ctx context.Context, uri string) {
+	diagnostics := s.diagnose(ctx, uri, s.documents[uri])
+	if err := s.notify("textDocument/publishDiagnostics", map[string]any{"uri": uri, "diagnostics": diagnostics}); err != nil {
+		fmt.Fprintf(os.Stderr, "error publishing diagnostics: %v\n", err)
+	}
+}
+
+// reply sends the response to a request.
+func (s *Server) reply(id json.RawMessage, result any, rpcErr *responseError) error {
+	response := map[string]any{"id": id} // A nil ID encodes as null
+	if rpcErr != nil {
+		response["error"] = rpcErr
+	} else {
+		response["result"] = result
+	}
+	return writeMessage(s.output, response)
+}
+
+// notify sends a notification to the client.
+func (s *Server) notify(method string, params any) error {
+	return writeMessage(s.output, map[string]any{"method": method, "params": params})
+}
@@ -0,0 +1,269 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+)
+
+type staticSystems []*entities.System
+
+func (s staticSystems) ListSystems(ctx context.Context, projectRoot string) ([]*entities.System, error) {
+	return s, nil
+}
+
+type failingParser struct{ err error }
+
+func (p failingParser) ParseRelationships(ctx context.Context, d2Source string) ([]entities.D2Relationship, error) {
+	return nil, p.err
+}
+
+// testModel returns a system with an API container of two components whose
+// files live under root.
+func testModel(t *testing.T, root string) staticSystems {
+	t.Helper()
+	sys, _ := entities.NewSystem("Payments")
+	api, _ := entities.NewContainer("API")
+	for _, name := range []string{"Handler", "Store"} {
+		comp, _ := entities.NewComponent(name)
+		comp.Technology = "Go"
+		comp.Description = name + " component"
+		comp.Path = filepath.Join(root, "src", sys.ID, api.ID, comp.ID)
+		if name == "Store" {
+			comp.Aliases = []string{"payments/db/store"}
+		}
+		if err := api.AddComponent(comp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sys.AddContainer(api); err != nil {
+		t.Fatal(err)
+	}
+	return staticSystems{sys}
+}
+
+// session runs the server over the given messages and returns the
+// responses and notifications it wrote.
+func session(t *testing.T, server *Server, messages ...map[string]any) []map[string]any {
+	t.Helper()
+	var input bytes.Buffer
+	for _, msg := range messages {
+		msg["jsonrpc"] = "2.0"
+		body, _ := json.Marshal(msg)
+		fmt.Fprintf(&input, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	}
+	var output bytes.Buffer
+	server.input = bufio.NewReader(&input)
+	server.output = &output
+	if err := server.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var out []map[string]any
+	reader := bufio.NewReader(&output)
+	for {
+		header, err := reader.ReadString('\n')
+		if err == io.EOF {
+			return out
+		}
+		var length int
+		fmt.Sscanf(header, "Content-Length: %d", &length)
+		reader.ReadString('\n')
+		body := make([]byte, length)
+		io.ReadFull(reader, body)
+		var msg map[string]any
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatalf("invalid message %q: %v", body, err)
+		}
+		out = append(out, msg)
+	}
+}
+
+func didOpen(uri, text string) map[string]any {
+	return map[string]any{"method": "textDocument/didOpen", "params": map[string]any{
+		"textDocument": map[string]any{"uri": uri, "languageId": "markdown", "version": 1, "text": text},
+	}}
+}
+
+func request(id int, method, uri string, line, character int) map[string]any {
+	return map[string]any{"id": id, "method": method, "params": map[string]any{
+		"textDocument": map[string]any{"uri": uri},
+		"position":     map[string]any{"line": line, "character": character},
+	}}
+}
+
+// diagnosticMessages returns the messages of the first diagnostics published.
+func diagnosticMessages(t *testing.T, out []map[string]any) []string {
+	t.Helper()
+	for _, msg := range out {
+		if msg["method"] == "textDocument/publishDiagnostics" {
+			var messages []string
+			for _, d := range msg["params"].(map[string]any)["diagnostics"].([]any) {
+				messages = append(messages, d.(map[string]any)["message"].(string))
+			}
+			return messages
+		}
+	}
+	t.Fatal("no diagnostics published")
+	return nil
+}
+
+func TestInitialize(t *testing.T) {
+	server := NewServer("", nil, nil, nil, nil)
+	out := session(t, server,
+		map[string]any{"id": 1, "method": "initialize", "params": map[string]any{}},
+		map[string]any{"id": 2, "method": "unknown/method"},
+		map[string]any{"id": 3, "method": "shutdown"},
+		map[string]any{"method": "exit"},
+	)
+	if len(out) != 3 {
+		t.Fatalf("got %d responses, want 3", len(out))
+	}
+	capabilities := out[0]["result"].(map[string]any)["capabilities"].(map[string]any)
+	for _, capability := range []string{"completionProvider", "definitionProvider", "hoverProvider"} {
+		if capabilities[capability] == nil {
+			t.Errorf("initialize result missing %s", capability)
+		}
+	}
+	if code := out[1]["error"].(map[string]any)["code"]; code != float64(codeMethodNotFound) {
+		t.Errorf("unknown method error code = %v", code)
+	}
+	if result, ok := out[2]["result"]; !ok || result != nil {
+		t.Errorf("shutdown result = %v, want null", result)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	root := t.TempDir()
+	model := testModel(t, root)
+	handler := pathToURI(filepath.Join(root, "src", "payments", "api", "handler", "component.md"))
+
+	tests := []struct {
+		name string
+		uri  string
+		text string
+		want []string
+	}{
+		{
+			name: "valid",
+			uri:  handler,
+			text: "---\nname: Handler\ntags:\n  - http\nrelationships:\n  store: \"Reads\"\n  payments/api/store: \"Writes\"\n  payments/db/store: \"Reads\"\n---\n",
+		},
+		{
+			name: "unknown target",
+			uri:  handler,
+			text: "---\nname: Handler\nrelationships:\n  ledger: \"Posts\"\n---\n",
+			want: []string{`Unknown relationship target "ledger"`},
+		},
+		{
+			name: "inline list and missing name",
+			uri:  handler,
+			text: "---\ndescription: Handles requests\ntags: [http]\n---\n",
+			want: []string{"Missing name", "tags is ignored"},
+		},
+		{
+			name: "unterminated",
+			uri:  pathToURI(filepath.Join(root, "src", "payments", "system.md")),
+			text: "---\nname: Payments\nnot a key\n",
+			want: []string{"not closed", "Expected \"key: value\""},
+		},
+		{
+			name: "other markdown",
+			uri:  pathToURI(filepath.Join(root, "README.md")),
+			text: "# Notes\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServer(root, model, nil, nil, nil)
+			got := diagnosticMessages(t, session(t, server, didOpen(tt.uri, tt.text)))
+			if len(got) != len(tt.want) {
+				t.Fatalf("diagnostics = %q, want %d", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestDiagnosticsD2(t *testing.T) {
+	parser := failingParser{err: errors.New("D2 parse error: 2:5: unexpected text after map key")}
+	server := NewServer("", nil, parser, nil, nil)
+	out := session(t, server, didOpen("file:///project/src/payments/payments.d2", "a -> b\nc: d e {\n"))
+
+	params := out[0]["params"].(map[string]any)
+	diagnostics := params["diagnostics"].([]any)
+	if len(diagnostics) != 1 {
+		t.Fatalf("diagnostics = %v, want 1", diagnostics)
+	}
+	d := diagnostics[0].(map[string]any)
+	start := d["range"].(map[string]any)["start"].(map[string]any)
+	if start["line"] != float64(1) || start["character"] != float64(4) {
+		t.Errorf("range start = %v, want line 1 character 4", start)
+	}
+	if d["message"] != "unexpected text after map key" {
+		t.Errorf("message = %v", d["message"])
+	}
+}
+
+func TestCompletionDefinitionHover(t *testing.T) {
+	root := t.TempDir()
+	model := testModel(t, root)
+	uri := pathToURI(filepath.Join(root, "src", "payments", "api", "handler", "component.md"))
+	text := "---\nname: Handler\ntechnology: Go\nrelationships:\n  sto\n  store: \"Reads\"\n---\n"
+
+	server := NewServer(root, model, nil, nil, nil)
+	out := session(t, server,
+		didOpen(uri, text),
+		request(1, "textDocument/completion", uri, 4, 5),
+		request(2, "textDocument/definition", uri, 5, 3),
+		request(3, "textDocument/hover", uri, 5, 3),
+		request(4, "textDocument/hover", uri, 2, 2),
+		request(5, "textDocument/definition", uri, 1, 2),
+	)
+	responses := make(map[float64]any)
+	for _, msg := range out {
+		if id, ok := msg["id"].(float64); ok {
+			responses[id] = msg["result"]
+		}
+	}
+
+	items := responses[1].([]any)
+	if len(items) != 1 {
+		t.Fatalf("completion = %v, want only the other component", items)
+	}
+	item := items[0].(map[string]any)
+	edit := item["textEdit"].(map[string]any)
+	if item["label"] != "payments/api/store" || edit["newText"] != "payments/api/store" {
+		t.Errorf("completion item = %v", item)
+	}
+
+	location := responses[2].(map[string]any)
+	if want := pathToURI(filepath.Join(root, "src", "payments", "api", "store", "component.md")); location["uri"] != want {
+		t.Errorf("definition = %v, want %s", location["uri"], want)
+	}
+
+	hover := responses[3].(map[string]any)["contents"].(map[string]any)["value"].(string)
+	if !strings.Contains(hover, "**Store** `payments/api/store`") || !strings.Contains(hover, "Technology: Go") {
+		t.Errorf("target hover = %q", hover)
+	}
+	keyHover := responses[4].(map[string]any)["contents"].(map[string]any)["value"].(string)
+	if !strings.Contains(keyHover, "**technology**") {
+		t.Errorf("key hover = %q", keyHover)
+	}
+	if responses[5] != nil {
+		t.Errorf("definition outside relationships = %v, want null", responses[5])
+	}
+}
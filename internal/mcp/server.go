@@ -8,8 +8,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"sync"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
 )
 
 // Tool represents an MCP tool that can be called by the client.
@@ -27,6 +30,11 @@ type Tool interface {
 	Call(ctx context.Context, args map[string]any) (any, error)
 }
 
+// Auditor records mutating tool calls and the changes they make.
+type Auditor interface {
+	Track(ctx context.Context, entry *entities.AuditEntry, change func() error) error
+}
+
 // Server implements the MCP server for loko.
 // It communicates with clients via JSON-RPC 2.0 over stdio.
 type Server struct {
@@ -36,8 +44,19 @@ type Server struct {
 	tools       map[string]Tool
 	toolsMutex  sync.RWMutex
 	graphCache  *GraphCache // Cache for architecture graphs
+
+	auditor    Auditor                // Optional: records mutating tool calls
+	isMutating func(name string) bool // Selects the tool calls to audit
+	clientName string                 // MCP client from the initialize handshake
+	allowed    func(name string) bool // Optional: tools this session may list and call
+	stageRoot  string                 // Optional: project copy that tool calls read and write
+
+	resolvePath func(path string) (string, error) // Optional: confines path arguments to the sandbox
 }
 
+// sandboxedArgs are the tool arguments naming paths that a sandbox confines.
+var sandboxedArgs = []string{"project_root", "output_dir"}
+
 // NewServer creates a new MCP server.
 func NewServer(projectRoot string, input io.Reader, output io.Writer) *Server {
 	if input == nil {
@@ -53,9 +72,61 @@ func NewServer(projectRoot string, input io.Reader, output io.Writer) *Server {
 		output:      output,
 		tools:       make(map[string]Tool),
 		graphCache:  NewGraphCache(),
+		clientName:  "unknown",
 	}
 }
 
+// SetAuditor records every call to a tool for which isMutating returns true.
+func (s *Server) SetAuditor(auditor Auditor, isMutating func(name string) bool) {
+	s.auditor = auditor
+	s.isMutating = isMutating
+}
+
+// SetToolPolicy restricts the session to the tools for which allowed returns
+// true. Other tools are hidden from tools/list and calls to them are rejected.
+func (s *Server) SetToolPolicy(allowed func(name string) bool) {
+	s.allowed = allowed
+}
+
+// SetStagingRoot points every tool call at the project copy at root, so that
+// mutating tools stage their changes there instead of writing the project.
+func (s *Server) SetStagingRoot(root string) {
+	s.stageRoot = root
+}
+
+// SetSandbox confines the project_root and output_dir arguments of tool calls
+// to the paths resolve accepts, passing tools the resolved paths. A call with
+// a path resolve rejects fails without reaching the tool, and a call without
+// project_root gets resolve(".").
+func (s *Server) SetSandbox(resolve func(path string) (string, error)) {
+	s.resolvePath = resolve
+}
+
+// sandboxArgs returns arguments with their paths resolved by the sandbox.
+func (s *Server) sandboxArgs(arguments map[string]any) (map[string]any, error) {
+	resolved := maps.Clone(arguments)
+	for _, name := range sandboxedArgs {
+		path, _ := arguments[name].(string)
+		if path == "" && name != "project_root" {
+			continue
+		}
+		if path == "" {
+			path = "."
+		}
+		canonical, err := s.resolvePath(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		resolved[name] = canonical
+	}
+	return resolved, nil
+}
+
+// permitted reports whether the tool policy allows the named tool.
+func (s *Server) permitted(name string) bool {
+	return s.allowed == nil || s.allowed(name)
+}
+
 // GetGraphCache returns the server's graph cache for tool access.
 func (s *Server) GetGraphCache() *GraphCache {
 	return s.graphCache
@@ -136,6 +207,7 @@ func (s *Server) handleInitialize(id any, request map[string]any) map[string]any
 	if params, ok := request["params"].(map[string]any); ok {
 		if clientInfo, ok := params["clientInfo"].(map[string]any); ok {
 			fmt.Fprintf(os.Stderr, "MCP client connected: %v\n", clientInfo)
+			s.clientName = formatClientInfo(clientInfo)
 		}
 	}
 
@@ -164,6 +236,9 @@ func (s *Server) handleToolsList(id any) map[string]any {
 
 	tools := make([]map[string]any, 0, len(s.tools))
 	for _, tool := range s.tools {
+		if !s.permitted(tool.Name()) {
+			continue
+		}
 		toolDesc := map[string]any{
 			"name":        tool.Name(),
 			"description": tool.Description(),
@@ -208,9 +283,42 @@ func (s *Server) handleToolCall(id any, request map[string]any) map[string]any {
 	if !exists {
 		return s.errorResponse(id, -32601, fmt.Sprintf("Tool not found: %s", toolName), nil)
 	}
+	if !s.permitted(toolName) {
+		return s.errorResponse(id, -32001, fmt.Sprintf("Tool not permitted: %s", toolName), nil)
+	}
+
+	// Audit entries keep the arguments as sent by the client
+	callArgs := arguments
+	if s.resolvePath != nil {
+		resolved, err := s.sandboxArgs(arguments)
+		if err != nil {
+			return s.errorResponse(id, -32001, fmt.Sprintf("Path not permitted: %v", err), nil)
+		}
+		callArgs = resolved
+	}
+	if s.stageRoot != "" {
+		callArgs = maps.Clone(callArgs)
+		callArgs["project_root"] = s.stageRoot
+	}
 
 	// Call the tool
-	result, err := tool.Call(context.Background(), arguments)
+	ctx := context.Background()
+	var result any
+	var err error
+	if s.auditor != nil && s.isMutating(toolName) {
+		entry := &entities.AuditEntry{
+			Source:    entities.AuditSourceMCP,
+			Actor:     s.clientName,
+			Action:    toolName,
+			Arguments: arguments,
+		}
+		err = s.auditor.Track(ctx, entry, func() error {
+			result, err = tool.Call(ctx, callArgs)
+			return err
+		})
+	} else {
+		result, err = tool.Call(ctx, callArgs)
+	}
 	if err != nil {
 		return s.errorResponse(id, -32000, fmt.Sprintf("Tool error: %v", err), nil)
 	}
@@ -222,19 +330,36 @@ func (s *Server) handleToolCall(id any, request map[string]any) map[string]any {
 	}
 }
 
+// formatClientInfo returns "name version" from MCP clientInfo, or "unknown".
+func formatClientInfo(clientInfo map[string]any) string {
+	name, _ := clientInfo["name"].(string)
+	if name == "" {
+		return "unknown"
+	}
+	if version, _ := clientInfo["version"].(string); version != "" {
+		return name + " " + version
+	}
+	return name
+}
+
 // wrapToolResult wraps a tool result in the MCP content array format.
 // MCP protocol requires tool results as {"content": [{"type": "text", "text": "..."}]}.
+// String results, such as TOON documents, are sent as is; others as JSON.
 func wrapToolResult(result any) map[string]any {
-	textContent, err := json.Marshal(result)
-	if err != nil {
-		textContent = []byte(fmt.Sprintf("%v", result))
+	text, ok := result.(string)
+	if !ok {
+		textContent, err := json.Marshal(result)
+		if err != nil {
+			textContent = []byte(fmt.Sprintf("%v", result))
+		}
+		text = string(textContent)
 	}
 
 	return map[string]any{
 		"content": []map[string]any{
 			{
 				"type": "text",
-				"text": string(textContent),
+				"text": text,
 			},
 		},
 	}
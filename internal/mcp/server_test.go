@@ -4,7 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
 )
 
 // TestServerInitialization tests creating a new MCP server.
@@ -199,6 +203,153 @@ func TestCallToolRequest(t *testing.T) {
 	}
 }
 
+// recordingAuditor captures audited tool calls.
+type recordingAuditor struct {
+	entries []*entities.AuditEntry
+}
+
+func (a *recordingAuditor) Track(_ context.Context, entry *entities.AuditEntry, change func() error) error {
+	err := change()
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	a.entries = append(a.entries, entry)
+	return err
+}
+
+// TestCallToolAudited tests that mutating tool calls are recorded with the client as actor.
+func TestCallToolAudited(t *testing.T) {
+	server := NewServer("test", bytes.NewBufferString(""), bytes.NewBuffer([]byte{}))
+	auditor := &recordingAuditor{}
+	server.SetAuditor(auditor, func(name string) bool { return name == "create_system" })
+
+	calls := 0
+	server.RegisterTool(&MockTool{NameValue: "create_system", CallFunc: func(ctx context.Context, args map[string]any) (any, error) {
+		calls++
+		return map[string]any{"ok": true}, nil
+	}})
+	server.RegisterTool(&MockTool{NameValue: "query_project"})
+
+	server.handleRequest(map[string]any{
+		"jsonrpc": "2.0", "id": 0, "method": "initialize",
+		"params": map[string]any{"clientInfo": map[string]any{"name": "claude-code", "version": "2.0"}},
+	})
+	for _, name := range []string{"create_system", "query_project"} {
+		response := server.handleRequest(map[string]any{
+			"jsonrpc": "2.0", "id": 1, "method": "tools/call",
+			"params": map[string]any{"name": name, "arguments": map[string]any{"name": "Payments"}},
+		})
+		if _, hasError := response["error"]; hasError {
+			t.Fatalf("%s: unexpected error %v", name, response["error"])
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("expected create_system to be called once, got %d", calls)
+	}
+	if len(auditor.entries) != 1 {
+		t.Fatalf("expected only the mutating call to be audited, got %d entries", len(auditor.entries))
+	}
+	entry := auditor.entries[0]
+	if entry.Source != entities.AuditSourceMCP || entry.Actor != "claude-code 2.0" || entry.Action != "create_system" || entry.Arguments["name"] != "Payments" {
+		t.Errorf("entry = %+v", entry)
+	}
+}
+
+// TestToolPolicy tests that disallowed tools are hidden and cannot be called.
+func TestToolPolicy(t *testing.T) {
+	server := NewServer("test", bytes.NewBufferString(""), bytes.NewBuffer([]byte{}))
+	server.SetToolPolicy(func(name string) bool { return name != "delete_relationship" })
+
+	calls := 0
+	server.RegisterTool(&MockTool{NameValue: "delete_relationship", CallFunc: func(ctx context.Context, args map[string]any) (any, error) {
+		calls++
+		return nil, nil
+	}})
+	server.RegisterTool(&MockTool{NameValue: "query_project"})
+
+	response := server.handleRequest(map[string]any{"jsonrpc": "2.0", "id": 1, "method": "tools/list"})
+	tools := response["result"].(map[string]any)["tools"].([]map[string]any)
+	if len(tools) != 1 || tools[0]["name"] != "query_project" {
+		t.Errorf("tools/list = %v, want only query_project", tools)
+	}
+
+	response = server.handleRequest(map[string]any{
+		"jsonrpc": "2.0", "id": 2, "method": "tools/call",
+		"params": map[string]any{"name": "delete_relationship"},
+	})
+	if _, hasError := response["error"]; !hasError {
+		t.Error("expected error response for a disallowed tool")
+	}
+	if calls != 0 {
+		t.Errorf("disallowed tool was called %d times", calls)
+	}
+}
+
+// TestStagingRoot tests that tool calls are pointed at the staged copy while
+// the audit log keeps the client's arguments.
+func TestStagingRoot(t *testing.T) {
+	server := NewServer("test", bytes.NewBufferString(""), bytes.NewBuffer([]byte{}))
+	auditor := &recordingAuditor{}
+	server.SetAuditor(auditor, func(name string) bool { return true })
+	server.SetStagingRoot("/project/.loko/staging/agent")
+
+	var got any
+	server.RegisterTool(&MockTool{NameValue: "create_system", CallFunc: func(ctx context.Context, args map[string]any) (any, error) {
+		got = args["project_root"]
+		return nil, nil
+	}})
+	server.handleRequest(map[string]any{
+		"jsonrpc": "2.0", "id": 1, "method": "tools/call",
+		"params": map[string]any{"name": "create_system", "arguments": map[string]any{"project_root": "/project"}},
+	})
+
+	if got != "/project/.loko/staging/agent" {
+		t.Errorf("tool project_root = %v, want the staging root", got)
+	}
+	if len(auditor.entries) != 1 || auditor.entries[0].Arguments["project_root"] != "/project" {
+		t.Errorf("audited arguments = %+v, want the client's project_root", auditor.entries)
+	}
+}
+
+// TestSandbox tests that path arguments are resolved by the sandbox and that
+// calls with rejected paths never reach the tool.
+func TestSandbox(t *testing.T) {
+	server := NewServer("test", bytes.NewBufferString(""), bytes.NewBuffer([]byte{}))
+	server.SetSandbox(func(path string) (string, error) {
+		if strings.HasPrefix(path, "..") {
+			return "", errors.New("outside")
+		}
+		return "/project/" + path, nil
+	})
+
+	var got map[string]any
+	server.RegisterTool(&MockTool{NameValue: "build_docs", CallFunc: func(ctx context.Context, args map[string]any) (any, error) {
+		got = args
+		return nil, nil
+	}})
+	call := func(arguments map[string]any) map[string]any {
+		got = nil
+		return server.handleRequest(map[string]any{
+			"jsonrpc": "2.0", "id": 1, "method": "tools/call",
+			"params": map[string]any{"name": "build_docs", "arguments": arguments},
+		})
+	}
+
+	call(map[string]any{"output_dir": "dist"})
+	if got["project_root"] != "/project/." || got["output_dir"] != "/project/dist" {
+		t.Errorf("tool arguments = %v, want resolved paths", got)
+	}
+
+	response := call(map[string]any{"project_root": ".", "output_dir": "../../etc"})
+	if _, hasError := response["error"]; !hasError {
+		t.Error("expected error response for a path outside the sandbox")
+	}
+	if got != nil {
+		t.Error("tool was called with a path outside the sandbox")
+	}
+}
+
 // TestCallNonexistentTool tests calling a tool that doesn't exist.
 func TestCallNonexistentTool(t *testing.T) {
 	server := NewServer("test", bytes.NewBufferString(""), bytes.NewBuffer([]byte{}))
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/madstone-tech/loko/internal/adapters/d2"
@@ -39,6 +40,10 @@ func (t *BuildDocsTool) InputSchema() map[string]any {
 				"type":        "string",
 				"description": "Output directory for HTML files",
 			},
+			"allow_plaintext": map[string]any{
+				"type":        "boolean",
+				"description": "Build even when the project sources are encrypted at rest, writing decrypted documentation",
+			},
 		},
 		"required": []string{"project_root", "output_dir"},
 	}
@@ -62,6 +67,13 @@ func (t *BuildDocsTool) Call(ctx context.Context, args map[string]any) (any, err
 	if err != nil {
 		return nil, fmt.Errorf("failed to load project: %w", err)
 	}
+	allowPlaintext, _ := args["allow_plaintext"].(bool)
+	if err := usecases.EnsurePlaintextAllowed(project, projectRoot, allowPlaintext); err != nil {
+		if errors.Is(err, usecases.ErrEncryptedSources) {
+			return nil, fmt.Errorf("%w (set allow_plaintext to override)", err)
+		}
+		return nil, err
+	}
 
 	systems, err := t.repo.ListSystems(ctx, projectRoot)
 	if err != nil {
@@ -0,0 +1,33 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// TestBuildDocsTool_EncryptedSources checks that a project encrypted at rest
+// is not built into plaintext documentation unless allow_plaintext is set.
+func TestBuildDocsTool_EncryptedSources(t *testing.T) {
+	projectRoot, repo := initTestProject(t)
+	encrypted := filepath.Join(projectRoot, "src", "payment-service", "notes.md.age")
+	if err := os.WriteFile(encrypted, []byte("age"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	outputDir := filepath.Join(t.TempDir(), "dist")
+
+	_, err := NewBuildDocsTool(repo).Call(context.Background(), map[string]any{
+		"project_root": projectRoot,
+		"output_dir":   outputDir,
+	})
+	if !errors.Is(err, usecases.ErrEncryptedSources) {
+		t.Fatalf("Call error = %v, want ErrEncryptedSources", err)
+	}
+	if _, err := os.Stat(outputDir); !os.IsNotExist(err) {
+		t.Errorf("output directory should not be written: %v", err)
+	}
+}
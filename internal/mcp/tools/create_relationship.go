@@ -44,6 +44,10 @@ func (t *CreateRelationshipTool) InputSchema() map[string]any {
 				"type": "string", "enum": []string{"forward", "bidirectional"},
 				"description": "Arrow direction (default: 'forward')",
 			},
+			"kind": map[string]any{
+				"type":        "string",
+				"description": "Semantic kind: uses, reads, writes, publishes, subscribes, implements, or a kind from [relationship_types] in loko.toml",
+			},
 		},
 	}
 }
@@ -91,6 +95,17 @@ func (t *CreateRelationshipTool) Call(ctx context.Context, args map[string]any)
 	}
 
 	uc := usecases.NewCreateRelationship(t.repo)
+	if t.projectRepo != nil {
+		var config *entities.ProjectConfig
+		if project, err := t.projectRepo.LoadProject(ctx, projectRoot); err == nil {
+			config = project.Config
+		}
+		kinds, err := usecases.NewValidateRelationshipKinds(config)
+		if err != nil {
+			return nil, err
+		}
+		uc.WithKinds(kinds)
+	}
 	rel, err := uc.Execute(ctx, &usecases.CreateRelationshipRequest{
 		ProjectRoot: projectRoot,
 		SystemID:    systemID,
@@ -100,6 +115,7 @@ func (t *CreateRelationshipTool) Call(ctx context.Context, args map[string]any)
 		Type:        getString(args, "type"),
 		Technology:  getString(args, "technology"),
 		Direction:   getString(args, "direction"),
+		Kind:        getString(args, "kind"),
 	})
 	if err != nil {
 		return nil, err
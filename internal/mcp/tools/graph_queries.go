@@ -0,0 +1,285 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/madstone-tech/loko/internal/adapters/encoding"
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// graphQuery holds what the read-only graph tools share: they answer from
+// the cached architecture graph of a project, building it on a cache miss.
+type graphQuery struct {
+	repo    usecases.ProjectRepository
+	relRepo usecases.RelationshipRepository // Optional: loads relationships.toml into graph
+	cache   GraphCache                      // Optional: reuses graphs across calls
+}
+
+// graph returns the architecture graph of the project at projectRoot.
+func (q graphQuery) graph(ctx context.Context, projectRoot string) (*entities.ArchitectureGraph, error) {
+	if q.cache != nil {
+		if graph, ok := q.cache.Get(projectRoot); ok {
+			return graph, nil
+		}
+	}
+	graph, err := getGraphFromProjectWithRel(ctx, q.repo, q.relRepo, projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	if q.cache != nil {
+		q.cache.Set(projectRoot, graph)
+	}
+	return graph, nil
+}
+
+// query parses the common arguments of a graph tool and returns the query
+// use case over the project's graph and the requested output format.
+func (q graphQuery) query(ctx context.Context, args map[string]any) (*usecases.QueryGraph, string, error) {
+	projectRoot := getString(args, "project_root")
+	if projectRoot == "" {
+		projectRoot = "."
+	}
+	format := getString(args, "format")
+	switch format {
+	case "":
+		format = "toon"
+	case "toon", "json":
+	default:
+		return nil, "", fmt.Errorf("unsupported format %q (supported: toon, json)", format)
+	}
+	graph, err := q.graph(ctx, projectRoot)
+	if err != nil {
+		return nil, "", err
+	}
+	return usecases.NewQueryGraph(graph), format, nil
+}
+
+// graphResult returns result as TOON text, or as is for JSON.
+func graphResult(result any, format string) (any, error) {
+	if format == "json" {
+		return result, nil
+	}
+	data, err := encoding.NewEncoder().EncodeTOON(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", err)
+	}
+	return string(data), nil
+}
+
+// graphToolSchema returns the input schema of a graph tool with the common
+// project_root and format properties and extra ones.
+func graphToolSchema(required []string, extra map[string]any) map[string]any {
+	properties := map[string]any{
+		"project_root": map[string]any{"type": "string", "description": "Root directory of the project"},
+		"format": map[string]any{
+			"type":        "string",
+			"enum":        []string{"toon", "json"},
+			"default":     "toon",
+			"description": "Output format: toon (Token-Optimized Object Notation) or json",
+		},
+	}
+	for name, property := range extra {
+		properties[name] = property
+	}
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   append([]string{"project_root"}, required...),
+	}
+}
+
+// entityIDProperty is the schema of the element ID input of the graph tools.
+var entityIDProperty = map[string]any{
+	"type":        "string",
+	"description": "Qualified ID (e.g., 'shop/api/cart') or unambiguous short ID (e.g., 'cart') of a system, container or component",
+}
+
+// transitiveProperty is the schema of the transitive input of the graph tools.
+var transitiveProperty = map[string]any{
+	"type":        "boolean",
+	"description": "Also follow relationships transitively; each element reports its depth (default: false)",
+}
+
+// neighborsResult is the result of get_dependencies and get_dependents.
+type neighborsResult struct {
+	Entity   string                  `json:"entity"   toon:"entity"`
+	Elements []usecases.GraphNodeRef `json:"elements" toon:"elements"`
+}
+
+// GetDependenciesTool lists the elements an element depends on.
+type GetDependenciesTool struct{ graphQuery }
+
+// NewGetDependenciesTool creates a new get_dependencies tool.
+func NewGetDependenciesTool(repo usecases.ProjectRepository, relRepo usecases.RelationshipRepository, cache GraphCache) *GetDependenciesTool {
+	return &GetDependenciesTool{graphQuery{repo: repo, relRepo: relRepo, cache: cache}}
+}
+
+func (t *GetDependenciesTool) Name() string {
+	return "get_dependencies"
+}
+
+func (t *GetDependenciesTool) Description() string {
+	return "List the systems, containers and components an element depends on, directly or transitively, from the cached architecture graph. Returns TOON by default."
+}
+
+func (t *GetDependenciesTool) InputSchema() map[string]any {
+	return graphToolSchema([]string{"entity_id"}, map[string]any{
+		"entity_id":  entityIDProperty,
+		"transitive": transitiveProperty,
+	})
+}
+
+func (t *GetDependenciesTool) Call(ctx context.Context, args map[string]any) (any, error) {
+	query, format, err := t.query(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	entity, err := query.Resolve(getString(args, "entity_id"))
+	if err != nil {
+		return nil, err
+	}
+	transitive, _ := args["transitive"].(bool)
+	elements, err := query.Dependencies(entity, transitive)
+	if err != nil {
+		return nil, err
+	}
+	return graphResult(neighborsResult{Entity: entity, Elements: elements}, format)
+}
+
+// GetDependentsTool lists the elements depending on an element.
+type GetDependentsTool struct{ graphQuery }
+
+// NewGetDependentsTool creates a new get_dependents tool.
+func NewGetDependentsTool(repo usecases.ProjectRepository, relRepo usecases.RelationshipRepository, cache GraphCache) *GetDependentsTool {
+	return &GetDependentsTool{graphQuery{repo: repo, relRepo: relRepo, cache: cache}}
+}
+
+func (t *GetDependentsTool) Name() string {
+	return "get_dependents"
+}
+
+func (t *GetDependentsTool) Description() string {
+	return "List the systems, containers and components depending on an element, directly or transitively, from the cached architecture graph. Returns TOON by default."
+}
+
+func (t *GetDependentsTool) InputSchema() map[string]any {
+	return graphToolSchema([]string{"entity_id"}, map[string]any{
+		"entity_id":  entityIDProperty,
+		"transitive": transitiveProperty,
+	})
+}
+
+func (t *GetDependentsTool) Call(ctx context.Context, args map[string]any) (any, error) {
+	query, format, err := t.query(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	entity, err := query.Resolve(getString(args, "entity_id"))
+	if err != nil {
+		return nil, err
+	}
+	transitive, _ := args["transitive"].(bool)
+	elements, err := query.Dependents(entity, transitive)
+	if err != nil {
+		return nil, err
+	}
+	return graphResult(neighborsResult{Entity: entity, Elements: elements}, format)
+}
+
+// pathResult is the result of find_path.
+type pathResult struct {
+	Source string                   `json:"source"         toon:"source"`
+	Target string                   `json:"target"         toon:"target"`
+	Found  bool                     `json:"found"          toon:"found"`
+	Path   []usecases.GraphPathStep `json:"path,omitempty" toon:"path,omitempty"`
+}
+
+// FindPathTool finds how one element reaches another through relationships.
+type FindPathTool struct{ graphQuery }
+
+// NewFindPathTool creates a new find_path tool.
+func NewFindPathTool(repo usecases.ProjectRepository, relRepo usecases.RelationshipRepository, cache GraphCache) *FindPathTool {
+	return &FindPathTool{graphQuery{repo: repo, relRepo: relRepo, cache: cache}}
+}
+
+func (t *FindPathTool) Name() string {
+	return "find_path"
+}
+
+func (t *FindPathTool) Description() string {
+	return "Find a shortest chain of relationships from one element to another in the cached architecture graph, with the description of each hop. Returns TOON by default."
+}
+
+func (t *FindPathTool) InputSchema() map[string]any {
+	return graphToolSchema([]string{"source_id", "target_id"}, map[string]any{
+		"source_id": withDescription(entityIDProperty, "Element the path starts from: qualified or unambiguous short ID"),
+		"target_id": withDescription(entityIDProperty, "Element the path leads to: qualified or unambiguous short ID"),
+	})
+}
+
+func (t *FindPathTool) Call(ctx context.Context, args map[string]any) (any, error) {
+	query, format, err := t.query(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	source, err := query.Resolve(getString(args, "source_id"))
+	if err != nil {
+		return nil, err
+	}
+	target, err := query.Resolve(getString(args, "target_id"))
+	if err != nil {
+		return nil, err
+	}
+	path, err := query.FindPath(source, target)
+	if err != nil {
+		return nil, err
+	}
+	return graphResult(pathResult{Source: source, Target: target, Found: path != nil, Path: path}, format)
+}
+
+// ImpactAnalysisTool reports the blast radius of a change to an element.
+type ImpactAnalysisTool struct{ graphQuery }
+
+// NewImpactAnalysisTool creates a new impact_analysis tool.
+func NewImpactAnalysisTool(repo usecases.ProjectRepository, relRepo usecases.RelationshipRepository, cache GraphCache) *ImpactAnalysisTool {
+	return &ImpactAnalysisTool{graphQuery{repo: repo, relRepo: relRepo, cache: cache}}
+}
+
+func (t *ImpactAnalysisTool) Name() string {
+	return "impact_analysis"
+}
+
+func (t *ImpactAnalysisTool) Description() string {
+	return "Report the blast radius of changing an element: every element depending on it or on its children, transitively, with its distance, and the systems and owners impacted. Answers from the cached architecture graph; returns TOON by default."
+}
+
+func (t *ImpactAnalysisTool) InputSchema() map[string]any {
+	return graphToolSchema([]string{"entity_id"}, map[string]any{
+		"entity_id": entityIDProperty,
+		"max_depth": map[string]any{"type": "number", "description": "Follow at most this many relationships (default: no limit)"},
+	})
+}
+
+func (t *ImpactAnalysisTool) Call(ctx context.Context, args map[string]any) (any, error) {
+	query, format, err := t.query(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	report, err := query.Impact(getString(args, "entity_id"), getInt(args, "max_depth"))
+	if err != nil {
+		return nil, err
+	}
+	return graphResult(report, format)
+}
+
+// withDescription returns a copy of schema with a description.
+func withDescription(schema map[string]any, description string) map[string]any {
+	out := make(map[string]any, len(schema))
+	for key, value := range schema {
+		out[key] = value
+	}
+	out["description"] = description
+	return out
+}
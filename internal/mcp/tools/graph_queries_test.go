@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/core/entities"
+	"github.com/madstone-tech/loko/internal/core/usecases"
+	"github.com/madstone-tech/loko/internal/mcp"
+)
+
+// cachedGraphFixture caches the graph of a project where shop/web depends on
+// shop/api/cart, so the graph tools can answer without loading a project.
+func cachedGraphFixture(t *testing.T, projectRoot string) GraphCache {
+	t.Helper()
+	project, _ := entities.NewProject("shop")
+	shop, _ := entities.NewSystem("Shop")
+	web, _ := entities.NewContainer("Web")
+	web.Relationships = map[string]string{"shop/api/cart": "adds items to"}
+	api, _ := entities.NewContainer("API")
+	cart, _ := entities.NewComponent("Cart")
+	_ = api.AddComponent(cart)
+	_ = shop.AddContainer(web)
+	_ = shop.AddContainer(api)
+
+	graph, err := usecases.NewBuildArchitectureGraph().Execute(context.Background(), project, []*entities.System{shop})
+	if err != nil {
+		t.Fatalf("failed to build graph: %v", err)
+	}
+	cache := mcp.NewGraphCache()
+	cache.Set(projectRoot, graph)
+	return cache
+}
+
+func TestGraphQueryToolsAnswerFromCache(t *testing.T) {
+	cache := cachedGraphFixture(t, "/project")
+
+	tests := []struct {
+		tool Tool
+		args map[string]any
+		want []string
+	}{
+		{NewGetDependenciesTool(nil, nil, cache), map[string]any{"entity_id": "web"}, []string{"entity: shop/web", "shop/api/cart"}},
+		{NewGetDependentsTool(nil, nil, cache), map[string]any{"entity_id": "cart"}, []string{"entity: shop/api/cart", "shop/web"}},
+		{NewFindPathTool(nil, nil, cache), map[string]any{"source_id": "web", "target_id": "cart"}, []string{"found: true", "adds items to"}},
+		{NewImpactAnalysisTool(nil, nil, cache), map[string]any{"entity_id": "shop/api"}, []string{"entity: shop/api", "shop/web"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool.Name(), func(t *testing.T) {
+			tt.args["project_root"] = "/project"
+			result, err := tt.tool.Call(context.Background(), tt.args)
+			if err != nil {
+				t.Fatalf("Call failed: %v", err)
+			}
+			text, ok := result.(string)
+			if !ok {
+				t.Fatalf("result = %T, want TOON text", result)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("result does not contain %q:\n%s", want, text)
+				}
+			}
+		})
+	}
+}
+
+func TestGraphQueryToolsJSONAndErrors(t *testing.T) {
+	cache := cachedGraphFixture(t, "/project")
+	tool := NewGetDependenciesTool(nil, nil, cache)
+
+	result, err := tool.Call(context.Background(), map[string]any{"project_root": "/project", "entity_id": "web", "format": "json"})
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	neighbors, ok := result.(neighborsResult)
+	if !ok || len(neighbors.Elements) != 1 || neighbors.Elements[0].ID != "shop/api/cart" {
+		t.Errorf("result = %+v, want shop/api/cart", result)
+	}
+
+	if _, err := tool.Call(context.Background(), map[string]any{"project_root": "/project", "entity_id": "nowhere"}); err == nil {
+		t.Error("expected an error for an unknown element")
+	}
+	if _, err := tool.Call(context.Background(), map[string]any{"project_root": "/project", "entity_id": "web", "format": "xml"}); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
@@ -3,6 +3,8 @@ package tools
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
 	"github.com/madstone-tech/loko/internal/core/usecases"
@@ -149,7 +151,7 @@ func (t *QueryDependenciesTool) Call(ctx context.Context, args map[string]any) (
 	}
 
 	// Build architecture graph (includes relationships.toml when relRepo is wired).
-	graphBuilder := usecases.NewBuildArchitectureGraphWithRelRepo(t.relRepo)
+	graphBuilder := newGraphBuilder(t.relRepo)
 	graph, err := graphBuilder.Execute(ctx, project, systems)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build architecture graph: %w", err)
@@ -236,7 +238,7 @@ func (t *QueryDependenciesTool) queryContainerDependencies(
 	seen := make(map[string]bool)
 	var allDeps []map[string]any
 
-	for shortCompID := range container.Components {
+	for _, shortCompID := range slices.Sorted(maps.Keys(container.Components)) {
 		// Resolve short component ID to qualified graph node ID.
 		qualifiedID, ok := graph.ResolveID(shortCompID)
 		if !ok {
@@ -345,7 +347,7 @@ func (t *QueryRelatedComponentsTool) Call(ctx context.Context, args map[string]a
 	}
 
 	// Build architecture graph (includes relationships.toml when relRepo is wired).
-	graphBuilder := usecases.NewBuildArchitectureGraphWithRelRepo(t.relRepo)
+	graphBuilder := newGraphBuilder(t.relRepo)
 	graph, err := graphBuilder.Execute(ctx, project, systems)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build architecture graph: %w", err)
@@ -462,7 +464,7 @@ func (t *AnalyzeCouplingTool) Call(ctx context.Context, args map[string]any) (an
 	}
 
 	// Build architecture graph (includes relationships.toml when relRepo is wired).
-	graphBuilder := usecases.NewBuildArchitectureGraphWithRelRepo(t.relRepo)
+	graphBuilder := newGraphBuilder(t.relRepo)
 	graph, err := graphBuilder.Execute(ctx, project, systems)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build architecture graph: %w", err)
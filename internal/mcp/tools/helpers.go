@@ -3,9 +3,11 @@ package tools
 import (
 	"context"
 	"fmt"
+	"maps"
 	"strings"
 
 	"github.com/go-viper/mapstructure/v2"
+	"github.com/madstone-tech/loko/internal/adapters/d2"
 	"github.com/madstone-tech/loko/internal/core/entities"
 	"github.com/madstone-tech/loko/internal/core/usecases"
 )
@@ -192,6 +194,13 @@ func notFoundError(entityType, input, suggestion string) error {
 }
 
 // updateComponentSchema is the JSON schema for the update_component tool input.
+// expectedHashProperty is the schema of the optional expected_hash input of
+// the update tools.
+var expectedHashProperty = map[string]any{
+	"type":        "string",
+	"description": "content_hash returned when the element was last read or updated; the update fails with a conflict if its file changed since",
+}
+
 var updateComponentSchema = map[string]any{
 	"type":     "object",
 	"required": []string{"project_root", "system_name", "container_name", "component_name"},
@@ -203,6 +212,7 @@ var updateComponentSchema = map[string]any{
 		"description":    map[string]any{"type": "string", "description": "New description (leave empty to keep current)"},
 		"technology":     map[string]any{"type": "string", "description": "New technology (leave empty to keep current)"},
 		"tags":           map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Replace tags list"},
+		"expected_hash":  expectedHashProperty,
 	},
 }
 
@@ -232,6 +242,63 @@ var createComponentsSchema = map[string]any{
 	"required": []string{"project_root", "system_name", "container_name", "components"},
 }
 
+// proposeArchitectureSchema is the JSON schema for the propose_architecture
+// tool input.
+var proposeArchitectureSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"project_root": map[string]any{"type": "string", "description": "Root directory of the project"},
+		"systems": map[string]any{
+			"type":        "array",
+			"description": "Systems to create, or existing systems to add containers to",
+			"items": proposedElementSchema("System", map[string]any{
+				"containers": map[string]any{
+					"type":        "array",
+					"description": "Containers of the system, or existing containers to add components to",
+					"items": proposedElementSchema("Container", map[string]any{
+						"technology": map[string]any{"type": "string", "description": "Technology (e.g., 'Go', 'PostgreSQL')"},
+						"components": map[string]any{
+							"type":        "array",
+							"description": "Components of the container",
+							"items": proposedElementSchema("Component", map[string]any{
+								"technology": map[string]any{"type": "string", "description": "Technology/implementation details"},
+							}),
+						},
+					}),
+				},
+			}),
+		},
+		"relationships": map[string]any{
+			"type":        "array",
+			"description": "Relationships between elements of the proposal or of the project",
+			"items": map[string]any{
+				"type":     "object",
+				"required": []string{"source", "target"},
+				"properties": map[string]any{
+					"source":     map[string]any{"type": "string", "description": "Source element path with names or IDs, e.g. 'Shop/Web' or 'shop/web/cart'"},
+					"target":     map[string]any{"type": "string", "description": "Target element path, e.g. 'payments'"},
+					"label":      map[string]any{"type": "string", "description": "Human-readable description (default: 'Uses')"},
+					"type":       map[string]any{"type": "string", "enum": []string{"sync", "async", "event"}, "description": "Communication type (default: 'sync')"},
+					"technology": map[string]any{"type": "string", "description": "Technology used (e.g., 'gRPC')"},
+				},
+			},
+		},
+	},
+	"required": []string{"project_root"},
+}
+
+// proposedElementSchema is the schema of an element of a proposal with the
+// common name, description and tags properties and extra ones.
+func proposedElementSchema(kind string, extra map[string]any) map[string]any {
+	properties := map[string]any{
+		"name":        map[string]any{"type": "string", "description": kind + " name"},
+		"description": map[string]any{"type": "string", "description": "What does this " + strings.ToLower(kind) + " do?"},
+		"tags":        map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Tags for categorization"},
+	}
+	maps.Copy(properties, extra)
+	return map[string]any{"type": "object", "required": []string{"name"}, "properties": properties}
+}
+
 // relationshipToMap converts a Relationship entity to a JSON-friendly map.
 func relationshipToMap(rel *entities.Relationship) map[string]any {
 	m := map[string]any{
@@ -249,6 +316,9 @@ func relationshipToMap(rel *entities.Relationship) map[string]any {
 	if rel.Direction != "" {
 		m["direction"] = rel.Direction
 	}
+	if rel.Kind != "" {
+		m["kind"] = rel.Kind
+	}
 	return m
 }
 
@@ -302,6 +372,13 @@ func getGraphFromProject(ctx context.Context, repo usecases.ProjectRepository, p
 	return getGraphFromProjectWithRel(ctx, repo, nil, projectRoot)
 }
 
+// newGraphBuilder returns the graph builder of the query tools: relationships
+// come from frontmatter, the D2 diagrams at every level and, when relRepo is
+// non-nil, relationships.toml.
+func newGraphBuilder(relRepo usecases.RelationshipRepository) *usecases.BuildArchitectureGraph {
+	return usecases.NewBuildArchitectureGraphFull(d2.NewD2Parser(), relRepo)
+}
+
 // getGraphFromProjectWithRel builds and returns an ArchitectureGraph, optionally
 // loading TOML relationships when relRepo is non-nil.
 func getGraphFromProjectWithRel(ctx context.Context, repo usecases.ProjectRepository, relRepo usecases.RelationshipRepository, projectRoot string) (*entities.ArchitectureGraph, error) {
@@ -317,7 +394,7 @@ func getGraphFromProjectWithRel(ctx context.Context, repo usecases.ProjectReposi
 	}
 
 	// Build architecture graph (includes relationships.toml when relRepo is non-nil).
-	graphBuilder := usecases.NewBuildArchitectureGraphWithRelRepo(relRepo)
+	graphBuilder := newGraphBuilder(relRepo)
 	graph, err := graphBuilder.Execute(ctx, project, systems)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build architecture graph: %w", err)
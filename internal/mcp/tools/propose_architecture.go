@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// ProposeArchitectureTool creates a whole hierarchy of systems, containers,
+// components and relationships in one transaction.
+type ProposeArchitectureTool struct {
+	repo             usecases.ProjectRepository
+	relRepo          usecases.RelationshipRepository
+	diagramGenerator usecases.DiagramGenerator
+	graphCache       GraphCache
+}
+
+// NewProposeArchitectureTool creates a new propose_architecture tool.
+func NewProposeArchitectureTool(
+	repo usecases.ProjectRepository,
+	relRepo usecases.RelationshipRepository,
+	diagramGenerator usecases.DiagramGenerator,
+	cache GraphCache,
+) *ProposeArchitectureTool {
+	return &ProposeArchitectureTool{repo: repo, relRepo: relRepo, diagramGenerator: diagramGenerator, graphCache: cache}
+}
+
+func (t *ProposeArchitectureTool) Name() string { return "propose_architecture" }
+
+func (t *ProposeArchitectureTool) Description() string {
+	return "Create systems, containers, components and the relationships between them in one transaction: the whole proposal is checked first, and if any step fails nothing is left behind. Existing systems and containers are reused."
+}
+
+func (t *ProposeArchitectureTool) InputSchema() map[string]any { return proposeArchitectureSchema }
+
+// Call executes the propose_architecture tool.
+func (t *ProposeArchitectureTool) Call(ctx context.Context, args map[string]any) (any, error) {
+	projectRoot := getString(args, "project_root")
+	if projectRoot == "" {
+		projectRoot = "."
+	}
+
+	proposal := &usecases.ArchitectureProposal{}
+	for _, s := range objectList(args, "systems") {
+		system := usecases.ProposedSystem{
+			Name: getString(s, "name"), Description: getString(s, "description"), Tags: stringList(s, "tags"),
+		}
+		for _, c := range objectList(s, "containers") {
+			container := usecases.ProposedContainer{
+				Name: getString(c, "name"), Description: getString(c, "description"),
+				Technology: getString(c, "technology"), Tags: stringList(c, "tags"),
+			}
+			for _, comp := range objectList(c, "components") {
+				container.Components = append(container.Components, usecases.ProposedComponent{
+					Name: getString(comp, "name"), Description: getString(comp, "description"),
+					Technology: getString(comp, "technology"), Tags: stringList(comp, "tags"),
+				})
+			}
+			system.Containers = append(system.Containers, container)
+		}
+		proposal.Systems = append(proposal.Systems, system)
+	}
+	for _, r := range objectList(args, "relationships") {
+		proposal.Relationships = append(proposal.Relationships, usecases.ProposedRelationship{
+			Source: getString(r, "source"), Target: getString(r, "target"), Label: getString(r, "label"),
+			Type: getString(r, "type"), Technology: getString(r, "technology"),
+		})
+	}
+
+	result, err := usecases.NewProposeArchitecture(t.repo, t.relRepo, usecases.WithDiagramGenerator(t.diagramGenerator)).
+		Execute(ctx, projectRoot, proposal)
+	if err != nil {
+		return nil, err
+	}
+	if t.graphCache != nil {
+		t.graphCache.Invalidate(projectRoot)
+	}
+
+	relationships := make([]map[string]any, 0, len(result.Relationships))
+	for _, rel := range result.Relationships {
+		relationships = append(relationships, relationshipToMap(rel))
+	}
+	return map[string]any{
+		"created":       result.Created,
+		"existing":      result.Existing,
+		"relationships": relationships,
+		"message": fmt.Sprintf("Created %d element(s) and %d relationship(s)",
+			len(result.Created), len(result.Relationships)),
+	}, nil
+}
+
+// objectList returns the objects of the array args[key], skipping other items.
+func objectList(args map[string]any, key string) []map[string]any {
+	items, _ := args[key].([]any)
+	objects := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		if object, ok := item.(map[string]any); ok {
+			objects = append(objects, object)
+		}
+	}
+	return objects
+}
+
+// stringList returns the strings of the array args[key], skipping other items.
+func stringList(args map[string]any, key string) []string {
+	items, _ := args[key].([]any)
+	var strs []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/madstone-tech/loko/internal/adapters/filesystem"
+)
+
+func proposalArgs(projectRoot string) map[string]any {
+	return map[string]any{
+		"project_root": projectRoot,
+		"systems": []any{
+			map[string]any{"name": "Billing", "description": "Invoices customers", "containers": []any{
+				map[string]any{"name": "Worker", "technology": "Go"},
+			}},
+			map[string]any{"name": "Payment Service", "containers": []any{
+				map[string]any{"name": "API Server", "components": []any{
+					map[string]any{"name": "Cart", "tags": []any{"core"}},
+				}},
+			}},
+		},
+		"relationships": []any{
+			map[string]any{"source": "Billing/Worker", "target": "payment-service/api-server", "label": "Fetches payments", "technology": "HTTPS"},
+		},
+	}
+}
+
+func TestProposeArchitectureTool(t *testing.T) {
+	projectRoot := initTestProjectWithContainer(t)
+	relRepo := filesystem.NewFilesystemRelationshipRepository()
+	tool := NewProposeArchitectureTool(filesystem.NewProjectRepository(), relRepo, nil, nil)
+
+	result, err := tool.Call(context.Background(), proposalArgs(projectRoot))
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	resp := result.(map[string]any)
+	created, _ := resp["created"].([]string)
+	if strings.Join(created, ",") != "billing,billing/worker,payment-service/api-server/cart" {
+		t.Errorf("created = %v", created)
+	}
+	if existing, _ := resp["existing"].([]string); len(existing) != 2 {
+		t.Errorf("expected the payment service and its API server to be reused, got %v", existing)
+	}
+
+	for _, rel := range []string{"billing/system.md", "billing/worker/container.md", "payment-service/api-server/cart/component.md"} {
+		if _, err := os.Stat(filepath.Join(projectRoot, "src", rel)); err != nil {
+			t.Errorf("expected %s: %v", rel, err)
+		}
+	}
+	rels, err := relRepo.LoadRelationships(context.Background(), projectRoot, "billing")
+	if err != nil || len(rels) != 1 || rels[0].Technology != "HTTPS" {
+		t.Errorf("relationships = %+v, %v", rels, err)
+	}
+}
+
+// TestProposeArchitectureTool_RollsBackOnFailure validates that an element
+// failing to save mid-sequence leaves none of the proposal behind.
+func TestProposeArchitectureTool_RollsBackOnFailure(t *testing.T) {
+	projectRoot := initTestProjectWithContainer(t)
+	// A file where the Cart component directory must go makes its save fail
+	// after Billing was created.
+	blocker := filepath.Join(projectRoot, "src", "payment-service", "api-server", "cart")
+	if err := os.WriteFile(blocker, []byte("in the way"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tool := NewProposeArchitectureTool(filesystem.NewProjectRepository(), filesystem.NewFilesystemRelationshipRepository(), nil, nil)
+
+	_, err := tool.Call(context.Background(), proposalArgs(projectRoot))
+	if err == nil || !strings.Contains(err.Error(), "no changes were made") {
+		t.Fatalf("expected a rolled back failure, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, "src", "billing")); !os.IsNotExist(err) {
+		t.Errorf("billing was left behind: %v", err)
+	}
+	if _, err := os.Stat(blocker); err != nil {
+		t.Errorf("pre-existing file was removed: %v", err)
+	}
+}
+
+func TestProposeArchitectureTool_InvalidProposal(t *testing.T) {
+	projectRoot := initTestProjectWithContainer(t)
+	tool := NewProposeArchitectureTool(filesystem.NewProjectRepository(), filesystem.NewFilesystemRelationshipRepository(), nil, nil)
+
+	args := proposalArgs(projectRoot)
+	args["relationships"] = []any{map[string]any{"source": "billing/worker", "target": "ledger"}}
+	if _, err := tool.Call(context.Background(), args); err == nil || !strings.Contains(err.Error(), "ledger") {
+		t.Fatalf("expected the unknown element to be reported, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, "src", "billing")); !os.IsNotExist(err) {
+		t.Errorf("an invalid proposal wrote billing: %v", err)
+	}
+}
@@ -21,6 +21,31 @@ type Tool interface {
 	Call(ctx context.Context, args map[string]any) (any, error)
 }
 
+// mutatingTools lists the tools that write to the project directory: those
+// that change the architecture model and build_docs, which writes the site.
+var mutatingTools = map[string]bool{
+	"build_docs":           true,
+	"create_system":        true,
+	"create_container":     true,
+	"create_component":     true,
+	"create_components":    true,
+	"update_system":        true,
+	"update_container":     true,
+	"update_component":     true,
+	"update_diagram":       true,
+	"create_relationship":  true,
+	"delete_relationship":  true,
+	"propose_architecture": true,
+	"commit_changes":       true,
+	"discard_changes":      true,
+}
+
+// IsMutating reports whether the named tool writes to the project directory,
+// so its calls are recorded in the audit log.
+func IsMutating(name string) bool {
+	return mutatingTools[name]
+}
+
 // Registry manages a collection of MCP tools.
 type Registry struct {
 	mu    sync.RWMutex
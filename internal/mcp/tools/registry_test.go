@@ -177,3 +177,50 @@ func TestRegistry_ConcurrentAccess(t *testing.T) {
 		<-readDone
 	}
 }
+
+// TestIsMutating verifies that every tool that writes to the project
+// directory is audited and that read-only tools are not.
+func TestIsMutating(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"create_system", true},
+		{"create_container", true},
+		{"create_component", true},
+		{"create_components", true},
+		{"update_system", true},
+		{"update_container", true},
+		{"update_component", true},
+		{"update_diagram", true},
+		{"create_relationship", true},
+		{"delete_relationship", true},
+		{"propose_architecture", true},
+		{"commit_changes", true},
+		{"discard_changes", true},
+		{"build_docs", true},
+		{"query_project", false},
+		{"query_architecture", false},
+		{"search_elements", false},
+		{"find_relationships", false},
+		{"list_relationships", false},
+		{"query_dependencies", false},
+		{"query_related_components", false},
+		{"analyze_coupling", false},
+		{"get_dependencies", false},
+		{"get_dependents", false},
+		{"find_path", false},
+		{"impact_analysis", false},
+		{"staged_changes", false},
+		{"validate", false},
+		{"validate_diagram", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsMutating(tt.name); got != tt.want {
+				t.Errorf("IsMutating(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
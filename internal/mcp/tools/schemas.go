@@ -154,6 +154,10 @@ var Schemas = map[string]any{
 				"type":        "string",
 				"description": "Output directory for HTML files",
 			},
+			"allow_plaintext": map[string]any{
+				"type":        "boolean",
+				"description": "Build even when the project sources are encrypted at rest, writing decrypted documentation",
+			},
 		},
 		"required": []string{"project_root", "output_dir"},
 	},
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/madstone-tech/loko/internal/core/entities"
 	"github.com/madstone-tech/loko/internal/core/usecases"
@@ -24,7 +25,7 @@ func (t *SearchElementsTool) Name() string {
 }
 
 func (t *SearchElementsTool) Description() string {
-	return "Search architecture elements by name pattern, type, technology, or tags"
+	return "Search architecture elements by name pattern, type, technology, tags, or custom frontmatter fields"
 }
 
 func (t *SearchElementsTool) InputSchema() map[string]any {
@@ -32,11 +33,16 @@ func (t *SearchElementsTool) InputSchema() map[string]any {
 		"type": "object",
 		"properties": map[string]any{
 			"project_root": map[string]any{"type": "string", "description": "Project root directory"},
-			"query":        map[string]any{"type": "string", "description": "Search pattern (supports glob: *, ?)"},
+			"query":        map[string]any{"type": "string", "description": "Search pattern (supports glob: *, ?); terms like meta.compliance=pci filter by custom fields"},
 			"type":         map[string]any{"type": "string", "description": "Filter by type: system, container, component"},
 			"technology":   map[string]any{"type": "string", "description": "Filter by technology (e.g., Go, Python)"},
 			"tag":          map[string]any{"type": "string", "description": "Filter by tag (e.g., critical, production)"},
-			"limit":        map[string]any{"type": "number", "description": "Max results (default: 20, max: 100)"},
+			"metadata": map[string]any{
+				"type":                 "object",
+				"additionalProperties": map[string]any{"type": "string"},
+				"description":          "Filter by custom frontmatter fields (e.g., {\"compliance\": \"pci\"})",
+			},
+			"limit": map[string]any{"type": "number", "description": "Max results (default: 20, max: 100)"},
 		},
 		"required": []string{"project_root", "query"},
 	}
@@ -52,6 +58,12 @@ func (t *SearchElementsTool) Call(ctx context.Context, arguments map[string]any)
 		Tag:         getString(arguments, "tag"),
 		Limit:       getInt(arguments, "limit"),
 	}
+	if filters, ok := arguments["metadata"].(map[string]any); ok {
+		req.Metadata = make(map[string]string, len(filters))
+		for key, value := range filters {
+			req.Metadata[key] = fmt.Sprint(value)
+		}
+	}
 
 	// Call use case
 	return t.useCase.Execute(ctx, req)
@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/madstone-tech/loko/internal/core/usecases"
+)
+
+// StagedChangesTool lists the changes staged by the session for review.
+type StagedChangesTool struct {
+	area *usecases.StagingArea
+}
+
+// NewStagedChangesTool creates a new staged_changes tool.
+func NewStagedChangesTool(area *usecases.StagingArea) *StagedChangesTool {
+	return &StagedChangesTool{area: area}
+}
+
+func (t *StagedChangesTool) Name() string { return "staged_changes" }
+
+func (t *StagedChangesTool) Description() string {
+	return "List the architecture changes staged by this session, with a line diff of each file, for review before commit_changes or discard_changes"
+}
+
+func (t *StagedChangesTool) InputSchema() map[string]any {
+	return map[string]any{"type": "object", "properties": map[string]any{}}
+}
+
+// Call executes the staged_changes tool.
+func (t *StagedChangesTool) Call(ctx context.Context, args map[string]any) (any, error) {
+	changes, err := t.area.Changes()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"session": t.area.Session(),
+		"changes": changes,
+		"count":   len(changes),
+	}, nil
+}
+
+// CommitChangesTool writes the session's staged changes to the project.
+type CommitChangesTool struct {
+	area       *usecases.StagingArea
+	graphCache GraphCache
+}
+
+// NewCommitChangesTool creates a new commit_changes tool.
+func NewCommitChangesTool(area *usecases.StagingArea, cache GraphCache) *CommitChangesTool {
+	return &CommitChangesTool{area: area, graphCache: cache}
+}
+
+func (t *CommitChangesTool) Name() string { return "commit_changes" }
+
+func (t *CommitChangesTool) Description() string {
+	return "Apply the architecture changes staged by this session to the project. Fails without changes if the same files were edited in the project meanwhile."
+}
+
+func (t *CommitChangesTool) InputSchema() map[string]any {
+	return map[string]any{"type": "object", "properties": map[string]any{}}
+}
+
+// Call executes the commit_changes tool.
+func (t *CommitChangesTool) Call(ctx context.Context, args map[string]any) (any, error) {
+	changes, err := t.area.Commit()
+	if err != nil {
+		return nil, err
+	}
+	if t.graphCache != nil {
+		t.graphCache.Invalidate(t.area.Root())
+	}
+	return map[string]any{
+		"session": t.area.Session(),
+		"changes": changes,
+		"message": fmt.Sprintf("Committed %d staged file change(s)", len(changes)),
+	}, nil
+}
+
+// DiscardChangesTool drops the session's staged changes.
+type DiscardChangesTool struct {
+	area       *usecases.StagingArea
+	graphCache GraphCache
+}
+
+// NewDiscardChangesTool creates a new discard_changes tool.
+func NewDiscardChangesTool(area *usecases.StagingArea, cache GraphCache) *DiscardChangesTool {
+	return &DiscardChangesTool{area: area, graphCache: cache}
+}
+
+func (t *DiscardChangesTool) Name() string { return "discard_changes" }
+
+func (t *DiscardChangesTool) Description() string {
+	return "Drop the architecture changes staged by this session and start again from the project"
+}
+
+func (t *DiscardChangesTool) InputSchema() map[string]any {
+	return map[string]any{"type": "object", "properties": map[string]any{}}
+}
+
+// Call executes the discard_changes tool.
+func (t *DiscardChangesTool) Call(ctx context.Context, args map[string]any) (any, error) {
+	if err := t.area.Discard(); err != nil {
+		return nil, err
+	}
+	if t.graphCache != nil {
+		t.graphCache.Invalidate(t.area.Root())
+	}
+	return map[string]any{
+		"session": t.area.Session(),
+		"message": "Staged changes discarded",
+	}, nil
+}
@@ -69,6 +69,11 @@ func (t *UpdateComponentTool) Call(ctx context.Context, args map[string]any) (an
 		return nil, notFoundError("component", componentName, suggestion)
 	}
 
+	// Fail the save if the file changed since the caller read it
+	if hash := getString(args, "expected_hash"); hash != "" {
+		component.ContentHash = hash
+	}
+
 	// Update only non-empty fields
 	if desc, ok := args["description"].(string); ok && desc != "" {
 		component.Description = desc
@@ -87,11 +92,12 @@ func (t *UpdateComponentTool) Call(ctx context.Context, args map[string]any) (an
 
 	return map[string]any{
 		"component": map[string]any{
-			"id":          component.ID,
-			"name":        component.Name,
-			"description": component.Description,
-			"technology":  component.Technology,
-			"tags":        component.Tags,
+			"id":           component.ID,
+			"name":         component.Name,
+			"description":  component.Description,
+			"technology":   component.Technology,
+			"tags":         component.Tags,
+			"content_hash": component.ContentHash,
 		},
 		"message": fmt.Sprintf("Component %q updated", component.Name),
 	}, nil
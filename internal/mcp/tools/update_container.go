@@ -55,6 +55,7 @@ func (t *UpdateContainerTool) InputSchema() map[string]any {
 				"items":       map[string]any{"type": "string"},
 				"description": "Replace tags list",
 			},
+			"expected_hash": expectedHashProperty,
 		},
 		"required": []string{"project_root", "system_name", "container_name"},
 	}
@@ -95,6 +96,11 @@ func (t *UpdateContainerTool) Call(ctx context.Context, args map[string]any) (an
 		return nil, notFoundError("container", containerName, suggestion)
 	}
 
+	// Fail the save if the file changed since the caller read it
+	if hash := getString(args, "expected_hash"); hash != "" {
+		container.ContentHash = hash
+	}
+
 	// Update only non-empty fields
 	if desc, ok := args["description"].(string); ok && desc != "" {
 		container.Description = desc
@@ -113,11 +119,12 @@ func (t *UpdateContainerTool) Call(ctx context.Context, args map[string]any) (an
 
 	return map[string]any{
 		"container": map[string]any{
-			"id":          container.ID,
-			"name":        container.Name,
-			"description": container.Description,
-			"technology":  container.Technology,
-			"tags":        container.Tags,
+			"id":           container.ID,
+			"name":         container.Name,
+			"description":  container.Description,
+			"technology":   container.Technology,
+			"tags":         container.Tags,
+			"content_hash": container.ContentHash,
 		},
 		"message": fmt.Sprintf("Container %q updated", container.Name),
 	}, nil
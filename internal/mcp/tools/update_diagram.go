@@ -85,13 +85,14 @@ func (t *UpdateDiagramTool) Call(ctx context.Context, args map[string]any) (any,
 	// Build diagram path using project source directory
 	var diagramPath string
 	systemID := entities.NormalizeName(systemName)
+	systemDir := filepath.Join(sourceDir, filepath.FromSlash(entities.SystemSourcePath(systemID)))
 	if containerName != "" {
-		// Update container diagram: src/{systemID}/{containerID}/{containerID}.d2
+		// Update container diagram: src/{system path}/{containerID}/{containerID}.d2
 		containerID := entities.NormalizeName(containerName)
-		diagramPath = filepath.Join(sourceDir, systemID, containerID, containerID+".d2")
+		diagramPath = filepath.Join(systemDir, containerID, containerID+".d2")
 	} else {
-		// Update system diagram: src/{systemID}/system.d2
-		diagramPath = filepath.Join(sourceDir, systemID, "system.d2")
+		// Update system diagram: src/{system path}/system.d2
+		diagramPath = filepath.Join(systemDir, "system.d2")
 	}
 
 	updateReq := &usecases.UpdateDiagramRequest{
@@ -79,6 +79,7 @@ func (t *UpdateSystemTool) InputSchema() map[string]any {
 				"items":       map[string]any{"type": "string"},
 				"description": "Replace tags list",
 			},
+			"expected_hash": expectedHashProperty,
 		},
 		"required": []string{"project_root", "system_name"},
 	}
@@ -113,6 +114,11 @@ func (t *UpdateSystemTool) Call(ctx context.Context, args map[string]any) (any,
 		return nil, notFoundError("system", systemName, suggestion)
 	}
 
+	// Fail the save if the file changed since the caller read it
+	if hash := getString(args, "expected_hash"); hash != "" {
+		system.ContentHash = hash
+	}
+
 	// Update only non-empty fields
 	if desc, ok := args["description"].(string); ok && desc != "" {
 		system.Description = desc
@@ -162,6 +168,7 @@ func (t *UpdateSystemTool) Call(ctx context.Context, args map[string]any) (any,
 			"framework":        system.Framework,
 			"database":         system.Database,
 			"tags":             system.Tags,
+			"content_hash":     system.ContentHash,
 		},
 		"message": fmt.Sprintf("System %q updated", system.Name),
 	}, nil
@@ -67,7 +67,7 @@ func (t *ValidateTool) Call(ctx context.Context, args map[string]any) (any, erro
 	validateUC := usecases.NewValidateArchitecture()
 
 	// Build architecture graph (includes relationships.toml when relRepo is wired).
-	graphUC := usecases.NewBuildArchitectureGraphWithRelRepo(t.relRepo)
+	graphUC := newGraphBuilder(t.relRepo)
 	graph, err := graphUC.Execute(ctx, project, systems)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build architecture graph: %w", err)